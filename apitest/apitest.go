@@ -0,0 +1,356 @@
+// Package apitest provides an in-memory fake of the manager, so the TUI, the
+// gRPC server and tools built on them can be tested without spawning
+// processes or touching the user's configuration. Full adds the optional
+// capabilities of the daemon's manager, and Daemon those its clients see.
+// It lives outside internal/ so tools outside this module can import it.
+package apitest
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/logs"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// FirstPID is the PID given to the first server started by a Manager, each
+// later start gets the next one
+const FirstPID = 1000
+
+// Change reports a server's status changing
+type Change struct {
+	Server string
+	Old    server.Status
+	New    server.Status
+}
+
+// Manager is a fake manager whose servers live in memory. Starting and
+// stopping only flips statuses, and statuses, tools, latencies and errors
+// can be scripted per server. It is safe for concurrent use.
+type Manager struct {
+	mu          sync.RWMutex
+	servers     map[string]*server.Server
+	serverOrder []string
+	configPath  string
+	maintenance bool
+	nextPID     int
+	started     time.Time
+
+	latencies   map[string]time.Duration // Delay of starting and stopping
+	startErrors map[string]error
+	stopErrors  map[string]error
+	logs        map[string]*logs.Buffer
+	calls       []string
+	changes     chan Change
+}
+
+// NewManager creates a fake manager without servers
+func NewManager() *Manager {
+	return &Manager{
+		servers:     make(map[string]*server.Server),
+		configPath:  "/tmp/mcp-manager-test/mcp.json",
+		nextPID:     FirstPID,
+		started:     time.Now(),
+		latencies:   make(map[string]time.Duration),
+		startErrors: make(map[string]error),
+		stopErrors:  make(map[string]error),
+		logs:        make(map[string]*logs.Buffer),
+		changes:     make(chan Change, 64),
+	}
+}
+
+// AddServer adds a stopped server
+func (m *Manager) AddServer(name, command string, port int, description string) error {
+	return m.Add(server.NewServer(name, command, port, description))
+}
+
+// Add adds a server as is, keeping its status and tools
+func (m *Manager) Add(srv *server.Server) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.servers[srv.Name]; exists {
+		return fmt.Errorf("server '%s' already exists", srv.Name)
+	}
+	m.add(srv)
+	return nil
+}
+
+// RemoveServer removes a server, as if deleted from the configuration
+func (m *Manager) RemoveServer(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, err := m.lookup(name); err != nil {
+		return err
+	}
+	m.remove(name)
+	return nil
+}
+
+// SetConfigPath sets the path returned by GetConfigPath
+func (m *Manager) SetConfigPath(path string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.configPath = path
+}
+
+// SetStatus moves a server to status, as if its process had changed state
+// on its own
+func (m *Manager) SetStatus(name string, status server.Status) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	srv, err := m.lookup(name)
+	if err != nil {
+		return err
+	}
+	m.setStatus(srv, status)
+	return nil
+}
+
+// SetTools sets the tools a server reports
+func (m *Manager) SetTools(name string, tools []server.Tool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	srv, err := m.lookup(name)
+	if err != nil {
+		return err
+	}
+	srv.SetTools(tools)
+	return nil
+}
+
+// SetLatency makes starting and stopping a server take d
+func (m *Manager) SetLatency(name string, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.latencies[name] = d
+}
+
+// FailStart makes starting a server fail with err, or succeed again when
+// err is nil. A failed start leaves the server in the error status.
+func (m *Manager) FailStart(name string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.startErrors[name] = err
+}
+
+// FailStop makes stopping a server fail with err, or succeed again when
+// err is nil
+func (m *Manager) FailStop(name string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stopErrors[name] = err
+}
+
+// Calls returns the calls changing servers made so far, e.g. "start foo"
+func (m *Manager) Calls() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]string(nil), m.calls...)
+}
+
+// Changes returns the status changes of the servers. Changes nobody reads
+// are dropped once the channel is full.
+func (m *Manager) Changes() <-chan Change {
+	return m.changes
+}
+
+// GetServers returns copies of all servers and their order
+func (m *Manager) GetServers() (map[string]*server.Server, []string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	servers := make(map[string]*server.Server, len(m.servers))
+	for name, srv := range m.servers {
+		srvCopy := *srv
+		servers[name] = &srvCopy
+	}
+	return servers, append([]string(nil), m.serverOrder...), nil
+}
+
+// GetServer returns a server. Changes made to it are seen by later calls.
+func (m *Manager) GetServer(name string) (*server.Server, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.lookup(name)
+}
+
+// GetServerOrder returns the ordered list of server names
+func (m *Manager) GetServerOrder() ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]string(nil), m.serverOrder...), nil
+}
+
+// StartServer marks a server running with a new PID, after its latency
+func (m *Manager) StartServer(name string) error {
+	m.wait(name)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = append(m.calls, "start "+name)
+	srv, err := m.lookup(name)
+	if err != nil {
+		return err
+	}
+	if err := m.startErrors[name]; err != nil {
+		m.setStatus(srv, server.StatusError)
+		return err
+	}
+	if srv.IsRunning() {
+		return nil
+	}
+	srv.SetPID(m.nextPID)
+	m.nextPID++
+	m.setStatus(srv, server.StatusRunning)
+	return nil
+}
+
+// StopServer marks a server stopped, after its latency
+func (m *Manager) StopServer(name string) error {
+	return m.stop(name, "stop "+name)
+}
+
+// StopAllServers stops every running server
+func (m *Manager) StopAllServers() {
+	order, _ := m.GetServerOrder()
+	for _, name := range order {
+		if srv, err := m.GetServer(name); err == nil && srv.IsRunning() {
+			m.StopServer(name)
+		}
+	}
+}
+
+// GetConfigPath returns the configuration file path
+func (m *Manager) GetConfigPath() (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.configPath, nil
+}
+
+// UpdateToolCounts does nothing, tools are set with SetTools
+func (m *Manager) UpdateToolCounts() error {
+	return nil
+}
+
+// SetMaintenance toggles maintenance mode for a server, or for the whole
+// manager when name is empty
+func (m *Manager) SetMaintenance(name string, enabled bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if name == "" {
+		m.maintenance = enabled
+		return nil
+	}
+	srv, err := m.lookup(name)
+	if err != nil {
+		return err
+	}
+	srv.Maintenance = enabled
+	return nil
+}
+
+// Maintenance returns true if the whole manager is in maintenance mode
+func (m *Manager) Maintenance() (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.maintenance, nil
+}
+
+// Logs returns the output buffer of a server, lines added to it are
+// streamed like those of a real process
+func (m *Manager) Logs(name string) (*logs.Buffer, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	buffer, exists := m.logs[name]
+	if !exists {
+		return nil, fmt.Errorf("server '%s' %w", name, server.ErrNotFound)
+	}
+	return buffer, nil
+}
+
+// Stop stops every running server
+func (m *Manager) Stop() error {
+	m.StopAllServers()
+	return nil
+}
+
+// Close stops every running server
+func (m *Manager) Close() error {
+	return m.Stop()
+}
+
+// stop marks a server stopped after its latency, recording call
+func (m *Manager) stop(name, call string) error {
+	m.wait(name)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = append(m.calls, call)
+	srv, err := m.lookup(name)
+	if err != nil {
+		return err
+	}
+	if err := m.stopErrors[name]; err != nil {
+		return err
+	}
+	srv.SetPID(0)
+	m.setStatus(srv, server.StatusStopped)
+	return nil
+}
+
+// add adds a server. Must be called with m.mu held.
+func (m *Manager) add(srv *server.Server) {
+	m.servers[srv.Name] = srv
+	m.serverOrder = append(m.serverOrder, srv.Name)
+	m.logs[srv.Name] = logs.NewBuffer(logs.DefaultCapacity)
+}
+
+// remove removes a server. Must be called with m.mu held.
+func (m *Manager) remove(name string) {
+	delete(m.servers, name)
+	delete(m.logs, name)
+	for i, n := range m.serverOrder {
+		if n == name {
+			m.serverOrder = append(m.serverOrder[:i], m.serverOrder[i+1:]...)
+			break
+		}
+	}
+}
+
+// lookup returns a server. Must be called with m.mu held.
+func (m *Manager) lookup(name string) (*server.Server, error) {
+	srv, exists := m.servers[name]
+	if !exists {
+		return nil, fmt.Errorf("server '%s' %w", name, server.ErrNotFound)
+	}
+	return srv, nil
+}
+
+// setStatus changes the status of a server and reports it. Must be called
+// with m.mu held.
+func (m *Manager) setStatus(srv *server.Server, status server.Status) {
+	old := srv.Status
+	srv.SetStatus(status)
+	if old == status {
+		return
+	}
+
+	report(m.changes, Change{Server: srv.Name, Old: old, New: status})
+}
+
+// wait sleeps for the latency of a server
+func (m *Manager) wait(name string) {
+	m.mu.RLock()
+	d := m.latencies[name]
+	m.mu.RUnlock()
+	time.Sleep(d)
+}
@@ -0,0 +1,215 @@
+package apitest_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/apitest"
+	"github.com/tartavull/mcp-manager/internal/api"
+	mcpgrpc "github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+var (
+	_ api.ManagerInterface     = (*apitest.Manager)(nil)
+	_ mcpgrpc.ManagerInterface = (*apitest.Manager)(nil)
+	_ mcpgrpc.LogSource        = (*apitest.Manager)(nil)
+	_ api.Liveness             = (*apitest.Daemon)(nil)
+	_ api.Uptime               = (*apitest.Daemon)(nil)
+	_ api.Reconnection         = (*apitest.Daemon)(nil)
+
+	// Full implements the optional interfaces of the daemon's manager
+	_ mcpgrpc.FleetManager     = (*apitest.Full)(nil)
+	_ mcpgrpc.Drainer          = (*apitest.Full)(nil)
+	_ mcpgrpc.ProfileManager   = (*apitest.Full)(nil)
+	_ mcpgrpc.DiskManager      = (*apitest.Full)(nil)
+	_ mcpgrpc.BackupManager    = (*apitest.Full)(nil)
+	_ mcpgrpc.Approver         = (*apitest.Full)(nil)
+	_ mcpgrpc.TranscriptSource = (*apitest.Full)(nil)
+	_ mcpgrpc.PluginSource     = (*apitest.Full)(nil)
+	_ mcpgrpc.Describer        = (*apitest.Full)(nil)
+	_ mcpgrpc.Cloner           = (*apitest.Full)(nil)
+	_ mcpgrpc.ConfigSource     = (*apitest.Full)(nil)
+	_ mcpgrpc.PortSource       = (*apitest.Full)(nil)
+	_ mcpgrpc.StatusSource     = (*apitest.Full)(nil)
+
+	// and Daemon those of a client of the daemon
+	_ api.Approvals     = (*apitest.Daemon)(nil)
+	_ api.Transcripts   = (*apitest.Daemon)(nil)
+	_ api.Profiles      = (*apitest.Daemon)(nil)
+	_ api.DiskUsage     = (*apitest.Daemon)(nil)
+	_ api.Describer     = (*apitest.Daemon)(nil)
+	_ api.Cloner        = (*apitest.Daemon)(nil)
+	_ api.Notifications = (*apitest.Daemon)(nil)
+)
+
+func TestManager_StartStop(t *testing.T) {
+	m := apitest.NewManager()
+	require.NoError(t, m.AddServer("a", "echo a", 4001, "Server a"))
+	require.NoError(t, m.AddServer("b", "echo b", 4002, "Server b"))
+	assert.Error(t, m.AddServer("a", "echo a", 4001, "Server a"))
+
+	require.NoError(t, m.StartServer("a"))
+	require.NoError(t, m.StartServer("b"))
+	a, err := m.GetServer("a")
+	require.NoError(t, err)
+	assert.Equal(t, server.StatusRunning, a.Status)
+	assert.Equal(t, apitest.FirstPID, a.PID)
+	b, _ := m.GetServer("b")
+	assert.Equal(t, apitest.FirstPID+1, b.PID)
+
+	require.NoError(t, m.StopServer("a"))
+	assert.Equal(t, server.StatusStopped, a.Status)
+	assert.Zero(t, a.PID)
+	assert.Equal(t, []string{"start a", "start b", "stop a"}, m.Calls())
+
+	_, err = m.GetServer("missing")
+	assert.ErrorIs(t, err, server.ErrNotFound)
+	assert.ErrorIs(t, m.StartServer("missing"), server.ErrNotFound)
+}
+
+func TestManager_GetServersCopies(t *testing.T) {
+	m := apitest.NewManager()
+	require.NoError(t, m.AddServer("a", "echo a", 4001, "Server a"))
+
+	servers, order, err := m.GetServers()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, order)
+	servers["a"].Status = server.StatusRunning
+
+	srv, _ := m.GetServer("a")
+	assert.Equal(t, server.StatusStopped, srv.Status)
+}
+
+func TestManager_Script(t *testing.T) {
+	m := apitest.NewManager()
+	require.NoError(t, m.AddServer("a", "echo a", 4001, "Server a"))
+
+	failure := errors.New("boom")
+	m.FailStart("a", failure)
+	assert.ErrorIs(t, m.StartServer("a"), failure)
+	srv, _ := m.GetServer("a")
+	assert.Equal(t, server.StatusError, srv.Status)
+
+	m.FailStart("a", nil)
+	m.SetLatency("a", 50*time.Millisecond)
+	begin := time.Now()
+	require.NoError(t, m.StartServer("a"))
+	assert.GreaterOrEqual(t, time.Since(begin), 50*time.Millisecond)
+
+	require.NoError(t, m.SetTools("a", []server.Tool{{Name: "one"}, {Name: "two"}}))
+	assert.Equal(t, 2, srv.ToolCount)
+
+	require.NoError(t, m.SetStatus("a", server.StatusStarting))
+	assert.Equal(t, server.StatusStarting, srv.Status)
+}
+
+func TestManager_Changes(t *testing.T) {
+	m := apitest.NewManager()
+	require.NoError(t, m.AddServer("a", "echo a", 4001, "Server a"))
+
+	require.NoError(t, m.StartServer("a"))
+	require.NoError(t, m.StartServer("a")) // Already running, no change
+	require.NoError(t, m.Close())
+
+	assert.Equal(t, apitest.Change{Server: "a", Old: server.StatusStopped, New: server.StatusRunning}, <-m.Changes())
+	assert.Equal(t, apitest.Change{Server: "a", Old: server.StatusRunning, New: server.StatusStopped}, <-m.Changes())
+	assert.Empty(t, m.Changes())
+}
+
+func TestManager_Maintenance(t *testing.T) {
+	m := apitest.NewManager()
+	require.NoError(t, m.AddServer("a", "echo a", 4001, "Server a"))
+
+	require.NoError(t, m.SetMaintenance("", true))
+	on, _ := m.Maintenance()
+	assert.True(t, on)
+
+	require.NoError(t, m.SetMaintenance("a", true))
+	srv, _ := m.GetServer("a")
+	assert.True(t, srv.Maintenance)
+	assert.ErrorIs(t, m.SetMaintenance("missing", true), server.ErrNotFound)
+}
+
+func TestDaemon_Liveness(t *testing.T) {
+	d := apitest.NewDaemon()
+	assert.True(t, d.Connected())
+
+	d.SetConnected(false)
+	assert.False(t, d.Connected())
+
+	uptime, err := d.Uptime()
+	require.NoError(t, err)
+	assert.Less(t, uptime, time.Minute)
+}
+
+func TestFull_Capabilities(t *testing.T) {
+	f := apitest.NewFull()
+	require.NoError(t, f.AddServer("a", "serve /docs", 4001, "Server a"))
+
+	// Clones take the next free name and port
+	name, port, err := f.CloneServer("a", "", []string{"docs=code"})
+	require.NoError(t, err)
+	assert.Equal(t, "a-2", name)
+	assert.Equal(t, 4002, port)
+	clone, _ := f.GetServer("a-2")
+	assert.Equal(t, "serve /code", clone.Command)
+
+	fleet, err := f.CreateFleet("run-1", []string{"a"}, true)
+	require.NoError(t, err)
+	assert.Equal(t, "a@run-1", fleet.Servers[0].Name)
+	assert.Equal(t, apitest.FirstFleetPort, fleet.Servers[0].Port)
+	require.NoError(t, f.DestroyFleet("run-1"))
+	_, err = f.GetServer("a@run-1")
+	assert.ErrorIs(t, err, server.ErrNotFound)
+
+	require.NoError(t, f.DrainServer("a", time.Second))
+	assert.Equal(t, []string{"clone a", "drain a 1s"}, f.Calls())
+
+	// Parked calls and their outcome are reported
+	f.ParkCall(mcpgrpc.Approval{ID: "1", Server: "a"})
+	require.NoError(t, f.DecideApproval("1", true, ""))
+	assert.ErrorIs(t, f.DecideApproval("1", true, ""), server.ErrNotFound)
+	assert.Equal(t, mcpgrpc.ApprovalPending, (<-f.ApprovalChanges()).State)
+	assert.Equal(t, mcpgrpc.ApprovalApproved, (<-f.ApprovalChanges()).State)
+
+	// Status changes are reported when made on the server's own
+	require.NoError(t, f.SetStatus("a", server.StatusError))
+	assert.Equal(t, mcpgrpc.StatusChange{Server: "a", Old: server.StatusStopped, New: server.StatusError}, <-f.StatusChanges())
+	require.NoError(t, f.StartServer("a"))
+	assert.Empty(t, f.StatusChanges())
+}
+
+func TestDaemon_Reachable(t *testing.T) {
+	d := apitest.NewDaemon()
+	require.NoError(t, d.AddServer("a", "echo a", 4001, "Server a"))
+
+	d.SetReachable(false)
+	_, _, err := d.GetServers()
+	assert.Error(t, err)
+	assert.Error(t, d.StartServer("a"))
+
+	d.SetReachable(true)
+	require.NoError(t, d.StartServer("a"))
+
+	// Closing the connection leaves the servers running
+	require.NoError(t, d.Close())
+	assert.True(t, d.Closed())
+	srv, _ := d.GetServer("a")
+	assert.True(t, srv.IsRunning())
+}
+
+func TestDaemon_Notifications(t *testing.T) {
+	d := apitest.NewDaemon()
+	require.NoError(t, d.AddServer("a", "echo a", 4001, "Server a"))
+
+	require.NoError(t, d.SetNotification("a", "mute"))
+	assert.ErrorIs(t, d.SetNotification("missing", "all"), server.ErrNotFound)
+	level, preferences, err := d.Notifications()
+	require.NoError(t, err)
+	assert.Equal(t, "crash", level)
+	assert.Equal(t, []mcpgrpc.NotificationPreference{{Server: "a", Level: "mute", Custom: true}}, preferences)
+}
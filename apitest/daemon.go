@@ -0,0 +1,262 @@
+package apitest
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/grpc/types"
+	"github.com/tartavull/mcp-manager/internal/server"
+	"github.com/tartavull/mcp-manager/internal/transcript"
+)
+
+// defaultNotificationLevel is the level of desktop notifications of servers
+// without one of their own, as in the daemon
+const defaultNotificationLevel = "crash"
+
+// errUnreachable is returned by the calls to a daemon that stopped
+// answering
+var errUnreachable = errors.New("connection refused")
+
+// Daemon is a fake manager reached over a connection to a daemon, with the
+// capabilities of a Full manager as clients see them. Its liveness, uptime,
+// reachability and event stream can be scripted.
+type Daemon struct {
+	*Full
+
+	mu            sync.RWMutex
+	connected     bool
+	state         types.ConnectionState
+	retry         types.Retry
+	reconnects    int
+	unreachable   bool
+	closed        bool
+	lastHeartbeat time.Time
+	notifications map[string]string // Level of the servers with their own
+}
+
+// NewDaemon creates a fake daemon without servers, connected and just
+// heard from
+func NewDaemon() *Daemon {
+	return &Daemon{
+		Full:          NewFull(),
+		connected:     true,
+		state:         types.StateConnected,
+		lastHeartbeat: time.Now(),
+		notifications: make(map[string]string),
+	}
+}
+
+// SetConnected marks the connection to the daemon up or down. Going up
+// counts as a heartbeat.
+func (d *Daemon) SetConnected(connected bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.connected = connected
+	if connected {
+		d.lastHeartbeat = time.Now()
+	}
+}
+
+// Connected returns true if the connection to the daemon is up
+func (d *Daemon) Connected() bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.connected
+}
+
+// LastHeartbeat returns when the daemon was last heard from
+func (d *Daemon) LastHeartbeat() time.Time {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.lastHeartbeat
+}
+
+// Uptime returns how long the fake daemon has existed
+func (d *Daemon) Uptime() (time.Duration, error) {
+	return time.Since(d.started), nil
+}
+
+// SetState sets the state of the event stream and the attempts to
+// reconnect it
+func (d *Daemon) SetState(state types.ConnectionState, retry types.Retry) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.state, d.retry = state, retry
+}
+
+// State returns the state of the event stream
+func (d *Daemon) State() types.ConnectionState {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.state
+}
+
+// Retry returns the attempts to reconnect the event stream
+func (d *Daemon) Retry() types.Retry {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.retry
+}
+
+// Reconnect counts an attempt to reconnect, leaving the state as it is
+func (d *Daemon) Reconnect() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.reconnects++
+}
+
+// Reconnects returns the number of calls to Reconnect
+func (d *Daemon) Reconnects() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.reconnects
+}
+
+// SetReachable makes the calls listing and changing servers fail as if the
+// daemon stopped answering, or answer again
+func (d *Daemon) SetReachable(reachable bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.unreachable = !reachable
+}
+
+// GetServers returns copies of all servers and their order
+func (d *Daemon) GetServers() (map[string]*server.Server, []string, error) {
+	if err := d.reachable(); err != nil {
+		return nil, nil, err
+	}
+	return d.Full.GetServers()
+}
+
+// GetServer returns a server. Changes made to it are seen by later calls.
+func (d *Daemon) GetServer(name string) (*server.Server, error) {
+	if err := d.reachable(); err != nil {
+		return nil, err
+	}
+	return d.Full.GetServer(name)
+}
+
+// GetServerOrder returns the ordered list of server names
+func (d *Daemon) GetServerOrder() ([]string, error) {
+	if err := d.reachable(); err != nil {
+		return nil, err
+	}
+	return d.Full.GetServerOrder()
+}
+
+// StartServer marks a server running with a new PID, after its latency
+func (d *Daemon) StartServer(name string) error {
+	if err := d.reachable(); err != nil {
+		return err
+	}
+	return d.Full.StartServer(name)
+}
+
+// StopServer marks a server stopped, after its latency
+func (d *Daemon) StopServer(name string) error {
+	if err := d.reachable(); err != nil {
+		return err
+	}
+	return d.Full.StopServer(name)
+}
+
+// SetMaintenance toggles maintenance mode for a server, or for the whole
+// daemon when name is empty
+func (d *Daemon) SetMaintenance(name string, enabled bool) error {
+	if err := d.reachable(); err != nil {
+		return err
+	}
+	return d.Full.SetMaintenance(name, enabled)
+}
+
+// Maintenance returns true if the whole daemon is in maintenance mode
+func (d *Daemon) Maintenance() (bool, error) {
+	if err := d.reachable(); err != nil {
+		return false, err
+	}
+	return d.Full.Maintenance()
+}
+
+// Close closes the connection, leaving the servers as they are
+func (d *Daemon) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.closed = true
+	return nil
+}
+
+// Closed returns true once the connection was closed
+func (d *Daemon) Closed() bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.closed
+}
+
+// PendingApprovals returns the parked calls, oldest first
+func (d *Daemon) PendingApprovals() ([]types.Approval, error) {
+	return d.Full.PendingApprovals(), nil
+}
+
+// Sessions returns the session transcripts without their calls
+func (d *Daemon) Sessions() ([]transcript.Session, error) {
+	return d.Full.Sessions(), nil
+}
+
+// FindSessions returns the session transcripts with calls given id as
+// request or run ID, holding only those calls
+func (d *Daemon) FindSessions(id string) ([]transcript.Session, error) {
+	return d.Full.FindSessions(id), nil
+}
+
+// Notifications returns the default level of desktop notifications and
+// the level of every server
+func (d *Daemon) Notifications() (string, []types.NotificationPreference, error) {
+	order, err := d.GetServerOrder()
+	if err != nil {
+		return "", nil, err
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	preferences := make([]types.NotificationPreference, 0, len(order))
+	for _, name := range order {
+		level, custom := d.notifications[name]
+		if !custom {
+			level = defaultNotificationLevel
+		}
+		preferences = append(preferences, types.NotificationPreference{Server: name, Level: level, Custom: custom})
+	}
+	return defaultNotificationLevel, preferences, nil
+}
+
+// SetNotification sets the level of desktop notifications of a server, or
+// resets it to the default when level is empty
+func (d *Daemon) SetNotification(name, level string) error {
+	if _, err := d.GetServer(name); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if level == "" {
+		delete(d.notifications, name)
+	} else {
+		d.notifications[name] = level
+	}
+	return nil
+}
+
+// reachable returns an error if the daemon stopped answering
+func (d *Daemon) reachable() error {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.unreachable {
+		return errUnreachable
+	}
+	return nil
+}
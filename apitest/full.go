@@ -0,0 +1,538 @@
+package apitest
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/grpc/types"
+	"github.com/tartavull/mcp-manager/internal/plugin"
+	"github.com/tartavull/mcp-manager/internal/server"
+	"github.com/tartavull/mcp-manager/internal/transcript"
+)
+
+// FirstFleetPort is the port given to the first server copied into a
+// fleet, each later copy gets the next one
+const FirstFleetPort = 5000
+
+// Full is a Manager with the optional capabilities of the daemon's manager:
+// fleets, draining, browser profiles, disk usage, backups, approvals,
+// transcripts, plugins, describing and cloning servers, and reporting
+// reloads, port migrations and status changes. Tests of managers without
+// them use a Manager.
+type Full struct {
+	*Manager
+
+	// Guarded by the Manager's mutex
+	fleets       map[string]*types.Fleet
+	nextPort     int
+	profiles     map[string][]types.BrowserProfile
+	disk         map[string][]types.DiskDir
+	backups      map[string][]types.Backup
+	approvals    []types.Approval
+	runtimes     map[string]*types.Runtime
+	plugins      []*plugin.Plugin
+	transcripts  *transcript.Store
+	approvalsOut chan types.ApprovalChange
+	configOut    chan types.ConfigChange
+	portsOut     chan types.PortMigration
+	statusOut    chan types.StatusChange
+}
+
+// NewFull creates a fake manager with every optional capability and
+// without servers
+func NewFull() *Full {
+	return &Full{
+		Manager:      NewManager(),
+		fleets:       make(map[string]*types.Fleet),
+		nextPort:     FirstFleetPort,
+		profiles:     make(map[string][]types.BrowserProfile),
+		disk:         make(map[string][]types.DiskDir),
+		backups:      make(map[string][]types.Backup),
+		runtimes:     make(map[string]*types.Runtime),
+		transcripts:  transcript.NewStore(),
+		approvalsOut: make(chan types.ApprovalChange, 64),
+		configOut:    make(chan types.ConfigChange, 64),
+		portsOut:     make(chan types.PortMigration, 64),
+		statusOut:    make(chan types.StatusChange, 64),
+	}
+}
+
+// SetStatus moves a server to status, as if its process had changed state
+// on its own, and reports it as a status change
+func (f *Full) SetStatus(name string, status server.Status) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	srv, err := f.lookup(name)
+	if err != nil {
+		return err
+	}
+	old := srv.Status
+	f.setStatus(srv, status)
+	if old != status {
+		report(f.statusOut, types.StatusChange{Server: name, Old: old, New: status})
+	}
+	return nil
+}
+
+// StatusChanges returns the status changes made with SetStatus
+func (f *Full) StatusChanges() <-chan types.StatusChange {
+	return f.statusOut
+}
+
+// ReportConfigChange reports a reload of the configuration
+func (f *Full) ReportConfigChange(change types.ConfigChange) {
+	report(f.configOut, change)
+}
+
+// ConfigChanges returns the reloads reported with ReportConfigChange
+func (f *Full) ConfigChanges() <-chan types.ConfigChange {
+	return f.configOut
+}
+
+// ReportPortMigration reports a step of moving a server to a new port
+func (f *Full) ReportPortMigration(migration types.PortMigration) {
+	report(f.portsOut, migration)
+}
+
+// PortMigrations returns the steps reported with ReportPortMigration
+func (f *Full) PortMigrations() <-chan types.PortMigration {
+	return f.portsOut
+}
+
+// CreateFleet copies servers as server@runID on ports from FirstFleetPort,
+// starting them if start is set
+func (f *Full) CreateFleet(runID string, names []string, start bool) (*types.Fleet, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, exists := f.fleets[runID]; exists {
+		return nil, fmt.Errorf("fleet '%s' %w", runID, server.ErrExists)
+	}
+	for _, name := range names {
+		if _, err := f.lookup(name); err != nil {
+			return nil, err
+		}
+	}
+
+	fleet := &types.Fleet{RunID: runID, Dir: filepath.Join(f.stateDir(), "fleets", runID), Created: time.Now()}
+	for _, name := range names {
+		source := f.servers[name]
+		srv := server.NewServer(name+"@"+runID, source.Command, f.nextPort, source.Description)
+		srv.RunID = runID
+		f.nextPort++
+		f.add(srv)
+		if start {
+			srv.SetPID(f.nextPID)
+			f.nextPID++
+			f.setStatus(srv, server.StatusRunning)
+		}
+		fleet.Servers = append(fleet.Servers, srv)
+	}
+	f.fleets[runID] = fleet
+	return fleet, nil
+}
+
+// DestroyFleet removes the copies of a fleet
+func (f *Full) DestroyFleet(runID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	fleet, exists := f.fleets[runID]
+	if !exists {
+		return fmt.Errorf("fleet '%s' %w", runID, server.ErrNotFound)
+	}
+	for _, srv := range fleet.Servers {
+		f.remove(srv.Name)
+	}
+	delete(f.fleets, runID)
+	return nil
+}
+
+// Fleets returns the fleets created so far
+func (f *Full) Fleets() []types.Fleet {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	var fleets []types.Fleet
+	for _, fleet := range f.fleets {
+		fleets = append(fleets, *fleet)
+	}
+	return fleets
+}
+
+// DrainServer stops a server at once, recording the call with its timeout,
+// e.g. "drain foo 5s"
+func (f *Full) DrainServer(name string, timeout time.Duration) error {
+	return f.stop(name, fmt.Sprintf("drain %s %s", name, timeout))
+}
+
+// AddProfile adds a browser profile of profile.Server. The profile a
+// server uses is the one marked attached.
+func (f *Full) AddProfile(profile types.BrowserProfile) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.profiles[profile.Server] = append(f.profiles[profile.Server], profile)
+}
+
+// BrowserProfiles returns the profiles added for a server
+func (f *Full) BrowserProfiles(name string) ([]types.BrowserProfile, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if _, err := f.lookup(name); err != nil {
+		return nil, err
+	}
+	return append([]types.BrowserProfile(nil), f.profiles[name]...), nil
+}
+
+// ResetProfile empties a profile
+func (f *Full) ResetProfile(name, profile string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	target, err := f.profile(name, profile)
+	if err != nil {
+		return err
+	}
+	target.Size = 0
+	return nil
+}
+
+// SnapshotProfile archives a profile under the next number, next to the
+// profile's directory
+func (f *Full) SnapshotProfile(name, profile string) (*types.ProfileSnapshot, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	target, err := f.profile(name, profile)
+	if err != nil {
+		return nil, err
+	}
+	number := strconv.Itoa(len(target.Snapshots) + 1)
+	snapshot := types.ProfileSnapshot{
+		Name:    number,
+		Path:    filepath.Join(filepath.Dir(target.Dir), ".snapshots", target.Name, number+".tar.gz"),
+		Size:    target.Size,
+		Created: time.Now(),
+	}
+	target.Snapshots = append(target.Snapshots, snapshot)
+	return &snapshot, nil
+}
+
+// RestoreProfile brings a profile back to a snapshot
+func (f *Full) RestoreProfile(name, profile, snapshot string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	target, err := f.profile(name, profile)
+	if err != nil {
+		return err
+	}
+	for _, kept := range target.Snapshots {
+		if kept.Name == snapshot {
+			target.Size = kept.Size
+			return nil
+		}
+	}
+	return fmt.Errorf("snapshot '%s' %w", snapshot, server.ErrNotFound)
+}
+
+// SetDisk sets the directories a server keeps files in
+func (f *Full) SetDisk(name string, dirs []types.DiskDir) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.disk[name] = dirs
+}
+
+// DiskUsage returns the directories set for servers, all of them when none
+// are named
+func (f *Full) DiskUsage(names []string) ([]types.ServerDisk, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	names, err := f.named(names)
+	if err != nil {
+		return nil, err
+	}
+	usage := make([]types.ServerDisk, 0, len(names))
+	for _, name := range names {
+		usage = append(usage, types.ServerDisk{Server: name, Dirs: append([]types.DiskDir(nil), f.disk[name]...)})
+	}
+	return usage, nil
+}
+
+// CleanCaches returns the caches of servers, all of them when none are
+// named, dropping them unless dryRun is set. Calls are recorded, e.g.
+// "clean foo" or "clean foo dry-run".
+func (f *Full) CleanCaches(names []string, dryRun bool) ([]types.ServerDisk, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	names, err := f.named(names)
+	if err != nil {
+		return nil, err
+	}
+	var cleaned []types.ServerDisk
+	for _, name := range names {
+		call := "clean " + name
+		if dryRun {
+			call += " dry-run"
+		}
+		f.calls = append(f.calls, call)
+
+		var caches, kept []types.DiskDir
+		for _, dir := range f.disk[name] {
+			if dir.Kind == types.DiskCache {
+				caches = append(caches, dir)
+			} else {
+				kept = append(kept, dir)
+			}
+		}
+		if len(caches) == 0 {
+			continue
+		}
+		cleaned = append(cleaned, types.ServerDisk{Server: name, Dirs: caches})
+		if !dryRun {
+			f.disk[name] = kept
+		}
+	}
+	return cleaned, nil
+}
+
+// BackupServer archives a server under the next number
+func (f *Full) BackupServer(name string) (*types.Backup, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.lookup(name); err != nil {
+		return nil, err
+	}
+	number := strconv.Itoa(len(f.backups[name]) + 1)
+	backup := types.Backup{
+		Server:  name,
+		Name:    number,
+		Path:    filepath.Join(f.stateDir(), "backups", name, number+".tar.gz"),
+		Created: time.Now(),
+	}
+	f.backups[name] = append(f.backups[name], backup)
+	return &backup, nil
+}
+
+// Backups returns the archives of a server, oldest first
+func (f *Full) Backups(name string) ([]types.Backup, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if _, err := f.lookup(name); err != nil {
+		return nil, err
+	}
+	return append([]types.Backup(nil), f.backups[name]...), nil
+}
+
+// RestoreBackup records restoring an archive, e.g. "restore foo 1"
+func (f *Full) RestoreBackup(name, backup string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, kept := range f.backups[name] {
+		if kept.Name == backup {
+			f.calls = append(f.calls, "restore "+name+" "+backup)
+			return nil
+		}
+	}
+	return fmt.Errorf("backup '%s' %w", backup, server.ErrNotFound)
+}
+
+// ParkCall parks a tool call for approval, reporting it as pending
+func (f *Full) ParkCall(approval types.Approval) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.approvals = append(f.approvals, approval)
+	report(f.approvalsOut, types.ApprovalChange{Approval: approval, State: types.ApprovalPending})
+}
+
+// PendingApprovals returns the parked calls, oldest first
+func (f *Full) PendingApprovals() []types.Approval {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return append([]types.Approval(nil), f.approvals...)
+}
+
+// DecideApproval lets a parked call through or rejects it, reporting the
+// outcome
+func (f *Full) DecideApproval(id string, approved bool, reason string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i, approval := range f.approvals {
+		if approval.ID != id {
+			continue
+		}
+		f.approvals = append(f.approvals[:i], f.approvals[i+1:]...)
+		change := types.ApprovalChange{Approval: approval, State: types.ApprovalRejected, Reason: reason}
+		if approved {
+			change.State, change.Reason = types.ApprovalApproved, ""
+		}
+		report(f.approvalsOut, change)
+		return nil
+	}
+	return fmt.Errorf("approval '%s' %w", id, server.ErrNotFound)
+}
+
+// ApprovalChanges returns the calls parked and decided
+func (f *Full) ApprovalChanges() <-chan types.ApprovalChange {
+	return f.approvalsOut
+}
+
+// Record records a call proxied for a client in its session transcript
+func (f *Full) Record(client string, entry transcript.Entry) {
+	f.transcripts.Record(client, entry)
+}
+
+// Sessions returns the session transcripts without their calls
+func (f *Full) Sessions() []transcript.Session {
+	return f.transcripts.Sessions()
+}
+
+// FindSessions returns the session transcripts with calls given id as
+// request or run ID, holding only those calls
+func (f *Full) FindSessions(id string) []transcript.Session {
+	return f.transcripts.Find(id)
+}
+
+// Session returns a session transcript with its calls
+func (f *Full) Session(id string) (*transcript.Session, error) {
+	if session, exists := f.transcripts.Get(id); exists {
+		return session, nil
+	}
+	return nil, fmt.Errorf("session '%s' %w", id, server.ErrNotFound)
+}
+
+// SetPlugins sets the plugins found
+func (f *Full) SetPlugins(plugins []*plugin.Plugin) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.plugins = plugins
+}
+
+// Plugins returns the plugins set with SetPlugins
+func (f *Full) Plugins() ([]*plugin.Plugin, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.plugins, nil
+}
+
+// SetRuntime sets what runtime.Server runs as
+func (f *Full) SetRuntime(runtime *types.Runtime) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.runtimes[runtime.Server] = runtime
+}
+
+// DescribeServer returns the runtime set for a server, or its bare command
+func (f *Full) DescribeServer(name string) (*types.Runtime, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	srv, err := f.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	if runtime, exists := f.runtimes[name]; exists {
+		return runtime, nil
+	}
+	return &types.Runtime{Server: name, Command: srv.Command, Running: srv.IsRunning()}, nil
+}
+
+// CloneServer adds a copy of a server on the next free port, named
+// "<source>-2" or the next free number if name is empty, with the
+// replacements "old=new" in its command. Calls are recorded, e.g.
+// "clone foo".
+func (f *Full) CloneServer(source, name string, replace []string) (string, int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, "clone "+source)
+	srv, err := f.lookup(source)
+	if err != nil {
+		return "", 0, err
+	}
+	if name == "" {
+		for i := 2; name == "" || f.servers[name] != nil; i++ {
+			name = fmt.Sprintf("%s-%d", source, i)
+		}
+	} else if _, exists := f.servers[name]; exists {
+		return "", 0, fmt.Errorf("server '%s' %w", name, server.ErrExists)
+	}
+
+	command := srv.Command
+	for _, r := range replace {
+		old, replacement, _ := strings.Cut(r, "=")
+		command = strings.ReplaceAll(command, old, replacement)
+	}
+	port := srv.Port + 1
+	for f.portUsed(port) {
+		port++
+	}
+	f.add(server.NewServer(name, command, port, srv.Description))
+	return name, port, nil
+}
+
+// profile returns a server's profile, its attached one if profile is
+// empty. Must be called with f.mu held.
+func (f *Full) profile(name, profile string) (*types.BrowserProfile, error) {
+	if _, err := f.lookup(name); err != nil {
+		return nil, err
+	}
+	profiles := f.profiles[name]
+	for i := range profiles {
+		if profiles[i].Name == profile || (profile == "" && profiles[i].Attached) {
+			return &profiles[i], nil
+		}
+	}
+	return nil, fmt.Errorf("profile '%s' of server '%s' %w", profile, name, server.ErrNotFound)
+}
+
+// named returns the servers named, or all of them when none are. Must be
+// called with f.mu held.
+func (f *Full) named(names []string) ([]string, error) {
+	if len(names) == 0 {
+		return append([]string(nil), f.serverOrder...), nil
+	}
+	for _, name := range names {
+		if _, err := f.lookup(name); err != nil {
+			return nil, err
+		}
+	}
+	return names, nil
+}
+
+// portUsed returns true if a server has port. Must be called with the
+// f.mu held.
+func (f *Full) portUsed(port int) bool {
+	for _, srv := range f.servers {
+		if srv.Port == port {
+			return true
+		}
+	}
+	return false
+}
+
+// stateDir returns the directory of the configuration file, where fleets
+// and backups are kept. Must be called with f.mu held.
+func (f *Full) stateDir() string {
+	return filepath.Dir(f.configPath)
+}
+
+// report sends a value to ch, dropping it if nobody reads ch and it's full
+func report[T any](ch chan T, value T) {
+	select {
+	case ch <- value:
+	default:
+	}
+}
@@ -3,19 +3,31 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 
+	"github.com/tartavull/mcp-manager/internal/config"
 	"github.com/tartavull/mcp-manager/internal/daemon"
 )
 
-const defaultGRPCPort = 8080
-
 func main() {
 	// Define command line flags
 	var (
-		port = flag.Int("port", defaultGRPCPort, "gRPC server port")
+		port        = flag.Int("port", 0, "gRPC server port (default: daemonPort of mcp.json, or 8080)")
+		webPort     = flag.Int("web-port", 0, "gRPC-Web and h2c port for browser dashboards (default: disabled)")
+		httpPort    = flag.Int("http-port", 0, "HTTP+JSON API port for clients without gRPC (default: disabled)")
+		bind        = flag.String("bind", "", "Interface the API ports listen on (default: daemonBindAddress of mcp.json, or 127.0.0.1)")
+		socket      = flag.String("socket", "", "Unix socket to serve the API on instead of -port (default: daemonSocket of mcp.json)")
+		coordinator = flag.Bool("coordinator", false, "Aggregate the servers of daemons that join")
+		peers       = flag.String("peers", "", "Static peers to aggregate, as host=address,...")
+		join        = flag.String("join", "", "Coordinator address to register with")
+		host        = flag.String("host", "", "Label of this daemon's servers (default: hostname)")
+		advertise   = flag.String("advertise", "", "Address the coordinator reaches this daemon on (default: host:port)")
+		profiling   = flag.Bool("pprof", false, "Serve profiles under /debug/pprof/ of -http-port")
+		quiet       = flag.Bool("quiet", false, "Don't report on stdout that the daemon started or stopped")
 	)
+	config.RegisterDirFlags(flag.CommandLine)
 
 	// Parse command
 	if len(os.Args) < 2 {
@@ -29,11 +41,33 @@ func main() {
 	os.Args = append([]string{os.Args[0]}, os.Args[2:]...)
 	flag.Parse()
 
+	peerMap, err := daemon.ParsePeers(*peers)
+	if err != nil {
+		log.Fatalf("Invalid -peers: %v", err)
+	}
+
 	// Create daemon instance
-	d, err := daemon.NewDaemon(*port)
+	d, err := daemon.NewDaemon(*port, *webPort, daemon.ClusterOptions{
+		Coordinator: *coordinator,
+		Peers:       peerMap,
+		Join:        *join,
+		Host:        *host,
+		Advertise:   *advertise,
+	})
 	if err != nil {
 		log.Fatalf("Failed to create daemon: %v", err)
 	}
+	d.SetHTTPPort(*httpPort)
+	d.SetProfiling(*profiling)
+	if *bind != "" {
+		d.SetBindAddress(*bind)
+	}
+	if *socket != "" {
+		d.SetSocket(*socket)
+	}
+	if *quiet {
+		d.SetOutput(io.Discard)
+	}
 
 	switch command {
 	case "run":
@@ -67,6 +101,12 @@ func main() {
 			log.Fatalf("Failed to start daemon: %v", err)
 		}
 
+	case "upgrade":
+		// Replace the running daemon with this binary, keeping its servers
+		if err := d.Upgrade(); err != nil {
+			log.Fatalf("Failed to upgrade daemon: %v", err)
+		}
+
 	default:
 		printUsage()
 		os.Exit(1)
@@ -84,10 +124,26 @@ Commands:
   start     Start daemon in background
   stop      Stop daemon
   status    Check daemon status
-  restart   Restart daemon
+  restart   Restart daemon, stopping and starting its servers
+  upgrade   Replace the running daemon with this binary, keeping its servers
 
 Flags:
-  -port int   gRPC server port (default: %d)
+  -port int          gRPC server port (default: daemonPort of mcp.json, or 8080)
+  -web-port int      gRPC-Web and h2c port for browser dashboards
+  -http-port int     HTTP+JSON API port for clients without gRPC
+  -bind address      Interface the API ports listen on (default: 127.0.0.1)
+  -socket path       Unix socket to serve the API on instead of -port
+  -pprof             Serve profiles under /debug/pprof/ of -http-port
+  -coordinator       Aggregate the servers of daemons that join
+  -peers list        Static peers to aggregate, as host=address,...
+  -join address      Coordinator address to register with
+  -host label        Label of this daemon's servers (default: hostname)
+  -advertise address Address the coordinator reaches this daemon on
+  -quiet             Don't report on stdout that the daemon started or stopped
+  -instance name     Name of the instance, with its own config, state and ports
+  -config-dir dir    Directory of mcp.json
+  -state-dir dir     Directory of runtime state, PIDs and logs
+  -cache-dir dir     Directory of cached files
 
 Examples:
   %s run                    # Run in foreground
@@ -95,5 +151,12 @@ Examples:
   %s start -port 9090       # Start on custom port
   %s stop                   # Stop daemon
   %s status                 # Check if daemon is running
-`, os.Args[0], defaultGRPCPort, os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0])
+  %s run -coordinator       # Aggregate daemons that join
+  %s run -join hub:8080     # Report to a coordinator
+  %s start -web-port 8081   # Also serve browser dashboards
+  %s start -http-port 8082  # Also serve the HTTP+JSON API
+  %s start -http-port 8082 -pprof  # Also serve profiles of the daemon
+  %s start -socket ~/.mcp-manager/daemon.sock  # Listen on a unix socket
+  %s start -instance work   # Start a separate instance
+`, os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0])
 }
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/grpc"
+)
+
+// approvalInfo is the schema of a parked call in json and yaml output
+type approvalInfo struct {
+	ID        string    `json:"id"`
+	Server    string    `json:"server"`
+	Tool      string    `json:"tool"`
+	Arguments string    `json:"arguments"`
+	Requested time.Time `json:"requested"`
+	Deadline  time.Time `json:"deadline"`
+}
+
+// runApprovals prints the tool calls waiting for approval
+func runApprovals(args []string) error {
+	fs := flag.NewFlagSet("approvals", flag.ExitOnError)
+	daemon := fs.String("daemon", defaultDaemonAddress(), "Daemon address")
+	output := outputFlag(fs)
+	fs.Parse(args)
+
+	format, err := parseOutput(*output)
+	if err != nil {
+		return err
+	}
+
+	client, err := connectDaemon(*daemon)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	approvals, err := client.ListApprovals()
+	if err != nil {
+		return err
+	}
+	if format.structured() {
+		infos := make([]approvalInfo, len(approvals))
+		for i, a := range approvals {
+			infos[i] = approvalInfo{ID: a.ID, Server: a.Server, Tool: a.Tool, Arguments: a.Arguments, Requested: a.Requested.UTC(), Deadline: a.Deadline.UTC()}
+		}
+		return format.write(os.Stdout, map[string][]approvalInfo{"approvals": infos})
+	}
+
+	if len(approvals) == 0 {
+		inform("No calls waiting for approval\n")
+		return nil
+	}
+	for _, a := range approvals {
+		fmt.Printf("%s  %-20s  %-24s  expires in %-6s  %s\n", a.ID, a.Server, a.Tool,
+			time.Until(a.Deadline).Round(time.Second), describeArguments(a, format == outputWide))
+	}
+	return nil
+}
+
+// describeArguments returns the arguments of a parked call, shortened
+// unless wide
+func describeArguments(a grpc.Approval, wide bool) string {
+	if wide || len(a.Arguments) <= 60 {
+		return a.Arguments
+	}
+	return a.Arguments[:57] + "..."
+}
+
+// runApprove lets the parked calls named in args through
+func runApprove(args []string) error {
+	return runDecision("approve", args, "Approved")
+}
+
+// runReject rejects the parked calls named in args
+func runReject(args []string) error {
+	return runDecision("reject", args, "Rejected")
+}
+
+// runDecision approves or rejects the parked calls named in args
+func runDecision(action string, args []string, done string) error {
+	fs := flag.NewFlagSet(action, flag.ExitOnError)
+	daemon := fs.String("daemon", defaultDaemonAddress(), "Daemon address")
+	var reason *string
+	if action == "reject" {
+		reason = fs.String("reason", "", "Reason returned to the client")
+	}
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		return fmt.Errorf("usage: %s %s <id>...", os.Args[0], action)
+	}
+
+	client, err := connectDaemon(*daemon)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	for _, id := range fs.Args() {
+		if action == "approve" {
+			err = client.DecideApproval(id, true, "")
+		} else {
+			err = client.DecideApproval(id, false, *reason)
+		}
+		if err != nil {
+			return err
+		}
+		inform("%s %s\n", done, id)
+	}
+	return nil
+}
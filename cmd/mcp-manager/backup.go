@@ -0,0 +1,104 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/grpc"
+)
+
+// backupInfo is the schema of a backup in json and yaml output
+type backupInfo struct {
+	Server  string    `json:"server"`
+	Name    string    `json:"name"`
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	Created time.Time `json:"created"`
+}
+
+// newBackupInfo converts a backup for json and yaml output
+func newBackupInfo(backup *grpc.Backup) backupInfo {
+	return backupInfo{Server: backup.Server, Name: backup.Name, Path: backup.Path, Size: backup.Size, Created: backup.Created.UTC()}
+}
+
+// runBackup archives the data paths of servers, or lists their backups
+// with -list
+func runBackup(args []string) error {
+	fs := flag.NewFlagSet("backup", flag.ExitOnError)
+	daemon := fs.String("daemon", defaultDaemonAddress(), "Daemon address")
+	list := fs.Bool("list", false, "List the backups instead of taking one")
+	output := outputFlag(fs)
+	fs.Parse(args)
+
+	format, err := parseOutput(*output)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("usage: %s backup [-list] <server>...", os.Args[0])
+	}
+
+	client, err := connectDaemon(*daemon)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	var backups []grpc.Backup
+	for _, name := range fs.Args() {
+		if *list {
+			listed, err := client.ListBackups(name)
+			if err != nil {
+				return err
+			}
+			backups = append(backups, listed...)
+			continue
+		}
+		backup, err := client.BackupServer(name)
+		if err != nil {
+			return err
+		}
+		backups = append(backups, *backup)
+	}
+
+	if format.structured() {
+		infos := make([]backupInfo, len(backups))
+		for i := range backups {
+			infos[i] = newBackupInfo(&backups[i])
+		}
+		return format.write(os.Stdout, map[string][]backupInfo{"backups": infos})
+	}
+	if *list && len(backups) == 0 {
+		inform("No backups\n")
+		return nil
+	}
+	for _, backup := range backups {
+		fmt.Printf("%-20s  %-20s  %10s  %s\n", backup.Server, backup.Name, formatSize(backup.Size), backup.Path)
+	}
+	return nil
+}
+
+// runRestore replaces the data paths of a server with a backup
+func runRestore(args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	daemon := fs.String("daemon", defaultDaemonAddress(), "Daemon address")
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		return fmt.Errorf("usage: %s restore <server> <backup>", os.Args[0])
+	}
+
+	client, err := connectDaemon(*daemon)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if err := client.RestoreBackup(fs.Arg(0), fs.Arg(1)); err != nil {
+		return err
+	}
+	inform("Restored %s from %s\n", fs.Arg(0), fs.Arg(1))
+	return nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+// runClone copies a server in mcp.json under a new name and port, with
+// substitutions "old=new" in its command
+func runClone(args []string) error {
+	fs := flag.NewFlagSet("clone", flag.ExitOnError)
+	daemon := fs.String("daemon", defaultDaemonAddress(), "Daemon address")
+	name := fs.String("name", "", "Name of the copy (default: <server>-2, or the next free number)")
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		return fmt.Errorf("usage: %s clone [-name name] <server> [old=new ...]", os.Args[0])
+	}
+
+	client, err := connectDaemon(*daemon)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	clone, port, err := client.CloneServer(fs.Arg(0), *name, fs.Args()[1:])
+	if err != nil {
+		return err
+	}
+	inform("Cloned %s as %s on port %d\n", fs.Arg(0), clone, port)
+	return nil
+}
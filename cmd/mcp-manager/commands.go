@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tartavull/mcp-manager/internal/i18n"
+	"github.com/tartavull/mcp-manager/internal/mcpmock"
+)
+
+// runCommand dispatches a mcp-manager subcommand
+func runCommand(command string, args []string) error {
+	switch command {
+	case "init":
+		return runInit(args)
+	case "self-update":
+		return runSelfUpdate(args)
+	case "diagnose":
+		return runDiagnose(args)
+	case "logs":
+		return runLogs(args)
+	case "describe":
+		return runDescribe(args)
+	case "events":
+		return runEvents(args)
+	case "stats":
+		return runStats(args)
+	case "list":
+		return runList(args)
+	case "tools":
+		return runTools(args)
+	case "status":
+		return runStatus(args)
+	case "start":
+		return runStart(args)
+	case "stop":
+		return runStop(args)
+	case "watch":
+		return runWatch(args)
+	case "ready":
+		return runReady(args)
+	case "approvals":
+		return runApprovals(args)
+	case "approve":
+		return runApprove(args)
+	case "reject":
+		return runReject(args)
+	case "sessions":
+		return runSessions(args)
+	case "transcript":
+		return runTranscript(args)
+	case "fixture":
+		return runFixture(args)
+	case "docs":
+		return runDocs(args)
+	case "secrets", "secret":
+		return runSecrets(args)
+	case "plugins":
+		return runPlugins(args)
+	case "fleet":
+		return runFleet(args)
+	case "profile":
+		return runProfile(args)
+	case "disk":
+		return runDisk(args)
+	case "cleanup":
+		return runCleanup(args)
+	case "backup":
+		return runBackup(args)
+	case "restore":
+		return runRestore(args)
+	case "providers":
+		return runProviders(args)
+	case "notifications":
+		return runNotifications(args)
+	case "import":
+		return runImport(args)
+	case "clone":
+		return runClone(args)
+	case "config":
+		return runConfig(args)
+	case "catalog":
+		return runCatalog(args)
+	case "monitoring":
+		return runMonitoring(args)
+	case "mock":
+		return mcpmock.Main(args)
+	case "help":
+		printUsage()
+		return nil
+	default:
+		printUsage()
+		return fmt.Errorf("unknown command '%s'", command)
+	}
+}
+
+// usageCommands are the subcommands listed in the usage, with what they do
+var usageCommands = []struct{ name, description string }{
+	{"init", "Run the setup wizard to create mcp.json"},
+	{"self-update", "Download and install the latest release (unverified: its checksums aren't signed)"},
+	{"diagnose", "Collect a diagnostics bundle for bug reports"},
+	{"logs", "Print a server's output captured by the daemon"},
+	{"describe", "Print what a server runs as: expanded command, env names, directory and limits"},
+	{"events", "Print past events from the daemon's journal"},
+	{"stats", "Print the uptime, outages and mean time to repair of servers (-days for the window)"},
+	{"list", "Print the names of the daemon's servers (-json for their full records)"},
+	{"tools", "Print the tools of the daemon's servers, or of the servers given"},
+	{"status", "Print the status of the daemon's servers (-short for status bars, -tag to filter)"},
+	{"watch", "Print the daemon's servers and then every change to them"},
+	{"start", "Start servers in the daemon (-tag for all servers with a tag)"},
+	{"stop", "Stop servers in the daemon (-tag for all servers with a tag)"},
+	{"ready", "Wait until servers (default: autostart servers) are healthy"},
+	{"approvals", "Print the tool calls waiting for approval"},
+	{"approve", "Let tool calls waiting for approval through"},
+	{"reject", "Reject tool calls waiting for approval (-reason to explain)"},
+	{"sessions", "Print the sessions of the clients calling the proxies (-id to filter by request or run ID)"},
+	{"transcript", "Print the calls of a session as markdown (-format json for JSON)"},
+	{"fixture", "Export the tool calls of a time window as a fixture for mock -replay"},
+	{"docs", "Generate HTML or markdown documentation of the servers' tools (generate)"},
+	{"secrets", "Manage the secrets mcp.json references (set, get, list, delete)"},
+	{"plugins", "Print the plugins the daemon found (-schema name for a config schema)"},
+	{"fleet", "Manage isolated copies of servers per evaluation run (create, destroy, list)"},
+	{"profile", "Manage the browser profiles of servers (list, reset, snapshot, restore)"},
+	{"disk", "Print the disk used by the caches and data of servers (-o wide for paths)"},
+	{"cleanup", "Delete the caches of stopped servers (-dry-run to only print them)"},
+	{"backup", "Archive the dataPaths of servers, stopping them meanwhile (-list for backups)"},
+	{"restore", "Replace the dataPaths of a server with a backup"},
+	{"providers", "Print the providers of generic gateway tools (pin, unpin to choose one)"},
+	{"notifications", "Print the desktop notification levels of servers (set, reset to change one)"},
+	{"import", "Add the servers of another mcp.json, checking its signature"},
+	{"clone", "Copy a server under a new name and port, replacing old=new in its command"},
+	{"config", "Flag risky settings of mcp.json (lint, -o json for scripts)"},
+	{"catalog", "Sign and verify catalogs and configs (keygen, sign, verify, check)"},
+	{"monitoring", "Print a Grafana dashboard or Prometheus alert rules for the servers (dashboard, alerts)"},
+	{"mock", "Run a mock MCP server on stdin/stdout, for tests and demos"},
+	{"help", "Show this help"},
+}
+
+// usageExitCodes are the exit codes of subcommands listed in the usage
+var usageExitCodes = []struct {
+	code        int
+	description string
+}{
+	{exitOK, "Success"},
+	{exitFailure, "Other failure"},
+	{exitNotFound, "Server not found"},
+	{exitAlreadyRunning, "Server already running (or already stopped)"},
+	{exitUnreachable, "Daemon unreachable"},
+	{exitTimeout, "Timed out"},
+}
+
+// printUsage prints the usage in the locale of the messages
+func printUsage() {
+	var b strings.Builder
+	fmt.Fprintf(&b, "MCP Manager\n\n%s\n", i18n.T("Usage:"))
+	fmt.Fprintf(&b, "  %s [flags]              %s\n", os.Args[0], i18n.T("Run the TUI"))
+	fmt.Fprintf(&b, "  %s <command> [flags]    %s\n", os.Args[0], i18n.T("Run a command"))
+
+	fmt.Fprintf(&b, "\n%s\n", i18n.T("Commands:"))
+	for _, command := range usageCommands {
+		fmt.Fprintf(&b, "  %-13s %s\n", command.name, i18n.T(command.description))
+	}
+
+	fmt.Fprintf(&b, "\n%s\n", i18n.T("Exit codes:"))
+	for _, exit := range usageExitCodes {
+		fmt.Fprintf(&b, "  %d  %s\n", exit.code, i18n.T(exit.description))
+	}
+
+	fmt.Fprintf(&b, "\n%s\n", i18n.T("Flags:"))
+	fmt.Fprintf(&b, "  -daemon string   %s\n", i18n.T("Daemon address (default: %s)", defaultDaemonAddress()))
+	fmt.Fprintf(&b, "                   %s\n", i18n.T("or ssh://user@host[/address] for a remote daemon"))
+	fmt.Fprintf(&b, "                   %s\n", i18n.T("or unix://path for one listening on a unix socket"))
+	fmt.Fprintf(&b, "  -instance name   %s\n", i18n.T("Use a separate instance, also before a command, e.g."))
+	fmt.Fprintf(&b, "                   %s -instance work status\n", os.Args[0])
+	fmt.Fprintf(&b, "  -quiet           %s\n", i18n.T("Only print the data asked for, before a command"))
+	fmt.Fprintf(&b, "  -machine         %s\n", i18n.T("Like -quiet, without colors, prompts or the TUI, for programs"))
+	fmt.Fprintf(&b, "  -tls-ca file     %s\n", i18n.T("PEM CAs verifying the daemon, enabling TLS"))
+	fmt.Fprintf(&b, "  -tls-cert file   %s\n", i18n.T("PEM client certificate, with -tls-key"))
+	fmt.Fprintf(&b, "  -tls-server-name %s\n", i18n.T("Name the daemon's certificate is verified for"))
+	fmt.Fprintf(&b, "  -standalone      %s\n", i18n.T("Run in standalone mode without daemon"))
+	fmt.Fprintf(&b, "  -overview        %s\n", i18n.T("Start on the overview screen"))
+	fmt.Fprintf(&b, "  -title           %s\n", i18n.T("Show running/total servers in the terminal title"))
+	fmt.Fprintf(&b, "  -debug           %s\n", i18n.T("Log how long each frame of the TUI takes to render"))
+	fmt.Fprint(os.Stderr, b.String())
+}
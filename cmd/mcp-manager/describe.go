@@ -0,0 +1,116 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/tartavull/mcp-manager/internal/grpc"
+)
+
+// runtimeInfo is the schema of what a server runs as in json and yaml
+// output
+type runtimeInfo struct {
+	Server    string      `json:"server"`
+	Command   string      `json:"command"`
+	Running   bool        `json:"running"`
+	Env       []varInfo   `json:"env"`
+	Path      string      `json:"path"`
+	Cwd       string      `json:"cwd"`
+	Transport string      `json:"transport,omitempty"`
+	Limits    []limitInfo `json:"limits"`
+	Error     string      `json:"error,omitempty"`
+}
+
+// varInfo is the schema of a variable of a server in json and yaml output
+type varInfo struct {
+	Name   string `json:"name"`
+	Source string `json:"source"`
+}
+
+// limitInfo is the schema of a limit of a server in json and yaml output
+type limitInfo struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// newRuntimeInfo converts what a server runs as for json and yaml output
+func newRuntimeInfo(runtime *grpc.Runtime) runtimeInfo {
+	info := runtimeInfo{
+		Server:    runtime.Server,
+		Command:   runtime.Command,
+		Running:   runtime.Running,
+		Env:       []varInfo{},
+		Path:      runtime.Path,
+		Cwd:       runtime.Cwd,
+		Transport: runtime.Transport,
+		Limits:    []limitInfo{},
+		Error:     runtime.Error,
+	}
+	for _, v := range runtime.Env {
+		info.Env = append(info.Env, varInfo(v))
+	}
+	for _, limit := range runtime.Limits {
+		info.Limits = append(info.Limits, limitInfo(limit))
+	}
+	return info
+}
+
+// runDescribe prints what a server runs as, or would if started now
+func runDescribe(args []string) error {
+	fs := flag.NewFlagSet("describe", flag.ExitOnError)
+	daemon := fs.String("daemon", defaultDaemonAddress(), "Daemon address")
+	output := outputFlag(fs)
+	fs.Parse(args)
+
+	format, err := parseOutput(*output)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: %s describe <server>", os.Args[0])
+	}
+
+	client, err := connectDaemon(*daemon)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	runtime, err := client.DescribeServer(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if format.structured() {
+		return format.write(os.Stdout, newRuntimeInfo(runtime))
+	}
+
+	state := "next start"
+	if runtime.Running {
+		state = "running"
+	}
+	transport := runtime.Transport
+	if transport == "" {
+		transport = "stdio"
+	}
+	fmt.Printf("Command (%s): %s\n", state, runtime.Command)
+	fmt.Printf("Directory:   %s\n", runtime.Cwd)
+	fmt.Printf("Transport:   %s\n", transport)
+	fmt.Printf("PATH:        %s\n", runtime.Path)
+	if len(runtime.Env) > 0 {
+		fmt.Println("Env:")
+		for _, v := range runtime.Env {
+			fmt.Printf("  %-30s %s\n", v.Name, v.Source)
+		}
+	}
+	if len(runtime.Limits) > 0 {
+		fmt.Println("Limits:")
+		for _, limit := range runtime.Limits {
+			fmt.Printf("  %-30s %s\n", limit.Name, limit.Value)
+		}
+	}
+	if runtime.Error != "" {
+		return fmt.Errorf("%s can't start: %s", runtime.Server, runtime.Error)
+	}
+	return nil
+}
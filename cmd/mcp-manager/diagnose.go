@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/api"
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/diagnose"
+)
+
+// runDiagnose collects a diagnostics bundle for bug reports
+func runDiagnose(args []string) error {
+	fs := flag.NewFlagSet("diagnose", flag.ExitOnError)
+	var (
+		daemon = fs.String("daemon", defaultDaemonAddress(), "Daemon address")
+		output = fs.String("o", "", "Output file (default: mcp-diagnostics-<timestamp>.tar.gz)")
+	)
+	fs.Parse(args)
+
+	cfg, err := config.New()
+	if err != nil {
+		return err
+	}
+
+	collector := &diagnose.Collector{
+		Config: cfg,
+		Daemon: diagnose.DaemonInfo{Address: *daemon},
+	}
+
+	collector.LogFiles = []string{
+		cfg.GetLogPath("daemon.log"),
+		cfg.GetLogPath("mcp-manager.log"),
+	}
+
+	fmt.Fprintf(os.Stderr, "Connecting to daemon at %s...\n", *daemon)
+	adapter, err := connectAdapter(*daemon)
+	if err != nil {
+		collector.Daemon.Error = err.Error()
+	} else {
+		defer adapter.Close()
+		collector.Daemon.Reachable = true
+		collector.Source = adapter
+		if health, err := adapter.Client.Health(); err != nil {
+			collector.Daemon.Error = err.Error()
+		} else {
+			collector.Daemon.UptimeSeconds = health.UptimeSeconds
+			collector.Daemon.RunningServers = int(health.RunningServers)
+			collector.Daemon.TotalServers = int(health.TotalServers)
+		}
+	}
+
+	report := collector.Collect()
+
+	path := *output
+	if path == "" {
+		path = fmt.Sprintf("mcp-diagnostics-%s.tar.gz", report.GeneratedAt.Format("20060102-150405"))
+	}
+	if err := collector.WriteBundle(report, path); err != nil {
+		return err
+	}
+
+	fmt.Print(report.Summary())
+	inform("\nDiagnostics bundle written to %s (collected in %s)\n", path, time.Since(report.GeneratedAt).Round(time.Millisecond))
+	return nil
+}
+
+// connectAdapter connects to the daemon with the credentials of the
+// environment and its pin in mcp.json
+func connectAdapter(address string) (*api.GRPCAdapter, error) {
+	options, err := clientOptions(address)
+	if err != nil {
+		return nil, err
+	}
+	return api.NewGRPCAdapterWithOptions(address, options)
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/tartavull/mcp-manager/internal/grpc"
+)
+
+// diskInfo is the schema of the disk used by a server in json and yaml
+// output
+type diskInfo struct {
+	Server string    `json:"server"`
+	Size   int64     `json:"size"`
+	Dirs   []dirInfo `json:"dirs"`
+}
+
+// dirInfo is the schema of a directory of a server in json and yaml output
+type dirInfo struct {
+	Kind  string `json:"kind"`
+	Label string `json:"label"`
+	Path  string `json:"path"`
+	Size  int64  `json:"size"`
+}
+
+// newDiskInfos converts the disk used by servers for json and yaml output
+func newDiskInfos(usage []grpc.ServerDisk) []diskInfo {
+	infos := make([]diskInfo, len(usage))
+	for i := range usage {
+		infos[i] = diskInfo{Server: usage[i].Server, Size: usage[i].Total(""), Dirs: []dirInfo{}}
+		for _, dir := range usage[i].Dirs {
+			infos[i].Dirs = append(infos[i].Dirs, dirInfo(dir))
+		}
+	}
+	return infos
+}
+
+// runDisk prints the caches and data of servers with the disk they use
+func runDisk(args []string) error {
+	fs := flag.NewFlagSet("disk", flag.ExitOnError)
+	daemon := fs.String("daemon", defaultDaemonAddress(), "Daemon address")
+	output := outputFlag(fs)
+	fs.Parse(args)
+
+	format, err := parseOutput(*output)
+	if err != nil {
+		return err
+	}
+
+	client, err := connectDaemon(*daemon)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	usage, err := client.DiskUsage(fs.Args())
+	if err != nil {
+		return err
+	}
+	if format.structured() {
+		return format.write(os.Stdout, map[string][]diskInfo{"servers": newDiskInfos(usage)})
+	}
+
+	for i := range usage {
+		disk := &usage[i]
+		fmt.Printf("%-20s  %10s  (cache %s, data %s)\n", disk.Server, formatSize(disk.Total("")),
+			formatSize(disk.Total(grpc.DiskCache)), formatSize(disk.Total(grpc.DiskData)))
+		if format == outputWide {
+			printDirs(disk.Dirs)
+		}
+	}
+	return nil
+}
+
+// runCleanup deletes the caches of stopped servers, or prints what would be
+// deleted with -dry-run
+func runCleanup(args []string) error {
+	fs := flag.NewFlagSet("cleanup", flag.ExitOnError)
+	daemon := fs.String("daemon", defaultDaemonAddress(), "Daemon address")
+	dryRun := fs.Bool("dry-run", false, "Print the caches that would be deleted")
+	output := outputFlag(fs)
+	fs.Parse(args)
+
+	format, err := parseOutput(*output)
+	if err != nil {
+		return err
+	}
+
+	client, err := connectDaemon(*daemon)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	cleaned, err := client.CleanCaches(fs.Args(), *dryRun)
+	if err != nil {
+		return err
+	}
+	if format.structured() {
+		return format.write(os.Stdout, map[string][]diskInfo{"servers": newDiskInfos(cleaned)})
+	}
+
+	var total int64
+	for i := range cleaned {
+		fmt.Println(cleaned[i].Server)
+		printDirs(cleaned[i].Dirs)
+		total += cleaned[i].Total("")
+	}
+	if *dryRun {
+		fmt.Printf("Would free %s\n", formatSize(total))
+	} else {
+		inform("Freed %s\n", formatSize(total))
+	}
+	return nil
+}
+
+// printDirs prints the directories of a server, indented
+func printDirs(dirs []grpc.DiskDir) {
+	for _, dir := range dirs {
+		fmt.Printf("  %-5s  %-16s  %10s  %s\n", dir.Kind, dir.Label, formatSize(dir.Size), dir.Path)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/docs"
+	"github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// runDocs generates the documentation of the tools of the daemon's servers
+func runDocs(args []string) error {
+	if len(args) == 0 || args[0] != "generate" {
+		return fmt.Errorf("usage: %s docs generate [-out dir] [-format html|markdown] [server...]", os.Args[0])
+	}
+
+	fs := flag.NewFlagSet("docs generate", flag.ExitOnError)
+	var (
+		daemon   = fs.String("daemon", defaultDaemonAddress(), "Daemon address")
+		out      = fs.String("out", "", "Directory the pages are written to (default: the one the daemon serves)")
+		format   = fs.String("format", "html", "Output format (html, markdown)")
+		examples = fs.Bool("examples", true, "Document tools with their latest recorded calls")
+	)
+	fs.Parse(args[1:])
+
+	dir := *out
+	if dir == "" {
+		cfg, err := config.New()
+		if err != nil {
+			return err
+		}
+		dir = cfg.GetDocsDir()
+	}
+
+	client, err := connectDaemon(*daemon)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	site, err := docsSite(client, fs.Args(), *examples)
+	if err != nil {
+		return err
+	}
+	written, err := docs.Write(dir, *format, site)
+	if err != nil {
+		return err
+	}
+	inform("Wrote %d pages to %s\n", len(written), dir)
+	if *out == "" && *format == "html" {
+		inform("The daemon serves them at %s on its -web-port\n", grpc.DocsPath)
+	}
+	return nil
+}
+
+// docsSite collects the tools of names, all servers if none are given, and
+// the examples of their recorded calls
+func docsSite(client *grpc.Client, names []string, examples bool) (*docs.Site, error) {
+	servers, order, err := client.GetServers()
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		names = order
+	}
+
+	site := &docs.Site{Generated: time.Now()}
+	for _, name := range names {
+		srv, exists := servers[name]
+		if !exists {
+			return nil, withExitCode(exitNotFound, fmt.Errorf("server '%s' %w", name, server.ErrNotFound))
+		}
+		// Only GetTools returns the input schemas
+		tools, err := client.GetTools(name)
+		if err != nil {
+			tools = srv.Tools
+		}
+		site.Servers = append(site.Servers, docs.Server{Name: name, Description: srv.Description, Tools: tools})
+	}
+
+	if examples {
+		sessions, err := sessionsBetween(client, time.Time{}, time.Time{})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: no examples, failed to read the recorded calls: %v\n", err)
+		} else {
+			site.Examples = docs.Examples(sessions)
+		}
+	}
+	return site, nil
+}
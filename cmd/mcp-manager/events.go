@@ -0,0 +1,174 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	pb "github.com/tartavull/mcp-manager/internal/grpc/pb"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// runEvents prints past events from the daemon's journal
+func runEvents(args []string) error {
+	fs := flag.NewFlagSet("events", flag.ExitOnError)
+	var (
+		daemon = fs.String("daemon", defaultDaemonAddress(), "Daemon address")
+		srv    = fs.String("server", "", "Only events about this server")
+		types  = fs.String("type", "", "Comma-separated event types, e.g. server_status,failover")
+		since  = fs.Duration("since", 0, "Only events in this past duration, e.g. 30m")
+		from   = fs.String("from", "", "Start of the time range, as HH:MM today or RFC 3339")
+		to     = fs.String("to", "", "End of the time range, as HH:MM today or RFC 3339")
+		limit  = fs.Int("n", 0, "Maximum number of events (default: 1000)")
+		output = outputFlag(fs)
+	)
+	fs.Parse(args)
+
+	format, err := parseOutput(*output)
+	if err != nil {
+		return err
+	}
+
+	query := &pb.EventQuery{Server: *srv, Limit: int32(*limit)}
+	if *since > 0 {
+		query.From = time.Now().Add(-*since).Unix()
+	}
+	for _, bound := range []struct {
+		value  string
+		target *int64
+	}{{*from, &query.From}, {*to, &query.To}} {
+		if bound.value == "" {
+			continue
+		}
+		t, err := parseEventTime(bound.value)
+		if err != nil {
+			return err
+		}
+		*bound.target = t.Unix()
+	}
+	for _, name := range strings.Split(*types, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		eventType, ok := pb.EventType_value[strings.ToUpper(name)]
+		if !ok {
+			return fmt.Errorf("unknown event type '%s'", name)
+		}
+		query.EventTypes = append(query.EventTypes, pb.EventType(eventType))
+	}
+
+	client, err := connectDaemon(*daemon)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result, err := client.QueryEvents(query)
+	if err != nil {
+		return err
+	}
+	if format.structured() {
+		list := eventList{Events: []eventInfo{}, Truncated: result.Truncated}
+		for _, event := range result.Events {
+			info, err := newEventInfo(event)
+			if err != nil {
+				return err
+			}
+			list.Events = append(list.Events, info)
+		}
+		return format.write(os.Stdout, list)
+	}
+
+	for _, event := range result.Events {
+		t := time.Unix(event.Timestamp, 0)
+		if format == outputWide {
+			fmt.Printf("%s  %-13s  %-20s  %s\n", t.Format(time.RFC3339), event.Type, event.ServerName(), describeEvent(event))
+		} else {
+			fmt.Printf("%s  %-13s  %s\n", t.Format("2006-01-02 15:04:05"), event.Type, describeEvent(event))
+		}
+	}
+	if result.Truncated {
+		fmt.Fprintf(os.Stderr, "More events matched; narrow the time range or raise -n\n")
+	}
+	return nil
+}
+
+// eventList is the schema of the events command's json and yaml output
+type eventList struct {
+	Events    []eventInfo `json:"events"`
+	Truncated bool        `json:"truncated"`
+}
+
+// eventInfo is the schema of an event in json and yaml output. Event holds
+// the event as in proto/mcp.proto.
+type eventInfo struct {
+	Time   time.Time       `json:"time"`
+	Type   string          `json:"type"`
+	Server string          `json:"server,omitempty"`
+	Event  json.RawMessage `json:"event"`
+}
+
+// newEventInfo converts an event for json and yaml output
+func newEventInfo(event *pb.Event) (eventInfo, error) {
+	data, err := protojson.Marshal(event)
+	if err != nil {
+		return eventInfo{}, fmt.Errorf("failed to encode event: %w", err)
+	}
+	return eventInfo{
+		Time:   time.Unix(event.Timestamp, 0).UTC(),
+		Type:   event.Type.String(),
+		Server: event.ServerName(),
+		Event:  data,
+	}, nil
+}
+
+// parseEventTime parses a time given as HH:MM today or RFC 3339
+func parseEventTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	clock, err := time.ParseInLocation("15:04", value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time '%s', expected HH:MM or RFC 3339", value)
+	}
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, time.Local), nil
+}
+
+// describeEvent summarizes the payload of an event
+func describeEvent(event *pb.Event) string {
+	switch payload := event.Payload.(type) {
+	case *pb.Event_ServerStatus:
+		return fmt.Sprintf("%s: %s -> %s", payload.ServerStatus.ServerName,
+			strings.ToLower(payload.ServerStatus.OldStatus.String()), strings.ToLower(payload.ServerStatus.NewStatus.String()))
+	case *pb.Event_ToolUpdate:
+		return fmt.Sprintf("%s: %d tools", payload.ToolUpdate.ServerName, payload.ToolUpdate.ToolCount)
+	case *pb.Event_ConfigChange:
+		change := payload.ConfigChange
+		return fmt.Sprintf("added %v, removed %v, modified %v, updated %v", change.ServersAdded, change.ServersRemoved, change.ServersModified, change.ServersUpdated)
+	case *pb.Event_Failover:
+		return fmt.Sprintf("%s: %s -> %s (%s)", payload.Failover.ServerName, payload.Failover.FromHost, payload.Failover.ToHost, payload.Failover.Reason)
+	case *pb.Event_CircuitBreaker:
+		if payload.CircuitBreaker.Reason == "" {
+			return fmt.Sprintf("%s: circuit %s", payload.CircuitBreaker.ServerName, payload.CircuitBreaker.State)
+		}
+		return fmt.Sprintf("%s: circuit %s (%s)", payload.CircuitBreaker.ServerName, payload.CircuitBreaker.State, payload.CircuitBreaker.Reason)
+	case *pb.Event_Approval:
+		approval := payload.Approval.GetApproval()
+		if payload.Approval.Reason == "" {
+			return fmt.Sprintf("%s: %s %s (%s)", approval.GetServerName(), approval.GetTool(), payload.Approval.State, approval.GetId())
+		}
+		return fmt.Sprintf("%s: %s %s (%s: %s)", approval.GetServerName(), approval.GetTool(), payload.Approval.State, approval.GetId(), payload.Approval.Reason)
+	case *pb.Event_PortMigration:
+		migration := payload.PortMigration
+		if migration.Reason == "" {
+			return fmt.Sprintf("%s: port %d -> %d %s", migration.ServerName, migration.OldPort, migration.NewPort, migration.State)
+		}
+		return fmt.Sprintf("%s: port %d -> %d %s (%s)", migration.ServerName, migration.OldPort, migration.NewPort, migration.State, migration.Reason)
+	default:
+		return ""
+	}
+}
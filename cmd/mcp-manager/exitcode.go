@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Exit codes of commands, for scripts to branch on
+const (
+	exitOK             = 0
+	exitFailure        = 1 // Any other failure
+	exitNotFound       = 2 // Unknown server
+	exitAlreadyRunning = 3 // Server already in the requested state
+	exitUnreachable    = 4 // Daemon unreachable
+	exitTimeout        = 5 // Operation timed out
+)
+
+// exitError sets the exit code of a failed command
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
+// withExitCode makes a command exit with code when it fails with err
+func withExitCode(code int, err error) error {
+	return &exitError{code: code, err: err}
+}
+
+// exitCode returns the exit code of a command that returned err
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return exitTimeout
+	}
+
+	if s, ok := status.FromError(err); ok {
+		switch s.Code() {
+		case codes.NotFound:
+			return exitNotFound
+		case codes.FailedPrecondition, codes.AlreadyExists:
+			return exitAlreadyRunning
+		case codes.Unavailable:
+			return exitUnreachable
+		case codes.DeadlineExceeded:
+			return exitTimeout
+		}
+	}
+	return exitFailure
+}
+
+// connectDaemon connects to the daemon, failing with exitUnreachable
+func connectDaemon(address string) (*grpc.Client, error) {
+	options, err := clientOptions(address)
+	if err != nil {
+		return nil, err
+	}
+	client, err := grpc.NewClientWithOptions(address, options)
+	if err != nil {
+		return nil, withExitCode(exitUnreachable, err)
+	}
+	return client, nil
+}
+
+// clientOptions returns the credentials of the environment and the pin of
+// the daemon at address in mcp.json
+func clientOptions(address string) (grpc.ClientOptions, error) {
+	options, err := grpc.EnvClientOptions()
+	if err != nil {
+		return options, err
+	}
+	cfg, err := config.New()
+	if err != nil {
+		return options, err
+	}
+	if options.Pin, err = cfg.DaemonPin(address); err != nil {
+		return options, fmt.Errorf("failed to read daemonPins: %w", err)
+	}
+	return options, nil
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestExitCode(t *testing.T) {
+	assert.Equal(t, exitOK, exitCode(nil))
+	assert.Equal(t, exitFailure, exitCode(errors.New("failed")))
+	assert.Equal(t, exitUnreachable, exitCode(withExitCode(exitUnreachable, errors.New("refused"))))
+	assert.Equal(t, exitTimeout, exitCode(fmt.Errorf("waiting: %w", context.DeadlineExceeded)))
+
+	assert.Equal(t, exitNotFound, exitCode(status.Error(codes.NotFound, "server 'x' not found")))
+	assert.Equal(t, exitAlreadyRunning, exitCode(status.Error(codes.FailedPrecondition, "server 'x' is already running")))
+	assert.Equal(t, exitAlreadyRunning, exitCode(status.Error(codes.AlreadyExists, "fleet 'x' already exists")))
+	assert.Equal(t, exitUnreachable, exitCode(status.Error(codes.Unavailable, "peer down")))
+	assert.Equal(t, exitTimeout, exitCode(status.Error(codes.DeadlineExceeded, "deadline exceeded")))
+
+	// Daemons that aren't coordinators fail, rather than look already running
+	assert.Equal(t, exitFailure, exitCode(status.Error(codes.Unimplemented, "daemon is not a coordinator")))
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/grpc"
+)
+
+// fleetInfo is the schema of a fleet in json and yaml output
+type fleetInfo struct {
+	RunID   string       `json:"runId"`
+	Dir     string       `json:"dir"`
+	Created time.Time    `json:"created"`
+	Servers []serverInfo `json:"servers"`
+}
+
+// newFleetInfo converts a fleet for json and yaml output
+func newFleetInfo(fleet *grpc.Fleet) fleetInfo {
+	info := fleetInfo{RunID: fleet.RunID, Dir: fleet.Dir, Created: fleet.Created.UTC(), Servers: []serverInfo{}}
+	for _, srv := range fleet.Servers {
+		info.Servers = append(info.Servers, newServerInfo(srv))
+	}
+	return info
+}
+
+// runFleet creates, destroys and lists the isolated copies of servers of
+// evaluation runs
+func runFleet(args []string) error {
+	usage := fmt.Errorf("usage: %s fleet create|destroy|list [run] [server...]", os.Args[0])
+	if len(args) == 0 {
+		return usage
+	}
+
+	fs := flag.NewFlagSet("fleet "+args[0], flag.ExitOnError)
+	daemon := fs.String("daemon", defaultDaemonAddress(), "Daemon address")
+	start := fs.Bool("start", false, "Start the copies once created")
+	output := outputFlag(fs)
+	fs.Parse(args[1:])
+
+	format, err := parseOutput(*output)
+	if err != nil {
+		return err
+	}
+
+	client, err := connectDaemon(*daemon)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	switch args[0] {
+	case "create":
+		if fs.NArg() == 0 {
+			return fmt.Errorf("usage: %s fleet create [-start] <run> [server...]", os.Args[0])
+		}
+		fleet, err := client.CreateFleet(fs.Arg(0), fs.Args()[1:], *start)
+		if err != nil {
+			return err
+		}
+		if format.structured() {
+			return format.write(os.Stdout, newFleetInfo(fleet))
+		}
+		printFleet(fleet, format == outputWide)
+		return nil
+	case "destroy":
+		if fs.NArg() == 0 {
+			return fmt.Errorf("usage: %s fleet destroy <run>...", os.Args[0])
+		}
+		for _, runID := range fs.Args() {
+			if err := client.DestroyFleet(runID); err != nil {
+				return err
+			}
+			inform("Destroyed fleet %s\n", runID)
+		}
+		return nil
+	case "list":
+		fleets, err := client.ListFleets()
+		if err != nil {
+			return err
+		}
+		if format.structured() {
+			infos := make([]fleetInfo, len(fleets))
+			for i := range fleets {
+				infos[i] = newFleetInfo(&fleets[i])
+			}
+			return format.write(os.Stdout, map[string][]fleetInfo{"fleets": infos})
+		}
+		if len(fleets) == 0 {
+			inform("No fleets\n")
+			return nil
+		}
+		for i := range fleets {
+			if i > 0 {
+				fmt.Println()
+			}
+			printFleet(&fleets[i], format == outputWide)
+		}
+		return nil
+	default:
+		return usage
+	}
+}
+
+// printFleet prints a fleet and the table of its servers
+func printFleet(fleet *grpc.Fleet, wide bool) {
+	fmt.Printf("Fleet %s (%s)\n", fleet.RunID, fleet.Dir)
+	fmt.Println(serverHeader(wide))
+	for _, srv := range fleet.Servers {
+		fmt.Println(serverRow(srv, wide, false))
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/secrets"
+	"github.com/tartavull/mcp-manager/internal/service"
+	"github.com/tartavull/mcp-manager/internal/wizard"
+)
+
+// runInit runs the first-run setup wizard
+func runInit(args []string) error {
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	var (
+		force       = fs.Bool("force", false, "Overwrite an existing mcp.json")
+		daemon      = fs.String("daemon", defaultDaemonAddress(), "Daemon address used when installing the service")
+		catalogFile = fs.String("catalog", "", "Catalog file or URL offered along with the built-in one")
+	)
+	fs.Parse(args)
+
+	if machine {
+		return fmt.Errorf("init asks questions, it can't run in machine mode")
+	}
+
+	cfg, err := config.New()
+	if err != nil {
+		return err
+	}
+
+	if cfg.MCPConfigExists() && !*force {
+		return fmt.Errorf("%s already exists (use -force to overwrite)", cfg.GetMCPConfigPath())
+	}
+
+	return runWizard(cfg, *daemon, *catalogFile)
+}
+
+// runWizard runs the setup wizard on the terminal and saves its result,
+// offering the entries of catalogSource too if it isn't empty
+func runWizard(cfg *config.Config, daemonAddress, catalogSource string) error {
+	w := wizard.New(os.Stdin, os.Stdout)
+
+	// The provenance policy of the mcp.json being replaced still applies
+	policy, err := loadPolicy()
+	if err != nil {
+		return err
+	}
+	w.Policy = policy
+	if catalogSource != "" {
+		if w.Entries, err = loadCatalog(catalogSource, policy); err != nil {
+			return err
+		}
+	}
+
+	w.BasePort = cfg.DefaultBasePort()
+	if isTerminal(os.Stdin) {
+		w.ReadSecret = func() (string, error) {
+			secret, err := term.ReadPassword(os.Stdin.Fd())
+			return string(secret), err
+		}
+	}
+
+	// mcp.json doesn't exist yet, so secrets go to the default store
+	opts := secrets.ConfigOptions(cfg, nil)
+	opts.Passphrase = readPassphrase
+	store, err := secrets.Open(opts)
+	if err != nil {
+		return err
+	}
+	w.Secrets = store
+
+	result, err := w.Run()
+	if err != nil {
+		return err
+	}
+
+	if err := cfg.SaveMCPConfig(result.Config); err != nil {
+		return err
+	}
+	inform("\nSaved %d servers to %s\n", len(result.Config.ServerOrder), cfg.GetMCPConfigPath())
+	if usesSecrets(result.Config) && store.Backend() == secrets.BackendFile {
+		inform("Secrets are encrypted in %s; set %s for the daemon to read them\n", cfg.GetSecretsPath(), secrets.PassphraseEnv)
+	}
+
+	if result.InstallService {
+		if err := installService(cfg, daemonAddress); err != nil {
+			// The config is saved, so don't fail the whole setup
+			fmt.Fprintf(os.Stderr, "Failed to install daemon service: %v\n", err)
+			fmt.Fprintf(os.Stderr, "You can start the daemon manually with: mcp-daemon start\n")
+		}
+	}
+
+	return nil
+}
+
+// usesSecrets returns true if any server references a secret
+func usesSecrets(mcpConfig *config.MCPConfig) bool {
+	for _, srv := range mcpConfig.Servers {
+		if secrets.HasRefs(srv.Env) {
+			return true
+		}
+	}
+	return false
+}
+
+// installService installs the daemon found next to this executable as a user service
+func installService(cfg *config.Config, daemonAddress string) error {
+	if cfg.Instance != "" {
+		return fmt.Errorf("services can only be installed for the default instance")
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate current executable: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+
+	daemonPath := filepath.Join(filepath.Dir(exe), "mcp-daemon")
+	if _, err := os.Stat(daemonPath); err != nil {
+		return fmt.Errorf("daemon binary not found at %s", daemonPath)
+	}
+
+	port := cfg.DaemonPort()
+	if _, p, err := net.SplitHostPort(daemonAddress); err == nil {
+		if port, err = strconv.Atoi(p); err != nil {
+			return fmt.Errorf("invalid daemon port '%s'", p)
+		}
+	}
+
+	path, err := service.Install(daemonPath, port, cfg.GetLogPath("daemon.log"))
+	if err != nil {
+		return err
+	}
+	inform("Installed daemon service at %s\n", path)
+	return nil
+}
+
+// isTerminal returns true if f is an interactive terminal, never in
+// machine mode
+func isTerminal(f *os.File) bool {
+	return !machine && term.IsTerminal(f.Fd())
+}
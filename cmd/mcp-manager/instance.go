@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+)
+
+// defaultDaemonAddress returns the address of the instance's daemon
+func defaultDaemonAddress() string {
+	if cfg, err := config.New(); err == nil {
+		return cfg.DaemonAddress()
+	}
+	return fmt.Sprintf("localhost:%d", config.DefaultDaemonPort)
+}
+
+// takeInstanceFlag removes a leading -instance flag from args, selecting
+// the instance for this process and the ones it starts
+func takeInstanceFlag(args []string) ([]string, error) {
+	if len(args) == 0 {
+		return args, nil
+	}
+
+	name, value, hasValue := strings.Cut(strings.TrimPrefix(strings.TrimPrefix(args[0], "-"), "-"), "=")
+	if !strings.HasPrefix(args[0], "-") || name != "instance" {
+		return args, nil
+	}
+	rest := args[1:]
+	if !hasValue {
+		if len(rest) == 0 {
+			return nil, fmt.Errorf("flag needs an argument: -instance")
+		}
+		value, rest = rest[0], rest[1:]
+	}
+	if err := config.ValidateInstance(value); err != nil {
+		return nil, err
+	}
+	return rest, os.Setenv(config.InstanceEnv, value)
+}
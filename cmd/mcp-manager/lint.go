@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/lint"
+)
+
+// runConfig runs a subcommand checking mcp.json
+func runConfig(args []string) error {
+	if len(args) == 0 || args[0] != "lint" {
+		return fmt.Errorf("usage: %s config lint [-severity level] [-fail-on level] [-o json|yaml]", os.Args[0])
+	}
+	return runLint(args[1:])
+}
+
+// runLint prints the risky settings of mcp.json, failing if any is at
+// least as severe as -fail-on
+func runLint(args []string) error {
+	levels := strings.Join(lint.Severities, ", ")
+	fs := flag.NewFlagSet("config lint", flag.ExitOnError)
+	var (
+		severity = fs.String("severity", lint.Info, "Least severe findings printed ("+levels+")")
+		failOn   = fs.String("fail-on", lint.Error, "Least severe findings failing the command ("+levels+")")
+		output   = outputFlag(fs)
+	)
+	fs.Parse(args)
+
+	for _, level := range []string{*severity, *failOn} {
+		if !slices.Contains(lint.Severities, level) {
+			return fmt.Errorf("unknown severity '%s', expected %s", level, levels)
+		}
+	}
+	format, err := parseOutput(*output)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.New()
+	if err != nil {
+		return err
+	}
+	mcpConfig, err := cfg.LoadMCPConfig()
+	if err != nil {
+		return err
+	}
+
+	findings := []lint.Finding{}
+	failed := 0
+	for _, finding := range lint.Lint(mcpConfig) {
+		if lint.AtLeast(finding.Severity, *failOn) {
+			failed++
+		}
+		if lint.AtLeast(finding.Severity, *severity) {
+			findings = append(findings, finding)
+		}
+	}
+
+	if format.structured() {
+		if err := format.write(os.Stdout, map[string][]lint.Finding{"findings": findings}); err != nil {
+			return err
+		}
+	} else if len(findings) == 0 {
+		inform("No findings in %s\n", cfg.GetMCPConfigPath())
+	} else {
+		for _, finding := range findings {
+			subject := finding.Server
+			if subject == "" {
+				subject = "mcp.json"
+			}
+			fmt.Printf("%-8s %-22s %-16s %s\n", strings.ToUpper(finding.Severity), finding.Rule, subject, finding.Message)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d findings at %s level or above", failed, *failOn)
+	}
+	return nil
+}
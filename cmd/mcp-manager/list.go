@@ -0,0 +1,141 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// runList prints the names of the daemon's servers, or their full records
+// with -o json for inventories
+func runList(args []string) error {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	var (
+		daemon = fs.String("daemon", defaultDaemonAddress(), "Daemon address")
+		tag    = fs.String("tag", "", "Only print the servers labeled with this tag")
+		output = outputFlag(fs)
+	)
+	fs.Parse(args)
+
+	format, err := parseOutput(*output)
+	if err != nil {
+		return err
+	}
+
+	servers, err := listServers(*daemon, *tag)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case format.structured():
+		for _, srv := range servers {
+			srv.StartedAt, srv.LastUpdated = srv.StartedAt.UTC(), srv.LastUpdated.UTC()
+		}
+		return format.write(os.Stdout, map[string][]*server.Server{"servers": servers})
+	case format == outputWide:
+		fmt.Println(serverHeader(true))
+		for _, srv := range servers {
+			fmt.Println(serverRow(srv, true, colorOutput()))
+		}
+	default:
+		for _, srv := range servers {
+			fmt.Println(srv.Name)
+		}
+	}
+	return nil
+}
+
+// runTools prints the tools of the daemon's servers, or of the servers
+// given
+func runTools(args []string) error {
+	fs := flag.NewFlagSet("tools", flag.ExitOnError)
+	var (
+		daemon = fs.String("daemon", defaultDaemonAddress(), "Daemon address")
+		tag    = fs.String("tag", "", "Only print the tools of servers labeled with this tag")
+		output = outputFlag(fs)
+	)
+	fs.Parse(args)
+
+	format, err := parseOutput(*output)
+	if err != nil {
+		return err
+	}
+
+	servers, err := listServers(*daemon, *tag)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		byName := make(map[string]*server.Server, len(servers))
+		for _, srv := range servers {
+			byName[srv.Name] = srv
+		}
+		servers = servers[:0]
+		for _, name := range fs.Args() {
+			srv, exists := byName[name]
+			if !exists {
+				return fmt.Errorf("server '%s' %w", name, server.ErrNotFound)
+			}
+			servers = append(servers, srv)
+		}
+	}
+
+	tools := []toolInfo{}
+	for _, srv := range servers {
+		for _, tool := range srv.Tools {
+			tools = append(tools, toolInfo{Server: srv.Name, Tool: tool})
+		}
+	}
+	if format.structured() {
+		return format.write(os.Stdout, map[string][]toolInfo{"tools": tools})
+	}
+
+	if len(tools) == 0 {
+		inform("No tools, start servers to list theirs\n")
+		return nil
+	}
+	fmt.Printf("%-20s %-30s %s\n", "SERVER", "TOOL", "DESCRIPTION")
+	for _, tool := range tools {
+		fmt.Printf("%-20s %-30s %s\n", tool.Server, tool.Name, firstLine(tool.Description))
+	}
+	return nil
+}
+
+// toolInfo is a tool in the tools command's json and yaml output, along
+// with the server providing it
+type toolInfo struct {
+	Server string `json:"server"`
+	server.Tool
+}
+
+// firstLine returns the first line of a description
+func firstLine(description string) string {
+	line, _, _ := strings.Cut(description, "\n")
+	return line
+}
+
+// listServers returns the daemon's servers in order, only those labeled
+// with tag unless it's empty
+func listServers(address, tag string) ([]*server.Server, error) {
+	client, err := connectDaemon(address)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	servers, order, err := client.GetServers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list servers: %w", err)
+	}
+	listed := []*server.Server{}
+	for _, name := range order {
+		if srv, exists := servers[name]; exists && (tag == "" || srv.HasTag(tag)) {
+			listed = append(listed, srv)
+		}
+	}
+	return listed, nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/logs"
+)
+
+// runLogs prints the captured output of a server running in the daemon
+func runLogs(args []string) error {
+	fs := flag.NewFlagSet("logs", flag.ExitOnError)
+	var (
+		daemon = fs.String("daemon", defaultDaemonAddress(), "Daemon address")
+		follow = fs.Bool("f", false, "Keep printing new lines")
+		tail   = fs.Int("n", 100, "Number of lines of history to print (0 for all)")
+		output = outputFlag(fs)
+	)
+	fs.Parse(args)
+
+	format, err := parseOutput(*output)
+	if err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: %s logs [-f] [-n lines] <server>", os.Args[0])
+	}
+
+	client, err := connectDaemon(*daemon)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	return client.StreamLogs(ctx, fs.Arg(0), *follow, *tail, func(line logs.Line) {
+		switch {
+		case format.structured():
+			format.writeItem(os.Stdout, logLine{
+				Time:     line.Time.UTC(),
+				Stream:   line.Stream,
+				Severity: string(line.Severity),
+				Text:     line.Text,
+			})
+		case format == outputWide:
+			severity := string(line.Severity)
+			if severity == "" {
+				severity = "-"
+			}
+			fmt.Printf("%s %-6s %-7s %s\n", line.Time.Format(time.RFC3339Nano), line.Stream, severity, line.Text)
+		default:
+			out := os.Stdout
+			if line.Stream == "stderr" {
+				out = os.Stderr
+			}
+			fmt.Fprintf(out, "%s %s\n", line.Time.Format("15:04:05.000"), line.Text)
+		}
+	})
+}
+
+// logLine is the schema of a line in the logs command's json and yaml
+// output
+type logLine struct {
+	Time     time.Time `json:"time"`
+	Stream   string    `json:"stream"`
+	Severity string    `json:"severity,omitempty"`
+	Text     string    `json:"text"`
+}
@@ -5,30 +5,76 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"path/filepath"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/tartavull/mcp-manager/internal/api"
+	"github.com/tartavull/mcp-manager/internal/auth"
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/i18n"
 	"github.com/tartavull/mcp-manager/internal/tui"
 )
 
-const (
-	defaultDaemonAddress = "localhost:8080"
-)
-
 func main() {
+	// Leading -instance, -quiet and -machine apply to subcommands too
+	args, err := takeGlobalFlags(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	os.Args = append(os.Args[:1], args...)
+
+	// Messages are translated before any is printed
+	locale := ""
+	if cfg, err := config.New(); err == nil {
+		locale = cfg.Locale()
+	}
+	i18n.SetLocale(i18n.Detect(locale))
+
+	// Subcommands run without the TUI
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-") {
+		if err := runCommand(os.Args[1], os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+		return
+	}
+
 	var (
-		daemon     = flag.String("daemon", defaultDaemonAddress, "Daemon address (use 'direct' for standalone mode)")
+		daemon     = flag.String("daemon", "", "Daemon address, ssh://user@host[/address] for a remote daemon or unix://path for a unix socket (use 'direct' for standalone mode, default: the instance's daemon)")
 		standalone = flag.Bool("standalone", false, "Run in standalone mode without daemon")
+		overview   = flag.Bool("overview", false, "Start on the overview screen")
+		title      = flag.Bool("title", false, "Show running/total servers in the terminal title")
+		debug      = flag.Bool("debug", false, "Log how long each frame of the TUI takes to render")
+		tlsFiles   = auth.EnvTLSFiles()
 	)
+	flag.StringVar(&tlsFiles.CA, "tls-ca", tlsFiles.CA, "PEM CAs verifying the daemon, enabling TLS (default: $"+auth.CAEnv+")")
+	flag.StringVar(&tlsFiles.Cert, "tls-cert", tlsFiles.Cert, "PEM client certificate (default: $"+auth.CertEnv+")")
+	flag.StringVar(&tlsFiles.Key, "tls-key", tlsFiles.Key, "Private key of the client certificate (default: $"+auth.KeyEnv+")")
+	flag.StringVar(&tlsFiles.ServerName, "tls-server-name", tlsFiles.ServerName, "Name the daemon's certificate is verified for (default: $"+auth.ServerNameEnv+")")
+	config.RegisterDirFlags(flag.CommandLine)
 
 	flag.Parse()
+	if machine {
+		fmt.Fprintln(os.Stderr, "Error: the TUI is interactive, run a subcommand in machine mode")
+		os.Exit(1)
+	}
+	if *daemon == "" {
+		*daemon = defaultDaemonAddress()
+	}
+
+	// Walk new users through setup before the first launch
+	if cfg, err := config.New(); err == nil && !cfg.MCPConfigExists() && isTerminal(os.Stdin) {
+		if err := runWizard(cfg, *daemon, ""); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
 
 	// Setup logging to file to avoid breaking TUI
-	if homeDir, err := os.UserHomeDir(); err == nil {
-		logDir := filepath.Join(homeDir, ".mcp-manager")
-		os.MkdirAll(logDir, 0755)
-		if logFile, err := os.OpenFile(filepath.Join(logDir, "mcp-manager.log"),
+	if cfg, err := config.New(); err == nil {
+		if logFile, err := os.OpenFile(cfg.GetLogPath("mcp-manager.log"),
 			os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644); err == nil {
 			log.SetOutput(logFile)
 			defer logFile.Close()
@@ -37,7 +83,7 @@ func main() {
 
 	// Determine which mode to run in
 	var manager api.ManagerInterface
-	var err error
+	var daemonAdapter *api.GRPCAdapter
 
 	if *standalone || *daemon == "direct" {
 		// Standalone mode - direct manager access
@@ -51,23 +97,24 @@ func main() {
 		log.Printf("Connecting to daemon at %s", *daemon)
 
 		// Try to connect to daemon
-		grpcAdapter, err := api.NewGRPCAdapter(*daemon)
+		options, err := clientOptions(*daemon)
+		if err == nil {
+			options.TLS, err = auth.LoadClientTLS(tlsFiles)
+		}
+		if err != nil {
+			log.Fatalf("Invalid connection settings: %v", err)
+		}
+		grpcAdapter, err := api.NewGRPCAdapterWithOptions(*daemon, options)
 		if err != nil {
 			// Check if we should suggest starting the daemon
-			fmt.Fprintf(os.Stderr, "Failed to connect to daemon at %s: %v\n", *daemon, err)
-			fmt.Fprintf(os.Stderr, "\nMake sure the daemon is running:\n")
+			fmt.Fprintln(os.Stderr, i18n.T("Failed to connect to daemon at %s: %v", *daemon, err))
+			fmt.Fprintf(os.Stderr, "\n%s\n", i18n.T("Make sure the daemon is running:"))
 			fmt.Fprintf(os.Stderr, "  mcp-daemon start\n\n")
-			fmt.Fprintf(os.Stderr, "Or run in standalone mode:\n")
+			fmt.Fprintln(os.Stderr, i18n.T("Or run in standalone mode:"))
 			fmt.Fprintf(os.Stderr, "  %s -standalone\n", os.Args[0])
 			os.Exit(1)
 		}
 
-		// Set up callback for real-time updates
-		grpcAdapter.SetOnServerUpdate(func() {
-			// This will be called when server status changes
-			// The TUI will handle the refresh
-		})
-
 		// Check daemon health
 		if health, err := grpcAdapter.Client.Health(); err != nil {
 			log.Printf("Warning: Failed to check daemon health: %v", err)
@@ -77,6 +124,7 @@ func main() {
 		}
 
 		manager = grpcAdapter
+		daemonAdapter = grpcAdapter
 	}
 
 	// Ensure cleanup on exit
@@ -88,8 +136,35 @@ func main() {
 
 	// Create and run TUI
 	model := tui.New(manager)
+	if *overview {
+		model = model.WithOverview()
+	}
+	if *title {
+		model = model.WithWindowTitle()
+	}
+	if *debug {
+		model = model.WithDebug()
+	}
+	if isTerminal(os.Stdout) {
+		// Ticks slow down while the terminal is unfocused
+		model = model.WithFocusEvents()
+		defer tui.ReportFocus(os.Stdout)()
+	}
 	p := tea.NewProgram(model, tea.WithAltScreen())
 
+	// The TUI renders the servers as the daemon reports changes, rather
+	// than asking for them every frame, and covers them while the daemon
+	// is lost until they are refreshed from it again
+	if daemonAdapter != nil {
+		daemonAdapter.SetOnServerUpdate(func() {
+			p.Send(tui.Changed())
+		})
+		daemonAdapter.SetOnConnectionState(func(state grpc.ConnectionState) {
+			log.Printf("Daemon event stream %s", state)
+			p.Send(tui.Changed())
+		})
+	}
+
 	if _, err := p.Run(); err != nil {
 		log.Fatalf("Error running TUI: %v", err)
 	}
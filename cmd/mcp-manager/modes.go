@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+var (
+	// quiet leaves only the data asked for on stdout: confirmations,
+	// progress and hints aren't printed. Errors still go to stderr.
+	quiet bool
+
+	// machine is quiet, and also never colors output nor asks questions,
+	// as if no terminal were attached, for programs embedding the CLI
+	machine bool
+)
+
+// takeGlobalFlags removes the leading flags of all subcommands from args:
+// -instance, -quiet and -machine, in any order
+func takeGlobalFlags(args []string) ([]string, error) {
+	for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+		switch strings.TrimLeft(args[0], "-") {
+		case "quiet":
+			quiet = true
+		case "machine":
+			quiet, machine = true, true
+		default:
+			rest, err := takeInstanceFlag(args)
+			if err != nil || len(rest) == len(args) {
+				return rest, err
+			}
+			args = rest
+			continue
+		}
+		args = args[1:]
+	}
+	return args, nil
+}
+
+// inform prints a confirmation, progress or hint to stdout unless quiet
+func inform(format string, args ...interface{}) {
+	if !quiet {
+		fmt.Printf(format, args...)
+	}
+}
+
+// colorOutput returns true if stdout is a terminal to color, unless
+// NO_COLOR is set
+func colorOutput() bool {
+	return isTerminal(os.Stdout) && os.Getenv("NO_COLOR") == ""
+}
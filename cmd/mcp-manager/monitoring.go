@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/metrics"
+)
+
+// runMonitoring prints a Grafana dashboard or Prometheus alert rules for
+// the metrics the daemon serves on its -http-port, naming the servers of
+// mcp.json
+func runMonitoring(args []string) error {
+	usage := fmt.Errorf("usage: %s monitoring dashboard|alerts [flags]", os.Args[0])
+	if len(args) == 0 {
+		return usage
+	}
+
+	options := metrics.DefaultAlertOptions
+	fs := flag.NewFlagSet("monitoring "+args[0], flag.ExitOnError)
+	fs.DurationVar(&options.DownFor, "down-for", options.DownFor, "Time an autostart server stays down before alerting")
+	fs.IntVar(&options.Restarts, "restarts", options.Restarts, "Automatic restarts within -restart-window making a restart storm")
+	fs.DurationVar(&options.RestartWindow, "restart-window", options.RestartWindow, "Window of -restarts")
+	fs.DurationVar(&options.LatencyP95, "latency-p95", options.LatencyP95, "p95 call latency alerted on after 10m")
+	fs.Parse(args[1:])
+
+	cfg, err := config.New()
+	if err != nil {
+		return err
+	}
+	mcpConfig, err := cfg.LoadMCPConfig()
+	if err != nil {
+		return err
+	}
+	var autostart []string
+	for _, name := range mcpConfig.ServerOrder {
+		if mcpConfig.Servers[name].Autostart {
+			autostart = append(autostart, name)
+		}
+	}
+
+	var data []byte
+	switch args[0] {
+	case "dashboard":
+		data, err = metrics.Dashboard(mcpConfig.ServerOrder)
+	case "alerts":
+		data, err = metrics.AlertRules(mcpConfig.ServerOrder, autostart, options)
+	default:
+		return usage
+	}
+	if err != nil {
+		return err
+	}
+	os.Stdout.Write(data)
+	if data[len(data)-1] != '\n' {
+		fmt.Println()
+	}
+	return nil
+}
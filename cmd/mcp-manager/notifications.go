@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tartavull/mcp-manager/internal/notify"
+)
+
+// notificationInfo is the schema of a server's notification level in json
+// and yaml output
+type notificationInfo struct {
+	Server string `json:"server"`
+	Level  string `json:"level"`
+	Custom bool   `json:"custom"`
+}
+
+// runNotifications lists the levels of the daemon's desktop notifications
+// of each server, or sets and resets the level of a server
+func runNotifications(args []string) error {
+	usage := fmt.Errorf("usage: %s notifications [list | set <server> <%s> | reset <server>]", os.Args[0], strings.Join(notify.Levels, "|"))
+	action := "list"
+	if len(args) > 0 && (args[0] == "list" || args[0] == "set" || args[0] == "reset") {
+		action, args = args[0], args[1:]
+	}
+
+	fs := flag.NewFlagSet("notifications "+action, flag.ExitOnError)
+	daemon := fs.String("daemon", defaultDaemonAddress(), "Daemon address")
+	output := outputFlag(fs)
+	fs.Parse(args)
+
+	format, err := parseOutput(*output)
+	if err != nil {
+		return err
+	}
+	if (action == "set" && fs.NArg() != 2) || (action == "reset" && fs.NArg() != 1) {
+		return usage
+	}
+
+	client, err := connectDaemon(*daemon)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	switch action {
+	case "set":
+		if err := client.SetNotification(fs.Arg(0), fs.Arg(1)); err != nil {
+			return err
+		}
+		inform("Notifications of %s set to %s\n", fs.Arg(0), fs.Arg(1))
+		return nil
+	case "reset":
+		if err := client.SetNotification(fs.Arg(0), ""); err != nil {
+			return err
+		}
+		inform("Notifications of %s reset to the default\n", fs.Arg(0))
+		return nil
+	}
+
+	level, preferences, err := client.Notifications()
+	if err != nil {
+		return err
+	}
+	if format.structured() {
+		infos := make([]notificationInfo, len(preferences))
+		for i, preference := range preferences {
+			infos[i] = notificationInfo{Server: preference.Server, Level: preference.Level, Custom: preference.Custom}
+		}
+		return format.write(os.Stdout, map[string]interface{}{"default": level, "servers": infos})
+	}
+
+	fmt.Printf("Default: %s\n", level)
+	for _, preference := range preferences {
+		source := "default"
+		if preference.Custom {
+			source = "set"
+		}
+		fmt.Printf("  %-20s  %-6s  (%s)\n", preference.Server, preference.Level, source)
+	}
+	return nil
+}
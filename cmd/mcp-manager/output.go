@@ -0,0 +1,243 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/server"
+	"gopkg.in/yaml.v3"
+)
+
+// outputFormat selects how read commands print their results
+type outputFormat string
+
+const (
+	outputTable outputFormat = ""
+	outputWide  outputFormat = "wide"
+	outputJSON  outputFormat = "json"
+	outputYAML  outputFormat = "yaml"
+)
+
+// outputFlag adds the -o flag to a command, and -json for -o json
+func outputFlag(fs *flag.FlagSet) *string {
+	output := fs.String("o", "", "Output format: json, yaml or wide")
+	fs.BoolFunc("json", "Same as -o json", func(string) error {
+		*output = string(outputJSON)
+		return nil
+	})
+	return output
+}
+
+// parseOutput validates the value of the -o flag
+func parseOutput(value string) (outputFormat, error) {
+	switch format := outputFormat(value); format {
+	case outputTable, outputWide, outputJSON, outputYAML:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown output format '%s', expected json, yaml or wide", value)
+	}
+}
+
+// structured returns true for the formats meant for scripts
+func (f outputFormat) structured() bool {
+	return f == outputJSON || f == outputYAML
+}
+
+// write encodes v as indented JSON or as YAML with the same keys
+func (f outputFormat) write(w io.Writer, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+	if f == outputJSON {
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
+	}
+
+	// JSON is YAML; decoding it as a node keeps the key order
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+	plainStyle(&node)
+	out, err := yaml.Marshal(&node)
+	if err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+// writeItem encodes v as an item of a stream: a line of JSON or a YAML
+// document
+func (f outputFormat) writeItem(w io.Writer, v any) error {
+	if f == outputJSON {
+		return json.NewEncoder(w).Encode(v)
+	}
+	if _, err := fmt.Fprintln(w, "---"); err != nil {
+		return err
+	}
+	return f.write(w, v)
+}
+
+// plainStyle clears the flow style and quotes YAML keeps from JSON input;
+// strings that need quotes are still quoted
+func plainStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		plainStyle(child)
+	}
+}
+
+// serverInfo is the schema of a server in json and yaml output
+type serverInfo struct {
+	Name          string     `json:"name"`
+	Status        string     `json:"status"`
+	Health        string     `json:"health,omitempty"`
+	HealthMessage string     `json:"healthMessage,omitempty"`
+	Port          int        `json:"port"`
+	PID           int        `json:"pid"`
+	Host          string     `json:"host,omitempty"`
+	RunID         string     `json:"runId,omitempty"`
+	Group         string     `json:"group,omitempty"`
+	Tags          []string   `json:"tags,omitempty"`
+	StartedAt     *time.Time `json:"startedAt,omitempty"`
+	UptimeSeconds int64      `json:"uptimeSeconds"`
+	Restarts      int        `json:"restarts"`
+	Maintenance   bool       `json:"maintenance"`
+	Draining      bool       `json:"draining,omitempty"`
+	Requests      int64      `json:"requests"`
+	InFlight      int64      `json:"inFlight"`
+	ToolCount     int        `json:"toolCount"`
+	Tools         []string   `json:"tools"`
+	Description   string     `json:"description,omitempty"`
+}
+
+// newServerInfo converts a server for json and yaml output
+func newServerInfo(srv *server.Server) serverInfo {
+	info := serverInfo{
+		Name:          srv.Name,
+		Status:        string(srv.Status),
+		Health:        string(srv.Health),
+		HealthMessage: srv.HealthMessage,
+		Port:          srv.Port,
+		PID:           srv.PID,
+		Host:          srv.Host,
+		RunID:         srv.RunID,
+		Group:         srv.Group,
+		Tags:          srv.Tags,
+		Restarts:      srv.Restarts,
+		Maintenance:   srv.Maintenance,
+		Draining:      srv.Draining,
+		Requests:      srv.Requests,
+		InFlight:      srv.InFlight,
+		ToolCount:     srv.ToolCount,
+		Tools:         make([]string, len(srv.Tools)),
+		Description:   srv.Description,
+	}
+	if !srv.StartedAt.IsZero() {
+		startedAt := srv.StartedAt.UTC()
+		info.StartedAt = &startedAt
+		info.UptimeSeconds = int64(time.Since(srv.StartedAt).Seconds())
+	}
+	for i, tool := range srv.Tools {
+		info.Tools[i] = tool.Name
+	}
+	return info
+}
+
+// ANSI colors of server statuses in terminal output
+const (
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+	colorRed    = "\033[31m"
+	colorReset  = "\033[0m"
+)
+
+// statusDraining is shown for running servers being drained
+const statusDraining = "draining"
+
+// serverHeader returns the header of the server table
+func serverHeader(wide bool) string {
+	header := fmt.Sprintf("%-20s %-10s %-6s %-6s", "NAME", "STATUS", "PORT", "TOOLS")
+	if wide {
+		header += fmt.Sprintf(" %-8s %-10s %-9s %-8s %-9s %-16s %s", "PID", "HEALTH", "UPTIME", "RESTARTS", "REQUESTS", "TAGS", "HOST")
+	}
+	return strings.TrimRight(header, " ")
+}
+
+// serverRow formats a server as a row of the server table, coloring it by
+// status if color is set
+func serverRow(srv *server.Server, wide, color bool) string {
+	status := string(srv.Status)
+	if srv.IsRunning() && srv.Health == server.HealthUnhealthy {
+		status = string(server.HealthUnhealthy)
+	}
+	if srv.IsRunning() && srv.Draining {
+		status = statusDraining
+	}
+	tools := "-"
+	if srv.IsRunning() {
+		tools = strconv.Itoa(srv.ToolCount)
+	}
+
+	row := fmt.Sprintf("%-20s %-10s %-6d %-6s", srv.Name, status, srv.Port, tools)
+	if wide {
+		pid, uptime := "-", "-"
+		if srv.PID > 0 {
+			pid = strconv.Itoa(srv.PID)
+		}
+		if !srv.StartedAt.IsZero() {
+			uptime = formatUptime(time.Since(srv.StartedAt))
+		}
+		health := string(srv.Health)
+		if health == "" {
+			health = "-"
+		}
+		tags := strings.Join(srv.Tags, ",")
+		if tags == "" {
+			tags = "-"
+		}
+		row += fmt.Sprintf(" %-8s %-10s %-9s %-8d %-9d %-16s %s", pid, health, uptime, srv.Restarts, srv.Requests, tags, srv.Host)
+	}
+	row = strings.TrimRight(row, " ")
+	if !color {
+		return row
+	}
+
+	switch status {
+	case string(server.StatusRunning):
+		return colorGreen + row + colorReset
+	case string(server.StatusStarting), string(server.StatusStopping), statusDraining:
+		return colorYellow + row + colorReset
+	case string(server.StatusError), string(server.HealthUnhealthy):
+		return colorRed + row + colorReset
+	default:
+		return row
+	}
+}
+
+// formatUptime formats a duration with its two largest units, e.g. 2d3h
+func formatUptime(d time.Duration) string {
+	d = d.Truncate(time.Second)
+	days := int(d / (24 * time.Hour))
+	hours := int(d / time.Hour % 24)
+	minutes := int(d / time.Minute % 60)
+	seconds := int(d / time.Second % 60)
+
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd%dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh%dm", hours, minutes)
+	case minutes > 0:
+		return fmt.Sprintf("%dm%ds", minutes, seconds)
+	default:
+		return fmt.Sprintf("%ds", seconds)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tartavull/mcp-manager/internal/plugin"
+)
+
+// pluginInfo is the schema of a plugin in json and yaml output
+type pluginInfo struct {
+	Name         string      `json:"name"`
+	Path         string      `json:"path"`
+	Version      string      `json:"version,omitempty"`
+	Description  string      `json:"description,omitempty"`
+	Kinds        []string    `json:"kinds"`
+	ConfigSchema interface{} `json:"configSchema,omitempty"`
+	Error        string      `json:"error,omitempty"`
+}
+
+// runPlugins prints the plugins the daemon found, or the config schema of
+// one of them
+func runPlugins(args []string) error {
+	fs := flag.NewFlagSet("plugins", flag.ExitOnError)
+	daemon := fs.String("daemon", defaultDaemonAddress(), "Daemon address")
+	schema := fs.String("schema", "", "Print the config schema of a plugin")
+	output := outputFlag(fs)
+	fs.Parse(args)
+
+	format, err := parseOutput(*output)
+	if err != nil {
+		return err
+	}
+
+	client, err := connectDaemon(*daemon)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	plugins, err := client.ListPlugins()
+	if err != nil {
+		return err
+	}
+
+	if *schema != "" {
+		for _, p := range plugins {
+			if p.Name != *schema {
+				continue
+			}
+			if len(p.ConfigSchema) == 0 {
+				return fmt.Errorf("plugin %s has no config schema", p.Name)
+			}
+			var indented bytes.Buffer
+			if err := json.Indent(&indented, p.ConfigSchema, "", "  "); err != nil {
+				return fmt.Errorf("plugin %s has an invalid config schema: %w", p.Name, err)
+			}
+			fmt.Println(indented.String())
+			return nil
+		}
+		return fmt.Errorf("plugin %s not found", *schema)
+	}
+
+	if format.structured() {
+		infos := make([]pluginInfo, len(plugins))
+		for i, p := range plugins {
+			infos[i] = pluginInfo{Name: p.Name, Path: p.Path, Version: p.Version, Description: p.Description, Kinds: p.Kinds, Error: p.Error}
+			if len(p.ConfigSchema) > 0 {
+				var schema interface{}
+				if json.Unmarshal(p.ConfigSchema, &schema) == nil {
+					infos[i].ConfigSchema = schema
+				}
+			}
+		}
+		return format.write(os.Stdout, map[string][]pluginInfo{"plugins": infos})
+	}
+
+	if len(plugins) == 0 {
+		inform("No plugins found\n")
+		return nil
+	}
+	for _, p := range plugins {
+		if p.Error != "" {
+			fmt.Printf("%-20s  %-24s  error: %s\n", p.Name, "", p.Error)
+			continue
+		}
+		fmt.Printf("%-20s  %-24s  %s\n", p.Name, strings.Join(p.Kinds, ","), describePlugin(p, format == outputWide))
+	}
+	return nil
+}
+
+// describePlugin returns the version and description of a plugin, with
+// its path when wide
+func describePlugin(p *plugin.Plugin, wide bool) string {
+	parts := []string{}
+	if p.Version != "" {
+		parts = append(parts, p.Version)
+	}
+	if p.Description != "" {
+		parts = append(parts, p.Description)
+	}
+	if wide {
+		parts = append(parts, "("+p.Path+")")
+	}
+	return strings.Join(parts, "  ")
+}
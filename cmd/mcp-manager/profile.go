@@ -0,0 +1,155 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/grpc"
+)
+
+// profileInfo is the schema of a browser profile in json and yaml output
+type profileInfo struct {
+	Server    string         `json:"server"`
+	Name      string         `json:"name"`
+	Dir       string         `json:"dir"`
+	Size      int64          `json:"size"`
+	Modified  time.Time      `json:"modified,omitempty"`
+	Attached  bool           `json:"attached"`
+	Snapshots []snapshotInfo `json:"snapshots"`
+}
+
+// snapshotInfo is the schema of a profile snapshot in json and yaml output
+type snapshotInfo struct {
+	Name    string    `json:"name"`
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	Created time.Time `json:"created"`
+}
+
+// newProfileInfo converts a browser profile for json and yaml output
+func newProfileInfo(profile *grpc.BrowserProfile) profileInfo {
+	info := profileInfo{
+		Server:    profile.Server,
+		Name:      profile.Name,
+		Dir:       profile.Dir,
+		Size:      profile.Size,
+		Attached:  profile.Attached,
+		Snapshots: []snapshotInfo{},
+	}
+	if !profile.Modified.IsZero() {
+		info.Modified = profile.Modified.UTC()
+	}
+	for _, snapshot := range profile.Snapshots {
+		info.Snapshots = append(info.Snapshots, snapshotInfo{Name: snapshot.Name, Path: snapshot.Path, Size: snapshot.Size, Created: snapshot.Created.UTC()})
+	}
+	return info
+}
+
+// runProfile lists, resets, snapshots and restores the browser profiles of
+// servers
+func runProfile(args []string) error {
+	usage := fmt.Errorf("usage: %s profile list|reset|snapshot|restore <server> [profile] [snapshot]", os.Args[0])
+	if len(args) == 0 {
+		return usage
+	}
+
+	fs := flag.NewFlagSet("profile "+args[0], flag.ExitOnError)
+	daemon := fs.String("daemon", defaultDaemonAddress(), "Daemon address")
+	output := outputFlag(fs)
+	fs.Parse(args[1:])
+
+	format, err := parseOutput(*output)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return usage
+	}
+	server, profile := fs.Arg(0), fs.Arg(1)
+
+	client, err := connectDaemon(*daemon)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	switch args[0] {
+	case "list":
+		profiles, err := client.ListProfiles(server)
+		if err != nil {
+			return err
+		}
+		if format.structured() {
+			infos := make([]profileInfo, len(profiles))
+			for i := range profiles {
+				infos[i] = newProfileInfo(&profiles[i])
+			}
+			return format.write(os.Stdout, map[string][]profileInfo{"profiles": infos})
+		}
+		if len(profiles) == 0 {
+			inform("Server %s has no browser profiles\n", server)
+			return nil
+		}
+		for _, p := range profiles {
+			printProfile(&p, format == outputWide)
+		}
+		return nil
+	case "reset":
+		if err := client.ResetProfile(server, profile); err != nil {
+			return err
+		}
+		inform("Reset browser profile of %s\n", server)
+		return nil
+	case "snapshot":
+		snapshot, err := client.SnapshotProfile(server, profile)
+		if err != nil {
+			return err
+		}
+		inform("Saved snapshot %s to %s\n", snapshot.Name, snapshot.Path)
+		return nil
+	case "restore":
+		if fs.NArg() < 3 {
+			return fmt.Errorf("usage: %s profile restore <server> <profile> <snapshot>", os.Args[0])
+		}
+		if err := client.RestoreProfile(server, profile, fs.Arg(2)); err != nil {
+			return err
+		}
+		inform("Restored browser profile %s of %s from %s\n", profile, server, fs.Arg(2))
+		return nil
+	default:
+		return usage
+	}
+}
+
+// printProfile prints a browser profile and its snapshots, with their
+// paths when wide
+func printProfile(profile *grpc.BrowserProfile, wide bool) {
+	attached := ""
+	if profile.Attached {
+		attached = "attached"
+	}
+	fmt.Printf("%-20s  %10s  %-8s  %d snapshots\n", profile.Name, formatSize(profile.Size), attached, len(profile.Snapshots))
+	if !wide {
+		return
+	}
+	fmt.Printf("  %s\n", profile.Dir)
+	for _, snapshot := range profile.Snapshots {
+		fmt.Printf("  %-20s  %10s  %s\n", snapshot.Name, formatSize(snapshot.Size), snapshot.Path)
+	}
+}
+
+// formatSize returns a size in bytes in human-readable units
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/catalog"
+	"github.com/tartavull/mcp-manager/internal/config"
+)
+
+// runImport adds the servers of another mcp.json, e.g. shared by a team,
+// after checking its provenance
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	var (
+		checksum = fs.String("sha256", "", "Expected SHA-256 checksum of the file")
+		replace  = fs.Bool("replace", false, "Replace servers that already exist")
+	)
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: %s import [-sha256 sum] [-replace] <file or URL>", os.Args[0])
+	}
+	source := fs.Arg(0)
+
+	cfg, err := config.New()
+	if err != nil {
+		return err
+	}
+	mcpConfig, err := cfg.LoadMCPConfig()
+	if err != nil {
+		return err
+	}
+	policy, err := catalog.NewPolicy(mcpConfig.Provenance)
+	if err != nil {
+		return err
+	}
+
+	data, signature, err := readSource(source, *checksum)
+	if err != nil {
+		return err
+	}
+	key, verifyErr := policy.Verify(data, signature)
+	if err := policy.Admit(source, verifyErr, printWarning); err != nil {
+		return err
+	}
+	if verifyErr == nil {
+		inform("Signed by %s\n", key)
+	}
+
+	imported, err := config.ParseMCPConfig(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", source, err)
+	}
+
+	nextPort := cfg.DefaultBasePort()
+	for _, srv := range mcpConfig.Servers {
+		nextPort = max(nextPort, srv.Port+1)
+	}
+	var added []string
+	for _, name := range imported.ServerOrder {
+		srv := imported.Servers[name]
+		existing, exists := mcpConfig.Servers[name]
+		if exists && !*replace {
+			fmt.Fprintf(os.Stderr, "Skipping %s: already exists (use -replace to replace it)\n", name)
+			continue
+		}
+		warn := func(warning string) { printWarning(name + ": " + warning) }
+		if err := policy.CheckCommand(srv.Command, warn); err != nil {
+			fmt.Fprintf(os.Stderr, "Skipping %s: %v\n", name, err)
+			continue
+		}
+
+		// Ports are local to this machine
+		if exists {
+			srv.Port = existing.Port
+		} else {
+			srv.Port = nextPort
+			nextPort++
+			mcpConfig.ServerOrder = append(mcpConfig.ServerOrder, name)
+		}
+		mcpConfig.Servers[name] = srv
+		added = append(added, name)
+	}
+
+	if len(added) == 0 {
+		inform("No servers imported\n")
+		return nil
+	}
+	if err := cfg.SaveMCPConfig(mcpConfig); err != nil {
+		return err
+	}
+	inform("Imported %s into %s\n", strings.Join(added, ", "), cfg.GetMCPConfigPath())
+	return nil
+}
+
+// runCatalog signs and verifies catalogs and configs
+func runCatalog(args []string) error {
+	usage := fmt.Errorf("usage: %s catalog keygen|sign|verify|check", os.Args[0])
+	if len(args) == 0 {
+		return usage
+	}
+
+	fs := flag.NewFlagSet("catalog "+args[0], flag.ExitOnError)
+	var (
+		keyFile  = fs.String("key", "", "Private key file (sign)")
+		checksum = fs.String("sha256", "", "Expected SHA-256 checksum of the file (verify)")
+	)
+	fs.Parse(args[1:])
+
+	switch args[0] {
+	case "keygen":
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: %s catalog keygen <private key file>", os.Args[0])
+		}
+		return generateKey(fs.Arg(0))
+	case "sign":
+		if fs.NArg() != 1 || *keyFile == "" {
+			return fmt.Errorf("usage: %s catalog sign -key <private key file> <file>", os.Args[0])
+		}
+		return signFile(*keyFile, fs.Arg(0))
+	case "verify":
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: %s catalog verify [-sha256 sum] <file or URL>", os.Args[0])
+		}
+		return verifySource(fs.Arg(0), *checksum)
+	case "check":
+		if fs.NArg() == 0 {
+			return fmt.Errorf("usage: %s catalog check <command>", os.Args[0])
+		}
+		unpinned := catalog.Unpinned(strings.Join(fs.Args(), " "))
+		if len(unpinned) == 0 {
+			inform("All packages are pinned\n")
+			return nil
+		}
+		return fmt.Errorf("unpinned packages: %s", strings.Join(unpinned, ", "))
+	default:
+		return usage
+	}
+}
+
+// generateKey writes a new private key and prints its public key
+func generateKey(path string) error {
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s already exists", path)
+	}
+	publicKey, privateKey, err := catalog.GenerateKey()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(privateKey+"\n"), 0600); err != nil {
+		return fmt.Errorf("failed to write private key: %w", err)
+	}
+	inform("Wrote the private key to %s\n", path)
+	fmt.Printf("Trust its signatures with \"provenance\": {\"trustedKeys\": [\"%s\"]}\n", publicKey)
+	return nil
+}
+
+// signFile writes the signature of a file next to it
+func signFile(keyFile, path string) error {
+	privateKey, err := os.ReadFile(keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to read private key: %w", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	signature, err := catalog.Sign(data, string(privateKey))
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path+catalog.SignatureSuffix, signature, 0644); err != nil {
+		return fmt.Errorf("failed to write signature: %w", err)
+	}
+	inform("Wrote %s\n", path+catalog.SignatureSuffix)
+	return nil
+}
+
+// verifySource checks the signature of a catalog or config against the
+// trusted keys
+func verifySource(source, checksum string) error {
+	policy, err := loadPolicy()
+	if err != nil {
+		return err
+	}
+	data, signature, err := readSource(source, checksum)
+	if err != nil {
+		return err
+	}
+	key, err := policy.Verify(data, signature)
+	if err != nil {
+		return fmt.Errorf("%s: %w", source, err)
+	}
+	inform("%s is signed by %s\n", source, key)
+	return nil
+}
+
+// loadPolicy returns the provenance policy of the instance
+func loadPolicy() (*catalog.Policy, error) {
+	cfg, err := config.New()
+	if err != nil {
+		return nil, err
+	}
+	if !cfg.MCPConfigExists() {
+		return catalog.NewPolicy(nil)
+	}
+	mcpConfig, err := cfg.LoadMCPConfig()
+	if err != nil {
+		return nil, err
+	}
+	return catalog.NewPolicy(mcpConfig.Provenance)
+}
+
+// loadCatalog returns the built-in catalog with the entries of a catalog
+// file or URL, checked against policy
+func loadCatalog(source string, policy *catalog.Policy) ([]catalog.Entry, error) {
+	data, signature, err := readSource(source, "")
+	if err != nil {
+		return nil, err
+	}
+	entries, err := catalog.Load(source, data, signature, policy, printWarning)
+	if err != nil {
+		return nil, err
+	}
+	return catalog.Merge(catalog.Entries, entries), nil
+}
+
+// readSource reads a file or URL and its signature, nil if it has none,
+// checking the file against a SHA-256 checksum if one is given
+func readSource(source, checksum string) ([]byte, []byte, error) {
+	read := os.ReadFile
+	if strings.HasPrefix(source, "https://") || strings.HasPrefix(source, "http://") {
+		read = fetch
+	}
+
+	data, err := read(source)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", source, err)
+	}
+	if checksum != "" {
+		if err := catalog.VerifyChecksum(data, checksum); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", source, err)
+		}
+	}
+
+	signature, err := read(source + catalog.SignatureSuffix)
+	if errors.Is(err, os.ErrNotExist) {
+		return data, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read the signature of %s: %w", source, err)
+	}
+	return data, signature, nil
+}
+
+// fetch downloads a URL, returning os.ErrNotExist on 404
+func fetch(url string) ([]byte, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	case http.StatusNotFound:
+		return nil, os.ErrNotExist
+	default:
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+}
+
+// printWarning reports something accepted without being verified
+func printWarning(warning string) {
+	fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+}
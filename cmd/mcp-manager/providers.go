@@ -0,0 +1,130 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/grpc"
+)
+
+// providerGroupInfo is the schema of a generic gateway tool in json and
+// yaml output
+type providerGroupInfo struct {
+	Tool      string         `json:"tool"`
+	Selected  string         `json:"selected"`
+	Pinned    string         `json:"pinned,omitempty"`
+	Providers []providerInfo `json:"providers"`
+}
+
+// providerInfo is the schema of a provider of a generic tool in json and
+// yaml output
+type providerInfo struct {
+	Server    string  `json:"server"`
+	Tool      string  `json:"tool"`
+	Running   bool    `json:"running"`
+	Calls     int     `json:"calls"`
+	Errors    int     `json:"errors"`
+	ErrorRate float64 `json:"errorRate"`
+	Latency   float64 `json:"latencyMs"`
+}
+
+// newProviderGroupInfo converts a generic gateway tool for json and yaml
+// output
+func newProviderGroupInfo(group *grpc.ProviderGroup) providerGroupInfo {
+	info := providerGroupInfo{Tool: group.Tool, Selected: group.Selected, Pinned: group.Pinned, Providers: []providerInfo{}}
+	for _, provider := range group.Providers {
+		info.Providers = append(info.Providers, providerInfo{
+			Server:    provider.Server,
+			Tool:      provider.Tool,
+			Running:   provider.Running,
+			Calls:     provider.Calls,
+			Errors:    provider.Errors,
+			ErrorRate: provider.ErrorRate,
+			Latency:   float64(provider.Latency.Microseconds()) / 1000,
+		})
+	}
+	return info
+}
+
+// runProviders lists the generic tools of the daemon's gateway with their
+// providers, or pins and unpins their providers
+func runProviders(args []string) error {
+	usage := fmt.Errorf("usage: %s providers [list | pin <tool> <server> | unpin <tool>]", os.Args[0])
+	action := "list"
+	if len(args) > 0 && (args[0] == "list" || args[0] == "pin" || args[0] == "unpin") {
+		action, args = args[0], args[1:]
+	}
+
+	fs := flag.NewFlagSet("providers "+action, flag.ExitOnError)
+	daemon := fs.String("daemon", defaultDaemonAddress(), "Daemon address")
+	output := outputFlag(fs)
+	fs.Parse(args)
+
+	format, err := parseOutput(*output)
+	if err != nil {
+		return err
+	}
+	if (action == "pin" && fs.NArg() != 2) || (action == "unpin" && fs.NArg() != 1) {
+		return usage
+	}
+
+	client, err := connectDaemon(*daemon)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	switch action {
+	case "pin":
+		if err := client.PinProvider(fs.Arg(0), fs.Arg(1)); err != nil {
+			return err
+		}
+		inform("Pinned %s to %s\n", fs.Arg(0), fs.Arg(1))
+		return nil
+	case "unpin":
+		if err := client.PinProvider(fs.Arg(0), ""); err != nil {
+			return err
+		}
+		inform("Selecting the provider of %s automatically\n", fs.Arg(0))
+		return nil
+	}
+
+	groups, err := client.Providers()
+	if err != nil {
+		return err
+	}
+	if format.structured() {
+		infos := make([]providerGroupInfo, len(groups))
+		for i := range groups {
+			infos[i] = newProviderGroupInfo(&groups[i])
+		}
+		return format.write(os.Stdout, map[string][]providerGroupInfo{"providers": infos})
+	}
+	if len(groups) == 0 {
+		inform("No generic tools\n")
+		return nil
+	}
+
+	for _, group := range groups {
+		selection := group.Selected
+		switch {
+		case selection == "":
+			selection = "no provider running"
+		case selection == group.Pinned:
+			selection += " (pinned)"
+		default:
+			selection += " (auto)"
+		}
+		fmt.Printf("%s → %s\n", group.Tool, selection)
+		for _, provider := range group.Providers {
+			state := "stopped"
+			if provider.Running {
+				state = fmt.Sprintf("%d calls, %.0f%% errors, %s", provider.Calls, provider.ErrorRate*100, provider.Latency.Round(time.Millisecond))
+			}
+			fmt.Printf("  %-20s  %-24s  %s\n", provider.Server, provider.Tool, state)
+		}
+	}
+	return nil
+}
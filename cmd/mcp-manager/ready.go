@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// readyPollInterval is how often ready checks the servers when no events
+// arrive
+const readyPollInterval = time.Second
+
+// runReady waits until the named servers, or all autostart servers, are
+// running and healthy
+func runReady(args []string) error {
+	fs := flag.NewFlagSet("ready", flag.ExitOnError)
+	var (
+		daemon  = fs.String("daemon", defaultDaemonAddress(), "Daemon address")
+		timeout = fs.Duration("timeout", 2*time.Minute, "How long to wait")
+		quiet   = fs.Bool("q", false, "Don't print progress")
+	)
+	fs.Parse(args)
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	// Bootstrap scripts may run before the daemon is listening
+	client, err := waitForDaemon(ctx, *daemon)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	waiting := ""
+	for {
+		servers, _, err := client.GetServers()
+		if err != nil {
+			return fmt.Errorf("failed to list servers: %w", err)
+		}
+		names, err := readyTargets(servers, fs.Args())
+		if err != nil {
+			return err
+		}
+
+		pending := pendingServers(servers, names)
+		if len(pending) == 0 {
+			if !*quiet && len(names) == 0 {
+				inform("No autostart servers to wait for\n")
+			} else if !*quiet {
+				inform("Ready: %s\n", strings.Join(names, ", "))
+			}
+			return nil
+		}
+		if summary := strings.Join(pending, ", "); summary != waiting && !*quiet {
+			fmt.Fprintf(os.Stderr, "Waiting for %s\n", summary)
+			waiting = summary
+		}
+
+		select {
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				return withExitCode(exitTimeout, fmt.Errorf("timed out waiting for %s", strings.Join(pending, ", ")))
+			}
+			return ctx.Err()
+		case <-client.Events():
+		case <-time.After(readyPollInterval):
+		}
+	}
+}
+
+// waitForDaemon connects to the daemon, retrying until ctx is done
+func waitForDaemon(ctx context.Context, address string) (*grpc.Client, error) {
+	for {
+		client, err := connectDaemon(address)
+		if err == nil {
+			return client, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(readyPollInterval):
+		}
+	}
+}
+
+// readyTargets returns the servers to wait for: names, or the autostart
+// servers if no names are given
+func readyTargets(servers map[string]*server.Server, names []string) ([]string, error) {
+	if len(names) > 0 {
+		for _, name := range names {
+			if _, exists := servers[name]; !exists {
+				return nil, withExitCode(exitNotFound, fmt.Errorf("server '%s' %w", name, server.ErrNotFound))
+			}
+		}
+		return names, nil
+	}
+
+	var autostart []string
+	for name, srv := range servers {
+		if srv.Autostart {
+			autostart = append(autostart, name)
+		}
+	}
+	sort.Strings(autostart)
+	return autostart, nil
+}
+
+// pendingServers describes the servers in names that aren't ready yet
+func pendingServers(servers map[string]*server.Server, names []string) []string {
+	var pending []string
+	for _, name := range names {
+		srv := servers[name]
+		if srv.Ready() {
+			continue
+		}
+		state := string(srv.Status)
+		if srv.IsRunning() && srv.Health != server.HealthUnknown {
+			state = string(srv.Health)
+		}
+		pending = append(pending, fmt.Sprintf("%s (%s)", name, state))
+	}
+	return pending
+}
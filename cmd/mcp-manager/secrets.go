@@ -0,0 +1,179 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/secrets"
+)
+
+// runSecrets manages the secrets server env references as secret:<name>
+func runSecrets(args []string) error {
+	usage := fmt.Errorf("usage: %s secrets set|get|list|delete [name]", os.Args[0])
+	if len(args) == 0 {
+		return usage
+	}
+
+	fs := flag.NewFlagSet("secrets "+args[0], flag.ExitOnError)
+	output := outputFlag(fs)
+	fs.Parse(args[1:])
+
+	store, err := openSecrets()
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "set":
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: %s secrets set <name> (the value is read from stdin)", os.Args[0])
+		}
+		return setSecret(store, fs.Arg(0))
+	case "get":
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: %s secrets get <name>", os.Args[0])
+		}
+		value, err := store.Get(fs.Arg(0))
+		if errors.Is(err, secrets.ErrNotFound) {
+			return fmt.Errorf("secret '%s' not found", fs.Arg(0))
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Println(value)
+		return nil
+	case "list":
+		return listSecrets(store, *output)
+	case "delete":
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: %s secrets delete <name>", os.Args[0])
+		}
+		err := store.Delete(fs.Arg(0))
+		if errors.Is(err, secrets.ErrNotFound) {
+			return fmt.Errorf("secret '%s' not found", fs.Arg(0))
+		}
+		return err
+	default:
+		return usage
+	}
+}
+
+// setSecret stores a secret read from the terminal without echo, or from
+// stdin when it's piped
+func setSecret(store secrets.Store, name string) error {
+	if err := secrets.ValidateName(name); err != nil {
+		return err
+	}
+
+	var value string
+	if isTerminal(os.Stdin) {
+		fmt.Fprintf(os.Stderr, "Value of %s: ", name)
+		data, err := term.ReadPassword(os.Stdin.Fd())
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return fmt.Errorf("failed to read value: %w", err)
+		}
+		value = strings.TrimSpace(string(data))
+	} else {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read value: %w", err)
+		}
+		value = strings.TrimRight(string(data), "\r\n")
+	}
+	if value == "" {
+		return fmt.Errorf("empty value")
+	}
+
+	if err := store.Set(name, value); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Stored %s in the %s; reference it in mcp.json as \"%s\"\n", name, store.Backend(), secrets.Ref(name))
+	return nil
+}
+
+// listSecrets prints the names of the secrets, never their values
+func listSecrets(store secrets.Store, output string) error {
+	format, err := parseOutput(output)
+	if err != nil {
+		return err
+	}
+	names, err := store.List()
+	if err != nil {
+		return err
+	}
+	if format.structured() {
+		if names == nil {
+			names = []string{}
+		}
+		return format.write(os.Stdout, map[string]interface{}{"backend": store.Backend(), "secrets": names})
+	}
+
+	if len(names) == 0 {
+		inform("No secrets in the %s\n", store.Backend())
+		return nil
+	}
+	for _, name := range names {
+		fmt.Println(name)
+	}
+	return nil
+}
+
+// openSecrets opens the secrets store of the instance
+func openSecrets() (secrets.Store, error) {
+	cfg, err := config.New()
+	if err != nil {
+		return nil, err
+	}
+	mcpConfig, err := cfg.LoadMCPConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := secrets.ConfigOptions(cfg, mcpConfig.Secrets)
+	opts.Passphrase = readPassphrase
+	return secrets.Open(opts)
+}
+
+// readPassphrase returns the passphrase of the secrets file from the
+// environment, or asks for it on the terminal, twice for a new file
+func readPassphrase(create bool) (string, error) {
+	if passphrase := os.Getenv(secrets.PassphraseEnv); passphrase != "" {
+		return passphrase, nil
+	}
+	if !isTerminal(os.Stdin) {
+		return "", fmt.Errorf("set %s to unlock the secrets file", secrets.PassphraseEnv)
+	}
+
+	ask := func(prompt string) (string, error) {
+		fmt.Fprint(os.Stderr, prompt)
+		data, err := term.ReadPassword(os.Stdin.Fd())
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("failed to read passphrase: %w", err)
+		}
+		return string(data), nil
+	}
+
+	if !create {
+		return ask("Passphrase of the secrets file: ")
+	}
+	passphrase, err := ask("New passphrase for the secrets file: ")
+	if err != nil {
+		return "", err
+	}
+	confirmed, err := ask("Repeat the passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if passphrase != confirmed {
+		return "", fmt.Errorf("passphrases don't match")
+	}
+	return passphrase, nil
+}
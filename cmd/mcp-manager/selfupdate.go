@@ -0,0 +1,150 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/update"
+	"github.com/tartavull/mcp-manager/internal/version"
+)
+
+// runSelfUpdate replaces the installed binaries with the latest release
+func runSelfUpdate(args []string) error {
+	fs := flag.NewFlagSet("self-update", flag.ExitOnError)
+	var (
+		check   = fs.Bool("check", false, "Only check whether an update is available")
+		force   = fs.Bool("force", false, "Reinstall even if already up to date")
+		restart = fs.Bool("restart", true, "Restart the daemon after updating, handing its running servers to the new binary")
+		daemon  = fs.String("daemon", defaultDaemonAddress(), "Daemon address to restart")
+		repo    = fs.String("repo", update.DefaultRepository, "GitHub repository to update from")
+	)
+	fs.Parse(args)
+
+	updater := update.New()
+	updater.Repository = *repo
+
+	release, err := updater.LatestRelease()
+	if err != nil {
+		return err
+	}
+
+	if release.TagName == version.Version && !*force {
+		inform("Already up to date (%s)\n", version.Version)
+		return nil
+	}
+
+	if *check {
+		fmt.Printf("Update available: %s -> %s\n", version.Version, release.TagName)
+		return nil
+	}
+
+	inform("Downloading %s for %s...\n", release.TagName, updater.Suffix())
+	binaries, err := updater.Download(release)
+	if err != nil {
+		return err
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate current executable: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+
+	managerBinary, ok := binaries["mcp-manager"]
+	if !ok {
+		return fmt.Errorf("release %s does not contain mcp-manager", release.TagName)
+	}
+	if err := update.ReplaceExecutable(exe, managerBinary); err != nil {
+		return err
+	}
+	inform("Updated %s\n", exe)
+
+	// The daemon is installed next to the manager
+	daemonPath := filepath.Join(filepath.Dir(exe), "mcp-daemon")
+	daemonBinary, ok := binaries["mcp-daemon"]
+	if _, err := os.Stat(daemonPath); err != nil || !ok {
+		inform("Daemon binary not found at %s, skipping\n", daemonPath)
+		return nil
+	}
+	if err := update.ReplaceExecutable(daemonPath, daemonBinary); err != nil {
+		return err
+	}
+	inform("Updated %s\n", daemonPath)
+
+	if *restart {
+		if err := restartLocalDaemon(daemonPath, *daemon); err != nil {
+			return fmt.Errorf("binaries updated but daemon restart failed: %w", err)
+		}
+	}
+
+	inform("Successfully updated to %s\n", release.TagName)
+	return nil
+}
+
+// restartLocalDaemon replaces the daemon at address with the newly
+// installed binary, if it runs on this machine and is up. Its running
+// servers are handed to the new binary instead of being restarted.
+func restartLocalDaemon(daemonPath, address string) error {
+	args, local, err := localDaemonArgs(address)
+	if err != nil {
+		return err
+	}
+	if !local {
+		inform("The daemon at %s runs on another host, run self-update there to update it\n", address)
+		return nil
+	}
+	if !daemonReachable(address) {
+		return nil
+	}
+
+	inform("Upgrading the daemon...\n")
+	args = append([]string{"upgrade"}, args...)
+	if quiet {
+		args = append(args, "-quiet")
+	}
+	cmd := exec.Command(daemonPath, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// localDaemonArgs returns the flags of mcp-daemon selecting the daemon at
+// address, resolved as clients resolve it, or false if the daemon runs on
+// another host
+func localDaemonArgs(address string) ([]string, bool, error) {
+	if strings.HasPrefix(address, grpc.SSHScheme) {
+		return nil, false, nil
+	}
+	if path, local, err := grpc.UnixSocketPath(address); err != nil {
+		return nil, false, err
+	} else if local {
+		return []string{"-socket", path}, true, nil
+	}
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid daemon address %q: %w", address, err)
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return nil, false, nil
+	}
+	return []string{"-port", port}, true, nil
+}
+
+// daemonReachable checks if a daemon answers at address, connecting as
+// clients do
+func daemonReachable(address string) bool {
+	client, err := grpc.NewClient(address)
+	if err != nil {
+		return false
+	}
+	client.Close()
+	return true
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/transcript"
+)
+
+// runSessions prints the session transcripts recorded by the daemon
+func runSessions(args []string) error {
+	fs := flag.NewFlagSet("sessions", flag.ExitOnError)
+	daemon := fs.String("daemon", defaultDaemonAddress(), "Daemon address")
+	id := fs.String("id", "", "Only print the calls given this request or agent run ID")
+	output := outputFlag(fs)
+	fs.Parse(args)
+
+	format, err := parseOutput(*output)
+	if err != nil {
+		return err
+	}
+
+	client, err := connectDaemon(*daemon)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	sessions, err := client.ListSessions()
+	if *id != "" {
+		sessions, err = client.FindSessions(*id)
+	}
+	if err != nil {
+		return err
+	}
+	if format.structured() {
+		for i := range sessions {
+			sessions[i].Started, sessions[i].Updated = sessions[i].Started.UTC(), sessions[i].Updated.UTC()
+		}
+		if sessions == nil {
+			sessions = []transcript.Session{}
+		}
+		return format.write(os.Stdout, map[string][]transcript.Session{"sessions": sessions})
+	}
+
+	if len(sessions) == 0 {
+		inform("No sessions recorded\n")
+		return nil
+	}
+	for _, s := range sessions {
+		fmt.Printf("%s  %-28s  %4d calls  %3d errors  %s ago\n", s.ID, s.Client, s.Calls, s.Errors,
+			time.Since(s.Updated).Round(time.Second))
+
+		// Filtered sessions hold the calls given the ID
+		for _, entry := range s.Entries {
+			outcome := "ok"
+			if entry.Error != "" {
+				outcome = "error: " + entry.Error
+			}
+			fmt.Printf("  %s  %-16s  %s %s (%dms) %s  %s\n", entry.Time.Format("15:04:05"), entry.Server,
+				entry.Method, entry.Tool, entry.DurationMs, outcome, entry.Describe())
+		}
+	}
+	return nil
+}
+
+// runTranscript prints a session transcript as markdown or JSON
+func runTranscript(args []string) error {
+	fs := flag.NewFlagSet("transcript", flag.ExitOnError)
+	daemon := fs.String("daemon", defaultDaemonAddress(), "Daemon address")
+	format := fs.String("format", "markdown", "Output format (markdown, json)")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: %s transcript [-format markdown|json] <id>", os.Args[0])
+	}
+	if *format != "markdown" && *format != "json" {
+		return fmt.Errorf("invalid format '%s' (markdown, json)", *format)
+	}
+
+	client, err := connectDaemon(*daemon)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	session, err := client.GetSession(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(session)
+	}
+	_, err = os.Stdout.Write(transcript.Markdown(session))
+	return err
+}
+
+// runFixture exports the tool calls recorded in a time window as a fixture
+// the mock server replays, so real interactions become regression tests
+func runFixture(args []string) error {
+	fs := flag.NewFlagSet("fixture", flag.ExitOnError)
+	var (
+		daemon = fs.String("daemon", defaultDaemonAddress(), "Daemon address")
+		srv    = fs.String("server", "", "Only calls to this server")
+		id     = fs.String("id", "", "Only calls given this request or agent run ID")
+		since  = fs.Duration("since", 0, "Only calls in this past duration, e.g. 30m")
+		from   = fs.String("from", "", "Start of the time range, as HH:MM today or RFC 3339")
+		to     = fs.String("to", "", "End of the time range, as HH:MM today or RFC 3339")
+	)
+	fs.Parse(args)
+
+	var start, end time.Time
+	if *since > 0 {
+		start = time.Now().Add(-*since)
+	}
+	for _, bound := range []struct {
+		value  string
+		target *time.Time
+	}{{*from, &start}, {*to, &end}} {
+		if bound.value == "" {
+			continue
+		}
+		t, err := parseEventTime(bound.value)
+		if err != nil {
+			return err
+		}
+		*bound.target = t
+	}
+
+	client, err := connectDaemon(*daemon)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	// Filtered sessions hold their calls, others are fetched if they may
+	// have calls in the window
+	var sessions []transcript.Session
+	if *id != "" {
+		sessions, err = client.FindSessions(*id)
+		if err != nil {
+			return err
+		}
+	} else if sessions, err = sessionsBetween(client, start, end); err != nil {
+		return err
+	}
+
+	fixture := transcript.NewFixture(sessions, start.UTC(), end.UTC())
+	if *srv != "" {
+		for name := range fixture.Servers {
+			if name != *srv {
+				delete(fixture.Servers, name)
+			}
+		}
+	}
+
+	calls := 0
+	for _, server := range fixture.Servers {
+		calls += len(server.Calls)
+	}
+	fmt.Fprintf(os.Stderr, "Exported %d calls to %d servers", calls, len(fixture.Servers))
+	if fixture.Skipped > 0 {
+		fmt.Fprintf(os.Stderr, ", skipped %d with shortened results", fixture.Skipped)
+	}
+	fmt.Fprintln(os.Stderr)
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(fixture)
+}
+
+// sessionsBetween returns the sessions with their calls that may have
+// calls between start and end. A zero start or end leaves the window open.
+func sessionsBetween(client *grpc.Client, start, end time.Time) ([]transcript.Session, error) {
+	summaries, err := client.ListSessions()
+	if err != nil {
+		return nil, err
+	}
+	var sessions []transcript.Session
+	for _, summary := range summaries {
+		if (!start.IsZero() && summary.Updated.Before(start)) || (!end.IsZero() && summary.Started.After(end)) {
+			continue
+		}
+		session, err := client.GetSession(summary.ID)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, *session)
+	}
+	return sessions, nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/grpc"
+)
+
+// runStart starts servers in the daemon
+func runStart(args []string) error {
+	return runServerAction("start", args, "Started")
+}
+
+// runStop stops servers in the daemon
+func runStop(args []string) error {
+	return runServerAction("stop", args, "Stopped")
+}
+
+// runServerAction starts or stops the servers named in args, stopping at
+// the first failure so its exit code is kept
+func runServerAction(action string, args []string, done string) error {
+	fs := flag.NewFlagSet(action, flag.ExitOnError)
+	daemon := fs.String("daemon", defaultDaemonAddress(), "Daemon address")
+	tag := fs.String("tag", "", "Act on all the servers labeled with this tag")
+	var drain *bool
+	var timeout *time.Duration
+	if action == "stop" {
+		drain = fs.Bool("drain", false, "Stop routing gateway calls to the servers and wait for calls in flight first")
+		timeout = fs.Duration("timeout", 0, "Longest wait for calls in flight when draining (default 30s)")
+	}
+	fs.Parse(args)
+
+	if (fs.NArg() == 0) == (*tag == "") {
+		return fmt.Errorf("usage: %s %s <server>... or %s %s -tag <tag>", os.Args[0], action, os.Args[0], action)
+	}
+	if *tag != "" && action == "stop" && *drain {
+		return fmt.Errorf("-drain takes server names, not -tag")
+	}
+
+	client, err := connectDaemon(*daemon)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if *tag != "" {
+		return runTagAction(client, action, *tag, done)
+	}
+
+	for _, name := range fs.Args() {
+		switch {
+		case action == "start":
+			err = client.StartServer(name)
+		case *drain:
+			err = client.DrainServer(name, *timeout)
+		default:
+			err = client.StopServer(name)
+		}
+		if err != nil {
+			return err
+		}
+		inform("%s %s\n", done, name)
+	}
+	return nil
+}
+
+// runTagAction starts the stopped servers or stops the running servers
+// labeled with tag
+func runTagAction(client *grpc.Client, action, tag, done string) error {
+	var names []string
+	var err error
+	if action == "start" {
+		names, err = client.StartTagged(tag)
+	} else {
+		names, err = client.StopTagged(tag)
+	}
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		inform("No server tagged %s to %s\n", tag, action)
+	}
+	for _, name := range names {
+		inform("%s %s\n", done, name)
+	}
+	return nil
+}
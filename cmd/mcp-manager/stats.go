@@ -0,0 +1,105 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/tartavull/mcp-manager/internal/uptime"
+)
+
+// uptimeInfo is the schema of the availability of a server in json and
+// yaml output
+type uptimeInfo struct {
+	Server      string   `json:"server"`
+	Today       *float64 `json:"today"`  // Percentage up today, null if neither up nor down
+	Uptime      *float64 `json:"uptime"` // Percentage up over the window
+	UpSeconds   int64    `json:"upSeconds"`
+	DownSeconds int64    `json:"downSeconds"`
+	Outages     int      `json:"outages"`
+	MTTRSeconds int64    `json:"mttrSeconds"`
+}
+
+// percentage returns the uptime of stats, nil without monitored time
+func percentage(stats uptime.Stats) *float64 {
+	if p, ok := stats.Uptime(); ok {
+		return &p
+	}
+	return nil
+}
+
+// formatPercentage formats an uptime percentage, - when unknown
+func formatPercentage(p *float64) string {
+	if p == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f%%", *p)
+}
+
+// runStats prints the uptime, outages and mean time to repair of servers
+// over the last days, from the status changes the daemon recorded
+func runStats(args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	daemon := fs.String("daemon", defaultDaemonAddress(), "Daemon address")
+	days := fs.Int("days", 7, fmt.Sprintf("Calendar days to report, today included (1-%d)", uptime.Retention))
+	output := outputFlag(fs)
+	fs.Parse(args)
+
+	format, err := parseOutput(*output)
+	if err != nil {
+		return err
+	}
+	if *days < 1 || *days > uptime.Retention {
+		return fmt.Errorf("-days must be between 1 and %d", uptime.Retention)
+	}
+
+	client, err := connectDaemon(*daemon)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	window, err := client.ServerUptime(*days)
+	if err != nil {
+		return err
+	}
+	today, err := client.ServerUptime(1)
+	if err != nil {
+		return err
+	}
+	todays := make(map[string]uptime.Stats, len(today))
+	for _, stats := range today {
+		todays[stats.Server] = stats
+	}
+
+	infos := make([]uptimeInfo, len(window))
+	for i, stats := range window {
+		infos[i] = uptimeInfo{
+			Server:      stats.Server,
+			Today:       percentage(todays[stats.Server]),
+			Uptime:      percentage(stats),
+			UpSeconds:   int64(stats.Up.Seconds()),
+			DownSeconds: int64(stats.Down.Seconds()),
+			Outages:     stats.Outages,
+			MTTRSeconds: int64(stats.MTTR.Seconds()),
+		}
+	}
+	if format.structured() {
+		return format.write(os.Stdout, map[string]interface{}{"days": *days, "servers": infos})
+	}
+
+	if len(infos) == 0 {
+		inform("No status changes recorded in the last %d days\n", *days)
+		return nil
+	}
+	fmt.Printf("%-20s  %8s  %8s  %8s  %7s  %8s\n", "SERVER", "TODAY", fmt.Sprintf("%dD", *days), "DOWN", "OUTAGES", "MTTR")
+	for i, info := range infos {
+		mttr := "-"
+		if window[i].MTTR > 0 {
+			mttr = formatUptime(window[i].MTTR)
+		}
+		fmt.Printf("%-20s  %8s  %8s  %8s  %7d  %8s\n", info.Server, formatPercentage(info.Today), formatPercentage(info.Uptime),
+			formatUptime(window[i].Down), info.Outages, mttr)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// statusInfo is the schema of the status command's json and yaml output
+type statusInfo struct {
+	Summary server.Summary `json:"summary"`
+	Servers []serverInfo   `json:"servers"`
+}
+
+// runStatus prints the status of the daemon's servers
+func runStatus(args []string) error {
+	fs := flag.NewFlagSet("status", flag.ExitOnError)
+	var (
+		daemon = fs.String("daemon", defaultDaemonAddress(), "Daemon address")
+		short  = fs.Bool("short", false, "Print one line, e.g. 3/10 or 3/10 1! when servers are failing")
+		tag    = fs.String("tag", "", "Only print the servers labeled with this tag")
+		output = outputFlag(fs)
+	)
+	fs.Parse(args)
+
+	format, err := parseOutput(*output)
+	if err != nil {
+		return err
+	}
+
+	client, err := connectDaemon(*daemon)
+	if err != nil {
+		if *short {
+			// Status bars show that the daemon is down instead of an error
+			fmt.Println("-/-")
+			os.Exit(exitUnreachable)
+		}
+		return err
+	}
+	defer client.Close()
+
+	servers, order, err := client.GetServers()
+	if err != nil {
+		return fmt.Errorf("failed to list servers: %w", err)
+	}
+	if *tag != "" {
+		for name, srv := range servers {
+			if !srv.HasTag(*tag) {
+				delete(servers, name)
+			}
+		}
+	}
+
+	summary := server.Summarize(servers)
+	if *short {
+		fmt.Println(summary)
+		return nil
+	}
+
+	if format.structured() {
+		status := statusInfo{Summary: summary, Servers: []serverInfo{}}
+		for _, name := range order {
+			if srv, exists := servers[name]; exists {
+				status.Servers = append(status.Servers, newServerInfo(srv))
+			}
+		}
+		return format.write(os.Stdout, status)
+	}
+
+	fmt.Println(serverHeader(format == outputWide))
+	for _, name := range order {
+		if srv, exists := servers[name]; exists {
+			fmt.Println(serverRow(srv, format == outputWide, colorOutput()))
+		}
+	}
+	fmt.Printf("\n%d of %d servers running", summary.Running, summary.Total)
+	if summary.Failing > 0 {
+		fmt.Printf(", %d failing", summary.Failing)
+	}
+	fmt.Println()
+	return nil
+}
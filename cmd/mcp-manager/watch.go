@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+
+	"github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// runWatch prints the server table, then a row for every change the
+// daemon reports, until interrupted
+func runWatch(args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	var (
+		daemon  = fs.String("daemon", defaultDaemonAddress(), "Daemon address")
+		noColor = fs.Bool("no-color", false, "Don't color statuses")
+		output  = outputFlag(fs)
+	)
+	fs.Parse(args)
+
+	format, err := parseOutput(*output)
+	if err != nil {
+		return err
+	}
+
+	client, err := connectDaemon(*daemon)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	// Structured output has an item per server, tables a row
+	color := !*noColor && colorOutput()
+	show := func(srv *server.Server) error {
+		if format.structured() {
+			return format.writeItem(os.Stdout, newServerInfo(srv))
+		}
+		_, err := fmt.Println(serverRow(srv, format == outputWide, color))
+		return err
+	}
+
+	servers, order, err := client.GetServers()
+	if err != nil {
+		return fmt.Errorf("failed to list servers: %w", err)
+	}
+	if !format.structured() {
+		fmt.Println(serverHeader(format == outputWide))
+	}
+	for _, name := range order {
+		if srv, exists := servers[name]; exists {
+			if err := show(srv); err != nil {
+				return err
+			}
+		}
+	}
+
+	// The stream is resubscribed by the client; watching ends when it gives up
+	disconnected := make(chan struct{})
+	var once sync.Once
+	client.SetOnConnectionState(func(state grpc.ConnectionState) {
+		fmt.Fprintf(os.Stderr, "Event stream %s\n", state)
+		if state == grpc.StateDisconnected {
+			once.Do(func() { close(disconnected) })
+		}
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-disconnected:
+			return withExitCode(exitUnreachable, fmt.Errorf("lost connection to daemon"))
+		case <-client.Events():
+			changed, err := changedServers(client, servers)
+			if err != nil {
+				return err
+			}
+			for _, srv := range changed {
+				if err := show(srv); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// changedServers returns the servers that changed since the last seen
+// state in servers, and updates it. Removed servers are returned with the
+// status "removed".
+func changedServers(client *grpc.Client, servers map[string]*server.Server) ([]*server.Server, error) {
+	latest, order, err := client.GetServers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list servers: %w", err)
+	}
+
+	var changed []*server.Server
+	for _, name := range order {
+		srv, exists := latest[name]
+		if !exists {
+			continue
+		}
+		if old, seen := servers[name]; !seen || serverChanged(old, srv) {
+			changed = append(changed, srv)
+		}
+	}
+	for name, old := range servers {
+		if _, exists := latest[name]; !exists {
+			changed = append(changed, &server.Server{Name: name, Port: old.Port, Status: "removed"})
+		}
+	}
+
+	clear(servers)
+	for name, srv := range latest {
+		servers[name] = srv
+	}
+	return changed, nil
+}
+
+// serverChanged returns true if a server changed in a way worth a new row
+func serverChanged(old, srv *server.Server) bool {
+	return old.Status != srv.Status || old.Health != srv.Health || old.PID != srv.PID ||
+		old.ToolCount != srv.ToolCount || old.Restarts != srv.Restarts || old.Maintenance != srv.Maintenance
+}
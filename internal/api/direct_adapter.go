@@ -1,13 +1,18 @@
 package api
 
 import (
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/gateway"
+	"github.com/tartavull/mcp-manager/internal/grpc"
 	"github.com/tartavull/mcp-manager/internal/manager"
 	"github.com/tartavull/mcp-manager/internal/server"
+	"github.com/tartavull/mcp-manager/internal/transcript"
 )
 
 // DirectAdapter implements ManagerInterface using direct manager access
 type DirectAdapter struct {
-	manager *manager.Manager
+	manager   *manager.Manager
+	validator *gateway.Validator
 }
 
 // NewDirectAdapter creates a new direct adapter
@@ -16,9 +21,14 @@ func NewDirectAdapter() (*DirectAdapter, error) {
 	if err != nil {
 		return nil, err
 	}
+	cfg, err := config.New()
+	if err != nil {
+		return nil, err
+	}
 
 	return &DirectAdapter{
-		manager: mgr,
+		manager:   mgr,
+		validator: &gateway.Validator{Config: cfg, Source: mgr},
 	}, nil
 }
 
@@ -62,6 +72,73 @@ func (d *DirectAdapter) UpdateToolCounts() error {
 	return nil
 }
 
+// SetMaintenance toggles maintenance mode
+func (d *DirectAdapter) SetMaintenance(name string, enabled bool) error {
+	return d.manager.SetMaintenance(name, enabled)
+}
+
+// Maintenance returns true if the manager is in maintenance mode
+func (d *DirectAdapter) Maintenance() (bool, error) {
+	return d.manager.Maintenance()
+}
+
+// ValidateConfig returns the problems of mcp.json and the tools the
+// gateway would expose under other names
+func (d *DirectAdapter) ValidateConfig() (*grpc.Validation, error) {
+	return d.validator.ValidateConfig()
+}
+
+// PendingApprovals returns the tool calls waiting for approval
+func (d *DirectAdapter) PendingApprovals() ([]grpc.Approval, error) {
+	return d.manager.PendingApprovals(), nil
+}
+
+// DecideApproval lets a parked tool call through or rejects it
+func (d *DirectAdapter) DecideApproval(id string, approved bool, reason string) error {
+	return d.manager.DecideApproval(id, approved, reason)
+}
+
+// Sessions returns the session transcripts of the proxies' clients
+func (d *DirectAdapter) Sessions() ([]transcript.Session, error) {
+	return d.manager.Sessions(), nil
+}
+
+// FindSessions returns the session transcripts with calls given a request
+// or run ID
+func (d *DirectAdapter) FindSessions(id string) ([]transcript.Session, error) {
+	return d.manager.FindSessions(id), nil
+}
+
+// Session returns a session transcript with its calls
+func (d *DirectAdapter) Session(id string) (*transcript.Session, error) {
+	return d.manager.Session(id)
+}
+
+// ResetProfile deletes the files of a server's browser profile
+func (d *DirectAdapter) ResetProfile(server, profile string) error {
+	return d.manager.ResetProfile(server, profile)
+}
+
+// SnapshotProfile archives a server's browser profile
+func (d *DirectAdapter) SnapshotProfile(server, profile string) (*grpc.ProfileSnapshot, error) {
+	return d.manager.SnapshotProfile(server, profile)
+}
+
+// DiskUsage returns the disk used by servers
+func (d *DirectAdapter) DiskUsage(names []string) ([]grpc.ServerDisk, error) {
+	return d.manager.DiskUsage(names)
+}
+
+// DescribeServer returns what a server runs as, or would if started now
+func (d *DirectAdapter) DescribeServer(name string) (*grpc.Runtime, error) {
+	return d.manager.DescribeServer(name)
+}
+
+// CloneServer copies a server in mcp.json under a new name and port
+func (d *DirectAdapter) CloneServer(source, name string, replace []string) (string, int, error) {
+	return d.manager.CloneServer(source, name, replace)
+}
+
 // Close cleans up resources
 func (d *DirectAdapter) Close() error {
 	return d.manager.Close()
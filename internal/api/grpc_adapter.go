@@ -1,8 +1,12 @@
 package api
 
 import (
+	"time"
+
 	"github.com/tartavull/mcp-manager/internal/grpc"
 	"github.com/tartavull/mcp-manager/internal/server"
+	"github.com/tartavull/mcp-manager/internal/transcript"
+	"github.com/tartavull/mcp-manager/internal/uptime"
 )
 
 // GRPCAdapter implements ManagerInterface using gRPC client
@@ -23,6 +27,19 @@ func NewGRPCAdapter(address string) (*GRPCAdapter, error) {
 	}, nil
 }
 
+// NewGRPCAdapterWithOptions creates a new gRPC adapter connecting with the
+// credentials of options
+func NewGRPCAdapterWithOptions(address string, options grpc.ClientOptions) (*GRPCAdapter, error) {
+	client, err := grpc.NewClientWithOptions(address, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GRPCAdapter{
+		Client: client,
+	}, nil
+}
+
 // SetOnServerUpdate sets the callback for server updates
 func (g *GRPCAdapter) SetOnServerUpdate(callback func()) {
 	g.onServerUpdate = callback
@@ -73,6 +90,142 @@ func (g *GRPCAdapter) UpdateToolCounts() error {
 	return nil
 }
 
+// SetMaintenance toggles maintenance mode
+func (g *GRPCAdapter) SetMaintenance(name string, enabled bool) error {
+	return g.Client.SetMaintenance(name, enabled)
+}
+
+// Maintenance returns true if the daemon is in maintenance mode
+func (g *GRPCAdapter) Maintenance() (bool, error) {
+	return g.Client.Maintenance()
+}
+
+// Connected returns true if the daemon sent a heartbeat recently
+func (g *GRPCAdapter) Connected() bool {
+	return g.Client.Connected()
+}
+
+// LastHeartbeat returns when the daemon was last heard from
+func (g *GRPCAdapter) LastHeartbeat() time.Time {
+	return g.Client.LastHeartbeat()
+}
+
+// State returns the connection state of the daemon's event stream
+func (g *GRPCAdapter) State() grpc.ConnectionState {
+	return g.Client.State()
+}
+
+// Retry returns the attempts to reconnect the daemon's event stream
+func (g *GRPCAdapter) Retry() grpc.Retry {
+	return g.Client.Retry()
+}
+
+// Reconnect attempts to reconnect the daemon's event stream now
+func (g *GRPCAdapter) Reconnect() {
+	g.Client.Reconnect()
+}
+
+// Uptime returns how long the daemon has been running
+func (g *GRPCAdapter) Uptime() (time.Duration, error) {
+	health, err := g.Client.Health()
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(health.UptimeSeconds) * time.Second, nil
+}
+
+// ValidateConfig returns the problems of the daemon's configuration and the
+// tools its gateway exposes under other names
+func (g *GRPCAdapter) ValidateConfig() (*grpc.Validation, error) {
+	return g.Client.ValidateConfig()
+}
+
+// PendingApprovals returns the tool calls waiting for approval in the daemon
+func (g *GRPCAdapter) PendingApprovals() ([]grpc.Approval, error) {
+	return g.Client.ListApprovals()
+}
+
+// DecideApproval lets a tool call parked in the daemon through or rejects it
+func (g *GRPCAdapter) DecideApproval(id string, approved bool, reason string) error {
+	return g.Client.DecideApproval(id, approved, reason)
+}
+
+// Sessions returns the session transcripts recorded by the daemon
+func (g *GRPCAdapter) Sessions() ([]transcript.Session, error) {
+	return g.Client.ListSessions()
+}
+
+// FindSessions returns the session transcripts recorded by the daemon with
+// calls given a request or run ID
+func (g *GRPCAdapter) FindSessions(id string) ([]transcript.Session, error) {
+	return g.Client.FindSessions(id)
+}
+
+// Session returns a session transcript recorded by the daemon
+func (g *GRPCAdapter) Session(id string) (*transcript.Session, error) {
+	return g.Client.GetSession(id)
+}
+
+// ResetProfile deletes the files of a server's browser profile in the
+// daemon
+func (g *GRPCAdapter) ResetProfile(server, profile string) error {
+	return g.Client.ResetProfile(server, profile)
+}
+
+// SnapshotProfile archives a server's browser profile in the daemon
+func (g *GRPCAdapter) SnapshotProfile(server, profile string) (*grpc.ProfileSnapshot, error) {
+	return g.Client.SnapshotProfile(server, profile)
+}
+
+// ServerUptime returns the availability of the daemon's servers
+func (g *GRPCAdapter) ServerUptime(days int) ([]uptime.Stats, error) {
+	return g.Client.ServerUptime(days)
+}
+
+// DiskUsage returns the disk used by the daemon's servers
+func (g *GRPCAdapter) DiskUsage(names []string) ([]grpc.ServerDisk, error) {
+	return g.Client.DiskUsage(names)
+}
+
+// DescribeServer returns what a server of the daemon runs as, or would if
+// started now
+func (g *GRPCAdapter) DescribeServer(name string) (*grpc.Runtime, error) {
+	return g.Client.DescribeServer(name)
+}
+
+// CloneServer copies a server in the daemon's mcp.json under a new name
+// and port
+func (g *GRPCAdapter) CloneServer(source, name string, replace []string) (string, int, error) {
+	return g.Client.CloneServer(source, name, replace)
+}
+
+// Providers returns the generic tools of the daemon's gateway with the
+// provider each selects
+func (g *GRPCAdapter) Providers() ([]grpc.ProviderGroup, error) {
+	return g.Client.Providers()
+}
+
+// PinProvider makes a server serve a generic tool of the daemon's gateway
+func (g *GRPCAdapter) PinProvider(tool, server string) error {
+	return g.Client.PinProvider(tool, server)
+}
+
+// Notifications returns the levels of the daemon's desktop notifications
+func (g *GRPCAdapter) Notifications() (string, []grpc.NotificationPreference, error) {
+	return g.Client.Notifications()
+}
+
+// SetNotification sets the level of desktop notifications of a server
+func (g *GRPCAdapter) SetNotification(server, level string) error {
+	return g.Client.SetNotification(server, level)
+}
+
+// SetOnConnectionState sets the callback for changes of the connection to
+// the daemon's event stream
+func (g *GRPCAdapter) SetOnConnectionState(callback func(grpc.ConnectionState)) {
+	g.Client.SetOnConnectionState(callback)
+}
+
 // Close cleans up resources
 func (g *GRPCAdapter) Close() error {
 	return g.Client.Close()
@@ -1,7 +1,12 @@
 package api
 
 import (
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/grpc"
 	"github.com/tartavull/mcp-manager/internal/server"
+	"github.com/tartavull/mcp-manager/internal/transcript"
+	"github.com/tartavull/mcp-manager/internal/uptime"
 )
 
 // ManagerInterface defines the common interface for managing MCP servers
@@ -28,6 +33,145 @@ type ManagerInterface interface {
 	// UpdateToolCounts triggers tool count updates
 	UpdateToolCounts() error
 
+	// SetMaintenance toggles maintenance mode for a server, or for the
+	// whole daemon when name is empty
+	SetMaintenance(name string, enabled bool) error
+
+	// Maintenance returns true if the whole daemon is in maintenance mode
+	Maintenance() (bool, error)
+
 	// Close cleans up resources
 	Close() error
 }
+
+// Liveness is implemented by managers reached over a connection to the
+// daemon, for the TUI's connection indicator
+type Liveness interface {
+	// Connected returns true if the daemon sent a heartbeat recently
+	Connected() bool
+
+	// LastHeartbeat returns when the daemon was last heard from
+	LastHeartbeat() time.Time
+}
+
+// Reconnection is implemented by managers resubscribing to the daemon's
+// events when the stream is lost, for the TUI's reconnect overlay
+type Reconnection interface {
+	// State returns whether the event stream is connected, being
+	// reconnected or given up on
+	State() grpc.ConnectionState
+
+	// Retry returns the attempts to reconnect the event stream
+	Retry() grpc.Retry
+
+	// Reconnect attempts to reconnect now, also after giving up
+	Reconnect()
+}
+
+// Uptime is implemented by managers reached over a connection to the
+// daemon, for the TUI's overview
+type Uptime interface {
+	// Uptime returns how long the daemon has been running
+	Uptime() (time.Duration, error)
+}
+
+// Availability is implemented by managers reached over a connection to a
+// daemon tracking the status changes of servers, for the TUI's overview
+type Availability interface {
+	// ServerUptime returns the availability of the servers over the last
+	// days calendar days
+	ServerUptime(days int) ([]uptime.Stats, error)
+}
+
+// Validator is implemented by managers that can check the configuration,
+// for the TUI's overview
+type Validator interface {
+	// ValidateConfig returns the problems of mcp.json and the tools the
+	// gateway exposes under other names
+	ValidateConfig() (*grpc.Validation, error)
+}
+
+// Approvals is implemented by managers whose proxies park tool calls for an
+// operator's approval, for the TUI's approval queue
+type Approvals interface {
+	// PendingApprovals returns the calls waiting for approval, oldest first
+	PendingApprovals() ([]grpc.Approval, error)
+
+	// DecideApproval lets a parked call through or rejects it with reason
+	DecideApproval(id string, approved bool, reason string) error
+}
+
+// Transcripts is implemented by managers recording the proxied calls of
+// each client, for the TUI's session view
+type Transcripts interface {
+	// Sessions returns the session transcripts without their calls,
+	// latest first
+	Sessions() ([]transcript.Session, error)
+
+	// FindSessions returns the session transcripts with calls an agent
+	// gave id as request or run ID, holding only those calls
+	FindSessions(id string) ([]transcript.Session, error)
+
+	// Session returns a session transcript with its calls
+	Session(id string) (*transcript.Session, error)
+}
+
+// Profiles is implemented by managers keeping the browser profiles of
+// browser-automation servers, for the TUI's detail view
+type Profiles interface {
+	// ResetProfile deletes the files of a server's browser profile, its
+	// attached one if profile is empty
+	ResetProfile(server, profile string) error
+
+	// SnapshotProfile archives a server's browser profile, its attached one
+	// if profile is empty
+	SnapshotProfile(server, profile string) (*grpc.ProfileSnapshot, error)
+}
+
+// DiskUsage is implemented by managers tracking the caches and data of
+// servers, for the TUI's detail view
+type DiskUsage interface {
+	// DiskUsage returns the disk used by servers, all of them when none are
+	// named
+	DiskUsage(names []string) ([]grpc.ServerDisk, error)
+}
+
+// Describer is implemented by managers spawning servers, for the TUI's
+// detail view
+type Describer interface {
+	// DescribeServer returns what a server runs as, or would if started now
+	DescribeServer(name string) (*grpc.Runtime, error)
+}
+
+// Cloner is implemented by managers editing mcp.json, for the TUI's
+// duplicate action
+type Cloner interface {
+	// CloneServer copies a server under a new name, "<source>-2" or the
+	// next free number if empty, with the replacements "old=new" in its
+	// command. Returns the name and port of the copy.
+	CloneServer(source, name string, replace []string) (string, int, error)
+}
+
+// Providers is implemented by managers reached over a connection to a
+// daemon serving a gateway, for the TUI's overview
+type Providers interface {
+	// Providers returns the generic gateway tools with the provider each
+	// selects
+	Providers() ([]grpc.ProviderGroup, error)
+
+	// PinProvider makes a server serve a generic tool, or lets the gateway
+	// select it again when server is empty
+	PinProvider(tool, server string) error
+}
+
+// Notifications is implemented by managers reached over a connection to a
+// daemon, for the TUI's detail view
+type Notifications interface {
+	// Notifications returns the default level of desktop notifications and
+	// the level of every server
+	Notifications() (string, []grpc.NotificationPreference, error)
+
+	// SetNotification sets the level of desktop notifications of a server,
+	// or resets it to the default when level is empty
+	SetNotification(server, level string) error
+}
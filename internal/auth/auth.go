@@ -0,0 +1,199 @@
+// Package auth authenticates the clients of the daemon, the gateway and
+// the proxies through pluggable providers: static tokens, OpenID Connect
+// or JWT tokens, and TLS client certificates
+package auth
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+)
+
+// DaemonClient names the daemon when it calls its own proxies, e.g. to
+// forward the calls of gateway clients
+const DaemonClient = "daemon"
+
+// EnvClient names the clients sending the token of DaemonTokenEnv
+const EnvClient = "env"
+
+// ErrUnauthenticated is returned for clients no provider accepts
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+// errNoCredentials is returned by providers when the client didn't send
+// the kind of credentials they check
+var errNoCredentials = errors.New("no credentials")
+
+// Credentials are what a client presented
+type Credentials struct {
+	Token        string              // Bearer token, empty if none
+	Certificates []*x509.Certificate // Verified client certificate chain, leaf first
+}
+
+// Identity is an authenticated client
+type Identity struct {
+	Subject  string // Name of the client, e.g. a token name or an email
+	Provider string // Name of the provider that authenticated it
+}
+
+// Provider authenticates clients
+type Provider interface {
+	Authenticate(ctx context.Context, creds Credentials) (*Identity, error)
+}
+
+// named sets the provider name of the identities of a provider
+type named struct {
+	name     string
+	provider Provider
+}
+
+// Chain accepts clients any of its providers accepts, tried in order
+type Chain []named
+
+// Authenticate returns the identity from the first provider accepting the
+// client, or an error wrapping ErrUnauthenticated with why each refused
+func (c Chain) Authenticate(ctx context.Context, creds Credentials) (*Identity, error) {
+	var reasons []string
+	for _, p := range c {
+		identity, err := p.provider.Authenticate(ctx, creds)
+		if err == nil {
+			if p.name != "" {
+				identity.Provider = p.name
+			}
+			return identity, nil
+		}
+		if !errors.Is(err, errNoCredentials) {
+			reasons = append(reasons, fmt.Sprintf("%s: %v", p.name, err))
+		}
+	}
+	if len(reasons) == 0 {
+		return nil, fmt.Errorf("%w: no credentials", ErrUnauthenticated)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnauthenticated, strings.Join(reasons, "; "))
+}
+
+// New creates a provider from its settings
+func New(cfg *config.AuthProviderConfig, tlsConfig *config.TLSConfig) (Provider, error) {
+	switch cfg.Type {
+	case "token":
+		return newTokenProvider(cfg)
+	case "oidc", "jwt":
+		return newJWTProvider(cfg)
+	case "mtls":
+		if tlsConfig == nil || tlsConfig.ClientCA == "" {
+			return nil, fmt.Errorf("mtls needs tls.clientCA")
+		}
+		return newMTLSProvider(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown type '%s' (token, oidc, jwt, mtls)", cfg.Type)
+	}
+}
+
+// Build returns the chain of the providers named, or nil if names is
+// empty, leaving the layer open
+func Build(cfg *config.AuthConfig, names []string) (Provider, error) {
+	if cfg == nil || len(names) == 0 {
+		return nil, nil
+	}
+
+	chain := make(Chain, 0, len(names))
+	for _, name := range names {
+		if name == DaemonClient || name == EnvClient {
+			return nil, fmt.Errorf("auth provider name '%s' is reserved", name)
+		}
+		providerConfig, exists := cfg.Providers[name]
+		if !exists || providerConfig == nil {
+			return nil, fmt.Errorf("unknown auth provider '%s'", name)
+		}
+		provider, err := New(providerConfig, cfg.TLS)
+		if err != nil {
+			return nil, fmt.Errorf("auth provider '%s': %w", name, err)
+		}
+		chain = append(chain, named{name: name, provider: provider})
+	}
+	return chain, nil
+}
+
+// Trust returns a provider also accepting token, identifying its client
+// as DaemonClient. A nil provider stays nil, leaving the layer open.
+func Trust(provider Provider, token string) Provider {
+	if provider == nil {
+		return nil
+	}
+	return WithToken(provider, DaemonClient, token)
+}
+
+// WithToken returns a provider also accepting token, identifying its
+// client as name. A nil provider becomes one accepting only token.
+func WithToken(provider Provider, name, token string) Provider {
+	chain := Chain{{name: name, provider: &tokenProvider{tokens: map[string]string{name: token}}}}
+	if provider == nil {
+		return chain
+	}
+	if providers, ok := provider.(Chain); ok {
+		return append(chain, providers...)
+	}
+	return append(chain, named{provider: provider})
+}
+
+// FromRequest returns the credentials of an HTTP request
+func FromRequest(r *http.Request) Credentials {
+	var creds Credentials
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		creds.Token = strings.TrimSpace(token)
+	}
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		creds.Certificates = r.TLS.VerifiedChains[0]
+	}
+	return creds
+}
+
+// identityKey is the request context key of the authenticated identity
+type identityKey struct{}
+
+// FromContext returns the identity a Require handler authenticated, or
+// nil when the layer is open
+func FromContext(ctx context.Context) *Identity {
+	identity, _ := ctx.Value(identityKey{}).(*Identity)
+	return identity
+}
+
+// WithIdentity returns ctx carrying identity
+func WithIdentity(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// Require lets requests through to next once provider authenticates
+// them, answering others with 401 and a JSON-RPC error. A nil provider
+// lets all requests through.
+func Require(provider Provider, next http.Handler) http.Handler {
+	if provider == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions {
+			// CORS preflights carry no credentials
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		identity, err := provider.Authenticate(r.Context(), FromRequest(r))
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      nil,
+				"error":   map[string]interface{}{"code": -32001, "message": "unauthorized"},
+			})
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(WithIdentity(r.Context(), identity)))
+	})
+}
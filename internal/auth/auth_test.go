@@ -0,0 +1,182 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/internal/config"
+)
+
+func tokenConfig(tokens map[string]string) *config.AuthProviderConfig {
+	return &config.AuthProviderConfig{Type: "token", Tokens: tokens}
+}
+
+func TestTokenProvider(t *testing.T) {
+	p, err := newTokenProvider(tokenConfig(map[string]string{"ci": "secret-ci", "alice": "secret-alice"}))
+	require.NoError(t, err)
+
+	identity, err := p.Authenticate(context.Background(), Credentials{Token: "secret-alice"})
+	require.NoError(t, err)
+	assert.Equal(t, "alice", identity.Subject)
+
+	_, err = p.Authenticate(context.Background(), Credentials{Token: "guess"})
+	assert.EqualError(t, err, "unknown token")
+
+	_, err = p.Authenticate(context.Background(), Credentials{})
+	assert.ErrorIs(t, err, errNoCredentials)
+
+	_, err = newTokenProvider(tokenConfig(nil))
+	assert.EqualError(t, err, "tokens is required")
+	_, err = newTokenProvider(tokenConfig(map[string]string{"ci": ""}))
+	assert.EqualError(t, err, "empty token for 'ci'")
+}
+
+func TestBuild(t *testing.T) {
+	cfg := &config.AuthConfig{
+		Providers: map[string]*config.AuthProviderConfig{
+			"ci":    tokenConfig(map[string]string{"ci": "secret"}),
+			"team":  {Type: "mtls"},
+			"other": {Type: "ldap"},
+		},
+	}
+
+	provider, err := Build(cfg, nil)
+	require.NoError(t, err)
+	assert.Nil(t, provider, "layers without providers stay open")
+	provider, err = Build(nil, []string{"ci"})
+	require.NoError(t, err)
+	assert.Nil(t, provider)
+
+	provider, err = Build(cfg, []string{"ci"})
+	require.NoError(t, err)
+	identity, err := provider.Authenticate(context.Background(), Credentials{Token: "secret"})
+	require.NoError(t, err)
+	assert.Equal(t, &Identity{Subject: "ci", Provider: "ci"}, identity)
+
+	_, err = Build(cfg, []string{"missing"})
+	assert.EqualError(t, err, "unknown auth provider 'missing'")
+	_, err = Build(cfg, []string{"other"})
+	assert.EqualError(t, err, "auth provider 'other': unknown type 'ldap' (token, oidc, jwt, mtls)")
+	_, err = Build(cfg, []string{"team"})
+	assert.EqualError(t, err, "auth provider 'team': mtls needs tls.clientCA")
+
+	cfg.Providers[DaemonClient] = tokenConfig(map[string]string{"x": "y"})
+	_, err = Build(cfg, []string{DaemonClient})
+	assert.EqualError(t, err, "auth provider name 'daemon' is reserved")
+	cfg.Providers[EnvClient] = tokenConfig(map[string]string{"x": "y"})
+	_, err = Build(cfg, []string{EnvClient})
+	assert.EqualError(t, err, "auth provider name 'env' is reserved")
+}
+
+func TestChain(t *testing.T) {
+	ci, err := newTokenProvider(tokenConfig(map[string]string{"ci": "secret"}))
+	require.NoError(t, err)
+	chain := Chain{
+		{name: "team", provider: newMTLSProvider(&config.AuthProviderConfig{})},
+		{name: "ci", provider: ci},
+	}
+
+	identity, err := chain.Authenticate(context.Background(), Credentials{Token: "secret"})
+	require.NoError(t, err)
+	assert.Equal(t, "ci", identity.Provider)
+
+	_, err = chain.Authenticate(context.Background(), Credentials{})
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+	assert.EqualError(t, err, "unauthenticated: no credentials")
+
+	_, err = chain.Authenticate(context.Background(), Credentials{Token: "wrong"})
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+	assert.EqualError(t, err, "unauthenticated: ci: unknown token")
+}
+
+func TestTrust(t *testing.T) {
+	assert.Nil(t, Trust(nil, "internal"))
+
+	ci, err := newTokenProvider(tokenConfig(map[string]string{"ci": "secret"}))
+	require.NoError(t, err)
+	provider := Trust(Chain{{name: "ci", provider: ci}}, "internal")
+
+	identity, err := provider.Authenticate(context.Background(), Credentials{Token: "internal"})
+	require.NoError(t, err)
+	assert.Equal(t, &Identity{Subject: DaemonClient, Provider: DaemonClient}, identity)
+
+	identity, err = provider.Authenticate(context.Background(), Credentials{Token: "secret"})
+	require.NoError(t, err)
+	assert.Equal(t, &Identity{Subject: "ci", Provider: "ci"}, identity)
+}
+
+func TestWithToken(t *testing.T) {
+	// An open layer only accepts the token
+	provider := WithToken(nil, EnvClient, "shared")
+	identity, err := provider.Authenticate(context.Background(), Credentials{Token: "shared"})
+	require.NoError(t, err)
+	assert.Equal(t, &Identity{Subject: EnvClient, Provider: EnvClient}, identity)
+	_, err = provider.Authenticate(context.Background(), Credentials{})
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+
+	team := newMTLSProvider(&config.AuthProviderConfig{})
+	provider = WithToken(team, EnvClient, "shared")
+	_, err = provider.Authenticate(context.Background(), Credentials{Token: "shared"})
+	require.NoError(t, err)
+	identity, err = provider.Authenticate(context.Background(), Credentials{Certificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "alice"}}}})
+	require.NoError(t, err)
+	assert.Equal(t, "alice", identity.Subject)
+}
+
+func TestFromRequest(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	assert.Equal(t, Credentials{}, FromRequest(r))
+
+	r.Header.Set("Authorization", "Bearer  abc ")
+	leaf := &x509.Certificate{}
+	r.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{leaf}}}
+	creds := FromRequest(r)
+	assert.Equal(t, "abc", creds.Token)
+	assert.Equal(t, []*x509.Certificate{leaf}, creds.Certificates)
+
+	r.Header.Set("Authorization", "Basic abc")
+	assert.Empty(t, FromRequest(r).Token)
+}
+
+func TestRequire(t *testing.T) {
+	var seen *Identity
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = FromContext(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	ci, err := newTokenProvider(tokenConfig(map[string]string{"ci": "secret"}))
+	require.NoError(t, err)
+	handler := Require(Chain{{name: "ci", provider: ci}}, next)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{}")))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, "Bearer", w.Header().Get("WWW-Authenticate"))
+	assert.Contains(t, w.Body.String(), `"code":-32001`)
+
+	w = httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{}"))
+	r.Header.Set("Authorization", "Bearer secret")
+	handler.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, &Identity{Subject: "ci", Provider: "ci"}, seen)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodOptions, "/", nil))
+	assert.Equal(t, http.StatusNoContent, w.Code, "preflights pass")
+
+	seen = nil
+	w = httptest.NewRecorder()
+	Require(nil, next).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Nil(t, seen, "open layers have no identity")
+}
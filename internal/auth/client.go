@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// Environment variables holding the credentials the clients of a guarded
+// daemon or gateway present
+const (
+	TokenEnv = "MCP_MANAGER_TOKEN"    // Bearer token
+	CAEnv    = "MCP_MANAGER_TLS_CA"   // PEM CAs verifying the daemon, enabling TLS
+	CertEnv  = "MCP_MANAGER_TLS_CERT" // PEM client certificate, for mtls providers
+	KeyEnv   = "MCP_MANAGER_TLS_KEY"  // Private key of the client certificate
+
+	// DaemonTokenEnv holds a bearer token the daemon requires of API
+	// clients, besides the providers auth.daemon lists in mcp.json
+	DaemonTokenEnv = "MCP_MANAGER_DAEMON_TOKEN"
+
+	// ServerNameEnv overrides the name the daemon's certificate is
+	// verified for, e.g. when reaching it through a tunnel on localhost
+	ServerNameEnv = "MCP_MANAGER_TLS_SERVER_NAME"
+)
+
+// ClientToken returns the bearer token clients send, empty if none
+func ClientToken() string {
+	return os.Getenv(TokenEnv)
+}
+
+// TLSFiles are the files of the TLS settings clients connect with
+type TLSFiles struct {
+	CA         string // PEM CAs verifying the daemon, enabling TLS
+	Cert       string // PEM client certificate, for mtls providers and requireClientCert
+	Key        string // Private key of the client certificate
+	ServerName string // Name the daemon's certificate is verified for, e.g. through a tunnel
+}
+
+// EnvTLSFiles returns the TLS files of the environment
+func EnvTLSFiles() TLSFiles {
+	return TLSFiles{
+		CA:         os.Getenv(CAEnv),
+		Cert:       os.Getenv(CertEnv),
+		Key:        os.Getenv(KeyEnv),
+		ServerName: os.Getenv(ServerNameEnv),
+	}
+}
+
+// ClientTLS returns the TLS settings clients connect with according to
+// the environment, or nil to connect without TLS
+func ClientTLS() (*tls.Config, error) {
+	return LoadClientTLS(EnvTLSFiles())
+}
+
+// LoadClientTLS returns the TLS settings of files, or nil to connect
+// without TLS if they name neither a CA nor a certificate
+func LoadClientTLS(files TLSFiles) (*tls.Config, error) {
+	if files.CA == "" && files.Cert == "" {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12, ServerName: files.ServerName}
+	if files.CA != "" {
+		data, err := os.ReadFile(files.CA)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificates in %s", files.CA)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if files.Cert != "" {
+		pair, err := tls.LoadX509KeyPair(files.Cert, files.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{pair}
+	}
+	return tlsConfig, nil
+}
@@ -0,0 +1,320 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	jose "github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/tartavull/mcp-manager/internal/config"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// keysTTL is how long fetched signing keys are used before refetching
+	keysTTL = time.Hour
+
+	// keysRetry is how often keys are refetched for unknown key IDs, e.g.
+	// after the issuer rotated them
+	keysRetry = time.Minute
+
+	// clockSkew is the leeway of token expiry and not-before times
+	clockSkew = time.Minute
+)
+
+// algorithms are the signature algorithms accepted. Symmetric and unsigned
+// tokens are refused, so a public key can't be used as a shared secret.
+var algorithms = []jose.SignatureAlgorithm{
+	jose.RS256, jose.RS384, jose.RS512,
+	jose.PS256, jose.PS384, jose.PS512,
+	jose.ES256, jose.ES384, jose.ES512,
+}
+
+// curves are the curves of the keys of the ECDSA algorithms
+var curves = map[jose.SignatureAlgorithm]elliptic.Curve{
+	jose.ES256: elliptic.P256(),
+	jose.ES384: elliptic.P384(),
+	jose.ES512: elliptic.P521(),
+}
+
+// jwtProvider accepts JWTs signed by the keys of an OpenID Connect issuer
+// or a JWKS URL
+type jwtProvider struct {
+	issuer       string
+	jwksURL      string // Empty until discovered for oidc
+	audience     string
+	subjectClaim string
+	claims       map[string]string
+	client       *http.Client
+
+	fetches singleflight.Group // Fetches of the keys in progress
+
+	mu       sync.Mutex
+	keys     map[string]crypto.PublicKey // Key ID to key
+	fetched  time.Time                   // Last attempt to fetch the keys
+	fetchErr error                       // Why it failed
+}
+
+// newJWTProvider creates a provider validating JWTs. Keys are fetched on
+// first use, so an unreachable issuer doesn't prevent startup.
+func newJWTProvider(cfg *config.AuthProviderConfig) (*jwtProvider, error) {
+	if cfg.Type == "oidc" && cfg.Issuer == "" {
+		return nil, fmt.Errorf("issuer is required")
+	}
+	if cfg.Type == "jwt" && cfg.JWKSURL == "" {
+		return nil, fmt.Errorf("jwksURL is required")
+	}
+
+	p := &jwtProvider{
+		issuer:       strings.TrimSuffix(cfg.Issuer, "/"),
+		jwksURL:      cfg.JWKSURL,
+		audience:     cfg.Audience,
+		subjectClaim: cfg.SubjectClaim,
+		claims:       cfg.Claims,
+		client:       &http.Client{Timeout: 10 * time.Second},
+	}
+	if p.subjectClaim == "" {
+		p.subjectClaim = "sub"
+	}
+	return p, nil
+}
+
+// Authenticate accepts clients with a valid token, naming them after its
+// subject claim
+func (p *jwtProvider) Authenticate(ctx context.Context, creds Credentials) (*Identity, error) {
+	if strings.Count(creds.Token, ".") != 2 {
+		// Not a JWT, e.g. a static token
+		return nil, errNoCredentials
+	}
+
+	claims, err := p.verify(ctx, creds.Token)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkClaims(claims, time.Now()); err != nil {
+		return nil, err
+	}
+	subject, _ := claims[p.subjectClaim].(string)
+	if subject == "" {
+		return nil, fmt.Errorf("token has no %s claim", p.subjectClaim)
+	}
+	return &Identity{Subject: subject}, nil
+}
+
+// verify checks the signature of a token, returning its claims
+func (p *jwtProvider) verify(ctx context.Context, token string) (map[string]interface{}, error) {
+	parsed, err := jwt.ParseSigned(token, algorithms)
+	if err != nil {
+		var unexpected *jose.ErrUnexpectedSignatureAlgorithm
+		if errors.As(err, &unexpected) {
+			return nil, fmt.Errorf("unsupported algorithm '%s'", unexpected.Got)
+		}
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+	header := parsed.Headers[0]
+
+	key, err := p.key(ctx, header.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkKey(jose.SignatureAlgorithm(header.Algorithm), key); err != nil {
+		return nil, err
+	}
+
+	var claims map[string]interface{}
+	if err := parsed.Claims(key, &claims); err != nil {
+		if errors.Is(err, jose.ErrCryptoFailure) {
+			return nil, errors.New("invalid token signature")
+		}
+		return nil, fmt.Errorf("invalid token claims: %w", err)
+	}
+	return claims, nil
+}
+
+// checkClaims checks the expiry, issuer, audience and required claims of
+// a verified token
+func (p *jwtProvider) checkClaims(claims map[string]interface{}, now time.Time) error {
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return fmt.Errorf("token has no expiry")
+	}
+	if now.After(time.Unix(int64(exp), 0).Add(clockSkew)) {
+		return fmt.Errorf("token expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(clockSkew).Before(time.Unix(int64(nbf), 0)) {
+		return fmt.Errorf("token not valid yet")
+	}
+	if p.issuer != "" {
+		if iss, _ := claims["iss"].(string); strings.TrimSuffix(iss, "/") != p.issuer {
+			return fmt.Errorf("token issued by '%s'", iss)
+		}
+	}
+	if p.audience != "" && !claimContains(claims["aud"], p.audience) {
+		return fmt.Errorf("token not meant for '%s'", p.audience)
+	}
+	for claim, value := range p.claims {
+		if !claimContains(claims[claim], value) {
+			return fmt.Errorf("token lacks %s '%s'", claim, value)
+		}
+	}
+	return nil
+}
+
+// claimContains returns true if a claim is value or a list containing it
+func claimContains(claim interface{}, value string) bool {
+	switch c := claim.(type) {
+	case string:
+		return c == value
+	case []interface{}:
+		for _, item := range c {
+			if s, ok := item.(string); ok && s == value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// key returns the signing key with ID kid, fetching the keys when they're
+// stale or don't include it
+func (p *jwtProvider) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	p.mu.Lock()
+	stale := time.Since(p.fetched) > keysTTL
+	retry := stale || time.Since(p.fetched) >= keysRetry
+	key, known := p.lookup(kid)
+	fetchErr := p.fetchErr
+	p.mu.Unlock()
+
+	if known && !stale {
+		return key, nil
+	}
+	if !retry {
+		if fetchErr != nil {
+			return nil, fetchErr
+		}
+		return nil, fmt.Errorf("unknown signing key '%s'", kid)
+	}
+
+	// Requests arriving meanwhile wait for the same fetch, which outlives
+	// the request starting it
+	_, err, _ := p.fetches.Do("keys", func() (interface{}, error) {
+		keys, err := p.fetchKeys(context.WithoutCancel(ctx))
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.fetched, p.fetchErr = time.Now(), err
+		if err == nil {
+			p.keys = keys
+		}
+		return nil, err
+	})
+	if err != nil {
+		if known {
+			// Keep using the keys while the issuer is unreachable
+			return key, nil
+		}
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if key, known = p.lookup(kid); !known {
+		return nil, fmt.Errorf("unknown signing key '%s'", kid)
+	}
+	return key, nil
+}
+
+// lookup returns the key with ID kid, or the only key for tokens without
+// a key ID
+func (p *jwtProvider) lookup(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	key, exists := p.keys[kid]
+	return key, exists
+}
+
+// fetchKeys downloads the signing keys, discovering their URL from the
+// issuer's metadata if needed
+func (p *jwtProvider) fetchKeys(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	if p.jwksURL == "" {
+		var metadata struct {
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if err := p.getJSON(ctx, p.issuer+"/.well-known/openid-configuration", &metadata); err != nil {
+			return nil, fmt.Errorf("failed to discover issuer keys: %w", err)
+		}
+		if metadata.JWKSURI == "" {
+			return nil, fmt.Errorf("issuer metadata has no jwks_uri")
+		}
+		p.jwksURL = metadata.JWKSURI
+	}
+
+	// Keys of types not supported are skipped rather than failing the set
+	var set struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+	if err := p.getJSON(ctx, p.jwksURL, &set); err != nil {
+		return nil, fmt.Errorf("failed to fetch signing keys: %w", err)
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, data := range set.Keys {
+		var k jose.JSONWebKey
+		if err := k.UnmarshalJSON(data); err != nil || !k.Valid() {
+			continue
+		}
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		switch key := k.Public().Key.(type) {
+		case *rsa.PublicKey, *ecdsa.PublicKey:
+			keys[k.KeyID] = key
+		}
+	}
+	return keys, nil
+}
+
+// getJSON decodes the JSON document at url
+func (p *jwtProvider) getJSON(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// checkKey checks key is of the type alg signs with, and of its curve for
+// ECDSA
+func checkKey(alg jose.SignatureAlgorithm, key crypto.PublicKey) error {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if strings.HasPrefix(string(alg), "RS") || strings.HasPrefix(string(alg), "PS") {
+			return nil
+		}
+	case *ecdsa.PublicKey:
+		if curve, exists := curves[alg]; exists && k.Curve == curve {
+			return nil
+		}
+	}
+	return fmt.Errorf("key doesn't match algorithm '%s'", alg)
+}
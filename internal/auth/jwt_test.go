@@ -0,0 +1,230 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/internal/config"
+)
+
+// testIssuer serves OpenID Connect metadata and signing keys
+type testIssuer struct {
+	*httptest.Server
+	rsaKey  *rsa.PrivateKey
+	ecKey   *ecdsa.PrivateKey
+	fetches atomic.Int32
+	delay   atomic.Int64 // Of serving the keys
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	issuer := &testIssuer{rsaKey: rsaKey, ecKey: ecKey}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"issuer": issuer.URL, "jwks_uri": issuer.URL + "/keys"})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		issuer.fetches.Add(1)
+		time.Sleep(time.Duration(issuer.delay.Load()))
+		encode := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{
+			{"kty": "RSA", "kid": "rsa", "use": "sig", "n": encode(rsaKey.N.Bytes()), "e": "AQAB"},
+			{"kty": "EC", "kid": "ec", "crv": "P-256", "x": encode(ecKey.X.FillBytes(make([]byte, 32))), "y": encode(ecKey.Y.FillBytes(make([]byte, 32)))},
+			{"kty": "RSA", "kid": "enc", "use": "enc", "n": encode(rsaKey.N.Bytes()), "e": "AQAB"},
+		}})
+	})
+	issuer.Server = httptest.NewServer(mux)
+	t.Cleanup(issuer.Close)
+	return issuer
+}
+
+// sign returns a token with claims signed with alg and the key kid
+func (i *testIssuer) sign(t *testing.T, alg, kid string, claims map[string]interface{}) string {
+	encode := func(v interface{}) string {
+		data, err := json.Marshal(v)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signed := encode(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"}) + "." + encode(claims)
+	digest := sha256.Sum256([]byte(signed))
+
+	var signature []byte
+	switch alg {
+	case "RS256":
+		var err error
+		signature, err = rsa.SignPKCS1v15(rand.Reader, i.rsaKey, crypto.SHA256, digest[:])
+		require.NoError(t, err)
+	case "ES256":
+		r, s, err := ecdsa.Sign(rand.Reader, i.ecKey, digest[:])
+		require.NoError(t, err)
+		signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	default:
+		signature = []byte("signature")
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// claims returns valid claims for the issuer
+func (i *testIssuer) claims() map[string]interface{} {
+	return map[string]interface{}{
+		"iss":    i.URL,
+		"sub":    "alice@example.com",
+		"aud":    []string{"mcp-manager", "other"},
+		"exp":    time.Now().Add(time.Hour).Unix(),
+		"groups": []string{"engineering"},
+	}
+}
+
+func TestJWTProvider(t *testing.T) {
+	issuer := newTestIssuer(t)
+	p, err := newJWTProvider(&config.AuthProviderConfig{
+		Type:     "oidc",
+		Issuer:   issuer.URL + "/",
+		Audience: "mcp-manager",
+		Claims:   map[string]string{"groups": "engineering"},
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	for _, alg := range []string{"RS256", "ES256"} {
+		kid := map[string]string{"RS256": "rsa", "ES256": "ec"}[alg]
+		identity, err := p.Authenticate(ctx, Credentials{Token: issuer.sign(t, alg, kid, issuer.claims())})
+		require.NoError(t, err, alg)
+		assert.Equal(t, "alice@example.com", identity.Subject)
+	}
+	assert.Equal(t, int32(1), issuer.fetches.Load(), "keys are cached")
+
+	tests := []struct {
+		name   string
+		modify func(claims map[string]interface{})
+		alg    string
+		kid    string
+		err    string
+	}{
+		{name: "expired", modify: func(c map[string]interface{}) { c["exp"] = time.Now().Add(-time.Hour).Unix() }, err: "token expired"},
+		{name: "no expiry", modify: func(c map[string]interface{}) { delete(c, "exp") }, err: "token has no expiry"},
+		{name: "not valid yet", modify: func(c map[string]interface{}) { c["nbf"] = time.Now().Add(time.Hour).Unix() }, err: "token not valid yet"},
+		{name: "other issuer", modify: func(c map[string]interface{}) { c["iss"] = "https://evil.example.com" }, err: "token issued by 'https://evil.example.com'"},
+		{name: "other audience", modify: func(c map[string]interface{}) { c["aud"] = "other" }, err: "token not meant for 'mcp-manager'"},
+		{name: "missing claim", modify: func(c map[string]interface{}) { c["groups"] = []string{"sales"} }, err: "token lacks groups 'engineering'"},
+		{name: "no subject", modify: func(c map[string]interface{}) { delete(c, "sub") }, err: "token has no sub claim"},
+		{name: "symmetric", alg: "HS256", err: "unsupported algorithm 'HS256'"},
+		{name: "unsigned", alg: "none", err: "unsupported algorithm 'none'"},
+		{name: "wrong key type", alg: "ES256", kid: "rsa", err: "key doesn't match algorithm 'ES256'"},
+		{name: "wrong curve", alg: "ES384", kid: "ec", err: "key doesn't match algorithm 'ES384'"},
+		{name: "encryption key", kid: "enc", err: "unknown signing key 'enc'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := issuer.claims()
+			if tt.modify != nil {
+				tt.modify(claims)
+			}
+			alg, kid := tt.alg, tt.kid
+			if alg == "" {
+				alg = "RS256"
+			}
+			if kid == "" {
+				kid = "rsa"
+			}
+			_, err := p.Authenticate(ctx, Credentials{Token: issuer.sign(t, alg, kid, claims)})
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+
+	t.Run("bad signature", func(t *testing.T) {
+		claims := issuer.claims()
+		claims["sub"] = "mallory@example.com"
+		token := strings.Split(issuer.sign(t, "RS256", "rsa", issuer.claims()), ".")
+		forged := strings.Split(issuer.sign(t, "RS256", "rsa", claims), ".")
+		_, err := p.Authenticate(ctx, Credentials{Token: forged[0] + "." + forged[1] + "." + token[2]})
+		assert.EqualError(t, err, "invalid token signature")
+	})
+
+	t.Run("not a JWT", func(t *testing.T) {
+		_, err := p.Authenticate(ctx, Credentials{Token: "static-token"})
+		assert.ErrorIs(t, err, errNoCredentials)
+	})
+}
+
+func TestJWTProviderKeys(t *testing.T) {
+	issuer := newTestIssuer(t)
+	p, err := newJWTProvider(&config.AuthProviderConfig{Type: "jwt", JWKSURL: issuer.URL + "/keys"})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = p.Authenticate(ctx, Credentials{Token: issuer.sign(t, "RS256", "rotated", issuer.claims())})
+	assert.EqualError(t, err, "unknown signing key 'rotated'")
+	_, err = p.Authenticate(ctx, Credentials{Token: issuer.sign(t, "RS256", "rotated", issuer.claims())})
+	assert.EqualError(t, err, "unknown signing key 'rotated'")
+	assert.Equal(t, int32(1), issuer.fetches.Load(), "unknown keys are refetched at most every keysRetry")
+
+	// Keys are still used when the issuer goes away after they expire
+	p.fetched = time.Now().Add(-2 * keysTTL)
+	issuer.Close()
+	identity, err := p.Authenticate(ctx, Credentials{Token: issuer.sign(t, "ES256", "ec", issuer.claims())})
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.com", identity.Subject)
+	assert.Error(t, p.fetchErr)
+}
+
+func TestJWTProviderConcurrentFetch(t *testing.T) {
+	issuer := newTestIssuer(t)
+	issuer.delay.Store(int64(300 * time.Millisecond))
+	p, err := newJWTProvider(&config.AuthProviderConfig{Type: "jwt", JWKSURL: issuer.URL + "/keys"})
+	require.NoError(t, err)
+	token := issuer.sign(t, "RS256", "rsa", issuer.claims())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := p.Authenticate(context.Background(), Credentials{Token: token})
+			assert.NoError(t, err)
+		}()
+	}
+
+	// The keys are fetched without holding the lock, once for all requests
+	require.Eventually(t, func() bool { return issuer.fetches.Load() == 1 }, time.Second, time.Millisecond)
+	assert.Eventually(t, func() bool {
+		if !p.mu.TryLock() {
+			return false
+		}
+		p.mu.Unlock()
+		return true
+	}, 100*time.Millisecond, time.Millisecond)
+	wg.Wait()
+	assert.Equal(t, int32(1), issuer.fetches.Load())
+}
+
+func TestNewJWTProvider(t *testing.T) {
+	_, err := newJWTProvider(&config.AuthProviderConfig{Type: "oidc"})
+	assert.EqualError(t, err, "issuer is required")
+	_, err = newJWTProvider(&config.AuthProviderConfig{Type: "jwt"})
+	assert.EqualError(t, err, "jwksURL is required")
+
+	p, err := newJWTProvider(&config.AuthProviderConfig{Type: "jwt", JWKSURL: "http://keys", SubjectClaim: "email"})
+	require.NoError(t, err)
+	assert.Equal(t, "email", p.subjectClaim)
+}
@@ -0,0 +1,99 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+)
+
+// mtlsProvider accepts clients presenting a certificate signed by the
+// client CA, which the TLS handshake verifies
+type mtlsProvider struct {
+	subjects []string // Accepted names; empty accepts all
+}
+
+// newMTLSProvider creates a provider accepting verified certificates
+func newMTLSProvider(cfg *config.AuthProviderConfig) *mtlsProvider {
+	return &mtlsProvider{subjects: cfg.Subjects}
+}
+
+// Authenticate accepts clients with a verified certificate, naming them
+// after its common name, or its first DNS name or email if it has none
+func (p *mtlsProvider) Authenticate(ctx context.Context, creds Credentials) (*Identity, error) {
+	if len(creds.Certificates) == 0 {
+		return nil, errNoCredentials
+	}
+
+	leaf := creds.Certificates[0]
+	names := certificateNames(leaf)
+	if len(names) == 0 {
+		return nil, fmt.Errorf("certificate has no name")
+	}
+	if len(p.subjects) == 0 {
+		return &Identity{Subject: names[0]}, nil
+	}
+	for _, name := range names {
+		if slices.Contains(p.subjects, name) {
+			return &Identity{Subject: name}, nil
+		}
+	}
+	return nil, fmt.Errorf("certificate '%s' not allowed", names[0])
+}
+
+// certificateNames returns the names of a certificate, common name first
+func certificateNames(cert *x509.Certificate) []string {
+	var names []string
+	if cert.Subject.CommonName != "" {
+		names = append(names, cert.Subject.CommonName)
+	}
+	names = append(names, cert.DNSNames...)
+	return append(names, cert.EmailAddresses...)
+}
+
+// ServerTLS returns the TLS settings of a listener, asking clients for a
+// certificate signed by the client CA if there is one. Clients without a
+// certificate may still authenticate with a token.
+func ServerTLS(cfg *config.TLSConfig) (*tls.Config, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	if cfg.Cert == "" || cfg.Key == "" {
+		return nil, fmt.Errorf("tls needs cert and key")
+	}
+	if cfg.RequireClientCert && cfg.ClientCA == "" {
+		return nil, fmt.Errorf("requireClientCert needs clientCA")
+	}
+
+	cert, err := tls.LoadX509KeyPair(cfg.Cert, cfg.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	tlsConfig := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+
+	if cfg.ClientCA != "" {
+		data, err := os.ReadFile(cfg.ClientCA)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificates in client CA %s", cfg.ClientCA)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return tlsConfig, nil
+}
+
+// RequireClientCert returns tlsConfig rejecting clients without a
+// certificate signed by its client CA during the handshake
+func RequireClientCert(tlsConfig *tls.Config) *tls.Config {
+	tlsConfig = tlsConfig.Clone()
+	tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	return tlsConfig
+}
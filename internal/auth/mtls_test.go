@@ -0,0 +1,219 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/internal/config"
+)
+
+// testCert is a certificate and its key
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	der  []byte
+}
+
+// newTestCert creates a certificate signed by parent, or a self-signed CA
+// if parent is nil
+func newTestCert(t *testing.T, template *x509.Certificate, parent *testCert) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCert{cert: cert, key: key, der: der}
+}
+
+// write saves the certificate and key as PEM files, returning their paths
+func (c *testCert) write(t *testing.T, name string) (string, string) {
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, name+".pem")
+	keyPath := filepath.Join(dir, name+"-key.pem")
+	require.NoError(t, os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.der}), 0600))
+	keyDER, err := x509.MarshalECPrivateKey(c.key)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	return certPath, keyPath
+}
+
+func TestMTLSProvider(t *testing.T) {
+	ctx := context.Background()
+	named := &x509.Certificate{Subject: pkix.Name{CommonName: "alice"}, DNSNames: []string{"alice.example.com"}}
+	unnamed := &x509.Certificate{EmailAddresses: []string{"bob@example.com"}}
+
+	p := newMTLSProvider(&config.AuthProviderConfig{})
+	identity, err := p.Authenticate(ctx, Credentials{Certificates: []*x509.Certificate{named}})
+	require.NoError(t, err)
+	assert.Equal(t, "alice", identity.Subject)
+	identity, err = p.Authenticate(ctx, Credentials{Certificates: []*x509.Certificate{unnamed}})
+	require.NoError(t, err)
+	assert.Equal(t, "bob@example.com", identity.Subject)
+	_, err = p.Authenticate(ctx, Credentials{Certificates: []*x509.Certificate{{}}})
+	assert.EqualError(t, err, "certificate has no name")
+	_, err = p.Authenticate(ctx, Credentials{Token: "abc"})
+	assert.ErrorIs(t, err, errNoCredentials)
+
+	p = newMTLSProvider(&config.AuthProviderConfig{Subjects: []string{"alice.example.com"}})
+	identity, err = p.Authenticate(ctx, Credentials{Certificates: []*x509.Certificate{named}})
+	require.NoError(t, err)
+	assert.Equal(t, "alice.example.com", identity.Subject)
+	_, err = p.Authenticate(ctx, Credentials{Certificates: []*x509.Certificate{unnamed}})
+	assert.EqualError(t, err, "certificate 'bob@example.com' not allowed")
+}
+
+func TestServerTLS(t *testing.T) {
+	ca := newTestCert(t, &x509.Certificate{Subject: pkix.Name{CommonName: "test CA"}}, nil)
+	server := newTestCert(t, &x509.Certificate{
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, ca)
+	client := newTestCert(t, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "alice"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca)
+	caPath, _ := ca.write(t, "ca")
+	serverCert, serverKey := server.write(t, "server")
+	clientCert, clientKey := client.write(t, "client")
+
+	tlsConfig, err := ServerTLS(nil)
+	require.NoError(t, err)
+	assert.Nil(t, tlsConfig)
+	_, err = ServerTLS(&config.TLSConfig{Cert: serverCert})
+	assert.EqualError(t, err, "tls needs cert and key")
+	_, err = ServerTLS(&config.TLSConfig{Cert: serverCert, Key: serverKey, ClientCA: serverKey})
+	assert.EqualError(t, err, "no certificates in client CA "+serverKey)
+
+	tlsConfig, err = ServerTLS(&config.TLSConfig{Cert: serverCert, Key: serverKey, ClientCA: caPath})
+	require.NoError(t, err)
+	provider, err := Build(&config.AuthConfig{
+		Providers: map[string]*config.AuthProviderConfig{
+			"team": {Type: "mtls"},
+			"ci":   tokenConfig(map[string]string{"ci": "secret"}),
+		},
+		TLS: &config.TLSConfig{ClientCA: caPath},
+	}, []string{"team", "ci"})
+	require.NoError(t, err)
+
+	srv := httptest.NewUnstartedServer(Require(provider, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, FromContext(r.Context()).Subject)
+	})))
+	srv.TLS = tlsConfig
+	srv.StartTLS()
+	defer srv.Close()
+
+	get := func(env map[string]string) (int, string) {
+		for _, name := range []string{TokenEnv, CAEnv, CertEnv, KeyEnv} {
+			t.Setenv(name, env[name])
+		}
+		clientTLS, err := ClientTLS()
+		require.NoError(t, err)
+		httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: clientTLS}}
+		req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+		require.NoError(t, err)
+		if token := ClientToken(); token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := httpClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	code, body := get(map[string]string{CAEnv: caPath, CertEnv: clientCert, KeyEnv: clientKey})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "alice", body)
+
+	code, body = get(map[string]string{CAEnv: caPath, TokenEnv: "secret"})
+	assert.Equal(t, http.StatusOK, code, "clients without a certificate may use a token")
+	assert.Equal(t, "ci", body)
+
+	code, _ = get(map[string]string{CAEnv: caPath})
+	assert.Equal(t, http.StatusUnauthorized, code)
+}
+
+func TestClientTLS(t *testing.T) {
+	t.Setenv(CAEnv, "")
+	t.Setenv(CertEnv, "")
+	tlsConfig, err := ClientTLS()
+	require.NoError(t, err)
+	assert.Nil(t, tlsConfig)
+
+	t.Setenv(CAEnv, filepath.Join(t.TempDir(), "missing.pem"))
+	_, err = ClientTLS()
+	assert.ErrorContains(t, err, "failed to read CA")
+}
+
+func TestRequireClientCert(t *testing.T) {
+	ca := newTestCert(t, &x509.Certificate{Subject: pkix.Name{CommonName: "test CA"}}, nil)
+	server := newTestCert(t, &x509.Certificate{
+		DNSNames:    []string{"daemon.internal"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, ca)
+	client := newTestCert(t, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "alice"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca)
+	caPath, _ := ca.write(t, "ca")
+	serverCert, serverKey := server.write(t, "server")
+	clientCert, clientKey := client.write(t, "client")
+
+	_, err := ServerTLS(&config.TLSConfig{Cert: serverCert, Key: serverKey, RequireClientCert: true})
+	assert.EqualError(t, err, "requireClientCert needs clientCA")
+	tlsConfig, err := ServerTLS(&config.TLSConfig{Cert: serverCert, Key: serverKey, ClientCA: caPath, RequireClientCert: true})
+	require.NoError(t, err)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.TLS = RequireClientCert(tlsConfig)
+	srv.StartTLS()
+	defer srv.Close()
+
+	get := func(files TLSFiles) error {
+		clientTLS, err := LoadClientTLS(files)
+		require.NoError(t, err)
+		httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: clientTLS}}
+		resp, err := httpClient.Get(srv.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	// The certificate names the daemon, not the address it is reached on
+	assert.Error(t, get(TLSFiles{CA: caPath, Cert: clientCert, Key: clientKey}))
+	assert.NoError(t, get(TLSFiles{CA: caPath, Cert: clientCert, Key: clientKey, ServerName: "daemon.internal"}))
+	assert.Error(t, get(TLSFiles{CA: caPath, ServerName: "daemon.internal"}), "clients need a certificate")
+	assert.Equal(t, tls.VerifyClientCertIfGiven, tlsConfig.ClientAuth, "the original settings are left alone")
+}
@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+)
+
+// tokenProvider accepts a fixed set of bearer tokens
+type tokenProvider struct {
+	tokens map[string]string // Client name to token
+}
+
+// newTokenProvider creates a provider accepting the configured tokens
+func newTokenProvider(cfg *config.AuthProviderConfig) (*tokenProvider, error) {
+	if len(cfg.Tokens) == 0 {
+		return nil, fmt.Errorf("tokens is required")
+	}
+	for name, token := range cfg.Tokens {
+		if token == "" {
+			return nil, fmt.Errorf("empty token for '%s'", name)
+		}
+	}
+	return &tokenProvider{tokens: cfg.Tokens}, nil
+}
+
+// Authenticate accepts clients sending one of the tokens, naming them
+// after it
+func (p *tokenProvider) Authenticate(ctx context.Context, creds Credentials) (*Identity, error) {
+	if creds.Token == "" {
+		return nil, errNoCredentials
+	}
+
+	// Compare with every token, so timing doesn't tell which one is close
+	subject := ""
+	for name, token := range p.tokens {
+		if subtle.ConstantTimeCompare([]byte(creds.Token), []byte(token)) == 1 {
+			subject = name
+		}
+	}
+	if subject == "" {
+		return nil, errors.New("unknown token")
+	}
+	return &Identity{Subject: subject}, nil
+}
@@ -0,0 +1,350 @@
+// Package bus exports daemon events to message buses, so automation can
+// react to server lifecycle changes without holding a gRPC stream open
+package bus
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+	pb "github.com/tartavull/mcp-manager/internal/grpc/pb"
+	"github.com/tartavull/mcp-manager/internal/plugin"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	// queueSize is how many events may wait for a slow broker before
+	// events are dropped
+	queueSize = 256
+
+	// dialTimeout bounds connecting to a broker
+	dialTimeout = 5 * time.Second
+
+	// writeTimeout bounds sending a message to a broker
+	writeTimeout = 5 * time.Second
+)
+
+// Publisher sends messages to topics of a message bus
+type Publisher interface {
+	Publish(topic string, payload []byte) error
+	Close() error
+}
+
+// Escaper is implemented by publishers whose topics reserve characters, to
+// make server names safe to substitute into a topic
+type Escaper interface {
+	Escape(name string) string
+}
+
+// Exporter forwards events to the configured message buses
+type Exporter struct {
+	sinks []*sink
+	wg    sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// sink is a message bus and the topics events are sent to
+type sink struct {
+	name      string
+	publisher Publisher
+	topics    map[string]string // Lowercase event type, or "*", to topic template
+	queue     chan message
+}
+
+// message is an event waiting to be published
+type message struct {
+	topic   string
+	payload []byte
+}
+
+// New creates an exporter for the configured buses, or nil if there are
+// none. Brokers are connected on the first event.
+func New(cfgs []config.EventExportConfig) (*Exporter, error) {
+	return NewWithPlugins(cfgs, "")
+}
+
+// NewWithPlugins creates an exporter like New, finding the notifier
+// plugins of plugin sinks in pluginDir
+func NewWithPlugins(cfgs []config.EventExportConfig, pluginDir string) (*Exporter, error) {
+	if len(cfgs) == 0 {
+		return nil, nil
+	}
+
+	e := &Exporter{}
+	for i, cfg := range cfgs {
+		s, err := newSink(cfg, pluginDir)
+		if err != nil {
+			e.Close()
+			return nil, fmt.Errorf("invalid eventExport entry %d: %w", i+1, err)
+		}
+		e.sinks = append(e.sinks, s)
+	}
+
+	for _, s := range e.sinks {
+		e.wg.Add(1)
+		go func() {
+			defer e.wg.Done()
+			s.run()
+		}()
+	}
+	return e, nil
+}
+
+// newSink creates the publisher and topic mapping of a bus
+func newSink(cfg config.EventExportConfig, pluginDir string) (*sink, error) {
+	// Notifier plugins receive every event unless told otherwise
+	if cfg.Type == "plugin" && len(cfg.Topics) == 0 {
+		cfg.Topics = map[string]string{"*": "{type}"}
+	}
+
+	topics := make(map[string]string)
+	for key, topic := range cfg.Topics {
+		key = strings.ToLower(key)
+		if key != "*" && !exportable(key) {
+			return nil, fmt.Errorf("unknown event type '%s'", key)
+		}
+		if topic == "" {
+			return nil, fmt.Errorf("empty topic for '%s'", key)
+		}
+		topics[key] = topic
+	}
+	if len(topics) == 0 {
+		return nil, fmt.Errorf("no topics")
+	}
+
+	name := cfg.Type
+	var publisher Publisher
+	switch cfg.Type {
+	case "nats":
+		publisher = NewNATS(hostPort(cfg.Address, "4222"), cfg.Username, cfg.Password)
+	case "redis":
+		publisher = NewRedis(hostPort(cfg.Address, "6379"), cfg.Username, cfg.Password)
+	case "mqtt":
+		publisher = NewMQTT(hostPort(cfg.Address, "1883"), cfg.Username, cfg.Password)
+	case "plugin":
+		p, err := plugin.Find(pluginDir, cfg.Plugin, plugin.KindNotifier)
+		if err != nil {
+			return nil, err
+		}
+		name, publisher = "plugin "+p.Name, NewPlugin(p, cfg.Config)
+	default:
+		return nil, fmt.Errorf("unknown type '%s', expected nats, redis, mqtt or plugin", cfg.Type)
+	}
+
+	return &sink{
+		name:      name,
+		publisher: publisher,
+		topics:    topics,
+		queue:     make(chan message, queueSize),
+	}, nil
+}
+
+// exportable returns true if events of a lowercase type can be exported
+func exportable(eventType string) bool {
+	value, ok := pb.EventType_value[strings.ToUpper(eventType)]
+	return ok && value != int32(pb.EventType_ALL) && value != int32(pb.EventType_HEARTBEAT)
+}
+
+// hostPort returns the broker address, accepting URLs like
+// nats://host:port and defaulting to localhost and the protocol's port
+func hostPort(address, defaultPort string) string {
+	if _, rest, ok := strings.Cut(address, "://"); ok {
+		address = rest
+	}
+	address = strings.TrimSuffix(address, "/")
+	if address == "" {
+		return net.JoinHostPort("localhost", defaultPort)
+	}
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		return net.JoinHostPort(address, defaultPort)
+	}
+	return address
+}
+
+// Export queues an event for the buses whose topics map its type
+func (e *Exporter) Export(event *pb.Event) {
+	if e == nil || event.Type == pb.EventType_HEARTBEAT {
+		return
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closed {
+		return
+	}
+
+	var payload []byte
+	for _, s := range e.sinks {
+		topic, ok := s.topic(event)
+		if !ok {
+			continue
+		}
+
+		if payload == nil {
+			var err error
+			if payload, err = protojson.Marshal(event); err != nil {
+				log.Printf("Failed to marshal event for export: %v", err)
+				return
+			}
+		}
+
+		select {
+		case s.queue <- message{topic: topic, payload: payload}:
+		default:
+			log.Printf("Event export queue for %s full, dropping event", s.name)
+		}
+	}
+}
+
+// Queued returns the events waiting to be published, over all buses
+func (e *Exporter) Queued() int {
+	if e == nil {
+		return 0
+	}
+	queued := 0
+	for _, s := range e.sinks {
+		queued += len(s.queue)
+	}
+	return queued
+}
+
+// Close publishes the queued events and disconnects from the brokers
+func (e *Exporter) Close() error {
+	if e == nil {
+		return nil
+	}
+
+	e.mu.Lock()
+	if e.closed {
+		e.mu.Unlock()
+		return nil
+	}
+	e.closed = true
+	for _, s := range e.sinks {
+		close(s.queue)
+	}
+	e.mu.Unlock()
+	e.wg.Wait()
+
+	var firstErr error
+	for _, s := range e.sinks {
+		if err := s.publisher.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// run publishes queued events until the queue is closed
+func (s *sink) run() {
+	for msg := range s.queue {
+		if err := s.publisher.Publish(msg.topic, msg.payload); err != nil {
+			log.Printf("Failed to export event to %s topic %s: %v", s.name, msg.topic, err)
+		}
+	}
+}
+
+// topic returns the topic an event is sent to
+func (s *sink) topic(event *pb.Event) (string, bool) {
+	eventType := strings.ToLower(event.Type.String())
+	template, ok := s.topics[eventType]
+	if !ok {
+		if template, ok = s.topics["*"]; !ok {
+			return "", false
+		}
+	}
+
+	server := event.ServerName()
+	if escaper, ok := s.publisher.(Escaper); ok {
+		server = escaper.Escape(server)
+	}
+	return strings.NewReplacer("{type}", eventType, "{server}", server).Replace(template), true
+}
+
+// conn is a broker connection shared by the publishers: writes are
+// serialized and the connection is dropped on the first error, so the
+// next message reconnects
+type conn struct {
+	mu      sync.Mutex
+	netConn net.Conn
+}
+
+// write sends data on c, connecting first with dial if needed. A write on
+// an existing connection that fails is retried once on a new one, as the
+// broker may have closed it since the last message.
+func (c *conn) write(data []byte, dial func() (net.Conn, error)) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for attempt := 0; ; attempt++ {
+		fresh := c.netConn == nil
+		if fresh {
+			netConn, err := dial()
+			if err != nil {
+				return err
+			}
+			c.netConn = netConn
+		}
+
+		c.netConn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		_, err := c.netConn.Write(data)
+		if err == nil {
+			return nil
+		}
+
+		c.netConn.Close()
+		c.netConn = nil
+		if fresh || attempt > 0 {
+			return err
+		}
+	}
+}
+
+// drop closes netConn if it is still the current connection, e.g. when
+// its reader fails
+func (c *conn) drop(netConn net.Conn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.netConn == netConn {
+		c.netConn.Close()
+		c.netConn = nil
+	}
+}
+
+// reply sends data on netConn if it is still the current connection, e.g.
+// to answer a broker's ping. Returns false if it isn't.
+func (c *conn) reply(netConn net.Conn, data []byte) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.netConn != netConn {
+		return false
+	}
+	netConn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if _, err := netConn.Write(data); err != nil {
+		c.netConn.Close()
+		c.netConn = nil
+		return false
+	}
+	return true
+}
+
+// close closes the current connection
+func (c *conn) close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.netConn == nil {
+		return nil
+	}
+	err := c.netConn.Close()
+	c.netConn = nil
+	return err
+}
@@ -0,0 +1,361 @@
+package bus
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/internal/config"
+	pb "github.com/tartavull/mcp-manager/internal/grpc/pb"
+)
+
+// fakeBroker accepts connections on a local port, handing each to handle
+func fakeBroker(t *testing.T, handle func(net.Conn)) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { lis.Close() })
+
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				handle(conn)
+			}()
+		}
+	}()
+	return lis.Addr().String()
+}
+
+func statusEvent(name string) *pb.Event {
+	return &pb.Event{
+		Type: pb.EventType_SERVER_STATUS,
+		Payload: &pb.Event_ServerStatus{ServerStatus: &pb.ServerStatusEvent{
+			ServerName: name,
+			NewStatus:  pb.ServerStatus_RUNNING,
+		}},
+	}
+}
+
+func TestNew(t *testing.T) {
+	exporter, err := New(nil)
+	require.NoError(t, err)
+	assert.Nil(t, exporter)
+
+	// Nil exporters ignore events
+	exporter.Export(statusEvent("test"))
+	assert.NoError(t, exporter.Close())
+
+	tests := []struct {
+		cfg  config.EventExportConfig
+		want string
+	}{
+		{config.EventExportConfig{Type: "kafka", Topics: map[string]string{"*": "events"}}, "unknown type 'kafka'"},
+		{config.EventExportConfig{Type: "nats", Topics: map[string]string{"heartbeat": "beats"}}, "unknown event type 'heartbeat'"},
+		{config.EventExportConfig{Type: "nats", Topics: map[string]string{"server_status": ""}}, "empty topic"},
+		{config.EventExportConfig{Type: "nats"}, "no topics"},
+	}
+	for _, tt := range tests {
+		_, err := New([]config.EventExportConfig{tt.cfg})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), tt.want)
+	}
+}
+
+func TestHostPort(t *testing.T) {
+	assert.Equal(t, "localhost:4222", hostPort("", "4222"))
+	assert.Equal(t, "broker:4222", hostPort("broker", "4222"))
+	assert.Equal(t, "broker:5000", hostPort("nats://broker:5000", "4222"))
+	assert.Equal(t, "[::1]:1883", hostPort("::1", "1883"))
+}
+
+func TestSink_Topic(t *testing.T) {
+	s, err := newSink(config.EventExportConfig{
+		Type: "nats",
+		Topics: map[string]string{
+			"SERVER_STATUS": "mcp.{server}.status",
+			"*":             "mcp.{type}",
+		},
+	}, "")
+	require.NoError(t, err)
+
+	topic, ok := s.topic(statusEvent("github"))
+	assert.True(t, ok)
+	assert.Equal(t, "mcp.github.status", topic)
+
+	topic, ok = s.topic(&pb.Event{Type: pb.EventType_CONFIG_CHANGE})
+	assert.True(t, ok)
+	assert.Equal(t, "mcp.config_change", topic)
+
+	// Server names can't add tokens, wildcards or commands to subjects
+	topic, _ = s.topic(statusEvent("a.b *>\r\nPUB x 1"))
+	assert.Equal(t, "mcp.a_b_____PUB_x_1.status", topic)
+
+	// Without a catch-all, unmapped events aren't sent
+	s, err = newSink(config.EventExportConfig{Type: "mqtt", Topics: map[string]string{"failover": "mcp/failover", "server_status": "mcp/{server}/status"}}, "")
+	require.NoError(t, err)
+	_, ok = s.topic(&pb.Event{Type: pb.EventType_CONFIG_CHANGE})
+	assert.False(t, ok)
+
+	topic, _ = s.topic(statusEvent("a/b+#.c"))
+	assert.Equal(t, "mcp/a_b__.c/status", topic)
+
+	// Redis channels take any name
+	s, err = newSink(config.EventExportConfig{Type: "redis", Topics: map[string]string{"*": "mcp:{server}"}}, "")
+	require.NoError(t, err)
+	topic, _ = s.topic(statusEvent("a.b/c"))
+	assert.Equal(t, "mcp:a.b/c", topic)
+}
+
+func TestPublish_InvalidTopic(t *testing.T) {
+	assert.ErrorContains(t, NewNATS("localhost:1", "", "").Publish("mcp events", nil), "invalid NATS subject")
+	assert.ErrorContains(t, NewMQTT("localhost:1", "", "").Publish("mcp/+", nil), "invalid MQTT topic")
+	assert.ErrorContains(t, NewMQTT("localhost:1", "", "").Publish("", nil), "invalid MQTT topic")
+}
+
+func TestNATS_Publish(t *testing.T) {
+	received := make(chan string, 2)
+	address := fakeBroker(t, func(conn net.Conn) {
+		reader := bufio.NewReader(conn)
+		fmt.Fprint(conn, "INFO {\"server_id\":\"test\"}\r\n")
+
+		line, _ := reader.ReadString('\n')
+		var options map[string]any
+		json.Unmarshal([]byte(strings.TrimPrefix(strings.TrimSpace(line), "CONNECT ")), &options)
+		received <- fmt.Sprint(options["user"])
+
+		if line, _ := reader.ReadString('\n'); line == "PING\r\n" {
+			fmt.Fprint(conn, "PONG\r\n")
+		}
+
+		// The client answers pings
+		fmt.Fprint(conn, "PING\r\n")
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if line == "PONG\r\n" {
+				received <- "PONG"
+				continue
+			}
+
+			var subject string
+			var size int
+			fmt.Sscanf(line, "PUB %s %d", &subject, &size)
+			payload := make([]byte, size+2)
+			io.ReadFull(reader, payload)
+			received <- subject + " " + strings.TrimSpace(string(payload))
+		}
+	})
+
+	n := NewNATS(address, "alice", "secret")
+	defer n.Close()
+	require.NoError(t, n.Publish("mcp.events", []byte("hello")))
+
+	assert.Equal(t, "alice", <-received)
+	var messages []string
+	for len(messages) < 2 {
+		select {
+		case msg := <-received:
+			messages = append(messages, msg)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("got %v, want the message and a PONG", messages)
+		}
+	}
+	assert.ElementsMatch(t, []string{"PONG", "mcp.events hello"}, messages)
+}
+
+// readRedisCommand reads a RESP array of bulk strings
+func readRedisCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+
+	args := make([]string, count)
+	for i := range args {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+		arg := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, arg); err != nil {
+			return nil, err
+		}
+		args[i] = string(arg[:size])
+	}
+	return args, nil
+}
+
+func TestRedis_Publish(t *testing.T) {
+	received := make(chan []string, 10)
+	address := fakeBroker(t, func(conn net.Conn) {
+		reader := bufio.NewReader(conn)
+		for {
+			args, err := readRedisCommand(reader)
+			if err != nil {
+				return
+			}
+			received <- args
+			if args[0] == "AUTH" {
+				fmt.Fprint(conn, "+OK\r\n")
+			} else {
+				fmt.Fprint(conn, ":1\r\n")
+			}
+		}
+	})
+
+	r := NewRedis(address, "", "secret")
+	defer r.Close()
+	require.NoError(t, r.Publish("mcp:events", []byte("line 1\r\nline 2")))
+
+	assert.Equal(t, []string{"AUTH", "secret"}, <-received)
+	assert.Equal(t, []string{"PUBLISH", "mcp:events", "line 1\r\nline 2"}, <-received)
+}
+
+func TestMQTT_Publish(t *testing.T) {
+	received := make(chan []byte, 2)
+	address := fakeBroker(t, func(conn net.Conn) {
+		reader := bufio.NewReader(conn)
+		for {
+			header, err := reader.ReadByte()
+			if err != nil {
+				return
+			}
+			length, multiplier := 0, 1
+			for {
+				digit, _ := reader.ReadByte()
+				length += int(digit&0x7f) * multiplier
+				multiplier *= 128
+				if digit&0x80 == 0 {
+					break
+				}
+			}
+			body := make([]byte, length)
+			io.ReadFull(reader, body)
+			received <- append([]byte{header}, body...)
+
+			if header == mqttConnect {
+				conn.Write([]byte{mqttConnAck, 2, 0, 0})
+			}
+		}
+	})
+
+	m := NewMQTT(address, "bob", "")
+	defer m.Close()
+	payload := strings.Repeat("x", 200) // Needs a two byte remaining length
+	require.NoError(t, m.Publish("mcp/events", []byte(payload)))
+
+	connect := <-received
+	assert.Equal(t, byte(mqttConnect), connect[0])
+	assert.Equal(t, "MQTT", string(connect[3:7]))
+	assert.Equal(t, byte(0x82), connect[8], "clean session with a username")
+	assert.Contains(t, string(connect), "bob")
+
+	publish := <-received
+	assert.Equal(t, byte(mqttPublish), publish[0])
+	assert.Equal(t, "mcp/events", string(publish[3:13]))
+	assert.Equal(t, payload, string(publish[13:]))
+}
+
+func TestExporter_Export(t *testing.T) {
+	received := make(chan []string, 10)
+	address := fakeBroker(t, func(conn net.Conn) {
+		reader := bufio.NewReader(conn)
+		for {
+			args, err := readRedisCommand(reader)
+			if err != nil {
+				return
+			}
+			received <- args
+			fmt.Fprint(conn, ":1\r\n")
+		}
+	})
+
+	exporter, err := New([]config.EventExportConfig{{
+		Type:    "redis",
+		Address: address,
+		Topics:  map[string]string{"server_status": "mcp:{server}"},
+	}})
+	require.NoError(t, err)
+
+	exporter.Export(&pb.Event{Type: pb.EventType_HEARTBEAT})
+	exporter.Export(&pb.Event{Type: pb.EventType_CONFIG_CHANGE})
+	exporter.Export(statusEvent("github"))
+	require.NoError(t, exporter.Close())
+
+	// Closing publishes the queued events; unmapped ones were skipped
+	args := <-received
+	require.Len(t, args, 3)
+	assert.Equal(t, "mcp:github", args[1])
+
+	var event map[string]any
+	require.NoError(t, json.Unmarshal([]byte(args[2]), &event))
+	assert.Equal(t, "SERVER_STATUS", event["type"])
+	assert.Len(t, received, 0)
+
+	// Events after closing are ignored
+	exporter.Export(statusEvent("github"))
+}
+
+func TestPlugin_Publish(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are sh scripts")
+	}
+	dir := t.TempDir()
+	out := filepath.Join(dir, "events")
+	script := fmt.Sprintf(`#!/bin/sh
+req=$(cat)
+case "$req" in
+*'"describe"'*) echo '{"result":{"kinds":["notifier"]}}' ;;
+*) echo "$req" >> '%s'; echo '{}' ;;
+esac
+`, out)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pager"), []byte(script), 0755))
+
+	_, err := NewWithPlugins([]config.EventExportConfig{{Type: "plugin", Plugin: "missing"}}, dir)
+	assert.ErrorContains(t, err, "plugin missing not found")
+
+	// Plugins receive every event by default, under its type
+	exporter, err := NewWithPlugins([]config.EventExportConfig{{
+		Type:   "plugin",
+		Plugin: "pager",
+		Config: json.RawMessage(`{"channel":"ops"}`),
+	}}, dir)
+	require.NoError(t, err)
+	exporter.Export(statusEvent("github"))
+	require.NoError(t, exporter.Close())
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	var req struct {
+		Method string          `json:"method"`
+		Config json.RawMessage `json:"config"`
+		Params struct {
+			Topic string         `json:"topic"`
+			Event map[string]any `json:"event"`
+		} `json:"params"`
+	}
+	require.NoError(t, json.Unmarshal(data, &req))
+	assert.Equal(t, "notify.publish", req.Method)
+	assert.JSONEq(t, `{"channel":"ops"}`, string(req.Config))
+	assert.Equal(t, "server_status", req.Params.Topic)
+	assert.Equal(t, "SERVER_STATUS", req.Params.Event["type"])
+}
@@ -0,0 +1,163 @@
+package bus
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// mqttKeepAlive is the interval the broker expects a packet within;
+	// pings are sent at half of it
+	mqttKeepAlive = 60 * time.Second
+
+	mqttConnect  = 0x10
+	mqttConnAck  = 0x20
+	mqttPublish  = 0x30 // QoS 0, not retained
+	mqttPingReq  = 0xc0
+	mqttProtocol = 4 // MQTT 3.1.1
+)
+
+// MQTT publishes messages to MQTT topics with QoS 0
+type MQTT struct {
+	address  string
+	username string
+	password string
+	clientID string
+	conn     conn
+}
+
+// NewMQTT creates a publisher for the MQTT broker at address
+func NewMQTT(address, username, password string) *MQTT {
+	host, _ := os.Hostname()
+	return &MQTT{
+		address:  address,
+		username: username,
+		password: password,
+		clientID: fmt.Sprintf("mcp-manager-%s-%d", host, os.Getpid()),
+	}
+}
+
+// mqttEscaper replaces the characters MQTT topics reserve: "/" separates
+// levels and "+" and "#" are wildcards, which can't be published to
+var mqttEscaper = strings.NewReplacer("/", "_", "+", "_", "#", "_", "\x00", "_")
+
+// Escape makes name a single level of a topic
+func (m *MQTT) Escape(name string) string {
+	return mqttEscaper.Replace(name)
+}
+
+// Publish sends payload to a topic
+func (m *MQTT) Publish(topic string, payload []byte) error {
+	if topic == "" || strings.ContainsAny(topic, "+#\x00") {
+		return fmt.Errorf("invalid MQTT topic %q", topic)
+	}
+	body := mqttString(nil, topic)
+	body = append(body, payload...)
+	return m.conn.write(mqttPacket(mqttPublish, body), m.dial)
+}
+
+// Close disconnects from the broker
+func (m *MQTT) Close() error {
+	return m.conn.close()
+}
+
+// dial connects with a clean session and waits for the broker to accept
+func (m *MQTT) dial() (net.Conn, error) {
+	netConn, err := net.DialTimeout("tcp", m.address, dialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MQTT broker: %w", err)
+	}
+	netConn.SetDeadline(time.Now().Add(dialTimeout))
+
+	fail := func(err error) (net.Conn, error) {
+		netConn.Close()
+		return nil, fmt.Errorf("failed to connect to MQTT broker: %w", err)
+	}
+
+	flags := byte(0x02) // Clean session
+	if m.username != "" {
+		flags |= 0x80
+		if m.password != "" {
+			flags |= 0x40
+		}
+	}
+
+	body := mqttString(nil, "MQTT")
+	body = append(body, mqttProtocol, flags)
+	body = binary.BigEndian.AppendUint16(body, uint16(mqttKeepAlive/time.Second))
+	body = mqttString(body, m.clientID)
+	if m.username != "" {
+		body = mqttString(body, m.username)
+		if m.password != "" {
+			body = mqttString(body, m.password)
+		}
+	}
+	if _, err := netConn.Write(mqttPacket(mqttConnect, body)); err != nil {
+		return fail(err)
+	}
+
+	reader := bufio.NewReader(netConn)
+	ack := make([]byte, 4)
+	if _, err := io.ReadFull(reader, ack); err != nil {
+		return fail(err)
+	}
+	if ack[0] != mqttConnAck {
+		return fail(fmt.Errorf("unexpected packet type 0x%x", ack[0]))
+	}
+	if ack[3] != 0 {
+		return fail(fmt.Errorf("broker refused connection with code %d", ack[3]))
+	}
+
+	netConn.SetDeadline(time.Time{})
+	go m.read(netConn, reader)
+	go m.keepAlive(netConn)
+	return netConn, nil
+}
+
+// read drains the broker's ping responses until the connection fails
+func (m *MQTT) read(netConn net.Conn, reader *bufio.Reader) {
+	defer m.conn.drop(netConn)
+	io.Copy(io.Discard, reader)
+}
+
+// keepAlive pings the broker until the connection is replaced or closed
+func (m *MQTT) keepAlive(netConn net.Conn) {
+	ticker := time.NewTicker(mqttKeepAlive / 2)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if !m.conn.reply(netConn, []byte{mqttPingReq, 0}) {
+			return
+		}
+	}
+}
+
+// mqttPacket frames a packet body with its type and remaining length
+func mqttPacket(packetType byte, body []byte) []byte {
+	packet := []byte{packetType}
+	length := len(body)
+	for {
+		digit := byte(length % 128)
+		length /= 128
+		if length > 0 {
+			digit |= 0x80
+		}
+		packet = append(packet, digit)
+		if length == 0 {
+			break
+		}
+	}
+	return append(packet, body...)
+}
+
+// mqttString appends a length-prefixed UTF-8 string
+func mqttString(b []byte, s string) []byte {
+	b = binary.BigEndian.AppendUint16(b, uint16(len(s)))
+	return append(b, s...)
+}
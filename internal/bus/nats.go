@@ -0,0 +1,131 @@
+package bus
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"time"
+)
+
+// NATS publishes messages to NATS subjects
+type NATS struct {
+	address  string
+	username string
+	password string
+	conn     conn
+}
+
+// NewNATS creates a publisher for the NATS server at address
+func NewNATS(address, username, password string) *NATS {
+	return &NATS{address: address, username: username, password: password}
+}
+
+// natsEscaper replaces the characters NATS subjects reserve: whitespace
+// ends the subject, "." separates tokens and "*" and ">" are wildcards
+var natsEscaper = strings.NewReplacer(" ", "_", "\t", "_", "\r", "_", "\n", "_", ".", "_", "*", "_", ">", "_")
+
+// Escape makes name a single token of a subject
+func (n *NATS) Escape(name string) string {
+	return natsEscaper.Replace(name)
+}
+
+// Publish sends payload to a subject
+func (n *NATS) Publish(subject string, payload []byte) error {
+	if subject == "" || strings.ContainsAny(subject, " \t\r\n") {
+		return fmt.Errorf("invalid NATS subject %q", subject)
+	}
+	msg := fmt.Appendf(nil, "PUB %s %d\r\n", subject, len(payload))
+	msg = append(append(msg, payload...), "\r\n"...)
+	return n.conn.write(msg, n.dial)
+}
+
+// Close disconnects from the server
+func (n *NATS) Close() error {
+	return n.conn.close()
+}
+
+// dial connects and completes the handshake: the server greets with INFO,
+// and a PING after CONNECT is answered with PONG once it is accepted
+func (n *NATS) dial() (net.Conn, error) {
+	netConn, err := net.DialTimeout("tcp", n.address, dialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+	netConn.SetDeadline(time.Now().Add(dialTimeout))
+	reader := bufio.NewReader(netConn)
+
+	fail := func(err error) (net.Conn, error) {
+		netConn.Close()
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return fail(err)
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		return fail(fmt.Errorf("unexpected greeting %q", strings.TrimSpace(line)))
+	}
+
+	options := map[string]any{
+		"verbose":  false,
+		"pedantic": false,
+		"name":     "mcp-manager",
+		"lang":     "go",
+		"version":  "1.0.0",
+	}
+	if n.username != "" {
+		options["user"] = n.username
+		options["pass"] = n.password
+	}
+	connect, err := json.Marshal(options)
+	if err != nil {
+		return fail(err)
+	}
+	if _, err := fmt.Fprintf(netConn, "CONNECT %s\r\nPING\r\n", connect); err != nil {
+		return fail(err)
+	}
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return fail(err)
+		}
+		line = strings.TrimSpace(line)
+		if line == "PONG" {
+			break
+		}
+		if strings.HasPrefix(line, "-ERR") {
+			return fail(fmt.Errorf("server refused connection: %s", line))
+		}
+	}
+
+	netConn.SetDeadline(time.Time{})
+	go n.read(netConn, reader)
+	return netConn, nil
+}
+
+// read answers the server's pings and logs its errors until the
+// connection fails
+func (n *NATS) read(netConn net.Conn, reader *bufio.Reader) {
+	defer n.conn.drop(netConn)
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "PING":
+			if !n.conn.reply(netConn, []byte("PONG\r\n")) {
+				return
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			log.Printf("NATS error: %s", line)
+		}
+	}
+}
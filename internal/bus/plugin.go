@@ -0,0 +1,29 @@
+package bus
+
+import (
+	"encoding/json"
+
+	"github.com/tartavull/mcp-manager/internal/plugin"
+)
+
+// Plugin publishes messages through a notifier plugin, e.g. to a chat or
+// paging service
+type Plugin struct {
+	plugin *plugin.Plugin
+	config json.RawMessage
+}
+
+// NewPlugin creates a publisher calling p with config
+func NewPlugin(p *plugin.Plugin, config json.RawMessage) *Plugin {
+	return &Plugin{plugin: p, config: config}
+}
+
+// Publish sends payload, a protojson event, to a topic
+func (p *Plugin) Publish(topic string, payload []byte) error {
+	return p.plugin.Call(plugin.MethodNotify, p.config, plugin.NotifyParams{Topic: topic, Event: payload}, nil)
+}
+
+// Close does nothing, as each event runs the plugin anew
+func (p *Plugin) Close() error {
+	return nil
+}
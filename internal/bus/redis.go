@@ -0,0 +1,96 @@
+package bus
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Redis publishes messages to Redis pub/sub channels
+type Redis struct {
+	address  string
+	username string
+	password string
+	conn     conn
+}
+
+// NewRedis creates a publisher for the Redis server at address
+func NewRedis(address, username, password string) *Redis {
+	return &Redis{address: address, username: username, password: password}
+}
+
+// Publish sends payload to a channel
+func (r *Redis) Publish(channel string, payload []byte) error {
+	return r.conn.write(redisCommand("PUBLISH", channel, string(payload)), r.dial)
+}
+
+// Close disconnects from the server
+func (r *Redis) Close() error {
+	return r.conn.close()
+}
+
+// dial connects and authenticates if credentials are set
+func (r *Redis) dial() (net.Conn, error) {
+	netConn, err := net.DialTimeout("tcp", r.address, dialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+	reader := bufio.NewReader(netConn)
+
+	if r.password != "" {
+		args := []string{"AUTH", r.password}
+		if r.username != "" {
+			args = []string{"AUTH", r.username, r.password}
+		}
+
+		netConn.SetDeadline(time.Now().Add(dialTimeout))
+		if _, err := netConn.Write(redisCommand(args...)); err != nil {
+			netConn.Close()
+			return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+		}
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			netConn.Close()
+			return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+		}
+		if !strings.HasPrefix(line, "+OK") {
+			netConn.Close()
+			return nil, fmt.Errorf("failed to authenticate with Redis: %s", strings.TrimSpace(line))
+		}
+		netConn.SetDeadline(time.Time{})
+	}
+
+	go r.read(netConn, reader)
+	return netConn, nil
+}
+
+// read discards the replies to PUBLISH, logging errors, until the
+// connection fails
+func (r *Redis) read(netConn net.Conn, reader *bufio.Reader) {
+	defer r.conn.drop(netConn)
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		if strings.HasPrefix(line, "-") {
+			log.Printf("Redis error: %s", strings.TrimSpace(line[1:]))
+		}
+	}
+}
+
+// redisCommand encodes a command as a RESP array of bulk strings
+func redisCommand(args ...string) []byte {
+	cmd := []byte("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		cmd = append(cmd, "$"+strconv.Itoa(len(arg))+"\r\n"...)
+		cmd = append(cmd, arg...)
+		cmd = append(cmd, "\r\n"...)
+	}
+	return cmd
+}
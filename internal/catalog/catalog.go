@@ -0,0 +1,167 @@
+package catalog
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Param is a value substituted into an entry's command, e.g. a directory
+// or connection string
+type Param struct {
+	Name    string `json:"name"`
+	Prompt  string `json:"prompt"`
+	Default string `json:"default,omitempty"`
+}
+
+// Secret is an environment variable holding a credential the server needs
+type Secret struct {
+	Env    string `json:"env"`
+	Prompt string `json:"prompt"`
+}
+
+// Entry describes a well-known MCP server that can be installed
+type Entry struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Command may reference params as {name}
+	Command     string   `json:"command"`
+	Params      []Param  `json:"params,omitempty"`
+	Secrets     []Secret `json:"secrets,omitempty"`
+	Recommended bool     `json:"recommended,omitempty"`
+
+	// Source is the catalog file the entry was loaded from, empty for
+	// the built-in catalog
+	Source string `json:"-"`
+	// Signed is true if the entry is built in or its catalog is signed by
+	// a trusted key
+	Signed bool `json:"-"`
+}
+
+// Entries is the built-in catalog of MCP servers
+var Entries = []Entry{
+	{
+		Name:        "playwright",
+		Description: "Browser automation, screenshots, web interaction",
+		Command:     "npx @playwright/mcp@latest",
+		Recommended: true,
+	},
+	{
+		Name:        "filesystem",
+		Description: "File system operations (read/write/create/delete)",
+		Command:     "npx @modelcontextprotocol/server-filesystem@latest {root}",
+		Params: []Param{
+			{Name: "root", Prompt: "Directory the filesystem server may access", Default: "/tmp"},
+		},
+		Recommended: true,
+	},
+	{
+		Name:        "sequential-thinking",
+		Description: "Structured problem-solving with reasoning paths",
+		Command:     "npx @modelcontextprotocol/server-sequential-thinking@latest",
+		Recommended: true,
+	},
+	{
+		Name:        "memory",
+		Description: "Knowledge graph-based persistent memory system",
+		Command:     "npx @modelcontextprotocol/server-memory@latest",
+	},
+	{
+		Name:        "github",
+		Description: "GitHub repository and issue management",
+		Command:     "npx @modelcontextprotocol/server-github@latest",
+		Secrets: []Secret{
+			{Env: "GITHUB_PERSONAL_ACCESS_TOKEN", Prompt: "GitHub personal access token"},
+		},
+	},
+	{
+		Name:        "postgres",
+		Description: "PostgreSQL database operations and queries",
+		Command:     "npx @modelcontextprotocol/server-postgres@latest {url}",
+		Params: []Param{
+			{Name: "url", Prompt: "PostgreSQL connection URL", Default: "postgresql://localhost/mydb"},
+		},
+	},
+	{
+		Name:        "brave-search",
+		Description: "Web and local search using Brave's Search API",
+		Command:     "npx @modelcontextprotocol/server-brave-search@latest",
+		Secrets: []Secret{
+			{Env: "BRAVE_API_KEY", Prompt: "Brave Search API key"},
+		},
+	},
+	{
+		Name:        "google-maps",
+		Description: "Location services, directions, and place details",
+		Command:     "npx @modelcontextprotocol/server-google-maps@latest",
+		Secrets: []Secret{
+			{Env: "GOOGLE_MAPS_API_KEY", Prompt: "Google Maps API key"},
+		},
+	},
+	{
+		Name:        "slack",
+		Description: "Channel management and messaging capabilities",
+		Command:     "npx @modelcontextprotocol/server-slack@latest",
+		Secrets: []Secret{
+			{Env: "SLACK_BOT_TOKEN", Prompt: "Slack bot token (xoxb-...)"},
+			{Env: "SLACK_TEAM_ID", Prompt: "Slack team ID"},
+		},
+	},
+	{
+		Name:        "everything",
+		Description: "Test server with prompts, resources, and tools",
+		Command:     "npx @modelcontextprotocol/server-everything@latest",
+	},
+}
+
+func init() {
+	// The built-in catalog ships with the release, whose checksum
+	// self-update verifies
+	for i := range Entries {
+		Entries[i].Signed = true
+	}
+}
+
+// Find returns the catalog entry with the given name
+func Find(name string) (*Entry, error) {
+	return FindIn(Entries, name)
+}
+
+// FindIn returns the entry with the given name among entries
+func FindIn(entries []Entry, name string) (*Entry, error) {
+	for i := range entries {
+		if entries[i].Name == name {
+			return &entries[i], nil
+		}
+	}
+	return nil, fmt.Errorf("catalog entry '%s' not found", name)
+}
+
+// Recommended returns the names of the recommended entries
+func Recommended() []string {
+	return RecommendedIn(Entries)
+}
+
+// RecommendedIn returns the names of the recommended entries among entries
+func RecommendedIn(entries []Entry) []string {
+	var names []string
+	for _, entry := range entries {
+		if entry.Recommended {
+			names = append(names, entry.Name)
+		}
+	}
+	return names
+}
+
+// Render returns the entry command with params substituted, using
+// defaults for params missing from values
+func (e *Entry) Render(values map[string]string) string {
+	command := e.Command
+	for _, param := range e.Params {
+		value, ok := values[param.Name]
+		if !ok || value == "" {
+			value = param.Default
+		}
+		command = strings.ReplaceAll(command, "{"+param.Name+"}", value)
+	}
+	return command
+}
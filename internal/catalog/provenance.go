@@ -0,0 +1,297 @@
+package catalog
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+)
+
+// SignatureSuffix is appended to the name of a catalog or config to name
+// its signature
+const SignatureSuffix = ".sig"
+
+// ErrUnsigned is returned for catalogs and configs without a signature by
+// a trusted key
+var ErrUnsigned = errors.New("not signed by a trusted key")
+
+// Policy decides which catalogs, configs and commands are accepted. The
+// zero Policy accepts anything, warning about what it can't verify.
+type Policy struct {
+	Keys          []ed25519.PublicKey
+	RequireSigned bool
+	BlockUnpinned bool
+}
+
+// NewPolicy creates the policy set in mcp.json, which may be nil
+func NewPolicy(cfg *config.ProvenanceConfig) (*Policy, error) {
+	p := &Policy{}
+	if cfg == nil {
+		return p, nil
+	}
+	for _, encoded := range cfg.TrustedKeys {
+		key, err := ParsePublicKey(encoded)
+		if err != nil {
+			return nil, err
+		}
+		p.Keys = append(p.Keys, key)
+	}
+	p.RequireSigned, p.BlockUnpinned = cfg.RequireSigned, cfg.BlockUnpinned
+	if p.RequireSigned && len(p.Keys) == 0 {
+		return nil, fmt.Errorf("provenance requires signatures but trusts no keys")
+	}
+	return p, nil
+}
+
+// Verify checks the signature of data, returning the fingerprint of the
+// trusted key that made it. signature is the content of the .sig file,
+// nil if there is none.
+func (p *Policy) Verify(data, signature []byte) (string, error) {
+	if signature == nil {
+		return "", ErrUnsigned
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return "", fmt.Errorf("invalid signature")
+	}
+	if p != nil {
+		for _, key := range p.Keys {
+			if ed25519.Verify(key, data, sig) {
+				return Fingerprint(key), nil
+			}
+		}
+	}
+	return "", ErrUnsigned
+}
+
+// Admit returns an error if the policy refuses content from source,
+// whose signature check failed with err. Content it accepts anyway is
+// reported through warn.
+func (p *Policy) Admit(source string, err error, warn func(string)) error {
+	if err == nil {
+		return nil
+	}
+	if p != nil && p.RequireSigned {
+		return fmt.Errorf("%s: %w", source, err)
+	}
+	warn(fmt.Sprintf("%s: %v, its origin can't be verified", source, err))
+	return nil
+}
+
+// CheckCommand returns an error if the policy refuses a server command,
+// reporting the packages it fetches without a pinned version through warn
+func (p *Policy) CheckCommand(command string, warn func(string)) error {
+	unpinned := Unpinned(command)
+	if len(unpinned) == 0 {
+		return nil
+	}
+	if p != nil && p.BlockUnpinned {
+		return fmt.Errorf("command fetches unpinned %s", strings.Join(unpinned, ", "))
+	}
+	for _, ref := range unpinned {
+		warn(fmt.Sprintf("%s isn't pinned to a version, so each start may run different code", ref))
+	}
+	return nil
+}
+
+// VerifyChecksum checks data against a hex SHA-256 checksum
+func VerifyChecksum(data []byte, checksum string) error {
+	sum := sha256.Sum256(data)
+	if actual := hex.EncodeToString(sum[:]); !strings.EqualFold(actual, strings.TrimSpace(checksum)) {
+		return fmt.Errorf("checksum mismatch: expected %s, got %s", checksum, actual)
+	}
+	return nil
+}
+
+// Load parses a catalog file, a JSON object listing entries, checking
+// its signature against the policy. Entries of unsigned catalogs the
+// policy accepts are marked as such.
+func Load(source string, data, signature []byte, policy *Policy, warn func(string)) ([]Entry, error) {
+	_, verifyErr := policy.Verify(data, signature)
+	if err := policy.Admit(source, verifyErr, warn); err != nil {
+		return nil, err
+	}
+
+	var file struct {
+		Entries []Entry `json:"entries"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid catalog %s: %w", source, err)
+	}
+	for i := range file.Entries {
+		entry := &file.Entries[i]
+		if entry.Name == "" || entry.Command == "" {
+			return nil, fmt.Errorf("invalid catalog %s: entry %d needs a name and a command", source, i+1)
+		}
+		entry.Source, entry.Signed = source, verifyErr == nil
+	}
+	return file.Entries, nil
+}
+
+// Merge returns base with the entries of extra appended, replacing the
+// entries of base with the same name
+func Merge(base, extra []Entry) []Entry {
+	merged := append([]Entry(nil), base...)
+	for _, entry := range extra {
+		if existing, err := FindIn(merged, entry.Name); err == nil {
+			*existing = entry
+			continue
+		}
+		merged = append(merged, entry)
+	}
+	return merged
+}
+
+// GenerateKey returns a new base64 key pair signing catalogs and configs
+func GenerateKey() (publicKey, privateKey string, err error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	return base64.StdEncoding.EncodeToString(public), base64.StdEncoding.EncodeToString(private.Seed()), nil
+}
+
+// Sign returns the content of the .sig file of data, signed with a base64
+// private key from GenerateKey
+func Sign(data []byte, privateKey string) ([]byte, error) {
+	seed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(privateKey))
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("invalid private key")
+	}
+	sig := ed25519.Sign(ed25519.NewKeyFromSeed(seed), data)
+	return []byte(base64.StdEncoding.EncodeToString(sig) + "\n"), nil
+}
+
+// ParsePublicKey parses a base64 Ed25519 public key
+func ParsePublicKey(encoded string) (ed25519.PublicKey, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid trusted key '%s'", encoded)
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// Fingerprint returns a short identifier of a public key
+func Fingerprint(key ed25519.PublicKey) string {
+	sum := sha256.Sum256(key)
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:])[:16]
+}
+
+var (
+	// exactVersion matches pinned npm versions, e.g. 1.2.3 or 1.0.0-rc.1
+	exactVersion = regexp.MustCompile(`^v?\d+\.\d+\.\d+([-+][0-9A-Za-z.-]+)?$`)
+
+	// pythonPin matches pinned Python requirements, e.g. mcp-server==1.2
+	pythonPin = regexp.MustCompile(`^[A-Za-z0-9._\[\],-]+(==|@)[0-9][0-9A-Za-z.+!-]*$`)
+)
+
+// dockerValueFlags are docker run flags taking a separate value
+var dockerValueFlags = map[string]bool{
+	"-e": true, "--env": true, "--env-file": true, "-v": true, "--volume": true,
+	"--mount": true, "-p": true, "--publish": true, "--name": true, "--network": true,
+	"-w": true, "--workdir": true, "--entrypoint": true, "-u": true, "--user": true,
+	"--platform": true, "-l": true, "--label": true, "-m": true, "--memory": true,
+}
+
+// Unpinned returns the packages a command fetches without pinning them
+// to a version: npx, bunx, pnpm dlx and npm exec packages without an
+// exact version, uvx and pipx run packages without ==version, and docker
+// images without a digest
+func Unpinned(command string) []string {
+	// Quotes are dropped, so commands run through sh -c are checked too
+	fields := strings.Fields(command)
+	for i, field := range fields {
+		fields[i] = strings.Trim(field, `'"`)
+	}
+	var unpinned []string
+	for i := 0; i < len(fields); i++ {
+		launcher := filepath.Base(fields[i])
+		rest := fields[i+1:]
+		var ref string
+		var pinned func(string) bool
+		switch {
+		case launcher == "npx" || launcher == "bunx":
+			ref, pinned = packageArg(rest, "-p", "--package"), npmPinned
+		case (launcher == "pnpm" || launcher == "yarn") && len(rest) > 0 && rest[0] == "dlx",
+			launcher == "npm" && len(rest) > 0 && rest[0] == "exec":
+			ref, pinned = packageArg(rest[1:], "-p", "--package"), npmPinned
+		case launcher == "uvx":
+			ref, pinned = packageArg(rest, "--from"), pythonPin.MatchString
+		case launcher == "pipx" && len(rest) > 0 && rest[0] == "run":
+			ref, pinned = packageArg(rest[1:], "--spec"), pythonPin.MatchString
+		case (launcher == "docker" || launcher == "podman") && len(rest) > 0 && rest[0] == "run":
+			ref, pinned = dockerImage(rest[1:]), dockerPinned
+		default:
+			continue
+		}
+		if ref != "" && !local(ref) && !pinned(ref) {
+			unpinned = append(unpinned, ref)
+		}
+	}
+	return unpinned
+}
+
+// packageArg returns the package a launcher runs: the value of one of
+// the flags naming it, or else its first argument
+func packageArg(args []string, flags ...string) string {
+	for i, arg := range args {
+		for _, flag := range flags {
+			if arg == flag && i+1 < len(args) {
+				return args[i+1]
+			}
+			if value, ok := strings.CutPrefix(arg, flag+"="); ok {
+				return value
+			}
+		}
+	}
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			return arg
+		}
+	}
+	return ""
+}
+
+// dockerImage returns the image of docker run arguments
+func dockerImage(args []string) string {
+	for i := 0; i < len(args); i++ {
+		if dockerValueFlags[args[i]] {
+			i++
+			continue
+		}
+		if !strings.HasPrefix(args[i], "-") {
+			return args[i]
+		}
+	}
+	return ""
+}
+
+// npmPinned returns true if an npm package has an exact version, e.g.
+// @scope/name@1.2.3
+func npmPinned(ref string) bool {
+	at := strings.LastIndex(ref, "@")
+	if at <= 0 {
+		return false
+	}
+	return exactVersion.MatchString(ref[at+1:])
+}
+
+// dockerPinned returns true if an image is pinned by digest
+func dockerPinned(ref string) bool {
+	return strings.Contains(ref, "@sha256:")
+}
+
+// local returns true if a package is a local path, which isn't fetched
+func local(ref string) bool {
+	return strings.HasPrefix(ref, ".") || strings.HasPrefix(ref, "/") || strings.HasPrefix(ref, "~") ||
+		strings.HasPrefix(ref, "file:")
+}
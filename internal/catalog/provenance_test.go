@@ -0,0 +1,164 @@
+package catalog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/internal/config"
+)
+
+func TestUnpinned(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []string
+	}{
+		{"npx @playwright/mcp@latest", []string{"@playwright/mcp@latest"}},
+		{"npx -y @modelcontextprotocol/server-filesystem /tmp", []string{"@modelcontextprotocol/server-filesystem"}},
+		{"npx -y @modelcontextprotocol/server-filesystem@2025.7.1 /tmp", nil},
+		{"npx mcp-server@^1.2.0", []string{"mcp-server@^1.2.0"}},
+		{"npx mcp-server@1.2.0-rc.1", nil},
+		{"npx --package=mcp-server@1.2.3 mcp-server", nil},
+		{"pnpm dlx mcp-server", []string{"mcp-server"}},
+		{"uvx mcp-server-fetch", []string{"mcp-server-fetch"}},
+		{"uvx mcp-server-fetch==0.6.2", nil},
+		{"uvx --from mcp-server-git==1.0.0 mcp-server-git --repo .", nil},
+		{"pipx run mcp-server-time", []string{"mcp-server-time"}},
+		{"docker run -i --rm -e TOKEN ghcr.io/github/github-mcp-server", []string{"ghcr.io/github/github-mcp-server"}},
+		{"docker run -i --rm ghcr.io/github/github-mcp-server@sha256:abc", nil},
+		{"npx ./local-server", nil},
+		{"/usr/local/bin/mcp-server --port 3000", nil},
+		{"sh -c 'npx a@latest && uvx b'", []string{"a@latest", "b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			assert.Equal(t, tt.want, Unpinned(tt.command))
+		})
+	}
+}
+
+func TestPolicy_Verify(t *testing.T) {
+	public, private, err := GenerateKey()
+	require.NoError(t, err)
+	other, _, err := GenerateKey()
+	require.NoError(t, err)
+
+	data := []byte(`{"entries": []}`)
+	signature, err := Sign(data, private)
+	require.NoError(t, err)
+
+	policy, err := NewPolicy(&config.ProvenanceConfig{TrustedKeys: []string{other, public}})
+	require.NoError(t, err)
+	key, err := policy.Verify(data, signature)
+	require.NoError(t, err)
+	parsed, err := ParsePublicKey(public)
+	require.NoError(t, err)
+	assert.Equal(t, Fingerprint(parsed), key)
+
+	_, err = policy.Verify([]byte(`{"entries": [{}]}`), signature)
+	assert.ErrorIs(t, err, ErrUnsigned, "tampered data")
+	_, err = policy.Verify(data, nil)
+	assert.ErrorIs(t, err, ErrUnsigned, "no signature")
+	_, err = policy.Verify(data, []byte("not base64!"))
+	assert.ErrorContains(t, err, "invalid signature")
+
+	untrusting, err := NewPolicy(&config.ProvenanceConfig{TrustedKeys: []string{other}})
+	require.NoError(t, err)
+	_, err = untrusting.Verify(data, signature)
+	assert.ErrorIs(t, err, ErrUnsigned, "untrusted key")
+
+	_, err = NewPolicy(&config.ProvenanceConfig{TrustedKeys: []string{"abc"}})
+	assert.Error(t, err)
+	_, err = NewPolicy(&config.ProvenanceConfig{RequireSigned: true})
+	assert.ErrorContains(t, err, "trusts no keys")
+}
+
+func TestPolicy_Admit(t *testing.T) {
+	var warnings []string
+	warn := func(warning string) { warnings = append(warnings, warning) }
+
+	var lenient *Policy
+	assert.NoError(t, lenient.Admit("team.json", ErrUnsigned, warn))
+	assert.Len(t, warnings, 1)
+	assert.NoError(t, lenient.Admit("team.json", nil, warn))
+	assert.Len(t, warnings, 1)
+
+	strict := &Policy{RequireSigned: true}
+	assert.ErrorIs(t, strict.Admit("team.json", ErrUnsigned, warn), ErrUnsigned)
+	assert.Len(t, warnings, 1)
+}
+
+func TestPolicy_CheckCommand(t *testing.T) {
+	var warnings []string
+	warn := func(warning string) { warnings = append(warnings, warning) }
+
+	assert.NoError(t, (&Policy{}).CheckCommand("npx a@latest", warn))
+	assert.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "a@latest")
+
+	blocking := &Policy{BlockUnpinned: true}
+	assert.ErrorContains(t, blocking.CheckCommand("npx a@latest", warn), "unpinned a@latest")
+	assert.NoError(t, blocking.CheckCommand("npx a@1.0.0", warn))
+	assert.Len(t, warnings, 1)
+}
+
+func TestLoad(t *testing.T) {
+	public, private, err := GenerateKey()
+	require.NoError(t, err)
+	policy, err := NewPolicy(&config.ProvenanceConfig{TrustedKeys: []string{public}})
+	require.NoError(t, err)
+
+	data := []byte(`{"entries": [
+		{"name": "fetch", "description": "Web fetching", "command": "uvx mcp-server-fetch==0.6.2"},
+		{"name": "github", "command": "docker run -i ghcr.io/github/github-mcp-server@sha256:abc",
+		 "secrets": [{"env": "GITHUB_PERSONAL_ACCESS_TOKEN", "prompt": "GitHub token"}]}
+	]}`)
+	signature, err := Sign(data, private)
+	require.NoError(t, err)
+
+	entries, err := Load("team.json", data, signature, policy, func(string) { t.Fatal("unexpected warning") })
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.True(t, entries[0].Signed)
+	assert.Equal(t, "team.json", entries[0].Source)
+	assert.Equal(t, "GITHUB_PERSONAL_ACCESS_TOKEN", entries[1].Secrets[0].Env)
+
+	// Unsigned catalogs are refused when signatures are required
+	policy.RequireSigned = true
+	_, err = Load("team.json", data, nil, policy, nil)
+	assert.ErrorIs(t, err, ErrUnsigned)
+
+	// or marked as unsigned otherwise
+	policy.RequireSigned = false
+	entries, err = Load("team.json", data, nil, policy, func(string) {})
+	require.NoError(t, err)
+	assert.False(t, entries[0].Signed)
+
+	_, err = Load("team.json", []byte(`{"entries": [{"name": "x"}]}`), nil, nil, func(string) {})
+	assert.ErrorContains(t, err, "needs a name and a command")
+
+	// Loaded entries replace built-in ones with the same name
+	merged := Merge(Entries, entries)
+	assert.Len(t, merged, len(Entries)+1)
+	github, err := FindIn(merged, "github")
+	require.NoError(t, err)
+	assert.Equal(t, "team.json", github.Source)
+	builtIn, err := Find("github")
+	require.NoError(t, err)
+	assert.Empty(t, builtIn.Source, "the built-in catalog is unchanged")
+}
+
+func TestBuiltInEntriesAreSigned(t *testing.T) {
+	for _, entry := range Entries {
+		assert.True(t, entry.Signed, entry.Name)
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	data := []byte("catalog")
+	sum := sha256.Sum256(data)
+	assert.NoError(t, VerifyChecksum(data, hex.EncodeToString(sum[:])))
+	assert.ErrorContains(t, VerifyChecksum(data, "00"), "checksum mismatch")
+}
@@ -0,0 +1,409 @@
+package cluster
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+	mcpgrpc "github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/logs"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// PeerTTL is how long a registered daemon stays in the fleet without a
+// heartbeat. Static peers never expire.
+const PeerTTL = 30 * time.Second
+
+// Peer is a remote daemon whose servers are part of the fleet
+type Peer interface {
+	GetServers() (map[string]*server.Server, []string, error)
+	StartServer(name string) error
+	StopServer(name string) error
+	SetMaintenance(name string, enabled bool) error
+	Close() error
+}
+
+// peer tracks a daemon in the fleet
+type peer struct {
+	address  string
+	client   Peer // Nil until connected
+	static   bool
+	lastSeen time.Time
+}
+
+// Coordinator aggregates the servers of the local manager and of remote
+// daemons into one fleet. Servers are named "host/name" and carry their
+// host label, and operations are routed to the daemon that owns them.
+type Coordinator struct {
+	local mcpgrpc.ManagerInterface
+	host  string
+
+	mu    sync.Mutex
+	peers map[string]*peer
+
+	// Failover state, see failover.go
+	failover     map[string]*config.FailoverConfig
+	active       map[string]string   // Server name to the host running it
+	targets      map[string]*url.URL // Server name to the active proxy
+	failovers    chan mcpgrpc.Failover
+	gateways     []*http.Server
+	stopFailover chan struct{}
+
+	// dial connects to a daemon, replaceable for tests
+	dial func(address string) (Peer, error)
+}
+
+// NewCoordinator creates a coordinator that labels the local manager's
+// servers with host
+func NewCoordinator(local mcpgrpc.ManagerInterface, host string) *Coordinator {
+	return &Coordinator{
+		local:     local,
+		host:      host,
+		peers:     make(map[string]*peer),
+		active:    make(map[string]string),
+		targets:   make(map[string]*url.URL),
+		failovers: make(chan mcpgrpc.Failover, 100),
+		dial:      dialPeer,
+	}
+}
+
+// dialPeer connects to the daemon at address
+func dialPeer(address string) (Peer, error) {
+	client, err := mcpgrpc.NewClient(address)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// AddPeer adds a static peer, connected on first use
+func (c *Coordinator) AddPeer(host, address string) error {
+	if err := c.validateHost(host); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.peers[host] = &peer{address: address, static: true}
+	return nil
+}
+
+// Register adds a daemon to the fleet or refreshes its heartbeat
+func (c *Coordinator) Register(host, address string) error {
+	if err := c.validateHost(host); err != nil {
+		return err
+	}
+	if address == "" {
+		return fmt.Errorf("address is required")
+	}
+
+	c.mu.Lock()
+	if p, exists := c.peers[host]; exists && p.address == address {
+		p.lastSeen = time.Now()
+		c.mu.Unlock()
+		return nil
+	}
+	c.mu.Unlock()
+
+	// Dial outside the lock, connecting can take a while
+	client, err := c.dial(address)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", address, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if old, exists := c.peers[host]; exists && old.client != nil {
+		old.client.Close()
+	}
+	if _, exists := c.peers[host]; !exists {
+		log.Printf("Daemon %s joined the fleet from %s", host, address)
+	}
+	c.peers[host] = &peer{address: address, client: client, lastSeen: time.Now()}
+	return nil
+}
+
+// validateHost checks that a host label can be used in server names
+func (c *Coordinator) validateHost(host string) error {
+	switch {
+	case host == "":
+		return fmt.Errorf("host is required")
+	case strings.Contains(host, "/"):
+		return fmt.Errorf("host %q must not contain '/'", host)
+	case host == c.host:
+		return fmt.Errorf("host %q is the coordinator itself", host)
+	}
+	return nil
+}
+
+// Hosts returns the host labels of the fleet, the coordinator first
+func (c *Coordinator) Hosts() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pruneExpired()
+	hosts := make([]string, 0, len(c.peers))
+	for host := range c.peers {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	return append([]string{c.host}, hosts...)
+}
+
+// pruneExpired drops registered daemons that stopped sending heartbeats.
+// Must be called with mu held.
+func (c *Coordinator) pruneExpired() {
+	for host, p := range c.peers {
+		if !p.static && time.Since(p.lastSeen) > PeerTTL {
+			log.Printf("Daemon %s left the fleet: no heartbeat for %s", host, PeerTTL)
+			if p.client != nil {
+				p.client.Close()
+			}
+			delete(c.peers, host)
+		}
+	}
+}
+
+// peerClient returns the connected client of a peer
+func (c *Coordinator) peerClient(host string) (Peer, error) {
+	c.mu.Lock()
+	p, exists := c.peers[host]
+	if !exists {
+		c.mu.Unlock()
+		return nil, fmt.Errorf("host %s %w", host, server.ErrNotFound)
+	}
+	if p.client != nil {
+		client := p.client
+		c.mu.Unlock()
+		return client, nil
+	}
+	address := p.address
+	c.mu.Unlock()
+
+	client, err := c.dial(address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", host, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if p.client != nil {
+		// Connected concurrently, keep the first connection
+		client.Close()
+		return p.client, nil
+	}
+	p.client = client
+	return client, nil
+}
+
+// GetServers returns the servers of the whole fleet in host order.
+// Unreachable daemons are skipped so one host can't take down the view.
+func (c *Coordinator) GetServers() (map[string]*server.Server, []string, error) {
+	hosts := c.Hosts()
+
+	type hostServers struct {
+		servers map[string]*server.Server
+		order   []string
+		err     error
+	}
+	results := make([]hostServers, len(hosts))
+
+	var wg sync.WaitGroup
+	for i, host := range hosts {
+		wg.Add(1)
+		go func(i int, host string) {
+			defer wg.Done()
+			if host == c.host {
+				results[i].servers, results[i].order, results[i].err = c.local.GetServers()
+				return
+			}
+
+			client, err := c.peerClient(host)
+			if err != nil {
+				results[i].err = err
+				return
+			}
+			results[i].servers, results[i].order, results[i].err = client.GetServers()
+		}(i, host)
+	}
+	wg.Wait()
+
+	servers := make(map[string]*server.Server)
+	var order []string
+	for i, host := range hosts {
+		if results[i].err != nil {
+			if host == c.host {
+				return nil, nil, results[i].err
+			}
+			log.Printf("Skipping unreachable daemon %s: %v", host, results[i].err)
+			continue
+		}
+
+		for _, name := range results[i].order {
+			srv, exists := results[i].servers[name]
+			if !exists {
+				continue
+			}
+			// Copy so the local manager's servers keep their names
+			labeled := *srv
+			labeled.Name = host + "/" + name
+			labeled.Host = host
+			servers[labeled.Name] = &labeled
+			order = append(order, labeled.Name)
+		}
+	}
+
+	return servers, order, nil
+}
+
+// GetServerOrder returns the fleet's server names in host order
+func (c *Coordinator) GetServerOrder() ([]string, error) {
+	_, order, err := c.GetServers()
+	return order, err
+}
+
+// GetServer returns a server of the fleet by its "host/name" name
+func (c *Coordinator) GetServer(name string) (*server.Server, error) {
+	servers, _, err := c.GetServers()
+	if err != nil {
+		return nil, err
+	}
+
+	srv, exists := servers[name]
+	if !exists {
+		return nil, fmt.Errorf("server %s %w", name, server.ErrNotFound)
+	}
+	return srv, nil
+}
+
+// StartServer starts a server on the daemon that owns it
+func (c *Coordinator) StartServer(name string) error {
+	host, local, err := c.splitName(name)
+	if err != nil {
+		return err
+	}
+	if host == c.host {
+		return c.local.StartServer(local)
+	}
+
+	client, err := c.peerClient(host)
+	if err != nil {
+		return err
+	}
+	return client.StartServer(local)
+}
+
+// StopServer stops a server on the daemon that owns it
+func (c *Coordinator) StopServer(name string) error {
+	host, local, err := c.splitName(name)
+	if err != nil {
+		return err
+	}
+	if host == c.host {
+		return c.local.StopServer(local)
+	}
+
+	client, err := c.peerClient(host)
+	if err != nil {
+		return err
+	}
+	return client.StopServer(local)
+}
+
+// SetMaintenance toggles maintenance mode for a server on the daemon that
+// owns it, or for the coordinator when name is empty. The coordinator
+// doesn't fail servers over while it is in maintenance.
+func (c *Coordinator) SetMaintenance(name string, enabled bool) error {
+	if name == "" {
+		return c.local.SetMaintenance("", enabled)
+	}
+
+	host, local, err := c.splitName(name)
+	if err != nil {
+		return err
+	}
+	if host == c.host {
+		return c.local.SetMaintenance(local, enabled)
+	}
+
+	client, err := c.peerClient(host)
+	if err != nil {
+		return err
+	}
+	return client.SetMaintenance(local, enabled)
+}
+
+// Maintenance returns true if the coordinator is in maintenance mode
+func (c *Coordinator) Maintenance() (bool, error) {
+	return c.local.Maintenance()
+}
+
+// Logs returns the captured output of a local server. Logs of servers on
+// other daemons are streamed from those daemons.
+func (c *Coordinator) Logs(name string) (*logs.Buffer, error) {
+	host, local, err := c.splitName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	source, ok := c.local.(mcpgrpc.LogSource)
+	if host != c.host || !ok {
+		return nil, fmt.Errorf("logs of %s are only available from the daemon running it", name)
+	}
+	return source.Logs(local)
+}
+
+// splitName splits a fleet server name into its host and local name
+func (c *Coordinator) splitName(name string) (string, string, error) {
+	host, local, ok := strings.Cut(name, "/")
+	if !ok {
+		return "", "", fmt.Errorf("server %s has no host label", name)
+	}
+	return host, local, nil
+}
+
+// GetConfigPath returns the coordinator's own configuration path
+func (c *Coordinator) GetConfigPath() (string, error) {
+	return c.local.GetConfigPath()
+}
+
+// UpdateToolCounts refreshes the local tool counts. Remote daemons refresh
+// their own.
+func (c *Coordinator) UpdateToolCounts() error {
+	return c.local.UpdateToolCounts()
+}
+
+// StopAllServers stops the local servers. Remote daemons keep running.
+func (c *Coordinator) StopAllServers() {
+	c.local.StopAllServers()
+}
+
+// Stop disconnects from the fleet and stops the local manager
+func (c *Coordinator) Stop() error {
+	c.mu.Lock()
+	if c.stopFailover != nil {
+		close(c.stopFailover)
+		c.stopFailover = nil
+	}
+	for _, gateway := range c.gateways {
+		gateway.Close()
+	}
+	c.gateways = nil
+	for _, p := range c.peers {
+		if p.client != nil {
+			p.client.Close()
+			p.client = nil
+		}
+	}
+	c.mu.Unlock()
+
+	return c.local.Stop()
+}
@@ -0,0 +1,113 @@
+package cluster
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/apitest"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// newDaemon returns a fake daemon serving stopped servers, used as both
+// the local manager and a remote peer
+func newDaemon(names ...string) *apitest.Daemon {
+	d := apitest.NewDaemon()
+	for i, name := range names {
+		d.AddServer(name, "cmd", 4000+i, "")
+	}
+	return d
+}
+
+// serverOf returns a server of a fake daemon, even one not answering
+func serverOf(d *apitest.Daemon, name string) *server.Server {
+	srv, _ := d.Full.GetServer(name)
+	return srv
+}
+
+// newTestCoordinator creates a coordinator whose peers are fake daemons
+// keyed by address
+func newTestCoordinator(local *apitest.Daemon, remotes map[string]*apitest.Daemon) *Coordinator {
+	c := NewCoordinator(local, "hub")
+	c.dial = func(address string) (Peer, error) {
+		d, exists := remotes[address]
+		if !exists {
+			return nil, fmt.Errorf("no daemon at %s", address)
+		}
+		return d, nil
+	}
+	return c
+}
+
+func TestCoordinator_GetServers(t *testing.T) {
+	local := newDaemon("memory")
+	remote := newDaemon("github", "slack")
+	c := newTestCoordinator(local, map[string]*apitest.Daemon{"box:8080": remote})
+
+	require.NoError(t, c.AddPeer("box", "box:8080"))
+
+	servers, order, err := c.GetServers()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"hub/memory", "box/github", "box/slack"}, order)
+	assert.Equal(t, "box", servers["box/github"].Host)
+	assert.Equal(t, "hub", servers["hub/memory"].Host)
+
+	// The local manager's servers keep their own names
+	assert.Equal(t, "memory", serverOf(local, "memory").Name)
+}
+
+func TestCoordinator_SkipsUnreachablePeers(t *testing.T) {
+	local := newDaemon("memory")
+	remote := newDaemon("github")
+	remote.SetReachable(false)
+	c := newTestCoordinator(local, map[string]*apitest.Daemon{"box:8080": remote})
+
+	require.NoError(t, c.AddPeer("box", "box:8080"))
+	require.NoError(t, c.AddPeer("gone", "gone:8080"))
+
+	_, order, err := c.GetServers()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"hub/memory"}, order)
+}
+
+func TestCoordinator_RoutesOperations(t *testing.T) {
+	local := newDaemon("memory")
+	remote := newDaemon("github")
+	c := newTestCoordinator(local, map[string]*apitest.Daemon{"box:8080": remote})
+
+	require.NoError(t, c.Register("box", "box:8080"))
+
+	require.NoError(t, c.StartServer("box/github"))
+	assert.Equal(t, server.StatusRunning, serverOf(remote, "github").Status)
+	assert.Equal(t, server.StatusStopped, serverOf(local, "memory").Status)
+
+	require.NoError(t, c.StartServer("hub/memory"))
+	assert.Equal(t, server.StatusRunning, serverOf(local, "memory").Status)
+
+	require.NoError(t, c.StopServer("box/github"))
+	assert.Equal(t, server.StatusStopped, serverOf(remote, "github").Status)
+
+	assert.Error(t, c.StartServer("memory"))
+	assert.Error(t, c.StartServer("other/memory"))
+}
+
+func TestCoordinator_Register(t *testing.T) {
+	remote := newDaemon("github")
+	c := newTestCoordinator(newDaemon(), map[string]*apitest.Daemon{"box:8080": remote})
+
+	assert.Error(t, c.Register("", "box:8080"))
+	assert.Error(t, c.Register("a/b", "box:8080"))
+	assert.Error(t, c.Register("hub", "box:8080"))
+	assert.Error(t, c.Register("box", ""))
+	assert.Error(t, c.Register("box", "unknown:8080"))
+
+	require.NoError(t, c.Register("box", "box:8080"))
+	assert.Equal(t, []string{"hub", "box"}, c.Hosts())
+
+	// Daemons without a heartbeat leave the fleet
+	c.peers["box"].lastSeen = time.Now().Add(-2 * PeerTTL)
+	assert.Equal(t, []string{"hub"}, c.Hosts())
+	assert.True(t, remote.Closed())
+}
@@ -0,0 +1,252 @@
+package cluster
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+	mcpgrpc "github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// FailoverInterval is how often the coordinator checks failover groups
+const FailoverInterval = 5 * time.Second
+
+// EnableFailover keeps each server of groups running on one of its hosts,
+// and starts the gateways of groups with a port
+func (c *Coordinator) EnableFailover(groups map[string]*config.FailoverConfig) error {
+	for name, group := range groups {
+		if group == nil || len(group.Hosts) == 0 {
+			return fmt.Errorf("failover for %s requires at least one host", name)
+		}
+	}
+
+	stop := make(chan struct{})
+	c.mu.Lock()
+	c.failover = groups
+	c.stopFailover = stop
+	c.mu.Unlock()
+
+	for name, group := range groups {
+		if group.Port == 0 {
+			continue
+		}
+		if err := c.startGateway(name, group.Port); err != nil {
+			return err
+		}
+	}
+
+	go c.failoverLoop(stop)
+	return nil
+}
+
+// Failovers returns the failovers performed by the coordinator
+func (c *Coordinator) Failovers() <-chan mcpgrpc.Failover {
+	return c.failovers
+}
+
+// ActiveHost returns the host currently running a failover server
+func (c *Coordinator) ActiveHost(name string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.active[name]
+}
+
+// failoverLoop checks the failover groups until stop is closed
+func (c *Coordinator) failoverLoop(stop chan struct{}) {
+	c.checkFailover()
+
+	ticker := time.NewTicker(FailoverInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.checkFailover()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// checkFailover moves every failover server whose active host failed to
+// the next available host
+func (c *Coordinator) checkFailover() {
+	if maintenance, err := c.Maintenance(); err == nil && maintenance {
+		return
+	}
+
+	servers, _, err := c.GetServers()
+	if err != nil {
+		log.Printf("Failed to check failover: %v", err)
+		return
+	}
+
+	c.mu.Lock()
+	names := make([]string, 0, len(c.failover))
+	for name := range c.failover {
+		names = append(names, name)
+	}
+	c.mu.Unlock()
+	sort.Strings(names)
+
+	for _, name := range names {
+		c.mu.Lock()
+		group := c.failover[name]
+		active := c.active[name]
+		c.mu.Unlock()
+
+		// Servers being worked on stay where they are
+		current := servers[active+"/"+name]
+		if active != "" && (serverAvailable(current) || current != nil && current.Maintenance) {
+			continue
+		}
+
+		next := c.pickHost(name, group.Hosts, active, servers)
+
+		// Stop a failed instance still running so only one host serves
+		if active != "" && current != nil && current.IsRunning() && next != "" {
+			if err := c.StopServer(active + "/" + name); err != nil {
+				log.Printf("Failed to stop %s on %s: %v", name, active, err)
+			}
+		}
+
+		switch {
+		case next == "" && active != "":
+			log.Printf("No host available to fail over %s from %s", name, active)
+		case next != "" && active == "":
+			log.Printf("Running %s on %s", name, next)
+		case next != "":
+			reason := failureReason(current)
+			log.Printf("Failing over %s from %s to %s: %s", name, active, next, reason)
+			select {
+			case c.failovers <- mcpgrpc.Failover{Server: name, From: active, To: next, Reason: reason}:
+			default:
+				// Nobody is listening
+			}
+		}
+
+		c.setActive(name, next, servers[next+"/"+name])
+	}
+}
+
+// pickHost returns the host to run a failover server on: one already
+// running it, or else the first host in order that starts it
+func (c *Coordinator) pickHost(name string, hosts []string, failed string, servers map[string]*server.Server) string {
+	for _, host := range hosts {
+		if host != failed && serverAvailable(servers[host+"/"+name]) {
+			return host
+		}
+	}
+
+	for _, host := range hosts {
+		srv := servers[host+"/"+name]
+		// Skip unreachable hosts, hosts without the server, failed instances
+		// and instances in maintenance
+		if host == failed || srv == nil || srv.IsRunning() || srv.Maintenance {
+			continue
+		}
+		if err := c.StartServer(host + "/" + name); err != nil {
+			log.Printf("Failed to start %s on %s: %v", name, host, err)
+			continue
+		}
+		return host
+	}
+	return ""
+}
+
+// setActive records the host serving a failover server and where its
+// gateway routes to
+func (c *Coordinator) setActive(name, host string, srv *server.Server) {
+	var target *url.URL
+	if host != "" && srv != nil {
+		target = &url.URL{Scheme: "http", Host: net.JoinHostPort(c.proxyHost(host), fmt.Sprint(srv.Port))}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if host == "" {
+		delete(c.active, name)
+		delete(c.targets, name)
+		return
+	}
+	c.active[name] = host
+	c.targets[name] = target
+}
+
+// proxyHost returns the address of a host's HTTP proxies
+func (c *Coordinator) proxyHost(host string) string {
+	if host == c.host {
+		return "localhost"
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	p, exists := c.peers[host]
+	if !exists {
+		return host
+	}
+	if h, _, err := net.SplitHostPort(p.address); err == nil {
+		return h
+	}
+	return p.address
+}
+
+// startGateway serves a failover server's proxy on the coordinator, routed
+// to whichever host is active
+func (c *Coordinator) startGateway(name string, port int) error {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return fmt.Errorf("failed to start gateway for %s: %w", name, err)
+	}
+
+	gateway := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.mu.Lock()
+		target := c.targets[name]
+		c.mu.Unlock()
+
+		if target == nil {
+			http.Error(w, fmt.Sprintf("no host is running %s", name), http.StatusServiceUnavailable)
+			return
+		}
+
+		proxy := httputil.NewSingleHostReverseProxy(target)
+		proxy.FlushInterval = -1 // Stream SSE responses
+		proxy.ServeHTTP(w, r)
+	})}
+
+	c.mu.Lock()
+	c.gateways = append(c.gateways, gateway)
+	c.mu.Unlock()
+
+	go gateway.Serve(listener)
+	log.Printf("Gateway for %s listening on port %d", name, port)
+	return nil
+}
+
+// serverAvailable returns true if a server can serve requests
+func serverAvailable(srv *server.Server) bool {
+	return srv != nil && srv.IsRunning() && srv.Health != server.HealthUnhealthy
+}
+
+// failureReason describes why the active instance of a server failed
+func failureReason(srv *server.Server) string {
+	switch {
+	case srv == nil:
+		return "host unreachable"
+	case srv.Health == server.HealthUnhealthy && srv.HealthMessage != "":
+		return "unhealthy: " + srv.HealthMessage
+	case srv.Health == server.HealthUnhealthy:
+		return "unhealthy"
+	default:
+		return "server " + string(srv.Status)
+	}
+}
@@ -0,0 +1,122 @@
+package cluster
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/apitest"
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+func TestCoordinator_CheckFailover(t *testing.T) {
+	box := newDaemon("github")
+	laptop := newDaemon("github")
+	c := newTestCoordinator(newDaemon(), map[string]*apitest.Daemon{"box:8080": box, "laptop:8080": laptop})
+	require.NoError(t, c.AddPeer("box", "box:8080"))
+	require.NoError(t, c.AddPeer("laptop", "laptop:8080"))
+	c.failover = map[string]*config.FailoverConfig{"github": {Hosts: []string{"box", "laptop"}}}
+
+	// The preferred host starts the server
+	c.checkFailover()
+	assert.Equal(t, "box", c.ActiveHost("github"))
+	assert.Equal(t, server.StatusRunning, serverOf(box, "github").Status)
+	assert.Equal(t, server.StatusStopped, serverOf(laptop, "github").Status)
+
+	// An unhealthy instance is stopped and replaced
+	serverOf(box, "github").SetHealth(server.HealthUnhealthy, "401 Unauthorized")
+	c.checkFailover()
+	assert.Equal(t, "laptop", c.ActiveHost("github"))
+	assert.Equal(t, server.StatusStopped, serverOf(box, "github").Status)
+	assert.Equal(t, server.StatusRunning, serverOf(laptop, "github").Status)
+
+	failover := <-c.Failovers()
+	assert.Equal(t, "github", failover.Server)
+	assert.Equal(t, "box", failover.From)
+	assert.Equal(t, "laptop", failover.To)
+	assert.Equal(t, "unhealthy: 401 Unauthorized", failover.Reason)
+
+	// An unreachable host fails over too, and the server stays put once
+	// the original host recovers
+	serverOf(box, "github").SetHealth(server.HealthUnknown, "")
+	laptop.SetReachable(false)
+	c.checkFailover()
+	assert.Equal(t, "box", c.ActiveHost("github"))
+	assert.Equal(t, "host unreachable", (<-c.Failovers()).Reason)
+
+	laptop.SetReachable(true)
+	c.checkFailover()
+	assert.Equal(t, "box", c.ActiveHost("github"))
+}
+
+func TestCoordinator_FailoverMaintenance(t *testing.T) {
+	box := newDaemon("github")
+	laptop := newDaemon("github")
+	local := newDaemon()
+	c := newTestCoordinator(local, map[string]*apitest.Daemon{"box:8080": box, "laptop:8080": laptop})
+	require.NoError(t, c.AddPeer("box", "box:8080"))
+	require.NoError(t, c.AddPeer("laptop", "laptop:8080"))
+	c.failover = map[string]*config.FailoverConfig{"github": {Hosts: []string{"box", "laptop"}}}
+
+	c.checkFailover()
+	require.Equal(t, "box", c.ActiveHost("github"))
+
+	// A server being worked on isn't failed over
+	require.NoError(t, c.SetMaintenance("box/github", true))
+	serverOf(box, "github").SetStatus(server.StatusStopped)
+	c.checkFailover()
+	assert.Equal(t, "box", c.ActiveHost("github"))
+	assert.Equal(t, server.StatusStopped, serverOf(laptop, "github").Status)
+
+	// Nor is anything while the coordinator is in maintenance
+	require.NoError(t, c.SetMaintenance("box/github", false))
+	require.NoError(t, c.SetMaintenance("", true))
+	c.checkFailover()
+	assert.Equal(t, "box", c.ActiveHost("github"))
+
+	require.NoError(t, c.SetMaintenance("", false))
+	c.checkFailover()
+	assert.Equal(t, "laptop", c.ActiveHost("github"))
+}
+
+func TestCoordinator_Gateway(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "served %s", r.URL.Path)
+	}))
+	defer backend.Close()
+
+	backendURL, err := url.Parse(backend.URL)
+	require.NoError(t, err)
+	backendPort, err := strconv.Atoi(backendURL.Port())
+	require.NoError(t, err)
+
+	box := newDaemon("github")
+	serverOf(box, "github").Port = backendPort
+	c := newTestCoordinator(newDaemon(), map[string]*apitest.Daemon{"127.0.0.1:8080": box})
+	require.NoError(t, c.AddPeer("box", "127.0.0.1:8080"))
+
+	require.NoError(t, c.startGateway("github", 8110))
+	defer c.Stop()
+
+	resp, err := http.Get("http://localhost:8110/tools/list")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	c.failover = map[string]*config.FailoverConfig{"github": {Hosts: []string{"box"}, Port: 8110}}
+	c.checkFailover()
+
+	resp, err = http.Get("http://localhost:8110/tools/list")
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "served /tools/list", string(body))
+}
@@ -0,0 +1,59 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	mcpgrpc "github.com/tartavull/mcp-manager/internal/grpc"
+	pb "github.com/tartavull/mcp-manager/internal/grpc/pb"
+	"google.golang.org/grpc"
+)
+
+// HeartbeatInterval is how often a daemon re-registers with its coordinator
+const HeartbeatInterval = 10 * time.Second
+
+// Join registers a daemon with a coordinator and keeps sending heartbeats
+// until ctx is cancelled. address is where the coordinator reaches the
+// daemon.
+func Join(ctx context.Context, coordinator, host, address string) error {
+	opts, err := mcpgrpc.DialOptions()
+	if err != nil {
+		return err
+	}
+	conn, err := grpc.NewClient(coordinator, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to connect to coordinator: %w", err)
+	}
+	defer conn.Close()
+
+	client := pb.NewMCPManagerClient(conn)
+	registered := false
+
+	ticker := time.NewTicker(HeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		regCtx, cancel := context.WithTimeout(ctx, HeartbeatInterval)
+		_, err := client.Register(regCtx, &pb.RegisterRequest{Host: host, Address: address})
+		cancel()
+
+		switch {
+		case err != nil && registered:
+			log.Printf("Lost coordinator %s: %v", coordinator, err)
+			registered = false
+		case err != nil:
+			log.Printf("Failed to register with coordinator %s: %v", coordinator, err)
+		case !registered:
+			log.Printf("Registered with coordinator %s as %s", coordinator, host)
+			registered = true
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,434 @@
+package cluster
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+	mcpgrpc "github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/logs"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// upstream tracks a daemon whose servers a Chain serves
+type upstream struct {
+	address  string
+	offset   int
+	client   Peer                 // Nil until connected
+	forwards map[int]*http.Server // Local port to the proxy forwarding to it
+}
+
+// Chain serves the servers of upstream daemons alongside the local ones.
+// Local servers keep their names, and upstream servers are named
+// "<upstream>/<name>" and carry the upstream as their host label, with
+// operations forwarded to the upstream. Unlike a Coordinator, the upstreams
+// don't know about the chain, so any daemon can be fronted by another.
+type Chain struct {
+	local       mcpgrpc.ManagerInterface
+	bindAddress string // Interface forwarded ports listen on
+
+	mu        sync.Mutex
+	upstreams map[string]*upstream
+
+	// dial connects to a daemon, replaceable for tests
+	dial func(address string) (Peer, error)
+}
+
+// NewChain creates a chain serving the local manager's servers and those
+// of upstreams, keyed by the name their servers are prefixed with.
+// Forwarded ports listen on bindAddress, the proxies' interface, or on
+// loopback if empty.
+func NewChain(local mcpgrpc.ManagerInterface, upstreams map[string]*config.UpstreamConfig, bindAddress string) (*Chain, error) {
+	if bindAddress == "" {
+		bindAddress = "127.0.0.1"
+	}
+	c := &Chain{
+		local:       local,
+		bindAddress: bindAddress,
+		upstreams:   make(map[string]*upstream),
+		dial:        dialPeer,
+	}
+	for name, cfg := range upstreams {
+		switch {
+		case name == "" || strings.Contains(name, "/"):
+			return nil, fmt.Errorf("invalid upstream name %q", name)
+		case cfg == nil || cfg.Address == "":
+			return nil, fmt.Errorf("upstream %s requires an address", name)
+		}
+		c.upstreams[name] = &upstream{address: cfg.Address, offset: cfg.PortOffset, forwards: make(map[int]*http.Server)}
+	}
+	return c, nil
+}
+
+// Upstreams returns the names of the upstreams in order
+func (c *Chain) Upstreams() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	names := make([]string, 0, len(c.upstreams))
+	for name := range c.upstreams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// client returns the connected client of an upstream
+func (c *Chain) client(name string) (Peer, error) {
+	c.mu.Lock()
+	u, exists := c.upstreams[name]
+	if !exists {
+		c.mu.Unlock()
+		return nil, fmt.Errorf("upstream %s %w", name, server.ErrNotFound)
+	}
+	if u.client != nil {
+		client := u.client
+		c.mu.Unlock()
+		return client, nil
+	}
+	address := u.address
+	c.mu.Unlock()
+
+	// Dial outside the lock, connecting can take a while
+	client, err := c.dial(address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to upstream %s: %w", name, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if u.client != nil {
+		// Connected concurrently, keep the first connection
+		client.Close()
+		return u.client, nil
+	}
+	u.client = client
+	return client, nil
+}
+
+// route returns the upstream owning a server and its name there, or an
+// empty upstream for local servers
+func (c *Chain) route(name string) (string, string) {
+	prefix, rest, ok := strings.Cut(name, "/")
+	if !ok {
+		return "", name
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, exists := c.upstreams[prefix]; !exists {
+		return "", name
+	}
+	return prefix, rest
+}
+
+// GetServers returns the local servers followed by those of each upstream.
+// Unreachable upstreams are skipped so one can't take down the view.
+func (c *Chain) GetServers() (map[string]*server.Server, []string, error) {
+	servers, order, err := c.local.GetServers()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	names := c.Upstreams()
+	type upstreamServers struct {
+		servers map[string]*server.Server
+		order   []string
+		err     error
+	}
+	results := make([]upstreamServers, len(names))
+
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			client, err := c.client(name)
+			if err != nil {
+				results[i].err = err
+				return
+			}
+			results[i].servers, results[i].order, results[i].err = client.GetServers()
+		}(i, name)
+	}
+	wg.Wait()
+
+	for i, name := range names {
+		if results[i].err != nil {
+			log.Printf("Skipping unreachable upstream %s: %v", name, results[i].err)
+			continue
+		}
+
+		listed := make(map[int]bool)
+		for _, remote := range results[i].order {
+			srv, exists := results[i].servers[remote]
+			if !exists {
+				continue
+			}
+			labeled := *srv
+			labeled.Name = name + "/" + remote
+			labeled.Host = name
+			if port := c.forward(name, srv.Port); port != 0 {
+				labeled.Port = port
+				listed[port] = true
+			}
+			servers[labeled.Name] = &labeled
+			order = append(order, labeled.Name)
+		}
+		c.closeForwards(name, listed)
+	}
+
+	return servers, order, nil
+}
+
+// forward makes sure the proxy of an upstream server on port is forwarded
+// on this host, if the upstream has a port offset, returning the local
+// port. Forwards are set up as servers are listed, which the gRPC server
+// does every few seconds.
+func (c *Chain) forward(name string, port int) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	u := c.upstreams[name]
+	if u == nil || u.offset == 0 || port == 0 {
+		return 0
+	}
+	local := port + u.offset
+	if _, exists := u.forwards[local]; exists {
+		return local
+	}
+
+	host := u.address
+	if h, _, err := net.SplitHostPort(u.address); err == nil {
+		host = h
+	}
+	target := &url.URL{Scheme: "http", Host: net.JoinHostPort(host, fmt.Sprint(port))}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(c.bindAddress, fmt.Sprint(local)))
+	if err != nil {
+		log.Printf("Failed to forward %s port %d: %v", name, port, err)
+		return 0
+	}
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	proxy.FlushInterval = -1 // Stream SSE responses
+	forward := &http.Server{Handler: proxy}
+	u.forwards[local] = forward
+
+	go forward.Serve(listener)
+	log.Printf("Forwarding port %d to %s on upstream %s", local, target.Host, name)
+	return local
+}
+
+// closeForwards closes the forwarded ports of an upstream not in keep, as
+// their servers were removed from it
+func (c *Chain) closeForwards(name string, keep map[int]bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	u := c.upstreams[name]
+	if u == nil {
+		return
+	}
+	for port, forward := range u.forwards {
+		if !keep[port] {
+			forward.Close()
+			delete(u.forwards, port)
+			log.Printf("Stopped forwarding port %d of upstream %s", port, name)
+		}
+	}
+}
+
+// GetServerOrder returns the names of the local and upstream servers
+func (c *Chain) GetServerOrder() ([]string, error) {
+	_, order, err := c.GetServers()
+	return order, err
+}
+
+// GetServer returns a local or upstream server
+func (c *Chain) GetServer(name string) (*server.Server, error) {
+	if upstream, _ := c.route(name); upstream == "" {
+		return c.local.GetServer(name)
+	}
+
+	servers, _, err := c.GetServers()
+	if err != nil {
+		return nil, err
+	}
+	srv, exists := servers[name]
+	if !exists {
+		return nil, fmt.Errorf("server %s %w", name, server.ErrNotFound)
+	}
+	return srv, nil
+}
+
+// StartServer starts a server on the daemon that owns it
+func (c *Chain) StartServer(name string) error {
+	upstream, remote := c.route(name)
+	if upstream == "" {
+		return c.local.StartServer(name)
+	}
+
+	client, err := c.client(upstream)
+	if err != nil {
+		return err
+	}
+	return client.StartServer(remote)
+}
+
+// StopServer stops a server on the daemon that owns it
+func (c *Chain) StopServer(name string) error {
+	upstream, remote := c.route(name)
+	if upstream == "" {
+		return c.local.StopServer(name)
+	}
+
+	client, err := c.client(upstream)
+	if err != nil {
+		return err
+	}
+	return client.StopServer(remote)
+}
+
+// SetMaintenance toggles maintenance mode for a server on the daemon that
+// owns it, or for the local daemon when name is empty
+func (c *Chain) SetMaintenance(name string, enabled bool) error {
+	upstream, remote := c.route(name)
+	if upstream == "" {
+		return c.local.SetMaintenance(name, enabled)
+	}
+
+	client, err := c.client(upstream)
+	if err != nil {
+		return err
+	}
+	return client.SetMaintenance(remote, enabled)
+}
+
+// Maintenance returns true if the local daemon is in maintenance mode
+func (c *Chain) Maintenance() (bool, error) {
+	return c.local.Maintenance()
+}
+
+// Logs returns the captured output of a local server. Logs of upstream
+// servers are streamed from their daemon.
+func (c *Chain) Logs(name string) (*logs.Buffer, error) {
+	source, ok := c.local.(mcpgrpc.LogSource)
+	if upstream, _ := c.route(name); upstream != "" || !ok {
+		return nil, fmt.Errorf("logs of %s are only available from the daemon running it", name)
+	}
+	return source.Logs(name)
+}
+
+// DescribeServer returns what a local server runs as. Upstream servers
+// are described by their daemon.
+func (c *Chain) DescribeServer(name string) (*mcpgrpc.Runtime, error) {
+	describer, ok := c.local.(mcpgrpc.Describer)
+	if upstream, _ := c.route(name); upstream != "" || !ok {
+		return nil, fmt.Errorf("%s is only described by the daemon running it", name)
+	}
+	return describer.DescribeServer(name)
+}
+
+// CloneServer copies a local server in the daemon's mcp.json. Upstream
+// servers are cloned on their daemon.
+func (c *Chain) CloneServer(source, name string, replace []string) (string, int, error) {
+	cloner, ok := c.local.(mcpgrpc.Cloner)
+	if upstream, _ := c.route(source); upstream != "" || !ok {
+		return "", 0, fmt.Errorf("%s is only cloned by the daemon running it", source)
+	}
+	return cloner.CloneServer(source, name, replace)
+}
+
+// CircuitChanges returns the circuit breaker changes of the local servers
+func (c *Chain) CircuitChanges() <-chan mcpgrpc.CircuitChange {
+	if source, ok := c.local.(mcpgrpc.CircuitSource); ok {
+		return source.CircuitChanges()
+	}
+	return nil
+}
+
+// ConfigChanges returns the reloads of the local configuration
+func (c *Chain) ConfigChanges() <-chan mcpgrpc.ConfigChange {
+	if source, ok := c.local.(mcpgrpc.ConfigSource); ok {
+		return source.ConfigChanges()
+	}
+	return nil
+}
+
+// PortMigrations returns the port migrations of the local servers
+func (c *Chain) PortMigrations() <-chan mcpgrpc.PortMigration {
+	if source, ok := c.local.(mcpgrpc.PortSource); ok {
+		return source.PortMigrations()
+	}
+	return nil
+}
+
+// StatusChanges returns the crashes and automatic restarts of the local
+// servers
+func (c *Chain) StatusChanges() <-chan mcpgrpc.StatusChange {
+	if source, ok := c.local.(mcpgrpc.StatusSource); ok {
+		return source.StatusChanges()
+	}
+	return nil
+}
+
+// Failovers returns the failovers of the local manager, if it coordinates
+// a fleet
+func (c *Chain) Failovers() <-chan mcpgrpc.Failover {
+	if source, ok := c.local.(mcpgrpc.FailoverSource); ok {
+		return source.Failovers()
+	}
+	return nil
+}
+
+// Register adds a daemon to the fleet of the local manager, if it
+// coordinates one
+func (c *Chain) Register(host, address string) error {
+	registrar, ok := c.local.(mcpgrpc.Registrar)
+	if !ok {
+		return fmt.Errorf("this daemon is not a coordinator")
+	}
+	return registrar.Register(host, address)
+}
+
+// GetConfigPath returns the local configuration path
+func (c *Chain) GetConfigPath() (string, error) {
+	return c.local.GetConfigPath()
+}
+
+// UpdateToolCounts refreshes the local tool counts. Upstreams refresh their
+// own.
+func (c *Chain) UpdateToolCounts() error {
+	return c.local.UpdateToolCounts()
+}
+
+// StopAllServers stops the local servers. Upstream servers keep running.
+func (c *Chain) StopAllServers() {
+	c.local.StopAllServers()
+}
+
+// Stop disconnects from the upstreams, closes the forwarded ports and stops
+// the local manager
+func (c *Chain) Stop() error {
+	c.mu.Lock()
+	for _, u := range c.upstreams {
+		for port, forward := range u.forwards {
+			forward.Close()
+			delete(u.forwards, port)
+		}
+		if u.client != nil {
+			u.client.Close()
+			u.client = nil
+		}
+	}
+	c.mu.Unlock()
+
+	return c.local.Stop()
+}
@@ -0,0 +1,159 @@
+package cluster
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/apitest"
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// newTestChain creates a chain whose upstreams are fake daemons keyed by
+// address
+func newTestChain(t *testing.T, local *apitest.Daemon, upstreams map[string]*config.UpstreamConfig, remotes map[string]*apitest.Daemon) *Chain {
+	t.Helper()
+
+	c, err := NewChain(local, upstreams, "")
+	require.NoError(t, err)
+	c.dial = func(address string) (Peer, error) {
+		d, exists := remotes[address]
+		if !exists {
+			return nil, fmt.Errorf("no daemon at %s", address)
+		}
+		return d, nil
+	}
+	t.Cleanup(func() { c.Stop() })
+	return c
+}
+
+func TestNewChain_Invalid(t *testing.T) {
+	_, err := NewChain(newDaemon(), map[string]*config.UpstreamConfig{"a/b": {Address: "box:8080"}}, "")
+	assert.Error(t, err)
+	_, err = NewChain(newDaemon(), map[string]*config.UpstreamConfig{"box": {}}, "")
+	assert.Error(t, err)
+}
+
+func TestChain_GetServers(t *testing.T) {
+	local := newDaemon("memory")
+	homelab := newDaemon("github", "slack")
+	nas := newDaemon("files")
+	nas.SetReachable(false)
+	c := newTestChain(t, local, map[string]*config.UpstreamConfig{
+		"homelab": {Address: "homelab:8080"},
+		"nas":     {Address: "nas:8080"},
+		"gone":    {Address: "gone:8080"},
+	}, map[string]*apitest.Daemon{"homelab:8080": homelab, "nas:8080": nas})
+
+	// Local servers keep their names, unreachable upstreams are skipped
+	servers, order, err := c.GetServers()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"memory", "homelab/github", "homelab/slack"}, order)
+	assert.Equal(t, "homelab", servers["homelab/github"].Host)
+	assert.Equal(t, serverOf(homelab, "github").Port, servers["homelab/github"].Port)
+	assert.Empty(t, servers["memory"].Host)
+	assert.Equal(t, "github", serverOf(homelab, "github").Name)
+
+	srv, err := c.GetServer("homelab/slack")
+	require.NoError(t, err)
+	assert.Equal(t, "homelab/slack", srv.Name)
+	_, err = c.GetServer("homelab/missing")
+	assert.ErrorIs(t, err, server.ErrNotFound)
+}
+
+func TestChain_RoutesOperations(t *testing.T) {
+	local := newDaemon("memory", "team/notes")
+	homelab := newDaemon("github")
+	c := newTestChain(t, local, map[string]*config.UpstreamConfig{
+		"homelab": {Address: "homelab:8080"},
+	}, map[string]*apitest.Daemon{"homelab:8080": homelab})
+
+	require.NoError(t, c.StartServer("homelab/github"))
+	assert.Equal(t, server.StatusRunning, serverOf(homelab, "github").Status)
+	assert.Equal(t, server.StatusStopped, serverOf(local, "memory").Status)
+
+	require.NoError(t, c.StartServer("memory"))
+	assert.Equal(t, server.StatusRunning, serverOf(local, "memory").Status)
+
+	// Names whose prefix isn't an upstream are local
+	require.NoError(t, c.StartServer("team/notes"))
+	assert.Equal(t, server.StatusRunning, serverOf(local, "team/notes").Status)
+
+	require.NoError(t, c.SetMaintenance("homelab/github", true))
+	assert.True(t, serverOf(homelab, "github").Maintenance)
+
+	require.NoError(t, c.StopServer("homelab/github"))
+	assert.Equal(t, server.StatusStopped, serverOf(homelab, "github").Status)
+
+	_, err := c.Logs("homelab/github")
+	assert.Error(t, err)
+
+	require.NoError(t, c.Stop())
+	assert.True(t, homelab.Closed())
+}
+
+func TestChain_ForwardsPorts(t *testing.T) {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "upstream proxy")
+	}))
+	defer proxy.Close()
+	proxyPort := proxy.Listener.Addr().(*net.TCPAddr).Port
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	localPort := lis.Addr().(*net.TCPAddr).Port
+	lis.Close()
+
+	homelab := newDaemon("github")
+	serverOf(homelab, "github").Port = proxyPort
+	c := newTestChain(t, newDaemon(), map[string]*config.UpstreamConfig{
+		"homelab": {Address: "127.0.0.1:8080", PortOffset: localPort - proxyPort},
+	}, map[string]*apitest.Daemon{"127.0.0.1:8080": homelab})
+
+	servers, _, err := c.GetServers()
+	require.NoError(t, err)
+	assert.Equal(t, localPort, servers["homelab/github"].Port)
+
+	resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/", localPort))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "upstream proxy", string(body))
+
+	// Listing again reuses the forward
+	servers, _, err = c.GetServers()
+	require.NoError(t, err)
+	assert.Equal(t, localPort, servers["homelab/github"].Port)
+
+	// Forwards listen on loopback only
+	if ip := nonLoopbackIP(); ip != "" {
+		_, err = http.Get(fmt.Sprintf("http://%s/", net.JoinHostPort(ip, fmt.Sprint(localPort))))
+		assert.Error(t, err)
+	}
+
+	// and close once the server is removed from the upstream
+	require.NoError(t, homelab.RemoveServer("github"))
+	_, _, err = c.GetServers()
+	require.NoError(t, err)
+	_, err = http.Get(fmt.Sprintf("http://127.0.0.1:%d/", localPort))
+	assert.Error(t, err)
+}
+
+// nonLoopbackIP returns an IPv4 address of another interface than
+// loopback, or "" if there is none
+func nonLoopbackIP() string {
+	addrs, _ := net.InterfaceAddrs()
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && !ipNet.IP.IsLoopback() && ipNet.IP.To4() != nil {
+			return ipNet.IP.String()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// CloneServer copies the server source as name, right after it in the
+// server order, on the first port after those configured. An empty name
+// picks "<source>-2", or the next free number. Each replacement "old=new"
+// substitutes new for old in the command of the copy.
+func (c *MCPConfig) CloneServer(source, name string, basePort int, replace []string) (string, error) {
+	srv, exists := c.Servers[source]
+	if !exists {
+		return "", fmt.Errorf("server '%s' not found", source)
+	}
+
+	var pairs []string
+	for _, r := range replace {
+		old, replacement, ok := strings.Cut(r, "=")
+		if !ok || old == "" {
+			return "", fmt.Errorf("invalid replacement '%s', want old=new", r)
+		}
+		pairs = append(pairs, old, replacement)
+	}
+
+	taken := c.serverNames()
+	if name == "" {
+		for i := 2; ; i++ {
+			name = InstanceName(source, i)
+			if !taken[name] {
+				break
+			}
+		}
+	} else if taken[name] {
+		return "", fmt.Errorf("server '%s' already exists", name)
+	}
+
+	// A JSON round trip copies the maps and slices too
+	data, err := json.Marshal(srv)
+	if err != nil {
+		return "", fmt.Errorf("failed to copy server '%s': %w", source, err)
+	}
+	var clone MCPServerConfig
+	if err := json.Unmarshal(data, &clone); err != nil {
+		return "", fmt.Errorf("failed to copy server '%s': %w", source, err)
+	}
+	if len(pairs) > 0 {
+		clone.Command = strings.NewReplacer(pairs...).Replace(clone.Command)
+	}
+	clone.Port = c.nextFreePort(basePort, clone.InstanceCount())
+
+	c.Servers[name] = &clone
+	at := slices.Index(c.ServerOrder, source) + 1
+	if at == 0 {
+		at = len(c.ServerOrder)
+	}
+	c.ServerOrder = slices.Insert(c.ServerOrder, at, name)
+	return name, nil
+}
+
+// serverNames returns the names of the servers, including the instances
+// templates expand into
+func (c *MCPConfig) serverNames() map[string]bool {
+	names := make(map[string]bool, len(c.Servers))
+	for name, srv := range c.Servers {
+		names[name] = true
+		if srv.IsTemplate() {
+			for i := 1; i <= srv.InstanceCount(); i++ {
+				names[InstanceName(name, i)] = true
+			}
+		}
+	}
+	return names
+}
+
+// nextFreePort returns the first of count consecutive ports after those
+// of the servers, from basePort, avoiding the gateway's port
+func (c *MCPConfig) nextFreePort(basePort, count int) int {
+	port := basePort
+	if c.BasePort != 0 {
+		port = c.BasePort
+	}
+	for _, srv := range c.Servers {
+		if last := srv.Port + srv.InstanceCount() - 1; srv.Port != 0 && last >= port {
+			port = last + 1
+		}
+	}
+	if c.Gateway != nil && c.Gateway.Port >= port && c.Gateway.Port < port+count {
+		port = c.Gateway.Port + 1
+	}
+	return port
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloneServer(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &Config{ConfigDir: tempDir}
+
+	testConfig := `{
+  "servers": {
+    "filesystem": {
+      "command": "npx @modelcontextprotocol/server-filesystem /home/me/docs",
+      "env": {"LOG_LEVEL": "debug"},
+      "tags": ["files"]
+    },
+    "filesystem-2": {"command": "npx @modelcontextprotocol/server-filesystem /tmp"},
+    "browser": {"command": "npx @playwright/mcp", "instances": 2}
+  },
+  "gateway": {"port": 4005}
+}`
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "mcp.json"), []byte(testConfig), 0644))
+	mcpConfig, err := cfg.LoadMCPConfig()
+	require.NoError(t, err)
+
+	// The copy skips taken names and the gateway's port
+	name, err := mcpConfig.CloneServer("filesystem", "", cfg.DefaultBasePort(), []string{"/home/me/docs=/home/me/code"})
+	require.NoError(t, err)
+	assert.Equal(t, "filesystem-3", name)
+	assert.Equal(t, []string{"filesystem", "filesystem-3", "filesystem-2", "browser"}, mcpConfig.ServerOrder)
+
+	clone := mcpConfig.Servers["filesystem-3"]
+	assert.Equal(t, "npx @modelcontextprotocol/server-filesystem /home/me/code", clone.Command)
+	assert.Equal(t, 4006, clone.Port)
+	assert.Equal(t, []string{"files"}, clone.Tags)
+	clone.Env["LOG_LEVEL"] = "info"
+	assert.Equal(t, "debug", mcpConfig.Servers["filesystem"].Env["LOG_LEVEL"], "the copy doesn't share the source's maps")
+
+	// Templates are copied whole, reserving a port per instance
+	name, err = mcpConfig.CloneServer("browser", "browser-eu", cfg.DefaultBasePort(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "browser-eu", name)
+	assert.Equal(t, 4007, mcpConfig.Servers["browser-eu"].Port)
+	assert.Equal(t, 2, mcpConfig.Servers["browser-eu"].Instances)
+
+	require.NoError(t, cfg.SaveMCPConfig(mcpConfig))
+	saved, err := cfg.LoadMCPConfig()
+	require.NoError(t, err)
+	assert.Equal(t, mcpConfig.ServerOrder, saved.ServerOrder)
+
+	_, err = mcpConfig.CloneServer("missing", "", cfg.DefaultBasePort(), nil)
+	assert.EqualError(t, err, "server 'missing' not found")
+	_, err = mcpConfig.CloneServer("filesystem", "browser-1", cfg.DefaultBasePort(), nil)
+	assert.EqualError(t, err, "server 'browser-1' already exists", "instances of templates are taken")
+	_, err = mcpConfig.CloneServer("filesystem", "", cfg.DefaultBasePort(), []string{"docs"})
+	assert.EqualError(t, err, "invalid replacement 'docs', want old=new")
+}
@@ -1,147 +1,123 @@
 package config
 
 import (
-	"encoding/json"
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+)
 
-	"github.com/tartavull/mcp-manager/internal/server"
+// Environment variables overriding the directories
+const (
+	ConfigDirEnv = "MCP_CONFIG_DIR"
+	StateDirEnv  = "MCP_STATE_DIR"
+	CacheDirEnv  = "MCP_CACHE_DIR"
 )
 
-// Config manages the application configuration
+// Config manages the application configuration and locates the files of
+// the manager, following the XDG base directory specification:
+//
+//	ConfigDir: mcp.json, edited by the user ($XDG_CONFIG_HOME/mcp-manager)
+//	StateDir:  state.json, PIDs, logs and the event journal ($XDG_STATE_HOME/mcp-manager)
+//	CacheDir:  files that can be deleted at any time ($XDG_CACHE_HOME/mcp-manager)
+//
+// Named instances keep theirs in instances/<name> of each directory.
 type Config struct {
+	Instance  string // Name of the instance, empty for the default one
 	ConfigDir string
-	PidDir    string
+	StateDir  string
+	CacheDir  string
 }
 
-// New creates a new configuration manager
+// New creates a new configuration manager, creating its directories
 func New() (*Config, error) {
-	var configDir string
-
-	// Check for environment variable first
-	if envDir := os.Getenv("MCP_CONFIG_DIR"); envDir != "" {
-		configDir = envDir
-	} else {
-		// Fall back to default location
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			return nil, fmt.Errorf("failed to get home directory: %w", err)
-		}
-		configDir = filepath.Join(homeDir, ".config", "mcp-manager")
-	}
-
-	pidDir := filepath.Join(configDir, "pids")
-
-	// Create directories if they don't exist
-	if err := os.MkdirAll(configDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create config directory: %w", err)
+	instance := os.Getenv(InstanceEnv)
+	if err := ValidateInstance(instance); err != nil {
+		return nil, err
 	}
-
-	if err := os.MkdirAll(pidDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create pid directory: %w", err)
+	appDir := []string{"mcp-manager"}
+	if instance != "" {
+		appDir = append(appDir, "instances", instance)
 	}
 
-	return &Config{
-		ConfigDir: configDir,
-		PidDir:    pidDir,
-	}, nil
-}
-
-// GetServersFilePath returns the path to the servers configuration file
-func (c *Config) GetServersFilePath() string {
-	return filepath.Join(c.ConfigDir, "servers.json")
-}
-
-// GetPidFilePath returns the path to a server's PID file
-func (c *Config) GetPidFilePath(serverName string) string {
-	return filepath.Join(c.PidDir, fmt.Sprintf("%s.pid", serverName))
-}
-
-// LoadServers loads server configurations from file
-func (c *Config) LoadServers() (map[string]*server.Server, error) {
-	filePath := c.GetServersFilePath()
-
-	// If file doesn't exist, return default servers
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		defaultServers := server.GetDefaultServers()
-		serverMap := make(map[string]*server.Server)
-		for _, srv := range defaultServers {
-			serverMap[srv.Name] = srv
+	homeDir, homeErr := os.UserHomeDir()
+	dir := func(env, xdg string, fallback ...string) (string, error) {
+		if envDir := os.Getenv(env); envDir != "" {
+			return envDir, nil
 		}
-
-		// Save default servers to file
-		if err := c.SaveServers(serverMap); err != nil {
-			return nil, fmt.Errorf("failed to save default servers: %w", err)
+		if xdgDir := os.Getenv(xdg); filepath.IsAbs(xdgDir) {
+			return filepath.Join(append([]string{xdgDir}, appDir...)...), nil
 		}
-
-		return serverMap, nil
+		if homeErr != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", homeErr)
+		}
+		return filepath.Join(append(append([]string{homeDir}, fallback...), appDir...)...), nil
 	}
 
-	// Read existing file
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read servers file: %w", err)
+	c := &Config{Instance: instance}
+	var err error
+	if c.ConfigDir, err = dir(ConfigDirEnv, "XDG_CONFIG_HOME", ".config"); err != nil {
+		return nil, err
 	}
-
-	var serverMap map[string]*server.Server
-	if err := json.Unmarshal(data, &serverMap); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal servers: %w", err)
+	if c.StateDir, err = dir(StateDirEnv, "XDG_STATE_HOME", ".local", "state"); err != nil {
+		return nil, err
 	}
-
-	return serverMap, nil
-}
-
-// SaveServers saves server configurations to file
-func (c *Config) SaveServers(servers map[string]*server.Server) error {
-	filePath := c.GetServersFilePath()
-
-	data, err := json.MarshalIndent(servers, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal servers: %w", err)
+	if c.CacheDir, err = dir(CacheDirEnv, "XDG_CACHE_HOME", ".cache"); err != nil {
+		return nil, err
 	}
 
-	if err := os.WriteFile(filePath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write servers file: %w", err)
+	// Create the directories if they don't exist
+	for _, d := range []string{c.ConfigDir, c.StateDir, c.CacheDir} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create directory: %w", err)
+		}
 	}
 
-	return nil
+	return c, nil
 }
 
-// SavePID saves a process ID to a PID file
-func (c *Config) SavePID(serverName string, pid int) error {
-	filePath := c.GetPidFilePath(serverName)
-	data := fmt.Sprintf("%d", pid)
-
-	if err := os.WriteFile(filePath, []byte(data), 0644); err != nil {
-		return fmt.Errorf("failed to write PID file: %w", err)
+// RegisterDirFlags adds -instance, -config-dir, -state-dir and -cache-dir
+// to fs, which override the directories of this process and the processes
+// it starts
+func RegisterDirFlags(fs *flag.FlagSet) {
+	for _, f := range []struct{ name, env, usage string }{
+		{"instance", InstanceEnv, "Name of the instance, with its own config, state and ports"},
+		{"config-dir", ConfigDirEnv, "Directory of mcp.json"},
+		{"state-dir", StateDirEnv, "Directory of runtime state, PIDs and logs"},
+		{"cache-dir", CacheDirEnv, "Directory of cached files"},
+	} {
+		env := f.env
+		fs.Func(f.name, fmt.Sprintf("%s (env %s)", f.usage, env), func(value string) error {
+			return os.Setenv(env, value)
+		})
 	}
-
-	return nil
 }
 
-// LoadPID loads a process ID from a PID file
-func (c *Config) LoadPID(serverName string) (int, error) {
-	filePath := c.GetPidFilePath(serverName)
-
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		return 0, err
+// GetStateDir returns the directory of runtime state. Configs without one
+// keep their state in ConfigDir.
+func (c *Config) GetStateDir() string {
+	if c.StateDir == "" {
+		return c.ConfigDir
 	}
+	return c.StateDir
+}
 
-	var pid int
-	if _, err := fmt.Sscanf(string(data), "%d", &pid); err != nil {
-		return 0, fmt.Errorf("failed to parse PID: %w", err)
+// GetCacheDir returns the directory of cached files. Configs without one
+// keep them in ConfigDir.
+func (c *Config) GetCacheDir() string {
+	if c.CacheDir == "" {
+		return c.ConfigDir
 	}
+	return c.CacheDir
+}
 
-	return pid, nil
+// GetLogPath returns the path of a log file, e.g. daemon.log
+func (c *Config) GetLogPath(name string) string {
+	return filepath.Join(c.GetStateDir(), name)
 }
 
-// RemovePID removes a PID file
-func (c *Config) RemovePID(serverName string) error {
-	filePath := c.GetPidFilePath(serverName)
-	if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("failed to remove PID file: %w", err)
-	}
-	return nil
+// getServersFilePath returns the path to servers.json, the configuration
+// file before mcp.json, which is only read to migrate it
+func (c *Config) getServersFilePath() string {
+	return filepath.Join(c.ConfigDir, "servers.json")
 }
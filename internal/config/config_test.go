@@ -1,15 +1,15 @@
 package config
 
 import (
-	"encoding/json"
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"github.com/tartavull/mcp-manager/internal/server"
 )
 
 func TestNew(t *testing.T) {
@@ -17,24 +17,62 @@ func TestNew(t *testing.T) {
 	require.NoError(t, err)
 
 	assert.NotEmpty(t, config.ConfigDir)
-	assert.NotEmpty(t, config.PidDir)
 	assert.Contains(t, config.ConfigDir, "mcp-manager")
-	assert.Contains(t, config.PidDir, "pids")
 
-	// Check that directories exist
+	// Check that the directory exists
 	assert.DirExists(t, config.ConfigDir)
-	assert.DirExists(t, config.PidDir)
+}
+
+func TestNew_Dirs(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(InstanceEnv, "")
+	t.Setenv(ConfigDirEnv, "")
+	t.Setenv(StateDirEnv, "")
+	t.Setenv(CacheDirEnv, "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_STATE_HOME", "")
+	t.Setenv("XDG_CACHE_HOME", "")
+
+	// Defaults of the XDG base directory specification
+	config, err := New()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, ".config", "mcp-manager"), config.ConfigDir)
+	assert.Equal(t, filepath.Join(home, ".local", "state", "mcp-manager"), config.StateDir)
+	assert.Equal(t, filepath.Join(home, ".cache", "mcp-manager"), config.CacheDir)
+	assert.DirExists(t, config.StateDir)
+	assert.DirExists(t, config.CacheDir)
+
+	// XDG variables, ignored when relative
+	t.Setenv("XDG_STATE_HOME", filepath.Join(home, "state"))
+	t.Setenv("XDG_CACHE_HOME", "relative")
+	config, err = New()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, "state", "mcp-manager"), config.StateDir)
+	assert.Equal(t, filepath.Join(home, ".cache", "mcp-manager"), config.CacheDir)
+
+	// Overrides, which the flags set
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	RegisterDirFlags(fs)
+	require.NoError(t, fs.Parse([]string{"-state-dir", filepath.Join(home, "custom")}))
+	config, err = New()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, "custom"), config.StateDir)
+	assert.Equal(t, filepath.Join(home, "custom", "state.json"), config.GetStatePath())
+	assert.Equal(t, filepath.Join(home, "custom", "daemon.log"), config.GetLogPath("daemon.log"))
 }
 
 func TestConfig_GetPaths(t *testing.T) {
 	config, err := New()
 	require.NoError(t, err)
 
-	serversFile := config.GetServersFilePath()
-	assert.Contains(t, serversFile, "servers.json")
+	assert.Equal(t, filepath.Join(config.ConfigDir, "mcp.json"), config.GetMCPConfigPath())
+	assert.Equal(t, filepath.Join(config.StateDir, "state.json"), config.GetStatePath())
 
-	pidFile := config.GetPidFilePath("test-server")
-	assert.Contains(t, pidFile, "test-server.pid")
+	// Configs without state and cache directories keep everything together
+	config = &Config{ConfigDir: t.TempDir()}
+	assert.Equal(t, filepath.Join(config.ConfigDir, "state.json"), config.GetStatePath())
+	assert.Equal(t, config.ConfigDir, config.GetCacheDir())
 }
 
 func TestConfig_PIDOperations(t *testing.T) {
@@ -62,137 +100,55 @@ func TestConfig_PIDOperations(t *testing.T) {
 	assert.Error(t, err)
 }
 
-func TestConfig_LoadServers_DefaultServers(t *testing.T) {
-	// Create a temporary config for testing
-	tempDir := t.TempDir()
-	config := &Config{
-		ConfigDir: tempDir,
-		PidDir:    filepath.Join(tempDir, "pids"),
-	}
-
-	// Ensure PID directory exists
-	err := os.MkdirAll(config.PidDir, 0755)
-	require.NoError(t, err)
+func TestConfig_State(t *testing.T) {
+	config := &Config{ConfigDir: t.TempDir()}
 
-	// Load servers when no file exists (should create default servers)
-	servers, err := config.LoadServers()
+	// No state file means no running servers
+	state, err := config.LoadState()
 	require.NoError(t, err)
+	assert.Empty(t, state.Servers)
 
-	// Should have default servers
-	assert.Greater(t, len(servers), 0)
-
-	// Check that some expected servers exist
-	assert.Contains(t, servers, "filesystem")
-	assert.Contains(t, servers, "github")
-
-	// Check that servers file was created
-	assert.FileExists(t, config.GetServersFilePath())
-}
+	before := time.Now()
+	require.NoError(t, config.SavePID("test", 123))
 
-func TestConfig_SaveAndLoadServers(t *testing.T) {
-	// Create a temporary config for testing
-	tempDir := t.TempDir()
-	config := &Config{
-		ConfigDir: tempDir,
-		PidDir:    filepath.Join(tempDir, "pids"),
-	}
-
-	// Ensure PID directory exists
-	err := os.MkdirAll(config.PidDir, 0755)
-	require.NoError(t, err)
-
-	// Create test servers
-	servers := map[string]*server.Server{
-		"test1": server.NewServer("test1", "cmd1", 4001, "Test 1"),
-		"test2": server.NewServer("test2", "cmd2", 4002, "Test 2"),
-	}
-
-	// Set some additional properties
-	servers["test1"].SetStatus(server.StatusRunning)
-	servers["test1"].SetPID(123)
-	servers["test1"].SetToolCount(10)
-
-	// Save servers
-	err = config.SaveServers(servers)
-	require.NoError(t, err)
-
-	// Load servers
-	loadedServers, err := config.LoadServers()
+	state, err = config.LoadState()
 	require.NoError(t, err)
+	require.Contains(t, state.Servers, "test")
+	assert.Equal(t, 123, state.Servers["test"].PID)
+	assert.False(t, state.Servers["test"].StartedAt.Before(before))
 
-	// Verify loaded servers
-	assert.Len(t, loadedServers, 2)
-	assert.Contains(t, loadedServers, "test1")
-	assert.Contains(t, loadedServers, "test2")
-
-	// Check server details
-	srv1 := loadedServers["test1"]
-	assert.Equal(t, "test1", srv1.Name)
-	assert.Equal(t, "cmd1", srv1.Command)
-	assert.Equal(t, 4001, srv1.Port)
-	assert.Equal(t, "Test 1", srv1.Description)
-	assert.Equal(t, server.StatusRunning, srv1.Status)
-	assert.Equal(t, 123, srv1.PID)
-	assert.Equal(t, 10, srv1.ToolCount)
-
-	srv2 := loadedServers["test2"]
-	assert.Equal(t, "test2", srv2.Name)
-	assert.Equal(t, server.StatusStopped, srv2.Status)
+	// The state is kept apart from the declarative config
+	assert.FileExists(t, config.GetStatePath())
+	assert.False(t, config.MCPConfigExists())
 }
 
-func TestConfig_LoadServers_InvalidJSON(t *testing.T) {
-	// Create a temporary config for testing
-	tempDir := t.TempDir()
-	config := &Config{
-		ConfigDir: tempDir,
-		PidDir:    filepath.Join(tempDir, "pids"),
-	}
-
-	// Write invalid JSON to servers file
-	serversFile := config.GetServersFilePath()
-	err := os.WriteFile(serversFile, []byte(`{invalid json}`), 0644)
-	require.NoError(t, err)
+func TestConfig_State_InvalidJSON(t *testing.T) {
+	config := &Config{ConfigDir: t.TempDir()}
+	require.NoError(t, os.WriteFile(config.GetStatePath(), []byte(`{invalid json}`), 0644))
 
-	// Loading should fail
-	_, err = config.LoadServers()
+	_, err := config.LoadPID("test")
 	assert.Error(t, err)
 }
 
-func TestConfig_SaveServers_PermissionError(t *testing.T) {
-	// Create a temporary config for testing
-	tempDir := t.TempDir()
-	config := &Config{
-		ConfigDir: filepath.Join(tempDir, "readonly"),
-		PidDir:    filepath.Join(tempDir, "pids"),
-	}
+func TestConfig_State_ImportsPIDFiles(t *testing.T) {
+	config := &Config{ConfigDir: t.TempDir()}
+	pidDir := filepath.Join(config.ConfigDir, "pids")
+	require.NoError(t, os.MkdirAll(pidDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(pidDir, "legacy.pid"), []byte("4242"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(pidDir, "invalid.pid"), []byte("invalid-pid"), 0644))
 
-	// Create readonly directory
-	err := os.MkdirAll(config.ConfigDir, 0444)
+	pid, err := config.LoadPID("legacy")
 	require.NoError(t, err)
-
-	servers := map[string]*server.Server{
-		"test": server.NewServer("test", "cmd", 4001, "Test"),
-	}
-
-	// Save should fail due to permissions
-	err = config.SaveServers(servers)
+	assert.Equal(t, 4242, pid)
+	_, err = config.LoadPID("invalid")
 	assert.Error(t, err)
-}
-
-func TestConfig_PIDFile_InvalidContent(t *testing.T) {
-	config, err := New()
-	require.NoError(t, err)
-
-	serverName := "test-server"
-	pidFile := config.GetPidFilePath(serverName)
 
-	// Write invalid PID content
-	err = os.WriteFile(pidFile, []byte("invalid-pid"), 0644)
+	// The PID files are removed once the state is saved
+	require.NoError(t, config.SavePID("other", 1))
+	assert.NoDirExists(t, pidDir)
+	pid, err = config.LoadPID("legacy")
 	require.NoError(t, err)
-
-	// Loading should fail
-	_, err = config.LoadPID(serverName)
-	assert.Error(t, err)
+	assert.Equal(t, 4242, pid)
 }
 
 func TestConfig_RemovePID_NonExistent(t *testing.T) {
@@ -204,56 +160,13 @@ func TestConfig_RemovePID_NonExistent(t *testing.T) {
 	assert.NoError(t, err)
 }
 
-func TestConfig_ServersFile_JSONFormat(t *testing.T) {
-	// Create a temporary config for testing
-	tempDir := t.TempDir()
-	config := &Config{
-		ConfigDir: tempDir,
-		PidDir:    filepath.Join(tempDir, "pids"),
-	}
-
-	// Ensure PID directory exists
-	err := os.MkdirAll(config.PidDir, 0755)
-	require.NoError(t, err)
-
-	// Create test servers
-	servers := map[string]*server.Server{
-		"test": server.NewServer("test", "npm start", 4001, "Test Server"),
-	}
-
-	// Save servers
-	err = config.SaveServers(servers)
-	require.NoError(t, err)
-
-	// Read the file and verify JSON format
-	data, err := os.ReadFile(config.GetServersFilePath())
-	require.NoError(t, err)
-
-	var jsonData map[string]interface{}
-	err = json.Unmarshal(data, &jsonData)
-	require.NoError(t, err)
-
-	// Verify structure
-	assert.Contains(t, jsonData, "test")
-	testServer := jsonData["test"].(map[string]interface{})
-	assert.Equal(t, "test", testServer["name"])
-	assert.Equal(t, "npm start", testServer["command"])
-	assert.Equal(t, float64(4001), testServer["port"]) // JSON numbers are float64
-	assert.Equal(t, "Test Server", testServer["description"])
-}
-
 func TestConfig_ConcurrentPIDOperations(t *testing.T) {
 	// Create a temporary config for testing
 	tempDir := t.TempDir()
 	config := &Config{
 		ConfigDir: tempDir,
-		PidDir:    filepath.Join(tempDir, "pids"),
 	}
 
-	// Ensure PID directory exists
-	err := os.MkdirAll(config.PidDir, 0755)
-	require.NoError(t, err)
-
 	serverName := "concurrent-test"
 	pid := 98765
 
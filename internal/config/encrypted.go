@@ -0,0 +1,124 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// How a file can be encrypted, e.g. to keep mcp.json in a dotfiles repo
+const (
+	EncryptionSOPS = "sops" // SOPS, with any of its key types
+	EncryptionAge  = "age"  // age, binary or armored
+)
+
+// AgeKeyFileEnv is the age identity file decrypting age-encrypted files,
+// shared with SOPS (default: sops/age/keys.txt in the user config dir)
+const AgeKeyFileEnv = "SOPS_AGE_KEY_FILE"
+
+// ErrEncryptedConfig is returned when saving over an encrypted mcp.json,
+// which would replace it with plaintext
+var ErrEncryptedConfig = errors.New("mcp.json is encrypted")
+
+// decryptCommand runs a decryption tool with stdin, returning its output.
+// Plaintext only ever lives in memory.
+var decryptCommand = func(stdin []byte, args ...string) ([]byte, error) {
+	if _, err := exec.LookPath(args[0]); err != nil {
+		return nil, fmt.Errorf("%s is not installed", args[0])
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s: %s", args[0], msg)
+		}
+		return nil, fmt.Errorf("%s: %w", args[0], err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// Encryption returns how data is encrypted, or an empty string if it's
+// plaintext
+func Encryption(data []byte) string {
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("age-encryption.org/")) ||
+		bytes.HasPrefix(trimmed, []byte("-----BEGIN AGE ENCRYPTED FILE-----")) {
+		return EncryptionAge
+	}
+
+	// SOPS keeps its metadata in a top-level sops key, in JSON or YAML
+	var probe struct {
+		SOPS map[string]interface{} `yaml:"sops"`
+	}
+	if yaml.Unmarshal(trimmed, &probe) == nil && probe.SOPS != nil {
+		return EncryptionSOPS
+	}
+	return ""
+}
+
+// Decrypt returns the plaintext of a file read from path, which is data
+// itself if it isn't encrypted, and how it was encrypted. SOPS files are
+// decrypted to JSON.
+func Decrypt(path string, data []byte) ([]byte, string, error) {
+	encryption := Encryption(data)
+	var plaintext []byte
+	var err error
+	switch encryption {
+	case "":
+		return data, "", nil
+	case EncryptionSOPS:
+		// sops picks the input format from the file extension
+		plaintext, err = decryptCommand(nil, "sops", "--decrypt", "--output-type", "json", path)
+	case EncryptionAge:
+		var keyFile string
+		if keyFile, err = ageKeyFile(); err == nil {
+			plaintext, err = decryptCommand(data, "age", "--decrypt", "-i", keyFile)
+		}
+	}
+	if err != nil {
+		return nil, encryption, fmt.Errorf("failed to decrypt %s: %w", filepath.Base(path), err)
+	}
+	return plaintext, encryption, nil
+}
+
+// DecryptFile reads a file, decrypting it if it's encrypted
+func DecryptFile(path string) ([]byte, string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", err
+	}
+	return Decrypt(path, data)
+}
+
+// ageKeyFile returns the path of the age identities
+func ageKeyFile() (string, error) {
+	if path := os.Getenv(AgeKeyFileEnv); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("set %s to the age identity file", AgeKeyFileEnv)
+	}
+	return filepath.Join(dir, "sops", "age", "keys.txt"), nil
+}
+
+// checkNotEncrypted returns ErrEncryptedConfig if the file at path is
+// encrypted
+func checkNotEncrypted(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	if encryption := Encryption(data); encryption != "" {
+		return fmt.Errorf("%w with %s, edit it with %s instead", ErrEncryptedConfig, encryption, encryption)
+	}
+	return nil
+}
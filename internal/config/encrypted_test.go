@@ -0,0 +1,111 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDecrypt replaces the decryption tools, recording their arguments
+func fakeDecrypt(t *testing.T, plaintext string, err error) *[]string {
+	var args []string
+	original := decryptCommand
+	decryptCommand = func(stdin []byte, a ...string) ([]byte, error) {
+		args = a
+		if err != nil {
+			return nil, err
+		}
+		return []byte(plaintext), nil
+	}
+	t.Cleanup(func() { decryptCommand = original })
+	return &args
+}
+
+func TestEncryption(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"plaintext", `{"servers": {}}`, ""},
+		{"age", "age-encryption.org/v1\n-> X25519 abc\n", EncryptionAge},
+		{"armored age", "-----BEGIN AGE ENCRYPTED FILE-----\nYWdl\n-----END AGE ENCRYPTED FILE-----\n", EncryptionAge},
+		{"sops json", `{"servers": "ENC[AES256_GCM,data:abc]", "sops": {"version": "3.8.1"}}`, EncryptionSOPS},
+		{"sops yaml", "servers: ENC[AES256_GCM,data:abc]\nsops:\n  version: 3.8.1\n", EncryptionSOPS},
+		{"sops key not metadata", `{"sops": "enabled"}`, ""},
+		{"garbage", "\x00\x01", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Encryption([]byte(tt.data)))
+		})
+	}
+}
+
+func TestDecrypt(t *testing.T) {
+	t.Run("plaintext is returned as is", func(t *testing.T) {
+		args := fakeDecrypt(t, "unused", nil)
+		plaintext, encryption, err := Decrypt("mcp.json", []byte(`{"servers": {}}`))
+		require.NoError(t, err)
+		assert.Equal(t, `{"servers": {}}`, string(plaintext))
+		assert.Empty(t, encryption)
+		assert.Nil(t, *args)
+	})
+
+	t.Run("sops", func(t *testing.T) {
+		args := fakeDecrypt(t, `{"servers": {}}`, nil)
+		plaintext, encryption, err := Decrypt("/dotfiles/mcp.yaml", []byte("sops:\n  version: 3.8.1\n"))
+		require.NoError(t, err)
+		assert.Equal(t, EncryptionSOPS, encryption)
+		assert.Equal(t, `{"servers": {}}`, string(plaintext))
+		assert.Equal(t, []string{"sops", "--decrypt", "--output-type", "json", "/dotfiles/mcp.yaml"}, *args)
+	})
+
+	t.Run("age uses the key file", func(t *testing.T) {
+		t.Setenv(AgeKeyFileEnv, "/keys/age.txt")
+		args := fakeDecrypt(t, `{"servers": {}}`, nil)
+		_, encryption, err := Decrypt("mcp.json", []byte("age-encryption.org/v1\n"))
+		require.NoError(t, err)
+		assert.Equal(t, EncryptionAge, encryption)
+		assert.Equal(t, []string{"age", "--decrypt", "-i", "/keys/age.txt"}, *args)
+	})
+
+	t.Run("failures name the file", func(t *testing.T) {
+		fakeDecrypt(t, "", errors.New("sops: no key could decrypt the data"))
+		_, _, err := Decrypt("/dotfiles/mcp.json", []byte(`{"sops": {"version": "3.8.1"}}`))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to decrypt mcp.json")
+		assert.Contains(t, err.Error(), "no key could decrypt")
+	})
+}
+
+func TestLoadEncryptedMCPConfig(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &Config{ConfigDir: tempDir}
+	encrypted := `{"servers": "ENC[AES256_GCM,data:abc]", "sops": {"version": "3.8.1"}}`
+	configPath := filepath.Join(tempDir, "mcp.json")
+	require.NoError(t, os.WriteFile(configPath, []byte(encrypted), 0600))
+
+	// An unversioned config is migrated in memory only
+	fakeDecrypt(t, `{"servers": {"db": {"command": "postgres-mcp", "env": {"DATABASE_URL": "postgres://u:p@db/app"}}}}`, nil)
+	mcpConfig, err := cfg.LoadMCPConfig()
+	require.NoError(t, err)
+	assert.Equal(t, EncryptionSOPS, mcpConfig.Encryption)
+	require.Contains(t, mcpConfig.Servers, "db")
+	assert.Equal(t, "postgres://u:p@db/app", mcpConfig.Servers["db"].Env["DATABASE_URL"])
+
+	data, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, encrypted, string(data))
+
+	// Saving would replace the encrypted file with plaintext
+	err = cfg.SaveMCPConfig(mcpConfig)
+	assert.ErrorIs(t, err, ErrEncryptedConfig)
+	data, err = os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, encrypted, string(data))
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+
+	"github.com/tartavull/mcp-manager/internal/shellenv"
+)
+
+// InstanceEnv selects a named instance, e.g. one per project
+const InstanceEnv = "MCP_INSTANCE"
+
+// DefaultDaemonPort is the gRPC port of the default instance's daemon
+const DefaultDaemonPort = 8080
+
+// DefaultDaemonBindAddress keeps the daemon's APIs local unless the user
+// opts in
+const DefaultDaemonBindAddress = "127.0.0.1"
+
+const (
+	// instancePortStride separates the default ports of instances
+	instancePortStride = 100
+
+	// instancePortSlots is the number of strides instances are spread over
+	instancePortSlots = 30
+)
+
+// instanceName matches valid instance names, which are used in paths
+var instanceName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// ValidateInstance returns an error if name can't name an instance
+func ValidateInstance(name string) error {
+	if name != "" && !instanceName.MatchString(name) {
+		return fmt.Errorf("invalid instance name '%s': use letters, digits, - and _", name)
+	}
+	return nil
+}
+
+// PortOffset returns how far the default ports of an instance are moved
+// from those of the default instance: 0 for it, and a multiple of 100
+// derived from the name for the others, so instances don't collide unless
+// their names hash to the same slot
+func PortOffset(instance string) int {
+	if instance == "" {
+		return 0
+	}
+	h := fnv.New32a()
+	h.Write([]byte(instance))
+	return instancePortStride * (1 + int(h.Sum32()%instancePortSlots))
+}
+
+// DefaultBasePort returns the first port assigned to servers without one
+// when mcp.json doesn't set basePort
+func (c *Config) DefaultBasePort() int {
+	return MCPBasePort + PortOffset(c.Instance)
+}
+
+// DaemonPort returns the gRPC port of the instance's daemon: daemonPort of
+// mcp.json, or the instance's default
+func (c *Config) DaemonPort() int {
+	if mcpConfig, err := c.LoadMCPConfig(); err == nil && mcpConfig.DaemonPort != 0 {
+		return mcpConfig.DaemonPort
+	}
+	return DefaultDaemonPort + PortOffset(c.Instance)
+}
+
+// DaemonSocket returns the unix socket the instance's daemon listens on,
+// daemonSocket of mcp.json with ~ expanded, or empty to listen on its port
+func (c *Config) DaemonSocket() string {
+	if mcpConfig, err := c.LoadMCPConfig(); err == nil && mcpConfig.DaemonSocket != "" {
+		return shellenv.ExpandHome(mcpConfig.DaemonSocket)
+	}
+	return ""
+}
+
+// DaemonBindAddress returns the interface the instance's daemon listens
+// on: daemonBindAddress of mcp.json, or DefaultDaemonBindAddress
+func (c *Config) DaemonBindAddress() string {
+	if mcpConfig, err := c.LoadMCPConfig(); err == nil && mcpConfig.DaemonBindAddress != "" {
+		return mcpConfig.DaemonBindAddress
+	}
+	return DefaultDaemonBindAddress
+}
+
+// DaemonAddress returns the address clients reach the instance's daemon on
+func (c *Config) DaemonAddress() string {
+	if socket := c.DaemonSocket(); socket != "" {
+		return "unix://" + socket
+	}
+	return fmt.Sprintf("localhost:%d", c.DaemonPort())
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_Instance(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	for _, env := range []string{ConfigDirEnv, StateDirEnv, CacheDirEnv, "XDG_CONFIG_HOME", "XDG_STATE_HOME", "XDG_CACHE_HOME"} {
+		t.Setenv(env, "")
+	}
+
+	t.Setenv(InstanceEnv, "work")
+	config, err := New()
+	require.NoError(t, err)
+	assert.Equal(t, "work", config.Instance)
+	assert.Equal(t, filepath.Join(home, ".config", "mcp-manager", "instances", "work"), config.ConfigDir)
+	assert.Equal(t, filepath.Join(home, ".local", "state", "mcp-manager", "instances", "work"), config.StateDir)
+	assert.Equal(t, filepath.Join(home, ".cache", "mcp-manager", "instances", "work"), config.CacheDir)
+
+	// Explicit directories win
+	t.Setenv(ConfigDirEnv, filepath.Join(home, "work-config"))
+	config, err = New()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, "work-config"), config.ConfigDir)
+
+	t.Setenv(InstanceEnv, "../escape")
+	_, err = New()
+	assert.ErrorContains(t, err, "invalid instance name")
+}
+
+func TestPortOffset(t *testing.T) {
+	assert.Equal(t, 0, PortOffset(""))
+	for _, name := range []string{"work", "personal", "a"} {
+		offset := PortOffset(name)
+		assert.Positive(t, offset)
+		assert.LessOrEqual(t, offset, instancePortStride*instancePortSlots)
+		assert.Zero(t, offset%instancePortStride)
+		assert.Equal(t, offset, PortOffset(name))
+	}
+	assert.NotEqual(t, PortOffset("work"), PortOffset("personal"))
+}
+
+func TestInstancePorts(t *testing.T) {
+	work := &Config{Instance: "work", ConfigDir: t.TempDir()}
+	personal := &Config{Instance: "personal", ConfigDir: t.TempDir()}
+	require.NoError(t, os.WriteFile(work.GetMCPConfigPath(), []byte(`{"servers": {"a": {"command": "a"}}}`), 0644))
+	require.NoError(t, os.WriteFile(personal.GetMCPConfigPath(), []byte(`{"servers": {"a": {"command": "a"}}}`), 0644))
+
+	// Instances get their own default ports
+	workConfig, err := work.LoadMCPConfig()
+	require.NoError(t, err)
+	personalConfig, err := personal.LoadMCPConfig()
+	require.NoError(t, err)
+	assert.Equal(t, MCPBasePort+PortOffset("work"), workConfig.Servers["a"].Port)
+	assert.NotEqual(t, workConfig.Servers["a"].Port, personalConfig.Servers["a"].Port)
+	assert.Equal(t, DefaultDaemonPort+PortOffset("work"), work.DaemonPort())
+	assert.NotEqual(t, work.DaemonPort(), personal.DaemonPort())
+
+	// mcp.json sets them explicitly
+	require.NoError(t, os.WriteFile(work.GetMCPConfigPath(), []byte(`{"servers": {"a": {"command": "a"}}, "basePort": 5001, "daemonPort": 9090}`), 0644))
+	workConfig, err = work.LoadMCPConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 5001, workConfig.Servers["a"].Port)
+	assert.Equal(t, 9090, work.DaemonPort())
+	assert.Equal(t, "localhost:9090", work.DaemonAddress())
+	assert.Empty(t, work.DaemonSocket())
+
+	// A socket replaces the port
+	t.Setenv("HOME", "/home/me")
+	require.NoError(t, os.WriteFile(work.GetMCPConfigPath(), []byte(`{"servers": {}, "daemonPort": 9090, "daemonSocket": "~/.mcp-manager/work.sock"}`), 0644))
+	assert.Equal(t, "/home/me/.mcp-manager/work.sock", work.DaemonSocket())
+	assert.Equal(t, "unix:///home/me/.mcp-manager/work.sock", work.DaemonAddress())
+}
@@ -3,10 +3,14 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+
+	"github.com/tartavull/mcp-manager/internal/redact"
+	"github.com/tartavull/mcp-manager/internal/server"
 )
 
 // Base port for MCP servers
@@ -14,54 +18,561 @@ const MCPBasePort = 4001
 
 // MCPServerConfig represents a server configuration in mcp.json
 type MCPServerConfig struct {
-	Command     string `json:"command"`
-	Port        int    `json:"port,omitempty"` // Optional - will be auto-assigned if not specified
-	Description string `json:"description,omitempty"`
+	Command     string             `json:"command"`
+	Port        int                `json:"port,omitempty"` // Optional - will be auto-assigned if not specified
+	Description string             `json:"description,omitempty"`
+	Env         map[string]string  `json:"env,omitempty"` // Extra environment variables, e.g. API tokens
+	HealthCheck *HealthCheckConfig `json:"healthCheck,omitempty"`
+	Autostart   bool               `json:"autostart,omitempty"` // Start the server when the daemon starts
+	Tags        []string           `json:"tags,omitempty"`      // Labels policies select servers by, e.g. heavy
+
+	// RestartStrategy controls how config changes are applied to a running
+	// server: RestartBlueGreen or empty to stop and start it
+	RestartStrategy string `json:"restartStrategy,omitempty"`
+
+	// BrowserProfile names the browser profile of a browser-automation
+	// server, kept in the state directory and set as its user data dir
+	BrowserProfile string `json:"browserProfile,omitempty"`
+
+	// DataPaths lists the files and directories a server keeps its data
+	// in, e.g. a memory store or sqlite database, for backups. ${VAR}
+	// expands the server's env.
+	DataPaths []string `json:"dataPaths,omitempty"`
+
+	// Standby keeps a second, initialized instance of a slow-starting
+	// server ready to take over at once when the serving one crashes
+	Standby bool `json:"standby,omitempty"`
+
+	// ShadowCommand runs a second "shadow" instance, e.g. a newer version,
+	// that receives a copy of all tools/call traffic; divergent results are logged
+	ShadowCommand string `json:"shadowCommand,omitempty"`
+
+	// Middleware is the proxy middleware chain for the server, outermost first
+	Middleware []server.MiddlewareConfig `json:"middleware,omitempty"`
+
+	// ResultLimit truncates large tool results, e.g. screenshots or file dumps
+	ResultLimit *server.ResultLimit `json:"resultLimit,omitempty"`
+
+	// ReadOnly rejects calls to tools that may modify anything, e.g. when
+	// demoing agents against production-adjacent servers
+	ReadOnly *server.ReadOnly `json:"readOnly,omitempty"`
+
+	// Approval parks calls to sensitive tools until an operator approves them
+	Approval *ApprovalConfig `json:"approval,omitempty"`
+
+	// CircuitBreaker fails requests fast after consecutive failures of the
+	// server, instead of queuing each behind a timeout
+	CircuitBreaker *CircuitBreakerConfig `json:"circuitBreaker,omitempty"`
+
+	// Chaos injects latency, dropped responses and stdio restarts into the
+	// server's proxy, for testing clients' failure handling
+	Chaos *ChaosConfig `json:"chaos,omitempty"`
+
+	// RestartPolicy restarts the server when its process exits
+	RestartPolicy *RestartPolicyConfig `json:"restartPolicy,omitempty"`
+
+	// Hooks are shell commands run around the server's lifecycle
+	Hooks *HooksConfig `json:"hooks,omitempty"`
+
+	// Priority sets the CPU and I/O scheduling priority of the server's processes
+	Priority *PriorityConfig `json:"priority,omitempty"`
+
+	// Requires lists devices and paths that must be available to start the server
+	Requires *RequirementsConfig `json:"requires,omitempty"`
+
+	// Hosts maps host names to the addresses the server resolves them to,
+	// e.g. to point it at staging endpoints
+	Hosts map[string]string `json:"hosts,omitempty"`
+
+	// DNS lists the nameservers the server resolves names with
+	DNS []string `json:"dns,omitempty"`
+
+	// OutboundProxy replaces the default outbound proxy for the server
+	OutboundProxy *OutboundProxyConfig `json:"outboundProxy,omitempty"`
+
+	// Transport runs the server through a transport plugin, e.g. in a
+	// sandbox or on a remote peer
+	Transport *server.Transport `json:"transport,omitempty"`
+
+	// Instances expands the server into numbered instances, name-1 to
+	// name-N, substituting {{instance}} in their command, env and description
+	Instances int `json:"instances,omitempty"`
+
+	// Matrix expands the server into an instance per combination of the
+	// values of its parameters, substituting {{param}} for each
+	Matrix map[string][]string `json:"matrix,omitempty"`
+
+	// Group is the name of the server an instance was expanded from
+	Group string `json:"-"`
+}
+
+// RestartBlueGreen restarts a server by starting a second instance and
+// switching the proxy to it once ready, so the port never goes down
+const RestartBlueGreen = "blue-green"
+
+// HealthCheckConfig configures a custom health check run periodically while
+// the server is running. Exactly one of Command or URL should be set.
+type HealthCheckConfig struct {
+	Command  string `json:"command,omitempty"`  // Shell command; healthy when it exits 0
+	URL      string `json:"url,omitempty"`      // HTTP URL; healthy on a 2xx response
+	Interval string `json:"interval,omitempty"` // Duration between checks (default: 30s)
+	Timeout  string `json:"timeout,omitempty"`  // Duration of a single check (default: 10s)
+}
+
+// RestartPolicyConfig configures the automatic restart of a server whose
+// process exits
+type RestartPolicyConfig struct {
+	Policy      string `json:"policy"`                // never, on-failure or always
+	MaxRestarts int    `json:"maxRestarts,omitempty"` // Consecutive restarts before giving up (default: unlimited)
+	Backoff     string `json:"backoff,omitempty"`     // Delay before the first restart, doubled each time (default: 1s)
+	MaxBackoff  string `json:"maxBackoff,omitempty"`  // Cap on the delay between restarts (default: 1m)
+}
+
+// CircuitBreakerConfig configures the circuit breaker of a server's proxy
+type CircuitBreakerConfig struct {
+	Failures int    `json:"failures,omitempty"` // Consecutive failures opening the circuit (default: 5)
+	Cooldown string `json:"cooldown,omitempty"` // Duration the circuit stays open before a trial request (default: 30s)
+}
+
+// ApprovalConfig lists the tools of a server whose calls need an
+// operator's approval
+type ApprovalConfig struct {
+	Tools   []string `json:"tools"`             // Tools needing approval
+	Timeout string   `json:"timeout,omitempty"` // Duration before a call is rejected (default: 5m)
+}
+
+// ChaosConfig configures the faults injected into a server's proxy
+type ChaosConfig struct {
+	Latency     string   `json:"latency,omitempty"`     // Duration added to every call
+	Jitter      string   `json:"jitter,omitempty"`      // Random extra latency up to this duration
+	DropRate    float64  `json:"dropRate,omitempty"`    // Fraction of responses dropped, 0 to 1
+	RestartRate float64  `json:"restartRate,omitempty"` // Fraction of calls killing the stdio process first, 0 to 1
+	Methods     []string `json:"methods,omitempty"`     // Methods faults apply to (default: all)
+	Seed        int64    `json:"seed,omitempty"`        // Seed for a reproducible fault sequence
+}
+
+// HooksConfig configures shell commands run by the manager around a
+// server's lifecycle, with the server's environment
+type HooksConfig struct {
+	PreStart  string `json:"preStart,omitempty"`  // Before starting; a failure aborts the start
+	PostStart string `json:"postStart,omitempty"` // After the server started
+	PreStop   string `json:"preStop,omitempty"`   // Before stopping the server
+	PostStop  string `json:"postStop,omitempty"`  // After the server stopped
+	OnCrash   string `json:"onCrash,omitempty"`   // After the server exited unexpectedly
+	Timeout   string `json:"timeout,omitempty"`   // Duration of a single hook (default: 30s)
+}
+
+// PriorityConfig configures the scheduling priority a server is spawned with
+type PriorityConfig struct {
+	Nice    int    `json:"nice,omitempty"`    // -20 (highest) to 19 (lowest), as for nice(1)
+	IOClass string `json:"ioClass,omitempty"` // realtime, best-effort or idle, as for ionice(1); Linux only
+	IOLevel *int   `json:"ioLevel,omitempty"` // 0 (highest) to 7 (lowest) within realtime and best-effort
+}
+
+// RequirementsConfig declares what a server needs from the host, e.g. GPUs
+// for local-model servers or directories mounted into their containers
+type RequirementsConfig struct {
+	Devices []string `json:"devices,omitempty"` // Device files or glob patterns, e.g. /dev/nvidia*
+	Mounts  []string `json:"mounts,omitempty"`  // Files or directories that must exist
+}
+
+// FailoverConfig keeps a server running on one of several cluster hosts.
+// The coordinator starts it on the next host in order when the active one
+// fails.
+type FailoverConfig struct {
+	Hosts []string `json:"hosts"`          // Candidate hosts, preferred first
+	Port  int      `json:"port,omitempty"` // Coordinator port routing to the active host
+}
+
+// UpstreamConfig is another daemon whose servers a daemon serves as its
+// own, e.g. a laptop fronting a homelab
+type UpstreamConfig struct {
+	Address string `json:"address"` // gRPC address of the upstream daemon
+
+	// PortOffset forwards the upstream's proxies on the daemon's host, each
+	// on its port plus the offset (default: not forwarded)
+	PortOffset int `json:"portOffset,omitempty"`
+}
+
+// DaemonPinConfig pins the identity of a remote daemon, so clients refuse
+// to connect to anything presenting another
+type DaemonPinConfig struct {
+	// CertSHA256 is the SHA-256 fingerprint of the daemon's TLS
+	// certificate in hex, trusted without a CA
+	CertSHA256 string `json:"certSHA256,omitempty"`
+
+	// HostKey is the SSH host key of an ssh:// daemon's host, as listed in
+	// known_hosts, e.g. "ssh-ed25519 AAAAC3..."
+	HostKey string `json:"hostKey,omitempty"`
+}
+
+// GatewayConfig serves the tools of all running servers on one MCP
+// endpoint, resolving tools with the same name on several servers
+type GatewayConfig struct {
+	Port int `json:"port"` // Port of the aggregated endpoint
+
+	// PrefixAll names every tool "<prefix>_<tool>" instead of only those
+	// whose name is taken
+	PrefixAll bool `json:"prefixAll,omitempty"`
+
+	// Priority lists the servers that keep a conflicting tool name, first
+	// wins; other servers follow in mcp.json order
+	Priority []string `json:"priority,omitempty"`
+
+	// Servers sets the prefix and renames of each server's tools, keyed by
+	// server name
+	Servers map[string]*GatewayServerConfig `json:"servers,omitempty"`
+
+	// Routes send calls of a tool to another server than the one exposing
+	// it, or copy them there, e.g. to canary a new server; first match wins
+	Routes []GatewayRoute `json:"routes,omitempty"`
+
+	// FanOut sends the calls of read-only tools to several servers serving
+	// the same capability, e.g. two search providers, and combines their
+	// results
+	FanOut []GatewayFanOut `json:"fanOut,omitempty"`
+
+	// Providers exposes generic tools, e.g. "search.web", served by the
+	// healthiest of several servers providing the capability
+	Providers []GatewayProviders `json:"providers,omitempty"`
+
+	// MaxUploadMB is the size of the largest file uploaded through the
+	// /files endpoint (default: 64)
+	MaxUploadMB int `json:"maxUploadMB,omitempty"`
+}
+
+// GatewayFanOut calls a gateway tool on several servers at once
+type GatewayFanOut struct {
+	Tool    string            `json:"tool"`              // Gateway name of the tool
+	Servers []string          `json:"servers"`           // Servers called, besides the one exposing the tool
+	Tools   map[string]string `json:"tools,omitempty"`   // Server to its name of the tool, when it differs
+	Policy  string            `json:"policy,omitempty"`  // "fastest" (default) or "merge"
+	Timeout string            `json:"timeout,omitempty"` // Longest wait for the servers (default: 10s)
+}
+
+// GatewayProviders is a generic gateway tool served by one of several
+// servers at a time
+type GatewayProviders struct {
+	Tool      string            `json:"tool"`          // Gateway name of the generic tool
+	Providers map[string]string `json:"providers"`     // Server to its tool serving the generic one
+	Pin       string            `json:"pin,omitempty"` // Server always serving the tool while running
+}
+
+// GatewayRoute sends some calls of a gateway tool to another server, which
+// is called with the tool's name on the server exposing it
+type GatewayRoute struct {
+	Tool    string  `json:"tool"`              // Gateway name of the tool
+	Profile string  `json:"profile,omitempty"` // Only calls of clients with this profile (default: all)
+	Server  string  `json:"server"`            // Server receiving the calls
+	Percent float64 `json:"percent,omitempty"` // Share of the calls routed (default: 100)
+	Shadow  bool    `json:"shadow,omitempty"`  // Copy the calls instead, answering from the usual server
+}
+
+// GatewayServerConfig names a server's tools in the gateway. Servers with
+// instances are served as one, keyed by the name of their entry.
+type GatewayServerConfig struct {
+	Prefix string            `json:"prefix,omitempty"` // Prefix of the server's tools (default: the server name)
+	Rename map[string]string `json:"rename,omitempty"` // Tool name to the name exposed by the gateway
+
+	// Balance spreads the calls of a server with instances over them:
+	// "round-robin" (default), "least-in-flight" or "latency"
+	Balance string `json:"balance,omitempty"`
+}
+
+// DiscoveryConfig advertises running proxies so other machines can find
+// them without hard-coded URLs
+type DiscoveryConfig struct {
+	MDNS   bool          `json:"mdns,omitempty"` // Advertise as _mcp._tcp.local. services
+	Consul *ConsulConfig `json:"consul,omitempty"`
+}
+
+// ConsulConfig registers running proxies as Consul services with HTTP
+// health checks against the proxy
+type ConsulConfig struct {
+	Address        string `json:"address,omitempty"`        // Consul agent URL (default: http://127.0.0.1:8500)
+	Token          string `json:"token,omitempty"`          // ACL token
+	CheckInterval  string `json:"checkInterval,omitempty"`  // Duration between health checks (default: 10s)
+	ServiceAddress string `json:"serviceAddress,omitempty"` // Address other machines reach the proxies on (default: the agent's)
+}
+
+// EventExportConfig forwards daemon events to a message bus topic per
+// event type
+type EventExportConfig struct {
+	Type     string `json:"type"`               // nats, redis, mqtt or plugin
+	Address  string `json:"address,omitempty"`  // Broker host:port (default: localhost and the protocol's port)
+	Username string `json:"username,omitempty"` // Credentials, if the broker requires them
+	Password string `json:"password,omitempty"`
+
+	// Plugin is the notifier plugin of the plugin type, passed Config
+	Plugin string          `json:"plugin,omitempty"`
+	Config json.RawMessage `json:"config,omitempty"`
+
+	// Topics maps event types (server_status, tool_update, config_change,
+	// failover, or * for the rest) to topics; {server} and {type} are
+	// replaced by the event's server and type. Unmapped events aren't sent.
+	Topics map[string]string `json:"topics"`
+}
+
+// EventJournalConfig bounds the journal of daemon events kept for the
+// QueryEvents API
+type EventJournalConfig struct {
+	Disabled bool   `json:"disabled,omitempty"`
+	MaxSize  int64  `json:"maxSize,omitempty"` // Disk space in bytes (default: 64MB)
+	MaxAge   string `json:"maxAge,omitempty"`  // Duration events are kept (default: 168h)
+}
+
+// EventMonitorConfig tunes how the daemon detects the status and tool
+// changes of servers it broadcasts
+type EventMonitorConfig struct {
+	Interval     string `json:"interval,omitempty"`     // Between status checks (default: 2s)
+	ToolInterval string `json:"toolInterval,omitempty"` // Between refreshes of the servers' tools (default: 30s)
+	Workers      int    `json:"workers,omitempty"`      // Broadcasts running at once (default: 4)
+}
+
+// AuthConfig authenticates the clients of the daemon's gRPC API, the
+// gateway and the HTTP proxies
+type AuthConfig struct {
+	// Providers are the ways clients authenticate, keyed by name
+	Providers map[string]*AuthProviderConfig `json:"providers"`
+
+	// Daemon, Gateway and Proxies list the providers each layer accepts,
+	// any of which authenticates a client; empty leaves the layer open
+	Daemon  []string `json:"daemon,omitempty"`
+	Gateway []string `json:"gateway,omitempty"`
+	Proxies []string `json:"proxies,omitempty"`
+
+	// TLS serves the daemon and the gateway over TLS, needed by mtls
+	// providers
+	TLS *TLSConfig `json:"tls,omitempty"`
+}
+
+// AuthProviderConfig configures a way clients authenticate
+type AuthProviderConfig struct {
+	Type string `json:"type"` // token, oidc, jwt or mtls
+
+	// Tokens are the bearer tokens of a token provider, keyed by the name
+	// of their client
+	Tokens map[string]string `json:"tokens,omitempty"`
+
+	// Issuer is the OpenID Connect issuer whose tokens an oidc provider
+	// accepts, its keys discovered from the issuer's metadata
+	Issuer string `json:"issuer,omitempty"`
+
+	// JWKSURL serves the keys signing the tokens of a jwt provider, e.g.
+	// https://sso.example.com/keys (oidc: discovered)
+	JWKSURL string `json:"jwksURL,omitempty"`
+
+	Audience     string            `json:"audience,omitempty"`     // Required aud claim
+	SubjectClaim string            `json:"subjectClaim,omitempty"` // Claim naming the client (default: sub)
+	Claims       map[string]string `json:"claims,omitempty"`       // Required claim values, e.g. {"groups": "mcp"}
+
+	// Subjects lists the certificate names an mtls provider accepts;
+	// empty accepts any certificate signed by the client CA
+	Subjects []string `json:"subjects,omitempty"`
+}
+
+// TLSConfig serves a listener over TLS
+type TLSConfig struct {
+	Cert     string `json:"cert"`               // PEM certificate file
+	Key      string `json:"key"`                // PEM private key file
+	ClientCA string `json:"clientCA,omitempty"` // PEM CAs verifying client certificates
+
+	// RequireClientCert rejects API clients without a certificate signed
+	// by ClientCA during the handshake, whatever providers the daemon
+	// accepts. The gateway still accepts clients without one.
+	RequireClientCert bool `json:"requireClientCert,omitempty"`
+}
+
+// SecretsConfig selects where secrets are stored. Server env values of
+// the form secret:<name> are replaced by the secret when servers start.
+type SecretsConfig struct {
+	Backend string `json:"backend,omitempty"` // keychain, file or plugin (default: keychain if available, else file)
+
+	// Plugin is the secrets plugin of the plugin backend, passed Config
+	Plugin string          `json:"plugin,omitempty"`
+	Config json.RawMessage `json:"config,omitempty"`
+
+	// File is a SOPS- or age-encrypted JSON or YAML file of secrets, e.g.
+	// in a dotfiles repo, read instead of the backend. Nested keys are
+	// joined with '/', e.g. github/GITHUB_TOKEN. Relative to mcp.json.
+	File string `json:"file,omitempty"`
+}
+
+// ProvenanceConfig sets what servers installed from catalogs or imported
+// from other configs must prove about their origin. The built-in catalog
+// ships with the verified release, so it counts as signed.
+type ProvenanceConfig struct {
+	// TrustedKeys are base64 Ed25519 public keys, signing catalogs and
+	// configs in a <file>.sig next to them
+	TrustedKeys []string `json:"trustedKeys,omitempty"`
+
+	RequireSigned bool `json:"requireSigned,omitempty"` // Refuse entries not signed by a trusted key
+	BlockUnpinned bool `json:"blockUnpinned,omitempty"` // Refuse commands fetching packages without a pinned version
+}
+
+// OutboundProxyConfig sets the proxy environment variables of server
+// commands, including the npm ones used by npx downloads
+type OutboundProxyConfig struct {
+	HTTP    string   `json:"http,omitempty"`    // Proxy URL for HTTP requests
+	HTTPS   string   `json:"https,omitempty"`   // Proxy URL for HTTPS requests
+	SOCKS   string   `json:"socks,omitempty"`   // Proxy URL for all protocols, e.g. socks5://localhost:1080
+	NoProxy []string `json:"noProxy,omitempty"` // Hosts, domains or CIDRs reached directly
+}
+
+// Env returns the environment variables applying the proxy settings: nil
+// if c is nil, and empty if c sets nothing, e.g. to bypass the default
+func (c *OutboundProxyConfig) Env() []string {
+	if c == nil {
+		return nil
+	}
+
+	env := []string{}
+	add := func(value string, keys ...string) {
+		if value == "" {
+			return
+		}
+		for _, key := range keys {
+			env = append(env, key+"="+value)
+		}
+	}
+	add(c.HTTP, "HTTP_PROXY", "http_proxy", "npm_config_proxy")
+	add(c.HTTPS, "HTTPS_PROXY", "https_proxy", "npm_config_https_proxy")
+	add(c.SOCKS, "ALL_PROXY", "all_proxy")
+	add(strings.Join(c.NoProxy, ","), "NO_PROXY", "no_proxy", "npm_config_noproxy")
+	return env
+}
+
+// OutboundProxyFor returns the outbound proxy of a server: its own, or the
+// default
+func (c *MCPConfig) OutboundProxyFor(name string) *OutboundProxyConfig {
+	if srv, exists := c.Servers[name]; exists && srv.OutboundProxy != nil {
+		return srv.OutboundProxy
+	}
+	return c.OutboundProxy
+}
+
+// PowerConfig stops the servers tagged heavy when the battery runs low,
+// and starts them again on AC power
+type PowerConfig struct {
+	Threshold int    `json:"threshold,omitempty"` // Battery percentage at or below which servers stop (default: 100, any time on battery)
+	Interval  string `json:"interval,omitempty"`  // Duration between battery checks (default: 1m)
+	Notify    bool   `json:"notify,omitempty"`    // Show desktop notifications when servers are stopped or resumed
+}
+
+// MCPSettings holds the top-level settings of mcp.json that apply to all servers
+type MCPSettings struct {
+	// ShellEnv sources the user's login shell environment for server commands,
+	// needed when the daemon is launched by launchd/systemd without the user's PATH
+	ShellEnv bool `json:"shellEnv,omitempty"`
+
+	// Path lists directories prepended to PATH for server commands
+	Path []string `json:"path,omitempty"`
+
+	// BindAddress is the interface the HTTP proxies listen on (default: all interfaces)
+	BindAddress string `json:"bindAddress,omitempty"`
+
+	// Redaction lists rules masking secrets in payloads before they are
+	// logged or recorded, in addition to the built-in secret field names
+	Redaction []redact.Rule `json:"redaction,omitempty"`
+
+	// Failover lists servers a cluster coordinator keeps running on one of
+	// several hosts, keyed by server name
+	Failover map[string]*FailoverConfig `json:"failover,omitempty"`
+
+	// Upstreams lists daemons whose servers are served alongside the local
+	// ones, named "<upstream>/<server>", keyed by upstream name
+	Upstreams map[string]*UpstreamConfig `json:"upstreams,omitempty"`
+
+	// DaemonPins pins what remote daemons present to clients, keyed by the
+	// address clients are given, e.g. "hub:8443" or "ssh://me@gpu-box"
+	DaemonPins map[string]*DaemonPinConfig `json:"daemonPins,omitempty"`
+
+	// Gateway serves the tools of all running servers on one endpoint
+	Gateway *GatewayConfig `json:"gateway,omitempty"`
+
+	// Auth authenticates the clients of the daemon, gateway and proxies
+	Auth *AuthConfig `json:"auth,omitempty"`
+
+	// Secrets selects where the secrets server env references are stored
+	Secrets *SecretsConfig `json:"secrets,omitempty"`
+
+	// Provenance checks servers installed from catalogs or imported configs
+	Provenance *ProvenanceConfig `json:"provenance,omitempty"`
+
+	// CORSOrigins lists the browser origins allowed to call the HTTP proxies
+	// and the daemon's gRPC-Web and HTTP APIs (default: any origin for the
+	// proxies, only loopback origins for the daemon)
+	CORSOrigins []string `json:"corsOrigins,omitempty"`
+
+	// Discovery advertises running proxies on the network
+	Discovery *DiscoveryConfig `json:"discovery,omitempty"`
+
+	// EventExport forwards daemon events to message buses
+	EventExport []EventExportConfig `json:"eventExport,omitempty"`
+
+	// EventJournal bounds the daemon's journal of past events
+	EventJournal *EventJournalConfig `json:"eventJournal,omitempty"`
+
+	// EventMonitor tunes the detection of status and tool changes
+	EventMonitor *EventMonitorConfig `json:"eventMonitor,omitempty"`
+
+	// Power stops heavy servers while a laptop runs low on battery
+	Power *PowerConfig `json:"power,omitempty"`
+
+	// Notifications is the level of desktop notifications of server events
+	// for servers without their own: mute, crash, tools or all (default:
+	// mute)
+	Notifications string `json:"notifications,omitempty"`
+
+	// OutboundProxy is the proxy server commands reach external APIs and
+	// package registries through, unless a server sets its own
+	OutboundProxy *OutboundProxyConfig `json:"outboundProxy,omitempty"`
+
+	// BasePort is the first port assigned to servers without one
+	// (default: 4001, moved for named instances)
+	BasePort int `json:"basePort,omitempty"`
+
+	// DaemonPort is the gRPC port of the daemon (default: 8080, moved for
+	// named instances)
+	DaemonPort int `json:"daemonPort,omitempty"`
+
+	// DaemonSocket is a unix socket the daemon listens on instead of
+	// DaemonPort, e.g. ~/.mcp-manager/daemon.sock
+	DaemonSocket string `json:"daemonSocket,omitempty"`
+
+	// DaemonBindAddress is the interface the daemon's gRPC, gRPC-Web and
+	// HTTP APIs listen on (default: 127.0.0.1, 0.0.0.0 for all interfaces)
+	DaemonBindAddress string `json:"daemonBindAddress,omitempty"`
+
+	// Locale is the language of the TUI and CLI, e.g. "es" (default: the
+	// system's, unless MCP_MANAGER_LANG sets another)
+	Locale string `json:"locale,omitempty"`
 }
 
 // MCPConfig represents the full mcp.json configuration
 type MCPConfig struct {
-	Servers     map[string]*MCPServerConfig `json:"servers"`
-	ServerOrder []string                    `json:"-"` // Not serialized, stores JSON order
+	Version int                         `json:"version,omitempty"` // Schema version, see CurrentVersion
+	Servers map[string]*MCPServerConfig `json:"servers"`
+	MCPSettings
+	ServerOrder []string `json:"-"` // Not serialized, stores JSON order
+	Encryption  string   `json:"-"` // How mcp.json is encrypted, empty if it isn't
 }
 
 // LoadMCPConfig loads the MCP configuration from mcp.json
 func (c *Config) LoadMCPConfig() (*MCPConfig, error) {
 	filePath := filepath.Join(c.ConfigDir, "mcp.json")
 
-	// If file doesn't exist, return built-in defaults (don't save)
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		defaultConfig := &MCPConfig{
-			Servers: map[string]*MCPServerConfig{
-				"playwright": {
-					Command:     "npx @playwright/mcp@latest",
-					Description: "Browser automation, screenshots, web interaction",
-				},
-				"filesystem": {
-					Command:     "npx @modelcontextprotocol/server-filesystem@latest /tmp",
-					Description: "File system operations (read/write/create/delete)",
-				},
-				"postgres": {
-					Command:     "npx @modelcontextprotocol/server-postgres@latest postgresql://localhost/mydb",
-					Description: "PostgreSQL database operations and queries",
-				},
-				"github": {
-					Command:     "npx @modelcontextprotocol/server-github@latest",
-					Description: "GitHub repository and issue management",
-				},
-				"sequential-thinking": {
-					Command:     "npx @modelcontextprotocol/server-sequential-thinking@latest",
-					Description: "Structured problem-solving with reasoning paths",
-				},
-			},
-			// Set default order
-			ServerOrder: []string{"playwright", "filesystem", "postgres", "github", "sequential-thinking"},
+	// If file doesn't exist, migrate servers.json if there is one
+	_, err := os.Stat(filePath)
+	if os.IsNotExist(err) {
+		if data, err := os.ReadFile(c.getServersFilePath()); err == nil {
+			return c.loadServersFile(data)
 		}
+	}
 
-		// Assign sequential ports to default config
-		c.assignSequentialPortsWithOrder(defaultConfig)
-
-		return defaultConfig, nil
+	// Otherwise there are no servers yet: the wizard or the user adds them
+	if os.IsNotExist(err) {
+		return &MCPConfig{Servers: map[string]*MCPServerConfig{}, Version: CurrentVersion}, nil
 	}
 
 	// Read existing file
@@ -70,6 +581,18 @@ func (c *Config) LoadMCPConfig() (*MCPConfig, error) {
 		return nil, fmt.Errorf("failed to read MCP config: %w", err)
 	}
 
+	// Decrypt configs kept encrypted, e.g. in a dotfiles repo
+	data, encryption, err := Decrypt(filePath, data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Upgrade files written by older versions
+	data, version, err := migrateMCPConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate MCP config: %w", err)
+	}
+
 	var config MCPConfig
 	if err := json.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal MCP config: %w", err)
@@ -77,6 +600,16 @@ func (c *Config) LoadMCPConfig() (*MCPConfig, error) {
 
 	// Extract server order from JSON
 	config.ServerOrder = c.extractServerOrder(data)
+	config.Encryption = encryption
+
+	// Write the migrated file back before ports are assigned, so servers
+	// without one keep following the order. Encrypted files are migrated
+	// in memory only, rather than written back in plaintext.
+	if version < CurrentVersion && encryption == "" {
+		if err := c.saveMigrated(&config, version); err != nil {
+			log.Printf("Warning: failed to save migrated MCP config: %v", err)
+		}
+	}
 
 	// Assign sequential ports to any servers without ports
 	c.assignSequentialPortsWithOrder(&config)
@@ -84,6 +617,22 @@ func (c *Config) LoadMCPConfig() (*MCPConfig, error) {
 	return &config, nil
 }
 
+// ParseMCPConfig parses the content of an mcp.json from elsewhere, e.g.
+// to import its servers, upgrading it if it's from an older version.
+// Ports aren't assigned.
+func ParseMCPConfig(data []byte) (*MCPConfig, error) {
+	data, _, err := migrateMCPConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate MCP config: %w", err)
+	}
+	var config MCPConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal MCP config: %w", err)
+	}
+	config.ServerOrder = (&Config{}).extractServerOrder(data)
+	return &config, nil
+}
+
 // extractServerOrder extracts the order of servers from raw JSON data
 func (c *Config) extractServerOrder(data []byte) []string {
 	var orderedKeys []string
@@ -125,15 +674,19 @@ func (c *Config) assignSequentialPortsWithOrder(config *MCPConfig) {
 	}
 
 	// Assign ports sequentially
-	nextPort := MCPBasePort
+	nextPort := config.BasePort
+	if nextPort == 0 {
+		nextPort = c.DefaultBasePort()
+	}
 	for _, name := range orderedKeys {
+		// Templates reserve a port for each instance
 		if srv, exists := config.Servers[name]; exists && srv.Port == 0 {
 			srv.Port = nextPort
-			nextPort++
+			nextPort += srv.InstanceCount()
 		} else if exists && srv.Port != 0 {
 			// Keep track of highest used port
-			if srv.Port >= nextPort {
-				nextPort = srv.Port + 1
+			if last := srv.Port + srv.InstanceCount() - 1; last >= nextPort {
+				nextPort = last + 1
 			}
 		}
 	}
@@ -144,12 +697,16 @@ func (c *Config) assignSequentialPorts(config *MCPConfig) {
 	c.assignSequentialPortsWithOrder(config)
 }
 
-// SaveMCPConfig saves the MCP configuration to mcp.json
+// SaveMCPConfig saves the MCP configuration to mcp.json, refusing to
+// replace an encrypted one
 func (c *Config) SaveMCPConfig(config *MCPConfig) error {
 	filePath := filepath.Join(c.ConfigDir, "mcp.json")
+	if err := checkNotEncrypted(filePath); err != nil {
+		return err
+	}
 
 	// Create ordered JSON to preserve server order
-	orderedJSON := "{\n  \"servers\": {\n"
+	orderedJSON := fmt.Sprintf("{\n  \"version\": %d,\n  \"servers\": {\n", CurrentVersion)
 
 	// Write servers in the specified order
 	for i, name := range config.ServerOrder {
@@ -169,9 +726,22 @@ func (c *Config) SaveMCPConfig(config *MCPConfig) error {
 		}
 	}
 
-	orderedJSON += "  }\n}"
+	orderedJSON += "  }"
 
-	if err := os.WriteFile(filePath, []byte(orderedJSON), 0644); err != nil {
+	// Append top-level settings after the servers
+	settingsJSON, err := json.MarshalIndent(config.MCPSettings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal settings: %w", err)
+	}
+	if settings := strings.TrimSpace(string(settingsJSON)); settings != "{}" {
+		settings = strings.TrimSuffix(strings.TrimPrefix(settings, "{"), "}")
+		orderedJSON += "," + strings.TrimRight(settings, "\n")
+	}
+
+	orderedJSON += "\n}"
+
+	// Server env may hold API tokens, so keep the file private
+	if err := os.WriteFile(filePath, []byte(orderedJSON), 0600); err != nil {
 		return fmt.Errorf("failed to write MCP config: %w", err)
 	}
 
@@ -182,3 +752,57 @@ func (c *Config) SaveMCPConfig(config *MCPConfig) error {
 func (c *Config) GetMCPConfigPath() string {
 	return filepath.Join(c.ConfigDir, "mcp.json")
 }
+
+// GetSecretsPath returns the path of the encrypted secrets file, used
+// when there is no OS keychain
+func (c *Config) GetSecretsPath() string {
+	return filepath.Join(c.ConfigDir, "secrets.enc")
+}
+
+// GetPluginDir returns the directory plugins are discovered in
+func (c *Config) GetPluginDir() string {
+	return filepath.Join(c.ConfigDir, "plugins")
+}
+
+// GetScriptDir returns the directory scripts reacting to events are
+// loaded from
+func (c *Config) GetScriptDir() string {
+	return filepath.Join(c.ConfigDir, "scripts")
+}
+
+// GetDocsDir returns the directory of the generated tool documentation the
+// daemon serves
+func (c *Config) GetDocsDir() string {
+	return filepath.Join(c.GetStateDir(), "docs")
+}
+
+// Locale returns the locale mcp.json sets, or "" if it sets none or can't
+// be read. Unlike LoadMCPConfig, it neither migrates the file nor decrypts
+// it, as every command reads the locale before printing anything.
+func (c *Config) Locale() string {
+	data, err := os.ReadFile(c.GetMCPConfigPath())
+	if err != nil || Encryption(data) != "" {
+		return ""
+	}
+	var settings struct {
+		Locale string `json:"locale"`
+	}
+	json.Unmarshal(data, &settings)
+	return settings.Locale
+}
+
+// DaemonPin returns the pin of the daemon at address, or nil if it has
+// none
+func (c *Config) DaemonPin(address string) (*DaemonPinConfig, error) {
+	mcpConfig, err := c.LoadMCPConfig()
+	if err != nil {
+		return nil, err
+	}
+	return mcpConfig.DaemonPins[address], nil
+}
+
+// MCPConfigExists returns true if mcp.json has been created
+func (c *Config) MCPConfigExists() bool {
+	_, err := os.Stat(c.GetMCPConfigPath())
+	return err == nil
+}
@@ -50,6 +50,18 @@ func TestMCPConfigPreservesJSONOrder(t *testing.T) {
 	assert.Equal(t, 4003, mcpConfig.Servers["beta"].Port, "beta should get port 4003 (third)")
 }
 
+func TestLoadMCPConfigMissing(t *testing.T) {
+	cfg := &Config{ConfigDir: t.TempDir()}
+
+	// No built-in servers are made up when there's no mcp.json
+	mcpConfig, err := cfg.LoadMCPConfig()
+	require.NoError(t, err)
+	assert.Empty(t, mcpConfig.Servers)
+	assert.Empty(t, mcpConfig.ServerOrder)
+	assert.Equal(t, CurrentVersion, mcpConfig.Version)
+	assert.False(t, cfg.MCPConfigExists())
+}
+
 func TestGetOrderedServerNames(t *testing.T) {
 	// Create a temporary directory for test
 	tempDir := t.TempDir()
@@ -91,3 +103,76 @@ func TestGetOrderedServerNames(t *testing.T) {
 	assert.Equal(t, "first", orderedNames[1], "Second server in JSON should be second")
 	assert.Equal(t, "second", orderedNames[2], "Third server in JSON should be third")
 }
+
+func TestMCPConfigSettingsRoundTrip(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &Config{ConfigDir: tempDir}
+
+	original := &MCPConfig{
+		Servers: map[string]*MCPServerConfig{
+			"alpha": {Command: "echo alpha", Port: 4001},
+		},
+		MCPSettings: MCPSettings{
+			ShellEnv: true,
+			Path:     []string{"/opt/homebrew/bin", "~/.local/bin"},
+		},
+		ServerOrder: []string{"alpha"},
+	}
+	require.NoError(t, cfg.SaveMCPConfig(original))
+
+	loaded, err := cfg.LoadMCPConfig()
+	require.NoError(t, err)
+	assert.True(t, loaded.ShellEnv)
+	assert.Equal(t, []string{"/opt/homebrew/bin", "~/.local/bin"}, loaded.Path)
+	assert.Equal(t, "echo alpha", loaded.Servers["alpha"].Command)
+	assert.Equal(t, []string{"alpha"}, loaded.ServerOrder)
+}
+
+func TestLocale(t *testing.T) {
+	cfg := &Config{ConfigDir: t.TempDir()}
+	assert.Empty(t, cfg.Locale())
+
+	require.NoError(t, cfg.SaveMCPConfig(&MCPConfig{
+		Servers:     map[string]*MCPServerConfig{},
+		MCPSettings: MCPSettings{Locale: "es"},
+	}))
+	assert.Equal(t, "es", cfg.Locale())
+}
+
+func TestDaemonPin(t *testing.T) {
+	cfg := &Config{ConfigDir: t.TempDir()}
+	pin, err := cfg.DaemonPin("hub:8443")
+	require.NoError(t, err)
+	assert.Nil(t, pin)
+
+	require.NoError(t, os.WriteFile(cfg.GetMCPConfigPath(), []byte(`{
+  "servers": {},
+  "daemonPins": {"hub:8443": {"certSHA256": "ab:cd"}}
+}`), 0644))
+	pin, err = cfg.DaemonPin("hub:8443")
+	require.NoError(t, err)
+	assert.Equal(t, &DaemonPinConfig{CertSHA256: "ab:cd"}, pin)
+	pin, err = cfg.DaemonPin("ssh://me@gpu-box")
+	require.NoError(t, err)
+	assert.Nil(t, pin)
+}
+
+func TestOutboundProxyConfig(t *testing.T) {
+	var nilProxy *OutboundProxyConfig
+	assert.Nil(t, nilProxy.Env())
+
+	proxy := &OutboundProxyConfig{HTTPS: "http://proxy:3128", NoProxy: []string{"localhost", ".internal"}}
+	assert.Equal(t, []string{
+		"HTTPS_PROXY=http://proxy:3128", "https_proxy=http://proxy:3128", "npm_config_https_proxy=http://proxy:3128",
+		"NO_PROXY=localhost,.internal", "no_proxy=localhost,.internal", "npm_config_noproxy=localhost,.internal",
+	}, proxy.Env())
+
+	mcpConfig := &MCPConfig{Servers: map[string]*MCPServerConfig{
+		"github":   {Command: "npx github"},
+		"internal": {Command: "npx internal", OutboundProxy: &OutboundProxyConfig{}},
+	}}
+	mcpConfig.OutboundProxy = proxy
+	assert.Same(t, proxy, mcpConfig.OutboundProxyFor("github"))
+	assert.NotNil(t, mcpConfig.OutboundProxyFor("internal").Env())
+	assert.Empty(t, mcpConfig.OutboundProxyFor("internal").Env())
+}
@@ -0,0 +1,289 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+)
+
+// CurrentVersion is the version of the mcp.json schema this build reads
+// and writes. Older files are migrated on load.
+//
+//	0: servers.json, a map of server names to servers with runtime state
+//	1: mcp.json without a version, possibly using the legacy mcpServers
+//	   key and args lists of other MCP clients
+//	2: mcp.json with a version
+const CurrentVersion = 2
+
+// migration upgrades a config document from one version to the next
+type migration struct {
+	description string
+	migrate     func(doc map[string]json.RawMessage) (map[string]json.RawMessage, error)
+}
+
+// migrations upgrade version i to i+1
+var migrations = []migration{
+	{"convert servers.json to mcp.json", migrateServersFile},
+	{"rename mcpServers to servers and join args into commands", migrateLegacyNames},
+}
+
+// configVersion returns the version of an mcp.json document, 1 for
+// documents written before versioning
+func configVersion(doc map[string]json.RawMessage) (int, error) {
+	raw, exists := doc["version"]
+	if !exists {
+		return 1, nil
+	}
+	var version int
+	if err := json.Unmarshal(raw, &version); err != nil || version < 1 {
+		return 0, fmt.Errorf("invalid version %s", raw)
+	}
+	return version, nil
+}
+
+// migrateMCPConfig upgrades an mcp.json document to CurrentVersion,
+// returning it with the version it had
+func migrateMCPConfig(data []byte) ([]byte, int, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, 0, err
+	}
+	version, err := configVersion(doc)
+	if err != nil || version == CurrentVersion {
+		return data, version, err
+	}
+	data, err = migrate(data, version)
+	return data, version, err
+}
+
+// migrate upgrades a config document of the given version to
+// CurrentVersion, returning it as JSON
+func migrate(data []byte, version int) ([]byte, error) {
+	if version > CurrentVersion {
+		return nil, fmt.Errorf("version %d is newer than the supported %d, upgrade mcp-manager", version, CurrentVersion)
+	}
+
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	for ; version < CurrentVersion; version++ {
+		var err error
+		log.Printf("Migrating config from version %d: %s", version, migrations[version].description)
+		if doc, err = migrations[version].migrate(doc); err != nil {
+			return nil, fmt.Errorf("failed to migrate from version %d: %w", version, err)
+		}
+	}
+
+	version = CurrentVersion
+	doc["version"], _ = json.Marshal(version)
+	return json.Marshal(doc)
+}
+
+// migrateServersFile converts servers.json, keeping the declarative fields
+// of its servers in port order
+func migrateServersFile(doc map[string]json.RawMessage) (map[string]json.RawMessage, error) {
+	type legacyServer struct {
+		Command     string `json:"command"`
+		Port        int    `json:"port,omitempty"`
+		Description string `json:"description,omitempty"`
+	}
+
+	servers := make(map[string]legacyServer, len(doc))
+	names := make([]string, 0, len(doc))
+	for name, raw := range doc {
+		var srv legacyServer
+		if err := json.Unmarshal(raw, &srv); err != nil {
+			return nil, fmt.Errorf("server '%s': %w", name, err)
+		}
+		servers[name] = srv
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if servers[names[i]].Port != servers[names[j]].Port {
+			return servers[names[i]].Port < servers[names[j]].Port
+		}
+		return names[i] < names[j]
+	})
+
+	var out orderedObject
+	for _, name := range names {
+		raw, err := json.Marshal(servers[name])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, member{name, raw})
+	}
+	raw, err := out.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]json.RawMessage{"servers": raw}, nil
+}
+
+// migrateLegacyNames accepts the format of other MCP clients: servers under
+// mcpServers, with their arguments in an args list
+func migrateLegacyNames(doc map[string]json.RawMessage) (map[string]json.RawMessage, error) {
+	if legacy, exists := doc["mcpServers"]; exists {
+		if _, exists := doc["servers"]; exists {
+			return nil, fmt.Errorf("both servers and mcpServers are set")
+		}
+		doc["servers"] = legacy
+		delete(doc, "mcpServers")
+	}
+
+	raw, exists := doc["servers"]
+	if !exists || string(raw) == "null" {
+		return doc, nil
+	}
+	servers, err := decodeOrdered(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid servers: %w", err)
+	}
+
+	for i, m := range servers {
+		srv, err := decodeOrdered(m.value)
+		if err != nil {
+			return nil, fmt.Errorf("server '%s': %w", m.key, err)
+		}
+		argsIndex := srv.index("args")
+		if argsIndex < 0 {
+			continue
+		}
+
+		var args []string
+		if err := json.Unmarshal(srv[argsIndex].value, &args); err != nil {
+			return nil, fmt.Errorf("server '%s': invalid args: %w", m.key, err)
+		}
+		var command string
+		if commandIndex := srv.index("command"); commandIndex >= 0 {
+			json.Unmarshal(srv[commandIndex].value, &command)
+		}
+		words := []string{command}
+		for _, arg := range args {
+			words = append(words, shellWord(arg))
+		}
+		joined, _ := json.Marshal(strings.Join(words, " "))
+
+		srv = append(srv[:argsIndex], srv[argsIndex+1:]...)
+		if commandIndex := srv.index("command"); commandIndex >= 0 {
+			srv[commandIndex].value = joined
+		} else {
+			srv = append(orderedObject{{"command", joined}}, srv...)
+		}
+		if servers[i].value, err = srv.MarshalJSON(); err != nil {
+			return nil, err
+		}
+	}
+
+	if doc["servers"], err = servers.MarshalJSON(); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// shellWord quotes an argument for sh, unless it needs no quoting
+func shellWord(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("@%+=:,./_-", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// member is a key and value of a JSON object
+type member struct {
+	key   string
+	value json.RawMessage
+}
+
+// orderedObject is a JSON object keeping the order of its members
+type orderedObject []member
+
+// decodeOrdered decodes a JSON object, keeping the order of its members
+func decodeOrdered(data []byte) (orderedObject, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, fmt.Errorf("expected an object")
+	}
+
+	var object orderedObject
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+		object = append(object, member{token.(string), value})
+	}
+	return object, nil
+}
+
+// index returns the position of key, or -1
+func (o orderedObject) index(key string) int {
+	for i, m := range o {
+		if m.key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// MarshalJSON encodes the object with its members in order
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, err := json.Marshal(m.key)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(m.value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// saveMigrated writes a config migrated from an older version to
+// mcp.json, first backing up the old mcp.json as mcp.json.v<version>.bak
+func (c *Config) saveMigrated(config *MCPConfig, version int) error {
+	path := c.GetMCPConfigPath()
+	if data, err := os.ReadFile(path); err == nil {
+		if err := os.WriteFile(fmt.Sprintf("%s.v%d.bak", path, version), data, 0600); err != nil {
+			return fmt.Errorf("failed to back up MCP config: %w", err)
+		}
+	}
+	return c.SaveMCPConfig(config)
+}
+
+// loadServersFile migrates servers.json to mcp.json, which is loaded from
+// then on. servers.json itself is left in place.
+func (c *Config) loadServersFile(data []byte) (*MCPConfig, error) {
+	data, err := migrate(data, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate servers.json: %w", err)
+	}
+
+	var config MCPConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal migrated servers.json: %w", err)
+	}
+	config.ServerOrder = c.extractServerOrder(data)
+	if err := c.saveMigrated(&config, 0); err != nil {
+		return nil, err
+	}
+	return c.LoadMCPConfig()
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMCPConfig_MigratesLegacyNames(t *testing.T) {
+	cfg := &Config{ConfigDir: t.TempDir()}
+	legacy := `{
+  "mcpServers": {
+    "zebra": {"command": "npx", "args": ["-y", "@modelcontextprotocol/server-filesystem", "/my files"]},
+    "alpha": {"command": "echo alpha", "port": 4010}
+  },
+  "shellEnv": true
+}`
+	require.NoError(t, os.WriteFile(cfg.GetMCPConfigPath(), []byte(legacy), 0644))
+
+	loaded, err := cfg.LoadMCPConfig()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"zebra", "alpha"}, loaded.ServerOrder)
+	assert.Equal(t, "npx -y @modelcontextprotocol/server-filesystem '/my files'", loaded.Servers["zebra"].Command)
+	assert.Equal(t, 4001, loaded.Servers["zebra"].Port)
+	assert.True(t, loaded.ShellEnv)
+
+	// The old file is backed up and the migrated one written back, without
+	// the assigned ports
+	backup, err := os.ReadFile(cfg.GetMCPConfigPath() + ".v1.bak")
+	require.NoError(t, err)
+	assert.Equal(t, legacy, string(backup))
+	written, err := os.ReadFile(cfg.GetMCPConfigPath())
+	require.NoError(t, err)
+	assert.Contains(t, string(written), `"version": 2`)
+	assert.NotContains(t, string(written), "mcpServers")
+	assert.NotContains(t, string(written), `"port": 4001`)
+
+	// Current files are left alone
+	require.NoError(t, os.Remove(cfg.GetMCPConfigPath()+".v1.bak"))
+	_, err = cfg.LoadMCPConfig()
+	require.NoError(t, err)
+	assert.NoFileExists(t, cfg.GetMCPConfigPath()+".v1.bak")
+}
+
+func TestLoadMCPConfig_MigratesServersFile(t *testing.T) {
+	cfg := &Config{ConfigDir: t.TempDir()}
+	serversFile := filepath.Join(cfg.ConfigDir, "servers.json")
+	require.NoError(t, os.WriteFile(serversFile, []byte(`{
+  "second": {"name": "second", "command": "echo second", "port": 4002, "description": "Second", "status": "running", "pid": 123},
+  "first": {"name": "first", "command": "echo first", "port": 4001, "description": "First", "status": "stopped"}
+}`), 0644))
+
+	loaded, err := cfg.LoadMCPConfig()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first", "second"}, loaded.ServerOrder)
+	assert.Equal(t, &MCPServerConfig{Command: "echo second", Port: 4002, Description: "Second"}, loaded.Servers["second"])
+	assert.Equal(t, CurrentVersion, loaded.Version)
+	assert.True(t, cfg.MCPConfigExists())
+	assert.FileExists(t, serversFile)
+}
+
+func TestLoadMCPConfig_NewerVersion(t *testing.T) {
+	cfg := &Config{ConfigDir: t.TempDir()}
+	require.NoError(t, os.WriteFile(filepath.Join(cfg.ConfigDir, "mcp.json"), []byte(`{"version": 99, "servers": {}}`), 0644))
+
+	_, err := cfg.LoadMCPConfig()
+	assert.ErrorContains(t, err, "newer than the supported")
+}
+
+func TestMigrateLegacyNames_Conflict(t *testing.T) {
+	_, err := migrate([]byte(`{"servers": {}, "mcpServers": {}}`), 1)
+	assert.Error(t, err)
+}
@@ -0,0 +1,168 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// State is the runtime state of the servers, kept in state.json. mcp.json
+// declares the servers and is owned by the user, the setup wizard and the
+// add/remove server operations; state.json is owned by the manager, which
+// rewrites it as servers start and stop. It is never edited by hand.
+type State struct {
+	Servers map[string]*ServerState `json:"servers"`
+
+	// Notifications maps servers to the level of desktop notifications
+	// chosen for them in the TUI, CLI or API
+	Notifications map[string]string `json:"notifications,omitempty"`
+}
+
+// ServerState is the runtime state of a running server
+type ServerState struct {
+	PID       int       `json:"pid"`
+	StartedAt time.Time `json:"startedAt"`
+}
+
+// stateMu serializes updates of state.json within the process
+var stateMu sync.Mutex
+
+// GetStatePath returns the path to state.json
+func (c *Config) GetStatePath() string {
+	return filepath.Join(c.GetStateDir(), "state.json")
+}
+
+// legacyPidDir is where PID files were kept before state.json
+func (c *Config) legacyPidDir() string {
+	return filepath.Join(c.ConfigDir, "pids")
+}
+
+// LoadState loads the runtime state
+func (c *Config) LoadState() (*State, error) {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	return c.loadState()
+}
+
+// UpdateState applies fn to the runtime state and saves it
+func (c *Config) UpdateState(fn func(*State)) error {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	state, err := c.loadState()
+	if err != nil {
+		return err
+	}
+	fn(state)
+	return c.saveState(state)
+}
+
+// loadState loads state.json, or imports the legacy PID files when it
+// doesn't exist yet. Must be called with stateMu held.
+func (c *Config) loadState() (*State, error) {
+	state := &State{Servers: make(map[string]*ServerState)}
+
+	data, err := os.ReadFile(c.GetStatePath())
+	if os.IsNotExist(err) {
+		c.importPIDFiles(state)
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal state: %w", err)
+	}
+	if state.Servers == nil {
+		state.Servers = make(map[string]*ServerState)
+	}
+	return state, nil
+}
+
+// importPIDFiles adds the servers of the legacy PID files to state
+func (c *Config) importPIDFiles(state *State) {
+	entries, _ := os.ReadDir(c.legacyPidDir())
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".pid")
+		if !ok {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(c.legacyPidDir(), entry.Name()))
+		if err != nil {
+			continue
+		}
+		var pid int
+		if _, err := fmt.Sscanf(string(data), "%d", &pid); err == nil && pid > 0 {
+			state.Servers[name] = &ServerState{PID: pid}
+		}
+	}
+}
+
+// saveState atomically replaces state.json, removing the legacy PID files
+// it supersedes. Must be called with stateMu held.
+func (c *Config) saveState(state *State) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+
+	path := c.GetStatePath()
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".state.json.new-*")
+	if err != nil {
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+
+	os.RemoveAll(c.legacyPidDir())
+	return nil
+}
+
+// SavePID records that a server is running as process pid
+func (c *Config) SavePID(serverName string, pid int) error {
+	return c.UpdateState(func(state *State) {
+		state.Servers[serverName] = &ServerState{PID: pid, StartedAt: time.Now()}
+	})
+}
+
+// LoadPID returns the process ID of a running server
+func (c *Config) LoadPID(serverName string) (int, error) {
+	state, err := c.LoadState()
+	if err != nil {
+		return 0, err
+	}
+	srv, exists := state.Servers[serverName]
+	if !exists {
+		return 0, fmt.Errorf("no PID recorded for server '%s'", serverName)
+	}
+	return srv.PID, nil
+}
+
+// RemovePID records that a server is no longer running
+func (c *Config) RemovePID(serverName string) error {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	state, err := c.loadState()
+	if err != nil {
+		return err
+	}
+	if _, exists := state.Servers[serverName]; !exists {
+		return nil
+	}
+	delete(state.Servers, serverName)
+	return c.saveState(state)
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// IsTemplate returns true if the server expands into several instances
+func (s *MCPServerConfig) IsTemplate() bool {
+	return s.Instances != 0 || len(s.Matrix) > 0
+}
+
+// InstanceCount returns the number of servers the entry expands into, one
+// unless it's a template
+func (s *MCPServerConfig) InstanceCount() int {
+	if len(s.Matrix) > 0 {
+		count := 1
+		for _, values := range s.Matrix {
+			count *= len(values)
+		}
+		return count
+	}
+	if s.Instances > 0 {
+		return s.Instances
+	}
+	return 1
+}
+
+// InstanceName returns the name of the instance of a template numbered
+// i, from 1
+func InstanceName(name string, i int) string {
+	return name + "-" + strconv.Itoa(i)
+}
+
+// ExpandInstances replaces the servers declaring instances or a matrix by
+// their instances, in their place in the server order. Instances of a
+// template with a port get consecutive ports from it.
+func (c *MCPConfig) ExpandInstances() error {
+	var order []string
+	expanded := make(map[string]*MCPServerConfig, len(c.Servers))
+	groups := make(map[string]string) // Template of each instance
+	for _, name := range c.ServerOrder {
+		srv, exists := c.Servers[name]
+		if !exists {
+			continue
+		}
+		if !srv.IsTemplate() {
+			expanded[name] = srv
+			order = append(order, name)
+			continue
+		}
+
+		servers, err := expandTemplate(name, srv)
+		if err != nil {
+			return fmt.Errorf("server '%s': %w", name, err)
+		}
+		for i, instance := range servers {
+			instanceName := InstanceName(name, i+1)
+			expanded[instanceName] = instance
+			order = append(order, instanceName)
+			groups[instanceName] = name
+		}
+	}
+
+	// Instances can't replace servers configured by name
+	for instance, group := range groups {
+		if srv, exists := c.Servers[instance]; exists && !srv.IsTemplate() {
+			return fmt.Errorf("server '%s' conflicts with an instance of '%s'", instance, group)
+		}
+	}
+
+	c.Servers = expanded
+	c.ServerOrder = order
+	return nil
+}
+
+// expandTemplate returns the instances of a template in order
+func expandTemplate(name string, tmpl *MCPServerConfig) ([]*MCPServerConfig, error) {
+	if tmpl.Instances < 0 {
+		return nil, fmt.Errorf("invalid instances %d", tmpl.Instances)
+	}
+	if tmpl.Instances > 0 && len(tmpl.Matrix) > 0 {
+		return nil, fmt.Errorf("set instances or matrix, not both")
+	}
+
+	params := make([]string, 0, len(tmpl.Matrix))
+	for param, values := range tmpl.Matrix {
+		if len(values) == 0 {
+			return nil, fmt.Errorf("matrix parameter '%s' has no values", param)
+		}
+		params = append(params, param)
+	}
+	sort.Strings(params)
+
+	count := tmpl.InstanceCount()
+	instances := make([]*MCPServerConfig, count)
+	for i := range instances {
+		vars := []string{"{{instance}}", strconv.Itoa(i + 1), "{{name}}", InstanceName(name, i+1)}
+
+		// The last parameter varies fastest
+		rest := i
+		for j := len(params) - 1; j >= 0; j-- {
+			values := tmpl.Matrix[params[j]]
+			vars = append(vars, "{{"+params[j]+"}}", values[rest%len(values)])
+			rest /= len(values)
+		}
+		instances[i] = expandInstance(name, tmpl, i, strings.NewReplacer(vars...))
+	}
+	return instances, nil
+}
+
+// expandInstance returns the instance of a template numbered i, from 0
+func expandInstance(name string, tmpl *MCPServerConfig, i int, vars *strings.Replacer) *MCPServerConfig {
+	srv := *tmpl
+	srv.Instances = 0
+	srv.Matrix = nil
+	srv.Group = name
+	srv.Command = vars.Replace(tmpl.Command)
+	srv.ShadowCommand = vars.Replace(tmpl.ShadowCommand)
+	srv.Description = vars.Replace(tmpl.Description)
+	if tmpl.Port != 0 {
+		srv.Port = tmpl.Port + i
+	}
+	if tmpl.Env != nil {
+		srv.Env = maps.Clone(tmpl.Env)
+		for key, value := range srv.Env {
+			srv.Env[key] = vars.Replace(value)
+		}
+	}
+	return &srv
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandInstances(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &Config{ConfigDir: tempDir}
+
+	testConfig := `{
+  "servers": {
+    "browser": {
+      "command": "npx @playwright/mcp --user-data-dir /tmp/profile-{{instance}}",
+      "description": "Browser {{instance}}",
+      "env": {"NAME": "{{name}}"},
+      "instances": 3
+    },
+    "github": {"command": "github-mcp"},
+    "search": {
+      "command": "search-mcp --region {{region}} --tier {{tier}}",
+      "port": 5000,
+      "matrix": {"tier": ["free", "pro"], "region": ["eu", "us"]}
+    }
+  }
+}`
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "mcp.json"), []byte(testConfig), 0644))
+
+	mcpConfig, err := cfg.LoadMCPConfig()
+	require.NoError(t, err)
+
+	// Templates reserve a port per instance
+	assert.Equal(t, 4001, mcpConfig.Servers["browser"].Port)
+	assert.Equal(t, 4004, mcpConfig.Servers["github"].Port)
+
+	require.NoError(t, mcpConfig.ExpandInstances())
+	assert.Equal(t, []string{"browser-1", "browser-2", "browser-3", "github", "search-1", "search-2", "search-3", "search-4"}, mcpConfig.ServerOrder)
+	assert.Len(t, mcpConfig.Servers, 8)
+
+	browser := mcpConfig.Servers["browser-2"]
+	assert.Equal(t, "npx @playwright/mcp --user-data-dir /tmp/profile-2", browser.Command)
+	assert.Equal(t, "Browser 2", browser.Description)
+	assert.Equal(t, map[string]string{"NAME": "browser-2"}, browser.Env)
+	assert.Equal(t, 4002, browser.Port)
+	assert.Equal(t, "browser", browser.Group)
+	assert.Zero(t, browser.Instances)
+	assert.Equal(t, "", mcpConfig.Servers["github"].Group)
+
+	// The last parameter varies fastest
+	assert.Equal(t, "search-mcp --region eu --tier free", mcpConfig.Servers["search-1"].Command)
+	assert.Equal(t, "search-mcp --region eu --tier pro", mcpConfig.Servers["search-2"].Command)
+	assert.Equal(t, "search-mcp --region us --tier free", mcpConfig.Servers["search-3"].Command)
+	assert.Equal(t, 5003, mcpConfig.Servers["search-4"].Port)
+	assert.Nil(t, mcpConfig.Servers["search-4"].Matrix)
+}
+
+func TestExpandInstances_Errors(t *testing.T) {
+	tests := []struct {
+		servers map[string]*MCPServerConfig
+		order   []string
+		want    string
+	}{
+		{
+			map[string]*MCPServerConfig{"a": {Command: "a", Instances: 2, Matrix: map[string][]string{"x": {"1"}}}},
+			[]string{"a"},
+			"server 'a': set instances or matrix, not both",
+		},
+		{
+			map[string]*MCPServerConfig{"a": {Command: "a", Matrix: map[string][]string{"x": {}}}},
+			[]string{"a"},
+			"server 'a': matrix parameter 'x' has no values",
+		},
+		{
+			map[string]*MCPServerConfig{"a": {Command: "a", Instances: -1}},
+			[]string{"a"},
+			"server 'a': invalid instances -1",
+		},
+		{
+			map[string]*MCPServerConfig{"a": {Command: "a", Instances: 2}, "a-2": {Command: "b"}},
+			[]string{"a", "a-2"},
+			"server 'a-2' conflicts with an instance of 'a'",
+		},
+	}
+	for _, tt := range tests {
+		mcpConfig := &MCPConfig{Servers: tt.servers, ServerOrder: tt.order}
+		assert.EqualError(t, mcpConfig.ExpandInstances(), tt.want)
+	}
+}
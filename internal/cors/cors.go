@@ -0,0 +1,94 @@
+// Package cors applies the browser access policy shared by the HTTP
+// proxies and the daemon's gRPC-Web endpoint
+package cors
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Policy controls which browser origins may call an endpoint
+type Policy struct {
+	// AllowedOrigins lists the origins allowed to make requests, e.g.
+	// "http://localhost:3000"; "*" or an empty list allows any origin
+	AllowedOrigins []string
+
+	// Local makes an empty AllowedOrigins allow only loopback origins, such
+	// as http://localhost:3000, instead of any origin
+	Local bool
+
+	AllowedMethods []string // Methods allowed in preflight requests
+	AllowedHeaders []string // Request headers allowed in preflight requests
+	ExposedHeaders []string // Response headers readable by scripts
+}
+
+// allowAny returns true if every origin is allowed
+func (p Policy) allowAny() bool {
+	return len(p.AllowedOrigins) == 0 && !p.Local || slices.Contains(p.AllowedOrigins, "*")
+}
+
+// Allowed returns true if requests from origin are allowed
+func (p Policy) Allowed(origin string) bool {
+	if p.allowAny() || slices.Contains(p.AllowedOrigins, origin) {
+		return true
+	}
+	return len(p.AllowedOrigins) == 0 && loopback(origin)
+}
+
+// loopback returns true if origin is served from this machine
+func loopback(origin string) bool {
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	if u.Hostname() == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(u.Hostname())
+	return ip != nil && ip.IsLoopback()
+}
+
+// sameOrigin returns true if origin is the endpoint serving r itself
+func sameOrigin(origin string, r *http.Request) bool {
+	u, err := url.Parse(origin)
+	return err == nil && u.Host != "" && u.Host == r.Host
+}
+
+// Handler adds CORS headers to the responses of next and answers preflight
+// requests. Requests from disallowed origins are rejected before reaching
+// next, so simple cross-origin requests can't cause side effects either.
+// Requests from the endpoint's own origin are always allowed.
+func (p Policy) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		allowed := origin == "" || p.Allowed(origin) || sameOrigin(origin, r)
+
+		if allowed {
+			if p.allowAny() {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			} else {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				w.Header().Add("Vary", "Origin")
+			}
+			w.Header().Set("Access-Control-Allow-Methods", strings.Join(p.AllowedMethods, ", "))
+			w.Header().Set("Access-Control-Allow-Headers", strings.Join(p.AllowedHeaders, ", "))
+			if len(p.ExposedHeaders) > 0 {
+				w.Header().Set("Access-Control-Expose-Headers", strings.Join(p.ExposedHeaders, ", "))
+			}
+		}
+
+		if !allowed {
+			http.Error(w, "origin not allowed", http.StatusForbidden)
+			return
+		}
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,80 @@
+package cors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func serve(policy Policy, method, origin string) *httptest.ResponseRecorder {
+	handler := policy.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	req := httptest.NewRequest(method, "/", nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestPolicy_AnyOrigin(t *testing.T) {
+	policy := Policy{AllowedMethods: []string{"GET", "POST"}, AllowedHeaders: []string{"Content-Type"}}
+
+	rec := serve(policy, http.MethodOptions, "http://dashboard.local")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "*", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "GET, POST", rec.Header().Get("Access-Control-Allow-Methods"))
+	assert.Empty(t, rec.Header().Get("Access-Control-Expose-Headers"))
+
+	rec = serve(policy, http.MethodGet, "")
+	assert.Equal(t, http.StatusTeapot, rec.Code)
+}
+
+func TestPolicy_AllowedOrigins(t *testing.T) {
+	policy := Policy{AllowedOrigins: []string{"http://localhost:3000"}, ExposedHeaders: []string{"Grpc-Status"}}
+
+	rec := serve(policy, http.MethodPost, "http://localhost:3000")
+	assert.Equal(t, http.StatusTeapot, rec.Code)
+	assert.Equal(t, "http://localhost:3000", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "Origin", rec.Header().Get("Vary"))
+	assert.Equal(t, "Grpc-Status", rec.Header().Get("Access-Control-Expose-Headers"))
+
+	// Other origins are refused, never reaching the handler
+	rec = serve(policy, http.MethodOptions, "http://evil.example")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = serve(policy, http.MethodPost, "http://evil.example")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+
+	// Non-browser clients are unaffected
+	rec = serve(policy, http.MethodPost, "")
+	assert.Equal(t, http.StatusTeapot, rec.Code)
+}
+
+func TestPolicy_Local(t *testing.T) {
+	policy := Policy{Local: true}
+
+	rec := serve(policy, http.MethodPost, "http://localhost:3000")
+	assert.Equal(t, http.StatusTeapot, rec.Code)
+	assert.Equal(t, "http://localhost:3000", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, http.StatusTeapot, serve(policy, http.MethodPost, "http://127.0.0.1:8080").Code)
+	assert.Equal(t, http.StatusTeapot, serve(policy, http.MethodPost, "http://[::1]").Code)
+
+	// The endpoint's own pages may call it
+	assert.Equal(t, http.StatusTeapot, serve(policy, http.MethodPost, "http://example.com").Code)
+
+	assert.Equal(t, http.StatusForbidden, serve(policy, http.MethodPost, "https://evil.example").Code)
+	assert.Equal(t, http.StatusForbidden, serve(policy, http.MethodOptions, "http://localhost.evil.example").Code)
+
+	// Explicit origins, including "*", replace the default
+	policy.AllowedOrigins = []string{"https://dash.example.com"}
+	assert.Equal(t, http.StatusForbidden, serve(policy, http.MethodPost, "http://localhost:3000").Code)
+	policy.AllowedOrigins = []string{"*"}
+	assert.Equal(t, http.StatusTeapot, serve(policy, http.MethodPost, "https://evil.example").Code)
+}
@@ -2,59 +2,201 @@ package daemon
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
+	"net/http/pprof"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/tartavull/mcp-manager/internal/auth"
+	"github.com/tartavull/mcp-manager/internal/bus"
+	"github.com/tartavull/mcp-manager/internal/cluster"
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/gateway"
 	"github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/journal"
 	"github.com/tartavull/mcp-manager/internal/manager"
+	"github.com/tartavull/mcp-manager/internal/metrics"
+	"github.com/tartavull/mcp-manager/internal/notify"
+	"github.com/tartavull/mcp-manager/internal/plugin"
+	"github.com/tartavull/mcp-manager/internal/power"
+	"github.com/tartavull/mcp-manager/internal/shellenv"
+	"github.com/tartavull/mcp-manager/internal/uptime"
 )
 
+// ClusterOptions configures how a daemon takes part in a fleet
+type ClusterOptions struct {
+	Coordinator bool              // Aggregate the servers of daemons that join
+	Peers       map[string]string // Static peers, host label to gRPC address
+	Join        string            // Coordinator address to register with
+	Host        string            // Label of this daemon's servers
+	Advertise   string            // Address the coordinator reaches this daemon on
+}
+
+// IsCoordinator returns true if the daemon aggregates other daemons
+func (o ClusterOptions) IsCoordinator() bool {
+	return o.Coordinator || len(o.Peers) > 0
+}
+
+// args returns the command line flags that reproduce the options
+func (o ClusterOptions) args() []string {
+	var args []string
+	if o.Coordinator {
+		args = append(args, "-coordinator")
+	}
+	if len(o.Peers) > 0 {
+		peers := make([]string, 0, len(o.Peers))
+		for host, address := range o.Peers {
+			peers = append(peers, host+"="+address)
+		}
+		sort.Strings(peers)
+		args = append(args, "-peers", strings.Join(peers, ","))
+	}
+	if o.Join != "" {
+		args = append(args, "-join", o.Join)
+	}
+	if o.Host != "" {
+		args = append(args, "-host", o.Host)
+	}
+	if o.Advertise != "" {
+		args = append(args, "-advertise", o.Advertise)
+	}
+	return args
+}
+
+// ParsePeers parses a comma-separated list of host=address peers
+func ParsePeers(list string) (map[string]string, error) {
+	peers := make(map[string]string)
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		host, address, ok := strings.Cut(entry, "=")
+		if !ok || host == "" || address == "" {
+			return nil, fmt.Errorf("invalid peer %q, expected host=address", entry)
+		}
+		peers[host] = address
+	}
+	return peers, nil
+}
+
 // Daemon represents the MCP Manager daemon
 type Daemon struct {
 	manager  *manager.Manager
 	grpcPort int
+	webPort  int    // gRPC-Web and h2c port, zero when disabled
+	httpPort int    // HTTP+JSON API port, zero when disabled
+	socket   string // Unix socket served instead of grpcPort, empty for TCP
+	bind     string // Interface the TCP ports listen on
+	pprof    bool   // Serve profiles of the daemon on httpPort
+	cluster  ClusterOptions
 	pidFile  string
 	logFile  string
+	out      io.Writer // Where Start and Stop report, stdout by default
 	ctx      context.Context
 	cancel   context.CancelFunc
 }
 
-// NewDaemon creates a new daemon instance
-func NewDaemon(grpcPort int) (*Daemon, error) {
-	// Create manager
+// NewDaemon creates a new daemon instance. A zero grpcPort uses the port
+// of the instance's mcp.json, and its daemonSocket replaces the port. A
+// non-zero webPort also serves the API over gRPC-Web and h2c for browser
+// dashboards.
+func NewDaemon(grpcPort, webPort int, clusterOpts ClusterOptions) (*Daemon, error) {
+	// Create manager, adopting the servers of the daemon this binary
+	// replaced on upgrade
 	mgr, err := manager.New()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create manager: %w", err)
 	}
+	inherit(mgr)
+
+	cfg, err := config.New()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config: %w", err)
+	}
+	if grpcPort == 0 {
+		grpcPort = cfg.DaemonPort()
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 
-	// Determine pid file location
-	homeDir, _ := os.UserHomeDir()
-	pidFile := filepath.Join(homeDir, ".mcp-manager", "daemon.pid")
-	logFile := filepath.Join(homeDir, ".mcp-manager", "daemon.log")
+	// The PID and log files live in the state directory
+	pidFile := filepath.Join(cfg.GetStateDir(), "daemon.pid")
+	logFile := cfg.GetLogPath("daemon.log")
 
-	// Ensure directory exists
-	os.MkdirAll(filepath.Dir(pidFile), 0755)
+	// Label servers with the machine name unless told otherwise
+	if clusterOpts.Host == "" {
+		clusterOpts.Host, _ = os.Hostname()
+	}
+	if clusterOpts.Advertise == "" {
+		clusterOpts.Advertise = fmt.Sprintf("%s:%d", clusterOpts.Host, grpcPort)
+	}
 
 	return &Daemon{
 		manager:  mgr,
 		grpcPort: grpcPort,
+		webPort:  webPort,
+		socket:   cfg.DaemonSocket(),
+		bind:     cfg.DaemonBindAddress(),
+		cluster:  clusterOpts,
 		pidFile:  pidFile,
 		logFile:  logFile,
+		out:      os.Stdout,
 		ctx:      ctx,
 		cancel:   cancel,
 	}, nil
 }
 
+// SetOutput sets where Start and Stop report what they did, e.g.
+// io.Discard to keep stdout clean for scripts
+func (d *Daemon) SetOutput(w io.Writer) {
+	d.out = w
+}
+
+// SetHTTPPort also serves the main RPCs as an HTTP+JSON API on port, for
+// clients without gRPC. Zero disables it.
+func (d *Daemon) SetHTTPPort(port int) {
+	d.httpPort = port
+}
+
+// SetBindAddress listens on the interface at address instead of the one
+// of mcp.json, e.g. 0.0.0.0 for all interfaces
+func (d *Daemon) SetBindAddress(address string) {
+	d.bind = address
+}
+
+// SetProfiling serves the daemon's goroutine, heap and CPU profiles under
+// /debug/pprof/ of the HTTP port, behind the API's authentication
+func (d *Daemon) SetProfiling(enabled bool) {
+	d.pprof = enabled
+}
+
+// SetSocket serves the API on the unix socket at path instead of the
+// gRPC port, so only users allowed to open it reach the daemon
+func (d *Daemon) SetSocket(path string) {
+	d.socket = shellenv.ExpandHome(path)
+}
+
 // Run starts the daemon in foreground mode
 func (d *Daemon) Run() error {
-	log.Printf("Starting MCP Manager daemon on port %d", d.grpcPort)
+	if d.pprof && d.httpPort == 0 {
+		return fmt.Errorf("profiles are served on the HTTP port, set -http-port")
+	}
+	if d.socket != "" {
+		log.Printf("Starting MCP Manager daemon on %s", d.socket)
+	} else {
+		log.Printf("Starting MCP Manager daemon on port %d", d.grpcPort)
+	}
 
 	// Write PID file
 	if err := d.writePIDFile(); err != nil {
@@ -66,18 +208,173 @@ func (d *Daemon) Run() error {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
+	// Upgrade hands the servers to the binary installed in its place
+	upgradeChan := make(chan os.Signal, 1)
+	signal.Notify(upgradeChan, syscall.SIGUSR2)
+
+	cfg, mcpConfig, err := d.loadConfig()
+	if err != nil {
+		return err
+	}
+	logPlugins(cfg.GetPluginDir())
+
+	// Coordinators serve the whole fleet instead of the local servers
+	var served grpc.ManagerInterface = d.manager
+	if d.cluster.IsCoordinator() {
+		coordinator := cluster.NewCoordinator(d.manager, d.cluster.Host)
+		for host, address := range d.cluster.Peers {
+			if err := coordinator.AddPeer(host, address); err != nil {
+				return fmt.Errorf("failed to add peer: %w", err)
+			}
+		}
+		if err := enableFailover(coordinator, mcpConfig); err != nil {
+			return err
+		}
+		served = coordinator
+		log.Printf("Coordinating fleet as %s with %d static peers", d.cluster.Host, len(d.cluster.Peers))
+	}
+
+	// Upstream daemons' servers are served alongside the local ones
+	if len(mcpConfig.Upstreams) > 0 {
+		chain, err := cluster.NewChain(served, mcpConfig.Upstreams, mcpConfig.BindAddress)
+		if err != nil {
+			return fmt.Errorf("failed to add upstreams: %w", err)
+		}
+		served = chain
+		log.Printf("Serving the servers of upstreams %s", strings.Join(chain.Upstreams(), ", "))
+	}
+
+	security, err := buildSecurity(mcpConfig.Auth, os.Getenv(auth.DaemonTokenEnv))
+	if err != nil {
+		return err
+	}
+
+	gw, err := startGateway(served, mcpConfig.Gateway, gateway.Options{
+		Auth:       security.gateway,
+		TLS:        security.gatewayTLS,
+		ProxyToken: d.manager.ProxyToken(),
+	})
+	if err != nil {
+		return err
+	}
+	if gw != nil {
+		defer gw.Stop()
+	}
+
+	events, err := openJournal(filepath.Join(filepath.Dir(d.logFile), "events"), mcpConfig.EventJournal)
+	if err != nil {
+		return err
+	}
+	if events != nil {
+		defer events.Close()
+	}
+
+	// Availability is reported without it rather than failing to start
+	tracker, err := uptime.Open(filepath.Join(cfg.GetStateDir(), "uptime.json"))
+	if err != nil {
+		log.Printf("Warning: not tracking uptime: %v", err)
+	} else {
+		go tracker.Run(d.ctx)
+	}
+
+	exporter, err := bus.NewWithPlugins(mcpConfig.EventExport, cfg.GetPluginDir())
+	if err != nil {
+		return fmt.Errorf("failed to export events: %w", err)
+	}
+	defer exporter.Close()
+
+	policy, err := newPowerPolicy(d.manager, mcpConfig.Power)
+	if err != nil {
+		return err
+	}
+
+	notifier, err := notify.New(cfg, served, mcpConfig.Notifications)
+	if err != nil {
+		return fmt.Errorf("failed to enable notifications: %w", err)
+	}
+
+	monitor, err := monitorOptions(mcpConfig.EventMonitor)
+	if err != nil {
+		return err
+	}
+
+	// The API reports its subscribers and event queues for the metrics
+	self := metrics.NewSelf()
+
+	opts := grpc.ServeOptions{
+		WebPort:     d.webPort,
+		CORSOrigins: mcpConfig.CORSOrigins,
+		Journal:     events,
+		Validator:   &gateway.Validator{Config: cfg, Source: served},
+		Notifier:    notifier,
+		DocsDir:     cfg.GetDocsDir(),
+		Auth:        security.daemon,
+		TLS:         security.tls,
+		Socket:      d.socket,
+		Host:        d.bind,
+		Uptime:      tracker,
+		Self:        self,
+		Monitor:     monitor,
+	}
+	if gw != nil {
+		opts.Providers = gw
+	}
+	if exporter != nil {
+		opts.Exporter = exporter
+		log.Printf("Exporting events to %d message buses", len(mcpConfig.EventExport))
+	}
+	// The HTTP API shares the server, which alone consumes the manager's
+	// events
+	opts.API = grpc.NewAPI(served, opts)
+
 	// Start gRPC server in goroutine
 	errChan := make(chan error, 1)
 	go func() {
-		if err := grpc.Serve(d.manager, d.grpcPort); err != nil {
+		if err := grpc.Serve(served, d.grpcPort, opts); err != nil {
 			errChan <- err
 		}
 	}()
 
+	if d.httpPort > 0 {
+		lis, err := listenREST(d.bind, d.httpPort, security.daemon)
+		if err != nil {
+			return err
+		}
+
+		// Prometheus scrapes the metrics next to the API
+		handler := http.NewServeMux()
+		handler.Handle("/", RESTHandler(opts.API, security.daemon, mcpConfig.CORSOrigins))
+		handler.Handle("GET /metrics", auth.Require(security.daemon, metrics.Handler(served, d.manager.CallMetrics(), self)))
+		if d.pprof {
+			// e.g. go tool pprof http://localhost:8082/debug/pprof/heap
+			handler.Handle("/debug/pprof/", auth.Require(security.daemon, http.HandlerFunc(pprof.Index)))
+			handler.Handle("/debug/pprof/cmdline", auth.Require(security.daemon, http.HandlerFunc(pprof.Cmdline)))
+			handler.Handle("/debug/pprof/profile", auth.Require(security.daemon, http.HandlerFunc(pprof.Profile)))
+			handler.Handle("/debug/pprof/symbol", auth.Require(security.daemon, http.HandlerFunc(pprof.Symbol)))
+			handler.Handle("/debug/pprof/trace", auth.Require(security.daemon, http.HandlerFunc(pprof.Trace)))
+			log.Printf("Serving profiles under /debug/pprof/ on port %d", d.httpPort)
+		}
+		serveREST(d.ctx, handler, lis, security.tls)
+	}
+
+	go d.manager.StartAutostartServers()
+
+	if policy != nil {
+		go policy.Run(d.ctx)
+	}
+
+	if d.cluster.Join != "" {
+		go cluster.Join(d.ctx, d.cluster.Join, d.cluster.Host, d.cluster.Advertise)
+	}
+
 	// Wait for shutdown signal or error
+	upgrade := false
 	select {
 	case <-sigChan:
 		log.Println("Received shutdown signal")
+	case <-upgradeChan:
+		log.Println("Received upgrade signal")
+		upgrade = true
 	case err := <-errChan:
 		log.Printf("gRPC server error: %v", err)
 		return err
@@ -89,17 +386,205 @@ func (d *Daemon) Run() error {
 	log.Println("Shutting down daemon...")
 	d.cancel()
 
-	// Stop all servers
-	d.manager.StopAllServers()
+	// Stop all servers, unless they're handed to the new binary
+	if !upgrade {
+		d.manager.StopAllServers()
+	}
+	if tracker != nil {
+		if err := tracker.Flush(); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
 
-	// Stop manager
-	if err := d.manager.Stop(); err != nil {
+	// Stop manager, disconnecting from the fleet
+	if err := served.Stop(); err != nil {
 		log.Printf("Error stopping manager: %v", err)
 	}
 
+	if upgrade {
+		err := d.reexec()
+		log.Printf("Failed to upgrade, stopping the servers: %v", err)
+		d.manager.StopAllServers()
+		return err
+	}
+	return nil
+}
+
+// loadConfig loads the settings of mcp.json the daemon applies at startup
+func (d *Daemon) loadConfig() (*config.Config, *config.MCPConfig, error) {
+	cfg, err := config.New()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create config: %w", err)
+	}
+	mcpConfig, err := cfg.LoadMCPConfig()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load MCP config: %w", err)
+	}
+	return cfg, mcpConfig, nil
+}
+
+// security holds the authentication of the daemon's API and gateway
+type security struct {
+	daemon  auth.Provider // Nil when the API is open
+	gateway auth.Provider // Nil when the gateway is open
+	tls     *tls.Config   // Nil when served in cleartext
+
+	// gatewayTLS is tls without requiring client certificates, which the
+	// gateway's clients authenticate with tokens instead of
+	gatewayTLS *tls.Config
+}
+
+// buildSecurity builds the authentication settings of mcp.json, failing
+// rather than serving an endpoint open by mistake. A non-empty token is
+// required of API clients too, see auth.DaemonTokenEnv.
+func buildSecurity(cfg *config.AuthConfig, token string) (security, error) {
+	var sec security
+	if cfg != nil {
+		var err error
+		if sec.daemon, err = auth.Build(cfg, cfg.Daemon); err != nil {
+			return sec, fmt.Errorf("invalid auth settings of the daemon: %w", err)
+		}
+		if sec.gateway, err = auth.Build(cfg, cfg.Gateway); err != nil {
+			return sec, fmt.Errorf("invalid auth settings of the gateway: %w", err)
+		}
+		if sec.tls, err = auth.ServerTLS(cfg.TLS); err != nil {
+			return sec, fmt.Errorf("invalid auth settings: %w", err)
+		}
+		sec.gatewayTLS = sec.tls
+		if cfg.TLS != nil && cfg.TLS.RequireClientCert {
+			sec.tls = auth.RequireClientCert(sec.tls)
+			log.Printf("Requiring client certificates of API clients")
+		}
+
+		if sec.daemon != nil {
+			log.Printf("Authenticating API clients with %s", strings.Join(cfg.Daemon, ", "))
+		}
+		if sec.gateway != nil {
+			log.Printf("Authenticating gateway clients with %s", strings.Join(cfg.Gateway, ", "))
+		}
+	}
+
+	if token != "" {
+		sec.daemon = auth.WithToken(sec.daemon, auth.EnvClient, token)
+		log.Printf("Authenticating API clients with the token of %s", auth.DaemonTokenEnv)
+	}
+	return sec, nil
+}
+
+// startGateway serves the tools of all running servers on one endpoint,
+// or returns nil if the gateway isn't configured
+func startGateway(source gateway.Source, cfg *config.GatewayConfig, opts gateway.Options) (*gateway.Gateway, error) {
+	if cfg == nil || cfg.Port == 0 {
+		return nil, nil
+	}
+
+	gw := gateway.NewWithOptions(source, cfg, opts)
+	if err := gw.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start gateway: %w", err)
+	}
+	return gw, nil
+}
+
+// logPlugins logs the plugins found in dir, which are described again
+// whenever they're used or listed
+func logPlugins(dir string) {
+	plugins, err := plugin.Discover(dir)
+	if err != nil {
+		log.Printf("Warning: %v", err)
+		return
+	}
+	for _, p := range plugins {
+		if p.Error != "" {
+			log.Printf("Warning: plugin %s at %s can't be used: %s", p.Name, p.Path, p.Error)
+			continue
+		}
+		log.Printf("Found plugin %s %s providing %s", p.Name, p.Version, strings.Join(p.Kinds, ", "))
+	}
+}
+
+// openJournal opens the journal of past events in dir, or returns nil if
+// it is disabled
+func openJournal(dir string, cfg *config.EventJournalConfig) (*journal.Journal, error) {
+	var opts journal.Options
+	if cfg != nil {
+		if cfg.Disabled {
+			return nil, nil
+		}
+		opts.MaxSize = cfg.MaxSize
+		if cfg.MaxAge != "" {
+			maxAge, err := time.ParseDuration(cfg.MaxAge)
+			if err != nil || maxAge <= 0 {
+				return nil, fmt.Errorf("invalid eventJournal maxAge '%s'", cfg.MaxAge)
+			}
+			opts.MaxAge = maxAge
+		}
+	}
+
+	events, err := journal.Open(dir, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event journal: %w", err)
+	}
+	return events, nil
+}
+
+// monitorOptions parses the settings of the event monitor of mcp.json
+func monitorOptions(cfg *config.EventMonitorConfig) (grpc.MonitorOptions, error) {
+	var opts grpc.MonitorOptions
+	if cfg == nil {
+		return opts, nil
+	}
+	for _, d := range []struct {
+		name  string
+		value string
+		dest  *time.Duration
+	}{
+		{"interval", cfg.Interval, &opts.Interval},
+		{"toolInterval", cfg.ToolInterval, &opts.ToolInterval},
+	} {
+		if d.value == "" {
+			continue
+		}
+		interval, err := time.ParseDuration(d.value)
+		if err != nil || interval <= 0 {
+			return opts, fmt.Errorf("invalid eventMonitor %s '%s'", d.name, d.value)
+		}
+		*d.dest = interval
+	}
+	if cfg.Workers < 0 {
+		return opts, fmt.Errorf("invalid eventMonitor workers %d", cfg.Workers)
+	}
+	opts.Workers = cfg.Workers
+	return opts, nil
+}
+
+// enableFailover applies the failover groups of mcp.json to a coordinator
+func enableFailover(coordinator *cluster.Coordinator, mcpConfig *config.MCPConfig) error {
+	if len(mcpConfig.Failover) == 0 {
+		return nil
+	}
+
+	if err := coordinator.EnableFailover(mcpConfig.Failover); err != nil {
+		return fmt.Errorf("failed to enable failover: %w", err)
+	}
+	log.Printf("Failover enabled for %d servers", len(mcpConfig.Failover))
 	return nil
 }
 
+// newPowerPolicy creates the policy stopping heavy servers on low battery,
+// or returns nil if it isn't configured
+func newPowerPolicy(mgr *manager.Manager, cfg *config.PowerConfig) (*power.Policy, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	policy, err := power.New(mgr, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enable power policy: %w", err)
+	}
+	log.Printf("Power policy enabled for servers tagged %s", power.HeavyTag)
+	return policy, nil
+}
+
 // Start starts the daemon in background mode
 func (d *Daemon) Start() error {
 	// Check if already running
@@ -107,9 +592,26 @@ func (d *Daemon) Start() error {
 		return fmt.Errorf("daemon is already running")
 	}
 
-	// Fork the process
-	cmd := os.Args[0]
-	args := []string{"daemon", "run"}
+	// Fork the process, resolving the binary so a freshly installed
+	// executable is picked up after self-update
+	cmd, err := os.Executable()
+	if err != nil {
+		cmd = os.Args[0]
+	}
+	args := []string{"run", "-port", strconv.Itoa(d.grpcPort), "-bind", d.bind}
+	if d.socket != "" {
+		args = append(args, "-socket", d.socket)
+	}
+	if d.webPort > 0 {
+		args = append(args, "-web-port", strconv.Itoa(d.webPort))
+	}
+	if d.httpPort > 0 {
+		args = append(args, "-http-port", strconv.Itoa(d.httpPort))
+	}
+	if d.pprof {
+		args = append(args, "-pprof")
+	}
+	args = append(args, d.cluster.args()...)
 
 	// Redirect output to log file
 	logFile, err := os.OpenFile(d.logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
@@ -137,8 +639,8 @@ func (d *Daemon) Start() error {
 		return fmt.Errorf("daemon failed to start")
 	}
 
-	fmt.Printf("Daemon started successfully (PID: %d)\n", d.readPID())
-	fmt.Printf("Logs: %s\n", d.logFile)
+	fmt.Fprintf(d.out, "Daemon started successfully (PID: %d)\n", d.readPID())
+	fmt.Fprintf(d.out, "Logs: %s\n", d.logFile)
 	return nil
 }
 
@@ -162,7 +664,7 @@ func (d *Daemon) Stop() error {
 	// Wait for it to stop
 	for i := 0; i < 10; i++ {
 		if !d.isRunning() {
-			fmt.Println("Daemon stopped successfully")
+			fmt.Fprintln(d.out, "Daemon stopped successfully")
 			return nil
 		}
 		time.Sleep(500 * time.Millisecond)
@@ -207,9 +709,15 @@ func (d *Daemon) writePIDFile() error {
 	return os.WriteFile(d.pidFile, []byte(fmt.Sprintf("%d", pid)), 0644)
 }
 
-// readPID reads the PID from file
+// readPID reads the PID from file, falling back to the PID file of
+// versions that kept it in ~/.mcp-manager so their daemons can be stopped
 func (d *Daemon) readPID() int {
 	data, err := os.ReadFile(d.pidFile)
+	if os.IsNotExist(err) {
+		if homeDir, homeErr := os.UserHomeDir(); homeErr == nil {
+			data, err = os.ReadFile(filepath.Join(homeDir, ".mcp-manager", "daemon.pid"))
+		}
+	}
 	if err != nil {
 		return 0
 	}
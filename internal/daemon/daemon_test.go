@@ -0,0 +1,58 @@
+package daemon
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/internal/auth"
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/grpc"
+)
+
+func TestBuildSecurity_Token(t *testing.T) {
+	ctx := context.Background()
+
+	sec, err := buildSecurity(nil, "")
+	require.NoError(t, err)
+	assert.Nil(t, sec.daemon, "the API stays open without settings")
+
+	// The token of the environment closes an open API
+	sec, err = buildSecurity(nil, "shared")
+	require.NoError(t, err)
+	require.NotNil(t, sec.daemon)
+	identity, err := sec.daemon.Authenticate(ctx, auth.Credentials{Token: "shared"})
+	require.NoError(t, err)
+	assert.Equal(t, auth.EnvClient, identity.Subject)
+	_, err = sec.daemon.Authenticate(ctx, auth.Credentials{})
+	assert.ErrorIs(t, err, auth.ErrUnauthenticated)
+	assert.Nil(t, sec.gateway, "the gateway isn't affected")
+
+	// and is accepted besides the providers of mcp.json
+	sec, err = buildSecurity(&config.AuthConfig{
+		Providers: map[string]*config.AuthProviderConfig{
+			"ci": {Type: "token", Tokens: map[string]string{"ci": "secret"}},
+		},
+		Daemon: []string{"ci"},
+	}, "shared")
+	require.NoError(t, err)
+	for _, token := range []string{"shared", "secret"} {
+		_, err = sec.daemon.Authenticate(ctx, auth.Credentials{Token: token})
+		assert.NoError(t, err, token)
+	}
+}
+
+func TestMonitorOptions(t *testing.T) {
+	opts, err := monitorOptions(nil)
+	require.NoError(t, err)
+	assert.Zero(t, opts)
+
+	opts, err = monitorOptions(&config.EventMonitorConfig{Interval: "5s", ToolInterval: "2m", Workers: 8})
+	require.NoError(t, err)
+	assert.Equal(t, grpc.MonitorOptions{Interval: 5 * time.Second, ToolInterval: 2 * time.Minute, Workers: 8}, opts)
+
+	_, err = monitorOptions(&config.EventMonitorConfig{ToolInterval: "0s"})
+	assert.EqualError(t, err, "invalid eventMonitor toolInterval '0s'")
+}
@@ -0,0 +1,133 @@
+package daemon
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/manager"
+)
+
+const (
+	// handoffEnv passes the daemon's servers to the binary replacing it, as
+	// the PIDs of their processes and the descriptors of their pipes:
+	// "pid=stdin,stdout,stderr ..."
+	handoffEnv = "MCP_DAEMON_HANDOFF"
+
+	// upgradeTimeout is how long Upgrade waits for the daemon to come back
+	upgradeTimeout = 30 * time.Second
+)
+
+// Upgrade replaces the running daemon with the binary self-update
+// installed in its place, keeping its servers running: the daemon execs
+// the binary, which adopts them. Daemons of versions without upgrades
+// exit instead, and are started again.
+func (d *Daemon) Upgrade() error {
+	pid := d.readPID()
+	if pid == 0 || !d.isRunning() {
+		return fmt.Errorf("daemon is not running")
+	}
+	info, err := os.Stat(d.pidFile)
+	if err != nil {
+		return fmt.Errorf("failed to read PID file: %w", err)
+	}
+
+	if err := syscall.Kill(pid, syscall.SIGUSR2); err != nil {
+		return fmt.Errorf("failed to send signal: %w", err)
+	}
+
+	// The new binary writes the PID file again once it runs
+	for deadline := time.Now().Add(upgradeTimeout); time.Now().Before(deadline); {
+		time.Sleep(500 * time.Millisecond)
+		if !d.isRunning() {
+			fmt.Fprintln(d.out, "The daemon exited instead of upgrading, starting it again")
+			return d.Start()
+		}
+		if next, err := os.Stat(d.pidFile); err == nil && next.ModTime().After(info.ModTime()) {
+			fmt.Fprintf(d.out, "Daemon upgraded (PID: %d)\n", pid)
+			return nil
+		}
+	}
+	return fmt.Errorf("the daemon didn't upgrade within %v, see %s", upgradeTimeout, d.logFile)
+}
+
+// reexec replaces the daemon's process with its binary, as installed now,
+// handing it the pipes of the running servers. It only returns on failure,
+// leaving the servers running.
+func (d *Daemon) reexec() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate executable: %w", err)
+	}
+
+	pids, files := d.manager.Handoff()
+
+	// The pipes are duplicated without close-on-exec, so no other child
+	// may be started meanwhile
+	syscall.ForkLock.Lock()
+	defer syscall.ForkLock.Unlock()
+
+	var servers []string
+	var duplicated []int
+	defer func() {
+		for _, fd := range duplicated {
+			syscall.Close(fd)
+		}
+	}()
+	for i, pid := range pids {
+		var fds []string
+		for _, file := range files[3*i : 3*i+3] {
+			fd, err := syscall.Dup(int(file.Fd()))
+			if err != nil {
+				return fmt.Errorf("failed to hand over the pipes of process %d: %w", pid, err)
+			}
+			duplicated = append(duplicated, fd)
+			fds = append(fds, strconv.Itoa(fd))
+		}
+		servers = append(servers, fmt.Sprintf("%d=%s", pid, strings.Join(fds, ",")))
+	}
+
+	env := append(os.Environ(), handoffEnv+"="+strings.Join(servers, " "))
+	args := append([]string{exe, "run"}, os.Args[1:]...)
+	log.Printf("Handing %d servers to %s", len(pids), exe)
+	return syscall.Exec(exe, args, env)
+}
+
+// inherit passes the pipes of the servers the daemon this process replaced
+// handed over to the manager, which adopted their processes
+func inherit(mgr *manager.Manager) {
+	value, exists := os.LookupEnv(handoffEnv)
+	if !exists {
+		return
+	}
+	// Servers started from now on don't inherit it
+	os.Unsetenv(handoffEnv)
+
+	var pids []int
+	var files []*os.File
+	for _, server := range strings.Fields(value) {
+		pid, fds, _ := strings.Cut(server, "=")
+		processID, err := strconv.Atoi(pid)
+		var pipes []*os.File
+		for _, fd := range strings.Split(fds, ",") {
+			if descriptor, err := strconv.Atoi(fd); err == nil {
+				pipes = append(pipes, os.NewFile(uintptr(descriptor), "pipe of process "+pid))
+			}
+		}
+		if err != nil || len(pipes) != 3 {
+			log.Printf("Warning: ignoring invalid handed over server %q", server)
+			for _, pipe := range pipes {
+				pipe.Close()
+			}
+			continue
+		}
+		pids = append(pids, processID)
+		files = append(files, pipes...)
+	}
+	mgr.Inherit(pids, files)
+	log.Printf("Inherited the pipes of %d servers handed over on upgrade", len(pids))
+}
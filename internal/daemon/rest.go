@@ -0,0 +1,172 @@
+package daemon
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"log"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/tartavull/mcp-manager/internal/auth"
+	"github.com/tartavull/mcp-manager/internal/cors"
+	pb "github.com/tartavull/mcp-manager/internal/grpc/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// restJSON encodes the messages of the REST API, with zero values so
+// clients don't need to know the proto defaults
+var restJSON = protojson.MarshalOptions{EmitUnpopulated: true}
+
+// restCall is an RPC called for a REST request
+type restCall func(r *http.Request) (proto.Message, error)
+
+// RESTHandler serves the main RPCs of api over HTTP and JSON, for clients
+// that don't speak gRPC:
+//
+//	GET  /v1/health                    Health
+//	GET  /v1/servers                   ListServers
+//	GET  /v1/servers/{name}            GetServer
+//	POST /v1/servers/{name}/start      StartServer
+//	POST /v1/servers/{name}/stop       StopServer
+//	GET  /v1/servers/{name}/tools      GetTools
+//
+// Responses are the messages of the RPCs in their JSON mapping, and errors
+// {"code": "NotFound", "message": "..."} with the matching HTTP status.
+// POST requests must be sent as application/json, which browsers can't do
+// across origins without a preflight. Requests are authenticated with
+// provider, unless nil, and origins lists the browser origins allowed to
+// call it; empty allows only its own and loopback origins.
+func RESTHandler(api pb.MCPManagerServer, provider auth.Provider, origins []string) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("GET /v1/health", restCall(func(r *http.Request) (proto.Message, error) {
+		return api.Health(r.Context(), &pb.Empty{})
+	}))
+	mux.Handle("GET /v1/servers", restCall(func(r *http.Request) (proto.Message, error) {
+		return api.ListServers(r.Context(), &pb.Empty{})
+	}))
+	mux.Handle("GET /v1/servers/{name}", restCall(func(r *http.Request) (proto.Message, error) {
+		return api.GetServer(r.Context(), &pb.ServerRequest{Name: r.PathValue("name")})
+	}))
+	mux.Handle("POST /v1/servers/{name}/start", restCall(func(r *http.Request) (proto.Message, error) {
+		return api.StartServer(r.Context(), &pb.ServerRequest{Name: r.PathValue("name")})
+	}))
+	mux.Handle("POST /v1/servers/{name}/stop", restCall(func(r *http.Request) (proto.Message, error) {
+		return api.StopServer(r.Context(), &pb.ServerRequest{Name: r.PathValue("name")})
+	}))
+	mux.Handle("GET /v1/servers/{name}/tools", restCall(func(r *http.Request) (proto.Message, error) {
+		return api.GetTools(r.Context(), &pb.ServerRequest{Name: r.PathValue("name")})
+	}))
+
+	return cors.Policy{
+		AllowedOrigins: origins,
+		Local:          true,
+		AllowedMethods: []string{"GET", "POST", "OPTIONS"},
+		AllowedHeaders: []string{"Content-Type", "Authorization"},
+	}.Handler(auth.Require(provider, mux))
+}
+
+// ServeHTTP calls the RPC and writes its response or error as JSON
+func (call restCall) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == http.MethodPost {
+		if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			json.NewEncoder(w).Encode(map[string]string{"code": codes.InvalidArgument.String(), "message": "POST requests must have Content-Type application/json"})
+			return
+		}
+	}
+
+	msg, err := call(r)
+	if err != nil {
+		st := status.Convert(err)
+		w.WriteHeader(restStatus(st.Code()))
+		json.NewEncoder(w).Encode(map[string]string{"code": st.Code().String(), "message": st.Message()})
+		return
+	}
+
+	data, err := restJSON.Marshal(msg)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"code": codes.Internal.String(), "message": err.Error()})
+		return
+	}
+	w.Write(append(data, '\n'))
+}
+
+// restStatus returns the HTTP status of a gRPC error code
+func restStatus(code codes.Code) int {
+	switch code {
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted, codes.FailedPrecondition:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// listenREST listens for the HTTP API on host, the interface the gRPC API
+// listens on. It refuses hosts other machines can reach unless provider
+// authenticates the requests, as the API starts and stops servers.
+func listenREST(host string, port int, provider auth.Provider) (net.Listener, error) {
+	if provider == nil && !isLoopback(host) {
+		return nil, fmt.Errorf("refusing to serve the HTTP API on %s without authentication, configure auth or %s", host, auth.DaemonTokenEnv)
+	}
+	lis, err := net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for the HTTP API: %w", err)
+	}
+	return lis, nil
+}
+
+// isLoopback returns true if host only accepts connections from this
+// machine
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// serveREST serves RESTHandler on lis until ctx is done
+func serveREST(ctx context.Context, handler http.Handler, lis net.Listener, tlsConfig *tls.Config) {
+	srv := &http.Server{Handler: handler}
+	serve := srv.Serve
+	if tlsConfig != nil {
+		srv.TLSConfig = tlsConfig.Clone()
+		serve = func(l net.Listener) error { return srv.ServeTLS(l, "", "") }
+	}
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+	go func() {
+		if err := serve(lis); err != nil && err != http.ErrServerClosed {
+			log.Printf("HTTP API server error: %v", err)
+		}
+	}()
+	log.Printf("HTTP API listening on %s", lis.Addr())
+}
@@ -0,0 +1,157 @@
+package daemon
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/apitest"
+	"github.com/tartavull/mcp-manager/internal/auth"
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// restRequest calls the REST API of handler, decoding its JSON response
+func restRequest(t *testing.T, handler http.Handler, method, path string) (int, map[string]interface{}) {
+	req := httptest.NewRequest(method, path, nil)
+	if method == "POST" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body), rec.Body.String())
+	return rec.Code, body
+}
+
+func TestRESTHandler(t *testing.T) {
+	mgr := apitest.NewManager()
+	require.NoError(t, mgr.AddServer("fs", "echo fs", 4001, "Files"))
+	require.NoError(t, mgr.SetTools("fs", []server.Tool{{Name: "read", Description: "Read a file"}}))
+	handler := RESTHandler(grpc.NewServer(mgr), nil, nil)
+
+	code, body := restRequest(t, handler, "GET", "/v1/servers")
+	assert.Equal(t, http.StatusOK, code)
+	require.Len(t, body["servers"], 1)
+	assert.Equal(t, "fs", body["servers"].([]interface{})[0].(map[string]interface{})["name"])
+
+	code, body = restRequest(t, handler, "POST", "/v1/servers/fs/start")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "RUNNING", body["status"])
+	assert.Equal(t, []string{"start fs"}, mgr.Calls())
+
+	code, body = restRequest(t, handler, "GET", "/v1/health")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, float64(1), body["runningServers"])
+
+	code, body = restRequest(t, handler, "GET", "/v1/servers/fs/tools")
+	assert.Equal(t, http.StatusOK, code)
+	require.Len(t, body["tools"], 1)
+
+	code, body = restRequest(t, handler, "POST", "/v1/servers/fs/stop")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "STOPPED", body["status"])
+
+	// Errors carry the gRPC code
+	code, body = restRequest(t, handler, "GET", "/v1/servers/missing")
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Equal(t, "NotFound", body["code"])
+	assert.Contains(t, body["message"], "missing")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/v1/servers/fs/start", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	// Without corsOrigins only pages on this machine may call it
+	for origin, want := range map[string]int{"http://localhost:3000": http.StatusOK, "https://evil.example": http.StatusForbidden} {
+		req := httptest.NewRequest("GET", "/v1/servers", nil)
+		req.Header.Set("Origin", origin)
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, want, rec.Code, origin)
+	}
+}
+
+func TestRESTHandler_Auth(t *testing.T) {
+	provider, err := auth.Build(&config.AuthConfig{
+		Providers: map[string]*config.AuthProviderConfig{
+			"ci": {Type: "token", Tokens: map[string]string{"ci": "secret"}},
+		},
+	}, []string{"ci"})
+	require.NoError(t, err)
+	handler := RESTHandler(grpc.NewServer(apitest.NewManager()), provider, []string{"https://dash.example.com"})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/v1/servers", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req := httptest.NewRequest("GET", "/v1/servers", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("Origin", "https://dash.example.com")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "https://dash.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestRESTHandler_CrossOrigin(t *testing.T) {
+	mgr := apitest.NewManager()
+	require.NoError(t, mgr.AddServer("fs", "echo fs", 4001, "Files"))
+	handler := RESTHandler(grpc.NewServer(mgr), nil, []string{"https://dash.example.com"})
+
+	// A form POST from another origin never reaches the server
+	req := httptest.NewRequest("POST", "/v1/servers/fs/start", nil)
+	req.Header.Set("Origin", "https://evil.example")
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// Neither does a POST that isn't JSON
+	req = httptest.NewRequest("POST", "/v1/servers/fs/start", nil)
+	req.Header.Set("Content-Type", "text/plain")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+	assert.Empty(t, mgr.Calls())
+
+	req = httptest.NewRequest("POST", "/v1/servers/fs/start", nil)
+	req.Header.Set("Origin", "https://dash.example.com")
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, []string{"start fs"}, mgr.Calls())
+}
+
+func TestListenREST(t *testing.T) {
+	lis, err := listenREST(config.DefaultDaemonBindAddress, 0, nil)
+	require.NoError(t, err)
+	defer lis.Close()
+	host, _, err := net.SplitHostPort(lis.Addr().String())
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", host, "the HTTP API listens on the daemon's bind address")
+
+	// Other machines can't reach the API without authentication
+	_, err = listenREST("0.0.0.0", 0, nil)
+	assert.ErrorContains(t, err, "without authentication")
+	_, err = listenREST("", 0, nil)
+	assert.Error(t, err)
+
+	provider, err := auth.Build(&config.AuthConfig{
+		Providers: map[string]*config.AuthProviderConfig{
+			"ci": {Type: "token", Tokens: map[string]string{"ci": "secret"}},
+		},
+	}, []string{"ci"})
+	require.NoError(t, err)
+	lis, err = listenREST("0.0.0.0", 0, provider)
+	require.NoError(t, err)
+	lis.Close()
+}
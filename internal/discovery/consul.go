@@ -0,0 +1,188 @@
+package discovery
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+)
+
+const (
+	defaultConsulAddress = "http://127.0.0.1:8500"
+
+	// defaultCheckInterval is how often Consul checks a proxy's health
+	defaultCheckInterval = 10 * time.Second
+
+	// deregisterAfter removes services left behind by a daemon that died
+	deregisterAfter = "10m"
+)
+
+// Consul registers services with a Consul agent as "mcp-<server>", tagged
+// "mcp", with an HTTP check against the proxy's /health endpoint
+type Consul struct {
+	address        string
+	token          string
+	host           string
+	serviceAddress string
+	interval       time.Duration
+	client         *http.Client
+
+	mu         sync.Mutex
+	registered map[string]bool
+}
+
+// consulService is the agent's service registration payload
+type consulService struct {
+	ID      string            `json:"ID"`
+	Name    string            `json:"Name"`
+	Tags    []string          `json:"Tags"`
+	Address string            `json:"Address,omitempty"`
+	Port    int               `json:"Port"`
+	Meta    map[string]string `json:"Meta"`
+	Check   consulCheck       `json:"Check"`
+}
+
+// consulCheck is the health check of a registration
+type consulCheck struct {
+	HTTP                           string `json:"HTTP"`
+	Interval                       string `json:"Interval"`
+	Timeout                        string `json:"Timeout"`
+	DeregisterCriticalServiceAfter string `json:"DeregisterCriticalServiceAfter"`
+}
+
+// NewConsul creates an advertiser registering services for host
+func NewConsul(cfg *config.ConsulConfig, host string) (*Consul, error) {
+	c := &Consul{
+		address:        strings.TrimSuffix(cfg.Address, "/"),
+		token:          cfg.Token,
+		host:           host,
+		serviceAddress: cfg.ServiceAddress,
+		interval:       defaultCheckInterval,
+		client:         &http.Client{Timeout: 5 * time.Second},
+		registered:     make(map[string]bool),
+	}
+	if c.address == "" {
+		c.address = defaultConsulAddress
+	}
+
+	if cfg.CheckInterval != "" {
+		interval, err := time.ParseDuration(cfg.CheckInterval)
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("invalid Consul checkInterval '%s'", cfg.CheckInterval)
+		}
+		c.interval = interval
+	}
+
+	return c, nil
+}
+
+// Advertise registers or updates a service
+func (c *Consul) Advertise(svc Service) error {
+	checkHost := c.serviceAddress
+	if checkHost == "" {
+		checkHost = "127.0.0.1"
+	}
+
+	registration := consulService{
+		ID:      c.serviceID(svc.Name),
+		Name:    "mcp-" + svc.Name,
+		Tags:    []string{"mcp"},
+		Address: c.serviceAddress,
+		Port:    svc.Port,
+		Meta: map[string]string{
+			"server": svc.Name,
+			"host":   c.host,
+			"tools":  strconv.Itoa(svc.Tools),
+		},
+		Check: consulCheck{
+			HTTP:                           fmt.Sprintf("http://%s/health", net.JoinHostPort(checkHost, strconv.Itoa(svc.Port))),
+			Interval:                       c.interval.String(),
+			Timeout:                        min(c.interval, 5*time.Second).String(),
+			DeregisterCriticalServiceAfter: deregisterAfter,
+		},
+	}
+
+	body, err := json.Marshal(registration)
+	if err != nil {
+		return fmt.Errorf("failed to marshal Consul service: %w", err)
+	}
+	if err := c.put("/v1/agent/service/register", body); err != nil {
+		return fmt.Errorf("failed to register with Consul: %w", err)
+	}
+
+	c.mu.Lock()
+	c.registered[svc.Name] = true
+	c.mu.Unlock()
+	return nil
+}
+
+// Withdraw deregisters a service
+func (c *Consul) Withdraw(name string) error {
+	c.mu.Lock()
+	registered := c.registered[name]
+	delete(c.registered, name)
+	c.mu.Unlock()
+
+	if !registered {
+		return nil
+	}
+	if err := c.put("/v1/agent/service/deregister/"+c.serviceID(name), nil); err != nil {
+		return fmt.Errorf("failed to deregister from Consul: %w", err)
+	}
+	return nil
+}
+
+// Close deregisters all services
+func (c *Consul) Close() error {
+	c.mu.Lock()
+	names := make([]string, 0, len(c.registered))
+	for name := range c.registered {
+		names = append(names, name)
+	}
+	c.mu.Unlock()
+
+	var firstErr error
+	for _, name := range names {
+		if err := c.Withdraw(name); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// serviceID returns the ID of a server's registration, unique per host
+func (c *Consul) serviceID(name string) string {
+	return fmt.Sprintf("mcp-%s-%s", c.host, name)
+}
+
+// put sends a request to the Consul agent
+func (c *Consul) put(path string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPut, c.address+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("X-Consul-Token", c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("HTTP %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+// Package discovery advertises running MCP proxies on the network, via
+// mDNS and Consul, so clients can find them without hard-coded URLs
+package discovery
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+)
+
+// Service is a running proxy to advertise
+type Service struct {
+	Name  string // Server name
+	Port  int    // HTTP proxy port
+	Tools int    // Number of tools the server provides
+}
+
+// Advertiser makes services discoverable
+type Advertiser interface {
+	// Advertise adds a service, or updates it if already advertised
+	Advertise(svc Service) error
+	// Withdraw removes a service
+	Withdraw(name string) error
+	// Close withdraws all services
+	Close() error
+}
+
+// update is a pending change to the advertised services
+type update struct {
+	svc      Service
+	withdraw bool
+}
+
+// Publisher applies service changes to advertisers in the background, in
+// order, so callers never wait on the network. A nil Publisher does nothing.
+type Publisher struct {
+	advertisers []Advertiser
+	updates     chan update
+	done        chan struct{}
+}
+
+// New creates a publisher for the configured advertisers, or nil if
+// discovery is disabled
+func New(cfg *config.DiscoveryConfig) (*Publisher, error) {
+	if cfg == nil || (!cfg.MDNS && cfg.Consul == nil) {
+		return nil, nil
+	}
+
+	host := Hostname()
+	var advertisers []Advertiser
+
+	if cfg.MDNS {
+		mdns, err := NewMDNS(host)
+		if err != nil {
+			return nil, err
+		}
+		advertisers = append(advertisers, mdns)
+	}
+
+	if cfg.Consul != nil {
+		consul, err := NewConsul(cfg.Consul, host)
+		if err != nil {
+			for _, a := range advertisers {
+				a.Close()
+			}
+			return nil, err
+		}
+		advertisers = append(advertisers, consul)
+	}
+
+	return NewPublisher(advertisers...), nil
+}
+
+// NewPublisher creates a publisher for advertisers
+func NewPublisher(advertisers ...Advertiser) *Publisher {
+	p := &Publisher{
+		advertisers: advertisers,
+		updates:     make(chan update, 64),
+		done:        make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+// Advertise adds or updates a service
+func (p *Publisher) Advertise(svc Service) {
+	if p == nil {
+		return
+	}
+	p.updates <- update{svc: svc}
+}
+
+// Withdraw removes a service
+func (p *Publisher) Withdraw(name string) {
+	if p == nil {
+		return
+	}
+	p.updates <- update{svc: Service{Name: name}, withdraw: true}
+}
+
+// Close applies pending changes and withdraws all services
+func (p *Publisher) Close() error {
+	if p == nil {
+		return nil
+	}
+	close(p.updates)
+	<-p.done
+
+	var errs []string
+	for _, a := range p.advertisers {
+		if err := a.Close(); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to close discovery: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// run applies updates until the publisher is closed
+func (p *Publisher) run() {
+	defer close(p.done)
+
+	for u := range p.updates {
+		for _, a := range p.advertisers {
+			var err error
+			if u.withdraw {
+				err = a.Withdraw(u.svc.Name)
+			} else {
+				err = a.Advertise(u.svc)
+			}
+			if err != nil {
+				log.Printf("Warning: service discovery for %s: %v", u.svc.Name, err)
+			}
+		}
+	}
+}
+
+// Hostname returns the short host name services are advertised under
+func Hostname() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		return "localhost"
+	}
+	host, _, _ = strings.Cut(host, ".")
+	return host
+}
@@ -0,0 +1,122 @@
+package discovery
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/internal/config"
+)
+
+// recorder is an advertiser recording the calls it receives
+type recorder struct {
+	mu     sync.Mutex
+	calls  []string
+	closed bool
+}
+
+func (r *recorder) Advertise(svc Service) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, "advertise "+svc.Name)
+	return nil
+}
+
+func (r *recorder) Withdraw(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, "withdraw "+name)
+	return nil
+}
+
+func (r *recorder) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestPublisher(t *testing.T) {
+	r := &recorder{}
+	p := NewPublisher(r)
+
+	p.Advertise(Service{Name: "github", Port: 4001})
+	p.Withdraw("github")
+	p.Advertise(Service{Name: "memory", Port: 4002})
+	require.NoError(t, p.Close())
+
+	assert.Equal(t, []string{"advertise github", "withdraw github", "advertise memory"}, r.calls)
+	assert.True(t, r.closed)
+
+	// A disabled publisher does nothing
+	var disabled *Publisher
+	disabled.Advertise(Service{Name: "github"})
+	disabled.Withdraw("github")
+	assert.NoError(t, disabled.Close())
+}
+
+func TestNew_Disabled(t *testing.T) {
+	p, err := New(nil)
+	require.NoError(t, err)
+	assert.Nil(t, p)
+
+	p, err = New(&config.DiscoveryConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, p)
+
+	_, err = New(&config.DiscoveryConfig{Consul: &config.ConsulConfig{CheckInterval: "often"}})
+	assert.Error(t, err)
+}
+
+func TestConsul(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+	var registration consulService
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+r.Header.Get("X-Consul-Token"))
+		if r.URL.Path == "/v1/agent/service/register" {
+			body, _ := io.ReadAll(r.Body)
+			json.Unmarshal(body, &registration)
+		}
+	}))
+	defer agent.Close()
+
+	c, err := NewConsul(&config.ConsulConfig{Address: agent.URL, Token: "secret", ServiceAddress: "10.0.0.5"}, "box")
+	require.NoError(t, err)
+
+	require.NoError(t, c.Advertise(Service{Name: "github", Port: 4001, Tools: 12}))
+	assert.Equal(t, "mcp-box-github", registration.ID)
+	assert.Equal(t, "mcp-github", registration.Name)
+	assert.Equal(t, "10.0.0.5", registration.Address)
+	assert.Equal(t, "12", registration.Meta["tools"])
+	assert.Equal(t, "http://10.0.0.5:4001/health", registration.Check.HTTP)
+	assert.Equal(t, "10s", registration.Check.Interval)
+
+	// Withdrawing an unknown service is a no-op
+	require.NoError(t, c.Withdraw("memory"))
+	require.NoError(t, c.Close())
+
+	assert.Equal(t, []string{
+		"PUT /v1/agent/service/register secret",
+		"PUT /v1/agent/service/deregister/mcp-box-github secret",
+	}, requests)
+}
+
+func TestConsul_Error(t *testing.T) {
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "ACL not found", http.StatusForbidden)
+	}))
+	defer agent.Close()
+
+	c, err := NewConsul(&config.ConsulConfig{Address: agent.URL}, "box")
+	require.NoError(t, err)
+
+	err = c.Advertise(Service{Name: "github", Port: 4001})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ACL not found")
+}
@@ -0,0 +1,360 @@
+package discovery
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	// ServiceType is the DNS-SD service type proxies are advertised as
+	ServiceType = "_mcp._tcp.local."
+
+	// servicesEnum lists the service types on the network (RFC 6763 §9)
+	servicesEnum = "_services._dns-sd._udp.local."
+
+	mdnsAddress = "224.0.0.251:5353"
+
+	// mdnsTTL is how long, in seconds, clients cache the records
+	mdnsTTL = 120
+
+	// cacheFlush marks records no other responder on the network owns
+	cacheFlush = 1 << 15
+
+	// maxLabel is the longest label of a DNS name, and maxString the
+	// longest TXT string, in bytes
+	maxLabel  = 63
+	maxString = 255
+)
+
+// MDNS answers mDNS queries for the advertised services, as instances of
+// ServiceType named "<server> on <host>", truncated to a DNS label
+type MDNS struct {
+	host  string
+	conn  *net.UDPConn
+	group *net.UDPAddr
+
+	mu       sync.Mutex
+	services map[string]Service
+}
+
+// NewMDNS starts an mDNS responder for host
+func NewMDNS(host string) (*MDNS, error) {
+	group, err := net.ResolveUDPAddr("udp4", mdnsAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve mDNS address: %w", err)
+	}
+
+	conn, err := net.ListenMulticastUDP("udp4", nil, group)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for mDNS: %w", err)
+	}
+
+	m := newMDNS(host)
+	m.conn = conn
+	m.group = group
+	go m.serve()
+	return m, nil
+}
+
+// newMDNS creates a responder without a connection
+func newMDNS(host string) *MDNS {
+	return &MDNS{
+		host:     truncate(strings.ReplaceAll(host, ".", "-"), maxLabel),
+		services: make(map[string]Service),
+	}
+}
+
+// Advertise adds or updates a service and announces it
+func (m *MDNS) Advertise(svc Service) error {
+	m.mu.Lock()
+	records, err := m.records(svc, mdnsTTL)
+	if err == nil {
+		m.services[svc.Name] = svc
+	}
+	m.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	addresses, err := m.addressRecords(mdnsTTL)
+	if err != nil {
+		return err
+	}
+	return m.send(records, addresses)
+}
+
+// Withdraw removes a service, telling clients to forget it
+func (m *MDNS) Withdraw(name string) error {
+	m.mu.Lock()
+	svc, exists := m.services[name]
+	delete(m.services, name)
+	m.mu.Unlock()
+
+	if !exists {
+		return nil
+	}
+	records, err := m.records(svc, 0)
+	if err != nil {
+		return err
+	}
+	return m.send(records, nil)
+}
+
+// Close withdraws all services and stops the responder
+func (m *MDNS) Close() error {
+	m.mu.Lock()
+	var goodbyes []dnsmessage.Resource
+	for _, svc := range m.services {
+		// Advertise already built each service's records
+		records, _ := m.records(svc, 0)
+		goodbyes = append(goodbyes, records...)
+	}
+	m.services = make(map[string]Service)
+	m.mu.Unlock()
+
+	if len(goodbyes) > 0 {
+		m.send(goodbyes, nil)
+	}
+	return m.conn.Close()
+}
+
+// serve answers queries until the connection is closed
+func (m *MDNS) serve() {
+	buf := make([]byte, 9000)
+	for {
+		n, src, err := m.conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+
+		// Queries from other ports are one-shot "legacy" queries expecting a
+		// direct reply (RFC 6762 §6.7)
+		legacy := src.Port != m.group.Port
+		response, err := m.response(buf[:n], legacy)
+		if err != nil || response == nil {
+			continue
+		}
+
+		dst := m.group
+		if legacy {
+			dst = src
+		}
+		m.conn.WriteToUDP(response, dst)
+	}
+}
+
+// send multicasts an unsolicited response with answers
+func (m *MDNS) send(answers, additionals []dnsmessage.Resource) error {
+	msg := dnsmessage.Message{
+		Header:      dnsmessage.Header{Response: true, Authoritative: true},
+		Answers:     answers,
+		Additionals: additionals,
+	}
+	packet, err := msg.Pack()
+	if err != nil {
+		return fmt.Errorf("failed to pack mDNS response: %w", err)
+	}
+	if _, err := m.conn.WriteToUDP(packet, m.group); err != nil {
+		return fmt.Errorf("failed to send mDNS response: %w", err)
+	}
+	return nil
+}
+
+// response returns the packed response to a query, or nil if it asks
+// about nothing advertised
+func (m *MDNS) response(query []byte, legacy bool) ([]byte, error) {
+	var msg dnsmessage.Message
+	if err := msg.Unpack(query); err != nil {
+		return nil, err
+	}
+	if msg.Header.Response {
+		return nil, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var answers, additionals []dnsmessage.Resource
+	needAddress := false
+
+	for _, q := range msg.Questions {
+		name := q.Name.String()
+		all := q.Type == dnsmessage.TypeALL
+
+		switch {
+		case strings.EqualFold(name, servicesEnum) && (q.Type == dnsmessage.TypePTR || all):
+			if len(m.services) > 0 {
+				ptr, err := m.ptr(servicesEnum, ServiceType, mdnsTTL)
+				if err != nil {
+					return nil, err
+				}
+				answers = append(answers, ptr)
+			}
+
+		case strings.EqualFold(name, ServiceType) && (q.Type == dnsmessage.TypePTR || all):
+			for _, svc := range m.services {
+				records, err := m.records(svc, mdnsTTL)
+				if err != nil {
+					return nil, err
+				}
+				answers = append(answers, records[0])
+				additionals = append(additionals, records[1:]...)
+				needAddress = true
+			}
+
+		case strings.EqualFold(name, m.target()) && (q.Type == dnsmessage.TypeA || all):
+			if len(m.services) > 0 {
+				addresses, err := m.addressRecords(mdnsTTL)
+				if err != nil {
+					return nil, err
+				}
+				answers = append(answers, addresses...)
+			}
+
+		default:
+			for _, svc := range m.services {
+				if !strings.EqualFold(name, m.instance(svc)) {
+					continue
+				}
+				records, err := m.records(svc, mdnsTTL)
+				if err != nil {
+					return nil, err
+				}
+				if q.Type == dnsmessage.TypeSRV || all {
+					answers = append(answers, records[1])
+					needAddress = true
+				}
+				if q.Type == dnsmessage.TypeTXT || all {
+					answers = append(answers, records[2])
+				}
+			}
+		}
+	}
+
+	if len(answers) == 0 {
+		return nil, nil
+	}
+	if needAddress {
+		addresses, err := m.addressRecords(mdnsTTL)
+		if err != nil {
+			return nil, err
+		}
+		additionals = append(additionals, addresses...)
+	}
+
+	response := dnsmessage.Message{
+		Header:      dnsmessage.Header{Response: true, Authoritative: true},
+		Answers:     answers,
+		Additionals: additionals,
+	}
+	if legacy {
+		response.Header.ID = msg.Header.ID
+		response.Questions = msg.Questions
+	}
+	return response.Pack()
+}
+
+// instance returns the DNS-SD instance name of a service
+func (m *MDNS) instance(svc Service) string {
+	return truncate(fmt.Sprintf("%s on %s", strings.ReplaceAll(svc.Name, ".", "-"), m.host), maxLabel) + "." + ServiceType
+}
+
+// truncate cuts s to at most n bytes, without splitting a character
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// target returns the name the host's addresses are published under
+func (m *MDNS) target() string {
+	return m.host + ".local."
+}
+
+// records returns the PTR, SRV and TXT records of a service
+func (m *MDNS) records(svc Service, ttl uint32) ([]dnsmessage.Resource, error) {
+	instance, err := dnsmessage.NewName(m.instance(svc))
+	if err != nil {
+		return nil, fmt.Errorf("invalid mDNS instance name for %s: %w", svc.Name, err)
+	}
+	target, err := dnsmessage.NewName(m.target())
+	if err != nil {
+		return nil, fmt.Errorf("invalid mDNS host name: %w", err)
+	}
+	ptr, err := m.ptr(ServiceType, m.instance(svc), ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	return []dnsmessage.Resource{
+		ptr,
+		{
+			Header: dnsmessage.ResourceHeader{Name: instance, Class: dnsmessage.ClassINET | cacheFlush, TTL: ttl},
+			Body:   &dnsmessage.SRVResource{Port: uint16(svc.Port), Target: target},
+		},
+		{
+			Header: dnsmessage.ResourceHeader{Name: instance, Class: dnsmessage.ClassINET | cacheFlush, TTL: ttl},
+			Body: &dnsmessage.TXTResource{TXT: []string{
+				truncate("name="+svc.Name, maxString),
+				fmt.Sprintf("tools=%d", svc.Tools),
+				"path=/",
+			}},
+		},
+	}, nil
+}
+
+// ptr returns a shared PTR record from name to target
+func (m *MDNS) ptr(name, target string, ttl uint32) (dnsmessage.Resource, error) {
+	from, err := dnsmessage.NewName(name)
+	if err != nil {
+		return dnsmessage.Resource{}, fmt.Errorf("invalid mDNS name %q: %w", name, err)
+	}
+	to, err := dnsmessage.NewName(target)
+	if err != nil {
+		return dnsmessage.Resource{}, fmt.Errorf("invalid mDNS name %q: %w", target, err)
+	}
+	return dnsmessage.Resource{
+		Header: dnsmessage.ResourceHeader{Name: from, Class: dnsmessage.ClassINET, TTL: ttl},
+		Body:   &dnsmessage.PTRResource{PTR: to},
+	}, nil
+}
+
+// addressRecords returns A records for the host's non-loopback IPv4
+// addresses
+func (m *MDNS) addressRecords(ttl uint32) ([]dnsmessage.Resource, error) {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		// Without interfaces there are no addresses to publish
+		return nil, nil
+	}
+	target, err := dnsmessage.NewName(m.target())
+	if err != nil {
+		return nil, fmt.Errorf("invalid mDNS host name: %w", err)
+	}
+
+	var records []dnsmessage.Resource
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.IsLoopback() {
+			continue
+		}
+		ip4 := ipNet.IP.To4()
+		if ip4 == nil {
+			continue
+		}
+		records = append(records, dnsmessage.Resource{
+			Header: dnsmessage.ResourceHeader{Name: target, Class: dnsmessage.ClassINET | cacheFlush, TTL: ttl},
+			Body:   &dnsmessage.AResource{A: [4]byte(ip4)},
+		})
+	}
+	return records, nil
+}
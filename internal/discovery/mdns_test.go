@@ -0,0 +1,96 @@
+package discovery
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// query packs an mDNS query for name
+func query(t *testing.T, name string, qtype dnsmessage.Type) []byte {
+	msg := dnsmessage.Message{
+		Header: dnsmessage.Header{ID: 7},
+		Questions: []dnsmessage.Question{
+			{Name: dnsmessage.MustNewName(name), Type: qtype, Class: dnsmessage.ClassINET},
+		},
+	}
+	packet, err := msg.Pack()
+	require.NoError(t, err)
+	return packet
+}
+
+// answer returns the unpacked response of m to a query
+func answer(t *testing.T, m *MDNS, packet []byte, legacy bool) *dnsmessage.Message {
+	response, err := m.response(packet, legacy)
+	require.NoError(t, err)
+	if response == nil {
+		return nil
+	}
+
+	var msg dnsmessage.Message
+	require.NoError(t, msg.Unpack(response))
+	return &msg
+}
+
+func TestMDNS_Response(t *testing.T) {
+	m := newMDNS("box.lan")
+	m.services["github"] = Service{Name: "github", Port: 4001, Tools: 12}
+
+	// Browsing the service type lists the instance, with its SRV and TXT
+	msg := answer(t, m, query(t, ServiceType, dnsmessage.TypePTR), false)
+	require.NotNil(t, msg)
+	require.Len(t, msg.Answers, 1)
+	assert.Equal(t, "github on box-lan._mcp._tcp.local.", msg.Answers[0].Body.(*dnsmessage.PTRResource).PTR.String())
+	assert.Equal(t, uint16(0), msg.Header.ID)
+
+	var srv *dnsmessage.SRVResource
+	var txt *dnsmessage.TXTResource
+	for _, r := range msg.Additionals {
+		switch body := r.Body.(type) {
+		case *dnsmessage.SRVResource:
+			srv = body
+		case *dnsmessage.TXTResource:
+			txt = body
+		}
+	}
+	require.NotNil(t, srv)
+	assert.Equal(t, uint16(4001), srv.Port)
+	assert.Equal(t, "box-lan.local.", srv.Target.String())
+	require.NotNil(t, txt)
+	assert.Contains(t, txt.TXT, "tools=12")
+
+	// Resolving an instance, case-insensitively, from a legacy client
+	msg = answer(t, m, query(t, "GitHub on box-lan._mcp._tcp.local.", dnsmessage.TypeSRV), true)
+	require.NotNil(t, msg)
+	require.Len(t, msg.Answers, 1)
+	assert.Equal(t, uint16(7), msg.Header.ID)
+	assert.Len(t, msg.Questions, 1)
+
+	// Queries about anything else are ignored
+	assert.Nil(t, answer(t, m, query(t, "_http._tcp.local.", dnsmessage.TypePTR), false))
+
+	delete(m.services, "github")
+	assert.Nil(t, answer(t, m, query(t, ServiceType, dnsmessage.TypePTR), false))
+}
+
+func TestMDNS_LongName(t *testing.T) {
+	m := newMDNS(strings.Repeat("h", 100))
+	name := strings.Repeat("ü", 200)
+	m.services[name] = Service{Name: name, Port: 4001}
+
+	// The instance label is cut to 63 bytes, between characters
+	msg := answer(t, m, query(t, ServiceType, dnsmessage.TypePTR), false)
+	require.NotNil(t, msg)
+	require.Len(t, msg.Answers, 1)
+	instance := msg.Answers[0].Body.(*dnsmessage.PTRResource).PTR.String()
+	assert.Equal(t, strings.Repeat("ü", 31)+"."+ServiceType, instance)
+
+	msg = answer(t, m, query(t, instance, dnsmessage.TypeTXT), false)
+	require.NotNil(t, msg)
+	require.Len(t, msg.Answers, 1)
+	txt := msg.Answers[0].Body.(*dnsmessage.TXTResource).TXT
+	assert.Equal(t, "name="+strings.Repeat("ü", 125), txt[0])
+}
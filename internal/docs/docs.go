@@ -0,0 +1,223 @@
+// Package docs renders the tools of servers, with their schemas and
+// examples from recorded calls, as static HTML or markdown documentation
+package docs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/server"
+	"github.com/tartavull/mcp-manager/internal/transcript"
+)
+
+// Formats lists the formats documentation is written in
+var Formats = []string{"html", "markdown"}
+
+// maxExamples is how many recorded calls document a tool
+const maxExamples = 3
+
+// Server is a server and the tools it documents
+type Server struct {
+	Name        string
+	Description string
+	Tools       []server.Tool
+}
+
+// Example is a recorded call of a tool
+type Example struct {
+	Arguments string // JSON of the redacted arguments
+	Result    string // JSON of the result, possibly shortened
+}
+
+// Site is the documentation of the tools of servers
+type Site struct {
+	Servers   []Server
+	Examples  map[string]map[string][]Example // Server to tool to its examples
+	Generated time.Time
+}
+
+// Param is an argument of a tool, as its input schema describes it
+type Param struct {
+	Name        string
+	Type        string
+	Description string
+	Required    bool
+}
+
+// Examples returns the latest successful calls of each tool in sessions,
+// by server and tool
+func Examples(sessions []transcript.Session) map[string]map[string][]Example {
+	var entries []transcript.Entry
+	for _, session := range sessions {
+		for _, entry := range session.Entries {
+			if entry.Method == "tools/call" && entry.Tool != "" && entry.Error == "" {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time.After(entries[j].Time) })
+
+	examples := make(map[string]map[string][]Example)
+	for _, entry := range entries {
+		if examples[entry.Server] == nil {
+			examples[entry.Server] = make(map[string][]Example)
+		}
+		tools := examples[entry.Server]
+		if len(tools[entry.Tool]) < maxExamples {
+			tools[entry.Tool] = append(tools[entry.Tool], Example{Arguments: indentJSON(entry.Arguments), Result: indentJSON(entry.Result)})
+		}
+	}
+	return examples
+}
+
+// Params returns the arguments an input schema describes, required ones
+// first
+func Params(schema interface{}) []Param {
+	object, _ := schema.(map[string]interface{})
+	properties, _ := object["properties"].(map[string]interface{})
+	required, _ := object["required"].([]interface{})
+
+	params := make([]Param, 0, len(properties))
+	for name, property := range properties {
+		fields, _ := property.(map[string]interface{})
+		description, _ := fields["description"].(string)
+		params = append(params, Param{
+			Name:        name,
+			Type:        schemaType(fields),
+			Description: description,
+			Required:    slices.Contains(required, interface{}(name)),
+		})
+	}
+	sort.Slice(params, func(i, j int) bool {
+		if params[i].Required != params[j].Required {
+			return params[i].Required
+		}
+		return params[i].Name < params[j].Name
+	})
+	return params
+}
+
+// schemaType describes the type of a property, e.g. "array of string" or
+// "one of a, b"
+func schemaType(fields map[string]interface{}) string {
+	if values, ok := fields["enum"].([]interface{}); ok && len(values) > 0 {
+		names := make([]string, len(values))
+		for i, value := range values {
+			names[i] = fmt.Sprint(value)
+		}
+		return "one of " + strings.Join(names, ", ")
+	}
+
+	var kind string
+	switch t := fields["type"].(type) {
+	case string:
+		kind = t
+	case []interface{}:
+		names := make([]string, len(t))
+		for i, name := range t {
+			names[i] = fmt.Sprint(name)
+		}
+		kind = strings.Join(names, " or ")
+	default:
+		return "any"
+	}
+	if items, ok := fields["items"].(map[string]interface{}); ok && kind == "array" {
+		return "array of " + schemaType(items)
+	}
+	return kind
+}
+
+// Write writes the index and a page per server of site to dir, as "html"
+// or "markdown", returning the paths of the files written
+func Write(dir, format string, site *Site) ([]string, error) {
+	var (
+		ext    string
+		render func(*Site, *Server) ([]byte, error)
+	)
+	switch format {
+	case "html":
+		ext, render = ".html", renderHTML
+	case "markdown":
+		ext, render = ".md", renderMarkdown
+	default:
+		return nil, fmt.Errorf("unknown format '%s', use %s", format, strings.Join(Formats, ", "))
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	pages := []struct {
+		name   string
+		server *Server
+	}{{"index", nil}}
+	for i := range site.Servers {
+		pages = append(pages, struct {
+			name   string
+			server *Server
+		}{Slug(site.Servers[i].Name), &site.Servers[i]})
+	}
+
+	var written []string
+	for _, page := range pages {
+		data, err := render(site, page.server)
+		if err != nil {
+			return written, err
+		}
+		path := filepath.Join(dir, page.name+ext)
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return written, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
+
+// Slug names the page of a server, keeping letters, digits, dots,
+// underscores and dashes
+func Slug(name string) string {
+	slug := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '_', r == '-':
+			return r
+		default:
+			return '-'
+		}
+	}, name)
+	if slug == "index" || strings.Trim(slug, ".") == "" {
+		slug = "server-" + slug
+	}
+	return slug
+}
+
+// schemaJSON returns the indented JSON of a tool's input schema, or "" if
+// it has none
+func schemaJSON(tool server.Tool) string {
+	if tool.InputSchema == nil {
+		return ""
+	}
+	data, err := json.MarshalIndent(tool.InputSchema, "", "  ")
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// indentJSON indents JSON, leaving anything else, such as shortened
+// results, as it is
+func indentJSON(data string) string {
+	var value interface{}
+	if json.Unmarshal([]byte(data), &value) != nil {
+		return data
+	}
+	indented, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return data
+	}
+	return string(indented)
+}
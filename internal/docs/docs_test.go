@@ -0,0 +1,112 @@
+package docs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/internal/server"
+	"github.com/tartavull/mcp-manager/internal/transcript"
+)
+
+// testSite documents a server with a tool taking arguments and one without
+func testSite() *Site {
+	return &Site{
+		Generated: time.Date(2026, 10, 17, 9, 30, 0, 0, time.UTC),
+		Servers: []Server{{
+			Name:        "github",
+			Description: "GitHub | issues",
+			Tools: []server.Tool{
+				{Name: "create_issue", Description: "Opens an <issue>", InputSchema: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"title":  map[string]interface{}{"type": "string", "description": "Title of the issue"},
+						"labels": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+						"state":  map[string]interface{}{"enum": []interface{}{"open", "closed"}},
+					},
+					"required": []interface{}{"title"},
+				}},
+				{Name: "whoami"},
+			},
+		}},
+		Examples: map[string]map[string][]Example{
+			"github": {"create_issue": {{Arguments: `{"title": "bug"}`, Result: `{"number": 7}`}}},
+		},
+	}
+}
+
+func TestExamples(t *testing.T) {
+	now := time.Now()
+	sessions := []transcript.Session{{Entries: []transcript.Entry{
+		{Time: now, Server: "github", Method: "tools/call", Tool: "search", Arguments: `{"q":"old"}`, Result: `{}`},
+		{Time: now.Add(time.Second), Server: "github", Method: "tools/call", Tool: "search", Error: "boom"},
+		{Time: now.Add(2 * time.Second), Server: "github", Method: "tools/list", Result: `{}`},
+	}}, {Entries: []transcript.Entry{
+		{Time: now.Add(3 * time.Second), Server: "github", Method: "tools/call", Tool: "search", Arguments: `{"q":"new"}`, Result: `{"text":"abc…`},
+		{Time: now.Add(4 * time.Second), Server: "github", Method: "tools/call", Tool: "search", Arguments: `{"q":"a"}`, Result: `{}`},
+		{Time: now.Add(5 * time.Second), Server: "github", Method: "tools/call", Tool: "search", Arguments: `{"q":"b"}`, Result: `{}`},
+	}}}
+
+	// The latest successful calls, up to three per tool
+	examples := Examples(sessions)
+	search := examples["github"]["search"]
+	require.Len(t, search, maxExamples)
+	assert.Equal(t, "{\n  \"q\": \"b\"\n}", search[0].Arguments)
+	assert.Equal(t, `{"text":"abc…`, search[2].Result, "shortened results are kept as they are")
+	assert.Len(t, examples["github"], 1)
+}
+
+func TestParams(t *testing.T) {
+	params := Params(testSite().Servers[0].Tools[0].InputSchema)
+	assert.Equal(t, []Param{
+		{Name: "title", Type: "string", Description: "Title of the issue", Required: true},
+		{Name: "labels", Type: "array of string"},
+		{Name: "state", Type: "one of open, closed"},
+	}, params)
+
+	assert.Empty(t, Params(nil))
+}
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	written, err := Write(dir, "html", testSite())
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "index.html"), filepath.Join(dir, "github.html")}, written)
+
+	index, err := os.ReadFile(written[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(index), `<a href="github.html">github</a>`)
+	assert.Contains(t, string(index), "2026-10-17 09:30 UTC")
+
+	page, err := os.ReadFile(written[1])
+	require.NoError(t, err)
+	assert.Contains(t, string(page), "Opens an &lt;issue&gt;", "descriptions are escaped")
+	assert.Contains(t, string(page), "<td><code>title</code></td><td>string</td><td>yes</td>")
+	assert.Contains(t, string(page), `<h2>whoami</h2>`)
+	assert.Contains(t, string(page), "{&#34;number&#34;: 7}")
+
+	written, err = Write(dir, "markdown", testSite())
+	require.NoError(t, err)
+	index, err = os.ReadFile(written[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(index), "| [github](github.md) | 2 | GitHub \\| issues |")
+
+	page, err = os.ReadFile(written[1])
+	require.NoError(t, err)
+	assert.Contains(t, string(page), "## create_issue\n\nOpens an <issue>")
+	assert.Contains(t, string(page), "| `labels` | array of string |  |  |")
+	assert.Contains(t, string(page), "### Example\n\n```json\n{\"title\": \"bug\"}\n```")
+
+	_, err = Write(dir, "pdf", testSite())
+	assert.ErrorContains(t, err, "unknown format 'pdf'")
+}
+
+func TestSlug(t *testing.T) {
+	assert.Equal(t, "github", Slug("github"))
+	assert.Equal(t, "my-server-1", Slug("my server/1"))
+	assert.Equal(t, "server-index", Slug("index"))
+	assert.Equal(t, "server-..", Slug(".."))
+}
@@ -0,0 +1,145 @@
+package docs
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// pageTemplate renders the index and server pages of the HTML site
+var pageTemplate = template.Must(template.New("page").Funcs(template.FuncMap{
+	"slug":   Slug,
+	"params": func(tool server.Tool) []Param { return Params(tool.InputSchema) },
+	"schema": schemaJSON,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{if .Server}}{{.Server.Name}} - {{end}}MCP Tools</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", sans-serif; max-width: 60rem; margin: 2rem auto; padding: 0 1rem; color: #1e1e2e; }
+a { color: #1e66f5; }
+pre { background: #eff1f5; padding: .75rem; overflow-x: auto; border-radius: 4px; }
+table { border-collapse: collapse; margin: .5rem 0; }
+th, td { border: 1px solid #ccd0da; padding: .25rem .5rem; text-align: left; vertical-align: top; }
+.tool { border-top: 1px solid #ccd0da; padding-top: .5rem; }
+.muted { color: #6c6f85; }
+</style>
+</head>
+<body>
+{{- if .Server}}
+<p><a href="index.html">All servers</a></p>
+<h1>{{.Server.Name}}</h1>
+{{- with .Server.Description}}
+<p>{{.}}</p>
+{{- end}}
+{{- $examples := index .Site.Examples .Server.Name}}
+{{- range .Server.Tools}}
+<section class="tool" id="{{.Name}}">
+<h2>{{.Name}}</h2>
+{{- with .Title}}
+<p><strong>{{.}}</strong></p>
+{{- end}}
+{{- with .Description}}
+<p>{{.}}</p>
+{{- end}}
+{{- with params .}}
+<table>
+<tr><th>Argument</th><th>Type</th><th>Required</th><th>Description</th></tr>
+{{- range .}}
+<tr><td><code>{{.Name}}</code></td><td>{{.Type}}</td><td>{{if .Required}}yes{{end}}</td><td>{{.Description}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- with schema .}}
+<details><summary>Input schema</summary>
+<pre>{{.}}</pre>
+</details>
+{{- end}}
+{{- range index $examples .Name}}
+<h3>Example</h3>
+<pre>{{.Arguments}}</pre>
+<p class="muted">Result</p>
+<pre>{{.Result}}</pre>
+{{- end}}
+</section>
+{{- end}}
+{{- else}}
+<h1>MCP Tools</h1>
+<p class="muted">Generated {{.Site.Generated.Format "2006-01-02 15:04 MST"}}</p>
+<table>
+<tr><th>Server</th><th>Tools</th><th>Description</th></tr>
+{{- range .Site.Servers}}
+<tr><td><a href="{{slug .Name}}.html">{{.Name}}</a></td><td>{{len .Tools}}</td><td>{{.Description}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+</body>
+</html>
+`))
+
+// renderHTML renders the page of a server, or the index if it is nil
+func renderHTML(site *Site, srv *Server) ([]byte, error) {
+	var b bytes.Buffer
+	if err := pageTemplate.Execute(&b, map[string]interface{}{"Site": site, "Server": srv}); err != nil {
+		return nil, fmt.Errorf("failed to render page: %w", err)
+	}
+	return b.Bytes(), nil
+}
+
+// renderMarkdown renders the page of a server, or the index if it is nil
+func renderMarkdown(site *Site, srv *Server) ([]byte, error) {
+	var b strings.Builder
+	if srv == nil {
+		b.WriteString("# MCP Tools\n\n")
+		fmt.Fprintf(&b, "Generated %s\n\n", site.Generated.Format("2006-01-02 15:04 MST"))
+		b.WriteString("| Server | Tools | Description |\n|---|---|---|\n")
+		for _, s := range site.Servers {
+			fmt.Fprintf(&b, "| [%s](%s.md) | %d | %s |\n", s.Name, Slug(s.Name), len(s.Tools), cell(s.Description))
+		}
+		return []byte(b.String()), nil
+	}
+
+	fmt.Fprintf(&b, "[All servers](index.md)\n\n# %s\n\n", srv.Name)
+	if srv.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", srv.Description)
+	}
+	examples := site.Examples[srv.Name]
+	for _, tool := range srv.Tools {
+		fmt.Fprintf(&b, "## %s\n\n", tool.Name)
+		if tool.Title != "" {
+			fmt.Fprintf(&b, "**%s**\n\n", tool.Title)
+		}
+		if tool.Description != "" {
+			fmt.Fprintf(&b, "%s\n\n", tool.Description)
+		}
+		if params := Params(tool.InputSchema); len(params) > 0 {
+			b.WriteString("| Argument | Type | Required | Description |\n|---|---|---|---|\n")
+			for _, param := range params {
+				required := ""
+				if param.Required {
+					required = "yes"
+				}
+				fmt.Fprintf(&b, "| `%s` | %s | %s | %s |\n", param.Name, cell(param.Type), required, cell(param.Description))
+			}
+			b.WriteString("\n")
+		}
+		if schema := schemaJSON(tool); schema != "" {
+			fmt.Fprintf(&b, "<details><summary>Input schema</summary>\n\n```json\n%s\n```\n\n</details>\n\n", schema)
+		}
+		for _, example := range examples[tool.Name] {
+			fmt.Fprintf(&b, "### Example\n\n```json\n%s\n```\n\nResult:\n\n```json\n%s\n```\n\n", example.Arguments, example.Result)
+		}
+	}
+	return []byte(b.String()), nil
+}
+
+// cell escapes text for a markdown table cell
+func cell(text string) string {
+	text = strings.ReplaceAll(text, "|", `\|`)
+	return strings.Join(strings.Fields(text), " ")
+}
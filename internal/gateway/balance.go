@@ -0,0 +1,161 @@
+package gateway
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Strategies spreading the calls of a server with instances over them
+const (
+	RoundRobin    = "round-robin"     // Each instance in turn
+	LeastInFlight = "least-in-flight" // The instance answering the fewest calls
+	Latency       = "latency"         // The instance expected to answer first
+)
+
+// Strategies lists the balancing strategies, the default first
+var Strategies = []string{RoundRobin, LeastInFlight, Latency}
+
+// latencyWeight is the weight of the latest call in the moving average
+// latency of an instance
+const latencyWeight = 0.2
+
+// InstanceStats counts the calls sent to an instance of a server
+type InstanceStats struct {
+	Server   string  `json:"server"`    // Server the instance belongs to
+	Instance string  `json:"instance"`  // Name of the instance
+	InFlight int     `json:"inFlight"`  // Calls the instance is answering
+	Calls    int     `json:"calls"`     // Calls answered
+	Errors   int     `json:"errors"`    // Calls the instance failed to answer
+	Latency  float64 `json:"latencyMs"` // Moving average of the time the instance took to answer
+
+	latency time.Duration
+}
+
+// balancer picks the instances taking the calls of servers with instances
+type balancer struct {
+	mu    sync.Mutex
+	next  map[string]int            // Server to the instance it tries first
+	stats map[string]*InstanceStats // Instance to its counts
+}
+
+// newBalancer creates a balancer without counts
+func newBalancer() *balancer {
+	return &balancer{next: make(map[string]int), stats: make(map[string]*InstanceStats)}
+}
+
+// pick returns the instance of server taking a call with strategy, counting
+// the call in flight until done is called. Ties go to the instances in
+// turn, so idle instances share calls under any strategy.
+func (b *balancer) pick(server, strategy string, instances []Instance) Instance {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	first := b.next[server] % len(instances)
+	picked, best := first, -1.0
+	for i := range instances {
+		index := (first + i) % len(instances)
+		if score := b.score(strategy, server, instances[index].Server); best < 0 || score < best {
+			picked, best = index, score
+		}
+	}
+	b.next[server] = first + 1
+
+	b.instance(server, instances[picked].Server).InFlight++
+	return instances[picked]
+}
+
+// score returns how loaded an instance is under strategy, lowest first.
+// Must be called with b.mu held.
+func (b *balancer) score(strategy, server, instance string) float64 {
+	stats := b.instance(server, instance)
+	switch strategy {
+	case LeastInFlight:
+		return float64(stats.InFlight)
+	case Latency:
+		// Instances without calls yet score 0 so they are tried
+		return float64(stats.latency) * float64(stats.InFlight+1)
+	default:
+		return 0
+	}
+}
+
+// instance returns the counts of an instance. Must be called with b.mu
+// held.
+func (b *balancer) instance(server, instance string) *InstanceStats {
+	stats, exists := b.stats[instance]
+	if !exists || stats.Server != server {
+		stats = &InstanceStats{Server: server, Instance: instance}
+		b.stats[instance] = stats
+	}
+	return stats
+}
+
+// done counts a call an instance answered
+func (b *balancer) done(server, instance string, took time.Duration, failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stats := b.instance(server, instance)
+	stats.InFlight--
+	stats.Calls++
+	if failed {
+		stats.Errors++
+	}
+	if stats.Calls == 1 {
+		stats.latency = took
+	} else {
+		stats.latency = time.Duration(latencyWeight*float64(took) + (1-latencyWeight)*float64(stats.latency))
+	}
+}
+
+// Stats returns the counts of the instances, by server and then instance
+func (b *balancer) Stats() []InstanceStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stats := make([]InstanceStats, 0, len(b.stats))
+	for _, s := range b.stats {
+		s.Latency = float64(s.latency.Microseconds()) / 1000
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Server != stats[j].Server {
+			return stats[i].Server < stats[j].Server
+		}
+		return stats[i].Instance < stats[j].Instance
+	})
+	return stats
+}
+
+// strategy returns the balancing strategy of a server with instances
+func (g *Gateway) strategy(server string) string {
+	if settings := g.cfg.Servers[server]; settings != nil && settings.Balance != "" {
+		return settings.Balance
+	}
+	return RoundRobin
+}
+
+// send forwards a call to a server, or to the instance its strategy picks
+// if it has instances
+func (g *Gateway) send(server string, table *Table, tool string, params map[string]interface{}, from caller) (json.RawMessage, *rpcError) {
+	return g.balance(server, table, func(name string, port int) (json.RawMessage, *rpcError) {
+		return g.forward(name, port, tool, params, from)
+	})
+}
+
+// balance makes call to the proxy of a server, or of the instance its
+// strategy picks if it has instances
+func (g *Gateway) balance(server string, table *Table, call func(server string, port int) (json.RawMessage, *rpcError)) (json.RawMessage, *rpcError) {
+	instances, pooled := table.Pools[server]
+	if !pooled {
+		return call(server, table.Ports[server])
+	}
+
+	instance := g.balancer.pick(server, g.strategy(server), instances)
+	start := time.Now()
+	result, rpcErr := call(instance.Server, instance.Port)
+	g.balancer.done(server, instance.Server, time.Since(start), rpcErr != nil)
+	return result, rpcErr
+}
@@ -0,0 +1,145 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/apitest"
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// addInstance adds a running instance of group with tools to source
+func addInstance(t *testing.T, source *apitest.Manager, group, name string, port int, names ...string) {
+	t.Helper()
+
+	addServer(t, source, name, port, names...)
+	srv, err := source.GetServer(name)
+	require.NoError(t, err)
+	srv.Group = group
+}
+
+func TestBalancer(t *testing.T) {
+	instances := []Instance{{Server: "a-1", Port: 1}, {Server: "a-2", Port: 2}, {Server: "a-3", Port: 3}}
+
+	t.Run("round-robin", func(t *testing.T) {
+		b := newBalancer()
+		var picked []string
+		for i := 0; i < 4; i++ {
+			picked = append(picked, b.pick("a", RoundRobin, instances).Server)
+		}
+		assert.Equal(t, []string{"a-1", "a-2", "a-3", "a-1"}, picked)
+	})
+
+	t.Run("least-in-flight", func(t *testing.T) {
+		b := newBalancer()
+		assert.Equal(t, "a-1", b.pick("a", LeastInFlight, instances).Server)
+		assert.Equal(t, "a-2", b.pick("a", LeastInFlight, instances).Server)
+		b.done("a", "a-1", time.Millisecond, false)
+
+		// a-1 finished its call, a-2 is still answering
+		assert.Equal(t, "a-3", b.pick("a", LeastInFlight, instances).Server)
+		assert.Equal(t, "a-1", b.pick("a", LeastInFlight, instances).Server)
+	})
+
+	t.Run("latency", func(t *testing.T) {
+		b := newBalancer()
+		for _, instance := range instances {
+			b.pick("a", Latency, []Instance{instance})
+		}
+		b.done("a", "a-1", 30*time.Millisecond, false)
+		b.done("a", "a-2", 10*time.Millisecond, false)
+		b.done("a", "a-3", 50*time.Millisecond, false)
+
+		assert.Equal(t, "a-2", b.pick("a", Latency, instances).Server)
+		// Calls in flight slow the instance down: 10ms * 2 < 30ms
+		assert.Equal(t, "a-2", b.pick("a", Latency, instances).Server)
+		// 10ms * 3 ties with 30ms, a-1 comes first in turn
+		assert.Equal(t, "a-1", b.pick("a", Latency, instances).Server)
+
+		// A slow call raises the moving average
+		b.done("a", "a-2", 110*time.Millisecond, true)
+		stats := b.Stats()
+		require.Len(t, stats, 3)
+		assert.Equal(t, InstanceStats{Server: "a", Instance: "a-2", InFlight: 1, Calls: 2, Errors: 1, Latency: 30, latency: 30 * time.Millisecond}, stats[1])
+	})
+}
+
+func TestGateway_Instances(t *testing.T) {
+	var calls [3][]string
+	source := apitest.NewManager()
+	addServer(t, source, "github", fakeProxy(t, &calls[0]), "search")
+	addInstance(t, source, "browser", "browser-1", fakeProxy(t, &calls[1]), "navigate")
+	addInstance(t, source, "browser", "browser-2", fakeProxy(t, &calls[2]), "navigate")
+	g := New(source, &config.GatewayConfig{Servers: map[string]*config.GatewayServerConfig{"browser": {Prefix: "web"}}})
+
+	// Instances are served as one server named after their entry
+	table, conflicts, err := g.Resolve()
+	require.NoError(t, err)
+	assert.Empty(t, conflicts)
+	assert.Equal(t, []string{"search", "navigate"}, []string{table.Tools[0].Name, table.Tools[1].Name})
+	assert.Equal(t, "browser", table.Routes["navigate"].Server)
+	browser1, err := source.GetServer("browser-1")
+	require.NoError(t, err)
+	browser2, err := source.GetServer("browser-2")
+	require.NoError(t, err)
+	assert.Equal(t, []Instance{{"browser-1", browser1.Port}, {"browser-2", browser2.Port}}, table.Pools["browser"])
+
+	for i := 0; i < 3; i++ {
+		post(t, g, `{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": {"name": "navigate"}}`)
+	}
+	assert.Len(t, calls[1], 2)
+	assert.Len(t, calls[2], 1)
+
+	// Draining instances get no new calls
+	browser1.Draining = true
+	post(t, g, `{"jsonrpc": "2.0", "id": 2, "method": "tools/call", "params": {"name": "navigate"}}`)
+	post(t, g, `{"jsonrpc": "2.0", "id": 3, "method": "tools/call", "params": {"name": "navigate"}}`)
+	assert.Len(t, calls[1], 2)
+	assert.Len(t, calls[2], 3)
+
+	w := httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/instances", nil))
+	var resp struct {
+		Instances []InstanceStats `json:"instances"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Instances, 2)
+	assert.Equal(t, "browser-1", resp.Instances[0].Instance)
+	assert.Equal(t, 2, resp.Instances[0].Calls)
+	assert.Equal(t, 3, resp.Instances[1].Calls)
+	assert.Zero(t, resp.Instances[1].InFlight)
+
+	// Without instances left, the server's tools are gone
+	require.NoError(t, source.SetStatus("browser-2", server.StatusStopped))
+	resp2 := post(t, g, `{"jsonrpc": "2.0", "id": 4, "method": "tools/call", "params": {"name": "navigate"}}`)
+	assert.Equal(t, float64(codeInvalidParams), resp2["error"].(map[string]interface{})["code"])
+}
+
+func TestGatewayProblems_Balance(t *testing.T) {
+	source := apitest.NewManager()
+	addServer(t, source, "github", 4001, "search")
+	addInstance(t, source, "browser", "browser-1", 4002, "navigate")
+
+	servers, _, err := source.GetServers()
+	require.NoError(t, err)
+	problems := gatewayProblems(&config.GatewayConfig{
+		Port: 4000,
+		Servers: map[string]*config.GatewayServerConfig{
+			"browser": {Balance: LeastInFlight},
+			"github":  {Balance: Latency},
+			"jira":    {Balance: "random"},
+		},
+		Routes: []config.GatewayRoute{{Tool: "search", Server: "browser"}},
+	}, servers)
+	assert.Equal(t, []string{
+		"gateway balances 'github', which has no instances",
+		"gateway settings for unknown server 'jira'",
+		"gateway balance 'random' for 'jira' is unknown, use round-robin, least-in-flight, latency",
+	}, problems)
+}
@@ -0,0 +1,161 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+)
+
+// Policies combining the results of a call fanned out to several servers
+const (
+	Fastest = "fastest" // The first successful result
+	Merge   = "merge"   // The content of all successful results, in the order of the servers
+)
+
+// Policies lists the fan-out policies, the default first
+var Policies = []string{Fastest, Merge}
+
+// defaultFanOutTimeout is the longest wait for the servers a call is fanned
+// out to, unless a rule sets another
+const defaultFanOutTimeout = 10 * time.Second
+
+// fanOutTarget is a server a call is fanned out to, with its name of the
+// tool
+type fanOutTarget struct {
+	Server string
+	Tool   string
+}
+
+// fanOutAnswer is the answer of a server to a fanned-out call
+type fanOutAnswer struct {
+	index  int // Of the server in the targets
+	result json.RawMessage
+	err    *rpcError
+}
+
+// failed returns true if the server failed to answer or answered with a
+// tool error
+func (a fanOutAnswer) failed() bool {
+	return toolFailed(a.result, a.err)
+}
+
+// fanOutRule returns the fan-out rule of a gateway tool, or nil
+func (g *Gateway) fanOutRule(tool string) *config.GatewayFanOut {
+	for i := range g.cfg.FanOut {
+		if g.cfg.FanOut[i].Tool == tool {
+			return &g.cfg.FanOut[i]
+		}
+	}
+	return nil
+}
+
+// fanOutTargets returns the running servers a rule calls a tool on, the
+// server exposing it first unless the rule lists it elsewhere
+func fanOutTargets(rule *config.GatewayFanOut, route Route, table *Table) []fanOutTarget {
+	servers := rule.Servers
+	if !slices.Contains(servers, route.Server) {
+		servers = append([]string{route.Server}, servers...)
+	}
+
+	var targets []fanOutTarget
+	for _, server := range servers {
+		if _, running := table.Ports[server]; !running {
+			continue
+		}
+		tool := route.Tool
+		if name := rule.Tools[server]; name != "" && server != route.Server {
+			tool = name
+		}
+		targets = append(targets, fanOutTarget{Server: server, Tool: tool})
+	}
+	return targets
+}
+
+// readOnlyTool returns true if a gateway tool is annotated read-only, so
+// calling it on several servers is safe
+func readOnlyTool(table *Table, name string) bool {
+	for _, tool := range table.Tools {
+		if tool.Name == name {
+			return tool.Annotations != nil && tool.Annotations.ReadOnlyHint
+		}
+	}
+	return false
+}
+
+// fanOut calls a tool on all targets at once and combines their answers
+// with the rule's policy. Answers later than the rule's timeout are left
+// out. The first failure is returned when no server succeeds.
+func (g *Gateway) fanOut(rule *config.GatewayFanOut, targets []fanOutTarget, table *Table, params map[string]interface{}, from caller) (json.RawMessage, *rpcError) {
+	timeout := defaultFanOutTimeout
+	if d, err := time.ParseDuration(rule.Timeout); err == nil && d > 0 {
+		timeout = d
+	}
+
+	answers := make(chan fanOutAnswer, len(targets))
+	for i, target := range targets {
+		go func() {
+			result, rpcErr := g.send(target.Server, table, target.Tool, params, from)
+			answers <- fanOutAnswer{index: i, result: result, err: rpcErr}
+		}()
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	var succeeded, failures []fanOutAnswer
+wait:
+	for range targets {
+		select {
+		case answer := <-answers:
+			if answer.failed() {
+				failures = append(failures, answer)
+				continue
+			}
+			if rule.Policy != Merge {
+				return answer.result, nil
+			}
+			succeeded = append(succeeded, answer)
+		case <-deadline.C:
+			break wait
+		}
+	}
+
+	if len(succeeded) > 0 {
+		return mergeResults(succeeded, targets)
+	}
+	if len(failures) > 0 {
+		return failures[0].result, failures[0].err
+	}
+	return nil, &rpcError{Code: codeInternalError, Message: fmt.Sprintf("No server answered %s within %s", rule.Tool, timeout)}
+}
+
+// mergeResults concatenates the content of tool results in the order of
+// their servers, listing the servers in _meta
+func mergeResults(answers []fanOutAnswer, targets []fanOutTarget) (json.RawMessage, *rpcError) {
+	slices.SortFunc(answers, func(a, b fanOutAnswer) int { return a.index - b.index })
+
+	content := []json.RawMessage{}
+	servers := make([]string, 0, len(answers))
+	for _, answer := range answers {
+		var result struct {
+			Content []json.RawMessage `json:"content"`
+		}
+		if err := json.Unmarshal(answer.result, &result); err != nil {
+			continue
+		}
+		content = append(content, result.Content...)
+		servers = append(servers, targets[answer.index].Server)
+	}
+
+	merged, err := json.Marshal(map[string]interface{}{
+		"content": content,
+		"_meta":   map[string]interface{}{"servers": servers},
+	})
+	if err != nil {
+		return nil, &rpcError{Code: codeInternalError, Message: err.Error()}
+	}
+	return merged, nil
+}
@@ -0,0 +1,145 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/apitest"
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// answerProxy is a server proxy answering tool calls with text after
+// delay, as a tool error if failing is set, and counting the calls
+func answerProxy(t *testing.T, text string, delay time.Duration, failing bool, calls *atomic.Int32) int {
+	t.Helper()
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		time.Sleep(delay)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      1,
+			"result": map[string]interface{}{
+				"content": []map[string]string{{"type": "text", "text": text}},
+				"isError": failing,
+			},
+		})
+	}))
+	t.Cleanup(proxy.Close)
+	return proxy.Listener.Addr().(*net.TCPAddr).Port
+}
+
+// readOnly marks the tools of a server of source read-only
+func readOnly(t *testing.T, source *apitest.Manager, name string) {
+	t.Helper()
+
+	srv, err := source.GetServer(name)
+	require.NoError(t, err)
+	for i := range srv.Tools {
+		srv.Tools[i].Annotations = &server.ToolAnnotations{ReadOnlyHint: true}
+	}
+}
+
+// texts returns the texts in the content of a tool result
+func texts(result interface{}) []string {
+	var texts []string
+	for _, item := range result.(map[string]interface{})["content"].([]interface{}) {
+		texts = append(texts, item.(map[string]interface{})["text"].(string))
+	}
+	return texts
+}
+
+func TestGateway_FanOut(t *testing.T) {
+	var braveCalls, exaCalls, brokenCalls atomic.Int32
+	source := apitest.NewManager()
+	addServer(t, source, "brave", answerProxy(t, "brave", 300*time.Millisecond, false, &braveCalls), "web_search")
+	addServer(t, source, "exa", answerProxy(t, "exa", 0, false, &exaCalls), "search")
+	addServer(t, source, "broken", answerProxy(t, "broken", 0, true, &brokenCalls), "web_search")
+	addServer(t, source, "offline", 0, "web_search")
+	for _, name := range []string{"brave", "exa", "broken"} {
+		readOnly(t, source, name)
+	}
+	rule := config.GatewayFanOut{
+		Tool:    "web_search",
+		Servers: []string{"exa", "broken", "offline"},
+		Tools:   map[string]string{"exa": "search"},
+	}
+	g := New(source, &config.GatewayConfig{FanOut: []config.GatewayFanOut{rule}})
+	call := `{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": {"name": "web_search"}}`
+
+	// The fastest success wins over failures and slower servers
+	resp := post(t, g, call)
+	assert.Equal(t, []string{"exa"}, texts(resp["result"]))
+	assert.Eventually(t, func() bool { return braveCalls.Load() == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(1), exaCalls.Load())
+	assert.Equal(t, int32(1), brokenCalls.Load())
+
+	// Merged results keep the order of the servers, leaving failures out
+	rule.Policy = Merge
+	g = New(source, &config.GatewayConfig{FanOut: []config.GatewayFanOut{rule}})
+	resp = post(t, g, call)
+	result := resp["result"].(map[string]interface{})
+	assert.Equal(t, []string{"brave", "exa"}, texts(result))
+	assert.Equal(t, map[string]interface{}{"servers": []interface{}{"brave", "exa"}}, result["_meta"])
+
+	// Servers later than the timeout are left out
+	rule.Timeout = "50ms"
+	g = New(source, &config.GatewayConfig{FanOut: []config.GatewayFanOut{rule}})
+	resp = post(t, g, call)
+	assert.Equal(t, []string{"exa"}, texts(resp["result"]))
+}
+
+func TestGateway_FanOutFailures(t *testing.T) {
+	var primaryCalls, otherCalls atomic.Int32
+	source := apitest.NewManager()
+	addServer(t, source, "primary", answerProxy(t, "primary failed", 0, true, &primaryCalls), "lookup")
+	addServer(t, source, "other", answerProxy(t, "other failed", 50*time.Millisecond, true, &otherCalls), "lookup")
+	g := New(source, &config.GatewayConfig{FanOut: []config.GatewayFanOut{{Tool: "lookup", Servers: []string{"other"}}}})
+	call := `{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": {"name": "lookup"}}`
+
+	// Tools not annotated read-only go to their server alone
+	post(t, g, call)
+	assert.Equal(t, int32(1), primaryCalls.Load())
+	assert.Zero(t, otherCalls.Load())
+
+	// Without a success, the first failure is returned
+	readOnly(t, source, "primary")
+	resp := post(t, g, call)
+	result := resp["result"].(map[string]interface{})
+	assert.Equal(t, true, result["isError"])
+	assert.Equal(t, []string{"primary failed"}, texts(result))
+	assert.Equal(t, int32(1), otherCalls.Load())
+}
+
+func TestGatewayProblems_FanOut(t *testing.T) {
+	source := apitest.NewManager()
+	addServer(t, source, "brave", 4001, "web_search")
+	addServer(t, source, "exa", 4002, "search")
+
+	servers, _, err := source.GetServers()
+	require.NoError(t, err)
+	problems := gatewayProblems(&config.GatewayConfig{
+		Port: 4000,
+		FanOut: []config.GatewayFanOut{
+			{Tool: "web_search", Servers: []string{"exa"}, Policy: Merge, Timeout: "5s"},
+			{Servers: []string{"exa"}},
+			{Tool: "search"},
+			{Tool: "fetch", Servers: []string{"jina"}, Policy: "random", Timeout: "soon"},
+		},
+	}, servers)
+	assert.Equal(t, []string{
+		"gateway fan-out 2 requires a tool",
+		"gateway fan-out for 'search' requires servers",
+		"gateway fan-out for 'fetch' names unknown server 'jina'",
+		"gateway fan-out policy 'random' for 'fetch' is unknown, use fastest, merge",
+		"gateway fan-out for 'fetch' has invalid timeout 'soon'",
+	}, problems)
+}
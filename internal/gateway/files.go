@@ -0,0 +1,378 @@
+package gateway
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// filesPrefix is the path of the endpoint up- and downloading the files of
+// servers, followed by the server and the path of the file
+const filesPrefix = "/files/"
+
+// defaultMaxUploadMB bounds uploads unless the config sets maxUploadMB
+const defaultMaxUploadMB = 64
+
+// The file tools of servers the files endpoint bridges to, in order of
+// preference
+var (
+	writeTools = []string{"write_file"}
+	readTools  = []string{"read_text_file", "read_file"}
+)
+
+// content is an item of the content of a tool result or a resource
+type content struct {
+	Type     string   `json:"type"`
+	Text     string   `json:"text"`
+	Data     string   `json:"data"` // Base64 of images and audio
+	Blob     string   `json:"blob"` // Base64 of binary resources
+	MimeType string   `json:"mimeType"`
+	Resource *content `json:"resource"` // Embedded resource
+}
+
+// serveFile transfers files between clients and servers, so clients don't
+// embed their content in JSON-RPC bodies. PUT /files/<server>/<path>
+// uploads the body, and POST its "file" multipart part, streaming it to
+// the server's write_file tool. GET downloads the file with its read_file
+// tool, or as a file:// resource if it has none.
+func (g *Gateway) serveFile(w http.ResponseWriter, r *http.Request, from caller) {
+	server, path, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, filesPrefix), "/")
+	if !ok || server == "" || path == "" {
+		http.Error(w, "Expected /files/<server>/<path>", http.StatusBadRequest)
+		return
+	}
+	path = "/" + path
+
+	table, _, err := g.Resolve()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if _, running := table.Ports[server]; !running {
+		http.Error(w, fmt.Sprintf("Server '%s' isn't running", server), http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut, http.MethodPost:
+		g.uploadFile(w, r, table, server, path, from)
+	case http.MethodGet:
+		g.downloadFile(w, table, server, path, from)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// uploadFile streams the uploaded content to path with the write tool of
+// server. It's sent base64-encoded if the tool takes an encoding, so
+// binary files can be uploaded, or else as text, which must be UTF-8.
+func (g *Gateway) uploadFile(w http.ResponseWriter, r *http.Request, table *Table, server, path string, from caller) {
+	tool := serverTool(table, server, writeTools)
+	if tool == "" {
+		http.Error(w, fmt.Sprintf("Server '%s' has no write_file tool", server), http.StatusNotImplemented)
+		return
+	}
+
+	limit := g.cfg.MaxUploadMB
+	if limit <= 0 {
+		limit = defaultMaxUploadMB
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, int64(limit)<<20)
+
+	var body io.Reader = r.Body
+	if r.Method == http.MethodPost {
+		part, err := filePart(r)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Invalid upload: %v", err), http.StatusBadRequest)
+			return
+		}
+		body = part
+	}
+	upload := &countingReader{reader: body}
+	binary := takesBase64(table, server, tool)
+
+	result, rpcErr := g.balance(server, table, func(name string, port int) (json.RawMessage, *rpcError) {
+		request, done := writeRequest(tool, path, upload, binary)
+		result, rpcErr := g.postBody(name, port, request, from)
+		request.Close()
+		// Errors writing to the closed request are the call's
+		if err := <-done; errors.Is(err, errNotText) {
+			upload.err = err
+		}
+		return result, rpcErr
+	})
+	if err := upload.err; err != nil {
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.As(err, &tooLarge):
+			http.Error(w, fmt.Sprintf("Files are limited to %d MB", limit), http.StatusRequestEntityTooLarge)
+		case errors.Is(err, errNotText):
+			http.Error(w, fmt.Sprintf("Only UTF-8 text files can be uploaded: %s takes the content as text, so binary files would be corrupted", tool), http.StatusUnsupportedMediaType)
+		default:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+	if toolFailed(result, rpcErr) {
+		http.Error(w, failure(result, rpcErr), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{"server": server, "path": path, "bytes": upload.n})
+}
+
+// errNotText reports content that the write tool can't take as text
+var errNotText = errors.New("the content isn't UTF-8 text")
+
+// countingReader counts the bytes read from reader and keeps its error
+type countingReader struct {
+	reader io.Reader
+	n      int64
+	err    error
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.reader.Read(p)
+	c.n += int64(n)
+	if err != nil && err != io.EOF {
+		c.err = err
+	}
+	return n, err
+}
+
+// writeRequest returns a reader of the JSON-RPC request calling tool to
+// write the content of upload to path, writing it as it's read. Closing the
+// reader stops the writing, whose error done then reports.
+func writeRequest(tool, path string, upload io.Reader, binary bool) (*io.PipeReader, <-chan error) {
+	reader, writer := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		err := writeCall(writer, tool, path, upload, binary)
+		writer.CloseWithError(err)
+		done <- err
+	}()
+	return reader, done
+}
+
+// writeCall writes the request of writeRequest to w
+func writeCall(w io.Writer, tool, path string, upload io.Reader, binary bool) error {
+	name, _ := json.Marshal(tool)
+	file, _ := json.Marshal(path)
+	if _, err := fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":%s,"arguments":{"path":%s,"content":"`, name, file); err != nil {
+		return err
+	}
+
+	end := `"}}}`
+	if binary {
+		encoder := base64.NewEncoder(base64.StdEncoding, w)
+		if _, err := io.Copy(encoder, upload); err != nil {
+			return err
+		}
+		if err := encoder.Close(); err != nil {
+			return err
+		}
+		end = `","encoding":"base64"}}}`
+	} else if err := copyText(w, upload); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, end)
+	return err
+}
+
+// copyText copies the UTF-8 text read from r to w escaped as the content of
+// a JSON string, failing with errNotText on other bytes
+func copyText(w io.Writer, r io.Reader) error {
+	buf := make([]byte, 32<<10)
+	carry := 0 // Bytes of a rune split across reads, at the start of buf
+	for {
+		n, err := r.Read(buf[carry:])
+		n += carry
+		// Keep the bytes of a rune that may be completed by the next read
+		end := n
+		for i := n - 1; i >= 0 && i >= n-utf8.UTFMax+1; i-- {
+			if utf8.RuneStart(buf[i]) {
+				if !utf8.FullRune(buf[i:n]) {
+					end = i
+				}
+				break
+			}
+		}
+		if err == io.EOF {
+			end = n
+		}
+		if !utf8.Valid(buf[:end]) {
+			return errNotText
+		}
+		if end > 0 {
+			quoted, _ := json.Marshal(string(buf[:end]))
+			if _, werr := w.Write(quoted[1 : len(quoted)-1]); werr != nil {
+				return werr
+			}
+		}
+		carry = copy(buf, buf[end:n])
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// takesBase64 reports whether tool of server takes base64 content, as its
+// input has an encoding that allows it
+func takesBase64(table *Table, server, tool string) bool {
+	for _, exposed := range table.Tools {
+		route := table.Routes[exposed.Name]
+		if route.Server != server || route.Tool != tool {
+			continue
+		}
+		data, _ := json.Marshal(exposed.InputSchema)
+		var schema struct {
+			Properties struct {
+				Encoding *struct {
+					Enum []string `json:"enum"`
+				} `json:"encoding"`
+			} `json:"properties"`
+		}
+		if json.Unmarshal(data, &schema) != nil || schema.Properties.Encoding == nil {
+			return false
+		}
+		enum := schema.Properties.Encoding.Enum
+		if len(enum) == 0 {
+			return true
+		}
+		for _, value := range enum {
+			if value == "base64" {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+// filePart returns the "file" part of a multipart upload
+func filePart(r *http.Request) (io.Reader, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, fmt.Errorf("expected a multipart form: %w", err)
+	}
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			return nil, fmt.Errorf("expected a \"file\" part: %w", err)
+		}
+		if part.FormName() == "file" {
+			return part, nil
+		}
+	}
+}
+
+// downloadFile streams the file at path of server with its read tool, or
+// as a resource
+func (g *Gateway) downloadFile(w http.ResponseWriter, table *Table, server, path string, from caller) {
+	var items []content
+	if tool := serverTool(table, server, readTools); tool != "" {
+		result, rpcErr := g.send(server, table, tool, map[string]interface{}{"arguments": map[string]interface{}{"path": path}}, from)
+		if toolFailed(result, rpcErr) {
+			http.Error(w, failure(result, rpcErr), http.StatusBadGateway)
+			return
+		}
+		var answer struct {
+			Content []content `json:"content"`
+		}
+		json.Unmarshal(result, &answer)
+		items = answer.Content
+	} else {
+		result, rpcErr := g.post(server, table.Ports[server], "resources/read", map[string]interface{}{"uri": "file://" + path}, from)
+		if rpcErr != nil {
+			http.Error(w, rpcErr.Message, http.StatusBadGateway)
+			return
+		}
+		var answer struct {
+			Contents []content `json:"contents"`
+		}
+		json.Unmarshal(result, &answer)
+		items = answer.Contents
+	}
+
+	reader, mimeType := contentReader(items)
+	w.Header().Set("Content-Type", mimeType)
+	if _, err := io.Copy(w, reader); err != nil {
+		log.Printf("Gateway: failed to send %s of %s: %v", path, server, err)
+	}
+}
+
+// contentReader returns a reader of the text and decoded binary data of
+// items, and the MIME type of the first
+func contentReader(items []content) (io.Reader, string) {
+	mimeType := ""
+	readers := make([]io.Reader, 0, len(items))
+	for _, item := range items {
+		if item.Resource != nil {
+			item = *item.Resource
+		}
+		if mimeType == "" {
+			mimeType = item.MimeType
+		}
+		switch {
+		case item.Blob != "":
+			readers = append(readers, base64.NewDecoder(base64.StdEncoding, strings.NewReader(item.Blob)))
+		case item.Data != "":
+			readers = append(readers, base64.NewDecoder(base64.StdEncoding, strings.NewReader(item.Data)))
+		default:
+			if mimeType == "" {
+				mimeType = "text/plain; charset=utf-8"
+			}
+			readers = append(readers, strings.NewReader(item.Text))
+		}
+	}
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+	return io.MultiReader(readers...), mimeType
+}
+
+// serverTool returns the first of names that server has as a tool, or ""
+// if it has none
+func serverTool(table *Table, server string, names []string) string {
+	for _, name := range names {
+		for _, route := range table.Routes {
+			if route.Server == server && route.Tool == name {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// failure describes why a call failed, from its error or the text of the
+// tool error it answered with
+func failure(result json.RawMessage, rpcErr *rpcError) string {
+	if rpcErr != nil {
+		return rpcErr.Message
+	}
+	var answer struct {
+		Content []content `json:"content"`
+	}
+	json.Unmarshal(result, &answer)
+	var texts []string
+	for _, item := range answer.Content {
+		if item.Text != "" {
+			texts = append(texts, item.Text)
+		}
+	}
+	if len(texts) == 0 {
+		return "The tool failed"
+	}
+	return strings.Join(texts, "\n")
+}
@@ -0,0 +1,170 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/apitest"
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/transcript"
+)
+
+// fileProxy is the proxy of a filesystem server keeping files in memory,
+// answering write_file and read_file, or resources/read without tools
+func fileProxy(t *testing.T, files map[string]string) int {
+	t.Helper()
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+			Params struct {
+				Name      string            `json:"name"`
+				URI       string            `json:"uri"`
+				Arguments map[string]string `json:"arguments"`
+			} `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		files["client"] = r.Header.Get(transcript.ClientHeader)
+
+		var result interface{}
+		switch {
+		case req.Method == "resources/read":
+			uri := strings.TrimPrefix(req.Params.URI, "file://")
+			result = map[string]interface{}{"contents": []map[string]string{{"uri": req.Params.URI, "mimeType": "image/png", "blob": files[uri]}}}
+		case req.Params.Name == "write_file":
+			content := req.Params.Arguments["content"]
+			if req.Params.Arguments["encoding"] == "base64" {
+				data, _ := base64.StdEncoding.DecodeString(content)
+				content = string(data)
+			}
+			files[req.Params.Arguments["path"]] = content
+			result = map[string]interface{}{"content": []map[string]string{{"type": "text", "text": "ok"}}}
+		case req.Params.Name == "read_file":
+			text, exists := files[req.Params.Arguments["path"]]
+			if !exists {
+				result = map[string]interface{}{"isError": true, "content": []map[string]string{{"type": "text", "text": "no such file"}}}
+				break
+			}
+			result = map[string]interface{}{"content": []map[string]string{{"type": "text", "text": text}}}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": result})
+	}))
+	t.Cleanup(proxy.Close)
+	return proxy.Listener.Addr().(*net.TCPAddr).Port
+}
+
+func TestGateway_Files(t *testing.T) {
+	files := map[string]string{}
+	source := apitest.NewManager()
+	addServer(t, source, "fs", fileProxy(t, files), "write_file", "read_file")
+	g := New(source, &config.GatewayConfig{MaxUploadMB: 1})
+
+	serve := func(r *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		g.ServeHTTP(w, r)
+		return w
+	}
+
+	// Uploads are written with write_file
+	w := serve(httptest.NewRequest(http.MethodPut, "/files/fs/tmp/notes.txt", strings.NewReader("hello")))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{"server": "fs", "path": "/tmp/notes.txt", "bytes": 5}`, w.Body.String())
+	assert.Equal(t, "hello", files["/tmp/notes.txt"])
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	form.WriteField("note", "ignored")
+	part, err := form.CreateFormFile("file", "report.md")
+	require.NoError(t, err)
+	part.Write([]byte("# Report"))
+	require.NoError(t, form.Close())
+	r := httptest.NewRequest(http.MethodPost, "/files/fs/tmp/report.md", &body)
+	r.Header.Set("Content-Type", form.FormDataContentType())
+	r.Header.Set("Authorization", "Bearer secret-key")
+	assert.Equal(t, http.StatusCreated, serve(r).Code)
+	assert.Equal(t, "# Report", files["/tmp/report.md"])
+	assert.Equal(t, transcript.ClientID(r), files["client"], "proxies see the client uploading")
+
+	// Downloads are read with read_file
+	w = serve(httptest.NewRequest(http.MethodGet, "/files/fs/tmp/notes.txt", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "hello", w.Body.String())
+	assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+
+	w = serve(httptest.NewRequest(http.MethodGet, "/files/fs/tmp/missing.txt", nil))
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+	assert.Contains(t, w.Body.String(), "no such file")
+
+	// Uploads are bounded and must be text
+	w = serve(httptest.NewRequest(http.MethodPut, "/files/fs/tmp/big.txt", strings.NewReader(strings.Repeat("a", 2<<20))))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	w = serve(httptest.NewRequest(http.MethodPut, "/files/fs/tmp/a.bin", bytes.NewReader([]byte{0xff, 0xfe})))
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	assert.Contains(t, w.Body.String(), "Only UTF-8 text files")
+
+	assert.Equal(t, http.StatusNotFound, serve(httptest.NewRequest(http.MethodGet, "/files/other/a.txt", nil)).Code)
+	assert.Equal(t, http.StatusBadRequest, serve(httptest.NewRequest(http.MethodGet, "/files/fs", nil)).Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, serve(httptest.NewRequest(http.MethodDelete, "/files/fs/a.txt", nil)).Code)
+}
+
+func TestGateway_FilesResources(t *testing.T) {
+	files := map[string]string{"/img/logo.png": "iVBORw=="}
+	source := apitest.NewManager()
+	addServer(t, source, "docs", fileProxy(t, files), "search")
+	g := New(source, nil)
+
+	// Servers without file tools serve files as resources
+	w := httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/files/docs/img/logo.png", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+	assert.Equal(t, []byte{0x89, 'P', 'N', 'G'}, w.Body.Bytes())
+
+	w = httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/files/docs/a.txt", strings.NewReader("a")))
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+}
+
+func TestGateway_FilesBinary(t *testing.T) {
+	files := map[string]string{}
+	source := apitest.NewManager()
+	addServer(t, source, "fs", fileProxy(t, files), "write_file")
+	srv, err := source.GetServer("fs")
+	require.NoError(t, err)
+	srv.Tools[0].InputSchema = map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"encoding": map[string]interface{}{"enum": []string{"utf-8", "base64"}}},
+	}
+	g := New(source, nil)
+
+	// Write tools taking base64 are sent binary files encoded
+	data := []byte{0x89, 'P', 'N', 'G', 0xff, 0x00}
+	w := httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/files/fs/img/logo.png", bytes.NewReader(data)))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{"server": "fs", "path": "/img/logo.png", "bytes": 6}`, w.Body.String())
+	assert.Equal(t, string(data), files["/img/logo.png"])
+}
+
+func TestCopyText(t *testing.T) {
+	// Runes split across reads are escaped whole
+	text := "héllo \"wörld\"\n✓"
+	var out bytes.Buffer
+	require.NoError(t, copyText(&out, iotest.OneByteReader(strings.NewReader(text))))
+	var decoded string
+	require.NoError(t, json.Unmarshal([]byte(`"`+out.String()+`"`), &decoded))
+	assert.Equal(t, text, decoded)
+
+	assert.ErrorIs(t, copyText(&out, bytes.NewReader([]byte{'a', 0xff})), errNotText)
+	assert.ErrorIs(t, copyText(&out, bytes.NewReader([]byte{0xe2, 0x9c})), errNotText, "runes cut off at the end")
+}
@@ -0,0 +1,424 @@
+// Package gateway serves the tools of all running servers on one MCP
+// endpoint, so clients configure a single URL instead of one per server
+package gateway
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/auth"
+	"github.com/tartavull/mcp-manager/internal/config"
+	mcpgrpc "github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/server"
+	"github.com/tartavull/mcp-manager/internal/transcript"
+	"github.com/tartavull/mcp-manager/internal/version"
+)
+
+// protocolVersion is the MCP protocol version the gateway speaks
+const protocolVersion = "2024-11-05"
+
+// JSON-RPC error codes returned by the gateway
+const (
+	codeParseError     = -32700
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// Source provides the servers whose tools the gateway serves
+type Source interface {
+	GetServers() (map[string]*server.Server, []string, error)
+}
+
+// request is a JSON-RPC request, or a notification when ID is nil
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// response is a JSON-RPC response
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcError is a JSON-RPC error
+type rpcError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// Gateway is an MCP endpoint listing the tools of all running servers and
+// routing calls to the proxy of the server owning each tool
+type Gateway struct {
+	source   Source
+	cfg      *config.GatewayConfig
+	opts     Options
+	router   *router
+	balancer *balancer
+	selector *selector
+	client   *http.Client
+	server   *http.Server
+}
+
+// caller is the client making a call and the IDs it gave the call, which
+// the proxies the call is forwarded to record
+type caller struct {
+	client string
+	ids    transcript.Correlation
+}
+
+// Options secures the gateway
+type Options struct {
+	// Auth rejects requests from clients it doesn't authenticate; nil
+	// leaves the gateway open
+	Auth auth.Provider
+
+	// TLS serves the gateway over TLS; nil serves it in cleartext
+	TLS *tls.Config
+
+	// ProxyToken authenticates the gateway with the proxies it forwards
+	// calls to, when they require it
+	ProxyToken string
+}
+
+// New creates a gateway serving the tools of source's servers, named as
+// cfg sets
+func New(source Source, cfg *config.GatewayConfig) *Gateway {
+	return NewWithOptions(source, cfg, Options{})
+}
+
+// NewWithOptions creates a gateway with security options
+func NewWithOptions(source Source, cfg *config.GatewayConfig, opts Options) *Gateway {
+	if cfg == nil {
+		cfg = &config.GatewayConfig{}
+	}
+	return &Gateway{
+		source:   source,
+		cfg:      cfg,
+		opts:     opts,
+		router:   newRouter(cfg.Routes),
+		balancer: newBalancer(),
+		selector: newSelector(),
+		client:   &http.Client{Timeout: 5 * time.Minute},
+	}
+}
+
+// Start listens on the configured port
+func (g *Gateway) Start() error {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", g.cfg.Port))
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	g.server = &http.Server{Handler: auth.Require(g.opts.Auth, g)}
+	serve := g.server.Serve
+	if g.opts.TLS != nil {
+		g.server.TLSConfig = g.opts.TLS.Clone()
+		serve = func(l net.Listener) error { return g.server.ServeTLS(l, "", "") }
+	}
+
+	go func() {
+		if err := serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Printf("Gateway error: %v", err)
+		}
+	}()
+	log.Printf("Gateway listening on port %d", g.cfg.Port)
+	return nil
+}
+
+// Stop closes the endpoint
+func (g *Gateway) Stop() error {
+	if g.server == nil {
+		return nil
+	}
+	return g.server.Close()
+}
+
+// Resolve names the tools of the running servers
+func (g *Gateway) Resolve() (*Table, []mcpgrpc.ToolConflict, error) {
+	servers, order, err := g.source.GetServers()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var running []ServerTools
+	pools := make(map[string]int) // Group to its index in running
+	for _, name := range order {
+		srv, exists := servers[name]
+		if !exists || !srv.IsRunning() {
+			continue
+		}
+		if srv.Group == "" {
+			running = append(running, ServerTools{Server: name, Port: srv.Port, Tools: srv.Tools})
+			continue
+		}
+
+		// Instances are served as one server whose calls any of them
+		// takes, except those draining
+		if srv.Draining {
+			continue
+		}
+		instance := Instance{Server: name, Port: srv.Port}
+		i, exists := pools[srv.Group]
+		if !exists {
+			pools[srv.Group] = len(running)
+			running = append(running, ServerTools{Server: srv.Group, Port: srv.Port, Tools: srv.Tools, Instances: []Instance{instance}})
+			continue
+		}
+		running[i].Instances = append(running[i].Instances, instance)
+		if len(running[i].Tools) == 0 {
+			running[i].Tools = srv.Tools
+		}
+	}
+	table, conflicts := Resolve(running, g.cfg)
+	return table, conflicts, nil
+}
+
+// RouteStats returns the counts of each routing rule, in order
+func (g *Gateway) RouteStats() []RouteStats {
+	return g.router.Stats()
+}
+
+// InstanceStats returns the counts of the instances calls were sent to,
+// by server and then instance
+func (g *Gateway) InstanceStats() []InstanceStats {
+	return g.balancer.Stats()
+}
+
+// ServeHTTP answers the MCP requests posted to the gateway, GET /routes
+// with the counts of the routing rules, GET /instances with those of the
+// instances and /files/ with the files of servers
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet && r.URL.Path == "/routes" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"routes": g.RouteStats()})
+		return
+	}
+	if r.Method == http.MethodGet && r.URL.Path == "/instances" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"instances": g.InstanceStats()})
+		return
+	}
+	if strings.HasPrefix(r.URL.Path, filesPrefix) {
+		from := callerOf(r)
+		from.ids.SetHeaders(w.Header())
+		g.serveFile(w, r, from)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{Code: codeParseError, Message: "Invalid JSON"}})
+		return
+	}
+	if req.ID == nil {
+		// Notifications, e.g. notifications/initialized, need no answer
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	profile := r.URL.Query().Get("profile")
+	if profile == "" {
+		profile = r.Header.Get(ProfileHeader)
+	}
+
+	from := callerOf(r)
+	from.ids.SetHeaders(w.Header())
+
+	resp := response{JSONRPC: "2.0", ID: req.ID}
+	resp.Result, resp.Error = g.handle(req, profile, from)
+	writeResponse(w, resp)
+}
+
+// callerOf returns the caller of a request. Authenticated clients are
+// known by their identity.
+func callerOf(r *http.Request) caller {
+	from := caller{client: transcript.ClientID(r), ids: transcript.CorrelationOf(r)}
+	if identity := auth.FromContext(r.Context()); identity != nil {
+		from.client = "user:" + identity.Subject
+	}
+	return from
+}
+
+// handle answers a request of a caller with profile
+func (g *Gateway) handle(req request, profile string, from caller) (interface{}, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		return map[string]interface{}{
+			"protocolVersion": protocolVersion,
+			"capabilities":    map[string]interface{}{"tools": map[string]bool{"listChanged": false}},
+			"serverInfo":      map[string]string{"name": "mcp-manager-gateway", "version": version.Version},
+		}, nil
+
+	case "ping":
+		return map[string]interface{}{}, nil
+
+	case "tools/list":
+		table, _, err := g.Resolve()
+		if err != nil {
+			return nil, &rpcError{Code: codeInternalError, Message: err.Error()}
+		}
+		tools := table.Tools
+		if tools == nil {
+			tools = []server.Tool{}
+		}
+		return map[string]interface{}{"tools": tools}, nil
+
+	case "tools/call":
+		return g.call(req.Params, profile, from)
+
+	default:
+		return nil, &rpcError{Code: codeMethodNotFound, Message: fmt.Sprintf("Method not found: %s", req.Method)}
+	}
+}
+
+// call forwards a tools/call to the proxy of the server owning the tool,
+// under the server's name for it, or to the server a routing rule picks.
+// Calls of servers with instances go to the instance their strategy picks.
+// Read-only tools with a fan-out rule are called on all their servers, and
+// generic tools on the provider selected for them.
+func (g *Gateway) call(raw json.RawMessage, profile string, from caller) (interface{}, *rpcError) {
+	var params map[string]interface{}
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "Invalid params"}
+	}
+	name, _ := params["name"].(string)
+
+	table, _, err := g.Resolve()
+	if err != nil {
+		return nil, &rpcError{Code: codeInternalError, Message: err.Error()}
+	}
+	route, exists := table.Routes[name]
+	if !exists {
+		return nil, &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("Unknown tool: %s", name)}
+	}
+
+	if providers := table.Providers[name]; len(providers) > 0 {
+		result, rpcErr := g.callProvider(name, providers, table, params, from)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+		return result, nil
+	}
+
+	if fanOut := g.fanOutRule(name); fanOut != nil && readOnlyTool(table, name) {
+		if targets := fanOutTargets(fanOut, route, table); len(targets) > 1 {
+			result, rpcErr := g.fanOut(fanOut, targets, table, params, from)
+			if rpcErr != nil {
+				return nil, rpcErr
+			}
+			return result, nil
+		}
+	}
+
+	rule, shadows := g.router.match(name, profile, table.Ports)
+	target := route.Server
+	if rule >= 0 {
+		target = g.router.routes[rule].Server
+	}
+
+	start := time.Now()
+	result, rpcErr := g.send(target, table, route.Tool, params, from)
+	if rule >= 0 {
+		g.router.record(rule, time.Since(start), rpcErr != nil, false)
+	}
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	for _, shadow := range shadows {
+		server := g.router.routes[shadow].Server
+		go g.mirror(shadow, server, table, route.Tool, params, from, result)
+	}
+	return result, nil
+}
+
+// toolFailed returns true if a server failed to answer a call or answered
+// with a tool error
+func toolFailed(result json.RawMessage, rpcErr *rpcError) bool {
+	if rpcErr != nil {
+		return true
+	}
+	var answer struct {
+		IsError bool `json:"isError"`
+	}
+	return json.Unmarshal(result, &answer) == nil && answer.IsError
+}
+
+// forward calls a tool of a server on its proxy listening on port, on
+// behalf of a caller
+func (g *Gateway) forward(server string, port int, tool string, params map[string]interface{}, from caller) (json.RawMessage, *rpcError) {
+	forwarded := make(map[string]interface{}, len(params))
+	for key, value := range params {
+		forwarded[key] = value
+	}
+	forwarded["name"] = tool
+	return g.post(server, port, "tools/call", forwarded, from)
+}
+
+// post sends a request of a caller to the proxy of a server listening on
+// port, returning its result
+func (g *Gateway) post(server string, port int, method string, params map[string]interface{}, from caller) (json.RawMessage, *rpcError) {
+	body, err := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": method, "params": params})
+	if err != nil {
+		return nil, &rpcError{Code: codeInternalError, Message: err.Error()}
+	}
+	return g.postBody(server, port, bytes.NewReader(body), from)
+}
+
+// postBody sends the JSON-RPC request in body to the proxy of a server
+// listening on port, returning its result. The body is streamed, so it
+// can be written as it is sent.
+func (g *Gateway) postBody(server string, port int, body io.Reader, from caller) (json.RawMessage, *rpcError) {
+	httpReq, err := http.NewRequest(http.MethodPost, fmt.Sprintf("http://localhost:%d/", port), body)
+	if err != nil {
+		return nil, &rpcError{Code: codeInternalError, Message: err.Error()}
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set(transcript.ClientHeader, from.client)
+	from.ids.SetHeaders(httpReq.Header)
+	if g.opts.ProxyToken != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+g.opts.ProxyToken)
+	}
+
+	resp, err := g.client.Do(httpReq)
+	if err != nil {
+		return nil, &rpcError{Code: codeInternalError, Message: fmt.Sprintf("Failed to reach %s: %v", server, err)}
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Result json.RawMessage `json:"result"`
+		Error  *rpcError       `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, &rpcError{Code: codeInternalError, Message: fmt.Sprintf("Invalid response from %s: %v", server, err)}
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Result, nil
+}
+
+// writeResponse encodes a JSON-RPC response
+func writeResponse(w http.ResponseWriter, resp response) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,220 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/apitest"
+	"github.com/tartavull/mcp-manager/internal/auth"
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/transcript"
+)
+
+// addServer adds a server with tools to source, running on port unless it
+// is zero
+func addServer(t *testing.T, source *apitest.Manager, name string, port int, names ...string) {
+	t.Helper()
+
+	require.NoError(t, source.AddServer(name, "cmd", port, ""))
+	require.NoError(t, source.SetTools(name, tools(names...)))
+	if port != 0 {
+		require.NoError(t, source.StartServer(name))
+	}
+}
+
+// fakeProxy is a server proxy recording the tools called
+func fakeProxy(t *testing.T, called *[]string) int {
+	t.Helper()
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Params struct {
+				Name string `json:"name"`
+			} `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		*called = append(*called, req.Params.Name)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      1,
+			"result":  map[string]string{"called": req.Params.Name},
+		})
+	}))
+	t.Cleanup(proxy.Close)
+	return proxy.Listener.Addr().(*net.TCPAddr).Port
+}
+
+// post sends a JSON-RPC request to the gateway
+func post(t *testing.T, g *Gateway, body string) map[string]interface{} {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp
+}
+
+func TestGateway_ToolsList(t *testing.T) {
+	source := apitest.NewManager()
+	addServer(t, source, "github", 4001, "search")
+	addServer(t, source, "gitlab", 4002, "search")
+	addServer(t, source, "stopped", 0, "idle")
+	g := New(source, nil)
+
+	resp := post(t, g, `{"jsonrpc": "2.0", "id": "a", "method": "initialize"}`)
+	assert.Equal(t, "a", resp["id"])
+	assert.Equal(t, protocolVersion, resp["result"].(map[string]interface{})["protocolVersion"])
+
+	// Only running servers' tools are listed
+	resp = post(t, g, `{"jsonrpc": "2.0", "id": 1, "method": "tools/list"}`)
+	var names []string
+	for _, tool := range resp["result"].(map[string]interface{})["tools"].([]interface{}) {
+		names = append(names, tool.(map[string]interface{})["name"].(string))
+	}
+	assert.Equal(t, []string{"search", "gitlab_search"}, names)
+
+	resp = post(t, g, `{"jsonrpc": "2.0", "id": 2, "method": "resources/list"}`)
+	assert.Equal(t, float64(codeMethodNotFound), resp["error"].(map[string]interface{})["code"])
+
+	// Notifications get no response
+	w := httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"jsonrpc": "2.0", "method": "notifications/initialized"}`)))
+	assert.Equal(t, http.StatusAccepted, w.Code)
+}
+
+func TestGateway_ToolsCall(t *testing.T) {
+	var githubCalls, gitlabCalls []string
+	source := apitest.NewManager()
+	addServer(t, source, "github", fakeProxy(t, &githubCalls), "search")
+	addServer(t, source, "gitlab", fakeProxy(t, &gitlabCalls), "search")
+	g := New(source, nil)
+
+	// Calls reach the server owning the tool under its own name
+	resp := post(t, g, `{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": {"name": "gitlab_search", "arguments": {"q": "x"}}}`)
+	assert.Equal(t, map[string]interface{}{"called": "search"}, resp["result"])
+	assert.Equal(t, []string{"search"}, gitlabCalls)
+	assert.Empty(t, githubCalls)
+
+	post(t, g, `{"jsonrpc": "2.0", "id": 2, "method": "tools/call", "params": {"name": "search"}}`)
+	assert.Equal(t, []string{"search"}, githubCalls)
+
+	resp = post(t, g, `{"jsonrpc": "2.0", "id": 3, "method": "tools/call", "params": {"name": "missing"}}`)
+	assert.Equal(t, float64(codeInvalidParams), resp["error"].(map[string]interface{})["code"])
+}
+
+func TestGateway_ClientIdentity(t *testing.T) {
+	var clients, requests []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clients = append(clients, r.Header.Get(transcript.ClientHeader))
+		requests = append(requests, r.Header.Get(transcript.RequestIDHeader))
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": map[string]string{}})
+	}))
+	t.Cleanup(proxy.Close)
+	source := apitest.NewManager()
+	addServer(t, source, "github", proxy.Listener.Addr().(*net.TCPAddr).Port, "search")
+	g := New(source, nil)
+
+	// Proxies see the gateway's client rather than the gateway
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": {"name": "search"}}`))
+	r.Header.Set("Authorization", "Bearer secret-key")
+	r.Header.Set(transcript.RequestIDHeader, "req-7")
+	w := httptest.NewRecorder()
+	g.ServeHTTP(w, r)
+	require.Len(t, clients, 1)
+	assert.Equal(t, transcript.ClientID(r), clients[0])
+	assert.True(t, strings.HasPrefix(clients[0], "key:"))
+
+	// Request IDs reach the proxies and come back to the client
+	assert.Equal(t, []string{"req-7"}, requests)
+	assert.Equal(t, "req-7", w.Header().Get(transcript.RequestIDHeader))
+}
+
+func TestValidator(t *testing.T) {
+	cfg := &config.Config{ConfigDir: t.TempDir()}
+	source := apitest.NewManager()
+	addServer(t, source, "github", 4001, "search")
+	addServer(t, source, "gitlab", 4002, "search")
+	v := &Validator{Config: cfg, Source: source}
+
+	// Without a gateway, tools don't conflict
+	require.NoError(t, os.WriteFile(cfg.GetMCPConfigPath(), []byte(`{"servers": {"github": {"command": "a"}, "gitlab": {"command": "b"}}}`), 0644))
+	validation, err := v.ValidateConfig()
+	require.NoError(t, err)
+	assert.Empty(t, validation.Problems)
+	assert.Empty(t, validation.Conflicts)
+
+	require.NoError(t, os.WriteFile(cfg.GetMCPConfigPath(), []byte(`{
+		"servers": {"github": {"command": "a"}, "gitlab": {"command": "b"}},
+		"gateway": {
+			"port": 4000,
+			"maxUploadMB": -1,
+			"priority": ["gitlab", "jira"],
+			"servers": {"github": {"prefix": "gh"}, "gitlab": {"prefix": "gh"}},
+			"routes": [{"tool": "search", "server": "jira"}, {"tool": "search", "server": "github", "percent": 150}]
+		}
+	}`), 0644))
+	validation, err = v.ValidateConfig()
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"gateway maxUploadMB can't be negative",
+		"gateway priority lists unknown server 'jira'",
+		"servers 'github' and 'gitlab' share the gateway prefix 'gh'",
+		"gateway route 1 for 'search' names unknown server 'jira'",
+		"gateway route 2 for 'search' has percent 150 outside 0-100",
+	}, validation.Problems)
+	require.Len(t, validation.Conflicts, 1)
+	assert.Equal(t, []string{"gitlab", "github"}, validation.Conflicts[0].Servers)
+	assert.Equal(t, map[string]string{"github": "gh_search"}, validation.Conflicts[0].Renamed)
+
+	// Configs that don't load are reported as problems
+	require.NoError(t, os.WriteFile(cfg.GetMCPConfigPath(), []byte(`{`), 0644))
+	validation, err = v.ValidateConfig()
+	require.NoError(t, err)
+	assert.Len(t, validation.Problems, 1)
+}
+
+func TestGateway_Auth(t *testing.T) {
+	var headers []http.Header
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header.Clone())
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": map[string]string{}})
+	}))
+	t.Cleanup(proxy.Close)
+	source := apitest.NewManager()
+	addServer(t, source, "github", proxy.Listener.Addr().(*net.TCPAddr).Port, "search")
+
+	provider, err := auth.Build(&config.AuthConfig{
+		Providers: map[string]*config.AuthProviderConfig{
+			"ci": {Type: "token", Tokens: map[string]string{"ci": "secret"}},
+		},
+	}, []string{"ci"})
+	require.NoError(t, err)
+	g := NewWithOptions(source, nil, Options{Auth: provider, ProxyToken: "internal"})
+	handler := auth.Require(g.opts.Auth, g)
+
+	call := func(token string) int {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": {"name": "search"}}`))
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, call(""))
+	assert.Empty(t, headers)
+
+	assert.Equal(t, http.StatusOK, call("secret"))
+	require.Len(t, headers, 1)
+	assert.Equal(t, "Bearer internal", headers[0].Get("Authorization"), "the gateway authenticates with the proxies itself")
+	assert.Equal(t, "user:ci", headers[0].Get(transcript.ClientHeader))
+}
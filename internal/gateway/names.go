@@ -0,0 +1,177 @@
+package gateway
+
+import (
+	"maps"
+	"regexp"
+	"slices"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+	mcpgrpc "github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// prefixUnsafe matches the characters of server names that aren't allowed
+// in tool names
+var prefixUnsafe = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+// ServerTools are the tools a server exposes
+type ServerTools struct {
+	Server    string
+	Port      int // Port of the server's proxy
+	Tools     []server.Tool
+	Instances []Instance // Instances taking the server's calls, if it has any
+}
+
+// Instance is a running instance of a server with instances
+type Instance struct {
+	Server string
+	Port   int // Port of the instance's proxy
+}
+
+// Route is the server tool behind a tool exposed by the gateway
+type Route struct {
+	Server string
+	Port   int
+	Tool   string
+}
+
+// Table maps the tools exposed by the gateway to the servers' tools
+type Table struct {
+	Tools  []server.Tool         // Exposed tools with their gateway names, in order
+	Routes map[string]Route      // Exposed name to the server tool
+	Ports  map[string]int        // Server to the port of its proxy
+	Pools  map[string][]Instance // Server with instances to those taking calls
+
+	Providers map[string][]Route // Generic tool to its running providers, by server
+}
+
+// Resolve names the tools of servers for the gateway. Servers in the
+// configured priority claim names first, then the others in order. A tool
+// is exposed under its rename, its prefixed name with PrefixAll, or else
+// its own name. When the name is taken, the tool is prefixed with its
+// server's prefix instead, or hidden if it was already renamed or prefixed
+// or its prefixed name is taken too. Generic tools of providers are exposed
+// under names left free, as the tool of their first running provider.
+func Resolve(servers []ServerTools, cfg *config.GatewayConfig) (*Table, []mcpgrpc.ToolConflict) {
+	if cfg == nil {
+		cfg = &config.GatewayConfig{}
+	}
+
+	table := &Table{Routes: make(map[string]Route), Ports: make(map[string]int, len(servers)), Pools: make(map[string][]Instance), Providers: make(map[string][]Route)}
+	for _, st := range servers {
+		table.Ports[st.Server] = st.Port
+		if len(st.Instances) > 0 {
+			table.Pools[st.Server] = st.Instances
+		}
+	}
+	owners := make(map[string]string) // Exposed name to the server claiming it
+	conflicts := make(map[string]*mcpgrpc.ToolConflict)
+	var conflictOrder []string
+
+	conflict := func(name string) *mcpgrpc.ToolConflict {
+		c, exists := conflicts[name]
+		if !exists {
+			c = &mcpgrpc.ToolConflict{Tool: name, Servers: []string{owners[name]}, Renamed: make(map[string]string)}
+			conflicts[name] = c
+			conflictOrder = append(conflictOrder, name)
+		}
+		return c
+	}
+
+	claim := func(name string, st ServerTools, tool server.Tool) {
+		owners[name] = st.Server
+		table.Routes[name] = Route{Server: st.Server, Port: st.Port, Tool: tool.Name}
+		exposed := tool
+		exposed.Name = name
+		table.Tools = append(table.Tools, exposed)
+	}
+
+	for _, st := range prioritize(servers, cfg.Priority) {
+		settings := cfg.Servers[st.Server]
+		prefix := prefixUnsafe.ReplaceAllString(st.Server, "_")
+		if settings != nil && settings.Prefix != "" {
+			prefix = settings.Prefix
+		}
+
+		for _, tool := range st.Tools {
+			name, fixed := tool.Name, false
+			if renamed, exists := rename(settings, tool.Name); exists {
+				name, fixed = renamed, true
+			} else if cfg.PrefixAll {
+				name, fixed = prefix+"_"+tool.Name, true
+			}
+
+			if _, taken := owners[name]; !taken {
+				claim(name, st, tool)
+				continue
+			}
+
+			c := conflict(name)
+			c.Servers = append(c.Servers, st.Server)
+			prefixed := prefix + "_" + tool.Name
+			if _, taken := owners[prefixed]; fixed || taken {
+				c.Hidden = append(c.Hidden, st.Server)
+				continue
+			}
+			c.Renamed[st.Server] = prefixed
+			claim(prefixed, st, tool)
+		}
+	}
+
+	for _, group := range cfg.Providers {
+		if _, taken := owners[group.Tool]; taken || group.Tool == "" {
+			continue
+		}
+		for _, name := range slices.Sorted(maps.Keys(group.Providers)) {
+			for _, st := range servers {
+				if st.Server != name {
+					continue
+				}
+				for _, tool := range st.Tools {
+					if tool.Name != group.Providers[name] {
+						continue
+					}
+					if len(table.Providers[group.Tool]) == 0 {
+						claim(group.Tool, st, tool)
+					}
+					table.Providers[group.Tool] = append(table.Providers[group.Tool], Route{Server: st.Server, Port: st.Port, Tool: tool.Name})
+				}
+			}
+		}
+	}
+
+	result := make([]mcpgrpc.ToolConflict, len(conflictOrder))
+	for i, name := range conflictOrder {
+		result[i] = *conflicts[name]
+	}
+	return table, result
+}
+
+// rename returns the name the settings of a server rename a tool to, if any
+func rename(settings *config.GatewayServerConfig, tool string) (string, bool) {
+	if settings == nil {
+		return "", false
+	}
+	name, exists := settings.Rename[tool]
+	return name, exists && name != ""
+}
+
+// prioritize orders servers with those in priority first
+func prioritize(servers []ServerTools, priority []string) []ServerTools {
+	ordered := make([]ServerTools, 0, len(servers))
+	placed := make(map[string]bool)
+	for _, name := range priority {
+		for _, st := range servers {
+			if st.Server == name && !placed[name] {
+				ordered = append(ordered, st)
+				placed[name] = true
+			}
+		}
+	}
+	for _, st := range servers {
+		if !placed[st.Server] {
+			ordered = append(ordered, st)
+		}
+	}
+	return ordered
+}
@@ -0,0 +1,96 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tartavull/mcp-manager/internal/config"
+	mcpgrpc "github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// tools returns tools with the given names
+func tools(names ...string) []server.Tool {
+	result := make([]server.Tool, len(names))
+	for i, name := range names {
+		result[i] = server.Tool{Name: name}
+	}
+	return result
+}
+
+// exposed returns the names of the tools a table exposes
+func exposed(table *Table) []string {
+	names := make([]string, len(table.Tools))
+	for i, tool := range table.Tools {
+		names[i] = tool.Name
+	}
+	return names
+}
+
+func TestResolve_NoConflicts(t *testing.T) {
+	table, conflicts := Resolve([]ServerTools{
+		{Server: "github", Port: 4001, Tools: tools("create_issue")},
+		{Server: "memory", Port: 4002, Tools: tools("remember", "recall")},
+	}, nil)
+
+	assert.Empty(t, conflicts)
+	assert.Equal(t, []string{"create_issue", "remember", "recall"}, exposed(table))
+	assert.Equal(t, Route{Server: "memory", Port: 4002, Tool: "recall"}, table.Routes["recall"])
+}
+
+func TestResolve_Conflicts(t *testing.T) {
+	servers := []ServerTools{
+		{Server: "github", Tools: tools("search", "create_issue")},
+		{Server: "gitlab", Tools: tools("search")},
+		{Server: "jira", Tools: tools("search")},
+	}
+
+	// The first server keeps the name, the others are prefixed
+	table, conflicts := Resolve(servers, nil)
+	assert.Equal(t, []string{"search", "create_issue", "gitlab_search", "jira_search"}, exposed(table))
+	assert.Equal(t, Route{Server: "gitlab", Tool: "search"}, table.Routes["gitlab_search"])
+	assert.Equal(t, []mcpgrpc.ToolConflict{{
+		Tool:    "search",
+		Servers: []string{"github", "gitlab", "jira"},
+		Renamed: map[string]string{"gitlab": "gitlab_search", "jira": "jira_search"},
+	}}, conflicts)
+
+	// Priority decides who keeps it
+	table, conflicts = Resolve(servers, &config.GatewayConfig{Priority: []string{"jira"}})
+	assert.Equal(t, "jira", table.Routes["search"].Server)
+	assert.Equal(t, []string{"jira", "github", "gitlab"}, conflicts[0].Servers)
+}
+
+func TestResolve_PrefixesAndRenames(t *testing.T) {
+	servers := []ServerTools{
+		{Server: "github", Tools: tools("search")},
+		{Server: "homelab/gitea", Tools: tools("search", "list")},
+	}
+
+	// Server names are made safe for tool names, and prefixes can be set
+	table, _ := Resolve(servers, nil)
+	assert.Contains(t, table.Routes, "homelab_gitea_search")
+	table, _ = Resolve(servers, &config.GatewayConfig{Servers: map[string]*config.GatewayServerConfig{
+		"homelab/gitea": {Prefix: "gitea"},
+	}})
+	assert.Contains(t, table.Routes, "gitea_search")
+
+	// Every tool is prefixed with PrefixAll
+	table, conflicts := Resolve(servers, &config.GatewayConfig{PrefixAll: true})
+	assert.Empty(t, conflicts)
+	assert.Equal(t, []string{"github_search", "homelab_gitea_search", "homelab_gitea_list"}, exposed(table))
+
+	// Renames win over prefixes, and a renamed tool losing its name is hidden
+	table, conflicts = Resolve(servers, &config.GatewayConfig{Servers: map[string]*config.GatewayServerConfig{
+		"github":        {Rename: map[string]string{"search": "code_search"}},
+		"homelab/gitea": {Rename: map[string]string{"list": "code_search"}},
+	}})
+	assert.Equal(t, []string{"code_search", "search"}, exposed(table))
+	assert.Equal(t, Route{Server: "homelab/gitea", Tool: "search"}, table.Routes["search"])
+	assert.Equal(t, []mcpgrpc.ToolConflict{{
+		Tool:    "code_search",
+		Servers: []string{"github", "homelab/gitea"},
+		Renamed: map[string]string{},
+		Hidden:  []string{"homelab/gitea"},
+	}}, conflicts)
+}
@@ -0,0 +1,223 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+	mcpgrpc "github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// unhealthyErrorRate is the moving average share of failed calls from
+// which a provider is only selected if all others fail as often
+const unhealthyErrorRate = 0.5
+
+// probeEvery sends every so many calls of a generic tool to the provider
+// called least recently instead, so the counts of all providers stay
+// current and recovered ones are selected again
+const probeEvery = 20
+
+// providerCounts are the rolling counts of a provider of a generic tool
+type providerCounts struct {
+	calls     int
+	errors    int
+	errorRate float64
+	latency   time.Duration
+	last      int // Call of the tool it took last
+}
+
+// selector selects the providers serving generic tools by their latency
+// and errors
+type selector struct {
+	mu     sync.Mutex
+	pins   map[string]string                     // Tool to the provider pinned at runtime, "" when unpinned
+	calls  map[string]int                        // Tool to its calls
+	counts map[string]map[string]*providerCounts // Tool to its providers' counts
+}
+
+// newSelector creates a selector without counts or pins
+func newSelector() *selector {
+	return &selector{
+		pins:   make(map[string]string),
+		calls:  make(map[string]int),
+		counts: make(map[string]map[string]*providerCounts),
+	}
+}
+
+// provider returns the counts of a provider of tool. Must be called with
+// s.mu held.
+func (s *selector) provider(tool, name string) *providerCounts {
+	if s.counts[tool] == nil {
+		s.counts[tool] = make(map[string]*providerCounts)
+	}
+	counts, exists := s.counts[tool][name]
+	if !exists {
+		counts = &providerCounts{}
+		s.counts[tool][name] = counts
+	}
+	return counts
+}
+
+// pinned returns the provider pinned to a generic tool at runtime, or else
+// in its settings
+func (s *selector) pinned(group *config.GatewayProviders) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if pin, exists := s.pins[group.Tool]; exists {
+		return pin
+	}
+	return group.Pin
+}
+
+// choose returns the running provider of tool taking its next call: the
+// pinned one, or else the healthy one answering fastest. Providers without
+// calls yet are tried first, and ties go to the first. Must be called with
+// s.mu held.
+func (s *selector) choose(tool, pin string, providers []Route) Route {
+	best := -1
+	for i, route := range providers {
+		if route.Server == pin {
+			return route
+		}
+		if best < 0 || s.better(tool, route.Server, providers[best].Server) {
+			best = i
+		}
+	}
+	return providers[best]
+}
+
+// better returns true if provider a of tool is healthier than b, or as
+// healthy and faster. Must be called with s.mu held.
+func (s *selector) better(tool, a, b string) bool {
+	countsA, countsB := s.provider(tool, a), s.provider(tool, b)
+	healthyA, healthyB := countsA.errorRate < unhealthyErrorRate, countsB.errorRate < unhealthyErrorRate
+	if healthyA != healthyB {
+		return healthyA
+	}
+	return countsA.latency < countsB.latency
+}
+
+// pick returns the provider of tool taking a call, the one called least
+// recently every probeEvery calls unless one is pinned
+func (s *selector) pick(tool, pin string, providers []Route) Route {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls[tool]++
+	picked := s.choose(tool, pin, providers)
+	if picked.Server != pin && s.calls[tool]%probeEvery == 0 {
+		for _, route := range providers {
+			if s.provider(tool, route.Server).last < s.provider(tool, picked.Server).last {
+				picked = route
+			}
+		}
+	}
+	s.provider(tool, picked.Server).last = s.calls[tool]
+	return picked
+}
+
+// done counts a call a provider of tool answered
+func (s *selector) done(tool, name string, took time.Duration, failed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counts := s.provider(tool, name)
+	counts.calls++
+	failure := 0.0
+	if failed {
+		counts.errors++
+		failure = 1
+	}
+	if counts.calls == 1 {
+		counts.latency = took
+		counts.errorRate = failure
+		return
+	}
+	counts.latency = time.Duration(latencyWeight*float64(took) + (1-latencyWeight)*float64(counts.latency))
+	counts.errorRate = latencyWeight*failure + (1-latencyWeight)*counts.errorRate
+}
+
+// providerGroup returns the settings of a generic tool, or nil
+func (g *Gateway) providerGroup(tool string) *config.GatewayProviders {
+	for i := range g.cfg.Providers {
+		if g.cfg.Providers[i].Tool == tool {
+			return &g.cfg.Providers[i]
+		}
+	}
+	return nil
+}
+
+// callProvider forwards a call of a generic tool to the provider selected
+// for it, counting how it answered
+func (g *Gateway) callProvider(name string, providers []Route, table *Table, params map[string]interface{}, from caller) (json.RawMessage, *rpcError) {
+	pin := ""
+	if group := g.providerGroup(name); group != nil {
+		pin = g.selector.pinned(group)
+	}
+	route := g.selector.pick(name, pin, providers)
+
+	start := time.Now()
+	result, rpcErr := g.send(route.Server, table, route.Tool, params, from)
+	g.selector.done(name, route.Server, time.Since(start), toolFailed(result, rpcErr))
+	return result, rpcErr
+}
+
+// Providers returns the generic tools with the provider each selects for
+// its next call and the providers' counts, in the order of the settings
+func (g *Gateway) Providers() ([]mcpgrpc.ProviderGroup, error) {
+	table, _, err := g.Resolve()
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]mcpgrpc.ProviderGroup, 0, len(g.cfg.Providers))
+	for i := range g.cfg.Providers {
+		settings := &g.cfg.Providers[i]
+		group := mcpgrpc.ProviderGroup{Tool: settings.Tool, Pinned: g.selector.pinned(settings)}
+
+		g.selector.mu.Lock()
+		running := table.Providers[settings.Tool]
+		if len(running) > 0 {
+			group.Selected = g.selector.choose(settings.Tool, group.Pinned, running).Server
+		}
+		for _, name := range slices.Sorted(maps.Keys(settings.Providers)) {
+			counts := g.selector.provider(settings.Tool, name)
+			group.Providers = append(group.Providers, mcpgrpc.ProviderStats{
+				Server:    name,
+				Tool:      settings.Providers[name],
+				Running:   slices.ContainsFunc(running, func(route Route) bool { return route.Server == name }),
+				Calls:     counts.calls,
+				Errors:    counts.errors,
+				ErrorRate: counts.errorRate,
+				Latency:   counts.latency,
+			})
+		}
+		g.selector.mu.Unlock()
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+// PinProvider makes a provider serve a generic tool whenever it is
+// running, or lets the gateway select the provider again when name is
+// empty, overriding the pin of the settings until the daemon restarts
+func (g *Gateway) PinProvider(tool, name string) error {
+	group := g.providerGroup(tool)
+	if group == nil {
+		return fmt.Errorf("generic tool '%s' %w", tool, server.ErrNotFound)
+	}
+	if _, exists := group.Providers[name]; name != "" && !exists {
+		return fmt.Errorf("provider '%s' of '%s' %w", name, tool, server.ErrNotFound)
+	}
+
+	g.selector.mu.Lock()
+	defer g.selector.mu.Unlock()
+	g.selector.pins[tool] = name
+	return nil
+}
@@ -0,0 +1,151 @@
+package gateway
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/apitest"
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+func TestResolve_Providers(t *testing.T) {
+	cfg := &config.GatewayConfig{Providers: []config.GatewayProviders{
+		{Tool: "search.web", Providers: map[string]string{"brave": "brave_web_search", "google": "google_search", "bing": "search"}},
+		{Tool: "fetch", Providers: map[string]string{"brave": "fetch"}},
+	}}
+	table, _ := Resolve([]ServerTools{
+		{Server: "google", Port: 4002, Tools: tools("google_search")},
+		{Server: "brave", Port: 4001, Tools: tools("brave_web_search")},
+		{Server: "fetcher", Port: 4003, Tools: tools("fetch")},
+	}, cfg)
+
+	// Generic tools are exposed besides the providers' own, unless taken
+	assert.Equal(t, []string{"google_search", "brave_web_search", "fetch", "search.web"}, exposed(table))
+	assert.Equal(t, []Route{
+		{Server: "brave", Port: 4001, Tool: "brave_web_search"},
+		{Server: "google", Port: 4002, Tool: "google_search"},
+	}, table.Providers["search.web"])
+	assert.Equal(t, "fetcher", table.Routes["fetch"].Server)
+	assert.Empty(t, table.Providers["fetch"])
+}
+
+func TestSelector(t *testing.T) {
+	s := newSelector()
+	providers := []Route{{Server: "brave"}, {Server: "google"}}
+
+	// Providers without calls are tried first, in order
+	assert.Equal(t, "brave", s.pick("search", "", providers).Server)
+	s.done("search", "brave", 300*time.Millisecond, false)
+	assert.Equal(t, "google", s.pick("search", "", providers).Server)
+	s.done("search", "google", 100*time.Millisecond, false)
+
+	// Then the fastest healthy one
+	assert.Equal(t, "google", s.pick("search", "", providers).Server)
+	for range 4 {
+		s.done("search", "google", 100*time.Millisecond, true)
+	}
+	assert.Equal(t, "brave", s.pick("search", "", providers).Server)
+
+	// Pins win over the counts
+	assert.Equal(t, "google", s.pick("search", "google", providers).Server)
+
+	// Every probeEvery calls, the provider called least recently is tried
+	var picked []string
+	for range probeEvery {
+		picked = append(picked, s.pick("search", "", providers).Server)
+	}
+	assert.Contains(t, picked, "google")
+	assert.Equal(t, probeEvery-1, countOf(picked, "brave"))
+}
+
+// countOf counts the occurrences of value in values
+func countOf(values []string, value string) int {
+	count := 0
+	for _, v := range values {
+		if v == value {
+			count++
+		}
+	}
+	return count
+}
+
+func TestGateway_Providers(t *testing.T) {
+	var braveCalls, googleCalls atomic.Int32
+	source := apitest.NewManager()
+	addServer(t, source, "brave", answerProxy(t, "brave", 50*time.Millisecond, false, &braveCalls), "brave_web_search")
+	addServer(t, source, "google", answerProxy(t, "google", 0, false, &googleCalls), "google_search")
+	addServer(t, source, "bing", 0, "search")
+	g := New(source, &config.GatewayConfig{Providers: []config.GatewayProviders{
+		{Tool: "search.web", Providers: map[string]string{"brave": "brave_web_search", "google": "google_search", "bing": "search"}},
+	}})
+	call := `{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": {"name": "search.web"}}`
+
+	// After trying both, the faster provider takes the calls
+	for range 4 {
+		post(t, g, call)
+	}
+	assert.Equal(t, int32(1), braveCalls.Load())
+	assert.Equal(t, int32(3), googleCalls.Load())
+
+	groups, err := g.Providers()
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.Equal(t, "google", groups[0].Selected)
+	assert.Empty(t, groups[0].Pinned)
+	require.Len(t, groups[0].Providers, 3)
+	assert.Equal(t, "bing", groups[0].Providers[0].Server)
+	assert.False(t, groups[0].Providers[0].Running)
+	assert.Equal(t, 1, groups[0].Providers[1].Calls)
+	assert.GreaterOrEqual(t, groups[0].Providers[1].Latency, 50*time.Millisecond)
+	assert.Equal(t, 3, groups[0].Providers[2].Calls)
+
+	// Pinned providers take the calls until unpinned
+	require.NoError(t, g.PinProvider("search.web", "brave"))
+	resp := post(t, g, call)
+	assert.Equal(t, []string{"brave"}, texts(resp["result"]))
+	groups, _ = g.Providers()
+	assert.Equal(t, "brave", groups[0].Selected)
+	assert.Equal(t, "brave", groups[0].Pinned)
+
+	require.NoError(t, g.PinProvider("search.web", ""))
+	resp = post(t, g, call)
+	assert.Equal(t, []string{"google"}, texts(resp["result"]))
+
+	// Stopped pinned providers are passed over
+	require.NoError(t, g.PinProvider("search.web", "bing"))
+	resp = post(t, g, call)
+	assert.Equal(t, []string{"google"}, texts(resp["result"]))
+
+	assert.True(t, errors.Is(g.PinProvider("search.news", "brave"), server.ErrNotFound))
+	assert.True(t, errors.Is(g.PinProvider("search.web", "yahoo"), server.ErrNotFound))
+}
+
+func TestGatewayProblems_Providers(t *testing.T) {
+	source := apitest.NewManager()
+	addServer(t, source, "brave", 4001, "brave_web_search")
+	addServer(t, source, "google", 4002, "google_search")
+
+	servers, _, err := source.GetServers()
+	require.NoError(t, err)
+	problems := gatewayProblems(&config.GatewayConfig{
+		Port: 4000,
+		Providers: []config.GatewayProviders{
+			{Tool: "search.web", Providers: map[string]string{"brave": "brave_web_search", "google": "google_search"}, Pin: "google"},
+			{Providers: map[string]string{"brave": "brave_web_search"}},
+			{Tool: "search.news"},
+			{Tool: "search.images", Providers: map[string]string{"brave": "", "yahoo": "images"}, Pin: "google"},
+		},
+	}, servers)
+	assert.Equal(t, []string{
+		"gateway providers 2 require a tool",
+		"generic tool 'search.news' requires providers",
+		"generic tool 'search.images' requires the tool of provider 'brave'",
+		"generic tool 'search.images' names unknown provider 'yahoo'",
+		"generic tool 'search.images' is pinned to 'google', which isn't one of its providers",
+	}, problems)
+}
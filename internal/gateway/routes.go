@@ -0,0 +1,140 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+)
+
+// ProfileHeader identifies the profile of a gateway client, as does the
+// profile query parameter of the gateway URL
+const ProfileHeader = "X-MCP-Profile"
+
+// RouteStats counts the calls a routing rule applied to
+type RouteStats struct {
+	Tool           string  `json:"tool"`
+	Profile        string  `json:"profile,omitempty"`
+	Server         string  `json:"server"`
+	Shadow         bool    `json:"shadow,omitempty"`
+	Calls          int     `json:"calls"`                 // Calls routed, or copied for shadows
+	Errors         int     `json:"errors"`                // Calls the server failed to answer
+	Divergences    int     `json:"divergences,omitempty"` // Copied calls answered differently
+	AverageLatency float64 `json:"averageLatencyMs"`      // Mean time the server took to answer
+
+	latency time.Duration // Total time the server took to answer
+}
+
+// router applies the routing rules of the gateway to tool calls
+type router struct {
+	routes []config.GatewayRoute
+
+	// sample returns a number in [0, 100), replaceable for tests
+	sample func() float64
+
+	mu    sync.Mutex
+	stats []RouteStats // Indexed as routes
+}
+
+// newRouter creates a router applying routes in order
+func newRouter(routes []config.GatewayRoute) *router {
+	r := &router{
+		routes: routes,
+		sample: func() float64 { return rand.Float64() * 100 },
+		stats:  make([]RouteStats, len(routes)),
+	}
+	for i, route := range routes {
+		r.stats[i] = RouteStats{Tool: route.Tool, Profile: route.Profile, Server: route.Server, Shadow: route.Shadow}
+	}
+	return r
+}
+
+// match returns the rule routing a call of tool from a client with
+// profile, or -1 to use the server exposing the tool, and the shadow rules
+// copying it. Rules whose server isn't running are skipped.
+func (r *router) match(tool, profile string, running map[string]int) (int, []int) {
+	target := -1
+	var shadows []int
+	for i, route := range r.routes {
+		if route.Tool != tool || (route.Profile != "" && route.Profile != profile) {
+			continue
+		}
+		if _, exists := running[route.Server]; !exists {
+			continue
+		}
+		if route.Shadow {
+			if r.hit(route) {
+				shadows = append(shadows, i)
+			}
+			continue
+		}
+		if target < 0 && r.hit(route) {
+			target = i
+		}
+	}
+	return target, shadows
+}
+
+// hit samples whether a call falls in the share of a rule
+func (r *router) hit(route config.GatewayRoute) bool {
+	return route.Percent <= 0 || route.Percent >= 100 || r.sample() < route.Percent
+}
+
+// record counts a call a rule applied to
+func (r *router) record(rule int, took time.Duration, failed, diverged bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := &r.stats[rule]
+	stats.Calls++
+	stats.latency += took
+	if failed {
+		stats.Errors++
+	}
+	if diverged {
+		stats.Divergences++
+	}
+}
+
+// Stats returns the counts of each rule, in order
+func (r *router) Stats() []RouteStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := make([]RouteStats, len(r.stats))
+	for i, s := range r.stats {
+		if s.Calls > 0 {
+			s.AverageLatency = float64(s.latency.Microseconds()) / 1000 / float64(s.Calls)
+		}
+		stats[i] = s
+	}
+	return stats
+}
+
+// mirror copies a call to the server of a shadow rule and compares its
+// answer with the primary's
+func (g *Gateway) mirror(rule int, server string, table *Table, tool string, params map[string]interface{}, from caller, primary json.RawMessage) {
+	start := time.Now()
+	result, rpcErr := g.send(server, table, tool, params, from)
+	took := time.Since(start)
+
+	diverged := rpcErr == nil && !sameJSON(result, primary)
+	if diverged {
+		log.Printf("Gateway shadow %s diverged on %s", server, tool)
+	}
+	g.router.record(rule, took, rpcErr != nil, diverged)
+}
+
+// sameJSON returns true if two JSON documents are equal, ignoring
+// formatting
+func sameJSON(a, b json.RawMessage) bool {
+	var ca, cb bytes.Buffer
+	if json.Compact(&ca, a) != nil || json.Compact(&cb, b) != nil {
+		return bytes.Equal(a, b)
+	}
+	return bytes.Equal(ca.Bytes(), cb.Bytes())
+}
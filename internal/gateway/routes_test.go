@@ -0,0 +1,90 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/apitest"
+	"github.com/tartavull/mcp-manager/internal/config"
+)
+
+func TestRouter_Match(t *testing.T) {
+	r := newRouter([]config.GatewayRoute{
+		{Tool: "read_file", Profile: "webapp", Server: "fs-webapp"},
+		{Tool: "search", Server: "search-v2", Percent: 5},
+		{Tool: "search", Server: "search-next", Shadow: true, Percent: 50},
+		{Tool: "search", Server: "stopped"},
+	})
+	running := map[string]int{"fs-webapp": 1, "search-v2": 2, "search-next": 3}
+
+	// Profiles select rules
+	rule, shadows := r.match("read_file", "webapp", running)
+	assert.Equal(t, 0, rule)
+	assert.Empty(t, shadows)
+	rule, _ = r.match("read_file", "other", running)
+	assert.Equal(t, -1, rule)
+
+	// Shares are sampled per call, and rules of stopped servers skipped
+	r.sample = func() float64 { return 3 }
+	rule, shadows = r.match("search", "", running)
+	assert.Equal(t, 1, rule)
+	assert.Equal(t, []int{2}, shadows)
+
+	r.sample = func() float64 { return 70 }
+	rule, shadows = r.match("search", "", running)
+	assert.Equal(t, -1, rule)
+	assert.Empty(t, shadows)
+}
+
+func TestGateway_Routes(t *testing.T) {
+	var fsCalls, fsWebappCalls, searchCalls, shadowCalls []string
+	source := apitest.NewManager()
+	addServer(t, source, "fs", fakeProxy(t, &fsCalls), "read_file")
+	addServer(t, source, "fs-webapp", fakeProxy(t, &fsWebappCalls), "read_file")
+	addServer(t, source, "search", fakeProxy(t, &searchCalls), "search")
+	addServer(t, source, "search-next", fakeProxy(t, &shadowCalls), "search")
+	g := New(source, &config.GatewayConfig{Routes: []config.GatewayRoute{
+		{Tool: "read_file", Profile: "webapp", Server: "fs-webapp"},
+		{Tool: "search", Server: "search-next", Shadow: true},
+	}})
+
+	// The profile comes from the URL or a header
+	call := `{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": {"name": "read_file"}}`
+	g.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/?profile=webapp", strings.NewReader(call)))
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(call))
+	req.Header.Set(ProfileHeader, "webapp")
+	g.ServeHTTP(httptest.NewRecorder(), req)
+	post(t, g, call)
+	assert.Equal(t, []string{"read_file", "read_file"}, fsWebappCalls)
+	assert.Equal(t, []string{"read_file"}, fsCalls)
+
+	// Shadows get a copy, the client the primary's answer
+	resp := post(t, g, `{"jsonrpc": "2.0", "id": 2, "method": "tools/call", "params": {"name": "search"}}`)
+	assert.Equal(t, map[string]interface{}{"called": "search"}, resp["result"])
+	assert.Eventually(t, func() bool { return g.RouteStats()[1].Calls == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"search"}, searchCalls)
+
+	w := httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/routes", nil))
+	var stats struct {
+		Routes []RouteStats `json:"routes"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+	require.Len(t, stats.Routes, 2)
+	assert.Equal(t, "fs-webapp", stats.Routes[0].Server)
+	assert.Equal(t, 2, stats.Routes[0].Calls)
+	assert.Zero(t, stats.Routes[0].Errors)
+	assert.True(t, stats.Routes[1].Shadow)
+	assert.Zero(t, stats.Routes[1].Divergences)
+}
+
+func TestSameJSON(t *testing.T) {
+	assert.True(t, sameJSON(json.RawMessage(`{"a": 1}`), json.RawMessage(`{"a":1}`)))
+	assert.False(t, sameJSON(json.RawMessage(`{"a": 1}`), json.RawMessage(`{"a": 2}`)))
+}
@@ -0,0 +1,177 @@
+package gateway
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+	mcpgrpc "github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// Validator checks mcp.json and the tool names of the running servers,
+// serving the ValidateConfig RPC
+type Validator struct {
+	Config *config.Config
+	Source Source // Servers whose tools are checked for conflicts
+}
+
+// ValidateConfig loads mcp.json afresh and reports the settings that can't
+// be applied and the tools the gateway would expose under other names
+func (v *Validator) ValidateConfig() (*mcpgrpc.Validation, error) {
+	validation := &mcpgrpc.Validation{}
+
+	mcpConfig, err := v.Config.LoadMCPConfig()
+	if err != nil {
+		validation.Problems = append(validation.Problems, err.Error())
+		return validation, nil
+	}
+	if mcpConfig.Gateway == nil {
+		return validation, nil
+	}
+
+	g := New(v.Source, mcpConfig.Gateway)
+	servers, _, err := v.Source.GetServers()
+	if err != nil {
+		return nil, err
+	}
+	validation.Problems = append(validation.Problems, gatewayProblems(mcpConfig.Gateway, servers)...)
+
+	table, conflicts, err := g.Resolve()
+	if err != nil {
+		return nil, err
+	}
+	validation.Conflicts = conflicts
+	for _, rule := range mcpConfig.Gateway.FanOut {
+		if _, exposed := table.Routes[rule.Tool]; exposed && !readOnlyTool(table, rule.Tool) {
+			validation.Problems = append(validation.Problems, fmt.Sprintf("gateway fan-out for '%s' is ignored, the tool isn't annotated read-only", rule.Tool))
+		}
+	}
+	for _, group := range mcpConfig.Gateway.Providers {
+		if route, exposed := table.Routes[group.Tool]; exposed && len(table.Providers[group.Tool]) == 0 {
+			validation.Problems = append(validation.Problems, fmt.Sprintf("generic tool '%s' is hidden, '%s' exposes a tool of that name", group.Tool, route.Server))
+		}
+	}
+	return validation, nil
+}
+
+// gatewayProblems reports gateway settings naming unknown servers, sharing
+// a prefix, balancing calls or combining fanned-out results unknown ways,
+// routing calls nowhere or pinning generic tools to other servers. Servers
+// with instances are known by the name of their entry.
+func gatewayProblems(cfg *config.GatewayConfig, servers map[string]*server.Server) []string {
+	groups := make(map[string]bool)
+	for _, srv := range servers {
+		if srv.Group != "" {
+			groups[srv.Group] = true
+		}
+	}
+	known := func(name string) bool {
+		return servers[name] != nil || groups[name]
+	}
+
+	var problems []string
+	if cfg.Port == 0 {
+		problems = append(problems, "gateway requires a port")
+	}
+	if cfg.MaxUploadMB < 0 {
+		problems = append(problems, "gateway maxUploadMB can't be negative")
+	}
+	for _, name := range cfg.Priority {
+		if !known(name) {
+			problems = append(problems, fmt.Sprintf("gateway priority lists unknown server '%s'", name))
+		}
+	}
+
+	names := make([]string, 0, len(cfg.Servers))
+	for name := range cfg.Servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	prefixes := make(map[string]string)
+	for _, name := range names {
+		if !known(name) {
+			problems = append(problems, fmt.Sprintf("gateway settings for unknown server '%s'", name))
+		}
+		settings := cfg.Servers[name]
+		if settings != nil && settings.Balance != "" {
+			switch {
+			case !slices.Contains(Strategies, settings.Balance):
+				problems = append(problems, fmt.Sprintf("gateway balance '%s' for '%s' is unknown, use %s", settings.Balance, name, strings.Join(Strategies, ", ")))
+			case servers[name] != nil:
+				problems = append(problems, fmt.Sprintf("gateway balances '%s', which has no instances", name))
+			}
+		}
+		if settings == nil || settings.Prefix == "" {
+			continue
+		}
+		if other, taken := prefixes[settings.Prefix]; taken {
+			problems = append(problems, fmt.Sprintf("servers '%s' and '%s' share the gateway prefix '%s'", other, name, settings.Prefix))
+			continue
+		}
+		prefixes[settings.Prefix] = name
+	}
+
+	for i, route := range cfg.Routes {
+		switch {
+		case route.Tool == "":
+			problems = append(problems, fmt.Sprintf("gateway route %d requires a tool", i+1))
+		case route.Server == "":
+			problems = append(problems, fmt.Sprintf("gateway route %d for '%s' requires a server", i+1, route.Tool))
+		case !known(route.Server):
+			problems = append(problems, fmt.Sprintf("gateway route %d for '%s' names unknown server '%s'", i+1, route.Tool, route.Server))
+		case route.Percent < 0 || route.Percent > 100:
+			problems = append(problems, fmt.Sprintf("gateway route %d for '%s' has percent %g outside 0-100", i+1, route.Tool, route.Percent))
+		}
+	}
+
+	for i, rule := range cfg.FanOut {
+		if rule.Tool == "" {
+			problems = append(problems, fmt.Sprintf("gateway fan-out %d requires a tool", i+1))
+			continue
+		}
+		if len(rule.Servers) == 0 {
+			problems = append(problems, fmt.Sprintf("gateway fan-out for '%s' requires servers", rule.Tool))
+		}
+		for _, name := range rule.Servers {
+			if !known(name) {
+				problems = append(problems, fmt.Sprintf("gateway fan-out for '%s' names unknown server '%s'", rule.Tool, name))
+			}
+		}
+		if rule.Policy != "" && !slices.Contains(Policies, rule.Policy) {
+			problems = append(problems, fmt.Sprintf("gateway fan-out policy '%s' for '%s' is unknown, use %s", rule.Policy, rule.Tool, strings.Join(Policies, ", ")))
+		}
+		if rule.Timeout != "" {
+			if d, err := time.ParseDuration(rule.Timeout); err != nil || d <= 0 {
+				problems = append(problems, fmt.Sprintf("gateway fan-out for '%s' has invalid timeout '%s'", rule.Tool, rule.Timeout))
+			}
+		}
+	}
+
+	for i, group := range cfg.Providers {
+		if group.Tool == "" {
+			problems = append(problems, fmt.Sprintf("gateway providers %d require a tool", i+1))
+			continue
+		}
+		if len(group.Providers) == 0 {
+			problems = append(problems, fmt.Sprintf("generic tool '%s' requires providers", group.Tool))
+		}
+		for _, name := range slices.Sorted(maps.Keys(group.Providers)) {
+			switch {
+			case !known(name):
+				problems = append(problems, fmt.Sprintf("generic tool '%s' names unknown provider '%s'", group.Tool, name))
+			case group.Providers[name] == "":
+				problems = append(problems, fmt.Sprintf("generic tool '%s' requires the tool of provider '%s'", group.Tool, name))
+			}
+		}
+		if _, exists := group.Providers[group.Pin]; group.Pin != "" && !exists {
+			problems = append(problems, fmt.Sprintf("generic tool '%s' is pinned to '%s', which isn't one of its providers", group.Tool, group.Pin))
+		}
+	}
+	return problems
+}
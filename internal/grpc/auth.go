@@ -0,0 +1,141 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"strings"
+
+	"github.com/tartavull/mcp-manager/internal/auth"
+	"github.com/tartavull/mcp-manager/internal/config"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// authenticate checks the credentials of an RPC, returning ctx carrying
+// the client's identity
+func authenticate(ctx context.Context, provider auth.Provider) (context.Context, error) {
+	var creds auth.Credentials
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, value := range md.Get("authorization") {
+			if token, ok := strings.CutPrefix(value, "Bearer "); ok {
+				creds.Token = strings.TrimSpace(token)
+			}
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(info.State.VerifiedChains) > 0 {
+			creds.Certificates = info.State.VerifiedChains[0]
+		}
+	}
+
+	identity, err := provider.Authenticate(ctx, creds)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return auth.WithIdentity(ctx, identity), nil
+}
+
+// authInterceptors return the server options rejecting RPCs provider
+// doesn't authenticate
+func authInterceptors(provider auth.Provider) []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			ctx, err := authenticate(ctx, provider)
+			if err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.ChainStreamInterceptor(func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			ctx, err := authenticate(stream.Context(), provider)
+			if err != nil {
+				return err
+			}
+			return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+		}),
+	}
+}
+
+// authenticatedStream is a stream whose context carries the identity of
+// its client
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context carrying the client's identity
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// tokenCredentials sends a bearer token with every RPC
+type tokenCredentials struct {
+	token string
+}
+
+// GetRequestMetadata returns the authorization header
+func (t tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + t.token}, nil
+}
+
+// RequireTransportSecurity allows tokens over plaintext, e.g. to a daemon
+// on localhost
+func (t tokenCredentials) RequireTransportSecurity() bool {
+	return false
+}
+
+// ClientOptions are the credentials clients connect to a daemon with
+type ClientOptions struct {
+	TLS   *tls.Config // Nil connects without TLS
+	Token string      // Bearer token sent with every RPC, empty for none
+
+	// Pin refuses daemons presenting another TLS certificate or SSH host
+	// key, nil to trust the CAs and known_hosts
+	Pin *config.DaemonPinConfig
+}
+
+// EnvClientOptions returns the credentials of the environment, see
+// auth.TokenEnv and auth.CAEnv
+func EnvClientOptions() (ClientOptions, error) {
+	tlsConfig, err := auth.ClientTLS()
+	if err != nil {
+		return ClientOptions{}, err
+	}
+	return ClientOptions{TLS: tlsConfig, Token: auth.ClientToken()}, nil
+}
+
+// dialOptions returns the options connecting with the credentials
+func (o ClientOptions) dialOptions() ([]grpc.DialOption, error) {
+	tlsConfig := o.TLS
+	if o.Pin != nil && o.Pin.CertSHA256 != "" {
+		var err error
+		if tlsConfig, err = pinTLS(tlsConfig, o.Pin.CertSHA256); err != nil {
+			return nil, err
+		}
+	}
+
+	transport := insecure.NewCredentials()
+	if tlsConfig != nil {
+		transport = credentials.NewTLS(tlsConfig)
+	}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(transport)}
+	if o.Token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(tokenCredentials{token: o.Token}))
+	}
+	return opts, nil
+}
+
+// DialOptions returns the options connecting to a daemon with the
+// credentials of the environment, see auth.TokenEnv
+func DialOptions() ([]grpc.DialOption, error) {
+	options, err := EnvClientOptions()
+	if err != nil {
+		return nil, err
+	}
+	return options.dialOptions()
+}
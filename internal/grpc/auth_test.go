@@ -0,0 +1,86 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/apitest"
+	"github.com/tartavull/mcp-manager/internal/auth"
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/grpc/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// dialAuthServer serves a manager requiring the token "secret" in memory,
+// and connects to it with the credentials of the environment
+func dialAuthServer(t *testing.T) pb.MCPManagerClient {
+	provider, err := auth.Build(&config.AuthConfig{
+		Providers: map[string]*config.AuthProviderConfig{
+			"ci": {Type: "token", Tokens: map[string]string{"ci": "secret"}},
+		},
+	}, []string{"ci"})
+	require.NoError(t, err)
+
+	grpcServer := grpc.NewServer(authInterceptors(provider)...)
+	pb.RegisterMCPManagerServer(grpcServer, NewServer(apitest.NewManager()))
+	lis := bufconn.Listen(1024 * 1024)
+	go grpcServer.Serve(lis)
+
+	opts, err := DialOptions()
+	require.NoError(t, err)
+	opts = append(opts, grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+		return lis.Dial()
+	}))
+	conn, err := grpc.DialContext(context.Background(), "bufnet", opts...)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		conn.Close()
+		grpcServer.Stop()
+	})
+	return pb.NewMCPManagerClient(conn)
+}
+
+func TestAuthInterceptors(t *testing.T) {
+	t.Setenv(auth.CAEnv, "")
+	t.Setenv(auth.CertEnv, "")
+	ctx := context.Background()
+
+	t.Setenv(auth.TokenEnv, "")
+	client := dialAuthServer(t)
+	_, err := client.ListServers(ctx, &pb.Empty{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	stream, err := client.Subscribe(ctx, &pb.SubscribeRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unauthenticated, status.Code(err), "streams are authenticated too")
+
+	t.Setenv(auth.TokenEnv, "wrong")
+	client = dialAuthServer(t)
+	_, err = client.ListServers(ctx, &pb.Empty{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	t.Setenv(auth.TokenEnv, "secret")
+	client = dialAuthServer(t)
+	_, err = client.ListServers(ctx, &pb.Empty{})
+	assert.NoError(t, err)
+}
+
+func TestEnvClientOptions(t *testing.T) {
+	t.Setenv(auth.CAEnv, "")
+	t.Setenv(auth.CertEnv, "")
+	t.Setenv(auth.TokenEnv, "secret")
+	options, err := EnvClientOptions()
+	require.NoError(t, err)
+	assert.Equal(t, ClientOptions{Token: "secret"}, options)
+
+	t.Setenv(auth.CAEnv, t.TempDir()+"/missing.pem")
+	_, err = EnvClientOptions()
+	assert.ErrorContains(t, err, "failed to read CA")
+}
@@ -2,16 +2,22 @@ package grpc
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
+	"os"
 	"sync"
 	"time"
 
 	pb "github.com/tartavull/mcp-manager/internal/grpc/pb"
+	"github.com/tartavull/mcp-manager/internal/logs"
+	"github.com/tartavull/mcp-manager/internal/plugin"
 	"github.com/tartavull/mcp-manager/internal/server"
+	"github.com/tartavull/mcp-manager/internal/transcript"
+	"github.com/tartavull/mcp-manager/internal/uptime"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
 )
 
 // Client represents a gRPC client for the MCP Manager daemon
@@ -20,13 +26,28 @@ type Client struct {
 	client pb.MCPManagerClient
 
 	// Event handling
-	eventStream pb.MCPManager_SubscribeClient
-	eventChan   chan Event
-	eventMu     sync.Mutex
+	eventStream  pb.MCPManager_SubscribeClient
+	cancelStream context.CancelFunc
+	eventTypes   []pb.EventType // Filters resubscribed after reconnecting
+	eventChan    chan Event
+	eventMu      sync.Mutex
+	backoff      Backoff
+	retryStart   time.Time // When the current run of reconnects began
+	retryAttempt int
+	retryNext    time.Time     // When the waiting attempt is made
+	retryNow     chan struct{} // Skips the wait of the next attempt
+	done         chan struct{} // Closed with the client
+	cleanup      func()        // Removes what connecting left behind, if set
+
+	// Liveness of the event stream
+	lastHeartbeat time.Time
+	heartbeatMu   sync.RWMutex
 
 	// Callbacks for TUI updates
-	onServerUpdate func()
-	callbackMu     sync.RWMutex
+	onServerUpdate    func()
+	onConnectionState func(ConnectionState)
+	state             ConnectionState
+	callbackMu        sync.RWMutex
 }
 
 // Event represents a client-side event
@@ -36,44 +57,108 @@ type Event struct {
 	Details interface{}
 }
 
-// NewClient creates a new gRPC client
+// NewClient creates a new gRPC client with the credentials of the
+// environment. Addresses like ssh://user@host/localhost:8080 reach a
+// remote daemon through ssh, and unix://~/.mcp-manager/daemon.sock one
+// listening on a unix socket.
 func NewClient(address string) (*Client, error) {
+	options, err := EnvClientOptions()
+	if err != nil {
+		return nil, err
+	}
+	return NewClientWithOptions(address, options)
+}
+
+// NewClientWithOptions creates a new gRPC client connecting with the
+// credentials of options
+func NewClientWithOptions(address string, options ClientOptions) (*Client, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	conn, err := grpc.DialContext(ctx, address,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	opts, err := options.dialOptions()
+	if err != nil {
+		return nil, err
+	}
+	target, tunneled, err := parseSSHAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	if tunneled {
+		if options.Pin != nil && options.Pin.HostKey != "" {
+			if target.knownHosts, err = writeKnownHosts(options.Pin.HostKey); err != nil {
+				return nil, err
+			}
+		}
+		opts = append(opts, target.dialOption())
+		address = "passthrough:///" + target.daemon
+	} else if options.Pin != nil && options.Pin.HostKey != "" {
+		return nil, fmt.Errorf("hostKey pins ssh:// daemons only")
+	} else if path, local, err := UnixSocketPath(address); err != nil {
+		return nil, err
+	} else if local {
+		address = UnixScheme + path
+	}
+	removeKnownHosts := func() {
+		if target.knownHosts != "" {
+			os.Remove(target.knownHosts)
+		}
+	}
+
+	// Connection errors, e.g. a certificate not matching the pin, are
+	// reported rather than timing out
+	conn, err := grpc.DialContext(ctx, address, append(opts,
 		grpc.WithBlock(),
-	)
+		grpc.WithReturnConnectionError(),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{Time: 10 * time.Second, Timeout: 5 * time.Second, PermitWithoutStream: true}),
+	)...)
 	if err != nil {
+		removeKnownHosts()
 		return nil, fmt.Errorf("failed to connect to daemon: %w", err)
 	}
 
-	client := pb.NewMCPManagerClient(conn)
-
-	c := &Client{
-		conn:      conn,
-		client:    client,
-		eventChan: make(chan Event, 100),
-	}
+	c := newClient(conn, DefaultBackoff)
+	c.cleanup = removeKnownHosts
 
 	// Start event subscription
 	if err := c.Subscribe(); err != nil {
 		conn.Close()
+		removeKnownHosts()
 		return nil, fmt.Errorf("failed to subscribe to events: %w", err)
 	}
 
 	return c, nil
 }
 
+// newClient creates a client on conn without subscribing to events
+func newClient(conn *grpc.ClientConn, backoff Backoff) *Client {
+	return &Client{
+		conn:      conn,
+		client:    pb.NewMCPManagerClient(conn),
+		eventChan: make(chan Event, 100),
+		backoff:   backoff,
+		retryNow:  make(chan struct{}, 1),
+		done:      make(chan struct{}),
+	}
+}
+
 // Close closes the client connection
 func (c *Client) Close() error {
 	c.eventMu.Lock()
 	if c.eventStream != nil {
 		c.eventStream.CloseSend()
+		c.cancelStream()
+		c.eventStream = nil
+	}
+	select {
+	case <-c.done:
+	default:
+		close(c.done)
 	}
 	c.eventMu.Unlock()
 
+	if c.cleanup != nil {
+		c.cleanup()
+	}
 	return c.conn.Close()
 }
 
@@ -133,6 +218,32 @@ func (c *Client) StopServer(name string) error {
 	return err
 }
 
+// StartTagged starts the stopped servers labeled with tag, returning their
+// names
+func (c *Client) StartTagged(tag string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	resp, err := c.client.StartTagged(ctx, &pb.TagRequest{Tag: tag})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Order, nil
+}
+
+// StopTagged stops the running servers labeled with tag, returning their
+// names
+func (c *Client) StopTagged(tag string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	resp, err := c.client.StopTagged(ctx, &pb.TagRequest{Tag: tag})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Order, nil
+}
+
 // GetTools returns the tools for a specific server
 func (c *Client) GetTools(name string) ([]server.Tool, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -150,6 +261,9 @@ func (c *Client) GetTools(name string) ([]server.Tool, error) {
 			Title:       t.Title,
 			Description: t.Description,
 		}
+		if t.InputSchema != "" {
+			json.Unmarshal([]byte(t.InputSchema), &tools[i].InputSchema)
+		}
 	}
 
 	return tools, nil
@@ -168,6 +282,523 @@ func (c *Client) GetConfigPath() (string, error) {
 	return resp.Path, nil
 }
 
+// ValidateConfig returns the problems of the daemon's configuration and the
+// tools its gateway exposes under other names
+func (c *Client) ValidateConfig() (*Validation, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := c.client.ValidateConfig(ctx, &pb.Empty{})
+	if err != nil {
+		return nil, err
+	}
+
+	validation := &Validation{Problems: resp.Problems}
+	for _, conflict := range resp.Conflicts {
+		validation.Conflicts = append(validation.Conflicts, ToolConflict{
+			Tool:    conflict.Tool,
+			Servers: conflict.Servers,
+			Renamed: conflict.Renamed,
+			Hidden:  conflict.Hidden,
+		})
+	}
+	return validation, nil
+}
+
+// ListApprovals returns the tool calls waiting for approval, oldest first
+func (c *Client) ListApprovals() ([]Approval, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := c.client.ListApprovals(ctx, &pb.Empty{})
+	if err != nil {
+		return nil, err
+	}
+
+	approvals := make([]Approval, len(resp.Approvals))
+	for i, approval := range resp.Approvals {
+		approvals[i] = approvalFromProto(approval)
+	}
+	return approvals, nil
+}
+
+// DecideApproval lets a parked tool call through or rejects it with reason
+func (c *Client) DecideApproval(id string, approved bool, reason string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := c.client.DecideApproval(ctx, &pb.ApprovalDecision{Id: id, Approved: approved, Reason: reason})
+	return err
+}
+
+// approvalFromProto converts a parked tool call
+func approvalFromProto(approval *pb.Approval) Approval {
+	return Approval{
+		ID:        approval.Id,
+		Server:    approval.ServerName,
+		Tool:      approval.Tool,
+		Arguments: approval.Arguments,
+		Requested: time.Unix(approval.RequestedAt, 0),
+		Deadline:  time.Unix(approval.Deadline, 0),
+		RequestID: approval.RequestId,
+		RunID:     approval.RunId,
+	}
+}
+
+// ListSessions returns the session transcripts of the proxies' clients
+// without their calls, latest first
+func (c *Client) ListSessions() ([]transcript.Session, error) {
+	return c.listSessions(&pb.SessionFilter{})
+}
+
+// FindSessions returns the session transcripts with calls an agent gave id
+// as request or run ID, holding only those calls, latest first
+func (c *Client) FindSessions(id string) ([]transcript.Session, error) {
+	return c.listSessions(&pb.SessionFilter{CorrelationId: id})
+}
+
+// listSessions returns the session transcripts a filter selects
+func (c *Client) listSessions(filter *pb.SessionFilter) ([]transcript.Session, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := c.client.ListSessions(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]transcript.Session, len(resp.Sessions))
+	for i, session := range resp.Sessions {
+		sessions[i] = *sessionFromProto(session)
+	}
+	return sessions, nil
+}
+
+// GetSession returns a session transcript with its calls
+func (c *Client) GetSession(id string) (*transcript.Session, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := c.client.GetSession(ctx, &pb.SessionRequest{Id: id})
+	if err != nil {
+		return nil, err
+	}
+	return sessionFromProto(resp), nil
+}
+
+// CreateFleet copies servers, all when none are given, into an isolated
+// fleet for an evaluation run, starting them if start is set
+func (c *Client) CreateFleet(runID string, servers []string, start bool) (*Fleet, error) {
+	// Starting the copies may take a while
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	resp, err := c.client.CreateFleet(ctx, &pb.FleetRequest{RunId: runID, Servers: servers, Start: start})
+	if err != nil {
+		return nil, err
+	}
+	return fleetFromProto(resp), nil
+}
+
+// DestroyFleet stops the servers of a run's fleet and removes them with
+// their state
+func (c *Client) DestroyFleet(runID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	_, err := c.client.DestroyFleet(ctx, &pb.FleetRequest{RunId: runID})
+	return err
+}
+
+// DrainServer stops a server once the calls in flight on its proxy finish,
+// waiting at most timeout, or the daemon's default when zero
+func (c *Client) DrainServer(name string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout+time.Minute)
+	defer cancel()
+
+	_, err := c.client.DrainServer(ctx, &pb.DrainRequest{Name: name, TimeoutMs: timeout.Milliseconds()})
+	return err
+}
+
+// ListProfiles returns the browser profiles of a server, sorted by name
+func (c *Client) ListProfiles(server string) ([]BrowserProfile, error) {
+	// Profile sizes are summed when listed
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	resp, err := c.client.ListProfiles(ctx, &pb.ServerRequest{Name: server})
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := make([]BrowserProfile, len(resp.Profiles))
+	for i, msg := range resp.Profiles {
+		profiles[i] = BrowserProfile{
+			Server:   msg.Server,
+			Name:     msg.Name,
+			Dir:      msg.Dir,
+			Size:     msg.Size,
+			Attached: msg.Attached,
+		}
+		if msg.Modified > 0 {
+			profiles[i].Modified = time.Unix(msg.Modified, 0)
+		}
+		for _, snapshot := range msg.Snapshots {
+			profiles[i].Snapshots = append(profiles[i].Snapshots, *snapshotFromProto(snapshot))
+		}
+	}
+	return profiles, nil
+}
+
+// ResetProfile deletes the files of a stopped server's browser profile, the
+// one it uses when profile is empty
+func (c *Client) ResetProfile(server, profile string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	_, err := c.client.ResetProfile(ctx, &pb.ProfileRequest{Server: server, Profile: profile})
+	return err
+}
+
+// SnapshotProfile archives a server's browser profile, the one it uses
+// when profile is empty
+func (c *Client) SnapshotProfile(server, profile string) (*ProfileSnapshot, error) {
+	// Browser profiles may be large
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	resp, err := c.client.SnapshotProfile(ctx, &pb.ProfileRequest{Server: server, Profile: profile})
+	if err != nil {
+		return nil, err
+	}
+	return snapshotFromProto(resp), nil
+}
+
+// RestoreProfile replaces a stopped server's browser profile with a
+// snapshot
+func (c *Client) RestoreProfile(server, profile, snapshot string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	_, err := c.client.RestoreProfile(ctx, &pb.ProfileRequest{Server: server, Profile: profile, Snapshot: snapshot})
+	return err
+}
+
+// snapshotFromProto converts a profile snapshot
+func snapshotFromProto(msg *pb.ProfileSnapshot) *ProfileSnapshot {
+	return &ProfileSnapshot{
+		Name:    msg.Name,
+		Path:    msg.Path,
+		Size:    msg.Size,
+		Created: time.Unix(msg.Created, 0),
+	}
+}
+
+// DiskUsage returns the disk used by servers, all of them when none are
+// named
+func (c *Client) DiskUsage(names []string) ([]ServerDisk, error) {
+	// Directories are walked when asked
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	resp, err := c.client.DiskUsage(ctx, &pb.DiskRequest{Names: names})
+	if err != nil {
+		return nil, err
+	}
+	return diskUsageFromProto(resp), nil
+}
+
+// CleanCaches deletes the caches of stopped servers, all of them when none
+// are named, returning what was deleted, or would be if dryRun is set
+func (c *Client) CleanCaches(names []string, dryRun bool) ([]ServerDisk, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	resp, err := c.client.CleanCaches(ctx, &pb.DiskRequest{Names: names, DryRun: dryRun})
+	if err != nil {
+		return nil, err
+	}
+	return diskUsageFromProto(resp), nil
+}
+
+// diskUsageFromProto converts the disk used by servers
+func diskUsageFromProto(msg *pb.DiskUsageList) []ServerDisk {
+	usage := make([]ServerDisk, len(msg.Servers))
+	for i, disk := range msg.Servers {
+		usage[i].Server = disk.Server
+		for _, dir := range disk.Dirs {
+			usage[i].Dirs = append(usage[i].Dirs, DiskDir{Kind: dir.Kind, Label: dir.Label, Path: dir.Path, Size: dir.Size})
+		}
+	}
+	return usage
+}
+
+// BackupServer archives the data of a server, stopping it meanwhile if it
+// runs
+func (c *Client) BackupServer(name string) (*Backup, error) {
+	// Data stores may be large, and servers take a while to stop and start
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	resp, err := c.client.BackupServer(ctx, &pb.ServerRequest{Name: name})
+	if err != nil {
+		return nil, err
+	}
+	return backupFromProto(resp), nil
+}
+
+// ListBackups returns the backups of a server, oldest first
+func (c *Client) ListBackups(name string) ([]Backup, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := c.client.ListBackups(ctx, &pb.ServerRequest{Name: name})
+	if err != nil {
+		return nil, err
+	}
+	backups := make([]Backup, len(resp.Backups))
+	for i, msg := range resp.Backups {
+		backups[i] = *backupFromProto(msg)
+	}
+	return backups, nil
+}
+
+// RestoreBackup replaces the data of a server with a backup, stopping it
+// meanwhile if it runs
+func (c *Client) RestoreBackup(name, backup string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	_, err := c.client.RestoreBackup(ctx, &pb.BackupRequest{Server: name, Backup: backup})
+	return err
+}
+
+// backupFromProto converts a backup
+func backupFromProto(msg *pb.Backup) *Backup {
+	return &Backup{
+		Server:  msg.Server,
+		Name:    msg.Name,
+		Path:    msg.Path,
+		Size:    msg.Size,
+		Created: time.Unix(msg.Created, 0),
+	}
+}
+
+// Providers returns the generic gateway tools with the provider each
+// selects and their rolling counts
+func (c *Client) Providers() ([]ProviderGroup, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := c.client.ListProviders(ctx, &pb.Empty{})
+	if err != nil {
+		return nil, err
+	}
+	groups := make([]ProviderGroup, len(resp.Groups))
+	for i, msg := range resp.Groups {
+		groups[i] = ProviderGroup{Tool: msg.Tool, Selected: msg.Selected, Pinned: msg.Pinned}
+		for _, provider := range msg.Providers {
+			groups[i].Providers = append(groups[i].Providers, ProviderStats{
+				Server:    provider.Server,
+				Tool:      provider.Tool,
+				Running:   provider.Running,
+				Calls:     int(provider.Calls),
+				Errors:    int(provider.Errors),
+				ErrorRate: provider.ErrorRate,
+				Latency:   time.Duration(provider.LatencyMs * float64(time.Millisecond)),
+			})
+		}
+	}
+	return groups, nil
+}
+
+// PinProvider makes a server serve a generic gateway tool, or lets the
+// gateway select it again when server is empty
+func (c *Client) PinProvider(tool, server string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := c.client.PinProvider(ctx, &pb.PinRequest{Tool: tool, Server: server})
+	return err
+}
+
+// Notifications returns the default level of desktop notifications and the
+// level of every server
+func (c *Client) Notifications() (string, []NotificationPreference, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := c.client.ListNotifications(ctx, &pb.Empty{})
+	if err != nil {
+		return "", nil, err
+	}
+	preferences := make([]NotificationPreference, len(resp.Servers))
+	for i, msg := range resp.Servers {
+		preferences[i] = NotificationPreference{Server: msg.Server, Level: msg.Level, Custom: msg.Custom}
+	}
+	return resp.DefaultLevel, preferences, nil
+}
+
+// SetNotification sets the level of desktop notifications of a server, or
+// resets it to the default when level is empty
+func (c *Client) SetNotification(server, level string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := c.client.SetNotification(ctx, &pb.NotificationRequest{Server: server, Level: level})
+	return err
+}
+
+// DescribeServer returns what a server runs as, or would if started now
+func (c *Client) DescribeServer(name string) (*Runtime, error) {
+	// Transport plugins are asked for the command
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	resp, err := c.client.DescribeServer(ctx, &pb.ServerRequest{Name: name})
+	if err != nil {
+		return nil, err
+	}
+	runtime := &Runtime{
+		Server:    resp.Server,
+		Command:   resp.Command,
+		Running:   resp.Running,
+		Path:      resp.Path,
+		Cwd:       resp.Cwd,
+		Transport: resp.Transport,
+		Error:     resp.Error,
+	}
+	for _, v := range resp.Env {
+		runtime.Env = append(runtime.Env, RuntimeVar{Name: v.Name, Source: v.Source})
+	}
+	for _, limit := range resp.Limits {
+		runtime.Limits = append(runtime.Limits, RuntimeLimit{Name: limit.Name, Value: limit.Value})
+	}
+	return runtime, nil
+}
+
+// CloneServer copies a server under a new name, "<source>-2" or the next
+// free number if empty, with the replacements "old=new" in its command.
+// Returns the name and port of the copy.
+func (c *Client) CloneServer(source, name string, replace []string) (string, int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	resp, err := c.client.CloneServer(ctx, &pb.CloneRequest{ServerName: source, Name: name, Replace: replace})
+	if err != nil {
+		return "", 0, err
+	}
+	return resp.Name, int(resp.Port), nil
+}
+
+// ListFleets returns the fleets of the runs in progress, sorted by run ID
+func (c *Client) ListFleets() ([]Fleet, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := c.client.ListFleets(ctx, &pb.Empty{})
+	if err != nil {
+		return nil, err
+	}
+
+	fleets := make([]Fleet, len(resp.Fleets))
+	for i, msg := range resp.Fleets {
+		fleets[i] = *fleetFromProto(msg)
+	}
+	return fleets, nil
+}
+
+// fleetFromProto converts a run's fleet
+func fleetFromProto(msg *pb.Fleet) *Fleet {
+	fleet := &Fleet{
+		RunID:   msg.RunId,
+		Dir:     msg.Dir,
+		Created: time.Unix(msg.Created, 0),
+	}
+	for _, srv := range msg.Servers {
+		fleet.Servers = append(fleet.Servers, protoToServer(srv))
+	}
+	return fleet
+}
+
+// ListPlugins returns the plugins the daemon found, sorted by name
+func (c *Client) ListPlugins() ([]*plugin.Plugin, error) {
+	// Plugins are described when listed, which may take a while
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	resp, err := c.client.ListPlugins(ctx, &pb.Empty{})
+	if err != nil {
+		return nil, err
+	}
+
+	plugins := make([]*plugin.Plugin, len(resp.Plugins))
+	for i, msg := range resp.Plugins {
+		plugins[i] = &plugin.Plugin{
+			Name: msg.Name,
+			Path: msg.Path,
+			Manifest: plugin.Manifest{
+				Version:     msg.Version,
+				Description: msg.Description,
+				Kinds:       msg.Kinds,
+			},
+			Error: msg.Error,
+		}
+		if msg.ConfigSchema != "" {
+			plugins[i].ConfigSchema = json.RawMessage(msg.ConfigSchema)
+		}
+	}
+	return plugins, nil
+}
+
+// sessionFromProto converts a session transcript
+func sessionFromProto(msg *pb.TranscriptSession) *transcript.Session {
+	session := &transcript.Session{
+		ID:      msg.Id,
+		Client:  msg.Client,
+		Started: time.Unix(msg.Started, 0),
+		Updated: time.Unix(msg.Updated, 0),
+		Calls:   int(msg.Calls),
+		Errors:  int(msg.Errors),
+	}
+	for _, entry := range msg.Entries {
+		session.Entries = append(session.Entries, transcript.Entry{
+			Time:       time.UnixMilli(entry.TimestampMs),
+			Server:     entry.ServerName,
+			Method:     entry.Method,
+			Tool:       entry.Tool,
+			Arguments:  entry.Arguments,
+			Result:     entry.Result,
+			Error:      entry.Error,
+			DurationMs: entry.DurationMs,
+			Correlation: transcript.Correlation{
+				RequestID: entry.RequestId,
+				RunID:     entry.RunId,
+			},
+		})
+	}
+	return session
+}
+
+// SetMaintenance enables or disables maintenance mode for a server, or for
+// the whole daemon when name is empty
+func (c *Client) SetMaintenance(name string, enabled bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := c.client.SetMaintenance(ctx, &pb.MaintenanceRequest{Name: name, Enabled: enabled})
+	return err
+}
+
+// Maintenance returns true if the daemon is in maintenance mode
+func (c *Client) Maintenance() (bool, error) {
+	health, err := c.Health()
+	if err != nil {
+		return false, err
+	}
+	return health.Maintenance, nil
+}
+
 // Health checks the health of the daemon
 func (c *Client) Health() (*pb.HealthStatus, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -176,60 +807,196 @@ func (c *Client) Health() (*pb.HealthStatus, error) {
 	return c.client.Health(ctx, &pb.Empty{})
 }
 
-// Subscribe starts listening for real-time events
+// QueryEvents returns past events from the daemon's journal
+func (c *Client) QueryEvents(query *pb.EventQuery) (*pb.EventList, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	events, err := c.client.QueryEvents(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+	return events, nil
+}
+
+// ServerUptime returns the availability of the daemon's servers over the
+// last days calendar days
+func (c *Client) ServerUptime(days int) ([]uptime.Stats, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	report, err := c.client.GetUptime(ctx, &pb.UptimeRequest{Days: int32(days)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get uptime: %w", err)
+	}
+	stats := make([]uptime.Stats, len(report.Servers))
+	for i, srv := range report.Servers {
+		stats[i] = uptime.Stats{
+			Server:  srv.Server,
+			Up:      seconds(srv.UpSeconds),
+			Down:    seconds(srv.DownSeconds),
+			Outages: int(srv.Outages),
+			MTTR:    seconds(srv.MttrSeconds),
+		}
+	}
+	return stats, nil
+}
+
+// seconds converts seconds to a duration
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// StreamLogs calls fn with the captured output of a server: the last
+// tailLines lines, all retained lines if zero, then new lines until ctx is
+// done if follow is set
+func (c *Client) StreamLogs(ctx context.Context, name string, follow bool, tailLines int, fn func(logs.Line)) error {
+	stream, err := c.client.StreamLogs(ctx, &pb.LogsRequest{
+		Name:      name,
+		Follow:    follow,
+		TailLines: int32(tailLines),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to stream logs: %w", err)
+	}
+
+	for {
+		line, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to stream logs: %w", err)
+		}
+		fn(logLineFromProto(line))
+	}
+}
+
+// Subscribe starts listening for real-time events. The stream is
+// resubscribed with the same event types when the connection is lost.
 func (c *Client) Subscribe(eventTypes ...pb.EventType) error {
 	c.eventMu.Lock()
 	defer c.eventMu.Unlock()
 
-	// Close existing stream if any
-	if c.eventStream != nil {
-		c.eventStream.CloseSend()
-	}
-
 	// If no types specified, subscribe to all
 	if len(eventTypes) == 0 {
 		eventTypes = []pb.EventType{pb.EventType_ALL}
 	}
+	c.eventTypes = eventTypes
 
-	stream, err := c.client.Subscribe(context.Background(), &pb.SubscribeRequest{
+	return c.subscribe(eventTypes)
+}
+
+// subscribe replaces the event stream. Must be called with c.eventMu held.
+func (c *Client) subscribe(eventTypes []pb.EventType) error {
+	// Close existing stream if any
+	if c.eventStream != nil {
+		c.eventStream.CloseSend()
+		c.cancelStream()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := c.client.Subscribe(ctx, &pb.SubscribeRequest{
 		EventTypes: eventTypes,
 	})
 	if err != nil {
+		cancel()
 		return err
 	}
 
 	c.eventStream = stream
+	c.cancelStream = cancel
+	c.markHeartbeat()
+	c.setState(StateConnected)
 
 	// Start event receiver
-	go c.receiveEvents()
+	go c.receiveEvents(stream)
+	go c.watchHeartbeats(ctx, cancel)
 
 	return nil
 }
 
+// LastHeartbeat returns when the daemon was last heard from on the event
+// stream
+func (c *Client) LastHeartbeat() time.Time {
+	c.heartbeatMu.RLock()
+	defer c.heartbeatMu.RUnlock()
+	return c.lastHeartbeat
+}
+
+// Connected returns true if the daemon sent a heartbeat recently
+func (c *Client) Connected() bool {
+	return time.Since(c.LastHeartbeat()) <= HeartbeatTimeout
+}
+
+// markHeartbeat records that the daemon was heard from
+func (c *Client) markHeartbeat() {
+	c.heartbeatMu.Lock()
+	c.lastHeartbeat = time.Now()
+	c.heartbeatMu.Unlock()
+}
+
+// watchHeartbeats cancels a stream without heartbeats for HeartbeatTimeout,
+// so a half-open connection is noticed and the stream resubscribed
+func (c *Client) watchHeartbeats(ctx context.Context, cancel context.CancelFunc) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !c.Connected() {
+				log.Printf("No heartbeat from daemon for %s, reconnecting", HeartbeatTimeout)
+				cancel()
+				return
+			}
+		}
+	}
+}
+
 // Events returns the event channel
 func (c *Client) Events() <-chan Event {
 	return c.eventChan
 }
 
 // receiveEvents processes incoming events from the stream
-func (c *Client) receiveEvents() {
+func (c *Client) receiveEvents(stream pb.MCPManager_SubscribeClient) {
+	received := false
 	for {
-		event, err := c.eventStream.Recv()
+		event, err := stream.Recv()
 		if err != nil {
+			// Streams replaced by Subscribe or closed with the client are done
+			c.eventMu.Lock()
+			current := c.eventStream == stream
+			c.eventMu.Unlock()
+			if !current {
+				return
+			}
+
 			if err == io.EOF {
 				log.Println("Event stream closed by server")
 			} else {
 				log.Printf("Error receiving event: %v", err)
 			}
-
-			// Try to reconnect after a delay
-			time.Sleep(2 * time.Second)
-			if err := c.Subscribe(); err != nil {
-				log.Printf("Failed to reconnect: %v", err)
-			}
+			c.reconnect(stream)
 			return
 		}
 
+		// Any event shows the daemon is alive; heartbeats carry nothing else
+		c.markHeartbeat()
+		if !received {
+			received = true
+			c.resetRetries(stream)
+		}
+		if event.Type == pb.EventType_HEARTBEAT {
+			continue
+		}
+
 		// Convert proto event to client event
 		clientEvent := Event{
 			Type: event.Type.String(),
@@ -253,6 +1020,36 @@ func (c *Client) receiveEvents() {
 				"added":    payload.ConfigChange.ServersAdded,
 				"removed":  payload.ConfigChange.ServersRemoved,
 				"modified": payload.ConfigChange.ServersModified,
+				"updated":  payload.ConfigChange.ServersUpdated,
+			}
+		case *pb.Event_Failover:
+			clientEvent.Server = payload.Failover.ServerName
+			clientEvent.Details = map[string]string{
+				"from":   payload.Failover.FromHost,
+				"to":     payload.Failover.ToHost,
+				"reason": payload.Failover.Reason,
+			}
+		case *pb.Event_CircuitBreaker:
+			clientEvent.Server = payload.CircuitBreaker.ServerName
+			clientEvent.Details = map[string]string{
+				"state":  payload.CircuitBreaker.State,
+				"reason": payload.CircuitBreaker.Reason,
+			}
+		case *pb.Event_Approval:
+			clientEvent.Server = payload.Approval.GetApproval().GetServerName()
+			clientEvent.Details = map[string]string{
+				"id":     payload.Approval.GetApproval().GetId(),
+				"tool":   payload.Approval.GetApproval().GetTool(),
+				"state":  payload.Approval.State,
+				"reason": payload.Approval.Reason,
+			}
+		case *pb.Event_PortMigration:
+			clientEvent.Server = payload.PortMigration.ServerName
+			clientEvent.Details = map[string]interface{}{
+				"old_port": payload.PortMigration.OldPort,
+				"new_port": payload.PortMigration.NewPort,
+				"state":    payload.PortMigration.State,
+				"reason":   payload.PortMigration.Reason,
 			}
 		}
 
@@ -290,16 +1087,44 @@ func protoToServer(pb *pb.Server) *server.Server {
 		}
 	}
 
+	var restartPolicy *server.RestartPolicy
+	if pb.RestartPolicy != "" {
+		restartPolicy = &server.RestartPolicy{
+			Mode:        server.RestartMode(pb.RestartPolicy),
+			MaxRestarts: int(pb.MaxRestarts),
+		}
+	}
+
+	var startedAt time.Time
+	if pb.StartedAt > 0 {
+		startedAt = time.Unix(pb.StartedAt, 0)
+	}
+
 	return &server.Server{
-		Name:        pb.Name,
-		Command:     pb.Command,
-		Port:        int(pb.Port),
-		Description: pb.Description,
-		Status:      protoToStatus(pb.Status),
-		PID:         int(pb.Pid),
-		ToolCount:   int(pb.ToolCount),
-		Tools:       tools,
-		LastUpdated: time.Unix(pb.LastUpdated, 0),
+		Name:           pb.Name,
+		Command:        pb.Command,
+		Port:           int(pb.Port),
+		Description:    pb.Description,
+		Status:         protoToStatus(pb.Status),
+		Health:         server.Health(pb.Health),
+		HealthMessage:  pb.HealthMessage,
+		Host:           pb.Host,
+		RestartPolicy:  restartPolicy,
+		Restarts:       int(pb.Restarts),
+		Maintenance:    pb.Maintenance,
+		Requests:       pb.Requests,
+		StartedAt:      startedAt,
+		Autostart:      pb.Autostart,
+		RunID:          pb.RunId,
+		Group:          pb.Group,
+		Draining:       pb.Draining,
+		InFlight:       pb.InFlight,
+		BrowserProfile: pb.BrowserProfile,
+		Tags:           pb.Tags,
+		PID:            int(pb.Pid),
+		ToolCount:      int(pb.ToolCount),
+		Tools:          tools,
+		LastUpdated:    time.Unix(pb.LastUpdated, 0),
 	}
 }
 
@@ -319,3 +1144,24 @@ func protoToStatus(status pb.ServerStatus) server.Status {
 		return server.StatusStopped
 	}
 }
+
+func logLineFromProto(line *pb.LogLine) logs.Line {
+	severity := logs.SeverityUnknown
+	switch line.Severity {
+	case pb.LogSeverity_LOG_DEBUG:
+		severity = logs.SeverityDebug
+	case pb.LogSeverity_LOG_INFO:
+		severity = logs.SeverityInfo
+	case pb.LogSeverity_LOG_WARNING:
+		severity = logs.SeverityWarning
+	case pb.LogSeverity_LOG_ERROR:
+		severity = logs.SeverityError
+	}
+
+	return logs.Line{
+		Time:     time.UnixMilli(line.TimestampMs),
+		Stream:   line.Stream,
+		Text:     line.Text,
+		Severity: severity,
+	}
+}
@@ -1,6 +1,57 @@
 package grpc
 
-import "github.com/tartavull/mcp-manager/internal/server"
+import (
+	"time"
+
+	pb "github.com/tartavull/mcp-manager/internal/grpc/pb"
+	"github.com/tartavull/mcp-manager/internal/grpc/types"
+	"github.com/tartavull/mcp-manager/internal/logs"
+	"github.com/tartavull/mcp-manager/internal/plugin"
+	"github.com/tartavull/mcp-manager/internal/server"
+	"github.com/tartavull/mcp-manager/internal/transcript"
+)
+
+// Values reported by managers, see package types
+type (
+	Failover               = types.Failover
+	CircuitChange          = types.CircuitChange
+	ConfigChange           = types.ConfigChange
+	StatusChange           = types.StatusChange
+	PortMigration          = types.PortMigration
+	Approval               = types.Approval
+	ApprovalChange         = types.ApprovalChange
+	Fleet                  = types.Fleet
+	BrowserProfile         = types.BrowserProfile
+	ProfileSnapshot        = types.ProfileSnapshot
+	DiskDir                = types.DiskDir
+	ServerDisk             = types.ServerDisk
+	Backup                 = types.Backup
+	ToolConflict           = types.ToolConflict
+	Validation             = types.Validation
+	ProviderStats          = types.ProviderStats
+	ProviderGroup          = types.ProviderGroup
+	NotificationPreference = types.NotificationPreference
+	RuntimeVar             = types.RuntimeVar
+	RuntimeLimit           = types.RuntimeLimit
+	Runtime                = types.Runtime
+)
+
+// States and kinds of the values reported by managers, see package types
+const (
+	PortSwitched     = types.PortSwitched
+	PortReleased     = types.PortReleased
+	PortFailed       = types.PortFailed
+	ApprovalPending  = types.ApprovalPending
+	ApprovalApproved = types.ApprovalApproved
+	ApprovalRejected = types.ApprovalRejected
+	ApprovalExpired  = types.ApprovalExpired
+	DiskCache        = types.DiskCache
+	DiskData         = types.DiskData
+	VarConfig        = types.VarConfig
+	VarSecret        = types.VarSecret
+	VarProxy         = types.VarProxy
+	VarProfile       = types.VarProfile
+)
 
 // ManagerInterface defines the interface needed by the gRPC server
 type ManagerInterface interface {
@@ -11,6 +62,153 @@ type ManagerInterface interface {
 	StopServer(name string) error
 	GetConfigPath() (string, error)
 	UpdateToolCounts() error
+	SetMaintenance(name string, enabled bool) error
+	Maintenance() (bool, error)
 	StopAllServers()
 	Stop() error
 }
+
+// Registrar is implemented by managers that accept daemons joining their
+// fleet, enabling the Register RPC
+type Registrar interface {
+	Register(host, address string) error
+}
+
+// FailoverSource is implemented by managers that move servers between
+// hosts, whose failovers are broadcast as events
+type FailoverSource interface {
+	Failovers() <-chan Failover
+}
+
+// CircuitSource is implemented by managers whose proxies have circuit
+// breakers, whose state changes are broadcast as events
+type CircuitSource interface {
+	CircuitChanges() <-chan CircuitChange
+}
+
+// ConfigSource is implemented by managers reloading the configuration when
+// it changes, whose reloads are broadcast as events
+type ConfigSource interface {
+	ConfigChanges() <-chan ConfigChange
+}
+
+// StatusSource is implemented by managers supervising their servers'
+// processes, whose crashes and restarts are broadcast at once rather than
+// when status changes are next polled, which could miss a quick restart
+type StatusSource interface {
+	StatusChanges() <-chan StatusChange
+}
+
+// PortSource is implemented by managers moving running servers to new
+// ports, whose migrations are broadcast as events
+type PortSource interface {
+	PortMigrations() <-chan PortMigration
+}
+
+// Approver is implemented by managers whose proxies park calls for
+// approval, enabling the ListApprovals and DecideApproval RPCs. Changes are
+// broadcast as events.
+type Approver interface {
+	PendingApprovals() []Approval
+	DecideApproval(id string, approved bool, reason string) error
+	ApprovalChanges() <-chan ApprovalChange
+}
+
+// TranscriptSource is implemented by managers recording the proxied calls
+// of each client, enabling the ListSessions and GetSession RPCs
+type TranscriptSource interface {
+	Sessions() []transcript.Session
+	FindSessions(id string) []transcript.Session // Only the calls given id as request or run ID
+	Session(id string) (*transcript.Session, error)
+}
+
+// PluginSource is implemented by managers finding plugins in a
+// directory, enabling the ListPlugins RPC
+type PluginSource interface {
+	Plugins() ([]*plugin.Plugin, error)
+}
+
+// FleetManager is implemented by managers copying servers per evaluation
+// run, enabling the CreateFleet, DestroyFleet and ListFleets RPCs
+type FleetManager interface {
+	CreateFleet(runID string, servers []string, start bool) (*Fleet, error)
+	DestroyFleet(runID string) error
+	Fleets() []Fleet
+}
+
+// Drainer is implemented by managers that stop servers gracefully, once
+// the calls in flight on their proxies finish, enabling the DrainServer RPC
+type Drainer interface {
+	DrainServer(name string, timeout time.Duration) error
+}
+
+// ProfileManager is implemented by managers keeping the browser profiles
+// of servers, enabling the ListProfiles, ResetProfile, SnapshotProfile and
+// RestoreProfile RPCs. An empty profile is the one the server uses.
+type ProfileManager interface {
+	BrowserProfiles(server string) ([]BrowserProfile, error)
+	ResetProfile(server, profile string) error
+	SnapshotProfile(server, profile string) (*ProfileSnapshot, error)
+	RestoreProfile(server, profile, snapshot string) error
+}
+
+// DiskManager is implemented by managers tracking the files of servers,
+// enabling the DiskUsage and CleanCaches RPCs. Both apply to all servers
+// when none are named.
+type DiskManager interface {
+	DiskUsage(names []string) ([]ServerDisk, error)
+	CleanCaches(names []string, dryRun bool) ([]ServerDisk, error)
+}
+
+// BackupManager is implemented by managers archiving the data paths of
+// servers, enabling the BackupServer, ListBackups and RestoreBackup RPCs.
+// Running servers are stopped meanwhile and started again.
+type BackupManager interface {
+	BackupServer(name string) (*Backup, error)
+	Backups(name string) ([]Backup, error)
+	RestoreBackup(name, backup string) error
+}
+
+// LogSource is implemented by managers capturing the output of their
+// servers, enabling the StreamLogs RPC
+type LogSource interface {
+	Logs(name string) (*logs.Buffer, error)
+}
+
+// EventExporter receives the events broadcast to subscribers, e.g. to
+// forward them to a message bus. Export must not block.
+type EventExporter interface {
+	Export(event *pb.Event)
+}
+
+// ConfigValidator checks the configuration, enabling the ValidateConfig RPC
+type ConfigValidator interface {
+	ValidateConfig() (*Validation, error)
+}
+
+// ProviderSelector selects the servers serving generic gateway tools,
+// enabling the ListProviders and PinProvider RPCs
+type ProviderSelector interface {
+	Providers() ([]ProviderGroup, error)
+	PinProvider(tool, server string) error // Empty server selects automatically again
+}
+
+// Notifier shows desktop notifications of the broadcast events, enabling
+// the ListNotifications and SetNotification RPCs
+type Notifier interface {
+	EventExporter
+	Preferences() (string, []NotificationPreference, error) // Default level and the level of every server
+	SetPreference(server, level string) error               // Empty level resets the server to the default
+}
+
+// Describer is implemented by managers spawning servers, enabling the
+// DescribeServer RPC
+type Describer interface {
+	DescribeServer(name string) (*Runtime, error)
+}
+
+// Cloner is implemented by managers editing mcp.json, enabling the
+// CloneServer RPC
+type Cloner interface {
+	CloneServer(source, name string, replace []string) (string, int, error)
+}
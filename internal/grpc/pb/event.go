@@ -0,0 +1,22 @@
+package pb
+
+// ServerName returns the name of the server an event is about, or "" for
+// events about no single server
+func (x *Event) ServerName() string {
+	switch payload := x.GetPayload().(type) {
+	case *Event_ServerStatus:
+		return payload.ServerStatus.GetServerName()
+	case *Event_ToolUpdate:
+		return payload.ToolUpdate.GetServerName()
+	case *Event_Failover:
+		return payload.Failover.GetServerName()
+	case *Event_CircuitBreaker:
+		return payload.CircuitBreaker.GetServerName()
+	case *Event_Approval:
+		return payload.Approval.GetApproval().GetServerName()
+	case *Event_PortMigration:
+		return payload.PortMigration.GetServerName()
+	default:
+		return ""
+	}
+}
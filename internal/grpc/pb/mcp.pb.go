@@ -79,10 +79,15 @@ func (ServerStatus) EnumDescriptor() ([]byte, []int) {
 type EventType int32
 
 const (
-	EventType_ALL           EventType = 0
-	EventType_SERVER_STATUS EventType = 1
-	EventType_TOOL_UPDATE   EventType = 2
-	EventType_CONFIG_CHANGE EventType = 3
+	EventType_ALL             EventType = 0
+	EventType_SERVER_STATUS   EventType = 1
+	EventType_TOOL_UPDATE     EventType = 2
+	EventType_CONFIG_CHANGE   EventType = 3
+	EventType_FAILOVER        EventType = 4
+	EventType_HEARTBEAT       EventType = 5 // Sent on every stream regardless of the requested types
+	EventType_CIRCUIT_BREAKER EventType = 6
+	EventType_APPROVAL        EventType = 7
+	EventType_PORT_MIGRATION  EventType = 8
 )
 
 // Enum value maps for EventType.
@@ -92,12 +97,22 @@ var (
 		1: "SERVER_STATUS",
 		2: "TOOL_UPDATE",
 		3: "CONFIG_CHANGE",
+		4: "FAILOVER",
+		5: "HEARTBEAT",
+		6: "CIRCUIT_BREAKER",
+		7: "APPROVAL",
+		8: "PORT_MIGRATION",
 	}
 	EventType_value = map[string]int32{
-		"ALL":           0,
-		"SERVER_STATUS": 1,
-		"TOOL_UPDATE":   2,
-		"CONFIG_CHANGE": 3,
+		"ALL":             0,
+		"SERVER_STATUS":   1,
+		"TOOL_UPDATE":     2,
+		"CONFIG_CHANGE":   3,
+		"FAILOVER":        4,
+		"HEARTBEAT":       5,
+		"CIRCUIT_BREAKER": 6,
+		"APPROVAL":        7,
+		"PORT_MIGRATION":  8,
 	}
 )
 
@@ -128,6 +143,61 @@ func (EventType) EnumDescriptor() ([]byte, []int) {
 	return file_mcp_proto_rawDescGZIP(), []int{1}
 }
 
+type LogSeverity int32
+
+const (
+	LogSeverity_LOG_UNKNOWN LogSeverity = 0
+	LogSeverity_LOG_DEBUG   LogSeverity = 1
+	LogSeverity_LOG_INFO    LogSeverity = 2
+	LogSeverity_LOG_WARNING LogSeverity = 3
+	LogSeverity_LOG_ERROR   LogSeverity = 4
+)
+
+// Enum value maps for LogSeverity.
+var (
+	LogSeverity_name = map[int32]string{
+		0: "LOG_UNKNOWN",
+		1: "LOG_DEBUG",
+		2: "LOG_INFO",
+		3: "LOG_WARNING",
+		4: "LOG_ERROR",
+	}
+	LogSeverity_value = map[string]int32{
+		"LOG_UNKNOWN": 0,
+		"LOG_DEBUG":   1,
+		"LOG_INFO":    2,
+		"LOG_WARNING": 3,
+		"LOG_ERROR":   4,
+	}
+)
+
+func (x LogSeverity) Enum() *LogSeverity {
+	p := new(LogSeverity)
+	*p = x
+	return p
+}
+
+func (x LogSeverity) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LogSeverity) Descriptor() protoreflect.EnumDescriptor {
+	return file_mcp_proto_enumTypes[2].Descriptor()
+}
+
+func (LogSeverity) Type() protoreflect.EnumType {
+	return &file_mcp_proto_enumTypes[2]
+}
+
+func (x LogSeverity) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LogSeverity.Descriptor instead.
+func (LogSeverity) EnumDescriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{2}
+}
+
 // Basic messages
 type Empty struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -209,6 +279,50 @@ func (x *ServerRequest) GetName() string {
 	return ""
 }
 
+type TagRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tag           string                 `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TagRequest) Reset() {
+	*x = TagRequest{}
+	mi := &file_mcp_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TagRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TagRequest) ProtoMessage() {}
+
+func (x *TagRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TagRequest.ProtoReflect.Descriptor instead.
+func (*TagRequest) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TagRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
 type StatusResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
@@ -219,7 +333,7 @@ type StatusResponse struct {
 
 func (x *StatusResponse) Reset() {
 	*x = StatusResponse{}
-	mi := &file_mcp_proto_msgTypes[2]
+	mi := &file_mcp_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -231,7 +345,7 @@ func (x *StatusResponse) String() string {
 func (*StatusResponse) ProtoMessage() {}
 
 func (x *StatusResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mcp_proto_msgTypes[2]
+	mi := &file_mcp_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -244,7 +358,7 @@ func (x *StatusResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StatusResponse.ProtoReflect.Descriptor instead.
 func (*StatusResponse) Descriptor() ([]byte, []int) {
-	return file_mcp_proto_rawDescGZIP(), []int{2}
+	return file_mcp_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *StatusResponse) GetSuccess() bool {
@@ -270,7 +384,7 @@ type PathResponse struct {
 
 func (x *PathResponse) Reset() {
 	*x = PathResponse{}
-	mi := &file_mcp_proto_msgTypes[3]
+	mi := &file_mcp_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -282,7 +396,7 @@ func (x *PathResponse) String() string {
 func (*PathResponse) ProtoMessage() {}
 
 func (x *PathResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mcp_proto_msgTypes[3]
+	mi := &file_mcp_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -295,7 +409,7 @@ func (x *PathResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PathResponse.ProtoReflect.Descriptor instead.
 func (*PathResponse) Descriptor() ([]byte, []int) {
-	return file_mcp_proto_rawDescGZIP(), []int{3}
+	return file_mcp_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *PathResponse) GetPath() string {
@@ -305,25 +419,145 @@ func (x *PathResponse) GetPath() string {
 	return ""
 }
 
-// Server related messages
-type Server struct {
+type MaintenanceRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Command       string                 `protobuf:"bytes,2,opt,name=command,proto3" json:"command,omitempty"`
-	Port          int32                  `protobuf:"varint,3,opt,name=port,proto3" json:"port,omitempty"`
-	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
-	Status        ServerStatus           `protobuf:"varint,5,opt,name=status,proto3,enum=mcp.ServerStatus" json:"status,omitempty"`
-	Pid           int32                  `protobuf:"varint,6,opt,name=pid,proto3" json:"pid,omitempty"`
-	ToolCount     int32                  `protobuf:"varint,7,opt,name=tool_count,json=toolCount,proto3" json:"tool_count,omitempty"`
-	Tools         []*Tool                `protobuf:"bytes,8,rep,name=tools,proto3" json:"tools,omitempty"`
-	LastUpdated   int64                  `protobuf:"varint,9,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"` // Unix timestamp
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"` // Empty for the whole daemon
+	Enabled       bool                   `protobuf:"varint,2,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MaintenanceRequest) Reset() {
+	*x = MaintenanceRequest{}
+	mi := &file_mcp_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MaintenanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MaintenanceRequest) ProtoMessage() {}
+
+func (x *MaintenanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MaintenanceRequest.ProtoReflect.Descriptor instead.
+func (*MaintenanceRequest) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *MaintenanceRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MaintenanceRequest) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+type RegisterRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Host          string                 `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`       // Label shown for the daemon's servers
+	Address       string                 `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"` // gRPC address the coordinator reaches the daemon on
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
+func (x *RegisterRequest) Reset() {
+	*x = RegisterRequest{}
+	mi := &file_mcp_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterRequest) ProtoMessage() {}
+
+func (x *RegisterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterRequest.ProtoReflect.Descriptor instead.
+func (*RegisterRequest) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RegisterRequest) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *RegisterRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+// Server related messages
+type Server struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Name           string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Command        string                 `protobuf:"bytes,2,opt,name=command,proto3" json:"command,omitempty"`
+	Port           int32                  `protobuf:"varint,3,opt,name=port,proto3" json:"port,omitempty"`
+	Description    string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	Status         ServerStatus           `protobuf:"varint,5,opt,name=status,proto3,enum=mcp.ServerStatus" json:"status,omitempty"`
+	Pid            int32                  `protobuf:"varint,6,opt,name=pid,proto3" json:"pid,omitempty"`
+	ToolCount      int32                  `protobuf:"varint,7,opt,name=tool_count,json=toolCount,proto3" json:"tool_count,omitempty"`
+	Tools          []*Tool                `protobuf:"bytes,8,rep,name=tools,proto3" json:"tools,omitempty"`
+	LastUpdated    int64                  `protobuf:"varint,9,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"` // Unix timestamp
+	Health         string                 `protobuf:"bytes,10,opt,name=health,proto3" json:"health,omitempty"`                              // Custom health check result: "", "pending", "healthy" or "unhealthy"
+	HealthMessage  string                 `protobuf:"bytes,11,opt,name=health_message,json=healthMessage,proto3" json:"health_message,omitempty"`
+	Host           string                 `protobuf:"bytes,12,opt,name=host,proto3" json:"host,omitempty"`                                        // Daemon running the server, set by coordinators
+	RestartPolicy  string                 `protobuf:"bytes,13,opt,name=restart_policy,json=restartPolicy,proto3" json:"restart_policy,omitempty"` // "", "never", "on-failure" or "always"
+	MaxRestarts    int32                  `protobuf:"varint,14,opt,name=max_restarts,json=maxRestarts,proto3" json:"max_restarts,omitempty"`
+	Restarts       int32                  `protobuf:"varint,15,opt,name=restarts,proto3" json:"restarts,omitempty"` // Automatic restarts since the last manual start
+	Maintenance    bool                   `protobuf:"varint,16,opt,name=maintenance,proto3" json:"maintenance,omitempty"`
+	Requests       int64                  `protobuf:"varint,17,opt,name=requests,proto3" json:"requests,omitempty"`                                  // MCP requests served by the proxy since it started
+	StartedAt      int64                  `protobuf:"varint,18,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`               // Unix timestamp, zero unless running
+	Autostart      bool                   `protobuf:"varint,19,opt,name=autostart,proto3" json:"autostart,omitempty"`                                // Started when the daemon starts
+	RunId          string                 `protobuf:"bytes,20,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`                            // Evaluation run whose fleet the server belongs to
+	Group          string                 `protobuf:"bytes,21,opt,name=group,proto3" json:"group,omitempty"`                                         // Template the server is an instance of
+	Draining       bool                   `protobuf:"varint,22,opt,name=draining,proto3" json:"draining,omitempty"`                                  // Gets no new gateway calls, stopping once idle
+	InFlight       int64                  `protobuf:"varint,23,opt,name=in_flight,json=inFlight,proto3" json:"in_flight,omitempty"`                  // MCP requests the proxy is answering
+	BrowserProfile string                 `protobuf:"bytes,24,opt,name=browser_profile,json=browserProfile,proto3" json:"browser_profile,omitempty"` // Browser profile used as the user data dir
+	Tags           []string               `protobuf:"bytes,25,rep,name=tags,proto3" json:"tags,omitempty"`                                           // Labels to select servers by, e.g. browser
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
 func (x *Server) Reset() {
 	*x = Server{}
-	mi := &file_mcp_proto_msgTypes[4]
+	mi := &file_mcp_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -335,7 +569,7 @@ func (x *Server) String() string {
 func (*Server) ProtoMessage() {}
 
 func (x *Server) ProtoReflect() protoreflect.Message {
-	mi := &file_mcp_proto_msgTypes[4]
+	mi := &file_mcp_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -348,7 +582,7 @@ func (x *Server) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Server.ProtoReflect.Descriptor instead.
 func (*Server) Descriptor() ([]byte, []int) {
-	return file_mcp_proto_rawDescGZIP(), []int{4}
+	return file_mcp_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *Server) GetName() string {
@@ -414,6 +648,118 @@ func (x *Server) GetLastUpdated() int64 {
 	return 0
 }
 
+func (x *Server) GetHealth() string {
+	if x != nil {
+		return x.Health
+	}
+	return ""
+}
+
+func (x *Server) GetHealthMessage() string {
+	if x != nil {
+		return x.HealthMessage
+	}
+	return ""
+}
+
+func (x *Server) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *Server) GetRestartPolicy() string {
+	if x != nil {
+		return x.RestartPolicy
+	}
+	return ""
+}
+
+func (x *Server) GetMaxRestarts() int32 {
+	if x != nil {
+		return x.MaxRestarts
+	}
+	return 0
+}
+
+func (x *Server) GetRestarts() int32 {
+	if x != nil {
+		return x.Restarts
+	}
+	return 0
+}
+
+func (x *Server) GetMaintenance() bool {
+	if x != nil {
+		return x.Maintenance
+	}
+	return false
+}
+
+func (x *Server) GetRequests() int64 {
+	if x != nil {
+		return x.Requests
+	}
+	return 0
+}
+
+func (x *Server) GetStartedAt() int64 {
+	if x != nil {
+		return x.StartedAt
+	}
+	return 0
+}
+
+func (x *Server) GetAutostart() bool {
+	if x != nil {
+		return x.Autostart
+	}
+	return false
+}
+
+func (x *Server) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *Server) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *Server) GetDraining() bool {
+	if x != nil {
+		return x.Draining
+	}
+	return false
+}
+
+func (x *Server) GetInFlight() int64 {
+	if x != nil {
+		return x.InFlight
+	}
+	return 0
+}
+
+func (x *Server) GetBrowserProfile() string {
+	if x != nil {
+		return x.BrowserProfile
+	}
+	return ""
+}
+
+func (x *Server) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
 type ServerList struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Servers       []*Server              `protobuf:"bytes,1,rep,name=servers,proto3" json:"servers,omitempty"`
@@ -424,7 +770,7 @@ type ServerList struct {
 
 func (x *ServerList) Reset() {
 	*x = ServerList{}
-	mi := &file_mcp_proto_msgTypes[5]
+	mi := &file_mcp_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -436,7 +782,7 @@ func (x *ServerList) String() string {
 func (*ServerList) ProtoMessage() {}
 
 func (x *ServerList) ProtoReflect() protoreflect.Message {
-	mi := &file_mcp_proto_msgTypes[5]
+	mi := &file_mcp_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -449,7 +795,7 @@ func (x *ServerList) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ServerList.ProtoReflect.Descriptor instead.
 func (*ServerList) Descriptor() ([]byte, []int) {
-	return file_mcp_proto_rawDescGZIP(), []int{5}
+	return file_mcp_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *ServerList) GetServers() []*Server {
@@ -472,13 +818,14 @@ type Tool struct {
 	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
 	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	InputSchema   string                 `protobuf:"bytes,4,opt,name=input_schema,json=inputSchema,proto3" json:"input_schema,omitempty"` // JSON Schema of the arguments, only set by GetTools
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Tool) Reset() {
 	*x = Tool{}
-	mi := &file_mcp_proto_msgTypes[6]
+	mi := &file_mcp_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -490,7 +837,7 @@ func (x *Tool) String() string {
 func (*Tool) ProtoMessage() {}
 
 func (x *Tool) ProtoReflect() protoreflect.Message {
-	mi := &file_mcp_proto_msgTypes[6]
+	mi := &file_mcp_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -503,7 +850,7 @@ func (x *Tool) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Tool.ProtoReflect.Descriptor instead.
 func (*Tool) Descriptor() ([]byte, []int) {
-	return file_mcp_proto_rawDescGZIP(), []int{6}
+	return file_mcp_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *Tool) GetName() string {
@@ -527,16 +874,23 @@ func (x *Tool) GetDescription() string {
 	return ""
 }
 
-type ToolList struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Tools         []*Tool                `protobuf:"bytes,1,rep,name=tools,proto3" json:"tools,omitempty"`
-	unknownFields protoimpl.UnknownFields
+func (x *Tool) GetInputSchema() string {
+	if x != nil {
+		return x.InputSchema
+	}
+	return ""
+}
+
+type ToolList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tools         []*Tool                `protobuf:"bytes,1,rep,name=tools,proto3" json:"tools,omitempty"`
+	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ToolList) Reset() {
 	*x = ToolList{}
-	mi := &file_mcp_proto_msgTypes[7]
+	mi := &file_mcp_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -548,7 +902,7 @@ func (x *ToolList) String() string {
 func (*ToolList) ProtoMessage() {}
 
 func (x *ToolList) ProtoReflect() protoreflect.Message {
-	mi := &file_mcp_proto_msgTypes[7]
+	mi := &file_mcp_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -561,7 +915,7 @@ func (x *ToolList) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ToolList.ProtoReflect.Descriptor instead.
 func (*ToolList) Descriptor() ([]byte, []int) {
-	return file_mcp_proto_rawDescGZIP(), []int{7}
+	return file_mcp_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *ToolList) GetTools() []*Tool {
@@ -583,7 +937,7 @@ type Config struct {
 
 func (x *Config) Reset() {
 	*x = Config{}
-	mi := &file_mcp_proto_msgTypes[8]
+	mi := &file_mcp_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -595,7 +949,7 @@ func (x *Config) String() string {
 func (*Config) ProtoMessage() {}
 
 func (x *Config) ProtoReflect() protoreflect.Message {
-	mi := &file_mcp_proto_msgTypes[8]
+	mi := &file_mcp_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -608,7 +962,7 @@ func (x *Config) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Config.ProtoReflect.Descriptor instead.
 func (*Config) Descriptor() ([]byte, []int) {
-	return file_mcp_proto_rawDescGZIP(), []int{8}
+	return file_mcp_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *Config) GetConfigPath() string {
@@ -643,7 +997,7 @@ type ServerConfig struct {
 
 func (x *ServerConfig) Reset() {
 	*x = ServerConfig{}
-	mi := &file_mcp_proto_msgTypes[9]
+	mi := &file_mcp_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -655,7 +1009,7 @@ func (x *ServerConfig) String() string {
 func (*ServerConfig) ProtoMessage() {}
 
 func (x *ServerConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_mcp_proto_msgTypes[9]
+	mi := &file_mcp_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -668,7 +1022,7 @@ func (x *ServerConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ServerConfig.ProtoReflect.Descriptor instead.
 func (*ServerConfig) Descriptor() ([]byte, []int) {
-	return file_mcp_proto_rawDescGZIP(), []int{9}
+	return file_mcp_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *ServerConfig) GetCommand() string {
@@ -692,6 +1046,127 @@ func (x *ServerConfig) GetDescription() string {
 	return ""
 }
 
+type ConfigValidation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Problems      []string               `protobuf:"bytes,1,rep,name=problems,proto3" json:"problems,omitempty"` // Settings that can't be applied
+	Conflicts     []*ToolConflict        `protobuf:"bytes,2,rep,name=conflicts,proto3" json:"conflicts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfigValidation) Reset() {
+	*x = ConfigValidation{}
+	mi := &file_mcp_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfigValidation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigValidation) ProtoMessage() {}
+
+func (x *ConfigValidation) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigValidation.ProtoReflect.Descriptor instead.
+func (*ConfigValidation) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ConfigValidation) GetProblems() []string {
+	if x != nil {
+		return x.Problems
+	}
+	return nil
+}
+
+func (x *ConfigValidation) GetConflicts() []*ToolConflict {
+	if x != nil {
+		return x.Conflicts
+	}
+	return nil
+}
+
+// A tool name exposed by several servers in the gateway
+type ToolConflict struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tool          string                 `protobuf:"bytes,1,opt,name=tool,proto3" json:"tool,omitempty"`
+	Servers       []string               `protobuf:"bytes,2,rep,name=servers,proto3" json:"servers,omitempty"`                                                                           // The server keeping the name first
+	Renamed       map[string]string      `protobuf:"bytes,3,rep,name=renamed,proto3" json:"renamed,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Server to the name its tool is exposed as instead
+	Hidden        []string               `protobuf:"bytes,4,rep,name=hidden,proto3" json:"hidden,omitempty"`                                                                             // Servers whose tool isn't exposed
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ToolConflict) Reset() {
+	*x = ToolConflict{}
+	mi := &file_mcp_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ToolConflict) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ToolConflict) ProtoMessage() {}
+
+func (x *ToolConflict) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ToolConflict.ProtoReflect.Descriptor instead.
+func (*ToolConflict) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ToolConflict) GetTool() string {
+	if x != nil {
+		return x.Tool
+	}
+	return ""
+}
+
+func (x *ToolConflict) GetServers() []string {
+	if x != nil {
+		return x.Servers
+	}
+	return nil
+}
+
+func (x *ToolConflict) GetRenamed() map[string]string {
+	if x != nil {
+		return x.Renamed
+	}
+	return nil
+}
+
+func (x *ToolConflict) GetHidden() []string {
+	if x != nil {
+		return x.Hidden
+	}
+	return nil
+}
+
 // Streaming messages
 type SubscribeRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -702,7 +1177,7 @@ type SubscribeRequest struct {
 
 func (x *SubscribeRequest) Reset() {
 	*x = SubscribeRequest{}
-	mi := &file_mcp_proto_msgTypes[10]
+	mi := &file_mcp_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -714,7 +1189,7 @@ func (x *SubscribeRequest) String() string {
 func (*SubscribeRequest) ProtoMessage() {}
 
 func (x *SubscribeRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mcp_proto_msgTypes[10]
+	mi := &file_mcp_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -727,7 +1202,7 @@ func (x *SubscribeRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SubscribeRequest.ProtoReflect.Descriptor instead.
 func (*SubscribeRequest) Descriptor() ([]byte, []int) {
-	return file_mcp_proto_rawDescGZIP(), []int{10}
+	return file_mcp_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *SubscribeRequest) GetEventTypes() []EventType {
@@ -746,6 +1221,11 @@ type Event struct {
 	//	*Event_ServerStatus
 	//	*Event_ToolUpdate
 	//	*Event_ConfigChange
+	//	*Event_Failover
+	//	*Event_Heartbeat
+	//	*Event_CircuitBreaker
+	//	*Event_Approval
+	//	*Event_PortMigration
 	Payload       isEvent_Payload `protobuf_oneof:"payload"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -753,7 +1233,7 @@ type Event struct {
 
 func (x *Event) Reset() {
 	*x = Event{}
-	mi := &file_mcp_proto_msgTypes[11]
+	mi := &file_mcp_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -765,7 +1245,7 @@ func (x *Event) String() string {
 func (*Event) ProtoMessage() {}
 
 func (x *Event) ProtoReflect() protoreflect.Message {
-	mi := &file_mcp_proto_msgTypes[11]
+	mi := &file_mcp_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -778,7 +1258,7 @@ func (x *Event) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Event.ProtoReflect.Descriptor instead.
 func (*Event) Descriptor() ([]byte, []int) {
-	return file_mcp_proto_rawDescGZIP(), []int{11}
+	return file_mcp_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *Event) GetType() EventType {
@@ -829,6 +1309,51 @@ func (x *Event) GetConfigChange() *ConfigChangeEvent {
 	return nil
 }
 
+func (x *Event) GetFailover() *FailoverEvent {
+	if x != nil {
+		if x, ok := x.Payload.(*Event_Failover); ok {
+			return x.Failover
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetHeartbeat() *HeartbeatEvent {
+	if x != nil {
+		if x, ok := x.Payload.(*Event_Heartbeat); ok {
+			return x.Heartbeat
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetCircuitBreaker() *CircuitBreakerEvent {
+	if x != nil {
+		if x, ok := x.Payload.(*Event_CircuitBreaker); ok {
+			return x.CircuitBreaker
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetApproval() *ApprovalEvent {
+	if x != nil {
+		if x, ok := x.Payload.(*Event_Approval); ok {
+			return x.Approval
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetPortMigration() *PortMigrationEvent {
+	if x != nil {
+		if x, ok := x.Payload.(*Event_PortMigration); ok {
+			return x.PortMigration
+		}
+	}
+	return nil
+}
+
 type isEvent_Payload interface {
 	isEvent_Payload()
 }
@@ -845,12 +1370,42 @@ type Event_ConfigChange struct {
 	ConfigChange *ConfigChangeEvent `protobuf:"bytes,5,opt,name=config_change,json=configChange,proto3,oneof"`
 }
 
+type Event_Failover struct {
+	Failover *FailoverEvent `protobuf:"bytes,6,opt,name=failover,proto3,oneof"`
+}
+
+type Event_Heartbeat struct {
+	Heartbeat *HeartbeatEvent `protobuf:"bytes,7,opt,name=heartbeat,proto3,oneof"`
+}
+
+type Event_CircuitBreaker struct {
+	CircuitBreaker *CircuitBreakerEvent `protobuf:"bytes,8,opt,name=circuit_breaker,json=circuitBreaker,proto3,oneof"`
+}
+
+type Event_Approval struct {
+	Approval *ApprovalEvent `protobuf:"bytes,9,opt,name=approval,proto3,oneof"`
+}
+
+type Event_PortMigration struct {
+	PortMigration *PortMigrationEvent `protobuf:"bytes,10,opt,name=port_migration,json=portMigration,proto3,oneof"`
+}
+
 func (*Event_ServerStatus) isEvent_Payload() {}
 
 func (*Event_ToolUpdate) isEvent_Payload() {}
 
 func (*Event_ConfigChange) isEvent_Payload() {}
 
+func (*Event_Failover) isEvent_Payload() {}
+
+func (*Event_Heartbeat) isEvent_Payload() {}
+
+func (*Event_CircuitBreaker) isEvent_Payload() {}
+
+func (*Event_Approval) isEvent_Payload() {}
+
+func (*Event_PortMigration) isEvent_Payload() {}
+
 type ServerStatusEvent struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ServerName    string                 `protobuf:"bytes,1,opt,name=server_name,json=serverName,proto3" json:"server_name,omitempty"`
@@ -862,7 +1417,7 @@ type ServerStatusEvent struct {
 
 func (x *ServerStatusEvent) Reset() {
 	*x = ServerStatusEvent{}
-	mi := &file_mcp_proto_msgTypes[12]
+	mi := &file_mcp_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -874,7 +1429,7 @@ func (x *ServerStatusEvent) String() string {
 func (*ServerStatusEvent) ProtoMessage() {}
 
 func (x *ServerStatusEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_mcp_proto_msgTypes[12]
+	mi := &file_mcp_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -887,7 +1442,7 @@ func (x *ServerStatusEvent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ServerStatusEvent.ProtoReflect.Descriptor instead.
 func (*ServerStatusEvent) Descriptor() ([]byte, []int) {
-	return file_mcp_proto_rawDescGZIP(), []int{12}
+	return file_mcp_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *ServerStatusEvent) GetServerName() string {
@@ -922,7 +1477,7 @@ type ToolUpdateEvent struct {
 
 func (x *ToolUpdateEvent) Reset() {
 	*x = ToolUpdateEvent{}
-	mi := &file_mcp_proto_msgTypes[13]
+	mi := &file_mcp_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -934,7 +1489,7 @@ func (x *ToolUpdateEvent) String() string {
 func (*ToolUpdateEvent) ProtoMessage() {}
 
 func (x *ToolUpdateEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_mcp_proto_msgTypes[13]
+	mi := &file_mcp_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -947,7 +1502,7 @@ func (x *ToolUpdateEvent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ToolUpdateEvent.ProtoReflect.Descriptor instead.
 func (*ToolUpdateEvent) Descriptor() ([]byte, []int) {
-	return file_mcp_proto_rawDescGZIP(), []int{13}
+	return file_mcp_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *ToolUpdateEvent) GetServerName() string {
@@ -975,14 +1530,15 @@ type ConfigChangeEvent struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
 	ServersAdded    []string               `protobuf:"bytes,1,rep,name=servers_added,json=serversAdded,proto3" json:"servers_added,omitempty"`
 	ServersRemoved  []string               `protobuf:"bytes,2,rep,name=servers_removed,json=serversRemoved,proto3" json:"servers_removed,omitempty"`
-	ServersModified []string               `protobuf:"bytes,3,rep,name=servers_modified,json=serversModified,proto3" json:"servers_modified,omitempty"`
+	ServersModified []string               `protobuf:"bytes,3,rep,name=servers_modified,json=serversModified,proto3" json:"servers_modified,omitempty"` // Changes needing a restart, done at once if running
+	ServersUpdated  []string               `protobuf:"bytes,4,rep,name=servers_updated,json=serversUpdated,proto3" json:"servers_updated,omitempty"`    // Metadata changes applied without a restart
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
 
 func (x *ConfigChangeEvent) Reset() {
 	*x = ConfigChangeEvent{}
-	mi := &file_mcp_proto_msgTypes[14]
+	mi := &file_mcp_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -994,7 +1550,7 @@ func (x *ConfigChangeEvent) String() string {
 func (*ConfigChangeEvent) ProtoMessage() {}
 
 func (x *ConfigChangeEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_mcp_proto_msgTypes[14]
+	mi := &file_mcp_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1007,7 +1563,7 @@ func (x *ConfigChangeEvent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ConfigChangeEvent.ProtoReflect.Descriptor instead.
 func (*ConfigChangeEvent) Descriptor() ([]byte, []int) {
-	return file_mcp_proto_rawDescGZIP(), []int{14}
+	return file_mcp_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *ConfigChangeEvent) GetServersAdded() []string {
@@ -1031,32 +1587,38 @@ func (x *ConfigChangeEvent) GetServersModified() []string {
 	return nil
 }
 
-// Health check
-type HealthStatus struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	Healthy        bool                   `protobuf:"varint,1,opt,name=healthy,proto3" json:"healthy,omitempty"`
-	UptimeSeconds  int64                  `protobuf:"varint,2,opt,name=uptime_seconds,json=uptimeSeconds,proto3" json:"uptime_seconds,omitempty"`
-	RunningServers int32                  `protobuf:"varint,3,opt,name=running_servers,json=runningServers,proto3" json:"running_servers,omitempty"`
-	TotalServers   int32                  `protobuf:"varint,4,opt,name=total_servers,json=totalServers,proto3" json:"total_servers,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+func (x *ConfigChangeEvent) GetServersUpdated() []string {
+	if x != nil {
+		return x.ServersUpdated
+	}
+	return nil
 }
 
-func (x *HealthStatus) Reset() {
-	*x = HealthStatus{}
-	mi := &file_mcp_proto_msgTypes[15]
+type FailoverEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServerName    string                 `protobuf:"bytes,1,opt,name=server_name,json=serverName,proto3" json:"server_name,omitempty"`
+	FromHost      string                 `protobuf:"bytes,2,opt,name=from_host,json=fromHost,proto3" json:"from_host,omitempty"` // Empty when no host was active
+	ToHost        string                 `protobuf:"bytes,3,opt,name=to_host,json=toHost,proto3" json:"to_host,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FailoverEvent) Reset() {
+	*x = FailoverEvent{}
+	mi := &file_mcp_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *HealthStatus) String() string {
+func (x *FailoverEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HealthStatus) ProtoMessage() {}
+func (*FailoverEvent) ProtoMessage() {}
 
-func (x *HealthStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_mcp_proto_msgTypes[15]
+func (x *FailoverEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1067,74 +1629,3153 @@ func (x *HealthStatus) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HealthStatus.ProtoReflect.Descriptor instead.
-func (*HealthStatus) Descriptor() ([]byte, []int) {
-	return file_mcp_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use FailoverEvent.ProtoReflect.Descriptor instead.
+func (*FailoverEvent) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{20}
 }
 
-func (x *HealthStatus) GetHealthy() bool {
+func (x *FailoverEvent) GetServerName() string {
 	if x != nil {
-		return x.Healthy
+		return x.ServerName
 	}
-	return false
+	return ""
 }
 
-func (x *HealthStatus) GetUptimeSeconds() int64 {
+func (x *FailoverEvent) GetFromHost() string {
 	if x != nil {
-		return x.UptimeSeconds
+		return x.FromHost
 	}
-	return 0
+	return ""
 }
 
-func (x *HealthStatus) GetRunningServers() int32 {
+func (x *FailoverEvent) GetToHost() string {
 	if x != nil {
-		return x.RunningServers
+		return x.ToHost
 	}
-	return 0
+	return ""
 }
 
-func (x *HealthStatus) GetTotalServers() int32 {
+func (x *FailoverEvent) GetReason() string {
 	if x != nil {
-		return x.TotalServers
+		return x.Reason
 	}
-	return 0
+	return ""
 }
 
-var File_mcp_proto protoreflect.FileDescriptor
+type CircuitBreakerEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServerName    string                 `protobuf:"bytes,1,opt,name=server_name,json=serverName,proto3" json:"server_name,omitempty"`
+	State         string                 `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`   // closed, open or half-open
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"` // Failure that opened the circuit
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_mcp_proto_rawDesc = "" +
-	"\n" +
-	"\tmcp.proto\x12\x03mcp\"\a\n" +
-	"\x05Empty\"#\n" +
-	"\rServerRequest\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\"D\n" +
-	"\x0eStatusResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"\"\n" +
-	"\fPathResponse\x12\x12\n" +
-	"\x04path\x18\x01 \x01(\tR\x04path\"\x8c\x02\n" +
-	"\x06Server\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
-	"\acommand\x18\x02 \x01(\tR\acommand\x12\x12\n" +
-	"\x04port\x18\x03 \x01(\x05R\x04port\x12 \n" +
-	"\vdescription\x18\x04 \x01(\tR\vdescription\x12)\n" +
-	"\x06status\x18\x05 \x01(\x0e2\x11.mcp.ServerStatusR\x06status\x12\x10\n" +
-	"\x03pid\x18\x06 \x01(\x05R\x03pid\x12\x1d\n" +
-	"\n" +
-	"tool_count\x18\a \x01(\x05R\ttoolCount\x12\x1f\n" +
-	"\x05tools\x18\b \x03(\v2\t.mcp.ToolR\x05tools\x12!\n" +
-	"\flast_updated\x18\t \x01(\x03R\vlastUpdated\"I\n" +
-	"\n" +
-	"ServerList\x12%\n" +
-	"\aservers\x18\x01 \x03(\v2\v.mcp.ServerR\aservers\x12\x14\n" +
-	"\x05order\x18\x02 \x03(\tR\x05order\"R\n" +
-	"\x04Tool\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
-	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
-	"\vdescription\x18\x03 \x01(\tR\vdescription\"+\n" +
-	"\bToolList\x12\x1f\n" +
-	"\x05tools\x18\x01 \x03(\v2\t.mcp.ToolR\x05tools\"\xcf\x01\n" +
-	"\x06Config\x12\x1f\n" +
+func (x *CircuitBreakerEvent) Reset() {
+	*x = CircuitBreakerEvent{}
+	mi := &file_mcp_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CircuitBreakerEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CircuitBreakerEvent) ProtoMessage() {}
+
+func (x *CircuitBreakerEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CircuitBreakerEvent.ProtoReflect.Descriptor instead.
+func (*CircuitBreakerEvent) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *CircuitBreakerEvent) GetServerName() string {
+	if x != nil {
+		return x.ServerName
+	}
+	return ""
+}
+
+func (x *CircuitBreakerEvent) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *CircuitBreakerEvent) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ApprovalEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Approval      *Approval              `protobuf:"bytes,1,opt,name=approval,proto3" json:"approval,omitempty"`
+	State         string                 `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`   // pending, approved, rejected or expired
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"` // Why the call was rejected or expired
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApprovalEvent) Reset() {
+	*x = ApprovalEvent{}
+	mi := &file_mcp_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApprovalEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApprovalEvent) ProtoMessage() {}
+
+func (x *ApprovalEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApprovalEvent.ProtoReflect.Descriptor instead.
+func (*ApprovalEvent) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ApprovalEvent) GetApproval() *Approval {
+	if x != nil {
+		return x.Approval
+	}
+	return nil
+}
+
+func (x *ApprovalEvent) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *ApprovalEvent) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type PortMigrationEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServerName    string                 `protobuf:"bytes,1,opt,name=server_name,json=serverName,proto3" json:"server_name,omitempty"`
+	OldPort       int32                  `protobuf:"varint,2,opt,name=old_port,json=oldPort,proto3" json:"old_port,omitempty"`
+	NewPort       int32                  `protobuf:"varint,3,opt,name=new_port,json=newPort,proto3" json:"new_port,omitempty"`
+	State         string                 `protobuf:"bytes,4,opt,name=state,proto3" json:"state,omitempty"`   // switched, released or failed
+	Reason        string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"` // Why the migration failed
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PortMigrationEvent) Reset() {
+	*x = PortMigrationEvent{}
+	mi := &file_mcp_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PortMigrationEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PortMigrationEvent) ProtoMessage() {}
+
+func (x *PortMigrationEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PortMigrationEvent.ProtoReflect.Descriptor instead.
+func (*PortMigrationEvent) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *PortMigrationEvent) GetServerName() string {
+	if x != nil {
+		return x.ServerName
+	}
+	return ""
+}
+
+func (x *PortMigrationEvent) GetOldPort() int32 {
+	if x != nil {
+		return x.OldPort
+	}
+	return 0
+}
+
+func (x *PortMigrationEvent) GetNewPort() int32 {
+	if x != nil {
+		return x.NewPort
+	}
+	return 0
+}
+
+func (x *PortMigrationEvent) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *PortMigrationEvent) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type HeartbeatEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IntervalMs    int64                  `protobuf:"varint,1,opt,name=interval_ms,json=intervalMs,proto3" json:"interval_ms,omitempty"` // Time until the next heartbeat
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeartbeatEvent) Reset() {
+	*x = HeartbeatEvent{}
+	mi := &file_mcp_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeartbeatEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeartbeatEvent) ProtoMessage() {}
+
+func (x *HeartbeatEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeartbeatEvent.ProtoReflect.Descriptor instead.
+func (*HeartbeatEvent) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *HeartbeatEvent) GetIntervalMs() int64 {
+	if x != nil {
+		return x.IntervalMs
+	}
+	return 0
+}
+
+// Event journal
+type EventQuery struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          int64                  `protobuf:"varint,1,opt,name=from,proto3" json:"from,omitempty"` // Unix timestamps bounding the events, 0 for unbounded
+	To            int64                  `protobuf:"varint,2,opt,name=to,proto3" json:"to,omitempty"`
+	EventTypes    []EventType            `protobuf:"varint,3,rep,packed,name=event_types,json=eventTypes,proto3,enum=mcp.EventType" json:"event_types,omitempty"` // Empty for all
+	Server        string                 `protobuf:"bytes,4,opt,name=server,proto3" json:"server,omitempty"`                                                      // Server the events are about, empty for all
+	Limit         int32                  `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`                                                       // Maximum number of events, oldest first (default: 1000)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EventQuery) Reset() {
+	*x = EventQuery{}
+	mi := &file_mcp_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventQuery) ProtoMessage() {}
+
+func (x *EventQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventQuery.ProtoReflect.Descriptor instead.
+func (*EventQuery) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *EventQuery) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *EventQuery) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *EventQuery) GetEventTypes() []EventType {
+	if x != nil {
+		return x.EventTypes
+	}
+	return nil
+}
+
+func (x *EventQuery) GetServer() string {
+	if x != nil {
+		return x.Server
+	}
+	return ""
+}
+
+func (x *EventQuery) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type EventList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*Event               `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	Truncated     bool                   `protobuf:"varint,2,opt,name=truncated,proto3" json:"truncated,omitempty"` // More events matched than the limit
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EventList) Reset() {
+	*x = EventList{}
+	mi := &file_mcp_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventList) ProtoMessage() {}
+
+func (x *EventList) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventList.ProtoReflect.Descriptor instead.
+func (*EventList) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *EventList) GetEvents() []*Event {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *EventList) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+// Availability of servers
+type UptimeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Days          int32                  `protobuf:"varint,1,opt,name=days,proto3" json:"days,omitempty"` // Calendar days ending today (default: 7)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UptimeRequest) Reset() {
+	*x = UptimeRequest{}
+	mi := &file_mcp_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UptimeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UptimeRequest) ProtoMessage() {}
+
+func (x *UptimeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UptimeRequest.ProtoReflect.Descriptor instead.
+func (*UptimeRequest) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *UptimeRequest) GetDays() int32 {
+	if x != nil {
+		return x.Days
+	}
+	return 0
+}
+
+type ServerUptime struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Server        string                 `protobuf:"bytes,1,opt,name=server,proto3" json:"server,omitempty"`
+	UpSeconds     float64                `protobuf:"fixed64,2,opt,name=up_seconds,json=upSeconds,proto3" json:"up_seconds,omitempty"`       // Time running
+	DownSeconds   float64                `protobuf:"fixed64,3,opt,name=down_seconds,json=downSeconds,proto3" json:"down_seconds,omitempty"` // Time in the error state
+	Outages       int32                  `protobuf:"varint,4,opt,name=outages,proto3" json:"outages,omitempty"`                             // Times the server entered the error state
+	MttrSeconds   float64                `protobuf:"fixed64,5,opt,name=mttr_seconds,json=mttrSeconds,proto3" json:"mttr_seconds,omitempty"` // Mean time to leave the error state, 0 without repairs
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ServerUptime) Reset() {
+	*x = ServerUptime{}
+	mi := &file_mcp_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServerUptime) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerUptime) ProtoMessage() {}
+
+func (x *ServerUptime) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerUptime.ProtoReflect.Descriptor instead.
+func (*ServerUptime) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ServerUptime) GetServer() string {
+	if x != nil {
+		return x.Server
+	}
+	return ""
+}
+
+func (x *ServerUptime) GetUpSeconds() float64 {
+	if x != nil {
+		return x.UpSeconds
+	}
+	return 0
+}
+
+func (x *ServerUptime) GetDownSeconds() float64 {
+	if x != nil {
+		return x.DownSeconds
+	}
+	return 0
+}
+
+func (x *ServerUptime) GetOutages() int32 {
+	if x != nil {
+		return x.Outages
+	}
+	return 0
+}
+
+func (x *ServerUptime) GetMttrSeconds() float64 {
+	if x != nil {
+		return x.MttrSeconds
+	}
+	return 0
+}
+
+type UptimeReport struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Servers       []*ServerUptime        `protobuf:"bytes,1,rep,name=servers,proto3" json:"servers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UptimeReport) Reset() {
+	*x = UptimeReport{}
+	mi := &file_mcp_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UptimeReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UptimeReport) ProtoMessage() {}
+
+func (x *UptimeReport) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UptimeReport.ProtoReflect.Descriptor instead.
+func (*UptimeReport) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *UptimeReport) GetServers() []*ServerUptime {
+	if x != nil {
+		return x.Servers
+	}
+	return nil
+}
+
+// Log streaming
+type LogsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Follow        bool                   `protobuf:"varint,2,opt,name=follow,proto3" json:"follow,omitempty"`                        // Keep streaming new lines
+	TailLines     int32                  `protobuf:"varint,3,opt,name=tail_lines,json=tailLines,proto3" json:"tail_lines,omitempty"` // Lines of history to send first; 0 for all retained, -1 for none
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogsRequest) Reset() {
+	*x = LogsRequest{}
+	mi := &file_mcp_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogsRequest) ProtoMessage() {}
+
+func (x *LogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogsRequest.ProtoReflect.Descriptor instead.
+func (*LogsRequest) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *LogsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *LogsRequest) GetFollow() bool {
+	if x != nil {
+		return x.Follow
+	}
+	return false
+}
+
+func (x *LogsRequest) GetTailLines() int32 {
+	if x != nil {
+		return x.TailLines
+	}
+	return 0
+}
+
+type LogLine struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TimestampMs   int64                  `protobuf:"varint,1,opt,name=timestamp_ms,json=timestampMs,proto3" json:"timestamp_ms,omitempty"` // Unix milliseconds
+	Stream        string                 `protobuf:"bytes,2,opt,name=stream,proto3" json:"stream,omitempty"`                               // "stdout" or "stderr"
+	Text          string                 `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
+	Severity      LogSeverity            `protobuf:"varint,4,opt,name=severity,proto3,enum=mcp.LogSeverity" json:"severity,omitempty"` // Guessed from the level words in the line
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogLine) Reset() {
+	*x = LogLine{}
+	mi := &file_mcp_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogLine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogLine) ProtoMessage() {}
+
+func (x *LogLine) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogLine.ProtoReflect.Descriptor instead.
+func (*LogLine) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *LogLine) GetTimestampMs() int64 {
+	if x != nil {
+		return x.TimestampMs
+	}
+	return 0
+}
+
+func (x *LogLine) GetStream() string {
+	if x != nil {
+		return x.Stream
+	}
+	return ""
+}
+
+func (x *LogLine) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *LogLine) GetSeverity() LogSeverity {
+	if x != nil {
+		return x.Severity
+	}
+	return LogSeverity_LOG_UNKNOWN
+}
+
+// Health check
+type HealthStatus struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Healthy        bool                   `protobuf:"varint,1,opt,name=healthy,proto3" json:"healthy,omitempty"`
+	UptimeSeconds  int64                  `protobuf:"varint,2,opt,name=uptime_seconds,json=uptimeSeconds,proto3" json:"uptime_seconds,omitempty"`
+	RunningServers int32                  `protobuf:"varint,3,opt,name=running_servers,json=runningServers,proto3" json:"running_servers,omitempty"`
+	TotalServers   int32                  `protobuf:"varint,4,opt,name=total_servers,json=totalServers,proto3" json:"total_servers,omitempty"`
+	Maintenance    bool                   `protobuf:"varint,5,opt,name=maintenance,proto3" json:"maintenance,omitempty"` // Daemon-wide maintenance mode
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *HealthStatus) Reset() {
+	*x = HealthStatus{}
+	mi := &file_mcp_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthStatus) ProtoMessage() {}
+
+func (x *HealthStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthStatus.ProtoReflect.Descriptor instead.
+func (*HealthStatus) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *HealthStatus) GetHealthy() bool {
+	if x != nil {
+		return x.Healthy
+	}
+	return false
+}
+
+func (x *HealthStatus) GetUptimeSeconds() int64 {
+	if x != nil {
+		return x.UptimeSeconds
+	}
+	return 0
+}
+
+func (x *HealthStatus) GetRunningServers() int32 {
+	if x != nil {
+		return x.RunningServers
+	}
+	return 0
+}
+
+func (x *HealthStatus) GetTotalServers() int32 {
+	if x != nil {
+		return x.TotalServers
+	}
+	return 0
+}
+
+func (x *HealthStatus) GetMaintenance() bool {
+	if x != nil {
+		return x.Maintenance
+	}
+	return false
+}
+
+// Approvals
+type Approval struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ServerName    string                 `protobuf:"bytes,2,opt,name=server_name,json=serverName,proto3" json:"server_name,omitempty"`
+	Tool          string                 `protobuf:"bytes,3,opt,name=tool,proto3" json:"tool,omitempty"`
+	Arguments     string                 `protobuf:"bytes,4,opt,name=arguments,proto3" json:"arguments,omitempty"`                         // Redacted JSON arguments of the call
+	RequestedAt   int64                  `protobuf:"varint,5,opt,name=requested_at,json=requestedAt,proto3" json:"requested_at,omitempty"` // Unix timestamps
+	Deadline      int64                  `protobuf:"varint,6,opt,name=deadline,proto3" json:"deadline,omitempty"`                          // When the call is rejected if still pending
+	RequestId     string                 `protobuf:"bytes,7,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`        // IDs the agent gave the call and its run
+	RunId         string                 `protobuf:"bytes,8,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Approval) Reset() {
+	*x = Approval{}
+	mi := &file_mcp_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Approval) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Approval) ProtoMessage() {}
+
+func (x *Approval) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Approval.ProtoReflect.Descriptor instead.
+func (*Approval) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *Approval) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Approval) GetServerName() string {
+	if x != nil {
+		return x.ServerName
+	}
+	return ""
+}
+
+func (x *Approval) GetTool() string {
+	if x != nil {
+		return x.Tool
+	}
+	return ""
+}
+
+func (x *Approval) GetArguments() string {
+	if x != nil {
+		return x.Arguments
+	}
+	return ""
+}
+
+func (x *Approval) GetRequestedAt() int64 {
+	if x != nil {
+		return x.RequestedAt
+	}
+	return 0
+}
+
+func (x *Approval) GetDeadline() int64 {
+	if x != nil {
+		return x.Deadline
+	}
+	return 0
+}
+
+func (x *Approval) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *Approval) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+type ApprovalList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Approvals     []*Approval            `protobuf:"bytes,1,rep,name=approvals,proto3" json:"approvals,omitempty"` // Oldest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApprovalList) Reset() {
+	*x = ApprovalList{}
+	mi := &file_mcp_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApprovalList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApprovalList) ProtoMessage() {}
+
+func (x *ApprovalList) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApprovalList.ProtoReflect.Descriptor instead.
+func (*ApprovalList) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ApprovalList) GetApprovals() []*Approval {
+	if x != nil {
+		return x.Approvals
+	}
+	return nil
+}
+
+type ApprovalDecision struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Approved      bool                   `protobuf:"varint,2,opt,name=approved,proto3" json:"approved,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"` // Why the call was rejected
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApprovalDecision) Reset() {
+	*x = ApprovalDecision{}
+	mi := &file_mcp_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApprovalDecision) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApprovalDecision) ProtoMessage() {}
+
+func (x *ApprovalDecision) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApprovalDecision.ProtoReflect.Descriptor instead.
+func (*ApprovalDecision) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ApprovalDecision) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ApprovalDecision) GetApproved() bool {
+	if x != nil {
+		return x.Approved
+	}
+	return false
+}
+
+func (x *ApprovalDecision) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// Session transcripts
+type SessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionRequest) Reset() {
+	*x = SessionRequest{}
+	mi := &file_mcp_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionRequest) ProtoMessage() {}
+
+func (x *SessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionRequest.ProtoReflect.Descriptor instead.
+func (*SessionRequest) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *SessionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type SessionFilter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CorrelationId string                 `protobuf:"bytes,1,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"` // Request or agent run ID of the calls to list, all sessions when empty
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionFilter) Reset() {
+	*x = SessionFilter{}
+	mi := &file_mcp_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionFilter) ProtoMessage() {}
+
+func (x *SessionFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionFilter.ProtoReflect.Descriptor instead.
+func (*SessionFilter) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *SessionFilter) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+type TranscriptEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TimestampMs   int64                  `protobuf:"varint,1,opt,name=timestamp_ms,json=timestampMs,proto3" json:"timestamp_ms,omitempty"`
+	ServerName    string                 `protobuf:"bytes,2,opt,name=server_name,json=serverName,proto3" json:"server_name,omitempty"`
+	Method        string                 `protobuf:"bytes,3,opt,name=method,proto3" json:"method,omitempty"`
+	Tool          string                 `protobuf:"bytes,4,opt,name=tool,proto3" json:"tool,omitempty"`
+	Arguments     string                 `protobuf:"bytes,5,opt,name=arguments,proto3" json:"arguments,omitempty"` // Redacted JSON
+	Result        string                 `protobuf:"bytes,6,opt,name=result,proto3" json:"result,omitempty"`       // Redacted JSON, possibly shortened
+	Error         string                 `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	DurationMs    int64                  `protobuf:"varint,8,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	RequestId     string                 `protobuf:"bytes,9,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"` // IDs the agent gave the call and its run
+	RunId         string                 `protobuf:"bytes,10,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TranscriptEntry) Reset() {
+	*x = TranscriptEntry{}
+	mi := &file_mcp_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TranscriptEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TranscriptEntry) ProtoMessage() {}
+
+func (x *TranscriptEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TranscriptEntry.ProtoReflect.Descriptor instead.
+func (*TranscriptEntry) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *TranscriptEntry) GetTimestampMs() int64 {
+	if x != nil {
+		return x.TimestampMs
+	}
+	return 0
+}
+
+func (x *TranscriptEntry) GetServerName() string {
+	if x != nil {
+		return x.ServerName
+	}
+	return ""
+}
+
+func (x *TranscriptEntry) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *TranscriptEntry) GetTool() string {
+	if x != nil {
+		return x.Tool
+	}
+	return ""
+}
+
+func (x *TranscriptEntry) GetArguments() string {
+	if x != nil {
+		return x.Arguments
+	}
+	return ""
+}
+
+func (x *TranscriptEntry) GetResult() string {
+	if x != nil {
+		return x.Result
+	}
+	return ""
+}
+
+func (x *TranscriptEntry) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *TranscriptEntry) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *TranscriptEntry) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *TranscriptEntry) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+type TranscriptSession struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Client        string                 `protobuf:"bytes,2,opt,name=client,proto3" json:"client,omitempty"`
+	Started       int64                  `protobuf:"varint,3,opt,name=started,proto3" json:"started,omitempty"` // Unix timestamps
+	Updated       int64                  `protobuf:"varint,4,opt,name=updated,proto3" json:"updated,omitempty"`
+	Calls         int32                  `protobuf:"varint,5,opt,name=calls,proto3" json:"calls,omitempty"`
+	Errors        int32                  `protobuf:"varint,6,opt,name=errors,proto3" json:"errors,omitempty"`
+	Entries       []*TranscriptEntry     `protobuf:"bytes,7,rep,name=entries,proto3" json:"entries,omitempty"` // Empty in session lists, unless filtered
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TranscriptSession) Reset() {
+	*x = TranscriptSession{}
+	mi := &file_mcp_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TranscriptSession) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TranscriptSession) ProtoMessage() {}
+
+func (x *TranscriptSession) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TranscriptSession.ProtoReflect.Descriptor instead.
+func (*TranscriptSession) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *TranscriptSession) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *TranscriptSession) GetClient() string {
+	if x != nil {
+		return x.Client
+	}
+	return ""
+}
+
+func (x *TranscriptSession) GetStarted() int64 {
+	if x != nil {
+		return x.Started
+	}
+	return 0
+}
+
+func (x *TranscriptSession) GetUpdated() int64 {
+	if x != nil {
+		return x.Updated
+	}
+	return 0
+}
+
+func (x *TranscriptSession) GetCalls() int32 {
+	if x != nil {
+		return x.Calls
+	}
+	return 0
+}
+
+func (x *TranscriptSession) GetErrors() int32 {
+	if x != nil {
+		return x.Errors
+	}
+	return 0
+}
+
+func (x *TranscriptSession) GetEntries() []*TranscriptEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type SessionList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sessions      []*TranscriptSession   `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"` // Latest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionList) Reset() {
+	*x = SessionList{}
+	mi := &file_mcp_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionList) ProtoMessage() {}
+
+func (x *SessionList) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionList.ProtoReflect.Descriptor instead.
+func (*SessionList) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *SessionList) GetSessions() []*TranscriptSession {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+// Plugins
+type Plugin struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Version       string                 `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	Kinds         []string               `protobuf:"bytes,5,rep,name=kinds,proto3" json:"kinds,omitempty"`                                   // transport, notifier or secrets
+	ConfigSchema  string                 `protobuf:"bytes,6,opt,name=config_schema,json=configSchema,proto3" json:"config_schema,omitempty"` // JSON Schema of the plugin's config
+	Error         string                 `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`                                   // Why the plugin couldn't be described
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Plugin) Reset() {
+	*x = Plugin{}
+	mi := &file_mcp_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Plugin) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Plugin) ProtoMessage() {}
+
+func (x *Plugin) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Plugin.ProtoReflect.Descriptor instead.
+func (*Plugin) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *Plugin) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Plugin) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *Plugin) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *Plugin) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Plugin) GetKinds() []string {
+	if x != nil {
+		return x.Kinds
+	}
+	return nil
+}
+
+func (x *Plugin) GetConfigSchema() string {
+	if x != nil {
+		return x.ConfigSchema
+	}
+	return ""
+}
+
+func (x *Plugin) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type PluginList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Plugins       []*Plugin              `protobuf:"bytes,1,rep,name=plugins,proto3" json:"plugins,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PluginList) Reset() {
+	*x = PluginList{}
+	mi := &file_mcp_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginList) ProtoMessage() {}
+
+func (x *PluginList) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginList.ProtoReflect.Descriptor instead.
+func (*PluginList) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *PluginList) GetPlugins() []*Plugin {
+	if x != nil {
+		return x.Plugins
+	}
+	return nil
+}
+
+// Fleets
+type FleetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RunId         string                 `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	Servers       []string               `protobuf:"bytes,2,rep,name=servers,proto3" json:"servers,omitempty"` // Servers to copy, all when empty
+	Start         bool                   `protobuf:"varint,3,opt,name=start,proto3" json:"start,omitempty"`    // Start the copies once created
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FleetRequest) Reset() {
+	*x = FleetRequest{}
+	mi := &file_mcp_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FleetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FleetRequest) ProtoMessage() {}
+
+func (x *FleetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FleetRequest.ProtoReflect.Descriptor instead.
+func (*FleetRequest) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *FleetRequest) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *FleetRequest) GetServers() []string {
+	if x != nil {
+		return x.Servers
+	}
+	return nil
+}
+
+func (x *FleetRequest) GetStart() bool {
+	if x != nil {
+		return x.Start
+	}
+	return false
+}
+
+type Fleet struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RunId         string                 `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	Dir           string                 `protobuf:"bytes,2,opt,name=dir,proto3" json:"dir,omitempty"`          // State directory of the run
+	Created       int64                  `protobuf:"varint,3,opt,name=created,proto3" json:"created,omitempty"` // Unix timestamp
+	Servers       []*Server              `protobuf:"bytes,4,rep,name=servers,proto3" json:"servers,omitempty"`  // Copies, named server@run
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Fleet) Reset() {
+	*x = Fleet{}
+	mi := &file_mcp_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Fleet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Fleet) ProtoMessage() {}
+
+func (x *Fleet) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Fleet.ProtoReflect.Descriptor instead.
+func (*Fleet) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *Fleet) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *Fleet) GetDir() string {
+	if x != nil {
+		return x.Dir
+	}
+	return ""
+}
+
+func (x *Fleet) GetCreated() int64 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+func (x *Fleet) GetServers() []*Server {
+	if x != nil {
+		return x.Servers
+	}
+	return nil
+}
+
+type FleetList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Fleets        []*Fleet               `protobuf:"bytes,1,rep,name=fleets,proto3" json:"fleets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FleetList) Reset() {
+	*x = FleetList{}
+	mi := &file_mcp_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FleetList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FleetList) ProtoMessage() {}
+
+func (x *FleetList) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FleetList.ProtoReflect.Descriptor instead.
+func (*FleetList) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *FleetList) GetFleets() []*Fleet {
+	if x != nil {
+		return x.Fleets
+	}
+	return nil
+}
+
+// Draining
+type DrainRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	TimeoutMs     int64                  `protobuf:"varint,2,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"` // Longest wait for calls in flight, default when zero
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DrainRequest) Reset() {
+	*x = DrainRequest{}
+	mi := &file_mcp_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DrainRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrainRequest) ProtoMessage() {}
+
+func (x *DrainRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrainRequest.ProtoReflect.Descriptor instead.
+func (*DrainRequest) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *DrainRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DrainRequest) GetTimeoutMs() int64 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
+}
+
+// Browser profiles
+type ProfileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Server        string                 `protobuf:"bytes,1,opt,name=server,proto3" json:"server,omitempty"`
+	Profile       string                 `protobuf:"bytes,2,opt,name=profile,proto3" json:"profile,omitempty"`   // The server's profile when empty
+	Snapshot      string                 `protobuf:"bytes,3,opt,name=snapshot,proto3" json:"snapshot,omitempty"` // Snapshot to restore
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProfileRequest) Reset() {
+	*x = ProfileRequest{}
+	mi := &file_mcp_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProfileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProfileRequest) ProtoMessage() {}
+
+func (x *ProfileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProfileRequest.ProtoReflect.Descriptor instead.
+func (*ProfileRequest) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *ProfileRequest) GetServer() string {
+	if x != nil {
+		return x.Server
+	}
+	return ""
+}
+
+func (x *ProfileRequest) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+func (x *ProfileRequest) GetSnapshot() string {
+	if x != nil {
+		return x.Snapshot
+	}
+	return ""
+}
+
+type ProfileSnapshot struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Size          int64                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`       // Bytes of the archive
+	Created       int64                  `protobuf:"varint,4,opt,name=created,proto3" json:"created,omitempty"` // Unix timestamp
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProfileSnapshot) Reset() {
+	*x = ProfileSnapshot{}
+	mi := &file_mcp_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProfileSnapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProfileSnapshot) ProtoMessage() {}
+
+func (x *ProfileSnapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProfileSnapshot.ProtoReflect.Descriptor instead.
+func (*ProfileSnapshot) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *ProfileSnapshot) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ProfileSnapshot) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ProfileSnapshot) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *ProfileSnapshot) GetCreated() int64 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+type BrowserProfile struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Server        string                 `protobuf:"bytes,1,opt,name=server,proto3" json:"server,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Dir           string                 `protobuf:"bytes,3,opt,name=dir,proto3" json:"dir,omitempty"`
+	Size          int64                  `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`          // Bytes of the files
+	Modified      int64                  `protobuf:"varint,5,opt,name=modified,proto3" json:"modified,omitempty"`  // Unix timestamp of the latest change
+	Attached      bool                   `protobuf:"varint,6,opt,name=attached,proto3" json:"attached,omitempty"`  // The server is configured to use it
+	Snapshots     []*ProfileSnapshot     `protobuf:"bytes,7,rep,name=snapshots,proto3" json:"snapshots,omitempty"` // Oldest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BrowserProfile) Reset() {
+	*x = BrowserProfile{}
+	mi := &file_mcp_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BrowserProfile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BrowserProfile) ProtoMessage() {}
+
+func (x *BrowserProfile) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BrowserProfile.ProtoReflect.Descriptor instead.
+func (*BrowserProfile) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *BrowserProfile) GetServer() string {
+	if x != nil {
+		return x.Server
+	}
+	return ""
+}
+
+func (x *BrowserProfile) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *BrowserProfile) GetDir() string {
+	if x != nil {
+		return x.Dir
+	}
+	return ""
+}
+
+func (x *BrowserProfile) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *BrowserProfile) GetModified() int64 {
+	if x != nil {
+		return x.Modified
+	}
+	return 0
+}
+
+func (x *BrowserProfile) GetAttached() bool {
+	if x != nil {
+		return x.Attached
+	}
+	return false
+}
+
+func (x *BrowserProfile) GetSnapshots() []*ProfileSnapshot {
+	if x != nil {
+		return x.Snapshots
+	}
+	return nil
+}
+
+type ProfileList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Profiles      []*BrowserProfile      `protobuf:"bytes,1,rep,name=profiles,proto3" json:"profiles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProfileList) Reset() {
+	*x = ProfileList{}
+	mi := &file_mcp_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProfileList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProfileList) ProtoMessage() {}
+
+func (x *ProfileList) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProfileList.ProtoReflect.Descriptor instead.
+func (*ProfileList) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *ProfileList) GetProfiles() []*BrowserProfile {
+	if x != nil {
+		return x.Profiles
+	}
+	return nil
+}
+
+// Disk usage
+type DiskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Names         []string               `protobuf:"bytes,1,rep,name=names,proto3" json:"names,omitempty"`                  // All servers when empty
+	DryRun        bool                   `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"` // Report the caches without deleting them
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiskRequest) Reset() {
+	*x = DiskRequest{}
+	mi := &file_mcp_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiskRequest) ProtoMessage() {}
+
+func (x *DiskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiskRequest.ProtoReflect.Descriptor instead.
+func (*DiskRequest) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *DiskRequest) GetNames() []string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+func (x *DiskRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type DiskDir struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`   // cache or data
+	Label         string                 `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"` // What's kept, e.g. npx or profiles
+	Path          string                 `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
+	Size          int64                  `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"` // Bytes of the files
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiskDir) Reset() {
+	*x = DiskDir{}
+	mi := &file_mcp_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiskDir) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiskDir) ProtoMessage() {}
+
+func (x *DiskDir) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiskDir.ProtoReflect.Descriptor instead.
+func (*DiskDir) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *DiskDir) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *DiskDir) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *DiskDir) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *DiskDir) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+type ServerDisk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Server        string                 `protobuf:"bytes,1,opt,name=server,proto3" json:"server,omitempty"`
+	Dirs          []*DiskDir             `protobuf:"bytes,2,rep,name=dirs,proto3" json:"dirs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ServerDisk) Reset() {
+	*x = ServerDisk{}
+	mi := &file_mcp_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServerDisk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerDisk) ProtoMessage() {}
+
+func (x *ServerDisk) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerDisk.ProtoReflect.Descriptor instead.
+func (*ServerDisk) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *ServerDisk) GetServer() string {
+	if x != nil {
+		return x.Server
+	}
+	return ""
+}
+
+func (x *ServerDisk) GetDirs() []*DiskDir {
+	if x != nil {
+		return x.Dirs
+	}
+	return nil
+}
+
+type DiskUsageList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Servers       []*ServerDisk          `protobuf:"bytes,1,rep,name=servers,proto3" json:"servers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiskUsageList) Reset() {
+	*x = DiskUsageList{}
+	mi := &file_mcp_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiskUsageList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiskUsageList) ProtoMessage() {}
+
+func (x *DiskUsageList) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiskUsageList.ProtoReflect.Descriptor instead.
+func (*DiskUsageList) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *DiskUsageList) GetServers() []*ServerDisk {
+	if x != nil {
+		return x.Servers
+	}
+	return nil
+}
+
+// Backups
+type BackupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Server        string                 `protobuf:"bytes,1,opt,name=server,proto3" json:"server,omitempty"`
+	Backup        string                 `protobuf:"bytes,2,opt,name=backup,proto3" json:"backup,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BackupRequest) Reset() {
+	*x = BackupRequest{}
+	mi := &file_mcp_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackupRequest) ProtoMessage() {}
+
+func (x *BackupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackupRequest.ProtoReflect.Descriptor instead.
+func (*BackupRequest) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *BackupRequest) GetServer() string {
+	if x != nil {
+		return x.Server
+	}
+	return ""
+}
+
+func (x *BackupRequest) GetBackup() string {
+	if x != nil {
+		return x.Backup
+	}
+	return ""
+}
+
+type Backup struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Server        string                 `protobuf:"bytes,1,opt,name=server,proto3" json:"server,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Path          string                 `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
+	Size          int64                  `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`       // Bytes of the archive
+	Created       int64                  `protobuf:"varint,5,opt,name=created,proto3" json:"created,omitempty"` // Unix timestamp
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Backup) Reset() {
+	*x = Backup{}
+	mi := &file_mcp_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Backup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Backup) ProtoMessage() {}
+
+func (x *Backup) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Backup.ProtoReflect.Descriptor instead.
+func (*Backup) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *Backup) GetServer() string {
+	if x != nil {
+		return x.Server
+	}
+	return ""
+}
+
+func (x *Backup) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Backup) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *Backup) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *Backup) GetCreated() int64 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+type BackupList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Backups       []*Backup              `protobuf:"bytes,1,rep,name=backups,proto3" json:"backups,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BackupList) Reset() {
+	*x = BackupList{}
+	mi := &file_mcp_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackupList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackupList) ProtoMessage() {}
+
+func (x *BackupList) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackupList.ProtoReflect.Descriptor instead.
+func (*BackupList) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *BackupList) GetBackups() []*Backup {
+	if x != nil {
+		return x.Backups
+	}
+	return nil
+}
+
+// Providers
+type ProviderStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Server        string                 `protobuf:"bytes,1,opt,name=server,proto3" json:"server,omitempty"`
+	Tool          string                 `protobuf:"bytes,2,opt,name=tool,proto3" json:"tool,omitempty"` // Server's tool serving the generic one
+	Running       bool                   `protobuf:"varint,3,opt,name=running,proto3" json:"running,omitempty"`
+	Calls         int64                  `protobuf:"varint,4,opt,name=calls,proto3" json:"calls,omitempty"`
+	Errors        int64                  `protobuf:"varint,5,opt,name=errors,proto3" json:"errors,omitempty"`
+	ErrorRate     float64                `protobuf:"fixed64,6,opt,name=error_rate,json=errorRate,proto3" json:"error_rate,omitempty"` // Moving average share of failed calls
+	LatencyMs     float64                `protobuf:"fixed64,7,opt,name=latency_ms,json=latencyMs,proto3" json:"latency_ms,omitempty"` // Moving average latency
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProviderStats) Reset() {
+	*x = ProviderStats{}
+	mi := &file_mcp_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProviderStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProviderStats) ProtoMessage() {}
+
+func (x *ProviderStats) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProviderStats.ProtoReflect.Descriptor instead.
+func (*ProviderStats) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *ProviderStats) GetServer() string {
+	if x != nil {
+		return x.Server
+	}
+	return ""
+}
+
+func (x *ProviderStats) GetTool() string {
+	if x != nil {
+		return x.Tool
+	}
+	return ""
+}
+
+func (x *ProviderStats) GetRunning() bool {
+	if x != nil {
+		return x.Running
+	}
+	return false
+}
+
+func (x *ProviderStats) GetCalls() int64 {
+	if x != nil {
+		return x.Calls
+	}
+	return 0
+}
+
+func (x *ProviderStats) GetErrors() int64 {
+	if x != nil {
+		return x.Errors
+	}
+	return 0
+}
+
+func (x *ProviderStats) GetErrorRate() float64 {
+	if x != nil {
+		return x.ErrorRate
+	}
+	return 0
+}
+
+func (x *ProviderStats) GetLatencyMs() float64 {
+	if x != nil {
+		return x.LatencyMs
+	}
+	return 0
+}
+
+type ProviderGroup struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tool          string                 `protobuf:"bytes,1,opt,name=tool,proto3" json:"tool,omitempty"`
+	Selected      string                 `protobuf:"bytes,2,opt,name=selected,proto3" json:"selected,omitempty"` // Provider taking the next calls
+	Pinned        string                 `protobuf:"bytes,3,opt,name=pinned,proto3" json:"pinned,omitempty"`
+	Providers     []*ProviderStats       `protobuf:"bytes,4,rep,name=providers,proto3" json:"providers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProviderGroup) Reset() {
+	*x = ProviderGroup{}
+	mi := &file_mcp_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProviderGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProviderGroup) ProtoMessage() {}
+
+func (x *ProviderGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProviderGroup.ProtoReflect.Descriptor instead.
+func (*ProviderGroup) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *ProviderGroup) GetTool() string {
+	if x != nil {
+		return x.Tool
+	}
+	return ""
+}
+
+func (x *ProviderGroup) GetSelected() string {
+	if x != nil {
+		return x.Selected
+	}
+	return ""
+}
+
+func (x *ProviderGroup) GetPinned() string {
+	if x != nil {
+		return x.Pinned
+	}
+	return ""
+}
+
+func (x *ProviderGroup) GetProviders() []*ProviderStats {
+	if x != nil {
+		return x.Providers
+	}
+	return nil
+}
+
+type ProviderList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Groups        []*ProviderGroup       `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProviderList) Reset() {
+	*x = ProviderList{}
+	mi := &file_mcp_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProviderList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProviderList) ProtoMessage() {}
+
+func (x *ProviderList) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProviderList.ProtoReflect.Descriptor instead.
+func (*ProviderList) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *ProviderList) GetGroups() []*ProviderGroup {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+type PinRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tool          string                 `protobuf:"bytes,1,opt,name=tool,proto3" json:"tool,omitempty"`
+	Server        string                 `protobuf:"bytes,2,opt,name=server,proto3" json:"server,omitempty"` // Selects automatically again when empty
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PinRequest) Reset() {
+	*x = PinRequest{}
+	mi := &file_mcp_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PinRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PinRequest) ProtoMessage() {}
+
+func (x *PinRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PinRequest.ProtoReflect.Descriptor instead.
+func (*PinRequest) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *PinRequest) GetTool() string {
+	if x != nil {
+		return x.Tool
+	}
+	return ""
+}
+
+func (x *PinRequest) GetServer() string {
+	if x != nil {
+		return x.Server
+	}
+	return ""
+}
+
+// Notifications
+type NotificationPreference struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Server        string                 `protobuf:"bytes,1,opt,name=server,proto3" json:"server,omitempty"`
+	Level         string                 `protobuf:"bytes,2,opt,name=level,proto3" json:"level,omitempty"`    // mute, crash, tools or all
+	Custom        bool                   `protobuf:"varint,3,opt,name=custom,proto3" json:"custom,omitempty"` // Chosen for the server rather than the default
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NotificationPreference) Reset() {
+	*x = NotificationPreference{}
+	mi := &file_mcp_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotificationPreference) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationPreference) ProtoMessage() {}
+
+func (x *NotificationPreference) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotificationPreference.ProtoReflect.Descriptor instead.
+func (*NotificationPreference) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *NotificationPreference) GetServer() string {
+	if x != nil {
+		return x.Server
+	}
+	return ""
+}
+
+func (x *NotificationPreference) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *NotificationPreference) GetCustom() bool {
+	if x != nil {
+		return x.Custom
+	}
+	return false
+}
+
+type NotificationList struct {
+	state         protoimpl.MessageState    `protogen:"open.v1"`
+	DefaultLevel  string                    `protobuf:"bytes,1,opt,name=default_level,json=defaultLevel,proto3" json:"default_level,omitempty"`
+	Servers       []*NotificationPreference `protobuf:"bytes,2,rep,name=servers,proto3" json:"servers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NotificationList) Reset() {
+	*x = NotificationList{}
+	mi := &file_mcp_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotificationList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationList) ProtoMessage() {}
+
+func (x *NotificationList) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotificationList.ProtoReflect.Descriptor instead.
+func (*NotificationList) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *NotificationList) GetDefaultLevel() string {
+	if x != nil {
+		return x.DefaultLevel
+	}
+	return ""
+}
+
+func (x *NotificationList) GetServers() []*NotificationPreference {
+	if x != nil {
+		return x.Servers
+	}
+	return nil
+}
+
+type NotificationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Server        string                 `protobuf:"bytes,1,opt,name=server,proto3" json:"server,omitempty"`
+	Level         string                 `protobuf:"bytes,2,opt,name=level,proto3" json:"level,omitempty"` // Resets the server to the default level when empty
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NotificationRequest) Reset() {
+	*x = NotificationRequest{}
+	mi := &file_mcp_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotificationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationRequest) ProtoMessage() {}
+
+func (x *NotificationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotificationRequest.ProtoReflect.Descriptor instead.
+func (*NotificationRequest) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *NotificationRequest) GetServer() string {
+	if x != nil {
+		return x.Server
+	}
+	return ""
+}
+
+func (x *NotificationRequest) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+// Effective runtime of a server
+type RuntimeVar struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Source        string                 `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"` // config, secret, proxy or profile; values are never sent
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RuntimeVar) Reset() {
+	*x = RuntimeVar{}
+	mi := &file_mcp_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RuntimeVar) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RuntimeVar) ProtoMessage() {}
+
+func (x *RuntimeVar) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RuntimeVar.ProtoReflect.Descriptor instead.
+func (*RuntimeVar) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *RuntimeVar) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RuntimeVar) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+type RuntimeLimit struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RuntimeLimit) Reset() {
+	*x = RuntimeLimit{}
+	mi := &file_mcp_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RuntimeLimit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RuntimeLimit) ProtoMessage() {}
+
+func (x *RuntimeLimit) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RuntimeLimit.ProtoReflect.Descriptor instead.
+func (*RuntimeLimit) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *RuntimeLimit) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RuntimeLimit) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type ServerRuntime struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Server        string                 `protobuf:"bytes,1,opt,name=server,proto3" json:"server,omitempty"`
+	Command       string                 `protobuf:"bytes,2,opt,name=command,proto3" json:"command,omitempty"`  // As spawned, after profiles, transports, resolvers and priorities
+	Running       bool                   `protobuf:"varint,3,opt,name=running,proto3" json:"running,omitempty"` // The command is the one the running server was started with
+	Env           []*RuntimeVar          `protobuf:"bytes,4,rep,name=env,proto3" json:"env,omitempty"`
+	Path          string                 `protobuf:"bytes,5,opt,name=path,proto3" json:"path,omitempty"` // PATH the command is looked up in
+	Cwd           string                 `protobuf:"bytes,6,opt,name=cwd,proto3" json:"cwd,omitempty"`
+	Transport     string                 `protobuf:"bytes,7,opt,name=transport,proto3" json:"transport,omitempty"` // Transport plugin, empty when spawned directly
+	Limits        []*RuntimeLimit        `protobuf:"bytes,8,rep,name=limits,proto3" json:"limits,omitempty"`
+	Error         string                 `protobuf:"bytes,9,opt,name=error,proto3" json:"error,omitempty"` // Why the server can't start as configured
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ServerRuntime) Reset() {
+	*x = ServerRuntime{}
+	mi := &file_mcp_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServerRuntime) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerRuntime) ProtoMessage() {}
+
+func (x *ServerRuntime) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerRuntime.ProtoReflect.Descriptor instead.
+func (*ServerRuntime) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *ServerRuntime) GetServer() string {
+	if x != nil {
+		return x.Server
+	}
+	return ""
+}
+
+func (x *ServerRuntime) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *ServerRuntime) GetRunning() bool {
+	if x != nil {
+		return x.Running
+	}
+	return false
+}
+
+func (x *ServerRuntime) GetEnv() []*RuntimeVar {
+	if x != nil {
+		return x.Env
+	}
+	return nil
+}
+
+func (x *ServerRuntime) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ServerRuntime) GetCwd() string {
+	if x != nil {
+		return x.Cwd
+	}
+	return ""
+}
+
+func (x *ServerRuntime) GetTransport() string {
+	if x != nil {
+		return x.Transport
+	}
+	return ""
+}
+
+func (x *ServerRuntime) GetLimits() []*RuntimeLimit {
+	if x != nil {
+		return x.Limits
+	}
+	return nil
+}
+
+func (x *ServerRuntime) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type CloneRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServerName    string                 `protobuf:"bytes,1,opt,name=server_name,json=serverName,proto3" json:"server_name,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`       // Name of the copy, "<server>-2" or the next free number if empty
+	Replace       []string               `protobuf:"bytes,3,rep,name=replace,proto3" json:"replace,omitempty"` // Substitutions "old=new" in the command of the copy
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloneRequest) Reset() {
+	*x = CloneRequest{}
+	mi := &file_mcp_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloneRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloneRequest) ProtoMessage() {}
+
+func (x *CloneRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloneRequest.ProtoReflect.Descriptor instead.
+func (*CloneRequest) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *CloneRequest) GetServerName() string {
+	if x != nil {
+		return x.ServerName
+	}
+	return ""
+}
+
+func (x *CloneRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CloneRequest) GetReplace() []string {
+	if x != nil {
+		return x.Replace
+	}
+	return nil
+}
+
+type CloneResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Port          int32                  `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloneResponse) Reset() {
+	*x = CloneResponse{}
+	mi := &file_mcp_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloneResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloneResponse) ProtoMessage() {}
+
+func (x *CloneResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloneResponse.ProtoReflect.Descriptor instead.
+func (*CloneResponse) Descriptor() ([]byte, []int) {
+	return file_mcp_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *CloneResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CloneResponse) GetPort() int32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+var File_mcp_proto protoreflect.FileDescriptor
+
+const file_mcp_proto_rawDesc = "" +
+	"\n" +
+	"\tmcp.proto\x12\x03mcp\"\a\n" +
+	"\x05Empty\"#\n" +
+	"\rServerRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"\x1e\n" +
+	"\n" +
+	"TagRequest\x12\x10\n" +
+	"\x03tag\x18\x01 \x01(\tR\x03tag\"D\n" +
+	"\x0eStatusResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\"\n" +
+	"\fPathResponse\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\"B\n" +
+	"\x12MaintenanceRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aenabled\x18\x02 \x01(\bR\aenabled\"?\n" +
+	"\x0fRegisterRequest\x12\x12\n" +
+	"\x04host\x18\x01 \x01(\tR\x04host\x12\x18\n" +
+	"\aaddress\x18\x02 \x01(\tR\aaddress\"\xe3\x05\n" +
+	"\x06Server\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\acommand\x18\x02 \x01(\tR\acommand\x12\x12\n" +
+	"\x04port\x18\x03 \x01(\x05R\x04port\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12)\n" +
+	"\x06status\x18\x05 \x01(\x0e2\x11.mcp.ServerStatusR\x06status\x12\x10\n" +
+	"\x03pid\x18\x06 \x01(\x05R\x03pid\x12\x1d\n" +
+	"\n" +
+	"tool_count\x18\a \x01(\x05R\ttoolCount\x12\x1f\n" +
+	"\x05tools\x18\b \x03(\v2\t.mcp.ToolR\x05tools\x12!\n" +
+	"\flast_updated\x18\t \x01(\x03R\vlastUpdated\x12\x16\n" +
+	"\x06health\x18\n" +
+	" \x01(\tR\x06health\x12%\n" +
+	"\x0ehealth_message\x18\v \x01(\tR\rhealthMessage\x12\x12\n" +
+	"\x04host\x18\f \x01(\tR\x04host\x12%\n" +
+	"\x0erestart_policy\x18\r \x01(\tR\rrestartPolicy\x12!\n" +
+	"\fmax_restarts\x18\x0e \x01(\x05R\vmaxRestarts\x12\x1a\n" +
+	"\brestarts\x18\x0f \x01(\x05R\brestarts\x12 \n" +
+	"\vmaintenance\x18\x10 \x01(\bR\vmaintenance\x12\x1a\n" +
+	"\brequests\x18\x11 \x01(\x03R\brequests\x12\x1d\n" +
+	"\n" +
+	"started_at\x18\x12 \x01(\x03R\tstartedAt\x12\x1c\n" +
+	"\tautostart\x18\x13 \x01(\bR\tautostart\x12\x15\n" +
+	"\x06run_id\x18\x14 \x01(\tR\x05runId\x12\x14\n" +
+	"\x05group\x18\x15 \x01(\tR\x05group\x12\x1a\n" +
+	"\bdraining\x18\x16 \x01(\bR\bdraining\x12\x1b\n" +
+	"\tin_flight\x18\x17 \x01(\x03R\binFlight\x12'\n" +
+	"\x0fbrowser_profile\x18\x18 \x01(\tR\x0ebrowserProfile\x12\x12\n" +
+	"\x04tags\x18\x19 \x03(\tR\x04tags\"I\n" +
+	"\n" +
+	"ServerList\x12%\n" +
+	"\aservers\x18\x01 \x03(\v2\v.mcp.ServerR\aservers\x12\x14\n" +
+	"\x05order\x18\x02 \x03(\tR\x05order\"u\n" +
+	"\x04Tool\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12!\n" +
+	"\finput_schema\x18\x04 \x01(\tR\vinputSchema\"+\n" +
+	"\bToolList\x12\x1f\n" +
+	"\x05tools\x18\x01 \x03(\v2\t.mcp.ToolR\x05tools\"\xcf\x01\n" +
+	"\x06Config\x12\x1f\n" +
 	"\vconfig_path\x18\x01 \x01(\tR\n" +
 	"configPath\x122\n" +
 	"\aservers\x18\x02 \x03(\v2\x18.mcp.Config.ServersEntryR\aservers\x12!\n" +
@@ -1145,17 +4786,34 @@ const file_mcp_proto_rawDesc = "" +
 	"\fServerConfig\x12\x18\n" +
 	"\acommand\x18\x01 \x01(\tR\acommand\x12\x12\n" +
 	"\x04port\x18\x02 \x01(\x05R\x04port\x12 \n" +
-	"\vdescription\x18\x03 \x01(\tR\vdescription\"C\n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\"_\n" +
+	"\x10ConfigValidation\x12\x1a\n" +
+	"\bproblems\x18\x01 \x03(\tR\bproblems\x12/\n" +
+	"\tconflicts\x18\x02 \x03(\v2\x11.mcp.ToolConflictR\tconflicts\"\xca\x01\n" +
+	"\fToolConflict\x12\x12\n" +
+	"\x04tool\x18\x01 \x01(\tR\x04tool\x12\x18\n" +
+	"\aservers\x18\x02 \x03(\tR\aservers\x128\n" +
+	"\arenamed\x18\x03 \x03(\v2\x1e.mcp.ToolConflict.RenamedEntryR\arenamed\x12\x16\n" +
+	"\x06hidden\x18\x04 \x03(\tR\x06hidden\x1a:\n" +
+	"\fRenamedEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"C\n" +
 	"\x10SubscribeRequest\x12/\n" +
 	"\vevent_types\x18\x01 \x03(\x0e2\x0e.mcp.EventTypeR\n" +
-	"eventTypes\"\x8b\x02\n" +
+	"eventTypes\"\xab\x04\n" +
 	"\x05Event\x12\"\n" +
 	"\x04type\x18\x01 \x01(\x0e2\x0e.mcp.EventTypeR\x04type\x12\x1c\n" +
 	"\ttimestamp\x18\x02 \x01(\x03R\ttimestamp\x12=\n" +
 	"\rserver_status\x18\x03 \x01(\v2\x16.mcp.ServerStatusEventH\x00R\fserverStatus\x127\n" +
 	"\vtool_update\x18\x04 \x01(\v2\x14.mcp.ToolUpdateEventH\x00R\n" +
 	"toolUpdate\x12=\n" +
-	"\rconfig_change\x18\x05 \x01(\v2\x16.mcp.ConfigChangeEventH\x00R\fconfigChangeB\t\n" +
+	"\rconfig_change\x18\x05 \x01(\v2\x16.mcp.ConfigChangeEventH\x00R\fconfigChange\x120\n" +
+	"\bfailover\x18\x06 \x01(\v2\x12.mcp.FailoverEventH\x00R\bfailover\x123\n" +
+	"\theartbeat\x18\a \x01(\v2\x13.mcp.HeartbeatEventH\x00R\theartbeat\x12C\n" +
+	"\x0fcircuit_breaker\x18\b \x01(\v2\x18.mcp.CircuitBreakerEventH\x00R\x0ecircuitBreaker\x120\n" +
+	"\bapproval\x18\t \x01(\v2\x12.mcp.ApprovalEventH\x00R\bapproval\x12@\n" +
+	"\x0eport_migration\x18\n" +
+	" \x01(\v2\x17.mcp.PortMigrationEventH\x00R\rportMigrationB\t\n" +
 	"\apayload\"\x98\x01\n" +
 	"\x11ServerStatusEvent\x12\x1f\n" +
 	"\vserver_name\x18\x01 \x01(\tR\n" +
@@ -1169,27 +4827,272 @@ const file_mcp_proto_rawDesc = "" +
 	"serverName\x12\x1d\n" +
 	"\n" +
 	"tool_count\x18\x02 \x01(\x05R\ttoolCount\x12\x1f\n" +
-	"\x05tools\x18\x03 \x03(\v2\t.mcp.ToolR\x05tools\"\x8c\x01\n" +
+	"\x05tools\x18\x03 \x03(\v2\t.mcp.ToolR\x05tools\"\xb5\x01\n" +
 	"\x11ConfigChangeEvent\x12#\n" +
 	"\rservers_added\x18\x01 \x03(\tR\fserversAdded\x12'\n" +
 	"\x0fservers_removed\x18\x02 \x03(\tR\x0eserversRemoved\x12)\n" +
-	"\x10servers_modified\x18\x03 \x03(\tR\x0fserversModified\"\x9d\x01\n" +
+	"\x10servers_modified\x18\x03 \x03(\tR\x0fserversModified\x12'\n" +
+	"\x0fservers_updated\x18\x04 \x03(\tR\x0eserversUpdated\"~\n" +
+	"\rFailoverEvent\x12\x1f\n" +
+	"\vserver_name\x18\x01 \x01(\tR\n" +
+	"serverName\x12\x1b\n" +
+	"\tfrom_host\x18\x02 \x01(\tR\bfromHost\x12\x17\n" +
+	"\ato_host\x18\x03 \x01(\tR\x06toHost\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\"d\n" +
+	"\x13CircuitBreakerEvent\x12\x1f\n" +
+	"\vserver_name\x18\x01 \x01(\tR\n" +
+	"serverName\x12\x14\n" +
+	"\x05state\x18\x02 \x01(\tR\x05state\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"h\n" +
+	"\rApprovalEvent\x12)\n" +
+	"\bapproval\x18\x01 \x01(\v2\r.mcp.ApprovalR\bapproval\x12\x14\n" +
+	"\x05state\x18\x02 \x01(\tR\x05state\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"\x99\x01\n" +
+	"\x12PortMigrationEvent\x12\x1f\n" +
+	"\vserver_name\x18\x01 \x01(\tR\n" +
+	"serverName\x12\x19\n" +
+	"\bold_port\x18\x02 \x01(\x05R\aoldPort\x12\x19\n" +
+	"\bnew_port\x18\x03 \x01(\x05R\anewPort\x12\x14\n" +
+	"\x05state\x18\x04 \x01(\tR\x05state\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\"1\n" +
+	"\x0eHeartbeatEvent\x12\x1f\n" +
+	"\vinterval_ms\x18\x01 \x01(\x03R\n" +
+	"intervalMs\"\x8f\x01\n" +
+	"\n" +
+	"EventQuery\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\x03R\x02to\x12/\n" +
+	"\vevent_types\x18\x03 \x03(\x0e2\x0e.mcp.EventTypeR\n" +
+	"eventTypes\x12\x16\n" +
+	"\x06server\x18\x04 \x01(\tR\x06server\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limit\"M\n" +
+	"\tEventList\x12\"\n" +
+	"\x06events\x18\x01 \x03(\v2\n" +
+	".mcp.EventR\x06events\x12\x1c\n" +
+	"\ttruncated\x18\x02 \x01(\bR\ttruncated\"#\n" +
+	"\rUptimeRequest\x12\x12\n" +
+	"\x04days\x18\x01 \x01(\x05R\x04days\"\xa5\x01\n" +
+	"\fServerUptime\x12\x16\n" +
+	"\x06server\x18\x01 \x01(\tR\x06server\x12\x1d\n" +
+	"\n" +
+	"up_seconds\x18\x02 \x01(\x01R\tupSeconds\x12!\n" +
+	"\fdown_seconds\x18\x03 \x01(\x01R\vdownSeconds\x12\x18\n" +
+	"\aoutages\x18\x04 \x01(\x05R\aoutages\x12!\n" +
+	"\fmttr_seconds\x18\x05 \x01(\x01R\vmttrSeconds\";\n" +
+	"\fUptimeReport\x12+\n" +
+	"\aservers\x18\x01 \x03(\v2\x11.mcp.ServerUptimeR\aservers\"X\n" +
+	"\vLogsRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06follow\x18\x02 \x01(\bR\x06follow\x12\x1d\n" +
+	"\n" +
+	"tail_lines\x18\x03 \x01(\x05R\ttailLines\"\x86\x01\n" +
+	"\aLogLine\x12!\n" +
+	"\ftimestamp_ms\x18\x01 \x01(\x03R\vtimestampMs\x12\x16\n" +
+	"\x06stream\x18\x02 \x01(\tR\x06stream\x12\x12\n" +
+	"\x04text\x18\x03 \x01(\tR\x04text\x12,\n" +
+	"\bseverity\x18\x04 \x01(\x0e2\x10.mcp.LogSeverityR\bseverity\"\xbf\x01\n" +
 	"\fHealthStatus\x12\x18\n" +
 	"\ahealthy\x18\x01 \x01(\bR\ahealthy\x12%\n" +
 	"\x0euptime_seconds\x18\x02 \x01(\x03R\ruptimeSeconds\x12'\n" +
 	"\x0frunning_servers\x18\x03 \x01(\x05R\x0erunningServers\x12#\n" +
-	"\rtotal_servers\x18\x04 \x01(\x05R\ftotalServers*O\n" +
+	"\rtotal_servers\x18\x04 \x01(\x05R\ftotalServers\x12 \n" +
+	"\vmaintenance\x18\x05 \x01(\bR\vmaintenance\"\xe2\x01\n" +
+	"\bApproval\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1f\n" +
+	"\vserver_name\x18\x02 \x01(\tR\n" +
+	"serverName\x12\x12\n" +
+	"\x04tool\x18\x03 \x01(\tR\x04tool\x12\x1c\n" +
+	"\targuments\x18\x04 \x01(\tR\targuments\x12!\n" +
+	"\frequested_at\x18\x05 \x01(\x03R\vrequestedAt\x12\x1a\n" +
+	"\bdeadline\x18\x06 \x01(\x03R\bdeadline\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\a \x01(\tR\trequestId\x12\x15\n" +
+	"\x06run_id\x18\b \x01(\tR\x05runId\";\n" +
+	"\fApprovalList\x12+\n" +
+	"\tapprovals\x18\x01 \x03(\v2\r.mcp.ApprovalR\tapprovals\"V\n" +
+	"\x10ApprovalDecision\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\bapproved\x18\x02 \x01(\bR\bapproved\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\" \n" +
+	"\x0eSessionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"6\n" +
+	"\rSessionFilter\x12%\n" +
+	"\x0ecorrelation_id\x18\x01 \x01(\tR\rcorrelationId\"\xa4\x02\n" +
+	"\x0fTranscriptEntry\x12!\n" +
+	"\ftimestamp_ms\x18\x01 \x01(\x03R\vtimestampMs\x12\x1f\n" +
+	"\vserver_name\x18\x02 \x01(\tR\n" +
+	"serverName\x12\x16\n" +
+	"\x06method\x18\x03 \x01(\tR\x06method\x12\x12\n" +
+	"\x04tool\x18\x04 \x01(\tR\x04tool\x12\x1c\n" +
+	"\targuments\x18\x05 \x01(\tR\targuments\x12\x16\n" +
+	"\x06result\x18\x06 \x01(\tR\x06result\x12\x14\n" +
+	"\x05error\x18\a \x01(\tR\x05error\x12\x1f\n" +
+	"\vduration_ms\x18\b \x01(\x03R\n" +
+	"durationMs\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\t \x01(\tR\trequestId\x12\x15\n" +
+	"\x06run_id\x18\n" +
+	" \x01(\tR\x05runId\"\xcd\x01\n" +
+	"\x11TranscriptSession\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06client\x18\x02 \x01(\tR\x06client\x12\x18\n" +
+	"\astarted\x18\x03 \x01(\x03R\astarted\x12\x18\n" +
+	"\aupdated\x18\x04 \x01(\x03R\aupdated\x12\x14\n" +
+	"\x05calls\x18\x05 \x01(\x05R\x05calls\x12\x16\n" +
+	"\x06errors\x18\x06 \x01(\x05R\x06errors\x12.\n" +
+	"\aentries\x18\a \x03(\v2\x14.mcp.TranscriptEntryR\aentries\"A\n" +
+	"\vSessionList\x122\n" +
+	"\bsessions\x18\x01 \x03(\v2\x16.mcp.TranscriptSessionR\bsessions\"\xbd\x01\n" +
+	"\x06Plugin\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\tR\aversion\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12\x14\n" +
+	"\x05kinds\x18\x05 \x03(\tR\x05kinds\x12#\n" +
+	"\rconfig_schema\x18\x06 \x01(\tR\fconfigSchema\x12\x14\n" +
+	"\x05error\x18\a \x01(\tR\x05error\"3\n" +
+	"\n" +
+	"PluginList\x12%\n" +
+	"\aplugins\x18\x01 \x03(\v2\v.mcp.PluginR\aplugins\"U\n" +
+	"\fFleetRequest\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\tR\x05runId\x12\x18\n" +
+	"\aservers\x18\x02 \x03(\tR\aservers\x12\x14\n" +
+	"\x05start\x18\x03 \x01(\bR\x05start\"q\n" +
+	"\x05Fleet\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\tR\x05runId\x12\x10\n" +
+	"\x03dir\x18\x02 \x01(\tR\x03dir\x12\x18\n" +
+	"\acreated\x18\x03 \x01(\x03R\acreated\x12%\n" +
+	"\aservers\x18\x04 \x03(\v2\v.mcp.ServerR\aservers\"/\n" +
+	"\tFleetList\x12\"\n" +
+	"\x06fleets\x18\x01 \x03(\v2\n" +
+	".mcp.FleetR\x06fleets\"A\n" +
+	"\fDrainRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"timeout_ms\x18\x02 \x01(\x03R\ttimeoutMs\"^\n" +
+	"\x0eProfileRequest\x12\x16\n" +
+	"\x06server\x18\x01 \x01(\tR\x06server\x12\x18\n" +
+	"\aprofile\x18\x02 \x01(\tR\aprofile\x12\x1a\n" +
+	"\bsnapshot\x18\x03 \x01(\tR\bsnapshot\"g\n" +
+	"\x0fProfileSnapshot\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x03R\x04size\x12\x18\n" +
+	"\acreated\x18\x04 \x01(\x03R\acreated\"\xce\x01\n" +
+	"\x0eBrowserProfile\x12\x16\n" +
+	"\x06server\x18\x01 \x01(\tR\x06server\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x10\n" +
+	"\x03dir\x18\x03 \x01(\tR\x03dir\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x03R\x04size\x12\x1a\n" +
+	"\bmodified\x18\x05 \x01(\x03R\bmodified\x12\x1a\n" +
+	"\battached\x18\x06 \x01(\bR\battached\x122\n" +
+	"\tsnapshots\x18\a \x03(\v2\x14.mcp.ProfileSnapshotR\tsnapshots\">\n" +
+	"\vProfileList\x12/\n" +
+	"\bprofiles\x18\x01 \x03(\v2\x13.mcp.BrowserProfileR\bprofiles\"<\n" +
+	"\vDiskRequest\x12\x14\n" +
+	"\x05names\x18\x01 \x03(\tR\x05names\x12\x17\n" +
+	"\adry_run\x18\x02 \x01(\bR\x06dryRun\"[\n" +
+	"\aDiskDir\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x14\n" +
+	"\x05label\x18\x02 \x01(\tR\x05label\x12\x12\n" +
+	"\x04path\x18\x03 \x01(\tR\x04path\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x03R\x04size\"F\n" +
+	"\n" +
+	"ServerDisk\x12\x16\n" +
+	"\x06server\x18\x01 \x01(\tR\x06server\x12 \n" +
+	"\x04dirs\x18\x02 \x03(\v2\f.mcp.DiskDirR\x04dirs\":\n" +
+	"\rDiskUsageList\x12)\n" +
+	"\aservers\x18\x01 \x03(\v2\x0f.mcp.ServerDiskR\aservers\"?\n" +
+	"\rBackupRequest\x12\x16\n" +
+	"\x06server\x18\x01 \x01(\tR\x06server\x12\x16\n" +
+	"\x06backup\x18\x02 \x01(\tR\x06backup\"v\n" +
+	"\x06Backup\x12\x16\n" +
+	"\x06server\x18\x01 \x01(\tR\x06server\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04path\x18\x03 \x01(\tR\x04path\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x03R\x04size\x12\x18\n" +
+	"\acreated\x18\x05 \x01(\x03R\acreated\"3\n" +
+	"\n" +
+	"BackupList\x12%\n" +
+	"\abackups\x18\x01 \x03(\v2\v.mcp.BackupR\abackups\"\xc1\x01\n" +
+	"\rProviderStats\x12\x16\n" +
+	"\x06server\x18\x01 \x01(\tR\x06server\x12\x12\n" +
+	"\x04tool\x18\x02 \x01(\tR\x04tool\x12\x18\n" +
+	"\arunning\x18\x03 \x01(\bR\arunning\x12\x14\n" +
+	"\x05calls\x18\x04 \x01(\x03R\x05calls\x12\x16\n" +
+	"\x06errors\x18\x05 \x01(\x03R\x06errors\x12\x1d\n" +
+	"\n" +
+	"error_rate\x18\x06 \x01(\x01R\terrorRate\x12\x1d\n" +
+	"\n" +
+	"latency_ms\x18\a \x01(\x01R\tlatencyMs\"\x89\x01\n" +
+	"\rProviderGroup\x12\x12\n" +
+	"\x04tool\x18\x01 \x01(\tR\x04tool\x12\x1a\n" +
+	"\bselected\x18\x02 \x01(\tR\bselected\x12\x16\n" +
+	"\x06pinned\x18\x03 \x01(\tR\x06pinned\x120\n" +
+	"\tproviders\x18\x04 \x03(\v2\x12.mcp.ProviderStatsR\tproviders\":\n" +
+	"\fProviderList\x12*\n" +
+	"\x06groups\x18\x01 \x03(\v2\x12.mcp.ProviderGroupR\x06groups\"8\n" +
+	"\n" +
+	"PinRequest\x12\x12\n" +
+	"\x04tool\x18\x01 \x01(\tR\x04tool\x12\x16\n" +
+	"\x06server\x18\x02 \x01(\tR\x06server\"^\n" +
+	"\x16NotificationPreference\x12\x16\n" +
+	"\x06server\x18\x01 \x01(\tR\x06server\x12\x14\n" +
+	"\x05level\x18\x02 \x01(\tR\x05level\x12\x16\n" +
+	"\x06custom\x18\x03 \x01(\bR\x06custom\"n\n" +
+	"\x10NotificationList\x12#\n" +
+	"\rdefault_level\x18\x01 \x01(\tR\fdefaultLevel\x125\n" +
+	"\aservers\x18\x02 \x03(\v2\x1b.mcp.NotificationPreferenceR\aservers\"C\n" +
+	"\x13NotificationRequest\x12\x16\n" +
+	"\x06server\x18\x01 \x01(\tR\x06server\x12\x14\n" +
+	"\x05level\x18\x02 \x01(\tR\x05level\"8\n" +
+	"\n" +
+	"RuntimeVar\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\"8\n" +
+	"\fRuntimeLimit\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\"\x83\x02\n" +
+	"\rServerRuntime\x12\x16\n" +
+	"\x06server\x18\x01 \x01(\tR\x06server\x12\x18\n" +
+	"\acommand\x18\x02 \x01(\tR\acommand\x12\x18\n" +
+	"\arunning\x18\x03 \x01(\bR\arunning\x12!\n" +
+	"\x03env\x18\x04 \x03(\v2\x0f.mcp.RuntimeVarR\x03env\x12\x12\n" +
+	"\x04path\x18\x05 \x01(\tR\x04path\x12\x10\n" +
+	"\x03cwd\x18\x06 \x01(\tR\x03cwd\x12\x1c\n" +
+	"\ttransport\x18\a \x01(\tR\ttransport\x12)\n" +
+	"\x06limits\x18\b \x03(\v2\x11.mcp.RuntimeLimitR\x06limits\x12\x14\n" +
+	"\x05error\x18\t \x01(\tR\x05error\"]\n" +
+	"\fCloneRequest\x12\x1f\n" +
+	"\vserver_name\x18\x01 \x01(\tR\n" +
+	"serverName\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
+	"\areplace\x18\x03 \x03(\tR\areplace\"7\n" +
+	"\rCloneResponse\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04port\x18\x02 \x01(\x05R\x04port*O\n" +
 	"\fServerStatus\x12\v\n" +
 	"\aSTOPPED\x10\x00\x12\f\n" +
 	"\bSTARTING\x10\x01\x12\v\n" +
 	"\aRUNNING\x10\x02\x12\f\n" +
 	"\bSTOPPING\x10\x03\x12\t\n" +
-	"\x05ERROR\x10\x04*K\n" +
+	"\x05ERROR\x10\x04*\x9f\x01\n" +
 	"\tEventType\x12\a\n" +
 	"\x03ALL\x10\x00\x12\x11\n" +
 	"\rSERVER_STATUS\x10\x01\x12\x0f\n" +
 	"\vTOOL_UPDATE\x10\x02\x12\x11\n" +
-	"\rCONFIG_CHANGE\x10\x032\xd6\x03\n" +
+	"\rCONFIG_CHANGE\x10\x03\x12\f\n" +
+	"\bFAILOVER\x10\x04\x12\r\n" +
+	"\tHEARTBEAT\x10\x05\x12\x13\n" +
+	"\x0fCIRCUIT_BREAKER\x10\x06\x12\f\n" +
+	"\bAPPROVAL\x10\a\x12\x12\n" +
+	"\x0ePORT_MIGRATION\x10\b*[\n" +
+	"\vLogSeverity\x12\x0f\n" +
+	"\vLOG_UNKNOWN\x10\x00\x12\r\n" +
+	"\tLOG_DEBUG\x10\x01\x12\f\n" +
+	"\bLOG_INFO\x10\x02\x12\x0f\n" +
+	"\vLOG_WARNING\x10\x03\x12\r\n" +
+	"\tLOG_ERROR\x10\x042\x81\x11\n" +
 	"\n" +
 	"MCPManager\x12*\n" +
 	"\vListServers\x12\n" +
@@ -1197,18 +5100,61 @@ const file_mcp_proto_rawDesc = "" +
 	"\tGetServer\x12\x12.mcp.ServerRequest\x1a\v.mcp.Server\x12.\n" +
 	"\vStartServer\x12\x12.mcp.ServerRequest\x1a\v.mcp.Server\x12-\n" +
 	"\n" +
-	"StopServer\x12\x12.mcp.ServerRequest\x1a\v.mcp.Server\x12-\n" +
+	"StopServer\x12\x12.mcp.ServerRequest\x1a\v.mcp.Server\x12/\n" +
+	"\vStartTagged\x12\x0f.mcp.TagRequest\x1a\x0f.mcp.ServerList\x12.\n" +
+	"\n" +
+	"StopTagged\x12\x0f.mcp.TagRequest\x1a\x0f.mcp.ServerList\x12-\n" +
 	"\bGetTools\x12\x12.mcp.ServerRequest\x1a\r.mcp.ToolList\x12$\n" +
 	"\tGetConfig\x12\n" +
 	".mcp.Empty\x1a\v.mcp.Config\x12/\n" +
 	"\fReloadConfig\x12\n" +
 	".mcp.Empty\x1a\x13.mcp.StatusResponse\x12.\n" +
 	"\rGetConfigPath\x12\n" +
-	".mcp.Empty\x1a\x11.mcp.PathResponse\x120\n" +
+	".mcp.Empty\x1a\x11.mcp.PathResponse\x123\n" +
+	"\x0eValidateConfig\x12\n" +
+	".mcp.Empty\x1a\x15.mcp.ConfigValidation\x120\n" +
 	"\tSubscribe\x12\x15.mcp.SubscribeRequest\x1a\n" +
-	".mcp.Event0\x01\x12'\n" +
+	".mcp.Event0\x01\x12.\n" +
+	"\n" +
+	"StreamLogs\x12\x10.mcp.LogsRequest\x1a\f.mcp.LogLine0\x01\x12.\n" +
+	"\vQueryEvents\x12\x0f.mcp.EventQuery\x1a\x0e.mcp.EventList\x122\n" +
+	"\tGetUptime\x12\x12.mcp.UptimeRequest\x1a\x11.mcp.UptimeReport\x12'\n" +
 	"\x06Health\x12\n" +
-	".mcp.Empty\x1a\x11.mcp.HealthStatusB3Z1github.com/tartavull/mcp-manager/internal/grpc/pbb\x06proto3"
+	".mcp.Empty\x1a\x11.mcp.HealthStatus\x12>\n" +
+	"\x0eSetMaintenance\x12\x17.mcp.MaintenanceRequest\x1a\x13.mcp.StatusResponse\x125\n" +
+	"\bRegister\x12\x14.mcp.RegisterRequest\x1a\x13.mcp.StatusResponse\x12.\n" +
+	"\rListApprovals\x12\n" +
+	".mcp.Empty\x1a\x11.mcp.ApprovalList\x12<\n" +
+	"\x0eDecideApproval\x12\x15.mcp.ApprovalDecision\x1a\x13.mcp.StatusResponse\x124\n" +
+	"\fListSessions\x12\x12.mcp.SessionFilter\x1a\x10.mcp.SessionList\x129\n" +
+	"\n" +
+	"GetSession\x12\x13.mcp.SessionRequest\x1a\x16.mcp.TranscriptSession\x12*\n" +
+	"\vListPlugins\x12\n" +
+	".mcp.Empty\x1a\x0f.mcp.PluginList\x12,\n" +
+	"\vCreateFleet\x12\x11.mcp.FleetRequest\x1a\n" +
+	".mcp.Fleet\x126\n" +
+	"\fDestroyFleet\x12\x11.mcp.FleetRequest\x1a\x13.mcp.StatusResponse\x12(\n" +
+	"\n" +
+	"ListFleets\x12\n" +
+	".mcp.Empty\x1a\x0e.mcp.FleetList\x12-\n" +
+	"\vDrainServer\x12\x11.mcp.DrainRequest\x1a\v.mcp.Server\x124\n" +
+	"\fListProfiles\x12\x12.mcp.ServerRequest\x1a\x10.mcp.ProfileList\x128\n" +
+	"\fResetProfile\x12\x13.mcp.ProfileRequest\x1a\x13.mcp.StatusResponse\x12<\n" +
+	"\x0fSnapshotProfile\x12\x13.mcp.ProfileRequest\x1a\x14.mcp.ProfileSnapshot\x12:\n" +
+	"\x0eRestoreProfile\x12\x13.mcp.ProfileRequest\x1a\x13.mcp.StatusResponse\x121\n" +
+	"\tDiskUsage\x12\x10.mcp.DiskRequest\x1a\x12.mcp.DiskUsageList\x123\n" +
+	"\vCleanCaches\x12\x10.mcp.DiskRequest\x1a\x12.mcp.DiskUsageList\x12/\n" +
+	"\fBackupServer\x12\x12.mcp.ServerRequest\x1a\v.mcp.Backup\x122\n" +
+	"\vListBackups\x12\x12.mcp.ServerRequest\x1a\x0f.mcp.BackupList\x128\n" +
+	"\rRestoreBackup\x12\x12.mcp.BackupRequest\x1a\x13.mcp.StatusResponse\x12.\n" +
+	"\rListProviders\x12\n" +
+	".mcp.Empty\x1a\x11.mcp.ProviderList\x123\n" +
+	"\vPinProvider\x12\x0f.mcp.PinRequest\x1a\x13.mcp.StatusResponse\x126\n" +
+	"\x11ListNotifications\x12\n" +
+	".mcp.Empty\x1a\x15.mcp.NotificationList\x12@\n" +
+	"\x0fSetNotification\x12\x18.mcp.NotificationRequest\x1a\x13.mcp.StatusResponse\x128\n" +
+	"\x0eDescribeServer\x12\x12.mcp.ServerRequest\x1a\x12.mcp.ServerRuntime\x124\n" +
+	"\vCloneServer\x12\x11.mcp.CloneRequest\x1a\x12.mcp.CloneResponseB3Z1github.com/tartavull/mcp-manager/internal/grpc/pbb\x06proto3"
 
 var (
 	file_mcp_proto_rawDescOnce sync.Once
@@ -1222,69 +5168,217 @@ func file_mcp_proto_rawDescGZIP() []byte {
 	return file_mcp_proto_rawDescData
 }
 
-var file_mcp_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_mcp_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_mcp_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_mcp_proto_msgTypes = make([]protoimpl.MessageInfo, 72)
 var file_mcp_proto_goTypes = []any{
-	(ServerStatus)(0),         // 0: mcp.ServerStatus
-	(EventType)(0),            // 1: mcp.EventType
-	(*Empty)(nil),             // 2: mcp.Empty
-	(*ServerRequest)(nil),     // 3: mcp.ServerRequest
-	(*StatusResponse)(nil),    // 4: mcp.StatusResponse
-	(*PathResponse)(nil),      // 5: mcp.PathResponse
-	(*Server)(nil),            // 6: mcp.Server
-	(*ServerList)(nil),        // 7: mcp.ServerList
-	(*Tool)(nil),              // 8: mcp.Tool
-	(*ToolList)(nil),          // 9: mcp.ToolList
-	(*Config)(nil),            // 10: mcp.Config
-	(*ServerConfig)(nil),      // 11: mcp.ServerConfig
-	(*SubscribeRequest)(nil),  // 12: mcp.SubscribeRequest
-	(*Event)(nil),             // 13: mcp.Event
-	(*ServerStatusEvent)(nil), // 14: mcp.ServerStatusEvent
-	(*ToolUpdateEvent)(nil),   // 15: mcp.ToolUpdateEvent
-	(*ConfigChangeEvent)(nil), // 16: mcp.ConfigChangeEvent
-	(*HealthStatus)(nil),      // 17: mcp.HealthStatus
-	nil,                       // 18: mcp.Config.ServersEntry
+	(ServerStatus)(0),              // 0: mcp.ServerStatus
+	(EventType)(0),                 // 1: mcp.EventType
+	(LogSeverity)(0),               // 2: mcp.LogSeverity
+	(*Empty)(nil),                  // 3: mcp.Empty
+	(*ServerRequest)(nil),          // 4: mcp.ServerRequest
+	(*TagRequest)(nil),             // 5: mcp.TagRequest
+	(*StatusResponse)(nil),         // 6: mcp.StatusResponse
+	(*PathResponse)(nil),           // 7: mcp.PathResponse
+	(*MaintenanceRequest)(nil),     // 8: mcp.MaintenanceRequest
+	(*RegisterRequest)(nil),        // 9: mcp.RegisterRequest
+	(*Server)(nil),                 // 10: mcp.Server
+	(*ServerList)(nil),             // 11: mcp.ServerList
+	(*Tool)(nil),                   // 12: mcp.Tool
+	(*ToolList)(nil),               // 13: mcp.ToolList
+	(*Config)(nil),                 // 14: mcp.Config
+	(*ServerConfig)(nil),           // 15: mcp.ServerConfig
+	(*ConfigValidation)(nil),       // 16: mcp.ConfigValidation
+	(*ToolConflict)(nil),           // 17: mcp.ToolConflict
+	(*SubscribeRequest)(nil),       // 18: mcp.SubscribeRequest
+	(*Event)(nil),                  // 19: mcp.Event
+	(*ServerStatusEvent)(nil),      // 20: mcp.ServerStatusEvent
+	(*ToolUpdateEvent)(nil),        // 21: mcp.ToolUpdateEvent
+	(*ConfigChangeEvent)(nil),      // 22: mcp.ConfigChangeEvent
+	(*FailoverEvent)(nil),          // 23: mcp.FailoverEvent
+	(*CircuitBreakerEvent)(nil),    // 24: mcp.CircuitBreakerEvent
+	(*ApprovalEvent)(nil),          // 25: mcp.ApprovalEvent
+	(*PortMigrationEvent)(nil),     // 26: mcp.PortMigrationEvent
+	(*HeartbeatEvent)(nil),         // 27: mcp.HeartbeatEvent
+	(*EventQuery)(nil),             // 28: mcp.EventQuery
+	(*EventList)(nil),              // 29: mcp.EventList
+	(*UptimeRequest)(nil),          // 30: mcp.UptimeRequest
+	(*ServerUptime)(nil),           // 31: mcp.ServerUptime
+	(*UptimeReport)(nil),           // 32: mcp.UptimeReport
+	(*LogsRequest)(nil),            // 33: mcp.LogsRequest
+	(*LogLine)(nil),                // 34: mcp.LogLine
+	(*HealthStatus)(nil),           // 35: mcp.HealthStatus
+	(*Approval)(nil),               // 36: mcp.Approval
+	(*ApprovalList)(nil),           // 37: mcp.ApprovalList
+	(*ApprovalDecision)(nil),       // 38: mcp.ApprovalDecision
+	(*SessionRequest)(nil),         // 39: mcp.SessionRequest
+	(*SessionFilter)(nil),          // 40: mcp.SessionFilter
+	(*TranscriptEntry)(nil),        // 41: mcp.TranscriptEntry
+	(*TranscriptSession)(nil),      // 42: mcp.TranscriptSession
+	(*SessionList)(nil),            // 43: mcp.SessionList
+	(*Plugin)(nil),                 // 44: mcp.Plugin
+	(*PluginList)(nil),             // 45: mcp.PluginList
+	(*FleetRequest)(nil),           // 46: mcp.FleetRequest
+	(*Fleet)(nil),                  // 47: mcp.Fleet
+	(*FleetList)(nil),              // 48: mcp.FleetList
+	(*DrainRequest)(nil),           // 49: mcp.DrainRequest
+	(*ProfileRequest)(nil),         // 50: mcp.ProfileRequest
+	(*ProfileSnapshot)(nil),        // 51: mcp.ProfileSnapshot
+	(*BrowserProfile)(nil),         // 52: mcp.BrowserProfile
+	(*ProfileList)(nil),            // 53: mcp.ProfileList
+	(*DiskRequest)(nil),            // 54: mcp.DiskRequest
+	(*DiskDir)(nil),                // 55: mcp.DiskDir
+	(*ServerDisk)(nil),             // 56: mcp.ServerDisk
+	(*DiskUsageList)(nil),          // 57: mcp.DiskUsageList
+	(*BackupRequest)(nil),          // 58: mcp.BackupRequest
+	(*Backup)(nil),                 // 59: mcp.Backup
+	(*BackupList)(nil),             // 60: mcp.BackupList
+	(*ProviderStats)(nil),          // 61: mcp.ProviderStats
+	(*ProviderGroup)(nil),          // 62: mcp.ProviderGroup
+	(*ProviderList)(nil),           // 63: mcp.ProviderList
+	(*PinRequest)(nil),             // 64: mcp.PinRequest
+	(*NotificationPreference)(nil), // 65: mcp.NotificationPreference
+	(*NotificationList)(nil),       // 66: mcp.NotificationList
+	(*NotificationRequest)(nil),    // 67: mcp.NotificationRequest
+	(*RuntimeVar)(nil),             // 68: mcp.RuntimeVar
+	(*RuntimeLimit)(nil),           // 69: mcp.RuntimeLimit
+	(*ServerRuntime)(nil),          // 70: mcp.ServerRuntime
+	(*CloneRequest)(nil),           // 71: mcp.CloneRequest
+	(*CloneResponse)(nil),          // 72: mcp.CloneResponse
+	nil,                            // 73: mcp.Config.ServersEntry
+	nil,                            // 74: mcp.ToolConflict.RenamedEntry
 }
 var file_mcp_proto_depIdxs = []int32{
 	0,  // 0: mcp.Server.status:type_name -> mcp.ServerStatus
-	8,  // 1: mcp.Server.tools:type_name -> mcp.Tool
-	6,  // 2: mcp.ServerList.servers:type_name -> mcp.Server
-	8,  // 3: mcp.ToolList.tools:type_name -> mcp.Tool
-	18, // 4: mcp.Config.servers:type_name -> mcp.Config.ServersEntry
-	1,  // 5: mcp.SubscribeRequest.event_types:type_name -> mcp.EventType
-	1,  // 6: mcp.Event.type:type_name -> mcp.EventType
-	14, // 7: mcp.Event.server_status:type_name -> mcp.ServerStatusEvent
-	15, // 8: mcp.Event.tool_update:type_name -> mcp.ToolUpdateEvent
-	16, // 9: mcp.Event.config_change:type_name -> mcp.ConfigChangeEvent
-	0,  // 10: mcp.ServerStatusEvent.old_status:type_name -> mcp.ServerStatus
-	0,  // 11: mcp.ServerStatusEvent.new_status:type_name -> mcp.ServerStatus
-	8,  // 12: mcp.ToolUpdateEvent.tools:type_name -> mcp.Tool
-	11, // 13: mcp.Config.ServersEntry.value:type_name -> mcp.ServerConfig
-	2,  // 14: mcp.MCPManager.ListServers:input_type -> mcp.Empty
-	3,  // 15: mcp.MCPManager.GetServer:input_type -> mcp.ServerRequest
-	3,  // 16: mcp.MCPManager.StartServer:input_type -> mcp.ServerRequest
-	3,  // 17: mcp.MCPManager.StopServer:input_type -> mcp.ServerRequest
-	3,  // 18: mcp.MCPManager.GetTools:input_type -> mcp.ServerRequest
-	2,  // 19: mcp.MCPManager.GetConfig:input_type -> mcp.Empty
-	2,  // 20: mcp.MCPManager.ReloadConfig:input_type -> mcp.Empty
-	2,  // 21: mcp.MCPManager.GetConfigPath:input_type -> mcp.Empty
-	12, // 22: mcp.MCPManager.Subscribe:input_type -> mcp.SubscribeRequest
-	2,  // 23: mcp.MCPManager.Health:input_type -> mcp.Empty
-	7,  // 24: mcp.MCPManager.ListServers:output_type -> mcp.ServerList
-	6,  // 25: mcp.MCPManager.GetServer:output_type -> mcp.Server
-	6,  // 26: mcp.MCPManager.StartServer:output_type -> mcp.Server
-	6,  // 27: mcp.MCPManager.StopServer:output_type -> mcp.Server
-	9,  // 28: mcp.MCPManager.GetTools:output_type -> mcp.ToolList
-	10, // 29: mcp.MCPManager.GetConfig:output_type -> mcp.Config
-	4,  // 30: mcp.MCPManager.ReloadConfig:output_type -> mcp.StatusResponse
-	5,  // 31: mcp.MCPManager.GetConfigPath:output_type -> mcp.PathResponse
-	13, // 32: mcp.MCPManager.Subscribe:output_type -> mcp.Event
-	17, // 33: mcp.MCPManager.Health:output_type -> mcp.HealthStatus
-	24, // [24:34] is the sub-list for method output_type
-	14, // [14:24] is the sub-list for method input_type
-	14, // [14:14] is the sub-list for extension type_name
-	14, // [14:14] is the sub-list for extension extendee
-	0,  // [0:14] is the sub-list for field type_name
+	12, // 1: mcp.Server.tools:type_name -> mcp.Tool
+	10, // 2: mcp.ServerList.servers:type_name -> mcp.Server
+	12, // 3: mcp.ToolList.tools:type_name -> mcp.Tool
+	73, // 4: mcp.Config.servers:type_name -> mcp.Config.ServersEntry
+	17, // 5: mcp.ConfigValidation.conflicts:type_name -> mcp.ToolConflict
+	74, // 6: mcp.ToolConflict.renamed:type_name -> mcp.ToolConflict.RenamedEntry
+	1,  // 7: mcp.SubscribeRequest.event_types:type_name -> mcp.EventType
+	1,  // 8: mcp.Event.type:type_name -> mcp.EventType
+	20, // 9: mcp.Event.server_status:type_name -> mcp.ServerStatusEvent
+	21, // 10: mcp.Event.tool_update:type_name -> mcp.ToolUpdateEvent
+	22, // 11: mcp.Event.config_change:type_name -> mcp.ConfigChangeEvent
+	23, // 12: mcp.Event.failover:type_name -> mcp.FailoverEvent
+	27, // 13: mcp.Event.heartbeat:type_name -> mcp.HeartbeatEvent
+	24, // 14: mcp.Event.circuit_breaker:type_name -> mcp.CircuitBreakerEvent
+	25, // 15: mcp.Event.approval:type_name -> mcp.ApprovalEvent
+	26, // 16: mcp.Event.port_migration:type_name -> mcp.PortMigrationEvent
+	0,  // 17: mcp.ServerStatusEvent.old_status:type_name -> mcp.ServerStatus
+	0,  // 18: mcp.ServerStatusEvent.new_status:type_name -> mcp.ServerStatus
+	12, // 19: mcp.ToolUpdateEvent.tools:type_name -> mcp.Tool
+	36, // 20: mcp.ApprovalEvent.approval:type_name -> mcp.Approval
+	1,  // 21: mcp.EventQuery.event_types:type_name -> mcp.EventType
+	19, // 22: mcp.EventList.events:type_name -> mcp.Event
+	31, // 23: mcp.UptimeReport.servers:type_name -> mcp.ServerUptime
+	2,  // 24: mcp.LogLine.severity:type_name -> mcp.LogSeverity
+	36, // 25: mcp.ApprovalList.approvals:type_name -> mcp.Approval
+	41, // 26: mcp.TranscriptSession.entries:type_name -> mcp.TranscriptEntry
+	42, // 27: mcp.SessionList.sessions:type_name -> mcp.TranscriptSession
+	44, // 28: mcp.PluginList.plugins:type_name -> mcp.Plugin
+	10, // 29: mcp.Fleet.servers:type_name -> mcp.Server
+	47, // 30: mcp.FleetList.fleets:type_name -> mcp.Fleet
+	51, // 31: mcp.BrowserProfile.snapshots:type_name -> mcp.ProfileSnapshot
+	52, // 32: mcp.ProfileList.profiles:type_name -> mcp.BrowserProfile
+	55, // 33: mcp.ServerDisk.dirs:type_name -> mcp.DiskDir
+	56, // 34: mcp.DiskUsageList.servers:type_name -> mcp.ServerDisk
+	59, // 35: mcp.BackupList.backups:type_name -> mcp.Backup
+	61, // 36: mcp.ProviderGroup.providers:type_name -> mcp.ProviderStats
+	62, // 37: mcp.ProviderList.groups:type_name -> mcp.ProviderGroup
+	65, // 38: mcp.NotificationList.servers:type_name -> mcp.NotificationPreference
+	68, // 39: mcp.ServerRuntime.env:type_name -> mcp.RuntimeVar
+	69, // 40: mcp.ServerRuntime.limits:type_name -> mcp.RuntimeLimit
+	15, // 41: mcp.Config.ServersEntry.value:type_name -> mcp.ServerConfig
+	3,  // 42: mcp.MCPManager.ListServers:input_type -> mcp.Empty
+	4,  // 43: mcp.MCPManager.GetServer:input_type -> mcp.ServerRequest
+	4,  // 44: mcp.MCPManager.StartServer:input_type -> mcp.ServerRequest
+	4,  // 45: mcp.MCPManager.StopServer:input_type -> mcp.ServerRequest
+	5,  // 46: mcp.MCPManager.StartTagged:input_type -> mcp.TagRequest
+	5,  // 47: mcp.MCPManager.StopTagged:input_type -> mcp.TagRequest
+	4,  // 48: mcp.MCPManager.GetTools:input_type -> mcp.ServerRequest
+	3,  // 49: mcp.MCPManager.GetConfig:input_type -> mcp.Empty
+	3,  // 50: mcp.MCPManager.ReloadConfig:input_type -> mcp.Empty
+	3,  // 51: mcp.MCPManager.GetConfigPath:input_type -> mcp.Empty
+	3,  // 52: mcp.MCPManager.ValidateConfig:input_type -> mcp.Empty
+	18, // 53: mcp.MCPManager.Subscribe:input_type -> mcp.SubscribeRequest
+	33, // 54: mcp.MCPManager.StreamLogs:input_type -> mcp.LogsRequest
+	28, // 55: mcp.MCPManager.QueryEvents:input_type -> mcp.EventQuery
+	30, // 56: mcp.MCPManager.GetUptime:input_type -> mcp.UptimeRequest
+	3,  // 57: mcp.MCPManager.Health:input_type -> mcp.Empty
+	8,  // 58: mcp.MCPManager.SetMaintenance:input_type -> mcp.MaintenanceRequest
+	9,  // 59: mcp.MCPManager.Register:input_type -> mcp.RegisterRequest
+	3,  // 60: mcp.MCPManager.ListApprovals:input_type -> mcp.Empty
+	38, // 61: mcp.MCPManager.DecideApproval:input_type -> mcp.ApprovalDecision
+	40, // 62: mcp.MCPManager.ListSessions:input_type -> mcp.SessionFilter
+	39, // 63: mcp.MCPManager.GetSession:input_type -> mcp.SessionRequest
+	3,  // 64: mcp.MCPManager.ListPlugins:input_type -> mcp.Empty
+	46, // 65: mcp.MCPManager.CreateFleet:input_type -> mcp.FleetRequest
+	46, // 66: mcp.MCPManager.DestroyFleet:input_type -> mcp.FleetRequest
+	3,  // 67: mcp.MCPManager.ListFleets:input_type -> mcp.Empty
+	49, // 68: mcp.MCPManager.DrainServer:input_type -> mcp.DrainRequest
+	4,  // 69: mcp.MCPManager.ListProfiles:input_type -> mcp.ServerRequest
+	50, // 70: mcp.MCPManager.ResetProfile:input_type -> mcp.ProfileRequest
+	50, // 71: mcp.MCPManager.SnapshotProfile:input_type -> mcp.ProfileRequest
+	50, // 72: mcp.MCPManager.RestoreProfile:input_type -> mcp.ProfileRequest
+	54, // 73: mcp.MCPManager.DiskUsage:input_type -> mcp.DiskRequest
+	54, // 74: mcp.MCPManager.CleanCaches:input_type -> mcp.DiskRequest
+	4,  // 75: mcp.MCPManager.BackupServer:input_type -> mcp.ServerRequest
+	4,  // 76: mcp.MCPManager.ListBackups:input_type -> mcp.ServerRequest
+	58, // 77: mcp.MCPManager.RestoreBackup:input_type -> mcp.BackupRequest
+	3,  // 78: mcp.MCPManager.ListProviders:input_type -> mcp.Empty
+	64, // 79: mcp.MCPManager.PinProvider:input_type -> mcp.PinRequest
+	3,  // 80: mcp.MCPManager.ListNotifications:input_type -> mcp.Empty
+	67, // 81: mcp.MCPManager.SetNotification:input_type -> mcp.NotificationRequest
+	4,  // 82: mcp.MCPManager.DescribeServer:input_type -> mcp.ServerRequest
+	71, // 83: mcp.MCPManager.CloneServer:input_type -> mcp.CloneRequest
+	11, // 84: mcp.MCPManager.ListServers:output_type -> mcp.ServerList
+	10, // 85: mcp.MCPManager.GetServer:output_type -> mcp.Server
+	10, // 86: mcp.MCPManager.StartServer:output_type -> mcp.Server
+	10, // 87: mcp.MCPManager.StopServer:output_type -> mcp.Server
+	11, // 88: mcp.MCPManager.StartTagged:output_type -> mcp.ServerList
+	11, // 89: mcp.MCPManager.StopTagged:output_type -> mcp.ServerList
+	13, // 90: mcp.MCPManager.GetTools:output_type -> mcp.ToolList
+	14, // 91: mcp.MCPManager.GetConfig:output_type -> mcp.Config
+	6,  // 92: mcp.MCPManager.ReloadConfig:output_type -> mcp.StatusResponse
+	7,  // 93: mcp.MCPManager.GetConfigPath:output_type -> mcp.PathResponse
+	16, // 94: mcp.MCPManager.ValidateConfig:output_type -> mcp.ConfigValidation
+	19, // 95: mcp.MCPManager.Subscribe:output_type -> mcp.Event
+	34, // 96: mcp.MCPManager.StreamLogs:output_type -> mcp.LogLine
+	29, // 97: mcp.MCPManager.QueryEvents:output_type -> mcp.EventList
+	32, // 98: mcp.MCPManager.GetUptime:output_type -> mcp.UptimeReport
+	35, // 99: mcp.MCPManager.Health:output_type -> mcp.HealthStatus
+	6,  // 100: mcp.MCPManager.SetMaintenance:output_type -> mcp.StatusResponse
+	6,  // 101: mcp.MCPManager.Register:output_type -> mcp.StatusResponse
+	37, // 102: mcp.MCPManager.ListApprovals:output_type -> mcp.ApprovalList
+	6,  // 103: mcp.MCPManager.DecideApproval:output_type -> mcp.StatusResponse
+	43, // 104: mcp.MCPManager.ListSessions:output_type -> mcp.SessionList
+	42, // 105: mcp.MCPManager.GetSession:output_type -> mcp.TranscriptSession
+	45, // 106: mcp.MCPManager.ListPlugins:output_type -> mcp.PluginList
+	47, // 107: mcp.MCPManager.CreateFleet:output_type -> mcp.Fleet
+	6,  // 108: mcp.MCPManager.DestroyFleet:output_type -> mcp.StatusResponse
+	48, // 109: mcp.MCPManager.ListFleets:output_type -> mcp.FleetList
+	10, // 110: mcp.MCPManager.DrainServer:output_type -> mcp.Server
+	53, // 111: mcp.MCPManager.ListProfiles:output_type -> mcp.ProfileList
+	6,  // 112: mcp.MCPManager.ResetProfile:output_type -> mcp.StatusResponse
+	51, // 113: mcp.MCPManager.SnapshotProfile:output_type -> mcp.ProfileSnapshot
+	6,  // 114: mcp.MCPManager.RestoreProfile:output_type -> mcp.StatusResponse
+	57, // 115: mcp.MCPManager.DiskUsage:output_type -> mcp.DiskUsageList
+	57, // 116: mcp.MCPManager.CleanCaches:output_type -> mcp.DiskUsageList
+	59, // 117: mcp.MCPManager.BackupServer:output_type -> mcp.Backup
+	60, // 118: mcp.MCPManager.ListBackups:output_type -> mcp.BackupList
+	6,  // 119: mcp.MCPManager.RestoreBackup:output_type -> mcp.StatusResponse
+	63, // 120: mcp.MCPManager.ListProviders:output_type -> mcp.ProviderList
+	6,  // 121: mcp.MCPManager.PinProvider:output_type -> mcp.StatusResponse
+	66, // 122: mcp.MCPManager.ListNotifications:output_type -> mcp.NotificationList
+	6,  // 123: mcp.MCPManager.SetNotification:output_type -> mcp.StatusResponse
+	70, // 124: mcp.MCPManager.DescribeServer:output_type -> mcp.ServerRuntime
+	72, // 125: mcp.MCPManager.CloneServer:output_type -> mcp.CloneResponse
+	84, // [84:126] is the sub-list for method output_type
+	42, // [42:84] is the sub-list for method input_type
+	42, // [42:42] is the sub-list for extension type_name
+	42, // [42:42] is the sub-list for extension extendee
+	0,  // [0:42] is the sub-list for field type_name
 }
 
 func init() { file_mcp_proto_init() }
@@ -1292,18 +5386,23 @@ func file_mcp_proto_init() {
 	if File_mcp_proto != nil {
 		return
 	}
-	file_mcp_proto_msgTypes[11].OneofWrappers = []any{
+	file_mcp_proto_msgTypes[16].OneofWrappers = []any{
 		(*Event_ServerStatus)(nil),
 		(*Event_ToolUpdate)(nil),
 		(*Event_ConfigChange)(nil),
+		(*Event_Failover)(nil),
+		(*Event_Heartbeat)(nil),
+		(*Event_CircuitBreaker)(nil),
+		(*Event_Approval)(nil),
+		(*Event_PortMigration)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_mcp_proto_rawDesc), len(file_mcp_proto_rawDesc)),
-			NumEnums:      2,
-			NumMessages:   17,
+			NumEnums:      3,
+			NumMessages:   72,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
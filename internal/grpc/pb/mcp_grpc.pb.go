@@ -19,16 +19,48 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	MCPManager_ListServers_FullMethodName   = "/mcp.MCPManager/ListServers"
-	MCPManager_GetServer_FullMethodName     = "/mcp.MCPManager/GetServer"
-	MCPManager_StartServer_FullMethodName   = "/mcp.MCPManager/StartServer"
-	MCPManager_StopServer_FullMethodName    = "/mcp.MCPManager/StopServer"
-	MCPManager_GetTools_FullMethodName      = "/mcp.MCPManager/GetTools"
-	MCPManager_GetConfig_FullMethodName     = "/mcp.MCPManager/GetConfig"
-	MCPManager_ReloadConfig_FullMethodName  = "/mcp.MCPManager/ReloadConfig"
-	MCPManager_GetConfigPath_FullMethodName = "/mcp.MCPManager/GetConfigPath"
-	MCPManager_Subscribe_FullMethodName     = "/mcp.MCPManager/Subscribe"
-	MCPManager_Health_FullMethodName        = "/mcp.MCPManager/Health"
+	MCPManager_ListServers_FullMethodName       = "/mcp.MCPManager/ListServers"
+	MCPManager_GetServer_FullMethodName         = "/mcp.MCPManager/GetServer"
+	MCPManager_StartServer_FullMethodName       = "/mcp.MCPManager/StartServer"
+	MCPManager_StopServer_FullMethodName        = "/mcp.MCPManager/StopServer"
+	MCPManager_StartTagged_FullMethodName       = "/mcp.MCPManager/StartTagged"
+	MCPManager_StopTagged_FullMethodName        = "/mcp.MCPManager/StopTagged"
+	MCPManager_GetTools_FullMethodName          = "/mcp.MCPManager/GetTools"
+	MCPManager_GetConfig_FullMethodName         = "/mcp.MCPManager/GetConfig"
+	MCPManager_ReloadConfig_FullMethodName      = "/mcp.MCPManager/ReloadConfig"
+	MCPManager_GetConfigPath_FullMethodName     = "/mcp.MCPManager/GetConfigPath"
+	MCPManager_ValidateConfig_FullMethodName    = "/mcp.MCPManager/ValidateConfig"
+	MCPManager_Subscribe_FullMethodName         = "/mcp.MCPManager/Subscribe"
+	MCPManager_StreamLogs_FullMethodName        = "/mcp.MCPManager/StreamLogs"
+	MCPManager_QueryEvents_FullMethodName       = "/mcp.MCPManager/QueryEvents"
+	MCPManager_GetUptime_FullMethodName         = "/mcp.MCPManager/GetUptime"
+	MCPManager_Health_FullMethodName            = "/mcp.MCPManager/Health"
+	MCPManager_SetMaintenance_FullMethodName    = "/mcp.MCPManager/SetMaintenance"
+	MCPManager_Register_FullMethodName          = "/mcp.MCPManager/Register"
+	MCPManager_ListApprovals_FullMethodName     = "/mcp.MCPManager/ListApprovals"
+	MCPManager_DecideApproval_FullMethodName    = "/mcp.MCPManager/DecideApproval"
+	MCPManager_ListSessions_FullMethodName      = "/mcp.MCPManager/ListSessions"
+	MCPManager_GetSession_FullMethodName        = "/mcp.MCPManager/GetSession"
+	MCPManager_ListPlugins_FullMethodName       = "/mcp.MCPManager/ListPlugins"
+	MCPManager_CreateFleet_FullMethodName       = "/mcp.MCPManager/CreateFleet"
+	MCPManager_DestroyFleet_FullMethodName      = "/mcp.MCPManager/DestroyFleet"
+	MCPManager_ListFleets_FullMethodName        = "/mcp.MCPManager/ListFleets"
+	MCPManager_DrainServer_FullMethodName       = "/mcp.MCPManager/DrainServer"
+	MCPManager_ListProfiles_FullMethodName      = "/mcp.MCPManager/ListProfiles"
+	MCPManager_ResetProfile_FullMethodName      = "/mcp.MCPManager/ResetProfile"
+	MCPManager_SnapshotProfile_FullMethodName   = "/mcp.MCPManager/SnapshotProfile"
+	MCPManager_RestoreProfile_FullMethodName    = "/mcp.MCPManager/RestoreProfile"
+	MCPManager_DiskUsage_FullMethodName         = "/mcp.MCPManager/DiskUsage"
+	MCPManager_CleanCaches_FullMethodName       = "/mcp.MCPManager/CleanCaches"
+	MCPManager_BackupServer_FullMethodName      = "/mcp.MCPManager/BackupServer"
+	MCPManager_ListBackups_FullMethodName       = "/mcp.MCPManager/ListBackups"
+	MCPManager_RestoreBackup_FullMethodName     = "/mcp.MCPManager/RestoreBackup"
+	MCPManager_ListProviders_FullMethodName     = "/mcp.MCPManager/ListProviders"
+	MCPManager_PinProvider_FullMethodName       = "/mcp.MCPManager/PinProvider"
+	MCPManager_ListNotifications_FullMethodName = "/mcp.MCPManager/ListNotifications"
+	MCPManager_SetNotification_FullMethodName   = "/mcp.MCPManager/SetNotification"
+	MCPManager_DescribeServer_FullMethodName    = "/mcp.MCPManager/DescribeServer"
+	MCPManager_CloneServer_FullMethodName       = "/mcp.MCPManager/CloneServer"
 )
 
 // MCPManagerClient is the client API for MCPManager service.
@@ -40,16 +72,65 @@ type MCPManagerClient interface {
 	GetServer(ctx context.Context, in *ServerRequest, opts ...grpc.CallOption) (*Server, error)
 	StartServer(ctx context.Context, in *ServerRequest, opts ...grpc.CallOption) (*Server, error)
 	StopServer(ctx context.Context, in *ServerRequest, opts ...grpc.CallOption) (*Server, error)
+	// Operations on all the servers labeled with a tag
+	StartTagged(ctx context.Context, in *TagRequest, opts ...grpc.CallOption) (*ServerList, error)
+	StopTagged(ctx context.Context, in *TagRequest, opts ...grpc.CallOption) (*ServerList, error)
 	// Tool information
 	GetTools(ctx context.Context, in *ServerRequest, opts ...grpc.CallOption) (*ToolList, error)
 	// Configuration
 	GetConfig(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Config, error)
 	ReloadConfig(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*StatusResponse, error)
 	GetConfigPath(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*PathResponse, error)
+	ValidateConfig(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ConfigValidation, error)
 	// Real-time streaming
 	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error)
+	StreamLogs(ctx context.Context, in *LogsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LogLine], error)
+	// Past events from the daemon's journal
+	QueryEvents(ctx context.Context, in *EventQuery, opts ...grpc.CallOption) (*EventList, error)
+	// Availability of servers derived from their status changes
+	GetUptime(ctx context.Context, in *UptimeRequest, opts ...grpc.CallOption) (*UptimeReport, error)
 	// Health check
 	Health(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*HealthStatus, error)
+	// Maintenance mode suppresses automatic actions
+	SetMaintenance(ctx context.Context, in *MaintenanceRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+	// Cluster membership, served by coordinators
+	Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+	// Tool calls waiting for an operator's approval
+	ListApprovals(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ApprovalList, error)
+	DecideApproval(ctx context.Context, in *ApprovalDecision, opts ...grpc.CallOption) (*StatusResponse, error)
+	// Proxied calls grouped by client
+	ListSessions(ctx context.Context, in *SessionFilter, opts ...grpc.CallOption) (*SessionList, error)
+	GetSession(ctx context.Context, in *SessionRequest, opts ...grpc.CallOption) (*TranscriptSession, error)
+	// Plugins found in the daemon's plugin directory
+	ListPlugins(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*PluginList, error)
+	// Isolated copies of servers per evaluation run
+	CreateFleet(ctx context.Context, in *FleetRequest, opts ...grpc.CallOption) (*Fleet, error)
+	DestroyFleet(ctx context.Context, in *FleetRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+	ListFleets(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*FleetList, error)
+	// Stops a server once the calls in flight on its proxy finish
+	DrainServer(ctx context.Context, in *DrainRequest, opts ...grpc.CallOption) (*Server, error)
+	// Browser profiles of browser-automation servers
+	ListProfiles(ctx context.Context, in *ServerRequest, opts ...grpc.CallOption) (*ProfileList, error)
+	ResetProfile(ctx context.Context, in *ProfileRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+	SnapshotProfile(ctx context.Context, in *ProfileRequest, opts ...grpc.CallOption) (*ProfileSnapshot, error)
+	RestoreProfile(ctx context.Context, in *ProfileRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+	// Disk used by servers and pruning of their caches
+	DiskUsage(ctx context.Context, in *DiskRequest, opts ...grpc.CallOption) (*DiskUsageList, error)
+	CleanCaches(ctx context.Context, in *DiskRequest, opts ...grpc.CallOption) (*DiskUsageList, error)
+	// Archives of the data servers keep
+	BackupServer(ctx context.Context, in *ServerRequest, opts ...grpc.CallOption) (*Backup, error)
+	ListBackups(ctx context.Context, in *ServerRequest, opts ...grpc.CallOption) (*BackupList, error)
+	RestoreBackup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+	// Providers of generic gateway tools
+	ListProviders(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ProviderList, error)
+	PinProvider(ctx context.Context, in *PinRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+	// Desktop notifications of server events
+	ListNotifications(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*NotificationList, error)
+	SetNotification(ctx context.Context, in *NotificationRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+	// What a server runs as, for debugging its config
+	DescribeServer(ctx context.Context, in *ServerRequest, opts ...grpc.CallOption) (*ServerRuntime, error)
+	// Copies of servers, e.g. a second filesystem server on another directory
+	CloneServer(ctx context.Context, in *CloneRequest, opts ...grpc.CallOption) (*CloneResponse, error)
 }
 
 type mCPManagerClient struct {
@@ -100,6 +181,26 @@ func (c *mCPManagerClient) StopServer(ctx context.Context, in *ServerRequest, op
 	return out, nil
 }
 
+func (c *mCPManagerClient) StartTagged(ctx context.Context, in *TagRequest, opts ...grpc.CallOption) (*ServerList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ServerList)
+	err := c.cc.Invoke(ctx, MCPManager_StartTagged_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPManagerClient) StopTagged(ctx context.Context, in *TagRequest, opts ...grpc.CallOption) (*ServerList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ServerList)
+	err := c.cc.Invoke(ctx, MCPManager_StopTagged_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *mCPManagerClient) GetTools(ctx context.Context, in *ServerRequest, opts ...grpc.CallOption) (*ToolList, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ToolList)
@@ -130,294 +231,1317 @@ func (c *mCPManagerClient) ReloadConfig(ctx context.Context, in *Empty, opts ...
 	return out, nil
 }
 
-func (c *mCPManagerClient) GetConfigPath(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*PathResponse, error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(PathResponse)
-	err := c.cc.Invoke(ctx, MCPManager_GetConfigPath_FullMethodName, in, out, cOpts...)
-	if err != nil {
+func (c *mCPManagerClient) GetConfigPath(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*PathResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PathResponse)
+	err := c.cc.Invoke(ctx, MCPManager_GetConfigPath_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPManagerClient) ValidateConfig(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ConfigValidation, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConfigValidation)
+	err := c.cc.Invoke(ctx, MCPManager_ValidateConfig_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPManagerClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MCPManager_ServiceDesc.Streams[0], MCPManager_Subscribe_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeRequest, Event]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MCPManager_SubscribeClient = grpc.ServerStreamingClient[Event]
+
+func (c *mCPManagerClient) StreamLogs(ctx context.Context, in *LogsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LogLine], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MCPManager_ServiceDesc.Streams[1], MCPManager_StreamLogs_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[LogsRequest, LogLine]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MCPManager_StreamLogsClient = grpc.ServerStreamingClient[LogLine]
+
+func (c *mCPManagerClient) QueryEvents(ctx context.Context, in *EventQuery, opts ...grpc.CallOption) (*EventList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EventList)
+	err := c.cc.Invoke(ctx, MCPManager_QueryEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPManagerClient) GetUptime(ctx context.Context, in *UptimeRequest, opts ...grpc.CallOption) (*UptimeReport, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UptimeReport)
+	err := c.cc.Invoke(ctx, MCPManager_GetUptime_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPManagerClient) Health(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*HealthStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HealthStatus)
+	err := c.cc.Invoke(ctx, MCPManager_Health_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPManagerClient) SetMaintenance(ctx context.Context, in *MaintenanceRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatusResponse)
+	err := c.cc.Invoke(ctx, MCPManager_SetMaintenance_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPManagerClient) Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatusResponse)
+	err := c.cc.Invoke(ctx, MCPManager_Register_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPManagerClient) ListApprovals(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ApprovalList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ApprovalList)
+	err := c.cc.Invoke(ctx, MCPManager_ListApprovals_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPManagerClient) DecideApproval(ctx context.Context, in *ApprovalDecision, opts ...grpc.CallOption) (*StatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatusResponse)
+	err := c.cc.Invoke(ctx, MCPManager_DecideApproval_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPManagerClient) ListSessions(ctx context.Context, in *SessionFilter, opts ...grpc.CallOption) (*SessionList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SessionList)
+	err := c.cc.Invoke(ctx, MCPManager_ListSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPManagerClient) GetSession(ctx context.Context, in *SessionRequest, opts ...grpc.CallOption) (*TranscriptSession, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TranscriptSession)
+	err := c.cc.Invoke(ctx, MCPManager_GetSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPManagerClient) ListPlugins(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*PluginList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PluginList)
+	err := c.cc.Invoke(ctx, MCPManager_ListPlugins_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPManagerClient) CreateFleet(ctx context.Context, in *FleetRequest, opts ...grpc.CallOption) (*Fleet, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Fleet)
+	err := c.cc.Invoke(ctx, MCPManager_CreateFleet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPManagerClient) DestroyFleet(ctx context.Context, in *FleetRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatusResponse)
+	err := c.cc.Invoke(ctx, MCPManager_DestroyFleet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPManagerClient) ListFleets(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*FleetList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FleetList)
+	err := c.cc.Invoke(ctx, MCPManager_ListFleets_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPManagerClient) DrainServer(ctx context.Context, in *DrainRequest, opts ...grpc.CallOption) (*Server, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Server)
+	err := c.cc.Invoke(ctx, MCPManager_DrainServer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPManagerClient) ListProfiles(ctx context.Context, in *ServerRequest, opts ...grpc.CallOption) (*ProfileList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProfileList)
+	err := c.cc.Invoke(ctx, MCPManager_ListProfiles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPManagerClient) ResetProfile(ctx context.Context, in *ProfileRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatusResponse)
+	err := c.cc.Invoke(ctx, MCPManager_ResetProfile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPManagerClient) SnapshotProfile(ctx context.Context, in *ProfileRequest, opts ...grpc.CallOption) (*ProfileSnapshot, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProfileSnapshot)
+	err := c.cc.Invoke(ctx, MCPManager_SnapshotProfile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPManagerClient) RestoreProfile(ctx context.Context, in *ProfileRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatusResponse)
+	err := c.cc.Invoke(ctx, MCPManager_RestoreProfile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPManagerClient) DiskUsage(ctx context.Context, in *DiskRequest, opts ...grpc.CallOption) (*DiskUsageList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DiskUsageList)
+	err := c.cc.Invoke(ctx, MCPManager_DiskUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPManagerClient) CleanCaches(ctx context.Context, in *DiskRequest, opts ...grpc.CallOption) (*DiskUsageList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DiskUsageList)
+	err := c.cc.Invoke(ctx, MCPManager_CleanCaches_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPManagerClient) BackupServer(ctx context.Context, in *ServerRequest, opts ...grpc.CallOption) (*Backup, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Backup)
+	err := c.cc.Invoke(ctx, MCPManager_BackupServer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPManagerClient) ListBackups(ctx context.Context, in *ServerRequest, opts ...grpc.CallOption) (*BackupList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BackupList)
+	err := c.cc.Invoke(ctx, MCPManager_ListBackups_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPManagerClient) RestoreBackup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatusResponse)
+	err := c.cc.Invoke(ctx, MCPManager_RestoreBackup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPManagerClient) ListProviders(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ProviderList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProviderList)
+	err := c.cc.Invoke(ctx, MCPManager_ListProviders_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPManagerClient) PinProvider(ctx context.Context, in *PinRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatusResponse)
+	err := c.cc.Invoke(ctx, MCPManager_PinProvider_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPManagerClient) ListNotifications(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*NotificationList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NotificationList)
+	err := c.cc.Invoke(ctx, MCPManager_ListNotifications_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPManagerClient) SetNotification(ctx context.Context, in *NotificationRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatusResponse)
+	err := c.cc.Invoke(ctx, MCPManager_SetNotification_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPManagerClient) DescribeServer(ctx context.Context, in *ServerRequest, opts ...grpc.CallOption) (*ServerRuntime, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ServerRuntime)
+	err := c.cc.Invoke(ctx, MCPManager_DescribeServer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPManagerClient) CloneServer(ctx context.Context, in *CloneRequest, opts ...grpc.CallOption) (*CloneResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CloneResponse)
+	err := c.cc.Invoke(ctx, MCPManager_CloneServer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MCPManagerServer is the server API for MCPManager service.
+// All implementations must embed UnimplementedMCPManagerServer
+// for forward compatibility.
+type MCPManagerServer interface {
+	// Basic server operations
+	ListServers(context.Context, *Empty) (*ServerList, error)
+	GetServer(context.Context, *ServerRequest) (*Server, error)
+	StartServer(context.Context, *ServerRequest) (*Server, error)
+	StopServer(context.Context, *ServerRequest) (*Server, error)
+	// Operations on all the servers labeled with a tag
+	StartTagged(context.Context, *TagRequest) (*ServerList, error)
+	StopTagged(context.Context, *TagRequest) (*ServerList, error)
+	// Tool information
+	GetTools(context.Context, *ServerRequest) (*ToolList, error)
+	// Configuration
+	GetConfig(context.Context, *Empty) (*Config, error)
+	ReloadConfig(context.Context, *Empty) (*StatusResponse, error)
+	GetConfigPath(context.Context, *Empty) (*PathResponse, error)
+	ValidateConfig(context.Context, *Empty) (*ConfigValidation, error)
+	// Real-time streaming
+	Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[Event]) error
+	StreamLogs(*LogsRequest, grpc.ServerStreamingServer[LogLine]) error
+	// Past events from the daemon's journal
+	QueryEvents(context.Context, *EventQuery) (*EventList, error)
+	// Availability of servers derived from their status changes
+	GetUptime(context.Context, *UptimeRequest) (*UptimeReport, error)
+	// Health check
+	Health(context.Context, *Empty) (*HealthStatus, error)
+	// Maintenance mode suppresses automatic actions
+	SetMaintenance(context.Context, *MaintenanceRequest) (*StatusResponse, error)
+	// Cluster membership, served by coordinators
+	Register(context.Context, *RegisterRequest) (*StatusResponse, error)
+	// Tool calls waiting for an operator's approval
+	ListApprovals(context.Context, *Empty) (*ApprovalList, error)
+	DecideApproval(context.Context, *ApprovalDecision) (*StatusResponse, error)
+	// Proxied calls grouped by client
+	ListSessions(context.Context, *SessionFilter) (*SessionList, error)
+	GetSession(context.Context, *SessionRequest) (*TranscriptSession, error)
+	// Plugins found in the daemon's plugin directory
+	ListPlugins(context.Context, *Empty) (*PluginList, error)
+	// Isolated copies of servers per evaluation run
+	CreateFleet(context.Context, *FleetRequest) (*Fleet, error)
+	DestroyFleet(context.Context, *FleetRequest) (*StatusResponse, error)
+	ListFleets(context.Context, *Empty) (*FleetList, error)
+	// Stops a server once the calls in flight on its proxy finish
+	DrainServer(context.Context, *DrainRequest) (*Server, error)
+	// Browser profiles of browser-automation servers
+	ListProfiles(context.Context, *ServerRequest) (*ProfileList, error)
+	ResetProfile(context.Context, *ProfileRequest) (*StatusResponse, error)
+	SnapshotProfile(context.Context, *ProfileRequest) (*ProfileSnapshot, error)
+	RestoreProfile(context.Context, *ProfileRequest) (*StatusResponse, error)
+	// Disk used by servers and pruning of their caches
+	DiskUsage(context.Context, *DiskRequest) (*DiskUsageList, error)
+	CleanCaches(context.Context, *DiskRequest) (*DiskUsageList, error)
+	// Archives of the data servers keep
+	BackupServer(context.Context, *ServerRequest) (*Backup, error)
+	ListBackups(context.Context, *ServerRequest) (*BackupList, error)
+	RestoreBackup(context.Context, *BackupRequest) (*StatusResponse, error)
+	// Providers of generic gateway tools
+	ListProviders(context.Context, *Empty) (*ProviderList, error)
+	PinProvider(context.Context, *PinRequest) (*StatusResponse, error)
+	// Desktop notifications of server events
+	ListNotifications(context.Context, *Empty) (*NotificationList, error)
+	SetNotification(context.Context, *NotificationRequest) (*StatusResponse, error)
+	// What a server runs as, for debugging its config
+	DescribeServer(context.Context, *ServerRequest) (*ServerRuntime, error)
+	// Copies of servers, e.g. a second filesystem server on another directory
+	CloneServer(context.Context, *CloneRequest) (*CloneResponse, error)
+	mustEmbedUnimplementedMCPManagerServer()
+}
+
+// UnimplementedMCPManagerServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedMCPManagerServer struct{}
+
+func (UnimplementedMCPManagerServer) ListServers(context.Context, *Empty) (*ServerList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListServers not implemented")
+}
+func (UnimplementedMCPManagerServer) GetServer(context.Context, *ServerRequest) (*Server, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetServer not implemented")
+}
+func (UnimplementedMCPManagerServer) StartServer(context.Context, *ServerRequest) (*Server, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartServer not implemented")
+}
+func (UnimplementedMCPManagerServer) StopServer(context.Context, *ServerRequest) (*Server, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopServer not implemented")
+}
+func (UnimplementedMCPManagerServer) StartTagged(context.Context, *TagRequest) (*ServerList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartTagged not implemented")
+}
+func (UnimplementedMCPManagerServer) StopTagged(context.Context, *TagRequest) (*ServerList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopTagged not implemented")
+}
+func (UnimplementedMCPManagerServer) GetTools(context.Context, *ServerRequest) (*ToolList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTools not implemented")
+}
+func (UnimplementedMCPManagerServer) GetConfig(context.Context, *Empty) (*Config, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConfig not implemented")
+}
+func (UnimplementedMCPManagerServer) ReloadConfig(context.Context, *Empty) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReloadConfig not implemented")
+}
+func (UnimplementedMCPManagerServer) GetConfigPath(context.Context, *Empty) (*PathResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConfigPath not implemented")
+}
+func (UnimplementedMCPManagerServer) ValidateConfig(context.Context, *Empty) (*ConfigValidation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateConfig not implemented")
+}
+func (UnimplementedMCPManagerServer) Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[Event]) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedMCPManagerServer) StreamLogs(*LogsRequest, grpc.ServerStreamingServer[LogLine]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamLogs not implemented")
+}
+func (UnimplementedMCPManagerServer) QueryEvents(context.Context, *EventQuery) (*EventList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryEvents not implemented")
+}
+func (UnimplementedMCPManagerServer) GetUptime(context.Context, *UptimeRequest) (*UptimeReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUptime not implemented")
+}
+func (UnimplementedMCPManagerServer) Health(context.Context, *Empty) (*HealthStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Health not implemented")
+}
+func (UnimplementedMCPManagerServer) SetMaintenance(context.Context, *MaintenanceRequest) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetMaintenance not implemented")
+}
+func (UnimplementedMCPManagerServer) Register(context.Context, *RegisterRequest) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Register not implemented")
+}
+func (UnimplementedMCPManagerServer) ListApprovals(context.Context, *Empty) (*ApprovalList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListApprovals not implemented")
+}
+func (UnimplementedMCPManagerServer) DecideApproval(context.Context, *ApprovalDecision) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DecideApproval not implemented")
+}
+func (UnimplementedMCPManagerServer) ListSessions(context.Context, *SessionFilter) (*SessionList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSessions not implemented")
+}
+func (UnimplementedMCPManagerServer) GetSession(context.Context, *SessionRequest) (*TranscriptSession, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSession not implemented")
+}
+func (UnimplementedMCPManagerServer) ListPlugins(context.Context, *Empty) (*PluginList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPlugins not implemented")
+}
+func (UnimplementedMCPManagerServer) CreateFleet(context.Context, *FleetRequest) (*Fleet, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateFleet not implemented")
+}
+func (UnimplementedMCPManagerServer) DestroyFleet(context.Context, *FleetRequest) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DestroyFleet not implemented")
+}
+func (UnimplementedMCPManagerServer) ListFleets(context.Context, *Empty) (*FleetList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFleets not implemented")
+}
+func (UnimplementedMCPManagerServer) DrainServer(context.Context, *DrainRequest) (*Server, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DrainServer not implemented")
+}
+func (UnimplementedMCPManagerServer) ListProfiles(context.Context, *ServerRequest) (*ProfileList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProfiles not implemented")
+}
+func (UnimplementedMCPManagerServer) ResetProfile(context.Context, *ProfileRequest) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetProfile not implemented")
+}
+func (UnimplementedMCPManagerServer) SnapshotProfile(context.Context, *ProfileRequest) (*ProfileSnapshot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SnapshotProfile not implemented")
+}
+func (UnimplementedMCPManagerServer) RestoreProfile(context.Context, *ProfileRequest) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreProfile not implemented")
+}
+func (UnimplementedMCPManagerServer) DiskUsage(context.Context, *DiskRequest) (*DiskUsageList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiskUsage not implemented")
+}
+func (UnimplementedMCPManagerServer) CleanCaches(context.Context, *DiskRequest) (*DiskUsageList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CleanCaches not implemented")
+}
+func (UnimplementedMCPManagerServer) BackupServer(context.Context, *ServerRequest) (*Backup, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BackupServer not implemented")
+}
+func (UnimplementedMCPManagerServer) ListBackups(context.Context, *ServerRequest) (*BackupList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBackups not implemented")
+}
+func (UnimplementedMCPManagerServer) RestoreBackup(context.Context, *BackupRequest) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreBackup not implemented")
+}
+func (UnimplementedMCPManagerServer) ListProviders(context.Context, *Empty) (*ProviderList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProviders not implemented")
+}
+func (UnimplementedMCPManagerServer) PinProvider(context.Context, *PinRequest) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PinProvider not implemented")
+}
+func (UnimplementedMCPManagerServer) ListNotifications(context.Context, *Empty) (*NotificationList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListNotifications not implemented")
+}
+func (UnimplementedMCPManagerServer) SetNotification(context.Context, *NotificationRequest) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetNotification not implemented")
+}
+func (UnimplementedMCPManagerServer) DescribeServer(context.Context, *ServerRequest) (*ServerRuntime, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DescribeServer not implemented")
+}
+func (UnimplementedMCPManagerServer) CloneServer(context.Context, *CloneRequest) (*CloneResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloneServer not implemented")
+}
+func (UnimplementedMCPManagerServer) mustEmbedUnimplementedMCPManagerServer() {}
+func (UnimplementedMCPManagerServer) testEmbeddedByValue()                    {}
+
+// UnsafeMCPManagerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MCPManagerServer will
+// result in compilation errors.
+type UnsafeMCPManagerServer interface {
+	mustEmbedUnimplementedMCPManagerServer()
+}
+
+func RegisterMCPManagerServer(s grpc.ServiceRegistrar, srv MCPManagerServer) {
+	// If the following call pancis, it indicates UnimplementedMCPManagerServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&MCPManager_ServiceDesc, srv)
+}
+
+func _MCPManager_ListServers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPManagerServer).ListServers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPManager_ListServers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPManagerServer).ListServers(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPManager_GetServer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ServerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPManagerServer).GetServer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPManager_GetServer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPManagerServer).GetServer(ctx, req.(*ServerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPManager_StartServer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ServerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPManagerServer).StartServer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPManager_StartServer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPManagerServer).StartServer(ctx, req.(*ServerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPManager_StopServer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ServerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPManagerServer).StopServer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPManager_StopServer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPManagerServer).StopServer(ctx, req.(*ServerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPManager_StartTagged_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPManagerServer).StartTagged(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPManager_StartTagged_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPManagerServer).StartTagged(ctx, req.(*TagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPManager_StopTagged_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPManagerServer).StopTagged(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPManager_StopTagged_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPManagerServer).StopTagged(ctx, req.(*TagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPManager_GetTools_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ServerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPManagerServer).GetTools(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPManager_GetTools_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPManagerServer).GetTools(ctx, req.(*ServerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPManager_GetConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPManagerServer).GetConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPManager_GetConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPManagerServer).GetConfig(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPManager_ReloadConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPManagerServer).ReloadConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPManager_ReloadConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPManagerServer).ReloadConfig(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPManager_GetConfigPath_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPManagerServer).GetConfigPath(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPManager_GetConfigPath_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPManagerServer).GetConfigPath(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPManager_ValidateConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPManagerServer).ValidateConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPManager_ValidateConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPManagerServer).ValidateConfig(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPManager_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MCPManagerServer).Subscribe(m, &grpc.GenericServerStream[SubscribeRequest, Event]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MCPManager_SubscribeServer = grpc.ServerStreamingServer[Event]
+
+func _MCPManager_StreamLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(LogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MCPManagerServer).StreamLogs(m, &grpc.GenericServerStream[LogsRequest, LogLine]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MCPManager_StreamLogsServer = grpc.ServerStreamingServer[LogLine]
+
+func _MCPManager_QueryEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EventQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPManagerServer).QueryEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPManager_QueryEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPManagerServer).QueryEvents(ctx, req.(*EventQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPManager_GetUptime_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UptimeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPManagerServer).GetUptime(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPManager_GetUptime_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPManagerServer).GetUptime(ctx, req.(*UptimeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPManager_Health_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPManagerServer).Health(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPManager_Health_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPManagerServer).Health(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPManager_SetMaintenance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MaintenanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPManagerServer).SetMaintenance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPManager_SetMaintenance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPManagerServer).SetMaintenance(ctx, req.(*MaintenanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPManager_Register_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPManagerServer).Register(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPManager_Register_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPManagerServer).Register(ctx, req.(*RegisterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPManager_ListApprovals_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPManagerServer).ListApprovals(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPManager_ListApprovals_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPManagerServer).ListApprovals(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPManager_DecideApproval_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApprovalDecision)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPManagerServer).DecideApproval(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPManager_DecideApproval_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPManagerServer).DecideApproval(ctx, req.(*ApprovalDecision))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPManager_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SessionFilter)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPManagerServer).ListSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPManager_ListSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPManagerServer).ListSessions(ctx, req.(*SessionFilter))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPManager_GetSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPManagerServer).GetSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPManager_GetSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPManagerServer).GetSession(ctx, req.(*SessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPManager_ListPlugins_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPManagerServer).ListPlugins(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPManager_ListPlugins_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPManagerServer).ListPlugins(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPManager_CreateFleet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FleetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPManagerServer).CreateFleet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPManager_CreateFleet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPManagerServer).CreateFleet(ctx, req.(*FleetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPManager_DestroyFleet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FleetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPManagerServer).DestroyFleet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPManager_DestroyFleet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPManagerServer).DestroyFleet(ctx, req.(*FleetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPManager_ListFleets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPManagerServer).ListFleets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPManager_ListFleets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPManagerServer).ListFleets(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPManager_DrainServer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DrainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPManagerServer).DrainServer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPManager_DrainServer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPManagerServer).DrainServer(ctx, req.(*DrainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPManager_ListProfiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ServerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPManagerServer).ListProfiles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPManager_ListProfiles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPManagerServer).ListProfiles(ctx, req.(*ServerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPManager_ResetProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPManagerServer).ResetProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPManager_ResetProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPManagerServer).ResetProfile(ctx, req.(*ProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPManager_SnapshotProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProfileRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(MCPManagerServer).SnapshotProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPManager_SnapshotProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPManagerServer).SnapshotProfile(ctx, req.(*ProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *mCPManagerClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &MCPManager_ServiceDesc.Streams[0], MCPManager_Subscribe_FullMethodName, cOpts...)
-	if err != nil {
+func _MCPManager_RestoreProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProfileRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &grpc.GenericClientStream[SubscribeRequest, Event]{ClientStream: stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(MCPManagerServer).RestoreProfile(ctx, in)
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPManager_RestoreProfile_FullMethodName,
 	}
-	return x, nil
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPManagerServer).RestoreProfile(ctx, req.(*ProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type MCPManager_SubscribeClient = grpc.ServerStreamingClient[Event]
-
-func (c *mCPManagerClient) Health(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*HealthStatus, error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(HealthStatus)
-	err := c.cc.Invoke(ctx, MCPManager_Health_FullMethodName, in, out, cOpts...)
-	if err != nil {
+func _MCPManager_DiskUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiskRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
-}
-
-// MCPManagerServer is the server API for MCPManager service.
-// All implementations must embed UnimplementedMCPManagerServer
-// for forward compatibility.
-type MCPManagerServer interface {
-	// Basic server operations
-	ListServers(context.Context, *Empty) (*ServerList, error)
-	GetServer(context.Context, *ServerRequest) (*Server, error)
-	StartServer(context.Context, *ServerRequest) (*Server, error)
-	StopServer(context.Context, *ServerRequest) (*Server, error)
-	// Tool information
-	GetTools(context.Context, *ServerRequest) (*ToolList, error)
-	// Configuration
-	GetConfig(context.Context, *Empty) (*Config, error)
-	ReloadConfig(context.Context, *Empty) (*StatusResponse, error)
-	GetConfigPath(context.Context, *Empty) (*PathResponse, error)
-	// Real-time streaming
-	Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[Event]) error
-	// Health check
-	Health(context.Context, *Empty) (*HealthStatus, error)
-	mustEmbedUnimplementedMCPManagerServer()
-}
-
-// UnimplementedMCPManagerServer must be embedded to have
-// forward compatible implementations.
-//
-// NOTE: this should be embedded by value instead of pointer to avoid a nil
-// pointer dereference when methods are called.
-type UnimplementedMCPManagerServer struct{}
-
-func (UnimplementedMCPManagerServer) ListServers(context.Context, *Empty) (*ServerList, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListServers not implemented")
-}
-func (UnimplementedMCPManagerServer) GetServer(context.Context, *ServerRequest) (*Server, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetServer not implemented")
-}
-func (UnimplementedMCPManagerServer) StartServer(context.Context, *ServerRequest) (*Server, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method StartServer not implemented")
-}
-func (UnimplementedMCPManagerServer) StopServer(context.Context, *ServerRequest) (*Server, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method StopServer not implemented")
-}
-func (UnimplementedMCPManagerServer) GetTools(context.Context, *ServerRequest) (*ToolList, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetTools not implemented")
-}
-func (UnimplementedMCPManagerServer) GetConfig(context.Context, *Empty) (*Config, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetConfig not implemented")
-}
-func (UnimplementedMCPManagerServer) ReloadConfig(context.Context, *Empty) (*StatusResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ReloadConfig not implemented")
-}
-func (UnimplementedMCPManagerServer) GetConfigPath(context.Context, *Empty) (*PathResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetConfigPath not implemented")
-}
-func (UnimplementedMCPManagerServer) Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[Event]) error {
-	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
-}
-func (UnimplementedMCPManagerServer) Health(context.Context, *Empty) (*HealthStatus, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Health not implemented")
-}
-func (UnimplementedMCPManagerServer) mustEmbedUnimplementedMCPManagerServer() {}
-func (UnimplementedMCPManagerServer) testEmbeddedByValue()                    {}
-
-// UnsafeMCPManagerServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to MCPManagerServer will
-// result in compilation errors.
-type UnsafeMCPManagerServer interface {
-	mustEmbedUnimplementedMCPManagerServer()
-}
-
-func RegisterMCPManagerServer(s grpc.ServiceRegistrar, srv MCPManagerServer) {
-	// If the following call pancis, it indicates UnimplementedMCPManagerServer was
-	// embedded by pointer and is nil.  This will cause panics if an
-	// unimplemented method is ever invoked, so we test this at initialization
-	// time to prevent it from happening at runtime later due to I/O.
-	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
-		t.testEmbeddedByValue()
+	if interceptor == nil {
+		return srv.(MCPManagerServer).DiskUsage(ctx, in)
 	}
-	s.RegisterService(&MCPManager_ServiceDesc, srv)
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPManager_DiskUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPManagerServer).DiskUsage(ctx, req.(*DiskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _MCPManager_ListServers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
+func _MCPManager_CleanCaches_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiskRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MCPManagerServer).ListServers(ctx, in)
+		return srv.(MCPManagerServer).CleanCaches(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MCPManager_ListServers_FullMethodName,
+		FullMethod: MCPManager_CleanCaches_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MCPManagerServer).ListServers(ctx, req.(*Empty))
+		return srv.(MCPManagerServer).CleanCaches(ctx, req.(*DiskRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MCPManager_GetServer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _MCPManager_BackupServer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ServerRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MCPManagerServer).GetServer(ctx, in)
+		return srv.(MCPManagerServer).BackupServer(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MCPManager_GetServer_FullMethodName,
+		FullMethod: MCPManager_BackupServer_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MCPManagerServer).GetServer(ctx, req.(*ServerRequest))
+		return srv.(MCPManagerServer).BackupServer(ctx, req.(*ServerRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MCPManager_StartServer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _MCPManager_ListBackups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ServerRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MCPManagerServer).StartServer(ctx, in)
+		return srv.(MCPManagerServer).ListBackups(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MCPManager_StartServer_FullMethodName,
+		FullMethod: MCPManager_ListBackups_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MCPManagerServer).StartServer(ctx, req.(*ServerRequest))
+		return srv.(MCPManagerServer).ListBackups(ctx, req.(*ServerRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MCPManager_StopServer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ServerRequest)
+func _MCPManager_RestoreBackup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BackupRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MCPManagerServer).StopServer(ctx, in)
+		return srv.(MCPManagerServer).RestoreBackup(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MCPManager_StopServer_FullMethodName,
+		FullMethod: MCPManager_RestoreBackup_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MCPManagerServer).StopServer(ctx, req.(*ServerRequest))
+		return srv.(MCPManagerServer).RestoreBackup(ctx, req.(*BackupRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MCPManager_GetTools_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ServerRequest)
+func _MCPManager_ListProviders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MCPManagerServer).GetTools(ctx, in)
+		return srv.(MCPManagerServer).ListProviders(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MCPManager_GetTools_FullMethodName,
+		FullMethod: MCPManager_ListProviders_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MCPManagerServer).GetTools(ctx, req.(*ServerRequest))
+		return srv.(MCPManagerServer).ListProviders(ctx, req.(*Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MCPManager_GetConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
+func _MCPManager_PinProvider_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PinRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MCPManagerServer).GetConfig(ctx, in)
+		return srv.(MCPManagerServer).PinProvider(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MCPManager_GetConfig_FullMethodName,
+		FullMethod: MCPManager_PinProvider_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MCPManagerServer).GetConfig(ctx, req.(*Empty))
+		return srv.(MCPManagerServer).PinProvider(ctx, req.(*PinRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MCPManager_ReloadConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _MCPManager_ListNotifications_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MCPManagerServer).ReloadConfig(ctx, in)
+		return srv.(MCPManagerServer).ListNotifications(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MCPManager_ReloadConfig_FullMethodName,
+		FullMethod: MCPManager_ListNotifications_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MCPManagerServer).ReloadConfig(ctx, req.(*Empty))
+		return srv.(MCPManagerServer).ListNotifications(ctx, req.(*Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MCPManager_GetConfigPath_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
+func _MCPManager_SetNotification_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NotificationRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MCPManagerServer).GetConfigPath(ctx, in)
+		return srv.(MCPManagerServer).SetNotification(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MCPManager_GetConfigPath_FullMethodName,
+		FullMethod: MCPManager_SetNotification_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MCPManagerServer).GetConfigPath(ctx, req.(*Empty))
+		return srv.(MCPManagerServer).SetNotification(ctx, req.(*NotificationRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MCPManager_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(SubscribeRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _MCPManager_DescribeServer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ServerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(MCPManagerServer).Subscribe(m, &grpc.GenericServerStream[SubscribeRequest, Event]{ServerStream: stream})
+	if interceptor == nil {
+		return srv.(MCPManagerServer).DescribeServer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPManager_DescribeServer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPManagerServer).DescribeServer(ctx, req.(*ServerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type MCPManager_SubscribeServer = grpc.ServerStreamingServer[Event]
-
-func _MCPManager_Health_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
+func _MCPManager_CloneServer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloneRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MCPManagerServer).Health(ctx, in)
+		return srv.(MCPManagerServer).CloneServer(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MCPManager_Health_FullMethodName,
+		FullMethod: MCPManager_CloneServer_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MCPManagerServer).Health(ctx, req.(*Empty))
+		return srv.(MCPManagerServer).CloneServer(ctx, req.(*CloneRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -445,6 +1569,14 @@ var MCPManager_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "StopServer",
 			Handler:    _MCPManager_StopServer_Handler,
 		},
+		{
+			MethodName: "StartTagged",
+			Handler:    _MCPManager_StartTagged_Handler,
+		},
+		{
+			MethodName: "StopTagged",
+			Handler:    _MCPManager_StopTagged_Handler,
+		},
 		{
 			MethodName: "GetTools",
 			Handler:    _MCPManager_GetTools_Handler,
@@ -461,10 +1593,126 @@ var MCPManager_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetConfigPath",
 			Handler:    _MCPManager_GetConfigPath_Handler,
 		},
+		{
+			MethodName: "ValidateConfig",
+			Handler:    _MCPManager_ValidateConfig_Handler,
+		},
+		{
+			MethodName: "QueryEvents",
+			Handler:    _MCPManager_QueryEvents_Handler,
+		},
+		{
+			MethodName: "GetUptime",
+			Handler:    _MCPManager_GetUptime_Handler,
+		},
 		{
 			MethodName: "Health",
 			Handler:    _MCPManager_Health_Handler,
 		},
+		{
+			MethodName: "SetMaintenance",
+			Handler:    _MCPManager_SetMaintenance_Handler,
+		},
+		{
+			MethodName: "Register",
+			Handler:    _MCPManager_Register_Handler,
+		},
+		{
+			MethodName: "ListApprovals",
+			Handler:    _MCPManager_ListApprovals_Handler,
+		},
+		{
+			MethodName: "DecideApproval",
+			Handler:    _MCPManager_DecideApproval_Handler,
+		},
+		{
+			MethodName: "ListSessions",
+			Handler:    _MCPManager_ListSessions_Handler,
+		},
+		{
+			MethodName: "GetSession",
+			Handler:    _MCPManager_GetSession_Handler,
+		},
+		{
+			MethodName: "ListPlugins",
+			Handler:    _MCPManager_ListPlugins_Handler,
+		},
+		{
+			MethodName: "CreateFleet",
+			Handler:    _MCPManager_CreateFleet_Handler,
+		},
+		{
+			MethodName: "DestroyFleet",
+			Handler:    _MCPManager_DestroyFleet_Handler,
+		},
+		{
+			MethodName: "ListFleets",
+			Handler:    _MCPManager_ListFleets_Handler,
+		},
+		{
+			MethodName: "DrainServer",
+			Handler:    _MCPManager_DrainServer_Handler,
+		},
+		{
+			MethodName: "ListProfiles",
+			Handler:    _MCPManager_ListProfiles_Handler,
+		},
+		{
+			MethodName: "ResetProfile",
+			Handler:    _MCPManager_ResetProfile_Handler,
+		},
+		{
+			MethodName: "SnapshotProfile",
+			Handler:    _MCPManager_SnapshotProfile_Handler,
+		},
+		{
+			MethodName: "RestoreProfile",
+			Handler:    _MCPManager_RestoreProfile_Handler,
+		},
+		{
+			MethodName: "DiskUsage",
+			Handler:    _MCPManager_DiskUsage_Handler,
+		},
+		{
+			MethodName: "CleanCaches",
+			Handler:    _MCPManager_CleanCaches_Handler,
+		},
+		{
+			MethodName: "BackupServer",
+			Handler:    _MCPManager_BackupServer_Handler,
+		},
+		{
+			MethodName: "ListBackups",
+			Handler:    _MCPManager_ListBackups_Handler,
+		},
+		{
+			MethodName: "RestoreBackup",
+			Handler:    _MCPManager_RestoreBackup_Handler,
+		},
+		{
+			MethodName: "ListProviders",
+			Handler:    _MCPManager_ListProviders_Handler,
+		},
+		{
+			MethodName: "PinProvider",
+			Handler:    _MCPManager_PinProvider_Handler,
+		},
+		{
+			MethodName: "ListNotifications",
+			Handler:    _MCPManager_ListNotifications_Handler,
+		},
+		{
+			MethodName: "SetNotification",
+			Handler:    _MCPManager_SetNotification_Handler,
+		},
+		{
+			MethodName: "DescribeServer",
+			Handler:    _MCPManager_DescribeServer_Handler,
+		},
+		{
+			MethodName: "CloneServer",
+			Handler:    _MCPManager_CloneServer_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -472,6 +1720,11 @@ var MCPManager_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _MCPManager_Subscribe_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "StreamLogs",
+			Handler:       _MCPManager_StreamLogs_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "mcp.proto",
 }
@@ -0,0 +1,69 @@
+package grpc
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// pinnedHostAlias is the name ssh checks a pinned host key under, so the
+// user's known_hosts entries for the host don't count
+const pinnedHostAlias = "mcp-manager-pinned-daemon"
+
+// pinTLS returns tlsConfig accepting only the certificate with the SHA-256
+// fingerprint, in hex with or without colons. Without CAs to verify the
+// daemon, the pin is trusted instead.
+func pinTLS(tlsConfig *tls.Config, fingerprint string) (*tls.Config, error) {
+	want, err := hex.DecodeString(strings.ReplaceAll(fingerprint, ":", ""))
+	if err != nil || len(want) != sha256.Size {
+		return nil, fmt.Errorf("invalid certSHA256 '%s'", fingerprint)
+	}
+
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	} else {
+		tlsConfig = tlsConfig.Clone()
+	}
+	if tlsConfig.RootCAs == nil {
+		tlsConfig.InsecureSkipVerify = true
+	}
+	tlsConfig.VerifyConnection = func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return fmt.Errorf("daemon presented no certificate")
+		}
+		got := sha256.Sum256(cs.PeerCertificates[0].Raw)
+		if !bytes.Equal(got[:], want) {
+			return fmt.Errorf("daemon certificate %x doesn't match the pinned %x", got, want)
+		}
+		return nil
+	}
+	return tlsConfig, nil
+}
+
+// writeKnownHosts writes a known_hosts file holding only hostKey, as a
+// type and base64 key optionally preceded by host names like ssh-keyscan
+// prints, returning its path
+func writeKnownHosts(hostKey string) (string, error) {
+	fields := strings.Fields(hostKey)
+	if len(fields) == 3 {
+		fields = fields[1:]
+	}
+	if len(fields) != 2 {
+		return "", fmt.Errorf("invalid hostKey '%s', expected e.g. 'ssh-ed25519 AAAAC3...'", hostKey)
+	}
+
+	f, err := os.CreateTemp("", "mcp-manager-known-hosts-")
+	if err != nil {
+		return "", fmt.Errorf("failed to pin host key: %w", err)
+	}
+	defer f.Close()
+	if _, err := fmt.Fprintf(f, "%s %s %s\n", pinnedHostAlias, fields[0], fields[1]); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to pin host key: %w", err)
+	}
+	return f.Name(), nil
+}
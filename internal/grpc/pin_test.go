@@ -0,0 +1,116 @@
+package grpc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/apitest"
+	"github.com/tartavull/mcp-manager/internal/auth"
+	"github.com/tartavull/mcp-manager/internal/config"
+	pb "github.com/tartavull/mcp-manager/internal/grpc/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// serveSelfSigned serves a manager over TLS with a new self-signed
+// certificate, returning its address and the certificate's fingerprint
+func serveSelfSigned(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "daemon"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	grpcServer := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	})))
+	pb.RegisterMCPManagerServer(grpcServer, NewServer(apitest.NewManager()))
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go grpcServer.Serve(lis)
+	t.Cleanup(grpcServer.Stop)
+
+	return lis.Addr().String(), fmt.Sprintf("%x", sha256.Sum256(der))
+}
+
+func TestClientOptions_PinnedCert(t *testing.T) {
+	address, fingerprint := serveSelfSigned(t)
+
+	// The pin is trusted without a CA, in either notation
+	client, err := NewClientWithOptions(address, ClientOptions{Pin: &config.DaemonPinConfig{CertSHA256: fingerprint}})
+	require.NoError(t, err)
+	client.Close()
+	var colons []string
+	for i := 0; i < len(fingerprint); i += 2 {
+		colons = append(colons, strings.ToUpper(fingerprint[i:i+2]))
+	}
+	client, err = NewClientWithOptions(address, ClientOptions{Pin: &config.DaemonPinConfig{CertSHA256: strings.Join(colons, ":")}})
+	require.NoError(t, err)
+	client.Close()
+
+	_, err = NewClientWithOptions(address, ClientOptions{Pin: &config.DaemonPinConfig{CertSHA256: strings.Repeat("00", 32)}})
+	assert.ErrorContains(t, err, "doesn't match the pinned")
+
+	_, err = NewClientWithOptions(address, ClientOptions{Pin: &config.DaemonPinConfig{CertSHA256: "abc"}})
+	assert.EqualError(t, err, "invalid certSHA256 'abc'")
+
+	// Without the pin the self-signed certificate isn't trusted
+	_, err = NewClientWithOptions(address, ClientOptions{TLS: &tls.Config{MinVersion: tls.VersionTLS12}})
+	assert.Error(t, err)
+}
+
+func TestClientOptions_PinnedHostKey(t *testing.T) {
+	t.Setenv(auth.TokenEnv, "")
+	mgr := apitest.NewManager()
+	grpcServer := grpc.NewServer()
+	pb.RegisterMCPManagerServer(grpcServer, NewServer(mgr))
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go grpcServer.Serve(lis)
+	defer grpcServer.Stop()
+
+	argsFile := t.TempDir() + "/args"
+	t.Setenv(sshHelperEnv, argsFile)
+	defer func(command string) { SSHCommand = command }(SSHCommand)
+	SSHCommand = os.Args[0]
+
+	// ssh checks the pinned key alone, ignoring known_hosts
+	pin := &config.DaemonPinConfig{HostKey: "box ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIHost"}
+	client, err := NewClientWithOptions("ssh://me@box/"+lis.Addr().String(), ClientOptions{Pin: pin})
+	require.NoError(t, err)
+	args, err := os.ReadFile(argsFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(args), "-o StrictHostKeyChecking=yes -o HostKeyAlias="+pinnedHostAlias)
+	assert.Contains(t, string(args), "-o GlobalKnownHostsFile="+os.DevNull)
+
+	knownHosts := strings.TrimPrefix(strings.Fields(string(args)[strings.Index(string(args), "UserKnownHostsFile="):])[0], "UserKnownHostsFile=")
+	data, err := os.ReadFile(knownHosts)
+	require.NoError(t, err)
+	assert.Equal(t, pinnedHostAlias+" ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIHost\n", string(data))
+	client.Close()
+	assert.NoFileExists(t, knownHosts)
+
+	_, err = NewClientWithOptions(lis.Addr().String(), ClientOptions{Pin: pin})
+	assert.EqualError(t, err, "hostKey pins ssh:// daemons only")
+	_, err = NewClientWithOptions("ssh://me@box/"+lis.Addr().String(), ClientOptions{Pin: &config.DaemonPinConfig{HostKey: "AAAA"}})
+	assert.ErrorContains(t, err, "invalid hostKey")
+}
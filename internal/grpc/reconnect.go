@@ -0,0 +1,170 @@
+package grpc
+
+import (
+	"log"
+	"math/rand/v2"
+	"time"
+
+	pb "github.com/tartavull/mcp-manager/internal/grpc/pb"
+	"github.com/tartavull/mcp-manager/internal/grpc/types"
+)
+
+// States of a client's event stream, see package types
+type (
+	ConnectionState = types.ConnectionState
+	Retry           = types.Retry
+)
+
+const (
+	StateConnected    = types.StateConnected
+	StateReconnecting = types.StateReconnecting
+	StateDisconnected = types.StateDisconnected
+)
+
+// Backoff configures how a lost event stream is resubscribed
+type Backoff struct {
+	Initial time.Duration // Delay before the first attempt
+	Max     time.Duration // Cap on the delay, doubled after each attempt
+	Window  time.Duration // Time after which reconnecting gives up
+}
+
+// DefaultBackoff retries quickly at first, then every 30s for 10 minutes
+var DefaultBackoff = Backoff{
+	Initial: 500 * time.Millisecond,
+	Max:     30 * time.Second,
+	Window:  10 * time.Minute,
+}
+
+// delay returns the jittered delay before an attempt: a random duration
+// between half and all of the exponential delay, so clients of a restarted
+// daemon don't reconnect in lockstep
+func (b Backoff) delay(attempt int) time.Duration {
+	delay := b.Initial
+	for i := 0; i < attempt && delay < b.Max; i++ {
+		delay *= 2
+	}
+	delay = min(delay, b.Max)
+
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+// SetOnConnectionState sets the callback for changes of the event stream's
+// connection state
+func (c *Client) SetOnConnectionState(callback func(ConnectionState)) {
+	c.callbackMu.Lock()
+	c.onConnectionState = callback
+	c.callbackMu.Unlock()
+}
+
+// State returns the connection state of the event stream
+func (c *Client) State() ConnectionState {
+	c.callbackMu.RLock()
+	defer c.callbackMu.RUnlock()
+	return c.state
+}
+
+// Retry returns the attempts to resubscribe the event stream, zero while
+// it's connected
+func (c *Client) Retry() Retry {
+	c.eventMu.Lock()
+	defer c.eventMu.Unlock()
+	return Retry{Attempt: c.retryAttempt, Next: c.retryNext}
+}
+
+// Reconnect makes the next attempt to resubscribe a lost event stream now,
+// or starts attempting again after giving up
+func (c *Client) Reconnect() {
+	switch c.State() {
+	case StateReconnecting:
+		select {
+		case c.retryNow <- struct{}{}:
+		default:
+		}
+	case StateDisconnected:
+		c.eventMu.Lock()
+		c.retryStart, c.retryAttempt = time.Time{}, 0
+		failed := c.eventStream
+		c.eventMu.Unlock()
+		go c.reconnect(failed)
+	}
+}
+
+// setState records a connection state, notifying the callback of changes
+func (c *Client) setState(state ConnectionState) {
+	c.callbackMu.Lock()
+	changed := c.state != state
+	c.state = state
+	callback := c.onConnectionState
+	c.callbackMu.Unlock()
+
+	if changed && callback != nil {
+		callback(state)
+	}
+}
+
+// reconnect resubscribes a failed stream with its event filters, backing
+// off between attempts, until it succeeds, the stream is replaced or the
+// client closed, or the retry window passes. Attempts count from the last
+// stream that received an event, so a daemon accepting streams and dropping
+// them right away doesn't reset the window.
+func (c *Client) reconnect(failed pb.MCPManager_SubscribeClient) {
+	c.setState(StateReconnecting)
+
+	c.eventMu.Lock()
+	if c.retryStart.IsZero() {
+		c.retryStart = time.Now()
+	}
+	deadline := c.retryStart.Add(c.backoff.Window)
+	c.eventMu.Unlock()
+
+	for {
+		c.eventMu.Lock()
+		attempt := c.retryAttempt
+		c.retryAttempt++
+		c.eventMu.Unlock()
+
+		delay := c.backoff.delay(attempt)
+		if time.Now().Add(delay).After(deadline) {
+			log.Printf("Giving up reconnecting to the daemon after %s", c.backoff.Window)
+			c.setState(StateDisconnected)
+			return
+		}
+
+		c.eventMu.Lock()
+		c.retryNext = time.Now().Add(delay)
+		c.eventMu.Unlock()
+
+		select {
+		case <-time.After(delay):
+		case <-c.retryNow:
+		case <-c.done:
+			return
+		}
+
+		c.eventMu.Lock()
+		c.retryNext = time.Time{}
+		if c.eventStream != failed {
+			c.eventMu.Unlock()
+			return
+		}
+		err := c.subscribe(c.eventTypes)
+		c.eventMu.Unlock()
+
+		if err == nil {
+			log.Printf("Resubscribed to daemon events (attempt %d)", attempt+1)
+			return
+		}
+		log.Printf("Failed to reconnect: %v", err)
+	}
+}
+
+// resetRetries marks stream as working once it receives an event
+func (c *Client) resetRetries(stream pb.MCPManager_SubscribeClient) {
+	c.eventMu.Lock()
+	if c.eventStream == stream {
+		c.retryStart = time.Time{}
+		c.retryAttempt = 0
+	}
+	c.eventMu.Unlock()
+}
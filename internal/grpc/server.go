@@ -2,41 +2,128 @@ package grpc
 
 import (
 	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"hash/fnv"
 	"log"
 	"net"
+	"net/http"
+	"slices"
+	"strconv"
 	"sync"
 	"time"
 
+	"github.com/tartavull/mcp-manager/internal/auth"
 	pb "github.com/tartavull/mcp-manager/internal/grpc/pb"
+	"github.com/tartavull/mcp-manager/internal/journal"
+	"github.com/tartavull/mcp-manager/internal/logs"
+	"github.com/tartavull/mcp-manager/internal/metrics"
 	"github.com/tartavull/mcp-manager/internal/server"
+	"github.com/tartavull/mcp-manager/internal/transcript"
+	"github.com/tartavull/mcp-manager/internal/uptime"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/status"
 )
 
+const (
+	// HeartbeatInterval is how often the daemon sends a heartbeat on each
+	// event stream
+	HeartbeatInterval = 5 * time.Second
+
+	// HeartbeatTimeout is how long clients wait for a heartbeat before
+	// considering the stream dead
+	HeartbeatTimeout = 3 * HeartbeatInterval
+
+	// sendTimeout is how long a subscriber may take to accept an event
+	// before it is considered dead, e.g. over a half-open connection
+	sendTimeout = 5 * time.Second
+
+	// broadcastQueueSize is how many of the monitor's events each
+	// broadcasting worker holds before the monitor waits
+	broadcastQueueSize = 100
+)
+
+// MonitorOptions tunes how the server detects the status and tool changes
+// it broadcasts
+type MonitorOptions struct {
+	Interval     time.Duration // Between status checks
+	ToolInterval time.Duration // Between refreshes of the servers' tools
+	Workers      int           // Broadcasts running at once
+}
+
+// DefaultMonitorOptions are the monitor's settings by default
+var DefaultMonitorOptions = MonitorOptions{
+	Interval:     2 * time.Second,
+	ToolInterval: 30 * time.Second,
+	Workers:      4,
+}
+
+// withDefaults returns the options with the zero ones set to their default
+func (o MonitorOptions) withDefaults() MonitorOptions {
+	if o.Interval <= 0 {
+		o.Interval = DefaultMonitorOptions.Interval
+	}
+	if o.ToolInterval <= 0 {
+		o.ToolInterval = DefaultMonitorOptions.ToolInterval
+	}
+	if o.Workers <= 0 {
+		o.Workers = DefaultMonitorOptions.Workers
+	}
+	return o
+}
+
 // Server implements the gRPC MCPManager service
 type Server struct {
 	pb.UnimplementedMCPManagerServer
-	manager   ManagerInterface
-	startTime time.Time
+	manager           ManagerInterface
+	startTime         time.Time
+	heartbeatInterval time.Duration
 
 	// Event broadcasting
 	subscribersMu sync.RWMutex
 	subscribers   map[string]chan *pb.Event
+	exporter      EventExporter    // Nil unless events go to a message bus
+	journal       *journal.Journal // Nil when the journal is disabled
+	validator     ConfigValidator  // Nil when configs can't be validated
+	providers     ProviderSelector // Nil without a gateway
+	notifier      Notifier         // Nil unless desktop notifications are shown
+	uptime        *uptime.Tracker  // Nil when availability isn't tracked
+
+	// queues are the depths of the manager's event channels, by name
+	queues map[string]func() int
 
 	// Status tracking for change detection
 	statusMu   sync.RWMutex
 	lastStatus map[string]server.Status
+	lastTools  map[string]uint64 // Hash of the tools last broadcast, by server
+
+	monitor    MonitorOptions
+	broadcasts []chan func() // Queues of the broadcasting workers
 }
 
 // NewServer creates a new gRPC server
 func NewServer(mgr ManagerInterface) *Server {
+	return newServer(mgr, DefaultMonitorOptions)
+}
+
+// newServer creates a new gRPC server whose monitor runs with opts
+func newServer(mgr ManagerInterface, opts MonitorOptions) *Server {
 	s := &Server{
-		manager:     mgr,
-		startTime:   time.Now(),
-		subscribers: make(map[string]chan *pb.Event),
-		lastStatus:  make(map[string]server.Status),
+		manager:           mgr,
+		startTime:         time.Now(),
+		heartbeatInterval: HeartbeatInterval,
+		subscribers:       make(map[string]chan *pb.Event),
+		lastStatus:        make(map[string]server.Status),
+		lastTools:         make(map[string]uint64),
+		monitor:           opts.withDefaults(),
+		queues:            make(map[string]func() int),
 	}
 
 	// Initialize status tracking
@@ -46,7 +133,38 @@ func NewServer(mgr ManagerInterface) *Server {
 	}
 
 	// Start event monitor
+	s.startBroadcasters()
 	go s.eventMonitor()
+	if source, ok := mgr.(FailoverSource); ok {
+		failovers := source.Failovers()
+		s.queues["failovers"] = func() int { return len(failovers) }
+		go s.forwardFailovers(failovers)
+	}
+	if source, ok := mgr.(CircuitSource); ok {
+		changes := source.CircuitChanges()
+		s.queues["circuit_changes"] = func() int { return len(changes) }
+		go s.forwardCircuitChanges(changes)
+	}
+	if approver, ok := mgr.(Approver); ok {
+		changes := approver.ApprovalChanges()
+		s.queues["approval_changes"] = func() int { return len(changes) }
+		go s.forwardApprovalChanges(changes)
+	}
+	if source, ok := mgr.(ConfigSource); ok {
+		changes := source.ConfigChanges()
+		s.queues["config_changes"] = func() int { return len(changes) }
+		go s.forwardConfigChanges(changes)
+	}
+	if source, ok := mgr.(PortSource); ok {
+		migrations := source.PortMigrations()
+		s.queues["port_migrations"] = func() int { return len(migrations) }
+		go s.forwardPortMigrations(migrations)
+	}
+	if source, ok := mgr.(StatusSource); ok {
+		changes := source.StatusChanges()
+		s.queues["status_changes"] = func() int { return len(changes) }
+		go s.forwardStatusChanges(changes)
+	}
 
 	return s
 }
@@ -87,7 +205,7 @@ func (s *Server) StartServer(ctx context.Context, req *pb.ServerRequest) (*pb.Se
 	s.broadcastServerStatusChange(req.Name, server.StatusStopped, server.StatusStarting)
 
 	if err := s.manager.StartServer(req.Name); err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to start server: %v", err)
+		return nil, status.Errorf(errorCode(err), "failed to start server: %v", err)
 	}
 
 	// Get updated server info
@@ -110,7 +228,7 @@ func (s *Server) StopServer(ctx context.Context, req *pb.ServerRequest) (*pb.Ser
 	s.broadcastServerStatusChange(req.Name, server.StatusRunning, server.StatusStopping)
 
 	if err := s.manager.StopServer(req.Name); err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to stop server: %v", err)
+		return nil, status.Errorf(errorCode(err), "failed to stop server: %v", err)
 	}
 
 	// Get updated server info
@@ -127,6 +245,75 @@ func (s *Server) StopServer(ctx context.Context, req *pb.ServerRequest) (*pb.Ser
 	return serverToProto(srv), nil
 }
 
+// StartTagged starts the stopped servers labeled with a tag, in order
+func (s *Server) StartTagged(ctx context.Context, req *pb.TagRequest) (*pb.ServerList, error) {
+	names, err := s.tagged(req.Tag, func(srv *server.Server) bool { return !srv.IsRunning() })
+	if err != nil {
+		return nil, err
+	}
+
+	list := &pb.ServerList{}
+	for _, name := range names {
+		srv, err := s.StartServer(ctx, &pb.ServerRequest{Name: name})
+		if err != nil {
+			return nil, err
+		}
+		list.Servers = append(list.Servers, srv)
+		list.Order = append(list.Order, name)
+	}
+	return list, nil
+}
+
+// StopTagged stops the running servers labeled with a tag, in reverse
+// order
+func (s *Server) StopTagged(ctx context.Context, req *pb.TagRequest) (*pb.ServerList, error) {
+	names, err := s.tagged(req.Tag, (*server.Server).IsRunning)
+	if err != nil {
+		return nil, err
+	}
+	slices.Reverse(names)
+
+	list := &pb.ServerList{}
+	for _, name := range names {
+		srv, err := s.StopServer(ctx, &pb.ServerRequest{Name: name})
+		if err != nil {
+			return nil, err
+		}
+		list.Servers = append(list.Servers, srv)
+		list.Order = append(list.Order, name)
+	}
+	return list, nil
+}
+
+// tagged returns the servers labeled with tag that selected accepts, in
+// order, failing if no server has the tag
+func (s *Server) tagged(tag string, selected func(*server.Server) bool) ([]string, error) {
+	if tag == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "no tag given")
+	}
+	servers, order, err := s.manager.GetServers()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get servers: %v", err)
+	}
+
+	var names []string
+	found := false
+	for _, name := range order {
+		srv, exists := servers[name]
+		if !exists || !srv.HasTag(tag) {
+			continue
+		}
+		found = true
+		if selected(srv) {
+			names = append(names, name)
+		}
+	}
+	if !found {
+		return nil, status.Errorf(codes.NotFound, "no server is tagged %s", tag)
+	}
+	return names, nil
+}
+
 // GetTools returns the tools for a specific server
 func (s *Server) GetTools(ctx context.Context, req *pb.ServerRequest) (*pb.ToolList, error) {
 	srv, err := s.manager.GetServer(req.Name)
@@ -141,6 +328,12 @@ func (s *Server) GetTools(ctx context.Context, req *pb.ServerRequest) (*pb.ToolL
 			Title:       tool.Title,
 			Description: tool.Description,
 		}
+		if tool.InputSchema != nil {
+			schema, err := json.Marshal(tool.InputSchema)
+			if err == nil {
+				tools[i].InputSchema = string(schema)
+			}
+		}
 	}
 
 	return &pb.ToolList{Tools: tools}, nil
@@ -198,6 +391,30 @@ func (s *Server) GetConfigPath(ctx context.Context, _ *pb.Empty) (*pb.PathRespon
 	}, nil
 }
 
+// ValidateConfig reports the problems of the configuration and the tools
+// the gateway exposes under other names
+func (s *Server) ValidateConfig(ctx context.Context, _ *pb.Empty) (*pb.ConfigValidation, error) {
+	if s.validator == nil {
+		return nil, status.Errorf(codes.Unimplemented, "daemon does not validate its configuration")
+	}
+
+	validation, err := s.validator.ValidateConfig()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to validate config: %v", err)
+	}
+
+	resp := &pb.ConfigValidation{Problems: validation.Problems}
+	for _, conflict := range validation.Conflicts {
+		resp.Conflicts = append(resp.Conflicts, &pb.ToolConflict{
+			Tool:    conflict.Tool,
+			Servers: conflict.Servers,
+			Renamed: conflict.Renamed,
+			Hidden:  conflict.Hidden,
+		})
+	}
+	return resp, nil
+}
+
 // Subscribe creates a streaming connection for real-time events
 func (s *Server) Subscribe(req *pb.SubscribeRequest, stream pb.MCPManager_SubscribeServer) error {
 	// Create a unique subscriber ID
@@ -219,17 +436,32 @@ func (s *Server) Subscribe(req *pb.SubscribeRequest, stream pb.MCPManager_Subscr
 
 	log.Printf("Client subscribed with ID: %s", subscriberID)
 
+	heartbeat := time.NewTicker(s.heartbeatInterval)
+	defer heartbeat.Stop()
+
 	// Send events to client
 	for {
 		select {
 		case event := <-eventChan:
 			// Filter events based on request
 			if shouldSendEvent(event, req.EventTypes) {
-				if err := stream.Send(event); err != nil {
+				if err := sendEvent(stream, event, sendTimeout); err != nil {
 					log.Printf("Error sending event to subscriber %s: %v", subscriberID, err)
 					return err
 				}
 			}
+		case <-heartbeat.C:
+			event := &pb.Event{
+				Type:      pb.EventType_HEARTBEAT,
+				Timestamp: time.Now().Unix(),
+				Payload: &pb.Event_Heartbeat{
+					Heartbeat: &pb.HeartbeatEvent{IntervalMs: s.heartbeatInterval.Milliseconds()},
+				},
+			}
+			if err := sendEvent(stream, event, sendTimeout); err != nil {
+				log.Printf("Subscriber %s missed a heartbeat: %v", subscriberID, err)
+				return err
+			}
 		case <-stream.Context().Done():
 			log.Printf("Client %s disconnected", subscriberID)
 			return stream.Context().Err()
@@ -237,6 +469,23 @@ func (s *Server) Subscribe(req *pb.SubscribeRequest, stream pb.MCPManager_Subscr
 	}
 }
 
+// sendEvent sends an event to a subscriber, giving up after timeout so
+// a subscriber that stopped reading doesn't hold its stream open forever.
+// Returning from the handler cancels the stream, unblocking the send.
+func sendEvent(stream pb.MCPManager_SubscribeServer, event *pb.Event, timeout time.Duration) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- stream.Send(event)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(timeout):
+		return status.Error(codes.DeadlineExceeded, "subscriber stopped receiving events")
+	}
+}
+
 // Health returns the health status of the daemon
 func (s *Server) Health(ctx context.Context, _ *pb.Empty) (*pb.HealthStatus, error) {
 	servers, _, err := s.manager.GetServers()
@@ -252,7 +501,13 @@ func (s *Server) Health(ctx context.Context, _ *pb.Empty) (*pb.HealthStatus, err
 		}
 	}
 
+	maintenance, err := s.manager.Maintenance()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get maintenance mode: %v", err)
+	}
+
 	return &pb.HealthStatus{
+		Maintenance:    maintenance,
 		Healthy:        true,
 		UptimeSeconds:  int64(time.Since(s.startTime).Seconds()),
 		RunningServers: int32(runningCount),
@@ -260,17 +515,666 @@ func (s *Server) Health(ctx context.Context, _ *pb.Empty) (*pb.HealthStatus, err
 	}, nil
 }
 
-// eventMonitor periodically checks for status changes and broadcasts events
+// SetMaintenance enables or disables maintenance mode for a server or the
+// whole daemon
+func (s *Server) SetMaintenance(ctx context.Context, req *pb.MaintenanceRequest) (*pb.StatusResponse, error) {
+	if err := s.manager.SetMaintenance(req.Name, req.Enabled); err != nil {
+		return nil, status.Errorf(codes.NotFound, "failed to set maintenance mode: %v", err)
+	}
+
+	target := req.Name
+	if target == "" {
+		target = "daemon"
+	}
+	state := "disabled"
+	if req.Enabled {
+		state = "enabled"
+	}
+
+	return &pb.StatusResponse{
+		Success: true,
+		Message: fmt.Sprintf("Maintenance mode %s for %s", state, target),
+	}, nil
+}
+
+// Register adds a daemon to the fleet of a coordinator
+func (s *Server) Register(ctx context.Context, req *pb.RegisterRequest) (*pb.StatusResponse, error) {
+	registrar, ok := s.manager.(Registrar)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "daemon is not a coordinator")
+	}
+
+	if err := registrar.Register(req.Host, req.Address); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to register: %v", err)
+	}
+
+	return &pb.StatusResponse{
+		Success: true,
+		Message: fmt.Sprintf("Registered %s", req.Host),
+	}, nil
+}
+
+// ListApprovals returns the tool calls waiting for approval
+func (s *Server) ListApprovals(ctx context.Context, _ *pb.Empty) (*pb.ApprovalList, error) {
+	approver, ok := s.manager.(Approver)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "daemon doesn't park calls for approval")
+	}
+
+	list := &pb.ApprovalList{}
+	for _, approval := range approver.PendingApprovals() {
+		list.Approvals = append(list.Approvals, approvalToProto(approval))
+	}
+	return list, nil
+}
+
+// DecideApproval lets a parked tool call through or rejects it
+func (s *Server) DecideApproval(ctx context.Context, req *pb.ApprovalDecision) (*pb.StatusResponse, error) {
+	approver, ok := s.manager.(Approver)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "daemon doesn't park calls for approval")
+	}
+
+	if err := approver.DecideApproval(req.Id, req.Approved, req.Reason); err != nil {
+		return nil, status.Errorf(errorCode(err), "failed to decide approval: %v", err)
+	}
+
+	decision := "Rejected"
+	if req.Approved {
+		decision = "Approved"
+	}
+	return &pb.StatusResponse{
+		Success: true,
+		Message: fmt.Sprintf("%s %s", decision, req.Id),
+	}, nil
+}
+
+// ListSessions returns the session transcripts of the proxies' clients
+// without their calls, or with only the calls an agent gave the request or
+// run ID of the filter
+func (s *Server) ListSessions(ctx context.Context, req *pb.SessionFilter) (*pb.SessionList, error) {
+	source, ok := s.manager.(TranscriptSource)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "daemon doesn't record session transcripts")
+	}
+
+	sessions := source.Sessions()
+	if req.CorrelationId != "" {
+		sessions = source.FindSessions(req.CorrelationId)
+	}
+	list := &pb.SessionList{}
+	for _, session := range sessions {
+		list.Sessions = append(list.Sessions, sessionToProto(&session))
+	}
+	return list, nil
+}
+
+// GetSession returns a session transcript with its calls
+func (s *Server) GetSession(ctx context.Context, req *pb.SessionRequest) (*pb.TranscriptSession, error) {
+	source, ok := s.manager.(TranscriptSource)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "daemon doesn't record session transcripts")
+	}
+
+	session, err := source.Session(req.Id)
+	if err != nil {
+		return nil, status.Errorf(errorCode(err), "%v", err)
+	}
+	return sessionToProto(session), nil
+}
+
+// ListPlugins returns the plugins the daemon found and their config
+// schemas
+func (s *Server) ListPlugins(ctx context.Context, _ *pb.Empty) (*pb.PluginList, error) {
+	source, ok := s.manager.(PluginSource)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "daemon doesn't support plugins")
+	}
+
+	plugins, err := source.Plugins()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+	list := &pb.PluginList{}
+	for _, p := range plugins {
+		list.Plugins = append(list.Plugins, &pb.Plugin{
+			Name:         p.Name,
+			Path:         p.Path,
+			Version:      p.Version,
+			Description:  p.Description,
+			Kinds:        p.Kinds,
+			ConfigSchema: string(p.ConfigSchema),
+			Error:        p.Error,
+		})
+	}
+	return list, nil
+}
+
+// CreateFleet copies servers into an isolated fleet for an evaluation run
+func (s *Server) CreateFleet(ctx context.Context, req *pb.FleetRequest) (*pb.Fleet, error) {
+	fleets, ok := s.manager.(FleetManager)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "daemon doesn't create fleets")
+	}
+
+	fleet, err := fleets.CreateFleet(req.RunId, req.Servers, req.Start)
+	if err != nil {
+		return nil, status.Errorf(errorCode(err), "failed to create fleet: %v", err)
+	}
+	return fleetToProto(fleet), nil
+}
+
+// DestroyFleet stops the servers of a run's fleet and removes them with
+// their state
+func (s *Server) DestroyFleet(ctx context.Context, req *pb.FleetRequest) (*pb.StatusResponse, error) {
+	fleets, ok := s.manager.(FleetManager)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "daemon doesn't create fleets")
+	}
+
+	if err := fleets.DestroyFleet(req.RunId); err != nil {
+		return nil, status.Errorf(errorCode(err), "failed to destroy fleet: %v", err)
+	}
+	return &pb.StatusResponse{
+		Success: true,
+		Message: fmt.Sprintf("Destroyed fleet %s", req.RunId),
+	}, nil
+}
+
+// ListFleets returns the fleets of the runs in progress
+func (s *Server) ListFleets(ctx context.Context, _ *pb.Empty) (*pb.FleetList, error) {
+	fleets, ok := s.manager.(FleetManager)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "daemon doesn't create fleets")
+	}
+
+	list := &pb.FleetList{}
+	for _, fleet := range fleets.Fleets() {
+		list.Fleets = append(list.Fleets, fleetToProto(&fleet))
+	}
+	return list, nil
+}
+
+// DrainServer stops a server once the calls in flight on its proxy finish
+func (s *Server) DrainServer(ctx context.Context, req *pb.DrainRequest) (*pb.Server, error) {
+	drainer, ok := s.manager.(Drainer)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "daemon doesn't drain servers")
+	}
+
+	s.broadcastServerStatusChange(req.Name, server.StatusRunning, server.StatusStopping)
+	if err := drainer.DrainServer(req.Name, time.Duration(req.TimeoutMs)*time.Millisecond); err != nil {
+		return nil, status.Errorf(errorCode(err), "failed to drain server: %v", err)
+	}
+
+	srv, err := s.manager.GetServer(req.Name)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "server not found after drain")
+	}
+
+	s.statusMu.Lock()
+	s.lastStatus[req.Name] = srv.Status
+	s.statusMu.Unlock()
+
+	return serverToProto(srv), nil
+}
+
+// ListProfiles returns the browser profiles of a server
+func (s *Server) ListProfiles(ctx context.Context, req *pb.ServerRequest) (*pb.ProfileList, error) {
+	profiles, ok := s.manager.(ProfileManager)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "daemon doesn't keep browser profiles")
+	}
+
+	list, err := profiles.BrowserProfiles(req.Name)
+	if err != nil {
+		return nil, status.Errorf(errorCode(err), "failed to list profiles: %v", err)
+	}
+	resp := &pb.ProfileList{}
+	for _, profile := range list {
+		msg := &pb.BrowserProfile{
+			Server:   profile.Server,
+			Name:     profile.Name,
+			Dir:      profile.Dir,
+			Size:     profile.Size,
+			Attached: profile.Attached,
+		}
+		if !profile.Modified.IsZero() {
+			msg.Modified = profile.Modified.Unix()
+		}
+		for _, snapshot := range profile.Snapshots {
+			msg.Snapshots = append(msg.Snapshots, snapshotToProto(&snapshot))
+		}
+		resp.Profiles = append(resp.Profiles, msg)
+	}
+	return resp, nil
+}
+
+// ResetProfile deletes the files of a stopped server's browser profile
+func (s *Server) ResetProfile(ctx context.Context, req *pb.ProfileRequest) (*pb.StatusResponse, error) {
+	profiles, ok := s.manager.(ProfileManager)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "daemon doesn't keep browser profiles")
+	}
+
+	if err := profiles.ResetProfile(req.Server, req.Profile); err != nil {
+		return nil, status.Errorf(errorCode(err), "failed to reset profile: %v", err)
+	}
+	return &pb.StatusResponse{Success: true, Message: fmt.Sprintf("Reset profile of %s", req.Server)}, nil
+}
+
+// SnapshotProfile archives a server's browser profile
+func (s *Server) SnapshotProfile(ctx context.Context, req *pb.ProfileRequest) (*pb.ProfileSnapshot, error) {
+	profiles, ok := s.manager.(ProfileManager)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "daemon doesn't keep browser profiles")
+	}
+
+	snapshot, err := profiles.SnapshotProfile(req.Server, req.Profile)
+	if err != nil {
+		return nil, status.Errorf(errorCode(err), "failed to snapshot profile: %v", err)
+	}
+	return snapshotToProto(snapshot), nil
+}
+
+// RestoreProfile replaces a stopped server's browser profile with a
+// snapshot
+func (s *Server) RestoreProfile(ctx context.Context, req *pb.ProfileRequest) (*pb.StatusResponse, error) {
+	profiles, ok := s.manager.(ProfileManager)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "daemon doesn't keep browser profiles")
+	}
+
+	if err := profiles.RestoreProfile(req.Server, req.Profile, req.Snapshot); err != nil {
+		return nil, status.Errorf(errorCode(err), "failed to restore profile: %v", err)
+	}
+	return &pb.StatusResponse{Success: true, Message: fmt.Sprintf("Restored profile of %s from %s", req.Server, req.Snapshot)}, nil
+}
+
+// snapshotToProto converts a profile snapshot
+func snapshotToProto(snapshot *ProfileSnapshot) *pb.ProfileSnapshot {
+	return &pb.ProfileSnapshot{
+		Name:    snapshot.Name,
+		Path:    snapshot.Path,
+		Size:    snapshot.Size,
+		Created: snapshot.Created.Unix(),
+	}
+}
+
+// DiskUsage returns the disk used by servers
+func (s *Server) DiskUsage(ctx context.Context, req *pb.DiskRequest) (*pb.DiskUsageList, error) {
+	disk, ok := s.manager.(DiskManager)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "daemon doesn't track disk usage")
+	}
+
+	usage, err := disk.DiskUsage(req.Names)
+	if err != nil {
+		return nil, status.Errorf(errorCode(err), "failed to get disk usage: %v", err)
+	}
+	return diskUsageToProto(usage), nil
+}
+
+// CleanCaches deletes the caches of stopped servers, returning what was
+// deleted, or would be on a dry run
+func (s *Server) CleanCaches(ctx context.Context, req *pb.DiskRequest) (*pb.DiskUsageList, error) {
+	disk, ok := s.manager.(DiskManager)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "daemon doesn't track disk usage")
+	}
+
+	cleaned, err := disk.CleanCaches(req.Names, req.DryRun)
+	if err != nil {
+		return nil, status.Errorf(errorCode(err), "failed to clean caches: %v", err)
+	}
+	return diskUsageToProto(cleaned), nil
+}
+
+// diskUsageToProto converts the disk used by servers
+func diskUsageToProto(usage []ServerDisk) *pb.DiskUsageList {
+	resp := &pb.DiskUsageList{}
+	for _, disk := range usage {
+		msg := &pb.ServerDisk{Server: disk.Server}
+		for _, dir := range disk.Dirs {
+			msg.Dirs = append(msg.Dirs, &pb.DiskDir{Kind: dir.Kind, Label: dir.Label, Path: dir.Path, Size: dir.Size})
+		}
+		resp.Servers = append(resp.Servers, msg)
+	}
+	return resp
+}
+
+// BackupServer archives the data of a server, stopping it meanwhile if it
+// runs
+func (s *Server) BackupServer(ctx context.Context, req *pb.ServerRequest) (*pb.Backup, error) {
+	backups, ok := s.manager.(BackupManager)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "daemon doesn't back up servers")
+	}
+
+	backup, err := backups.BackupServer(req.Name)
+	if err != nil {
+		return nil, status.Errorf(errorCode(err), "failed to back up server: %v", err)
+	}
+	return backupToProto(backup), nil
+}
+
+// ListBackups returns the backups of a server, oldest first
+func (s *Server) ListBackups(ctx context.Context, req *pb.ServerRequest) (*pb.BackupList, error) {
+	backups, ok := s.manager.(BackupManager)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "daemon doesn't back up servers")
+	}
+
+	list, err := backups.Backups(req.Name)
+	if err != nil {
+		return nil, status.Errorf(errorCode(err), "failed to list backups: %v", err)
+	}
+	resp := &pb.BackupList{}
+	for i := range list {
+		resp.Backups = append(resp.Backups, backupToProto(&list[i]))
+	}
+	return resp, nil
+}
+
+// RestoreBackup replaces the data of a server with a backup, stopping it
+// meanwhile if it runs
+func (s *Server) RestoreBackup(ctx context.Context, req *pb.BackupRequest) (*pb.StatusResponse, error) {
+	backups, ok := s.manager.(BackupManager)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "daemon doesn't back up servers")
+	}
+
+	if err := backups.RestoreBackup(req.Server, req.Backup); err != nil {
+		return nil, status.Errorf(errorCode(err), "failed to restore backup: %v", err)
+	}
+	return &pb.StatusResponse{Success: true, Message: fmt.Sprintf("Restored %s from %s", req.Server, req.Backup)}, nil
+}
+
+// backupToProto converts a backup
+func backupToProto(backup *Backup) *pb.Backup {
+	return &pb.Backup{
+		Server:  backup.Server,
+		Name:    backup.Name,
+		Path:    backup.Path,
+		Size:    backup.Size,
+		Created: backup.Created.Unix(),
+	}
+}
+
+// ListProviders returns the generic gateway tools with the provider each
+// selects and their rolling counts
+func (s *Server) ListProviders(ctx context.Context, _ *pb.Empty) (*pb.ProviderList, error) {
+	if s.providers == nil {
+		return nil, status.Errorf(codes.Unimplemented, "daemon doesn't serve a gateway")
+	}
+
+	groups, err := s.providers.Providers()
+	if err != nil {
+		return nil, status.Errorf(errorCode(err), "failed to list providers: %v", err)
+	}
+	resp := &pb.ProviderList{}
+	for _, group := range groups {
+		msg := &pb.ProviderGroup{Tool: group.Tool, Selected: group.Selected, Pinned: group.Pinned}
+		for _, provider := range group.Providers {
+			msg.Providers = append(msg.Providers, &pb.ProviderStats{
+				Server:    provider.Server,
+				Tool:      provider.Tool,
+				Running:   provider.Running,
+				Calls:     int64(provider.Calls),
+				Errors:    int64(provider.Errors),
+				ErrorRate: provider.ErrorRate,
+				LatencyMs: float64(provider.Latency.Microseconds()) / 1000,
+			})
+		}
+		resp.Groups = append(resp.Groups, msg)
+	}
+	return resp, nil
+}
+
+// PinProvider makes a server serve a generic gateway tool, or lets the
+// gateway select it again
+func (s *Server) PinProvider(ctx context.Context, req *pb.PinRequest) (*pb.StatusResponse, error) {
+	if s.providers == nil {
+		return nil, status.Errorf(codes.Unimplemented, "daemon doesn't serve a gateway")
+	}
+
+	if err := s.providers.PinProvider(req.Tool, req.Server); err != nil {
+		return nil, status.Errorf(errorCode(err), "failed to pin provider: %v", err)
+	}
+	if req.Server == "" {
+		return &pb.StatusResponse{Success: true, Message: fmt.Sprintf("Unpinned %s", req.Tool)}, nil
+	}
+	return &pb.StatusResponse{Success: true, Message: fmt.Sprintf("Pinned %s to %s", req.Tool, req.Server)}, nil
+}
+
+// ListNotifications returns the level of desktop notifications of every
+// server
+func (s *Server) ListNotifications(ctx context.Context, _ *pb.Empty) (*pb.NotificationList, error) {
+	if s.notifier == nil {
+		return nil, status.Errorf(codes.Unimplemented, "daemon doesn't show notifications")
+	}
+
+	level, preferences, err := s.notifier.Preferences()
+	if err != nil {
+		return nil, status.Errorf(errorCode(err), "failed to list notifications: %v", err)
+	}
+	resp := &pb.NotificationList{DefaultLevel: level}
+	for _, preference := range preferences {
+		resp.Servers = append(resp.Servers, &pb.NotificationPreference{
+			Server: preference.Server,
+			Level:  preference.Level,
+			Custom: preference.Custom,
+		})
+	}
+	return resp, nil
+}
+
+// SetNotification sets the level of desktop notifications of a server, or
+// resets it to the default
+func (s *Server) SetNotification(ctx context.Context, req *pb.NotificationRequest) (*pb.StatusResponse, error) {
+	if s.notifier == nil {
+		return nil, status.Errorf(codes.Unimplemented, "daemon doesn't show notifications")
+	}
+
+	if err := s.notifier.SetPreference(req.Server, req.Level); err != nil {
+		return nil, status.Errorf(errorCode(err), "failed to set notifications: %v", err)
+	}
+	if req.Level == "" {
+		return &pb.StatusResponse{Success: true, Message: fmt.Sprintf("Reset notifications of %s", req.Server)}, nil
+	}
+	return &pb.StatusResponse{Success: true, Message: fmt.Sprintf("Notifications of %s set to %s", req.Server, req.Level)}, nil
+}
+
+// DescribeServer returns what a server runs as, or would if started now
+func (s *Server) DescribeServer(ctx context.Context, req *pb.ServerRequest) (*pb.ServerRuntime, error) {
+	describer, ok := s.manager.(Describer)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "daemon doesn't describe servers")
+	}
+
+	runtime, err := describer.DescribeServer(req.Name)
+	if err != nil {
+		return nil, status.Errorf(errorCode(err), "failed to describe server: %v", err)
+	}
+	resp := &pb.ServerRuntime{
+		Server:    runtime.Server,
+		Command:   runtime.Command,
+		Running:   runtime.Running,
+		Path:      runtime.Path,
+		Cwd:       runtime.Cwd,
+		Transport: runtime.Transport,
+		Error:     runtime.Error,
+	}
+	for _, v := range runtime.Env {
+		resp.Env = append(resp.Env, &pb.RuntimeVar{Name: v.Name, Source: v.Source})
+	}
+	for _, limit := range runtime.Limits {
+		resp.Limits = append(resp.Limits, &pb.RuntimeLimit{Name: limit.Name, Value: limit.Value})
+	}
+	return resp, nil
+}
+
+// CloneServer copies a server in mcp.json under a new name and port
+func (s *Server) CloneServer(ctx context.Context, req *pb.CloneRequest) (*pb.CloneResponse, error) {
+	cloner, ok := s.manager.(Cloner)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "daemon doesn't clone servers")
+	}
+
+	name, port, err := cloner.CloneServer(req.ServerName, req.Name, req.Replace)
+	if err != nil {
+		return nil, status.Errorf(errorCode(err), "failed to clone server: %v", err)
+	}
+	return &pb.CloneResponse{Name: name, Port: int32(port)}, nil
+}
+
+// StreamLogs sends the captured output of a server, then new lines as they
+// are written if the request follows the logs
+func (s *Server) StreamLogs(req *pb.LogsRequest, stream pb.MCPManager_StreamLogsServer) error {
+	source, ok := s.manager.(LogSource)
+	if !ok {
+		return status.Errorf(codes.Unimplemented, "daemon does not capture server logs")
+	}
+
+	buffer, err := source.Logs(req.Name)
+	if err != nil {
+		return status.Errorf(codes.NotFound, "%v", err)
+	}
+
+	if !req.Follow {
+		for _, line := range buffer.Tail(int(req.TailLines)) {
+			if err := stream.Send(logLineToProto(line)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	history, follow, stop := buffer.Follow(int(req.TailLines))
+	defer stop()
+
+	for _, line := range history {
+		if err := stream.Send(logLineToProto(line)); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case line := <-follow:
+			if err := stream.Send(logLineToProto(line)); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
+// defaultEventLimit caps the events returned by QueryEvents
+const defaultEventLimit = 1000
+
+// QueryEvents returns past events from the journal
+func (s *Server) QueryEvents(ctx context.Context, req *pb.EventQuery) (*pb.EventList, error) {
+	if s.journal == nil {
+		return nil, status.Errorf(codes.Unimplemented, "event journal is disabled")
+	}
+
+	query := journal.Query{
+		Types:  req.EventTypes,
+		Server: req.Server,
+		Limit:  int(req.Limit),
+	}
+	if req.From > 0 {
+		query.From = time.Unix(req.From, 0)
+	}
+	if req.To > 0 {
+		query.To = time.Unix(req.To, 0)
+	}
+	if query.Limit <= 0 || query.Limit > defaultEventLimit {
+		query.Limit = defaultEventLimit
+	}
+
+	events, truncated, err := s.journal.Query(query)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to query events: %v", err)
+	}
+	return &pb.EventList{Events: events, Truncated: truncated}, nil
+}
+
+// defaultUptimeDays is the window of GetUptime
+const defaultUptimeDays = 7
+
+// GetUptime returns the availability of servers over the last days
+func (s *Server) GetUptime(ctx context.Context, req *pb.UptimeRequest) (*pb.UptimeReport, error) {
+	if s.uptime == nil {
+		return nil, status.Errorf(codes.Unimplemented, "uptime is not tracked")
+	}
+
+	days := int(req.Days)
+	if days == 0 {
+		days = defaultUptimeDays
+	}
+	if days < 0 || days > uptime.Retention {
+		return nil, status.Errorf(codes.InvalidArgument, "days must be between 1 and %d", uptime.Retention)
+	}
+
+	report := &pb.UptimeReport{}
+	for _, stats := range s.uptime.Report(days) {
+		report.Servers = append(report.Servers, &pb.ServerUptime{
+			Server:      stats.Server,
+			UpSeconds:   stats.Up.Seconds(),
+			DownSeconds: stats.Down.Seconds(),
+			Outages:     int32(stats.Outages),
+			MttrSeconds: stats.MTTR.Seconds(),
+		})
+	}
+	return report, nil
+}
+
+// eventMonitor detects status changes every Interval and refreshes the
+// servers' tools every ToolInterval, broadcasting the changes
 func (s *Server) eventMonitor() {
-	ticker := time.NewTicker(2 * time.Second)
-	defer ticker.Stop()
+	statusTicker := time.NewTicker(s.monitor.Interval)
+	defer statusTicker.Stop()
+	toolTicker := time.NewTicker(s.monitor.ToolInterval)
+	defer toolTicker.Stop()
 
-	for range ticker.C {
+	for {
+		select {
+		case <-statusTicker.C:
+		case <-toolTicker.C:
+			// Fetched in the background, the changes are noticed on a later tick
+			s.manager.UpdateToolCounts()
+			continue
+		}
 		s.checkStatusChanges()
 		s.checkToolUpdates()
 	}
 }
 
+// startBroadcasters starts the workers broadcasting the monitor's events
+func (s *Server) startBroadcasters() {
+	s.broadcasts = make([]chan func(), s.monitor.Workers)
+	for i := range s.broadcasts {
+		queue := make(chan func(), broadcastQueueSize)
+		s.broadcasts[i] = queue
+		go func() {
+			for broadcast := range queue {
+				broadcast()
+			}
+		}()
+	}
+}
+
+// dispatch queues a broadcast of the monitor about a server. The events of
+// a server always go to the same worker so they keep their order, and the
+// monitor waits while the worker's queue is full.
+func (s *Server) dispatch(serverName string, broadcast func()) {
+	h := fnv.New32a()
+	h.Write([]byte(serverName))
+	s.broadcasts[h.Sum32()%uint32(len(s.broadcasts))] <- broadcast
+}
+
 // checkStatusChanges checks for server status changes
 func (s *Server) checkStatusChanges() {
 	servers, _, err := s.manager.GetServers()
@@ -279,9 +1183,13 @@ func (s *Server) checkStatusChanges() {
 		return
 	}
 
-	s.statusMu.Lock()
-	defer s.statusMu.Unlock()
+	type change struct {
+		name     string
+		old, new server.Status
+	}
+	var changes []change
 
+	s.statusMu.Lock()
 	for name, srv := range servers {
 		lastStatus, exists := s.lastStatus[name]
 		if !exists || lastStatus != srv.Status {
@@ -292,7 +1200,7 @@ func (s *Server) checkStatusChanges() {
 			}
 
 			s.lastStatus[name] = srv.Status
-			go s.broadcastServerStatusChange(name, oldStatus, srv.Status)
+			changes = append(changes, change{name, oldStatus, srv.Status})
 		}
 	}
 
@@ -302,25 +1210,51 @@ func (s *Server) checkStatusChanges() {
 			delete(s.lastStatus, name)
 		}
 	}
+	s.statusMu.Unlock()
+
+	// Broadcast outside the lock, as exporters may be slow
+	for _, c := range changes {
+		s.dispatch(c.name, func() { s.broadcastServerStatusChange(c.name, c.old, c.new) })
+	}
 }
 
-// checkToolUpdates checks for tool count changes
+// checkToolUpdates broadcasts the tools of running servers whose tools
+// changed since they were last broadcast
 func (s *Server) checkToolUpdates() {
-	// Trigger tool count update
-	s.manager.UpdateToolCounts()
-
-	// Check for changes and broadcast
 	servers, _, err := s.manager.GetServers()
 	if err != nil {
 		log.Printf("Error checking tool updates: %v", err)
 		return
 	}
 
-	for _, srv := range servers {
-		if srv.IsRunning() && srv.ToolCount > 0 {
-			go s.broadcastToolUpdate(srv)
+	var updated []*server.Server
+	s.statusMu.Lock()
+	for name, srv := range servers {
+		if !srv.IsRunning() || srv.ToolCount == 0 {
+			// Broadcast again once the server is back
+			delete(s.lastTools, name)
+			continue
+		}
+		if hash := toolsHash(srv); s.lastTools[name] != hash {
+			s.lastTools[name] = hash
+			updated = append(updated, srv)
 		}
 	}
+	s.statusMu.Unlock()
+
+	for _, srv := range updated {
+		s.dispatch(srv.Name, func() { s.broadcastToolUpdate(srv) })
+	}
+}
+
+// toolsHash returns a hash of the tools a TOOL_UPDATE event carries
+func toolsHash(srv *server.Server) uint64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d\x00", srv.ToolCount)
+	for _, tool := range srv.Tools {
+		fmt.Fprintf(h, "%s\x00%s\x00%s\x00", tool.Name, tool.Title, tool.Description)
+	}
+	return h.Sum64()
 }
 
 // broadcastServerStatusChange broadcasts a server status change event
@@ -366,8 +1300,165 @@ func (s *Server) broadcastToolUpdate(srv *server.Server) {
 	s.broadcastEvent(event)
 }
 
+// forwardFailovers broadcasts the failovers of a coordinator
+func (s *Server) forwardFailovers(failovers <-chan Failover) {
+	for f := range failovers {
+		s.broadcastEvent(&pb.Event{
+			Type:      pb.EventType_FAILOVER,
+			Timestamp: time.Now().Unix(),
+			Payload: &pb.Event_Failover{
+				Failover: &pb.FailoverEvent{
+					ServerName: f.Server,
+					FromHost:   f.From,
+					ToHost:     f.To,
+					Reason:     f.Reason,
+				},
+			},
+		})
+	}
+}
+
+// forwardCircuitChanges broadcasts the circuit breaker changes of the
+// manager's proxies
+func (s *Server) forwardCircuitChanges(changes <-chan CircuitChange) {
+	for c := range changes {
+		s.broadcastEvent(&pb.Event{
+			Type:      pb.EventType_CIRCUIT_BREAKER,
+			Timestamp: time.Now().Unix(),
+			Payload: &pb.Event_CircuitBreaker{
+				CircuitBreaker: &pb.CircuitBreakerEvent{
+					ServerName: c.Server,
+					State:      c.State,
+					Reason:     c.Reason,
+				},
+			},
+		})
+	}
+}
+
+// forwardConfigChanges broadcasts the reloads of the manager's
+// configuration
+func (s *Server) forwardConfigChanges(changes <-chan ConfigChange) {
+	for c := range changes {
+		s.broadcastEvent(&pb.Event{
+			Type:      pb.EventType_CONFIG_CHANGE,
+			Timestamp: time.Now().Unix(),
+			Payload: &pb.Event_ConfigChange{
+				ConfigChange: &pb.ConfigChangeEvent{
+					ServersAdded:    c.Added,
+					ServersRemoved:  c.Removed,
+					ServersModified: c.Modified,
+					ServersUpdated:  c.Updated,
+				},
+			},
+		})
+	}
+}
+
+// forwardPortMigrations broadcasts the steps of moving the manager's
+// servers to new ports
+func (s *Server) forwardPortMigrations(migrations <-chan PortMigration) {
+	for m := range migrations {
+		s.broadcastEvent(&pb.Event{
+			Type:      pb.EventType_PORT_MIGRATION,
+			Timestamp: time.Now().Unix(),
+			Payload: &pb.Event_PortMigration{
+				PortMigration: &pb.PortMigrationEvent{
+					ServerName: m.Server,
+					OldPort:    int32(m.OldPort),
+					NewPort:    int32(m.NewPort),
+					State:      m.State,
+					Reason:     m.Reason,
+				},
+			},
+		})
+	}
+}
+
+// forwardStatusChanges broadcasts the crashes and restarts of the
+// manager's servers, recording the new status so polling doesn't report
+// it again
+func (s *Server) forwardStatusChanges(changes <-chan StatusChange) {
+	for c := range changes {
+		s.statusMu.Lock()
+		s.lastStatus[c.Server] = c.New
+		s.statusMu.Unlock()
+		s.broadcastServerStatusChange(c.Server, c.Old, c.New)
+	}
+}
+
+// forwardApprovalChanges broadcasts the calls parked for approval by the
+// manager's proxies and their outcomes
+func (s *Server) forwardApprovalChanges(changes <-chan ApprovalChange) {
+	for c := range changes {
+		s.broadcastEvent(&pb.Event{
+			Type:      pb.EventType_APPROVAL,
+			Timestamp: time.Now().Unix(),
+			Payload: &pb.Event_Approval{
+				Approval: &pb.ApprovalEvent{
+					Approval: approvalToProto(c.Approval),
+					State:    c.State,
+					Reason:   c.Reason,
+				},
+			},
+		})
+	}
+}
+
+// queuedExporter is an exporter that can tell how many events it hasn't
+// sent yet
+type queuedExporter interface {
+	Queued() int
+}
+
+// Instrument reports the subscribers of the server and the events waiting
+// in its queues to self: the manager's event channels, the subscribers'
+// channels and the exporter's queue
+func (s *Server) Instrument(self *metrics.Self) {
+	self.Subscribers(func() int {
+		s.subscribersMu.RLock()
+		defer s.subscribersMu.RUnlock()
+		return len(s.subscribers)
+	})
+	self.Queue("subscribers", func() int {
+		s.subscribersMu.RLock()
+		defer s.subscribersMu.RUnlock()
+		queued := 0
+		for _, ch := range s.subscribers {
+			queued += len(ch)
+		}
+		return queued
+	})
+	for name, depth := range s.queues {
+		self.Queue(name, depth)
+	}
+	self.Queue("monitor", func() int {
+		queued := 0
+		for _, queue := range s.broadcasts {
+			queued += len(queue)
+		}
+		return queued
+	})
+	if exporter, ok := s.exporter.(queuedExporter); ok {
+		self.Queue("export", exporter.Queued)
+	}
+}
+
 // broadcastEvent sends an event to all subscribers
 func (s *Server) broadcastEvent(event *pb.Event) {
+	if s.journal != nil {
+		s.journal.Export(event)
+	}
+	if s.exporter != nil {
+		s.exporter.Export(event)
+	}
+	if s.notifier != nil {
+		s.notifier.Export(event)
+	}
+	if s.uptime != nil {
+		s.uptime.Export(event)
+	}
+
 	s.subscribersMu.RLock()
 	defer s.subscribersMu.RUnlock()
 
@@ -394,17 +1485,114 @@ func serverToProto(srv *server.Server) *pb.Server {
 		}
 	}
 
+	var restartPolicy string
+	var maxRestarts int32
+	if srv.RestartPolicy != nil {
+		restartPolicy = string(srv.RestartPolicy.Mode)
+		maxRestarts = int32(srv.RestartPolicy.MaxRestarts)
+	}
+
+	var startedAt int64
+	if !srv.StartedAt.IsZero() {
+		startedAt = srv.StartedAt.Unix()
+	}
+
 	return &pb.Server{
-		Name:        srv.Name,
-		Command:     srv.Command,
-		Port:        int32(srv.Port),
-		Description: srv.Description,
-		Status:      statusToProto(srv.Status),
-		Pid:         int32(srv.PID),
-		ToolCount:   int32(srv.ToolCount),
-		Tools:       tools,
-		LastUpdated: srv.LastUpdated.Unix(),
+		Name:           srv.Name,
+		Command:        srv.Command,
+		Port:           int32(srv.Port),
+		Description:    srv.Description,
+		Status:         statusToProto(srv.Status),
+		Pid:            int32(srv.PID),
+		ToolCount:      int32(srv.ToolCount),
+		Tools:          tools,
+		LastUpdated:    srv.LastUpdated.Unix(),
+		Health:         string(srv.Health),
+		HealthMessage:  srv.HealthMessage,
+		Host:           srv.Host,
+		RestartPolicy:  restartPolicy,
+		MaxRestarts:    maxRestarts,
+		Restarts:       int32(srv.Restarts),
+		Maintenance:    srv.Maintenance,
+		Requests:       srv.Requests,
+		StartedAt:      startedAt,
+		Autostart:      srv.Autostart,
+		RunId:          srv.RunID,
+		Group:          srv.Group,
+		Draining:       srv.Draining,
+		InFlight:       srv.InFlight,
+		BrowserProfile: srv.BrowserProfile,
+		Tags:           srv.Tags,
+	}
+}
+
+func fleetToProto(fleet *Fleet) *pb.Fleet {
+	msg := &pb.Fleet{
+		RunId:   fleet.RunID,
+		Dir:     fleet.Dir,
+		Created: fleet.Created.Unix(),
+	}
+	for _, srv := range fleet.Servers {
+		msg.Servers = append(msg.Servers, serverToProto(srv))
+	}
+	return msg
+}
+
+func approvalToProto(approval Approval) *pb.Approval {
+	return &pb.Approval{
+		Id:          approval.ID,
+		ServerName:  approval.Server,
+		Tool:        approval.Tool,
+		Arguments:   approval.Arguments,
+		RequestedAt: approval.Requested.Unix(),
+		Deadline:    approval.Deadline.Unix(),
+		RequestId:   approval.RequestID,
+		RunId:       approval.RunID,
+	}
+}
+
+// sessionToProto converts a session transcript to its protobuf message
+func sessionToProto(session *transcript.Session) *pb.TranscriptSession {
+	msg := &pb.TranscriptSession{
+		Id:      session.ID,
+		Client:  session.Client,
+		Started: session.Started.Unix(),
+		Updated: session.Updated.Unix(),
+		Calls:   int32(session.Calls),
+		Errors:  int32(session.Errors),
+	}
+	for _, entry := range session.Entries {
+		msg.Entries = append(msg.Entries, &pb.TranscriptEntry{
+			TimestampMs: entry.Time.UnixMilli(),
+			ServerName:  entry.Server,
+			Method:      entry.Method,
+			Tool:        entry.Tool,
+			Arguments:   entry.Arguments,
+			Result:      entry.Result,
+			Error:       entry.Error,
+			DurationMs:  entry.DurationMs,
+			RequestId:   entry.RequestID,
+			RunId:       entry.RunID,
+		})
+	}
+	return msg
+}
+
+// errorCode returns the gRPC code of an error from the manager
+func errorCode(err error) codes.Code {
+	switch {
+	case errors.Is(err, server.ErrNotFound):
+		return codes.NotFound
+	case errors.Is(err, server.ErrAlreadyRunning), errors.Is(err, server.ErrNotRunning):
+		return codes.FailedPrecondition
+	case errors.Is(err, server.ErrExists):
+		return codes.AlreadyExists
+	}
+	// Errors forwarded from other daemons keep their code
+	if s, ok := status.FromError(err); ok && s.Code() != codes.Unknown {
+		return s.Code()
 	}
+	return codes.Internal
 }
 
 func statusToProto(status server.Status) pb.ServerStatus {
@@ -424,6 +1612,30 @@ func statusToProto(status server.Status) pb.ServerStatus {
 	}
 }
 
+func logLineToProto(line logs.Line) *pb.LogLine {
+	return &pb.LogLine{
+		TimestampMs: line.Time.UnixMilli(),
+		Stream:      line.Stream,
+		Text:        line.Text,
+		Severity:    severityToProto(line.Severity),
+	}
+}
+
+func severityToProto(severity logs.Severity) pb.LogSeverity {
+	switch severity {
+	case logs.SeverityDebug:
+		return pb.LogSeverity_LOG_DEBUG
+	case logs.SeverityInfo:
+		return pb.LogSeverity_LOG_INFO
+	case logs.SeverityWarning:
+		return pb.LogSeverity_LOG_WARNING
+	case logs.SeverityError:
+		return pb.LogSeverity_LOG_ERROR
+	default:
+		return pb.LogSeverity_LOG_UNKNOWN
+	}
+}
+
 func shouldSendEvent(event *pb.Event, types []pb.EventType) bool {
 	if len(types) == 0 || containsEventType(types, pb.EventType_ALL) {
 		return true
@@ -441,17 +1653,152 @@ func containsEventType(types []pb.EventType, target pb.EventType) bool {
 	return false
 }
 
+// ServeOptions configures the endpoints of the daemon API
+type ServeOptions struct {
+	// WebPort serves gRPC-Web and gRPC over cleartext HTTP/2 (h2c), so
+	// browser dashboards can call the API directly; zero disables it
+	WebPort int
+
+	// CORSOrigins lists the browser origins allowed to call the gRPC-Web
+	// endpoint; empty allows only its own and loopback origins
+	CORSOrigins []string
+
+	// Exporter receives every event broadcast to subscribers; nil disables
+	// exporting
+	Exporter EventExporter
+
+	// Journal records the broadcast events for QueryEvents; nil disables
+	// the RPC
+	Journal *journal.Journal
+
+	// Validator checks the configuration for ValidateConfig; nil disables
+	// the RPC
+	Validator ConfigValidator
+
+	// Providers selects the servers of generic gateway tools for
+	// ListProviders and PinProvider; nil disables the RPCs
+	Providers ProviderSelector
+
+	// Notifier shows desktop notifications of the broadcast events and
+	// serves ListNotifications and SetNotification; nil disables them
+	Notifier Notifier
+
+	// DocsDir holds the tool documentation served under DocsPath of the
+	// gRPC-Web endpoint; empty serves none
+	DocsDir string
+
+	// Auth rejects RPCs from clients it doesn't authenticate, also over
+	// gRPC-Web; nil leaves the API open
+	Auth auth.Provider
+
+	// TLS serves the API and the gRPC-Web endpoint over TLS; nil serves
+	// them in cleartext
+	TLS *tls.Config
+
+	// Socket serves the API on the unix socket at this path instead of the
+	// port; empty serves it over TCP
+	Socket string
+
+	// Host is the interface the API and the gRPC-Web endpoint listen on;
+	// empty listens on all interfaces
+	Host string
+
+	// Uptime records the status changes for GetUptime; nil disables the
+	// RPC
+	Uptime *uptime.Tracker
+
+	// Monitor tunes the detection of status and tool changes; zero fields
+	// take their default
+	Monitor MonitorOptions
+
+	// Self receives the subscriber count and the queue depths of the
+	// server for the daemon's metrics; nil reports none
+	Self *metrics.Self
+
+	// API is the server to serve, from NewAPI, so other endpoints can
+	// share it; nil creates one
+	API *Server
+}
+
+// NewAPI creates the server Serve serves for mgr. Each server monitors the
+// manager and consumes its events, so endpoints of the same daemon must
+// share one.
+func NewAPI(mgr ManagerInterface, opts ServeOptions) *Server {
+	srv := newServer(mgr, opts.Monitor)
+	srv.exporter = opts.Exporter
+	srv.journal = opts.Journal
+	srv.validator = opts.Validator
+	srv.providers = opts.Providers
+	srv.notifier = opts.Notifier
+	srv.uptime = opts.Uptime
+	if opts.Self != nil {
+		srv.Instrument(opts.Self)
+	}
+	return srv
+}
+
 // Serve starts the gRPC server
-func Serve(mgr ManagerInterface, port int) error {
-	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+func Serve(mgr ManagerInterface, port int, opts ServeOptions) error {
+	var lis net.Listener
+	var err error
+	if opts.Socket != "" {
+		lis, err = listenUnix(opts.Socket)
+	} else if lis, err = net.Listen("tcp", net.JoinHostPort(opts.Host, strconv.Itoa(port))); err != nil {
+		err = fmt.Errorf("failed to listen: %w", err)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to listen: %w", err)
+		return err
 	}
 
-	grpcServer := grpc.NewServer()
-	srv := NewServer(mgr)
+	// Keepalive pings detect half-open connections without any traffic
+	serverOpts := []grpc.ServerOption{
+		grpc.KeepaliveParams(keepalive.ServerParameters{Time: 10 * time.Second, Timeout: 5 * time.Second}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{MinTime: 5 * time.Second, PermitWithoutStream: true}),
+	}
+	if opts.TLS != nil {
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(opts.TLS)))
+	}
+	if opts.Auth != nil {
+		serverOpts = append(serverOpts, authInterceptors(opts.Auth)...)
+	}
+	grpcServer := grpc.NewServer(serverOpts...)
+	srv := opts.API
+	if srv == nil {
+		srv = NewAPI(mgr, opts)
+	}
 	pb.RegisterMCPManagerServer(grpcServer, srv)
 
-	log.Printf("gRPC server listening on port %d", port)
+	if opts.WebPort > 0 {
+		webLis, err := net.Listen("tcp", net.JoinHostPort(opts.Host, strconv.Itoa(opts.WebPort)))
+		if err != nil {
+			lis.Close()
+			return fmt.Errorf("failed to listen for gRPC-Web: %w", err)
+		}
+
+		handler := WebHandler(grpcServer, opts.CORSOrigins)
+		if opts.DocsDir != "" {
+			handler = DocsHandler(opts.DocsDir, opts.Auth, handler)
+		}
+		webServer := &http.Server{Handler: h2c.NewHandler(handler, &http2.Server{})}
+		serve := webServer.Serve
+		if opts.TLS != nil {
+			// HTTP/2 is negotiated over TLS instead
+			webServer.Handler = handler
+			webServer.TLSConfig = opts.TLS.Clone()
+			serve = func(l net.Listener) error { return webServer.ServeTLS(l, "", "") }
+		}
+		go func() {
+			if err := serve(webLis); err != nil && err != http.ErrServerClosed {
+				log.Printf("gRPC-Web server error: %v", err)
+			}
+		}()
+		log.Printf("gRPC-Web server listening on %s", webLis.Addr())
+	}
+
+	if opts.Socket != "" {
+		log.Printf("gRPC server listening on %s", opts.Socket)
+	} else {
+		log.Printf("gRPC server listening on %s", lis.Addr())
+	}
 	return grpcServer.Serve(lis)
 }
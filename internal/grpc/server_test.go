@@ -2,114 +2,45 @@ package grpc
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/apitest"
 	pb "github.com/tartavull/mcp-manager/internal/grpc/pb"
+	"github.com/tartavull/mcp-manager/internal/journal"
+	"github.com/tartavull/mcp-manager/internal/logs"
+	"github.com/tartavull/mcp-manager/internal/metrics"
+	"github.com/tartavull/mcp-manager/internal/plugin"
 	"github.com/tartavull/mcp-manager/internal/server"
+	"github.com/tartavull/mcp-manager/internal/transcript"
+	"github.com/tartavull/mcp-manager/internal/uptime"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
 )
 
-// Mock manager for testing
-type mockManager struct {
-	servers     map[string]*server.Server
-	serverOrder []string
-	configPath  string
-}
-
-func (m *mockManager) GetServers() (map[string]*server.Server, []string, error) {
-	return m.servers, m.serverOrder, nil
-}
-
-func (m *mockManager) GetServerOrder() ([]string, error) {
-	return m.serverOrder, nil
-}
-
-func (m *mockManager) GetServer(name string) (*server.Server, error) {
-	srv, exists := m.servers[name]
-	if !exists {
-		return nil, fmt.Errorf("server %s not found", name)
-	}
-	return srv, nil
-}
-
-func (m *mockManager) StartServer(name string) error {
-	if srv, exists := m.servers[name]; exists {
-		srv.Status = server.StatusRunning
-		srv.PID = 12345
-		return nil
-	}
-	return fmt.Errorf("server not found")
-}
-
-func (m *mockManager) StopServer(name string) error {
-	if srv, exists := m.servers[name]; exists {
-		srv.Status = server.StatusStopped
-		srv.PID = 0
-		return nil
-	}
-	return fmt.Errorf("server not found")
-}
-
-func (m *mockManager) GetConfigPath() (string, error) {
-	return m.configPath, nil
-}
-
-func (m *mockManager) UpdateToolCounts() error {
-	// No-op for tests
-	return nil
-}
-
-func (m *mockManager) StopAllServers() {
-	for _, srv := range m.servers {
-		srv.Status = server.StatusStopped
-		srv.PID = 0
-	}
-}
-
-func (m *mockManager) Stop() error {
-	return nil
-}
-
 // Helper to create test server with in-memory connection
-func setupTestServer(t *testing.T) (*grpc.ClientConn, pb.MCPManagerClient, *mockManager) {
-	// Create mock manager
-	mgr := &mockManager{
-		servers: map[string]*server.Server{
-			"test-server": {
-				Name:        "test-server",
-				Command:     "echo test",
-				Port:        4001,
-				Description: "Test server",
-				Status:      server.StatusStopped,
-				Tools: []server.Tool{
-					{Name: "tool1", Description: "Tool 1"},
-					{Name: "tool2", Description: "Tool 2"},
-				},
-				ToolCount: 2,
-			},
-			"another-server": {
-				Name:        "another-server",
-				Command:     "echo another",
-				Port:        4002,
-				Description: "Another test server",
-				Status:      server.StatusRunning,
-				PID:         54321,
-			},
-		},
-		serverOrder: []string{"test-server", "another-server"},
-		configPath:  "/test/config.json",
-	}
+func setupTestServer(t *testing.T, configure ...func(*Server)) (*grpc.ClientConn, pb.MCPManagerClient, *apitest.Manager) {
+	// Create fake manager
+	mgr := apitest.NewManager()
+	addTestServers(t, mgr)
 
 	// Create gRPC server
 	grpcServer := grpc.NewServer()
 	srv := NewServer(mgr)
+	for _, fn := range configure {
+		fn(srv)
+	}
 	pb.RegisterMCPManagerServer(grpcServer, srv)
 
 	// Create in-memory connection
@@ -140,6 +71,40 @@ func setupTestServer(t *testing.T) (*grpc.ClientConn, pb.MCPManagerClient, *mock
 	return conn, client, mgr
 }
 
+// setupFullServer starts a server for a fake manager with every optional
+// capability and the servers of setupTestServer
+func setupFullServer(t *testing.T) (*Server, *Client, *apitest.Full) {
+	mgr := apitest.NewFull()
+	addTestServers(t, mgr.Manager)
+	srv := NewServer(mgr)
+	return srv, newClient(dialTestServer(t, srv), DefaultBackoff), mgr
+}
+
+// addTestServers adds a stopped server with tools and a running one
+func addTestServers(t *testing.T, mgr *apitest.Manager) {
+	mgr.SetConfigPath("/test/config.json")
+	require.NoError(t, mgr.Add(&server.Server{
+		Name:        "test-server",
+		Command:     "echo test",
+		Port:        4001,
+		Description: "Test server",
+		Status:      server.StatusStopped,
+		Tools: []server.Tool{
+			{Name: "tool1", Description: "Tool 1", InputSchema: map[string]interface{}{"type": "object"}},
+			{Name: "tool2", Description: "Tool 2"},
+		},
+		ToolCount: 2,
+	}))
+	require.NoError(t, mgr.Add(&server.Server{
+		Name:        "another-server",
+		Command:     "echo another",
+		Port:        4002,
+		Description: "Another test server",
+		Status:      server.StatusRunning,
+		PID:         54321,
+	}))
+}
+
 func TestListServers(t *testing.T) {
 	_, client, _ := setupTestServer(t)
 
@@ -189,11 +154,13 @@ func TestStartServer(t *testing.T) {
 	resp, err := client.StartServer(ctx, &pb.ServerRequest{Name: "test-server"})
 	require.NoError(t, err)
 	assert.Equal(t, pb.ServerStatus_RUNNING, resp.Status)
-	assert.Equal(t, int32(12345), resp.Pid)
+	assert.Equal(t, int32(apitest.FirstPID), resp.Pid)
 
-	// Verify in mock manager
-	assert.Equal(t, server.StatusRunning, mgr.servers["test-server"].Status)
-	assert.Equal(t, 12345, mgr.servers["test-server"].PID)
+	// Verify in fake manager
+	srv, err := mgr.GetServer("test-server")
+	require.NoError(t, err)
+	assert.Equal(t, server.StatusRunning, srv.Status)
+	assert.Equal(t, apitest.FirstPID, srv.PID)
 }
 
 func TestStopServer(t *testing.T) {
@@ -206,9 +173,42 @@ func TestStopServer(t *testing.T) {
 	assert.Equal(t, pb.ServerStatus_STOPPED, resp.Status)
 	assert.Equal(t, int32(0), resp.Pid)
 
-	// Verify in mock manager
-	assert.Equal(t, server.StatusStopped, mgr.servers["another-server"].Status)
-	assert.Equal(t, 0, mgr.servers["another-server"].PID)
+	// Verify in fake manager
+	srv, err := mgr.GetServer("another-server")
+	require.NoError(t, err)
+	assert.Equal(t, server.StatusStopped, srv.Status)
+	assert.Equal(t, 0, srv.PID)
+}
+
+func TestTaggedServers(t *testing.T) {
+	_, client, mgr := setupTestServer(t)
+	ctx := context.Background()
+	require.NoError(t, mgr.Add(&server.Server{Name: "browser", Port: 4003, Tags: []string{"web"}}))
+	for _, name := range []string{"test-server", "another-server"} {
+		srv, err := mgr.GetServer(name)
+		require.NoError(t, err)
+		srv.Tags = []string{"web"}
+	}
+
+	// Servers already running are left alone
+	list, err := client.StartTagged(ctx, &pb.TagRequest{Tag: "web"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"test-server", "browser"}, list.Order)
+	assert.Equal(t, []string{"web"}, list.Servers[0].Tags)
+	assert.Equal(t, pb.ServerStatus_RUNNING, list.Servers[1].Status)
+
+	list, err = client.StopTagged(ctx, &pb.TagRequest{Tag: "web"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"browser", "another-server", "test-server"}, list.Order)
+	servers, _, _ := mgr.GetServers()
+	for name, srv := range servers {
+		assert.Equal(t, server.StatusStopped, srv.Status, name)
+	}
+
+	_, err = client.StopTagged(ctx, &pb.TagRequest{Tag: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = client.StartTagged(ctx, &pb.TagRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
 }
 
 func TestGetTools(t *testing.T) {
@@ -220,6 +220,8 @@ func TestGetTools(t *testing.T) {
 	assert.Len(t, resp.Tools, 2)
 	assert.Equal(t, "tool1", resp.Tools[0].Name)
 	assert.Equal(t, "Tool 1", resp.Tools[0].Description)
+	assert.JSONEq(t, `{"type": "object"}`, resp.Tools[0].InputSchema)
+	assert.Empty(t, resp.Tools[1].InputSchema)
 }
 
 func TestGetConfig(t *testing.T) {
@@ -256,6 +258,28 @@ func TestHealth(t *testing.T) {
 	assert.Equal(t, int32(2), resp.TotalServers)
 }
 
+func TestSetMaintenance(t *testing.T) {
+	_, client, mgr := setupTestServer(t)
+	ctx := context.Background()
+
+	_, err := client.SetMaintenance(ctx, &pb.MaintenanceRequest{Name: "test-server", Enabled: true})
+	require.NoError(t, err)
+	resp, err := client.GetServer(ctx, &pb.ServerRequest{Name: "test-server"})
+	require.NoError(t, err)
+	assert.True(t, resp.Maintenance)
+
+	_, err = client.SetMaintenance(ctx, &pb.MaintenanceRequest{Enabled: true})
+	require.NoError(t, err)
+	maintenance, _ := mgr.Maintenance()
+	assert.True(t, maintenance)
+	health, err := client.Health(ctx, &pb.Empty{})
+	require.NoError(t, err)
+	assert.True(t, health.Maintenance)
+
+	_, err = client.SetMaintenance(ctx, &pb.MaintenanceRequest{Name: "non-existent", Enabled: true})
+	assert.Error(t, err)
+}
+
 func TestSubscribe(t *testing.T) {
 	_, client, mgr := setupTestServer(t)
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -331,3 +355,897 @@ func TestHelperFunctions(t *testing.T) {
 	assert.True(t, shouldSendEvent(event, []pb.EventType{pb.EventType_SERVER_STATUS}))
 	assert.False(t, shouldSendEvent(event, []pb.EventType{pb.EventType_TOOL_UPDATE}))
 }
+
+func TestSubscribe_Heartbeat(t *testing.T) {
+	conn, client, mgr := setupTestServer(t, func(s *Server) {
+		s.heartbeatInterval = 50 * time.Millisecond
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Heartbeats are sent whatever the requested event types
+	stream, err := client.Subscribe(ctx, &pb.SubscribeRequest{
+		EventTypes: []pb.EventType{pb.EventType_FAILOVER},
+	})
+	require.NoError(t, err)
+
+	event, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, pb.EventType_HEARTBEAT, event.Type)
+	assert.Equal(t, int64(50), event.GetHeartbeat().IntervalMs)
+
+	// The client records heartbeats without reporting them as events
+	c := newClient(conn, DefaultBackoff)
+	require.NoError(t, c.Subscribe())
+	defer c.Close()
+	start := c.LastHeartbeat()
+	assert.True(t, c.Connected())
+
+	require.Eventually(t, func() bool {
+		return c.LastHeartbeat().After(start)
+	}, 2*time.Second, 10*time.Millisecond)
+
+	mgr.StartServer("test-server")
+	select {
+	case event := <-c.Events():
+		assert.NotEqual(t, pb.EventType_HEARTBEAT.String(), event.Type)
+	case <-time.After(3 * time.Second):
+		t.Fatal("no server status event")
+	}
+}
+
+// blockedStream is a subscriber that stopped reading events
+type blockedStream struct {
+	pb.MCPManager_SubscribeServer
+	unblock chan struct{}
+}
+
+func (s blockedStream) Send(*pb.Event) error {
+	<-s.unblock
+	return fmt.Errorf("stream closed")
+}
+
+func TestSendEvent_Timeout(t *testing.T) {
+	stream := blockedStream{unblock: make(chan struct{})}
+	defer close(stream.unblock)
+
+	err := sendEvent(stream, &pb.Event{}, 10*time.Millisecond)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "stopped receiving")
+}
+
+func TestBackoff_Delay(t *testing.T) {
+	b := Backoff{Initial: 100 * time.Millisecond, Max: time.Second, Window: time.Minute}
+
+	for attempt, want := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		want *= time.Millisecond
+		for i := 0; i < 20; i++ {
+			delay := b.delay(attempt)
+			assert.GreaterOrEqual(t, delay, want/2, "attempt %d", attempt)
+			assert.LessOrEqual(t, delay, want, "attempt %d", attempt)
+		}
+	}
+}
+
+// droppingServer fails the first drops subscriptions, recording every
+// subscription request
+type droppingServer struct {
+	*Server
+	drops    int
+	requests chan *pb.SubscribeRequest
+}
+
+func (s *droppingServer) Subscribe(req *pb.SubscribeRequest, stream pb.MCPManager_SubscribeServer) error {
+	s.requests <- req
+	if s.drops != 0 {
+		s.drops--
+		return status.Error(codes.Unavailable, "daemon restarting")
+	}
+	return s.Server.Subscribe(req, stream)
+}
+
+// setupDroppingServer starts a server failing the first drops
+// subscriptions, or all of them if drops is negative
+func setupDroppingServer(t *testing.T, drops int) (*grpc.ClientConn, *droppingServer) {
+	_, _, mgr := setupTestServer(t)
+	srv := &droppingServer{
+		Server:   NewServer(mgr),
+		drops:    drops,
+		requests: make(chan *pb.SubscribeRequest, 100),
+	}
+	srv.heartbeatInterval = 20 * time.Millisecond
+	return dialTestServer(t, srv), srv
+}
+
+// dialTestServer serves srv in memory and connects to it
+func dialTestServer(t *testing.T, srv pb.MCPManagerServer) *grpc.ClientConn {
+	grpcServer := grpc.NewServer()
+	pb.RegisterMCPManagerServer(grpcServer, srv)
+	lis := bufconn.Listen(1024 * 1024)
+	go grpcServer.Serve(lis)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		conn.Close()
+		grpcServer.Stop()
+	})
+	return conn
+}
+
+func TestClient_Reconnect(t *testing.T) {
+	conn, srv := setupDroppingServer(t, 2)
+
+	c := newClient(conn, Backoff{Initial: 10 * time.Millisecond, Max: 40 * time.Millisecond, Window: 5 * time.Second})
+	defer c.Close()
+
+	states := make(chan ConnectionState, 10)
+	c.SetOnConnectionState(func(state ConnectionState) { states <- state })
+
+	filters := []pb.EventType{pb.EventType_SERVER_STATUS, pb.EventType_FAILOVER}
+	require.NoError(t, c.Subscribe(filters...))
+
+	// Every resubscription keeps the chosen filters
+	for i := 0; i < 3; i++ {
+		select {
+		case req := <-srv.requests:
+			assert.Equal(t, filters, req.EventTypes)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("no subscription %d", i+1)
+		}
+	}
+
+	assert.Equal(t, StateConnected, <-states)
+	assert.Equal(t, StateReconnecting, <-states)
+	assert.Equal(t, StateConnected, <-states)
+
+	// Receiving events resets the retries
+	require.Eventually(t, func() bool {
+		c.eventMu.Lock()
+		defer c.eventMu.Unlock()
+		return c.retryAttempt == 0 && c.retryStart.IsZero()
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, StateConnected, c.State())
+}
+
+func TestClient_ReconnectGivesUp(t *testing.T) {
+	conn, _ := setupDroppingServer(t, -1)
+
+	c := newClient(conn, Backoff{Initial: 10 * time.Millisecond, Max: 20 * time.Millisecond, Window: 100 * time.Millisecond})
+	defer c.Close()
+	require.NoError(t, c.Subscribe())
+
+	require.Eventually(t, func() bool {
+		return c.State() == StateDisconnected
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.True(t, c.Retry().Next.IsZero())
+}
+
+func TestClient_ReconnectNow(t *testing.T) {
+	conn, srv := setupDroppingServer(t, -1)
+
+	c := newClient(conn, Backoff{Initial: time.Hour, Max: time.Hour, Window: 3 * time.Hour})
+	defer c.Close()
+	require.NoError(t, c.Subscribe())
+	<-srv.requests
+
+	// The countdown to the next attempt is known, and can be skipped
+	require.Eventually(t, func() bool {
+		return !c.Retry().Next.IsZero()
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.WithinDuration(t, time.Now().Add(45*time.Minute), c.Retry().Next, 16*time.Minute)
+	assert.Equal(t, StateReconnecting, c.State())
+
+	c.Reconnect()
+	select {
+	case <-srv.requests:
+	case <-time.After(2 * time.Second):
+		t.Fatal("no attempt after Reconnect")
+	}
+	require.Eventually(t, func() bool {
+		return c.Retry().Attempt == 2
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestClient_ReconnectAfterGivingUp(t *testing.T) {
+	conn, srv := setupDroppingServer(t, -1)
+
+	c := newClient(conn, Backoff{Initial: 10 * time.Millisecond, Max: 20 * time.Millisecond, Window: 100 * time.Millisecond})
+	defer c.Close()
+	require.NoError(t, c.Subscribe())
+	require.Eventually(t, func() bool {
+		return c.State() == StateDisconnected
+	}, 2*time.Second, 10*time.Millisecond)
+	for len(srv.requests) > 0 {
+		<-srv.requests
+	}
+
+	// Attempts start over
+	c.Reconnect()
+	select {
+	case <-srv.requests:
+	case <-time.After(2 * time.Second):
+		t.Fatal("no attempt after Reconnect")
+	}
+}
+
+func TestStreamLogs(t *testing.T) {
+	_, _, mgr := setupTestServer(t)
+	buffer, err := mgr.Logs("test-server")
+	require.NoError(t, err)
+	buffer.Add("stdout", "starting")
+	buffer.Add("stderr", "ERROR: missing token")
+
+	conn := dialTestServer(t, NewServer(mgr))
+	c := newClient(conn, DefaultBackoff)
+
+	// Without following, the history is sent and the stream ends
+	var lines []logs.Line
+	err = c.StreamLogs(context.Background(), "test-server", false, 1, func(line logs.Line) {
+		lines = append(lines, line)
+	})
+	require.NoError(t, err)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "ERROR: missing token", lines[0].Text)
+	assert.Equal(t, "stderr", lines[0].Stream)
+	assert.Equal(t, logs.SeverityError, lines[0].Severity)
+	assert.WithinDuration(t, time.Now(), lines[0].Time, time.Minute)
+
+	// Following sends new lines until the context is done
+	ctx, cancel := context.WithCancel(context.Background())
+	received := make(chan logs.Line, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- c.StreamLogs(ctx, "test-server", true, -1, func(line logs.Line) {
+			received <- line
+		})
+	}()
+
+	require.Eventually(t, func() bool {
+		buffer.Add("stdout", "new line")
+		return len(received) > 0
+	}, 2*time.Second, 20*time.Millisecond)
+	assert.Equal(t, "new line", (<-received).Text)
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("stream not closed")
+	}
+
+	err = c.StreamLogs(context.Background(), "missing", false, 0, func(logs.Line) {})
+	require.Error(t, err)
+	assert.Equal(t, codes.NotFound, status.Code(errors.Unwrap(err)))
+}
+
+func TestStreamLogs_Unimplemented(t *testing.T) {
+	_, _, mgr := setupTestServer(t)
+
+	// Hide the manager's logs behind the bare interface
+	client := pb.NewMCPManagerClient(dialTestServer(t, NewServer(struct{ ManagerInterface }{mgr})))
+
+	stream, err := client.StreamLogs(context.Background(), &pb.LogsRequest{Name: "test-server"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+// fakeExporter records exported events
+type fakeExporter struct {
+	events chan *pb.Event
+}
+
+func (e *fakeExporter) Export(event *pb.Event) {
+	e.events <- event
+}
+
+func TestServer_Exporter(t *testing.T) {
+	_, _, mgr := setupTestServer(t)
+	srv := NewServer(mgr)
+	exporter := &fakeExporter{events: make(chan *pb.Event, 10)}
+	srv.exporter = exporter
+
+	srv.broadcastServerStatusChange("test-server", server.StatusStopped, server.StatusRunning)
+
+	event := <-exporter.events
+	assert.Equal(t, pb.EventType_SERVER_STATUS, event.Type)
+	assert.Equal(t, "test-server", event.GetServerStatus().ServerName)
+}
+
+func TestQueryEvents(t *testing.T) {
+	_, client, mgr := setupTestServer(t)
+
+	// Without a journal, queries are unimplemented
+	_, err := client.QueryEvents(context.Background(), &pb.EventQuery{})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+
+	j, err := journal.Open(t.TempDir(), journal.Options{})
+	require.NoError(t, err)
+	defer j.Close()
+
+	srv := NewServer(mgr)
+	srv.journal = j
+	c := newClient(dialTestServer(t, srv), DefaultBackoff)
+
+	srv.broadcastServerStatusChange("test-server", server.StatusStopped, server.StatusRunning)
+	srv.broadcastServerStatusChange("other-server", server.StatusStopped, server.StatusRunning)
+
+	result, err := c.QueryEvents(&pb.EventQuery{Server: "test-server"})
+	require.NoError(t, err)
+	assert.False(t, result.Truncated)
+	require.Len(t, result.Events, 1)
+	assert.Equal(t, pb.ServerStatus_RUNNING, result.Events[0].GetServerStatus().NewStatus)
+
+	result, err = c.QueryEvents(&pb.EventQuery{Limit: 1})
+	require.NoError(t, err)
+	assert.True(t, result.Truncated)
+	assert.Len(t, result.Events, 1)
+
+	result, err = c.QueryEvents(&pb.EventQuery{EventTypes: []pb.EventType{pb.EventType_FAILOVER}})
+	require.NoError(t, err)
+	assert.Empty(t, result.Events)
+}
+
+// fakeValidator returns a fixed validation
+type fakeValidator struct {
+	validation *Validation
+}
+
+func (v fakeValidator) ValidateConfig() (*Validation, error) {
+	return v.validation, nil
+}
+
+func TestValidateConfig(t *testing.T) {
+	_, client, mgr := setupTestServer(t)
+
+	// Without a validator, validation is unimplemented
+	_, err := client.ValidateConfig(context.Background(), &pb.Empty{})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+
+	want := &Validation{
+		Problems: []string{"gateway requires a port"},
+		Conflicts: []ToolConflict{{
+			Tool:    "search",
+			Servers: []string{"github", "gitlab", "jira"},
+			Renamed: map[string]string{"gitlab": "gitlab_search"},
+			Hidden:  []string{"jira"},
+		}},
+	}
+	srv := NewServer(mgr)
+	srv.validator = fakeValidator{want}
+	c := newClient(dialTestServer(t, srv), DefaultBackoff)
+
+	got, err := c.ValidateConfig()
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestFleets(t *testing.T) {
+	_, client, _ := setupTestServer(t)
+
+	// Managers that don't copy servers don't create fleets
+	_, err := client.CreateFleet(context.Background(), &pb.FleetRequest{RunId: "run-1"})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+
+	_, c, _ := setupFullServer(t)
+
+	fleet, err := c.CreateFleet("run-1", []string{"test-server"}, true)
+	require.NoError(t, err)
+	assert.Equal(t, "run-1", fleet.RunID)
+	assert.Equal(t, "/test/fleets/run-1", fleet.Dir)
+	assert.WithinDuration(t, time.Now(), fleet.Created, time.Minute)
+	require.Len(t, fleet.Servers, 1)
+	assert.Equal(t, "test-server@run-1", fleet.Servers[0].Name)
+	assert.Equal(t, "run-1", fleet.Servers[0].RunID)
+	assert.Equal(t, apitest.FirstFleetPort, fleet.Servers[0].Port)
+	assert.Equal(t, server.StatusRunning, fleet.Servers[0].Status)
+
+	_, err = c.CreateFleet("run-1", []string{"test-server"}, false)
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	fleets, err := c.ListFleets()
+	require.NoError(t, err)
+	require.Len(t, fleets, 1)
+	assert.Equal(t, "run-1", fleets[0].RunID)
+
+	require.NoError(t, c.DestroyFleet("run-1"))
+	assert.Equal(t, codes.NotFound, status.Code(c.DestroyFleet("run-1")))
+}
+
+func TestDrainServer(t *testing.T) {
+	_, client, _ := setupTestServer(t)
+
+	// Managers that don't drain servers refuse
+	_, err := client.DrainServer(context.Background(), &pb.DrainRequest{Name: "another-server"})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+
+	_, c, mgr := setupFullServer(t)
+
+	require.NoError(t, c.DrainServer("another-server", 5*time.Second))
+	assert.Equal(t, []string{"drain another-server 5s"}, mgr.Calls())
+	srv, err := mgr.GetServer("another-server")
+	require.NoError(t, err)
+	assert.Equal(t, server.StatusStopped, srv.Status)
+
+	assert.Equal(t, codes.NotFound, status.Code(c.DrainServer("missing", 0)))
+}
+
+func TestProfiles(t *testing.T) {
+	_, client, _ := setupTestServer(t)
+
+	// Managers without browser profiles refuse
+	_, err := client.ListProfiles(context.Background(), &pb.ServerRequest{Name: "another-server"})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+
+	_, c, mgr := setupFullServer(t)
+	mgr.AddProfile(BrowserProfile{Server: "another-server", Name: "work", Dir: "/profiles/work", Size: 42, Attached: true})
+
+	snapshot, err := c.SnapshotProfile("another-server", "")
+	require.NoError(t, err)
+	assert.Equal(t, "/profiles/.snapshots/work/1.tar.gz", snapshot.Path)
+	assert.Equal(t, int64(42), snapshot.Size)
+
+	profiles, err := c.ListProfiles("another-server")
+	require.NoError(t, err)
+	require.Len(t, profiles, 1)
+	assert.Equal(t, "work", profiles[0].Name)
+	assert.Equal(t, int64(42), profiles[0].Size)
+	assert.True(t, profiles[0].Attached)
+	require.Len(t, profiles[0].Snapshots, 1)
+	assert.Equal(t, "1", profiles[0].Snapshots[0].Name)
+
+	require.NoError(t, c.ResetProfile("another-server", ""))
+	assert.Equal(t, codes.NotFound, status.Code(c.ResetProfile("missing", "")))
+	assert.Equal(t, codes.NotFound, status.Code(c.RestoreProfile("another-server", "work", "2")))
+}
+
+func TestDiskUsage(t *testing.T) {
+	_, client, _ := setupTestServer(t)
+
+	// Managers that don't track disk usage refuse
+	_, err := client.DiskUsage(context.Background(), &pb.DiskRequest{})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+
+	_, c, mgr := setupFullServer(t)
+	dirs := []DiskDir{{Kind: DiskCache, Label: "npx", Path: "/npx/another-server", Size: 100}}
+	mgr.SetDisk("another-server", dirs)
+
+	usage, err := c.DiskUsage([]string{"another-server"})
+	require.NoError(t, err)
+	assert.Equal(t, []ServerDisk{{Server: "another-server", Dirs: dirs}}, usage)
+	assert.Equal(t, int64(100), usage[0].Total(DiskCache))
+	assert.Zero(t, usage[0].Total(DiskData))
+
+	cleaned, err := c.CleanCaches([]string{"another-server"}, true)
+	require.NoError(t, err)
+	assert.Len(t, cleaned, 1)
+	assert.Equal(t, []string{"clean another-server dry-run"}, mgr.Calls())
+
+	_, err = c.CleanCaches([]string{"missing"}, false)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestBackups(t *testing.T) {
+	_, client, _ := setupTestServer(t)
+
+	// Managers that don't back up servers refuse
+	_, err := client.BackupServer(context.Background(), &pb.ServerRequest{Name: "another-server"})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+
+	_, c, _ := setupFullServer(t)
+
+	backup, err := c.BackupServer("another-server")
+	require.NoError(t, err)
+	assert.Equal(t, "/test/backups/another-server/1.tar.gz", backup.Path)
+	_, err = c.BackupServer("missing")
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	backups, err := c.ListBackups("another-server")
+	require.NoError(t, err)
+	assert.Equal(t, []Backup{*backup}, backups)
+
+	require.NoError(t, c.RestoreBackup("another-server", "1"))
+	assert.Equal(t, codes.NotFound, status.Code(c.RestoreBackup("another-server", "2")))
+}
+
+// fakeProviders selects the providers of a single generic tool
+type fakeProviders struct {
+	group ProviderGroup
+}
+
+func (f *fakeProviders) Providers() ([]ProviderGroup, error) {
+	return []ProviderGroup{f.group}, nil
+}
+
+func (f *fakeProviders) PinProvider(tool, name string) error {
+	if tool != f.group.Tool {
+		return fmt.Errorf("generic tool '%s' %w", tool, server.ErrNotFound)
+	}
+	f.group.Pinned = name
+	return nil
+}
+
+func TestProviders(t *testing.T) {
+	_, client, mgr := setupTestServer(t)
+
+	// Without a gateway, providers are unimplemented
+	_, err := client.ListProviders(context.Background(), &pb.Empty{})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+
+	providers := &fakeProviders{group: ProviderGroup{
+		Tool:     "search.web",
+		Selected: "brave",
+		Providers: []ProviderStats{
+			{Server: "brave", Tool: "brave_web_search", Running: true, Calls: 4, Errors: 1, ErrorRate: 0.2, Latency: 120 * time.Millisecond},
+			{Server: "google", Tool: "google_search"},
+		},
+	}}
+	srv := NewServer(mgr)
+	srv.providers = providers
+	c := newClient(dialTestServer(t, srv), DefaultBackoff)
+
+	groups, err := c.Providers()
+	require.NoError(t, err)
+	assert.Equal(t, []ProviderGroup{providers.group}, groups)
+
+	require.NoError(t, c.PinProvider("search.web", "google"))
+	assert.Equal(t, "google", providers.group.Pinned)
+	assert.Equal(t, codes.NotFound, status.Code(c.PinProvider("search.news", "google")))
+}
+
+// fakeNotifier keeps notification levels and the events it receives
+type fakeNotifier struct {
+	levels map[string]string
+	events []*pb.Event
+}
+
+func (f *fakeNotifier) Export(event *pb.Event) {
+	f.events = append(f.events, event)
+}
+
+func (f *fakeNotifier) Preferences() (string, []NotificationPreference, error) {
+	preferences := []NotificationPreference{}
+	for _, name := range []string{"alpha", "beta"} {
+		level, custom := f.levels[name]
+		if !custom {
+			level = "crash"
+		}
+		preferences = append(preferences, NotificationPreference{Server: name, Level: level, Custom: custom})
+	}
+	return "crash", preferences, nil
+}
+
+func (f *fakeNotifier) SetPreference(name, level string) error {
+	if name != "alpha" && name != "beta" {
+		return fmt.Errorf("server '%s' %w", name, server.ErrNotFound)
+	}
+	if level == "" {
+		delete(f.levels, name)
+	} else {
+		f.levels[name] = level
+	}
+	return nil
+}
+
+func TestNotifications(t *testing.T) {
+	_, client, mgr := setupTestServer(t)
+
+	// Without a notifier, notifications are unimplemented
+	_, err := client.ListNotifications(context.Background(), &pb.Empty{})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+
+	notifier := &fakeNotifier{levels: map[string]string{}}
+	srv := NewServer(mgr)
+	srv.notifier = notifier
+	c := newClient(dialTestServer(t, srv), DefaultBackoff)
+
+	require.NoError(t, c.SetNotification("beta", "mute"))
+	level, preferences, err := c.Notifications()
+	require.NoError(t, err)
+	assert.Equal(t, "crash", level)
+	assert.Equal(t, []NotificationPreference{
+		{Server: "alpha", Level: "crash"},
+		{Server: "beta", Level: "mute", Custom: true},
+	}, preferences)
+
+	require.NoError(t, c.SetNotification("beta", ""))
+	assert.Empty(t, notifier.levels)
+	assert.Equal(t, codes.NotFound, status.Code(c.SetNotification("gamma", "all")))
+
+	// Broadcast events are shown by the notifier
+	srv.broadcastServerStatusChange("alpha", server.StatusRunning, server.StatusError)
+	require.Len(t, notifier.events, 1)
+	assert.Equal(t, "alpha", notifier.events[0].GetServerStatus().ServerName)
+}
+
+func TestRegister_Unimplemented(t *testing.T) {
+	_, client, _ := setupTestServer(t)
+
+	// Daemons that aren't coordinators don't collide with the code of
+	// servers already in the requested state
+	_, err := client.Register(context.Background(), &pb.RegisterRequest{Host: "box", Address: "box:8080"})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+func TestErrorCode(t *testing.T) {
+	assert.Equal(t, codes.NotFound, errorCode(fmt.Errorf("server 'x' %w", server.ErrNotFound)))
+	assert.Equal(t, codes.FailedPrecondition, errorCode(fmt.Errorf("server 'x' is %w", server.ErrAlreadyRunning)))
+	assert.Equal(t, codes.FailedPrecondition, errorCode(fmt.Errorf("server 'x' is %w", server.ErrNotRunning)))
+	assert.Equal(t, codes.AlreadyExists, errorCode(fmt.Errorf("fleet 'x' %w", server.ErrExists)))
+	assert.Equal(t, codes.Unavailable, errorCode(status.Error(codes.Unavailable, "peer down")))
+	assert.Equal(t, codes.Internal, errorCode(errors.New("exec failed")))
+}
+
+func TestApprovals(t *testing.T) {
+	_, client, _ := setupTestServer(t)
+
+	// Managers that don't park calls don't serve approvals
+	_, err := client.ListApprovals(context.Background(), &pb.Empty{})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+
+	srv, c, mgr := setupFullServer(t)
+	events := make(chan *pb.Event, 2)
+	srv.subscribersMu.Lock()
+	srv.subscribers["test"] = events
+	srv.subscribersMu.Unlock()
+
+	requested := time.Unix(1000, 0)
+	approval := Approval{ID: "ab12", Server: "slack", Tool: "post_message", Arguments: `{"channel":"#general"}`, Requested: requested, Deadline: requested.Add(time.Minute)}
+	mgr.ParkCall(approval)
+
+	approvals, err := c.ListApprovals()
+	require.NoError(t, err)
+	assert.Equal(t, []Approval{approval}, approvals)
+
+	require.NoError(t, c.DecideApproval("ab12", false, "no"))
+	assert.Empty(t, mgr.PendingApprovals())
+	err = c.DecideApproval("ab12", true, "")
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// Changes are broadcast as events
+	for _, state := range []string{ApprovalPending, ApprovalRejected} {
+		event := <-events
+		assert.Equal(t, pb.EventType_APPROVAL, event.Type)
+		assert.Equal(t, "slack", event.ServerName())
+		assert.Equal(t, state, event.GetApproval().State)
+	}
+}
+
+func TestReloadEvents(t *testing.T) {
+	srv, _, mgr := setupFullServer(t)
+	events := make(chan *pb.Event, 1)
+	srv.subscribersMu.Lock()
+	srv.subscribers["test"] = events
+	srv.subscribersMu.Unlock()
+
+	mgr.ReportConfigChange(ConfigChange{Modified: []string{"github"}, Updated: []string{"slack"}})
+	event := <-events
+	assert.Equal(t, pb.EventType_CONFIG_CHANGE, event.Type)
+	assert.Equal(t, []string{"github"}, event.GetConfigChange().ServersModified)
+	assert.Equal(t, []string{"slack"}, event.GetConfigChange().ServersUpdated)
+
+	mgr.ReportPortMigration(PortMigration{Server: "github", OldPort: 8001, NewPort: 8002, State: PortSwitched})
+	event = <-events
+	assert.Equal(t, pb.EventType_PORT_MIGRATION, event.Type)
+	assert.Equal(t, "github", event.ServerName())
+	assert.Equal(t, int32(8002), event.GetPortMigration().NewPort)
+	assert.Equal(t, PortSwitched, event.GetPortMigration().State)
+}
+
+func TestStatusChangeEvents(t *testing.T) {
+	srv, _, mgr := setupFullServer(t)
+	events := make(chan *pb.Event, 2)
+	srv.subscribersMu.Lock()
+	srv.subscribers["test"] = events
+	srv.subscribersMu.Unlock()
+
+	// A crash and restart between two polls are both broadcast
+	require.NoError(t, mgr.SetStatus("another-server", server.StatusError))
+	require.NoError(t, mgr.SetStatus("another-server", server.StatusRunning))
+	for _, expected := range []pb.ServerStatus{pb.ServerStatus_ERROR, pb.ServerStatus_RUNNING} {
+		event := <-events
+		assert.Equal(t, pb.EventType_SERVER_STATUS, event.Type)
+		assert.Equal(t, "another-server", event.ServerName())
+		assert.Equal(t, expected, event.GetServerStatus().NewStatus)
+	}
+
+	srv.statusMu.Lock()
+	assert.Equal(t, server.StatusRunning, srv.lastStatus["another-server"], "polling doesn't report them again")
+	srv.statusMu.Unlock()
+}
+
+func TestDescribeServer(t *testing.T) {
+	_, client, _ := setupTestServer(t)
+
+	// Managers that don't spawn servers don't describe them
+	_, err := client.DescribeServer(context.Background(), &pb.ServerRequest{Name: "github"})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+
+	_, c, mgr := setupFullServer(t)
+	require.NoError(t, mgr.AddServer("github", "npx server-github", 8001, "GitHub"))
+	want := &Runtime{
+		Server:  "github",
+		Command: "exec nice -n 10 sh -c 'npx server-github'",
+		Running: true,
+		Env:     []RuntimeVar{{Name: "GITHUB_TOKEN", Source: VarSecret}},
+		Path:    "/usr/bin:/bin",
+		Cwd:     "/home/user",
+		Limits:  []RuntimeLimit{{Name: "nice", Value: "10"}},
+	}
+	mgr.SetRuntime(want)
+
+	runtime, err := c.DescribeServer("github")
+	require.NoError(t, err)
+	assert.Equal(t, want, runtime)
+
+	_, err = c.DescribeServer("missing")
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestCloneServer(t *testing.T) {
+	_, client, _ := setupTestServer(t)
+
+	// Managers that don't edit mcp.json don't clone servers
+	_, err := client.CloneServer(context.Background(), &pb.CloneRequest{ServerName: "github"})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+
+	_, c, mgr := setupFullServer(t)
+	require.NoError(t, mgr.AddServer("filesystem", "server-filesystem /home/me/docs", 8002, "Files"))
+
+	name, port, err := c.CloneServer("filesystem", "", []string{"docs=code"})
+	require.NoError(t, err)
+	assert.Equal(t, "filesystem-2", name)
+	assert.Equal(t, 8003, port)
+	srv, err := mgr.GetServer("filesystem-2")
+	require.NoError(t, err)
+	assert.Equal(t, "server-filesystem /home/me/code", srv.Command)
+
+	_, _, err = c.CloneServer("missing", "", nil)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestSessions(t *testing.T) {
+	_, client, _ := setupTestServer(t)
+
+	// Managers that don't record transcripts don't serve sessions
+	_, err := client.ListSessions(context.Background(), &pb.SessionFilter{})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+
+	_, c, mgr := setupFullServer(t)
+	entry := transcript.Entry{Time: time.UnixMilli(1000500), Server: "github", Method: "tools/call", Tool: "create_issue",
+		Arguments: `{"title":"bug"}`, Result: `{"content":[]}`, DurationMs: 12,
+		Correlation: transcript.Correlation{RequestID: "req-1", RunID: "run-7"}}
+	mgr.Record("key:1a2b3c4d", entry)
+	mgr.Record("key:1a2b3c4d", transcript.Entry{Time: time.UnixMilli(1000600), Server: "github", Method: "tools/list"})
+
+	sessions, err := c.ListSessions()
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "key:1a2b3c4d", sessions[0].Client)
+	assert.Equal(t, 2, sessions[0].Calls)
+	assert.Empty(t, sessions[0].Entries)
+
+	session, err := c.GetSession(sessions[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, entry, session.Entries[0])
+
+	// Filtered sessions hold the calls given the request or run ID
+	found, err := c.FindSessions("run-7")
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, 1, found[0].Calls)
+	assert.Equal(t, []transcript.Entry{entry}, found[0].Entries)
+	found, err = c.FindSessions("run-8")
+	require.NoError(t, err)
+	assert.Empty(t, found)
+
+	_, err = c.GetSession("missing")
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestPlugins(t *testing.T) {
+	_, client, _ := setupTestServer(t)
+
+	// Managers without plugins don't list them
+	_, err := client.ListPlugins(context.Background(), &pb.Empty{})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+
+	plugins := []*plugin.Plugin{
+		{Name: "gvisor", Path: "/plugins/gvisor", Manifest: plugin.Manifest{
+			Version: "0.1.0", Description: "Runs servers in gVisor", Kinds: []string{plugin.KindTransport},
+			ConfigSchema: json.RawMessage(`{"type":"object"}`),
+		}},
+		{Name: "broken", Path: "/plugins/broken", Error: "describe failed: exit status 1"},
+	}
+	_, c, mgr := setupFullServer(t)
+	mgr.SetPlugins(plugins)
+
+	listed, err := c.ListPlugins()
+	require.NoError(t, err)
+	assert.Equal(t, plugins, listed)
+}
+
+func TestUptime(t *testing.T) {
+	_, client, mgr := setupTestServer(t)
+
+	// Daemons that don't track uptime refuse
+	_, err := client.GetUptime(context.Background(), &pb.UptimeRequest{})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+
+	tracker, err := uptime.Open(filepath.Join(t.TempDir(), "uptime.json"))
+	require.NoError(t, err)
+	srv := NewServer(mgr)
+	srv.uptime = tracker
+	c := newClient(dialTestServer(t, srv), DefaultBackoff)
+
+	srv.broadcastServerStatusChange("test-server", server.StatusRunning, server.StatusError)
+	stats, err := c.ServerUptime(7)
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+	assert.Equal(t, "test-server", stats[0].Server)
+	assert.Equal(t, 1, stats[0].Outages)
+
+	_, err = c.ServerUptime(uptime.Retention + 1)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestInstrument(t *testing.T) {
+	_, _, mgr := setupTestServer(t)
+	srv := NewServer(mgr)
+	ch := make(chan *pb.Event, 10)
+	ch <- &pb.Event{Type: pb.EventType_HEARTBEAT}
+	srv.subscribers["sub-1"] = ch
+
+	self := metrics.NewSelf()
+	srv.Instrument(self)
+	var b strings.Builder
+	metrics.WriteSelf(&b, self)
+	assert.Contains(t, b.String(), "mcp_daemon_subscribers 1\n")
+	assert.Contains(t, b.String(), `mcp_daemon_event_queue_depth{queue="subscribers"} 1`)
+}
+
+func TestCheckToolUpdates(t *testing.T) {
+	_, _, mgr := setupTestServer(t)
+	srv := newServer(mgr, MonitorOptions{Interval: time.Hour, ToolInterval: time.Hour, Workers: 2})
+	exporter := &fakeExporter{events: make(chan *pb.Event, 10)}
+	srv.exporter = exporter
+	require.NoError(t, mgr.SetStatus("test-server", server.StatusRunning))
+
+	updated := func() []string {
+		srv.checkToolUpdates()
+		var names []string
+		for {
+			select {
+			case event := <-exporter.events:
+				names = append(names, event.GetToolUpdate().ServerName)
+			case <-time.After(100 * time.Millisecond):
+				return names
+			}
+		}
+	}
+
+	// Tools are broadcast once, then only when they change
+	assert.Equal(t, []string{"test-server"}, updated())
+	assert.Empty(t, updated())
+	require.NoError(t, mgr.SetTools("test-server", []server.Tool{{Name: "tool1", Description: "Renamed"}}))
+	assert.Equal(t, []string{"test-server"}, updated())
+
+	// and again once the server is back
+	require.NoError(t, mgr.SetStatus("test-server", server.StatusStopped))
+	assert.Empty(t, updated())
+	require.NoError(t, mgr.SetStatus("test-server", server.StatusRunning))
+	assert.Equal(t, []string{"test-server"}, updated())
+}
+
+func TestMonitorOptions_Defaults(t *testing.T) {
+	assert.Equal(t, DefaultMonitorOptions, MonitorOptions{}.withDefaults())
+	assert.Equal(t, 8, MonitorOptions{Workers: 8}.withDefaults().Workers)
+}
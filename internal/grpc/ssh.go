@@ -0,0 +1,138 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"sync/atomic"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+	"google.golang.org/grpc"
+)
+
+// SSHScheme prefixes the addresses of daemons reached through ssh, e.g.
+// ssh://user@host/localhost:8080
+const SSHScheme = "ssh://"
+
+// SSHCommand is the ssh binary daemons at ssh:// addresses are reached
+// through
+var SSHCommand = "ssh"
+
+// sshTarget is a daemon reached through ssh, from an address like
+// ssh://user@host:22/localhost:8080
+type sshTarget struct {
+	destination string // user@host given to ssh
+	port        string // ssh port, empty for ssh's default
+	daemon      string // Daemon address as seen from the remote host
+	knownHosts  string // File of the pinned host key, empty to trust ssh's
+}
+
+// parseSSHAddress parses an ssh:// address, returning false for other
+// addresses. The daemon defaults to the default instance's port on the
+// remote host.
+func parseSSHAddress(address string) (sshTarget, bool, error) {
+	if !strings.HasPrefix(address, SSHScheme) {
+		return sshTarget{}, false, nil
+	}
+	u, err := url.Parse(address)
+	if err != nil {
+		return sshTarget{}, true, fmt.Errorf("invalid daemon address %q: %w", address, err)
+	}
+	if u.Hostname() == "" {
+		return sshTarget{}, true, fmt.Errorf("invalid daemon address %q: missing host", address)
+	}
+
+	target := sshTarget{
+		destination: u.Hostname(),
+		port:        u.Port(),
+		daemon:      strings.TrimPrefix(u.Path, "/"),
+	}
+	// ssh would read a destination starting with "-" as an option, such
+	// as -oProxyCommand running a command
+	if strings.HasPrefix(u.Hostname(), "-") {
+		return sshTarget{}, true, fmt.Errorf("invalid daemon address %q: host can't start with '-'", address)
+	}
+	if u.User != nil {
+		if strings.HasPrefix(u.User.Username(), "-") {
+			return sshTarget{}, true, fmt.Errorf("invalid daemon address %q: user can't start with '-'", address)
+		}
+		target.destination = u.User.Username() + "@" + target.destination
+	}
+	if target.daemon == "" {
+		target.daemon = fmt.Sprintf("localhost:%d", config.DefaultDaemonPort)
+	} else if _, _, err := net.SplitHostPort(target.daemon); err != nil {
+		return sshTarget{}, true, fmt.Errorf("invalid daemon address %q: %w", address, err)
+	}
+	return target, true, nil
+}
+
+// args returns the arguments of ssh forwarding its stdio to the daemon.
+// Batch mode fails rather than prompting over the TUI, so the host needs
+// a key or agent.
+func (t sshTarget) args() []string {
+	args := []string{"-o", "BatchMode=yes", "-W", t.daemon}
+	if t.knownHosts != "" {
+		args = append(args,
+			"-o", "StrictHostKeyChecking=yes",
+			"-o", "HostKeyAlias="+pinnedHostAlias,
+			"-o", "UserKnownHostsFile="+t.knownHosts,
+			"-o", "GlobalKnownHostsFile="+os.DevNull)
+	}
+	if t.port != "" {
+		args = append(args, "-p", t.port)
+	}
+	return append(args, "--", t.destination)
+}
+
+// dialOption returns the option dialing each connection to the daemon
+// through a new ssh process, so reconnecting also reconnects the tunnel
+func (t sshTarget) dialOption() grpc.DialOption {
+	return grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return t.dial()
+	})
+}
+
+// dial starts ssh, returning a connection over its stdio
+func (t sshTarget) dial() (net.Conn, error) {
+	local, remote := net.Pipe()
+	var stderr bytes.Buffer
+	cmd := exec.Command(SSHCommand, t.args()...)
+	cmd.Stdin = remote
+	cmd.Stdout = remote
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		local.Close()
+		remote.Close()
+		return nil, fmt.Errorf("failed to start ssh: %w", err)
+	}
+
+	conn := &sshConn{Conn: local, cmd: cmd}
+	go func() {
+		if err := cmd.Wait(); err != nil && !conn.closed.Load() {
+			log.Printf("ssh to %s exited: %v: %s", t.destination, err, strings.TrimSpace(stderr.String()))
+		}
+		remote.Close()
+	}()
+	return conn, nil
+}
+
+// sshConn is a connection over the stdio of an ssh process
+type sshConn struct {
+	net.Conn
+	cmd    *exec.Cmd
+	closed atomic.Bool
+}
+
+// Close closes the connection, ending ssh
+func (c *sshConn) Close() error {
+	c.closed.Store(true)
+	err := c.Conn.Close()
+	c.cmd.Process.Kill()
+	return err
+}
@@ -0,0 +1,106 @@
+package grpc
+
+import (
+	"io"
+	"net"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/apitest"
+	"github.com/tartavull/mcp-manager/internal/auth"
+	pb "github.com/tartavull/mcp-manager/internal/grpc/pb"
+	"google.golang.org/grpc"
+)
+
+// sshHelperEnv makes the test binary act as ssh -W, recording its
+// arguments to the file it names
+const sshHelperEnv = "MCP_MANAGER_TEST_SSH"
+
+func TestMain(m *testing.M) {
+	if path := os.Getenv(sshHelperEnv); path != "" {
+		os.Exit(fakeSSH(path, os.Args[1:]))
+	}
+	os.Exit(m.Run())
+}
+
+// fakeSSH forwards stdio to the address of -W like ssh would, without
+// logging into anything
+func fakeSSH(path string, args []string) int {
+	os.WriteFile(path, []byte(strings.Join(args, " ")), 0644)
+
+	var address string
+	for i, arg := range args {
+		if arg == "-W" && i+1 < len(args) {
+			address = args[i+1]
+		}
+	}
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		return 255
+	}
+	go io.Copy(conn, os.Stdin)
+	io.Copy(os.Stdout, conn)
+	return 0
+}
+
+func TestParseSSHAddress(t *testing.T) {
+	target, ok, err := parseSSHAddress("ssh://me@box:2222/localhost:9090")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"-o", "BatchMode=yes", "-W", "localhost:9090", "-p", "2222", "--", "me@box"}, target.args())
+
+	target, ok, err = parseSSHAddress("ssh://box")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, sshTarget{destination: "box", daemon: "localhost:8080"}, target)
+
+	_, ok, err = parseSSHAddress("localhost:8080")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, _, err = parseSSHAddress("ssh:///localhost:8080")
+	assert.Error(t, err)
+	_, _, err = parseSSHAddress("ssh://box/localhost")
+	assert.Error(t, err)
+
+	// Destinations can't pass options to ssh
+	_, _, err = parseSSHAddress("ssh://-oProxyCommand=id/")
+	assert.ErrorContains(t, err, "host can't start with '-'")
+	_, _, err = parseSSHAddress("ssh://-oProxyCommand=id@box/")
+	assert.ErrorContains(t, err, "user can't start with '-'")
+}
+
+func TestNewClient_SSH(t *testing.T) {
+	t.Setenv(auth.TokenEnv, "")
+	t.Setenv(auth.CAEnv, "")
+	t.Setenv(auth.CertEnv, "")
+
+	mgr := apitest.NewManager()
+	require.NoError(t, mgr.AddServer("remote", "echo remote", 4001, "Remote server"))
+	grpcServer := grpc.NewServer()
+	pb.RegisterMCPManagerServer(grpcServer, NewServer(mgr))
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go grpcServer.Serve(lis)
+	defer grpcServer.Stop()
+
+	argsFile := t.TempDir() + "/args"
+	t.Setenv(sshHelperEnv, argsFile)
+	defer func(command string) { SSHCommand = command }(SSHCommand)
+	SSHCommand = os.Args[0]
+
+	client, err := NewClient("ssh://me@box:2222/" + lis.Addr().String())
+	require.NoError(t, err)
+	defer client.Close()
+
+	servers, _, err := client.GetServers()
+	require.NoError(t, err)
+	assert.Contains(t, servers, "remote")
+
+	args, err := os.ReadFile(argsFile)
+	require.NoError(t, err)
+	assert.Equal(t, "-o BatchMode=yes -W "+lis.Addr().String()+" -p 2222 -- me@box", string(args))
+}
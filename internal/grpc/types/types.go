@@ -0,0 +1,249 @@
+// Package types holds the values managers report through the gRPC API,
+// apart from the interfaces enabling each RPC, so fakes of the manager can
+// report them without importing the gRPC server
+package types
+
+import (
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// Failover reports a server moved to another host by a coordinator
+type Failover struct {
+	Server string
+	From   string
+	To     string
+	Reason string
+}
+
+// CircuitChange reports the circuit breaker of a server's proxy opening or
+// closing
+type CircuitChange struct {
+	Server string
+	State  string
+	Reason string
+}
+
+// ConfigChange reports the servers a reload of the configuration added,
+// removed, restarted or updated in place
+type ConfigChange struct {
+	Added    []string
+	Removed  []string
+	Modified []string // Changes needing a restart, done at once if running
+	Updated  []string // Metadata changes applied without a restart
+}
+
+// StatusChange reports a server's status changing without being asked
+// to, e.g. crashing or being restarted by its restart policy
+type StatusChange struct {
+	Server string
+	Old    server.Status
+	New    server.Status
+}
+
+// Port migration states reported by PortMigration
+const (
+	PortSwitched = "switched" // The new port serves, the old one is being released
+	PortReleased = "released"
+	PortFailed   = "failed"
+)
+
+// PortMigration reports a step of moving a running server to the port its
+// config changed to
+type PortMigration struct {
+	Server  string
+	OldPort int
+	NewPort int
+	State   string // One of the port migration states
+	Reason  string // Why the migration failed
+}
+
+// Approval is a tool call parked until an operator approves or rejects it
+type Approval struct {
+	ID        string
+	Server    string
+	Tool      string
+	Arguments string // Redacted JSON arguments of the call
+	Requested time.Time
+	Deadline  time.Time // When the call is rejected if still pending
+	RequestID string    // IDs the agent gave the call and its run, if any
+	RunID     string
+}
+
+// Approval states reported by ApprovalChange
+const (
+	ApprovalPending  = "pending"
+	ApprovalApproved = "approved"
+	ApprovalRejected = "rejected"
+	ApprovalExpired  = "expired"
+)
+
+// ApprovalChange reports a call parked for approval or its outcome
+type ApprovalChange struct {
+	Approval
+	State  string // One of the approval states
+	Reason string // Why the call was rejected
+}
+
+// Fleet is an isolated copy of servers for an evaluation run, with their
+// own ports and state
+type Fleet struct {
+	RunID   string
+	Dir     string // State directory of the run
+	Created time.Time
+	Servers []*server.Server // Copies, named server@run
+}
+
+// BrowserProfile is a browser profile kept for a server
+type BrowserProfile struct {
+	Server    string
+	Name      string
+	Dir       string
+	Size      int64 // Bytes of the files
+	Modified  time.Time
+	Attached  bool              // The server is configured to use it
+	Snapshots []ProfileSnapshot // Oldest first
+}
+
+// ProfileSnapshot is an archive of a browser profile
+type ProfileSnapshot struct {
+	Name    string
+	Path    string
+	Size    int64 // Bytes of the archive
+	Created time.Time
+}
+
+// Kinds of the directories servers keep files in
+const (
+	DiskCache = "cache" // Recreated when missing, pruned by CleanCaches
+	DiskData  = "data"  // Kept until deleted by hand
+)
+
+// DiskDir is a directory, or file, a server keeps files in
+type DiskDir struct {
+	Kind  string // DiskCache or DiskData
+	Label string // What's kept, e.g. npx or profiles
+	Path  string
+	Size  int64 // Bytes of the files
+}
+
+// ServerDisk is the disk used by a server
+type ServerDisk struct {
+	Server string
+	Dirs   []DiskDir
+}
+
+// Total returns the bytes in the server's directories of a kind, or all of
+// them when kind is empty
+func (d *ServerDisk) Total(kind string) int64 {
+	var total int64
+	for _, dir := range d.Dirs {
+		if kind == "" || dir.Kind == kind {
+			total += dir.Size
+		}
+	}
+	return total
+}
+
+// Backup is an archive of the data a server keeps
+type Backup struct {
+	Server  string
+	Name    string
+	Path    string
+	Size    int64 // Bytes of the archive
+	Created time.Time
+}
+
+// ToolConflict reports a tool name exposed by several servers in the
+// gateway and how it was resolved
+type ToolConflict struct {
+	Tool    string            // Name the servers expose
+	Servers []string          // Servers exposing it, the one keeping the name first
+	Renamed map[string]string // Server to the name its tool was exposed as instead
+	Hidden  []string          // Servers whose tool isn't exposed at all
+}
+
+// Validation reports the problems found in the configuration
+type Validation struct {
+	Problems  []string // Settings that can't be applied
+	Conflicts []ToolConflict
+}
+
+// ProviderStats are the rolling counts of a server providing a generic
+// gateway tool
+type ProviderStats struct {
+	Server    string
+	Tool      string // Server's tool serving the generic one
+	Running   bool
+	Calls     int
+	Errors    int
+	ErrorRate float64       // Moving average share of failed calls
+	Latency   time.Duration // Moving average of the time the server took to answer
+}
+
+// ProviderGroup is a generic gateway tool and the providers it selects from
+type ProviderGroup struct {
+	Tool      string
+	Selected  string // Provider taking the next calls, empty if none is running
+	Pinned    string // Provider pinned to the tool, if any
+	Providers []ProviderStats
+}
+
+// NotificationPreference is the level of desktop notifications of a
+// server's events
+type NotificationPreference struct {
+	Server string
+	Level  string // mute, crash, tools or all
+	Custom bool   // Chosen for the server rather than the default
+}
+
+// Sources of the variables a server gets on top of the daemon's environment
+const (
+	VarConfig  = "config"  // env in mcp.json
+	VarSecret  = "secret"  // env read from the secrets store
+	VarProxy   = "proxy"   // Outbound proxy
+	VarProfile = "profile" // Browser profile directory
+)
+
+// RuntimeVar is a variable a server gets. Values are never reported, they
+// may be secrets.
+type RuntimeVar struct {
+	Name   string
+	Source string // One of the variable sources
+}
+
+// RuntimeLimit is a limit the manager or proxy puts on a server, e.g. its
+// niceness or the size of its results
+type RuntimeLimit struct {
+	Name  string
+	Value string
+}
+
+// Runtime is what a server runs as, or would if started now
+type Runtime struct {
+	Server    string
+	Command   string // As spawned, after profiles, transports, resolvers and priorities
+	Running   bool   // Command is the one the running server was started with
+	Env       []RuntimeVar
+	Path      string // PATH the command is looked up in
+	Cwd       string
+	Transport string // Transport plugin, empty when spawned directly
+	Limits    []RuntimeLimit
+	Error     string // Why the server can't start as configured
+}
+
+// ConnectionState is the state of a client's event stream
+type ConnectionState string
+
+const (
+	StateConnected    ConnectionState = "connected"
+	StateReconnecting ConnectionState = "reconnecting"
+	StateDisconnected ConnectionState = "disconnected" // Gave up reconnecting
+)
+
+// Retry describes the attempts to resubscribe a lost event stream
+type Retry struct {
+	Attempt int       // Attempts made so far
+	Next    time.Time // When the next attempt is made, zero if none is waiting
+}
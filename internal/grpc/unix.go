@@ -0,0 +1,73 @@
+package grpc
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/shellenv"
+)
+
+// UnixScheme prefixes the addresses of daemons listening on a unix socket,
+// e.g. unix://~/.mcp-manager/daemon.sock
+const UnixScheme = "unix://"
+
+// UnixSocketPath returns the absolute path of the socket of a unix://
+// address, with ~ expanded, or false for other addresses
+func UnixSocketPath(address string) (string, bool, error) {
+	if !strings.HasPrefix(address, UnixScheme) {
+		return "", false, nil
+	}
+	path := shellenv.ExpandHome(strings.TrimPrefix(address, UnixScheme))
+	if path == "" {
+		return "", true, fmt.Errorf("invalid daemon address %q: missing socket path", address)
+	}
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", true, fmt.Errorf("invalid daemon address %q: %w", address, err)
+	}
+	return path, true, nil
+}
+
+// listenUnix listens on the socket at path, readable and writable by the
+// user only so file permissions guard the API. A socket left behind by a
+// daemon that died is replaced, one still answering is not.
+func listenUnix(path string) (net.Listener, error) {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create socket directory: %w", err)
+	}
+	// Whoever owns the directory may replace the socket with their own
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read socket directory: %w", err)
+	}
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok && int(stat.Uid) != os.Getuid() {
+		return nil, fmt.Errorf("failed to listen: socket directory %s is owned by another user", dir)
+	}
+	if info, err := os.Stat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to listen: a daemon is already listening on %s", path)
+		}
+		os.Remove(path)
+	}
+
+	// The socket is created without permissions for others, who could
+	// connect before it is restricted otherwise
+	mask := syscall.Umask(0077)
+	lis, err := net.Listen("unix", path)
+	syscall.Umask(mask)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen: %w", err)
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		lis.Close()
+		return nil, fmt.Errorf("failed to restrict socket: %w", err)
+	}
+	return lis, nil
+}
@@ -0,0 +1,97 @@
+package grpc
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/apitest"
+	"github.com/tartavull/mcp-manager/internal/auth"
+	pb "github.com/tartavull/mcp-manager/internal/grpc/pb"
+	"google.golang.org/grpc"
+)
+
+func TestUnixSocketPath(t *testing.T) {
+	t.Setenv("HOME", "/home/me")
+
+	path, ok, err := UnixSocketPath("unix:///run/mcp/daemon.sock")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "/run/mcp/daemon.sock", path)
+
+	path, ok, err = UnixSocketPath("unix://~/.mcp-manager/daemon.sock")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "/home/me/.mcp-manager/daemon.sock", path)
+
+	_, ok, err = UnixSocketPath("localhost:8080")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, _, err = UnixSocketPath("unix://")
+	assert.EqualError(t, err, `invalid daemon address "unix://": missing socket path`)
+}
+
+func TestNewClient_Unix(t *testing.T) {
+	t.Setenv(auth.TokenEnv, "")
+	t.Setenv(auth.CAEnv, "")
+	t.Setenv(auth.CertEnv, "")
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	mgr := apitest.NewManager()
+	require.NoError(t, mgr.AddServer("local", "echo local", 4001, "Local server"))
+	grpcServer := grpc.NewServer()
+	pb.RegisterMCPManagerServer(grpcServer, NewServer(mgr))
+	socket := filepath.Join(home, ".mcp-manager", "daemon.sock")
+	lis, err := listenUnix(socket)
+	require.NoError(t, err)
+	go grpcServer.Serve(lis)
+	defer grpcServer.Stop()
+
+	info, err := os.Stat(socket)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "only the user may open the socket")
+
+	client, err := NewClient("unix://~/.mcp-manager/daemon.sock")
+	require.NoError(t, err)
+	defer client.Close()
+	servers, _, err := client.GetServers()
+	require.NoError(t, err)
+	assert.Contains(t, servers, "local")
+
+	_, err = listenUnix(socket)
+	assert.ErrorContains(t, err, "a daemon is already listening on "+socket)
+}
+
+func TestListenUnix_Stale(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "daemon.sock")
+
+	// A socket nobody listens on anymore, like one left by a crash
+	stale, err := net.ListenUnix("unix", &net.UnixAddr{Name: socket, Net: "unix"})
+	require.NoError(t, err)
+	stale.SetUnlinkOnClose(false)
+	stale.Close()
+	require.FileExists(t, socket)
+
+	lis, err := listenUnix(socket)
+	require.NoError(t, err)
+	lis.Close()
+	assert.NoFileExists(t, socket, "the socket is removed when the daemon stops")
+}
+
+func TestListenUnix_ForeignDirectory(t *testing.T) {
+	if os.Getuid() != 0 {
+		t.Skip("only root can give a directory to another user")
+	}
+	dir := t.TempDir()
+	require.NoError(t, os.Chown(dir, 65534, 65534))
+
+	// Its owner could swap the socket for one of their own
+	_, err := listenUnix(filepath.Join(dir, "daemon.sock"))
+	assert.EqualError(t, err, "failed to listen: socket directory "+dir+" is owned by another user")
+	assert.NoFileExists(t, filepath.Join(dir, "daemon.sock"))
+}
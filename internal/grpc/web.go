@@ -0,0 +1,176 @@
+package grpc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/tartavull/mcp-manager/internal/auth"
+	"github.com/tartavull/mcp-manager/internal/cors"
+	"google.golang.org/grpc"
+)
+
+// webCORS returns the CORS policy of the gRPC-Web endpoint
+func webCORS(origins []string) cors.Policy {
+	return cors.Policy{
+		AllowedOrigins: origins,
+		Local:          true,
+		AllowedMethods: []string{"POST", "OPTIONS"},
+		AllowedHeaders: []string{"Content-Type", "X-Grpc-Web", "X-User-Agent", "Grpc-Timeout", "Authorization"},
+		ExposedHeaders: []string{"Grpc-Status", "Grpc-Message", "Grpc-Status-Details-Bin"},
+	}
+}
+
+// WebHandler serves grpcServer to browsers over gRPC-Web, and to regular
+// gRPC clients over HTTP/2 when wrapped for h2c. origins lists the browser
+// origins allowed to call it; empty allows only its own and loopback origins.
+func WebHandler(grpcServer *grpc.Server, origins []string) http.Handler {
+	return webCORS(origins).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType := r.Header.Get("Content-Type")
+		switch {
+		case strings.HasPrefix(contentType, "application/grpc-web"):
+			serveWeb(grpcServer, w, r)
+		case r.ProtoMajor == 2 && strings.HasPrefix(contentType, "application/grpc"):
+			grpcServer.ServeHTTP(w, r)
+		default:
+			http.Error(w, "expected a gRPC or gRPC-Web request", http.StatusUnsupportedMediaType)
+		}
+	}))
+}
+
+// DocsPath is where the web endpoint serves the generated tool
+// documentation
+const DocsPath = "/docs/"
+
+// DocsHandler serves the files of dir on GET requests under DocsPath,
+// authenticated by provider unless it is nil, and passes other requests
+// to next
+func DocsHandler(dir string, provider auth.Provider, next http.Handler) http.Handler {
+	docs := auth.Require(provider, http.StripPrefix(DocsPath, http.FileServer(http.Dir(dir))))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if (r.Method == http.MethodGet || r.Method == http.MethodHead) && strings.HasPrefix(r.URL.Path, DocsPath) {
+			docs.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// serveWeb translates a gRPC-Web request into a gRPC request and the
+// response back, moving the trailers into the body where browsers can
+// read them
+func serveWeb(grpcServer *grpc.Server, w http.ResponseWriter, r *http.Request) {
+	text := strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc-web-text")
+
+	req := r.Clone(r.Context())
+	req.Proto, req.ProtoMajor, req.ProtoMinor = "HTTP/2", 2, 0
+	req.Header.Set("Content-Type", "application/grpc+proto")
+	req.Header.Del("Content-Length")
+	req.ContentLength = -1
+	if text {
+		req.Body = io.NopCloser(base64.NewDecoder(base64.StdEncoding, r.Body))
+	}
+
+	ww := &webResponseWriter{w: w, header: make(http.Header), text: text}
+	grpcServer.ServeHTTP(ww, req)
+	ww.finish()
+}
+
+// webResponseWriter turns a gRPC response into a gRPC-Web response
+type webResponseWriter struct {
+	w           http.ResponseWriter
+	header      http.Header // Headers and trailers as set by gRPC
+	text        bool        // Base64 encode the body
+	wroteHeader bool
+}
+
+func (ww *webResponseWriter) Header() http.Header {
+	return ww.header
+}
+
+func (ww *webResponseWriter) WriteHeader(code int) {
+	if ww.wroteHeader {
+		return
+	}
+	ww.wroteHeader = true
+
+	trailers := ww.declaredTrailers()
+	for key, values := range ww.header {
+		if key == "Trailer" || trailers[key] || strings.HasPrefix(key, http.TrailerPrefix) {
+			continue
+		}
+		ww.w.Header()[key] = values
+	}
+
+	contentType := "application/grpc-web+proto"
+	if ww.text {
+		contentType = "application/grpc-web-text+proto"
+	}
+	ww.w.Header().Set("Content-Type", contentType)
+	ww.w.WriteHeader(code)
+}
+
+func (ww *webResponseWriter) Write(b []byte) (int, error) {
+	if !ww.wroteHeader {
+		ww.WriteHeader(http.StatusOK)
+	}
+	if ww.text {
+		if _, err := io.WriteString(ww.w, base64.StdEncoding.EncodeToString(b)); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+	}
+	return ww.w.Write(b)
+}
+
+func (ww *webResponseWriter) Flush() {
+	if !ww.wroteHeader {
+		ww.WriteHeader(http.StatusOK)
+	}
+	if flusher, ok := ww.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// declaredTrailers returns the canonical names of the trailers gRPC
+// announced in its headers
+func (ww *webResponseWriter) declaredTrailers() map[string]bool {
+	trailers := make(map[string]bool)
+	for _, value := range ww.header["Trailer"] {
+		for _, key := range strings.Split(value, ",") {
+			trailers[http.CanonicalHeaderKey(strings.TrimSpace(key))] = true
+		}
+	}
+	return trailers
+}
+
+// finish writes the trailers as the final gRPC-Web frame
+func (ww *webResponseWriter) finish() {
+	trailers := ww.declaredTrailers()
+
+	var lines []string
+	for key, values := range ww.header {
+		name, undeclared := strings.CutPrefix(key, http.TrailerPrefix)
+		if !undeclared && !trailers[key] {
+			continue
+		}
+		for _, value := range values {
+			lines = append(lines, fmt.Sprintf("%s: %s\r\n", strings.ToLower(name), value))
+		}
+	}
+	sort.Strings(lines)
+	payload := strings.Join(lines, "")
+
+	var frame bytes.Buffer
+	frame.WriteByte(0x80) // Trailer frame flag
+	binary.Write(&frame, binary.BigEndian, uint32(len(payload)))
+	frame.WriteString(payload)
+
+	ww.Write(frame.Bytes())
+	ww.Flush()
+}
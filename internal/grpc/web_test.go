@@ -0,0 +1,192 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/apitest"
+	"github.com/tartavull/mcp-manager/internal/auth"
+	"github.com/tartavull/mcp-manager/internal/config"
+	pb "github.com/tartavull/mcp-manager/internal/grpc/pb"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
+)
+
+// setupWebServer serves a fake manager on a gRPC-Web and h2c endpoint
+func setupWebServer(t *testing.T, origins []string) *httptest.Server {
+	mgr := apitest.NewManager()
+	require.NoError(t, mgr.AddServer("test-server", "echo test", 4001, "Test server"))
+
+	grpcServer := grpc.NewServer()
+	pb.RegisterMCPManagerServer(grpcServer, NewServer(mgr))
+
+	ts := httptest.NewServer(h2c.NewHandler(WebHandler(grpcServer, origins), &http2.Server{}))
+	t.Cleanup(func() {
+		ts.Close()
+		grpcServer.Stop()
+	})
+	return ts
+}
+
+// webCall makes a unary gRPC-Web call, returning the response message and
+// trailers
+func webCall(t *testing.T, url, method string, req proto.Message, text bool) ([]byte, string) {
+	payload, err := proto.Marshal(req)
+	require.NoError(t, err)
+
+	var body bytes.Buffer
+	body.WriteByte(0)
+	binary.Write(&body, binary.BigEndian, uint32(len(payload)))
+	body.Write(payload)
+
+	contentType := "application/grpc-web+proto"
+	reqBody := body.Bytes()
+	if text {
+		contentType = "application/grpc-web-text+proto"
+		reqBody = []byte(base64.StdEncoding.EncodeToString(reqBody))
+	}
+
+	resp, err := http.Post(url+method, contentType, bytes.NewReader(reqBody))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, contentType, resp.Header.Get("Content-Type"))
+
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	if text {
+		// Each write is encoded separately, so decode quantum by quantum
+		var decoded []byte
+		for i := 0; i+4 <= len(data); i += 4 {
+			part, err := base64.StdEncoding.DecodeString(string(data[i : i+4]))
+			require.NoError(t, err)
+			decoded = append(decoded, part...)
+		}
+		data = decoded
+	}
+
+	// Data frames, then the trailer frame
+	var message []byte
+	var trailers string
+	for len(data) >= 5 {
+		flag, length := data[0], binary.BigEndian.Uint32(data[1:5])
+		frame := data[5 : 5+length]
+		if flag&0x80 != 0 {
+			trailers = string(frame)
+		} else {
+			message = frame
+		}
+		data = data[5+length:]
+	}
+	return message, trailers
+}
+
+func TestWebHandler_GRPCWeb(t *testing.T) {
+	ts := setupWebServer(t, nil)
+
+	message, trailers := webCall(t, ts.URL, pb.MCPManager_GetServer_FullMethodName, &pb.ServerRequest{Name: "test-server"}, false)
+	var srv pb.Server
+	require.NoError(t, proto.Unmarshal(message, &srv))
+	assert.Equal(t, "test-server", srv.Name)
+	assert.Contains(t, trailers, "grpc-status: 0\r\n")
+
+	// Errors are reported in the trailers
+	_, trailers = webCall(t, ts.URL, pb.MCPManager_GetServer_FullMethodName, &pb.ServerRequest{Name: "missing"}, false)
+	assert.Contains(t, trailers, "grpc-status: 5\r\n")
+
+	message, trailers = webCall(t, ts.URL, pb.MCPManager_GetServer_FullMethodName, &pb.ServerRequest{Name: "test-server"}, true)
+	require.NoError(t, proto.Unmarshal(message, &srv))
+	assert.Equal(t, "test-server", srv.Name)
+	assert.Contains(t, trailers, "grpc-status: 0\r\n")
+}
+
+func TestWebHandler_H2C(t *testing.T) {
+	ts := setupWebServer(t, nil)
+
+	conn, err := grpc.NewClient(strings.TrimPrefix(ts.URL, "http://"), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	resp, err := pb.NewMCPManagerClient(conn).ListServers(context.Background(), &pb.Empty{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"test-server"}, resp.Order)
+}
+
+func TestWebHandler_CORS(t *testing.T) {
+	ts := setupWebServer(t, []string{"http://localhost:3000"})
+
+	preflight := func(origin string) *http.Response {
+		req, err := http.NewRequest(http.MethodOptions, ts.URL+pb.MCPManager_Health_FullMethodName, nil)
+		require.NoError(t, err)
+		req.Header.Set("Origin", origin)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp
+	}
+
+	resp := preflight("http://localhost:3000")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "http://localhost:3000", resp.Header.Get("Access-Control-Allow-Origin"))
+	assert.Contains(t, resp.Header.Get("Access-Control-Allow-Headers"), "X-Grpc-Web")
+	assert.Contains(t, resp.Header.Get("Access-Control-Expose-Headers"), "Grpc-Status")
+
+	assert.Equal(t, http.StatusForbidden, preflight("http://evil.example").StatusCode)
+
+	// Plain HTTP requests aren't gRPC
+	get, err := http.Get(ts.URL + "/")
+	require.NoError(t, err)
+	get.Body.Close()
+	assert.Equal(t, http.StatusUnsupportedMediaType, get.StatusCode)
+}
+
+func TestDocsHandler(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.html"), []byte("<h1>MCP Tools</h1>"), 0644))
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+	})
+	provider, err := auth.Build(&config.AuthConfig{
+		Providers: map[string]*config.AuthProviderConfig{
+			"ci": {Type: "token", Tokens: map[string]string{"ci": "secret"}},
+		},
+	}, []string{"ci"})
+	require.NoError(t, err)
+
+	get := func(handler http.Handler, path, token string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	handler := DocsHandler(dir, nil, next)
+	w := get(handler, "/docs/", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "MCP Tools")
+	assert.Equal(t, http.StatusNotFound, get(handler, "/docs/missing.html", "").Code)
+
+	// Other requests reach the API
+	assert.Equal(t, http.StatusUnsupportedMediaType, get(handler, "/mcp.MCPManager/Health", "").Code)
+
+	handler = DocsHandler(dir, provider, next)
+	assert.Equal(t, http.StatusUnauthorized, get(handler, "/docs/", "").Code)
+	assert.Equal(t, http.StatusOK, get(handler, "/docs/", "secret").Code)
+}
@@ -0,0 +1,176 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+const (
+	// DefaultInterval is the time between checks when not configured
+	DefaultInterval = 30 * time.Second
+
+	// DefaultTimeout bounds a single check when not configured
+	DefaultTimeout = 10 * time.Second
+
+	// maxMessageLength caps the failure output kept for display
+	maxMessageLength = 200
+)
+
+// ParseConfig converts a health check from mcp.json, applying defaults
+func ParseConfig(cfg *config.HealthCheckConfig) (*server.HealthCheck, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	if (cfg.Command == "") == (cfg.URL == "") {
+		return nil, fmt.Errorf("health check requires exactly one of command or url")
+	}
+
+	check := &server.HealthCheck{
+		Command:  cfg.Command,
+		URL:      cfg.URL,
+		Interval: DefaultInterval,
+		Timeout:  DefaultTimeout,
+	}
+
+	if cfg.Interval != "" {
+		interval, err := time.ParseDuration(cfg.Interval)
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("invalid health check interval '%s'", cfg.Interval)
+		}
+		check.Interval = interval
+	}
+
+	if cfg.Timeout != "" {
+		timeout, err := time.ParseDuration(cfg.Timeout)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("invalid health check timeout '%s'", cfg.Timeout)
+		}
+		check.Timeout = timeout
+	}
+
+	return check, nil
+}
+
+// Result is the outcome of a single health check
+type Result struct {
+	Health  server.Health
+	Message string
+}
+
+// Run executes a health check once. Commands run with env, the same
+// environment as the server, so they can reuse its credentials.
+func Run(ctx context.Context, check *server.HealthCheck, env []string) Result {
+	ctx, cancel := context.WithTimeout(ctx, check.Timeout)
+	defer cancel()
+
+	if check.URL != "" {
+		return runURL(ctx, check.URL)
+	}
+	return runCommand(ctx, check.Command, env)
+}
+
+// runCommand runs a shell command, healthy when it exits 0
+func runCommand(ctx context.Context, command string, env []string) Result {
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Env = env
+
+	// Kill the whole process group on timeout, so children of the shell
+	// don't keep the output pipe open
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = time.Second
+
+	out, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return Result{Health: server.HealthUnhealthy, Message: "health check timed out"}
+	}
+	if err != nil {
+		message := err.Error()
+		if output := strings.TrimSpace(string(out)); output != "" {
+			message = output
+		}
+		return Result{Health: server.HealthUnhealthy, Message: truncate(message)}
+	}
+
+	return Result{Health: server.HealthHealthy}
+}
+
+// runURL requests a URL, healthy on a 2xx response
+func runURL(ctx context.Context, url string) Result {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Result{Health: server.HealthUnhealthy, Message: truncate(err.Error())}
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return Result{Health: server.HealthUnhealthy, Message: truncate(err.Error())}
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return Result{Health: server.HealthUnhealthy, Message: fmt.Sprintf("HTTP %s", resp.Status)}
+	}
+
+	return Result{Health: server.HealthHealthy}
+}
+
+// truncate shortens a message to the first line and maxMessageLength
+func truncate(message string) string {
+	message, _, _ = strings.Cut(message, "\n")
+	if len(message) > maxMessageLength {
+		message = message[:maxMessageLength-3] + "..."
+	}
+	return message
+}
+
+// Monitor runs a health check periodically until stopped
+type Monitor struct {
+	cancel context.CancelFunc
+}
+
+// Start runs the check immediately and then every interval, reporting each
+// result to onResult
+func Start(check *server.HealthCheck, env []string, onResult func(Result)) *Monitor {
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &Monitor{cancel: cancel}
+
+	go func() {
+		ticker := time.NewTicker(check.Interval)
+		defer ticker.Stop()
+
+		for {
+			result := Run(ctx, check, env)
+			if ctx.Err() != nil {
+				return
+			}
+			onResult(result)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return m
+}
+
+// Stop stops the monitor, cancelling a check in progress
+func (m *Monitor) Stop() {
+	m.cancel()
+}
@@ -0,0 +1,102 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+func TestParseConfig(t *testing.T) {
+	check, err := ParseConfig(nil)
+	require.NoError(t, err)
+	assert.Nil(t, check)
+
+	check, err = ParseConfig(&config.HealthCheckConfig{Command: "pg_isready"})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultInterval, check.Interval)
+	assert.Equal(t, DefaultTimeout, check.Timeout)
+
+	check, err = ParseConfig(&config.HealthCheckConfig{URL: "http://localhost:9000/ready", Interval: "5s", Timeout: "1s"})
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Second, check.Interval)
+	assert.Equal(t, time.Second, check.Timeout)
+
+	_, err = ParseConfig(&config.HealthCheckConfig{})
+	assert.Error(t, err, "command or url is required")
+
+	_, err = ParseConfig(&config.HealthCheckConfig{Command: "true", URL: "http://localhost"})
+	assert.Error(t, err, "command and url are exclusive")
+
+	_, err = ParseConfig(&config.HealthCheckConfig{Command: "true", Interval: "soon"})
+	assert.Error(t, err)
+}
+
+func TestRun_Command(t *testing.T) {
+	check := &server.HealthCheck{Command: "test \"$DB\" = up", Timeout: time.Second}
+
+	result := Run(context.Background(), check, []string{"DB=up"})
+	assert.Equal(t, server.HealthHealthy, result.Health)
+
+	result = Run(context.Background(), check, []string{"DB=down"})
+	assert.Equal(t, server.HealthUnhealthy, result.Health)
+
+	check.Command = "echo connection refused >&2; exit 2"
+	result = Run(context.Background(), check, nil)
+	assert.Equal(t, server.HealthUnhealthy, result.Health)
+	assert.Equal(t, "connection refused", result.Message)
+}
+
+func TestRun_CommandTimeout(t *testing.T) {
+	check := &server.HealthCheck{Command: "sleep 5", Timeout: 100 * time.Millisecond}
+
+	result := Run(context.Background(), check, nil)
+	assert.Equal(t, server.HealthUnhealthy, result.Health)
+	assert.Equal(t, "health check timed out", result.Message)
+}
+
+func TestRun_URL(t *testing.T) {
+	ready := true
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !ready {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+
+	check := &server.HealthCheck{URL: ts.URL, Timeout: time.Second}
+	assert.Equal(t, server.HealthHealthy, Run(context.Background(), check, nil).Health)
+
+	ready = false
+	result := Run(context.Background(), check, nil)
+	assert.Equal(t, server.HealthUnhealthy, result.Health)
+	assert.Contains(t, result.Message, "503")
+}
+
+func TestMonitor(t *testing.T) {
+	check := &server.HealthCheck{Command: "true", Interval: 10 * time.Millisecond, Timeout: time.Second}
+
+	results := make(chan Result, 10)
+	monitor := Start(check, nil, func(result Result) {
+		select {
+		case results <- result:
+		default:
+		}
+	})
+	defer monitor.Stop()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case result := <-results:
+			assert.Equal(t, server.HealthHealthy, result.Health)
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for health check result")
+		}
+	}
+}
@@ -0,0 +1,137 @@
+// Package i18n translates the messages of the TUI and CLI. Messages are
+// keyed by their English text, so untranslated ones show in English.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// LocaleEnv selects the locale of the messages, over the locale of
+// mcp.json and the system's
+const LocaleEnv = "MCP_MANAGER_LANG"
+
+// English is the locale the messages are written in
+const English = "en"
+
+//go:embed locales/*.json
+var catalogs embed.FS
+
+var (
+	mu      sync.RWMutex
+	locale  = English
+	catalog map[string]string // English message to its translation
+
+	// untranslated collects the messages without a translation while
+	// Untranslated records them
+	untranslatedMu sync.Mutex
+	untranslated   map[string]bool
+)
+
+// Locales returns the locales messages are available in, English first
+func Locales() []string {
+	locales := []string{English}
+	entries, _ := catalogs.ReadDir("locales")
+	for _, entry := range entries {
+		locales = append(locales, strings.TrimSuffix(entry.Name(), ".json"))
+	}
+	return locales
+}
+
+// Detect returns the locale set by LocaleEnv, else configured, else the
+// system's LC_ALL, LC_MESSAGES or LANG
+func Detect(configured string) string {
+	for _, value := range []string{os.Getenv(LocaleEnv), configured, os.Getenv("LC_ALL"), os.Getenv("LC_MESSAGES"), os.Getenv("LANG")} {
+		if value != "" {
+			return value
+		}
+	}
+	return English
+}
+
+// normalize returns the language of a locale, e.g. "es" for "es_ES.UTF-8"
+func normalize(tag string) string {
+	tag = strings.ToLower(tag)
+	if i := strings.IndexAny(tag, "_-.@"); i >= 0 {
+		tag = tag[:i]
+	}
+	return tag
+}
+
+// SetLocale translates the messages into the language of tag, e.g. "es"
+// or "es_ES.UTF-8". Languages without a catalog, including "C" and
+// "POSIX", leave the messages in English.
+func SetLocale(tag string) error {
+	language := normalize(tag)
+	var translations map[string]string
+	if language != English {
+		data, err := catalogs.ReadFile("locales/" + language + ".json")
+		if err != nil {
+			language = English
+		} else if err := json.Unmarshal(data, &translations); err != nil {
+			return fmt.Errorf("invalid catalog for '%s': %w", language, err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	locale, catalog = language, translations
+	return nil
+}
+
+// Locale returns the language messages are translated into
+func Locale() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return locale
+}
+
+// T returns the translation of an English message, formatted with args
+// like fmt.Sprintf if there are any
+func T(message string, args ...any) string {
+	mu.RLock()
+	translated, exists := catalog[message]
+	missing := locale != English && (!exists || translated == "")
+	mu.RUnlock()
+
+	if missing {
+		untranslatedMu.Lock()
+		if untranslated != nil {
+			untranslated[message] = true
+		}
+		untranslatedMu.Unlock()
+	} else if exists {
+		message = translated
+	}
+
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// Untranslated records the messages T has no translation for in the
+// current locale until the returned function is called, which returns them
+// sorted. Tests use it to find messages missing from a catalog.
+func Untranslated() func() []string {
+	untranslatedMu.Lock()
+	untranslated = make(map[string]bool)
+	untranslatedMu.Unlock()
+
+	return func() []string {
+		untranslatedMu.Lock()
+		defer untranslatedMu.Unlock()
+		messages := make([]string, 0, len(untranslated))
+		for message := range untranslated {
+			messages = append(messages, message)
+		}
+		untranslated = nil
+		sort.Strings(messages)
+		return messages
+	}
+}
@@ -0,0 +1,71 @@
+package i18n
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "es", normalize("es"))
+	assert.Equal(t, "es", normalize("es_ES.UTF-8"))
+	assert.Equal(t, "pt", normalize("pt-BR"))
+	assert.Equal(t, "c", normalize("C.UTF-8"))
+}
+
+func TestDetect(t *testing.T) {
+	t.Setenv(LocaleEnv, "")
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "")
+	assert.Equal(t, English, Detect(""))
+
+	t.Setenv("LANG", "de_DE.UTF-8")
+	assert.Equal(t, "de_DE.UTF-8", Detect(""))
+	t.Setenv("LC_ALL", "fr_FR.UTF-8")
+	assert.Equal(t, "fr_FR.UTF-8", Detect(""))
+	assert.Equal(t, "es", Detect("es"))
+	t.Setenv(LocaleEnv, "en")
+	assert.Equal(t, "en", Detect("es"))
+}
+
+func TestSetLocale(t *testing.T) {
+	t.Cleanup(func() { SetLocale(English) })
+
+	require.NoError(t, SetLocale("es_ES.UTF-8"))
+	assert.Equal(t, "es", Locale())
+	assert.Equal(t, "Nombre", T("Name"))
+	assert.Equal(t, "Herramientas disponibles (3)", T("Available Tools (%d)", 3))
+	assert.Equal(t, "Not in the catalog", T("Not in the catalog"))
+
+	untranslated := Untranslated()
+	T("Name")
+	T("Not in the catalog: %d", 1)
+	assert.Equal(t, []string{"Not in the catalog: %d"}, untranslated())
+
+	// Languages without a catalog fall back to English
+	require.NoError(t, SetLocale("C"))
+	assert.Equal(t, English, Locale())
+	assert.Equal(t, "Name", T("Name"))
+	assert.Equal(t, "Available Tools (3)", T("Available Tools (%d)", 3))
+}
+
+func TestCatalogs(t *testing.T) {
+	verbs := regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+	assert.Contains(t, Locales(), "es")
+
+	for _, locale := range Locales()[1:] {
+		data, err := catalogs.ReadFile("locales/" + locale + ".json")
+		require.NoError(t, err)
+		var catalog map[string]string
+		require.NoError(t, json.Unmarshal(data, &catalog), locale)
+
+		// Translations take the same arguments as their messages
+		for message, translated := range catalog {
+			assert.Equal(t, verbs.FindAllString(message, -1), verbs.FindAllString(translated, -1), "%s: %q", locale, message)
+		}
+	}
+}
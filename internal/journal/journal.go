@@ -0,0 +1,326 @@
+// Package journal persists daemon events to an append-only log on disk,
+// bounded by size and age, so past events can be queried after the fact
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/tartavull/mcp-manager/internal/grpc/pb"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	// DefaultMaxSize bounds the disk space of the journal
+	DefaultMaxSize = 64 << 20
+
+	// DefaultMaxAge is how long events are kept
+	DefaultMaxAge = 7 * 24 * time.Hour
+
+	// segmentCount is how many files the journal is split into, so old
+	// events are removed a file at a time
+	segmentCount = 8
+
+	// minSegmentSize keeps small journals from rotating on every event
+	minSegmentSize = 64 << 10
+
+	segmentPrefix = "events-"
+	segmentSuffix = ".jsonl"
+)
+
+// Options bounds the journal
+type Options struct {
+	MaxSize int64         // Total size of the journal in bytes
+	MaxAge  time.Duration // Age after which events are removed
+}
+
+// Query selects events from the journal
+type Query struct {
+	From, To time.Time      // Time range, zero for unbounded
+	Types    []pb.EventType // Event types, empty for all
+	Server   string         // Server the events are about, empty for all
+	Limit    int            // Maximum number of events, oldest first; zero for all
+}
+
+// Journal appends events to segment files in a directory, named by the
+// time they were created
+type Journal struct {
+	dir         string
+	maxSize     int64
+	maxAge      time.Duration
+	segmentSize int64
+
+	mu   sync.Mutex
+	file *os.File // Segment being written
+	size int64    // Size of file
+
+	now func() time.Time // Replaceable for tests
+}
+
+// record is a journal line
+type record struct {
+	Time   time.Time       `json:"time"`
+	Type   string          `json:"type"`
+	Server string          `json:"server,omitempty"`
+	Event  json.RawMessage `json:"event"`
+}
+
+// Open opens the journal in dir, creating it if needed
+func Open(dir string, opts Options) (*Journal, error) {
+	if opts.MaxSize <= 0 {
+		opts.MaxSize = DefaultMaxSize
+	}
+	if opts.MaxAge <= 0 {
+		opts.MaxAge = DefaultMaxAge
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create journal directory: %w", err)
+	}
+
+	j := &Journal{
+		dir:         dir,
+		maxSize:     opts.MaxSize,
+		maxAge:      opts.MaxAge,
+		segmentSize: max(opts.MaxSize/segmentCount, minSegmentSize),
+		now:         time.Now,
+	}
+	if err := j.prune(); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// Append records an event
+func (j *Journal) Append(event *pb.Event) error {
+	data, err := protojson.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	now := j.now()
+	line, err := json.Marshal(record{
+		Time:   now,
+		Type:   event.Type.String(),
+		Server: event.ServerName(),
+		Event:  data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	line = append(line, '\n')
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.file != nil && j.size+int64(len(line)) > j.segmentSize {
+		j.file.Close()
+		j.file = nil
+		if err := j.prune(); err != nil {
+			log.Printf("Warning: failed to prune event journal: %v", err)
+		}
+	}
+
+	if j.file == nil {
+		name := fmt.Sprintf("%s%020d%s", segmentPrefix, now.UnixNano(), segmentSuffix)
+		file, err := os.OpenFile(filepath.Join(j.dir, name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to create journal segment: %w", err)
+		}
+		j.file = file
+		j.size = 0
+	}
+
+	n, err := j.file.Write(line)
+	j.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write event: %w", err)
+	}
+	return nil
+}
+
+// Export records an event, logging failures. It lets the journal receive
+// the events the daemon broadcasts.
+func (j *Journal) Export(event *pb.Event) {
+	if event.Type == pb.EventType_HEARTBEAT {
+		return
+	}
+	if err := j.Append(event); err != nil {
+		log.Printf("Warning: failed to journal event: %v", err)
+	}
+}
+
+// Query returns the events matching q, oldest first, and whether more
+// events matched than q.Limit
+func (j *Journal) Query(q Query) ([]*pb.Event, bool, error) {
+	// Segments are only appended to, so they are read without the lock
+	j.mu.Lock()
+	paths, err := j.segments()
+	j.mu.Unlock()
+	if err != nil {
+		return nil, false, err
+	}
+
+	var events []*pb.Event
+	for i, path := range paths {
+		// Segments hold the events written between their creation and the
+		// next segment's
+		if !q.To.IsZero() && segmentTime(path).After(q.To) {
+			break
+		}
+		if !q.From.IsZero() && i+1 < len(paths) && segmentTime(paths[i+1]).Before(q.From) {
+			continue
+		}
+
+		more, err := j.scan(path, q, &events)
+		if err != nil {
+			return nil, false, err
+		}
+		if more {
+			return events, true, nil
+		}
+	}
+	return events, false, nil
+}
+
+// scan appends the events of a segment matching q, returning true once
+// q.Limit is exceeded
+func (j *Journal) scan(path string, q Query, events *[]*pb.Event) (bool, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to open journal segment: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64<<10), 16<<20)
+	for scanner.Scan() {
+		var r record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			continue // Torn write
+		}
+		if !q.matches(r) {
+			continue
+		}
+		if q.Limit > 0 && len(*events) == q.Limit {
+			return true, nil
+		}
+
+		event := &pb.Event{}
+		if err := protojson.Unmarshal(r.Event, event); err != nil {
+			continue
+		}
+		event.Timestamp = r.Time.Unix()
+		*events = append(*events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("failed to read journal segment: %w", err)
+	}
+	return false, nil
+}
+
+// matches returns true if a record is selected by the query
+func (q Query) matches(r record) bool {
+	if !q.From.IsZero() && r.Time.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && r.Time.After(q.To) {
+		return false
+	}
+	if q.Server != "" && r.Server != q.Server {
+		return false
+	}
+	if len(q.Types) > 0 && !slices.ContainsFunc(q.Types, func(t pb.EventType) bool {
+		return t == pb.EventType_ALL || t.String() == r.Type
+	}) {
+		return false
+	}
+	return true
+}
+
+// Close closes the segment being written
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.file == nil {
+		return nil
+	}
+	err := j.file.Close()
+	j.file = nil
+	return err
+}
+
+// segments returns the paths of the segment files, oldest first
+func (j *Journal) segments() ([]string, error) {
+	entries, err := os.ReadDir(j.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list journal: %w", err)
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, segmentPrefix) && strings.HasSuffix(name, segmentSuffix) {
+			paths = append(paths, filepath.Join(j.dir, name))
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// segmentTime returns when a segment was created, from its name
+func segmentTime(path string) time.Time {
+	var nanos int64
+	name := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), segmentPrefix), segmentSuffix)
+	fmt.Sscanf(name, "%d", &nanos)
+	return time.Unix(0, nanos)
+}
+
+// prune removes the segments last written before the maximum age, then
+// the oldest segments until the journal fits its maximum size. Must be
+// called with j.mu held or before the journal is used.
+func (j *Journal) prune() error {
+	paths, err := j.segments()
+	if err != nil {
+		return err
+	}
+
+	type segment struct {
+		path string
+		size int64
+	}
+	var kept []segment
+	var total int64
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if j.now().Sub(info.ModTime()) > j.maxAge {
+			os.Remove(path)
+			continue
+		}
+		kept = append(kept, segment{path: path, size: info.Size()})
+		total += info.Size()
+	}
+
+	// The segment being written is never removed
+	for len(kept) > 1 && total > j.maxSize {
+		os.Remove(kept[0].path)
+		total -= kept[0].size
+		kept = kept[1:]
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package journal
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	pb "github.com/tartavull/mcp-manager/internal/grpc/pb"
+)
+
+func statusEvent(name string, status pb.ServerStatus) *pb.Event {
+	return &pb.Event{
+		Type: pb.EventType_SERVER_STATUS,
+		Payload: &pb.Event_ServerStatus{ServerStatus: &pb.ServerStatusEvent{
+			ServerName: name,
+			NewStatus:  status,
+		}},
+	}
+}
+
+// openTest opens a journal whose clock advances a minute per call
+func openTest(t *testing.T, opts Options) (*Journal, time.Time) {
+	j, err := Open(t.TempDir(), opts)
+	require.NoError(t, err)
+	t.Cleanup(func() { j.Close() })
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := start
+	j.now = func() time.Time {
+		clock = clock.Add(time.Minute)
+		return clock
+	}
+	return j, start
+}
+
+func TestJournal_Query(t *testing.T) {
+	j, start := openTest(t, Options{})
+
+	require.NoError(t, j.Append(statusEvent("github", pb.ServerStatus_RUNNING)))
+	require.NoError(t, j.Append(statusEvent("slack", pb.ServerStatus_ERROR)))
+	require.NoError(t, j.Append(&pb.Event{Type: pb.EventType_CONFIG_CHANGE}))
+	require.NoError(t, j.Append(statusEvent("github", pb.ServerStatus_STOPPED)))
+
+	events, truncated, err := j.Query(Query{})
+	require.NoError(t, err)
+	assert.False(t, truncated)
+	require.Len(t, events, 4)
+	assert.Equal(t, pb.ServerStatus_RUNNING, events[0].GetServerStatus().NewStatus)
+	assert.Equal(t, start.Add(time.Minute).Unix(), events[0].Timestamp)
+
+	events, _, err = j.Query(Query{Server: "github"})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, pb.ServerStatus_STOPPED, events[1].GetServerStatus().NewStatus)
+
+	events, _, err = j.Query(Query{Types: []pb.EventType{pb.EventType_CONFIG_CHANGE}})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, pb.EventType_CONFIG_CHANGE, events[0].Type)
+
+	events, _, err = j.Query(Query{From: start.Add(2 * time.Minute), To: start.Add(3 * time.Minute)})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "slack", events[0].ServerName())
+
+	events, truncated, err = j.Query(Query{Limit: 3})
+	require.NoError(t, err)
+	assert.True(t, truncated)
+	assert.Len(t, events, 3)
+}
+
+func TestJournal_Export(t *testing.T) {
+	j, _ := openTest(t, Options{})
+
+	j.Export(&pb.Event{Type: pb.EventType_HEARTBEAT})
+	j.Export(statusEvent("github", pb.ServerStatus_RUNNING))
+
+	events, _, err := j.Query(Query{})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, pb.EventType_SERVER_STATUS, events[0].Type)
+}
+
+func TestJournal_PruneSize(t *testing.T) {
+	j, _ := openTest(t, Options{MaxSize: 4 * minSegmentSize})
+
+	// Fill well past the maximum size
+	event := statusEvent("github", pb.ServerStatus_RUNNING)
+	for range 5000 {
+		require.NoError(t, j.Append(event))
+	}
+
+	paths, err := j.segments()
+	require.NoError(t, err)
+	assert.Greater(t, len(paths), 1)
+
+	var total int64
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		total += info.Size()
+	}
+	assert.LessOrEqual(t, total, int64(5*minSegmentSize))
+
+	// The newest events are kept
+	events, _, err := j.Query(Query{})
+	require.NoError(t, err)
+	assert.Less(t, len(events), 5000)
+	assert.NotEmpty(t, events)
+}
+
+func TestJournal_PruneAge(t *testing.T) {
+	dir := t.TempDir()
+	j, err := Open(dir, Options{MaxAge: time.Hour})
+	require.NoError(t, err)
+	require.NoError(t, j.Append(statusEvent("github", pb.ServerStatus_RUNNING)))
+	require.NoError(t, j.Close())
+
+	paths, err := j.segments()
+	require.NoError(t, err)
+	require.Len(t, paths, 1)
+
+	// Segments last written before the maximum age are removed on open
+	old := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(paths[0], old, old))
+
+	j, err = Open(dir, Options{MaxAge: time.Hour})
+	require.NoError(t, err)
+	defer j.Close()
+
+	events, _, err := j.Query(Query{})
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
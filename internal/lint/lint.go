@@ -0,0 +1,195 @@
+// Package lint flags risky settings in mcp.json that load fine but go
+// against best practices, e.g. servers reaching the whole disk or secrets
+// kept inline
+package lint
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/tartavull/mcp-manager/internal/catalog"
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/redact"
+	"github.com/tartavull/mcp-manager/internal/secrets"
+)
+
+// Severities of findings
+const (
+	Error   = "error"
+	Warning = "warning"
+	Info    = "info"
+)
+
+// Severities lists the severities, most severe first
+var Severities = []string{Error, Warning, Info}
+
+// Finding is a risky setting
+type Finding struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Server   string `json:"server,omitempty"` // Empty for global settings
+	Message  string `json:"message"`
+}
+
+// AtLeast returns true if severity is at least as severe as min
+func AtLeast(severity, min string) bool {
+	return slices.Index(Severities, severity) <= slices.Index(Severities, min)
+}
+
+// driveRoot matches the root of a Windows drive, e.g. C:\
+var driveRoot = regexp.MustCompile(`^[A-Za-z]:[\\/]?$`)
+
+// Lint returns the findings of cfg, most severe first
+func Lint(cfg *config.MCPConfig) []Finding {
+	redactor, err := redact.New(cfg.Redaction)
+	if err != nil {
+		redactor = redact.Default()
+	}
+
+	var findings []Finding
+	add := func(rule, severity, server, format string, args ...interface{}) {
+		findings = append(findings, Finding{Rule: rule, Severity: severity, Server: server, Message: fmt.Sprintf(format, args...)})
+	}
+
+	names := make([]string, 0, len(cfg.Servers))
+	for name := range cfg.Servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	ports := make(map[int][]string)
+	if cfg.Gateway != nil && cfg.Gateway.Port != 0 {
+		ports[cfg.Gateway.Port] = append(ports[cfg.Gateway.Port], "the gateway")
+	}
+	descriptions := make(map[string][]string)
+	for _, name := range names {
+		srv := cfg.Servers[name]
+		if srv == nil {
+			continue
+		}
+
+		switch root := filesystemRoot(srv.Command); root {
+		case "":
+		case "~":
+			add("filesystem-root", Warning, name, "filesystem server can reach the whole home directory")
+		default:
+			add("filesystem-root", Error, name, "filesystem server is rooted at '%s', far wider than agents need", root)
+		}
+
+		for _, command := range []string{srv.Command, srv.ShadowCommand} {
+			for _, ref := range catalog.Unpinned(command) {
+				add("unpinned-package", Warning, name, "'%s' isn't pinned to a version, so a new release runs unreviewed", ref)
+			}
+		}
+
+		keys := make([]string, 0, len(srv.Env))
+		for key := range srv.Env {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if inlineSecret(redactor, key, srv.Env[key]) {
+				add("inline-secret", Warning, name, "env %s holds a secret inline; move it to the secrets store with 'mcp-manager secrets set %s/%s'", key, name, key)
+			}
+		}
+
+		if srv.Port != 0 {
+			ports[srv.Port] = append(ports[srv.Port], fmt.Sprintf("'%s'", name))
+		}
+		if description := strings.TrimSpace(srv.Description); description != "" {
+			descriptions[description] = append(descriptions[description], name)
+		}
+	}
+
+	portList := make([]int, 0, len(ports))
+	for port := range ports {
+		portList = append(portList, port)
+	}
+	sort.Ints(portList)
+	for _, port := range portList {
+		if users := ports[port]; len(users) > 1 {
+			add("duplicate-port", Error, "", "port %d is used by %s", port, strings.Join(users, " and "))
+		}
+	}
+	for description, servers := range descriptions {
+		if len(servers) > 1 {
+			add("duplicate-description", Info, "", "servers '%s' share the description %q, so agents can't tell them apart", strings.Join(servers, "', '"), description)
+		}
+	}
+
+	// Explicit wildcards are errors, the default any origin a warning. The
+	// daemon only defaults to loopback origins, so only a wildcard opens it.
+	wildcard := slices.Contains(cfg.CORSOrigins, "*")
+	if len(cfg.CORSOrigins) == 0 || wildcard {
+		severity := Warning
+		if wildcard {
+			severity = Error
+		}
+		var open []string
+		if cfg.Auth == nil || len(cfg.Auth.Proxies) == 0 {
+			open = append(open, "proxies")
+		}
+		if wildcard && (cfg.Auth == nil || len(cfg.Auth.Daemon) == 0) {
+			open = append(open, "daemon")
+		}
+		if len(open) > 0 {
+			add("cors-wildcard", severity, "", "any website can call the %s from a browser: set corsOrigins or auth", strings.Join(open, " and "))
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.Severity != b.Severity {
+			return slices.Index(Severities, a.Severity) < slices.Index(Severities, b.Severity)
+		}
+		if a.Server != b.Server {
+			return a.Server < b.Server
+		}
+		if a.Rule != b.Rule {
+			return a.Rule < b.Rule
+		}
+		return a.Message < b.Message
+	})
+	return findings
+}
+
+// filesystemRoot returns the root directory a filesystem server command
+// is given, "~" for the home directory, or "" if it gives none of them
+func filesystemRoot(command string) string {
+	fields := strings.Fields(command)
+	for i, field := range fields {
+		if !strings.Contains(field, "server-filesystem") {
+			continue
+		}
+		for _, arg := range fields[i+1:] {
+			arg = strings.Trim(arg, `'"`)
+			switch {
+			case arg == "" || strings.HasPrefix(arg, "-"):
+			case strings.Trim(arg, "/") == "" || driveRoot.MatchString(arg):
+				return arg
+			case arg == "~" || arg == "~/" || arg == "$HOME" || arg == "${HOME}":
+				return "~"
+			case filepath.Clean(arg) == "/home" || filepath.Clean(arg) == "/Users":
+				return filepath.Clean(arg)
+			}
+		}
+	}
+	return ""
+}
+
+// inlineSecret returns true if an env variable named as a secret holds
+// its value rather than a reference to the secrets store or to another
+// variable
+func inlineSecret(redactor *redact.Redactor, key, value string) bool {
+	if value == "" || !redactor.SecretKey(key) {
+		return false
+	}
+	if len(secrets.RefNames(value)) > 0 {
+		return false
+	}
+	return !strings.HasPrefix(value, "$")
+}
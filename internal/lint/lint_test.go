@@ -0,0 +1,74 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tartavull/mcp-manager/internal/config"
+)
+
+func TestLint(t *testing.T) {
+	cfg := &config.MCPConfig{
+		Servers: map[string]*config.MCPServerConfig{
+			"fs": {
+				Command:     "npx -y @modelcontextprotocol/server-filesystem@1.0.0 '/'",
+				Port:        4001,
+				Description: "Files",
+			},
+			"home": {
+				Command:     "npx -y @modelcontextprotocol/server-filesystem@1.0.0 ~",
+				Port:        4002,
+				Description: "Files",
+			},
+			"github": {
+				Command: "npx @modelcontextprotocol/server-github",
+				Port:    4000,
+				Env: map[string]string{
+					"GITHUB_TOKEN": "ghp_abc",
+					"API_KEY":      "secret:github/API_KEY",
+					"PASSWORD":     "${GITHUB_PASSWORD}",
+					"AUTH_TOKEN":   "Bearer ${secret:github/AUTH_TOKEN}",
+					"LOG_LEVEL":    "debug",
+				},
+			},
+		},
+		MCPSettings: config.MCPSettings{
+			Gateway:     &config.GatewayConfig{Port: 4000},
+			CORSOrigins: []string{"*"},
+			Auth:        &config.AuthConfig{Daemon: []string{"team"}},
+		},
+	}
+
+	assert.Equal(t, []Finding{
+		{Rule: "cors-wildcard", Severity: Error, Message: "any website can call the proxies from a browser: set corsOrigins or auth"},
+		{Rule: "duplicate-port", Severity: Error, Message: "port 4000 is used by the gateway and 'github'"},
+		{Rule: "filesystem-root", Severity: Error, Server: "fs", Message: "filesystem server is rooted at '/', far wider than agents need"},
+		{Rule: "inline-secret", Severity: Warning, Server: "github", Message: "env GITHUB_TOKEN holds a secret inline; move it to the secrets store with 'mcp-manager secrets set github/GITHUB_TOKEN'"},
+		{Rule: "unpinned-package", Severity: Warning, Server: "github", Message: "'@modelcontextprotocol/server-github' isn't pinned to a version, so a new release runs unreviewed"},
+		{Rule: "filesystem-root", Severity: Warning, Server: "home", Message: "filesystem server can reach the whole home directory"},
+		{Rule: "duplicate-description", Severity: Info, Message: `servers 'fs', 'home' share the description "Files", so agents can't tell them apart`},
+	}, Lint(cfg))
+}
+
+func TestLint_Clean(t *testing.T) {
+	cfg := &config.MCPConfig{
+		Servers: map[string]*config.MCPServerConfig{
+			"fs": {Command: "npx -y @modelcontextprotocol/server-filesystem@1.0.0 /home/me/projects"},
+		},
+		MCPSettings: config.MCPSettings{CORSOrigins: []string{"http://localhost:3000"}},
+	}
+	assert.Empty(t, Lint(cfg))
+
+	// Open by default is only a warning
+	cfg.CORSOrigins = nil
+	findings := Lint(cfg)
+	assert.Len(t, findings, 1)
+	assert.Equal(t, Warning, findings[0].Severity)
+	assert.Contains(t, findings[0].Message, "call the proxies from")
+}
+
+func TestAtLeast(t *testing.T) {
+	assert.True(t, AtLeast(Error, Warning))
+	assert.True(t, AtLeast(Warning, Warning))
+	assert.False(t, AtLeast(Info, Warning))
+}
@@ -0,0 +1,208 @@
+// Package logs captures the output of server processes so clients can
+// tail it without access to the daemon's filesystem
+package logs
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+const (
+	// DefaultCapacity is how many lines a buffer retains
+	DefaultCapacity = 1000
+
+	// maxLineLength splits lines longer than this many bytes
+	maxLineLength = 64 * 1024
+
+	// followerBuffer is how many lines a follower may fall behind before
+	// lines are dropped
+	followerBuffer = 256
+)
+
+// Severity is the guessed severity of a line
+type Severity string
+
+const (
+	SeverityUnknown Severity = ""
+	SeverityDebug   Severity = "debug"
+	SeverityInfo    Severity = "info"
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+// Line is a line of output
+type Line struct {
+	Time     time.Time
+	Stream   string // "stdout" or "stderr"
+	Text     string
+	Severity Severity
+}
+
+// Buffer retains the latest lines of a server's output and passes new
+// lines to followers
+type Buffer struct {
+	capacity int
+
+	mu        sync.Mutex
+	lines     []Line
+	followers map[chan Line]struct{}
+}
+
+// NewBuffer creates a buffer retaining capacity lines
+func NewBuffer(capacity int) *Buffer {
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+	return &Buffer{
+		capacity:  capacity,
+		followers: make(map[chan Line]struct{}),
+	}
+}
+
+// Add records a line written to stream
+func (b *Buffer) Add(stream, text string) {
+	line := Line{
+		Time:     time.Now(),
+		Stream:   stream,
+		Text:     text,
+		Severity: GuessSeverity(text),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lines = append(b.lines, line)
+	if len(b.lines) > b.capacity {
+		b.lines = append(b.lines[:0], b.lines[len(b.lines)-b.capacity:]...)
+	}
+
+	// Followers that fall behind miss lines rather than block the process
+	for follower := range b.followers {
+		select {
+		case follower <- line:
+		default:
+		}
+	}
+}
+
+// Tail returns the last n lines; all retained lines if n is zero, none if
+// it is negative
+func (b *Buffer) Tail(n int) []Line {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.tail(n)
+}
+
+// tail returns the last n lines. Must be called with b.mu held.
+func (b *Buffer) tail(n int) []Line {
+	if n < 0 {
+		return nil
+	}
+	start := 0
+	if n > 0 && n < len(b.lines) {
+		start = len(b.lines) - n
+	}
+	return append([]Line(nil), b.lines[start:]...)
+}
+
+// Follow returns the last n lines, as Tail, and a channel receiving the
+// lines added after them until stop is called
+func (b *Buffer) Follow(n int) (lines []Line, follow <-chan Line, stop func()) {
+	follower := make(chan Line, followerBuffer)
+
+	b.mu.Lock()
+	lines = b.tail(n)
+	b.followers[follower] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	stop = func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.followers, follower)
+			b.mu.Unlock()
+			close(follower)
+		})
+	}
+	return lines, follower, stop
+}
+
+// Writer returns a writer adding each line written to it as a line of
+// stream
+func (b *Buffer) Writer(stream string) io.Writer {
+	return &lineWriter{buffer: b, stream: stream}
+}
+
+// lineWriter splits writes into lines
+type lineWriter struct {
+	buffer  *Buffer
+	stream  string
+	mu      sync.Mutex
+	partial []byte
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	data := append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			break
+		}
+		w.buffer.Add(w.stream, strings.TrimSuffix(string(data[:i]), "\r"))
+		data = data[i+1:]
+	}
+	for len(data) >= maxLineLength {
+		w.buffer.Add(w.stream, string(data[:maxLineLength]))
+		data = data[maxLineLength:]
+	}
+
+	w.partial = append(w.partial[:0], data...)
+	return len(p), nil
+}
+
+// severityWords maps the words servers commonly mark lines with to the
+// severity they suggest
+var severityWords = map[string]Severity{
+	"panic":     SeverityError,
+	"fatal":     SeverityError,
+	"critical":  SeverityError,
+	"crit":      SeverityError,
+	"error":     SeverityError,
+	"err":       SeverityError,
+	"exception": SeverityError,
+	"traceback": SeverityError,
+	"warning":   SeverityWarning,
+	"warn":      SeverityWarning,
+	"info":      SeverityInfo,
+	"notice":    SeverityInfo,
+	"debug":     SeverityDebug,
+	"trace":     SeverityDebug,
+}
+
+// severityWordLimit is how many leading words are checked, where log
+// formats put the level
+const severityWordLimit = 6
+
+// GuessSeverity guesses the severity of a line from the level words near
+// its start, e.g. "ERROR", "[warn]" or "level=info"
+func GuessSeverity(text string) Severity {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	for i, word := range words {
+		if i == severityWordLimit {
+			break
+		}
+		if severity, ok := severityWords[strings.ToLower(word)]; ok {
+			return severity
+		}
+	}
+	return SeverityUnknown
+}
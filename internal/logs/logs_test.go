@@ -0,0 +1,94 @@
+package logs
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func texts(lines []Line) []string {
+	var result []string
+	for _, line := range lines {
+		result = append(result, line.Text)
+	}
+	return result
+}
+
+func TestBuffer_Tail(t *testing.T) {
+	b := NewBuffer(3)
+	for i := 1; i <= 5; i++ {
+		b.Add("stdout", fmt.Sprintf("line %d", i))
+	}
+
+	assert.Equal(t, []string{"line 3", "line 4", "line 5"}, texts(b.Tail(0)))
+	assert.Equal(t, []string{"line 4", "line 5"}, texts(b.Tail(2)))
+	assert.Equal(t, []string{"line 3", "line 4", "line 5"}, texts(b.Tail(10)))
+	assert.Empty(t, b.Tail(-1))
+}
+
+func TestBuffer_Follow(t *testing.T) {
+	b := NewBuffer(10)
+	b.Add("stdout", "old 1")
+	b.Add("stdout", "old 2")
+
+	lines, follow, stop := b.Follow(1)
+	assert.Equal(t, []string{"old 2"}, texts(lines))
+
+	b.Add("stderr", "new")
+	line := <-follow
+	assert.Equal(t, "new", line.Text)
+	assert.Equal(t, "stderr", line.Stream)
+	assert.False(t, line.Time.IsZero())
+
+	stop()
+	stop()
+	_, open := <-follow
+	assert.False(t, open)
+
+	// Lines added after stopping go nowhere
+	b.Add("stdout", "after")
+}
+
+func TestBuffer_Writer(t *testing.T) {
+	b := NewBuffer(10)
+	w := b.Writer("stderr")
+
+	_, err := io.WriteString(w, "first\nsec")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first"}, texts(b.Tail(0)))
+
+	_, err = io.WriteString(w, "ond\r\nthird\n")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first", "second", "third"}, texts(b.Tail(0)))
+
+	// Overlong lines are split
+	_, err = io.WriteString(w, strings.Repeat("x", maxLineLength+10))
+	require.NoError(t, err)
+	lines := b.Tail(1)
+	assert.Len(t, lines[0].Text, maxLineLength)
+}
+
+func TestGuessSeverity(t *testing.T) {
+	tests := []struct {
+		text string
+		want Severity
+	}{
+		{"ERROR: connection refused", SeverityError},
+		{"2024-01-02T03:04:05Z [warn] slow query", SeverityWarning},
+		{`time=2024-01-02 level=info msg="listening"`, SeverityInfo},
+		{"DEBUG loading tools", SeverityDebug},
+		{"panic: runtime error: index out of range", SeverityError},
+		{"Traceback (most recent call last):", SeverityError},
+		{"Server listening on stdio", SeverityUnknown},
+		{"one two three four five six seven error", SeverityUnknown},
+		{"", SeverityUnknown},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, GuessSeverity(tt.text), tt.text)
+	}
+}
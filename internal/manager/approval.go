@@ -0,0 +1,203 @@
+package manager
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+	mcpgrpc "github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/proxy"
+	"github.com/tartavull/mcp-manager/internal/server"
+	"github.com/tartavull/mcp-manager/internal/transcript"
+)
+
+// defaultApprovalTimeout is the time a call waits for approval before it
+// is rejected
+const defaultApprovalTimeout = 5 * time.Minute
+
+// pendingApproval is a call parked until an operator decides on it
+type pendingApproval struct {
+	mcpgrpc.Approval
+	decided chan error // Receives nil when approved, or why it was rejected
+}
+
+// parseApproval converts the tools needing approval from mcp.json,
+// applying defaults
+func parseApproval(cfg *config.ApprovalConfig) (*server.Approval, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	if len(cfg.Tools) == 0 {
+		return nil, fmt.Errorf("tools is required")
+	}
+
+	approval := &server.Approval{Tools: cfg.Tools, Timeout: defaultApprovalTimeout}
+	if cfg.Timeout != "" {
+		timeout, err := time.ParseDuration(cfg.Timeout)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("invalid timeout '%s'", cfg.Timeout)
+		}
+		approval.Timeout = timeout
+	}
+	return approval, nil
+}
+
+// parseApprovalConfig returns the tools of the server needing approval,
+// ignoring invalid settings
+func parseApprovalConfig(name string, cfg *config.MCPServerConfig) *server.Approval {
+	approval, err := parseApproval(cfg.Approval)
+	if err != nil {
+		log.Printf("Warning: ignoring approval settings for %s: %v", name, err)
+		return nil
+	}
+	return approval
+}
+
+// approvalOptions returns the proxy approval gate of a server, parking its
+// calls in the manager's queue
+func (m *Manager) approvalOptions(srv *server.Server) *proxy.ApprovalOptions {
+	if srv.Approval == nil {
+		return nil
+	}
+
+	name, timeout := srv.Name, srv.Approval.Timeout
+	return &proxy.ApprovalOptions{
+		Tools: srv.Approval.Tools,
+		Request: func(ctx context.Context, tool string, arguments string) error {
+			return m.requestApproval(ctx, name, tool, arguments, timeout)
+		},
+	}
+}
+
+// requestApproval parks a call until it is decided on, times out or its
+// client gives up
+func (m *Manager) requestApproval(ctx context.Context, name, tool, arguments string, timeout time.Duration) error {
+	buf := make([]byte, 4)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Errorf("failed to generate approval ID: %w", err)
+	}
+	now := time.Now()
+	ids := transcript.CorrelationFrom(ctx)
+	pending := &pendingApproval{
+		Approval: mcpgrpc.Approval{
+			ID:        hex.EncodeToString(buf),
+			Server:    name,
+			Tool:      tool,
+			Arguments: arguments,
+			Requested: now,
+			Deadline:  now.Add(timeout),
+			RequestID: ids.RequestID,
+			RunID:     ids.RunID,
+		},
+		decided: make(chan error, 1),
+	}
+
+	m.approvalsMu.Lock()
+	if m.approvals == nil {
+		m.approvals = make(map[string]*pendingApproval)
+	}
+	m.approvals[pending.ID] = pending
+	m.approvalsMu.Unlock()
+
+	waiting := fmt.Sprintf("Call to %s on %s waiting for approval %s", tool, name, pending.ID)
+	if described := ids.Describe(); described != "" {
+		waiting += " (" + described + ")"
+	}
+	log.Print(waiting)
+	m.emitApproval(mcpgrpc.ApprovalChange{Approval: pending.Approval, State: mcpgrpc.ApprovalPending})
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	var err error
+	select {
+	case err = <-pending.decided:
+		return err
+	case <-timer.C:
+		err = fmt.Errorf("no decision within %s", timeout)
+	case <-ctx.Done():
+		err = errors.New("client disconnected")
+	}
+
+	// Not decided on, unless a decision raced the timeout
+	if !m.removeApproval(pending.ID) {
+		return <-pending.decided
+	}
+	log.Printf("Approval %s for %s on %s expired: %v", pending.ID, tool, name, err)
+	m.emitApproval(mcpgrpc.ApprovalChange{Approval: pending.Approval, State: mcpgrpc.ApprovalExpired, Reason: err.Error()})
+	return err
+}
+
+// removeApproval takes a call out of the queue, returning false if it
+// wasn't in it anymore
+func (m *Manager) removeApproval(id string) bool {
+	m.approvalsMu.Lock()
+	defer m.approvalsMu.Unlock()
+
+	if _, exists := m.approvals[id]; !exists {
+		return false
+	}
+	delete(m.approvals, id)
+	return true
+}
+
+// PendingApprovals returns the calls waiting for approval, oldest first
+func (m *Manager) PendingApprovals() []mcpgrpc.Approval {
+	m.approvalsMu.Lock()
+	defer m.approvalsMu.Unlock()
+
+	approvals := make([]mcpgrpc.Approval, 0, len(m.approvals))
+	for _, pending := range m.approvals {
+		approvals = append(approvals, pending.Approval)
+	}
+	sort.Slice(approvals, func(i, j int) bool {
+		return approvals[i].Requested.Before(approvals[j].Requested)
+	})
+	return approvals
+}
+
+// DecideApproval lets a parked call through or rejects it with reason
+func (m *Manager) DecideApproval(id string, approved bool, reason string) error {
+	m.approvalsMu.Lock()
+	pending, exists := m.approvals[id]
+	delete(m.approvals, id)
+	m.approvalsMu.Unlock()
+	if !exists {
+		return fmt.Errorf("approval '%s' %w", id, server.ErrNotFound)
+	}
+
+	change := mcpgrpc.ApprovalChange{Approval: pending.Approval, State: mcpgrpc.ApprovalApproved}
+	if approved {
+		log.Printf("Approval %s for %s on %s approved", id, pending.Tool, pending.Server)
+		pending.decided <- nil
+	} else {
+		if reason == "" {
+			reason = "rejected by operator"
+		}
+		log.Printf("Approval %s for %s on %s rejected: %s", id, pending.Tool, pending.Server, reason)
+		change.State, change.Reason = mcpgrpc.ApprovalRejected, reason
+		pending.decided <- errors.New(reason)
+	}
+	m.emitApproval(change)
+	return nil
+}
+
+// emitApproval reports a change of the approval queue, dropping it rather
+// than block the proxy when nobody reads them
+func (m *Manager) emitApproval(change mcpgrpc.ApprovalChange) {
+	select {
+	case m.approvalChanges <- change:
+	default:
+	}
+}
+
+// ApprovalChanges returns the calls parked for approval and their outcomes
+func (m *Manager) ApprovalChanges() <-chan mcpgrpc.ApprovalChange {
+	return m.approvalChanges
+}
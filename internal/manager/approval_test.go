@@ -0,0 +1,85 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+	mcpgrpc "github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+func TestParseApproval(t *testing.T) {
+	approval, err := parseApproval(nil)
+	require.NoError(t, err)
+	assert.Nil(t, approval)
+
+	approval, err = parseApproval(&config.ApprovalConfig{Tools: []string{"post_message"}})
+	require.NoError(t, err)
+	assert.Equal(t, &server.Approval{Tools: []string{"post_message"}, Timeout: defaultApprovalTimeout}, approval)
+
+	approval, err = parseApproval(&config.ApprovalConfig{Tools: []string{"post_message"}, Timeout: "30s"})
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, approval.Timeout)
+
+	for _, cfg := range []*config.ApprovalConfig{
+		{},
+		{Tools: []string{"post_message"}, Timeout: "later"},
+		{Tools: []string{"post_message"}, Timeout: "0s"},
+	} {
+		_, err := parseApproval(cfg)
+		assert.Error(t, err, cfg)
+	}
+}
+
+func TestManager_Approvals(t *testing.T) {
+	m := &Manager{approvalChanges: make(chan mcpgrpc.ApprovalChange, 10)}
+	srv := server.NewServer("slack", "slack-mcp", 4001, "")
+	assert.Nil(t, m.approvalOptions(srv))
+
+	srv.Approval = &server.Approval{Tools: []string{"post_message"}, Timeout: time.Minute}
+	opts := m.approvalOptions(srv)
+	require.NotNil(t, opts)
+
+	// request parks a call and returns its outcome
+	request := func() chan error {
+		result := make(chan error, 1)
+		go func() { result <- opts.Request(context.Background(), "post_message", `{"text":"hi"}`) }()
+		change := <-m.ApprovalChanges()
+		assert.Equal(t, mcpgrpc.ApprovalPending, change.State)
+		return result
+	}
+
+	result := request()
+	pending := m.PendingApprovals()
+	require.Len(t, pending, 1)
+	assert.Equal(t, "slack", pending[0].Server)
+	assert.Equal(t, `{"text":"hi"}`, pending[0].Arguments)
+	require.NoError(t, m.DecideApproval(pending[0].ID, true, ""))
+	assert.NoError(t, <-result)
+	assert.Equal(t, mcpgrpc.ApprovalApproved, (<-m.ApprovalChanges()).State)
+	assert.Empty(t, m.PendingApprovals())
+
+	result = request()
+	require.NoError(t, m.DecideApproval(m.PendingApprovals()[0].ID, false, "not now"))
+	assert.EqualError(t, <-result, "not now")
+	change := <-m.ApprovalChanges()
+	assert.Equal(t, mcpgrpc.ApprovalRejected, change.State)
+	assert.Equal(t, "not now", change.Reason)
+
+	err := m.DecideApproval("missing", true, "")
+	assert.True(t, errors.Is(err, server.ErrNotFound))
+
+	// Calls nobody decides on expire
+	err = m.requestApproval(context.Background(), "slack", "post_message", "{}", 10*time.Millisecond)
+	assert.Error(t, err)
+	<-m.ApprovalChanges()
+	change = <-m.ApprovalChanges()
+	assert.Equal(t, mcpgrpc.ApprovalExpired, change.State)
+	assert.Empty(t, m.PendingApprovals())
+}
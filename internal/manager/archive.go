@@ -0,0 +1,236 @@
+package manager
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Archives are named after when they were taken
+const (
+	archiveLayout = "20060102-150405.000"
+	archiveExt    = ".tar.gz"
+)
+
+// archiveFile is an archive kept in a directory
+type archiveFile struct {
+	name    string
+	path    string
+	size    int64
+	created time.Time
+}
+
+// listArchives returns the archives in dir, oldest first
+func listArchives(dir string) []archiveFile {
+	entries, _ := os.ReadDir(dir)
+
+	var archives []archiveFile
+	for _, entry := range entries {
+		name, isArchive := strings.CutSuffix(entry.Name(), archiveExt)
+		info, err := entry.Info()
+		if !isArchive || err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		created, err := time.ParseInLocation(archiveLayout, name, time.Local)
+		if err != nil {
+			created = info.ModTime()
+		}
+		archives = append(archives, archiveFile{name: name, path: filepath.Join(dir, entry.Name()), size: info.Size(), created: created})
+	}
+	return archives
+}
+
+// archiveEntry is a file or directory archived under name, or at the root
+// of the archive when name is empty. Entries with data are files holding
+// it instead.
+type archiveEntry struct {
+	name string
+	path string
+	data []byte
+}
+
+// archiveDir writes the files, directories and symlinks of dir to a
+// gzipped tarball at path
+func archiveDir(dir, path string) error {
+	return writeArchive(path, archiveEntry{path: dir})
+}
+
+// writeArchive writes entries to a gzipped tarball at path, replacing it
+// once complete. Directories are archived with their files and symlinks,
+// leaving out the locks of a running Chromium.
+func writeArchive(path string, entries ...archiveEntry) error {
+	tmp := path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+	for _, entry := range entries {
+		if err = archiveEntryTo(tw, entry); err != nil {
+			break
+		}
+	}
+	err = errors.Join(err, tw.Close(), gz.Close(), file.Close())
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// archiveEntryTo writes an entry to an archive
+func archiveEntryTo(tw *tar.Writer, entry archiveEntry) error {
+	if entry.data != nil {
+		header := &tar.Header{Name: entry.name, Mode: 0600, Size: int64(len(entry.data)), ModTime: time.Now()}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err := tw.Write(entry.data)
+		return err
+	}
+
+	return filepath.WalkDir(entry.path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(entry.path, p)
+		if err != nil {
+			return err
+		}
+		name := path.Join(entry.name, filepath.ToSlash(rel))
+		if name == "." || strings.HasPrefix(d.Name(), "Singleton") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		var link string
+		switch {
+		case info.Mode()&fs.ModeSymlink != 0:
+			if link, err = os.Readlink(p); err != nil {
+				return err
+			}
+		case !info.IsDir() && !info.Mode().IsRegular():
+			return nil // Sockets and the like are recreated by their owners
+		}
+
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = name
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		src, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.Copy(tw, src)
+		return err
+	})
+}
+
+// extractArchive extracts a gzipped tarball written by writeArchive,
+// writing each entry to the path target returns for its name, or skipping
+// it if target returns ""
+func extractArchive(path string, target func(name string) string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !filepath.IsLocal(header.Name) {
+			return fmt.Errorf("invalid path '%s' in archive", header.Name)
+		}
+
+		dest := target(header.Name)
+		if dest == "" {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(dest), 0700); err != nil {
+			return err
+		}
+		mode := header.FileInfo().Mode().Perm()
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(dest, mode|0700)
+		case tar.TypeSymlink:
+			err = os.Symlink(header.Linkname, dest)
+		case tar.TypeReg:
+			err = extractFile(tr, dest, mode)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// extractFile writes the current file of an archive to dest
+func extractFile(tr *tar.Reader, dest string, mode fs.FileMode) error {
+	dst, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dst, tr)
+	return errors.Join(err, dst.Close())
+}
+
+// readArchiveFile returns the contents of the file name in a gzipped
+// tarball
+func readArchiveFile(path, name string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("%s not found in archive", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Name == name && header.Typeflag == tar.TypeReg {
+			return io.ReadAll(tr)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package manager
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+
+	"github.com/tartavull/mcp-manager/internal/auth"
+	"github.com/tartavull/mcp-manager/internal/config"
+)
+
+// newProxyToken returns a random token the daemon authenticates with to
+// its own proxies
+func newProxyToken() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// buildProxyAuth returns the authentication of the proxies from mcp.json,
+// also accepting the daemon's token, or nil if they're open
+func buildProxyAuth(cfg *config.AuthConfig, token string) (auth.Provider, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	provider, err := auth.Build(cfg, cfg.Proxies)
+	if err != nil {
+		return nil, err
+	}
+	return auth.Trust(provider, token), nil
+}
+
+// reloadProxyAuth applies the proxy authentication of a reloaded mcp.json
+// to proxies started from now on, keeping the previous settings if the
+// new ones are invalid
+func (m *Manager) reloadProxyAuth(cfg *config.AuthConfig) {
+	provider, err := buildProxyAuth(cfg, m.proxyToken)
+	if err != nil {
+		log.Printf("Warning: keeping the previous proxy auth settings: %v", err)
+		return
+	}
+	m.proxyAuth = provider
+}
+
+// ProxyToken returns the token the proxies accept from the daemon, e.g.
+// for the gateway
+func (m *Manager) ProxyToken() string {
+	return m.proxyToken
+}
@@ -0,0 +1,63 @@
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/internal/auth"
+	"github.com/tartavull/mcp-manager/internal/config"
+)
+
+func TestBuildProxyAuth(t *testing.T) {
+	provider, err := buildProxyAuth(nil, "internal")
+	require.NoError(t, err)
+	assert.Nil(t, provider)
+
+	cfg := &config.AuthConfig{
+		Providers: map[string]*config.AuthProviderConfig{
+			"ci": {Type: "token", Tokens: map[string]string{"ci": "secret"}},
+		},
+		Daemon: []string{"ci"},
+	}
+	provider, err = buildProxyAuth(cfg, "internal")
+	require.NoError(t, err)
+	assert.Nil(t, provider, "proxies stay open unless listed")
+
+	cfg.Proxies = []string{"ci"}
+	provider, err = buildProxyAuth(cfg, "internal")
+	require.NoError(t, err)
+	identity, err := provider.Authenticate(context.Background(), auth.Credentials{Token: "internal"})
+	require.NoError(t, err)
+	assert.Equal(t, auth.DaemonClient, identity.Provider)
+	identity, err = provider.Authenticate(context.Background(), auth.Credentials{Token: "secret"})
+	require.NoError(t, err)
+	assert.Equal(t, "ci", identity.Subject)
+
+	cfg.Proxies = []string{"missing"}
+	_, err = buildProxyAuth(cfg, "internal")
+	assert.EqualError(t, err, "unknown auth provider 'missing'")
+}
+
+func TestReloadProxyAuth(t *testing.T) {
+	m := &Manager{proxyToken: newProxyToken()}
+	assert.Len(t, m.ProxyToken(), 32)
+
+	cfg := &config.AuthConfig{
+		Providers: map[string]*config.AuthProviderConfig{
+			"ci": {Type: "token", Tokens: map[string]string{"ci": "secret"}},
+		},
+		Proxies: []string{"ci"},
+	}
+	m.reloadProxyAuth(cfg)
+	require.NotNil(t, m.proxyAuth)
+
+	// Invalid settings keep the proxies guarded
+	cfg.Proxies = []string{"missing"}
+	m.reloadProxyAuth(cfg)
+	assert.NotNil(t, m.proxyAuth)
+
+	m.reloadProxyAuth(nil)
+	assert.Nil(t, m.proxyAuth)
+}
@@ -0,0 +1,233 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	mcpgrpc "github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/server"
+	"github.com/tartavull/mcp-manager/internal/shellenv"
+)
+
+// backupManifest is the first file of a backup, recording the data paths
+// archived as 0, 1, ... in order
+type backupManifest struct {
+	Server  string    `json:"server"`
+	Paths   []string  `json:"paths"`
+	Created time.Time `json:"created"`
+}
+
+// manifestName is the name of the manifest in a backup
+const manifestName = "manifest.json"
+
+// backupsDir returns the directory holding the backups of a server
+func (m *Manager) backupsDir(name string) string {
+	return filepath.Join(m.config.GetStateDir(), "backups", name)
+}
+
+// dataPaths returns the data paths of a server with its env and ~ expanded
+func dataPaths(srv *server.Server) ([]string, error) {
+	if len(srv.DataPaths) == 0 {
+		return nil, fmt.Errorf("server '%s' has no dataPaths", srv.Name)
+	}
+
+	paths := make([]string, len(srv.DataPaths))
+	for i, path := range srv.DataPaths {
+		expanded := shellenv.ExpandHome(os.Expand(path, func(key string) string {
+			if value, ok := srv.Env[key]; ok {
+				return value
+			}
+			return os.Getenv(key)
+		}))
+		if !filepath.IsAbs(expanded) {
+			return nil, fmt.Errorf("data path '%s' of '%s' is not absolute", path, srv.Name)
+		}
+		paths[i] = filepath.Clean(expanded)
+	}
+	return paths, nil
+}
+
+// backupTarget returns a copy of a server with data paths, and its data
+// paths
+func (m *Manager) backupTarget(name string) (*server.Server, []string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	srv, exists := m.servers[name]
+	if !exists {
+		return nil, nil, fmt.Errorf("server '%s' %w", name, server.ErrNotFound)
+	}
+	paths, err := dataPaths(srv)
+	if err != nil {
+		return nil, nil, err
+	}
+	srvCopy := *srv
+	return &srvCopy, paths, nil
+}
+
+// whileStopped runs fn with a server stopped, starting it again afterwards
+// if it was running, so its data isn't written meanwhile
+func (m *Manager) whileStopped(srv *server.Server, fn func() error) error {
+	if !srv.IsRunning() {
+		return fn()
+	}
+
+	log.Printf("Stopping %s to access its data", srv.Name)
+	if err := m.StopServer(srv.Name); err != nil {
+		return err
+	}
+	err := fn()
+	if startErr := m.StartServer(srv.Name); startErr != nil {
+		log.Printf("Failed to start %s again: %v", srv.Name, startErr)
+		if err == nil {
+			err = fmt.Errorf("failed to start '%s' again: %w", srv.Name, startErr)
+		}
+	}
+	return err
+}
+
+// BackupServer archives the data paths of a server into a backup named
+// after when it was taken. Running servers are stopped meanwhile and
+// started again.
+func (m *Manager) BackupServer(name string) (*mcpgrpc.Backup, error) {
+	m.backupMu.Lock()
+	defer m.backupMu.Unlock()
+
+	srv, paths, err := m.backupTarget(name)
+	if err != nil {
+		return nil, err
+	}
+	dir := m.backupsDir(name)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	created := time.Now()
+	backup := &mcpgrpc.Backup{Server: name, Name: created.Format(archiveLayout), Created: created}
+	backup.Path = filepath.Join(dir, backup.Name+archiveExt)
+
+	manifest, err := json.MarshalIndent(backupManifest{Server: name, Paths: paths, Created: created.UTC()}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	entries := []archiveEntry{{name: manifestName, data: manifest}}
+	for i, path := range paths {
+		// Paths that don't exist yet are left out, and kept as they are on restore
+		if _, err := os.Lstat(path); err == nil {
+			entries = append(entries, archiveEntry{name: strconv.Itoa(i), path: path})
+		}
+	}
+
+	err = m.whileStopped(srv, func() error {
+		return writeArchive(backup.Path, entries...)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to back up '%s': %w", name, err)
+	}
+	if info, err := os.Stat(backup.Path); err == nil {
+		backup.Size = info.Size()
+	}
+	log.Printf("Backed up %s to %s", name, backup.Path)
+	return backup, nil
+}
+
+// Backups returns the backups of a server, oldest first
+func (m *Manager) Backups(name string) ([]mcpgrpc.Backup, error) {
+	m.mu.RLock()
+	_, exists := m.servers[name]
+	m.mu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("server '%s' %w", name, server.ErrNotFound)
+	}
+
+	var backups []mcpgrpc.Backup
+	for _, archive := range listArchives(m.backupsDir(name)) {
+		backups = append(backups, mcpgrpc.Backup{
+			Server:  name,
+			Name:    archive.name,
+			Path:    archive.path,
+			Size:    archive.size,
+			Created: archive.created,
+		})
+	}
+	return backups, nil
+}
+
+// RestoreBackup replaces the data paths of a server with those in a
+// backup. Running servers are stopped meanwhile and started again.
+func (m *Manager) RestoreBackup(name, backup string) error {
+	m.backupMu.Lock()
+	defer m.backupMu.Unlock()
+
+	srv, paths, err := m.backupTarget(name)
+	if err != nil {
+		return err
+	}
+	if !profilePattern.MatchString(backup) {
+		return fmt.Errorf("invalid backup '%s'", backup)
+	}
+	archive := filepath.Join(m.backupsDir(name), backup+archiveExt)
+	if _, err := os.Stat(archive); err != nil {
+		return fmt.Errorf("backup '%s' of '%s' %w", backup, name, server.ErrNotFound)
+	}
+
+	data, err := readArchiveFile(archive, manifestName)
+	if err != nil {
+		return fmt.Errorf("failed to read backup '%s': %w", backup, err)
+	}
+	var manifest backupManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("failed to read backup '%s': %w", backup, err)
+	}
+	if len(manifest.Paths) != len(paths) {
+		return fmt.Errorf("backup '%s' has %d data paths, '%s' has %d", backup, len(manifest.Paths), name, len(paths))
+	}
+
+	return m.whileStopped(srv, func() error {
+		return restorePaths(archive, paths)
+	})
+}
+
+// restorePaths extracts the data paths in a backup next to where they
+// belong, then replaces them, so a broken archive leaves them alone
+func restorePaths(archive string, paths []string) error {
+	for _, path := range paths {
+		os.RemoveAll(path + ".restore")
+	}
+
+	restored := make(map[int]bool)
+	err := extractArchive(archive, func(name string) string {
+		index, rest, _ := strings.Cut(name, "/")
+		i, err := strconv.Atoi(index)
+		if err != nil || i < 0 || i >= len(paths) {
+			return "" // The manifest
+		}
+		restored[i] = true
+		return filepath.Join(paths[i]+".restore", rest)
+	})
+	defer func() {
+		for i := range restored {
+			os.RemoveAll(paths[i] + ".restore")
+		}
+	}()
+	if err != nil {
+		return err
+	}
+
+	for i := range restored {
+		if err := os.RemoveAll(paths[i]); err != nil {
+			return err
+		}
+		if err := os.Rename(paths[i]+".restore", paths[i]); err != nil {
+			return err
+		}
+	}
+	log.Printf("Restored %s from %s", strings.Join(paths, ", "), archive)
+	return nil
+}
@@ -0,0 +1,82 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+func TestManager_BackupServer(t *testing.T) {
+	m := createTestManager(t)
+	data := t.TempDir()
+	store := filepath.Join(data, "memory.json")
+	db := filepath.Join(data, "db")
+	require.NoError(t, os.WriteFile(store, []byte(`{"v": 1}`), 0600))
+	require.NoError(t, os.MkdirAll(db, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(db, "app.sqlite"), []byte("v1"), 0600))
+
+	_, err := m.BackupServer("test1")
+	assert.ErrorContains(t, err, "has no dataPaths")
+
+	srv := m.servers["test1"]
+	srv.Env = map[string]string{"MEMORY_FILE_PATH": store}
+	srv.DataPaths = []string{"${MEMORY_FILE_PATH}", db, filepath.Join(data, "missing")}
+
+	backup, err := m.BackupServer("test1")
+	require.NoError(t, err)
+	assert.FileExists(t, backup.Path)
+	assert.Equal(t, filepath.Join(m.config.GetStateDir(), "backups", "test1"), filepath.Dir(backup.Path))
+
+	require.NoError(t, os.WriteFile(store, []byte(`{"v": 2}`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(db, "app.sqlite-wal"), []byte("v2"), 0600))
+
+	backups, err := m.Backups("test1")
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	assert.Equal(t, backup.Name, backups[0].Name)
+
+	require.NoError(t, m.RestoreBackup("test1", backup.Name))
+	contents, err := os.ReadFile(store)
+	require.NoError(t, err)
+	assert.Equal(t, `{"v": 1}`, string(contents))
+	assert.FileExists(t, filepath.Join(db, "app.sqlite"))
+	assert.NoFileExists(t, filepath.Join(db, "app.sqlite-wal"))
+	assert.NoFileExists(t, store+".restore")
+
+	// Backups only restore into as many data paths as they hold
+	srv.DataPaths = srv.DataPaths[:1]
+	assert.ErrorContains(t, m.RestoreBackup("test1", backup.Name), "has 3 data paths")
+	assert.ErrorIs(t, m.RestoreBackup("test1", "missing"), server.ErrNotFound)
+	_, err = m.Backups("missing")
+	assert.ErrorIs(t, err, server.ErrNotFound)
+}
+
+func TestManager_BackupRunningServer(t *testing.T) {
+	m := createTestManager(t)
+	data := t.TempDir()
+
+	// The server writes its data until it stops
+	srv := server.NewServer("memory", mockMCPCommand, 8114, "Memory server")
+	srv.DataPaths = []string{data}
+	srv.Hooks = &server.Hooks{PreStop: "echo flushed > " + filepath.Join(data, "flushed"), Timeout: 5 * time.Second}
+	m.servers["memory"] = srv
+	require.NoError(t, m.StartServer("memory"))
+	defer m.StopServer("memory")
+	pid := srv.PID
+
+	backup, err := m.BackupServer("memory")
+	require.NoError(t, err)
+	assert.True(t, srv.IsRunning(), "the server is started again")
+	assert.NotEqual(t, pid, srv.PID)
+
+	require.NoError(t, os.Remove(filepath.Join(data, "flushed")))
+	require.NoError(t, m.RestoreBackup("memory", backup.Name))
+	assert.FileExists(t, filepath.Join(data, "flushed"), "the backup is taken once stopped")
+	assert.True(t, srv.IsRunning())
+}
@@ -0,0 +1,89 @@
+package manager
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+	mcpgrpc "github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/proxy"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+const (
+	// defaultBreakerFailures is the number of consecutive failures opening
+	// a circuit
+	defaultBreakerFailures = 5
+
+	// defaultBreakerCooldown is the time a circuit stays open
+	defaultBreakerCooldown = 30 * time.Second
+)
+
+// parseCircuitBreaker converts a circuit breaker from mcp.json, applying
+// defaults
+func parseCircuitBreaker(cfg *config.CircuitBreakerConfig) (*server.CircuitBreaker, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	breaker := &server.CircuitBreaker{Failures: defaultBreakerFailures, Cooldown: defaultBreakerCooldown}
+	if cfg.Failures < 0 {
+		return nil, fmt.Errorf("invalid failures %d", cfg.Failures)
+	}
+	if cfg.Failures > 0 {
+		breaker.Failures = cfg.Failures
+	}
+	if cfg.Cooldown != "" {
+		cooldown, err := time.ParseDuration(cfg.Cooldown)
+		if err != nil || cooldown <= 0 {
+			return nil, fmt.Errorf("invalid cooldown '%s'", cfg.Cooldown)
+		}
+		breaker.Cooldown = cooldown
+	}
+
+	return breaker, nil
+}
+
+// parseCircuitBreakerConfig returns the server's circuit breaker, ignoring
+// invalid ones
+func parseCircuitBreakerConfig(name string, cfg *config.MCPServerConfig) *server.CircuitBreaker {
+	breaker, err := parseCircuitBreaker(cfg.CircuitBreaker)
+	if err != nil {
+		log.Printf("Warning: ignoring circuit breaker for %s: %v", name, err)
+		return nil
+	}
+	return breaker
+}
+
+// circuitBreakerOptions returns the proxy circuit breaker of a server,
+// reporting its changes as events
+func (m *Manager) circuitBreakerOptions(srv *server.Server) *proxy.CircuitBreakerOptions {
+	if srv.CircuitBreaker == nil {
+		return nil
+	}
+
+	name := srv.Name
+	return &proxy.CircuitBreakerOptions{
+		Failures: srv.CircuitBreaker.Failures,
+		Cooldown: srv.CircuitBreaker.Cooldown,
+		OnChange: func(state proxy.CircuitState, reason string) {
+			if reason != "" {
+				log.Printf("Circuit breaker for %s %s: %s", name, state, reason)
+			} else {
+				log.Printf("Circuit breaker for %s %s", name, state)
+			}
+
+			// Drop changes nobody is reading rather than block the proxy
+			select {
+			case m.circuitChanges <- mcpgrpc.CircuitChange{Server: name, State: string(state), Reason: reason}:
+			default:
+			}
+		},
+	}
+}
+
+// CircuitChanges returns the circuit breaker changes of the proxies
+func (m *Manager) CircuitChanges() <-chan mcpgrpc.CircuitChange {
+	return m.circuitChanges
+}
@@ -0,0 +1,55 @@
+package manager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+	mcpgrpc "github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/proxy"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+func TestParseCircuitBreaker(t *testing.T) {
+	breaker, err := parseCircuitBreaker(nil)
+	require.NoError(t, err)
+	assert.Nil(t, breaker)
+
+	breaker, err = parseCircuitBreaker(&config.CircuitBreakerConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, &server.CircuitBreaker{Failures: defaultBreakerFailures, Cooldown: defaultBreakerCooldown}, breaker)
+
+	breaker, err = parseCircuitBreaker(&config.CircuitBreakerConfig{Failures: 3, Cooldown: "1m"})
+	require.NoError(t, err)
+	assert.Equal(t, &server.CircuitBreaker{Failures: 3, Cooldown: time.Minute}, breaker)
+
+	for _, cfg := range []*config.CircuitBreakerConfig{
+		{Failures: -1},
+		{Cooldown: "soon"},
+		{Cooldown: "0s"},
+	} {
+		_, err := parseCircuitBreaker(cfg)
+		assert.Error(t, err, cfg)
+	}
+}
+
+func TestManager_CircuitChanges(t *testing.T) {
+	m := &Manager{circuitChanges: make(chan mcpgrpc.CircuitChange, 1)}
+	srv := server.NewServer("github", "github-mcp", 4001, "")
+	assert.Nil(t, m.circuitBreakerOptions(srv))
+
+	srv.CircuitBreaker = &server.CircuitBreaker{Failures: 2, Cooldown: time.Second}
+	opts := m.circuitBreakerOptions(srv)
+	require.NotNil(t, opts)
+	assert.Equal(t, 2, opts.Failures)
+
+	opts.OnChange(proxy.CircuitOpen, "timeout")
+	assert.Equal(t, mcpgrpc.CircuitChange{Server: "github", State: "open", Reason: "timeout"}, <-m.CircuitChanges())
+
+	// Changes don't block when nobody reads them
+	opts.OnChange(proxy.CircuitHalfOpen, "")
+	opts.OnChange(proxy.CircuitClosed, "")
+}
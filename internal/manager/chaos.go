@@ -0,0 +1,58 @@
+package manager
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// parseChaos converts the faults injected into a server's proxy from
+// mcp.json
+func parseChaos(cfg *config.ChaosConfig) (*server.Chaos, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	chaos := &server.Chaos{
+		DropRate:    cfg.DropRate,
+		RestartRate: cfg.RestartRate,
+		Methods:     cfg.Methods,
+		Seed:        cfg.Seed,
+	}
+	if cfg.Latency != "" {
+		latency, err := time.ParseDuration(cfg.Latency)
+		if err != nil || latency < 0 {
+			return nil, fmt.Errorf("invalid latency '%s'", cfg.Latency)
+		}
+		chaos.Latency = latency
+	}
+	if cfg.Jitter != "" {
+		jitter, err := time.ParseDuration(cfg.Jitter)
+		if err != nil || jitter < 0 {
+			return nil, fmt.Errorf("invalid jitter '%s'", cfg.Jitter)
+		}
+		chaos.Jitter = jitter
+	}
+	if cfg.DropRate < 0 || cfg.DropRate > 1 {
+		return nil, fmt.Errorf("invalid dropRate %g, expected 0 to 1", cfg.DropRate)
+	}
+	if cfg.RestartRate < 0 || cfg.RestartRate > 1 {
+		return nil, fmt.Errorf("invalid restartRate %g, expected 0 to 1", cfg.RestartRate)
+	}
+
+	return chaos, nil
+}
+
+// parseChaosConfig returns the faults injected into the server's proxy,
+// ignoring invalid settings
+func parseChaosConfig(name string, cfg *config.MCPServerConfig) *server.Chaos {
+	chaos, err := parseChaos(cfg.Chaos)
+	if err != nil {
+		log.Printf("Warning: ignoring chaos settings for %s: %v", name, err)
+		return nil
+	}
+	return chaos
+}
@@ -0,0 +1,38 @@
+package manager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+func TestParseChaos(t *testing.T) {
+	chaos, err := parseChaos(nil)
+	require.NoError(t, err)
+	assert.Nil(t, chaos)
+
+	chaos, err = parseChaos(&config.ChaosConfig{Latency: "200ms", Jitter: "1s", DropRate: 0.1, RestartRate: 0.01, Seed: 7})
+	require.NoError(t, err)
+	assert.Equal(t, &server.Chaos{
+		Latency:     200 * time.Millisecond,
+		Jitter:      time.Second,
+		DropRate:    0.1,
+		RestartRate: 0.01,
+		Seed:        7,
+	}, chaos)
+
+	for _, cfg := range []*config.ChaosConfig{
+		{Latency: "slow"},
+		{Jitter: "-1s"},
+		{DropRate: 1.5},
+		{RestartRate: -0.1},
+	} {
+		_, err := parseChaos(cfg)
+		assert.Error(t, err, cfg)
+	}
+}
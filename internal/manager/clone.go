@@ -0,0 +1,47 @@
+package manager
+
+import (
+	"fmt"
+
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// CloneServer copies a server in mcp.json under a new name, "<source>-2"
+// or the next free number if empty, on the next free port, with the
+// replacements "old=new" in its command, e.g. to serve another directory.
+// The copy is loaded stopped. Returns its name and port.
+func (m *Manager) CloneServer(source, name string, replace []string) (string, int, error) {
+	m.mu.Lock()
+	mcpConfig, err := m.config.LoadMCPConfig()
+	if err != nil {
+		m.mu.Unlock()
+		return "", 0, fmt.Errorf("failed to load MCP config: %w", err)
+	}
+	if _, exists := mcpConfig.Servers[source]; !exists {
+		srv, loaded := m.servers[source]
+		m.mu.Unlock()
+		if loaded && srv.Group != "" {
+			return "", 0, fmt.Errorf("server '%s' is an instance of '%s', clone it instead", source, srv.Group)
+		}
+		return "", 0, fmt.Errorf("server '%s' %w", source, server.ErrNotFound)
+	}
+	if _, exists := m.servers[name]; exists {
+		m.mu.Unlock()
+		return "", 0, fmt.Errorf("server '%s' %w", name, server.ErrExists)
+	}
+
+	name, err = mcpConfig.CloneServer(source, name, m.config.DefaultBasePort(), replace)
+	if err == nil {
+		err = m.config.SaveMCPConfig(mcpConfig)
+	}
+	m.mu.Unlock()
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to clone server '%s': %w", source, err)
+	}
+
+	// Load the copy now rather than when the watcher notices
+	if err := m.reloadConfig(); err != nil {
+		return "", 0, err
+	}
+	return name, mcpConfig.Servers[name].Port, nil
+}
@@ -0,0 +1,44 @@
+package manager
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+func TestManager_CloneServer(t *testing.T) {
+	manager := createTestManager(t)
+	manager.servers = make(map[string]*server.Server)
+	require.NoError(t, os.WriteFile(manager.config.GetMCPConfigPath(), []byte(`{
+  "servers": {
+    "filesystem": {"command": "server-filesystem /home/me/docs", "port": 5000},
+    "browser": {"command": "playwright-mcp", "port": 5001, "instances": 2}
+  }
+}`), 0644))
+	require.NoError(t, manager.reloadConfig())
+
+	name, port, err := manager.CloneServer("filesystem", "", []string{"docs=code"})
+	require.NoError(t, err)
+	assert.Equal(t, "filesystem-2", name)
+	assert.Equal(t, 5003, port, "the next port after the instances of browser")
+
+	srv, err := manager.GetServer("filesystem-2")
+	require.NoError(t, err)
+	assert.Equal(t, "server-filesystem /home/me/code", srv.Command)
+	assert.Equal(t, 5003, srv.Port)
+	assert.Equal(t, server.StatusStopped, srv.Status)
+
+	mcpConfig, err := manager.config.LoadMCPConfig()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"filesystem", "filesystem-2", "browser"}, mcpConfig.ServerOrder)
+
+	_, _, err = manager.CloneServer("browser-1", "", nil)
+	assert.EqualError(t, err, "server 'browser-1' is an instance of 'browser', clone it instead")
+	_, _, err = manager.CloneServer("missing", "", nil)
+	assert.ErrorIs(t, err, server.ErrNotFound)
+	_, _, err = manager.CloneServer("filesystem", "browser-2", nil)
+	assert.ErrorIs(t, err, server.ErrExists)
+}
@@ -0,0 +1,139 @@
+package manager
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	mcpgrpc "github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/secrets"
+	"github.com/tartavull/mcp-manager/internal/server"
+	"github.com/tartavull/mcp-manager/internal/shellenv"
+)
+
+// DescribeServer returns what a server runs as, or would if started now:
+// the command as spawned, the variables it gets, its working directory and
+// limits. Values of variables are left out, they may be secrets.
+func (m *Manager) DescribeServer(name string) (*mcpgrpc.Runtime, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	srv, exists := m.servers[name]
+	if !exists {
+		return nil, fmt.Errorf("server '%s' %w", name, server.ErrNotFound)
+	}
+
+	env := m.serverEnv(srv)
+	if env == nil {
+		env = os.Environ()
+	}
+	runtime := &mcpgrpc.Runtime{
+		Server: name,
+		Env:    m.runtimeVars(srv),
+		Path:   shellenv.Lookup(env, "PATH"),
+		Limits: runtimeLimits(srv),
+	}
+	if srv.Transport != nil {
+		runtime.Transport = srv.Transport.Plugin
+	}
+	// Servers are spawned in the daemon's directory
+	if cwd, err := os.Getwd(); err == nil {
+		runtime.Cwd = cwd
+	}
+
+	if proxyServer, exists := m.proxies[name]; exists && srv.IsRunning() {
+		runtime.Command = proxyServer.Command()
+		runtime.Running = true
+		return runtime, nil
+	}
+
+	command, err := m.transportCommand(srv)
+	if err != nil {
+		runtime.Command = srv.Command
+		runtime.Error = err.Error()
+		return runtime, nil
+	}
+	runtime.Command = m.spawnCommand(srv, command)
+	if _, err := m.resolveEnv(srv); err != nil {
+		runtime.Error = err.Error()
+	}
+	return runtime, nil
+}
+
+// runtimeVars returns the variables a server gets on top of the daemon's
+// environment, sorted by name, as serverEnv layers them. Must be called
+// with m.mu held.
+func (m *Manager) runtimeVars(srv *server.Server) []mcpgrpc.RuntimeVar {
+	sources := make(map[string]string)
+	proxyEnv := srv.ProxyEnv
+	if proxyEnv == nil {
+		proxyEnv = m.proxyEnv
+	}
+	for _, v := range proxyEnv {
+		name, _, _ := strings.Cut(v, "=")
+		sources[name] = mcpgrpc.VarProxy
+	}
+	for name, value := range srv.Env {
+		sources[name] = mcpgrpc.VarConfig
+		if len(secrets.RefNames(value)) > 0 {
+			sources[name] = mcpgrpc.VarSecret
+		}
+	}
+	if srv.BrowserProfile != "" {
+		sources[ProfileDirEnv] = mcpgrpc.VarProfile
+	}
+
+	vars := make([]mcpgrpc.RuntimeVar, 0, len(sources))
+	for name, source := range sources {
+		vars = append(vars, mcpgrpc.RuntimeVar{Name: name, Source: source})
+	}
+	sort.Slice(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name })
+	return vars
+}
+
+// runtimeLimits returns the limits put on a server's process and proxy
+func runtimeLimits(srv *server.Server) []mcpgrpc.RuntimeLimit {
+	var limits []mcpgrpc.RuntimeLimit
+	add := func(name, format string, args ...interface{}) {
+		limits = append(limits, mcpgrpc.RuntimeLimit{Name: name, Value: fmt.Sprintf(format, args...)})
+	}
+
+	if p := srv.Priority; p != nil {
+		if p.Nice != 0 {
+			add("nice", "%d", p.Nice)
+		}
+		if p.IOClass != "" && p.IOLevel != nil {
+			add("io", "%s, level %d", p.IOClass, *p.IOLevel)
+		} else if p.IOClass != "" {
+			add("io", "%s", p.IOClass)
+		}
+	}
+	if r := srv.ResultLimit; r != nil {
+		if r.Spill {
+			add("results", "%d bytes, full results kept on disk", r.MaxSize)
+		} else {
+			add("results", "%d bytes", r.MaxSize)
+		}
+	}
+	for _, middleware := range srv.Middleware {
+		if middleware.Name != "rateLimit" {
+			continue
+		}
+		if burst, ok := middleware.Config["burst"]; ok {
+			add("rate limit", "%v requests/s, burst %v", middleware.Config["requestsPerSecond"], burst)
+		} else {
+			add("rate limit", "%v requests/s", middleware.Config["requestsPerSecond"])
+		}
+	}
+	if r := srv.ReadOnly; r != nil {
+		add("read-only", "allow [%s], deny [%s]", strings.Join(r.Allow, ", "), strings.Join(r.Deny, ", "))
+	}
+	if a := srv.Approval; a != nil {
+		add("approval", "%s, rejected after %s", strings.Join(a.Tools, ", "), a.Timeout)
+	}
+	if b := srv.CircuitBreaker; b != nil {
+		add("circuit breaker", "opens after %d failures for %s", b.Failures, b.Cooldown)
+	}
+	return limits
+}
@@ -0,0 +1,60 @@
+package manager
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	mcpgrpc "github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/mcpmock"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+func TestManager_DescribeServer(t *testing.T) {
+	manager := createTestManager(t)
+	manager.proxyEnv = []string{"HTTPS_PROXY=http://proxy:3128"}
+
+	srv := server.NewServer("github", "npx server-github", 8119, "GitHub")
+	srv.Env = map[string]string{"GITHUB_TOKEN": "secret:github/token", "LOG_LEVEL": "debug"}
+	srv.Priority = &server.Priority{Nice: 10}
+	srv.ResultLimit = &server.ResultLimit{MaxSize: 1024, Spill: true}
+	srv.Middleware = []server.MiddlewareConfig{{Name: "rateLimit", Config: map[string]interface{}{"requestsPerSecond": 2}}}
+	srv.CircuitBreaker = &server.CircuitBreaker{Failures: 5, Cooldown: 30 * time.Second}
+	manager.servers["github"] = srv
+
+	runtime, err := manager.DescribeServer("github")
+	require.NoError(t, err)
+	assert.Equal(t, "exec nice -n 10 sh -c 'npx server-github'", runtime.Command)
+	assert.False(t, runtime.Running)
+	assert.Equal(t, []mcpgrpc.RuntimeVar{
+		{Name: "GITHUB_TOKEN", Source: mcpgrpc.VarSecret},
+		{Name: "HTTPS_PROXY", Source: mcpgrpc.VarProxy},
+		{Name: "LOG_LEVEL", Source: mcpgrpc.VarConfig},
+	}, runtime.Env)
+	assert.Equal(t, []mcpgrpc.RuntimeLimit{
+		{Name: "nice", Value: "10"},
+		{Name: "results", Value: "1024 bytes, full results kept on disk"},
+		{Name: "rate limit", Value: "2 requests/s"},
+		{Name: "circuit breaker", Value: "opens after 5 failures for 30s"},
+	}, runtime.Limits)
+	assert.Equal(t, "no secrets store", runtime.Error, "the secret can't be read")
+	cwd, _ := os.Getwd()
+	assert.Equal(t, cwd, runtime.Cwd)
+	assert.NotEmpty(t, runtime.Path)
+
+	// Running servers report the command they were started with
+	mock := server.NewServer("mock", mcpmock.Command("-tools", ""), 8120, "Mock server")
+	manager.servers["mock"] = mock
+	require.NoError(t, manager.StartServer("mock"))
+	defer manager.StopServer("mock")
+	runtime, err = manager.DescribeServer("mock")
+	require.NoError(t, err)
+	assert.True(t, runtime.Running)
+	assert.Equal(t, mock.Command, runtime.Command)
+	assert.Empty(t, runtime.Error)
+
+	_, err = manager.DescribeServer("missing")
+	assert.ErrorIs(t, err, server.ErrNotFound)
+}
@@ -0,0 +1,241 @@
+package manager
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	mcpgrpc "github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// dataEnvSuffixes are the suffixes of env variables pointing servers at
+// where they keep data, e.g. MEMORY_FILE_PATH
+var dataEnvSuffixes = []string{"_PATH", "_FILE", "_DIR", "_HOME"}
+
+// npxCacheDir returns the directory npx installs packages in for a server
+func npxCacheDir(srv *server.Server) string {
+	cache := srv.Env["npm_config_cache"]
+	if cache == "" {
+		cache = os.Getenv("npm_config_cache")
+	}
+	if cache == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		cache = filepath.Join(home, ".npm")
+	}
+	return filepath.Join(cache, "_npx")
+}
+
+// npxPackage returns the package a command runs with npx, without its
+// version, or "" if it doesn't
+func npxPackage(command string) string {
+	fields := strings.Fields(command)
+	for i, field := range fields {
+		if filepath.Base(field) != "npx" {
+			continue
+		}
+		for j := i + 1; j < len(fields); j++ {
+			arg := fields[j]
+			switch {
+			case (arg == "-p" || arg == "--package") && j+1 < len(fields):
+				return trimVersion(fields[j+1])
+			case strings.HasPrefix(arg, "--package="):
+				return trimVersion(strings.TrimPrefix(arg, "--package="))
+			case !strings.HasPrefix(arg, "-"):
+				return trimVersion(arg)
+			}
+		}
+	}
+	return ""
+}
+
+// trimVersion returns a package spec without its version, keeping the @
+// of scoped packages
+func trimVersion(spec string) string {
+	if i := strings.LastIndex(spec, "@"); i > 0 {
+		return spec[:i]
+	}
+	return spec
+}
+
+// npxCaches returns the directories npx installed pkg in, one per version
+// it was run with
+func npxCaches(dir, pkg string) []string {
+	entries, _ := os.ReadDir(dir)
+
+	var caches []string
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name(), "package.json"))
+		if err != nil {
+			continue
+		}
+		var manifest struct {
+			Dependencies map[string]string `json:"dependencies"`
+		}
+		if json.Unmarshal(data, &manifest) == nil && manifest.Dependencies[pkg] != "" {
+			caches = append(caches, filepath.Join(dir, entry.Name()))
+		}
+	}
+	return caches
+}
+
+// serverDirs returns the directories, and files, a server keeps caches and
+// data in, without their sizes. Paths inside others are left out, so
+// nothing is counted twice.
+func (m *Manager) serverDirs(srv *server.Server) []mcpgrpc.DiskDir {
+	var dirs []mcpgrpc.DiskDir
+	add := func(kind, label, path string) {
+		if _, err := os.Stat(path); err == nil {
+			dirs = append(dirs, mcpgrpc.DiskDir{Kind: kind, Label: label, Path: path})
+		}
+	}
+
+	for _, command := range []string{srv.Command, srv.ShadowCommand} {
+		if pkg := npxPackage(command); pkg != "" {
+			for _, dir := range npxCaches(npxCacheDir(srv), pkg) {
+				add(mcpgrpc.DiskCache, "npx", dir)
+			}
+		}
+	}
+	add(mcpgrpc.DiskCache, "results", filepath.Join(m.config.GetCacheDir(), "results", srv.Name))
+	add(mcpgrpc.DiskCache, "resolver", m.resolverDir(srv.Name))
+	add(mcpgrpc.DiskData, "profiles", m.profilesDir(srv))
+	add(mcpgrpc.DiskData, "backups", m.backupsDir(srv.Name))
+	if paths, err := dataPaths(srv); err == nil {
+		for _, path := range paths {
+			add(mcpgrpc.DiskData, "dataPaths", path)
+		}
+	}
+
+	// Stores the server is pointed at, except for shared directories
+	home, _ := os.UserHomeDir()
+	shared := []string{home, m.config.ConfigDir, m.config.GetStateDir(), m.config.GetCacheDir()}
+	for _, key := range slices.Sorted(maps.Keys(srv.Env)) {
+		value := filepath.Clean(srv.Env[key])
+		if !filepath.IsAbs(value) || !slices.ContainsFunc(dataEnvSuffixes, func(suffix string) bool { return strings.HasSuffix(key, suffix) }) {
+			continue
+		}
+		if !slices.ContainsFunc(shared, func(dir string) bool { return dir != "" && within(dir, value) }) {
+			add(mcpgrpc.DiskData, key, value)
+		}
+	}
+
+	sort.SliceStable(dirs, func(i, j int) bool { return dirs[i].Path < dirs[j].Path })
+	kept := dirs[:0]
+	for _, dir := range dirs {
+		if !slices.ContainsFunc(kept, func(k mcpgrpc.DiskDir) bool { return within(dir.Path, k.Path) }) {
+			kept = append(kept, dir)
+		}
+	}
+	return kept
+}
+
+// within returns true if path is dir or inside it
+func within(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && filepath.IsLocal(rel)
+}
+
+// diskServers returns copies of the named servers, all of them in order
+// when none are
+func (m *Manager) diskServers(names []string) ([]server.Server, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if len(names) == 0 {
+		names = append(slices.Clone(m.serverOrder), m.fleetOrder()...)
+	}
+	servers := make([]server.Server, 0, len(names))
+	for _, name := range names {
+		srv, exists := m.servers[name]
+		if !exists {
+			return nil, fmt.Errorf("server '%s' %w", name, server.ErrNotFound)
+		}
+		servers = append(servers, *srv)
+	}
+	return servers, nil
+}
+
+// DiskUsage returns the caches and data of servers, all of them when none
+// are named, with the bytes they take
+func (m *Manager) DiskUsage(names []string) ([]mcpgrpc.ServerDisk, error) {
+	servers, err := m.diskServers(names)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := make([]mcpgrpc.ServerDisk, len(servers))
+	for i := range servers {
+		usage[i] = mcpgrpc.ServerDisk{Server: servers[i].Name, Dirs: m.serverDirs(&servers[i])}
+		for j := range usage[i].Dirs {
+			usage[i].Dirs[j].Size, _ = dirUsage(usage[i].Dirs[j].Path)
+		}
+	}
+	return usage, nil
+}
+
+// CleanCaches deletes the caches of stopped servers, all of them when none
+// are named, and returns what was deleted, or only what would be if dryRun
+// is set. Named servers must be stopped, and caches running servers share,
+// such as npx installs of the same package, are kept.
+func (m *Manager) CleanCaches(names []string, dryRun bool) ([]mcpgrpc.ServerDisk, error) {
+	servers, err := m.diskServers(names)
+	if err != nil {
+		return nil, err
+	}
+	all, err := m.diskServers(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	inUse := make(map[string]bool)
+	for i := range all {
+		if all[i].IsRunning() {
+			for _, dir := range m.serverDirs(&all[i]) {
+				inUse[dir.Path] = true
+			}
+		}
+	}
+
+	var cleaned []mcpgrpc.ServerDisk
+	var errs []error
+	for i := range servers {
+		srv := &servers[i]
+		if srv.IsRunning() {
+			if len(names) > 0 {
+				return nil, fmt.Errorf("server '%s' is %w, stop it before cleaning its caches", srv.Name, server.ErrAlreadyRunning)
+			}
+			continue
+		}
+
+		disk := mcpgrpc.ServerDisk{Server: srv.Name}
+		for _, dir := range m.serverDirs(srv) {
+			if dir.Kind != mcpgrpc.DiskCache || inUse[dir.Path] {
+				continue
+			}
+			dir.Size, _ = dirUsage(dir.Path)
+			if !dryRun {
+				if err := os.RemoveAll(dir.Path); err != nil {
+					errs = append(errs, fmt.Errorf("failed to clean %s cache of '%s': %w", dir.Label, srv.Name, err))
+					continue
+				}
+				log.Printf("Cleaned %s cache of %s: %s", dir.Label, srv.Name, dir.Path)
+			}
+			disk.Dirs = append(disk.Dirs, dir)
+		}
+		if len(disk.Dirs) > 0 {
+			cleaned = append(cleaned, disk)
+		}
+	}
+	return cleaned, errors.Join(errs...)
+}
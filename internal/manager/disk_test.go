@@ -0,0 +1,119 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mcpgrpc "github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+func TestNpxPackage(t *testing.T) {
+	tests := map[string]string{
+		"npx -y @playwright/mcp@latest --headless":      "@playwright/mcp",
+		"npx --yes @modelcontextprotocol/server-memory": "@modelcontextprotocol/server-memory",
+		"npx -p mcp-remote@1.2.0 mcp-remote https://x":  "mcp-remote",
+		"npx --package=tsx tsx server.ts":               "tsx",
+		"/usr/local/bin/npx mcp-server-fetch":           "mcp-server-fetch",
+		"uvx mcp-server-fetch":                          "",
+		"npx":                                           "",
+	}
+	for command, pkg := range tests {
+		assert.Equal(t, pkg, npxPackage(command), command)
+	}
+}
+
+// writeNpxCache writes an npx install of pkg into the npm cache
+func writeNpxCache(t *testing.T, cache, hash, pkg string, size int) string {
+	t.Helper()
+	dir := filepath.Join(cache, "_npx", hash)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "node_modules"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"dependencies": {"`+pkg+`": "^1.0.0"}}`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "node_modules", "index.js"), make([]byte, size), 0600))
+	return dir
+}
+
+func TestManager_DiskUsage(t *testing.T) {
+	m := createTestManager(t)
+	cache := t.TempDir()
+	t.Setenv("npm_config_cache", cache)
+	browserCache := writeNpxCache(t, cache, "a1", "@playwright/mcp", 100)
+	writeNpxCache(t, cache, "b2", "other", 100)
+
+	store := filepath.Join(t.TempDir(), "memory.json")
+	require.NoError(t, os.WriteFile(store, make([]byte, 10), 0600))
+
+	srv := m.servers["test1"]
+	srv.Command = "npx -y @playwright/mcp@latest"
+	srv.BrowserProfile = "work"
+	srv.Env = map[string]string{"MEMORY_FILE_PATH": store, "CONFIG_HOME": m.config.ConfigDir, "MODE": "/tmp"}
+	profile := filepath.Join(m.profilesDir(srv), "work")
+	require.NoError(t, os.MkdirAll(profile, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(profile, "Cookies"), make([]byte, 20), 0600))
+
+	usage, err := m.DiskUsage([]string{"test1"})
+	require.NoError(t, err)
+	require.Len(t, usage, 1)
+	assert.ElementsMatch(t, []mcpgrpc.DiskDir{
+		{Kind: mcpgrpc.DiskCache, Label: "npx", Path: browserCache, Size: 100 + int64(len(`{"dependencies": {"@playwright/mcp": "^1.0.0"}}`))},
+		{Kind: mcpgrpc.DiskData, Label: "profiles", Path: m.profilesDir(srv), Size: 20},
+		{Kind: mcpgrpc.DiskData, Label: "MEMORY_FILE_PATH", Path: store, Size: 10},
+	}, usage[0].Dirs, "shared directories and values other than paths are left out")
+	assert.Equal(t, int64(30), usage[0].Total(mcpgrpc.DiskData))
+
+	m.serverOrder = []string{"test1", "test2"}
+	usage, err = m.DiskUsage(nil)
+	require.NoError(t, err)
+	assert.Len(t, usage, 2)
+
+	_, err = m.DiskUsage([]string{"missing"})
+	assert.ErrorIs(t, err, server.ErrNotFound)
+}
+
+func TestManager_CleanCaches(t *testing.T) {
+	m := createTestManager(t)
+	m.serverOrder = []string{"test1", "test2"}
+	cache := t.TempDir()
+	t.Setenv("npm_config_cache", cache)
+	shared := writeNpxCache(t, cache, "a1", "@playwright/mcp", 100)
+	own := writeNpxCache(t, cache, "b2", "mcp-server-fetch", 100)
+
+	stopped := m.servers["test1"]
+	stopped.Command = "npx -y @playwright/mcp@latest"
+	stopped.ShadowCommand = "npx mcp-server-fetch"
+	results := filepath.Join(m.config.GetCacheDir(), "results", "test1")
+	require.NoError(t, os.MkdirAll(results, 0700))
+	profile := filepath.Join(m.profilesDir(stopped), "work")
+	require.NoError(t, os.MkdirAll(profile, 0700))
+
+	running := m.servers["test2"]
+	running.Command = "npx @playwright/mcp"
+	running.Status = server.StatusRunning
+
+	// A dry run deletes nothing
+	cleaned, err := m.CleanCaches(nil, true)
+	require.NoError(t, err)
+	require.Len(t, cleaned, 1)
+	assert.Equal(t, "test1", cleaned[0].Server)
+	var paths []string
+	for _, dir := range cleaned[0].Dirs {
+		paths = append(paths, dir.Path)
+	}
+	assert.ElementsMatch(t, []string{own, results}, paths, "caches of running servers and data are kept")
+	assert.DirExists(t, own)
+
+	cleaned, err = m.CleanCaches(nil, false)
+	require.NoError(t, err)
+	require.Len(t, cleaned, 1)
+	assert.NoDirExists(t, own)
+	assert.NoDirExists(t, results)
+	assert.DirExists(t, shared)
+	assert.DirExists(t, profile)
+
+	_, err = m.CleanCaches([]string{"test2"}, true)
+	assert.ErrorIs(t, err, server.ErrAlreadyRunning)
+}
@@ -0,0 +1,61 @@
+package manager
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// DefaultDrainTimeout bounds the wait for calls in flight when draining a
+// server without a timeout
+const DefaultDrainTimeout = 30 * time.Second
+
+// drainPoll is how often a draining server's proxy is checked for calls in
+// flight, replaceable for tests
+var drainPoll = 100 * time.Millisecond
+
+// DrainServer marks a server as draining, so the gateway routes no new
+// calls to it, waits up to timeout for the calls in flight on its proxy to
+// finish and then stops it. Calls still in flight after timeout are cut
+// off by the stop.
+func (m *Manager) DrainServer(name string, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = DefaultDrainTimeout
+	}
+
+	m.mu.Lock()
+	srv, exists := m.servers[name]
+	if !exists {
+		m.mu.Unlock()
+		return fmt.Errorf("server '%s' %w", name, server.ErrNotFound)
+	}
+	if !srv.IsRunning() {
+		m.mu.Unlock()
+		return fmt.Errorf("server '%s' is %w", name, server.ErrNotRunning)
+	}
+	srv.Draining = true
+	proxyServer := m.proxies[name]
+	m.mu.Unlock()
+	log.Printf("Draining %s", name)
+
+	// Check after a poll at the earliest, so calls the gateway routed just
+	// before the server was marked reach its proxy first
+	deadline := time.Now().Add(timeout)
+	ticker := time.NewTicker(drainPoll)
+	defer ticker.Stop()
+	for proxyServer != nil {
+		<-ticker.C
+		inFlight := proxyServer.InFlight()
+		if inFlight == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			log.Printf("Warning: %s still had %d calls in flight after %v, stopping anyway", name, inFlight, timeout)
+			break
+		}
+	}
+
+	return m.StopServer(name)
+}
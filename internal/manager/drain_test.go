@@ -0,0 +1,31 @@
+package manager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/internal/proxy"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+func TestManager_DrainServer(t *testing.T) {
+	drainPoll = time.Millisecond
+	t.Cleanup(func() { drainPoll = 100 * time.Millisecond })
+
+	m := createTestManager(t)
+	assert.ErrorIs(t, m.DrainServer("missing", 0), server.ErrNotFound)
+	assert.ErrorIs(t, m.DrainServer("test1", 0), server.ErrNotRunning)
+
+	// An idle proxy stops as soon as the server is marked
+	m.servers["test1"].SetStatus(server.StatusRunning)
+	m.proxies["test1"] = proxy.New(4001, "echo test1")
+	require.NoError(t, m.DrainServer("test1", time.Second))
+
+	srv, err := m.GetServer("test1")
+	require.NoError(t, err)
+	assert.Equal(t, server.StatusStopped, srv.Status)
+	assert.False(t, srv.Draining)
+	assert.NotContains(t, m.proxies, "test1")
+}
@@ -0,0 +1,283 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+	mcpgrpc "github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// Variables set for the servers of a fleet, also expanded in the values of
+// their env as ${MCP_RUN_ID} and ${MCP_RUN_DIR}
+const (
+	RunIDEnv  = "MCP_RUN_ID"
+	RunDirEnv = "MCP_RUN_DIR"
+)
+
+// runIDPattern restricts run IDs to what's safe in server names and paths
+var runIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// fleet is an isolated copy of servers for an evaluation run
+type fleet struct {
+	dir     string
+	created time.Time
+	servers []string // Names of the copies in config order
+}
+
+// FleetServerName returns the name of a server's copy in a run's fleet
+func FleetServerName(name, runID string) string {
+	return name + "@" + runID
+}
+
+// fleetsDir returns the directory holding the state of the fleets
+func (m *Manager) fleetsDir() string {
+	return filepath.Join(m.config.GetStateDir(), "fleets")
+}
+
+// CreateFleet copies servers, all configured ones when none are given,
+// into a fleet for an evaluation run. Each copy gets its own port and state
+// directory, so parallel runs don't share state. The copies are started if
+// start is set, and the fleet is destroyed again if any fails to start.
+func (m *Manager) CreateFleet(runID string, names []string, start bool) (*mcpgrpc.Fleet, error) {
+	if !runIDPattern.MatchString(runID) {
+		return nil, fmt.Errorf("invalid run ID '%s', use letters, digits, '.', '_' and '-'", runID)
+	}
+
+	mcpConfig, err := loadMCPConfig(m.config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load MCP config: %w", err)
+	}
+	if len(names) == 0 {
+		names = mcpConfig.ServerOrder
+	}
+	for _, name := range names {
+		if _, exists := mcpConfig.Servers[name]; !exists {
+			return nil, fmt.Errorf("server '%s' %w", name, server.ErrNotFound)
+		}
+	}
+	if len(names) == 0 {
+		return nil, errors.New("no servers to copy")
+	}
+
+	m.mu.Lock()
+	if _, exists := m.fleets[runID]; exists {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("fleet '%s' %w", runID, server.ErrExists)
+	}
+
+	f := &fleet{dir: filepath.Join(m.fleetsDir(), runID), created: time.Now()}
+	copies := make(map[string]*server.Server, len(names))
+	for _, name := range names {
+		srv, err := m.fleetServer(runID, f.dir, name, mcpConfig.Servers[name])
+		if err != nil {
+			m.mu.Unlock()
+			os.RemoveAll(f.dir)
+			return nil, err
+		}
+		copies[srv.Name] = srv
+		f.servers = append(f.servers, srv.Name)
+	}
+	for _, name := range f.servers {
+		if _, exists := m.servers[name]; exists {
+			m.mu.Unlock()
+			os.RemoveAll(f.dir)
+			return nil, fmt.Errorf("server '%s' %w", name, server.ErrExists)
+		}
+	}
+	maps.Copy(m.servers, copies)
+	if m.fleets == nil {
+		m.fleets = make(map[string]*fleet)
+	}
+	m.fleets[runID] = f
+	m.mu.Unlock()
+	log.Printf("Created fleet %s: %s", runID, strings.Join(f.servers, ", "))
+
+	if start {
+		for _, name := range f.servers {
+			if err := m.StartServer(name); err != nil {
+				if destroyErr := m.DestroyFleet(runID); destroyErr != nil {
+					log.Printf("Warning: failed to destroy fleet %s: %v", runID, destroyErr)
+				}
+				return nil, err
+			}
+		}
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.fleetInfo(runID, f), nil
+}
+
+// fleetServer creates the copy of a server for a run, with a free port and
+// a state directory. Must be called with m.mu held.
+func (m *Manager) fleetServer(runID, runDir, name string, cfg *config.MCPServerConfig) (*server.Server, error) {
+	dir := filepath.Join(runDir, name)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create state directory for '%s': %w", name, err)
+	}
+	port, err := m.freePort()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find a port for '%s': %w", name, err)
+	}
+
+	copyCfg := *cfg
+	copyCfg.Port = port
+	copyCfg.Autostart = false
+	copyCfg.Env = fleetEnv(cfg.Env, runID, dir)
+
+	srv := newServerFromConfig(FleetServerName(name, runID), &copyCfg)
+	srv.RunID = runID
+	if cfg.Group != "" {
+		// Instances of the copy are served apart from the originals
+		srv.Group = FleetServerName(cfg.Group, runID)
+	}
+	return srv, nil
+}
+
+// fleetEnv returns the env of a server's copy: its own with the run's
+// variables expanded, pointing temporary files and XDG data and state at
+// the copy's state directory
+func fleetEnv(env map[string]string, runID, dir string) map[string]string {
+	expand := strings.NewReplacer("${"+RunIDEnv+"}", runID, "${"+RunDirEnv+"}", dir)
+	vars := map[string]string{
+		RunIDEnv:         runID,
+		RunDirEnv:        dir,
+		"TMPDIR":         dir,
+		"XDG_DATA_HOME":  filepath.Join(dir, "data"),
+		"XDG_STATE_HOME": filepath.Join(dir, "state"),
+	}
+	for key, value := range env {
+		vars[key] = expand.Replace(value)
+	}
+	return vars
+}
+
+// freePort returns a port free on the bind address and not assigned to
+// any server. Must be called with m.mu held.
+func (m *Manager) freePort() (int, error) {
+	used := make(map[int]bool, len(m.servers))
+	for _, srv := range m.servers {
+		used[srv.Port] = true
+	}
+	for i := 0; i < 10; i++ {
+		listener, err := net.Listen("tcp", net.JoinHostPort(m.bindAddress, "0"))
+		if err != nil {
+			return 0, err
+		}
+		port := listener.Addr().(*net.TCPAddr).Port
+		listener.Close()
+		if !used[port] {
+			return port, nil
+		}
+	}
+	return 0, errors.New("no free port")
+}
+
+// DestroyFleet stops the servers of a run's fleet and removes them with
+// their state directories
+func (m *Manager) DestroyFleet(runID string) error {
+	m.mu.RLock()
+	f, exists := m.fleets[runID]
+	m.mu.RUnlock()
+	if !exists {
+		return fmt.Errorf("fleet '%s' %w", runID, server.ErrNotFound)
+	}
+
+	for _, name := range f.servers {
+		if err := m.StopServer(name); err != nil && !errors.Is(err, server.ErrNotRunning) && !errors.Is(err, server.ErrNotFound) {
+			return fmt.Errorf("failed to stop '%s': %w", name, err)
+		}
+	}
+
+	m.mu.Lock()
+	for _, name := range f.servers {
+		m.cancelRestart(name)
+		delete(m.servers, name)
+		m.dropLogs(name)
+		if err := m.config.RemovePID(name); err != nil {
+			log.Printf("Warning: failed to remove PID of %s: %v", name, err)
+		}
+	}
+	delete(m.fleets, runID)
+	m.mu.Unlock()
+
+	if err := os.RemoveAll(f.dir); err != nil {
+		return fmt.Errorf("failed to remove state of fleet '%s': %w", runID, err)
+	}
+	log.Printf("Destroyed fleet %s", runID)
+	return nil
+}
+
+// Fleets returns the fleets of the runs in progress, sorted by run ID
+func (m *Manager) Fleets() []mcpgrpc.Fleet {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	fleets := make([]mcpgrpc.Fleet, 0, len(m.fleets))
+	for _, runID := range m.fleetRuns() {
+		fleets = append(fleets, *m.fleetInfo(runID, m.fleets[runID]))
+	}
+	return fleets
+}
+
+// fleetInfo returns a fleet with copies of its servers. Must be called
+// with m.mu held.
+func (m *Manager) fleetInfo(runID string, f *fleet) *mcpgrpc.Fleet {
+	info := &mcpgrpc.Fleet{RunID: runID, Dir: f.dir, Created: f.created}
+	for _, name := range f.servers {
+		if srv, exists := m.servers[name]; exists {
+			srvCopy := *srv
+			info.Servers = append(info.Servers, &srvCopy)
+		}
+	}
+	return info
+}
+
+// fleetRuns returns the run IDs of the fleets in order. Must be called
+// with m.mu held.
+func (m *Manager) fleetRuns() []string {
+	runs := make([]string, 0, len(m.fleets))
+	for runID := range m.fleets {
+		runs = append(runs, runID)
+	}
+	sort.Strings(runs)
+	return runs
+}
+
+// fleetOrder returns the servers of the fleets, ordered by run and then
+// as in the config. Must be called with m.mu held.
+func (m *Manager) fleetOrder() []string {
+	var order []string
+	for _, runID := range m.fleetRuns() {
+		order = append(order, m.fleets[runID].servers...)
+	}
+	return order
+}
+
+// removeStaleFleets removes the state left by fleets of a previous daemon,
+// which aren't restored
+func (m *Manager) removeStaleFleets() {
+	entries, err := os.ReadDir(m.fleetsDir())
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if err := os.RemoveAll(filepath.Join(m.fleetsDir(), entry.Name())); err != nil {
+			log.Printf("Warning: failed to remove stale fleet %s: %v", entry.Name(), err)
+		}
+	}
+	if len(entries) > 0 {
+		log.Printf("Removed %d stale fleets", len(entries))
+	}
+}
@@ -0,0 +1,108 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// writeFleetConfig writes an mcp.json with two servers to copy
+func writeFleetConfig(t *testing.T, m *Manager) {
+	t.Helper()
+	data := `{"servers": {
+  "memory": {"command": "memory-server", "port": 4001, "autostart": true, "env": {"MEMORY_FILE_PATH": "${MCP_RUN_DIR}/memory.json", "TOKEN": "x"}},
+  "browser": {"command": "browser-server", "port": 4002}
+}}`
+	require.NoError(t, os.WriteFile(m.config.GetMCPConfigPath(), []byte(data), 0644))
+}
+
+func TestManager_CreateFleet(t *testing.T) {
+	m := createTestManager(t)
+	writeFleetConfig(t, m)
+
+	fleet, err := m.CreateFleet("run-1", nil, false)
+	require.NoError(t, err)
+	assert.Equal(t, "run-1", fleet.RunID)
+	assert.Equal(t, filepath.Join(m.config.GetStateDir(), "fleets", "run-1"), fleet.Dir)
+	require.Len(t, fleet.Servers, 2)
+	assert.Equal(t, "memory@run-1", fleet.Servers[0].Name, "copies keep the config order")
+	assert.Equal(t, "browser@run-1", fleet.Servers[1].Name)
+
+	memory, err := m.GetServer("memory@run-1")
+	require.NoError(t, err)
+	dir := filepath.Join(fleet.Dir, "memory")
+	assert.DirExists(t, dir)
+	assert.Equal(t, "run-1", memory.RunID)
+	assert.Equal(t, "memory-server", memory.Command)
+	assert.False(t, memory.Autostart)
+	assert.NotEqual(t, 4001, memory.Port)
+	assert.NotEqual(t, memory.Port, fleet.Servers[1].Port, "copies get their own ports")
+	assert.Equal(t, filepath.Join(dir, "memory.json"), memory.Env["MEMORY_FILE_PATH"])
+	assert.Equal(t, "x", memory.Env["TOKEN"])
+	assert.Equal(t, "run-1", memory.Env[RunIDEnv])
+	assert.Equal(t, dir, memory.Env["TMPDIR"])
+
+	// A second run gets separate copies
+	other, err := m.CreateFleet("run-2", []string{"memory"}, false)
+	require.NoError(t, err)
+	require.Len(t, other.Servers, 1)
+	assert.NotEqual(t, memory.Port, other.Servers[0].Port)
+
+	order, err := m.GetServerOrder()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"memory@run-1", "browser@run-1", "memory@run-2"}, order[len(order)-3:])
+
+	fleets := m.Fleets()
+	require.Len(t, fleets, 2)
+	assert.Equal(t, "run-1", fleets[0].RunID)
+	assert.Equal(t, "run-2", fleets[1].RunID)
+
+	// Fleets survive config reloads
+	require.NoError(t, m.reloadConfig())
+	_, err = m.GetServer("browser@run-1")
+	assert.NoError(t, err)
+}
+
+func TestManager_CreateFleet_Errors(t *testing.T) {
+	m := createTestManager(t)
+	writeFleetConfig(t, m)
+
+	_, err := m.CreateFleet("../run", nil, false)
+	assert.ErrorContains(t, err, "invalid run ID")
+	_, err = m.CreateFleet("run", []string{"missing"}, false)
+	assert.ErrorIs(t, err, server.ErrNotFound)
+
+	_, err = m.CreateFleet("run", []string{"memory"}, false)
+	require.NoError(t, err)
+	_, err = m.CreateFleet("run", []string{"browser"}, false)
+	assert.ErrorIs(t, err, server.ErrExists)
+}
+
+func TestManager_DestroyFleet(t *testing.T) {
+	m := createTestManager(t)
+	writeFleetConfig(t, m)
+
+	fleet, err := m.CreateFleet("run-1", nil, false)
+	require.NoError(t, err)
+	require.NoError(t, m.DestroyFleet("run-1"))
+
+	assert.NoDirExists(t, fleet.Dir)
+	assert.Empty(t, m.Fleets())
+	_, err = m.GetServer("memory@run-1")
+	assert.ErrorIs(t, err, server.ErrNotFound)
+	assert.ErrorIs(t, m.DestroyFleet("run-1"), server.ErrNotFound)
+}
+
+func TestManager_RemoveStaleFleets(t *testing.T) {
+	m := createTestManager(t)
+	stale := filepath.Join(m.fleetsDir(), "run-1", "memory")
+	require.NoError(t, os.MkdirAll(stale, 0700))
+
+	m.removeStaleFleets()
+	assert.NoDirExists(t, filepath.Join(m.fleetsDir(), "run-1"))
+}
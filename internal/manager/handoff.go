@@ -0,0 +1,227 @@
+package manager
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/logs"
+)
+
+// outputDrainTime is how long the output of a server process that exited
+// is still copied, as children it left behind may hold its pipes open
+const outputDrainTime = time.Second
+
+// pipes are the manager's ends of the standard streams of a server
+// process: its input, held open so stdio servers don't exit on EOF, and
+// its output, copied into the server's logs. The binary replacing the
+// daemon on upgrades inherits them, so the servers keep running.
+type pipes struct {
+	stdin  *os.File
+	stdout *os.File
+	stderr *os.File
+	copied sync.WaitGroup // Copies of the output in progress
+}
+
+// openPipes creates the pipes of a server process, returning the
+// manager's ends and the process's
+func openPipes() (*pipes, []*os.File, error) {
+	ours := &pipes{}
+	var theirs []*os.File
+	for i, end := range []**os.File{&ours.stdin, &ours.stdout, &ours.stderr} {
+		r, w, err := os.Pipe()
+		if err != nil {
+			ours.close()
+			closeFiles(theirs)
+			return nil, nil, fmt.Errorf("failed to create pipe: %w", err)
+		}
+		if i == 0 {
+			*end = w
+			theirs = append(theirs, r)
+		} else {
+			*end = r
+			theirs = append(theirs, w)
+		}
+	}
+	return ours, theirs, nil
+}
+
+// files returns the pipes in the order of stdin, stdout and stderr
+func (p *pipes) files() []*os.File {
+	return []*os.File{p.stdin, p.stdout, p.stderr}
+}
+
+// copyOutput copies what the process writes into output
+func (p *pipes) copyOutput(output *logs.Buffer) {
+	for stream, file := range map[string]*os.File{"stdout": p.stdout, "stderr": p.stderr} {
+		p.copied.Add(1)
+		go func() {
+			defer p.copied.Done()
+			io.Copy(output.Writer(stream), file)
+		}()
+	}
+}
+
+// release closes the pipes of a process that exited, once the output it
+// wrote was copied
+func (p *pipes) release() {
+	p.stdin.Close()
+	done := make(chan struct{})
+	go func() {
+		p.copied.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(outputDrainTime):
+	}
+	p.close()
+}
+
+// close closes the pipes
+func (p *pipes) close() {
+	closeFiles(p.files())
+}
+
+// closeFiles closes the files that were opened
+func closeFiles(files []*os.File) {
+	for _, file := range files {
+		if file != nil {
+			file.Close()
+		}
+	}
+}
+
+// keepPipes remembers the pipes of the server process with pid
+func (m *Manager) keepPipes(pid int, p *pipes) {
+	m.pipesMu.Lock()
+	defer m.pipesMu.Unlock()
+
+	if m.pipes == nil {
+		m.pipes = make(map[int]*pipes)
+	}
+	m.pipes[pid] = p
+}
+
+// releasePipes closes the pipes of the server process with pid, which
+// exited or was stopped
+func (m *Manager) releasePipes(pid int) {
+	m.pipesMu.Lock()
+	p, exists := m.pipes[pid]
+	delete(m.pipes, pid)
+	m.pipesMu.Unlock()
+
+	if exists {
+		p.release()
+	}
+}
+
+// Handoff gives up the pipes of the running server processes, returning
+// their PIDs and the pipes, three files each, for the binary replacing the
+// daemon to inherit. Their output is no longer copied here, and their
+// proxies and health checks are stopped, to be started again by the new
+// binary as it adopts the processes from state.json.
+func (m *Manager) Handoff() ([]int, []*os.File) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pipesMu.Lock()
+	defer m.pipesMu.Unlock()
+
+	var pids []int
+	var files []*os.File
+	for name, srv := range m.servers {
+		if !srv.IsRunning() {
+			continue
+		}
+		m.stopHealthMonitor(name, srv)
+		if proxyServer, exists := m.proxies[name]; exists {
+			if err := proxyServer.Stop(); err != nil {
+				log.Printf("Warning: failed to stop HTTP proxy for %s: %v", name, err)
+			}
+			delete(m.proxies, name)
+		}
+
+		p, exists := m.pipes[srv.PID]
+		if !exists {
+			continue
+		}
+		duplicates, err := duplicateFiles(p.files())
+		if err != nil {
+			log.Printf("Warning: failed to hand over the pipes of %s: %v", name, err)
+			continue
+		}
+		// Closing the pipes stops the copies of their output
+		delete(m.pipes, srv.PID)
+		p.close()
+		pids = append(pids, srv.PID)
+		files = append(files, duplicates...)
+	}
+	return pids, files
+}
+
+// duplicateFiles returns copies of files, which stay open when files are
+// closed
+func duplicateFiles(files []*os.File) ([]*os.File, error) {
+	var duplicates []*os.File
+	for _, file := range files {
+		conn, err := file.SyscallConn()
+		if err != nil {
+			closeFiles(duplicates)
+			return nil, err
+		}
+		var fd int
+		var dupErr error
+		err = conn.Control(func(original uintptr) {
+			// Children started meanwhile mustn't inherit the copy
+			syscall.ForkLock.RLock()
+			defer syscall.ForkLock.RUnlock()
+			if fd, dupErr = syscall.Dup(int(original)); dupErr == nil {
+				syscall.CloseOnExec(fd)
+			}
+		})
+		if err == nil {
+			err = dupErr
+		}
+		if err != nil {
+			closeFiles(duplicates)
+			return nil, err
+		}
+		duplicates = append(duplicates, os.NewFile(uintptr(fd), file.Name()))
+	}
+	return duplicates, nil
+}
+
+// Inherit takes over the pipes of the server processes the daemon returned
+// from Handoff before it was replaced, once they were adopted. Pipes of
+// processes that exited meanwhile are closed.
+func (m *Manager) Inherit(pids []int, files []*os.File) {
+	if len(files) != 3*len(pids) {
+		closeFiles(files)
+		return
+	}
+
+	// Held so the adopted servers aren't found exited meanwhile
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	adopted := make(map[int]string)
+	for name, srv := range m.servers {
+		if srv.IsRunning() {
+			adopted[srv.PID] = name
+		}
+	}
+	for i, pid := range pids {
+		p := &pipes{stdin: files[3*i], stdout: files[3*i+1], stderr: files[3*i+2]}
+		name, exists := adopted[pid]
+		if !exists {
+			p.close()
+			continue
+		}
+		p.copyOutput(m.logBuffer(name))
+		m.keepPipes(pid, p)
+	}
+}
@@ -0,0 +1,61 @@
+package manager
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+func TestManager_Handoff(t *testing.T) {
+	old := createTestManager(t)
+	p, err := old.startProcess("test1", "cat", nil)
+	require.NoError(t, err)
+	pid := p.cmd.Process.Pid
+	t.Cleanup(func() {
+		syscall.Kill(-pid, syscall.SIGKILL)
+		p.cmd.Wait()
+	})
+	old.servers["test1"].SetPID(pid)
+	old.servers["test1"].SetStatus(server.StatusRunning)
+
+	pids, files := old.Handoff()
+	require.Equal(t, []int{pid}, pids)
+	require.Len(t, files, 3)
+
+	// A server that exited meanwhile isn't adopted
+	exited, _, err := openPipes()
+	require.NoError(t, err)
+
+	next := createTestManager(t)
+	next.servers["test1"].SetPID(pid)
+	next.servers["test1"].SetStatus(server.StatusRunning)
+	next.Inherit([]int{pid, pid + 1}, append(files, exited.files()...))
+
+	_, err = exited.stdin.Write([]byte("lost\n"))
+	assert.ErrorIs(t, err, os.ErrClosed)
+
+	// The server still reads its input and its output reaches the logs
+	_, err = files[0].Write([]byte("hello\n"))
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		lines := next.logBuffer("test1").Tail(1)
+		return len(lines) == 1 && lines[0].Text == "hello"
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Empty(t, old.logBuffer("test1").Tail(1))
+}
+
+func TestProcessAlive_ReapsChildren(t *testing.T) {
+	// A server handed over by the daemon this process replaced, which
+	// started it
+	cmd := exec.Command("true")
+	require.NoError(t, cmd.Start())
+
+	assert.Eventually(t, func() bool { return !processAlive(cmd.Process.Pid) }, 5*time.Second, 10*time.Millisecond)
+	assert.False(t, processAlive(cmd.Process.Pid))
+}
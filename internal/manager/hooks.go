@@ -0,0 +1,150 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// Lifecycle hook events
+const (
+	HookPreStart  = "preStart"
+	HookPostStart = "postStart"
+	HookPreStop   = "preStop"
+	HookPostStop  = "postStop"
+	HookOnCrash   = "onCrash"
+)
+
+// defaultHookTimeout bounds hooks without a configured timeout
+const defaultHookTimeout = 30 * time.Second
+
+// parseHooks converts lifecycle hooks from mcp.json, applying defaults
+func parseHooks(cfg *config.HooksConfig) (*server.Hooks, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	hooks := &server.Hooks{
+		PreStart:  cfg.PreStart,
+		PostStart: cfg.PostStart,
+		PreStop:   cfg.PreStop,
+		PostStop:  cfg.PostStop,
+		OnCrash:   cfg.OnCrash,
+		Timeout:   defaultHookTimeout,
+	}
+
+	if cfg.Timeout != "" {
+		timeout, err := time.ParseDuration(cfg.Timeout)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("invalid hook timeout '%s'", cfg.Timeout)
+		}
+		hooks.Timeout = timeout
+	}
+
+	return hooks, nil
+}
+
+// parseHooksConfig returns the server's hooks, ignoring invalid ones
+func parseHooksConfig(name string, cfg *config.MCPServerConfig) *server.Hooks {
+	hooks, err := parseHooks(cfg.Hooks)
+	if err != nil {
+		log.Printf("Warning: ignoring hooks for %s: %v", name, err)
+		return nil
+	}
+	return hooks
+}
+
+// hookCommand returns the command configured for a hook event
+func hookCommand(hooks *server.Hooks, event string) string {
+	if hooks == nil {
+		return ""
+	}
+
+	switch event {
+	case HookPreStart:
+		return hooks.PreStart
+	case HookPostStart:
+		return hooks.PostStart
+	case HookPreStop:
+		return hooks.PreStop
+	case HookPostStop:
+		return hooks.PostStop
+	case HookOnCrash:
+		return hooks.OnCrash
+	}
+	return ""
+}
+
+// runHook runs a server's hook for event and waits for it to finish. The
+// hook gets the server's environment plus MCP_SERVER_NAME, MCP_SERVER_PORT,
+// MCP_HOOK and extra. Must be called without m.mu held.
+func (m *Manager) runHook(name, event string, extra ...string) error {
+	m.mu.RLock()
+	srv, exists := m.servers[name]
+	if !exists {
+		m.mu.RUnlock()
+		return nil
+	}
+	command := hookCommand(srv.Hooks, event)
+	if command == "" {
+		m.mu.RUnlock()
+		return nil
+	}
+	timeout := srv.Hooks.Timeout
+	env := m.serverEnv(srv)
+	port := srv.Port
+	m.mu.RUnlock()
+
+	if env == nil {
+		env = os.Environ()
+	}
+	env = append(env[:len(env):len(env)],
+		"MCP_SERVER_NAME="+name,
+		fmt.Sprintf("MCP_SERVER_PORT=%d", port),
+		"MCP_HOOK="+event,
+	)
+	env = append(env, extra...)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Env = env
+
+	// Kill the whole process group on timeout, so children of the shell
+	// don't keep the output pipe open
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = time.Second
+
+	out, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("timed out after %s", timeout)
+	}
+	if err != nil {
+		if output := strings.TrimSpace(string(out)); output != "" {
+			return fmt.Errorf("%w: %s", err, output)
+		}
+		return err
+	}
+	return nil
+}
+
+// runHookAsync runs a server's hook in the background, logging failures
+func (m *Manager) runHookAsync(name, event string, extra ...string) {
+	go func() {
+		if err := m.runHook(name, event, extra...); err != nil {
+			log.Printf("Warning: %s hook for %s failed: %v", event, name, err)
+		}
+	}()
+}
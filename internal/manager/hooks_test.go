@@ -0,0 +1,115 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/mcpmock"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+func TestParseHooks(t *testing.T) {
+	hooks, err := parseHooks(nil)
+	require.NoError(t, err)
+	assert.Nil(t, hooks)
+
+	hooks, err = parseHooks(&config.HooksConfig{PreStart: "make build"})
+	require.NoError(t, err)
+	assert.Equal(t, "make build", hookCommand(hooks, HookPreStart))
+	assert.Equal(t, "", hookCommand(hooks, HookPostStop))
+	assert.Equal(t, defaultHookTimeout, hooks.Timeout)
+
+	hooks, err = parseHooks(&config.HooksConfig{Timeout: "5s"})
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Second, hooks.Timeout)
+
+	_, err = parseHooks(&config.HooksConfig{Timeout: "soon"})
+	assert.Error(t, err)
+}
+
+func TestManager_RunHook(t *testing.T) {
+	manager := createTestManager(t)
+	out := filepath.Join(t.TempDir(), "hook.out")
+
+	srv := manager.servers["test1"]
+	srv.Env = map[string]string{"TOKEN": "secret"}
+	srv.Hooks = &server.Hooks{
+		PostStop: `echo "$MCP_HOOK $MCP_SERVER_NAME $MCP_SERVER_PORT $TOKEN $EXTRA" > ` + out,
+		PreStart: "echo not ready; exit 3",
+		PreStop:  "sleep 5",
+		Timeout:  100 * time.Millisecond,
+	}
+
+	require.NoError(t, manager.runHook("test1", HookPostStop, "EXTRA=1"))
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, "postStop test1 4001 secret 1\n", string(data))
+
+	err = manager.runHook("test1", HookPreStart)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not ready")
+
+	err = manager.runHook("test1", HookPreStop)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out")
+
+	// Servers without the hook succeed
+	assert.NoError(t, manager.runHook("test1", HookOnCrash))
+	assert.NoError(t, manager.runHook("test2", HookPreStart))
+}
+
+func TestManager_LifecycleHooks(t *testing.T) {
+	manager := createTestManager(t)
+	logFile := filepath.Join(t.TempDir(), "hooks.log")
+
+	record := func(event string) string { return "echo " + event + " >> " + logFile }
+	srv := server.NewServer("hooked", mockMCPCommand, 8104, "Hooked server")
+	srv.Hooks = &server.Hooks{
+		PreStart:  record(HookPreStart),
+		PostStart: record(HookPostStart),
+		PreStop:   record(HookPreStop),
+		PostStop:  record(HookPostStop),
+		Timeout:   5 * time.Second,
+	}
+	manager.servers["hooked"] = srv
+
+	events := func() []string {
+		data, _ := os.ReadFile(logFile)
+		return strings.Fields(string(data))
+	}
+
+	require.NoError(t, manager.StartServer("hooked"))
+	require.Eventually(t, func() bool { return len(events()) == 2 }, 5*time.Second, 20*time.Millisecond)
+
+	require.NoError(t, manager.StopServer("hooked"))
+	require.Eventually(t, func() bool { return len(events()) == 4 }, 5*time.Second, 20*time.Millisecond)
+	assert.Equal(t, []string{HookPreStart, HookPostStart, HookPreStop, HookPostStop}, events())
+
+	// A failing preStart hook aborts the start
+	srv.Hooks.PreStart = "exit 1"
+	assert.Error(t, manager.StartServer("hooked"))
+	assert.Equal(t, server.StatusStopped, srv.Status)
+}
+
+func TestManager_OnCrashHook(t *testing.T) {
+	manager := createTestManager(t)
+	out := filepath.Join(t.TempDir(), "crash.out")
+
+	crashing := mcpmock.Command("-tools", "", "-exit-after", "300ms", "-exit-code", "3")
+	srv := server.NewServer("crashy", crashing, 8105, "Crashing server")
+	srv.Hooks = &server.Hooks{OnCrash: `echo "$MCP_EXIT_CODE" > ` + out, Timeout: 5 * time.Second}
+	manager.servers["crashy"] = srv
+
+	require.NoError(t, manager.StartServer("crashy"))
+
+	require.Eventually(t, func() bool {
+		data, err := os.ReadFile(out)
+		return err == nil && string(data) == "3\n"
+	}, 10*time.Second, 50*time.Millisecond)
+}
@@ -4,19 +4,34 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"maps"
 	"net/http"
 	"os"
-	"os/exec"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
+	"github.com/tartavull/mcp-manager/internal/auth"
 	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/discovery"
+	mcpgrpc "github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/health"
+	"github.com/tartavull/mcp-manager/internal/logs"
+	"github.com/tartavull/mcp-manager/internal/metrics"
 	"github.com/tartavull/mcp-manager/internal/proxy"
+	"github.com/tartavull/mcp-manager/internal/redact"
+	"github.com/tartavull/mcp-manager/internal/secrets"
 	"github.com/tartavull/mcp-manager/internal/server"
+	"github.com/tartavull/mcp-manager/internal/shellenv"
+	"github.com/tartavull/mcp-manager/internal/transcript"
 )
 
 // Manager manages MCP servers and their HTTP proxies
@@ -28,7 +43,53 @@ type Manager struct {
 	watcher     *fsnotify.Watcher
 	stopWatcher chan struct{}
 	serverOrder []string // Stores the JSON order of servers
+	env         []string // Environment for server commands
+	proxyEnv    []string // Default outbound proxy variables for server commands
+	bindAddress string   // Interface the HTTP proxies listen on
+	corsOrigins []string // Browser origins allowed to call the HTTP proxies
+	redactor    *redact.Redactor
 	running     bool
+	maintenance bool // Daemon-wide maintenance mode
+
+	discovery       *discovery.Publisher // Advertises running proxies, nil when disabled
+	discoveryConfig *config.DiscoveryConfig
+
+	healthMonitors map[string]*health.Monitor // Custom health checks of running servers
+	restartTimers  map[string]*time.Timer     // Pending automatic restarts
+
+	logs   map[string]*logs.Buffer // Captured output, kept across restarts
+	logsMu sync.Mutex
+
+	pipes   map[int]*pipes // Pipes of the running server processes by PID
+	pipesMu sync.Mutex
+
+	circuitChanges chan mcpgrpc.CircuitChange // Circuit breaker changes of the proxies
+	configChanges  chan mcpgrpc.ConfigChange  // Servers changed by reloads of the configuration
+	portMigrations chan mcpgrpc.PortMigration // Steps of moving running servers to new ports
+	statusChanges  chan mcpgrpc.StatusChange  // Crashes and automatic restarts of servers
+
+	approvals       map[string]*pendingApproval // Calls waiting for approval by ID
+	approvalsMu     sync.Mutex
+	approvalChanges chan mcpgrpc.ApprovalChange // Calls parked for approval and their outcomes
+
+	refreshingTools atomic.Bool // A refresh of all servers' tools is running
+
+	transcripts *transcript.Store // Proxied calls grouped by client
+	calls       *metrics.Calls    // Latency of the proxied calls, for Prometheus
+
+	secrets       secrets.Store // Secrets server env references, nil when unavailable
+	secretsConfig *config.SecretsConfig
+
+	proxyAuth  auth.Provider // Authenticates the clients of the proxies, nil when open
+	proxyToken string        // Authenticates the daemon with its proxies
+
+	fleets map[string]*fleet // Isolated copies of servers by run ID
+
+	backupMu sync.Mutex // Serializes backups and restores, which stop servers
+
+	scripts      []*scriptProgram // Scripts reacting to events, in name order
+	scriptsMu    sync.Mutex
+	scriptEvents chan scriptEvent // Events waiting for the scripts, nil when disabled
 }
 
 // New creates a new MCP manager
@@ -39,7 +100,7 @@ func New() (*Manager, error) {
 	}
 
 	// Load from mcp.json
-	mcpConfig, err := cfg.LoadMCPConfig()
+	mcpConfig, err := loadMCPConfig(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load MCP config: %w", err)
 	}
@@ -47,7 +108,14 @@ func New() (*Manager, error) {
 	// Convert MCP config to server map
 	servers := make(map[string]*server.Server)
 	for name, srv := range mcpConfig.Servers {
-		servers[name] = server.NewServer(name, srv.Command, srv.Port, srv.Description)
+		servers[name] = newServerFromConfig(name, srv)
+	}
+
+	// Refuse to serve proxies open by mistake
+	proxyToken := newProxyToken()
+	proxyAuth, err := buildProxyAuth(mcpConfig.Auth, proxyToken)
+	if err != nil {
+		return nil, fmt.Errorf("invalid auth settings: %w", err)
 	}
 
 	// Create file watcher
@@ -63,19 +131,42 @@ func New() (*Manager, error) {
 		watcher:     watcher,
 		stopWatcher: make(chan struct{}),
 		serverOrder: mcpConfig.ServerOrder,
+		bindAddress: mcpConfig.BindAddress,
+		corsOrigins: mcpConfig.CORSOrigins,
 		running:     true,
+
+		circuitChanges:  make(chan mcpgrpc.CircuitChange, 100),
+		configChanges:   make(chan mcpgrpc.ConfigChange, 100),
+		portMigrations:  make(chan mcpgrpc.PortMigration, 100),
+		statusChanges:   make(chan mcpgrpc.StatusChange, 100),
+		approvalChanges: make(chan mcpgrpc.ApprovalChange, 100),
+		transcripts:     transcript.NewStore(),
+		calls:           metrics.NewCalls(),
+		proxyAuth:       proxyAuth,
+		proxyToken:      proxyToken,
+		scriptEvents:    make(chan scriptEvent, 100),
 	}
 
-	// Start watching the config file
-	configPath := cfg.GetMCPConfigPath()
-	if err := watcher.Add(configPath); err != nil {
+	m.env = buildEnv(mcpConfig)
+	m.proxyEnv = mcpConfig.OutboundProxy.Env()
+	m.redactor = buildRedactor(mcpConfig)
+	m.secrets, m.secretsConfig = openSecrets(cfg, mcpConfig.Secrets), mcpConfig.Secrets
+
+	// Watch the config directory rather than the file, so a config created
+	// after startup (e.g. by the setup wizard) or replaced by an editor is picked up
+	if err := watcher.Add(cfg.ConfigDir); err != nil {
 		log.Printf("Warning: failed to watch config file: %v", err)
 	} else {
+		m.watchScripts()
 		go m.watchConfigFile()
 	}
+	m.loadScripts()
+	go m.runScripts()
+	m.removeStaleFleets()
 
 	// Update server statuses based on running processes
 	m.updateServerStatuses()
+	m.setDiscovery(mcpConfig.Discovery)
 
 	return m, nil
 }
@@ -89,22 +180,37 @@ func (m *Manager) GetServers() (map[string]*server.Server, []string, error) {
 	for name, srv := range m.servers {
 		// Create a deep copy of the server to prevent race conditions
 		serverCopy := &server.Server{
-			Name:        srv.Name,
-			Command:     srv.Command,
-			Port:        srv.Port,
-			Description: srv.Description,
-			Status:      srv.Status,
-			PID:         srv.PID,
-			ToolCount:   srv.ToolCount,
-			Tools:       srv.Tools,
-			LastUpdated: srv.LastUpdated,
+			Name:           srv.Name,
+			Command:        srv.Command,
+			Port:           srv.Port,
+			Description:    srv.Description,
+			Status:         srv.Status,
+			Health:         srv.Health,
+			HealthMessage:  srv.HealthMessage,
+			RestartPolicy:  srv.RestartPolicy,
+			Restarts:       srv.Restarts,
+			Maintenance:    srv.Maintenance,
+			Draining:       srv.Draining,
+			BrowserProfile: srv.BrowserProfile,
+			Autostart:      srv.Autostart,
+			Tags:           srv.Tags,
+			RunID:          srv.RunID,
+			Group:          srv.Group,
+			PID:            srv.PID,
+			ToolCount:      srv.ToolCount,
+			Tools:          srv.Tools,
+			StartedAt:      srv.StartedAt,
+			LastUpdated:    srv.LastUpdated,
+		}
+		if proxyServer, exists := m.proxies[name]; exists {
+			serverCopy.Requests = proxyServer.Requests()
+			serverCopy.InFlight = proxyServer.InFlight()
 		}
 		servers[name] = serverCopy
 	}
 
 	// Return a copy of the order to prevent external modifications
-	order := make([]string, len(m.serverOrder))
-	copy(order, m.serverOrder)
+	order := append(slices.Clone(m.serverOrder), m.fleetOrder()...)
 
 	return servers, order, nil
 }
@@ -116,7 +222,7 @@ func (m *Manager) GetServer(name string) (*server.Server, error) {
 
 	srv, exists := m.servers[name]
 	if !exists {
-		return nil, fmt.Errorf("server '%s' not found", name)
+		return nil, fmt.Errorf("server '%s' %w", name, server.ErrNotFound)
 	}
 	return srv, nil
 }
@@ -127,52 +233,90 @@ func (m *Manager) GetServerOrder() ([]string, error) {
 	defer m.mu.RUnlock()
 
 	// Return a copy to prevent external modifications
-	order := make([]string, len(m.serverOrder))
-	copy(order, m.serverOrder)
-	return order, nil
+	return append(slices.Clone(m.serverOrder), m.fleetOrder()...), nil
 }
 
 // StartServer starts a specific MCP server and its HTTP proxy
 func (m *Manager) StartServer(name string) error {
+	// Run the preStart hook before taking the lock, it may take a while
+	if srv, err := m.GetServer(name); err == nil && !srv.IsRunning() {
+		if err := m.runHook(name, HookPreStart); err != nil {
+			return fmt.Errorf("preStart hook for '%s' failed: %w", name, err)
+		}
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	m.cancelRestart(name)
+	if err := m.startServer(name); err != nil {
+		return err
+	}
+
+	// Starting by hand resets the automatic restart counter
+	m.servers[name].Restarts = 0
+	return nil
+}
+
+// startServer starts a server and its HTTP proxy. Must be called with
+// m.mu held.
+func (m *Manager) startServer(name string) error {
 	srv, exists := m.servers[name]
 	if !exists {
-		return fmt.Errorf("server '%s' not found", name)
+		return fmt.Errorf("server '%s' %w", name, server.ErrNotFound)
 	}
 
 	if srv.IsRunning() {
-		return fmt.Errorf("server '%s' is already running", name)
+		return fmt.Errorf("server '%s' is %w", name, server.ErrAlreadyRunning)
+	}
+
+	if err := checkRequirements(srv.Requires); err != nil {
+		return fmt.Errorf("server '%s' can't start: %w", name, err)
+	}
+	if err := m.prepareResolver(srv); err != nil {
+		return fmt.Errorf("server '%s' can't start: %w", name, err)
+	}
+	if _, err := m.resolveEnv(srv); err != nil {
+		return fmt.Errorf("server '%s' can't start: %w", name, err)
+	}
+	command, err := m.transportCommand(srv)
+	if err != nil {
+		return fmt.Errorf("server '%s' can't start: %w", name, err)
 	}
 
 	srv.SetStatus(server.StatusStarting)
 
 	// Start the MCP server process
-	cmd := exec.Command("sh", "-c", srv.Command)
-	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
-
-	if err := cmd.Start(); err != nil {
+	command = m.spawnCommand(srv, command)
+	p, err := m.startProcess(name, command, m.serverEnv(srv))
+	if err != nil {
 		srv.SetStatus(server.StatusError)
 		return fmt.Errorf("failed to start server '%s': %w", name, err)
 	}
 
 	// Save PID
-	srv.SetPID(cmd.Process.Pid)
-	if err := m.config.SavePID(name, cmd.Process.Pid); err != nil {
+	srv.SetPID(p.cmd.Process.Pid)
+	if err := m.config.SavePID(name, p.cmd.Process.Pid); err != nil {
 		log.Printf("Warning: failed to save PID for %s: %v", name, err)
 	}
 
 	// Start HTTP proxy
-	proxyServer := proxy.New(srv.Port, srv.Command)
+	proxyServer := proxy.NewWithOptions(srv.Port, command, m.proxyOptions(srv))
 	if err := proxyServer.Start(); err != nil {
 		srv.SetStatus(server.StatusError)
-		cmd.Process.Kill()
+		srv.SetPID(0)
+		p.cmd.Process.Kill()
+		go m.supervise(name, p)
 		return fmt.Errorf("failed to start HTTP proxy for '%s': %w", name, err)
 	}
 
 	m.proxies[name] = proxyServer
 	srv.SetStatus(server.StatusRunning)
+	m.startHealthMonitor(name, srv)
+	go m.supervise(name, p)
+	m.runHookAsync(name, HookPostStart)
+	m.queueScriptEvent(ScriptOnServerStart, map[string]interface{}{"server": name})
+	m.advertise(srv)
 
 	// Get initial tool count after a short delay
 	go func() {
@@ -185,19 +329,33 @@ func (m *Manager) StartServer(name string) error {
 
 // StopServer stops a specific MCP server and its HTTP proxy
 func (m *Manager) StopServer(name string) error {
+	// Run the preStop hook before taking the lock, e.g. to flush state
+	if srv, err := m.GetServer(name); err == nil && srv.IsRunning() {
+		if err := m.runHook(name, HookPreStop); err != nil {
+			log.Printf("Warning: preStop hook for %s failed: %v", name, err)
+		}
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	srv, exists := m.servers[name]
 	if !exists {
-		return fmt.Errorf("server '%s' not found", name)
+		return fmt.Errorf("server '%s' %w", name, server.ErrNotFound)
 	}
 
 	if !srv.IsRunning() {
-		return fmt.Errorf("server '%s' is not running", name)
+		// Stopping a server waiting to be restarted cancels the restart
+		if _, pending := m.restartTimers[name]; pending {
+			m.cancelRestart(name)
+			srv.SetStatus(server.StatusStopped)
+			return nil
+		}
+		return fmt.Errorf("server '%s' is %w", name, server.ErrNotRunning)
 	}
 
 	srv.SetStatus(server.StatusStopping)
+	m.stopHealthMonitor(name, srv)
 
 	// Stop HTTP proxy
 	if proxyServer, exists := m.proxies[name]; exists {
@@ -222,6 +380,10 @@ func (m *Manager) StopServer(name string) error {
 	srv.SetPID(0)
 	srv.SetStatus(server.StatusStopped)
 	srv.SetToolCount(0)
+	srv.Draining = false
+	m.discovery.Withdraw(name)
+	m.runHookAsync(name, HookPostStop)
+	m.queueScriptEvent(ScriptOnServerStop, map[string]interface{}{"server": name})
 
 	return nil
 }
@@ -238,6 +400,19 @@ func (m *Manager) StartAllServers() {
 	}
 }
 
+// StartAutostartServers starts the servers configured to start with the
+// daemon, unless they are already running
+func (m *Manager) StartAutostartServers() {
+	servers, order, _ := m.GetServers()
+	for _, name := range order {
+		if srv, exists := servers[name]; exists && srv.Autostart && srv.Status == server.StatusStopped {
+			if err := m.StartServer(name); err != nil {
+				log.Printf("Failed to autostart %s: %v", name, err)
+			}
+		}
+	}
+}
+
 // StopAllServers stops all running servers
 func (m *Manager) StopAllServers() {
 	servers, _, _ := m.GetServers()
@@ -269,6 +444,7 @@ func (m *Manager) AddServer(name, command string, port int, description string)
 		Port:        port,
 		Description: description,
 	}
+	mcpConfig.ServerOrder = append(mcpConfig.ServerOrder, name)
 
 	// Save updated config
 	if err := m.config.SaveMCPConfig(mcpConfig); err != nil {
@@ -278,6 +454,7 @@ func (m *Manager) AddServer(name, command string, port int, description string)
 	// Add to runtime
 	srv := server.NewServer(name, command, port, description)
 	m.servers[name] = srv
+	m.serverOrder = append(m.serverOrder, name)
 
 	return nil
 }
@@ -289,7 +466,10 @@ func (m *Manager) RemoveServer(name string) error {
 
 	srv, exists := m.servers[name]
 	if !exists {
-		return fmt.Errorf("server '%s' not found", name)
+		return fmt.Errorf("server '%s' %w", name, server.ErrNotFound)
+	}
+	if srv.Group != "" {
+		return fmt.Errorf("server '%s' is an instance of '%s', change its instances instead", name, srv.Group)
 	}
 
 	// Stop server if running
@@ -317,6 +497,8 @@ func (m *Manager) RemoveServer(name string) error {
 
 	// Remove from runtime
 	delete(m.servers, name)
+	m.dropLogs(name)
+	m.serverOrder = slices.DeleteFunc(m.serverOrder, func(n string) bool { return n == name })
 
 	return nil
 }
@@ -369,12 +551,20 @@ func (m *Manager) updateServerStatuses() {
 			} else {
 				srv.SetStatus(server.StatusRunning)
 				srv.SetPID(pid)
+				m.startHealthMonitor(name, srv)
+				go m.watchAdopted(name, pid)
 
 				// Start HTTP proxy for running servers
 				if _, exists := m.proxies[name]; !exists {
-					proxyServer := proxy.New(srv.Port, srv.Command)
+					proxyServer := proxy.NewWithOptions(srv.Port, srv.Command, m.proxyOptions(srv))
 					if err := proxyServer.Start(); err == nil {
 						m.proxies[name] = proxyServer
+
+						// Get the tool count once the proxy is up
+						go func() {
+							time.Sleep(2 * time.Second)
+							m.updateToolCount(name)
+						}()
 					}
 				}
 			}
@@ -382,17 +572,45 @@ func (m *Manager) updateServerStatuses() {
 	}
 }
 
-// UpdateToolCounts updates tool counts for all running servers
+// toolRefreshWorkers bounds the servers whose tools are fetched at once
+const toolRefreshWorkers = 4
+
+// UpdateToolCounts refreshes the tools of all running servers in the
+// background, a few at a time. Calls while a refresh runs are ignored.
 func (m *Manager) UpdateToolCounts() error {
 	servers, _, err := m.GetServers()
 	if err != nil {
 		return err
 	}
+	var names []string
 	for name, srv := range servers {
 		if srv.IsRunning() {
-			go m.updateToolCount(name)
+			names = append(names, name)
 		}
 	}
+	if len(names) == 0 || !m.refreshingTools.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	go func() {
+		defer m.refreshingTools.Store(false)
+		queue := make(chan string)
+		var wg sync.WaitGroup
+		for range min(toolRefreshWorkers, len(names)) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for name := range queue {
+					m.updateToolCount(name)
+				}
+			}()
+		}
+		for _, name := range names {
+			queue <- name
+		}
+		close(queue)
+		wg.Wait()
+	}()
 	return nil
 }
 
@@ -406,12 +624,15 @@ func (m *Manager) updateToolCount(name string) {
 	}
 	m.mu.RUnlock()
 
-	// Wait a bit for the proxy to be ready
-	time.Sleep(2 * time.Second)
-
 	// Try to get tools list from HTTP proxy
 	client := &http.Client{Timeout: 5 * time.Second}
-	resp, err := client.Get(fmt.Sprintf("http://localhost:%d/tools/list", srv.Port))
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://localhost:%d/tools/list", srv.Port), nil)
+	if err != nil {
+		log.Printf("Failed to get tools for %s: %v", name, err)
+		return
+	}
+	req.Header.Set("Authorization", "Bearer "+m.proxyToken)
+	resp, err := client.Do(req)
 	if err != nil {
 		log.Printf("Failed to get tools for %s: %v", name, err)
 		return
@@ -437,6 +658,9 @@ func (m *Manager) updateToolCount(name string) {
 
 				m.mu.Lock()
 				srv.SetTools(tools)
+				if srv.IsRunning() {
+					m.advertise(srv)
+				}
 				m.mu.Unlock()
 			}
 		}
@@ -461,6 +685,18 @@ func (m *Manager) watchConfigFile() {
 				return
 			}
 
+			// Reload scripts when they change
+			if m.scriptsChanged(event) {
+				time.Sleep(100 * time.Millisecond)
+				m.loadScripts()
+				continue
+			}
+
+			// Only mcp.json is relevant in the watched directory
+			if filepath.Clean(event.Name) != m.config.GetMCPConfigPath() {
+				continue
+			}
+
 			// Handle file changes
 			if event.Op&fsnotify.Write == fsnotify.Write || event.Op&fsnotify.Create == fsnotify.Create {
 				log.Printf("Config file changed: %s", event.Name)
@@ -489,7 +725,7 @@ func (m *Manager) watchConfigFile() {
 // reloadConfig reloads the configuration and restarts affected servers
 func (m *Manager) reloadConfig() error {
 	// Load new config
-	mcpConfig, err := m.config.LoadMCPConfig()
+	mcpConfig, err := loadMCPConfig(m.config)
 	if err != nil {
 		return fmt.Errorf("failed to load MCP config: %w", err)
 	}
@@ -497,14 +733,34 @@ func (m *Manager) reloadConfig() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	// Update server order
+	// Update server order and environment
 	m.serverOrder = mcpConfig.ServerOrder
+	m.env = buildEnv(mcpConfig)
+	m.proxyEnv = mcpConfig.OutboundProxy.Env()
+	m.bindAddress = mcpConfig.BindAddress
+	m.corsOrigins = mcpConfig.CORSOrigins
+	m.redactor = buildRedactor(mcpConfig)
+	m.reloadProxyAuth(mcpConfig.Auth)
+	if !reflect.DeepEqual(m.secretsConfig, mcpConfig.Secrets) {
+		m.secrets, m.secretsConfig = openSecrets(m.config, mcpConfig.Secrets), mcpConfig.Secrets
+	}
+	if !reflect.DeepEqual(m.discoveryConfig, mcpConfig.Discovery) {
+		m.setDiscovery(mcpConfig.Discovery)
+	}
 
-	// Track servers to restart
+	// Track servers to restart, and those that can be restarted without
+	// closing their port
 	serversToRestart := make(map[string]bool)
+	blueGreen := make(map[string]bool)
+	movedFrom := make(map[string]int)
+	var change mcpgrpc.ConfigChange
 
 	// Check for changes in existing servers
 	for name, currentSrv := range m.servers {
+		// Fleets keep the config they were copied with
+		if currentSrv.RunID != "" {
+			continue
+		}
 		newConfig, exists := mcpConfig.Servers[name]
 
 		if !exists {
@@ -516,22 +772,121 @@ func (m *Manager) reloadConfig() error {
 				m.mu.Lock()
 			}
 			delete(m.servers, name)
+			m.dropLogs(name)
+			change.Removed = append(change.Removed, name)
 		} else {
-			// Check if configuration changed
+			// Check if configuration changed; priorities apply at spawn
+			newPriority := parsePriorityConfig(name, newConfig)
+			newProxyEnv := newConfig.OutboundProxy.Env()
+			newResolver := parseResolverConfig(name, newConfig)
+			newBreaker := parseCircuitBreakerConfig(name, newConfig)
+			newChaos := parseChaosConfig(name, newConfig)
+			newApproval := parseApprovalConfig(name, newConfig)
 			if currentSrv.Command != newConfig.Command ||
 				currentSrv.Port != newConfig.Port ||
-				currentSrv.Description != newConfig.Description {
+				!maps.Equal(currentSrv.Env, newConfig.Env) ||
+				currentSrv.BrowserProfile != newConfig.BrowserProfile ||
+				currentSrv.ShadowCommand != newConfig.ShadowCommand ||
+				currentSrv.Standby != newConfig.Standby ||
+				!reflect.DeepEqual(currentSrv.Middleware, newConfig.Middleware) ||
+				!reflect.DeepEqual(currentSrv.ResultLimit, newConfig.ResultLimit) ||
+				!reflect.DeepEqual(currentSrv.ReadOnly, newConfig.ReadOnly) ||
+				!reflect.DeepEqual(currentSrv.Approval, newApproval) ||
+				!reflect.DeepEqual(currentSrv.Priority, newPriority) ||
+				!slices.Equal(currentSrv.ProxyEnv, newProxyEnv) ||
+				!reflect.DeepEqual(currentSrv.Resolver, newResolver) ||
+				!reflect.DeepEqual(currentSrv.CircuitBreaker, newBreaker) ||
+				!reflect.DeepEqual(currentSrv.Chaos, newChaos) ||
+				!reflect.DeepEqual(currentSrv.Transport, newConfig.Transport) {
 				log.Printf("Configuration changed for server: %s", name)
 
+				// Port, shadow, standby, middleware, result limit, read-only,
+				// approval, circuit breaker and chaos changes need a new
+				// proxy, so they can't be blue/green. Servers with a standby
+				// always are, so they never go down.
+				blueGreen[name] = (newConfig.RestartStrategy == config.RestartBlueGreen || newConfig.Standby) &&
+					currentSrv.Port == newConfig.Port &&
+					currentSrv.ShadowCommand == newConfig.ShadowCommand &&
+					currentSrv.Standby == newConfig.Standby &&
+					reflect.DeepEqual(currentSrv.Middleware, newConfig.Middleware) &&
+					reflect.DeepEqual(currentSrv.ResultLimit, newConfig.ResultLimit) &&
+					reflect.DeepEqual(currentSrv.ReadOnly, newConfig.ReadOnly) &&
+					reflect.DeepEqual(currentSrv.Approval, newApproval) &&
+					reflect.DeepEqual(currentSrv.CircuitBreaker, newBreaker) &&
+					reflect.DeepEqual(currentSrv.Chaos, newChaos)
+
+				// Running servers move to a new port without going down
+				if currentSrv.Port != newConfig.Port && currentSrv.IsRunning() {
+					movedFrom[name] = currentSrv.Port
+				}
+
 				// Update server config
 				currentSrv.Command = newConfig.Command
 				currentSrv.Port = newConfig.Port
-				currentSrv.Description = newConfig.Description
+				currentSrv.Env = newConfig.Env
+				currentSrv.BrowserProfile = newConfig.BrowserProfile
+				currentSrv.ShadowCommand = newConfig.ShadowCommand
+				currentSrv.Standby = newConfig.Standby
+				currentSrv.Middleware = newConfig.Middleware
+				currentSrv.ResultLimit = newConfig.ResultLimit
+				currentSrv.ReadOnly = newConfig.ReadOnly
+				currentSrv.Approval = newApproval
+				currentSrv.Priority = newPriority
+				currentSrv.ProxyEnv = newProxyEnv
+				currentSrv.Resolver = newResolver
+				currentSrv.CircuitBreaker = newBreaker
+				currentSrv.Chaos = newChaos
+				currentSrv.Transport = newConfig.Transport
 
 				// Mark for restart if running
 				if currentSrv.IsRunning() {
 					serversToRestart[name] = true
 				}
+				change.Modified = append(change.Modified, name)
+			}
+
+			// Metadata doesn't reach the process, so it applies in place
+			newPolicy := parseRestartPolicyConfig(name, newConfig)
+			newHooks := parseHooksConfig(name, newConfig)
+			newRequires := parseRequirements(newConfig.Requires)
+			newCheck := parseHealthCheck(name, newConfig)
+			updated := currentSrv.Description != newConfig.Description ||
+				currentSrv.BlueGreen != (newConfig.RestartStrategy == config.RestartBlueGreen) ||
+				currentSrv.Autostart != newConfig.Autostart ||
+				!slices.Equal(currentSrv.Tags, newConfig.Tags) ||
+				currentSrv.Group != newConfig.Group ||
+				!slices.Equal(currentSrv.DataPaths, newConfig.DataPaths) ||
+				!reflect.DeepEqual(currentSrv.RestartPolicy, newPolicy) ||
+				!reflect.DeepEqual(currentSrv.Hooks, newHooks) ||
+				!reflect.DeepEqual(currentSrv.Requires, newRequires) ||
+				!reflect.DeepEqual(currentSrv.HealthCheck, newCheck)
+			if updated && !slices.Contains(change.Modified, name) {
+				log.Printf("Metadata changed for server: %s, applied without a restart", name)
+				change.Updated = append(change.Updated, name)
+			}
+
+			currentSrv.Description = newConfig.Description
+			currentSrv.BlueGreen = newConfig.RestartStrategy == config.RestartBlueGreen
+			currentSrv.Autostart = newConfig.Autostart
+			currentSrv.Tags = newConfig.Tags
+			currentSrv.Group = newConfig.Group
+			currentSrv.DataPaths = newConfig.DataPaths
+
+			// Restart policies apply to the next exit
+			currentSrv.RestartPolicy = newPolicy
+
+			// So do hooks and requirements
+			currentSrv.Hooks = newHooks
+			currentSrv.Requires = newRequires
+
+			// Health checks apply without restarting the server
+			if !reflect.DeepEqual(currentSrv.HealthCheck, newCheck) {
+				currentSrv.HealthCheck = newCheck
+				if currentSrv.IsRunning() && !serversToRestart[name] {
+					log.Printf("Health check changed for server: %s", name)
+					m.stopHealthMonitor(name, currentSrv)
+					m.startHealthMonitor(name, currentSrv)
+				}
 			}
 		}
 	}
@@ -540,12 +895,33 @@ func (m *Manager) reloadConfig() error {
 	for name, srv := range mcpConfig.Servers {
 		if _, exists := m.servers[name]; !exists {
 			log.Printf("Adding new server: %s", name)
-			m.servers[name] = server.NewServer(name, srv.Command, srv.Port, srv.Description)
+			m.servers[name] = newServerFromConfig(name, srv)
+			change.Added = append(change.Added, name)
 		}
 	}
 
 	// Restart servers that had config changes
 	for name := range serversToRestart {
+		if oldPort, moved := movedFrom[name]; moved {
+			m.mu.Unlock()
+			err := m.migratePort(name, oldPort)
+			m.mu.Lock()
+			if err != nil {
+				log.Printf("Failed to move server %s to its new port: %v", name, err)
+			}
+			continue
+		}
+		if blueGreen[name] {
+			log.Printf("Restarting server with new config (blue/green): %s", name)
+			m.mu.Unlock()
+			err := m.restartBlueGreen(name)
+			m.mu.Lock()
+			if err == nil {
+				continue
+			}
+			log.Printf("Blue/green restart of %s failed, falling back to stop/start: %v", name, err)
+		}
+
 		log.Printf("Restarting server with new config: %s", name)
 		m.mu.Unlock()
 		if err := m.StopServer(name); err != nil {
@@ -558,9 +934,387 @@ func (m *Manager) reloadConfig() error {
 		m.mu.Lock()
 	}
 
+	if len(change.Added)+len(change.Removed)+len(change.Modified)+len(change.Updated) > 0 {
+		for _, names := range [][]string{change.Added, change.Removed, change.Modified, change.Updated} {
+			sort.Strings(names)
+		}
+		// Drop changes nobody is reading rather than block the watcher
+		select {
+		case m.configChanges <- change:
+		default:
+		}
+	}
+
 	return nil
 }
 
+// ConfigChanges returns the servers each reload of the configuration
+// added, removed, restarted or updated in place
+func (m *Manager) ConfigChanges() <-chan mcpgrpc.ConfigChange {
+	return m.configChanges
+}
+
+// restartBlueGreen applies a config change to a running server without
+// closing its port: a new instance is started next to the old one, the
+// proxy switches to it once it is ready, and only then is the old instance
+// stopped. On failure the old instance keeps running.
+func (m *Manager) restartBlueGreen(name string) error {
+	m.mu.RLock()
+	srv, exists := m.servers[name]
+	proxyServer, hasProxy := m.proxies[name]
+	if !exists || !hasProxy || !srv.IsRunning() {
+		m.mu.RUnlock()
+		return fmt.Errorf("server '%s' is %w", name, server.ErrNotRunning)
+	}
+	command, err := m.transportCommand(srv)
+	if err != nil {
+		m.mu.RUnlock()
+		return err
+	}
+	command = m.spawnCommand(srv, command)
+	env := m.serverEnv(srv)
+	m.mu.RUnlock()
+
+	// Switch the proxy first; this is what clients talk to
+	if err := proxyServer.Swap(command, env); err != nil {
+		return err
+	}
+
+	// Replace the tracked server process
+	p, err := m.startProcess(name, command, env)
+	if err != nil {
+		return fmt.Errorf("failed to start server '%s': %w", name, err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	oldPID := srv.PID
+	srv.SetPID(p.cmd.Process.Pid)
+	go m.supervise(name, p)
+	if err := m.config.SavePID(name, p.cmd.Process.Pid); err != nil {
+		log.Printf("Warning: failed to save PID for %s: %v", name, err)
+	}
+	if oldPID > 0 {
+		if err := syscall.Kill(-oldPID, syscall.SIGTERM); err != nil {
+			log.Printf("Warning: failed to kill process group %d: %v", oldPID, err)
+		}
+	}
+
+	// The health check may depend on the changed env
+	m.stopHealthMonitor(name, srv)
+	m.startHealthMonitor(name, srv)
+
+	return nil
+}
+
+// SetMaintenance enables or disables maintenance mode for a server, or for
+// the whole daemon when name is empty. Maintenance suppresses automatic
+// restarts and health alerts while a server is worked on.
+func (m *Manager) SetMaintenance(name string, enabled bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if name == "" {
+		m.maintenance = enabled
+		log.Printf("Daemon maintenance mode: %t", enabled)
+		return nil
+	}
+
+	srv, exists := m.servers[name]
+	if !exists {
+		return fmt.Errorf("server '%s' %w", name, server.ErrNotFound)
+	}
+	srv.Maintenance = enabled
+	log.Printf("Maintenance mode for %s: %t", name, enabled)
+	return nil
+}
+
+// Maintenance returns true if the whole daemon is in maintenance mode
+func (m *Manager) Maintenance() (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.maintenance, nil
+}
+
+// inMaintenance returns true if automatic actions on a server are
+// suppressed. Must be called with m.mu held.
+func (m *Manager) inMaintenance(srv *server.Server) bool {
+	return m.maintenance || srv.Maintenance
+}
+
+// EffectivePath returns the PATH used when spawning server commands
+func (m *Manager) EffectivePath() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.env == nil {
+		return os.Getenv("PATH")
+	}
+	return shellenv.Lookup(m.env, "PATH")
+}
+
+// loadMCPConfig loads mcp.json with the servers declaring instances
+// expanded into them
+func loadMCPConfig(cfg *config.Config) (*config.MCPConfig, error) {
+	mcpConfig, err := cfg.LoadMCPConfig()
+	if err != nil {
+		return nil, err
+	}
+	if err := mcpConfig.ExpandInstances(); err != nil {
+		return nil, err
+	}
+	return mcpConfig, nil
+}
+
+// newServerFromConfig creates a runtime server from its mcp.json entry
+func newServerFromConfig(name string, cfg *config.MCPServerConfig) *server.Server {
+	srv := server.NewServer(name, cfg.Command, cfg.Port, cfg.Description)
+	srv.Env = cfg.Env
+	srv.ProxyEnv = cfg.OutboundProxy.Env()
+	srv.BrowserProfile = cfg.BrowserProfile
+	srv.DataPaths = cfg.DataPaths
+	srv.HealthCheck = parseHealthCheck(name, cfg)
+	srv.BlueGreen = cfg.RestartStrategy == config.RestartBlueGreen
+	srv.Standby = cfg.Standby
+	srv.ShadowCommand = cfg.ShadowCommand
+	srv.Middleware = cfg.Middleware
+	srv.ResultLimit = cfg.ResultLimit
+	srv.ReadOnly = cfg.ReadOnly
+	srv.Approval = parseApprovalConfig(name, cfg)
+	srv.CircuitBreaker = parseCircuitBreakerConfig(name, cfg)
+	srv.Chaos = parseChaosConfig(name, cfg)
+	srv.RestartPolicy = parseRestartPolicyConfig(name, cfg)
+	srv.Hooks = parseHooksConfig(name, cfg)
+	srv.Autostart = cfg.Autostart
+	srv.Tags = cfg.Tags
+	srv.Group = cfg.Group
+	srv.Priority = parsePriorityConfig(name, cfg)
+	srv.Requires = parseRequirements(cfg.Requires)
+	srv.Resolver = parseResolverConfig(name, cfg)
+	srv.Transport = cfg.Transport
+	return srv
+}
+
+// parseRestartPolicyConfig returns the server's restart policy, ignoring
+// invalid ones
+func parseRestartPolicyConfig(name string, cfg *config.MCPServerConfig) *server.RestartPolicy {
+	policy, err := parseRestartPolicy(cfg.RestartPolicy)
+	if err != nil {
+		log.Printf("Warning: ignoring restart policy for %s: %v", name, err)
+		return nil
+	}
+	return policy
+}
+
+// parseHealthCheck returns the server's health check, ignoring invalid ones
+func parseHealthCheck(name string, cfg *config.MCPServerConfig) *server.HealthCheck {
+	check, err := health.ParseConfig(cfg.HealthCheck)
+	if err != nil {
+		log.Printf("Warning: ignoring health check for %s: %v", name, err)
+		return nil
+	}
+	return check
+}
+
+// startHealthMonitor starts the custom health check of a running server.
+// Must be called with m.mu held.
+func (m *Manager) startHealthMonitor(name string, srv *server.Server) {
+	if srv.HealthCheck == nil {
+		return
+	}
+	if m.healthMonitors == nil {
+		m.healthMonitors = make(map[string]*health.Monitor)
+	}
+	if _, exists := m.healthMonitors[name]; exists {
+		return
+	}
+
+	// Servers aren't ready until their first check passes
+	srv.SetHealth(server.HealthPending, "")
+
+	var monitor *health.Monitor
+	monitor = health.Start(srv.HealthCheck, m.serverEnv(srv), func(result health.Result) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		// Ignore results from a monitor that has since been stopped
+		if m.healthMonitors[name] != monitor {
+			return
+		}
+		if srv.Health != result.Health && !m.inMaintenance(srv) {
+			log.Printf("Health check for %s: %s %s", name, result.Health, result.Message)
+		}
+		srv.SetHealth(result.Health, result.Message)
+	})
+	m.healthMonitors[name] = monitor
+}
+
+// stopHealthMonitor stops the custom health check of a server and clears
+// its result. Must be called with m.mu held.
+func (m *Manager) stopHealthMonitor(name string, srv *server.Server) {
+	if monitor, exists := m.healthMonitors[name]; exists {
+		monitor.Stop()
+		delete(m.healthMonitors, name)
+	}
+	srv.SetHealth(server.HealthUnknown, "")
+}
+
+// setDiscovery replaces the service discovery publisher, advertising the
+// running servers with the new one. Must be called with m.mu held, or
+// before the manager is shared.
+func (m *Manager) setDiscovery(cfg *config.DiscoveryConfig) {
+	if err := m.discovery.Close(); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	m.discoveryConfig = cfg
+
+	publisher, err := discovery.New(cfg)
+	if err != nil {
+		log.Printf("Warning: service discovery disabled: %v", err)
+	}
+	m.discovery = publisher
+
+	for _, srv := range m.servers {
+		if srv.IsRunning() {
+			m.advertise(srv)
+		}
+	}
+}
+
+// advertise publishes a running server's proxy for discovery. Must be
+// called with m.mu held.
+func (m *Manager) advertise(srv *server.Server) {
+	m.discovery.Advertise(discovery.Service{Name: srv.Name, Port: srv.Port, Tools: srv.ToolCount})
+}
+
+// serverEnv returns the process environment for a server, layering its
+// outbound proxy and then its configured variables over the shared
+// environment
+func (m *Manager) serverEnv(srv *server.Server) []string {
+	proxyEnv := srv.ProxyEnv
+	if proxyEnv == nil {
+		proxyEnv = m.proxyEnv
+	}
+	if len(srv.Env) == 0 && len(proxyEnv) == 0 && srv.BrowserProfile == "" {
+		return m.env
+	}
+
+	env := m.env
+	if env == nil {
+		env = os.Environ()
+	}
+
+	vars := make([]string, 0, len(srv.Env))
+	for key, value := range m.serverVars(srv) {
+		vars = append(vars, key+"="+value)
+	}
+	if srv.BrowserProfile != "" {
+		vars = append(vars, ProfileDirEnv+"="+filepath.Join(m.profilesDir(srv), srv.BrowserProfile))
+	}
+	sort.Strings(vars)
+	overlay := append(slices.Clone(proxyEnv), vars...)
+
+	return shellenv.Merge(env, overlay)
+}
+
+// spawnCommand returns the shell command starting one of a server's
+// commands with its resolver overrides and scheduling priority
+func (m *Manager) spawnCommand(srv *server.Server, command string) string {
+	return withPriority(m.withResolver(command, srv), srv.Priority)
+}
+
+// proxyOptions returns the HTTP proxy options for a server
+func (m *Manager) proxyOptions(srv *server.Server) proxy.Options {
+	opts := proxy.Options{
+		Env:           m.serverEnv(srv),
+		BindAddress:   m.bindAddress,
+		CORSOrigins:   m.corsOrigins,
+		ShadowCommand: m.spawnCommand(srv, srv.ShadowCommand),
+		Middleware:    srv.Middleware,
+		Redactor:      m.redactor,
+
+		CircuitBreaker: m.circuitBreakerOptions(srv),
+		Chaos:          srv.Chaos,
+		ReadOnly:       srv.ReadOnly,
+		Approval:       m.approvalOptions(srv),
+		Transcript:     m.callHook(srv),
+		Auth:           m.proxyAuth,
+		Standby:        srv.Standby,
+	}
+	opts.Stderr = m.logBuffer(srv.Name).Writer("stderr")
+	if srv.ResultLimit != nil {
+		opts.MaxResultSize = srv.ResultLimit.MaxSize
+		if srv.ResultLimit.Spill {
+			opts.SpillDir = filepath.Join(m.config.GetCacheDir(), "results", srv.Name)
+		}
+	}
+	return opts
+}
+
+// buildEnv builds the environment for server commands from the config settings
+func buildEnv(mcpConfig *config.MCPConfig) []string {
+	if !mcpConfig.ShellEnv && len(mcpConfig.Path) == 0 {
+		return nil
+	}
+
+	env, err := shellenv.Build(shellenv.Options{
+		LoginShell: mcpConfig.ShellEnv,
+		Path:       mcpConfig.Path,
+	})
+	if err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	log.Printf("Effective PATH for server commands: %s", shellenv.Lookup(env, "PATH"))
+
+	return env
+}
+
+// buildRedactor builds the payload redactor from the config settings,
+// falling back to the default rules if they are invalid
+func buildRedactor(mcpConfig *config.MCPConfig) *redact.Redactor {
+	redactor, err := redact.New(mcpConfig.Redaction)
+	if err != nil {
+		log.Printf("Warning: ignoring redaction rules: %v", err)
+		return redact.Default()
+	}
+	return redactor
+}
+
+// Logs returns the captured output of a server
+func (m *Manager) Logs(name string) (*logs.Buffer, error) {
+	m.mu.RLock()
+	_, exists := m.servers[name]
+	m.mu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("server '%s' %w", name, server.ErrNotFound)
+	}
+	return m.logBuffer(name), nil
+}
+
+// logBuffer returns the buffer capturing a server's output
+func (m *Manager) logBuffer(name string) *logs.Buffer {
+	m.logsMu.Lock()
+	defer m.logsMu.Unlock()
+
+	if m.logs == nil {
+		m.logs = make(map[string]*logs.Buffer)
+	}
+	buffer, exists := m.logs[name]
+	if !exists {
+		buffer = logs.NewBuffer(logs.DefaultCapacity)
+		m.logs[name] = buffer
+	}
+	return buffer
+}
+
+// dropLogs discards the captured output of a removed server
+func (m *Manager) dropLogs(name string) {
+	m.logsMu.Lock()
+	delete(m.logs, name)
+	m.logsMu.Unlock()
+}
+
 // GetConfigPath returns the path to the mcp.json config file
 func (m *Manager) GetConfigPath() (string, error) {
 	return m.config.GetMCPConfigPath(), nil
@@ -585,5 +1339,9 @@ func (m *Manager) Close() error {
 	// Mark as not running
 	m.Stop()
 
+	m.mu.Lock()
+	m.setDiscovery(nil)
+	m.mu.Unlock()
+
 	return nil
 }
@@ -1,39 +1,42 @@
 package manager
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"os"
-	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/discovery"
+	mcpgrpc "github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/logs"
+	"github.com/tartavull/mcp-manager/internal/mcpmock"
 	"github.com/tartavull/mcp-manager/internal/proxy"
+	"github.com/tartavull/mcp-manager/internal/redact"
 	"github.com/tartavull/mcp-manager/internal/server"
 )
 
+func TestMain(m *testing.M) {
+	mcpmock.RunIfHelper()
+	os.Exit(m.Run())
+}
+
 func createTestManager(t *testing.T) *Manager {
-	tempDir := t.TempDir()
 	cfg := &config.Config{
-		ConfigDir: tempDir,
-		PidDir:    filepath.Join(tempDir, "pids"),
+		ConfigDir: t.TempDir(),
 	}
 
-	err := os.MkdirAll(cfg.PidDir, 0755)
-	require.NoError(t, err)
-
 	// Create a test server map
 	servers := map[string]*server.Server{
 		"test1": server.NewServer("test1", "echo test1", 4001, "Test server 1"),
 		"test2": server.NewServer("test2", "echo test2", 4002, "Test server 2"),
 	}
 
-	// Save initial servers
-	err = cfg.SaveServers(servers)
-	require.NoError(t, err)
-
 	return &Manager{
 		servers: servers,
 		proxies: make(map[string]*proxy.Server),
@@ -42,6 +45,7 @@ func createTestManager(t *testing.T) *Manager {
 }
 
 func TestNew(t *testing.T) {
+	t.Setenv(config.ConfigDirEnv, t.TempDir())
 	manager, err := New()
 	require.NoError(t, err)
 
@@ -49,10 +53,10 @@ func TestNew(t *testing.T) {
 	assert.NotNil(t, manager.proxies)
 	assert.NotNil(t, manager.config)
 
-	// Should have default servers
+	// Without an mcp.json there are no servers until some are added
 	servers, _, err := manager.GetServers()
 	require.NoError(t, err)
-	assert.Greater(t, len(servers), 0)
+	assert.Empty(t, servers)
 }
 
 func TestManager_GetServers(t *testing.T) {
@@ -99,6 +103,14 @@ func TestManager_AddServer(t *testing.T) {
 	assert.Equal(t, 4003, srv.Port)
 	assert.Equal(t, "Test server 3", srv.Description)
 
+	// Verify server was ordered and persisted
+	order, _ := manager.GetServerOrder()
+	assert.Contains(t, order, "test3")
+	mcpConfig, err := manager.config.LoadMCPConfig()
+	require.NoError(t, err)
+	assert.Contains(t, mcpConfig.Servers, "test3")
+	assert.Contains(t, mcpConfig.ServerOrder, "test3")
+
 	// Try to add duplicate server
 	err = manager.AddServer("test3", "different command", 4004, "Different description")
 	assert.Error(t, err)
@@ -132,6 +144,7 @@ func TestManager_StartServer_NonExistentServer(t *testing.T) {
 	err := manager.StartServer("nonexistent")
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "not found")
+	assert.ErrorIs(t, err, server.ErrNotFound)
 }
 
 func TestManager_StopServer_NonRunningServer(t *testing.T) {
@@ -140,6 +153,7 @@ func TestManager_StopServer_NonRunningServer(t *testing.T) {
 	err := manager.StopServer("test1")
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "not running")
+	assert.ErrorIs(t, err, server.ErrNotRunning)
 }
 
 func TestManager_StopServer_NonExistentServer(t *testing.T) {
@@ -160,6 +174,24 @@ func TestManager_StartAllServers(t *testing.T) {
 	// (Note: they may not actually start due to echo command, but status should change)
 }
 
+func TestManager_StartAutostartServers(t *testing.T) {
+	manager := createTestManager(t)
+
+	// Running autostart servers are left alone, others aren't started
+	srv1, _ := manager.GetServer("test1")
+	srv1.Autostart = true
+	srv1.SetStatus(server.StatusRunning)
+	srv1.SetPID(123)
+
+	manager.StartAutostartServers()
+
+	servers, _, err := manager.GetServers()
+	require.NoError(t, err)
+	assert.Equal(t, 123, servers["test1"].PID)
+	assert.True(t, servers["test1"].Autostart)
+	assert.Equal(t, server.StatusStopped, servers["test2"].Status)
+}
+
 func TestManager_StopAllServers(t *testing.T) {
 	manager := createTestManager(t)
 
@@ -333,3 +365,243 @@ func TestManager_ThreadSafety(t *testing.T) {
 	require.NoError(t, err)
 	assert.GreaterOrEqual(t, len(servers), 2) // At least our original test servers
 }
+
+func TestManager_serverEnv(t *testing.T) {
+	manager := createTestManager(t)
+	manager.env = []string{"PATH=/usr/bin", "TOKEN=shared"}
+
+	// Servers without env share the manager environment
+	srv := manager.servers["test1"]
+	assert.Equal(t, manager.env, manager.serverEnv(srv))
+
+	srv.Env = map[string]string{"TOKEN": "secret", "API_KEY": "key"}
+	assert.Equal(t, []string{"PATH=/usr/bin", "TOKEN=secret", "API_KEY=key"}, manager.serverEnv(srv))
+	assert.Equal(t, []string{"PATH=/usr/bin", "TOKEN=shared"}, manager.env, "manager env should not be modified")
+
+	// Outbound proxy variables apply under the server's own variables
+	manager.proxyEnv = []string{"HTTPS_PROXY=http://proxy:3128", "TOKEN=proxy"}
+	assert.Equal(t, []string{"PATH=/usr/bin", "TOKEN=secret", "HTTPS_PROXY=http://proxy:3128", "API_KEY=key"}, manager.serverEnv(srv))
+
+	srv.Env = nil
+	srv.ProxyEnv = []string{"HTTPS_PROXY=http://staging-proxy:3128"}
+	assert.Equal(t, []string{"PATH=/usr/bin", "TOKEN=shared", "HTTPS_PROXY=http://staging-proxy:3128"}, manager.serverEnv(srv))
+
+	// An empty proxy bypasses the default
+	srv.ProxyEnv = []string{}
+	assert.Equal(t, manager.env, manager.serverEnv(srv))
+}
+
+func TestBuildRedactor(t *testing.T) {
+	mcpConfig := &config.MCPConfig{}
+	mcpConfig.Redaction = []redact.Rule{{Key: "^ssn$"}}
+	assert.Equal(t, `{"ssn":"[REDACTED]"}`, buildRedactor(mcpConfig).JSON(map[string]string{"ssn": "123"}))
+
+	// Invalid rules fall back to the defaults
+	mcpConfig.Redaction = []redact.Rule{{Key: "("}}
+	assert.Equal(t, `{"password":"[REDACTED]","ssn":"123"}`,
+		buildRedactor(mcpConfig).JSON(map[string]string{"ssn": "123", "password": "pw"}))
+}
+
+func TestManager_HealthMonitor(t *testing.T) {
+	manager := createTestManager(t)
+	srv := manager.servers["test1"]
+	srv.HealthCheck = &server.HealthCheck{Command: "exit 1", Interval: 10 * time.Millisecond, Timeout: time.Second}
+
+	manager.mu.Lock()
+	manager.startHealthMonitor("test1", srv)
+	manager.mu.Unlock()
+
+	assert.Eventually(t, func() bool {
+		manager.mu.RLock()
+		defer manager.mu.RUnlock()
+		return srv.Health == server.HealthUnhealthy
+	}, 2*time.Second, 10*time.Millisecond)
+
+	manager.mu.Lock()
+	manager.stopHealthMonitor("test1", srv)
+	manager.mu.Unlock()
+
+	// Results arriving after stopping are discarded
+	time.Sleep(50 * time.Millisecond)
+	manager.mu.RLock()
+	assert.Equal(t, server.HealthUnknown, srv.Health)
+	manager.mu.RUnlock()
+}
+
+// mockMCPCommand answers the proxy's handshake without listing tools
+var mockMCPCommand = mcpmock.Command("-tools", "")
+
+func TestManager_restartBlueGreen(t *testing.T) {
+	manager := createTestManager(t)
+
+	// Only running servers can be restarted in place
+	err := manager.restartBlueGreen("test1")
+	assert.Error(t, err)
+
+	manager.servers["bg"] = server.NewServer("bg", mockMCPCommand, 8095, "Blue/green server")
+	require.NoError(t, manager.StartServer("bg"))
+	defer manager.StopServer("bg")
+
+	srv := manager.servers["bg"]
+	oldPID := srv.PID
+
+	require.NoError(t, manager.restartBlueGreen("bg"))
+	assert.True(t, srv.IsRunning())
+	assert.NotEqual(t, oldPID, srv.PID)
+
+	// The proxy kept its listener
+	resp, err := http.Get("http://localhost:8095/health")
+	require.NoError(t, err)
+	resp.Body.Close()
+}
+
+// advertiser records the services advertised by the manager
+type advertiser struct {
+	mu       sync.Mutex
+	services map[string]discovery.Service
+}
+
+func (a *advertiser) Advertise(svc discovery.Service) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.services[svc.Name] = svc
+	return nil
+}
+
+func (a *advertiser) Withdraw(name string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.services, name)
+	return nil
+}
+
+func (a *advertiser) Close() error { return nil }
+
+func (a *advertiser) get(name string) (discovery.Service, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	svc, exists := a.services[name]
+	return svc, exists
+}
+
+func TestManager_Discovery(t *testing.T) {
+	manager := createTestManager(t)
+	ads := &advertiser{services: make(map[string]discovery.Service)}
+	manager.discovery = discovery.NewPublisher(ads)
+
+	manager.servers["found"] = server.NewServer("found", mockMCPCommand, 8106, "Discoverable server")
+	require.NoError(t, manager.StartServer("found"))
+
+	require.Eventually(t, func() bool {
+		svc, exists := ads.get("found")
+		return exists && svc.Port == 8106
+	}, 5*time.Second, 20*time.Millisecond)
+
+	require.NoError(t, manager.StopServer("found"))
+	require.Eventually(t, func() bool {
+		_, exists := ads.get("found")
+		return !exists
+	}, 5*time.Second, 20*time.Millisecond)
+}
+
+func TestManager_Logs(t *testing.T) {
+	manager := createTestManager(t)
+
+	noisy := mcpmock.Command("-tools", "", "-stderr", "WARN: starting up")
+	srv := server.NewServer("noisy", noisy, 8107, "Noisy server")
+	manager.servers["noisy"] = srv
+
+	require.NoError(t, manager.StartServer("noisy"))
+	defer manager.StopServer("noisy")
+
+	buffer, err := manager.Logs("noisy")
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return len(buffer.Tail(0)) > 0
+	}, 5*time.Second, 20*time.Millisecond)
+
+	line := buffer.Tail(0)[0]
+	assert.Equal(t, "WARN: starting up", line.Text)
+	assert.Equal(t, "stderr", line.Stream)
+	assert.Equal(t, logs.SeverityWarning, line.Severity)
+
+	// Logs are kept across restarts, and dropped with the server
+	require.NoError(t, manager.StopServer("noisy"))
+	again, err := manager.Logs("noisy")
+	require.NoError(t, err)
+	assert.Same(t, buffer, again)
+
+	require.NoError(t, manager.RemoveServer("noisy"))
+	_, err = manager.Logs("noisy")
+	assert.Error(t, err)
+}
+
+func TestManager_ReloadConfig_Instances(t *testing.T) {
+	manager := createTestManager(t)
+	writeInstances := func(instances int) {
+		data := fmt.Sprintf(`{"servers": {"web": {"command": "web-mcp --slot {{instance}}", "instances": %d}}}`, instances)
+		require.NoError(t, os.WriteFile(manager.config.GetMCPConfigPath(), []byte(data), 0644))
+	}
+
+	writeInstances(3)
+	require.NoError(t, manager.reloadConfig())
+	order, _ := manager.GetServerOrder()
+	assert.Equal(t, []string{"web-1", "web-2", "web-3"}, order)
+	srv, err := manager.GetServer("web-2")
+	require.NoError(t, err)
+	assert.Equal(t, "web-mcp --slot 2", srv.Command)
+	assert.Equal(t, "web", srv.Group)
+
+	// Instances are changed through their template
+	err = manager.RemoveServer("web-2")
+	assert.ErrorContains(t, err, "is an instance of 'web'")
+
+	writeInstances(2)
+	require.NoError(t, manager.reloadConfig())
+	_, err = manager.GetServer("web-3")
+	assert.ErrorIs(t, err, server.ErrNotFound)
+}
+
+func TestManager_ReloadConfig_Metadata(t *testing.T) {
+	manager := createTestManager(t)
+	manager.servers = make(map[string]*server.Server)
+	manager.configChanges = make(chan mcpgrpc.ConfigChange, 10)
+	writeServer := func(command, description string, tags ...string) {
+		data, err := json.Marshal(map[string]interface{}{"servers": map[string]interface{}{
+			"mock": map[string]interface{}{"command": command, "port": 8115, "description": description, "tags": tags},
+		}})
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(manager.config.GetMCPConfigPath(), data, 0644))
+	}
+
+	command := mcpmock.Command("-tools", "")
+	writeServer(command, "Mock server")
+	require.NoError(t, manager.reloadConfig())
+	assert.Equal(t, mcpgrpc.ConfigChange{Added: []string{"mock"}}, <-manager.configChanges)
+
+	require.NoError(t, manager.StartServer("mock"))
+	defer manager.StopServer("mock")
+	srv, err := manager.GetServer("mock")
+	require.NoError(t, err)
+	pid := srv.PID
+
+	// Descriptions and tags apply in place
+	writeServer(command, "Mock server for tests", "team:qa")
+	require.NoError(t, manager.reloadConfig())
+	assert.Equal(t, mcpgrpc.ConfigChange{Updated: []string{"mock"}}, <-manager.configChanges)
+	srv, err = manager.GetServer("mock")
+	require.NoError(t, err)
+	assert.Equal(t, pid, srv.PID, "the server isn't restarted")
+	assert.Equal(t, "Mock server for tests", srv.Description)
+	assert.Equal(t, []string{"team:qa"}, srv.Tags)
+
+	// Commands don't
+	require.NoError(t, manager.StopServer("mock"))
+	writeServer(mcpmock.Command("-tools", "echo"), "Mock echo server")
+	require.NoError(t, manager.reloadConfig())
+	assert.Equal(t, mcpgrpc.ConfigChange{Modified: []string{"mock"}}, <-manager.configChanges)
+
+	// Unchanged configurations aren't reported
+	require.NoError(t, manager.reloadConfig())
+	assert.Empty(t, manager.configChanges)
+}
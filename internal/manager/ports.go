@@ -0,0 +1,166 @@
+package manager
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"syscall"
+	"time"
+
+	mcpgrpc "github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/proxy"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// portTimeout is how long a proxy has to bind its new port, and the old
+// one to be released
+const portTimeout = 5 * time.Second
+
+// migratePort moves a running server to the port its config changed to: a
+// proxy is started on the new port and serves before the old proxy is
+// stopped and its port checked to be released. On failure before the
+// switch the server keeps running on the old port, and the next reload
+// tries again.
+func (m *Manager) migratePort(name string, oldPort int) error {
+	m.mu.RLock()
+	srv, exists := m.servers[name]
+	oldProxy, hasProxy := m.proxies[name]
+	if !exists || !hasProxy || !srv.IsRunning() {
+		m.mu.RUnlock()
+		return fmt.Errorf("server '%s' is %w", name, server.ErrNotRunning)
+	}
+	newPort := srv.Port
+	command, err := m.transportCommand(srv)
+	if err != nil {
+		m.mu.RUnlock()
+		return err
+	}
+	command = m.spawnCommand(srv, command)
+	env := m.serverEnv(srv)
+	opts := m.proxyOptions(srv)
+	bindAddress := m.bindAddress
+	m.mu.RUnlock()
+
+	fail := func(err error) error {
+		m.mu.Lock()
+		srv.Port = oldPort
+		m.mu.Unlock()
+		m.portMigrated(name, oldPort, newPort, mcpgrpc.PortFailed, err.Error())
+		return err
+	}
+
+	// Check the port first, the proxy only logs failing to bind it
+	if !portFree(bindAddress, newPort) {
+		return fail(fmt.Errorf("port %d is in use", newPort))
+	}
+	next := proxy.NewWithOptions(newPort, command, opts)
+	if err := next.Start(); err != nil {
+		return fail(fmt.Errorf("failed to start HTTP proxy: %w", err))
+	}
+	if !waitPort(func() bool { return portServing(bindAddress, newPort) }) {
+		next.Stop()
+		return fail(fmt.Errorf("proxy didn't bind port %d", newPort))
+	}
+	p, err := m.startProcess(name, command, env)
+	if err != nil {
+		next.Stop()
+		return fail(fmt.Errorf("failed to start server: %w", err))
+	}
+
+	// Switch to the new proxy and process
+	m.mu.Lock()
+	m.proxies[name] = next
+	oldPID := srv.PID
+	srv.SetPID(p.cmd.Process.Pid)
+	go m.supervise(name, p)
+	if err := m.config.SavePID(name, p.cmd.Process.Pid); err != nil {
+		log.Printf("Warning: failed to save PID for %s: %v", name, err)
+	}
+	if oldPID > 0 {
+		if err := syscall.Kill(-oldPID, syscall.SIGTERM); err != nil {
+			log.Printf("Warning: failed to kill process group %d: %v", oldPID, err)
+		}
+	}
+	// The health check may depend on the port
+	m.stopHealthMonitor(name, srv)
+	m.startHealthMonitor(name, srv)
+	m.advertise(srv)
+	m.mu.Unlock()
+	m.portMigrated(name, oldPort, newPort, mcpgrpc.PortSwitched, "")
+
+	go func() {
+		time.Sleep(2 * time.Second)
+		m.updateToolCount(name)
+	}()
+
+	if err := oldProxy.Stop(); err != nil {
+		log.Printf("Warning: failed to stop HTTP proxy for %s: %v", name, err)
+	}
+	if !waitPort(func() bool { return portFree(bindAddress, oldPort) }) {
+		err := fmt.Errorf("port %d is still in use", oldPort)
+		m.portMigrated(name, oldPort, newPort, mcpgrpc.PortFailed, err.Error())
+		return err
+	}
+	m.portMigrated(name, oldPort, newPort, mcpgrpc.PortReleased, "")
+	return nil
+}
+
+// portMigrated logs a step of a port migration and queues it for the event
+// stream
+func (m *Manager) portMigrated(name string, oldPort, newPort int, state, reason string) {
+	if reason != "" {
+		log.Printf("Port migration of %s from %d to %d %s: %s", name, oldPort, newPort, state, reason)
+	} else {
+		log.Printf("Port migration of %s from %d to %d %s", name, oldPort, newPort, state)
+	}
+
+	// Drop migrations nobody is reading rather than block the reload
+	select {
+	case m.portMigrations <- mcpgrpc.PortMigration{Server: name, OldPort: oldPort, NewPort: newPort, State: state, Reason: reason}:
+	default:
+	}
+}
+
+// PortMigrations returns the steps of moving running servers to new ports
+func (m *Manager) PortMigrations() <-chan mcpgrpc.PortMigration {
+	return m.portMigrations
+}
+
+// portFree returns true if port can be listened on at the bind address
+func portFree(bindAddress string, port int) bool {
+	listener, err := net.Listen("tcp", net.JoinHostPort(bindAddress, strconv.Itoa(port)))
+	if err != nil {
+		return false
+	}
+	listener.Close()
+	return true
+}
+
+// portServing returns true if a connection to port at the bind address
+// succeeds. Unlike portFree it doesn't hold the port, so it can't keep a
+// proxy from binding it.
+func portServing(bindAddress string, port int) bool {
+	if ip := net.ParseIP(bindAddress); bindAddress == "" || ip != nil && ip.IsUnspecified() {
+		bindAddress = "127.0.0.1"
+	}
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(bindAddress, strconv.Itoa(port)), time.Second)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// waitPort waits up to portTimeout for a check of a port to pass, and
+// returns whether it did
+func waitPort(check func() bool) bool {
+	deadline := time.Now().Add(portTimeout)
+	for !check() {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return true
+}
@@ -0,0 +1,74 @@
+package manager
+
+import (
+	"encoding/json"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	mcpgrpc "github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/mcpmock"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+func TestManager_MigratePort(t *testing.T) {
+	manager := createTestManager(t)
+	manager.servers = make(map[string]*server.Server)
+	manager.portMigrations = make(chan mcpgrpc.PortMigration, 10)
+	command := mcpmock.Command("-tools", "")
+	writePort := func(port int) {
+		data, err := json.Marshal(map[string]interface{}{"servers": map[string]interface{}{
+			"mock": map[string]interface{}{"command": command, "port": port},
+		}})
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(manager.config.GetMCPConfigPath(), data, 0644))
+	}
+
+	writePort(8116)
+	require.NoError(t, manager.reloadConfig())
+	require.NoError(t, manager.StartServer("mock"))
+	defer manager.StopServer("mock")
+	srv, err := manager.GetServer("mock")
+	require.NoError(t, err)
+	pid := srv.PID
+
+	// The new port serves before the old one is released
+	writePort(8117)
+	require.NoError(t, manager.reloadConfig())
+	assert.Equal(t, mcpgrpc.PortMigration{Server: "mock", OldPort: 8116, NewPort: 8117, State: mcpgrpc.PortSwitched}, <-manager.portMigrations)
+	assert.Equal(t, mcpgrpc.PortMigration{Server: "mock", OldPort: 8116, NewPort: 8117, State: mcpgrpc.PortReleased}, <-manager.portMigrations)
+
+	srv, err = manager.GetServer("mock")
+	require.NoError(t, err)
+	assert.Equal(t, server.StatusRunning, srv.Status)
+	assert.Equal(t, 8117, srv.Port)
+	assert.NotEqual(t, pid, srv.PID)
+	assert.True(t, portServing("", 8117))
+	assert.True(t, portFree("", 8116))
+
+	state, err := manager.config.LoadState()
+	require.NoError(t, err)
+	require.Contains(t, state.Servers, "mock")
+	assert.Equal(t, srv.PID, state.Servers["mock"].PID, "the PID file follows the new process")
+
+	// A taken port leaves the server where it was
+	listener, err := net.Listen("tcp", "127.0.0.1:8118")
+	require.NoError(t, err)
+	defer listener.Close()
+	pid = srv.PID
+
+	writePort(8118)
+	require.NoError(t, manager.reloadConfig())
+	migration := <-manager.portMigrations
+	assert.Equal(t, mcpgrpc.PortFailed, migration.State)
+	assert.Equal(t, "port 8118 is in use", migration.Reason)
+	assert.Empty(t, manager.portMigrations)
+
+	srv, err = manager.GetServer("mock")
+	require.NoError(t, err)
+	assert.Equal(t, 8117, srv.Port)
+	assert.Equal(t, pid, srv.PID)
+	assert.True(t, portServing("", 8117))
+}
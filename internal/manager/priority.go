@@ -0,0 +1,91 @@
+package manager
+
+import (
+	"fmt"
+	"log"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// ionice class numbers by name
+var ioClasses = map[server.IOClass]string{
+	server.IORealtime:   "1",
+	server.IOBestEffort: "2",
+	server.IOIdle:       "3",
+}
+
+// parsePriority converts a scheduling priority from mcp.json
+func parsePriority(cfg *config.PriorityConfig) (*server.Priority, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	priority := &server.Priority{
+		Nice:    cfg.Nice,
+		IOClass: server.IOClass(cfg.IOClass),
+		IOLevel: cfg.IOLevel,
+	}
+
+	if priority.Nice < -20 || priority.Nice > 19 {
+		return nil, fmt.Errorf("invalid nice %d, expected -20 to 19", priority.Nice)
+	}
+	if priority.IOClass != "" {
+		if _, ok := ioClasses[priority.IOClass]; !ok {
+			return nil, fmt.Errorf("invalid ioClass '%s', expected realtime, best-effort or idle", cfg.IOClass)
+		}
+		if runtime.GOOS != "linux" {
+			return nil, fmt.Errorf("ioClass is only supported on Linux")
+		}
+	}
+	if priority.IOLevel != nil {
+		if priority.IOClass != server.IORealtime && priority.IOClass != server.IOBestEffort {
+			return nil, fmt.Errorf("ioLevel requires the realtime or best-effort ioClass")
+		}
+		if *priority.IOLevel < 0 || *priority.IOLevel > 7 {
+			return nil, fmt.Errorf("invalid ioLevel %d, expected 0 to 7", *priority.IOLevel)
+		}
+	}
+
+	return priority, nil
+}
+
+// parsePriorityConfig returns the server's scheduling priority, ignoring
+// invalid ones
+func parsePriorityConfig(name string, cfg *config.MCPServerConfig) *server.Priority {
+	priority, err := parsePriority(cfg.Priority)
+	if err != nil {
+		log.Printf("Warning: ignoring priority for %s: %v", name, err)
+		return nil
+	}
+	return priority
+}
+
+// withPriority wraps a shell command to run it with a scheduling priority,
+// so that it and every process it spawns inherit it from the start
+func withPriority(command string, priority *server.Priority) string {
+	if command == "" || priority == nil || (priority.Nice == 0 && priority.IOClass == "") {
+		return command
+	}
+
+	args := []string{"exec"}
+	if priority.Nice != 0 {
+		args = append(args, "nice", "-n", strconv.Itoa(priority.Nice))
+	}
+	if priority.IOClass != "" {
+		args = append(args, "ionice", "-c", ioClasses[priority.IOClass])
+		if priority.IOLevel != nil {
+			args = append(args, "-n", strconv.Itoa(*priority.IOLevel))
+		}
+	}
+	args = append(args, "sh", "-c", shellQuote(command))
+	return strings.Join(args, " ")
+}
+
+// shellQuote quotes s as a single sh word
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
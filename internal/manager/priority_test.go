@@ -0,0 +1,66 @@
+package manager
+
+import (
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+func TestParsePriority(t *testing.T) {
+	priority, err := parsePriority(nil)
+	require.NoError(t, err)
+	assert.Nil(t, priority)
+
+	priority, err = parsePriority(&config.PriorityConfig{Nice: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 10, priority.Nice)
+
+	_, err = parsePriority(&config.PriorityConfig{Nice: 20})
+	assert.Error(t, err)
+
+	_, err = parsePriority(&config.PriorityConfig{IOClass: "low"})
+	assert.Error(t, err)
+
+	level := 4
+	_, err = parsePriority(&config.PriorityConfig{IOClass: "idle", IOLevel: &level})
+	assert.Error(t, err)
+
+	if runtime.GOOS == "linux" {
+		priority, err = parsePriority(&config.PriorityConfig{IOClass: "best-effort", IOLevel: &level})
+		require.NoError(t, err)
+		assert.Equal(t, server.IOBestEffort, priority.IOClass)
+
+		level = 8
+		_, err = parsePriority(&config.PriorityConfig{IOClass: "best-effort", IOLevel: &level})
+		assert.Error(t, err)
+	}
+}
+
+func TestWithPriority(t *testing.T) {
+	assert.Equal(t, "npx server", withPriority("npx server", nil))
+	assert.Equal(t, "npx server", withPriority("npx server", &server.Priority{}))
+
+	level := 7
+	command := withPriority("echo 'it''s'", &server.Priority{Nice: 5, IOClass: server.IOBestEffort, IOLevel: &level})
+	assert.Equal(t, `exec nice -n 5 ionice -c 2 -n 7 sh -c 'echo '\''it'\'''\''s'\'''`, command)
+
+	if _, err := exec.LookPath("nice"); err != nil {
+		t.Skip("nice not installed")
+	}
+
+	// The command and its children run with the added niceness
+	base, err := exec.Command("nice").Output()
+	require.NoError(t, err)
+	out, err := exec.Command("sh", "-c", withPriority("sh -c nice", &server.Priority{Nice: 5})).Output()
+	require.NoError(t, err)
+	before, _ := strconv.Atoi(strings.TrimSpace(string(base)))
+	after, _ := strconv.Atoi(strings.TrimSpace(string(out)))
+	assert.Equal(t, min(before+5, 19), after)
+}
@@ -0,0 +1,255 @@
+package manager
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	mcpgrpc "github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// ProfileDirEnv is set to the directory of a server's browser profile, so
+// its command can pass it on as "$MCP_PROFILE_DIR"
+const ProfileDirEnv = "MCP_PROFILE_DIR"
+
+// profilePattern restricts profile and snapshot names to what's safe in
+// paths
+var profilePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// userDataFlags are the flags browser-automation servers take their user
+// data dir with, by package
+var userDataFlags = map[string]string{
+	"@playwright/mcp": "--user-data-dir",
+}
+
+// profilesDir returns the directory holding a server's browser profiles.
+// Copies in fleets keep theirs with the fleet's state.
+func (m *Manager) profilesDir(srv *server.Server) string {
+	if srv.RunID != "" {
+		return filepath.Join(m.fleetsDir(), srv.RunID, "profiles", srv.Name)
+	}
+	return filepath.Join(m.config.GetStateDir(), "profiles", srv.Name)
+}
+
+// snapshotsDir returns the directory holding the snapshots of a profile
+func (m *Manager) snapshotsDir(srv *server.Server, profile string) string {
+	return filepath.Join(m.profilesDir(srv), ".snapshots", profile)
+}
+
+// profileCommand returns the command of a server using its browser
+// profile, if it has one: the profile's directory is created, and passed
+// to known browser-automation servers that aren't given a user data dir
+func (m *Manager) profileCommand(srv *server.Server) (string, error) {
+	if srv.BrowserProfile == "" {
+		return srv.Command, nil
+	}
+	if !profilePattern.MatchString(srv.BrowserProfile) {
+		return "", fmt.Errorf("invalid browser profile '%s', use letters, digits, '.', '_' and '-'", srv.BrowserProfile)
+	}
+
+	dir := filepath.Join(m.profilesDir(srv), srv.BrowserProfile)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create browser profile: %w", err)
+	}
+	for pkg, flag := range userDataFlags {
+		if strings.Contains(srv.Command, pkg) && !strings.Contains(srv.Command, flag) {
+			return srv.Command + " " + flag + " " + shellQuote(dir), nil
+		}
+	}
+	return srv.Command, nil
+}
+
+// BrowserProfiles returns the browser profiles kept for a server, sorted by
+// name, including the one it uses before it first starts
+func (m *Manager) BrowserProfiles(name string) ([]mcpgrpc.BrowserProfile, error) {
+	m.mu.RLock()
+	srv, exists := m.servers[name]
+	if !exists {
+		m.mu.RUnlock()
+		return nil, fmt.Errorf("server '%s' %w", name, server.ErrNotFound)
+	}
+	srvCopy := *srv
+	m.mu.RUnlock()
+
+	names := make(map[string]bool)
+	if srvCopy.BrowserProfile != "" {
+		names[srvCopy.BrowserProfile] = true
+	}
+	for _, dir := range []string{m.profilesDir(&srvCopy), filepath.Join(m.profilesDir(&srvCopy), ".snapshots")} {
+		entries, _ := os.ReadDir(dir)
+		for _, entry := range entries {
+			if entry.IsDir() && profilePattern.MatchString(entry.Name()) {
+				names[entry.Name()] = true
+			}
+		}
+	}
+
+	profiles := make([]mcpgrpc.BrowserProfile, 0, len(names))
+	for profile := range names {
+		info := mcpgrpc.BrowserProfile{
+			Server:   name,
+			Name:     profile,
+			Dir:      filepath.Join(m.profilesDir(&srvCopy), profile),
+			Attached: profile == srvCopy.BrowserProfile,
+		}
+		info.Size, info.Modified = dirUsage(info.Dir)
+		info.Snapshots = m.snapshots(&srvCopy, profile)
+		profiles = append(profiles, info)
+	}
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Name < profiles[j].Name })
+	return profiles, nil
+}
+
+// snapshots returns the snapshots of a profile, oldest first
+func (m *Manager) snapshots(srv *server.Server, profile string) []mcpgrpc.ProfileSnapshot {
+	var snapshots []mcpgrpc.ProfileSnapshot
+	for _, archive := range listArchives(m.snapshotsDir(srv, profile)) {
+		snapshots = append(snapshots, mcpgrpc.ProfileSnapshot{
+			Name:    archive.name,
+			Path:    archive.path,
+			Size:    archive.size,
+			Created: archive.created,
+		})
+	}
+	return snapshots
+}
+
+// dirUsage returns the total size of the files in dir and when the latest
+// changed
+func dirUsage(dir string) (int64, time.Time) {
+	var size int64
+	var modified time.Time
+	filepath.WalkDir(dir, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info, err := entry.Info(); err == nil {
+			if info.Mode().IsRegular() {
+				size += info.Size()
+			}
+			if info.ModTime().After(modified) {
+				modified = info.ModTime()
+			}
+		}
+		return nil
+	})
+	return size, modified
+}
+
+// profileTarget returns a server and the profile an action applies to, the
+// server's own when profile is empty. If stopped is set, the server must
+// not be running with the profile, as the browser would keep writing it.
+func (m *Manager) profileTarget(name, profile, action string, stopped bool) (*server.Server, string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	srv, exists := m.servers[name]
+	if !exists {
+		return nil, "", fmt.Errorf("server '%s' %w", name, server.ErrNotFound)
+	}
+	if profile == "" {
+		profile = srv.BrowserProfile
+	}
+	if profile == "" {
+		return nil, "", fmt.Errorf("server '%s' has no browser profile", name)
+	}
+	if !profilePattern.MatchString(profile) {
+		return nil, "", fmt.Errorf("invalid browser profile '%s'", profile)
+	}
+	if stopped && srv.IsRunning() && profile == srv.BrowserProfile {
+		return nil, "", fmt.Errorf("server '%s' is %w, stop it before %s its profile", name, server.ErrAlreadyRunning, action)
+	}
+	srvCopy := *srv
+	return &srvCopy, profile, nil
+}
+
+// ResetProfile deletes the files of a server's browser profile, so the
+// browser starts afresh. The server must be stopped if it uses the profile.
+func (m *Manager) ResetProfile(name, profile string) error {
+	srv, profile, err := m.profileTarget(name, profile, "resetting", true)
+	if err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(filepath.Join(m.profilesDir(srv), profile)); err != nil {
+		return fmt.Errorf("failed to reset profile '%s': %w", profile, err)
+	}
+	log.Printf("Reset browser profile %s of %s", profile, name)
+	return nil
+}
+
+// SnapshotProfile archives a server's browser profile. Profiles in use are
+// archived as they are on disk.
+func (m *Manager) SnapshotProfile(name, profile string) (*mcpgrpc.ProfileSnapshot, error) {
+	srv, profile, err := m.profileTarget(name, profile, "archiving", false)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(m.profilesDir(srv), profile)
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("profile '%s' of '%s' %w", profile, name, server.ErrNotFound)
+	}
+	snapshotsDir := m.snapshotsDir(srv, profile)
+	if err := os.MkdirAll(snapshotsDir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	created := time.Now()
+	snapshotName := created.Format(archiveLayout)
+	path := filepath.Join(snapshotsDir, snapshotName+archiveExt)
+	if err := archiveDir(dir, path); err != nil {
+		return nil, fmt.Errorf("failed to snapshot profile '%s': %w", profile, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Saved snapshot %s of browser profile %s of %s", snapshotName, profile, name)
+	return &mcpgrpc.ProfileSnapshot{Name: snapshotName, Path: path, Size: info.Size(), Created: created}, nil
+}
+
+// RestoreProfile replaces a server's browser profile with a snapshot of
+// it. The server must be stopped if it uses the profile.
+func (m *Manager) RestoreProfile(name, profile, snapshot string) error {
+	srv, profile, err := m.profileTarget(name, profile, "restoring", true)
+	if err != nil {
+		return err
+	}
+	if !profilePattern.MatchString(snapshot) {
+		return fmt.Errorf("invalid snapshot '%s'", snapshot)
+	}
+
+	path := filepath.Join(m.snapshotsDir(srv, profile), snapshot+archiveExt)
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("snapshot '%s' of profile '%s' %w", snapshot, profile, server.ErrNotFound)
+	}
+
+	// Extract next to the profile, so a broken archive leaves it alone
+	dir := filepath.Join(m.profilesDir(srv), profile)
+	restored := dir + ".restore"
+	os.RemoveAll(restored)
+	if err := os.MkdirAll(restored, 0700); err != nil {
+		return fmt.Errorf("failed to restore snapshot '%s': %w", snapshot, err)
+	}
+	if err := extractArchive(path, func(name string) string { return filepath.Join(restored, name) }); err != nil {
+		os.RemoveAll(restored)
+		return fmt.Errorf("failed to restore snapshot '%s': %w", snapshot, err)
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		os.RemoveAll(restored)
+		return fmt.Errorf("failed to replace profile '%s': %w", profile, err)
+	}
+	if err := os.Rename(restored, dir); err != nil {
+		return fmt.Errorf("failed to replace profile '%s': %w", profile, err)
+	}
+	log.Printf("Restored browser profile %s of %s from %s", profile, name, snapshot)
+	return nil
+}
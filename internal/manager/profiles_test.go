@@ -0,0 +1,107 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+func TestManager_profileCommand(t *testing.T) {
+	m := createTestManager(t)
+	m.env = []string{"PATH=/usr/bin"}
+	srv := m.servers["test1"]
+
+	command, err := m.profileCommand(srv)
+	require.NoError(t, err)
+	assert.Equal(t, "echo test1", command, "servers without a profile run as configured")
+
+	// Known browser servers are given the profile's directory
+	srv.Command = "npx @playwright/mcp@latest"
+	srv.BrowserProfile = "work"
+	dir := filepath.Join(m.config.GetStateDir(), "profiles", "test1", "work")
+	command, err = m.profileCommand(srv)
+	require.NoError(t, err)
+	assert.Equal(t, "npx @playwright/mcp@latest --user-data-dir '"+dir+"'", command)
+	assert.DirExists(t, dir)
+	assert.Equal(t, []string{"PATH=/usr/bin", "MCP_PROFILE_DIR=" + dir}, m.serverEnv(srv))
+
+	// Others pass it on themselves
+	srv.Command = "browser-server --profile $MCP_PROFILE_DIR"
+	command, err = m.profileCommand(srv)
+	require.NoError(t, err)
+	assert.Equal(t, srv.Command, command)
+
+	srv.BrowserProfile = "../work"
+	_, err = m.profileCommand(srv)
+	assert.Error(t, err)
+}
+
+func TestManager_BrowserProfiles(t *testing.T) {
+	m := createTestManager(t)
+	srv := m.servers["test1"]
+	srv.BrowserProfile = "work"
+
+	dir := filepath.Join(m.profilesDir(srv), "work")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "Default"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Default", "Cookies"), []byte("session=1"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "SingletonLock"), []byte("host-1"), 0600))
+
+	// Snapshot, change and restore the profile
+	snapshot, err := m.SnapshotProfile("test1", "")
+	require.NoError(t, err)
+	assert.FileExists(t, snapshot.Path)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Default", "Cookies"), []byte("session=2"), 0600))
+
+	profiles, err := m.BrowserProfiles("test1")
+	require.NoError(t, err)
+	require.Len(t, profiles, 1)
+	assert.Equal(t, "work", profiles[0].Name)
+	assert.True(t, profiles[0].Attached)
+	assert.Equal(t, int64(len("session=2")+len("host-1")), profiles[0].Size)
+	require.Len(t, profiles[0].Snapshots, 1)
+	assert.Equal(t, snapshot.Name, profiles[0].Snapshots[0].Name)
+
+	require.NoError(t, m.RestoreProfile("test1", "work", snapshot.Name))
+	data, err := os.ReadFile(filepath.Join(dir, "Default", "Cookies"))
+	require.NoError(t, err)
+	assert.Equal(t, "session=1", string(data))
+	assert.NoFileExists(t, filepath.Join(dir, "SingletonLock"), "Chromium's locks are left out")
+
+	// Resetting deletes the profile but keeps its snapshots
+	require.NoError(t, m.ResetProfile("test1", ""))
+	assert.NoDirExists(t, dir)
+	profiles, err = m.BrowserProfiles("test1")
+	require.NoError(t, err)
+	require.Len(t, profiles, 1)
+	assert.Len(t, profiles[0].Snapshots, 1)
+
+	err = m.RestoreProfile("test1", "work", "missing")
+	assert.ErrorIs(t, err, server.ErrNotFound)
+	_, err = m.SnapshotProfile("test2", "")
+	assert.ErrorContains(t, err, "has no browser profile")
+	_, err = m.BrowserProfiles("missing")
+	assert.ErrorIs(t, err, server.ErrNotFound)
+}
+
+func TestManager_ResetProfileRunning(t *testing.T) {
+	m := createTestManager(t)
+	srv := m.servers["test1"]
+	srv.BrowserProfile = "work"
+	srv.Status = server.StatusRunning
+
+	// The browser would keep writing its profile
+	err := m.ResetProfile("test1", "")
+	assert.ErrorIs(t, err, server.ErrAlreadyRunning)
+
+	// Other profiles of the server aren't in use
+	assert.NoError(t, m.ResetProfile("test1", "other"))
+
+	require.NoError(t, os.MkdirAll(filepath.Join(m.profilesDir(srv), "work"), 0700))
+	_, err = m.SnapshotProfile("test1", "")
+	assert.NoError(t, err, "profiles in use can be archived")
+}
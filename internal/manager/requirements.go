@@ -0,0 +1,55 @@
+package manager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/server"
+	"github.com/tartavull/mcp-manager/internal/shellenv"
+)
+
+// parseRequirements converts the requirements of a server from mcp.json
+func parseRequirements(cfg *config.RequirementsConfig) *server.Requirements {
+	if cfg == nil || (len(cfg.Devices) == 0 && len(cfg.Mounts) == 0) {
+		return nil
+	}
+	return &server.Requirements{Devices: cfg.Devices, Mounts: cfg.Mounts}
+}
+
+// checkRequirements returns an error naming the first device or path in
+// requires that isn't available
+func checkRequirements(requires *server.Requirements) error {
+	if requires == nil {
+		return nil
+	}
+
+	for _, pattern := range requires.Devices {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid device pattern '%s': %w", pattern, err)
+		}
+		if !hasDevice(matches) {
+			return fmt.Errorf("required device %s is not available", pattern)
+		}
+	}
+
+	for _, path := range requires.Mounts {
+		if _, err := os.Stat(shellenv.ExpandHome(path)); err != nil {
+			return fmt.Errorf("required mount %s is not available: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+// hasDevice returns true if any of paths is a device file
+func hasDevice(paths []string) bool {
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil && info.Mode()&os.ModeDevice != 0 {
+			return true
+		}
+	}
+	return false
+}
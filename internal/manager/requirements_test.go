@@ -0,0 +1,45 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+func TestCheckRequirements(t *testing.T) {
+	assert.Nil(t, parseRequirements(&config.RequirementsConfig{}))
+	assert.NoError(t, checkRequirements(nil))
+
+	dir := t.TempDir()
+	assert.NoError(t, checkRequirements(&server.Requirements{
+		Devices: []string{"/dev/nul*"},
+		Mounts:  []string{dir},
+	}))
+
+	err := checkRequirements(&server.Requirements{Devices: []string{"/dev/nvidia*"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "required device /dev/nvidia* is not available")
+
+	// Regular files aren't devices
+	err = checkRequirements(&server.Requirements{Devices: []string{dir}})
+	assert.Error(t, err)
+
+	err = checkRequirements(&server.Requirements{Mounts: []string{dir + "/models"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "required mount")
+}
+
+func TestManager_StartServer_MissingRequirement(t *testing.T) {
+	manager := createTestManager(t)
+
+	srv, _ := manager.GetServer("test1")
+	srv.Requires = &server.Requirements{Devices: []string{"/dev/nvidia*"}}
+
+	err := manager.StartServer("test1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "server 'test1' can't start: required device /dev/nvidia*")
+	assert.Equal(t, server.StatusStopped, srv.Status)
+}
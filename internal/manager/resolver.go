@@ -0,0 +1,144 @@
+package manager
+
+import (
+	"fmt"
+	"log"
+	"maps"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// System resolver files replaced for servers with overrides
+var (
+	hostsFile  = "/etc/hosts"
+	resolvFile = "/etc/resolv.conf"
+)
+
+// containerRun matches commands starting a container, whose resolver is
+// set with flags instead of a mount namespace
+var containerRun = regexp.MustCompile(`^(\s*(?:exec\s+)?(?:docker|podman|nerdctl)\s+(?:container\s+)?run)\b`)
+
+// parseResolver converts the host and DNS overrides of a server from
+// mcp.json
+func parseResolver(cfg *config.MCPServerConfig) (*server.Resolver, error) {
+	if len(cfg.Hosts) == 0 && len(cfg.DNS) == 0 {
+		return nil, nil
+	}
+
+	for host, ip := range cfg.Hosts {
+		if host == "" || strings.ContainsAny(host, " \t\n") {
+			return nil, fmt.Errorf("invalid host name '%s'", host)
+		}
+		if net.ParseIP(ip) == nil {
+			return nil, fmt.Errorf("invalid address '%s' for host %s", ip, host)
+		}
+	}
+	for _, nameserver := range cfg.DNS {
+		if net.ParseIP(nameserver) == nil {
+			return nil, fmt.Errorf("invalid nameserver '%s'", nameserver)
+		}
+	}
+
+	return &server.Resolver{Hosts: cfg.Hosts, Nameservers: cfg.DNS}, nil
+}
+
+// parseResolverConfig returns the server's resolver overrides, ignoring
+// invalid ones
+func parseResolverConfig(name string, cfg *config.MCPServerConfig) *server.Resolver {
+	resolver, err := parseResolver(cfg)
+	if err != nil {
+		log.Printf("Warning: ignoring hosts and dns for %s: %v", name, err)
+		return nil
+	}
+	return resolver
+}
+
+// resolverDir is where the resolver files of a server are written
+func (m *Manager) resolverDir(name string) string {
+	return filepath.Join(m.config.GetStateDir(), "resolver", name)
+}
+
+// prepareResolver writes the hosts and resolv.conf files of a server with
+// overrides, unless its commands start containers
+func (m *Manager) prepareResolver(srv *server.Server) error {
+	if srv.Resolver == nil || (containerRun.MatchString(srv.Command) &&
+		(srv.ShadowCommand == "" || containerRun.MatchString(srv.ShadowCommand))) {
+		return nil
+	}
+	if runtime.GOOS != "linux" {
+		return fmt.Errorf("hosts and dns overrides need Linux, or a docker or podman run command")
+	}
+
+	dir := m.resolverDir(srv.Name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create resolver directory: %w", err)
+	}
+
+	// The first entry for a name wins, so overrides go first
+	var hosts strings.Builder
+	for _, host := range slices.Sorted(maps.Keys(srv.Resolver.Hosts)) {
+		fmt.Fprintf(&hosts, "%s\t%s\n", srv.Resolver.Hosts[host], host)
+	}
+	system, _ := os.ReadFile(hostsFile)
+	hosts.Write(system)
+	if err := os.WriteFile(filepath.Join(dir, "hosts"), []byte(hosts.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write hosts file: %w", err)
+	}
+
+	if len(srv.Resolver.Nameservers) > 0 {
+		// Keep the search domains and options, replacing the nameservers
+		var resolv strings.Builder
+		for _, nameserver := range srv.Resolver.Nameservers {
+			fmt.Fprintf(&resolv, "nameserver %s\n", nameserver)
+		}
+		system, _ := os.ReadFile(resolvFile)
+		for _, line := range strings.Split(string(system), "\n") {
+			if fields := strings.Fields(line); len(fields) > 0 && fields[0] != "nameserver" {
+				resolv.WriteString(line + "\n")
+			}
+		}
+		if err := os.WriteFile(filepath.Join(dir, "resolv.conf"), []byte(resolv.String()), 0644); err != nil {
+			return fmt.Errorf("failed to write resolv.conf: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// withResolver wraps a shell command to apply a server's host and DNS
+// overrides. Container commands get --add-host and --dns flags; other
+// commands run in a private mount namespace where the files written by
+// prepareResolver are bound over the system ones.
+func (m *Manager) withResolver(command string, srv *server.Server) string {
+	resolver := srv.Resolver
+	if command == "" || resolver == nil {
+		return command
+	}
+
+	if containerRun.MatchString(command) {
+		var flags []string
+		for _, host := range slices.Sorted(maps.Keys(resolver.Hosts)) {
+			flags = append(flags, shellQuote("--add-host="+host+":"+resolver.Hosts[host]))
+		}
+		for _, nameserver := range resolver.Nameservers {
+			flags = append(flags, "--dns="+nameserver)
+		}
+		return containerRun.ReplaceAllString(command, "${1} "+strings.Join(flags, " "))
+	}
+
+	dir := m.resolverDir(srv.Name)
+	mounts := []string{"mount --bind " + shellQuote(filepath.Join(dir, "hosts")) + " " + hostsFile}
+	if len(resolver.Nameservers) > 0 {
+		mounts = append(mounts, "mount --bind "+shellQuote(filepath.Join(dir, "resolv.conf"))+" "+resolvFile)
+	}
+	script := strings.Join(mounts, " && ") + ` && exec sh -c "$1"`
+	return "exec unshare --user --map-current-user --mount sh -c " + shellQuote(script) + " sh " + shellQuote(command)
+}
@@ -0,0 +1,79 @@
+package manager
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+func TestParseResolver(t *testing.T) {
+	resolver, err := parseResolver(&config.MCPServerConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, resolver)
+
+	resolver, err = parseResolver(&config.MCPServerConfig{
+		Hosts: map[string]string{"api.example.com": "10.0.0.5"},
+		DNS:   []string{"10.0.0.53"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.53"}, resolver.Nameservers)
+
+	_, err = parseResolver(&config.MCPServerConfig{Hosts: map[string]string{"api.example.com": "staging"}})
+	assert.Error(t, err)
+
+	_, err = parseResolver(&config.MCPServerConfig{DNS: []string{"dns.example.com"}})
+	assert.Error(t, err)
+}
+
+func TestManager_WithResolver_Container(t *testing.T) {
+	manager := createTestManager(t)
+	srv := manager.servers["test1"]
+	srv.Resolver = &server.Resolver{
+		Hosts:       map[string]string{"api.example.com": "10.0.0.5"},
+		Nameservers: []string{"10.0.0.53"},
+	}
+
+	command := manager.withResolver("docker run --rm -i ghcr.io/github/github-mcp-server", srv)
+	assert.Equal(t, "docker run '--add-host=api.example.com:10.0.0.5' --dns=10.0.0.53 --rm -i ghcr.io/github/github-mcp-server", command)
+
+	// Containers need no files
+	srv.Command = command
+	require.NoError(t, manager.prepareResolver(srv))
+	assert.NoDirExists(t, manager.resolverDir("test1"))
+}
+
+func TestManager_WithResolver_Namespace(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("mount namespaces need Linux")
+	}
+	if err := exec.Command("unshare", "--user", "--map-current-user", "--mount", "true").Run(); err != nil {
+		t.Skip("unprivileged mount namespaces aren't available")
+	}
+
+	dir := t.TempDir()
+	hostsFile = filepath.Join(dir, "hosts")
+	t.Cleanup(func() { hostsFile = "/etc/hosts" })
+	require.NoError(t, os.WriteFile(hostsFile, []byte("127.0.0.1\tlocalhost\n"), 0644))
+
+	manager := createTestManager(t)
+	srv := manager.servers["test1"]
+	srv.Command = "cat " + hostsFile
+	srv.Resolver = &server.Resolver{Hosts: map[string]string{"api.example.com": "10.0.0.5"}}
+	require.NoError(t, manager.prepareResolver(srv))
+
+	// The server sees the overrides first, the system file is untouched
+	out, err := exec.Command("sh", "-c", manager.spawnCommand(srv, srv.Command)).CombinedOutput()
+	require.NoError(t, err, string(out))
+	assert.Equal(t, "10.0.0.5\tapi.example.com\n127.0.0.1\tlocalhost\n", string(out))
+
+	data, err := os.ReadFile(hostsFile)
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1\tlocalhost\n", string(data))
+}
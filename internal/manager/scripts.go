@@ -0,0 +1,326 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+	"go.starlark.net/syntax"
+
+	"github.com/tartavull/mcp-manager/internal/server"
+	"github.com/tartavull/mcp-manager/internal/transcript"
+)
+
+// Events scripts react to, named after the functions handling them
+const (
+	ScriptOnServerStart = "on_server_start"
+	ScriptOnServerStop  = "on_server_stop"
+	ScriptOnServerCrash = "on_server_crash"
+	ScriptOnToolCall    = "on_tool_call"
+)
+
+// scriptExt ends the names of scripts in the script directory
+const scriptExt = ".star"
+
+// scriptMaxSteps bounds the computation steps a load or a handler call
+// may run, so scripts can't hang the manager
+const scriptMaxSteps = 10_000_000
+
+// scriptTimeout bounds the time a load or a handler call may run, e.g.
+// blocked in a manager operation
+const scriptTimeout = 30 * time.Second
+
+// scriptFileOptions are the Starlark dialect of scripts: top-level
+// statements and while loops are allowed, as the steps are bounded
+var scriptFileOptions = syntax.FileOptions{
+	While:           true,
+	TopLevelControl: true,
+	GlobalReassign:  true,
+}
+
+// scriptProgram is a loaded script, its globals set by its top-level
+// statements. Globals aren't frozen, so they persist between calls.
+type scriptProgram struct {
+	file    string
+	globals starlark.StringDict
+}
+
+// scriptEvent is an event waiting for the scripts handling it
+type scriptEvent struct {
+	handler string
+	event   map[string]interface{}
+}
+
+// loadScripts loads the scripts of the script directory in name order,
+// replacing those loaded before. Scripts that fail to load are skipped.
+func (m *Manager) loadScripts() {
+	dir := m.config.GetScriptDir()
+	files, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("Warning: failed to read script directory: %v", err)
+	}
+
+	predeclared := starlark.StringDict{"manager": m.scriptAPI()}
+	var programs []*scriptProgram
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), scriptExt) {
+			continue
+		}
+		src, err := os.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			log.Printf("Warning: failed to read script %s: %v", file.Name(), err)
+			continue
+		}
+		_, prog, err := starlark.SourceProgramOptions(&scriptFileOptions, file.Name(), src, predeclared.Has)
+		if err != nil {
+			log.Printf("Warning: skipping script %v", err)
+			continue
+		}
+		var globals starlark.StringDict
+		err = runScript(file.Name(), func(thread *starlark.Thread) error {
+			globals, err = prog.Init(thread, predeclared)
+			return err
+		})
+		if err != nil {
+			log.Printf("Warning: skipping script %s: %s", file.Name(), scriptError(err))
+			continue
+		}
+		programs = append(programs, &scriptProgram{file: file.Name(), globals: globals})
+		log.Printf("Loaded script %s", file.Name())
+	}
+	sort.Slice(programs, func(i, j int) bool { return programs[i].file < programs[j].file })
+
+	m.scriptsMu.Lock()
+	m.scripts = programs
+	m.scriptsMu.Unlock()
+}
+
+// runScript runs fn on a new thread for a script, bounded by
+// scriptMaxSteps and scriptTimeout
+func runScript(file string, fn func(thread *starlark.Thread) error) error {
+	thread := &starlark.Thread{
+		Name:  file,
+		Print: func(_ *starlark.Thread, msg string) { log.Printf("Script %s: %s", file, msg) },
+	}
+	thread.SetMaxExecutionSteps(scriptMaxSteps)
+	timer := time.AfterFunc(scriptTimeout, func() { thread.Cancel("timed out") })
+	defer timer.Stop()
+	return fn(thread)
+}
+
+// scriptError returns the message of a script error, with the backtrace
+// of evaluation errors so they name the script and line
+func scriptError(err error) string {
+	var evalErr *starlark.EvalError
+	if errors.As(err, &evalErr) {
+		return evalErr.Backtrace()
+	}
+	return err.Error()
+}
+
+// queueScriptEvent passes an event to the scripts handling it, dropping
+// it if they're too busy
+func (m *Manager) queueScriptEvent(handler string, event map[string]interface{}) {
+	if m.scriptEvents == nil {
+		return
+	}
+	select {
+	case m.scriptEvents <- scriptEvent{handler: handler, event: event}:
+	default:
+		log.Printf("Warning: dropping %s event, scripts are busy", handler)
+	}
+}
+
+// runScripts calls the scripts handling each queued event, one event at a
+// time, until the manager is closed
+func (m *Manager) runScripts() {
+	for {
+		select {
+		case e := <-m.scriptEvents:
+			m.dispatchScriptEvent(e)
+		case <-m.stopWatcher:
+			return
+		}
+	}
+}
+
+// dispatchScriptEvent calls the scripts handling an event
+func (m *Manager) dispatchScriptEvent(e scriptEvent) {
+	m.scriptsMu.Lock()
+	defer m.scriptsMu.Unlock()
+
+	for _, prog := range m.scripts {
+		fn, ok := prog.globals[e.handler].(*starlark.Function)
+		if !ok {
+			continue
+		}
+		err := runScript(prog.file, func(thread *starlark.Thread) error {
+			_, err := starlark.Call(thread, fn, starlark.Tuple{toStarlark(e.event)}, nil)
+			return err
+		})
+		if err != nil {
+			log.Printf("Warning: %s failed: %s", e.handler, scriptError(err))
+		}
+	}
+}
+
+// toStarlark converts maps, slices and scalars to Starlark values. Types
+// with no Starlark equivalent are converted to strings.
+func toStarlark(v interface{}) starlark.Value {
+	switch v := v.(type) {
+	case nil:
+		return starlark.None
+	case bool:
+		return starlark.Bool(v)
+	case int:
+		return starlark.MakeInt(v)
+	case int64:
+		return starlark.MakeInt64(v)
+	case string:
+		return starlark.String(v)
+	case []string:
+		elems := make([]starlark.Value, len(v))
+		for i, s := range v {
+			elems[i] = starlark.String(s)
+		}
+		return starlark.NewList(elems)
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		d := starlark.NewDict(len(v))
+		for _, k := range keys {
+			d.SetKey(starlark.String(k), toStarlark(v[k]))
+		}
+		return d
+	default:
+		return starlark.String(fmt.Sprint(v))
+	}
+}
+
+// scriptAPI returns the manager module scripts call back into
+func (m *Manager) scriptAPI() *starlarkstruct.Module {
+	// serverAction wraps a method taking a server name
+	serverAction := func(name string, action func(string) error) *starlark.Builtin {
+		return starlark.NewBuiltin(name, func(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+			var server string
+			if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &server); err != nil {
+				return nil, err
+			}
+			return starlark.None, action(server)
+		})
+	}
+
+	return &starlarkstruct.Module{Name: "manager", Members: starlark.StringDict{
+		"start": serverAction("start", m.StartServer),
+		"stop":  serverAction("stop", m.StopServer),
+		"restart": serverAction("restart", func(name string) error {
+			if err := m.StopServer(name); err != nil && !errors.Is(err, server.ErrNotRunning) {
+				return err
+			}
+			return m.StartServer(name)
+		}),
+		"servers": starlark.NewBuiltin("servers", func(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+			if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 0); err != nil {
+				return nil, err
+			}
+			order, err := m.GetServerOrder()
+			if err != nil {
+				return nil, err
+			}
+			return toStarlark(order), nil
+		}),
+		"status": starlark.NewBuiltin("status", func(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+			var name string
+			if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &name); err != nil {
+				return nil, err
+			}
+			status, err := m.serverStatus(name)
+			if err != nil {
+				return nil, err
+			}
+			return starlark.String(status), nil
+		}),
+		"set_maintenance": starlark.NewBuiltin("set_maintenance", func(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+			var name string
+			var enabled bool
+			if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 2, &name, &enabled); err != nil {
+				return nil, err
+			}
+			return starlark.None, m.SetMaintenance(name, enabled)
+		}),
+	}}
+}
+
+// serverStatus returns the status of a server as a string
+func (m *Manager) serverStatus(name string) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	srv, exists := m.servers[name]
+	if !exists {
+		return "", fmt.Errorf("server '%s' %w", name, server.ErrNotFound)
+	}
+	return string(srv.Status), nil
+}
+
+// callHook returns the proxy hook of a server's calls, adding them to the
+// session transcripts and the call metrics and passing tool calls to
+// scripts
+func (m *Manager) callHook(srv *server.Server) func(string, transcript.Entry) {
+	record := m.recordTranscript(srv)
+	scripts := m.scriptEvents != nil
+	if record == nil && !scripts && m.calls == nil {
+		return nil
+	}
+	name := srv.Name
+	return func(client string, entry transcript.Entry) {
+		m.calls.Observe(name, time.Duration(entry.DurationMs)*time.Millisecond, entry.Error != "")
+		if record != nil {
+			record(client, entry)
+		}
+		if !scripts || entry.Method != "tools/call" {
+			return
+		}
+		m.queueScriptEvent(ScriptOnToolCall, map[string]interface{}{
+			"server":      name,
+			"tool":        entry.Tool,
+			"client":      client,
+			"arguments":   entry.Arguments,
+			"error":       entry.Error,
+			"duration_ms": entry.DurationMs,
+		})
+	}
+}
+
+// watchScripts watches the script directory, if there is one, so scripts
+// are reloaded when they change
+func (m *Manager) watchScripts() {
+	if err := m.watcher.Add(m.config.GetScriptDir()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("Warning: failed to watch script directory: %v", err)
+	}
+}
+
+// scriptsChanged returns true if a file event changes the scripts,
+// watching the script directory when it's created
+func (m *Manager) scriptsChanged(event fsnotify.Event) bool {
+	dir := m.config.GetScriptDir()
+	name := filepath.Clean(event.Name)
+	if name == dir {
+		if event.Op&fsnotify.Create != 0 {
+			m.watchScripts()
+		}
+		return true
+	}
+	return filepath.Dir(name) == dir && strings.HasSuffix(name, scriptExt)
+}
@@ -0,0 +1,101 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tartavull/mcp-manager/internal/server"
+	"github.com/tartavull/mcp-manager/internal/transcript"
+)
+
+// writeScript writes a script to the script directory of a manager
+func writeScript(t *testing.T, m *Manager, name, src string) {
+	t.Helper()
+	dir := m.config.GetScriptDir()
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(src), 0644))
+}
+
+func TestManager_Scripts(t *testing.T) {
+	m := createTestManager(t)
+	m.serverOrder = []string{"test1", "test2"}
+	writeScript(t, m, "crash.star", `
+seen = []
+
+def on_server_crash(event):
+    if event["exit_code"] != 0 and manager.status(event["server"]) == "stopped":
+        manager.set_maintenance(event["server"], True)
+    seen.append(event["server"])
+
+def on_tool_call(event):
+    seen.append(event["tool"] + " " + str(len(manager.servers())))
+`)
+	writeScript(t, m, "broken.star", "x = (")
+	writeScript(t, m, "notes.txt", "ignored")
+
+	m.loadScripts()
+	require.Len(t, m.scripts, 1, "scripts that fail to load are skipped")
+
+	m.dispatchScriptEvent(scriptEvent{handler: ScriptOnServerCrash, event: map[string]interface{}{"server": "test1", "exit_code": 1}})
+	m.dispatchScriptEvent(scriptEvent{handler: ScriptOnServerCrash, event: map[string]interface{}{"server": "test2", "exit_code": 0}})
+	m.dispatchScriptEvent(scriptEvent{handler: ScriptOnServerStart, event: map[string]interface{}{"server": "test1"}})
+	assert.True(t, m.servers["test1"].Maintenance)
+	assert.False(t, m.servers["test2"].Maintenance)
+
+	// Errors are logged, not returned
+	m.dispatchScriptEvent(scriptEvent{handler: ScriptOnServerCrash, event: map[string]interface{}{"server": "missing", "exit_code": 1}})
+
+	m.scriptEvents = make(chan scriptEvent, 1)
+	hook := m.callHook(m.servers["test1"])
+	hook("key:1a2b3c4d", transcript.Entry{Time: time.Now(), Method: "resources/read"})
+	hook("key:1a2b3c4d", transcript.Entry{Time: time.Now(), Method: "tools/call", Tool: "create_issue"})
+	hook("key:1a2b3c4d", transcript.Entry{Time: time.Now(), Method: "tools/call", Tool: "dropped"})
+	require.Len(t, m.scriptEvents, 1, "events are dropped when the queue is full")
+	m.dispatchScriptEvent(<-m.scriptEvents)
+
+	assert.Equal(t, `["test1", "test2", "create_issue 2"]`, m.scripts[0].globals["seen"].String())
+}
+
+func TestManager_ScriptsChanged(t *testing.T) {
+	m := createTestManager(t)
+	dir := m.config.GetScriptDir()
+	assert.True(t, m.scriptsChanged(fsnotify.Event{Name: filepath.Join(dir, "crash.star"), Op: fsnotify.Write}))
+	assert.True(t, m.scriptsChanged(fsnotify.Event{Name: dir, Op: fsnotify.Remove}))
+	assert.False(t, m.scriptsChanged(fsnotify.Event{Name: filepath.Join(dir, "notes.txt"), Op: fsnotify.Write}))
+	assert.False(t, m.scriptsChanged(fsnotify.Event{Name: m.config.GetMCPConfigPath(), Op: fsnotify.Write}))
+}
+
+func TestManager_ScriptsDisabled(t *testing.T) {
+	m := &Manager{}
+	srv := server.NewServer("github", "github-mcp", 4001, "")
+	assert.Nil(t, m.callHook(srv), "no hook without transcripts or scripts")
+	m.queueScriptEvent(ScriptOnServerStop, map[string]interface{}{"server": "github"})
+}
+
+func TestManager_ScriptsBounded(t *testing.T) {
+	m := createTestManager(t)
+	writeScript(t, m, "loop.star", `
+calls = []
+
+def on_server_start(event):
+    calls.append(event["server"])
+    while True:
+        pass
+`)
+	writeScript(t, m, "init.star", `
+while True:
+    pass
+`)
+
+	m.loadScripts()
+	require.Len(t, m.scripts, 1, "scripts whose load runs too long are skipped")
+
+	m.dispatchScriptEvent(scriptEvent{handler: ScriptOnServerStart, event: map[string]interface{}{"server": "test1"}})
+	assert.Equal(t, `["test1"]`, m.scripts[0].globals["calls"].String())
+}
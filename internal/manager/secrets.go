@@ -0,0 +1,46 @@
+package manager
+
+import (
+	"log"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/secrets"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// openSecrets opens the store of the secrets server env references, or
+// returns nil if the settings are invalid, failing the servers that
+// reference secrets
+func openSecrets(cfg *config.Config, settings *config.SecretsConfig) secrets.Store {
+	store, err := secrets.Open(secrets.ConfigOptions(cfg, settings))
+	if err != nil {
+		log.Printf("Warning: secrets unavailable: %v", err)
+		return nil
+	}
+	return store
+}
+
+// resolveEnv returns the configured variables of a server with the
+// secrets they reference. Must be called with m.mu held.
+func (m *Manager) resolveEnv(srv *server.Server) (map[string]string, error) {
+	return secrets.Resolve(m.secrets, srv.Env)
+}
+
+// serverVars returns the configured variables of a server, leaving out
+// those referencing secrets if any can't be read. Must be called with
+// m.mu held.
+func (m *Manager) serverVars(srv *server.Server) map[string]string {
+	env, err := m.resolveEnv(srv)
+	if err == nil {
+		return env
+	}
+
+	log.Printf("Warning: server %s: %v", srv.Name, err)
+	env = make(map[string]string, len(srv.Env))
+	for key, value := range srv.Env {
+		if len(secrets.RefNames(value)) == 0 {
+			env[key] = value
+		}
+	}
+	return env
+}
@@ -0,0 +1,45 @@
+package manager
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/internal/secrets"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+func TestManager_secretEnv(t *testing.T) {
+	manager := createTestManager(t)
+	manager.env = []string{"PATH=/usr/bin"}
+	store, err := secrets.Open(secrets.Options{
+		Backend:    secrets.BackendFile,
+		File:       filepath.Join(t.TempDir(), "secrets.enc"),
+		Passphrase: func(bool) (string, error) { return "passphrase", nil },
+	})
+	require.NoError(t, err)
+	require.NoError(t, store.Set("test1/TOKEN", "ghp_abc"))
+	manager.secrets = store
+
+	srv := manager.servers["test1"]
+	srv.Env = map[string]string{"TOKEN": "secret:test1/TOKEN", "MODE": "ci"}
+	assert.Equal(t, []string{"PATH=/usr/bin", "MODE=ci", "TOKEN=ghp_abc"}, manager.serverEnv(srv))
+	assert.Equal(t, "secret:test1/TOKEN", srv.Env["TOKEN"], "servers keep the reference")
+
+	// References may be part of a value
+	srv.Env["AUTH"] = "Bearer ${secret:test1/TOKEN}"
+	assert.Equal(t, []string{"PATH=/usr/bin", "AUTH=Bearer ghp_abc", "MODE=ci", "TOKEN=ghp_abc"}, manager.serverEnv(srv))
+	delete(srv.Env, "AUTH")
+
+	// Servers whose secrets can't be read don't start, nor get the reference
+	srv.Env["API_KEY"] = "secret:test1/API_KEY"
+	err = manager.StartServer("test1")
+	assert.ErrorContains(t, err, "server 'test1' can't start: secret 'test1/API_KEY' of API_KEY: secret not found")
+	assert.Equal(t, server.StatusStopped, srv.Status)
+	assert.Equal(t, []string{"PATH=/usr/bin", "MODE=ci"}, manager.serverEnv(srv))
+
+	manager.secrets = nil
+	err = manager.StartServer("test1")
+	assert.ErrorContains(t, err, "no secrets store")
+}
@@ -0,0 +1,54 @@
+package manager
+
+import (
+	"log"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// failover keeps a server with a standby up when its process exited: the
+// proxy switches to the standby, which is already initialized, and a new
+// process replaces the one that exited. It returns false, leaving the exit
+// to the restart policy, if no standby is ready or the policy gave up.
+// Must be called with m.mu held.
+func (m *Manager) failover(name string, srv *server.Server, exited *process) bool {
+	proxyServer, exists := m.proxies[name]
+	if !srv.Standby || !exists || !proxyServer.StandbyReady() {
+		return false
+	}
+	if time.Since(exited.started) >= stableRunTime {
+		srv.Restarts = 0
+	}
+	if policy := srv.RestartPolicy; policy != nil && policy.MaxRestarts > 0 && srv.Restarts >= policy.MaxRestarts {
+		return false
+	}
+
+	command, err := m.transportCommand(srv)
+	if err != nil {
+		log.Printf("Warning: failed to fail over %s: %v", name, err)
+		return false
+	}
+	command = m.spawnCommand(srv, command)
+	p, err := m.startProcess(name, command, m.serverEnv(srv))
+	if err != nil {
+		log.Printf("Warning: failed to fail over %s: %v", name, err)
+		return false
+	}
+
+	srv.SetPID(p.cmd.Process.Pid)
+	if err := m.config.SavePID(name, p.cmd.Process.Pid); err != nil {
+		log.Printf("Warning: failed to save PID for %s: %v", name, err)
+	}
+	srv.Restarts++
+	go m.supervise(name, p)
+
+	// Calls in flight finish first, so don't wait with m.mu held
+	go func() {
+		if err := proxyServer.Failover(); err != nil {
+			log.Printf("Warning: failed to switch %s to its standby: %v", name, err)
+		}
+	}()
+	log.Printf("Failed over %s to its standby (restart %d)", name, srv.Restarts)
+	return true
+}
@@ -0,0 +1,344 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os/exec"
+	"syscall"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+	mcpgrpc "github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+const (
+	// defaultRestartBackoff is the delay before the first restart
+	defaultRestartBackoff = time.Second
+
+	// defaultMaxRestartBackoff caps the delay between restarts
+	defaultMaxRestartBackoff = time.Minute
+
+	// stableRunTime is how long a server must stay up for its restart
+	// counter to reset
+	stableRunTime = 5 * time.Minute
+
+	// adoptedPollInterval is how often a server started by an earlier
+	// manager is checked for having exited, as only its parent can wait on it
+	adoptedPollInterval = 2 * time.Second
+)
+
+// process is a server process started by the manager
+type process struct {
+	cmd     *exec.Cmd
+	started time.Time
+}
+
+// startProcess starts the command of a server in its own process group,
+// capturing its output in the server's logs. The manager keeps the pipes of
+// the process until it exits.
+func (m *Manager) startProcess(name, command string, env []string) (*process, error) {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Env = env
+
+	ours, theirs, err := openPipes()
+	if err != nil {
+		return nil, err
+	}
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = theirs[0], theirs[1], theirs[2]
+	err = cmd.Start()
+	closeFiles(theirs)
+	if err != nil {
+		ours.close()
+		return nil, err
+	}
+
+	ours.copyOutput(m.logBuffer(name))
+	m.keepPipes(cmd.Process.Pid, ours)
+	return &process{cmd: cmd, started: time.Now()}, nil
+}
+
+// parseRestartPolicy converts a restart policy from mcp.json, applying
+// defaults
+func parseRestartPolicy(cfg *config.RestartPolicyConfig) (*server.RestartPolicy, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	policy := &server.RestartPolicy{
+		Mode:        server.RestartMode(cfg.Policy),
+		MaxRestarts: cfg.MaxRestarts,
+		Backoff:     defaultRestartBackoff,
+		MaxBackoff:  defaultMaxRestartBackoff,
+	}
+
+	switch policy.Mode {
+	case server.RestartNever, server.RestartOnFailure, server.RestartAlways:
+	default:
+		return nil, fmt.Errorf("invalid restart policy '%s', expected never, on-failure or always", cfg.Policy)
+	}
+	if cfg.MaxRestarts < 0 {
+		return nil, fmt.Errorf("invalid maxRestarts %d", cfg.MaxRestarts)
+	}
+
+	if cfg.Backoff != "" {
+		backoff, err := time.ParseDuration(cfg.Backoff)
+		if err != nil || backoff <= 0 {
+			return nil, fmt.Errorf("invalid restart backoff '%s'", cfg.Backoff)
+		}
+		policy.Backoff = backoff
+	}
+
+	if cfg.MaxBackoff != "" {
+		maxBackoff, err := time.ParseDuration(cfg.MaxBackoff)
+		if err != nil || maxBackoff <= 0 {
+			return nil, fmt.Errorf("invalid restart maxBackoff '%s'", cfg.MaxBackoff)
+		}
+		policy.MaxBackoff = maxBackoff
+	}
+	policy.MaxBackoff = max(policy.MaxBackoff, policy.Backoff)
+
+	return policy, nil
+}
+
+// restartDelay returns how long to wait before restarting a server that
+// exited, or false if the policy doesn't restart it
+func restartDelay(policy *server.RestartPolicy, failed bool, restarts int) (time.Duration, bool) {
+	if policy == nil {
+		return 0, false
+	}
+
+	switch policy.Mode {
+	case server.RestartAlways:
+	case server.RestartOnFailure:
+		if !failed {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+
+	if policy.MaxRestarts > 0 && restarts >= policy.MaxRestarts {
+		return 0, false
+	}
+
+	delay := policy.Backoff
+	for i := 0; i < restarts && delay < policy.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, policy.MaxBackoff), true
+}
+
+// supervise waits for a server process to exit and applies the server's
+// restart policy. Exits of processes the manager stopped or replaced are
+// ignored.
+func (m *Manager) supervise(name string, p *process) {
+	err := p.cmd.Wait()
+	m.releasePipes(p.cmd.Process.Pid)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	srv, exists := m.servers[name]
+	if !exists || srv.PID != p.cmd.Process.Pid {
+		return
+	}
+
+	if err != nil {
+		log.Printf("Server %s exited: %v", name, err)
+	} else {
+		log.Printf("Server %s exited", name)
+	}
+	m.runHookAsync(name, HookOnCrash, fmt.Sprintf("MCP_EXIT_CODE=%d", p.cmd.ProcessState.ExitCode()))
+	m.queueScriptEvent(ScriptOnServerCrash, map[string]interface{}{
+		"server":    name,
+		"exit_code": p.cmd.ProcessState.ExitCode(),
+		"restarts":  srv.Restarts,
+	})
+
+	// Servers with a standby ready stay up
+	if m.failover(name, srv, p) {
+		return
+	}
+	m.exited(name, srv, err != nil, time.Since(p.started))
+}
+
+// watchAdopted detects a server adopted from its PID file exiting. Its
+// parent, an earlier manager, is gone so it can't be waited on: the process
+// is polled instead, and as its exit code is unknown the exit counts as a
+// failure. Servers stopped or restarted since are no longer watched.
+func (m *Manager) watchAdopted(name string, pid int) {
+	defer m.releasePipes(pid)
+	ticker := time.NewTicker(adoptedPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stopWatcher:
+			return
+		case <-ticker.C:
+		}
+
+		m.mu.Lock()
+		srv, exists := m.servers[name]
+		if !exists || srv.PID != pid {
+			m.mu.Unlock()
+			// Reaped once stopped, if handed over by the daemon this
+			// process replaced
+			go syscall.Wait4(pid, nil, 0, nil)
+			return
+		}
+		if processAlive(pid) {
+			m.mu.Unlock()
+			continue
+		}
+
+		log.Printf("Server %s exited", name)
+		m.runHookAsync(name, HookOnCrash, "MCP_EXIT_CODE=-1")
+		m.queueScriptEvent(ScriptOnServerCrash, map[string]interface{}{
+			"server":    name,
+			"exit_code": -1,
+			"restarts":  srv.Restarts,
+		})
+		// How long it ran is unknown, so the restart counter is kept
+		m.exited(name, srv, true, 0)
+		m.mu.Unlock()
+		return
+	}
+}
+
+// processAlive returns true if a process with pid exists. Servers handed
+// over by the daemon this process replaced are its children, and are
+// reaped once they exit.
+func processAlive(pid int) bool {
+	var status syscall.WaitStatus
+	if reaped, err := syscall.Wait4(pid, &status, syscall.WNOHANG, nil); err == nil {
+		return reaped == 0
+	}
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// exited tears down a server whose process exited on its own after running
+// for ran, and restarts it as its restart policy says. Must be called with
+// m.mu held.
+func (m *Manager) exited(name string, srv *server.Server, failed bool, ran time.Duration) {
+	// Tear down what StopServer would, the process is already gone
+	m.stopHealthMonitor(name, srv)
+	if proxyServer, exists := m.proxies[name]; exists {
+		if err := proxyServer.Stop(); err != nil {
+			log.Printf("Warning: failed to stop HTTP proxy for %s: %v", name, err)
+		}
+		delete(m.proxies, name)
+	}
+	if err := m.config.RemovePID(name); err != nil {
+		log.Printf("Warning: failed to remove PID file for %s: %v", name, err)
+	}
+	srv.SetPID(0)
+	srv.SetToolCount(0)
+	m.discovery.Withdraw(name)
+	oldStatus := srv.Status
+	if failed {
+		srv.SetStatus(server.StatusError)
+	} else {
+		srv.SetStatus(server.StatusStopped)
+	}
+	m.emitStatus(name, oldStatus, srv.Status)
+
+	if ran >= stableRunTime {
+		srv.Restarts = 0
+	}
+
+	delay, restart := restartDelay(srv.RestartPolicy, failed, srv.Restarts)
+	if restart && m.inMaintenance(srv) {
+		log.Printf("Not restarting %s: in maintenance", name)
+		return
+	}
+	if !restart {
+		if srv.RestartPolicy != nil && srv.RestartPolicy.Mode != server.RestartNever {
+			log.Printf("Not restarting %s: gave up after %d restarts", name, srv.Restarts)
+		}
+		return
+	}
+
+	log.Printf("Restarting %s in %s (restart %d)", name, delay, srv.Restarts+1)
+	m.scheduleRestart(name, delay)
+}
+
+// scheduleRestart restarts a server after delay, unless it is started or
+// stopped by hand first. Must be called with m.mu held.
+func (m *Manager) scheduleRestart(name string, delay time.Duration) {
+	if m.restartTimers == nil {
+		m.restartTimers = make(map[string]*time.Timer)
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(delay, func() {
+		preStartErr := m.runHook(name, HookPreStart)
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		// Ignore a restart cancelled after the timer fired
+		if m.restartTimers[name] != timer {
+			return
+		}
+		delete(m.restartTimers, name)
+
+		srv, exists := m.servers[name]
+		if !exists || srv.IsRunning() || m.inMaintenance(srv) {
+			return
+		}
+
+		restarts, oldStatus := srv.Restarts+1, srv.Status
+		err := preStartErr
+		if err != nil {
+			err = fmt.Errorf("preStart hook failed: %w", err)
+		} else {
+			err = m.startServer(name)
+		}
+		srv.Restarts = restarts
+		m.emitStatus(name, oldStatus, srv.Status)
+		if err == nil {
+			return
+		}
+
+		// A failed attempt counts as a restart, and is retried with the
+		// next backoff step until the policy gives up
+		delay, retry := restartDelay(srv.RestartPolicy, true, srv.Restarts)
+		if !retry {
+			log.Printf("Failed to restart %s, giving up after %d restarts: %v", name, srv.Restarts, err)
+			return
+		}
+		log.Printf("Failed to restart %s, retrying in %s (restart %d): %v", name, delay, srv.Restarts+1, err)
+		m.scheduleRestart(name, delay)
+	})
+	m.restartTimers[name] = timer
+}
+
+// emitStatus reports a server's status changing on its own, dropping it
+// rather than block supervision when nobody reads them
+func (m *Manager) emitStatus(name string, oldStatus, newStatus server.Status) {
+	if oldStatus == newStatus {
+		return
+	}
+	select {
+	case m.statusChanges <- mcpgrpc.StatusChange{Server: name, Old: oldStatus, New: newStatus}:
+	default:
+	}
+}
+
+// StatusChanges returns the crashes and automatic restarts of servers
+func (m *Manager) StatusChanges() <-chan mcpgrpc.StatusChange {
+	return m.statusChanges
+}
+
+// cancelRestart cancels a pending restart of a server. Must be called with
+// m.mu held.
+func (m *Manager) cancelRestart(name string) {
+	if timer, exists := m.restartTimers[name]; exists {
+		timer.Stop()
+		delete(m.restartTimers, name)
+	}
+}
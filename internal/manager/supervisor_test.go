@@ -0,0 +1,253 @@
+package manager
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/internal/config"
+	mcpgrpc "github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/mcpmock"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+func TestParseRestartPolicy(t *testing.T) {
+	policy, err := parseRestartPolicy(nil)
+	require.NoError(t, err)
+	assert.Nil(t, policy)
+
+	policy, err = parseRestartPolicy(&config.RestartPolicyConfig{Policy: "on-failure", MaxRestarts: 3, Backoff: "2s"})
+	require.NoError(t, err)
+	assert.Equal(t, server.RestartOnFailure, policy.Mode)
+	assert.Equal(t, 3, policy.MaxRestarts)
+	assert.Equal(t, 2*time.Second, policy.Backoff)
+	assert.Equal(t, defaultMaxRestartBackoff, policy.MaxBackoff)
+	assert.Equal(t, "on-failure (max 3)", policy.String())
+
+	for _, cfg := range []*config.RestartPolicyConfig{
+		{Policy: "sometimes"},
+		{Policy: "always", MaxRestarts: -1},
+		{Policy: "always", Backoff: "soon"},
+		{Policy: "always", MaxBackoff: "-1s"},
+	} {
+		_, err := parseRestartPolicy(cfg)
+		assert.Error(t, err, cfg)
+	}
+}
+
+func TestRestartDelay(t *testing.T) {
+	policy := &server.RestartPolicy{Mode: server.RestartOnFailure, MaxRestarts: 5, Backoff: time.Second, MaxBackoff: 5 * time.Second}
+
+	delays := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for restarts, expected := range delays {
+		delay, ok := restartDelay(policy, true, restarts)
+		assert.True(t, ok)
+		assert.Equal(t, expected, delay)
+	}
+
+	_, ok := restartDelay(policy, true, 5)
+	assert.False(t, ok, "gives up after max restarts")
+
+	_, ok = restartDelay(policy, false, 0)
+	assert.False(t, ok, "clean exits aren't failures")
+
+	policy.Mode = server.RestartAlways
+	_, ok = restartDelay(policy, false, 0)
+	assert.True(t, ok)
+
+	_, ok = restartDelay(nil, true, 0)
+	assert.False(t, ok)
+}
+
+func TestManager_Supervise(t *testing.T) {
+	manager := createTestManager(t)
+
+	// Answers the proxy's handshake, then crashes
+	crashing := mcpmock.Command("-tools", "", "-exit-after", "300ms", "-exit-code", "1")
+	srv := server.NewServer("crashy", crashing, 8096, "Crashing server")
+	srv.RestartPolicy = &server.RestartPolicy{Mode: server.RestartOnFailure, MaxRestarts: 2, Backoff: 10 * time.Millisecond, MaxBackoff: 10 * time.Millisecond}
+	manager.servers["crashy"] = srv
+	manager.statusChanges = make(chan mcpgrpc.StatusChange, 10)
+
+	require.NoError(t, manager.StartServer("crashy"))
+
+	// Restarted twice, then left in the error state
+	require.Eventually(t, func() bool {
+		manager.mu.RLock()
+		defer manager.mu.RUnlock()
+		return srv.Restarts == 2 && srv.Status == server.StatusError && len(manager.restartTimers) == 0
+	}, 10*time.Second, 50*time.Millisecond)
+	assert.Equal(t, 0, srv.PID)
+
+	// Each crash and restart is reported, however quick
+	crash := mcpgrpc.StatusChange{Server: "crashy", Old: server.StatusRunning, New: server.StatusError}
+	restart := mcpgrpc.StatusChange{Server: "crashy", Old: server.StatusError, New: server.StatusRunning}
+	var changes []mcpgrpc.StatusChange
+	for len(manager.statusChanges) > 0 {
+		changes = append(changes, <-manager.statusChanges)
+	}
+	assert.Equal(t, []mcpgrpc.StatusChange{crash, restart, crash, restart, crash}, changes)
+
+	// Starting by hand resets the counter
+	srv.Command = mockMCPCommand
+	require.NoError(t, manager.StartServer("crashy"))
+	assert.Equal(t, 0, srv.Restarts)
+	require.NoError(t, manager.StopServer("crashy"))
+
+	// A manual stop isn't restarted
+	time.Sleep(100 * time.Millisecond)
+	manager.mu.RLock()
+	assert.Equal(t, server.StatusStopped, srv.Status)
+	manager.mu.RUnlock()
+}
+
+func TestManager_SuperviseFailedRestart(t *testing.T) {
+	manager := createTestManager(t)
+
+	// The command crashes, fails to spawn or stays up depending on which
+	// marker file exists
+	dir := t.TempDir()
+	crash, fail := filepath.Join(dir, "crash"), filepath.Join(dir, "fail")
+	crashing := mcpmock.Command("-tools", "", "-exit-after", "300ms", "-exit-code", "1")
+	command := fmt.Sprintf(`if [ -f %s ]; then exit 1; elif [ -f %s ]; then %s; else %s; fi`, fail, crash, crashing, mockMCPCommand)
+	srv := server.NewServer("flaky", command, 8098, "Flaky server")
+	srv.RestartPolicy = &server.RestartPolicy{Mode: server.RestartOnFailure, MaxRestarts: 3, Backoff: 200 * time.Millisecond, MaxBackoff: 200 * time.Millisecond}
+	manager.servers["flaky"] = srv
+
+	require.NoError(t, os.WriteFile(crash, nil, 0644))
+	require.NoError(t, manager.StartServer("flaky"))
+
+	// The first restart fails to spawn
+	require.NoError(t, os.WriteFile(fail, nil, 0644))
+	require.Eventually(t, func() bool {
+		manager.mu.RLock()
+		defer manager.mu.RUnlock()
+		return srv.Restarts == 1 && srv.Status == server.StatusError
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// and is retried rather than leaving the server down
+	require.NoError(t, os.Remove(fail))
+	require.NoError(t, os.Remove(crash))
+	require.Eventually(t, func() bool {
+		manager.mu.RLock()
+		defer manager.mu.RUnlock()
+		return srv.Restarts == 2 && srv.Status == server.StatusRunning
+	}, 5*time.Second, 10*time.Millisecond)
+	manager.mu.RLock()
+	assert.Empty(t, manager.restartTimers)
+	manager.mu.RUnlock()
+	require.NoError(t, manager.StopServer("flaky"))
+}
+
+func TestManager_SuperviseMaintenance(t *testing.T) {
+	manager := createTestManager(t)
+
+	crashing := mcpmock.Command("-tools", "", "-exit-after", "300ms", "-exit-code", "1")
+	srv := server.NewServer("crashy", crashing, 8097, "Crashing server")
+	srv.RestartPolicy = &server.RestartPolicy{Mode: server.RestartAlways, Backoff: 10 * time.Millisecond, MaxBackoff: 10 * time.Millisecond}
+	manager.servers["crashy"] = srv
+
+	require.NoError(t, manager.SetMaintenance("crashy", true))
+	require.NoError(t, manager.StartServer("crashy"))
+
+	// The crash isn't followed by a restart
+	require.Eventually(t, func() bool {
+		manager.mu.RLock()
+		defer manager.mu.RUnlock()
+		return srv.Status == server.StatusError
+	}, 5*time.Second, 50*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+
+	manager.mu.RLock()
+	defer manager.mu.RUnlock()
+	assert.Equal(t, server.StatusError, srv.Status)
+	assert.Equal(t, 0, srv.Restarts)
+	assert.Empty(t, manager.restartTimers)
+}
+
+func TestManager_SetMaintenance(t *testing.T) {
+	manager := createTestManager(t)
+
+	require.NoError(t, manager.SetMaintenance("", true))
+	maintenance, err := manager.Maintenance()
+	require.NoError(t, err)
+	assert.True(t, maintenance)
+	assert.True(t, manager.inMaintenance(manager.servers["test1"]))
+
+	require.NoError(t, manager.SetMaintenance("", false))
+	require.NoError(t, manager.SetMaintenance("test1", true))
+	assert.True(t, manager.inMaintenance(manager.servers["test1"]))
+	assert.False(t, manager.inMaintenance(manager.servers["test2"]))
+
+	servers, _, err := manager.GetServers()
+	require.NoError(t, err)
+	assert.True(t, servers["test1"].Maintenance)
+
+	assert.Error(t, manager.SetMaintenance("nonexistent", true))
+}
+
+func TestManager_SuperviseStandby(t *testing.T) {
+	manager := createTestManager(t)
+	srv := server.NewServer("standby", mockMCPCommand, 8113, "Server with a standby")
+	srv.Standby = true
+	manager.servers["standby"] = srv
+
+	require.NoError(t, manager.StartServer("standby"))
+	defer manager.StopServer("standby")
+	manager.mu.RLock()
+	proxyServer := manager.proxies["standby"]
+	pid := srv.PID
+	manager.mu.RUnlock()
+	require.Eventually(t, proxyServer.StandbyReady, 5*time.Second, 10*time.Millisecond)
+
+	// The crash is taken over by the standby instead of stopping the server
+	require.NoError(t, syscall.Kill(-pid, syscall.SIGKILL))
+	require.Eventually(t, func() bool {
+		manager.mu.RLock()
+		defer manager.mu.RUnlock()
+		return srv.Restarts == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	manager.mu.RLock()
+	defer manager.mu.RUnlock()
+	assert.Equal(t, server.StatusRunning, srv.Status)
+	assert.NotEqual(t, pid, srv.PID)
+	assert.Same(t, proxyServer, manager.proxies["standby"])
+}
+
+func TestManager_WatchAdopted(t *testing.T) {
+	manager := createTestManager(t)
+	manager.statusChanges = make(chan mcpgrpc.StatusChange, 10)
+
+	// A server left running by an earlier manager
+	cmd := exec.Command("sleep", "30")
+	require.NoError(t, cmd.Start())
+	pid := cmd.Process.Pid
+	require.NoError(t, manager.config.SavePID("test1", pid))
+	manager.updateServerStatuses()
+	srv, _ := manager.GetServer("test1")
+	require.Equal(t, server.StatusRunning, srv.Status)
+
+	// Reaped here, as the test is its parent
+	require.NoError(t, cmd.Process.Kill())
+	cmd.Wait()
+
+	require.Eventually(t, func() bool {
+		manager.mu.RLock()
+		defer manager.mu.RUnlock()
+		return manager.servers["test1"].Status == server.StatusError
+	}, 5*time.Second, 50*time.Millisecond)
+	assert.Equal(t, mcpgrpc.StatusChange{Server: "test1", Old: server.StatusRunning, New: server.StatusError}, <-manager.statusChanges)
+	_, err := manager.config.LoadPID("test1")
+	assert.Error(t, err, "the PID file is removed")
+	manager.mu.RLock()
+	defer manager.mu.RUnlock()
+	assert.Equal(t, 0, manager.servers["test1"].PID)
+	assert.NotContains(t, manager.proxies, "test1")
+}
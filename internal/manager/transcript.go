@@ -0,0 +1,55 @@
+package manager
+
+import (
+	"fmt"
+
+	"github.com/tartavull/mcp-manager/internal/metrics"
+	"github.com/tartavull/mcp-manager/internal/server"
+	"github.com/tartavull/mcp-manager/internal/transcript"
+)
+
+// recordTranscript returns the proxy hook adding the calls of a server to
+// the session transcripts of their clients, nil when there's no store
+func (m *Manager) recordTranscript(srv *server.Server) func(string, transcript.Entry) {
+	if m.transcripts == nil {
+		return nil
+	}
+	name := srv.Name
+	return func(client string, entry transcript.Entry) {
+		entry.Server = name
+		m.transcripts.Record(client, entry)
+	}
+}
+
+// CallMetrics returns the latency of the calls made through the proxies
+func (m *Manager) CallMetrics() *metrics.Calls {
+	return m.calls
+}
+
+// Sessions returns the session transcripts of the clients without their
+// calls, latest first
+func (m *Manager) Sessions() []transcript.Session {
+	if m.transcripts == nil {
+		return nil
+	}
+	return m.transcripts.Sessions()
+}
+
+// FindSessions returns the session transcripts with calls an agent gave id
+// as request or run ID, holding only those calls, latest first
+func (m *Manager) FindSessions(id string) []transcript.Session {
+	if m.transcripts == nil {
+		return nil
+	}
+	return m.transcripts.Find(id)
+}
+
+// Session returns a session transcript with its calls
+func (m *Manager) Session(id string) (*transcript.Session, error) {
+	if m.transcripts != nil {
+		if session, exists := m.transcripts.Get(id); exists {
+			return session, nil
+		}
+	}
+	return nil, fmt.Errorf("session '%s' %w", id, server.ErrNotFound)
+}
@@ -0,0 +1,51 @@
+package manager
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tartavull/mcp-manager/internal/metrics"
+	"github.com/tartavull/mcp-manager/internal/server"
+	"github.com/tartavull/mcp-manager/internal/transcript"
+)
+
+func TestManager_Sessions(t *testing.T) {
+	m := &Manager{}
+	srv := server.NewServer("github", "github-mcp", 4001, "")
+	assert.Nil(t, m.recordTranscript(srv))
+	assert.Empty(t, m.Sessions())
+
+	m.transcripts = transcript.NewStore()
+	record := m.recordTranscript(srv)
+	require.NotNil(t, record)
+	record("key:1a2b3c4d", transcript.Entry{Time: time.Now(), Method: "tools/call", Tool: "create_issue"})
+
+	sessions := m.Sessions()
+	require.Len(t, sessions, 1)
+	session, err := m.Session(sessions[0].ID)
+	require.NoError(t, err)
+	require.Len(t, session.Entries, 1)
+	assert.Equal(t, "github", session.Entries[0].Server, "calls are attributed to the server")
+
+	_, err = m.Session("missing")
+	assert.True(t, errors.Is(err, server.ErrNotFound))
+}
+
+func TestManager_CallMetrics(t *testing.T) {
+	m := &Manager{calls: metrics.NewCalls()}
+	srv := server.NewServer("github", "github-mcp", 4001, "")
+	hook := m.callHook(srv)
+	require.NotNil(t, hook, "calls are measured without transcripts or scripts")
+	hook("key:1a2b3c4d", transcript.Entry{Time: time.Now(), Method: "tools/call", DurationMs: 30})
+	hook("key:1a2b3c4d", transcript.Entry{Time: time.Now(), Method: "tools/call", DurationMs: 3000, Error: "timeout"})
+
+	var out strings.Builder
+	metrics.Write(&out, nil, nil, m.CallMetrics())
+	assert.Contains(t, out.String(), `mcp_call_duration_seconds_count{server="github"} 2`)
+	assert.Contains(t, out.String(), `mcp_call_errors_total{server="github"} 1`)
+}
@@ -0,0 +1,36 @@
+package manager
+
+import (
+	"fmt"
+
+	"github.com/tartavull/mcp-manager/internal/plugin"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// transportCommand returns the command running a server, asking its
+// transport plugin for it if it has one
+func (m *Manager) transportCommand(srv *server.Server) (string, error) {
+	command, err := m.profileCommand(srv)
+	if err != nil || srv.Transport == nil {
+		return command, err
+	}
+
+	p, err := plugin.Find(m.config.GetPluginDir(), srv.Transport.Plugin, plugin.KindTransport)
+	if err != nil {
+		return "", err
+	}
+	var result plugin.TransportResult
+	params := plugin.TransportParams{Server: srv.Name, Command: command}
+	if err := p.Call(plugin.MethodTransport, srv.Transport.Config, params, &result); err != nil {
+		return "", fmt.Errorf("transport %s: %w", p.Name, err)
+	}
+	if result.Command == "" {
+		return "", fmt.Errorf("transport %s returned no command", p.Name)
+	}
+	return result.Command, nil
+}
+
+// Plugins returns the plugins in the plugin directory, described anew
+func (m *Manager) Plugins() ([]*plugin.Plugin, error) {
+	return plugin.Discover(m.config.GetPluginDir())
+}
@@ -0,0 +1,57 @@
+package manager
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// sandboxPlugin is a transport plugin wrapping commands in its config
+const sandboxPlugin = `#!/bin/sh
+req=$(cat)
+case "$req" in
+*'"describe"'*) echo '{"result":{"kinds":["transport"],"configSchema":{"type":"object"}}}' ;;
+*'"empty"'*) echo '{"result":{}}' ;;
+*) echo '{"result":{"command":"sandbox -- echo test1"}}' ;;
+esac
+`
+
+func TestManager_transportCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are sh scripts")
+	}
+	manager := createTestManager(t)
+	dir := manager.config.GetPluginDir()
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sandbox"), []byte(sandboxPlugin), 0755))
+
+	// Servers without a transport run their command
+	srv := manager.servers["test1"]
+	command, err := manager.transportCommand(srv)
+	require.NoError(t, err)
+	assert.Equal(t, "echo test1", command)
+
+	srv.Transport = &server.Transport{Plugin: "sandbox"}
+	command, err = manager.transportCommand(srv)
+	require.NoError(t, err)
+	assert.Equal(t, "sandbox -- echo test1", command)
+
+	srv.Transport = &server.Transport{Plugin: "sandbox", Config: json.RawMessage(`"empty"`)}
+	_, err = manager.transportCommand(srv)
+	assert.EqualError(t, err, "transport sandbox returned no command")
+
+	srv.Transport = &server.Transport{Plugin: "webrtc"}
+	_, err = manager.transportCommand(srv)
+	assert.ErrorContains(t, err, "plugin webrtc not found")
+
+	plugins, err := manager.Plugins()
+	require.NoError(t, err)
+	require.Len(t, plugins, 1)
+	assert.Equal(t, []string{"transport"}, plugins[0].Kinds)
+}
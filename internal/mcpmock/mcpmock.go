@@ -0,0 +1,249 @@
+// Package mcpmock is a stdio MCP server standing in for real ones, so tests
+// and demos of the manager need neither npm nor python
+package mcpmock
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/transcript"
+)
+
+// HelperEnv marks a process started by Command as the mock server
+const HelperEnv = "MCP_MOCK_SERVER"
+
+// Options configures the mock server
+type Options struct {
+	Tools     []string        // Names of the tools listed
+	Result    json.RawMessage // Result of tools/call, nil echoes the call
+	Stderr    string          // Line written to stderr on start
+	ExitAfter time.Duration   // Exit this long after starting, 0 never
+	ExitCode  int             // Exit code when ExitAfter elapses
+
+	// Replay lists the tools of a recorded server and answers tools/call
+	// with its recorded calls, instead of Tools and Result
+	Replay *transcript.FixtureServer
+}
+
+// request is a JSON-RPC request, or a notification when ID is nil
+type request struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// response is a JSON-RPC response
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcError is the error of a JSON-RPC response
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// codeReplayed is the JSON-RPC error code of replayed and unknown calls
+const codeReplayed = -32603
+
+// Serve answers the requests read from in on out until in is closed.
+// initialize and tools/list are answered as an MCP server would, tools/call
+// with opts.Result or a call of opts.Replay and any other method with an
+// empty result.
+func Serve(in io.Reader, out io.Writer, opts Options) error {
+	decoder := json.NewDecoder(in)
+	encoder := json.NewEncoder(out)
+	for {
+		var req request
+		if err := decoder.Decode(&req); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("failed to read request: %w", err)
+		}
+		if req.ID == nil {
+			continue
+		}
+
+		resp := response{JSONRPC: "2.0", ID: req.ID}
+		if req.Method == "tools/call" && opts.Replay != nil {
+			resp.Result, resp.Error = replay(req, opts.Replay)
+		} else {
+			resp.Result = result(req, opts)
+		}
+		if err := encoder.Encode(resp); err != nil {
+			return fmt.Errorf("failed to write response: %w", err)
+		}
+	}
+}
+
+// result returns the result of a request
+func result(req request, opts Options) interface{} {
+	switch req.Method {
+	case "initialize":
+		return map[string]interface{}{
+			"protocolVersion": "2024-11-05",
+			"capabilities":    map[string]interface{}{"tools": map[string]bool{"listChanged": true}},
+			"serverInfo":      map[string]string{"name": "mock-server", "version": "1.0.0"},
+		}
+	case "tools/list":
+		names := opts.Tools
+		if opts.Replay != nil {
+			names = opts.Replay.Tools
+		}
+		tools := make([]map[string]string, len(names))
+		for i, name := range names {
+			tools[i] = map[string]string{"name": name, "description": "A test tool"}
+		}
+		return map[string]interface{}{"tools": tools}
+	case "tools/call":
+		if opts.Result != nil {
+			return opts.Result
+		}
+		var params struct {
+			Name string `json:"name"`
+		}
+		json.Unmarshal(req.Params, &params)
+		return map[string]interface{}{
+			"content": []map[string]string{{"type": "text", "text": "called " + params.Name}},
+		}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// replay answers a tools/call with the result or error of the recorded
+// call it matches
+func replay(req request, server *transcript.FixtureServer) (interface{}, *rpcError) {
+	var params struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	json.Unmarshal(req.Params, &params)
+
+	call := server.Find(params.Name, params.Arguments)
+	switch {
+	case call == nil:
+		return nil, &rpcError{Code: codeReplayed, Message: fmt.Sprintf("no recorded call of tool '%s'", params.Name)}
+	case call.Error != "":
+		return nil, &rpcError{Code: codeReplayed, Message: call.Error}
+	default:
+		return call.Result, nil
+	}
+}
+
+// loadReplay reads the recorded calls of a server from a fixture file. The
+// server may be left empty if the fixture has only one.
+func loadReplay(path, name string) (*transcript.FixtureServer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+	var fixture transcript.Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("invalid fixture %s: %w", path, err)
+	}
+
+	if name == "" {
+		if len(fixture.Servers) != 1 {
+			return nil, fmt.Errorf("fixture %s records %d servers, pick one with -replay-server", path, len(fixture.Servers))
+		}
+		for only := range fixture.Servers {
+			name = only
+		}
+	}
+	server, ok := fixture.Servers[name]
+	if !ok {
+		return nil, fmt.Errorf("fixture %s has no calls of server '%s'", path, name)
+	}
+	return server, nil
+}
+
+// Main runs the mock server on stdin and stdout, configured by the flags in
+// args
+func Main(args []string) error {
+	fs := flag.NewFlagSet("mock", flag.ContinueOnError)
+	var (
+		tools     = fs.String("tools", "test_tool", "Comma-separated names of the tools listed")
+		result    = fs.String("result", "", "JSON result of tools/call (default: echo the tool name)")
+		stderr    = fs.String("stderr", "", "Line written to stderr on start")
+		exitAfter = fs.Duration("exit-after", 0, "Exit this long after starting, e.g. to simulate a crash")
+		exitCode  = fs.Int("exit-code", 1, "Exit code when -exit-after elapses")
+		replayed  = fs.String("replay", "", "Fixture file whose recorded calls are replayed, see mcp-manager fixture")
+		server    = fs.String("replay-server", "", "Server of the fixture replayed (default: its only server)")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	opts := Options{Stderr: *stderr, ExitAfter: *exitAfter, ExitCode: *exitCode}
+	for _, name := range strings.Split(*tools, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			opts.Tools = append(opts.Tools, name)
+		}
+	}
+	if *result != "" {
+		if !json.Valid([]byte(*result)) {
+			return fmt.Errorf("invalid -result, expected JSON")
+		}
+		opts.Result = json.RawMessage(*result)
+	}
+	if *replayed != "" {
+		recorded, err := loadReplay(*replayed, *server)
+		if err != nil {
+			return err
+		}
+		opts.Replay = recorded
+	}
+
+	if opts.Stderr != "" {
+		fmt.Fprintln(os.Stderr, opts.Stderr)
+	}
+	if opts.ExitAfter > 0 {
+		time.AfterFunc(opts.ExitAfter, func() { os.Exit(opts.ExitCode) })
+	}
+	return Serve(os.Stdin, os.Stdout, opts)
+}
+
+// Command returns a shell command running the mock server with the flags
+// in args, by running the current executable again. Test packages using it
+// call RunIfHelper first thing in TestMain.
+func Command(args ...string) string {
+	executable, err := os.Executable()
+	if err != nil {
+		executable = os.Args[0]
+	}
+
+	words := []string{HelperEnv + "=1", shellQuote(executable)}
+	for _, arg := range args {
+		words = append(words, shellQuote(arg))
+	}
+	return strings.Join(words, " ")
+}
+
+// RunIfHelper runs the mock server and exits if the process was started by
+// Command, and returns otherwise
+func RunIfHelper() {
+	if os.Getenv(HelperEnv) == "" {
+		return
+	}
+	if err := Main(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "mock: %v\n", err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// shellQuote quotes s as a single sh word
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,138 @@
+package mcpmock
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/internal/transcript"
+)
+
+func TestMain(m *testing.M) {
+	RunIfHelper()
+	os.Exit(m.Run())
+}
+
+// serve answers requests, returning the responses
+func serve(t *testing.T, opts Options, requests ...string) []map[string]interface{} {
+	t.Helper()
+
+	var out strings.Builder
+	require.NoError(t, Serve(strings.NewReader(strings.Join(requests, "\n")), &out, opts))
+
+	var responses []map[string]interface{}
+	scanner := bufio.NewScanner(strings.NewReader(out.String()))
+	for scanner.Scan() {
+		var resp map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &resp))
+		responses = append(responses, resp)
+	}
+	return responses
+}
+
+func TestServe(t *testing.T) {
+	responses := serve(t, Options{Tools: []string{"a", "b"}},
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"a"}}`,
+		`{"jsonrpc":"2.0","id":"four","method":"ping"}`,
+	)
+
+	// Notifications are not answered
+	require.Len(t, responses, 4)
+	assert.Equal(t, float64(1), responses[0]["id"])
+	assert.Contains(t, responses[0]["result"], "protocolVersion")
+
+	tools := responses[1]["result"].(map[string]interface{})["tools"].([]interface{})
+	require.Len(t, tools, 2)
+	assert.Equal(t, "b", tools[1].(map[string]interface{})["name"])
+
+	content := responses[2]["result"].(map[string]interface{})["content"].([]interface{})
+	assert.Equal(t, "called a", content[0].(map[string]interface{})["text"])
+
+	assert.Equal(t, "four", responses[3]["id"])
+	assert.Empty(t, responses[3]["result"])
+}
+
+func TestServe_Result(t *testing.T) {
+	responses := serve(t, Options{Result: json.RawMessage(`{"changed":true}`)},
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"a"}}`)
+	require.Len(t, responses, 1)
+	assert.Equal(t, map[string]interface{}{"changed": true}, responses[0]["result"])
+}
+
+func TestServe_Replay(t *testing.T) {
+	recorded := &transcript.FixtureServer{Tools: []string{"search"}, Calls: []transcript.FixtureCall{
+		{Tool: "search", Arguments: json.RawMessage(`{"q":"a"}`), Result: json.RawMessage(`{"found":"a"}`)},
+		{Tool: "search", Arguments: json.RawMessage(`{"q":"b"}`), Error: "rate limited"},
+	}}
+	responses := serve(t, Options{Tools: []string{"ignored"}, Replay: recorded},
+		`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"search","arguments":{"q":"b"}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"search","arguments":{"q":"c"}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"missing"}}`,
+	)
+	require.Len(t, responses, 4)
+
+	tools := responses[0]["result"].(map[string]interface{})["tools"].([]interface{})
+	require.Len(t, tools, 1)
+	assert.Equal(t, "search", tools[0].(map[string]interface{})["name"])
+
+	// Calls get the recorded call with the same arguments, else the first
+	assert.Equal(t, "rate limited", responses[1]["error"].(map[string]interface{})["message"])
+	assert.Equal(t, map[string]interface{}{"found": "a"}, responses[2]["result"])
+	assert.Contains(t, responses[3]["error"].(map[string]interface{})["message"], "no recorded call")
+}
+
+func TestCommand_Replay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"version":1,"servers":{
+		"github":{"tools":["search"],"calls":[{"tool":"search","result":{"hits":3}}]},
+		"gitlab":{"tools":["list"],"calls":[]}}}`), 0o644))
+
+	cmd := exec.Command("sh", "-c", Command("-replay", path, "-replay-server", "github"))
+	cmd.Stdin = strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"search"}}`)
+	out, err := cmd.Output()
+	require.NoError(t, err)
+	assert.Contains(t, string(out), `"result":{"hits":3}`)
+
+	// Fixtures of several servers need one picked
+	assert.ErrorContains(t, Main([]string{"-replay", path}), "-replay-server")
+	assert.ErrorContains(t, Main([]string{"-replay", path, "-replay-server", "jira"}), "no calls of server 'jira'")
+}
+
+func TestCommand(t *testing.T) {
+	cmd := exec.Command("sh", "-c", Command("-tools", "it's", "-stderr", "starting"))
+	cmd.Stdin = strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	require.NoError(t, err)
+
+	assert.Contains(t, string(out), `"name":"it's"`)
+	assert.Equal(t, "starting\n", stderr.String())
+}
+
+func TestCommand_ExitAfter(t *testing.T) {
+	pr, pw, err := os.Pipe()
+	require.NoError(t, err)
+	defer pw.Close()
+
+	cmd := exec.Command("sh", "-c", Command("-exit-after", "50ms", "-exit-code", "3"))
+	cmd.Stdin = pr
+	err = cmd.Run()
+	var exitErr *exec.ExitError
+	require.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, 3, exitErr.ExitCode())
+}
+
+func TestMain_InvalidResult(t *testing.T) {
+	assert.Error(t, Main([]string{"-result", "{"}))
+}
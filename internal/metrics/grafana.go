@@ -0,0 +1,193 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// datasource is the Prometheus data source Grafana asks for on import
+var datasource = map[string]string{"type": "prometheus", "uid": "${DS_PROMETHEUS}"}
+
+// panel is a panel of a Grafana dashboard
+type panel map[string]interface{}
+
+// newPanel returns a panel of kind at x, y in the dashboard's 24 column
+// grid, querying exprs with the legends following each
+func newPanel(id int, kind, title, unit string, x, y, w, h int, exprs ...string) panel {
+	targets := make([]map[string]interface{}, 0, len(exprs)/2)
+	for i := 0; i+1 < len(exprs); i += 2 {
+		targets = append(targets, map[string]interface{}{
+			"datasource":   datasource,
+			"expr":         exprs[i],
+			"legendFormat": exprs[i+1],
+			"refId":        string(rune('A' + i/2)),
+		})
+	}
+	return panel{
+		"id":          id,
+		"type":        kind,
+		"title":       title,
+		"datasource":  datasource,
+		"gridPos":     map[string]int{"x": x, "y": y, "w": w, "h": h},
+		"fieldConfig": map[string]interface{}{"defaults": map[string]interface{}{"unit": unit}, "overrides": []interface{}{}},
+		"targets":     targets,
+	}
+}
+
+// Dashboard returns a Grafana dashboard of the servers' metrics, ready to
+// import, with a variable choosing among servers
+func Dashboard(servers []string) ([]byte, error) {
+	const selected = `server=~"$server"`
+	latency := fmt.Sprintf(`histogram_quantile(0.95, sum by (server, le) (rate(%s_bucket{%s}[5m])))`, CallDuration, selected)
+
+	panels := []panel{
+		newPanel(1, "stat", "Servers up", "none", 0, 0, 6, 4,
+			fmt.Sprintf(`sum(%s{%s})`, ServerUp, selected), ""),
+		newPanel(2, "stat", "Servers down", "none", 6, 0, 6, 4,
+			fmt.Sprintf(`count(%s{%s} == 0) or vector(0)`, ServerUp, selected), ""),
+		newPanel(3, "stat", "Restarts (1h)", "none", 12, 0, 6, 4,
+			fmt.Sprintf(`sum(increase(%s{%s}[1h]))`, ServerRestarts, selected), ""),
+		newPanel(4, "stat", "Call errors (1h)", "none", 18, 0, 6, 4,
+			fmt.Sprintf(`sum(increase(%s{%s}[1h]))`, CallErrors, selected), ""),
+		newPanel(5, "state-timeline", "Running", "none", 0, 4, 24, 8,
+			fmt.Sprintf(`%s{%s}`, ServerUp, selected), "{{server}}"),
+		newPanel(6, "timeseries", "Call latency p95", "s", 0, 12, 12, 8,
+			latency, "{{server}}"),
+		newPanel(7, "timeseries", "Calls", "reqps", 12, 12, 12, 8,
+			fmt.Sprintf(`sum by (server) (rate(%s_count{%s}[5m]))`, CallDuration, selected), "{{server}}"),
+		newPanel(8, "timeseries", "Call errors", "reqps", 0, 20, 12, 8,
+			fmt.Sprintf(`sum by (server) (rate(%s{%s}[5m]))`, CallErrors, selected), "{{server}}"),
+		newPanel(9, "timeseries", "Restarts (15m)", "none", 12, 20, 12, 8,
+			fmt.Sprintf(`increase(%s{%s}[15m])`, ServerRestarts, selected), "{{server}}"),
+		newPanel(10, "timeseries", "Daemon goroutines and open files", "none", 0, 28, 8, 8,
+			DaemonGoroutines, "goroutines", DaemonOpenFDs, "open files"),
+		newPanel(11, "timeseries", "Daemon heap", "bytes", 8, 28, 8, 8,
+			DaemonHeapBytes, "heap"),
+		newPanel(12, "timeseries", "Daemon event queues", "none", 16, 28, 8, 8,
+			DaemonQueueDepth, "{{queue}}", DaemonSubscribers, "subscribers"),
+	}
+
+	options := make([]map[string]interface{}, 0, len(servers))
+	for _, name := range servers {
+		options = append(options, map[string]interface{}{"text": name, "value": name, "selected": false})
+	}
+	dashboard := map[string]interface{}{
+		"__inputs": []map[string]string{{
+			"name": "DS_PROMETHEUS", "label": "Prometheus", "type": "datasource", "pluginId": "prometheus", "pluginName": "Prometheus",
+		}},
+		"uid":           "mcp-manager",
+		"title":         "MCP Manager",
+		"tags":          []string{"mcp-manager"},
+		"timezone":      "browser",
+		"schemaVersion": 39,
+		"refresh":       "30s",
+		"time":          map[string]string{"from": "now-6h", "to": "now"},
+		"panels":        panels,
+		"templating": map[string]interface{}{"list": []map[string]interface{}{{
+			"name":       "server",
+			"label":      "Server",
+			"type":       "custom",
+			"query":      strings.Join(servers, ","),
+			"options":    options,
+			"multi":      true,
+			"includeAll": true,
+			"current":    map[string]interface{}{"text": []string{"All"}, "value": []string{"$__all"}},
+		}}},
+	}
+	return json.MarshalIndent(dashboard, "", "  ")
+}
+
+// AlertOptions are the thresholds of the alert rules
+type AlertOptions struct {
+	DownFor       time.Duration // Time a server stays down before alerting
+	Restarts      int           // Automatic restarts within RestartWindow making a storm
+	RestartWindow time.Duration
+	LatencyP95    time.Duration // p95 call latency breaching for 10m
+}
+
+// DefaultAlertOptions are the thresholds of the alert rules by default
+var DefaultAlertOptions = AlertOptions{
+	DownFor:       5 * time.Minute,
+	Restarts:      3,
+	RestartWindow: 15 * time.Minute,
+	LatencyP95:    2 * time.Second,
+}
+
+// alertRule is a rule of a Prometheus rules file
+type alertRule struct {
+	Alert       string            `yaml:"alert"`
+	Expr        string            `yaml:"expr"`
+	For         string            `yaml:"for,omitempty"`
+	Labels      map[string]string `yaml:"labels"`
+	Annotations map[string]string `yaml:"annotations"`
+}
+
+// AlertRules returns Prometheus alert rules on servers: down for longer
+// than options.DownFor, only for the expected servers as others may be
+// stopped on purpose, restarting in a loop or answering slowly. Rules
+// without servers to watch are left out.
+func AlertRules(servers, expected []string, options AlertOptions) ([]byte, error) {
+	var rules []alertRule
+	if len(expected) > 0 {
+		rules = append(rules, alertRule{
+			Alert:  "MCPServerDown",
+			Expr:   fmt.Sprintf(`%s{%s} == 0`, ServerUp, serverMatcher(expected)),
+			For:    promDuration(options.DownFor),
+			Labels: map[string]string{"severity": "critical"},
+			Annotations: map[string]string{
+				"summary":     "MCP server {{ $labels.server }} is down",
+				"description": fmt.Sprintf("{{ $labels.server }} hasn't been running for %s.", options.DownFor),
+			},
+		})
+	}
+	if len(servers) > 0 {
+		rules = append(rules, alertRule{
+			Alert:  "MCPServerRestartStorm",
+			Expr:   fmt.Sprintf(`increase(%s{%s}[%s]) >= %d`, ServerRestarts, serverMatcher(servers), promDuration(options.RestartWindow), options.Restarts),
+			Labels: map[string]string{"severity": "warning"},
+			Annotations: map[string]string{
+				"summary":     "MCP server {{ $labels.server }} keeps restarting",
+				"description": fmt.Sprintf("{{ $labels.server }} was restarted {{ $value }} times in %s.", options.RestartWindow),
+			},
+		}, alertRule{
+			Alert: "MCPServerSlowCalls",
+			Expr: fmt.Sprintf(`histogram_quantile(0.95, sum by (server, le) (rate(%s_bucket{%s}[5m]))) > %g`,
+				CallDuration, serverMatcher(servers), options.LatencyP95.Seconds()),
+			For:    "10m",
+			Labels: map[string]string{"severity": "warning"},
+			Annotations: map[string]string{
+				"summary":     "MCP server {{ $labels.server }} answers slowly",
+				"description": fmt.Sprintf("95%% of the calls to {{ $labels.server }} took up to {{ $value }}s, over %s.", options.LatencyP95),
+			},
+		})
+	}
+
+	file := map[string]interface{}{"groups": []map[string]interface{}{{"name": "mcp-manager", "rules": rules}}}
+	return yaml.Marshal(file)
+}
+
+// serverMatcher returns the label matcher selecting servers exactly
+func serverMatcher(servers []string) string {
+	quoted := make([]string, len(servers))
+	for i, name := range servers {
+		quoted[i] = regexp.QuoteMeta(name)
+	}
+	return "server=~" + quote(strings.Join(quoted, "|"))
+}
+
+// promDuration returns d in the duration format of Prometheus, e.g. 5m
+func promDuration(d time.Duration) string {
+	switch {
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	default:
+		return fmt.Sprintf("%ds", d/time.Second)
+	}
+}
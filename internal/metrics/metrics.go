@@ -0,0 +1,151 @@
+// Package metrics exports the state of servers and the latency of their
+// calls in the Prometheus text format, and generates Grafana dashboards and
+// Prometheus alert rules querying them
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// Names of the exported metrics, all labeled with the server
+const (
+	ServerUp       = "mcp_server_up"             // 1 while running, else 0
+	ServerRestarts = "mcp_server_restarts_total" // Automatic restarts since the last manual start
+	CallDuration   = "mcp_call_duration_seconds" // Histogram of the calls made through the proxies
+	CallErrors     = "mcp_call_errors_total"     // Calls answered with an error
+)
+
+// buckets are the upper bounds of the call duration histogram, in seconds
+var buckets = []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// histogram is the calls made to a server
+type histogram struct {
+	counts []uint64 // Calls per bucket, not cumulative
+	sum    float64
+	count  uint64
+	errors uint64
+}
+
+// Calls records the latency of the calls made to each server. A nil Calls
+// records nothing.
+type Calls struct {
+	mu      sync.Mutex
+	servers map[string]*histogram
+}
+
+// NewCalls creates an empty record of calls
+func NewCalls() *Calls {
+	return &Calls{servers: make(map[string]*histogram)}
+}
+
+// Observe records a call to server that took d
+func (c *Calls) Observe(server string, d time.Duration, failed bool) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	h, exists := c.servers[server]
+	if !exists {
+		h = &histogram{counts: make([]uint64, len(buckets))}
+		c.servers[server] = h
+	}
+	seconds := d.Seconds()
+	if i := sort.SearchFloat64s(buckets, seconds); i < len(buckets) {
+		h.counts[i]++
+	}
+	h.sum += seconds
+	h.count++
+	if failed {
+		h.errors++
+	}
+}
+
+// Source lists the servers whose state is exported
+type Source interface {
+	GetServers() (map[string]*server.Server, []string, error)
+}
+
+// Handler serves the metrics of source's servers, of the calls made to
+// them and of the daemon itself
+func Handler(source Source, calls *Calls, self *Self) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		servers, order, err := source.GetServers()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		Write(w, servers, order, calls)
+		WriteSelf(w, self)
+	})
+}
+
+// Write writes the metrics of servers, in order, and of the calls made to
+// them in the Prometheus text format
+func Write(w io.Writer, servers map[string]*server.Server, order []string, calls *Calls) {
+	fmt.Fprintf(w, "# HELP %s Whether the server is running.\n# TYPE %s gauge\n", ServerUp, ServerUp)
+	for _, name := range order {
+		if srv, exists := servers[name]; exists {
+			up := 0
+			if srv.IsRunning() {
+				up = 1
+			}
+			fmt.Fprintf(w, "%s{server=%s} %d\n", ServerUp, quote(name), up)
+		}
+	}
+
+	fmt.Fprintf(w, "# HELP %s Automatic restarts of the server since it was last started by hand.\n# TYPE %s counter\n", ServerRestarts, ServerRestarts)
+	for _, name := range order {
+		if srv, exists := servers[name]; exists {
+			fmt.Fprintf(w, "%s{server=%s} %d\n", ServerRestarts, quote(name), srv.Restarts)
+		}
+	}
+
+	if calls == nil {
+		return
+	}
+	calls.mu.Lock()
+	defer calls.mu.Unlock()
+	names := make([]string, 0, len(calls.servers))
+	for name := range calls.servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(w, "# HELP %s Duration of the calls made to the server through its proxy.\n# TYPE %s histogram\n", CallDuration, CallDuration)
+	for _, name := range names {
+		h := calls.servers[name]
+		var cumulative uint64
+		for i, bound := range buckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(w, "%s_bucket{server=%s,le=\"%s\"} %d\n", CallDuration, quote(name), strconv.FormatFloat(bound, 'g', -1, 64), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket{server=%s,le=\"+Inf\"} %d\n", CallDuration, quote(name), h.count)
+		fmt.Fprintf(w, "%s_sum{server=%s} %s\n", CallDuration, quote(name), strconv.FormatFloat(h.sum, 'g', -1, 64))
+		fmt.Fprintf(w, "%s_count{server=%s} %d\n", CallDuration, quote(name), h.count)
+	}
+
+	fmt.Fprintf(w, "# HELP %s Calls to the server answered with an error.\n# TYPE %s counter\n", CallErrors, CallErrors)
+	for _, name := range names {
+		fmt.Fprintf(w, "%s{server=%s} %d\n", CallErrors, quote(name), calls.servers[name].errors)
+	}
+}
+
+// labelEscaper escapes label values in the text format
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// quote returns a label value in quotes
+func quote(value string) string {
+	return `"` + labelEscaper.Replace(value) + `"`
+}
@@ -0,0 +1,144 @@
+package metrics
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/apitest"
+	"github.com/tartavull/mcp-manager/internal/server"
+	"gopkg.in/yaml.v3"
+)
+
+func TestWrite(t *testing.T) {
+	web := server.NewServer("web", "web-server", 4001, "")
+	web.Status = server.StatusRunning
+	web.Restarts = 2
+	db := server.NewServer(`d"b`, "db-server", 4002, "")
+	servers := map[string]*server.Server{"web": web, `d"b`: db}
+	source := apitest.NewManager()
+	require.NoError(t, source.Add(web))
+	require.NoError(t, source.Add(db))
+
+	calls := NewCalls()
+	calls.Observe("web", 20*time.Millisecond, false)
+	calls.Observe("web", 3*time.Second, true)
+	calls.Observe("web", time.Minute, false)
+
+	rec := httptest.NewRecorder()
+	Handler(source, calls, nil).ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	out := rec.Body.String()
+
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/plain")
+	assert.Contains(t, out, "# TYPE mcp_server_up gauge\nmcp_server_up{server=\"web\"} 1\nmcp_server_up{server=\"d\\\"b\"} 0\n")
+	assert.Contains(t, out, `mcp_server_restarts_total{server="web"} 2`)
+	assert.Contains(t, out, `mcp_call_duration_seconds_bucket{server="web",le="0.01"} 0`)
+	assert.Contains(t, out, `mcp_call_duration_seconds_bucket{server="web",le="0.025"} 1`)
+	assert.Contains(t, out, `mcp_call_duration_seconds_bucket{server="web",le="5"} 2`)
+	assert.Contains(t, out, `mcp_call_duration_seconds_bucket{server="web",le="30"} 2`)
+	assert.Contains(t, out, `mcp_call_duration_seconds_bucket{server="web",le="+Inf"} 3`)
+	assert.Contains(t, out, `mcp_call_duration_seconds_sum{server="web"} 63.02`)
+	assert.Contains(t, out, `mcp_call_duration_seconds_count{server="web"} 3`)
+	assert.Contains(t, out, `mcp_call_errors_total{server="web"} 1`)
+
+	// Without calls recorded only the servers are exported
+	var b strings.Builder
+	Write(&b, servers, []string{"web"}, nil)
+	assert.NotContains(t, b.String(), CallDuration)
+	assert.NotContains(t, b.String(), `d\"b`)
+
+	var none *Calls
+	none.Observe("web", time.Second, false)
+}
+
+func TestWriteSelf(t *testing.T) {
+	self := NewSelf()
+	self.Subscribers(func() int { return 2 })
+	self.Queue("status_changes", func() int { return 5 })
+	self.Queue("export", func() int { return 0 })
+
+	var b strings.Builder
+	WriteSelf(&b, self)
+	out := b.String()
+	assert.Contains(t, out, "# TYPE mcp_daemon_goroutines gauge\nmcp_daemon_goroutines ")
+	assert.Contains(t, out, "mcp_daemon_heap_bytes ")
+	assert.Contains(t, out, "mcp_daemon_subscribers 2\n")
+	assert.Contains(t, out, "mcp_daemon_event_queue_depth{queue=\"export\"} 0\nmcp_daemon_event_queue_depth{queue=\"status_changes\"} 5\n")
+	if _, ok := openFDs(); ok {
+		assert.Contains(t, out, "mcp_daemon_open_fds ")
+	}
+
+	// Without components only the runtime is reported
+	b.Reset()
+	WriteSelf(&b, nil)
+	assert.Contains(t, b.String(), DaemonGoroutines)
+	assert.NotContains(t, b.String(), DaemonSubscribers)
+}
+
+func TestDashboard(t *testing.T) {
+	data, err := Dashboard([]string{"web", "db"})
+	require.NoError(t, err)
+
+	var dashboard struct {
+		UID        string `json:"uid"`
+		Panels     []map[string]interface{}
+		Templating struct {
+			List []struct {
+				Name  string `json:"name"`
+				Query string `json:"query"`
+			} `json:"list"`
+		} `json:"templating"`
+	}
+	require.NoError(t, json.Unmarshal(data, &dashboard))
+	assert.Equal(t, "mcp-manager", dashboard.UID)
+	assert.Len(t, dashboard.Panels, 12)
+	require.Len(t, dashboard.Templating.List, 1)
+	assert.Equal(t, "server", dashboard.Templating.List[0].Name)
+	assert.Equal(t, "web,db", dashboard.Templating.List[0].Query)
+	assert.Contains(t, string(data), `mcp_call_duration_seconds_bucket{server=~\"$server\"}`)
+}
+
+func TestAlertRules(t *testing.T) {
+	data, err := AlertRules([]string{"web", "a.b"}, []string{"web"}, DefaultAlertOptions)
+	require.NoError(t, err)
+
+	var file struct {
+		Groups []struct {
+			Name  string      `yaml:"name"`
+			Rules []alertRule `yaml:"rules"`
+		} `yaml:"groups"`
+	}
+	require.NoError(t, yaml.Unmarshal(data, &file))
+	require.Len(t, file.Groups, 1)
+	rules := file.Groups[0].Rules
+	require.Len(t, rules, 3)
+
+	assert.Equal(t, "MCPServerDown", rules[0].Alert)
+	assert.Equal(t, `mcp_server_up{server=~"web"} == 0`, rules[0].Expr)
+	assert.Equal(t, "5m", rules[0].For)
+	assert.Equal(t, "MCPServerRestartStorm", rules[1].Alert)
+	assert.Equal(t, `increase(mcp_server_restarts_total{server=~"web|a\\.b"}[15m]) >= 3`, rules[1].Expr)
+	assert.Equal(t, "MCPServerSlowCalls", rules[2].Alert)
+	assert.Contains(t, rules[2].Expr, "> 2")
+
+	// Without autostart servers nothing is expected to be running
+	options := DefaultAlertOptions
+	options.DownFor = 90 * time.Second
+	data, err = AlertRules([]string{"web"}, nil, options)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "MCPServerDown")
+
+	data, err = AlertRules(nil, nil, options)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "alert:")
+}
+
+func TestPromDuration(t *testing.T) {
+	assert.Equal(t, "2h", promDuration(2*time.Hour))
+	assert.Equal(t, "5m", promDuration(5*time.Minute))
+	assert.Equal(t, "90s", promDuration(90*time.Second))
+}
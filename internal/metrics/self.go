@@ -0,0 +1,93 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// Names of the daemon's own metrics, to diagnose the daemon itself
+const (
+	DaemonGoroutines  = "mcp_daemon_goroutines"
+	DaemonHeapBytes   = "mcp_daemon_heap_bytes"        // Bytes of reachable and not yet freed heap objects
+	DaemonHeapObjects = "mcp_daemon_heap_objects"      // Allocated heap objects
+	DaemonOpenFDs     = "mcp_daemon_open_fds"          // Only where the open files can be listed
+	DaemonSubscribers = "mcp_daemon_subscribers"       // Clients subscribed to events
+	DaemonQueueDepth  = "mcp_daemon_event_queue_depth" // Events waiting in a queue, labeled with the queue
+)
+
+// Self reports the load of the daemon's components: its event subscribers
+// and the events waiting in its queues. A nil Self reports none.
+type Self struct {
+	mu          sync.Mutex
+	subscribers func() int
+	queues      map[string]func() int
+}
+
+// NewSelf creates a report of no components
+func NewSelf() *Self {
+	return &Self{queues: make(map[string]func() int)}
+}
+
+// Subscribers reports the number of event subscribers with count
+func (s *Self) Subscribers(count func() int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subscribers = count
+}
+
+// Queue reports the events waiting in the queue name with depth
+func (s *Self) Queue(name string, depth func() int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queues[name] = depth
+}
+
+// WriteSelf writes the daemon's goroutines, heap and open files, and the
+// load self reports, in the Prometheus text format
+func WriteSelf(w io.Writer, self *Self) {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	fmt.Fprintf(w, "# HELP %s Goroutines of the daemon.\n# TYPE %s gauge\n%s %d\n", DaemonGoroutines, DaemonGoroutines, DaemonGoroutines, runtime.NumGoroutine())
+	fmt.Fprintf(w, "# HELP %s Bytes of allocated heap objects.\n# TYPE %s gauge\n%s %d\n", DaemonHeapBytes, DaemonHeapBytes, DaemonHeapBytes, mem.HeapAlloc)
+	fmt.Fprintf(w, "# HELP %s Allocated heap objects.\n# TYPE %s gauge\n%s %d\n", DaemonHeapObjects, DaemonHeapObjects, DaemonHeapObjects, mem.HeapObjects)
+	if fds, ok := openFDs(); ok {
+		fmt.Fprintf(w, "# HELP %s Open file descriptors of the daemon.\n# TYPE %s gauge\n%s %d\n", DaemonOpenFDs, DaemonOpenFDs, DaemonOpenFDs, fds)
+	}
+
+	if self == nil {
+		return
+	}
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	if self.subscribers != nil {
+		fmt.Fprintf(w, "# HELP %s Clients subscribed to events.\n# TYPE %s gauge\n%s %d\n", DaemonSubscribers, DaemonSubscribers, DaemonSubscribers, self.subscribers())
+	}
+	if len(self.queues) == 0 {
+		return
+	}
+	names := make([]string, 0, len(self.queues))
+	for name := range self.queues {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintf(w, "# HELP %s Events waiting in the queue.\n# TYPE %s gauge\n", DaemonQueueDepth, DaemonQueueDepth)
+	for _, name := range names {
+		fmt.Fprintf(w, "%s{queue=%s} %d\n", DaemonQueueDepth, quote(name), self.queues[name]())
+	}
+}
+
+// openFDs counts the open file descriptors of the process, false where
+// they can't be listed
+func openFDs() (int, bool) {
+	for _, dir := range []string{"/proc/self/fd", "/dev/fd"} {
+		if entries, err := os.ReadDir(dir); err == nil {
+			// Reading the directory opens one more
+			return len(entries) - 1, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,271 @@
+// Package notify shows desktop notifications of server events, filtered by
+// the level chosen for each server so busy daemons don't flood the desktop
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+	mcpgrpc "github.com/tartavull/mcp-manager/internal/grpc"
+	pb "github.com/tartavull/mcp-manager/internal/grpc/pb"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// Levels of notifications, from the quietest
+const (
+	Mute  = "mute"  // No notifications
+	Crash = "crash" // Servers failing
+	Tools = "tools" // Servers failing and their tools changing
+	All   = "all"   // Every event of the server
+)
+
+// Levels lists the notification levels, from the quietest
+var Levels = []string{Mute, Crash, Tools, All}
+
+// ErrUnsupported is returned when notifications can't be shown on this
+// platform
+var ErrUnsupported = errors.New("desktop notifications are not supported on " + runtime.GOOS)
+
+// Desktop shows a desktop notification with notify-send or osascript
+func Desktop(title, message string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %q with title %q", message, title)
+		return exec.Command("osascript", "-e", script).Run()
+	case "linux":
+		return exec.Command("notify-send", title, message).Run()
+	default:
+		return ErrUnsupported
+	}
+}
+
+// Servers lists the servers notifications are shown for
+type Servers interface {
+	GetServers() (map[string]*server.Server, []string, error)
+}
+
+// Notifier shows desktop notifications of the events of servers whose
+// level asks for them. Levels chosen for servers are kept in state.json.
+type Notifier struct {
+	cfg     *config.Config
+	servers Servers
+	level   string // Level of servers without their own
+	show    func(title, message string) error
+
+	mu     sync.Mutex
+	levels map[string]string   // Server to the level chosen for it
+	tools  map[string][]string // Server to the sorted names of its tools last seen
+}
+
+// New creates a notifier showing the events of servers without a level of
+// their own at level, mute if empty
+func New(cfg *config.Config, servers Servers, level string) (*Notifier, error) {
+	if level == "" {
+		level = Mute
+	}
+	if err := validLevel(level); err != nil {
+		return nil, err
+	}
+	state, err := cfg.LoadState()
+	if err != nil {
+		return nil, err
+	}
+
+	n := &Notifier{
+		cfg:     cfg,
+		servers: servers,
+		level:   level,
+		show:    Desktop,
+		levels:  make(map[string]string),
+		tools:   make(map[string][]string),
+	}
+	for name, level := range state.Notifications {
+		if validLevel(level) == nil {
+			n.levels[name] = level
+		}
+	}
+	return n, nil
+}
+
+// validLevel returns an error unless level is one of Levels
+func validLevel(level string) error {
+	if !slices.Contains(Levels, level) {
+		return fmt.Errorf("invalid notification level '%s', expected %s", level, strings.Join(Levels, ", "))
+	}
+	return nil
+}
+
+// Level returns the notification level of a server
+func (n *Notifier) Level(name string) string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if level, exists := n.levels[name]; exists {
+		return level
+	}
+	return n.level
+}
+
+// Preferences returns the default level and the level of every server, in
+// the order of mcp.json
+func (n *Notifier) Preferences() (string, []mcpgrpc.NotificationPreference, error) {
+	_, order, err := n.servers.GetServers()
+	if err != nil {
+		return "", nil, err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	preferences := make([]mcpgrpc.NotificationPreference, len(order))
+	for i, name := range order {
+		level, custom := n.levels[name]
+		if !custom {
+			level = n.level
+		}
+		preferences[i] = mcpgrpc.NotificationPreference{Server: name, Level: level, Custom: custom}
+	}
+	return n.level, preferences, nil
+}
+
+// SetPreference sets the notification level of a server, or resets it to
+// the default when level is empty
+func (n *Notifier) SetPreference(name, level string) error {
+	if level != "" {
+		if err := validLevel(level); err != nil {
+			return err
+		}
+	}
+	servers, _, err := n.servers.GetServers()
+	if err != nil {
+		return err
+	}
+	if _, exists := servers[name]; !exists {
+		return fmt.Errorf("server '%s' %w", name, server.ErrNotFound)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	err = n.cfg.UpdateState(func(state *config.State) {
+		if level == "" {
+			delete(state.Notifications, name)
+			return
+		}
+		if state.Notifications == nil {
+			state.Notifications = make(map[string]string)
+		}
+		state.Notifications[name] = level
+	})
+	if err != nil {
+		return err
+	}
+	if level == "" {
+		delete(n.levels, name)
+	} else {
+		n.levels[name] = level
+	}
+	return nil
+}
+
+// Export shows a notification of an event if the level of its server asks
+// for it
+func (n *Notifier) Export(event *pb.Event) {
+	name, minimum, title, message := n.describe(event)
+	if name == "" || slices.Index(Levels, n.Level(name)) < slices.Index(Levels, minimum) {
+		return
+	}
+	if err := n.show(title, message); err != nil && !errors.Is(err, ErrUnsupported) {
+		log.Printf("Failed to show notification: %v", err)
+	}
+}
+
+// describe returns the server of an event, the level from which it is
+// shown and its notification, or an empty server for events that aren't
+// shown
+func (n *Notifier) describe(event *pb.Event) (name, level, title, message string) {
+	switch payload := event.Payload.(type) {
+	case *pb.Event_ServerStatus:
+		e := payload.ServerStatus
+		if e.NewStatus == pb.ServerStatus_ERROR {
+			return e.ServerName, Crash, "MCP server crashed", fmt.Sprintf("%s stopped with an error", e.ServerName)
+		}
+		return e.ServerName, All, "MCP server " + statusName(e.NewStatus), fmt.Sprintf("%s: %s → %s", e.ServerName, statusName(e.OldStatus), statusName(e.NewStatus))
+
+	case *pb.Event_ToolUpdate:
+		e := payload.ToolUpdate
+		if changes := n.toolChanges(e); changes != "" {
+			return e.ServerName, Tools, "MCP tools changed", fmt.Sprintf("%s: %s", e.ServerName, changes)
+		}
+
+	case *pb.Event_Failover:
+		e := payload.Failover
+		return e.ServerName, All, "MCP server failed over", fmt.Sprintf("%s moved to %s: %s", e.ServerName, e.ToHost, e.Reason)
+
+	case *pb.Event_CircuitBreaker:
+		e := payload.CircuitBreaker
+		return e.ServerName, All, "MCP circuit breaker " + e.State, fmt.Sprintf("%s: %s", e.ServerName, e.Reason)
+
+	case *pb.Event_PortMigration:
+		if e := payload.PortMigration; e.State == "failed" {
+			return e.ServerName, All, "MCP port migration failed", fmt.Sprintf("%s: port %d -> %d: %s", e.ServerName, e.OldPort, e.NewPort, e.Reason)
+		}
+
+	case *pb.Event_Approval:
+		if e := payload.Approval; e.State == "pending" && e.Approval != nil {
+			return e.Approval.ServerName, All, "MCP tool call waiting for approval", fmt.Sprintf("%s: %s", e.Approval.ServerName, e.Approval.Tool)
+		}
+	}
+	return "", "", "", ""
+}
+
+// toolChanges records the tools of a server and describes how they changed
+// since they were last seen, or returns "" if they didn't or weren't seen
+// before. Tool updates are broadcast periodically, changed or not.
+func (n *Notifier) toolChanges(e *pb.ToolUpdateEvent) string {
+	names := make([]string, len(e.Tools))
+	for i, tool := range e.Tools {
+		names[i] = tool.Name
+	}
+	slices.Sort(names)
+
+	n.mu.Lock()
+	previous, seen := n.tools[e.ServerName]
+	n.tools[e.ServerName] = names
+	n.mu.Unlock()
+	if !seen || slices.Equal(previous, names) {
+		return ""
+	}
+
+	var changes []string
+	if added := missing(names, previous); len(added) > 0 {
+		changes = append(changes, "added "+strings.Join(added, ", "))
+	}
+	if removed := missing(previous, names); len(removed) > 0 {
+		changes = append(changes, "removed "+strings.Join(removed, ", "))
+	}
+	return strings.Join(changes, "; ")
+}
+
+// missing returns the names in a that aren't in b
+func missing(a, b []string) []string {
+	var names []string
+	for _, name := range a {
+		if !slices.Contains(b, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// statusName returns the lowercase name of a server status
+func statusName(status pb.ServerStatus) string {
+	return strings.ToLower(status.String())
+}
@@ -0,0 +1,133 @@
+package notify
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/internal/config"
+	mcpgrpc "github.com/tartavull/mcp-manager/internal/grpc"
+	pb "github.com/tartavull/mcp-manager/internal/grpc/pb"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// fakeServers lists a fixed set of servers
+type fakeServers []string
+
+func (f fakeServers) GetServers() (map[string]*server.Server, []string, error) {
+	servers := make(map[string]*server.Server)
+	for _, name := range f {
+		servers[name] = &server.Server{Name: name}
+	}
+	return servers, f, nil
+}
+
+// newTestNotifier creates a notifier recording the notifications it shows
+func newTestNotifier(t *testing.T, cfg *config.Config, level string) (*Notifier, *[]string) {
+	n, err := New(cfg, fakeServers{"alpha", "beta"}, level)
+	require.NoError(t, err)
+	var shown []string
+	n.show = func(title, message string) error {
+		shown = append(shown, title+": "+message)
+		return nil
+	}
+	return n, &shown
+}
+
+// statusEvent returns the event of a server changing status
+func statusEvent(name string, from, to pb.ServerStatus) *pb.Event {
+	return &pb.Event{Type: pb.EventType_SERVER_STATUS, Payload: &pb.Event_ServerStatus{
+		ServerStatus: &pb.ServerStatusEvent{ServerName: name, OldStatus: from, NewStatus: to},
+	}}
+}
+
+// toolEvent returns the event of the tools of a server
+func toolEvent(name string, tools ...string) *pb.Event {
+	e := &pb.ToolUpdateEvent{ServerName: name, ToolCount: int32(len(tools))}
+	for _, tool := range tools {
+		e.Tools = append(e.Tools, &pb.Tool{Name: tool})
+	}
+	return &pb.Event{Type: pb.EventType_TOOL_UPDATE, Payload: &pb.Event_ToolUpdate{ToolUpdate: e}}
+}
+
+func TestNew(t *testing.T) {
+	cfg := &config.Config{ConfigDir: t.TempDir(), StateDir: t.TempDir()}
+	n, err := New(cfg, fakeServers{}, "")
+	require.NoError(t, err)
+	assert.Equal(t, Mute, n.Level("alpha"))
+
+	_, err = New(cfg, fakeServers{}, "loud")
+	assert.EqualError(t, err, "invalid notification level 'loud', expected mute, crash, tools, all")
+}
+
+func TestExport(t *testing.T) {
+	cfg := &config.Config{ConfigDir: t.TempDir(), StateDir: t.TempDir()}
+	n, shown := newTestNotifier(t, cfg, Crash)
+	require.NoError(t, n.SetPreference("beta", Tools))
+
+	// Crashes are shown from the crash level, other status changes only
+	// at all
+	n.Export(statusEvent("alpha", pb.ServerStatus_STOPPED, pb.ServerStatus_RUNNING))
+	n.Export(statusEvent("alpha", pb.ServerStatus_RUNNING, pb.ServerStatus_ERROR))
+	assert.Equal(t, []string{"MCP server crashed: alpha stopped with an error"}, *shown)
+
+	// Tool changes are shown from the tools level, once the tools were seen
+	*shown = nil
+	for _, name := range []string{"alpha", "beta"} {
+		n.Export(toolEvent(name, "read", "write"))
+		n.Export(toolEvent(name, "read", "write"))
+		n.Export(toolEvent(name, "write", "search", "read"))
+		n.Export(toolEvent(name, "list"))
+	}
+	assert.Equal(t, []string{
+		"MCP tools changed: beta: added search",
+		"MCP tools changed: beta: added list; removed read, search, write",
+	}, *shown)
+
+	// Muted servers show nothing, and every event is shown at all
+	*shown = nil
+	require.NoError(t, n.SetPreference("alpha", Mute))
+	require.NoError(t, n.SetPreference("beta", All))
+	n.Export(statusEvent("alpha", pb.ServerStatus_RUNNING, pb.ServerStatus_ERROR))
+	n.Export(statusEvent("beta", pb.ServerStatus_STOPPED, pb.ServerStatus_RUNNING))
+	n.Export(&pb.Event{Type: pb.EventType_CIRCUIT_BREAKER, Payload: &pb.Event_CircuitBreaker{
+		CircuitBreaker: &pb.CircuitBreakerEvent{ServerName: "beta", State: "open", Reason: "timeout"},
+	}})
+	for _, state := range []string{"switched", "failed"} {
+		n.Export(&pb.Event{Type: pb.EventType_PORT_MIGRATION, Payload: &pb.Event_PortMigration{
+			PortMigration: &pb.PortMigrationEvent{ServerName: "beta", OldPort: 8001, NewPort: 8002, State: state, Reason: "port 8002 is in use"},
+		}})
+	}
+	n.Export(&pb.Event{Type: pb.EventType_HEARTBEAT})
+	assert.Equal(t, []string{
+		"MCP server running: beta: stopped → running",
+		"MCP circuit breaker open: beta: timeout",
+		"MCP port migration failed: beta: port 8001 -> 8002: port 8002 is in use",
+	}, *shown)
+}
+
+func TestPreferences(t *testing.T) {
+	cfg := &config.Config{ConfigDir: t.TempDir(), StateDir: t.TempDir()}
+	n, _ := newTestNotifier(t, cfg, Crash)
+
+	require.NoError(t, n.SetPreference("alpha", All))
+	assert.EqualError(t, n.SetPreference("alpha", "loud"), "invalid notification level 'loud', expected mute, crash, tools, all")
+	assert.True(t, errors.Is(n.SetPreference("gamma", All), server.ErrNotFound))
+
+	// Levels chosen for servers are kept in state.json
+	n, _ = newTestNotifier(t, cfg, Crash)
+	level, preferences, err := n.Preferences()
+	require.NoError(t, err)
+	assert.Equal(t, Crash, level)
+	assert.Equal(t, []mcpgrpc.NotificationPreference{
+		{Server: "alpha", Level: All, Custom: true},
+		{Server: "beta", Level: Crash},
+	}, preferences)
+
+	require.NoError(t, n.SetPreference("alpha", ""))
+	state, err := cfg.LoadState()
+	require.NoError(t, err)
+	assert.Empty(t, state.Notifications)
+	assert.Equal(t, Crash, n.Level("alpha"))
+}
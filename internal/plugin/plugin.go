@@ -0,0 +1,296 @@
+// Package plugin runs plugins adding transports, notification sinks and
+// secrets backends to the manager without forking it. Plugins are
+// executables in the plugin directory speaking a JSON protocol: each call
+// runs the plugin with a Request on stdin, and the plugin writes a
+// Response on stdout and exits.
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ProtocolVersion is sent with every request, so plugins can reject
+// requests they don't understand
+const ProtocolVersion = 1
+
+// FilePrefix may start the file names of plugins, e.g.
+// mcp-manager-plugin-gvisor for the gvisor plugin
+const FilePrefix = "mcp-manager-plugin-"
+
+// Kinds of extensions a plugin provides
+const (
+	// KindTransport runs servers differently, e.g. in a gVisor sandbox or
+	// on a remote peer, by replacing their command
+	KindTransport = "transport"
+
+	// KindNotifier receives daemon events, as an eventExport sink
+	KindNotifier = "notifier"
+
+	// KindSecrets stores secrets, as a secrets backend
+	KindSecrets = "secrets"
+)
+
+// Methods called on plugins
+const (
+	MethodDescribe  = "describe"          // Returns the Manifest
+	MethodTransport = "transport.command" // TransportParams to TransportResult
+	MethodNotify    = "notify.publish"    // NotifyParams, no result
+	MethodGet       = "secrets.get"       // SecretParams to SecretResult
+	MethodSet       = "secrets.set"       // SecretParams, no result
+	MethodDelete    = "secrets.delete"    // SecretParams, no result
+	MethodList      = "secrets.list"      // No params, SecretList
+)
+
+// CodeNotFound is the error code of secrets that don't exist
+const CodeNotFound = "not_found"
+
+// ErrNotFound is returned for calls failing with CodeNotFound
+var ErrNotFound = errors.New("not found")
+
+// Timeouts of plugin calls
+var (
+	describeTimeout = 5 * time.Second
+	callTimeout     = 30 * time.Second
+)
+
+// Request is written to the stdin of a plugin
+type Request struct {
+	Protocol int             `json:"protocol"`
+	Method   string          `json:"method"`
+	Config   json.RawMessage `json:"config,omitempty"` // Set where the plugin is used in mcp.json
+	Params   json.RawMessage `json:"params,omitempty"`
+}
+
+// Response is written by a plugin on stdout
+type Response struct {
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+	Code   string          `json:"code,omitempty"` // e.g. CodeNotFound
+}
+
+// Manifest describes a plugin, as returned by describe
+type Manifest struct {
+	Version     string   `json:"version,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Kinds       []string `json:"kinds"`
+
+	// ConfigSchema is the JSON Schema of the config the plugin takes
+	ConfigSchema json.RawMessage `json:"configSchema,omitempty"`
+}
+
+// TransportParams ask a transport plugin for the command running a server
+type TransportParams struct {
+	Server  string `json:"server"`
+	Command string `json:"command"` // Command in mcp.json
+}
+
+// TransportResult is the shell command run instead, speaking MCP over
+// stdio like the original one
+type TransportResult struct {
+	Command string `json:"command"`
+}
+
+// NotifyParams is a daemon event sent to a notifier plugin
+type NotifyParams struct {
+	Topic string          `json:"topic"`
+	Event json.RawMessage `json:"event"` // Event as protojson
+}
+
+// SecretParams name a secret, and give its value to set
+type SecretParams struct {
+	Name  string `json:"name"`
+	Value string `json:"value,omitempty"`
+}
+
+// SecretResult is the value of a secret
+type SecretResult struct {
+	Value string `json:"value"`
+}
+
+// SecretList names the secrets of a secrets plugin
+type SecretList struct {
+	Names []string `json:"names"`
+}
+
+// Plugin is an executable found in the plugin directory
+type Plugin struct {
+	Name string // File name without FilePrefix and extension
+	Path string
+	Manifest
+
+	// Error is why describe failed; such plugins can't be used
+	Error string
+}
+
+// Provides returns true if the plugin provides extensions of a kind
+func (p *Plugin) Provides(kind string) bool {
+	return p.Error == "" && slices.Contains(p.Kinds, kind)
+}
+
+// Discover returns the plugins in dir sorted by name, each described. A
+// missing directory has no plugins.
+func Discover(dir string) ([]*Plugin, error) {
+	files, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugin directory: %w", err)
+	}
+
+	var paths []string
+	for _, file := range files {
+		path := filepath.Join(dir, file.Name())
+		if !strings.HasPrefix(file.Name(), ".") && executable(path) {
+			paths = append(paths, path)
+		}
+	}
+
+	// Plugins are described concurrently, as each may take a while
+	plugins := make([]*Plugin, len(paths))
+	var wg sync.WaitGroup
+	for i, path := range paths {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			plugins[i] = describe(path)
+		}()
+	}
+	wg.Wait()
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins, nil
+}
+
+// Find returns the plugin named name in dir, which must provide kind
+func Find(dir, name, kind string) (*Plugin, error) {
+	if name == "" {
+		return nil, fmt.Errorf("no plugin named")
+	}
+	plugins, err := Discover(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range plugins {
+		if p.Name != name {
+			continue
+		}
+		if p.Error != "" {
+			return nil, fmt.Errorf("plugin %s: %s", name, p.Error)
+		}
+		if !p.Provides(kind) {
+			return nil, fmt.Errorf("plugin %s doesn't provide a %s", name, kind)
+		}
+		return p, nil
+	}
+	return nil, fmt.Errorf("plugin %s not found in %s", name, dir)
+}
+
+// describe runs the describe method of the plugin at path
+func describe(path string) *Plugin {
+	p := &Plugin{Name: nameOf(path), Path: path}
+	ctx, cancel := context.WithTimeout(context.Background(), describeTimeout)
+	defer cancel()
+	if err := p.call(ctx, MethodDescribe, nil, nil, &p.Manifest); err != nil {
+		p.Error = err.Error()
+	} else if len(p.Kinds) == 0 {
+		p.Error = "describes no kinds"
+	}
+	return p
+}
+
+// Call runs a method of the plugin with the config set in mcp.json,
+// decoding its result into result unless it's nil
+func (p *Plugin) Call(method string, config json.RawMessage, params, result interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout)
+	defer cancel()
+	return p.call(ctx, method, config, params, result)
+}
+
+// call runs a method of the plugin
+func (p *Plugin) call(ctx context.Context, method string, config json.RawMessage, params, result interface{}) error {
+	req := Request{Protocol: ProtocolVersion, Method: method, Config: config}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+		req.Params = data
+	}
+	input, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, p.Path)
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	runErr := cmd.Run()
+	if ctx.Err() != nil {
+		return fmt.Errorf("%s timed out", method)
+	}
+
+	var resp Response
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		if runErr != nil {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return fmt.Errorf("%s failed: %s", method, msg)
+			}
+			return fmt.Errorf("%s failed: %w", method, runErr)
+		}
+		return fmt.Errorf("invalid response to %s: %w", method, err)
+	}
+	if resp.Error != "" {
+		if resp.Code == CodeNotFound {
+			return fmt.Errorf("%w: %s", ErrNotFound, resp.Error)
+		}
+		return errors.New(resp.Error)
+	}
+	if runErr != nil {
+		return fmt.Errorf("%s failed: %w", method, runErr)
+	}
+	if result != nil && len(resp.Result) > 0 {
+		if err := json.Unmarshal(resp.Result, result); err != nil {
+			return fmt.Errorf("invalid result of %s: %w", method, err)
+		}
+	}
+	return nil
+}
+
+// nameOf returns the name of the plugin at path
+func nameOf(path string) string {
+	name := filepath.Base(path)
+	if runtime.GOOS == "windows" {
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return strings.TrimPrefix(name, FilePrefix)
+}
+
+// executable returns true if path is a file that can be run
+func executable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".exe", ".bat", ".cmd":
+			return true
+		}
+		return false
+	}
+	return info.Mode()&0111 != 0
+}
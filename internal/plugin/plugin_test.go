@@ -0,0 +1,212 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// helperEnv makes the test binary act as a plugin written with Serve
+const helperEnv = "PLUGIN_TEST_HELPER"
+
+// helperManifest is the manifest of the helper plugin
+var helperManifest = Manifest{
+	Version:      "1.0.0",
+	Description:  "Test plugin",
+	Kinds:        []string{KindTransport, KindSecrets},
+	ConfigSchema: json.RawMessage(`{"type":"object"}`),
+}
+
+func TestMain(m *testing.M) {
+	if os.Getenv(helperEnv) != "" {
+		err := Serve(helperManifest, map[string]Handler{
+			MethodTransport: func(config, params json.RawMessage) (interface{}, error) {
+				var p TransportParams
+				if err := json.Unmarshal(params, &p); err != nil {
+					return nil, err
+				}
+				return TransportResult{Command: fmt.Sprintf("sandbox %s -- %s", config, p.Command)}, nil
+			},
+			MethodGet: func(config, params json.RawMessage) (interface{}, error) {
+				return nil, fmt.Errorf("%w: no such secret", ErrNotFound)
+			},
+		})
+		if err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// writeScript writes an executable sh script into dir
+func writeScript(t *testing.T, dir, name, script string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0755))
+	return path
+}
+
+// writeHelper writes a plugin running the test binary as a Serve plugin
+func writeHelper(t *testing.T, dir, name string) string {
+	t.Helper()
+	exe, err := os.Executable()
+	require.NoError(t, err)
+	return writeScript(t, dir, name, fmt.Sprintf("%s=1 exec '%s' -test.run='^$'", helperEnv, exe))
+}
+
+func skipOnWindows(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are sh scripts")
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	skipOnWindows(t)
+	dir := t.TempDir()
+	writeHelper(t, dir, FilePrefix+"sandbox")
+	writeScript(t, dir, "broken", "echo oops >&2; exit 1")
+	writeScript(t, dir, "empty", `cat >/dev/null; echo '{"result":{"kinds":[]}}'`)
+	writeScript(t, dir, ".hidden", "exit 1")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README"), []byte("not a plugin"), 0644))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "data"), 0755))
+
+	plugins, err := Discover(dir)
+	require.NoError(t, err)
+	require.Len(t, plugins, 3)
+
+	assert.Equal(t, "broken", plugins[0].Name)
+	assert.Equal(t, "describe failed: oops", plugins[0].Error)
+	assert.False(t, plugins[0].Provides(KindTransport))
+
+	assert.Equal(t, "empty", plugins[1].Name)
+	assert.Equal(t, "describes no kinds", plugins[1].Error)
+
+	sandbox := plugins[2]
+	assert.Equal(t, "sandbox", sandbox.Name)
+	assert.Equal(t, filepath.Join(dir, FilePrefix+"sandbox"), sandbox.Path)
+	assert.Empty(t, sandbox.Error)
+	assert.Equal(t, "1.0.0", sandbox.Version)
+	assert.Equal(t, "Test plugin", sandbox.Description)
+	assert.JSONEq(t, `{"type":"object"}`, string(sandbox.ConfigSchema))
+	assert.True(t, sandbox.Provides(KindTransport))
+	assert.True(t, sandbox.Provides(KindSecrets))
+	assert.False(t, sandbox.Provides(KindNotifier))
+
+	// A missing directory has no plugins
+	plugins, err = Discover(filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	assert.Empty(t, plugins)
+}
+
+func TestFind(t *testing.T) {
+	skipOnWindows(t)
+	dir := t.TempDir()
+	writeHelper(t, dir, "sandbox")
+	writeScript(t, dir, "broken", "exit 1")
+
+	p, err := Find(dir, "sandbox", KindTransport)
+	require.NoError(t, err)
+	assert.Equal(t, "sandbox", p.Name)
+
+	_, err = Find(dir, "sandbox", KindNotifier)
+	assert.EqualError(t, err, "plugin sandbox doesn't provide a notifier")
+	_, err = Find(dir, "broken", KindTransport)
+	assert.ErrorContains(t, err, "plugin broken: describe failed")
+	_, err = Find(dir, "missing", KindTransport)
+	assert.EqualError(t, err, fmt.Sprintf("plugin missing not found in %s", dir))
+	_, err = Find(dir, "", KindTransport)
+	assert.Error(t, err)
+}
+
+func TestPlugin_Call(t *testing.T) {
+	skipOnWindows(t)
+	dir := t.TempDir()
+	writeHelper(t, dir, "sandbox")
+	p, err := Find(dir, "sandbox", KindTransport)
+	require.NoError(t, err)
+
+	var result TransportResult
+	params := TransportParams{Server: "github", Command: "npx server"}
+	require.NoError(t, p.Call(MethodTransport, json.RawMessage(`{"image":"node"}`), params, &result))
+	assert.Equal(t, `sandbox {"image":"node"} -- npx server`, result.Command)
+
+	err = p.Call(MethodGet, nil, SecretParams{Name: "missing"}, &SecretResult{})
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.EqualError(t, p.Call(MethodNotify, nil, nil, nil), "unknown method 'notify.publish'")
+}
+
+func TestPlugin_CallErrors(t *testing.T) {
+	skipOnWindows(t)
+	dir := t.TempDir()
+	call := func(script string) error {
+		p := &Plugin{Name: "test", Path: writeScript(t, dir, "test", script)}
+		return p.Call(MethodList, nil, nil, &SecretList{})
+	}
+
+	assert.EqualError(t, call("cat >/dev/null; echo '{\"error\":\"locked\"}'"), "locked")
+	assert.EqualError(t, call("cat >/dev/null; echo garbage"), "invalid response to secrets.list: invalid character 'g' looking for beginning of value")
+	assert.EqualError(t, call("cat >/dev/null; echo vault sealed >&2; exit 3"), "secrets.list failed: vault sealed")
+	assert.EqualError(t, call("cat >/dev/null; exit 3"), "secrets.list failed: exit status 3")
+	assert.EqualError(t, call(`cat >/dev/null; echo '{"result":{"names":"a"}}'`),
+		"invalid result of secrets.list: json: cannot unmarshal string into Go struct field SecretList.names of type []string")
+
+	// Requests are written on stdin
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "request"), nil, 0644))
+	p := &Plugin{Name: "test", Path: writeScript(t, dir, "test",
+		fmt.Sprintf(`cat > '%s'; echo '{}'`, filepath.Join(dir, "request")))}
+	require.NoError(t, p.Call(MethodSet, json.RawMessage(`{"vault":"dev"}`), SecretParams{Name: "a", Value: "1"}, nil))
+	data, err := os.ReadFile(filepath.Join(dir, "request"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"protocol":1,"method":"secrets.set","config":{"vault":"dev"},"params":{"name":"a","value":"1"}}`, string(data))
+
+	// Plugins that hang are killed
+	saved := callTimeout
+	callTimeout = 100 * time.Millisecond
+	defer func() { callTimeout = saved }()
+	assert.EqualError(t, call("exec sleep 10"), "secrets.list timed out")
+}
+
+func TestServe(t *testing.T) {
+	handlers := map[string]Handler{
+		MethodGet: func(config, params json.RawMessage) (interface{}, error) {
+			var p SecretParams
+			require.NoError(t, json.Unmarshal(params, &p))
+			if p.Name != "a" {
+				return nil, ErrNotFound
+			}
+			return SecretResult{Value: string(config)}, nil
+		},
+		MethodDelete: func(config, params json.RawMessage) (interface{}, error) {
+			return nil, nil
+		},
+	}
+	answer := func(request string) string {
+		var out bytes.Buffer
+		require.NoError(t, serve(strings.NewReader(request), &out, helperManifest, handlers))
+		return out.String()
+	}
+
+	assert.JSONEq(t, `{"result":{"version":"1.0.0","description":"Test plugin","kinds":["transport","secrets"],"configSchema":{"type":"object"}}}`,
+		answer(`{"protocol":1,"method":"describe"}`))
+	assert.JSONEq(t, `{"result":{"value":"1"}}`, answer(`{"protocol":1,"method":"secrets.get","config":1,"params":{"name":"a"}}`))
+	assert.JSONEq(t, `{"error":"not found","code":"not_found"}`, answer(`{"protocol":1,"method":"secrets.get","params":{"name":"b"}}`))
+	assert.JSONEq(t, `{}`, answer(`{"protocol":1,"method":"secrets.delete","params":{"name":"a"}}`))
+	assert.JSONEq(t, `{"error":"unknown method 'secrets.list'"}`, answer(`{"protocol":1,"method":"secrets.list"}`))
+	assert.JSONEq(t, `{"error":"unsupported protocol version 2"}`, answer(`{"protocol":2,"method":"describe"}`))
+	assert.Contains(t, answer(`garbage`), "invalid request")
+}
+
+func TestNameOf(t *testing.T) {
+	assert.Equal(t, "gvisor", nameOf("/plugins/"+FilePrefix+"gvisor"))
+	assert.Equal(t, "webrtc", nameOf("/plugins/webrtc"))
+}
@@ -0,0 +1,60 @@
+package plugin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Handler answers a method of a plugin, given the config set in mcp.json
+// and the params of the call
+type Handler func(config, params json.RawMessage) (interface{}, error)
+
+// Serve answers the request on stdin, for plugins written in Go: describe
+// with manifest and the other methods with handlers. Handlers return
+// ErrNotFound for missing secrets.
+func Serve(manifest Manifest, handlers map[string]Handler) error {
+	return serve(os.Stdin, os.Stdout, manifest, handlers)
+}
+
+// serve answers the request read from in on out
+func serve(in io.Reader, out io.Writer, manifest Manifest, handlers map[string]Handler) error {
+	var req Request
+	var resp Response
+	if err := json.NewDecoder(in).Decode(&req); err != nil {
+		resp.Error = fmt.Sprintf("invalid request: %v", err)
+		return write(out, resp)
+	}
+
+	var result interface{}
+	var err error
+	switch handler, ok := handlers[req.Method]; {
+	case req.Protocol > ProtocolVersion:
+		err = fmt.Errorf("unsupported protocol version %d", req.Protocol)
+	case req.Method == MethodDescribe:
+		result = manifest
+	case !ok:
+		err = fmt.Errorf("unknown method '%s'", req.Method)
+	default:
+		result, err = handler(req.Config, req.Params)
+	}
+
+	if err != nil {
+		resp.Error = err.Error()
+		if errors.Is(err, ErrNotFound) {
+			resp.Code = CodeNotFound
+		}
+	} else if result != nil {
+		if resp.Result, err = json.Marshal(result); err != nil {
+			resp.Error = fmt.Sprintf("failed to encode result: %v", err)
+		}
+	}
+	return write(out, resp)
+}
+
+// write writes a response
+func write(out io.Writer, resp Response) error {
+	return json.NewEncoder(out).Encode(resp)
+}
@@ -0,0 +1,192 @@
+package power
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/notify"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// HeavyTag marks the servers stopped while the battery is low
+const HeavyTag = "heavy"
+
+const (
+	// defaultThreshold stops heavy servers any time the machine is on battery
+	defaultThreshold = 100
+
+	// defaultInterval is the time between battery checks
+	defaultInterval = time.Minute
+)
+
+// Controller starts and stops the servers a policy applies to
+type Controller interface {
+	GetServers() (map[string]*server.Server, []string, error)
+	StartServer(name string) error
+	StopServer(name string) error
+	Maintenance() (bool, error)
+}
+
+// Policy stops the heavy servers when the machine is on battery at or below
+// a threshold, and starts them again on AC power. It only acts when the
+// state changes, so servers started by hand on battery keep running.
+type Policy struct {
+	controller Controller
+	threshold  int
+	interval   time.Duration
+	notify     bool
+
+	read     func() (State, error)
+	notifier func(title, message string) error
+
+	low     bool     // The battery was low at the last check
+	stopped []string // Servers stopped by the policy, to resume
+}
+
+// New creates a policy from the power settings of mcp.json
+func New(controller Controller, cfg *config.PowerConfig) (*Policy, error) {
+	p := &Policy{
+		controller: controller,
+		threshold:  defaultThreshold,
+		interval:   defaultInterval,
+		notify:     cfg.Notify,
+		read:       Read,
+		notifier:   notify.Desktop,
+	}
+
+	if cfg.Threshold != 0 {
+		if cfg.Threshold < 0 || cfg.Threshold > 100 {
+			return nil, fmt.Errorf("invalid power threshold %d, expected 1 to 100", cfg.Threshold)
+		}
+		p.threshold = cfg.Threshold
+	}
+	if cfg.Interval != "" {
+		interval, err := time.ParseDuration(cfg.Interval)
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("invalid power interval '%s'", cfg.Interval)
+		}
+		p.interval = interval
+	}
+
+	return p, nil
+}
+
+// Run checks the battery until ctx is done
+func (p *Policy) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.check(); errors.Is(err, ErrUnsupported) {
+			log.Printf("Power policy disabled: %v", err)
+			return
+		} else if err != nil {
+			log.Printf("Failed to check battery: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check reads the power state and applies the policy
+func (p *Policy) check() error {
+	state, err := p.read()
+	if err != nil {
+		return err
+	}
+	p.apply(state)
+	return nil
+}
+
+// apply stops or resumes servers when the battery becomes low or AC power
+// returns
+func (p *Policy) apply(state State) {
+	low := state.OnBattery && state.Percent <= p.threshold
+	if low == p.low {
+		return
+	}
+	p.low = low
+
+	if low {
+		p.stopHeavy(state)
+	} else {
+		p.resume(state)
+	}
+}
+
+// stopHeavy stops the running heavy servers that aren't in maintenance
+func (p *Policy) stopHeavy(state State) {
+	if on, _ := p.controller.Maintenance(); on {
+		return
+	}
+	servers, order, err := p.controller.GetServers()
+	if err != nil {
+		log.Printf("Failed to list servers for power policy: %v", err)
+		return
+	}
+
+	for _, name := range order {
+		srv, exists := servers[name]
+		if !exists || !srv.IsRunning() || !srv.HasTag(HeavyTag) || srv.Maintenance {
+			continue
+		}
+		if err := p.controller.StopServer(name); err != nil {
+			log.Printf("Failed to stop %s on battery: %v", name, err)
+			continue
+		}
+		p.stopped = append(p.stopped, name)
+	}
+
+	if len(p.stopped) > 0 {
+		p.report("Stopped heavy MCP servers", fmt.Sprintf("%s: %s", state, strings.Join(p.stopped, ", ")))
+	}
+}
+
+// resume starts the servers stopped by the policy that are still stopped
+func (p *Policy) resume(state State) {
+	stopped := p.stopped
+	p.stopped = nil
+
+	servers, _, err := p.controller.GetServers()
+	if err != nil {
+		log.Printf("Failed to list servers for power policy: %v", err)
+		return
+	}
+
+	var resumed []string
+	for _, name := range stopped {
+		if srv, exists := servers[name]; !exists || srv.Status != server.StatusStopped {
+			continue
+		}
+		if err := p.controller.StartServer(name); err != nil {
+			log.Printf("Failed to resume %s: %v", name, err)
+			continue
+		}
+		resumed = append(resumed, name)
+	}
+
+	if len(resumed) > 0 {
+		p.report("Resumed heavy MCP servers", fmt.Sprintf("%s: %s", state, strings.Join(resumed, ", ")))
+	}
+}
+
+// report logs what the policy did, and shows it as a desktop notification
+// if enabled
+func (p *Policy) report(title, message string) {
+	log.Printf("%s, %s", title, message)
+	if !p.notify {
+		return
+	}
+	if err := p.notifier(title, message); err != nil {
+		log.Printf("Failed to show notification: %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package power
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// fakeController tracks server statuses in memory
+type fakeController struct {
+	servers     map[string]*server.Server
+	order       []string
+	maintenance bool
+}
+
+func newFakeController(servers ...*server.Server) *fakeController {
+	c := &fakeController{servers: make(map[string]*server.Server)}
+	for _, srv := range servers {
+		c.servers[srv.Name] = srv
+		c.order = append(c.order, srv.Name)
+	}
+	return c
+}
+
+func (c *fakeController) GetServers() (map[string]*server.Server, []string, error) {
+	return c.servers, c.order, nil
+}
+
+func (c *fakeController) StartServer(name string) error {
+	c.servers[name].SetStatus(server.StatusRunning)
+	return nil
+}
+
+func (c *fakeController) StopServer(name string) error {
+	c.servers[name].SetStatus(server.StatusStopped)
+	return nil
+}
+
+func (c *fakeController) Maintenance() (bool, error) {
+	return c.maintenance, nil
+}
+
+func runningServer(name string, tags ...string) *server.Server {
+	srv := server.NewServer(name, "cmd", 4001, "")
+	srv.Tags = tags
+	srv.SetStatus(server.StatusRunning)
+	return srv
+}
+
+func TestNew(t *testing.T) {
+	p, err := New(nil, &config.PowerConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, defaultThreshold, p.threshold)
+	assert.Equal(t, defaultInterval, p.interval)
+
+	_, err = New(nil, &config.PowerConfig{Threshold: 101})
+	assert.Error(t, err)
+
+	_, err = New(nil, &config.PowerConfig{Interval: "often"})
+	assert.Error(t, err)
+}
+
+func TestPolicy_Apply(t *testing.T) {
+	indexer := runningServer("indexer", HeavyTag)
+	github := runningServer("github")
+	models := runningServer("models", HeavyTag)
+	models.Maintenance = true
+	controller := newFakeController(indexer, github, models)
+
+	p, err := New(controller, &config.PowerConfig{Threshold: 30, Notify: true})
+	require.NoError(t, err)
+	var notifications []string
+	p.notifier = func(title, message string) error {
+		notifications = append(notifications, title+": "+message)
+		return nil
+	}
+
+	// Above the threshold nothing stops
+	p.apply(State{Battery: true, OnBattery: true, Percent: 50})
+	assert.True(t, indexer.IsRunning())
+
+	p.apply(State{Battery: true, OnBattery: true, Percent: 30})
+	assert.Equal(t, server.StatusStopped, indexer.Status)
+	assert.True(t, github.IsRunning())
+	assert.True(t, models.IsRunning())
+	assert.Equal(t, []string{"Stopped heavy MCP servers: on battery (30%): indexer"}, notifications)
+
+	// Servers started by hand on battery keep running
+	indexer.SetStatus(server.StatusRunning)
+	p.apply(State{Battery: true, OnBattery: true, Percent: 25})
+	assert.True(t, indexer.IsRunning())
+
+	indexer.SetStatus(server.StatusStopped)
+	p.apply(State{Battery: true, Percent: 26})
+	assert.True(t, indexer.IsRunning())
+	assert.Len(t, notifications, 2)
+	assert.Equal(t, "Resumed heavy MCP servers: on AC power (26%): indexer", notifications[1])
+}
+
+func TestPolicy_Maintenance(t *testing.T) {
+	indexer := runningServer("indexer", HeavyTag)
+	controller := newFakeController(indexer)
+	controller.maintenance = true
+
+	p, err := New(controller, &config.PowerConfig{})
+	require.NoError(t, err)
+
+	p.apply(State{Battery: true, OnBattery: true, Percent: 90})
+	assert.True(t, indexer.IsRunning())
+}
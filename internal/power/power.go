@@ -0,0 +1,125 @@
+// Package power stops heavy servers while a laptop runs low on battery and
+// resumes them on AC power
+package power
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// ErrUnsupported is returned when the battery can't be read on this platform
+var ErrUnsupported = errors.New("battery state is not supported on " + runtime.GOOS)
+
+// State is the power source of the machine
+type State struct {
+	Battery   bool // A battery was found
+	OnBattery bool // Running from the battery rather than AC power
+	Percent   int  // Charge of the battery
+}
+
+// String describes the state for logs and notifications
+func (s State) String() string {
+	switch {
+	case !s.Battery:
+		return "no battery"
+	case s.OnBattery:
+		return fmt.Sprintf("on battery (%d%%)", s.Percent)
+	default:
+		return fmt.Sprintf("on AC power (%d%%)", s.Percent)
+	}
+}
+
+// sysfsRoot is where Linux lists power supplies
+const sysfsRoot = "/sys/class/power_supply"
+
+// Read returns the current power state
+func Read() (State, error) {
+	switch runtime.GOOS {
+	case "linux":
+		return readSysfs(sysfsRoot)
+	case "darwin":
+		out, err := exec.Command("pmset", "-g", "batt").Output()
+		if err != nil {
+			return State{}, fmt.Errorf("failed to run pmset: %w", err)
+		}
+		return parsePmset(string(out)), nil
+	default:
+		return State{}, ErrUnsupported
+	}
+}
+
+// readSysfs reads the batteries and AC adapters listed in root. The charge
+// is the average of all batteries.
+func readSysfs(root string) (State, error) {
+	supplies, err := os.ReadDir(root)
+	if err != nil {
+		return State{}, fmt.Errorf("failed to read power supplies: %w", err)
+	}
+
+	var state State
+	var batteries, total int
+	acOnline := false
+	for _, supply := range supplies {
+		dir := filepath.Join(root, supply.Name())
+		switch readAttr(dir, "type") {
+		case "Battery":
+			capacity, err := strconv.Atoi(readAttr(dir, "capacity"))
+			if err != nil {
+				continue
+			}
+			batteries++
+			total += capacity
+			if readAttr(dir, "status") == "Discharging" {
+				state.OnBattery = true
+			}
+		case "Mains", "USB":
+			if readAttr(dir, "online") == "1" {
+				acOnline = true
+			}
+		}
+	}
+
+	if batteries == 0 {
+		return State{}, nil
+	}
+	state.Battery = true
+	state.Percent = total / batteries
+	state.OnBattery = state.OnBattery && !acOnline
+	return state, nil
+}
+
+// readAttr reads a sysfs attribute, returning "" if it is missing
+func readAttr(dir, name string) string {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// pmsetPercent matches the charge in the output of pmset -g batt
+var pmsetPercent = regexp.MustCompile(`(\d+)%`)
+
+// parsePmset parses the output of pmset -g batt, e.g.
+//
+//	Now drawing from 'Battery Power'
+//	 -InternalBattery-0 (id=4653155)	85%; discharging; 4:01 remaining present: true
+func parsePmset(out string) State {
+	match := pmsetPercent.FindStringSubmatch(out)
+	if match == nil {
+		return State{}
+	}
+	percent, _ := strconv.Atoi(match[1])
+	return State{
+		Battery:   true,
+		OnBattery: strings.Contains(out, "'Battery Power'"),
+		Percent:   percent,
+	}
+}
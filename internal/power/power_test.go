@@ -0,0 +1,54 @@
+package power
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSupply creates a fake sysfs power supply with attrs
+func writeSupply(t *testing.T, root, name string, attrs map[string]string) {
+	dir := filepath.Join(root, name)
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	for attr, value := range attrs {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, attr), []byte(value+"\n"), 0644))
+	}
+}
+
+func TestReadSysfs(t *testing.T) {
+	root := t.TempDir()
+
+	state, err := readSysfs(root)
+	require.NoError(t, err)
+	assert.False(t, state.Battery)
+
+	writeSupply(t, root, "AC", map[string]string{"type": "Mains", "online": "0"})
+	writeSupply(t, root, "BAT0", map[string]string{"type": "Battery", "capacity": "40", "status": "Discharging"})
+	writeSupply(t, root, "BAT1", map[string]string{"type": "Battery", "capacity": "20", "status": "Discharging"})
+
+	state, err = readSysfs(root)
+	require.NoError(t, err)
+	assert.Equal(t, State{Battery: true, OnBattery: true, Percent: 30}, state)
+	assert.Equal(t, "on battery (30%)", state.String())
+
+	writeSupply(t, root, "AC", map[string]string{"online": "1"})
+	state, err = readSysfs(root)
+	require.NoError(t, err)
+	assert.False(t, state.OnBattery)
+
+	_, err = readSysfs(filepath.Join(root, "missing"))
+	assert.Error(t, err)
+}
+
+func TestParsePmset(t *testing.T) {
+	state := parsePmset("Now drawing from 'Battery Power'\n -InternalBattery-0 (id=4653155)\t85%; discharging; 4:01 remaining present: true\n")
+	assert.Equal(t, State{Battery: true, OnBattery: true, Percent: 85}, state)
+
+	state = parsePmset("Now drawing from 'AC Power'\n -InternalBattery-0 (id=4653155)\t100%; charged; 0:00 remaining present: true\n")
+	assert.Equal(t, State{Battery: true, Percent: 100}, state)
+
+	assert.False(t, parsePmset("Now drawing from 'AC Power'\n").Battery)
+}
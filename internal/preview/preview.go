@@ -0,0 +1,249 @@
+package preview
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // Register decoders for the formats MCP servers return
+	_ "image/jpeg"
+	"image/png"
+	"os"
+	"strings"
+)
+
+// Protocol is a terminal graphics protocol
+type Protocol string
+
+const (
+	ProtocolKitty  Protocol = "kitty"
+	ProtocolITerm2 Protocol = "iterm2"
+	ProtocolSixel  Protocol = "sixel"
+	ProtocolASCII  Protocol = "ascii"
+)
+
+// Terminal cell size in pixels, used to size sixel images
+const (
+	cellWidth  = 10
+	cellHeight = 20
+)
+
+// asciiRamp maps brightness to characters, darkest first
+const asciiRamp = " .:-=+*#%@"
+
+// Detect returns the graphics protocol supported by the current terminal.
+// MCP_MANAGER_IMAGE_PROTOCOL overrides the detection, e.g. for sixel
+// terminals, which can't be recognized from the environment.
+func Detect() Protocol {
+	return detect(os.Getenv)
+}
+
+// detect returns the graphics protocol for an environment
+func detect(getenv func(string) string) Protocol {
+	switch p := Protocol(strings.ToLower(getenv("MCP_MANAGER_IMAGE_PROTOCOL"))); p {
+	case ProtocolKitty, ProtocolITerm2, ProtocolSixel, ProtocolASCII:
+		return p
+	}
+
+	switch {
+	case getenv("KITTY_WINDOW_ID") != "" || getenv("TERM") == "xterm-kitty" || getenv("TERM_PROGRAM") == "ghostty":
+		return ProtocolKitty
+	case getenv("TERM_PROGRAM") == "iTerm.app" || getenv("TERM_PROGRAM") == "WezTerm":
+		return ProtocolITerm2
+	default:
+		return ProtocolASCII
+	}
+}
+
+// Render renders an encoded image to fit in cols x rows terminal cells
+func Render(data []byte, protocol Protocol, cols, rows int) (string, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode image: %w", err)
+	}
+	if cols <= 0 || rows <= 0 {
+		return "", fmt.Errorf("no room to render image")
+	}
+
+	switch protocol {
+	case ProtocolKitty:
+		return renderKitty(img, cols, rows)
+	case ProtocolITerm2:
+		return renderITerm2(data, cols, rows), nil
+	case ProtocolSixel:
+		return renderSixel(img, cols, rows), nil
+	default:
+		return renderASCII(img, cols, rows), nil
+	}
+}
+
+// renderKitty renders with the kitty graphics protocol, which requires PNG
+// data sent in chunks of at most 4096 bytes
+func renderKitty(img image.Image, cols, rows int) (string, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, fit(img, cols*cellWidth, rows*cellHeight)); err != nil {
+		return "", fmt.Errorf("failed to encode image: %w", err)
+	}
+	encoded := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	var b strings.Builder
+	for i := 0; i < len(encoded); i += 4096 {
+		end := min(i+4096, len(encoded))
+		more := 0
+		if end < len(encoded) {
+			more = 1
+		}
+		if i == 0 {
+			fmt.Fprintf(&b, "\x1b_Ga=T,f=100,c=%d,r=%d,m=%d;%s\x1b\\", cols, rows, more, encoded[i:end])
+		} else {
+			fmt.Fprintf(&b, "\x1b_Gm=%d;%s\x1b\\", more, encoded[i:end])
+		}
+	}
+	return b.String(), nil
+}
+
+// renderITerm2 renders with the iTerm2 inline images protocol, which
+// accepts the original encoded image
+func renderITerm2(data []byte, cols, rows int) string {
+	return fmt.Sprintf("\x1b]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=1:%s\a",
+		len(data), cols, rows, base64.StdEncoding.EncodeToString(data))
+}
+
+// renderSixel renders as sixels using a 6x6x6 color cube palette
+func renderSixel(img image.Image, cols, rows int) string {
+	img = fit(img, cols*cellWidth, rows*cellHeight)
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	// Quantize every pixel to the color cube
+	pixels := make([]int, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			r, g, b, _ := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			pixels[y*width+x] = cubeLevel(r)*36 + cubeLevel(g)*6 + cubeLevel(b)
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "\x1bPq\"1;1;%d;%d", width, height)
+	for i := 0; i < 216; i++ {
+		fmt.Fprintf(&b, "#%d;2;%d;%d;%d", i, i/36*20, i/6%6*20, i%6*20)
+	}
+
+	for band := 0; band < height; band += 6 {
+		// Draw each color used in the band as a separate pass
+		used := make(map[int]bool)
+		for y := band; y < min(band+6, height); y++ {
+			for x := 0; x < width; x++ {
+				used[pixels[y*width+x]] = true
+			}
+		}
+
+		first := true
+		for c := 0; c < 216; c++ {
+			if !used[c] {
+				continue
+			}
+			if !first {
+				b.WriteByte('$')
+			}
+			first = false
+			fmt.Fprintf(&b, "#%d", c)
+
+			var run byte
+			count := 0
+			flush := func() {
+				if count > 3 {
+					fmt.Fprintf(&b, "!%d%c", count, run)
+				} else {
+					b.WriteString(strings.Repeat(string(run), count))
+				}
+			}
+			for x := 0; x < width; x++ {
+				var bits byte
+				for dy := 0; dy < 6 && band+dy < height; dy++ {
+					if pixels[(band+dy)*width+x] == c {
+						bits |= 1 << dy
+					}
+				}
+				char := 63 + bits
+				if count > 0 && char == run {
+					count++
+					continue
+				}
+				if count > 0 {
+					flush()
+				}
+				run, count = char, 1
+			}
+			flush()
+		}
+		b.WriteByte('-')
+	}
+
+	b.WriteString("\x1b\\")
+	return b.String()
+}
+
+// cubeLevel maps a 16-bit color channel to one of the 6 color cube levels
+func cubeLevel(v uint32) int {
+	return int(((v>>8)*5 + 127) / 255)
+}
+
+// renderASCII renders a grayscale character thumbnail. Terminal cells are
+// about twice as tall as wide, so each row covers twice the height.
+func renderASCII(img image.Image, cols, rows int) string {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width == 0 || height == 0 {
+		return ""
+	}
+
+	// Preserve the aspect ratio within cols x rows
+	outCols := cols
+	outRows := height * cols / width / 2
+	if outRows > rows {
+		outRows = rows
+		outCols = width * rows * 2 / height
+	}
+	outCols, outRows = max(outCols, 1), max(outRows, 1)
+
+	var b strings.Builder
+	for row := 0; row < outRows; row++ {
+		for col := 0; col < outCols; col++ {
+			x := bounds.Min.X + col*width/outCols
+			y := bounds.Min.Y + row*height/outRows
+			gray := color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y
+			b.WriteByte(asciiRamp[int(gray)*(len(asciiRamp)-1)/255])
+		}
+		if row < outRows-1 {
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+// fit scales an image down with nearest-neighbor sampling to fit in
+// maxWidth x maxHeight pixels, preserving its aspect ratio
+func fit(img image.Image, maxWidth, maxHeight int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= maxWidth && height <= maxHeight {
+		return img
+	}
+
+	scaledWidth, scaledHeight := maxWidth, height*maxWidth/width
+	if scaledHeight > maxHeight {
+		scaledWidth, scaledHeight = width*maxHeight/height, maxHeight
+	}
+	scaledWidth, scaledHeight = max(scaledWidth, 1), max(scaledHeight, 1)
+
+	scaled := image.NewRGBA(image.Rect(0, 0, scaledWidth, scaledHeight))
+	for y := 0; y < scaledHeight; y++ {
+		for x := 0; x < scaledWidth; x++ {
+			scaled.Set(x, y, img.At(bounds.Min.X+x*width/scaledWidth, bounds.Min.Y+y*height/scaledHeight))
+		}
+	}
+	return scaled
+}
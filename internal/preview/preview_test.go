@@ -0,0 +1,80 @@
+package preview
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testImage returns a PNG that is black on the left half and white on the right
+func testImage(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if x >= width/2 {
+				img.Set(x, y, color.White)
+			} else {
+				img.Set(x, y, color.Black)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestDetect(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(key string) string { return vars[key] }
+	}
+
+	assert.Equal(t, ProtocolKitty, detect(env(map[string]string{"TERM": "xterm-kitty"})))
+	assert.Equal(t, ProtocolITerm2, detect(env(map[string]string{"TERM_PROGRAM": "iTerm.app"})))
+	assert.Equal(t, ProtocolASCII, detect(env(map[string]string{"TERM": "xterm-256color"})))
+	assert.Equal(t, ProtocolSixel, detect(env(map[string]string{"TERM": "xterm-kitty", "MCP_MANAGER_IMAGE_PROTOCOL": "sixel"})))
+}
+
+func TestRender_ASCII(t *testing.T) {
+	out, err := Render(testImage(t, 40, 20), ProtocolASCII, 10, 10)
+	require.NoError(t, err)
+
+	// 10 columns keep the 2:1 aspect ratio with half-height rows
+	lines := strings.Split(out, "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, "     @@@@@", lines[0])
+}
+
+func TestRender_Protocols(t *testing.T) {
+	data := testImage(t, 400, 200)
+
+	out, err := Render(data, ProtocolKitty, 20, 10)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(out, "\x1b_Ga=T,f=100,c=20,r=10,"))
+
+	out, err = Render(data, ProtocolITerm2, 20, 10)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(out, "\x1b]1337;File=inline=1;"))
+
+	out, err = Render(data, ProtocolSixel, 20, 10)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(out, "\x1bPq\"1;1;200;100"), "scaled to fit 20x10 cells")
+	assert.True(t, strings.HasSuffix(out, "-\x1b\\"))
+
+	_, err = Render([]byte("not an image"), ProtocolASCII, 20, 10)
+	assert.Error(t, err)
+}
+
+func TestFit(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 400, 100))
+	assert.Equal(t, image.Rect(0, 0, 100, 25), fit(img, 100, 100).Bounds())
+	assert.Equal(t, img, fit(img, 500, 500), "small images are not scaled")
+}
@@ -0,0 +1,58 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// codeNotApproved is the error code of calls an operator didn't approve
+const codeNotApproved = -32005
+
+// ApprovalOptions configures the tools of a proxy whose calls wait for an
+// operator's approval
+type ApprovalOptions struct {
+	Tools []string // Tools needing approval
+
+	// Request parks a call until it is approved, returning why it wasn't
+	// otherwise. Arguments are redacted. ctx ends when the client gives up.
+	Request func(ctx context.Context, tool string, arguments string) error
+}
+
+// approval holds calls to the tools of an ApprovalOptions until approved
+type approval struct {
+	opts ApprovalOptions
+}
+
+// newApproval creates the approval gate of a proxy
+func newApproval(opts ApprovalOptions) *approval {
+	return &approval{opts: opts}
+}
+
+// Wrap makes the approval gate a Middleware
+func (a *approval) Wrap(next Handler) Handler {
+	return func(call *Call) MCPResponse {
+		if call.Request.Method != "tools/call" {
+			return next(call)
+		}
+		name := toolName(call.Request)
+		if !slices.Contains(a.opts.Tools, name) {
+			return next(call)
+		}
+
+		ctx := context.Background()
+		if call.HTTP != nil {
+			ctx = call.HTTP.Context()
+		}
+		var arguments interface{}
+		if params, ok := call.Request.Params.(map[string]interface{}); ok {
+			arguments = params["arguments"]
+		}
+		if err := a.opts.Request(ctx, name, call.Redactor.JSON(arguments)); err != nil {
+			response := errorResponse(call, codeNotApproved, fmt.Sprintf("call to '%s' not approved: %v", name, err))
+			response.Error.Data = map[string]interface{}{"tool": name}
+			return response
+		}
+		return next(call)
+	}
+}
@@ -0,0 +1,49 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApproval(t *testing.T) {
+	var requested []string
+	decision := error(nil)
+	a := newApproval(ApprovalOptions{
+		Tools: []string{"post_message"},
+		Request: func(ctx context.Context, tool string, arguments string) error {
+			requested = append(requested, tool+" "+arguments)
+			return decision
+		},
+	})
+
+	calls := 0
+	handler := a.Wrap(echoHandler(&calls))
+	callTool := func(name string) MCPResponse {
+		call := newCall("tools/call")
+		call.Request.Params = map[string]interface{}{"name": name, "arguments": map[string]interface{}{"text": "hi", "token": "secret"}}
+		return handler(call)
+	}
+
+	// Other tools and methods pass without approval
+	assert.Nil(t, callTool("read_channel").Error)
+	assert.Nil(t, handler(newCall("tools/list")).Error)
+	assert.Empty(t, requested)
+
+	// Approved calls are forwarded, with redacted arguments shown
+	assert.Nil(t, callTool("post_message").Error)
+	require.Len(t, requested, 1)
+	assert.Contains(t, requested[0], `"text":"hi"`)
+	assert.NotContains(t, requested[0], "secret")
+	assert.Equal(t, 3, calls)
+
+	decision = errors.New("rejected by operator")
+	response := callTool("post_message")
+	require.NotNil(t, response.Error)
+	assert.Equal(t, codeNotApproved, response.Error.Code)
+	assert.Contains(t, response.Error.Message, "rejected by operator")
+	assert.Equal(t, 3, calls)
+}
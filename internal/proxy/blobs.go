@@ -0,0 +1,185 @@
+package proxy
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	maxBlobs     = 50       // Number of recent blobs kept per server
+	maxBlobBytes = 64 << 20 // Total decoded size of kept blobs
+)
+
+// BlobInfo describes a binary blob returned by a tool or resource
+type BlobInfo struct {
+	ID       string    `json:"id"`
+	MimeType string    `json:"mimeType"`
+	Size     int       `json:"size"`
+	Source   string    `json:"source"` // Tool name or resource URI
+	Created  time.Time `json:"created"`
+}
+
+// blob is a decoded blob kept for the blob endpoints
+type blob struct {
+	BlobInfo
+	data []byte
+}
+
+// blobContent is the part of tool and resource content that can carry a
+// base64 blob
+type blobContent struct {
+	Type     string       `json:"type"`
+	Data     string       `json:"data"` // Image and audio content
+	Blob     string       `json:"blob"` // Resource contents
+	MimeType string       `json:"mimeType"`
+	URI      string       `json:"uri"`
+	Resource *blobContent `json:"resource"` // Embedded resource
+}
+
+// collectBlobs decodes the base64 blobs of a tools/call or resources/read
+// result so they can be served as files
+func (s *Server) collectBlobs(request MCPRequest, response MCPResponse) {
+	raw, ok := response.Result.(json.RawMessage)
+	if !ok || !(bytes.Contains(raw, []byte(`"data"`)) || bytes.Contains(raw, []byte(`"blob"`))) {
+		return
+	}
+
+	var result struct {
+		Content  []blobContent `json:"content"`
+		Contents []blobContent `json:"contents"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return
+	}
+
+	source := toolName(request)
+	for _, item := range append(result.Content, result.Contents...) {
+		if item.Resource != nil {
+			item = *item.Resource
+		}
+
+		encoded := item.Data
+		if encoded == "" {
+			encoded = item.Blob
+		}
+		if encoded == "" {
+			continue
+		}
+
+		data, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			continue
+		}
+
+		mimeType := item.MimeType
+		if mimeType == "" {
+			mimeType = http.DetectContentType(data)
+		}
+		itemSource := source
+		if item.URI != "" {
+			itemSource = item.URI
+		}
+		s.addBlob(data, mimeType, itemSource)
+	}
+}
+
+// addBlob stores a blob, evicting the oldest ones over the limits
+func (s *Server) addBlob(data []byte, mimeType, source string) {
+	sum := sha256.Sum256(data)
+	id := hex.EncodeToString(sum[:8])
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, b := range s.blobs {
+		if b.ID == id {
+			return
+		}
+	}
+
+	s.blobs = append(s.blobs, &blob{
+		BlobInfo: BlobInfo{ID: id, MimeType: mimeType, Size: len(data), Source: source, Created: time.Now()},
+		data:     data,
+	})
+
+	total := 0
+	for _, b := range s.blobs {
+		total += b.Size
+	}
+	for len(s.blobs) > 1 && (len(s.blobs) > maxBlobs || total > maxBlobBytes) {
+		total -= s.blobs[0].Size
+		s.blobs = s.blobs[1:]
+	}
+}
+
+// Blobs returns the kept blobs, newest first
+func (s *Server) Blobs() []BlobInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	infos := make([]BlobInfo, 0, len(s.blobs))
+	for i := len(s.blobs) - 1; i >= 0; i-- {
+		infos = append(infos, s.blobs[i].BlobInfo)
+	}
+	return infos
+}
+
+// handleBlobs lists the kept blobs, newest first
+func (s *Server) handleBlobs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"blobs": s.Blobs()})
+}
+
+// handleBlob serves a decoded blob with its content type
+func (s *Server) handleBlob(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	s.mu.RLock()
+	var found *blob
+	for _, b := range s.blobs {
+		if b.ID == id {
+			found = b
+			break
+		}
+	}
+	s.mu.RUnlock()
+
+	if found == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	// Tools choose the bytes and their type, so browsers must neither
+	// guess another type nor run them as pages of the proxy's origin
+	mimeType := found.MimeType
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", mimeType)
+	w.Header().Set("Content-Length", strconv.Itoa(found.Size))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", "sandbox")
+	if !inlineMedia(mimeType) {
+		w.Header().Set("Content-Disposition", "attachment")
+	}
+	w.Write(found.data)
+}
+
+// inlineMedia returns true if content of mimeType is an image, audio or
+// video browsers may show inline, which excludes scriptable SVG
+func inlineMedia(mimeType string) bool {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil || mediaType == "image/svg+xml" {
+		return false
+	}
+	kind, _, _ := strings.Cut(mediaType, "/")
+	return kind == "image" || kind == "audio" || kind == "video"
+}
@@ -0,0 +1,91 @@
+package proxy
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_CollectBlobs(t *testing.T) {
+	server := New(0, "")
+	request := MCPRequest{Method: "tools/call", Params: map[string]interface{}{"name": "screenshot"}}
+
+	server.collectBlobs(request, MCPResponse{Result: json.RawMessage(`{"content": [
+		{"type": "text", "text": "done"},
+		{"type": "image", "data": "aGk=", "mimeType": "image/png"},
+		{"type": "resource", "resource": {"uri": "file:///tmp/a.pdf", "blob": "cGRm", "mimeType": "application/pdf"}},
+		{"type": "image", "data": "not base64!"}
+	]}`)})
+
+	// Duplicates are stored once
+	server.collectBlobs(MCPRequest{Method: "resources/read"}, MCPResponse{Result: json.RawMessage(`{"contents": [
+		{"uri": "file:///tmp/b.png", "blob": "aGk=", "mimeType": "image/png"}
+	]}`)})
+
+	blobs := server.Blobs()
+	require.Len(t, blobs, 2)
+	assert.Equal(t, "application/pdf", blobs[0].MimeType, "newest first")
+	assert.Equal(t, "file:///tmp/a.pdf", blobs[0].Source)
+	assert.Equal(t, "image/png", blobs[1].MimeType)
+	assert.Equal(t, "screenshot", blobs[1].Source)
+	assert.Equal(t, 2, blobs[1].Size)
+}
+
+func TestServer_AddBlob_Eviction(t *testing.T) {
+	server := New(0, "")
+	for i := 0; i < maxBlobs+5; i++ {
+		server.addBlob([]byte{byte(i)}, "application/octet-stream", "test")
+	}
+	blobs := server.Blobs()
+	require.Len(t, blobs, maxBlobs)
+	assert.Equal(t, server.blobs[0].ID, blobs[len(blobs)-1].ID)
+}
+
+func TestServer_BlobEndpoints(t *testing.T) {
+	command := getMockMCPCommand("-result", `{"content": [{"type": "image", "data": "iVBORw==", "mimeType": "image/png"}]}`)
+	server := New(8103, command)
+	require.NoError(t, server.Start())
+	defer server.Stop()
+
+	time.Sleep(100 * time.Millisecond)
+	callTool(t, 8103)
+
+	resp, err := http.Get("http://localhost:8103/resources/blobs")
+	require.NoError(t, err)
+	var list struct {
+		Blobs []BlobInfo `json:"blobs"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&list))
+	resp.Body.Close()
+	require.Len(t, list.Blobs, 1)
+
+	resp, err = http.Get("http://localhost:8103/resources/blob/" + list.Blobs[0].ID)
+	require.NoError(t, err)
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "nosniff", resp.Header.Get("X-Content-Type-Options"))
+	assert.Equal(t, "sandbox", resp.Header.Get("Content-Security-Policy"))
+	assert.Empty(t, resp.Header.Get("Content-Disposition"))
+	assert.Equal(t, []byte("\x89PNG"), data)
+
+	resp, err = http.Get("http://localhost:8103/resources/blob/missing")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestInlineMedia(t *testing.T) {
+	assert.True(t, inlineMedia("image/png"))
+	assert.True(t, inlineMedia("audio/wav"))
+	assert.True(t, inlineMedia("Video/MP4; codecs=avc1"))
+	assert.False(t, inlineMedia("image/svg+xml"))
+	assert.False(t, inlineMedia("text/html"))
+	assert.False(t, inlineMedia("not a type"))
+}
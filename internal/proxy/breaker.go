@@ -0,0 +1,158 @@
+package proxy
+
+import (
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of a proxy's circuit breaker
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"    // Calls pass through
+	CircuitOpen     CircuitState = "open"      // Calls fail fast
+	CircuitHalfOpen CircuitState = "half-open" // A trial call passes through
+)
+
+// codeUnavailable is the JSON-RPC error code of calls rejected while the
+// circuit is open
+const codeUnavailable = -32003
+
+// CircuitBreakerOptions configures the circuit breaker of a proxy
+type CircuitBreakerOptions struct {
+	Failures int           // Consecutive failures opening the circuit
+	Cooldown time.Duration // Time the circuit stays open before a trial call
+
+	// OnChange is called when the circuit changes state, with the error
+	// of the failure that opened it; nil ignores changes
+	OnChange func(state CircuitState, reason string)
+}
+
+// unavailableData is the data of the error returned while the circuit is
+// open, so clients can tell when to try again
+type unavailableData struct {
+	Reason            string `json:"reason"`
+	RetryAfterSeconds int    `json:"retryAfterSeconds"`
+}
+
+// breaker fails calls fast after consecutive failures of the MCP process,
+// instead of queuing each behind a timeout. Only failures to answer count;
+// errors returned by the server don't.
+type breaker struct {
+	opts CircuitBreakerOptions
+	now  func() time.Time
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int       // Consecutive failures while closed
+	openedAt time.Time // When the circuit last opened
+	reason   string    // Error of the failure that opened the circuit
+}
+
+// newBreaker creates a closed circuit breaker
+func newBreaker(opts CircuitBreakerOptions) *breaker {
+	return &breaker{opts: opts, now: time.Now, state: CircuitClosed}
+}
+
+// Wrap makes the breaker a Middleware
+func (b *breaker) Wrap(next Handler) Handler {
+	return func(call *Call) MCPResponse {
+		if retryAfter, ok := b.allow(); !ok {
+			response := errorResponse(call, codeUnavailable, "server unavailable")
+			response.Error.Data = unavailableData{
+				Reason:            b.lastReason(),
+				RetryAfterSeconds: int(retryAfter.Round(time.Second).Seconds()),
+			}
+			return response
+		}
+
+		response := next(call)
+		message := ""
+		if response.failure != failureNone {
+			message = response.Error.Message
+		}
+		b.record(response.failure != failureNone, message)
+		return response
+	}
+}
+
+// allow returns true if a call may pass, or the time until the next trial
+func (b *breaker) allow() (time.Duration, bool) {
+	b.mu.Lock()
+	switch b.state {
+	case CircuitOpen:
+		wait := b.opts.Cooldown - b.now().Sub(b.openedAt)
+		if wait > 0 {
+			b.mu.Unlock()
+			return wait, false
+		}
+		b.state = CircuitHalfOpen
+		b.mu.Unlock()
+		b.notify(CircuitHalfOpen, "")
+		return 0, true
+
+	case CircuitHalfOpen:
+		// Only the trial call passes until it completes
+		b.mu.Unlock()
+		return time.Second, false
+
+	default:
+		b.mu.Unlock()
+		return 0, true
+	}
+}
+
+// record updates the circuit with the outcome of a call
+func (b *breaker) record(failed bool, reason string) {
+	b.mu.Lock()
+	previous := b.state
+	switch {
+	case b.state == CircuitHalfOpen && failed:
+		b.open(reason)
+	case b.state == CircuitHalfOpen:
+		b.state = CircuitClosed
+		b.failures = 0
+	case failed:
+		b.failures++
+		if b.state == CircuitClosed && b.failures >= b.opts.Failures {
+			b.open(reason)
+		}
+	default:
+		b.failures = 0
+	}
+	state := b.state
+	b.mu.Unlock()
+
+	if state != previous {
+		b.notify(state, reason)
+	}
+}
+
+// open opens the circuit. Must be called with b.mu held.
+func (b *breaker) open(reason string) {
+	b.state = CircuitOpen
+	b.openedAt = b.now()
+	b.reason = reason
+	b.failures = 0
+}
+
+// lastReason returns the error of the failure that opened the circuit
+func (b *breaker) lastReason() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.reason
+}
+
+// State returns the current state of the circuit
+func (b *breaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// notify reports a state change
+func (b *breaker) notify(state CircuitState, reason string) {
+	if b.opts.OnChange != nil {
+		b.opts.OnChange(state, reason)
+	}
+}
@@ -0,0 +1,76 @@
+package proxy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBreaker(t *testing.T) {
+	var changes []CircuitState
+	b := newBreaker(CircuitBreakerOptions{
+		Failures: 2,
+		Cooldown: 30 * time.Second,
+		OnChange: func(state CircuitState, reason string) {
+			changes = append(changes, state)
+		},
+	})
+	now := time.Unix(1000, 0)
+	b.now = func() time.Time { return now }
+
+	calls := 0
+	handler := b.Wrap(flakyHandler(&calls, 3, failureTimeout))
+
+	// Consecutive failures open the circuit
+	handler(newCall("tools/list"))
+	assert.Equal(t, CircuitClosed, b.State())
+	handler(newCall("tools/list"))
+	assert.Equal(t, CircuitOpen, b.State())
+	assert.Equal(t, 2, calls)
+
+	// Calls fail fast while it is open
+	response := handler(newCall("tools/list"))
+	assert.Equal(t, 2, calls)
+	require.NotNil(t, response.Error)
+	assert.Equal(t, codeUnavailable, response.Error.Code)
+	data := response.Error.Data.(unavailableData)
+	assert.Equal(t, "Failed to read response: EOF", data.Reason)
+	assert.Equal(t, 30, data.RetryAfterSeconds)
+
+	// A failed trial call after the cooldown opens it again
+	now = now.Add(30 * time.Second)
+	handler(newCall("tools/list"))
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, CircuitOpen, b.State())
+
+	// A successful one closes it
+	now = now.Add(30 * time.Second)
+	response = handler(newCall("tools/list"))
+	assert.Nil(t, response.Error)
+	assert.Equal(t, CircuitClosed, b.State())
+
+	assert.Equal(t, []CircuitState{CircuitOpen, CircuitHalfOpen, CircuitOpen, CircuitHalfOpen, CircuitClosed}, changes)
+}
+
+func TestBreaker_CountsConsecutiveFailures(t *testing.T) {
+	b := newBreaker(CircuitBreakerOptions{Failures: 2, Cooldown: time.Minute})
+
+	// Errors returned by the server aren't failures
+	calls := 0
+	handler := b.Wrap(flakyHandler(&calls, 5, failureNone))
+	for i := 0; i < 5; i++ {
+		handler(newCall("tools/call"))
+	}
+	assert.Equal(t, CircuitClosed, b.State())
+
+	// A success resets the count
+	calls = 0
+	handler = b.Wrap(flakyHandler(&calls, 1, failureNoResponse))
+	handler(newCall("ping"))
+	handler(newCall("ping"))
+	calls = 0
+	handler(newCall("ping"))
+	assert.Equal(t, CircuitClosed, b.State())
+}
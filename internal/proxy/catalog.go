@@ -0,0 +1,83 @@
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// toolCatalog remembers the tools of the MCP process for middlewares that
+// depend on their schemas or annotations
+type toolCatalog struct {
+	mu    sync.Mutex
+	tools map[string]Tool // nil until listed
+}
+
+// observe forwards a tools/list call, remembering the tools of its first page
+func (c *toolCatalog) observe(call *Call, next Handler) MCPResponse {
+	response := next(call)
+	if cursor(call.Request) == "" {
+		c.learn(response)
+	}
+	return response
+}
+
+// lookup returns a tool, listing the tools through next first if they
+// haven't been yet
+func (c *toolCatalog) lookup(call *Call, next Handler, name string) (Tool, bool) {
+	c.mu.Lock()
+	listed := c.tools != nil
+	c.mu.Unlock()
+	if !listed {
+		list := *call
+		list.Request = MCPRequest{JSONRPC: "2.0", ID: call.Request.ID, Method: "tools/list", Params: map[string]interface{}{}}
+		c.learn(next(&list))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	tool, exists := c.tools[name]
+	return tool, exists
+}
+
+// learn replaces the tools with those of a tools/list response
+func (c *toolCatalog) learn(response MCPResponse) {
+	tools, err := parseTools(response)
+	if err != nil {
+		return
+	}
+	learned := make(map[string]Tool, len(tools))
+	for _, tool := range tools {
+		learned[tool.Name] = tool
+	}
+
+	c.mu.Lock()
+	c.tools = learned
+	c.mu.Unlock()
+}
+
+// cursor returns the pagination cursor of a list request
+func cursor(request MCPRequest) string {
+	if params, ok := request.Params.(map[string]interface{}); ok {
+		if c, ok := params["cursor"].(string); ok {
+			return c
+		}
+	}
+	return ""
+}
+
+// parseTools decodes the tools of a tools/list response
+func parseTools(response MCPResponse) ([]Tool, error) {
+	if response.Error != nil {
+		return nil, fmt.Errorf("MCP tools error: %s", response.Error.Message)
+	}
+	data, err := json.Marshal(response.Result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal tools result: %w", err)
+	}
+	var result ToolsListResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse tools result: %w", err)
+	}
+	return result.Tools, nil
+}
@@ -0,0 +1,80 @@
+package proxy
+
+import (
+	"log"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// chaos injects the faults of a server.Chaos into the calls reaching the
+// MCP process
+type chaos struct {
+	opts  server.Chaos
+	crash func() // Kills the MCP process
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// newChaos creates the fault injector of a proxy
+func newChaos(opts server.Chaos, crash func()) *chaos {
+	seed := uint64(opts.Seed)
+	if opts.Seed == 0 {
+		seed = rand.Uint64()
+	}
+	return &chaos{opts: opts, crash: crash, rand: rand.New(rand.NewPCG(seed, seed))}
+}
+
+// Wrap makes the injector a Middleware
+func (c *chaos) Wrap(next Handler) Handler {
+	return func(call *Call) MCPResponse {
+		if len(c.opts.Methods) > 0 && !slices.Contains(c.opts.Methods, call.Request.Method) {
+			return next(call)
+		}
+
+		delay, restart, drop := c.roll()
+		if delay > 0 {
+			time.Sleep(delay)
+		}
+		if restart {
+			log.Printf("Chaos: killing MCP process on port %d before %s", call.Port, call.Request.Method)
+			c.crash()
+		}
+
+		response := next(call)
+		if drop && response.failure == failureNone {
+			log.Printf("Chaos: dropping response to %s on port %d", call.Request.Method, call.Port)
+			return failureResponse(call.Request.ID, failureNoResponse, "Failed to read response: dropped by chaos mode")
+		}
+		return response
+	}
+}
+
+// roll draws the faults of a call
+func (c *chaos) roll() (delay time.Duration, restart, drop bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delay = c.opts.Latency
+	if c.opts.Jitter > 0 {
+		delay += time.Duration(c.rand.Int64N(int64(c.opts.Jitter) + 1))
+	}
+	restart = c.rand.Float64() < c.opts.RestartRate
+	drop = c.rand.Float64() < c.opts.DropRate
+	return delay, restart, drop
+}
+
+// crashMCPProcess kills the MCP process as if it crashed, leaving the proxy
+// to notice and restart it on the next request
+func (s *Server) crashMCPProcess() {
+	s.mcpMu.Lock()
+	defer s.mcpMu.Unlock()
+
+	if s.mcp != nil {
+		s.mcp.stop()
+	}
+}
@@ -0,0 +1,60 @@
+package proxy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+func TestChaos(t *testing.T) {
+	crashes := 0
+	c := newChaos(server.Chaos{Latency: 20 * time.Millisecond, DropRate: 1, Methods: []string{"tools/call"}}, func() { crashes++ })
+
+	calls := 0
+	handler := c.Wrap(flakyHandler(&calls, 0, failureNone))
+
+	// Faults only apply to the selected methods
+	response := handler(newCall("tools/list"))
+	assert.Nil(t, response.Error)
+
+	// Responses are dropped after the server handled the call
+	start := time.Now()
+	response = handler(newCall("tools/call"))
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	assert.Equal(t, 2, calls)
+	require.NotNil(t, response.Error)
+	assert.Equal(t, failureNoResponse, response.failure)
+	assert.Equal(t, 0, crashes)
+}
+
+func TestChaos_Seed(t *testing.T) {
+	faults := func() []bool {
+		c := newChaos(server.Chaos{DropRate: 0.5, Seed: 42}, func() {})
+		var drops []bool
+		for i := 0; i < 20; i++ {
+			_, _, drop := c.roll()
+			drops = append(drops, drop)
+		}
+		return drops
+	}
+	assert.Equal(t, faults(), faults())
+	assert.Contains(t, faults(), true)
+	assert.Contains(t, faults(), false)
+}
+
+func TestServer_ChaosRestart(t *testing.T) {
+	s := NewWithOptions(8104, getMockMCPCommand(), Options{Chaos: &server.Chaos{RestartRate: 1}})
+	require.NoError(t, s.Start())
+	defer s.Stop()
+
+	pid := s.mcp.cmd.Process.Pid
+
+	// The proxy recovers from the killed process on the same call
+	response := s.handler(s.newCall(MCPRequest{JSONRPC: "2.0", ID: 1, Method: "ping"}, nil))
+	assert.Nil(t, response.Error)
+	assert.NotEqual(t, pid, s.mcp.cmd.Process.Pid)
+}
@@ -0,0 +1,341 @@
+package proxy
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/redact"
+	"github.com/tartavull/mcp-manager/internal/server"
+	"github.com/tartavull/mcp-manager/internal/transcript"
+)
+
+// Call is an MCP request passing through the middleware chain
+type Call struct {
+	Request MCPRequest
+	Port    int
+	HTTP    *http.Request // Client request the call arrived on
+
+	// Redactor must be applied to payloads before middlewares log or record them
+	Redactor *redact.Redactor
+}
+
+// Handler handles an MCP call and returns its response
+type Handler func(call *Call) MCPResponse
+
+// Middleware wraps a Handler to add behavior to the proxy request path
+type Middleware interface {
+	Wrap(next Handler) Handler
+}
+
+// MiddlewareFunc adapts a function to the Middleware interface
+type MiddlewareFunc func(next Handler) Handler
+
+// Wrap calls f(next)
+func (f MiddlewareFunc) Wrap(next Handler) Handler {
+	return f(next)
+}
+
+// MiddlewareFactory creates a middleware from its mcp.json settings
+type MiddlewareFactory func(config map[string]interface{}) (Middleware, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]MiddlewareFactory{}
+)
+
+// RegisterMiddleware makes a middleware available by name to the
+// "middleware" list of server configs. Programs embedding the proxy call it
+// before starting servers to add their own middlewares.
+func RegisterMiddleware(name string, factory MiddlewareFactory) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, exists := registry[name]; exists {
+		return fmt.Errorf("middleware '%s' already registered", name)
+	}
+	registry[name] = factory
+	return nil
+}
+
+// RegisteredMiddleware returns the names of all registered middlewares
+func RegisteredMiddleware() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// buildMiddleware creates the middlewares of a chain from their configs
+func buildMiddleware(configs []server.MiddlewareConfig) ([]Middleware, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	chain := make([]Middleware, 0, len(configs))
+	for _, cfg := range configs {
+		factory, exists := registry[cfg.Name]
+		if !exists {
+			return nil, fmt.Errorf("unknown middleware '%s'", cfg.Name)
+		}
+		mw, err := factory(cfg.Config)
+		if err != nil {
+			return nil, fmt.Errorf("invalid config for middleware '%s': %w", cfg.Name, err)
+		}
+		chain = append(chain, mw)
+	}
+	return chain, nil
+}
+
+// chain wraps handler so the first middleware is the outermost
+func chain(handler Handler, middlewares []Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i].Wrap(handler)
+	}
+	return handler
+}
+
+// decodeConfig decodes middleware settings into target
+func decodeConfig(config map[string]interface{}, target interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
+// errorResponse builds an error response for a call
+func errorResponse(call *Call, code int, message string) MCPResponse {
+	return MCPResponse{
+		JSONRPC: "2.0",
+		ID:      call.Request.ID,
+		Error:   &MCPError{Code: code, Message: message},
+	}
+}
+
+func init() {
+	RegisterMiddleware("logging", newLoggingMiddleware)
+	RegisterMiddleware("auth", newAuthMiddleware)
+	RegisterMiddleware("rateLimit", newRateLimitMiddleware)
+	RegisterMiddleware("cache", newCacheMiddleware)
+	RegisterMiddleware("retry", newRetryMiddleware)
+	RegisterMiddleware("transform", newTransformMiddleware)
+	RegisterMiddleware("validate", newValidateMiddleware)
+}
+
+// loggingConfig configures the logging middleware
+type loggingConfig struct {
+	Payloads bool `json:"payloads"` // Also log redacted requests and responses
+}
+
+// newLoggingMiddleware logs each call with its duration and outcome
+func newLoggingMiddleware(config map[string]interface{}) (Middleware, error) {
+	var cfg loggingConfig
+	if err := decodeConfig(config, &cfg); err != nil {
+		return nil, err
+	}
+
+	return MiddlewareFunc(func(next Handler) Handler {
+		return func(call *Call) MCPResponse {
+			start := time.Now()
+			response := next(call)
+
+			outcome := "ok"
+			if response.Error != nil {
+				outcome = "error: " + response.Error.Message
+			}
+			if call.HTTP != nil {
+				if ids := transcript.CorrelationOf(call.HTTP).Describe(); ids != "" {
+					outcome += " " + ids
+				}
+			}
+			log.Printf("MCP call on port %d: %s %s (%s) %s",
+				call.Port, call.Request.Method, toolName(call.Request), time.Since(start).Round(time.Millisecond), outcome)
+			if cfg.Payloads {
+				log.Printf("MCP call on port %d: request=%s response=%s",
+					call.Port, truncateLog([]byte(call.Redactor.JSON(call.Request))), truncateLog([]byte(call.Redactor.JSON(response))))
+			}
+
+			return response
+		}
+	}), nil
+}
+
+// authConfig configures the auth middleware
+type authConfig struct {
+	Token string `json:"token"` // Required bearer token
+}
+
+// newAuthMiddleware rejects calls without the configured bearer token
+func newAuthMiddleware(config map[string]interface{}) (Middleware, error) {
+	var cfg authConfig
+	if err := decodeConfig(config, &cfg); err != nil {
+		return nil, err
+	}
+	if cfg.Token == "" {
+		return nil, fmt.Errorf("token is required")
+	}
+
+	return MiddlewareFunc(func(next Handler) Handler {
+		return func(call *Call) MCPResponse {
+			if call.HTTP != nil {
+				token := strings.TrimPrefix(call.HTTP.Header.Get("Authorization"), "Bearer ")
+				if subtle.ConstantTimeCompare([]byte(token), []byte(cfg.Token)) != 1 {
+					return errorResponse(call, -32001, "unauthorized")
+				}
+			}
+			return next(call)
+		}
+	}), nil
+}
+
+// rateLimitConfig configures the rate limit middleware
+type rateLimitConfig struct {
+	RequestsPerSecond float64 `json:"requestsPerSecond"`
+	Burst             int     `json:"burst"` // Default: one second worth of requests
+}
+
+// newRateLimitMiddleware rejects calls above a token bucket rate
+func newRateLimitMiddleware(config map[string]interface{}) (Middleware, error) {
+	var cfg rateLimitConfig
+	if err := decodeConfig(config, &cfg); err != nil {
+		return nil, err
+	}
+	if cfg.RequestsPerSecond <= 0 {
+		return nil, fmt.Errorf("requestsPerSecond must be positive")
+	}
+	if cfg.Burst <= 0 {
+		cfg.Burst = int(cfg.RequestsPerSecond)
+		if cfg.Burst < 1 {
+			cfg.Burst = 1
+		}
+	}
+
+	var (
+		mu     sync.Mutex
+		tokens = float64(cfg.Burst)
+		last   = time.Now()
+	)
+
+	return MiddlewareFunc(func(next Handler) Handler {
+		return func(call *Call) MCPResponse {
+			mu.Lock()
+			now := time.Now()
+			tokens += now.Sub(last).Seconds() * cfg.RequestsPerSecond
+			if tokens > float64(cfg.Burst) {
+				tokens = float64(cfg.Burst)
+			}
+			last = now
+			allowed := tokens >= 1
+			if allowed {
+				tokens--
+			}
+			mu.Unlock()
+
+			if !allowed {
+				return errorResponse(call, -32002, "rate limit exceeded")
+			}
+			return next(call)
+		}
+	}), nil
+}
+
+// cacheConfig configures the cache middleware
+type cacheConfig struct {
+	TTL     string   `json:"ttl"`     // Default: 30s
+	Methods []string `json:"methods"` // Default: the list methods
+}
+
+// maxCacheEntries bounds the responses a cache keeps, as each call with
+// new params adds one
+const maxCacheEntries = 1024
+
+// cacheEntry is a cached response
+type cacheEntry struct {
+	response MCPResponse
+	expires  time.Time
+}
+
+// newCacheMiddleware caches successful responses of read-only methods
+func newCacheMiddleware(config map[string]interface{}) (Middleware, error) {
+	cfg := cacheConfig{
+		TTL:     "30s",
+		Methods: []string{"tools/list", "resources/list", "prompts/list"},
+	}
+	if err := decodeConfig(config, &cfg); err != nil {
+		return nil, err
+	}
+	ttl, err := time.ParseDuration(cfg.TTL)
+	if err != nil || ttl <= 0 {
+		return nil, fmt.Errorf("invalid ttl '%s'", cfg.TTL)
+	}
+
+	methods := make(map[string]bool)
+	for _, method := range cfg.Methods {
+		methods[method] = true
+	}
+
+	var (
+		mu      sync.Mutex
+		entries = make(map[string]cacheEntry)
+	)
+
+	return MiddlewareFunc(func(next Handler) Handler {
+		return func(call *Call) MCPResponse {
+			if !methods[call.Request.Method] {
+				return next(call)
+			}
+
+			params, _ := json.Marshal(call.Request.Params)
+			key := call.Request.Method + " " + string(params)
+
+			mu.Lock()
+			entry, hit := entries[key]
+			mu.Unlock()
+			if hit && time.Now().Before(entry.expires) {
+				response := entry.response
+				response.ID = call.Request.ID
+				return response
+			}
+
+			response := next(call)
+			if response.Error == nil {
+				mu.Lock()
+				if _, exists := entries[key]; !exists && len(entries) >= maxCacheEntries {
+					evictCache(entries, time.Now())
+				}
+				entries[key] = cacheEntry{response: response, expires: time.Now().Add(ttl)}
+				mu.Unlock()
+			}
+			return response
+		}
+	}), nil
+}
+
+// evictCache removes the expired entries, or the one expiring first if
+// none have
+func evictCache(entries map[string]cacheEntry, now time.Time) {
+	oldest := ""
+	for key, entry := range entries {
+		if now.After(entry.expires) {
+			delete(entries, key)
+			continue
+		}
+		if oldest == "" || entry.expires.Before(entries[oldest].expires) {
+			oldest = key
+		}
+	}
+	if len(entries) >= maxCacheEntries {
+		delete(entries, oldest)
+	}
+}
@@ -0,0 +1,204 @@
+package proxy
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/internal/redact"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// echoHandler answers every call with its method
+func echoHandler(calls *int) Handler {
+	return func(call *Call) MCPResponse {
+		*calls++
+		return MCPResponse{JSONRPC: "2.0", ID: call.Request.ID, Result: call.Request.Method}
+	}
+}
+
+func newCall(method string) *Call {
+	return &Call{Request: MCPRequest{JSONRPC: "2.0", ID: 7, Method: method}}
+}
+
+func TestChain_Order(t *testing.T) {
+	var order []string
+	tag := func(name string) Middleware {
+		return MiddlewareFunc(func(next Handler) Handler {
+			return func(call *Call) MCPResponse {
+				order = append(order, name)
+				return next(call)
+			}
+		})
+	}
+
+	calls := 0
+	handler := chain(echoHandler(&calls), []Middleware{tag("first"), tag("second")})
+	handler(newCall("ping"))
+
+	assert.Equal(t, []string{"first", "second"}, order)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRegisterMiddleware(t *testing.T) {
+	factory := func(config map[string]interface{}) (Middleware, error) {
+		return MiddlewareFunc(func(next Handler) Handler { return next }), nil
+	}
+
+	require.NoError(t, RegisterMiddleware("test-custom", factory))
+	assert.Error(t, RegisterMiddleware("test-custom", factory), "duplicate names are rejected")
+	assert.Contains(t, RegisteredMiddleware(), "test-custom")
+
+	chain, err := buildMiddleware([]server.MiddlewareConfig{{Name: "test-custom"}, {Name: "logging"}})
+	require.NoError(t, err)
+	assert.Len(t, chain, 2)
+
+	_, err = buildMiddleware([]server.MiddlewareConfig{{Name: "missing"}})
+	assert.Error(t, err)
+}
+
+func TestLoggingMiddleware_RedactsPayloads(t *testing.T) {
+	var buf bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&buf)
+
+	mw, err := newLoggingMiddleware(map[string]interface{}{"payloads": true})
+	require.NoError(t, err)
+
+	redactor, err := redact.New([]redact.Rule{{Path: "$.params.arguments.query"}})
+	require.NoError(t, err)
+
+	calls := 0
+	call := newCall("tools/call")
+	call.Redactor = redactor
+	call.Request.Params = map[string]interface{}{
+		"name":      "search",
+		"arguments": map[string]interface{}{"query": "private", "token": "ghp_secret", "limit": 5},
+	}
+	mw.Wrap(echoHandler(&calls))(call)
+
+	assert.Contains(t, buf.String(), `"limit":5`)
+	assert.NotContains(t, buf.String(), "private")
+	assert.NotContains(t, buf.String(), "ghp_secret")
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	_, err := newAuthMiddleware(nil)
+	assert.Error(t, err, "token is required")
+
+	mw, err := newAuthMiddleware(map[string]interface{}{"token": "secret"})
+	require.NoError(t, err)
+
+	calls := 0
+	handler := mw.Wrap(echoHandler(&calls))
+
+	call := newCall("tools/call")
+	call.HTTP, _ = http.NewRequest(http.MethodPost, "/", nil)
+	response := handler(call)
+	require.NotNil(t, response.Error)
+	assert.Equal(t, "unauthorized", response.Error.Message)
+
+	call.HTTP.Header.Set("Authorization", "Bearer secret")
+	response = handler(call)
+	assert.Nil(t, response.Error)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	_, err := newRateLimitMiddleware(map[string]interface{}{})
+	assert.Error(t, err)
+
+	mw, err := newRateLimitMiddleware(map[string]interface{}{"requestsPerSecond": 1, "burst": 2})
+	require.NoError(t, err)
+
+	calls := 0
+	handler := mw.Wrap(echoHandler(&calls))
+
+	assert.Nil(t, handler(newCall("tools/call")).Error)
+	assert.Nil(t, handler(newCall("tools/call")).Error)
+
+	response := handler(newCall("tools/call"))
+	require.NotNil(t, response.Error)
+	assert.Equal(t, "rate limit exceeded", response.Error.Message)
+	assert.Equal(t, 2, calls)
+}
+
+func TestCacheMiddleware(t *testing.T) {
+	_, err := newCacheMiddleware(map[string]interface{}{"ttl": "never"})
+	assert.Error(t, err)
+
+	mw, err := newCacheMiddleware(map[string]interface{}{"ttl": "50ms"})
+	require.NoError(t, err)
+
+	calls := 0
+	handler := mw.Wrap(echoHandler(&calls))
+
+	// List methods are cached, with the ID of the current request
+	handler(newCall("tools/list"))
+	call := newCall("tools/list")
+	call.Request.ID = 8
+	assert.Equal(t, 8, handler(call).ID)
+	assert.Equal(t, 1, calls)
+
+	// Other methods are not
+	handler(newCall("tools/call"))
+	handler(newCall("tools/call"))
+	assert.Equal(t, 3, calls)
+
+	// Entries expire
+	time.Sleep(60 * time.Millisecond)
+	handler(newCall("tools/list"))
+	assert.Equal(t, 4, calls)
+}
+
+func TestEvictCache(t *testing.T) {
+	now := time.Now()
+	entries := map[string]cacheEntry{"expired": {expires: now.Add(-time.Second)}}
+	for i := 1; i < maxCacheEntries; i++ {
+		entries[strconv.Itoa(i)] = cacheEntry{expires: now.Add(time.Duration(i) * time.Second)}
+	}
+
+	// Expired entries go first
+	evictCache(entries, now)
+	assert.Len(t, entries, maxCacheEntries-1)
+	assert.NotContains(t, entries, "expired")
+
+	// Then the entry expiring first
+	entries["new"] = cacheEntry{expires: now.Add(time.Hour)}
+	evictCache(entries, now)
+	assert.Len(t, entries, maxCacheEntries-1)
+	assert.NotContains(t, entries, "1")
+	assert.Contains(t, entries, "new")
+}
+
+func TestServer_MiddlewareChain(t *testing.T) {
+	proxyServer := NewWithOptions(8099, getMockMCPCommand(), Options{
+		Middleware: []server.MiddlewareConfig{{Name: "auth", Config: map[string]interface{}{"token": "secret"}}},
+	})
+	require.NoError(t, proxyServer.Start())
+	defer proxyServer.Stop()
+
+	time.Sleep(100 * time.Millisecond)
+
+	resp, err := http.Get("http://localhost:8099/tools/list")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode, "unauthenticated calls are rejected")
+
+	// Internal tool refreshes bypass the chain
+	tools, err := proxyServer.getToolsFromMCP()
+	require.NoError(t, err)
+	assert.Len(t, tools, 1)
+}
+
+func TestServer_UnknownMiddleware(t *testing.T) {
+	proxyServer := NewWithOptions(8100, getMockMCPCommand(), Options{
+		Middleware: []server.MiddlewareConfig{{Name: "missing"}},
+	})
+	assert.Error(t, proxyServer.Start())
+}
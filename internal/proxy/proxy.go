@@ -4,13 +4,23 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"net/http"
 	"os/exec"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/tartavull/mcp-manager/internal/auth"
+	"github.com/tartavull/mcp-manager/internal/cors"
+	"github.com/tartavull/mcp-manager/internal/redact"
+	"github.com/tartavull/mcp-manager/internal/server"
+	"github.com/tartavull/mcp-manager/internal/transcript"
 )
 
 // MCPRequest represents an MCP JSON-RPC request
@@ -27,12 +37,37 @@ type MCPResponse struct {
 	ID      int         `json:"id"`
 	Result  interface{} `json:"result,omitempty"`
 	Error   *MCPError   `json:"error,omitempty"`
+
+	failure failure // Set on errors generated when the MCP process didn't answer
+}
+
+// failure classifies the errors the proxy returns when the MCP process
+// couldn't answer a request, for middlewares deciding whether to retry
+type failure int
+
+const (
+	failureNone       failure = iota
+	failureNotSent            // The request never reached the process
+	failureNoResponse         // The process may have handled the request
+	failureTimeout            // The process didn't answer in time
+)
+
+// failureResponse builds the error response for a request the MCP process
+// couldn't answer
+func failureResponse(id int, kind failure, message string) MCPResponse {
+	return MCPResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error:   &MCPError{Code: -1, Message: message},
+		failure: kind,
+	}
 }
 
 // MCPError represents an MCP JSON-RPC error
 type MCPError struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
 }
 
 // ToolsListResult represents the result of tools/list method
@@ -42,41 +77,129 @@ type ToolsListResult struct {
 
 // Tool represents an MCP tool
 type Tool struct {
-	Name        string      `json:"name"`
-	Title       string      `json:"title,omitempty"`
-	Description string      `json:"description,omitempty"`
-	InputSchema interface{} `json:"inputSchema,omitempty"`
+	Name        string                  `json:"name"`
+	Title       string                  `json:"title,omitempty"`
+	Description string                  `json:"description,omitempty"`
+	InputSchema interface{}             `json:"inputSchema,omitempty"`
+	Annotations *server.ToolAnnotations `json:"annotations,omitempty"`
+}
+
+// Options configures the MCP process spawned by the proxy
+type Options struct {
+	// Env is the environment of the MCP process; nil inherits the proxy's environment
+	Env []string
+
+	// BindAddress is the interface to listen on; empty listens on all interfaces
+	BindAddress string
+
+	// ShadowCommand starts a shadow MCP process that receives a copy of all
+	// tools/call traffic; divergent results are logged. Empty disables mirroring.
+	ShadowCommand string
+
+	// Middleware is the configured middleware chain, outermost first
+	Middleware []server.MiddlewareConfig
+
+	// Middlewares are added by programs embedding the proxy and run before
+	// the configured chain
+	Middlewares []Middleware
+
+	// Redactor masks secrets in payloads before they are logged; nil uses
+	// the default rules
+	Redactor *redact.Redactor
+
+	// MaxResultSize truncates tools/call results larger than this many
+	// bytes; zero disables the limit
+	MaxResultSize int
+
+	// CORSOrigins lists the browser origins allowed to call the proxy;
+	// empty allows any origin
+	CORSOrigins []string
+
+	// SpillDir stores the full results of truncated calls, served on
+	// GET /results/{id}. Empty discards them.
+	SpillDir string
+
+	// Stderr receives the stderr lines of the MCP process, which are
+	// logged either way
+	Stderr io.Writer
+
+	// CircuitBreaker fails calls fast while the MCP process keeps failing
+	// to answer; nil disables it
+	CircuitBreaker *CircuitBreakerOptions
+
+	// Chaos injects faults into calls for testing; nil disables it
+	Chaos *server.Chaos
+
+	// ReadOnly rejects calls to tools that aren't read-only; nil allows
+	// all tools
+	ReadOnly *server.ReadOnly
+
+	// Approval holds calls to sensitive tools until an operator approves
+	// them; nil disables it
+	Approval *ApprovalOptions
+
+	// Transcript receives the calls of each client, identified by
+	// transcript.ClientID; nil disables recording
+	Transcript func(client string, entry transcript.Entry)
+
+	// Auth rejects MCP requests and blob downloads from clients it doesn't
+	// authenticate; nil leaves the proxy open. Health and tool count
+	// endpoints stay open.
+	Auth auth.Provider
+
+	// Standby keeps a second MCP process initialized, taking over at once
+	// when the serving one fails
+	Standby bool
 }
 
 // Server represents an HTTP proxy server for an MCP server
 type Server struct {
 	port      int
 	command   string
+	opts      Options
 	server    *http.Server
 	ctx       context.Context
 	cancel    context.CancelFunc
 	toolCount int
+	requests  atomic.Int64 // MCP requests handled
+	inFlight  atomic.Int64 // MCP requests being answered
 	mu        sync.RWMutex
 
 	// Persistent MCP process fields
-	mcpCmd      *exec.Cmd
-	mcpStdin    io.WriteCloser
-	mcpStdout   io.ReadCloser
-	mcpStderr   io.ReadCloser
-	mcpDecoder  *json.Decoder
-	mcpMu       sync.Mutex // Protects MCP I/O operations
-	initialized bool
+	mcp         *mcpProcess // nil when not running
+	mcpMu       sync.Mutex  // Protects MCP I/O operations
 	requestID   int
 	requestIDMu sync.Mutex // Protects requestID counter
+
+	// Warm standby, see standby.go
+	standbyMu  sync.Mutex
+	standby    *mcpProcess // nil unless a standby is ready
+	warming    bool        // A standby is being started
+	generation int         // Bumped when the command changes, so stale standbys are discarded
+
+	shadow  *shadow  // nil unless mirroring is enabled
+	handler Handler  // Middleware chain around handle
+	spilled []string // IDs of spilled results, oldest first
+	blobs   []*blob  // Recent blobs from tool and resource results, oldest first
 }
 
 // New creates a new HTTP proxy server
 func New(port int, command string) *Server {
+	return NewWithOptions(port, command, Options{})
+}
+
+// NewWithOptions creates a new HTTP proxy server with process options
+func NewWithOptions(port int, command string, opts Options) *Server {
 	ctx, cancel := context.WithCancel(context.Background())
 
+	if opts.Redactor == nil {
+		opts.Redactor = redact.Default()
+	}
+
 	return &Server{
 		port:    port,
 		command: command,
+		opts:    opts,
 		ctx:     ctx,
 		cancel:  cancel,
 	}
@@ -84,11 +207,46 @@ func New(port int, command string) *Server {
 
 // Start starts the HTTP proxy server
 func (s *Server) Start() error {
+	configured, err := buildMiddleware(s.opts.Middleware)
+	if err != nil {
+		return err
+	}
+	var middlewares []Middleware
+	if s.opts.Transcript != nil {
+		// Outermost, so transcripts show what clients got back
+		middlewares = append(middlewares, &recorder{record: s.opts.Transcript})
+	}
+	middlewares = append(middlewares, s.opts.Middlewares...)
+	if s.opts.ReadOnly != nil {
+		// Before the configured chain, so rejected calls cost nothing
+		middlewares = append(middlewares, newReadOnly(*s.opts.ReadOnly))
+	}
+	if s.opts.Approval != nil {
+		middlewares = append(middlewares, newApproval(*s.opts.Approval))
+	}
+	middlewares = append(middlewares, configured...)
+	if s.opts.CircuitBreaker != nil {
+		// Innermost, so each retry counts
+		middlewares = append(middlewares, newBreaker(*s.opts.CircuitBreaker))
+	}
+	if s.opts.Chaos != nil {
+		// Innermost, so faults look like the MCP process failing
+		log.Printf("Chaos mode enabled on port %d", s.port)
+		middlewares = append(middlewares, newChaos(*s.opts.Chaos, s.crashMCPProcess))
+	}
+	s.handler = chain(s.handle, middlewares)
+
 	// Start the persistent MCP process first
 	if err := s.startMCPProcess(); err != nil {
 		return fmt.Errorf("failed to start MCP process: %w", err)
 	}
 
+	// The shadow is best effort and never blocks the primary
+	if s.opts.ShadowCommand != "" {
+		go s.startShadow()
+	}
+	s.warmStandby()
+
 	mux := http.NewServeMux()
 
 	// Health check endpoint
@@ -97,14 +255,22 @@ func (s *Server) Start() error {
 	// Tool count endpoint (GET)
 	mux.HandleFunc("/tools/count", s.handleToolsCount)
 
-	// Tools list endpoint (GET)
-	mux.HandleFunc("/tools/list", s.handleToolsList)
+	// Tools list endpoint (GET), which reveals the tools as the MCP
+	// endpoint does
+	mux.Handle("/tools/list", s.guard(s.handleToolsList))
+
+	// Decoded blobs from tool and resource results (GET)
+	mux.Handle("GET /resources/blobs", s.guard(s.handleBlobs))
+	mux.Handle("GET /resources/blob/{id}", s.guard(s.handleBlob))
+
+	// Spilled results of truncated calls (GET)
+	mux.Handle("GET /results/{id}", s.guard(s.handleResult))
 
 	// Full MCP proxy (POST)
-	mux.HandleFunc("/", s.handleMCPProxy)
+	mux.Handle("/", s.guard(s.handleMCPProxy))
 
 	s.server = &http.Server{
-		Addr:    fmt.Sprintf(":%d", s.port),
+		Addr:    net.JoinHostPort(s.opts.BindAddress, strconv.Itoa(s.port)),
 		Handler: s.enableCORS(mux),
 	}
 
@@ -126,7 +292,19 @@ func (s *Server) Stop() error {
 	s.cancel()
 
 	// Stop the persistent MCP process
+	s.mcpMu.Lock()
 	s.stopMCPProcess()
+	s.mcpMu.Unlock()
+	s.stopStandby()
+
+	s.mu.Lock()
+	if s.shadow != nil {
+		s.shadow.stop()
+		s.shadow = nil
+	}
+	s.mu.Unlock()
+
+	s.removeSpilledResults()
 
 	if s.server != nil {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -137,6 +315,13 @@ func (s *Server) Stop() error {
 	return nil
 }
 
+// Command returns the command MCP processes are started with
+func (s *Server) Command() string {
+	s.standbyMu.Lock()
+	defer s.standbyMu.Unlock()
+	return s.command
+}
+
 // GetToolCount returns the current tool count
 func (s *Server) GetToolCount() int {
 	s.mu.RLock()
@@ -144,20 +329,24 @@ func (s *Server) GetToolCount() int {
 	return s.toolCount
 }
 
-// enableCORS adds CORS headers to responses
-func (s *Server) enableCORS(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+// Requests returns the number of MCP requests the proxy has handled
+func (s *Server) Requests() int64 {
+	return s.requests.Load()
+}
 
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
-			return
-		}
+// InFlight returns the number of MCP requests the proxy is answering
+func (s *Server) InFlight() int64 {
+	return s.inFlight.Load()
+}
 
-		next.ServeHTTP(w, r)
-	})
+// enableCORS adds CORS headers to responses
+func (s *Server) enableCORS(next http.Handler) http.Handler {
+	policy := cors.Policy{
+		AllowedOrigins: s.opts.CORSOrigins,
+		AllowedMethods: []string{"GET", "POST", "OPTIONS"},
+		AllowedHeaders: []string{"Content-Type", "Authorization"},
+	}
+	return policy.Handler(next)
 }
 
 // handleHealth handles health check requests
@@ -167,6 +356,9 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 		"timestamp": time.Now().Format(time.RFC3339),
 		"port":      s.port,
 	}
+	if s.opts.Standby {
+		response["standby"] = s.standbyState()
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
@@ -193,7 +385,7 @@ func (s *Server) handleToolsList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tools, err := s.getToolsFromMCP()
+	tools, err := s.listTools(s.handler, r)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Failed to get tools: %v", err), http.StatusInternalServerError)
 		return
@@ -218,12 +410,57 @@ func (s *Server) handleMCPProxy(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response := s.proxyMCPRequest(request)
+	// The IDs agents give their calls go with them, and back in the response
+	correlation := transcript.CorrelationOf(r)
+	if correlation != (transcript.Correlation{}) {
+		r = r.WithContext(transcript.WithCorrelation(r.Context(), correlation))
+		correlation.SetHeaders(w.Header())
+	}
+
+	s.requests.Add(1)
+	s.inFlight.Add(1)
+	response := s.handler(s.newCall(request, r))
+	s.inFlight.Add(-1)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
+// guard authenticates the clients of a handler when the proxy requires it.
+// Authenticated clients are named in transcripts after their identity,
+// except the daemon, which forwards the identity of gateway clients.
+func (s *Server) guard(handler http.HandlerFunc) http.Handler {
+	return auth.Require(s.opts.Auth, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if identity := auth.FromContext(r.Context()); identity != nil && identity.Provider != auth.DaemonClient {
+			r.Header.Set(transcript.ClientHeader, "user:"+identity.Subject)
+		}
+		handler(w, r)
+	}))
+}
+
+// newCall creates a call for the middleware chain
+func (s *Server) newCall(request MCPRequest, r *http.Request) *Call {
+	return &Call{Request: request, Port: s.port, HTTP: r, Redactor: s.opts.Redactor}
+}
+
+// handle is the innermost handler of the middleware chain: it forwards the
+// call to the MCP process, mirrors tools/call traffic to the shadow, keeps
+// returned blobs and limits the size of tool results
+func (s *Server) handle(call *Call) MCPResponse {
+	response := s.proxyMCPRequest(call.Request)
+
+	switch call.Request.Method {
+	case "tools/call":
+		s.mirror(call.Request, response)
+		s.collectBlobs(call.Request, response)
+		response = s.limitResult(response)
+	case "resources/read":
+		s.collectBlobs(call.Request, response)
+	}
+
+	return response
+}
+
 // updateToolCount periodically updates the tool count
 func (s *Server) updateToolCount() {
 	// Initial delay to let the server fully start
@@ -268,8 +505,14 @@ func (s *Server) refreshToolCount() {
 	}
 }
 
-// getToolsFromMCP gets the list of tools from the MCP server
+// getToolsFromMCP gets the list of tools from the MCP server, bypassing
+// the middleware chain
 func (s *Server) getToolsFromMCP() ([]Tool, error) {
+	return s.listTools(s.handle, nil)
+}
+
+// listTools gets the list of tools through handler
+func (s *Server) listTools(handler Handler, r *http.Request) ([]Tool, error) {
 	// Use the persistent connection through proxyMCPRequest
 	toolsRequest := MCPRequest{
 		JSONRPC: "2.0",
@@ -278,24 +521,7 @@ func (s *Server) getToolsFromMCP() ([]Tool, error) {
 		Params:  map[string]interface{}{},
 	}
 
-	response := s.proxyMCPRequest(toolsRequest)
-
-	if response.Error != nil {
-		return nil, fmt.Errorf("MCP tools error: %s", response.Error.Message)
-	}
-
-	// Parse tools from response
-	resultBytes, err := json.Marshal(response.Result)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal tools result: %w", err)
-	}
-
-	var toolsResult ToolsListResult
-	if err := json.Unmarshal(resultBytes, &toolsResult); err != nil {
-		return nil, fmt.Errorf("failed to parse tools result: %w", err)
-	}
-
-	return toolsResult.Tools, nil
+	return parseTools(handler(s.newCall(toolsRequest, r)))
 }
 
 // proxyMCPRequest proxies a full MCP request to the stdio server
@@ -304,84 +530,46 @@ func (s *Server) proxyMCPRequest(request MCPRequest) MCPResponse {
 	defer s.mcpMu.Unlock()
 
 	// Check if process is initialized
-	if !s.initialized {
-		return MCPResponse{
-			JSONRPC: "2.0",
-			ID:      request.ID,
-			Error:   &MCPError{Code: -1, Message: "MCP process not initialized"},
-		}
+	if s.mcp == nil {
+		return failureResponse(request.ID, failureNotSent, "MCP process not initialized")
 	}
 
 	// Store original request ID
 	originalID := request.ID
 
-	// Update request ID to use our counter (we already hold the lock)
-	s.requestID++
-	request.ID = s.requestID
+	// Update request ID to use our counter
+	request.ID = s.getNextRequestID()
 
 	// Send the request
-	if err := json.NewEncoder(s.mcpStdin).Encode(request); err != nil {
+	if err := json.NewEncoder(s.mcp.stdin).Encode(request); err != nil {
 		// Try to restart the process if encoding fails
 		log.Printf("Failed to send request, attempting to restart MCP process: %v", err)
-		s.stopMCPProcess()
-		if restartErr := s.startMCPProcess(); restartErr != nil {
-			return MCPResponse{
-				JSONRPC: "2.0",
-				ID:      originalID,
-				Error:   &MCPError{Code: -1, Message: fmt.Sprintf("Failed to restart MCP process: %v", restartErr)},
-			}
+		if restartErr := s.restartMCPProcess(); restartErr != nil {
+			return failureResponse(originalID, failureNotSent, fmt.Sprintf("Failed to restart MCP process: %v", restartErr))
 		}
 		// Retry sending the request
-		if err := json.NewEncoder(s.mcpStdin).Encode(request); err != nil {
-			return MCPResponse{
-				JSONRPC: "2.0",
-				ID:      originalID,
-				Error:   &MCPError{Code: -1, Message: fmt.Sprintf("Failed to send request after restart: %v", err)},
-			}
+		if err := json.NewEncoder(s.mcp.stdin).Encode(request); err != nil {
+			return failureResponse(originalID, failureNotSent, fmt.Sprintf("Failed to send request after restart: %v", err))
 		}
 	}
 
 	// Read the response with timeout
-	responseChan := make(chan MCPResponse, 1)
-	errorChan := make(chan error, 1)
-
-	go func() {
-		var response MCPResponse
-		if err := s.mcpDecoder.Decode(&response); err != nil {
-			errorChan <- err
-		} else {
-			responseChan <- response
-		}
-	}()
-
-	select {
-	case response := <-responseChan:
-		// Update response ID to match original request
-		response.ID = originalID
-		return response
-	case err := <-errorChan:
+	response, err := s.mcp.read(30 * time.Second) // Increased timeout for browser operations
+	if err == errRequestTimeout {
+		return failureResponse(originalID, failureTimeout, "Request timeout")
+	}
+	if err != nil {
 		// Try to restart the process if decoding fails
 		log.Printf("Failed to read response, attempting to restart MCP process: %v", err)
-		s.stopMCPProcess()
-		if restartErr := s.startMCPProcess(); restartErr != nil {
-			return MCPResponse{
-				JSONRPC: "2.0",
-				ID:      originalID,
-				Error:   &MCPError{Code: -1, Message: fmt.Sprintf("Failed to restart MCP process: %v", restartErr)},
-			}
-		}
-		return MCPResponse{
-			JSONRPC: "2.0",
-			ID:      originalID,
-			Error:   &MCPError{Code: -1, Message: fmt.Sprintf("Failed to read response: %v", err)},
-		}
-	case <-time.After(30 * time.Second): // Increased timeout for browser operations
-		return MCPResponse{
-			JSONRPC: "2.0",
-			ID:      originalID,
-			Error:   &MCPError{Code: -1, Message: "Request timeout"},
+		if restartErr := s.restartMCPProcess(); restartErr != nil {
+			return failureResponse(originalID, failureNoResponse, fmt.Sprintf("Failed to restart MCP process: %v", restartErr))
 		}
+		return failureResponse(originalID, failureNoResponse, fmt.Sprintf("Failed to read response: %v", err))
 	}
+
+	// Update response ID to match original request
+	response.ID = originalID
+	return response
 }
 
 // startMCPProcess starts the persistent MCP process
@@ -389,37 +577,135 @@ func (s *Server) startMCPProcess() error {
 	s.mcpMu.Lock()
 	defer s.mcpMu.Unlock()
 
-	// Create the MCP process
-	s.mcpCmd = exec.CommandContext(s.ctx, "sh", "-c", s.command)
+	process, err := s.launchMCPProcess(s.command, s.opts.Env, s.opts.Stderr)
+	if err != nil {
+		return err
+	}
+
+	s.mcp = process
+	log.Printf("MCP process initialized successfully on port %d", s.port)
+
+	return nil
+}
+
+// restartMCPProcess replaces a failed MCP process with the standby, if
+// one is ready, or else a new process. Must be called with mcpMu held.
+func (s *Server) restartMCPProcess() error {
+	s.stopMCPProcess()
+
+	if standby := s.takeStandby(); standby != nil {
+		s.mcp = standby
+		log.Printf("Failed over to standby MCP process on port %d", s.port)
+		return nil
+	}
+
+	process, err := s.launchMCPProcess(s.command, s.opts.Env, s.opts.Stderr)
+	if err != nil {
+		return err
+	}
+
+	s.mcp = process
+	return nil
+}
+
+// Swap replaces the MCP process with a new one running command, without
+// closing the HTTP listener. The new process must complete the initialize
+// handshake and answer tools/list before it receives traffic; requests in
+// flight finish on the old process, which is stopped after the switch. If
+// the new process fails to become ready, the old one keeps serving. The
+// standby, if enabled, is replaced by one running command.
+func (s *Server) Swap(command string, env []string) error {
+	next, err := s.launchMCPProcess(command, env, s.opts.Stderr)
+	if err != nil {
+		return err
+	}
+
+	if err := next.ready(s.getNextRequestID()); err != nil {
+		next.stop()
+		return fmt.Errorf("new MCP process did not become ready: %w", err)
+	}
+
+	s.mcpMu.Lock()
+	previous := s.mcp
+	s.mcp = next
+	s.standbyMu.Lock()
+	s.command = command
+	s.opts.Env = env
+	s.standbyMu.Unlock()
+	s.mcpMu.Unlock()
+	s.replaceStandby()
+
+	if previous != nil {
+		previous.stop()
+	}
+	log.Printf("Switched port %d to new MCP process", s.port)
+
+	go s.refreshToolCount()
+
+	return nil
+}
+
+// stopMCPProcess stops the persistent MCP process. Must be called with
+// mcpMu held.
+func (s *Server) stopMCPProcess() {
+	if s.mcp != nil {
+		s.mcp.stop()
+		s.mcp = nil
+	}
+}
+
+// errRequestTimeout is returned when the MCP process doesn't answer in time
+var errRequestTimeout = errors.New("request timeout")
+
+// initTimeout bounds the initialize handshake, which may include a package download
+const initTimeout = 2 * time.Minute
+
+// mcpProcess is an MCP stdio process that completed the initialize handshake
+type mcpProcess struct {
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	stdout  io.ReadCloser
+	stderr  io.ReadCloser
+	decoder *json.Decoder
+}
+
+// launchMCPProcess starts an MCP process and performs the initialize
+// handshake, copying its stderr lines to stderr if not nil
+func (s *Server) launchMCPProcess(command string, env []string, stderr io.Writer) (*mcpProcess, error) {
+	p := &mcpProcess{cmd: exec.CommandContext(s.ctx, "sh", "-c", command)}
+	p.cmd.Env = env
 
 	var err error
-	s.mcpStdin, err = s.mcpCmd.StdinPipe()
+	p.stdin, err = p.cmd.StdinPipe()
 	if err != nil {
-		return fmt.Errorf("failed to create stdin pipe: %w", err)
+		return nil, fmt.Errorf("failed to create stdin pipe: %w", err)
 	}
 
-	s.mcpStdout, err = s.mcpCmd.StdoutPipe()
+	p.stdout, err = p.cmd.StdoutPipe()
 	if err != nil {
-		return fmt.Errorf("failed to create stdout pipe: %w", err)
+		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
 	}
 
-	s.mcpStderr, err = s.mcpCmd.StderrPipe()
+	p.stderr, err = p.cmd.StderrPipe()
 	if err != nil {
-		return fmt.Errorf("failed to create stderr pipe: %w", err)
+		return nil, fmt.Errorf("failed to create stderr pipe: %w", err)
 	}
 
-	if err := s.mcpCmd.Start(); err != nil {
-		return fmt.Errorf("failed to start MCP process: %w", err)
+	if err := p.cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start MCP process: %w", err)
 	}
 
 	// Create decoder for reading responses
-	s.mcpDecoder = json.NewDecoder(s.mcpStdout)
+	p.decoder = json.NewDecoder(p.stdout)
 
 	// Start stderr reader
 	go func() {
-		scanner := bufio.NewScanner(s.mcpStderr)
+		scanner := bufio.NewScanner(p.stderr)
 		for scanner.Scan() {
 			log.Printf("MCP stderr (port %d): %s", s.port, scanner.Text())
+			if stderr != nil {
+				fmt.Fprintln(stderr, scanner.Text())
+			}
 		}
 		if err := scanner.Err(); err != nil {
 			log.Printf("MCP stderr scanner error (port %d): %v", s.port, err)
@@ -445,45 +731,95 @@ func (s *Server) startMCPProcess() error {
 	}
 
 	// Send initialization request
-	if err := json.NewEncoder(s.mcpStdin).Encode(initRequest); err != nil {
-		s.stopMCPProcess()
-		return fmt.Errorf("failed to send init request: %w", err)
+	if err := json.NewEncoder(p.stdin).Encode(initRequest); err != nil {
+		p.stop()
+		return nil, fmt.Errorf("failed to send init request: %w", err)
 	}
 
 	// Read initialization response
-	var initResponse MCPResponse
-	if err := s.mcpDecoder.Decode(&initResponse); err != nil {
-		s.stopMCPProcess()
-		return fmt.Errorf("failed to read init response: %w", err)
+	initResponse, err := p.read(initTimeout)
+	if err != nil {
+		p.stop()
+		return nil, fmt.Errorf("failed to read init response: %w", err)
 	}
 
 	if initResponse.Error != nil {
-		s.stopMCPProcess()
-		return fmt.Errorf("MCP init error: %s", initResponse.Error.Message)
+		p.stop()
+		return nil, fmt.Errorf("MCP init error: %s", initResponse.Error.Message)
 	}
 
-	s.initialized = true
-	log.Printf("MCP process initialized successfully on port %d", s.port)
+	return p, nil
+}
 
-	return nil
+// rawResponse is a response whose result is passed through undecoded, so
+// large results are never expanded into generic values
+type rawResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      int             `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *MCPError       `json:"error,omitempty"`
 }
 
-// stopMCPProcess stops the persistent MCP process
-func (s *Server) stopMCPProcess() {
-	if s.mcpCmd != nil && s.mcpCmd.Process != nil {
-		s.mcpCmd.Process.Kill()
-		s.mcpCmd.Wait()
+// read reads the next response, giving up after timeout. The result is a
+// json.RawMessage.
+func (p *mcpProcess) read(timeout time.Duration) (MCPResponse, error) {
+	responseChan := make(chan MCPResponse, 1)
+	errorChan := make(chan error, 1)
+
+	go func() {
+		var raw rawResponse
+		if err := p.decoder.Decode(&raw); err != nil {
+			errorChan <- err
+			return
+		}
+		response := MCPResponse{JSONRPC: raw.JSONRPC, ID: raw.ID, Error: raw.Error}
+		if len(raw.Result) > 0 {
+			response.Result = raw.Result
+		}
+		responseChan <- response
+	}()
+
+	select {
+	case response := <-responseChan:
+		return response, nil
+	case err := <-errorChan:
+		return MCPResponse{}, err
+	case <-time.After(timeout):
+		return MCPResponse{}, errRequestTimeout
+	}
+}
+
+// ready checks that the process answers tools/list
+func (p *mcpProcess) ready(id int) error {
+	request := MCPRequest{
+		JSONRPC: "2.0",
+		ID:      id,
+		Method:  "tools/list",
+		Params:  map[string]interface{}{},
 	}
-	if s.mcpStdin != nil {
-		s.mcpStdin.Close()
+	if err := json.NewEncoder(p.stdin).Encode(request); err != nil {
+		return fmt.Errorf("failed to send tools/list: %w", err)
 	}
-	if s.mcpStdout != nil {
-		s.mcpStdout.Close()
+
+	response, err := p.read(30 * time.Second)
+	if err != nil {
+		return fmt.Errorf("failed to read tools/list response: %w", err)
 	}
-	if s.mcpStderr != nil {
-		s.mcpStderr.Close()
+	if response.Error != nil {
+		return fmt.Errorf("tools/list error: %s", response.Error.Message)
+	}
+	return nil
+}
+
+// stop kills the process and closes its pipes
+func (p *mcpProcess) stop() {
+	if p.cmd.Process != nil {
+		p.cmd.Process.Kill()
+		p.cmd.Wait()
 	}
-	s.initialized = false
+	p.stdin.Close()
+	p.stdout.Close()
+	p.stderr.Close()
 }
 
 // getNextRequestID returns the next request ID
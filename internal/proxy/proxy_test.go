@@ -5,59 +5,28 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"os"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/internal/auth"
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/mcpmock"
+	"github.com/tartavull/mcp-manager/internal/transcript"
 )
 
-// getMockMCPCommand returns a command that simulates an MCP server
-func getMockMCPCommand() string {
-	// This command creates a mock MCP server that handles multiple requests
-	return `python3 -c "
-import json
-import sys
-
-# Handle initialize request
-request = json.loads(sys.stdin.readline())
-response = {
-    'jsonrpc': '2.0',
-    'id': request['id'],
-    'result': {
-        'protocolVersion': '2024-11-05',
-        'capabilities': {'tools': {'listChanged': True}},
-        'serverInfo': {'name': 'mock-server', 'version': '1.0.0'}
-    }
+func TestMain(m *testing.M) {
+	mcpmock.RunIfHelper()
+	os.Exit(m.Run())
 }
-print(json.dumps(response))
-sys.stdout.flush()
-
-# Handle subsequent requests
-while True:
-    try:
-        request = json.loads(sys.stdin.readline())
-        if request['method'] == 'tools/list':
-            response = {
-                'jsonrpc': '2.0',
-                'id': request['id'],
-                'result': {
-                    'tools': [
-                        {'name': 'test_tool', 'description': 'A test tool'}
-                    ]
-                }
-            }
-        else:
-            response = {
-                'jsonrpc': '2.0',
-                'id': request['id'],
-                'result': {}
-            }
-        print(json.dumps(response))
-        sys.stdout.flush()
-    except:
-        break
-"`
+
+// getMockMCPCommand returns a command that simulates an MCP server with a
+// single tool, test_tool
+func getMockMCPCommand(args ...string) string {
+	return mcpmock.Command(args...)
 }
 
 func TestNew(t *testing.T) {
@@ -231,12 +200,17 @@ func TestServer_MCPProxyEndpoint(t *testing.T) {
 	requestBody, err := json.Marshal(request)
 	require.NoError(t, err)
 
-	resp, err := http.Post("http://localhost:8087/", "application/json", bytes.NewReader(requestBody))
+	req, err := http.NewRequest("POST", "http://localhost:8087/", bytes.NewReader(requestBody))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(transcript.RequestIDHeader, "req-1")
+	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
 	defer resp.Body.Close()
 
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "req-1", resp.Header.Get(transcript.RequestIDHeader), "request IDs are echoed")
 
 	var response MCPResponse
 	err = json.NewDecoder(resp.Body).Decode(&response)
@@ -244,6 +218,7 @@ func TestServer_MCPProxyEndpoint(t *testing.T) {
 
 	assert.Equal(t, "2.0", response.JSONRPC)
 	assert.Equal(t, 1, response.ID)
+	assert.Equal(t, int64(1), server.Requests())
 }
 
 func TestServer_MCPProxyEndpoint_InvalidJSON(t *testing.T) {
@@ -296,7 +271,7 @@ func TestServer_CORSHeaders(t *testing.T) {
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 	assert.Equal(t, "*", resp.Header.Get("Access-Control-Allow-Origin"))
 	assert.Equal(t, "GET, POST, OPTIONS", resp.Header.Get("Access-Control-Allow-Methods"))
-	assert.Equal(t, "Content-Type", resp.Header.Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "Content-Type, Authorization", resp.Header.Get("Access-Control-Allow-Headers"))
 }
 
 func TestServer_NotFoundEndpoint(t *testing.T) {
@@ -469,3 +444,102 @@ func TestServer_StopContext(t *testing.T) {
 	err = server.Stop()
 	require.NoError(t, err)
 }
+
+func TestServer_Swap(t *testing.T) {
+	server := New(8094, getMockMCPCommand())
+	require.NoError(t, server.Start())
+	defer server.Stop()
+
+	time.Sleep(100 * time.Millisecond)
+
+	toolNames := func() []string {
+		resp, err := http.Get("http://localhost:8094/tools/list")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var result ToolsListResult
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+
+		var names []string
+		for _, tool := range result.Tools {
+			names = append(names, tool.Name)
+		}
+		return names
+	}
+	assert.Equal(t, []string{"test_tool"}, toolNames())
+
+	// A process that never becomes ready leaves the current one serving
+	err := server.Swap("exit 1", nil)
+	assert.Error(t, err)
+	assert.Equal(t, []string{"test_tool"}, toolNames())
+
+	// A ready process takes over the port
+	newCommand := getMockMCPCommand("-tools", "new_tool")
+	require.NoError(t, server.Swap(newCommand, nil))
+	assert.Equal(t, []string{"new_tool"}, toolNames())
+}
+
+func TestServer_Guard(t *testing.T) {
+	provider, err := auth.Build(&config.AuthConfig{
+		Providers: map[string]*config.AuthProviderConfig{
+			"ci": {Type: "token", Tokens: map[string]string{"ci": "secret"}},
+		},
+	}, []string{"ci"})
+	require.NoError(t, err)
+	s := &Server{opts: Options{Auth: auth.Trust(provider, "internal")}}
+
+	var client string
+	handler := s.guard(func(w http.ResponseWriter, r *http.Request) {
+		client = r.Header.Get(transcript.ClientHeader)
+	})
+	serve := func(token, header string) int {
+		r := httptest.NewRequest(http.MethodPost, "/", nil)
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		if header != "" {
+			r.Header.Set(transcript.ClientHeader, header)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, serve("", ""))
+	assert.Equal(t, http.StatusUnauthorized, serve("wrong", "user:ci"))
+
+	assert.Equal(t, http.StatusOK, serve("secret", "someone-else"))
+	assert.Equal(t, "user:ci", client, "clients can't pose as others")
+
+	assert.Equal(t, http.StatusOK, serve("internal", "user:alice"))
+	assert.Equal(t, "user:alice", client, "the daemon forwards the client of the gateway")
+}
+
+func TestServer_ToolsListRequiresAuth(t *testing.T) {
+	provider, err := auth.Build(&config.AuthConfig{
+		Providers: map[string]*config.AuthProviderConfig{
+			"ci": {Type: "token", Tokens: map[string]string{"ci": "secret"}},
+		},
+	}, []string{"ci"})
+	require.NoError(t, err)
+	server := NewWithOptions(8108, getMockMCPCommand(), Options{Auth: provider})
+	require.NoError(t, server.Start())
+	defer server.Stop()
+
+	time.Sleep(100 * time.Millisecond)
+
+	get := func(token string) int {
+		req, err := http.NewRequest(http.MethodGet, "http://localhost:8108/tools/list", nil)
+		require.NoError(t, err)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, get(""))
+	assert.Equal(t, http.StatusOK, get("secret"))
+}
@@ -0,0 +1,54 @@
+package proxy
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// codeReadOnly is the error code of calls rejected by read-only mode
+const codeReadOnly = -32004
+
+// readOnly rejects calls to the tools a server.ReadOnly doesn't allow
+type readOnly struct {
+	opts    server.ReadOnly
+	catalog toolCatalog
+}
+
+// newReadOnly creates the read-only mode of a proxy
+func newReadOnly(opts server.ReadOnly) *readOnly {
+	return &readOnly{opts: opts}
+}
+
+// Wrap makes read-only mode a Middleware
+func (r *readOnly) Wrap(next Handler) Handler {
+	return func(call *Call) MCPResponse {
+		if call.Request.Method == "tools/list" {
+			return r.catalog.observe(call, next)
+		}
+		if call.Request.Method != "tools/call" {
+			return next(call)
+		}
+
+		name := toolName(call.Request)
+		if r.allowed(call, next, name) {
+			return next(call)
+		}
+		response := errorResponse(call, codeReadOnly, fmt.Sprintf("tool '%s' is not allowed in read-only mode", name))
+		response.Error.Data = map[string]interface{}{"tool": name}
+		return response
+	}
+}
+
+// allowed returns true if a tool may be called in read-only mode
+func (r *readOnly) allowed(call *Call, next Handler, name string) bool {
+	if slices.Contains(r.opts.Deny, name) {
+		return false
+	}
+	if slices.Contains(r.opts.Allow, name) {
+		return true
+	}
+	tool, exists := r.catalog.lookup(call, next, name)
+	return exists && tool.Annotations != nil && tool.Annotations.ReadOnlyHint
+}
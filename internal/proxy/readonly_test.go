@@ -0,0 +1,49 @@
+package proxy
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+func TestReadOnly(t *testing.T) {
+	r := newReadOnly(server.ReadOnly{Allow: []string{"run_query"}, Deny: []string{"export"}})
+
+	var called []string
+	handler := r.Wrap(func(call *Call) MCPResponse {
+		if call.Request.Method == "tools/list" {
+			return MCPResponse{JSONRPC: "2.0", ID: call.Request.ID, Result: json.RawMessage(`{"tools": [
+				{"name": "read_file", "annotations": {"readOnlyHint": true}},
+				{"name": "export", "annotations": {"readOnlyHint": true}},
+				{"name": "delete_file", "annotations": {"destructiveHint": true}},
+				{"name": "run_query"},
+				{"name": "write_file"}
+			]}`)}
+		}
+		called = append(called, toolName(call.Request))
+		return MCPResponse{JSONRPC: "2.0", ID: call.Request.ID, Result: "ok"}
+	})
+	callTool := func(name string) MCPResponse {
+		call := newCall("tools/call")
+		call.Request.Params = map[string]interface{}{"name": name}
+		return handler(call)
+	}
+
+	// Read-only tools and allowed ones pass
+	assert.Nil(t, callTool("read_file").Error)
+	assert.Nil(t, callTool("run_query").Error)
+
+	// Others are rejected, including unannotated and unknown tools
+	for _, name := range []string{"export", "delete_file", "write_file", "missing"} {
+		response := callTool(name)
+		require.NotNil(t, response.Error, name)
+		assert.Equal(t, codeReadOnly, response.Error.Code)
+	}
+	assert.Equal(t, []string{"read_file", "run_query"}, called)
+
+	// Other methods pass through
+	assert.Nil(t, handler(newCall("resources/list")).Error)
+}
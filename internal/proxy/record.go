@@ -0,0 +1,66 @@
+package proxy
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/tartavull/mcp-manager/internal/transcript"
+)
+
+// maxRecordedResult is the size of the results kept in transcripts
+const maxRecordedResult = 4096
+
+// recorder adds the calls of each client to its session transcript
+type recorder struct {
+	record func(client string, entry transcript.Entry)
+}
+
+// Wrap makes the recorder a Middleware
+func (r *recorder) Wrap(next Handler) Handler {
+	return func(call *Call) MCPResponse {
+		method := call.Request.Method
+		if method == "ping" || strings.HasPrefix(method, "notifications/") {
+			return next(call)
+		}
+
+		start := time.Now()
+		response := next(call)
+
+		entry := transcript.Entry{
+			Time:       start,
+			Method:     method,
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+		arguments := call.Request.Params
+		if method == "tools/call" {
+			entry.Tool = toolName(call.Request)
+			if params, ok := arguments.(map[string]interface{}); ok {
+				arguments = params["arguments"]
+			}
+		}
+		if arguments != nil {
+			entry.Arguments = call.Redactor.JSON(arguments)
+		}
+		if response.Error != nil {
+			entry.Error = response.Error.Message
+		} else if response.Result != nil {
+			entry.Result = call.Redactor.JSON(response.Result)
+			if len(entry.Result) > maxRecordedResult {
+				cut := maxRecordedResult
+				for cut > 0 && !utf8.RuneStart(entry.Result[cut]) {
+					cut--
+				}
+				entry.Result = entry.Result[:cut] + "…"
+			}
+		}
+
+		client := "unknown"
+		if call.HTTP != nil {
+			client = transcript.ClientID(call.HTTP)
+			entry.Correlation = transcript.CorrelationOf(call.HTTP)
+		}
+		r.record(client, entry)
+		return response
+	}
+}
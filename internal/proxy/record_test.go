@@ -0,0 +1,65 @@
+package proxy
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/internal/transcript"
+)
+
+func TestRecorder(t *testing.T) {
+	var clients []string
+	var entries []transcript.Entry
+	r := &recorder{record: func(client string, entry transcript.Entry) {
+		clients = append(clients, client)
+		entries = append(entries, entry)
+	}}
+
+	result := ""
+	handler := r.Wrap(func(call *Call) MCPResponse {
+		if toolName(call.Request) == "fail" {
+			return errorResponse(call, -32603, "boom")
+		}
+		return MCPResponse{JSONRPC: "2.0", ID: call.Request.ID, Result: map[string]interface{}{"text": result}}
+	})
+	callTool := func(name string) {
+		call := newCall("tools/call")
+		call.HTTP = httptest.NewRequest("POST", "/", nil)
+		call.HTTP.Header.Set(transcript.ClientHeader, "agent")
+		call.HTTP.Header.Set(transcript.RunIDHeader, "run-1")
+		call.Request.Params = map[string]interface{}{"name": name, "arguments": map[string]interface{}{"q": "x", "token": "secret"}}
+		handler(call)
+	}
+
+	// Pings and notifications aren't part of transcripts
+	handler(newCall("ping"))
+	handler(newCall("notifications/initialized"))
+	assert.Empty(t, entries)
+
+	result = "found"
+	callTool("search")
+	callTool("fail")
+	handler(newCall("tools/list"))
+	require.Len(t, entries, 3)
+	assert.Equal(t, []string{"agent", "agent", "unknown"}, clients)
+
+	assert.Equal(t, "tools/call", entries[0].Method)
+	assert.Equal(t, "search", entries[0].Tool)
+	assert.Contains(t, entries[0].Arguments, `"q":"x"`)
+	assert.NotContains(t, entries[0].Arguments, "secret")
+	assert.Equal(t, `{"text":"found"}`, entries[0].Result)
+	assert.Equal(t, "run-1", entries[0].RunID)
+	assert.Equal(t, "boom", entries[1].Error)
+	assert.Empty(t, entries[1].Result)
+	assert.Equal(t, "tools/list", entries[2].Method)
+
+	// Large results are shortened
+	result = strings.Repeat("é", maxRecordedResult)
+	callTool("search")
+	recorded := entries[3].Result
+	assert.LessOrEqual(t, len(recorded), maxRecordedResult+len("…"))
+	assert.True(t, strings.HasSuffix(recorded, "é…"))
+}
@@ -0,0 +1,157 @@
+package proxy
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"unicode/utf8"
+)
+
+// maxSpilledResults bounds the number of spilled results kept on disk per
+// server; the oldest are deleted first
+const maxSpilledResults = 50
+
+// resultIDPattern matches the IDs of spilled results
+var resultIDPattern = regexp.MustCompile(`^[0-9a-f]{16}$`)
+
+// limitResult truncates a tools/call result larger than MaxResultSize,
+// spilling the full result to disk when SpillDir is set. Text content is
+// kept up to the limit and other oversized content is replaced by a marker,
+// so clients still receive a valid tool result.
+func (s *Server) limitResult(response MCPResponse) MCPResponse {
+	raw, ok := response.Result.(json.RawMessage)
+	if !ok || s.opts.MaxResultSize <= 0 || len(raw) <= s.opts.MaxResultSize {
+		return response
+	}
+
+	marker := fmt.Sprintf("[Result truncated: %d bytes exceeds the limit of %d bytes]", len(raw), s.opts.MaxResultSize)
+	meta := map[string]interface{}{"truncated": true, "originalSize": len(raw)}
+
+	if s.opts.SpillDir != "" {
+		id, err := s.spillResult(raw)
+		if err != nil {
+			log.Printf("Failed to spill result on port %d: %v", s.port, err)
+		} else {
+			marker = fmt.Sprintf("[Result truncated: %d bytes exceeds the limit of %d bytes. Full result: GET /results/%s]",
+				len(raw), s.opts.MaxResultSize, id)
+			meta["resultId"] = id
+		}
+	}
+
+	var result struct {
+		Content []map[string]interface{} `json:"content"`
+		IsError bool                     `json:"isError,omitempty"`
+	}
+	json.Unmarshal(raw, &result)
+
+	content := truncateContent(result.Content, s.opts.MaxResultSize)
+	content = append(content, map[string]interface{}{"type": "text", "text": marker})
+
+	truncated := map[string]interface{}{"content": content, "_meta": meta}
+	if result.IsError {
+		truncated["isError"] = true
+	}
+	response.Result = truncated
+	return response
+}
+
+// truncateContent keeps content items until budget bytes are used. A text
+// item crossing the budget is cut; other items are replaced by a note.
+func truncateContent(items []map[string]interface{}, budget int) []map[string]interface{} {
+	var kept []map[string]interface{}
+	for i, item := range items {
+		data, _ := json.Marshal(item)
+		if len(data) <= budget {
+			kept = append(kept, item)
+			budget -= len(data)
+			continue
+		}
+
+		if text, ok := item["text"].(string); ok && item["type"] == "text" && budget > 0 {
+			if budget < len(text) {
+				// Don't split a multi-byte character
+				for budget > 0 && !utf8.RuneStart(text[budget]) {
+					budget--
+				}
+				text = text[:budget]
+			}
+			kept = append(kept, map[string]interface{}{"type": "text", "text": text})
+		} else {
+			kept = append(kept, map[string]interface{}{
+				"type": "text",
+				"text": fmt.Sprintf("[%v content omitted: %d bytes]", item["type"], len(data)),
+			})
+		}
+
+		if omitted := len(items) - i - 1; omitted > 0 {
+			kept = append(kept, map[string]interface{}{
+				"type": "text",
+				"text": fmt.Sprintf("[%d more content items omitted]", omitted),
+			})
+		}
+		break
+	}
+	return kept
+}
+
+// spillResult writes a result to the spill directory and returns its ID
+func (s *Server) spillResult(raw json.RawMessage) (string, error) {
+	if err := os.MkdirAll(s.opts.SpillDir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create spill directory: %w", err)
+	}
+
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate result ID: %w", err)
+	}
+	id := hex.EncodeToString(buf)
+
+	if err := os.WriteFile(filepath.Join(s.opts.SpillDir, id+".json"), raw, 0600); err != nil {
+		return "", fmt.Errorf("failed to write result: %w", err)
+	}
+
+	s.mu.Lock()
+	s.spilled = append(s.spilled, id)
+	var expired []string
+	if len(s.spilled) > maxSpilledResults {
+		expired = s.spilled[:len(s.spilled)-maxSpilledResults]
+		s.spilled = append([]string{}, s.spilled[len(expired):]...)
+	}
+	s.mu.Unlock()
+
+	for _, old := range expired {
+		os.Remove(filepath.Join(s.opts.SpillDir, old+".json"))
+	}
+
+	return id, nil
+}
+
+// removeSpilledResults deletes the results spilled by this server
+func (s *Server) removeSpilledResults() {
+	s.mu.Lock()
+	spilled := s.spilled
+	s.spilled = nil
+	s.mu.Unlock()
+
+	for _, id := range spilled {
+		os.Remove(filepath.Join(s.opts.SpillDir, id+".json"))
+	}
+}
+
+// handleResult serves a spilled result
+func (s *Server) handleResult(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if s.opts.SpillDir == "" || !resultIDPattern.MatchString(id) {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	http.ServeFile(w, r, filepath.Join(s.opts.SpillDir, id+".json"))
+}
@@ -0,0 +1,102 @@
+package proxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bigResultCommand answers tools/call with a 10000 character text result
+// followed by an image
+var bigResultCommand = getMockMCPCommand("-result",
+	`{"content": [{"type": "text", "text": "`+strings.Repeat("x", 10000)+`"}, {"type": "image", "data": "aGk=", "mimeType": "image/png"}]}`)
+
+func postToolCall(t *testing.T, port int) map[string]interface{} {
+	t.Helper()
+
+	body, err := json.Marshal(MCPRequest{JSONRPC: "2.0", ID: 1, Method: "tools/call", Params: map[string]interface{}{"name": "dump"}})
+	require.NoError(t, err)
+
+	resp, err := http.Post(fmt.Sprintf("http://localhost:%d/", port), "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var response struct {
+		Result map[string]interface{} `json:"result"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+	return response.Result
+}
+
+func TestTruncateContent(t *testing.T) {
+	items := []map[string]interface{}{
+		{"type": "text", "text": "hello"},
+		{"type": "text", "text": "héllo world"},
+		{"type": "text", "text": "never reached"},
+	}
+
+	// The first item is 30 bytes encoded, leaving 2 bytes for the second,
+	// which must not split the multi-byte character
+	kept := truncateContent(items, 32)
+	require.Len(t, kept, 3)
+	assert.Equal(t, items[0], kept[0])
+	assert.Equal(t, "h", kept[1]["text"])
+	assert.Equal(t, "[1 more content items omitted]", kept[2]["text"])
+
+	kept = truncateContent([]map[string]interface{}{{"type": "image", "data": "aGk="}}, 5)
+	require.Len(t, kept, 1)
+	assert.Contains(t, kept[0]["text"], "image content omitted")
+}
+
+func TestServer_LimitResult(t *testing.T) {
+	server := NewWithOptions(8101, bigResultCommand, Options{MaxResultSize: 1000})
+	require.NoError(t, server.Start())
+	defer server.Stop()
+
+	time.Sleep(100 * time.Millisecond)
+
+	result := postToolCall(t, 8101)
+	content := result["content"].([]interface{})
+	require.Len(t, content, 3)
+	assert.Len(t, content[0].(map[string]interface{})["text"], 1000)
+	assert.Equal(t, "[1 more content items omitted]", content[1].(map[string]interface{})["text"])
+	assert.Contains(t, content[2].(map[string]interface{})["text"], "Result truncated")
+	assert.Equal(t, true, result["_meta"].(map[string]interface{})["truncated"])
+}
+
+func TestServer_SpillResult(t *testing.T) {
+	spillDir := t.TempDir()
+	server := NewWithOptions(8102, bigResultCommand, Options{MaxResultSize: 1000, SpillDir: spillDir})
+	require.NoError(t, server.Start())
+
+	time.Sleep(100 * time.Millisecond)
+
+	result := postToolCall(t, 8102)
+	id, ok := result["_meta"].(map[string]interface{})["resultId"].(string)
+	require.True(t, ok)
+
+	resp, err := http.Get("http://localhost:8102/results/" + id)
+	require.NoError(t, err)
+	full, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(full), strings.Repeat("x", 10000))
+
+	resp, err = http.Get("http://localhost:8102/results/mcp.json")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// Spilled results are removed when the proxy stops
+	require.NoError(t, server.Stop())
+	assert.NoFileExists(t, spillDir+"/"+id+".json")
+}
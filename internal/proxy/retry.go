@@ -0,0 +1,152 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// codeCanceled is the JSON-RPC error code of calls whose client went away
+// while waiting to retry them
+const codeCanceled = -32006
+
+// idempotentMethods are retried by default even if the MCP process may
+// have handled the failed attempt
+var idempotentMethods = []string{
+	"ping",
+	"tools/list",
+	"resources/list",
+	"resources/templates/list",
+	"resources/read",
+	"prompts/list",
+	"prompts/get",
+}
+
+// retryConfig configures the retry middleware
+type retryConfig struct {
+	Attempts int      `json:"attempts"` // Total attempts per call (default: 3)
+	Backoff  string   `json:"backoff"`  // Delay before the first retry, doubled after each one (default: 100ms)
+	Methods  []string `json:"methods"`  // Idempotent methods (default: ping and the list, read and get methods)
+	Tools    []string `json:"tools"`    // Idempotent tools, whose tools/call may be retried
+
+	// Budget bounds retries so a server that is down isn't hammered: each
+	// call earns Ratio retries, up to Burst saved (default: 0.1 and 10)
+	Budget struct {
+		Ratio float64 `json:"ratio"`
+		Burst float64 `json:"burst"`
+	} `json:"budget"`
+}
+
+// retryBudget is a token bucket filled by calls and drained by retries
+type retryBudget struct {
+	mu     sync.Mutex
+	tokens float64
+	ratio  float64
+	burst  float64
+}
+
+// deposit adds the share of a call
+func (b *retryBudget) deposit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = min(b.tokens+b.ratio, b.burst)
+}
+
+// withdraw takes a token for a retry, returning false if none is left
+func (b *retryBudget) withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// newRetryMiddleware retries calls the MCP process failed to answer, e.g.
+// with a broken pipe right after a restart. Calls that never reached the
+// process are always retried; others only for idempotent methods and
+// tools. Timeouts aren't retried.
+func newRetryMiddleware(config map[string]interface{}) (Middleware, error) {
+	cfg := retryConfig{Attempts: 3, Backoff: "100ms", Methods: idempotentMethods}
+	cfg.Budget.Ratio = 0.1
+	cfg.Budget.Burst = 10
+	if err := decodeConfig(config, &cfg); err != nil {
+		return nil, err
+	}
+	if cfg.Attempts < 1 {
+		return nil, fmt.Errorf("attempts must be positive")
+	}
+	backoff, err := time.ParseDuration(cfg.Backoff)
+	if err != nil || backoff < 0 {
+		return nil, fmt.Errorf("invalid backoff '%s'", cfg.Backoff)
+	}
+	if cfg.Budget.Ratio < 0 || cfg.Budget.Burst < 1 {
+		return nil, fmt.Errorf("budget ratio can't be negative and burst must be at least 1")
+	}
+
+	methods := make(map[string]bool)
+	for _, method := range cfg.Methods {
+		methods[method] = true
+	}
+	tools := make(map[string]bool)
+	for _, tool := range cfg.Tools {
+		tools[tool] = true
+	}
+	budget := &retryBudget{tokens: cfg.Budget.Burst, ratio: cfg.Budget.Ratio, burst: cfg.Budget.Burst}
+
+	// retryable returns true if a failed call may run again
+	retryable := func(call *Call, response MCPResponse) bool {
+		switch response.failure {
+		case failureNotSent:
+			return true
+		case failureNoResponse:
+			if call.Request.Method == "tools/call" {
+				return tools[toolName(call.Request)]
+			}
+			return methods[call.Request.Method]
+		default:
+			return false
+		}
+	}
+
+	return MiddlewareFunc(func(next Handler) Handler {
+		return func(call *Call) MCPResponse {
+			budget.deposit()
+
+			ctx := context.Background()
+			if call.HTTP != nil {
+				ctx = call.HTTP.Context()
+			}
+			delay := backoff
+			response := next(call)
+			for attempt := 1; attempt < cfg.Attempts && retryable(call, response); attempt++ {
+				if !budget.withdraw() {
+					log.Printf("Not retrying %s on port %d: retry budget exhausted", call.Request.Method, call.Port)
+					break
+				}
+				log.Printf("Retrying %s on port %d after: %s", call.Request.Method, call.Port, response.Error.Message)
+				if err := sleep(ctx, delay); err != nil {
+					return errorResponse(call, codeCanceled, fmt.Sprintf("call canceled before retrying: %v", err))
+				}
+				delay *= 2
+				response = next(call)
+			}
+			return response
+		}
+	}), nil
+}
+
+// sleep waits for d, or returns the context's error if it is done first
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
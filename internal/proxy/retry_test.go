@@ -0,0 +1,115 @@
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyHandler fails the first failures calls with kind
+func flakyHandler(calls *int, failures int, kind failure) Handler {
+	return func(call *Call) MCPResponse {
+		*calls++
+		if *calls <= failures {
+			return failureResponse(call.Request.ID, kind, "Failed to read response: EOF")
+		}
+		return MCPResponse{JSONRPC: "2.0", ID: call.Request.ID, Result: "ok"}
+	}
+}
+
+func toolCall(name string) *Call {
+	call := newCall("tools/call")
+	call.Request.Params = map[string]interface{}{"name": name}
+	return call
+}
+
+func TestRetryMiddleware(t *testing.T) {
+	_, err := newRetryMiddleware(map[string]interface{}{"attempts": 0})
+	assert.Error(t, err)
+
+	_, err = newRetryMiddleware(map[string]interface{}{"backoff": "soon"})
+	assert.Error(t, err)
+
+	mw, err := newRetryMiddleware(map[string]interface{}{"backoff": "0s", "tools": []string{"search"}})
+	require.NoError(t, err)
+
+	// Idempotent methods are retried
+	calls := 0
+	response := mw.Wrap(flakyHandler(&calls, 2, failureNoResponse))(newCall("tools/list"))
+	assert.Nil(t, response.Error)
+	assert.Equal(t, 3, calls)
+
+	// Attempts are bounded
+	calls = 0
+	response = mw.Wrap(flakyHandler(&calls, 5, failureNoResponse))(newCall("tools/list"))
+	assert.NotNil(t, response.Error)
+	assert.Equal(t, 3, calls)
+
+	// Tool calls the server may have handled are retried for idempotent tools only
+	calls = 0
+	response = mw.Wrap(flakyHandler(&calls, 1, failureNoResponse))(toolCall("create_issue"))
+	assert.NotNil(t, response.Error)
+	assert.Equal(t, 1, calls)
+
+	calls = 0
+	response = mw.Wrap(flakyHandler(&calls, 1, failureNoResponse))(toolCall("search"))
+	assert.Nil(t, response.Error)
+	assert.Equal(t, 2, calls)
+
+	// Calls that never reached the server are always retried
+	calls = 0
+	response = mw.Wrap(flakyHandler(&calls, 1, failureNotSent))(toolCall("create_issue"))
+	assert.Nil(t, response.Error)
+
+	// Timeouts and server errors aren't
+	calls = 0
+	mw.Wrap(flakyHandler(&calls, 1, failureTimeout))(newCall("tools/list"))
+	assert.Equal(t, 1, calls)
+
+	calls = 0
+	mw.Wrap(flakyHandler(&calls, 1, failureNone))(newCall("tools/list"))
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryMiddleware_Budget(t *testing.T) {
+	mw, err := newRetryMiddleware(map[string]interface{}{
+		"backoff": "0s",
+		"budget":  map[string]interface{}{"ratio": 0, "burst": 2},
+	})
+	require.NoError(t, err)
+
+	// Two retries are saved, then failures are returned right away
+	calls := 0
+	handler := mw.Wrap(flakyHandler(&calls, 100, failureNotSent))
+	handler(newCall("tools/list"))
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	handler(newCall("tools/list"))
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryMiddleware_Canceled(t *testing.T) {
+	mw, err := newRetryMiddleware(map[string]interface{}{"backoff": "1h"})
+	require.NoError(t, err)
+
+	// A client that went away isn't kept waiting for the backoff
+	ctx, cancel := context.WithCancel(context.Background())
+	call := newCall("tools/list")
+	call.HTTP = httptest.NewRequest(http.MethodPost, "/", nil).WithContext(ctx)
+	calls := 0
+	handler := mw.Wrap(func(call *Call) MCPResponse {
+		cancel()
+		return flakyHandler(&calls, 1, failureNoResponse)(call)
+	})
+
+	response := handler(call)
+	require.NotNil(t, response.Error)
+	assert.Equal(t, codeCanceled, response.Error.Code)
+	assert.Equal(t, "call canceled before retrying: context canceled", response.Error.Message)
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,218 @@
+package proxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/redact"
+)
+
+const (
+	// shadowQueueSize bounds the mirrored calls waiting for the shadow;
+	// calls are dropped when the shadow falls behind
+	shadowQueueSize = 100
+
+	// maxDivergenceLogLength caps the results included in divergence logs
+	maxDivergenceLogLength = 500
+)
+
+// ShadowStats counts the calls mirrored to the shadow process
+type ShadowStats struct {
+	Mirrored    int  // Calls answered by the shadow
+	Divergences int  // Calls whose result differed from the primary
+	Dropped     int  // Calls dropped because the shadow fell behind
+	Failed      bool // The shadow process failed, so calls are no longer mirrored
+}
+
+// mirroredCall is a tools/call request and the primary's response
+type mirroredCall struct {
+	request  MCPRequest
+	response MCPResponse
+}
+
+// shadow runs a second MCP process that receives a copy of tools/call
+// traffic, to validate upgrades against real workloads
+type shadow struct {
+	port     int
+	process  *mcpProcess
+	redactor *redact.Redactor
+	queue    chan mirroredCall
+	done     chan struct{}
+
+	mu    sync.Mutex
+	stats ShadowStats
+}
+
+// startShadow launches the shadow process and starts mirroring
+func (s *Server) startShadow() {
+	process, err := s.launchMCPProcess(s.opts.ShadowCommand, s.opts.Env, nil)
+	if err != nil {
+		log.Printf("Failed to start shadow MCP process for port %d: %v", s.port, err)
+		return
+	}
+
+	sh := &shadow{
+		port:     s.port,
+		process:  process,
+		redactor: s.opts.Redactor,
+		queue:    make(chan mirroredCall, shadowQueueSize),
+		done:     make(chan struct{}),
+	}
+
+	s.mu.Lock()
+	if s.ctx.Err() != nil {
+		// Stopped while the shadow was starting
+		s.mu.Unlock()
+		process.stop()
+		return
+	}
+	s.shadow = sh
+	s.mu.Unlock()
+
+	log.Printf("Shadow MCP process initialized on port %d", s.port)
+	go sh.run(s.getNextRequestID)
+}
+
+// mirror queues a tools/call for the shadow without blocking
+func (s *Server) mirror(request MCPRequest, response MCPResponse) {
+	s.mu.RLock()
+	sh := s.shadow
+	s.mu.RUnlock()
+
+	if sh == nil {
+		return
+	}
+
+	sh.mu.Lock()
+	failed := sh.stats.Failed
+	sh.mu.Unlock()
+	if failed {
+		return
+	}
+
+	select {
+	case sh.queue <- mirroredCall{request: request, response: response}:
+	default:
+		sh.mu.Lock()
+		sh.stats.Dropped++
+		sh.mu.Unlock()
+	}
+}
+
+// ShadowStats returns the mirroring counters; ok is false when no shadow was started
+func (s *Server) ShadowStats() (stats ShadowStats, ok bool) {
+	s.mu.RLock()
+	sh := s.shadow
+	s.mu.RUnlock()
+
+	if sh == nil {
+		return ShadowStats{}, false
+	}
+
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	return sh.stats, true
+}
+
+// run sends queued calls to the shadow and compares the results
+func (sh *shadow) run(nextID func() int) {
+	for {
+		select {
+		case <-sh.done:
+			return
+		case call := <-sh.queue:
+			request := call.request
+			request.ID = nextID()
+
+			if err := json.NewEncoder(sh.process.stdin).Encode(request); err != nil {
+				sh.fail(err)
+				return
+			}
+
+			response, err := sh.process.read(30 * time.Second)
+			if err != nil {
+				sh.fail(err)
+				return
+			}
+
+			sh.compare(call, response)
+		}
+	}
+}
+
+// fail marks the shadow failed, so calls are no longer queued for it, and
+// kills its process in case it hung; stop still releases it
+func (sh *shadow) fail(err error) {
+	sh.mu.Lock()
+	sh.stats.Failed = true
+	sh.mu.Unlock()
+
+	log.Printf("Shadow on port %d failed, mirroring stopped: %v", sh.port, err)
+	if sh.process.cmd.Process != nil {
+		sh.process.cmd.Process.Kill()
+	}
+}
+
+// compare records the shadow result and logs it if it diverges
+func (sh *shadow) compare(call mirroredCall, shadowResponse MCPResponse) {
+	primary := outcome(call.response)
+	shadowed := outcome(shadowResponse)
+	diverged := !bytes.Equal(primary, shadowed)
+
+	sh.mu.Lock()
+	sh.stats.Mirrored++
+	if diverged {
+		sh.stats.Divergences++
+	}
+	sh.mu.Unlock()
+
+	if diverged {
+		log.Printf("Shadow divergence on port %d for %s: primary=%s shadow=%s",
+			sh.port, toolName(call.request),
+			truncateLog([]byte(sh.redactor.JSON(json.RawMessage(primary)))),
+			truncateLog([]byte(sh.redactor.JSON(json.RawMessage(shadowed)))))
+	}
+}
+
+// stop stops mirroring and the shadow process
+func (sh *shadow) stop() {
+	close(sh.done)
+	sh.process.stop()
+}
+
+// outcome returns the comparable part of a response; encoding/json sorts
+// map keys, so equal results encode identically
+func outcome(response MCPResponse) []byte {
+	// Decode raw results so key order and whitespace don't count as divergence
+	result := response.Result
+	if raw, ok := result.(json.RawMessage); ok {
+		json.Unmarshal(raw, &result)
+	}
+
+	data, _ := json.Marshal(struct {
+		Result interface{} `json:"result,omitempty"`
+		Error  *MCPError   `json:"error,omitempty"`
+	}{result, response.Error})
+	return data
+}
+
+// toolName returns the tool name of a tools/call request
+func toolName(request MCPRequest) string {
+	if params, ok := request.Params.(map[string]interface{}); ok {
+		if name, ok := params["name"].(string); ok {
+			return name
+		}
+	}
+	return "tools/call"
+}
+
+// truncateLog shortens data for logging
+func truncateLog(data []byte) string {
+	if len(data) > maxDivergenceLogLength {
+		return string(data[:maxDivergenceLogLength]) + "..."
+	}
+	return string(data)
+}
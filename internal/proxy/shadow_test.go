@@ -0,0 +1,108 @@
+package proxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func callTool(t *testing.T, port int) {
+	t.Helper()
+
+	body, err := json.Marshal(MCPRequest{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "tools/call",
+		Params:  map[string]interface{}{"name": "test_tool", "arguments": map[string]interface{}{}},
+	})
+	require.NoError(t, err)
+
+	resp, err := http.Post(fmt.Sprintf("http://localhost:%d/", port), "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	resp.Body.Close()
+}
+
+func waitForShadow(t *testing.T, server *Server) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		_, ok := server.ShadowStats()
+		return ok
+	}, 5*time.Second, 20*time.Millisecond)
+}
+
+func TestShadow_MatchingResults(t *testing.T) {
+	server := NewWithOptions(8096, getMockMCPCommand(), Options{ShadowCommand: getMockMCPCommand()})
+	require.NoError(t, server.Start())
+	defer server.Stop()
+	waitForShadow(t, server)
+
+	callTool(t, 8096)
+
+	assert.Eventually(t, func() bool {
+		stats, _ := server.ShadowStats()
+		return stats.Mirrored == 1
+	}, 5*time.Second, 20*time.Millisecond)
+
+	stats, _ := server.ShadowStats()
+	assert.Equal(t, 0, stats.Divergences)
+}
+
+func TestShadow_Divergence(t *testing.T) {
+	// The shadow answers tools/call with a different result
+	shadowCommand := getMockMCPCommand("-result", `{"changed": true}`)
+	server := NewWithOptions(8097, getMockMCPCommand(), Options{ShadowCommand: shadowCommand})
+	require.NoError(t, server.Start())
+	defer server.Stop()
+	waitForShadow(t, server)
+
+	callTool(t, 8097)
+	callTool(t, 8097)
+
+	assert.Eventually(t, func() bool {
+		stats, _ := server.ShadowStats()
+		return stats.Mirrored == 2 && stats.Divergences == 2
+	}, 5*time.Second, 20*time.Millisecond)
+}
+
+func TestShadow_Failed(t *testing.T) {
+	shadowCommand := getMockMCPCommand("-exit-after", "300ms")
+	server := NewWithOptions(8121, getMockMCPCommand(), Options{ShadowCommand: shadowCommand})
+	require.NoError(t, server.Start())
+	defer server.Stop()
+	waitForShadow(t, server)
+
+	// Once the shadow exits, mirroring stops instead of dropping calls
+	time.Sleep(400 * time.Millisecond)
+	callTool(t, 8121)
+	assert.Eventually(t, func() bool {
+		stats, _ := server.ShadowStats()
+		return stats.Failed
+	}, 5*time.Second, 20*time.Millisecond)
+
+	for range shadowQueueSize + 10 {
+		callTool(t, 8121)
+	}
+	stats, ok := server.ShadowStats()
+	assert.True(t, ok)
+	assert.Zero(t, stats.Dropped)
+	assert.Zero(t, stats.Mirrored)
+}
+
+func TestShadow_Disabled(t *testing.T) {
+	server := New(8098, getMockMCPCommand())
+	require.NoError(t, server.Start())
+	defer server.Stop()
+
+	time.Sleep(100 * time.Millisecond)
+
+	callTool(t, 8098)
+
+	_, ok := server.ShadowStats()
+	assert.False(t, ok)
+}
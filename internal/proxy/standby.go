@@ -0,0 +1,141 @@
+package proxy
+
+import (
+	"errors"
+	"log"
+)
+
+// Standby states reported by the health endpoint
+const (
+	standbyReady   = "ready"
+	standbyWarming = "warming"
+	standbyNone    = "none"
+)
+
+// warmStandby starts a standby process in the background, unless standbys
+// are disabled or one is ready or warming already
+func (s *Server) warmStandby() {
+	if !s.opts.Standby {
+		return
+	}
+
+	s.standbyMu.Lock()
+	if s.standby != nil || s.warming || s.ctx.Err() != nil {
+		s.standbyMu.Unlock()
+		return
+	}
+	s.warming = true
+	command, env, generation := s.command, s.opts.Env, s.generation
+	s.standbyMu.Unlock()
+
+	go func() {
+		process, err := s.launchMCPProcess(command, env, s.opts.Stderr)
+		if err == nil {
+			if err = process.ready(s.getNextRequestID()); err != nil {
+				process.stop()
+			}
+		}
+
+		s.standbyMu.Lock()
+		s.warming = false
+		if err != nil {
+			s.standbyMu.Unlock()
+			log.Printf("Failed to warm standby MCP process on port %d: %v", s.port, err)
+			return
+		}
+		if s.generation != generation || s.ctx.Err() != nil {
+			// The command changed or the proxy stopped while warming
+			s.standbyMu.Unlock()
+			process.stop()
+			s.warmStandby()
+			return
+		}
+		s.standby = process
+		s.standbyMu.Unlock()
+		log.Printf("Standby MCP process ready on port %d", s.port)
+	}()
+}
+
+// takeStandby returns the standby process, or nil if none is ready, and
+// warms the next one
+func (s *Server) takeStandby() *mcpProcess {
+	s.standbyMu.Lock()
+	standby := s.standby
+	s.standby = nil
+	s.standbyMu.Unlock()
+
+	s.warmStandby()
+	return standby
+}
+
+// replaceStandby discards the standby after the command changed and warms
+// one running the new command
+func (s *Server) replaceStandby() {
+	s.standbyMu.Lock()
+	s.generation++
+	stale := s.standby
+	s.standby = nil
+	s.standbyMu.Unlock()
+
+	if stale != nil {
+		stale.stop()
+	}
+	s.warmStandby()
+}
+
+// stopStandby stops the standby process, if any. Standbys still warming
+// are stopped once ready, as the proxy's context is done.
+func (s *Server) stopStandby() {
+	s.standbyMu.Lock()
+	standby := s.standby
+	s.standby = nil
+	s.standbyMu.Unlock()
+
+	if standby != nil {
+		standby.stop()
+	}
+}
+
+// standbyState returns whether a standby is ready or warming
+func (s *Server) standbyState() string {
+	s.standbyMu.Lock()
+	defer s.standbyMu.Unlock()
+
+	switch {
+	case s.standby != nil:
+		return standbyReady
+	case s.warming:
+		return standbyWarming
+	default:
+		return standbyNone
+	}
+}
+
+// StandbyReady returns true if a standby process is ready to take over
+func (s *Server) StandbyReady() bool {
+	return s.standbyState() == standbyReady
+}
+
+// Failover switches at once to the standby process, stopping the serving
+// one, and warms the next standby. Requests in flight finish on the
+// serving process first. It fails if no standby is ready.
+func (s *Server) Failover() error {
+	standby := s.takeStandby()
+	if standby == nil {
+		return errors.New("no standby MCP process ready")
+	}
+
+	s.mcpMu.Lock()
+	previous := s.mcp
+	s.mcp = standby
+	s.mcpMu.Unlock()
+
+	if previous != nil {
+		previous.stop()
+	}
+	log.Printf("Failed over to standby MCP process on port %d", s.port)
+
+	go s.refreshToolCount()
+
+	return nil
+}
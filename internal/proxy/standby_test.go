@@ -0,0 +1,49 @@
+package proxy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+func TestServer_Standby(t *testing.T) {
+	s := NewWithOptions(8111, getMockMCPCommand(), Options{Standby: true})
+	require.NoError(t, s.Start())
+	defer s.Stop()
+
+	require.Eventually(t, s.StandbyReady, 5*time.Second, 10*time.Millisecond)
+	standbyPID := s.standby.cmd.Process.Pid
+
+	// The standby takes over a crashed process on the same call
+	s.crashMCPProcess()
+	response := s.handler(s.newCall(MCPRequest{JSONRPC: "2.0", ID: 1, Method: "tools/list"}, nil))
+	assert.Nil(t, response.Error)
+	assert.Equal(t, standbyPID, s.mcp.cmd.Process.Pid)
+
+	// A new standby is warmed, and failing over by hand switches to it
+	require.Eventually(t, s.StandbyReady, 5*time.Second, 10*time.Millisecond)
+	standbyPID = s.standby.cmd.Process.Pid
+	require.NoError(t, s.Failover())
+	assert.Equal(t, standbyPID, s.mcp.cmd.Process.Pid)
+
+	// Swapping the command replaces the standby with one running it
+	require.Eventually(t, s.StandbyReady, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, s.Swap(getMockMCPCommand("-tools", "new_tool"), nil))
+	require.Eventually(t, s.StandbyReady, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, s.Failover())
+	tools, err := s.getToolsFromMCP()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"new_tool"}, []string{tools[0].Name})
+}
+
+func TestServer_FailoverWithoutStandby(t *testing.T) {
+	s := NewWithOptions(8112, getMockMCPCommand(), Options{Chaos: &server.Chaos{}})
+	require.NoError(t, s.Start())
+	defer s.Stop()
+
+	assert.False(t, s.StandbyReady())
+	assert.Error(t, s.Failover())
+}
@@ -0,0 +1,150 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// transformTimeout bounds each run of a transformation
+const transformTimeout = 5 * time.Second
+
+// jqCompileError is the exit code of jq for invalid expressions
+const jqCompileError = 3
+
+// toolTransform holds the jq expressions applied to a tool's calls
+type toolTransform struct {
+	Request  string `json:"request"`  // Rewrites the arguments of tools/call
+	Response string `json:"response"` // Rewrites the result of tools/call
+}
+
+// transformConfig configures the transform middleware
+type transformConfig struct {
+	JQ    string                   `json:"jq"`    // jq executable (default: jq on PATH)
+	Tools map[string]toolTransform `json:"tools"` // Tool name, or * for all tools, to its transformations
+}
+
+// newTransformMiddleware rewrites the arguments and results of tools/call
+// with jq expressions, e.g. to add default arguments or strip huge fields
+func newTransformMiddleware(config map[string]interface{}) (Middleware, error) {
+	cfg := transformConfig{JQ: "jq"}
+	if err := decodeConfig(config, &cfg); err != nil {
+		return nil, err
+	}
+	if len(cfg.Tools) == 0 {
+		return nil, fmt.Errorf("tools is required")
+	}
+	jq, err := exec.LookPath(cfg.JQ)
+	if err != nil {
+		return nil, fmt.Errorf("jq not found: %w", err)
+	}
+	for tool, transform := range cfg.Tools {
+		for _, expr := range []string{transform.Request, transform.Response} {
+			if err := checkJQ(jq, expr); err != nil {
+				return nil, fmt.Errorf("invalid expression for tool '%s': %w", tool, err)
+			}
+		}
+	}
+
+	return MiddlewareFunc(func(next Handler) Handler {
+		return func(call *Call) MCPResponse {
+			if call.Request.Method != "tools/call" {
+				return next(call)
+			}
+			transform, exists := cfg.Tools[toolName(call.Request)]
+			if !exists {
+				transform, exists = cfg.Tools["*"]
+			}
+			if !exists {
+				return next(call)
+			}
+
+			if transform.Request != "" {
+				params, ok := call.Request.Params.(map[string]interface{})
+				if !ok {
+					return errorResponse(call, -32602, "invalid params")
+				}
+				arguments, err := runJQ(jq, transform.Request, params["arguments"])
+				if err != nil {
+					return errorResponse(call, -32603, fmt.Sprintf("request transform failed: %v", err))
+				}
+
+				// Copy the params, the client's request may be retried
+				rewritten := make(map[string]interface{}, len(params))
+				for key, value := range params {
+					rewritten[key] = value
+				}
+				rewritten["arguments"] = arguments
+				call.Request.Params = rewritten
+			}
+
+			response := next(call)
+			if transform.Response == "" || response.Error != nil {
+				return response
+			}
+			result, err := runJQ(jq, transform.Response, response.Result)
+			if err != nil {
+				return errorResponse(call, -32603, fmt.Sprintf("response transform failed: %v", err))
+			}
+			response.Result = result
+			return response
+		}
+	}), nil
+}
+
+// checkJQ returns an error if expr doesn't compile
+func checkJQ(jq, expr string) error {
+	if expr == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), transformTimeout)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, jq, "-n", expr)
+	cmd.Stderr = &stderr
+	var exitErr *exec.ExitError
+	if err := cmd.Run(); errors.As(err, &exitErr) && exitErr.ExitCode() == jqCompileError {
+		return errors.New(strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// runJQ applies expr to value, which must produce a single output
+func runJQ(jq, expr string, value interface{}) (interface{}, error) {
+	input, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), transformTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, jq, "-c", expr)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, errors.New(message)
+		}
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(&stdout)
+	var output interface{}
+	if err := decoder.Decode(&output); err != nil {
+		return nil, fmt.Errorf("no output: %w", err)
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("expression produced several outputs")
+	}
+	return output, nil
+}
@@ -0,0 +1,52 @@
+package proxy
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransformMiddleware(t *testing.T) {
+	if _, err := exec.LookPath("jq"); err != nil {
+		t.Skip("jq not installed")
+	}
+
+	_, err := newTransformMiddleware(map[string]interface{}{})
+	assert.Error(t, err)
+	_, err = newTransformMiddleware(map[string]interface{}{"tools": map[string]interface{}{"search": map[string]interface{}{"request": ".limit //="}}})
+	assert.Error(t, err)
+
+	mw, err := newTransformMiddleware(map[string]interface{}{"tools": map[string]interface{}{
+		"search": map[string]interface{}{"request": ".limit //= 10", "response": "del(.raw)"},
+		"*":      map[string]interface{}{"response": "error(\"no\")"},
+	}})
+	require.NoError(t, err)
+
+	var arguments interface{}
+	handler := mw.Wrap(func(call *Call) MCPResponse {
+		if params, ok := call.Request.Params.(map[string]interface{}); ok {
+			arguments = params["arguments"]
+		}
+		return MCPResponse{JSONRPC: "2.0", ID: call.Request.ID, Result: map[string]interface{}{"raw": "...", "text": "ok"}}
+	})
+
+	// Arguments get defaults and results are trimmed
+	call := newCall("tools/call")
+	call.Request.Params = map[string]interface{}{"name": "search", "arguments": map[string]interface{}{"q": "x"}}
+	response := handler(call)
+	assert.Equal(t, map[string]interface{}{"q": "x", "limit": float64(10)}, arguments)
+	assert.Equal(t, map[string]interface{}{"text": "ok"}, response.Result)
+
+	// Failed transforms answer with an error
+	call = newCall("tools/call")
+	call.Request.Params = map[string]interface{}{"name": "other"}
+	response = handler(call)
+	require.NotNil(t, response.Error)
+	assert.Equal(t, -32603, response.Error.Code)
+
+	// Other methods pass through
+	response = handler(newCall("tools/list"))
+	assert.Nil(t, response.Error)
+}
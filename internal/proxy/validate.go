@@ -0,0 +1,261 @@
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// FieldError is a tools/call argument that doesn't match the tool's schema
+type FieldError struct {
+	Field   string `json:"field"` // Path of the argument, e.g. filters.labels[0]
+	Message string `json:"message"`
+}
+
+// validateConfig configures the validate middleware
+type validateConfig struct {
+	Tools []string `json:"tools"` // Tools to validate; empty validates all tools
+}
+
+// newValidateMiddleware checks tools/call arguments against the input schema
+// of the tool before forwarding, answering invalid calls with an invalid
+// params error listing the fields at fault. Schemas are learned from
+// tools/list responses, listing the tools itself before the first call.
+func newValidateMiddleware(config map[string]interface{}) (Middleware, error) {
+	var cfg validateConfig
+	if err := decodeConfig(config, &cfg); err != nil {
+		return nil, err
+	}
+	only := make(map[string]bool, len(cfg.Tools))
+	for _, tool := range cfg.Tools {
+		only[tool] = true
+	}
+
+	catalog := &toolCatalog{}
+
+	return MiddlewareFunc(func(next Handler) Handler {
+		return func(call *Call) MCPResponse {
+			if call.Request.Method == "tools/list" {
+				return catalog.observe(call, next)
+			}
+			if call.Request.Method != "tools/call" {
+				return next(call)
+			}
+
+			tool := toolName(call.Request)
+			if len(only) > 0 && !only[tool] {
+				return next(call)
+			}
+
+			info, known := catalog.lookup(call, next, tool)
+			if !known || info.InputSchema == nil {
+				// Unknown tools are left to the server to reject
+				return next(call)
+			}
+
+			var arguments interface{} = map[string]interface{}{}
+			if params, ok := call.Request.Params.(map[string]interface{}); ok && params["arguments"] != nil {
+				arguments = params["arguments"]
+			}
+			errs := validateSchema(info.InputSchema, arguments, "")
+			if len(errs) == 0 {
+				return next(call)
+			}
+
+			details := make([]string, len(errs))
+			for i, e := range errs {
+				details[i] = e.Field + ": " + e.Message
+			}
+			response := errorResponse(call, -32602, fmt.Sprintf("invalid arguments for tool '%s': %s", tool, strings.Join(details, "; ")))
+			response.Error.Data = map[string]interface{}{"tool": tool, "errors": errs}
+			return response
+		}
+	}), nil
+}
+
+// validateSchema checks value against a JSON schema, supporting the
+// keywords tool schemas commonly use. Unsupported keywords are ignored.
+func validateSchema(schema, value interface{}, path string) []FieldError {
+	s, ok := schema.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	field := path
+	if field == "" {
+		field = "arguments"
+	}
+	fail := func(format string, args ...interface{}) []FieldError {
+		return []FieldError{{Field: field, Message: fmt.Sprintf(format, args...)}}
+	}
+
+	if types := schemaTypes(s["type"]); len(types) > 0 {
+		matched := false
+		for _, t := range types {
+			if hasType(value, t) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fail("must be %s, got %s", strings.Join(types, " or "), typeOf(value))
+		}
+	}
+
+	if enum, ok := s["enum"].([]interface{}); ok {
+		found := false
+		for _, allowed := range enum {
+			if sameJSONValue(allowed, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			data, _ := json.Marshal(enum)
+			return fail("must be one of %s", data)
+		}
+	}
+
+	var errs []FieldError
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if required, ok := s["required"].([]interface{}); ok {
+			for _, name := range required {
+				if key, ok := name.(string); ok {
+					if _, exists := v[key]; !exists {
+						errs = append(errs, FieldError{Field: join(path, key), Message: "is required"})
+					}
+				}
+			}
+		}
+		properties, _ := s["properties"].(map[string]interface{})
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if property, exists := properties[key]; exists {
+				errs = append(errs, validateSchema(property, v[key], join(path, key))...)
+				continue
+			}
+			switch additional := s["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					errs = append(errs, FieldError{Field: join(path, key), Message: "is not allowed"})
+				}
+			case map[string]interface{}:
+				errs = append(errs, validateSchema(additional, v[key], join(path, key))...)
+			}
+		}
+	case []interface{}:
+		if min, ok := number(s["minItems"]); ok && float64(len(v)) < min {
+			errs = append(errs, FieldError{Field: field, Message: fmt.Sprintf("must have at least %g items", min)})
+		}
+		if max, ok := number(s["maxItems"]); ok && float64(len(v)) > max {
+			errs = append(errs, FieldError{Field: field, Message: fmt.Sprintf("must have at most %g items", max)})
+		}
+		for i, item := range v {
+			errs = append(errs, validateSchema(s["items"], item, fmt.Sprintf("%s[%d]", field, i))...)
+		}
+	case string:
+		length := float64(len([]rune(v)))
+		if min, ok := number(s["minLength"]); ok && length < min {
+			errs = append(errs, FieldError{Field: field, Message: fmt.Sprintf("must be at least %g characters", min)})
+		}
+		if max, ok := number(s["maxLength"]); ok && length > max {
+			errs = append(errs, FieldError{Field: field, Message: fmt.Sprintf("must be at most %g characters", max)})
+		}
+		if pattern, ok := s["pattern"].(string); ok {
+			if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(v) {
+				errs = append(errs, FieldError{Field: field, Message: fmt.Sprintf("must match %s", pattern)})
+			}
+		}
+	case float64:
+		if min, ok := number(s["minimum"]); ok && v < min {
+			errs = append(errs, FieldError{Field: field, Message: fmt.Sprintf("must be at least %g", min)})
+		}
+		if max, ok := number(s["maximum"]); ok && v > max {
+			errs = append(errs, FieldError{Field: field, Message: fmt.Sprintf("must be at most %g", max)})
+		}
+		if min, ok := number(s["exclusiveMinimum"]); ok && v <= min {
+			errs = append(errs, FieldError{Field: field, Message: fmt.Sprintf("must be greater than %g", min)})
+		}
+		if max, ok := number(s["exclusiveMaximum"]); ok && v >= max {
+			errs = append(errs, FieldError{Field: field, Message: fmt.Sprintf("must be less than %g", max)})
+		}
+	}
+	return errs
+}
+
+// schemaTypes returns the types a schema allows
+func schemaTypes(t interface{}) []string {
+	switch t := t.(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		types := make([]string, 0, len(t))
+		for _, name := range t {
+			if s, ok := name.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+// hasType returns true if value is of the JSON schema type t
+func hasType(value interface{}, t string) bool {
+	switch t {
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	}
+	return typeOf(value) == t
+}
+
+// typeOf returns the JSON schema type of a decoded JSON value
+func typeOf(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// number returns a schema keyword's numeric value
+func number(value interface{}) (float64, bool) {
+	n, ok := value.(float64)
+	return n, ok
+}
+
+// sameJSONValue returns true if two decoded JSON values are equal
+func sameJSONValue(a, b interface{}) bool {
+	da, errA := json.Marshal(a)
+	db, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(da) == string(db)
+}
+
+// join appends a property to a field path
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
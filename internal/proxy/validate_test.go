@@ -0,0 +1,78 @@
+package proxy
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// searchSchema is the input schema of the test tool
+const searchSchema = `{
+	"type": "object",
+	"required": ["q"],
+	"additionalProperties": false,
+	"properties": {
+		"q": {"type": "string", "minLength": 1},
+		"limit": {"type": "integer", "minimum": 1},
+		"sort": {"enum": ["stars", "updated"]},
+		"labels": {"type": "array", "items": {"type": "string"}}
+	}
+}`
+
+func TestValidateSchema(t *testing.T) {
+	var schema, arguments interface{}
+	require.NoError(t, json.Unmarshal([]byte(searchSchema), &schema))
+
+	require.NoError(t, json.Unmarshal([]byte(`{"q": "x", "limit": 5, "sort": "stars", "labels": ["bug"]}`), &arguments))
+	assert.Empty(t, validateSchema(schema, arguments, ""))
+
+	require.NoError(t, json.Unmarshal([]byte(`{"limit": 1.5, "sort": "best", "labels": ["bug", 3], "page": 2}`), &arguments))
+	assert.Equal(t, []FieldError{
+		{Field: "q", Message: "is required"},
+		{Field: "labels[1]", Message: "must be string, got number"},
+		{Field: "limit", Message: "must be integer, got number"},
+		{Field: "page", Message: "is not allowed"},
+		{Field: "sort", Message: `must be one of ["stars","updated"]`},
+	}, validateSchema(schema, arguments, ""))
+
+	assert.Equal(t, []FieldError{{Field: "arguments", Message: "must be object, got array"}},
+		validateSchema(schema, []interface{}{}, ""))
+}
+
+func TestValidateMiddleware(t *testing.T) {
+	mw, err := newValidateMiddleware(nil)
+	require.NoError(t, err)
+
+	var methods []string
+	handler := mw.Wrap(func(call *Call) MCPResponse {
+		methods = append(methods, call.Request.Method)
+		if call.Request.Method == "tools/list" {
+			return MCPResponse{JSONRPC: "2.0", ID: call.Request.ID, Result: json.RawMessage(`{"tools": [{"name": "search", "inputSchema": ` + searchSchema + `}]}`)}
+		}
+		return MCPResponse{JSONRPC: "2.0", ID: call.Request.ID, Result: "ok"}
+	})
+
+	// Schemas are listed before the first call, and valid calls forwarded
+	call := newCall("tools/call")
+	call.Request.Params = map[string]interface{}{"name": "search", "arguments": map[string]interface{}{"q": "x"}}
+	assert.Nil(t, handler(call).Error)
+	assert.Equal(t, []string{"tools/list", "tools/call"}, methods)
+
+	// Invalid calls never reach the server
+	call = newCall("tools/call")
+	call.Request.Params = map[string]interface{}{"name": "search", "arguments": map[string]interface{}{"limit": float64(0)}}
+	response := handler(call)
+	require.NotNil(t, response.Error)
+	assert.Equal(t, -32602, response.Error.Code)
+	assert.Equal(t, "invalid arguments for tool 'search': q: is required; limit: must be at least 1", response.Error.Message)
+	assert.Len(t, response.Error.Data.(map[string]interface{})["errors"], 2)
+	assert.Len(t, methods, 2)
+
+	// Unknown tools are left to the server
+	call = newCall("tools/call")
+	call.Request.Params = map[string]interface{}{"name": "other"}
+	assert.Nil(t, handler(call).Error)
+	assert.Len(t, methods, 3)
+}
@@ -0,0 +1,225 @@
+package redact
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Mask replaces redacted values
+const Mask = "[REDACTED]"
+
+// defaultKeys matches field names whose values are always redacted
+const defaultKeys = `(?i)(token|secret|password|passwd|api[_-]?key|authorization|credential)`
+
+// Rule selects values to redact. Exactly one field should be set.
+type Rule struct {
+	// Path is a JSONPath into the payload, e.g. $.params.arguments.password.
+	// Supports .name, ..name (any depth), .* and [n] / [*].
+	Path string `json:"path,omitempty"`
+
+	// Key is a regex matched against field names at any depth
+	Key string `json:"key,omitempty"`
+
+	// Value is a regex matched against string values; matches are masked
+	Value string `json:"value,omitempty"`
+}
+
+// Redactor masks secrets in payloads before they are logged or recorded
+type Redactor struct {
+	paths  [][]segment
+	keys   []*regexp.Regexp
+	values []*regexp.Regexp
+}
+
+// New creates a redactor from rules, in addition to the default key rule
+func New(rules []Rule) (*Redactor, error) {
+	r := &Redactor{keys: []*regexp.Regexp{regexp.MustCompile(defaultKeys)}}
+
+	for _, rule := range rules {
+		switch {
+		case rule.Path != "":
+			segments, err := parsePath(rule.Path)
+			if err != nil {
+				return nil, err
+			}
+			r.paths = append(r.paths, segments)
+		case rule.Key != "":
+			re, err := regexp.Compile(rule.Key)
+			if err != nil {
+				return nil, fmt.Errorf("invalid redaction key pattern '%s': %w", rule.Key, err)
+			}
+			r.keys = append(r.keys, re)
+		case rule.Value != "":
+			re, err := regexp.Compile(rule.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid redaction value pattern '%s': %w", rule.Value, err)
+			}
+			r.values = append(r.values, re)
+		default:
+			return nil, fmt.Errorf("redaction rule requires path, key or value")
+		}
+	}
+
+	return r, nil
+}
+
+// Default returns a redactor with only the default rules
+func Default() *Redactor {
+	r, _ := New(nil)
+	return r
+}
+
+// Apply returns a redacted copy of v as generic JSON values. The original
+// is not modified. A nil redactor applies the default rules.
+func (r *Redactor) Apply(v interface{}) interface{} {
+	if r == nil {
+		r = Default()
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return Mask
+	}
+	var node interface{}
+	if err := json.Unmarshal(data, &node); err != nil {
+		return Mask
+	}
+
+	for _, segments := range r.paths {
+		node = redactPath(node, segments)
+	}
+	return r.redactTree(node)
+}
+
+// JSON returns the redacted JSON encoding of v
+func (r *Redactor) JSON(v interface{}) string {
+	data, err := json.Marshal(r.Apply(v))
+	if err != nil {
+		return Mask
+	}
+	return string(data)
+}
+
+// redactTree applies the key and value rules at every depth
+func (r *Redactor) redactTree(node interface{}) interface{} {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		for key, value := range n {
+			if r.matchesKey(key) {
+				n[key] = Mask
+				continue
+			}
+			n[key] = r.redactTree(value)
+		}
+		return n
+	case []interface{}:
+		for i, value := range n {
+			n[i] = r.redactTree(value)
+		}
+		return n
+	case string:
+		for _, re := range r.values {
+			n = re.ReplaceAllString(n, Mask)
+		}
+		return n
+	default:
+		return n
+	}
+}
+
+// SecretKey returns true if the values of fields or variables named key
+// are secrets, as a key rule matches it. A nil redactor applies the
+// default rules.
+func (r *Redactor) SecretKey(key string) bool {
+	if r == nil {
+		r = Default()
+	}
+	return r.matchesKey(key)
+}
+
+// matchesKey returns true if a field name matches a key rule
+func (r *Redactor) matchesKey(key string) bool {
+	for _, re := range r.keys {
+		if re.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// segment is a step of a parsed JSONPath
+type segment struct {
+	name      string // Field name, or "*" for any field
+	index     int    // Array index, or -1 for any element
+	isIndex   bool
+	recursive bool // Matches at any depth (..)
+}
+
+var pathToken = regexp.MustCompile(`^(\.\.|\.)([^.\[\]]+)|^\[(\d+|\*)\]`)
+
+// parsePath parses the supported JSONPath subset
+func parsePath(path string) ([]segment, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("invalid redaction path '%s': must start with $", path)
+	}
+
+	var segments []segment
+	rest := path[1:]
+	for rest != "" {
+		match := pathToken.FindStringSubmatch(rest)
+		if match == nil {
+			return nil, fmt.Errorf("invalid redaction path '%s' at '%s'", path, rest)
+		}
+		rest = rest[len(match[0]):]
+
+		if match[3] != "" {
+			seg := segment{isIndex: true, index: -1}
+			if match[3] != "*" {
+				seg.index, _ = strconv.Atoi(match[3])
+			}
+			segments = append(segments, seg)
+			continue
+		}
+		segments = append(segments, segment{name: match[2], recursive: match[1] == ".."})
+	}
+
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("invalid redaction path '%s': selects the whole payload", path)
+	}
+	return segments, nil
+}
+
+// redactPath masks the values selected by segments
+func redactPath(node interface{}, segments []segment) interface{} {
+	if len(segments) == 0 {
+		return Mask
+	}
+	seg := segments[0]
+
+	switch n := node.(type) {
+	case map[string]interface{}:
+		for key, value := range n {
+			if !seg.isIndex && (seg.name == "*" || seg.name == key) {
+				value = redactPath(value, segments[1:])
+			}
+			if seg.recursive {
+				value = redactPath(value, segments)
+			}
+			n[key] = value
+		}
+	case []interface{}:
+		for i, value := range n {
+			if seg.isIndex && (seg.index == -1 || seg.index == i) {
+				value = redactPath(value, segments[1:])
+			}
+			if seg.recursive {
+				value = redactPath(value, segments)
+			}
+			n[i] = value
+		}
+	}
+	return node
+}
@@ -0,0 +1,95 @@
+package redact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApply_DefaultKeys(t *testing.T) {
+	payload := map[string]interface{}{
+		"params": map[string]interface{}{
+			"name": "query",
+			"arguments": map[string]interface{}{
+				"sql":      "SELECT 1",
+				"apiKey":   "sk-123",
+				"password": "hunter2",
+			},
+		},
+	}
+
+	redacted := Default().JSON(payload)
+	assert.JSONEq(t, `{"params": {"name": "query", "arguments": {"sql": "SELECT 1", "apiKey": "[REDACTED]", "password": "[REDACTED]"}}}`, redacted)
+
+	// The original is not modified
+	assert.Equal(t, "hunter2", payload["params"].(map[string]interface{})["arguments"].(map[string]interface{})["password"])
+}
+
+func TestApply_Paths(t *testing.T) {
+	r, err := New([]Rule{
+		{Path: "$.params.arguments.connection"},
+		{Path: "$..dsn"},
+		{Path: "$.items[*].value"},
+		{Path: "$.rows[1]"},
+	})
+	require.NoError(t, err)
+
+	payload := map[string]interface{}{
+		"params": map[string]interface{}{
+			"arguments": map[string]interface{}{"connection": "postgres://u:p@h/db", "other": "kept"},
+		},
+		"nested": map[string]interface{}{"deep": map[string]interface{}{"dsn": "secret"}},
+		"items":  []interface{}{map[string]interface{}{"value": 1}, map[string]interface{}{"value": 2}},
+		"rows":   []interface{}{"a", "b", "c"},
+	}
+
+	assert.JSONEq(t, `{
+		"params": {"arguments": {"connection": "[REDACTED]", "other": "kept"}},
+		"nested": {"deep": {"dsn": "[REDACTED]"}},
+		"items": [{"value": "[REDACTED]"}, {"value": "[REDACTED]"}],
+		"rows": ["a", "[REDACTED]", "c"]
+	}`, r.JSON(payload))
+}
+
+func TestApply_KeyAndValuePatterns(t *testing.T) {
+	r, err := New([]Rule{
+		{Key: "^ssn$"},
+		{Value: `ghp_[A-Za-z0-9]+`},
+	})
+	require.NoError(t, err)
+
+	payload := map[string]interface{}{
+		"ssn":   "123-45-6789",
+		"notes": []interface{}{"token ghp_abc123 leaked"},
+	}
+
+	assert.JSONEq(t, `{"ssn": "[REDACTED]", "notes": ["token [REDACTED] leaked"]}`, r.JSON(payload))
+}
+
+func TestSecretKey(t *testing.T) {
+	r, err := New([]Rule{{Key: "^SSN$"}})
+	require.NoError(t, err)
+
+	assert.True(t, r.SecretKey("GITHUB_TOKEN"))
+	assert.True(t, r.SecretKey("OPENAI_API_KEY"))
+	assert.True(t, r.SecretKey("SSN"))
+	assert.False(t, r.SecretKey("LOG_LEVEL"))
+
+	var defaults *Redactor
+	assert.False(t, defaults.SecretKey("SSN"))
+}
+
+func TestNew_InvalidRules(t *testing.T) {
+	for _, rule := range []Rule{
+		{},
+		{Path: "params.password"},
+		{Path: "$"},
+		{Path: "$.a[x]"},
+		{Key: "("},
+		{Value: "["},
+	} {
+		_, err := New([]Rule{rule})
+		assert.Error(t, err, "%+v", rule)
+	}
+}
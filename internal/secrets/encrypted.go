@@ -0,0 +1,125 @@
+package secrets
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// BackendEncrypted reads secrets from a SOPS- or age-encrypted file
+const BackendEncrypted = "encrypted file"
+
+// encryptedStore reads secrets from a file encrypted with SOPS or age,
+// which is edited with those tools
+type encryptedStore struct {
+	path string
+
+	mu       sync.Mutex
+	values   map[string]string // Decrypted secrets, kept until the file changes
+	modified time.Time
+	size     int64
+}
+
+// newEncryptedStore creates a store reading the file at path
+func newEncryptedStore(path string) *encryptedStore {
+	return &encryptedStore{path: path}
+}
+
+// Backend returns BackendEncrypted
+func (s *encryptedStore) Backend() string {
+	return BackendEncrypted
+}
+
+// Get returns a secret
+func (s *encryptedStore) Get(name string) (string, error) {
+	values, err := s.load()
+	if err != nil {
+		return "", err
+	}
+	value, exists := values[name]
+	if !exists {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+// Set fails, as the file is edited with SOPS or age
+func (s *encryptedStore) Set(name, value string) error {
+	return s.readOnly()
+}
+
+// Delete fails, as the file is edited with SOPS or age
+func (s *encryptedStore) Delete(name string) error {
+	return s.readOnly()
+}
+
+// List returns the names of the secrets
+func (s *encryptedStore) List() ([]string, error) {
+	values, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// readOnly returns the error of changes to the file
+func (s *encryptedStore) readOnly() error {
+	return fmt.Errorf("secrets are read from %s, edit it with sops or age", s.path)
+}
+
+// load returns the secrets, decrypting the file again if it changed
+func (s *encryptedStore) load() (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secrets file: %w", err)
+	}
+	if s.values != nil && info.ModTime().Equal(s.modified) && info.Size() == s.size {
+		return s.values, nil
+	}
+
+	plaintext, _, err := config.DecryptFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(plaintext, &doc); err != nil {
+		return nil, fmt.Errorf("invalid secrets file %s: %w", s.path, err)
+	}
+
+	values := make(map[string]string)
+	flatten(values, "", doc)
+	delete(values, "sops") // Metadata left by some sops versions
+	s.values, s.modified, s.size = values, info.ModTime(), info.Size()
+	return values, nil
+}
+
+// flatten adds the values of a document to values, naming nested ones
+// after their path, e.g. github/GITHUB_TOKEN
+func flatten(values map[string]string, prefix string, doc map[string]interface{}) {
+	for key, value := range doc {
+		name := prefix + key
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if name == "sops" {
+				continue
+			}
+			flatten(values, name+"/", v)
+		case nil:
+		default:
+			values[name] = fmt.Sprint(v)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package secrets
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptedStore(t *testing.T) {
+	// Plaintext files are read as is, which exercises everything but the
+	// decryption tools
+	path := filepath.Join(t.TempDir(), "secrets.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("github:\n  GITHUB_TOKEN: ghp_abc\nDATABASE_URL: postgres://db\nPORT: 5432\n"), 0600))
+
+	store, err := Open(Options{Backend: BackendKeychain, Encrypted: path})
+	require.NoError(t, err)
+	assert.Equal(t, BackendEncrypted, store.Backend())
+
+	value, err := store.Get("github/GITHUB_TOKEN")
+	require.NoError(t, err)
+	assert.Equal(t, "ghp_abc", value)
+	value, err = store.Get("PORT")
+	require.NoError(t, err)
+	assert.Equal(t, "5432", value)
+	_, err = store.Get("github")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	names, err := store.List()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DATABASE_URL", "PORT", "github/GITHUB_TOKEN"}, names)
+
+	assert.Error(t, store.Set("A", "1"))
+	assert.Error(t, store.Delete("PORT"))
+
+	// Edits are picked up
+	require.NoError(t, os.WriteFile(path, []byte(`{"PORT": "6543"}`), 0600))
+	later := time.Now().Add(time.Second)
+	require.NoError(t, os.Chtimes(path, later, later))
+	value, err = store.Get("PORT")
+	require.NoError(t, err)
+	assert.Equal(t, "6543", value)
+	_, err = store.Get("DATABASE_URL")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
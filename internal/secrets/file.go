@@ -0,0 +1,250 @@
+package secrets
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+const (
+	// fileVersion is the format of the encrypted file
+	fileVersion = 1
+
+	// defaultIterations is the PBKDF2 cost of new files
+	defaultIterations = 600000
+)
+
+// encryptedFile is the on-disk format of the file store: the secrets as
+// JSON, sealed with AES-256-GCM under a key derived from the passphrase
+type encryptedFile struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"` // pbkdf2-sha256
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Data       []byte `json:"data"`
+}
+
+// fileStore keeps secrets in a file encrypted with a passphrase
+type fileStore struct {
+	path       string
+	passphrase func(create bool) (string, error)
+	iterations int // PBKDF2 cost of new files
+
+	mu   sync.Mutex
+	key  []byte // Key derived for salt and cost, so the passphrase is asked once
+	salt []byte
+	cost int
+}
+
+// newFileStore creates a store for the file of opts
+func newFileStore(opts Options) *fileStore {
+	passphrase := opts.Passphrase
+	if passphrase == nil {
+		passphrase = func(bool) (string, error) { return envPassphrase() }
+	}
+	return &fileStore{path: opts.File, passphrase: passphrase, iterations: defaultIterations}
+}
+
+// Backend returns BackendFile
+func (s *fileStore) Backend() string {
+	return BackendFile
+}
+
+// Get returns a secret
+func (s *fileStore) Get(name string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values, err := s.load()
+	if err != nil {
+		return "", err
+	}
+	value, exists := values[name]
+	if !exists {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+// Set stores a secret, creating the file if needed
+func (s *fileStore) Set(name, value string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values, err := s.load()
+	if err != nil {
+		return err
+	}
+	values[name] = value
+	return s.save(values)
+}
+
+// Delete removes a secret
+func (s *fileStore) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, exists := values[name]; !exists {
+		return ErrNotFound
+	}
+	delete(values, name)
+	return s.save(values)
+}
+
+// List returns the names of the secrets
+func (s *fileStore) List() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// load decrypts the secrets, returning none if the file doesn't exist
+func (s *fileStore) load() (map[string]string, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return make(map[string]string), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secrets file: %w", err)
+	}
+
+	var file encryptedFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid secrets file %s: %w", s.path, err)
+	}
+	if file.Version != fileVersion || file.KDF != "pbkdf2-sha256" || file.Iterations < 1 {
+		return nil, fmt.Errorf("unsupported secrets file %s", s.path)
+	}
+
+	gcm, err := s.cipher(file.Salt, file.Iterations, false)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, file.Nonce, file.Data, nil)
+	if err != nil {
+		// Don't keep a key that doesn't open the file
+		s.key, s.salt, s.cost = nil, nil, 0
+		return nil, fmt.Errorf("wrong passphrase or corrupted secrets file")
+	}
+
+	values := make(map[string]string)
+	if err := json.Unmarshal(plaintext, &values); err != nil {
+		return nil, fmt.Errorf("invalid secrets file %s: %w", s.path, err)
+	}
+	return values, nil
+}
+
+// save encrypts the secrets, replacing the file atomically. A new file
+// gets a new salt, asking for the passphrase to protect it with.
+func (s *fileStore) save(values map[string]string) error {
+	file := encryptedFile{Version: fileVersion, KDF: "pbkdf2-sha256", Iterations: s.cost, Salt: s.salt}
+	create := s.key == nil
+	if create {
+		file.Iterations = s.iterations
+		file.Salt = make([]byte, 16)
+		if _, err := rand.Read(file.Salt); err != nil {
+			return err
+		}
+	}
+
+	gcm, err := s.cipher(file.Salt, file.Iterations, create)
+	if err != nil {
+		return err
+	}
+	plaintext, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	file.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(file.Nonce); err != nil {
+		return err
+	}
+	file.Data = gcm.Seal(nil, file.Nonce, plaintext, nil)
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.path, data)
+}
+
+// cipher returns the AES-GCM cipher of the key derived for salt, asking
+// for the passphrase unless the key is cached
+func (s *fileStore) cipher(salt []byte, iterations int, create bool) (cipher.AEAD, error) {
+	if s.key == nil || !bytes.Equal(s.salt, salt) || s.cost != iterations {
+		passphrase, err := s.passphrase(create)
+		if err != nil {
+			return nil, err
+		}
+		if passphrase == "" {
+			return nil, fmt.Errorf("empty passphrase")
+		}
+		key, err := pbkdf2.Key(sha256.New, passphrase, salt, iterations, 32)
+		if err != nil {
+			return nil, err
+		}
+		s.key, s.salt, s.cost = key, salt, iterations
+	}
+
+	block, err := aes.NewCipher(s.key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// writeFileAtomic replaces a file readable only by its owner
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create secrets directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".secrets-*")
+	if err != nil {
+		return fmt.Errorf("failed to write secrets file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write secrets file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write secrets file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0600); err != nil {
+		return fmt.Errorf("failed to write secrets file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write secrets file: %w", err)
+	}
+	return nil
+}
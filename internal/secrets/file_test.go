@@ -0,0 +1,96 @@
+package secrets
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore(t *testing.T) {
+	store := newTestFileStore(t, "passphrase")
+
+	names, err := store.List()
+	require.NoError(t, err)
+	assert.Empty(t, names)
+	_, err = store.Get("github/TOKEN")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoFileExists(t, store.path)
+
+	require.NoError(t, store.Set("github/TOKEN", "ghp_abc"))
+	require.NoError(t, store.Set("slack/TOKEN", "xoxb-123"))
+	require.NoError(t, store.Set("github/TOKEN", "ghp_def"))
+	assert.EqualError(t, store.Set("bad name", "x"), "invalid secret name 'bad name' (letters, digits, '.', '_', '/' and '-')")
+
+	value, err := store.Get("github/TOKEN")
+	require.NoError(t, err)
+	assert.Equal(t, "ghp_def", value)
+	names, err = store.List()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"github/TOKEN", "slack/TOKEN"}, names)
+
+	// The file is private and holds no secret in the clear
+	info, err := os.Stat(store.path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	data, err := os.ReadFile(store.path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "ghp_def")
+	assert.NotContains(t, string(data), "github/TOKEN")
+
+	require.NoError(t, store.Delete("slack/TOKEN"))
+	assert.ErrorIs(t, store.Delete("slack/TOKEN"), ErrNotFound)
+	names, err = store.List()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"github/TOKEN"}, names)
+}
+
+func TestFileStore_Passphrase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.enc")
+	var asked []bool
+	store := newFileStore(Options{File: path, Passphrase: func(create bool) (string, error) {
+		asked = append(asked, create)
+		return "correct", nil
+	}})
+	store.iterations = 1000
+
+	require.NoError(t, store.Set("a", "1"))
+	require.NoError(t, store.Set("b", "2"))
+	_, err := store.Get("a")
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true}, asked, "the passphrase is asked once, when creating the file")
+
+	other := newFileStore(Options{File: path, Passphrase: func(bool) (string, error) { return "wrong", nil }})
+	_, err = other.Get("a")
+	assert.EqualError(t, err, "wrong passphrase or corrupted secrets file")
+	assert.Error(t, other.Set("c", "3"))
+
+	reopened := newFileStore(Options{File: path, Passphrase: func(create bool) (string, error) {
+		assert.False(t, create)
+		return "correct", nil
+	}})
+	value, err := reopened.Get("b")
+	require.NoError(t, err)
+	assert.Equal(t, "2", value)
+
+	t.Setenv(PassphraseEnv, "")
+	_, err = newFileStore(Options{File: path}).Get("a")
+	assert.EqualError(t, err, "set "+PassphraseEnv+" to unlock the secrets file")
+	t.Setenv(PassphraseEnv, "correct")
+	value, err = newFileStore(Options{File: path}).Get("a")
+	require.NoError(t, err)
+	assert.Equal(t, "1", value)
+}
+
+func TestFileStore_Corrupted(t *testing.T) {
+	store := newTestFileStore(t, "passphrase")
+	require.NoError(t, os.WriteFile(store.path, []byte("not json"), 0600))
+	_, err := store.Get("a")
+	assert.ErrorContains(t, err, "invalid secrets file")
+
+	require.NoError(t, os.WriteFile(store.path, []byte(`{"version": 2}`), 0600))
+	_, err = store.Get("a")
+	assert.ErrorContains(t, err, "unsupported secrets file")
+}
@@ -0,0 +1,298 @@
+package secrets
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// keychainNotFound are the exit codes of the keychain tools for missing
+// secrets
+var keychainNotFound = map[string]int{
+	"darwin":  44, // errSecItemNotFound
+	"linux":   1,
+	"windows": 3, // Set by the scripts below
+}
+
+// windowsVault loads the Credential Manager's vault in PowerShell
+const windowsVault = `[void][Windows.Security.Credentials.PasswordVault,Windows.Security.Credentials,ContentType=WindowsRuntime]; $v = New-Object Windows.Security.Credentials.PasswordVault; `
+
+// keychain stores secrets in the OS keychain through its command line
+// tool: security on macOS, secret-tool on Linux and PowerShell on Windows
+type keychain struct {
+	goos    string
+	service string // Entries are this service's accounts
+
+	// run runs a command with stdin, returning its output or a
+	// *commandError
+	run func(stdin string, args ...string) (string, error)
+}
+
+// commandError is a keychain command exiting with an error
+type commandError struct {
+	code   int
+	stderr string
+}
+
+func (e *commandError) Error() string {
+	if e.stderr != "" {
+		return e.stderr
+	}
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+// newKeychain creates a keychain store for this OS
+func newKeychain(service string) *keychain {
+	if service == "" {
+		service = "mcp-manager"
+	}
+	return &keychain{goos: runtime.GOOS, service: service, run: runCommand}
+}
+
+// runCommand runs a keychain command
+func runCommand(stdin string, args ...string) (string, error) {
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return "", &commandError{code: exitErr.ExitCode(), stderr: strings.TrimSpace(stderr.String())}
+	}
+	if err != nil {
+		return "", err
+	}
+	if args[0] == "security" && stderr.Len() > 0 {
+		// Interactive mode reports failures on stderr only
+		return "", &commandError{code: 1, stderr: strings.TrimSpace(stderr.String())}
+	}
+	return stdout.String(), nil
+}
+
+// tool returns the command the keychain is used through
+func (k *keychain) tool() string {
+	switch k.goos {
+	case "darwin":
+		return "security"
+	case "linux":
+		return "secret-tool"
+	case "windows":
+		return "powershell"
+	}
+	return ""
+}
+
+// available returns true if the keychain can be used
+func (k *keychain) available() bool {
+	if k.tool() == "" {
+		return false
+	}
+	if _, err := exec.LookPath(k.tool()); err != nil {
+		return false
+	}
+	// The Secret Service is reached over the session bus, which headless
+	// machines often lack
+	return k.goos != "linux" || os.Getenv("DBUS_SESSION_BUS_ADDRESS") != ""
+}
+
+// requirement describes what the keychain needs
+func (k *keychain) requirement() string {
+	switch k.goos {
+	case "darwin":
+		return "needs the security command"
+	case "linux":
+		return "needs secret-tool and a D-Bus session"
+	case "windows":
+		return "needs PowerShell"
+	}
+	return "unsupported on " + k.goos
+}
+
+// Backend returns BackendKeychain
+func (k *keychain) Backend() string {
+	return BackendKeychain
+}
+
+// Get returns a secret
+func (k *keychain) Get(name string) (string, error) {
+	if err := ValidateName(name); err != nil {
+		return "", err
+	}
+
+	var out string
+	var err error
+	switch k.goos {
+	case "darwin":
+		out, err = k.run("", "security", "find-generic-password", "-s", k.service, "-a", name, "-w")
+		out = strings.TrimSuffix(out, "\n")
+	case "linux":
+		out, err = k.run("", "secret-tool", "lookup", "service", k.service, "account", name)
+	case "windows":
+		out, err = k.powershell("", fmt.Sprintf(`try { $c = $v.Retrieve('%s', '%s') } catch { exit 3 }; $c.RetrievePassword(); [Console]::Out.Write($c.Password)`, k.service, name))
+	default:
+		return "", fmt.Errorf("no OS keychain (%s)", k.requirement())
+	}
+	return out, k.check(err)
+}
+
+// Set stores a secret, replacing it if it exists. Values are passed on
+// stdin, never on the command line.
+func (k *keychain) Set(name, value string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+
+	var err error
+	switch k.goos {
+	case "darwin":
+		// -X takes the value in hex, so it needs no quoting
+		command := fmt.Sprintf("add-generic-password -U -s %s -a %s -X %s\n", k.service, name, hex.EncodeToString([]byte(value)))
+		_, err = k.run(command, "security", "-i")
+	case "linux":
+		_, err = k.run(value, "secret-tool", "store", "--label", k.service+": "+name, "service", k.service, "account", name)
+	case "windows":
+		_, err = k.powershell(value, fmt.Sprintf(`$value = [Console]::In.ReadToEnd(); try { $v.Remove($v.Retrieve('%s', '%s')) } catch {}; $v.Add((New-Object Windows.Security.Credentials.PasswordCredential('%s', '%s', $value)))`, k.service, name, k.service, name))
+	default:
+		return fmt.Errorf("no OS keychain (%s)", k.requirement())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to store secret: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a secret
+func (k *keychain) Delete(name string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+
+	var err error
+	switch k.goos {
+	case "darwin":
+		_, err = k.run("", "security", "delete-generic-password", "-s", k.service, "-a", name)
+	case "linux":
+		// clear succeeds for missing secrets too
+		if _, err = k.Get(name); err != nil {
+			return err
+		}
+		_, err = k.run("", "secret-tool", "clear", "service", k.service, "account", name)
+	case "windows":
+		_, err = k.powershell("", fmt.Sprintf(`try { $c = $v.Retrieve('%s', '%s') } catch { exit 3 }; $v.Remove($c)`, k.service, name))
+	default:
+		return fmt.Errorf("no OS keychain (%s)", k.requirement())
+	}
+	return k.check(err)
+}
+
+// List returns the names of the secrets of the service
+func (k *keychain) List() ([]string, error) {
+	var names []string
+	switch k.goos {
+	case "darwin":
+		out, err := k.run("", "security", "dump-keychain")
+		if err != nil {
+			return nil, err
+		}
+		names = parseDumpKeychain(out, k.service)
+	case "linux":
+		out, err := k.run("", "secret-tool", "search", "--all", "service", k.service)
+		if err != nil && !k.notFound(err) {
+			return nil, err
+		}
+		names = parseSecretToolSearch(out)
+	case "windows":
+		out, err := k.powershell("", fmt.Sprintf(`try { $v.FindAllByResource('%s') | ForEach-Object { $_.UserName } } catch {}`, k.service))
+		if err != nil {
+			return nil, err
+		}
+		names = strings.Fields(out)
+	default:
+		return nil, fmt.Errorf("no OS keychain (%s)", k.requirement())
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// powershell runs a script with the vault loaded as $v
+func (k *keychain) powershell(stdin, script string) (string, error) {
+	return k.run(stdin, "powershell", "-NoProfile", "-NonInteractive", "-Command", windowsVault+script)
+}
+
+// check converts the error of a command reading a secret
+func (k *keychain) check(err error) error {
+	if err == nil {
+		return nil
+	}
+	if k.notFound(err) {
+		return ErrNotFound
+	}
+	return fmt.Errorf("keychain: %w", err)
+}
+
+// notFound returns true if a command failed because the secret is missing
+func (k *keychain) notFound(err error) bool {
+	var cmdErr *commandError
+	return errors.As(err, &cmdErr) && cmdErr.code == keychainNotFound[k.goos]
+}
+
+// dumpAttribute matches the attributes of security dump-keychain
+var dumpAttribute = regexp.MustCompile(`^\s*"(acct|svce)"<blob>="(.*)"$`)
+
+// parseDumpKeychain returns the accounts of the service's generic
+// passwords in the output of security dump-keychain
+func parseDumpKeychain(out, service string) []string {
+	var names []string
+	var account, svc string
+	var generic bool
+	flush := func() {
+		if generic && svc == service && account != "" {
+			names = append(names, account)
+		}
+		account, svc, generic = "", "", false
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "keychain: "):
+			flush()
+		case line == `class: "genp"`:
+			generic = true
+		default:
+			if m := dumpAttribute.FindStringSubmatch(line); m != nil {
+				if m[1] == "acct" {
+					account = m[2]
+				} else {
+					svc = m[2]
+				}
+			}
+		}
+	}
+	flush()
+	return names
+}
+
+// parseSecretToolSearch returns the accounts in the output of
+// secret-tool search
+func parseSecretToolSearch(out string) []string {
+	var names []string
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		if name, ok := strings.CutPrefix(scanner.Text(), "attribute.account = "); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
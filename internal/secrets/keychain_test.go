@@ -0,0 +1,138 @@
+package secrets
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeKeychain records the commands of a keychain and answers them
+type fakeKeychain struct {
+	commands []string
+	stdin    []string
+	out      string
+	err      error
+}
+
+// keychain returns a keychain for goos running commands through f
+func (f *fakeKeychain) keychain(goos string) *keychain {
+	return &keychain{goos: goos, service: "mcp-manager", run: func(stdin string, args ...string) (string, error) {
+		f.commands = append(f.commands, strings.Join(args, " "))
+		f.stdin = append(f.stdin, stdin)
+		return f.out, f.err
+	}}
+}
+
+func TestKeychain_Darwin(t *testing.T) {
+	f := &fakeKeychain{out: "ghp_abc\n"}
+	k := f.keychain("darwin")
+
+	value, err := k.Get("github/TOKEN")
+	require.NoError(t, err)
+	assert.Equal(t, "ghp_abc", value)
+
+	require.NoError(t, k.Set("github/TOKEN", `ghp_"def`))
+	assert.Equal(t, "security -i", f.commands[1], "values stay off the command line")
+	assert.Equal(t, "add-generic-password -U -s mcp-manager -a github/TOKEN -X 6768705f22646566\n", f.stdin[1])
+
+	require.NoError(t, k.Delete("github/TOKEN"))
+	assert.Equal(t, []string{
+		"security find-generic-password -s mcp-manager -a github/TOKEN -w",
+		"security -i",
+		"security delete-generic-password -s mcp-manager -a github/TOKEN",
+	}, f.commands)
+
+	f.err = &commandError{code: 44}
+	_, err = k.Get("missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+	f.err = &commandError{code: 51, stderr: "User interaction is not allowed."}
+	_, err = k.Get("locked")
+	assert.EqualError(t, err, "keychain: User interaction is not allowed.")
+}
+
+func TestKeychain_Linux(t *testing.T) {
+	f := &fakeKeychain{out: "xoxb-123"}
+	k := f.keychain("linux")
+
+	value, err := k.Get("slack/TOKEN")
+	require.NoError(t, err)
+	assert.Equal(t, "xoxb-123", value)
+	require.NoError(t, k.Set("slack/TOKEN", "xoxb-456"))
+	assert.Equal(t, "xoxb-456", f.stdin[1])
+	require.NoError(t, k.Delete("slack/TOKEN"))
+	assert.Equal(t, []string{
+		"secret-tool lookup service mcp-manager account slack/TOKEN",
+		"secret-tool store --label mcp-manager: slack/TOKEN service mcp-manager account slack/TOKEN",
+		"secret-tool lookup service mcp-manager account slack/TOKEN",
+		"secret-tool clear service mcp-manager account slack/TOKEN",
+	}, f.commands)
+
+	f.out = "[/org/freedesktop/secrets/collection/login/2]\nlabel = mcp-manager: b\nsecret = 2\nattribute.account = b\nattribute.service = mcp-manager\n" +
+		"[/org/freedesktop/secrets/collection/login/1]\nlabel = mcp-manager: a\nsecret = 1\nattribute.account = a\nattribute.service = mcp-manager\n"
+	names, err := k.List()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, names)
+
+	f.out, f.err = "", &commandError{code: 1}
+	names, err = k.List()
+	require.NoError(t, err)
+	assert.Empty(t, names)
+	assert.ErrorIs(t, k.Delete("missing"), ErrNotFound)
+}
+
+func TestKeychain_Windows(t *testing.T) {
+	f := &fakeKeychain{out: "a\r\nb\r\n"}
+	k := f.keychain("windows")
+
+	require.NoError(t, k.Set("github/TOKEN", "ghp_abc"))
+	assert.Equal(t, "ghp_abc", f.stdin[0])
+	assert.NotContains(t, f.commands[0], "ghp_abc")
+	assert.True(t, strings.HasPrefix(f.commands[0], "powershell -NoProfile -NonInteractive -Command "))
+
+	names, err := k.List()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, names)
+
+	f.err = &commandError{code: 3}
+	_, err = k.Get("missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestKeychain_InvalidName(t *testing.T) {
+	f := &fakeKeychain{}
+	k := f.keychain("windows")
+	_, err := k.Get("x'); Remove-Item C:\\ #")
+	assert.Error(t, err)
+	assert.Empty(t, f.commands, "names are validated before reaching a command")
+}
+
+func TestParseDumpKeychain(t *testing.T) {
+	out := `keychain: "/Users/me/Library/Keychains/login.keychain-db"
+version: 512
+class: "genp"
+attributes:
+    "acct"<blob>="github/TOKEN"
+    "svce"<blob>="mcp-manager"
+keychain: "/Users/me/Library/Keychains/login.keychain-db"
+version: 512
+class: "genp"
+attributes:
+    "acct"<blob>="other"
+    "svce"<blob>="mcp-manager-work"
+keychain: "/Users/me/Library/Keychains/login.keychain-db"
+version: 512
+class: "inet"
+attributes:
+    "acct"<blob>="web"
+    "svce"<blob>="mcp-manager"
+keychain: "/Users/me/Library/Keychains/login.keychain-db"
+version: 512
+class: "genp"
+attributes:
+    "acct"<blob>="slack/TOKEN"
+    "svce"<blob>="mcp-manager"
+`
+	assert.Equal(t, []string{"github/TOKEN", "slack/TOKEN"}, parseDumpKeychain(out, "mcp-manager"))
+}
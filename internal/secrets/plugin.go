@@ -0,0 +1,77 @@
+package secrets
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/tartavull/mcp-manager/internal/plugin"
+)
+
+// pluginStore stores secrets through a secrets plugin
+type pluginStore struct {
+	plugin *plugin.Plugin
+	config json.RawMessage
+}
+
+// newPluginStore creates a store calling p with config
+func newPluginStore(p *plugin.Plugin, config json.RawMessage) *pluginStore {
+	return &pluginStore{plugin: p, config: config}
+}
+
+// Backend returns the plugin the secrets are stored with
+func (s *pluginStore) Backend() string {
+	return "plugin " + s.plugin.Name
+}
+
+// Get returns a secret
+func (s *pluginStore) Get(name string) (string, error) {
+	if err := ValidateName(name); err != nil {
+		return "", err
+	}
+	var result plugin.SecretResult
+	if err := s.call(plugin.MethodGet, plugin.SecretParams{Name: name}, &result); err != nil {
+		return "", err
+	}
+	return result.Value, nil
+}
+
+// Set stores a secret, replacing it if it exists
+func (s *pluginStore) Set(name, value string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	return s.call(plugin.MethodSet, plugin.SecretParams{Name: name, Value: value}, nil)
+}
+
+// Delete removes a secret
+func (s *pluginStore) Delete(name string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	return s.call(plugin.MethodDelete, plugin.SecretParams{Name: name}, nil)
+}
+
+// List returns the names of the secrets
+func (s *pluginStore) List() ([]string, error) {
+	var result plugin.SecretList
+	if err := s.call(plugin.MethodList, nil, &result); err != nil {
+		return nil, err
+	}
+	sort.Strings(result.Names)
+	return result.Names, nil
+}
+
+// call runs a method of the plugin, converting missing secrets to
+// ErrNotFound
+func (s *pluginStore) call(method string, params, result interface{}) error {
+	err := s.plugin.Call(method, s.config, params, result)
+	if errors.Is(err, plugin.ErrNotFound) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("plugin %s: %w", s.plugin.Name, err)
+	}
+	return nil
+}
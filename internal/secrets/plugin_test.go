@@ -0,0 +1,57 @@
+package secrets
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// vaultPlugin is a secrets plugin knowing a single secret
+const vaultPlugin = `#!/bin/sh
+req=$(cat)
+case "$req" in
+*'"describe"'*) echo '{"result":{"kinds":["secrets"]}}' ;;
+*'"secrets.get"'*'"github/TOKEN"'*) echo '{"result":{"value":"ghp_abc"}}' ;;
+*'"secrets.get"'*) echo '{"error":"no such secret","code":"not_found"}' ;;
+*'"secrets.list"'*) echo '{"result":{"names":["slack/TOKEN","github/TOKEN"]}}' ;;
+*'"secrets.set"'*'"vault":"readonly"'*) echo '{"error":"vault is read-only"}' ;;
+*) echo '{}' ;;
+esac
+`
+
+func TestPluginStore(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are sh scripts")
+	}
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "vault"), []byte(vaultPlugin), 0755))
+
+	store, err := Open(Options{Backend: BackendPlugin, Plugin: "vault", PluginDir: dir})
+	require.NoError(t, err)
+	assert.Equal(t, "plugin vault", store.Backend())
+
+	value, err := store.Get("github/TOKEN")
+	require.NoError(t, err)
+	assert.Equal(t, "ghp_abc", value)
+	_, err = store.Get("missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	names, err := store.List()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"github/TOKEN", "slack/TOKEN"}, names)
+	assert.NoError(t, store.Set("github/TOKEN", "ghp_def"))
+	assert.NoError(t, store.Delete("github/TOKEN"))
+	assert.Error(t, store.Set("bad name", "x"))
+
+	readonly, err := Open(Options{Backend: BackendPlugin, Plugin: "vault", PluginDir: dir, PluginConfig: json.RawMessage(`{"vault":"readonly"}`)})
+	require.NoError(t, err)
+	assert.EqualError(t, readonly.Set("github/TOKEN", "x"), "plugin vault: vault is read-only")
+
+	_, err = Open(Options{Backend: BackendPlugin, Plugin: "missing", PluginDir: dir})
+	assert.Error(t, err)
+}
@@ -0,0 +1,237 @@
+// Package secrets keeps credentials such as API tokens out of mcp.json,
+// in the OS keychain, in a file encrypted with a passphrase or in one
+// encrypted with SOPS or age. Server env values reference them as
+// secret:<name>, or within a value as ${secret:<name>}.
+package secrets
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/plugin"
+)
+
+const (
+	// BackendKeychain stores secrets in the OS keychain: the macOS
+	// Keychain, the Secret Service on Linux or the Windows Credential
+	// Manager
+	BackendKeychain = "keychain"
+
+	// BackendFile stores secrets in a file encrypted with a passphrase
+	BackendFile = "file"
+
+	// BackendPlugin stores secrets through a secrets plugin, e.g. in a
+	// password manager or a cloud secrets manager
+	BackendPlugin = "plugin"
+
+	// PassphraseEnv holds the passphrase of the encrypted file, e.g. for
+	// the daemon, which can't prompt for it
+	PassphraseEnv = "MCP_MANAGER_SECRETS_PASSPHRASE"
+
+	// refPrefix starts the env values referencing a secret
+	refPrefix = "secret:"
+)
+
+// ErrNotFound is returned for secrets that aren't stored
+var ErrNotFound = errors.New("secret not found")
+
+// validName matches secret names, e.g. github/GITHUB_TOKEN
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._/-]*$`)
+
+// inlineRef matches the references to secrets within env values, e.g.
+// Bearer ${secret:slack/TOKEN}
+var inlineRef = regexp.MustCompile(`\$\{secret:([A-Za-z0-9][A-Za-z0-9._/-]*)\}`)
+
+// Store keeps secrets by name
+type Store interface {
+	Get(name string) (string, error)
+	Set(name, value string) error
+	Delete(name string) error
+	List() ([]string, error) // Sorted names
+	Backend() string
+}
+
+// Options select and configure a store
+type Options struct {
+	// Backend is BackendKeychain, BackendFile or BackendPlugin; empty uses
+	// the keychain if there is one and the file otherwise
+	Backend string
+
+	// Service names the keychain entries, e.g. mcp-manager
+	Service string
+
+	// File is the path of the encrypted file
+	File string
+
+	// Encrypted is a SOPS- or age-encrypted file of secrets, read instead
+	// of the backend
+	Encrypted string
+
+	// Plugin is the secrets plugin of BackendPlugin, found in PluginDir
+	// and passed PluginConfig
+	Plugin       string
+	PluginConfig json.RawMessage
+	PluginDir    string
+
+	// Passphrase returns the passphrase of the file when it's first
+	// needed, create telling if it will protect a new file; nil reads
+	// PassphraseEnv
+	Passphrase func(create bool) (string, error)
+}
+
+// ConfigOptions returns the options of the store of an instance, keeping
+// the secrets of instances apart
+func ConfigOptions(cfg *config.Config, settings *config.SecretsConfig) Options {
+	opts := Options{Service: "mcp-manager", File: cfg.GetSecretsPath(), PluginDir: cfg.GetPluginDir()}
+	if cfg.Instance != "" {
+		opts.Service += "-" + cfg.Instance
+	}
+	if settings != nil {
+		opts.Backend = settings.Backend
+		opts.Plugin, opts.PluginConfig = settings.Plugin, settings.Config
+		if settings.File != "" {
+			opts.Encrypted = settings.File
+			if !filepath.IsAbs(opts.Encrypted) {
+				opts.Encrypted = filepath.Join(cfg.ConfigDir, opts.Encrypted)
+			}
+		}
+	}
+	return opts
+}
+
+// Open returns the store selected by opts
+func Open(opts Options) (Store, error) {
+	if opts.Encrypted != "" {
+		return newEncryptedStore(opts.Encrypted), nil
+	}
+
+	switch opts.Backend {
+	case "":
+		if k := newKeychain(opts.Service); k.available() {
+			return k, nil
+		}
+		return newFileStore(opts), nil
+	case BackendKeychain:
+		k := newKeychain(opts.Service)
+		if !k.available() {
+			return nil, fmt.Errorf("no OS keychain available (%s)", k.requirement())
+		}
+		return k, nil
+	case BackendFile:
+		return newFileStore(opts), nil
+	case BackendPlugin:
+		p, err := plugin.Find(opts.PluginDir, opts.Plugin, plugin.KindSecrets)
+		if err != nil {
+			return nil, err
+		}
+		return newPluginStore(p, opts.PluginConfig), nil
+	default:
+		return nil, fmt.Errorf("unknown secrets backend '%s' (keychain, file, plugin)", opts.Backend)
+	}
+}
+
+// ValidateName returns an error if name can't name a secret
+func ValidateName(name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("invalid secret name '%s' (letters, digits, '.', '_', '/' and '-')", name)
+	}
+	return nil
+}
+
+// Ref returns the env value referencing a secret
+func Ref(name string) string {
+	return refPrefix + name
+}
+
+// ParseRef returns the name of the secret an env value references
+func ParseRef(value string) (string, bool) {
+	name, ok := strings.CutPrefix(value, refPrefix)
+	return name, ok && name != ""
+}
+
+// RefNames returns the names of the secrets an env value references, as a
+// whole or with ${secret:<name>}
+func RefNames(value string) []string {
+	if name, ok := ParseRef(value); ok {
+		return []string{name}
+	}
+	var names []string
+	for _, match := range inlineRef.FindAllStringSubmatch(value, -1) {
+		names = append(names, match[1])
+	}
+	return names
+}
+
+// HasRefs returns true if any env value references a secret
+func HasRefs(env map[string]string) bool {
+	for _, value := range env {
+		if len(RefNames(value)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve returns env with the secrets its values reference. Secrets are
+// looked up once even if referenced several times.
+func Resolve(store Store, env map[string]string) (map[string]string, error) {
+	if !HasRefs(env) {
+		return env, nil
+	}
+	if store == nil {
+		return nil, fmt.Errorf("no secrets store")
+	}
+
+	resolved := make(map[string]string, len(env))
+	values := make(map[string]string)
+	lookup := func(key, name string) (string, error) {
+		secret, cached := values[name]
+		if !cached {
+			var err error
+			if secret, err = store.Get(name); err != nil {
+				return "", fmt.Errorf("secret '%s' of %s: %w", name, key, err)
+			}
+			values[name] = secret
+		}
+		return secret, nil
+	}
+
+	for key, value := range env {
+		if name, ok := ParseRef(value); ok {
+			secret, err := lookup(key, name)
+			if err != nil {
+				return nil, err
+			}
+			resolved[key] = secret
+			continue
+		}
+
+		var err error
+		resolved[key] = inlineRef.ReplaceAllStringFunc(value, func(ref string) string {
+			secret, lookupErr := lookup(key, inlineRef.FindStringSubmatch(ref)[1])
+			if lookupErr != nil && err == nil {
+				err = lookupErr
+			}
+			return secret
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return resolved, nil
+}
+
+// envPassphrase reads the passphrase from PassphraseEnv
+func envPassphrase() (string, error) {
+	passphrase := os.Getenv(PassphraseEnv)
+	if passphrase == "" {
+		return "", fmt.Errorf("set %s to unlock the secrets file", PassphraseEnv)
+	}
+	return passphrase, nil
+}
@@ -0,0 +1,122 @@
+package secrets
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/internal/config"
+)
+
+// newTestFileStore creates a file store with a cheap key derivation
+func newTestFileStore(t *testing.T, passphrase string) *fileStore {
+	s := newFileStore(Options{
+		File:       filepath.Join(t.TempDir(), "secrets.enc"),
+		Passphrase: func(bool) (string, error) { return passphrase, nil },
+	})
+	s.iterations = 1000
+	return s
+}
+
+func TestRefs(t *testing.T) {
+	assert.Equal(t, "secret:github/TOKEN", Ref("github/TOKEN"))
+
+	name, ok := ParseRef("secret:github/TOKEN")
+	assert.True(t, ok)
+	assert.Equal(t, "github/TOKEN", name)
+	_, ok = ParseRef("secret:")
+	assert.False(t, ok)
+	_, ok = ParseRef("ghp_abc")
+	assert.False(t, ok)
+
+	assert.Equal(t, []string{"github/TOKEN"}, RefNames("secret:github/TOKEN"))
+	assert.Equal(t, []string{"slack/TOKEN", "slack/TEAM"}, RefNames("Bearer ${secret:slack/TOKEN} ${secret:slack/TEAM}"))
+	assert.Empty(t, RefNames("${GITHUB_TOKEN}"))
+	assert.Empty(t, RefNames("${secret:}"))
+
+	assert.True(t, HasRefs(map[string]string{"A": "1", "B": "secret:b"}))
+	assert.True(t, HasRefs(map[string]string{"A": "${secret:a}"}))
+	assert.False(t, HasRefs(map[string]string{"A": "1"}))
+	assert.False(t, HasRefs(nil))
+}
+
+func TestValidateName(t *testing.T) {
+	for _, name := range []string{"github/GITHUB_TOKEN", "a", "slack.bot-token"} {
+		assert.NoError(t, ValidateName(name), name)
+	}
+	for _, name := range []string{"", "/abs", "with space", "quote'", `double"`, "semi;colon"} {
+		assert.Error(t, ValidateName(name), name)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	store := newTestFileStore(t, "passphrase")
+	require.NoError(t, store.Set("github/TOKEN", "ghp_abc"))
+	require.NoError(t, store.Set("github/USER", "octocat"))
+
+	env := map[string]string{"PLAIN": "value"}
+	resolved, err := Resolve(nil, env)
+	require.NoError(t, err)
+	assert.Equal(t, env, resolved, "env without references needs no store")
+
+	resolved, err = Resolve(store, map[string]string{
+		"PLAIN":        "value",
+		"GITHUB_TOKEN": "secret:github/TOKEN",
+		"GH_TOKEN":     "${secret:github/TOKEN}",
+		"AUTH_HEADER":  "Basic ${secret:github/USER}:${secret:github/TOKEN}",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"PLAIN":        "value",
+		"GITHUB_TOKEN": "ghp_abc",
+		"GH_TOKEN":     "ghp_abc",
+		"AUTH_HEADER":  "Basic octocat:ghp_abc",
+	}, resolved)
+
+	_, err = Resolve(store, map[string]string{"API_KEY": "secret:missing"})
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.EqualError(t, err, "secret 'missing' of API_KEY: secret not found")
+
+	_, err = Resolve(store, map[string]string{"URL": "https://${secret:github/USER}:${secret:missing}@example.com"})
+	assert.EqualError(t, err, "secret 'missing' of URL: secret not found")
+
+	_, err = Resolve(nil, map[string]string{"API_KEY": "secret:missing"})
+	assert.EqualError(t, err, "no secrets store")
+}
+
+func TestOpen(t *testing.T) {
+	store, err := Open(Options{Backend: BackendFile, File: filepath.Join(t.TempDir(), "secrets.enc")})
+	require.NoError(t, err)
+	assert.Equal(t, BackendFile, store.Backend())
+
+	_, err = Open(Options{Backend: "vault"})
+	assert.EqualError(t, err, "unknown secrets backend 'vault' (keychain, file, plugin)")
+
+	// Without a keychain, the file is used
+	t.Setenv("PATH", t.TempDir())
+	store, err = Open(Options{File: filepath.Join(t.TempDir(), "secrets.enc")})
+	require.NoError(t, err)
+	assert.Equal(t, BackendFile, store.Backend())
+	_, err = Open(Options{Backend: BackendKeychain})
+	assert.ErrorContains(t, err, "no OS keychain available")
+}
+
+func TestConfigOptions(t *testing.T) {
+	cfg := &config.Config{ConfigDir: "/config"}
+	assert.Equal(t, Options{Service: "mcp-manager", File: "/config/secrets.enc", PluginDir: "/config/plugins"}, ConfigOptions(cfg, nil))
+
+	cfg.Instance = "work"
+	opts := ConfigOptions(cfg, &config.SecretsConfig{Backend: BackendFile})
+	assert.Equal(t, "mcp-manager-work", opts.Service)
+	assert.Equal(t, BackendFile, opts.Backend)
+}
+
+func TestConfigOptions_EncryptedFile(t *testing.T) {
+	cfg := &config.Config{ConfigDir: "/config"}
+	opts := ConfigOptions(cfg, &config.SecretsConfig{File: "secrets.sops.yaml"})
+	assert.Equal(t, "/config/secrets.sops.yaml", opts.Encrypted)
+
+	opts = ConfigOptions(cfg, &config.SecretsConfig{File: "/dotfiles/secrets.age"})
+	assert.Equal(t, "/dotfiles/secrets.age", opts.Encrypted)
+}
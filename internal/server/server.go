@@ -2,7 +2,9 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 	"time"
 )
 
@@ -17,25 +19,209 @@ const (
 	StatusError    Status = "error"
 )
 
+// Errors of operations on servers, wrapped with the server's name
+var (
+	ErrNotFound       = errors.New("not found")
+	ErrAlreadyRunning = errors.New("already running")
+	ErrNotRunning     = errors.New("not running")
+	ErrExists         = errors.New("already exists")
+)
+
+// Health represents the result of a server's custom health check
+type Health string
+
+const (
+	HealthUnknown   Health = ""
+	HealthHealthy   Health = "healthy"
+	HealthPending   Health = "pending" // Checked, but no result yet
+	HealthUnhealthy Health = "unhealthy"
+)
+
+// HealthCheck configures an external readiness check for a server
+type HealthCheck struct {
+	Command  string        // Shell command; healthy when it exits 0
+	URL      string        // HTTP URL; healthy on a 2xx response
+	Interval time.Duration // Time between checks
+	Timeout  time.Duration // Maximum duration of a single check
+}
+
+// RestartMode selects when a server that exited is restarted
+type RestartMode string
+
+const (
+	RestartNever     RestartMode = "never"
+	RestartOnFailure RestartMode = "on-failure"
+	RestartAlways    RestartMode = "always"
+)
+
+// RestartPolicy configures the automatic restart of servers that exit
+type RestartPolicy struct {
+	Mode        RestartMode
+	MaxRestarts int           // Consecutive restarts before giving up; 0 is unlimited
+	Backoff     time.Duration // Delay before the first restart, doubled after each one
+	MaxBackoff  time.Duration // Cap on the delay between restarts
+}
+
+// String describes the policy for display
+func (p *RestartPolicy) String() string {
+	if p == nil {
+		return string(RestartNever)
+	}
+	if p.MaxRestarts > 0 {
+		return fmt.Sprintf("%s (max %d)", p.Mode, p.MaxRestarts)
+	}
+	return string(p.Mode)
+}
+
+// Hooks are shell commands run around a server's lifecycle
+type Hooks struct {
+	PreStart  string
+	PostStart string
+	PreStop   string
+	PostStop  string
+	OnCrash   string
+	Timeout   time.Duration // Maximum duration of a single hook
+}
+
+// IOClass is an I/O scheduling class, as for ionice(1)
+type IOClass string
+
+const (
+	IORealtime   IOClass = "realtime"
+	IOBestEffort IOClass = "best-effort"
+	IOIdle       IOClass = "idle"
+)
+
+// Priority is the CPU and I/O scheduling priority of a server's processes
+type Priority struct {
+	Nice    int     // Niceness, 0 is the default
+	IOClass IOClass // Empty keeps the default class
+	IOLevel *int    // Level within the realtime and best-effort classes
+}
+
+// Requirements are devices and paths a server needs to start
+type Requirements struct {
+	Devices []string // Device files or glob patterns matching at least one
+	Mounts  []string // Files or directories; a leading ~ is the home directory
+}
+
+// Resolver overrides name resolution for a server
+type Resolver struct {
+	Hosts       map[string]string // Host name to address, as in /etc/hosts
+	Nameservers []string          // Replace the system nameservers
+}
+
+// MiddlewareConfig selects a proxy middleware and its settings
+type MiddlewareConfig struct {
+	Name   string                 `json:"name"`
+	Config map[string]interface{} `json:"config,omitempty"`
+}
+
+// ResultLimit caps the size of tool results returned by a server's proxy
+type ResultLimit struct {
+	MaxSize int  `json:"maxSize"`         // Maximum result size in bytes
+	Spill   bool `json:"spill,omitempty"` // Keep full results on disk for retrieval
+}
+
+// ReadOnly makes a server's proxy reject calls to tools that may modify
+// anything. Tools are read-only when annotated with readOnlyHint.
+type ReadOnly struct {
+	Allow []string `json:"allow,omitempty"` // Tools allowed regardless of annotations
+	Deny  []string `json:"deny,omitempty"`  // Tools rejected regardless of annotations
+}
+
+// Transport runs a server through a transport plugin, which replaces its
+// command with one speaking MCP over stdio just the same
+type Transport struct {
+	Plugin string          `json:"plugin"`
+	Config json.RawMessage `json:"config,omitempty"` // Passed to the plugin, see its config schema
+}
+
+// Approval parks a server's calls to sensitive tools until an operator
+// approves or rejects them
+type Approval struct {
+	Tools   []string      // Tools needing approval
+	Timeout time.Duration // Time before a call is rejected
+}
+
+// CircuitBreaker fails requests fast while a server keeps failing to answer
+type CircuitBreaker struct {
+	Failures int           // Consecutive failures opening the circuit
+	Cooldown time.Duration // Time the circuit stays open before a trial request
+}
+
+// Chaos injects faults into a server's proxy to test how clients and the
+// manager handle failures
+type Chaos struct {
+	Latency     time.Duration // Added to every call
+	Jitter      time.Duration // Random extra latency up to this
+	DropRate    float64       // Fraction of responses lost after the server handled the call
+	RestartRate float64       // Fraction of calls killing the stdio process first
+	Methods     []string      // Methods faults apply to; empty for all
+	Seed        int64         // Seeds the fault sequence for reproducible runs; 0 for random
+}
+
 // Server represents an MCP server configuration and state
 type Server struct {
-	Name        string    `json:"name"`
-	Command     string    `json:"command"`
-	Port        int       `json:"port"` // HTTP proxy port (4001, 4002, etc.)
-	Description string    `json:"description"`
-	Status      Status    `json:"status"`
-	PID         int       `json:"pid,omitempty"`
-	ToolCount   int       `json:"tool_count,omitempty"`
-	Tools       []Tool    `json:"tools,omitempty"` // Store actual tools
-	LastUpdated time.Time `json:"last_updated,omitempty"`
+	Name           string             `json:"name"`
+	Command        string             `json:"command"`
+	Port           int                `json:"port"` // HTTP proxy port (4001, 4002, etc.)
+	Description    string             `json:"description"`
+	Env            map[string]string  `json:"-"` // Never serialized, may contain secrets
+	ProxyEnv       []string           `json:"-"` // Outbound proxy variables; nil uses the default
+	HealthCheck    *HealthCheck       `json:"-"`
+	BlueGreen      bool               `json:"-"` // Apply config changes without downtime
+	Standby        bool               `json:"-"` // Keep a second instance ready to take over
+	ShadowCommand  string             `json:"-"` // Receives mirrored tools/call traffic
+	Middleware     []MiddlewareConfig `json:"-"` // Proxy middleware chain, outermost first
+	ResultLimit    *ResultLimit       `json:"-"`
+	ReadOnly       *ReadOnly          `json:"-"`
+	Approval       *Approval          `json:"-"`
+	CircuitBreaker *CircuitBreaker    `json:"-"`
+	Chaos          *Chaos             `json:"-"`
+	RestartPolicy  *RestartPolicy     `json:"-"`
+	Hooks          *Hooks             `json:"-"`
+	Priority       *Priority          `json:"-"`
+	Requires       *Requirements      `json:"-"`
+	DataPaths      []string           `json:"-"` // Files and directories backed up, as configured
+	Resolver       *Resolver          `json:"-"`
+	Transport      *Transport         `json:"-"`
+	Restarts       int                `json:"restarts,omitempty"`        // Automatic restarts since the last manual start
+	Maintenance    bool               `json:"maintenance,omitempty"`     // Suppresses automatic restarts and alerts
+	Autostart      bool               `json:"autostart,omitempty"`       // Started when the daemon starts
+	Tags           []string           `json:"tags,omitempty"`            // Labels policies select servers by
+	Group          string             `json:"group,omitempty"`           // Template the server is an instance of
+	BrowserProfile string             `json:"browser_profile,omitempty"` // Browser profile used as the user data dir
+	Requests       int64              `json:"requests,omitempty"`        // MCP requests served by the proxy since it started
+	InFlight       int64              `json:"in_flight,omitempty"`       // MCP requests the proxy is answering
+	Draining       bool               `json:"draining,omitempty"`        // Gets no new gateway calls, stopping once idle
+	Status         Status             `json:"status"`
+	Health         Health             `json:"health,omitempty"`
+	HealthMessage  string             `json:"health_message,omitempty"`
+	Host           string             `json:"host,omitempty"`   // Daemon running the server in cluster mode
+	RunID          string             `json:"run_id,omitempty"` // Evaluation run whose fleet the server belongs to
+	PID            int                `json:"pid,omitempty"`
+	ToolCount      int                `json:"tool_count,omitempty"`
+	Tools          []Tool             `json:"tools,omitempty"`      // Store actual tools
+	StartedAt      time.Time          `json:"started_at,omitempty"` // When the server last became running
+	LastUpdated    time.Time          `json:"last_updated,omitempty"`
 }
 
 // Tool represents an MCP tool (matching proxy.Tool structure)
 type Tool struct {
-	Name        string      `json:"name"`
-	Title       string      `json:"title,omitempty"`
-	Description string      `json:"description,omitempty"`
-	InputSchema interface{} `json:"inputSchema,omitempty"`
+	Name        string           `json:"name"`
+	Title       string           `json:"title,omitempty"`
+	Description string           `json:"description,omitempty"`
+	InputSchema interface{}      `json:"inputSchema,omitempty"`
+	Annotations *ToolAnnotations `json:"annotations,omitempty"`
+}
+
+// ToolAnnotations are the hints a server gives about a tool's behavior
+type ToolAnnotations struct {
+	ReadOnlyHint    bool  `json:"readOnlyHint,omitempty"`    // Doesn't modify its environment
+	DestructiveHint *bool `json:"destructiveHint,omitempty"` // May delete or overwrite; true when unset
+	IdempotentHint  bool  `json:"idempotentHint,omitempty"`  // Repeated calls have no further effect
+	OpenWorldHint   *bool `json:"openWorldHint,omitempty"`   // Interacts with external entities; true when unset
 }
 
 // NewServer creates a new MCP server configuration
@@ -55,12 +241,36 @@ func (s *Server) IsRunning() bool {
 	return s.Status == StatusRunning
 }
 
+// HasTag returns true if the server is labeled with tag
+func (s *Server) HasTag(tag string) bool {
+	return slices.Contains(s.Tags, tag)
+}
+
+// Ready returns true if the server is running and passing its health
+// check, if it has one
+func (s *Server) Ready() bool {
+	return s.IsRunning() && (s.Health == HealthUnknown || s.Health == HealthHealthy)
+}
+
 // SetStatus updates the server status and timestamp
 func (s *Server) SetStatus(status Status) {
+	switch {
+	case status != StatusRunning:
+		s.StartedAt = time.Time{}
+	case s.Status != StatusRunning:
+		s.StartedAt = time.Now()
+	}
 	s.Status = status
 	s.LastUpdated = time.Now()
 }
 
+// SetHealth records the result of the latest health check
+func (s *Server) SetHealth(health Health, message string) {
+	s.Health = health
+	s.HealthMessage = message
+	s.LastUpdated = time.Now()
+}
+
 // SetPID sets the process ID for the running server
 func (s *Server) SetPID(pid int) {
 	s.PID = pid
@@ -85,6 +295,39 @@ func (s *Server) GetProxyURL() string {
 	return fmt.Sprintf("http://localhost:%d", s.Port)
 }
 
+// Summary counts servers for one-line status displays
+type Summary struct {
+	Running int `json:"running"`
+	Total   int `json:"total"`
+	Failing int `json:"failing"` // Servers in error or failing their health check
+}
+
+// Summarize counts the running and failing servers
+func Summarize(servers map[string]*Server) Summary {
+	summary := Summary{Total: len(servers)}
+	for _, srv := range servers {
+		switch {
+		case srv.Status == StatusError:
+			summary.Failing++
+		case srv.IsRunning():
+			summary.Running++
+			if srv.Health == HealthUnhealthy {
+				summary.Failing++
+			}
+		}
+	}
+	return summary
+}
+
+// String formats the summary as "running/total", followed by "N!" when
+// servers are failing
+func (s Summary) String() string {
+	if s.Failing > 0 {
+		return fmt.Sprintf("%d/%d %d!", s.Running, s.Total, s.Failing)
+	}
+	return fmt.Sprintf("%d/%d", s.Running, s.Total)
+}
+
 // ToJSON converts the server to JSON
 func (s *Server) ToJSON() ([]byte, error) {
 	return json.Marshal(s)
@@ -99,20 +342,3 @@ func FromJSON(data []byte) (*Server, error) {
 	}
 	return &server, nil
 }
-
-// GetDefaultServers returns the default MCP server configurations
-func GetDefaultServers() []*Server {
-	return []*Server{
-		NewServer("filesystem", "npx @modelcontextprotocol/server-filesystem@latest /tmp", 4001, "File system operations (read/write/create/delete)"),
-		NewServer("github", "npx @modelcontextprotocol/server-github@latest", 4002, "GitHub repository and issue management"),
-		NewServer("postgres", "npx @modelcontextprotocol/server-postgres@latest postgresql://localhost/mydb", 4003, "PostgreSQL database operations and queries"),
-		NewServer("google-maps", "npx @modelcontextprotocol/server-google-maps@latest", 4004, "Location services, directions, and place details"),
-		NewServer("brave-search", "npx @modelcontextprotocol/server-brave-search@latest", 4005, "Web and local search using Brave's Search API"),
-		NewServer("everything", "npx @modelcontextprotocol/server-everything@latest", 4006, "Test server with prompts, resources, and tools"),
-		NewServer("sequential-thinking", "npx @modelcontextprotocol/server-sequential-thinking@latest", 4007, "Structured problem-solving with reasoning paths"),
-		NewServer("memory", "npx @modelcontextprotocol/server-memory@latest", 4008, "Knowledge graph-based persistent memory system"),
-		NewServer("puppeteer", "npx @modelcontextprotocol/server-puppeteer@latest", 4009, "Browser automation and web scraping"),
-		NewServer("slack", "npx @modelcontextprotocol/server-slack@latest", 4010, "Channel management and messaging capabilities"),
-		NewServer("redis", "npx @modelcontextprotocol/server-redis@latest", 4011, "Interact with Redis key-value stores"),
-	}
-}
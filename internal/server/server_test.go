@@ -133,39 +133,6 @@ func TestFromJSON_InvalidData(t *testing.T) {
 	assert.Error(t, err)
 }
 
-func TestGetDefaultServers(t *testing.T) {
-	defaultServers := GetDefaultServers()
-
-	// Should have at least some default servers
-	assert.Greater(t, len(defaultServers), 0)
-
-	// Check for some expected servers
-	serverNames := make(map[string]bool)
-	for _, server := range defaultServers {
-		serverNames[server.Name] = true
-
-		// All servers should have required fields
-		assert.NotEmpty(t, server.Name)
-		assert.NotEmpty(t, server.Command)
-		assert.Greater(t, server.Port, 0)
-		assert.NotEmpty(t, server.Description)
-		assert.Equal(t, StatusStopped, server.Status)
-	}
-
-	// Check for some specific expected servers
-	expectedServers := []string{"filesystem", "github", "postgres"}
-	for _, expected := range expectedServers {
-		assert.True(t, serverNames[expected], "Expected server %s not found", expected)
-	}
-
-	// Check that ports are unique
-	ports := make(map[int]bool)
-	for _, server := range defaultServers {
-		assert.False(t, ports[server.Port], "Duplicate port %d found", server.Port)
-		ports[server.Port] = true
-	}
-}
-
 func TestStatus_String(t *testing.T) {
 	tests := []struct {
 		status   Status
@@ -207,3 +174,61 @@ func TestServer_JSONRoundTrip(t *testing.T) {
 		assert.Equal(t, server.ToolCount, newServer.ToolCount)
 	}
 }
+
+func TestSummarize(t *testing.T) {
+	unhealthy := NewServer("unhealthy", "cmd", 4003, "")
+	unhealthy.SetStatus(StatusRunning)
+	unhealthy.SetHealth(HealthUnhealthy, "exit status 1")
+	running := NewServer("running", "cmd", 4001, "")
+	running.SetStatus(StatusRunning)
+	failed := NewServer("failed", "cmd", 4002, "")
+	failed.SetStatus(StatusError)
+
+	servers := map[string]*Server{
+		"running":   running,
+		"failed":    failed,
+		"unhealthy": unhealthy,
+		"stopped":   NewServer("stopped", "cmd", 4004, ""),
+	}
+	summary := Summarize(servers)
+	assert.Equal(t, Summary{Running: 2, Total: 4, Failing: 2}, summary)
+	assert.Equal(t, "2/4 2!", summary.String())
+
+	delete(servers, "failed")
+	delete(servers, "unhealthy")
+	assert.Equal(t, "1/2", Summarize(servers).String())
+}
+
+func TestServer_StartedAt(t *testing.T) {
+	srv := NewServer("test", "cmd", 4001, "")
+	assert.True(t, srv.StartedAt.IsZero())
+
+	srv.SetStatus(StatusRunning)
+	startedAt := srv.StartedAt
+	assert.False(t, startedAt.IsZero())
+
+	// Staying running keeps the start time
+	srv.SetStatus(StatusRunning)
+	assert.Equal(t, startedAt, srv.StartedAt)
+
+	srv.SetStatus(StatusStopped)
+	assert.True(t, srv.StartedAt.IsZero())
+}
+
+func TestServer_Ready(t *testing.T) {
+	srv := NewServer("test", "cmd", 4001, "")
+	assert.False(t, srv.Ready())
+
+	// Running without a health check is ready
+	srv.SetStatus(StatusRunning)
+	assert.True(t, srv.Ready())
+
+	srv.SetHealth(HealthPending, "")
+	assert.False(t, srv.Ready())
+
+	srv.SetHealth(HealthHealthy, "")
+	assert.True(t, srv.Ready())
+
+	srv.SetHealth(HealthUnhealthy, "exit status 1")
+	assert.False(t, srv.Ready())
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	// systemdUnitName is the systemd user unit running the daemon
+	systemdUnitName = "mcp-daemon.service"
+
+	// launchdLabel is the launchd agent label running the daemon
+	launchdLabel = "com.tartavull.mcp-daemon"
+)
+
+// SystemdUnit returns a systemd user unit that runs the daemon in the foreground
+func SystemdUnit(daemonPath string, port int) string {
+	return fmt.Sprintf(`[Unit]
+Description=MCP Manager daemon
+After=network.target
+
+[Service]
+ExecStart=%s run -port %d
+Restart=on-failure
+RestartSec=5
+
+[Install]
+WantedBy=default.target
+`, daemonPath, port)
+}
+
+// LaunchdPlist returns a launchd agent that runs the daemon in the foreground
+func LaunchdPlist(daemonPath string, port int, logPath string) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>%s</string>
+	<key>ProgramArguments</key>
+	<array>
+		<string>%s</string>
+		<string>run</string>
+		<string>-port</string>
+		<string>%d</string>
+	</array>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<true/>
+	<key>StandardOutPath</key>
+	<string>%s</string>
+	<key>StandardErrorPath</key>
+	<string>%s</string>
+</dict>
+</plist>
+`, launchdLabel, daemonPath, port, logPath, logPath)
+}
+
+// Install registers the daemon as a user service that starts at login and
+// starts it immediately. It returns the path of the written service file.
+// launchd writes the daemon's output to logPath; systemd to its journal.
+func Install(daemonPath string, port int, logPath string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	switch runtime.GOOS {
+	case "linux":
+		path := filepath.Join(homeDir, ".config", "systemd", "user", systemdUnitName)
+		if err := writeFile(path, SystemdUnit(daemonPath, port)); err != nil {
+			return "", err
+		}
+		if err := run("systemctl", "--user", "daemon-reload"); err != nil {
+			return path, err
+		}
+		return path, run("systemctl", "--user", "enable", "--now", systemdUnitName)
+
+	case "darwin":
+		path := filepath.Join(homeDir, "Library", "LaunchAgents", launchdLabel+".plist")
+		if err := writeFile(path, LaunchdPlist(daemonPath, port, logPath)); err != nil {
+			return "", err
+		}
+		return path, run("launchctl", "load", "-w", path)
+
+	default:
+		return "", fmt.Errorf("service installation is not supported on %s", runtime.GOOS)
+	}
+}
+
+// writeFile writes a service file, creating its directory
+func writeFile(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create service directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write service file: %w", err)
+	}
+	return nil
+}
+
+// run executes a service manager command, including its output in errors
+func run(name string, args ...string) error {
+	out, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to run %s %s: %w: %s", name, strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
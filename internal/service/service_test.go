@@ -0,0 +1,23 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSystemdUnit(t *testing.T) {
+	unit := SystemdUnit("/usr/local/bin/mcp-daemon", 9090)
+
+	assert.Contains(t, unit, "ExecStart=/usr/local/bin/mcp-daemon run -port 9090")
+	assert.Contains(t, unit, "WantedBy=default.target")
+}
+
+func TestLaunchdPlist(t *testing.T) {
+	plist := LaunchdPlist("/usr/local/bin/mcp-daemon", 8080, "/tmp/daemon.log")
+
+	assert.Contains(t, plist, "<string>"+launchdLabel+"</string>")
+	assert.Contains(t, plist, "<string>/usr/local/bin/mcp-daemon</string>\n\t\t<string>run</string>")
+	assert.Contains(t, plist, "<string>8080</string>")
+	assert.Contains(t, plist, "<string>/tmp/daemon.log</string>")
+}
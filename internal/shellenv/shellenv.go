@@ -0,0 +1,146 @@
+package shellenv
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// loginShellTimeout bounds how long sourcing the login shell may take
+const loginShellTimeout = 5 * time.Second
+
+// Options controls how the environment for server commands is built
+type Options struct {
+	// LoginShell sources the user's login shell environment
+	LoginShell bool
+
+	// Path lists directories prepended to PATH
+	Path []string
+}
+
+var (
+	loginEnvOnce sync.Once
+	loginEnv     []string
+	loginEnvErr  error
+)
+
+// Build returns the environment for server commands, starting from the
+// daemon's own environment. The login shell environment is captured once
+// and cached, since spawning a login shell can be slow.
+func Build(opts Options) ([]string, error) {
+	env := os.Environ()
+
+	if opts.LoginShell {
+		loginEnvOnce.Do(func() {
+			loginEnv, loginEnvErr = LoginShellEnv(os.Getenv("SHELL"))
+		})
+		if loginEnvErr != nil {
+			return env, loginEnvErr
+		}
+		env = Merge(env, loginEnv)
+	}
+
+	if len(opts.Path) > 0 {
+		env = PrependPath(env, opts.Path)
+	}
+
+	return env, nil
+}
+
+// LoginShellEnv runs the given shell as a login shell and captures its environment
+func LoginShellEnv(shell string) ([]string, error) {
+	if shell == "" {
+		shell = "/bin/sh"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), loginShellTimeout)
+	defer cancel()
+
+	// Entries are NUL-terminated, as values may span lines
+	out, err := exec.CommandContext(ctx, shell, "-l", "-c", "env -0").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to capture login shell environment from %s: %w", shell, err)
+	}
+
+	return ParseEnv(string(out)), nil
+}
+
+// ParseEnv parses the output of env -0 into KEY=VALUE entries, skipping
+// those that are not variable assignments. Lines the login shell printed
+// before the first entry are dropped.
+func ParseEnv(output string) []string {
+	var env []string
+	for _, entry := range strings.Split(output, "\x00") {
+		key, _, ok := strings.Cut(entry, "=")
+		if i := strings.LastIndexByte(key, '\n'); i >= 0 {
+			entry, key = entry[i+1:], key[i+1:]
+		}
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			continue
+		}
+		env = append(env, entry)
+	}
+	return env
+}
+
+// Merge overlays the entries of overlay onto base
+func Merge(base, overlay []string) []string {
+	result := make([]string, 0, len(base)+len(overlay))
+	index := make(map[string]int)
+
+	for _, entry := range append(append([]string{}, base...), overlay...) {
+		key, _, _ := strings.Cut(entry, "=")
+		if i, exists := index[key]; exists {
+			result[i] = entry
+			continue
+		}
+		index[key] = len(result)
+		result = append(result, entry)
+	}
+
+	return result
+}
+
+// PrependPath prepends dirs to the PATH entry of env, expanding ~ to the
+// user's home directory
+func PrependPath(env []string, dirs []string) []string {
+	expanded := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		expanded = append(expanded, ExpandHome(dir))
+	}
+
+	path := Lookup(env, "PATH")
+	if path != "" {
+		expanded = append(expanded, path)
+	}
+
+	return Merge(env, []string{"PATH=" + strings.Join(expanded, string(os.PathListSeparator))})
+}
+
+// Lookup returns the value of key in env
+func Lookup(env []string, key string) string {
+	value := ""
+	for _, entry := range env {
+		if k, v, ok := strings.Cut(entry, "="); ok && k == key {
+			value = v
+		}
+	}
+	return value
+}
+
+// ExpandHome replaces a leading ~ with the user's home directory
+func ExpandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
@@ -0,0 +1,75 @@
+package shellenv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEnv(t *testing.T) {
+	output := "Welcome!\nPATH=/usr/bin:/bin\x00HOME=/home/user\x00not a variable\x00EMPTY=\x00=novalue\x00" +
+		"KEY=-----BEGIN KEY-----\nabc\nFAKE=entry\n-----END KEY-----\x00"
+
+	env := ParseEnv(output)
+	assert.Equal(t, []string{
+		"PATH=/usr/bin:/bin",
+		"HOME=/home/user",
+		"EMPTY=",
+		"KEY=-----BEGIN KEY-----\nabc\nFAKE=entry\n-----END KEY-----",
+	}, env, "multi-line values stay whole")
+}
+
+func TestMerge(t *testing.T) {
+	base := []string{"PATH=/usr/bin", "HOME=/home/user"}
+	overlay := []string{"PATH=/opt/bin:/usr/bin", "NODE_ENV=production"}
+
+	env := Merge(base, overlay)
+	assert.Equal(t, []string{"PATH=/opt/bin:/usr/bin", "HOME=/home/user", "NODE_ENV=production"}, env)
+}
+
+func TestPrependPath(t *testing.T) {
+	env := PrependPath([]string{"PATH=/usr/bin"}, []string{"/opt/homebrew/bin", "/usr/local/bin"})
+	assert.Equal(t, "/opt/homebrew/bin:/usr/local/bin:/usr/bin", Lookup(env, "PATH"))
+
+	// Works without an existing PATH
+	env = PrependPath([]string{"HOME=/home/user"}, []string{"/opt/bin"})
+	assert.Equal(t, "/opt/bin", Lookup(env, "PATH"))
+}
+
+func TestExpandHome(t *testing.T) {
+	home, err := os.UserHomeDir()
+	require.NoError(t, err)
+
+	assert.Equal(t, filepath.Join(home, ".local/bin"), ExpandHome("~/.local/bin"))
+	assert.Equal(t, home, ExpandHome("~"))
+	assert.Equal(t, "/usr/bin", ExpandHome("/usr/bin"))
+	assert.Equal(t, "~user/bin", ExpandHome("~user/bin"))
+}
+
+func TestLoginShellEnv(t *testing.T) {
+	// Fake shell that ignores its flags and prints a fixed environment
+	shell := filepath.Join(t.TempDir(), "fakeshell")
+	script := "#!/bin/sh\nprintf 'PATH=/login/bin:/usr/bin\\0NVM_DIR=/home/user/.nvm\\0'\n"
+	require.NoError(t, os.WriteFile(shell, []byte(script), 0755))
+
+	env, err := LoginShellEnv(shell)
+	require.NoError(t, err)
+	assert.Equal(t, "/login/bin:/usr/bin", Lookup(env, "PATH"))
+	assert.Equal(t, "/home/user/.nvm", Lookup(env, "NVM_DIR"))
+}
+
+func TestLoginShellEnv_Failure(t *testing.T) {
+	_, err := LoginShellEnv(filepath.Join(t.TempDir(), "missing-shell"))
+	assert.Error(t, err)
+}
+
+func TestBuild_PathOnly(t *testing.T) {
+	t.Setenv("PATH", "/usr/bin")
+
+	env, err := Build(Options{Path: []string{"/opt/bin"}})
+	require.NoError(t, err)
+	assert.Equal(t, "/opt/bin:/usr/bin", Lookup(env, "PATH"))
+}
@@ -0,0 +1,123 @@
+package transcript
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"sort"
+	"time"
+)
+
+// FixtureVersion is the version of the fixture format written by NewFixture
+const FixtureVersion = 1
+
+// Fixture holds the tool calls recorded in a time window, grouped by
+// server, so a mock server can replay them in tests
+type Fixture struct {
+	Version int                       `json:"version"`
+	From    time.Time                 `json:"from"`
+	To      time.Time                 `json:"to"`
+	Servers map[string]*FixtureServer `json:"servers"`
+	Skipped int                       `json:"skipped,omitempty"` // Calls whose shortened results can't be replayed
+}
+
+// FixtureServer holds the tools and calls of a server in a fixture
+type FixtureServer struct {
+	Tools []string      `json:"tools"`
+	Calls []FixtureCall `json:"calls"`
+}
+
+// FixtureCall is a recorded tool call and its result or error
+type FixtureCall struct {
+	Tool      string          `json:"tool"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+	Result    json.RawMessage `json:"result,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// NewFixture returns the tool calls of sessions made between from and to,
+// in the order they were made. A zero from or to leaves the window open,
+// and is set to the time of the first or last call.
+func NewFixture(sessions []Session, from, to time.Time) *Fixture {
+	var entries []Entry
+	for _, session := range sessions {
+		for _, entry := range session.Entries {
+			if entry.Method != "tools/call" || entry.Tool == "" {
+				continue
+			}
+			if (!from.IsZero() && entry.Time.Before(from)) || (!to.IsZero() && entry.Time.After(to)) {
+				continue
+			}
+			entries = append(entries, entry)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time.Before(entries[j].Time) })
+	if len(entries) > 0 {
+		if from.IsZero() {
+			from = entries[0].Time
+		}
+		if to.IsZero() {
+			to = entries[len(entries)-1].Time
+		}
+	}
+
+	fixture := &Fixture{Version: FixtureVersion, From: from, To: to, Servers: map[string]*FixtureServer{}}
+	for _, entry := range entries {
+		call := FixtureCall{Tool: entry.Tool, Error: entry.Error}
+		if entry.Arguments != "" && json.Valid([]byte(entry.Arguments)) {
+			call.Arguments = json.RawMessage(entry.Arguments)
+		}
+		if entry.Error == "" {
+			if !json.Valid([]byte(entry.Result)) {
+				fixture.Skipped++
+				continue
+			}
+			call.Result = json.RawMessage(entry.Result)
+		}
+
+		server := fixture.Servers[entry.Server]
+		if server == nil {
+			server = &FixtureServer{}
+			fixture.Servers[entry.Server] = server
+		}
+		if !slices.Contains(server.Tools, entry.Tool) {
+			server.Tools = append(server.Tools, entry.Tool)
+		}
+		server.Calls = append(server.Calls, call)
+	}
+	for _, server := range fixture.Servers {
+		sort.Strings(server.Tools)
+	}
+	return fixture
+}
+
+// Find returns the first call of tool given the same arguments, else the
+// first call of tool, or nil if tool was never called
+func (s *FixtureServer) Find(tool string, arguments json.RawMessage) *FixtureCall {
+	var first *FixtureCall
+	want := canonicalJSON(arguments)
+	for i := range s.Calls {
+		call := &s.Calls[i]
+		if call.Tool != tool {
+			continue
+		}
+		if bytes.Equal(canonicalJSON(call.Arguments), want) {
+			return call
+		}
+		if first == nil {
+			first = call
+		}
+	}
+	return first
+}
+
+// canonicalJSON re-encodes data with sorted keys and no spaces, so equal
+// values compare equal. Missing and null values are both empty.
+func canonicalJSON(data json.RawMessage) []byte {
+	var value interface{}
+	if len(data) == 0 || json.Unmarshal(data, &value) != nil || value == nil {
+		return nil
+	}
+	canonical, _ := json.Marshal(value)
+	return canonical
+}
@@ -0,0 +1,52 @@
+package transcript
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFixture(t *testing.T) {
+	start := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	sessions := []Session{
+		{ID: "a", Entries: []Entry{
+			{Time: start.Add(2 * time.Minute), Server: "github", Method: "tools/call", Tool: "search", Arguments: `{"q":"b"}`, Result: `{"hits":2}`},
+			{Time: start.Add(3 * time.Minute), Server: "github", Method: "tools/list", Result: `{"tools":[]}`},
+			{Time: start.Add(4 * time.Minute), Server: "github", Method: "tools/call", Tool: "search", Result: `{"text":"abc…`},
+		}},
+		{ID: "b", Entries: []Entry{
+			{Time: start.Add(time.Minute), Server: "github", Method: "tools/call", Tool: "close_issue", Arguments: `{"number":7}`, Error: "forbidden"},
+			{Time: start.Add(time.Hour), Server: "gitlab", Method: "tools/call", Tool: "list", Result: `{}`},
+		}},
+	}
+
+	fixture := NewFixture(sessions, time.Time{}, start.Add(30*time.Minute))
+	assert.Equal(t, FixtureVersion, fixture.Version)
+	assert.Equal(t, start.Add(time.Minute), fixture.From, "open windows start at the first call")
+	assert.Equal(t, 1, fixture.Skipped, "shortened results can't be replayed")
+
+	// Calls outside the window and other methods are left out
+	require.Len(t, fixture.Servers, 1)
+	github := fixture.Servers["github"]
+	assert.Equal(t, []string{"close_issue", "search"}, github.Tools)
+	require.Len(t, github.Calls, 2)
+	assert.Equal(t, FixtureCall{Tool: "close_issue", Arguments: json.RawMessage(`{"number":7}`), Error: "forbidden"}, github.Calls[0])
+	assert.JSONEq(t, `{"hits":2}`, string(github.Calls[1].Result))
+}
+
+func TestFixtureServer_Find(t *testing.T) {
+	server := &FixtureServer{Calls: []FixtureCall{
+		{Tool: "search", Arguments: json.RawMessage(`{"q":"a","n":1}`), Result: json.RawMessage(`1`)},
+		{Tool: "search", Arguments: json.RawMessage(`{"q":"b"}`), Result: json.RawMessage(`2`)},
+		{Tool: "list", Result: json.RawMessage(`3`)},
+	}}
+
+	assert.Equal(t, json.RawMessage(`2`), server.Find("search", json.RawMessage(`{ "q": "b" }`)).Result)
+	assert.Equal(t, json.RawMessage(`1`), server.Find("search", json.RawMessage(`{"n":1,"q":"a"}`)).Result, "key order doesn't matter")
+	assert.Equal(t, json.RawMessage(`1`), server.Find("search", json.RawMessage(`{"q":"c"}`)).Result)
+	assert.Equal(t, json.RawMessage(`3`), server.Find("list", json.RawMessage(`null`)).Result)
+	assert.Nil(t, server.Find("missing", nil))
+}
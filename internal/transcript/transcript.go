@@ -0,0 +1,323 @@
+// Package transcript groups the calls proxied for each client into
+// sessions, so what an agent did during a run can be reviewed
+package transcript
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMaxSessions is how many sessions a store retains
+	DefaultMaxSessions = 100
+
+	// DefaultMaxEntries is how many calls a session retains
+	DefaultMaxEntries = 1000
+
+	// DefaultIdleTimeout starts a new session for a client idle this long
+	DefaultIdleTimeout = 30 * time.Minute
+)
+
+// Entry is a call made by a client
+type Entry struct {
+	Time       time.Time `json:"time"`
+	Server     string    `json:"server"`
+	Method     string    `json:"method"`
+	Tool       string    `json:"tool,omitempty"`
+	Arguments  string    `json:"arguments,omitempty"` // Redacted JSON params or tool arguments
+	Result     string    `json:"result,omitempty"`    // Redacted JSON result, possibly shortened
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"durationMs"`
+	Correlation
+}
+
+// Session is the calls of a client without a long pause between them
+type Session struct {
+	ID      string    `json:"id"`
+	Client  string    `json:"client"` // Identity of the client, e.g. key:1a2b3c4d
+	Started time.Time `json:"started"`
+	Updated time.Time `json:"updated"` // Time of the last call
+	Calls   int       `json:"calls"`   // Calls made, including those no longer retained
+	Errors  int       `json:"errors"`
+	Entries []Entry   `json:"entries,omitempty"`
+}
+
+// Store retains the latest sessions of the clients
+type Store struct {
+	maxSessions int
+	maxEntries  int
+	idleTimeout time.Duration
+
+	mu       sync.Mutex
+	sessions []*Session          // Oldest first
+	current  map[string]*Session // Client to its latest session
+}
+
+// NewStore creates a store with the default limits
+func NewStore() *Store {
+	return &Store{
+		maxSessions: DefaultMaxSessions,
+		maxEntries:  DefaultMaxEntries,
+		idleTimeout: DefaultIdleTimeout,
+		current:     make(map[string]*Session),
+	}
+}
+
+// Record adds a call of client to its session, starting a new one if the
+// client was idle
+func (s *Store) Record(client string, entry Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, exists := s.current[client]
+	if !exists || entry.Time.Sub(session.Updated) > s.idleTimeout {
+		session = &Session{ID: newID(), Client: client, Started: entry.Time}
+		s.current[client] = session
+		s.sessions = append(s.sessions, session)
+		if len(s.sessions) > s.maxSessions {
+			dropped := s.sessions[0]
+			s.sessions = append(s.sessions[:0], s.sessions[1:]...)
+			if s.current[dropped.Client] == dropped {
+				delete(s.current, dropped.Client)
+			}
+		}
+	}
+
+	session.Updated = entry.Time
+	session.Calls++
+	if entry.Error != "" {
+		session.Errors++
+	}
+	session.Entries = append(session.Entries, entry)
+	if len(session.Entries) > s.maxEntries {
+		session.Entries = append(session.Entries[:0], session.Entries[len(session.Entries)-s.maxEntries:]...)
+	}
+}
+
+// Sessions returns the sessions without their entries, latest call first
+func (s *Store) Sessions() []Session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sessions := make([]Session, len(s.sessions))
+	for i, session := range s.sessions {
+		sessions[i] = *session
+		sessions[i].Entries = nil
+	}
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].Updated.After(sessions[j].Updated)
+	})
+	return sessions
+}
+
+// Get returns a session with its entries
+func (s *Store) Get(id string) (*Session, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, session := range s.sessions {
+		if session.ID == id {
+			copied := *session
+			copied.Entries = append([]Entry(nil), session.Entries...)
+			return &copied, true
+		}
+	}
+	return nil, false
+}
+
+// Find returns the sessions with calls an agent gave id as request or run
+// ID, latest call first, holding only those calls
+func (s *Store) Find(id string) []Session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var sessions []Session
+	for _, session := range s.sessions {
+		found := *session
+		found.Entries, found.Calls, found.Errors = nil, 0, 0
+		for _, entry := range session.Entries {
+			if !entry.Matches(id) {
+				continue
+			}
+			found.Entries = append(found.Entries, entry)
+			found.Calls++
+			if entry.Error != "" {
+				found.Errors++
+			}
+		}
+		if found.Calls > 0 {
+			found.Updated = found.Entries[len(found.Entries)-1].Time
+			sessions = append(sessions, found)
+		}
+	}
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].Updated.After(sessions[j].Updated)
+	})
+	return sessions
+}
+
+// newID returns a random session ID
+func newID() string {
+	buf := make([]byte, 4)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// Markdown renders a session for reading, e.g. in a review
+func Markdown(session *Session) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# Session %s\n\n", session.ID)
+	fmt.Fprintf(&b, "- Client: `%s`\n", session.Client)
+	fmt.Fprintf(&b, "- Started: %s\n", session.Started.Format(time.RFC3339))
+	fmt.Fprintf(&b, "- Last call: %s\n", session.Updated.Format(time.RFC3339))
+	fmt.Fprintf(&b, "- Calls: %d (%d failed)\n", session.Calls, session.Errors)
+	if dropped := session.Calls - len(session.Entries); dropped > 0 {
+		fmt.Fprintf(&b, "- The first %d calls are no longer retained\n", dropped)
+	}
+
+	for i, entry := range session.Entries {
+		name := entry.Method
+		if entry.Tool != "" {
+			name += " " + entry.Tool
+		}
+		fmt.Fprintf(&b, "\n## %d. %s: %s\n\n", i+1, entry.Server, name)
+		fmt.Fprintf(&b, "%s, %dms", entry.Time.Format("15:04:05"), entry.DurationMs)
+		if ids := entry.Describe(); ids != "" {
+			fmt.Fprintf(&b, ", %s", ids)
+		}
+		b.WriteString("\n")
+		if entry.Arguments != "" {
+			fmt.Fprintf(&b, "\nArguments:\n\n```json\n%s\n```\n", indent(entry.Arguments))
+		}
+		if entry.Error != "" {
+			fmt.Fprintf(&b, "\nError: %s\n", entry.Error)
+		} else if entry.Result != "" {
+			fmt.Fprintf(&b, "\nResult:\n\n```json\n%s\n```\n", indent(entry.Result))
+		}
+	}
+	return b.Bytes()
+}
+
+// indent pretty-prints JSON, leaving shortened documents as they are
+func indent(data string) string {
+	var b bytes.Buffer
+	if err := json.Indent(&b, []byte(data), "", "  "); err != nil {
+		return data
+	}
+	return b.String()
+}
+
+// ClientHeader names the client of a request, e.g. an agent run. The
+// gateway sets it when forwarding, so calls keep the identity of the
+// client that made them.
+const ClientHeader = "X-MCP-Client"
+
+// ClientID identifies the client of a request by, in order, its
+// ClientHeader, API key, MCP session or connection
+func ClientID(r *http.Request) string {
+	if client := r.Header.Get(ClientHeader); client != "" {
+		return client
+	}
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && token != "" {
+		// Never keep the key itself
+		sum := sha256.Sum256([]byte(token))
+		return "key:" + hex.EncodeToString(sum[:4])
+	}
+	if session := r.Header.Get("Mcp-Session-Id"); session != "" {
+		return "session:" + session
+	}
+	return "conn:" + r.RemoteAddr
+}
+
+// Headers carrying the IDs agents give their calls and runs, so a call can
+// be tied back to the run that made it
+const (
+	RequestIDHeader = "X-Request-ID"
+	RunIDHeader     = "X-Agent-Run-ID"
+)
+
+// maxIDLength bounds the IDs kept from RequestIDHeader and RunIDHeader
+const maxIDLength = 128
+
+// Correlation is the IDs an agent gave a call, empty if it gave none
+type Correlation struct {
+	RequestID string `json:"requestId,omitempty"`
+	RunID     string `json:"runId,omitempty"`
+}
+
+// CorrelationOf returns the IDs of the RequestIDHeader and RunIDHeader of a
+// request
+func CorrelationOf(r *http.Request) Correlation {
+	return Correlation{
+		RequestID: cleanID(r.Header.Get(RequestIDHeader)),
+		RunID:     cleanID(r.Header.Get(RunIDHeader)),
+	}
+}
+
+// cleanID shortens an ID to maxIDLength and drops its control characters,
+// so it can't forge log lines
+func cleanID(id string) string {
+	id = strings.Map(func(r rune) rune {
+		if r < ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(id))
+	if len(id) > maxIDLength {
+		id = strings.ToValidUTF8(id[:maxIDLength], "")
+	}
+	return id
+}
+
+// SetHeaders sets the headers of the IDs on h, so forwarded calls keep them
+func (c Correlation) SetHeaders(h http.Header) {
+	if c.RequestID != "" {
+		h.Set(RequestIDHeader, c.RequestID)
+	}
+	if c.RunID != "" {
+		h.Set(RunIDHeader, c.RunID)
+	}
+}
+
+// Matches returns true if id is the request or run ID
+func (c Correlation) Matches(id string) bool {
+	return id != "" && (c.RequestID == id || c.RunID == id)
+}
+
+// Describe describes the IDs for logs, e.g. "request=abc run=r1", or
+// returns "" if there are none
+func (c Correlation) Describe() string {
+	var ids []string
+	if c.RequestID != "" {
+		ids = append(ids, "request="+c.RequestID)
+	}
+	if c.RunID != "" {
+		ids = append(ids, "run="+c.RunID)
+	}
+	return strings.Join(ids, " ")
+}
+
+// correlationKey is the context key of the IDs of a call
+type correlationKey struct{}
+
+// WithCorrelation returns a copy of ctx carrying the IDs of a call
+func WithCorrelation(ctx context.Context, c Correlation) context.Context {
+	return context.WithValue(ctx, correlationKey{}, c)
+}
+
+// CorrelationFrom returns the IDs of the call ctx belongs to, empty if it
+// has none
+func CorrelationFrom(ctx context.Context) Correlation {
+	c, _ := ctx.Value(correlationKey{}).(Correlation)
+	return c
+}
@@ -0,0 +1,159 @@
+package transcript
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_Record(t *testing.T) {
+	s := NewStore()
+	start := time.Now()
+	call := func(client string, after time.Duration, err string) {
+		s.Record(client, Entry{Time: start.Add(after), Server: "github", Method: "tools/call", Tool: "search", Error: err})
+	}
+
+	call("key:a", 0, "")
+	call("key:b", time.Second, "")
+	call("key:a", 2*time.Second, "rate limited")
+
+	sessions := s.Sessions()
+	require.Len(t, sessions, 2)
+	assert.Equal(t, "key:a", sessions[0].Client, "latest call first")
+	assert.Equal(t, 2, sessions[0].Calls)
+	assert.Equal(t, 1, sessions[0].Errors)
+	assert.Empty(t, sessions[0].Entries)
+
+	session, exists := s.Get(sessions[0].ID)
+	require.True(t, exists)
+	require.Len(t, session.Entries, 2)
+	assert.Equal(t, "rate limited", session.Entries[1].Error)
+
+	// A client idle too long starts a new session
+	call("key:a", 2*time.Second+DefaultIdleTimeout+time.Second, "")
+	assert.Len(t, s.Sessions(), 3)
+
+	_, exists = s.Get("missing")
+	assert.False(t, exists)
+}
+
+func TestStore_Limits(t *testing.T) {
+	s := NewStore()
+	s.maxSessions, s.maxEntries = 2, 3
+	now := time.Now()
+
+	for i := 0; i < 5; i++ {
+		s.Record("key:a", Entry{Time: now, Method: "tools/call", Tool: fmt.Sprint(i)})
+	}
+	session, _ := s.Get(s.Sessions()[0].ID)
+	assert.Equal(t, 5, session.Calls)
+	require.Len(t, session.Entries, 3, "oldest calls are dropped")
+	assert.Equal(t, "2", session.Entries[0].Tool)
+
+	s.Record("key:b", Entry{Time: now})
+	s.Record("key:c", Entry{Time: now})
+	sessions := s.Sessions()
+	require.Len(t, sessions, 2, "oldest sessions are dropped")
+	for _, session := range sessions {
+		assert.NotEqual(t, "key:a", session.Client)
+	}
+
+	// The dropped client starts over
+	s.Record("key:a", Entry{Time: now})
+	session, _ = s.Get(s.Sessions()[0].ID)
+	assert.Equal(t, 1, session.Calls)
+}
+
+func TestStore_Find(t *testing.T) {
+	s := NewStore()
+	now := time.Now()
+	s.Record("key:a", Entry{Time: now, Tool: "search", Correlation: Correlation{RequestID: "r1", RunID: "run"}})
+	s.Record("key:a", Entry{Time: now, Tool: "other", Error: "boom", Correlation: Correlation{RequestID: "r2", RunID: "run"}})
+	s.Record("key:b", Entry{Time: now.Add(time.Second), Tool: "search", Correlation: Correlation{RequestID: "r3", RunID: "run"}})
+
+	// Only the matching calls are kept
+	sessions := s.Find("r2")
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "key:a", sessions[0].Client)
+	require.Len(t, sessions[0].Entries, 1)
+	assert.Equal(t, "other", sessions[0].Entries[0].Tool)
+	assert.Equal(t, 1, sessions[0].Calls)
+	assert.Equal(t, 1, sessions[0].Errors)
+
+	// A run spans sessions, latest first
+	sessions = s.Find("run")
+	require.Len(t, sessions, 2)
+	assert.Equal(t, "key:b", sessions[0].Client)
+	assert.Len(t, sessions[1].Entries, 2)
+
+	assert.Empty(t, s.Find("missing"))
+	assert.Empty(t, s.Find(""))
+}
+
+func TestMarkdown(t *testing.T) {
+	started := time.Date(2026, 10, 17, 9, 30, 0, 0, time.UTC)
+	session := &Session{ID: "ab12", Client: "key:1a2b3c4d", Started: started, Updated: started, Calls: 3, Errors: 1, Entries: []Entry{
+		{Time: started, Server: "github", Method: "tools/call", Tool: "create_issue", Arguments: `{"title":"bug"}`, Result: `{"number":7}`, DurationMs: 40},
+		{Time: started, Server: "github", Method: "tools/call", Tool: "close_issue", Arguments: `{"number":7}`, Error: "forbidden", Correlation: Correlation{RequestID: "r1"}},
+	}}
+
+	md := string(Markdown(session))
+	assert.Contains(t, md, "# Session ab12")
+	assert.Contains(t, md, "- Client: `key:1a2b3c4d`")
+	assert.Contains(t, md, "- Calls: 3 (1 failed)")
+	assert.Contains(t, md, "- The first 1 calls are no longer retained")
+	assert.Contains(t, md, "## 1. github: tools/call create_issue")
+	assert.Contains(t, md, "```json\n{\n  \"title\": \"bug\"\n}\n```")
+	assert.Contains(t, md, "Error: forbidden")
+	assert.Contains(t, md, "request=r1")
+
+	// Shortened results are kept as they are
+	session.Entries[0].Result = `{"text":"abc…`
+	assert.Contains(t, string(Markdown(session)), "```json\n{\"text\":\"abc…\n```")
+}
+
+func TestClientID(t *testing.T) {
+	r := httptest.NewRequest("POST", "/", nil)
+	r.RemoteAddr = "127.0.0.1:5000"
+	assert.Equal(t, "conn:127.0.0.1:5000", ClientID(r))
+
+	r.Header.Set("Mcp-Session-Id", "s1")
+	assert.Equal(t, "session:s1", ClientID(r))
+
+	r.Header.Set("Authorization", "Bearer secret-key")
+	id := ClientID(r)
+	assert.Regexp(t, `^key:[0-9a-f]{8}$`, id)
+	assert.NotContains(t, id, "secret")
+
+	r.Header.Set(ClientHeader, "agent-run-42")
+	assert.Equal(t, "agent-run-42", ClientID(r))
+}
+
+func TestCorrelationOf(t *testing.T) {
+	r := httptest.NewRequest("POST", "/", nil)
+	assert.Equal(t, Correlation{}, CorrelationOf(r))
+	assert.Empty(t, CorrelationOf(r).Describe())
+
+	r.Header.Set(RequestIDHeader, " abc\n[forged] ")
+	r.Header.Set(RunIDHeader, strings.Repeat("x", 200))
+	c := CorrelationOf(r)
+	assert.Equal(t, "abc[forged]", c.RequestID, "control characters are dropped")
+	assert.Len(t, c.RunID, maxIDLength)
+	assert.True(t, c.Matches("abc[forged]"))
+	assert.False(t, c.Matches(""))
+
+	h := http.Header{}
+	c.SetHeaders(h)
+	assert.Equal(t, "abc[forged]", h.Get(RequestIDHeader))
+
+	ctx := WithCorrelation(context.Background(), c)
+	assert.Equal(t, c, CorrelationFrom(ctx))
+	assert.Equal(t, Correlation{}, CorrelationFrom(context.Background()))
+}
@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tartavull/mcp-manager/internal/api"
+	"github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/i18n"
+)
+
+// maxApprovalArguments is the length arguments are shortened to in the
+// approval queue
+const maxApprovalArguments = 80
+
+var approvalStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("#F9E2AF")).
+	Border(lipgloss.RoundedBorder()).
+	BorderForeground(lipgloss.Color("#F9E2AF")).
+	Padding(0, 1)
+
+// approvalsMsg carries the tool calls waiting for approval
+type approvalsMsg struct {
+	approvals []grpc.Approval
+	err       error
+}
+
+// fetchApprovals returns the command asking the manager for the tool calls
+// waiting for approval, in the background so a slow daemon doesn't stall
+// the UI, or nil if the manager doesn't park calls
+func (m Model) fetchApprovals() tea.Cmd {
+	approvals, ok := m.manager.(api.Approvals)
+	if !ok {
+		return nil
+	}
+	return func() tea.Msg {
+		pending, err := approvals.PendingApprovals()
+		return approvalsMsg{approvals: pending, err: err}
+	}
+}
+
+// decideApproval approves or rejects the oldest call waiting for approval
+func (m Model) decideApproval(approved bool) (tea.Model, tea.Cmd) {
+	approvals, ok := m.manager.(api.Approvals)
+	if !ok || len(m.approvals) == 0 {
+		return m, nil
+	}
+
+	oldest := m.approvals[0]
+	if err := approvals.DecideApproval(oldest.ID, approved, ""); err != nil {
+		log.Printf("Failed to decide approval %s: %v", oldest.ID, err)
+	}
+	return m, m.fetchApprovals()
+}
+
+// approvalQueue renders the oldest call waiting for approval, or "" when
+// there are none
+func (m Model) approvalQueue() string {
+	if len(m.approvals) == 0 {
+		return ""
+	}
+
+	oldest := m.approvals[0]
+	arguments := oldest.Arguments
+	if len(arguments) > maxApprovalArguments {
+		arguments = arguments[:maxApprovalArguments-3] + "..."
+	}
+
+	var b strings.Builder
+	b.WriteString(i18n.T("⏸ %d call(s) waiting for approval", len(m.approvals)) + "\n")
+	fmt.Fprintf(&b, "%s: %s %s\n", oldest.Server, oldest.Tool, arguments)
+	b.WriteString(i18n.T("Expires in %s", oldest.Deadline.Sub(m.snapshot.Now).Round(time.Second)) + " • " + i18n.T("A Approve") + " • " + i18n.T("X Reject"))
+	return approvalStyle.Render(b.String())
+}
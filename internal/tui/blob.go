@@ -0,0 +1,169 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tartavull/mcp-manager/internal/auth"
+	"github.com/tartavull/mcp-manager/internal/preview"
+	"github.com/tartavull/mcp-manager/internal/proxy"
+)
+
+// blobClient fetches blobs from the server proxies
+var blobClient = &http.Client{Timeout: 10 * time.Second}
+
+// blobOpenedMsg reports the result of opening a blob in the system viewer
+type blobOpenedMsg struct {
+	path string
+	err  error
+}
+
+// openLatestBlobCmd downloads the newest blob returned by the server on
+// port and opens it in the system viewer
+func openLatestBlobCmd(port int) tea.Cmd {
+	return func() tea.Msg {
+		path, err := downloadLatestBlob(fmt.Sprintf("http://localhost:%d", port))
+		if err == nil {
+			err = openFile(path)
+		}
+		return blobOpenedMsg{path: path, err: err}
+	}
+}
+
+// fetchLatestBlob downloads the newest blob of a proxy matching the MIME
+// type prefix
+func fetchLatestBlob(baseURL, mimePrefix string) (proxy.BlobInfo, []byte, error) {
+	resp, err := getBlob(baseURL + "/resources/blobs")
+	if err != nil {
+		return proxy.BlobInfo{}, nil, fmt.Errorf("failed to list blobs: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return proxy.BlobInfo{}, nil, fmt.Errorf("failed to list blobs: %s", resp.Status)
+	}
+
+	var list struct {
+		Blobs []proxy.BlobInfo `json:"blobs"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return proxy.BlobInfo{}, nil, fmt.Errorf("failed to parse blob list: %w", err)
+	}
+
+	for _, info := range list.Blobs {
+		if !strings.HasPrefix(info.MimeType, mimePrefix) {
+			continue
+		}
+
+		resp, err := getBlob(baseURL + "/resources/blob/" + info.ID)
+		if err != nil {
+			return info, nil, fmt.Errorf("failed to download blob: %w", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return info, nil, fmt.Errorf("failed to download blob: %s", resp.Status)
+		}
+
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return info, nil, fmt.Errorf("failed to download blob: %w", err)
+		}
+		return info, data, nil
+	}
+
+	if mimePrefix == "image/" {
+		return proxy.BlobInfo{}, nil, fmt.Errorf("no images returned by this server yet")
+	}
+	return proxy.BlobInfo{}, nil, fmt.Errorf("no blobs returned by this server yet")
+}
+
+// getBlob requests a blob endpoint of a proxy, with the client token if
+// proxies require authentication
+func getBlob(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token := auth.ClientToken(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return blobClient.Do(req)
+}
+
+// downloadLatestBlob saves the newest blob of a proxy to a temporary file
+// named after its content type, and returns the file path
+func downloadLatestBlob(baseURL string) (string, error) {
+	info, data, err := fetchLatestBlob(baseURL, "")
+	if err != nil {
+		return "", err
+	}
+
+	ext := ".bin"
+	if exts, _ := mime.ExtensionsByType(info.MimeType); len(exts) > 0 {
+		ext = exts[0]
+	}
+
+	f, err := os.CreateTemp("", "mcp-blob-*"+ext)
+	if err != nil {
+		return "", fmt.Errorf("failed to create blob file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(data); err != nil {
+		return "", fmt.Errorf("failed to write blob file: %w", err)
+	}
+	return f.Name(), nil
+}
+
+// imagePreviewMsg carries the rendered preview of a server's latest image
+type imagePreviewMsg struct {
+	server   string
+	rendered string
+	err      error
+}
+
+// previewLatestImageCmd renders the newest image returned by the server on
+// port to fit in cols x rows cells
+func previewLatestImageCmd(name string, port, cols, rows int) tea.Cmd {
+	return func() tea.Msg {
+		_, data, err := fetchLatestBlob(fmt.Sprintf("http://localhost:%d", port), "image/")
+		if err != nil {
+			return imagePreviewMsg{server: name, err: err}
+		}
+
+		protocol := preview.Detect()
+		rendered, err := preview.Render(data, protocol, cols, rows)
+		if err == nil && protocol != preview.ProtocolASCII {
+			// Graphics are a single line of text; reserve the rows they cover
+			rendered += strings.Repeat("\n", rows-1)
+		}
+		return imagePreviewMsg{server: name, rendered: rendered, err: err}
+	}
+}
+
+// openFile opens a file with the system's default application
+func openFile(path string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", path)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", path)
+	default:
+		cmd = exec.Command("xdg-open", path)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	go cmd.Wait()
+	return nil
+}
@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/internal/proxy"
+)
+
+func TestDownloadLatestBlob(t *testing.T) {
+	var blobs []proxy.BlobInfo
+	mux := http.NewServeMux()
+	mux.HandleFunc("/resources/blobs", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"blobs": blobs})
+	})
+	mux.HandleFunc("/resources/blob/abc", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("\x89PNG"))
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	_, err := downloadLatestBlob(ts.URL)
+	assert.Error(t, err, "no blobs yet")
+
+	blobs = []proxy.BlobInfo{{ID: "abc", MimeType: "image/png"}}
+	path, err := downloadLatestBlob(ts.URL)
+	require.NoError(t, err)
+	defer os.Remove(path)
+
+	assert.Equal(t, ".png", filepath.Ext(path))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("\x89PNG"), data)
+}
+
+func TestFetchLatestBlob_MimeFilter(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/resources/blobs", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"blobs": []proxy.BlobInfo{
+			{ID: "pdf", MimeType: "application/pdf"},
+			{ID: "png", MimeType: "image/png"},
+		}})
+	})
+	mux.HandleFunc("/resources/blob/png", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("image"))
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	info, data, err := fetchLatestBlob(ts.URL, "image/")
+	require.NoError(t, err)
+	assert.Equal(t, "png", info.ID)
+	assert.Equal(t, []byte("image"), data)
+
+	_, _, err = fetchLatestBlob(ts.URL, "audio/")
+	assert.Error(t, err)
+}
+
+func TestModel_Update_ImagePreview(t *testing.T) {
+	model := New(createTestManager(t))
+	model.viewState = ViewDetail
+	model.selectedServer = "test1"
+
+	// Previews of other servers are ignored
+	updated, _ := model.Update(imagePreviewMsg{server: "test2", rendered: "@@"})
+	assert.Empty(t, updated.(Model).imagePreview)
+
+	updated, _ = model.Update(imagePreviewMsg{server: "test1", rendered: "@@"})
+	assert.Equal(t, "@@", updated.(Model).imagePreview)
+
+	// Pressing p again hides the preview
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	assert.Empty(t, updated.(Model).imagePreview)
+}
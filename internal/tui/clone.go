@@ -0,0 +1,30 @@
+package tui
+
+import (
+	"log"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tartavull/mcp-manager/internal/api"
+)
+
+// duplicateServer copies the server under the cursor, or the template of
+// the instance under it, on the next free port, then opens mcp.json to
+// tweak the copy's arguments
+func (m Model) duplicateServer() (tea.Model, tea.Cmd) {
+	cloner, ok := m.manager.(api.Cloner)
+	row, _, selected := m.selectedRow()
+	if !ok || !selected {
+		return m, nil
+	}
+	if srv, exists := m.snapshot.Servers[row]; exists && srv.Group != "" {
+		row = srv.Group
+	}
+
+	name, port, err := cloner.CloneServer(row, "", nil)
+	if err != nil {
+		log.Printf("Failed to duplicate %s: %v", row, err)
+		return m, nil
+	}
+	log.Printf("Duplicated %s as %s on port %d", row, name, port)
+	return m, m.openConfig()
+}
@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tartavull/mcp-manager/internal/api"
+	"github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/i18n"
+)
+
+// diskMsg carries the disk used by a server
+type diskMsg struct {
+	server string
+	disk   *grpc.ServerDisk // Nil if not tracked
+}
+
+// fetchDisk returns the command asking the manager for the disk used by
+// the selected server, in the background as its directories are walked,
+// or nil if the manager doesn't track disk usage
+func (m Model) fetchDisk() tea.Cmd {
+	usage, ok := m.manager.(api.DiskUsage)
+	if !ok {
+		return nil
+	}
+	name := m.selectedServer
+	return func() tea.Msg {
+		msg := diskMsg{server: name}
+		if disks, err := usage.DiskUsage([]string{name}); err == nil && len(disks) == 1 {
+			msg.disk = &disks[0]
+		}
+		return msg
+	}
+}
+
+// diskLine describes the disk used by the selected server, or "" if it
+// isn't known
+func (m Model) diskLine() string {
+	if m.disk == nil || m.disk.Server != m.selectedServer {
+		return ""
+	}
+	return i18n.T("Disk: %s (cache %s, data %s)",
+		formatSize(m.disk.Total("")),
+		formatSize(m.disk.Total(grpc.DiskCache)),
+		formatSize(m.disk.Total(grpc.DiskData)),
+	) + "\n"
+}
+
+// formatSize returns a size in bytes in human-readable units
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,120 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Intervals between ticks, longer when nothing is changing or nobody is
+// looking
+const (
+	busyTick    = time.Second      // Servers are starting or stopping
+	idleTick    = 2 * time.Second  // Nothing is in progress
+	blurredTick = 10 * time.Second // The terminal is unfocused
+)
+
+// Sequences turning the terminal's focus events on and off
+const (
+	enableFocusReport  = "\x1b[?1004h"
+	disableFocusReport = "\x1b[?1004l"
+)
+
+// resumedMsg is sent when the TUI is continued after being suspended
+type resumedMsg struct{}
+
+// ReportFocus makes the terminal writing w send focus events, which slow
+// the ticks of a model WithFocusEvents down while it is unfocused. The
+// returned function turns them off.
+func ReportFocus(w io.Writer) func() {
+	io.WriteString(w, enableFocusReport)
+	return func() {
+		io.WriteString(w, disableFocusReport)
+	}
+}
+
+// WithFocusEvents returns the model expecting focus events from the
+// terminal, turned on with ReportFocus
+func (m Model) WithFocusEvents() Model {
+	m.focusEvents = true
+	return m
+}
+
+// focusEvent returns whether msg reports that the terminal gained or lost
+// focus. bubbletea passes the events, CSI I and CSI O, on as unknown
+// sequences.
+func focusEvent(msg tea.Msg) (focused, ok bool) {
+	if _, isKey := msg.(tea.KeyMsg); isKey {
+		return false, false
+	}
+	s, ok := msg.(fmt.Stringer)
+	if !ok {
+		return false, false
+	}
+	switch s.String() {
+	case "?CSI[73]?":
+		return true, true
+	case "?CSI[79]?":
+		return false, true
+	}
+	return false, false
+}
+
+// tickInterval returns how long until the next tick: fast while servers
+// are starting or stopping or the daemon is being reconnected to, slower
+// when idle and slower still when the terminal is unfocused
+func (m Model) tickInterval() time.Duration {
+	switch {
+	case hasOperationsInProgress(m.snapshot.Servers), m.snapshot.Lost():
+		return busyTick
+	case m.blurred:
+		return blurredTick
+	default:
+		return idleTick
+	}
+}
+
+// stopJob stops the TUI's job like ctrl+z in a shell, returning when it is
+// continued. Focus events are turned off meanwhile, as the shell doesn't
+// expect them.
+type stopJob struct {
+	focusEvents bool
+	out         io.Writer
+}
+
+func (j *stopJob) Run() error {
+	continued := make(chan os.Signal, 1)
+	signal.Notify(continued, syscall.SIGCONT)
+	defer signal.Stop(continued)
+
+	if j.focusEvents {
+		io.WriteString(j.out, disableFocusReport)
+		defer io.WriteString(j.out, enableFocusReport)
+	}
+	if err := syscall.Kill(0, syscall.SIGTSTP); err != nil {
+		return err
+	}
+	<-continued
+	return nil
+}
+
+func (j *stopJob) SetStdin(io.Reader)    {}
+func (j *stopJob) SetStdout(w io.Writer) { j.out = w }
+func (j *stopJob) SetStderr(io.Writer)   {}
+
+// suspend hands the terminal back to the shell and stops the process,
+// polling included, until it is continued with fg
+func (m Model) suspend() (tea.Model, tea.Cmd) {
+	return m, tea.Exec(&stopJob{focusEvents: m.focusEvents}, func(err error) tea.Msg {
+		if err != nil {
+			log.Printf("Failed to suspend: %v", err)
+		}
+		return resumedMsg{}
+	})
+}
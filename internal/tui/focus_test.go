@@ -0,0 +1,75 @@
+package tui
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// focusRecorder records the focus events it gets, quitting after two
+type focusRecorder struct {
+	events []bool
+}
+
+func (r focusRecorder) Init() tea.Cmd { return nil }
+func (r focusRecorder) View() string  { return "" }
+
+func (r focusRecorder) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if focused, ok := focusEvent(msg); ok {
+		r.events = append(r.events, focused)
+		if len(r.events) == 2 {
+			return r, tea.Quit
+		}
+	}
+	return r, nil
+}
+
+func TestFocusEvent(t *testing.T) {
+	// The terminal sends CSI O on losing focus and CSI I on gaining it
+	p := tea.NewProgram(focusRecorder{},
+		tea.WithInput(strings.NewReader("\x1b[O\x1b[I")), tea.WithOutput(io.Discard))
+	timer := time.AfterFunc(5*time.Second, p.Kill)
+	defer timer.Stop()
+
+	final, err := p.Run()
+	require.NoError(t, err)
+	assert.Equal(t, []bool{false, true}, final.(focusRecorder).events)
+
+	_, ok := focusEvent(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?CSI[73]?")})
+	assert.False(t, ok)
+}
+
+// sequence is a message printed like the unknown sequences of bubbletea
+type sequence string
+
+func (s sequence) String() string { return string(s) }
+
+func TestModel_TickInterval(t *testing.T) {
+	mgr := createTestManager(t)
+	model := New(mgr)
+	assert.Equal(t, idleTick, model.tickInterval())
+
+	updated, cmd := model.Update(sequence("?CSI[79]?"))
+	assert.Nil(t, cmd)
+	assert.Equal(t, blurredTick, updated.(Model).tickInterval())
+
+	// Servers starting or stopping are followed closely, focused or not
+	srv, _ := mgr.GetServer("test2")
+	srv.SetStatus(server.StatusStarting)
+	updated, _ = updated.Update(refreshMsg{})
+	assert.Equal(t, busyTick, updated.(Model).tickInterval())
+	srv.SetStatus(server.StatusStopped)
+
+	// Regaining focus catches up at once
+	updated, cmd = updated.Update(sequence("?CSI[73]?"))
+	require.NotNil(t, cmd)
+	assert.IsType(t, refreshMsg{}, cmd())
+	updated, _ = updated.Update(refreshMsg{})
+	assert.Equal(t, idleTick, updated.(Model).tickInterval())
+}
@@ -0,0 +1,200 @@
+package tui
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// setServers updates the servers and the rows of the list, where the
+// instances of a template are grouped under a row of their own. Only the
+// servers with the tag filtered by are listed.
+func (m *Model) setServers(servers map[string]*server.Server, order []string) {
+	m.servers = getOrderedServerNames(servers, order)
+	m.rows, m.groups = groupRows(servers, filterTag(servers, m.servers, m.tagFilter), m.expanded)
+
+	// Ensure cursor is within bounds
+	if m.cursor >= len(m.rows) {
+		m.cursor = len(m.rows) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+}
+
+// groupRows returns the rows of the list for servers in order: servers
+// that aren't instances, and a row for each group of instances followed by
+// the instances if it is expanded. Groups take the place of their first
+// instance.
+func groupRows(servers map[string]*server.Server, names []string, expanded map[string]bool) ([]string, map[string][]string) {
+	groups := make(map[string][]string)
+	for _, name := range names {
+		if group := servers[name].Group; group != "" {
+			groups[group] = append(groups[group], name)
+		}
+	}
+
+	rows := make([]string, 0, len(names))
+	for _, name := range names {
+		group := servers[name].Group
+		switch {
+		case group == "":
+			rows = append(rows, name)
+		case groups[group][0] == name:
+			rows = append(rows, group)
+			if expanded[group] {
+				rows = append(rows, groups[group]...)
+			}
+		}
+	}
+	return rows, groups
+}
+
+// selectedRow returns the row under the cursor, and the instances in it if
+// it's a group
+func (m Model) selectedRow() (string, []string, bool) {
+	if m.cursor >= len(m.rows) {
+		return "", nil, false
+	}
+	row := m.rows[m.cursor]
+	return row, m.groups[row], true
+}
+
+// setExpanded expands or collapses the group under the cursor, or the
+// group of the instance under it, keeping the cursor on the group
+func (m Model) setExpanded(expand bool) Model {
+	row, instances, ok := m.selectedRow()
+	if !ok {
+		return m
+	}
+	group := row
+	if instances == nil {
+		srv, exists := m.snapshot.Servers[row]
+		if !exists || srv.Group == "" {
+			return m
+		}
+		group = srv.Group
+	}
+
+	if m.expanded == nil {
+		m.expanded = make(map[string]bool)
+	}
+	m.expanded[group] = expand
+	m.setServers(m.snapshot.Servers, m.snapshot.Order)
+	for i, r := range m.rows {
+		if r == group {
+			m.cursor = i
+		}
+	}
+	return m
+}
+
+// toggleGroup stops the running instances of a group, or starts them all
+// if none is running
+func (m Model) toggleGroup(instances []string) (tea.Model, tea.Cmd) {
+	servers := m.snapshot.Servers
+	stop := groupRunning(servers, instances) > 0
+
+	m.refreshing = true
+	go func() {
+		for _, name := range instances {
+			srv, exists := servers[name]
+			switch {
+			case !exists:
+			case stop && srv.IsRunning():
+				m.manager.StopServer(name)
+			case !stop && !srv.IsRunning():
+				m.manager.StartServer(name)
+			}
+		}
+	}()
+	return m, tea.Batch(
+		tea.Tick(100*time.Millisecond, func(t time.Time) tea.Msg {
+			return refreshMsg{}
+		}),
+		tea.Tick(500*time.Millisecond, func(t time.Time) tea.Msg {
+			return refreshMsg{}
+		}),
+	)
+}
+
+// toggleGroupMaintenance puts all instances of a group in maintenance, or
+// takes them out if they all are
+func (m Model) toggleGroupMaintenance(instances []string) {
+	servers := m.snapshot.Servers
+	enabled := false
+	for _, name := range instances {
+		if srv, exists := servers[name]; exists && !srv.Maintenance {
+			enabled = true
+		}
+	}
+	for _, name := range instances {
+		if err := m.manager.SetMaintenance(name, enabled); err != nil {
+			log.Printf("Failed to toggle maintenance for %s: %v", name, err)
+		}
+	}
+}
+
+// groupRunning counts the running instances of a group
+func groupRunning(servers map[string]*server.Server, instances []string) int {
+	running := 0
+	for _, name := range instances {
+		if srv, exists := servers[name]; exists && srv.IsRunning() {
+			running++
+		}
+	}
+	return running
+}
+
+// groupRow formats the row of a group of instances: its name marked
+// expanded or collapsed, the port of its first instance, how many of its
+// instances run and their tools
+func groupRow(group string, instances []string, servers map[string]*server.Server, expanded bool) (string, server.Status) {
+	marker := "▸ "
+	if expanded {
+		marker = "▾ "
+	}
+	displayName := fmt.Sprintf("%s%s (%d)", marker, group, len(instances))
+	if runes := []rune(displayName); len(runes) > 19 {
+		displayName = string(runes[:17]) + ".."
+	}
+
+	first := servers[instances[0]]
+	running := groupRunning(servers, instances)
+	status := server.StatusStopped
+	switch {
+	case running == len(instances):
+		status = server.StatusRunning
+	case running > 0:
+		status = server.StatusStarting
+	}
+	statusText := string(status)
+	if status == server.StatusStarting {
+		statusText = fmt.Sprintf("%d/%d up", running, len(instances))
+	}
+
+	tools := 0
+	for _, name := range instances {
+		if srv, exists := servers[name]; exists && srv.IsRunning() {
+			tools += srv.ToolCount
+		}
+	}
+	toolCount := "-"
+	if tools > 0 {
+		toolCount = strconv.Itoa(tools)
+	}
+
+	row := fmt.Sprintf("%-20s %-6d %-10s %-8s %-8s %s",
+		displayName,
+		first.Port,
+		statusText,
+		toolCount,
+		"-",
+		withTags(first.Description, first.Tags),
+	)
+	return row, status
+}
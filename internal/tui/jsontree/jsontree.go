@@ -0,0 +1,481 @@
+package jsontree
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tartavull/mcp-manager/internal/i18n"
+)
+
+// Styles for the tree
+var (
+	cursorStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("#FAFAFA")).
+			Background(lipgloss.Color("#F25D94"))
+
+	keyStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#89B4FA"))
+
+	stringStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#A6E3A1"))
+
+	scalarStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FAB387"))
+
+	summaryStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#6C7086"))
+
+	matchStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#1E1E2E")).
+			Background(lipgloss.Color("#F9E2AF"))
+)
+
+// identifierPattern matches object keys that need no quoting in paths
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// node is a value in the tree
+type node struct {
+	key      string // Object key or array index of the value
+	path     string // JSONPath of the value, e.g. $.tools[0].name
+	value    interface{}
+	children []*node // Nil for scalars
+	parent   *node
+	depth    int
+	expanded bool
+}
+
+// Model is a collapsible tree view of a JSON value, driven by the keyboard
+type Model struct {
+	root    *node
+	visible []*node // Nodes under expanded parents, in display order
+	cursor  int
+	offset  int // First visible line
+
+	searching bool   // Typing a search query
+	query     string // Last search query
+	status    string // Result of the last action
+
+	Width  int
+	Height int
+
+	// Clipboard copies text; defaults to the system clipboard
+	Clipboard func(text string) error
+}
+
+// New creates a tree of v, which may be any JSON encodable value or raw
+// JSON. Only the root starts expanded.
+func New(v interface{}) Model {
+	var data []byte
+	switch raw := v.(type) {
+	case json.RawMessage:
+		data = raw
+	case []byte:
+		data = raw
+	default:
+		data, _ = json.Marshal(v)
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		value = string(data)
+	}
+
+	root := build("", "$", value, nil, 0)
+	root.expanded = true
+
+	m := Model{root: root, Width: 80, Height: 20, Clipboard: copyToClipboard}
+	m.refresh()
+	return m
+}
+
+// build creates the node of a value and its descendants
+func build(key, path string, value interface{}, parent *node, depth int) *node {
+	n := &node{key: key, path: path, value: value, parent: parent, depth: depth}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		n.children = []*node{}
+		for _, k := range keys {
+			n.children = append(n.children, build(k, childPath(path, k), v[k], n, depth+1))
+		}
+	case []interface{}:
+		n.children = []*node{}
+		for i, item := range v {
+			n.children = append(n.children, build(strconv.Itoa(i), fmt.Sprintf("%s[%d]", path, i), item, n, depth+1))
+		}
+	}
+
+	return n
+}
+
+// childPath returns the JSONPath of an object member
+func childPath(path, key string) string {
+	if identifierPattern.MatchString(key) {
+		return path + "." + key
+	}
+	return fmt.Sprintf("%s['%s']", path, strings.ReplaceAll(key, "'", `\'`))
+}
+
+// Searching returns true while a search query is being typed, when keys
+// such as esc and q belong to the tree
+func (m Model) Searching() bool {
+	return m.searching
+}
+
+// Path returns the JSONPath of the node under the cursor
+func (m Model) Path() string {
+	if n := m.current(); n != nil {
+		return n.path
+	}
+	return ""
+}
+
+// Update handles a key press
+func (m Model) Update(msg tea.KeyMsg) (Model, tea.Cmd) {
+	if m.searching {
+		return m.updateSearch(msg), nil
+	}
+
+	n := m.current()
+	switch msg.String() {
+	case "up", "k":
+		m.move(-1)
+	case "down", "j":
+		m.move(1)
+	case "pgup":
+		m.move(-m.pageSize())
+	case "pgdown":
+		m.move(m.pageSize())
+	case "g", "home":
+		m.move(-len(m.visible))
+	case "G", "end":
+		m.move(len(m.visible))
+
+	case "enter", " ":
+		if n != nil && n.children != nil {
+			n.expanded = !n.expanded
+			m.refresh()
+		}
+	case "right", "l":
+		if n != nil && n.children != nil {
+			n.expanded = true
+			m.refresh()
+		}
+	case "left", "h":
+		if n != nil && n.children != nil && n.expanded {
+			n.expanded = false
+			m.refresh()
+		} else if n != nil && n.parent != nil {
+			m.moveTo(n.parent)
+		}
+	case "E":
+		setExpanded(m.root, true)
+		m.refresh()
+	case "C":
+		setExpanded(m.root, false)
+		m.root.expanded = true
+		m.refresh()
+		m.moveTo(m.root)
+
+	case "/":
+		m.searching = true
+		m.query = ""
+		m.status = ""
+	case "n":
+		m.findNext(1)
+	case "N":
+		m.findNext(-1)
+
+	case "y":
+		if n != nil {
+			data, _ := json.MarshalIndent(n.value, "", "  ")
+			m.copy(string(data), "Copied value", "Failed to copy value: %v")
+		}
+	case "Y":
+		if n != nil {
+			m.copy(n.path, "Copied path", "Failed to copy path: %v")
+		}
+	}
+
+	return m, nil
+}
+
+// updateSearch handles keys while typing a search query
+func (m Model) updateSearch(msg tea.KeyMsg) Model {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.searching = false
+		m.query = ""
+	case tea.KeyEnter:
+		m.searching = false
+		m.findNext(0)
+	case tea.KeyBackspace:
+		_, size := utf8.DecodeLastRuneInString(m.query)
+		m.query = m.query[:len(m.query)-size]
+	case tea.KeyRunes, tea.KeySpace:
+		m.query += string(msg.Runes)
+	}
+	return m
+}
+
+// findNext moves to the next node whose key or scalar value contains the
+// query, searching collapsed nodes too. A step of 0 includes the current node.
+func (m *Model) findNext(step int) {
+	if m.query == "" {
+		return
+	}
+
+	all := flatten(m.root, nil)
+	start := 0
+	if n := m.current(); n != nil {
+		for i, candidate := range all {
+			if candidate == n {
+				start = i
+				break
+			}
+		}
+	}
+
+	direction := 1
+	if step < 0 {
+		direction = -1
+	}
+	query := strings.ToLower(m.query)
+	for i := 0; i < len(all); i++ {
+		offset := i*direction + step
+		idx := ((start+offset)%len(all) + len(all)) % len(all)
+		if matches(all[idx], query) {
+			for p := all[idx].parent; p != nil; p = p.parent {
+				p.expanded = true
+			}
+			m.refresh()
+			m.moveTo(all[idx])
+			m.status = ""
+			return
+		}
+	}
+	m.status = i18n.T("No match for '%s'", m.query)
+}
+
+// matches returns true if the key or scalar value of n contains query
+func matches(n *node, query string) bool {
+	if strings.Contains(strings.ToLower(n.key), query) {
+		return true
+	}
+	if n.children == nil {
+		return strings.Contains(strings.ToLower(scalar(n.value)), query)
+	}
+	return false
+}
+
+// copy copies text to the clipboard and reports the outcome, with copied
+// or failed formatted with the error
+func (m *Model) copy(text, copied, failed string) {
+	if err := m.Clipboard(text); err != nil {
+		m.status = i18n.T(failed, err)
+		return
+	}
+	m.status = i18n.T(copied)
+}
+
+// View renders the visible part of the tree and a status line
+func (m Model) View() string {
+	var b strings.Builder
+
+	height := m.pageSize()
+	end := min(m.offset+height, len(m.visible))
+	for i := m.offset; i < end; i++ {
+		line := m.renderLine(m.visible[i])
+		if i == m.cursor {
+			line = cursorStyle.Render(lipgloss.NewStyle().MaxWidth(m.Width).Render(m.plainLine(m.visible[i])))
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	for i := end - m.offset; i < height; i++ {
+		b.WriteString("\n")
+	}
+
+	switch {
+	case m.searching:
+		b.WriteString("/" + m.query + "█")
+	case m.status != "":
+		b.WriteString(summaryStyle.Render(m.status))
+	default:
+		b.WriteString(summaryStyle.Render(m.Path()))
+	}
+
+	return b.String()
+}
+
+// renderLine renders a node with syntax highlighting
+func (m Model) renderLine(n *node) string {
+	var b strings.Builder
+	b.WriteString(strings.Repeat("  ", n.depth))
+	b.WriteString(marker(n))
+	if n.parent != nil {
+		b.WriteString(m.highlight(keyStyle, n.key))
+		b.WriteString(": ")
+	}
+
+	switch {
+	case n.children != nil:
+		b.WriteString(summaryStyle.Render(summary(n)))
+	case isString(n.value):
+		b.WriteString(m.highlight(stringStyle, scalar(n.value)))
+	default:
+		b.WriteString(m.highlight(scalarStyle, scalar(n.value)))
+	}
+
+	return lipgloss.NewStyle().MaxWidth(m.Width).Render(b.String())
+}
+
+// plainLine renders a node without styles, for the cursor line
+func (m Model) plainLine(n *node) string {
+	line := strings.Repeat("  ", n.depth) + marker(n)
+	if n.parent != nil {
+		line += n.key + ": "
+	}
+	if n.children != nil {
+		return line + summary(n)
+	}
+	return line + scalar(n.value)
+}
+
+// highlight renders text with style, marking occurrences of the search query
+func (m Model) highlight(style lipgloss.Style, text string) string {
+	if m.query == "" || m.searching {
+		return style.Render(text)
+	}
+
+	idx := strings.Index(strings.ToLower(text), strings.ToLower(m.query))
+	end := idx + len(m.query)
+	if idx < 0 || end > len(text) {
+		return style.Render(text)
+	}
+	return style.Render(text[:idx]) + matchStyle.Render(text[idx:end]) + style.Render(text[end:])
+}
+
+// marker returns the expand/collapse marker of a node
+func marker(n *node) string {
+	switch {
+	case n.children == nil:
+		return "  "
+	case n.expanded:
+		return "▾ "
+	default:
+		return "▸ "
+	}
+}
+
+// summary describes a container, with its contents when collapsed
+func summary(n *node) string {
+	switch n.value.(type) {
+	case []interface{}:
+		if n.expanded {
+			return "["
+		}
+		return fmt.Sprintf("[%d items]", len(n.children))
+	default:
+		if n.expanded {
+			return "{"
+		}
+		return fmt.Sprintf("{%d keys}", len(n.children))
+	}
+}
+
+// scalar returns the JSON encoding of a scalar value
+func scalar(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// isString returns true if v is a string
+func isString(v interface{}) bool {
+	_, ok := v.(string)
+	return ok
+}
+
+// refresh rebuilds the visible node list and keeps the cursor in range
+func (m *Model) refresh() {
+	current := m.current()
+	m.visible = flatten(m.root, func(n *node) bool { return n.expanded })
+	m.moveTo(current)
+}
+
+// flatten lists nodes in display order, descending into nodes accepted by
+// descend, or all nodes if descend is nil
+func flatten(n *node, descend func(*node) bool) []*node {
+	nodes := []*node{n}
+	if descend == nil || descend(n) {
+		for _, child := range n.children {
+			nodes = append(nodes, flatten(child, descend)...)
+		}
+	}
+	return nodes
+}
+
+// setExpanded expands or collapses n and all its descendants
+func setExpanded(n *node, expanded bool) {
+	if n.children == nil {
+		return
+	}
+	n.expanded = expanded
+	for _, child := range n.children {
+		setExpanded(child, expanded)
+	}
+}
+
+// current returns the node under the cursor
+func (m Model) current() *node {
+	if m.cursor < 0 || m.cursor >= len(m.visible) {
+		return nil
+	}
+	return m.visible[m.cursor]
+}
+
+// moveTo moves the cursor to n, or its closest visible ancestor
+func (m *Model) moveTo(n *node) {
+	for ; n != nil; n = n.parent {
+		for i, candidate := range m.visible {
+			if candidate == n {
+				m.move(i - m.cursor)
+				return
+			}
+		}
+	}
+	m.move(0)
+}
+
+// move moves the cursor by delta lines and scrolls to keep it visible
+func (m *Model) move(delta int) {
+	m.cursor = max(0, min(m.cursor+delta, len(m.visible)-1))
+
+	height := m.pageSize()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+height {
+		m.offset = m.cursor - height + 1
+	}
+}
+
+// pageSize returns the number of tree lines shown, leaving one line for
+// the status
+func (m Model) pageSize() int {
+	return max(m.Height-1, 1)
+}
@@ -0,0 +1,114 @@
+package jsontree
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testJSON = `{
+	"tools": [
+		{"name": "search", "inputSchema": {"properties": {"query": {"type": "string"}}}},
+		{"name": "fetch", "description": "Fetch a URL"}
+	],
+	"odd key": true
+}`
+
+func press(m Model, keys ...string) Model {
+	for _, key := range keys {
+		var msg tea.KeyMsg
+		switch key {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		}
+		m, _ = m.Update(msg)
+	}
+	return m
+}
+
+func TestNew(t *testing.T) {
+	m := New(json.RawMessage(testJSON))
+
+	// Only the root is expanded, with sorted keys
+	require.Len(t, m.visible, 3)
+	assert.Equal(t, "$['odd key']", m.visible[1].path)
+	assert.Equal(t, "$.tools", m.visible[2].path)
+	assert.Equal(t, "$", m.Path())
+}
+
+func TestModel_ExpandCollapse(t *testing.T) {
+	m := New(json.RawMessage(testJSON))
+
+	m = press(m, "j", "j", "l")
+	assert.Equal(t, "$.tools", m.Path())
+	assert.Len(t, m.visible, 5)
+
+	// h on a child moves to its parent, then collapses it
+	m = press(m, "j", "h")
+	assert.Equal(t, "$.tools", m.Path())
+	m = press(m, "h")
+	assert.Len(t, m.visible, 3)
+
+	m = press(m, "E")
+	assert.Len(t, m.visible, len(flatten(m.root, nil)))
+	m = press(m, "C")
+	assert.Len(t, m.visible, 3)
+	assert.Equal(t, "$", m.Path())
+}
+
+func TestModel_Search(t *testing.T) {
+	m := New(json.RawMessage(testJSON))
+
+	// Matches inside collapsed nodes are revealed
+	m = press(m, "/", "q", "u", "e", "r", "y", "enter")
+	assert.Equal(t, "$.tools[0].inputSchema.properties.query", m.Path())
+	assert.False(t, m.Searching())
+
+	// Values match too, and n wraps around
+	m = press(m, "/", "U", "R", "L", "enter")
+	assert.Equal(t, "$.tools[1].description", m.Path())
+	m = press(m, "n")
+	assert.Equal(t, "$.tools[1].description", m.Path())
+
+	m = press(m, "/", "z", "z", "enter")
+	assert.Contains(t, m.View(), "No match for 'zz'")
+
+	// Esc cancels typing
+	m = press(m, "/", "a", "esc")
+	assert.False(t, m.Searching())
+}
+
+func TestModel_Copy(t *testing.T) {
+	var copied string
+	m := New(json.RawMessage(testJSON))
+	m.Clipboard = func(text string) error {
+		copied = text
+		return nil
+	}
+
+	m = press(m, "/", `"fetch"`, "enter", "Y")
+	assert.Equal(t, "$.tools[1].name", copied)
+	m = press(m, "y")
+	assert.Equal(t, `"fetch"`, copied)
+	assert.Contains(t, m.View(), "Copied value")
+}
+
+func TestModel_View_Scrolling(t *testing.T) {
+	items := make([]int, 50)
+	m := New(items)
+	m.Height = 6
+
+	m = press(m, "G")
+	view := m.View()
+	assert.Len(t, strings.Split(view, "\n"), 6)
+	assert.Contains(t, view, "49: 0")
+	assert.Equal(t, "$[49]", m.Path())
+}
@@ -0,0 +1,136 @@
+package tui
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	markdownCodeStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#F9E2AF"))
+
+	// markdownListItem matches list items, capturing their indent, marker
+	// and text
+	markdownListItem = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+
+	// markdownHeading matches headings, capturing their text
+	markdownHeading = regexp.MustCompile(`^#{1,6}\s+(.*?)\s*#*$`)
+
+	// markdownLink matches links, capturing their text
+	markdownLink = regexp.MustCompile(`\[([^\]]+)\]\([^)\s]*\)`)
+)
+
+// renderMarkdown renders the markdown servers put in tool descriptions in
+// base style: headings and bold, italic and code spans are styled, list
+// items get bullets and text is wrapped to width, unless it is zero. Code
+// blocks are kept as they are.
+func renderMarkdown(text string, width int, base lipgloss.Style) string {
+	var lines, paragraph []string
+	flush := func() {
+		if len(paragraph) > 0 {
+			lines = append(lines, wrapMarkdown(inlineMarkdown(strings.Join(paragraph, " "), base), width, ""))
+			paragraph = nil
+		}
+	}
+
+	inCode := false
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			flush()
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			lines = append(lines, markdownCodeStyle.Render("  "+strings.TrimRight(line, " \t")))
+			continue
+		}
+
+		switch {
+		case trimmed == "":
+			flush()
+			if len(lines) > 0 && lines[len(lines)-1] != "" {
+				lines = append(lines, "")
+			}
+		case markdownHeading.MatchString(trimmed):
+			flush()
+			heading := markdownHeading.FindStringSubmatch(trimmed)[1]
+			lines = append(lines, wrapMarkdown(inlineMarkdown(heading, base.Bold(true)), width, ""))
+		case markdownListItem.MatchString(line):
+			flush()
+			item := markdownListItem.FindStringSubmatch(line)
+			marker := item[2]
+			if !strings.ContainsAny(marker[len(marker)-1:], ".)") {
+				marker = "•"
+			}
+			indent := strings.Repeat(" ", len(strings.ReplaceAll(item[1], "\t", "  ")))
+			prefix := indent + marker + " "
+			lines = append(lines, base.Render(prefix)+wrapMarkdown(inlineMarkdown(item[3], base), width-lipgloss.Width(prefix), strings.Repeat(" ", lipgloss.Width(prefix))))
+		default:
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	flush()
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
+
+// wrapMarkdown wraps styled text to width, indenting the lines after the
+// first
+func wrapMarkdown(text string, width int, indent string) string {
+	if width <= 0 {
+		return text
+	}
+	wrapped := strings.Split(lipgloss.NewStyle().Width(width).Render(text), "\n")
+	for i := range wrapped {
+		wrapped[i] = strings.TrimRight(wrapped[i], " ")
+		if i > 0 {
+			wrapped[i] = indent + wrapped[i]
+		}
+	}
+	return strings.Join(wrapped, "\n")
+}
+
+// inlineMarkdown styles the code, bold and italic spans and links of a line
+// of markdown, and the rest in base style
+func inlineMarkdown(line string, base lipgloss.Style) string {
+	line = markdownLink.ReplaceAllString(line, "$1")
+
+	var b, plain strings.Builder
+	flushPlain := func() {
+		if plain.Len() > 0 {
+			b.WriteString(base.Render(plain.String()))
+			plain.Reset()
+		}
+	}
+
+	for i := 0; i < len(line); {
+		rest := line[i:]
+		var delimiter string
+		var style lipgloss.Style
+		switch {
+		case strings.HasPrefix(rest, "`"):
+			delimiter, style = "`", markdownCodeStyle
+		case strings.HasPrefix(rest, "**"), strings.HasPrefix(rest, "__"):
+			delimiter, style = rest[:2], base.Bold(true)
+		case strings.HasPrefix(rest, "*"), strings.HasPrefix(rest, "_") && (i == 0 || line[i-1] == ' '):
+			delimiter, style = rest[:1], base.Italic(true)
+		}
+
+		// Spans need their closing delimiter, and text right after the
+		// opening one, so "a * b" stays as it is
+		if delimiter != "" && len(rest) > len(delimiter) && rest[len(delimiter)] != ' ' {
+			if end := strings.Index(rest[len(delimiter):], delimiter); end > 0 {
+				flushPlain()
+				b.WriteString(style.Render(rest[len(delimiter) : len(delimiter)+end]))
+				i += 2*len(delimiter) + end
+				continue
+			}
+		}
+		plain.WriteByte(line[i])
+		i++
+	}
+	flushPlain()
+	return b.String()
+}
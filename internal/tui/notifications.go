@@ -0,0 +1,57 @@
+package tui
+
+import (
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tartavull/mcp-manager/internal/api"
+	"github.com/tartavull/mcp-manager/internal/i18n"
+	"github.com/tartavull/mcp-manager/internal/notify"
+)
+
+// refreshNotifications asks the manager for the notification level of the
+// selected server
+func (m *Model) refreshNotifications() {
+	m.notifications = ""
+	notifications, ok := m.manager.(api.Notifications)
+	if !ok {
+		return
+	}
+	_, preferences, err := notifications.Notifications()
+	if err != nil {
+		return
+	}
+	for _, preference := range preferences {
+		if preference.Server == m.selectedServer {
+			m.notifications = preference.Level
+		}
+	}
+}
+
+// cycleNotifications moves the notification level of the selected server
+// to the next of notify.Levels
+func (m Model) cycleNotifications() (tea.Model, tea.Cmd) {
+	notifications, ok := m.manager.(api.Notifications)
+	if !ok || m.notifications == "" {
+		m.statusMessage = i18n.T("Notifications are not supported")
+		return m, nil
+	}
+
+	level := notify.Levels[(slices.Index(notify.Levels, m.notifications)+1)%len(notify.Levels)]
+	if err := notifications.SetNotification(m.selectedServer, level); err != nil {
+		m.statusMessage = err.Error()
+		return m, nil
+	}
+	m.notifications = level
+	m.statusMessage = i18n.T("Notifications: %s", level)
+	return m, nil
+}
+
+// notificationLine describes the notification level of the selected
+// server, or "" if it isn't known
+func (m Model) notificationLine() string {
+	if m.notifications == "" {
+		return ""
+	}
+	return i18n.T("Notifications: %s", m.notifications) + "\n"
+}
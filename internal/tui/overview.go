@@ -0,0 +1,412 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tartavull/mcp-manager/internal/api"
+	"github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/i18n"
+	"github.com/tartavull/mcp-manager/internal/server"
+	"github.com/tartavull/mcp-manager/internal/uptime"
+)
+
+const (
+	// maxOverviewEvents caps the status changes kept for the overview
+	maxOverviewEvents = 100
+
+	// topServersCount is how many servers the overview ranks by traffic
+	topServersCount = 5
+
+	// trafficBarWidth is the width of the busiest server's traffic bar
+	trafficBarWidth = 20
+
+	// uptimeDays is the window of the uptime table
+	uptimeDays = 7
+)
+
+var (
+	sectionStyle = lipgloss.NewStyle().Padding(0, 2)
+
+	eventTimeStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#585B70"))
+)
+
+// overviewEvent is a change to the servers noticed between refreshes
+type overviewEvent struct {
+	time time.Time
+	text string
+}
+
+// WithOverview returns the model showing the overview instead of the
+// server list, for a permanent dashboard pane
+func (m Model) WithOverview() Model {
+	m.viewState = ViewOverview
+	return m
+}
+
+// recordEvents notes the servers added, removed or changing status since
+// the last refresh
+func (m *Model) recordEvents(servers map[string]*server.Server) {
+	statuses := make(map[string]server.Status, len(servers))
+	for name, srv := range servers {
+		statuses[name] = srv.Status
+	}
+	if m.statuses == nil {
+		m.statuses = statuses
+		return
+	}
+
+	now := time.Now()
+	var events []overviewEvent
+	for _, name := range getOrderedServerNames(servers, m.servers) {
+		old, existed := m.statuses[name]
+		switch {
+		case !existed:
+			events = append(events, overviewEvent{now, name + " added"})
+		case old != statuses[name]:
+			events = append(events, overviewEvent{now, fmt.Sprintf("%s: %s → %s", name, old, statuses[name])})
+		}
+	}
+	for name := range m.statuses {
+		if _, exists := statuses[name]; !exists {
+			events = append(events, overviewEvent{now, name + " removed"})
+		}
+	}
+	m.statuses = statuses
+
+	m.events = append(m.events, events...)
+	if len(m.events) > maxOverviewEvents {
+		m.events = append([]overviewEvent(nil), m.events[len(m.events)-maxOverviewEvents:]...)
+	}
+}
+
+// refreshUptime asks the daemon how long it has been running
+func (m *Model) refreshUptime() {
+	uptime, ok := m.manager.(api.Uptime)
+	if !ok {
+		return
+	}
+	if d, err := uptime.Uptime(); err == nil {
+		m.daemonStart = time.Now().Add(-d)
+	}
+}
+
+// refreshValidation asks the manager for the problems of the configuration
+func (m *Model) refreshValidation() {
+	validator, ok := m.manager.(api.Validator)
+	if !ok {
+		return
+	}
+	if validation, err := validator.ValidateConfig(); err == nil {
+		m.validation = validation
+	}
+}
+
+// refreshProviders asks the manager which providers serve the generic
+// tools of the gateway
+func (m *Model) refreshProviders() {
+	selector, ok := m.manager.(api.Providers)
+	if !ok {
+		return
+	}
+	if providers, err := selector.Providers(); err == nil {
+		m.providers = providers
+	}
+}
+
+// refreshAvailability asks the daemon for the uptime of the servers
+func (m *Model) refreshAvailability() {
+	availability, ok := m.manager.(api.Availability)
+	if !ok {
+		return
+	}
+	if stats, err := availability.ServerUptime(uptimeDays); err == nil {
+		m.uptime = stats
+	}
+}
+
+// handleOverviewKeys handles key events in the overview
+func (m Model) handleOverviewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+
+	case "tab", "esc":
+		m.viewState = ViewList
+
+	case "r":
+		m.refreshing = true
+		return m, refreshCmd()
+	}
+
+	return m, nil
+}
+
+// viewOverview renders aggregate stats about the servers and the daemon
+func (m Model) viewOverview() string {
+	var b strings.Builder
+
+	servers := m.snapshot.Servers
+
+	title := titleStyle.Render(i18n.T("📊 MCP Overview"))
+	statusInfo := helpStyle.Render(i18n.T("Last refresh: %s", m.lastRefresh.Format("15:04:05")))
+	space := max(m.width-lipgloss.Width(title)-lipgloss.Width(statusInfo), 2)
+	b.WriteString(title + strings.Repeat(" ", space) + statusInfo)
+	b.WriteString("\n\n")
+
+	b.WriteString(headerStyle.Render(" " + i18n.T("Servers") + " "))
+	b.WriteString("\n")
+	b.WriteString(sectionStyle.Render(serverStats(servers)))
+	b.WriteString("\n\n")
+
+	b.WriteString(headerStyle.Render(" " + i18n.T("Daemon") + " "))
+	b.WriteString("\n")
+	b.WriteString(sectionStyle.Render(m.daemonStats()))
+	b.WriteString("\n\n")
+
+	if m.validation != nil && (len(m.validation.Problems) > 0 || len(m.validation.Conflicts) > 0) {
+		b.WriteString(headerStyle.Render(" " + i18n.T("Configuration") + " "))
+		b.WriteString("\n")
+		b.WriteString(sectionStyle.Render(validationReport(m.validation)))
+		b.WriteString("\n\n")
+	}
+
+	if len(m.providers) > 0 {
+		b.WriteString(headerStyle.Render(" " + i18n.T("Providers") + " "))
+		b.WriteString("\n")
+		b.WriteString(sectionStyle.Render(providersReport(m.providers)))
+		b.WriteString("\n\n")
+	}
+
+	if len(m.uptime) > 0 {
+		b.WriteString(headerStyle.Render(" " + i18n.T("Uptime (%d days)", uptimeDays) + " "))
+		b.WriteString("\n")
+		b.WriteString(sectionStyle.Render(uptimeReport(m.uptime)))
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString(headerStyle.Render(" " + i18n.T("Top Servers by Traffic") + " "))
+	b.WriteString("\n")
+	b.WriteString(sectionStyle.Render(topServers(servers)))
+	b.WriteString("\n\n")
+
+	b.WriteString(headerStyle.Render(" " + i18n.T("Recent Events") + " "))
+	b.WriteString("\n")
+
+	// Recent events fill the space left above the help
+	footerLines := 4
+	available := max(m.height-strings.Count(b.String(), "\n")-footerLines, 1)
+	events := m.events
+	if len(events) > available {
+		events = events[len(events)-available:]
+	}
+	if len(events) == 0 {
+		b.WriteString(helpStyle.Render("  " + i18n.T("No status changes since the TUI started")))
+		b.WriteString("\n")
+	}
+	for i := len(events) - 1; i >= 0; i-- {
+		b.WriteString(sectionStyle.Render(eventTimeStyle.Render(events[i].time.Format("15:04:05")) + " " + events[i].text))
+		b.WriteString("\n")
+	}
+
+	if remaining := m.height - strings.Count(b.String(), "\n") - footerLines; remaining > 0 {
+		b.WriteString(strings.Repeat("\n", remaining))
+	}
+
+	keys := []string{
+		i18n.T("Tab Server list"),
+		i18n.T("R Refresh"),
+		i18n.T("Q Quit"),
+	}
+
+	keyHelp := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#585B70")).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#585B70")).
+		Padding(0, 1).
+		Render(strings.Join(keys, " • "))
+
+	b.WriteString("\n")
+	b.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, keyHelp))
+
+	return b.String()
+}
+
+// serverStats counts the servers by status and their tools
+func serverStats(servers map[string]*server.Server) string {
+	counts := make(map[server.Status]int)
+	var tools, unhealthy, maintenance int
+	for _, srv := range servers {
+		counts[srv.Status]++
+		if srv.IsRunning() {
+			tools += srv.ToolCount
+			if srv.Health == server.HealthUnhealthy {
+				unhealthy++
+			}
+		}
+		if srv.Maintenance {
+			maintenance++
+		}
+	}
+
+	line := strings.Join([]string{
+		runningStyle.Render(i18n.T("%d running", counts[server.StatusRunning])),
+		startingStyle.Render(i18n.T("%d starting", counts[server.StatusStarting])),
+		stoppingStyle.Render(i18n.T("%d stopping", counts[server.StatusStopping])),
+		stoppedStyle.Render(i18n.T("%d stopped", counts[server.StatusStopped])),
+		unhealthyStyle.Render(i18n.T("%d error", counts[server.StatusError])),
+	}, "  ")
+
+	return line + "\n" + i18n.T("%d servers • %d tools • %d unhealthy • %d in maintenance",
+		len(servers), tools, unhealthy, maintenance)
+}
+
+// daemonStats describes the connection to the daemon and its state
+func (m Model) daemonStats() string {
+	var lines []string
+
+	if !m.snapshot.Daemon {
+		lines = append(lines, i18n.T("Mode: standalone"))
+	} else {
+		lines = append(lines, i18n.T("Mode: daemon"), i18n.T("Connection: %s", connectionIndicator(m.snapshot)))
+		if !m.daemonStart.IsZero() {
+			lines = append(lines, i18n.T("Uptime: %s", m.snapshot.Now.Sub(m.daemonStart).Truncate(time.Second)))
+		}
+	}
+
+	maintenance := i18n.T("Maintenance: off")
+	if m.snapshot.Maintenance {
+		maintenance = i18n.T("Maintenance: on")
+	}
+	lines = append(lines, maintenance)
+
+	return strings.Join(lines, "\n")
+}
+
+// validationReport lists the problems of the configuration and the tool
+// names the gateway resolved
+func validationReport(validation *grpc.Validation) string {
+	var lines []string
+	for _, problem := range validation.Problems {
+		lines = append(lines, unhealthyStyle.Render("✗ "+problem))
+	}
+	for _, conflict := range validation.Conflicts {
+		var outcomes []string
+		for _, name := range conflict.Servers[1:] {
+			if renamed, exists := conflict.Renamed[name]; exists {
+				outcomes = append(outcomes, fmt.Sprintf("%s as %s", name, renamed))
+			} else {
+				outcomes = append(outcomes, name+" hidden")
+			}
+		}
+		lines = append(lines, startingStyle.Render("⚠ ")+fmt.Sprintf("%s: kept by %s, %s",
+			conflict.Tool, conflict.Servers[0], strings.Join(outcomes, ", ")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// providersReport lists the generic tools of the gateway with the provider
+// selected for each and the providers' latency and errors
+func providersReport(groups []grpc.ProviderGroup) string {
+	var lines []string
+	for _, group := range groups {
+		selection := "no provider running"
+		if group.Selected != "" {
+			selection = group.Selected + " (auto)"
+			if group.Selected == group.Pinned {
+				selection = group.Selected + " (pinned)"
+			}
+		}
+
+		var providers []string
+		for _, provider := range group.Providers {
+			text := fmt.Sprintf("%s %s, %.0f%% errors", provider.Server, provider.Latency.Round(time.Millisecond), provider.ErrorRate*100)
+			switch {
+			case !provider.Running:
+				text = stoppedStyle.Render(provider.Server + " stopped")
+			case provider.Calls == 0:
+				text = provider.Server + " untried"
+			case provider.ErrorRate >= 0.5:
+				text = unhealthyStyle.Render(text)
+			}
+			providers = append(providers, text)
+		}
+		lines = append(lines, fmt.Sprintf("%s → %s  %s", group.Tool, runningStyle.Render(selection), strings.Join(providers, " • ")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// uptimeReport lists the least available servers first with their
+// outages and mean time to repair
+func uptimeReport(stats []uptime.Stats) string {
+	ranked := make([]uptime.Stats, 0, len(stats))
+	for _, s := range stats {
+		if _, ok := s.Uptime(); ok {
+			ranked = append(ranked, s)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, _ := ranked[i].Uptime()
+		b, _ := ranked[j].Uptime()
+		return a < b
+	})
+
+	var lines []string
+	for i, s := range ranked {
+		if i == topServersCount {
+			lines = append(lines, helpStyle.UnsetPadding().Render(i18n.T("%d more servers", len(ranked)-i)))
+			break
+		}
+		percentage, _ := s.Uptime()
+		text := fmt.Sprintf("%7.2f%%", percentage)
+		switch {
+		case percentage < 99:
+			text = unhealthyStyle.Render(text)
+		case percentage < 99.9:
+			text = startingStyle.Render(text)
+		default:
+			text = runningStyle.Render(text)
+		}
+		line := fmt.Sprintf("%-20s %s", s.Server, text)
+		if s.Outages > 0 {
+			line += "  " + i18n.T("%d outages, MTTR %s", s.Outages, s.MTTR)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// topServers ranks the servers by the requests their proxies served
+func topServers(servers map[string]*server.Server) string {
+	var ranked []*server.Server
+	for _, srv := range servers {
+		if srv.Requests > 0 {
+			ranked = append(ranked, srv)
+		}
+	}
+	if len(ranked) == 0 {
+		return helpStyle.UnsetPadding().Render(i18n.T("No requests served yet"))
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Requests != ranked[j].Requests {
+			return ranked[i].Requests > ranked[j].Requests
+		}
+		return ranked[i].Name < ranked[j].Name
+	})
+	if len(ranked) > topServersCount {
+		ranked = ranked[:topServersCount]
+	}
+
+	lines := make([]string, len(ranked))
+	for i, srv := range ranked {
+		bar := strings.Repeat("█", max(int(srv.Requests*trafficBarWidth/ranked[0].Requests), 1))
+		lines[i] = fmt.Sprintf("%-20s %8d requests %s", srv.Name, srv.Requests, runningStyle.Render(bar))
+	}
+	return strings.Join(lines, "\n")
+}
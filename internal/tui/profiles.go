@@ -0,0 +1,41 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tartavull/mcp-manager/internal/api"
+	"github.com/tartavull/mcp-manager/internal/i18n"
+)
+
+// snapshotProfile archives the browser profile of the selected server
+func (m Model) snapshotProfile() (tea.Model, tea.Cmd) {
+	profiles, ok := m.manager.(api.Profiles)
+	if !ok {
+		m.statusMessage = i18n.T("Browser profiles are not supported")
+		return m, nil
+	}
+
+	snapshot, err := profiles.SnapshotProfile(m.selectedServer, "")
+	if err != nil {
+		m.statusMessage = err.Error()
+		return m, nil
+	}
+	m.statusMessage = i18n.T("Saved profile snapshot %s to %s", snapshot.Name, snapshot.Path)
+	return m, nil
+}
+
+// resetProfile deletes the browser profile of the selected server, which
+// must be stopped
+func (m Model) resetProfile() (tea.Model, tea.Cmd) {
+	profiles, ok := m.manager.(api.Profiles)
+	if !ok {
+		m.statusMessage = i18n.T("Browser profiles are not supported")
+		return m, nil
+	}
+
+	if err := profiles.ResetProfile(m.selectedServer, ""); err != nil {
+		m.statusMessage = err.Error()
+		return m, nil
+	}
+	m.statusMessage = i18n.T("Reset browser profile")
+	return m, nil
+}
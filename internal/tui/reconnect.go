@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tartavull/mcp-manager/internal/api"
+	"github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/i18n"
+)
+
+var reconnectStyle = lipgloss.NewStyle().
+	Border(lipgloss.RoundedBorder()).
+	BorderForeground(lipgloss.Color("#EBA0AC")).
+	Padding(1, 3)
+
+// viewReconnect covers the view while the connection to the daemon is
+// lost, rather than showing servers as they were before it, counting down
+// to the next attempt to reconnect
+func (m Model) viewReconnect() string {
+	s := m.snapshot
+	lines := []string{unhealthyStyle.Bold(true).Render(i18n.T("⚠ Lost connection to the daemon")), ""}
+
+	switch {
+	case s.State == grpc.StateDisconnected:
+		lines = append(lines, i18n.T("Gave up reconnecting"))
+	case s.State == grpc.StateReconnecting && s.Retry.Next.After(s.Now):
+		lines = append(lines, i18n.T("Reconnecting in %s (attempt %d)",
+			s.Retry.Next.Sub(s.Now).Round(time.Second), s.Retry.Attempt))
+	case s.State == grpc.StateReconnecting:
+		lines = append(lines, i18n.T("Reconnecting..."))
+	default:
+		lines = append(lines, i18n.T("The daemon isn't answering, retrying at the next refresh"))
+	}
+	if !s.LastHeartbeat.IsZero() {
+		lines = append(lines, i18n.T("Last heard from %s ago", s.Now.Sub(s.LastHeartbeat).Truncate(time.Second)))
+	}
+	lines = append(lines, "", helpStyle.UnsetPadding().Render(i18n.T("R Retry now")+" • "+i18n.T("Q Quit")))
+
+	box := reconnectStyle.Render(strings.Join(lines, "\n"))
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+}
+
+// handleReconnectKeys handles key events while the connection to the
+// daemon is lost, retrying it at once on r
+func (m Model) handleReconnectKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+
+	case "r":
+		if reconnection, ok := m.manager.(api.Reconnection); ok {
+			reconnection.Reconnect()
+		}
+		m.captureLiveness()
+		return m, refreshCmd()
+	}
+	return m, nil
+}
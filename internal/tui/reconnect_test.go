@@ -0,0 +1,67 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/apitest"
+	"github.com/tartavull/mcp-manager/internal/grpc"
+)
+
+func TestModel_Reconnect(t *testing.T) {
+	daemon := apitest.NewDaemon()
+	require.NoError(t, daemon.AddServer("test1", "echo test1", 4001, "Test server 1"))
+	model := New(daemon)
+	model.width, model.height = 120, 40
+	assert.Contains(t, model.View(), "test1")
+
+	// Losing the stream covers the servers, counting down to the next attempt
+	daemon.SetState(grpc.StateReconnecting, grpc.Retry{Attempt: 2, Next: time.Now().Add(3500 * time.Millisecond)})
+	updated, _ := model.Update(Changed())
+	view := updated.View()
+	assert.Contains(t, view, "Lost connection to the daemon")
+	assert.Regexp(t, `Reconnecting in [34]s \(attempt 2\)`, view)
+	assert.NotContains(t, view, "test1")
+	assert.Equal(t, busyTick, updated.(Model).tickInterval())
+
+	// Keys other than retrying and quitting wait for the daemon
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, ViewList, updated.(Model).viewState)
+	updated, cmd := updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	assert.Equal(t, 1, daemon.Reconnects())
+	require.NotNil(t, cmd)
+
+	daemon.SetState(grpc.StateDisconnected, grpc.Retry{})
+	updated, _ = updated.Update(tickMsg(time.Now()))
+	assert.Contains(t, updated.View(), "Gave up reconnecting")
+
+	// Back with the servers once the stream is resubscribed
+	daemon.SetState(grpc.StateConnected, grpc.Retry{})
+	updated, _ = updated.Update(Changed())
+	assert.NotContains(t, updated.View(), "Lost connection")
+	assert.Contains(t, updated.View(), "test1")
+}
+
+func TestModel_Unreachable(t *testing.T) {
+	daemon := apitest.NewDaemon()
+	require.NoError(t, daemon.AddServer("test1", "echo test1", 4001, "Test server 1"))
+	model := New(daemon)
+	model.width, model.height = 120, 40
+
+	// A daemon not answering keeps the servers it last answered with,
+	// without recording them as removed
+	daemon.SetReachable(false)
+	updated, _ := model.Update(refreshMsg{})
+	m := updated.(Model)
+	assert.True(t, m.Snapshot().Lost())
+	assert.Contains(t, m.Snapshot().Servers, "test1")
+	assert.Empty(t, m.events)
+	assert.Contains(t, m.View(), "The daemon isn't answering")
+
+	daemon.SetReachable(true)
+	updated, _ = m.Update(refreshMsg{})
+	assert.False(t, updated.(Model).Snapshot().Lost())
+}
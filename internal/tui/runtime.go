@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tartavull/mcp-manager/internal/api"
+	"github.com/tartavull/mcp-manager/internal/i18n"
+)
+
+// refreshRuntime asks the manager what the selected server runs as
+func (m *Model) refreshRuntime() {
+	m.runtime = nil
+	describer, ok := m.manager.(api.Describer)
+	if !ok {
+		return
+	}
+	if runtime, err := describer.DescribeServer(m.selectedServer); err == nil {
+		m.runtime = runtime
+	}
+}
+
+// runtimeSection renders what the selected server runs as, or "" if it
+// isn't known
+func (m Model) runtimeSection() string {
+	if m.runtime == nil || m.runtime.Server != m.selectedServer {
+		return ""
+	}
+	r := m.runtime
+
+	var lines []string
+	if r.Running {
+		lines = append(lines, i18n.T("Command (running): %s", r.Command))
+	} else {
+		lines = append(lines, i18n.T("Command (next start): %s", r.Command))
+	}
+	lines = append(lines, i18n.T("Directory: %s", r.Cwd))
+	if r.Transport != "" {
+		lines = append(lines, i18n.T("Transport: %s", r.Transport))
+	} else {
+		lines = append(lines, i18n.T("Transport: stdio"))
+	}
+	lines = append(lines, i18n.T("PATH: %s", r.Path))
+	if len(r.Env) > 0 {
+		vars := make([]string, len(r.Env))
+		for i, v := range r.Env {
+			vars[i] = fmt.Sprintf("%s (%s)", v.Name, v.Source)
+		}
+		lines = append(lines, i18n.T("Env: %s", strings.Join(vars, ", ")))
+	}
+	for _, limit := range r.Limits {
+		lines = append(lines, i18n.T("Limit: %s %s", limit.Name, limit.Value))
+	}
+	if r.Error != "" {
+		lines = append(lines, unhealthyStyle.Render(i18n.T("Can't start: %s", r.Error)))
+	}
+
+	var b strings.Builder
+	b.WriteString(headerStyle.Render(" " + i18n.T("Effective Runtime") + " "))
+	b.WriteString("\n\n")
+	b.WriteString(lipgloss.NewStyle().Padding(0, 2).Render(strings.Join(lines, "\n")))
+	b.WriteString("\n\n")
+	return b.String()
+}
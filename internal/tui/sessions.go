@@ -0,0 +1,273 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tartavull/mcp-manager/internal/api"
+	"github.com/tartavull/mcp-manager/internal/i18n"
+	"github.com/tartavull/mcp-manager/internal/transcript"
+)
+
+// openSessions shows the session transcripts of the proxies' clients
+func (m Model) openSessions() (tea.Model, tea.Cmd) {
+	if _, ok := m.manager.(api.Transcripts); !ok {
+		return m, nil
+	}
+	m.viewState = ViewSessions
+	m.sessionCursor = 0
+	m.sessionFilter, m.filtering = "", false
+	m.statusMessage = ""
+	m.refreshSessions()
+	return m, nil
+}
+
+// refreshSessions asks the manager for the session transcripts, only with
+// the calls given the filter's request or run ID if there is one
+func (m *Model) refreshSessions() {
+	transcripts, ok := m.manager.(api.Transcripts)
+	if !ok || m.filtering {
+		return
+	}
+	sessions, err := transcripts.Sessions()
+	if m.sessionFilter != "" {
+		sessions, err = transcripts.FindSessions(m.sessionFilter)
+	}
+	if err != nil {
+		m.statusMessage = err.Error()
+		return
+	}
+	m.sessions = sessions
+	m.sessionCursor = min(m.sessionCursor, max(len(m.sessions)-1, 0))
+}
+
+// selectedSession returns the selected session with its calls, those of
+// filtered sessions being the calls given the filter's ID
+func (m Model) selectedSession() (*transcript.Session, error) {
+	transcripts, ok := m.manager.(api.Transcripts)
+	if !ok || m.sessionCursor >= len(m.sessions) {
+		return nil, fmt.Errorf("no session selected")
+	}
+	if m.sessionFilter != "" {
+		session := m.sessions[m.sessionCursor]
+		return &session, nil
+	}
+	return transcripts.Session(m.sessions[m.sessionCursor].ID)
+}
+
+// handleFilterKeys handles key events while the session filter is typed
+func (m Model) handleFilterKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEnter:
+		m.filtering = false
+		m.sessionCursor = 0
+		m.refreshSessions()
+	case tea.KeyEsc:
+		m.filtering = false
+		m.sessionFilter = ""
+		m.refreshSessions()
+	case tea.KeyBackspace:
+		if m.sessionFilter != "" {
+			runes := []rune(m.sessionFilter)
+			m.sessionFilter = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes:
+		m.sessionFilter += string(msg.Runes)
+	}
+	return m, nil
+}
+
+// handleSessionsKeys handles key events in the sessions view
+func (m Model) handleSessionsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.filtering {
+		return m.handleFilterKeys(msg)
+	}
+
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+
+	case "esc", "backspace":
+		// Clear the filter first
+		if m.sessionFilter != "" {
+			m.sessionFilter = ""
+			m.sessionCursor = 0
+			m.refreshSessions()
+			return m, nil
+		}
+		m.viewState = ViewList
+		m.statusMessage = ""
+
+	case "/":
+		// Filter the calls by the request or run ID agents gave them
+		m.filtering = true
+		m.sessionFilter = ""
+
+	case "up", "k":
+		if m.sessionCursor > 0 {
+			m.sessionCursor--
+		}
+
+	case "down", "j":
+		if m.sessionCursor < len(m.sessions)-1 {
+			m.sessionCursor++
+		}
+
+	case "r":
+		m.refreshSessions()
+
+	case "enter":
+		// Explore the calls of the session
+		session, err := m.selectedSession()
+		if err != nil {
+			m.statusMessage = err.Error()
+			return m, nil
+		}
+		m.openExplorer("Session "+session.ID+" of "+session.Client, explorableEntries(session.Entries))
+
+	case "e", "E":
+		// Export the session as markdown, or JSON with shift
+		session, err := m.selectedSession()
+		if err != nil {
+			m.statusMessage = err.Error()
+			return m, nil
+		}
+		path, err := exportSession(session, msg.String() == "E")
+		if err != nil {
+			m.statusMessage = i18n.T("Export failed: %v", err)
+		} else {
+			m.statusMessage = i18n.T("Exported to %s", path)
+		}
+	}
+
+	return m, nil
+}
+
+// explorableEntries returns the calls of a session with their arguments
+// and results decoded, so the explorer can expand them. Shortened results
+// stay strings.
+func explorableEntries(entries []transcript.Entry) []map[string]interface{} {
+	decode := func(data string) interface{} {
+		var v interface{}
+		if err := json.Unmarshal([]byte(data), &v); err != nil {
+			return data
+		}
+		return v
+	}
+
+	calls := make([]map[string]interface{}, len(entries))
+	for i, entry := range entries {
+		call := map[string]interface{}{
+			"time":       entry.Time.Format(time.RFC3339Nano),
+			"server":     entry.Server,
+			"method":     entry.Method,
+			"durationMs": entry.DurationMs,
+		}
+		if entry.Tool != "" {
+			call["tool"] = entry.Tool
+		}
+		if entry.RequestID != "" {
+			call["requestId"] = entry.RequestID
+		}
+		if entry.RunID != "" {
+			call["runId"] = entry.RunID
+		}
+		if entry.Arguments != "" {
+			call["arguments"] = decode(entry.Arguments)
+		}
+		if entry.Error != "" {
+			call["error"] = entry.Error
+		} else if entry.Result != "" {
+			call["result"] = decode(entry.Result)
+		}
+		calls[i] = call
+	}
+	return calls
+}
+
+// exportSession writes a session transcript to the temp directory as
+// markdown or JSON, returning the file's path
+func exportSession(session *transcript.Session, asJSON bool) (string, error) {
+	data, ext := transcript.Markdown(session), ".md"
+	if asJSON {
+		var err error
+		if data, err = json.MarshalIndent(session, "", "  "); err != nil {
+			return "", err
+		}
+		ext = ".json"
+	}
+	path := filepath.Join(os.TempDir(), "mcp-session-"+session.ID+ext)
+	return path, os.WriteFile(path, data, 0600)
+}
+
+// viewSessions renders the session transcripts
+func (m Model) viewSessions() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render(i18n.T("🧾 Sessions")))
+	b.WriteString("\n\n")
+
+	if m.filtering || m.sessionFilter != "" {
+		filter := i18n.T("Request or run ID: %s", m.sessionFilter)
+		if m.filtering {
+			filter += "█"
+		}
+		b.WriteString("  " + filter)
+		b.WriteString("\n\n")
+	}
+
+	if len(m.sessions) == 0 {
+		b.WriteString(helpStyle.Render("  " + i18n.T("No calls recorded yet")))
+		b.WriteString("\n")
+	} else {
+		header := fmt.Sprintf("%-10s %-30s %-8s %-8s %s", "ID", "CLIENT", "CALLS", "ERRORS", "LAST CALL")
+		b.WriteString(headerStyle.Render(header))
+		b.WriteString("\n")
+		for i, s := range m.sessions {
+			row := fmt.Sprintf("%-10s %-30s %-8d %-8d %s ago", s.ID, s.Client, s.Calls, s.Errors,
+				m.snapshot.Now.Sub(s.Updated).Round(time.Second))
+			switch {
+			case i == m.sessionCursor:
+				row = selectedStyle.Render(row)
+			case s.Errors > 0:
+				row = unhealthyStyle.Render(row)
+			}
+			b.WriteString(row)
+			b.WriteString("\n")
+		}
+	}
+
+	if m.statusMessage != "" {
+		b.WriteString(helpStyle.Render("  " + m.statusMessage))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	keys := []string{
+		i18n.T("↑/↓ Navigate"),
+		i18n.T("Enter Explore"),
+		i18n.T("E Export markdown"),
+		i18n.T("Shift+E Export JSON"),
+		i18n.T("/ Filter by request or run ID"),
+		i18n.T("R Refresh"),
+		i18n.T("ESC Back"),
+	}
+
+	keyHelp := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#585B70")).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#585B70")).
+		Padding(0, 1).
+		Render(strings.Join(keys, " • "))
+	b.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, keyHelp))
+
+	return b.String()
+}
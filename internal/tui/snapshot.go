@@ -0,0 +1,149 @@
+package tui
+
+import (
+	"log"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tartavull/mcp-manager/internal/api"
+	"github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// Snapshot is the state of the manager the views render. It is captured
+// on refreshes and changes reported by the daemon, so View makes no calls
+// to the manager and renders the same output for the same model.
+type Snapshot struct {
+	Servers     map[string]*server.Server
+	Order       []string
+	Maintenance bool // Daemon-wide maintenance mode
+
+	// Daemon is set when connected to a daemon, which is Connected while
+	// its heartbeats arrive
+	Daemon        bool
+	Connected     bool
+	LastHeartbeat time.Time
+
+	// State is the connection state of the daemon's event stream, and
+	// Retry the attempts to reconnect it while it's lost. Unreachable is
+	// set when the daemon didn't answer the last call for the servers,
+	// which are then those it last answered with.
+	State       grpc.ConnectionState
+	Retry       grpc.Retry
+	Unreachable bool
+
+	// Now is the time durations, e.g. since the last heartbeat, are
+	// measured to
+	Now time.Time
+}
+
+// capture takes a snapshot of the manager for the views
+func (m *Model) capture() {
+	servers, order, err := m.manager.GetServers()
+	if err != nil {
+		m.snapshot.Unreachable = true
+		m.captureLiveness()
+		return
+	}
+	m.captureServers(servers, order)
+}
+
+// refreshServers updates the list and the snapshot from the manager,
+// returning its servers and the command updating the terminal title. A
+// daemon not answering keeps the servers it last answered with.
+func (m *Model) refreshServers() (map[string]*server.Server, tea.Cmd) {
+	servers, order, err := m.manager.GetServers()
+	if err != nil {
+		log.Printf("Failed to get servers: %v", err)
+		m.snapshot.Unreachable = true
+		m.captureLiveness()
+		return m.snapshot.Servers, nil
+	}
+	m.recordEvents(servers)
+	titleCmd := m.windowTitleCmd(servers)
+	m.setServers(servers, order)
+	m.captureServers(servers, order)
+	return servers, titleCmd
+}
+
+// captureServers takes a snapshot of the manager with servers the caller
+// just got from it
+func (m *Model) captureServers(servers map[string]*server.Server, order []string) {
+	maintenance, _ := m.manager.Maintenance()
+	m.snapshot = Snapshot{
+		Servers:     servers,
+		Order:       order,
+		Maintenance: maintenance,
+	}
+	m.captureLiveness()
+}
+
+// captureLiveness updates the time of the snapshot and the connection to
+// the daemon, which the client tracks without calling it
+func (m *Model) captureLiveness() {
+	m.snapshot.Now = time.Now()
+	if liveness, ok := m.manager.(api.Liveness); ok {
+		m.snapshot.Daemon = true
+		m.snapshot.Connected = liveness.Connected()
+		m.snapshot.LastHeartbeat = liveness.LastHeartbeat()
+	}
+	if reconnection, ok := m.manager.(api.Reconnection); ok {
+		m.snapshot.State = reconnection.State()
+		m.snapshot.Retry = reconnection.Retry()
+	}
+}
+
+// Lost returns true if the connection to the daemon is lost: its event
+// stream is being reconnected or was given up on, or it stopped answering
+func (s Snapshot) Lost() bool {
+	switch s.State {
+	case grpc.StateReconnecting, grpc.StateDisconnected:
+		return true
+	}
+	return s.Unreachable
+}
+
+// Snapshot returns the state of the manager the model renders
+func (m Model) Snapshot() Snapshot {
+	return m.snapshot
+}
+
+// WithSnapshot returns the model rendering s instead of the state of the
+// manager, until its next refresh. Along with a fixed size and refresh
+// time, View then renders deterministically, e.g. for golden files or
+// other frontends of the same layout.
+func (m Model) WithSnapshot(s Snapshot) Model {
+	m.snapshot = s
+	m.setServers(s.Servers, s.Order)
+	return m
+}
+
+// WithRefreshTime returns the model showing t as its last refresh
+func (m Model) WithRefreshTime(t time.Time) Model {
+	m.lastRefresh = t
+	return m
+}
+
+// Changed is the message to send the model when the daemon reports a
+// change or the connection to it changes, e.g. from the callbacks of
+// GRPCAdapter, so it takes a new snapshot before the next refresh
+func Changed() tea.Msg {
+	return changedMsg{}
+}
+
+// WithDebug returns the model logging how long each frame takes to render
+func (m Model) WithDebug() Model {
+	m.debug = true
+	return m
+}
+
+// View renders the current view from the model and its snapshot
+func (m Model) View() string {
+	if !m.debug {
+		return m.render()
+	}
+	start := time.Now()
+	view := m.render()
+	log.Printf("Rendered %s view in %s", m.viewState, time.Since(start))
+	return view
+}
@@ -0,0 +1,174 @@
+package tui
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/apitest"
+	"github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/i18n"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+var update = flag.Bool("update", false, "Rewrite the golden files in testdata")
+
+// goldenSnapshot returns a fixed state of a daemon that lost its
+// connection, with a running, a stopped and an unhealthy server
+func goldenSnapshot() Snapshot {
+	now := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	filesystem := server.NewServer("filesystem", "npx server-filesystem /tmp", 4001, "Files in /tmp")
+	filesystem.SetStatus(server.StatusRunning)
+	filesystem.SetPID(123)
+	filesystem.SetToolCount(11)
+	filesystem.Tags = []string{"files"}
+
+	browser := server.NewServer("browser", "npx @playwright/mcp", 4002, "Browser automation")
+	browser.Maintenance = true
+
+	search := server.NewServer("search", "search-mcp", 4003, "Web search")
+	search.SetStatus(server.StatusRunning)
+	search.SetPID(456)
+	search.Health = server.HealthUnhealthy
+
+	return Snapshot{
+		Servers: map[string]*server.Server{
+			"filesystem": filesystem,
+			"browser":    browser,
+			"search":     search,
+		},
+		Order:         []string{"filesystem", "browser", "search"},
+		Maintenance:   true,
+		Daemon:        true,
+		LastHeartbeat: now.Add(-30 * time.Second),
+		Now:           now,
+	}
+}
+
+func TestModel_Golden(t *testing.T) {
+	snapshot := goldenSnapshot()
+	model := New(apitest.NewManager()).WithSnapshot(snapshot).WithRefreshTime(snapshot.Now)
+	model.width, model.height = 120, 40
+
+	detail := model
+	detail.viewState, detail.selectedServer = ViewDetail, "filesystem"
+	overview := model
+	overview.viewState = ViewOverview
+	lost := snapshot
+	lost.State, lost.Retry = grpc.StateReconnecting, grpc.Retry{Attempt: 3, Next: snapshot.Now.Add(4 * time.Second)}
+	reconnect := model.WithSnapshot(lost)
+	approvals := model
+	approvals.approvals = []grpc.Approval{{
+		ID:        "1",
+		Server:    "filesystem",
+		Tool:      "write_file",
+		Arguments: `{"path":"/tmp/notes.md"}`,
+		Requested: snapshot.Now.Add(-18 * time.Second),
+		Deadline:  snapshot.Now.Add(42 * time.Second),
+	}}
+
+	for name, m := range map[string]Model{"list": model, "detail": detail, "overview": overview, "reconnect": reconnect, "approvals": approvals} {
+		t.Run(name, func(t *testing.T) {
+			view := m.View()
+			assert.Equal(t, view, m.View(), "rendering twice renders the same")
+
+			path := filepath.Join("testdata", name+".golden")
+			if *update {
+				require.NoError(t, os.MkdirAll("testdata", 0755))
+				require.NoError(t, os.WriteFile(path, []byte(view), 0644))
+			}
+			golden, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, string(golden), view)
+		})
+	}
+}
+
+func TestModel_SnapshotRefresh(t *testing.T) {
+	daemon := apitest.NewDaemon()
+	require.NoError(t, daemon.AddServer("test1", "echo test1", 4001, "Test server 1"))
+	model := New(daemon)
+	model.width, model.height = 120, 40
+
+	// Ticks only update the connection to a daemon, which reports changes
+	require.NoError(t, daemon.SetMaintenance("", true))
+	daemon.SetConnected(false)
+	updated, _ := model.Update(tickMsg(time.Now()))
+	assert.False(t, updated.(Model).Snapshot().Maintenance)
+	assert.Contains(t, updated.View(), "no heartbeat")
+	assert.NotContains(t, updated.View(), "MAINTENANCE")
+
+	updated, _ = updated.Update(Changed())
+	assert.True(t, updated.(Model).Snapshot().Maintenance)
+	assert.Contains(t, updated.View(), "MAINTENANCE")
+
+	// Standalone managers are polled every tick
+	mgr := createTestManager(t)
+	updated = New(mgr)
+	require.NoError(t, mgr.SetMaintenance("", true))
+	updated, _ = updated.Update(tickMsg(time.Now()))
+	assert.True(t, updated.(Model).Snapshot().Maintenance)
+}
+
+// press sends keys to m, returning the updated model
+func press(m Model, keys ...string) Model {
+	for _, key := range keys {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		if key == "enter" {
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		}
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+	}
+	return m
+}
+
+func TestModel_Translated(t *testing.T) {
+	require.NoError(t, i18n.SetLocale("es"))
+	t.Cleanup(func() { i18n.SetLocale(i18n.English) })
+
+	snapshot := goldenSnapshot()
+	snapshot.Servers["filesystem"].Tools = []server.Tool{{Name: "read_file", Description: "Read a file"}}
+	model := New(apitest.NewManager()).WithSnapshot(snapshot).WithRefreshTime(snapshot.Now)
+	model.width, model.height = 120, 40
+
+	// Every message a view renders must be in the catalog
+	untranslated := i18n.Untranslated()
+	var views []Model
+	approvals := model
+	approvals.approvals = []grpc.Approval{{Server: "filesystem", Tool: "write_file", Deadline: snapshot.Now.Add(time.Minute)}}
+	views = append(views, approvals)
+	for _, state := range []ViewState{ViewDetail, ViewOverview, ViewSessions} {
+		m := model
+		m.viewState, m.selectedServer = state, "filesystem"
+		views = append(views, m)
+	}
+	lost := snapshot
+	lost.State = grpc.StateReconnecting
+	views = append(views, model.WithSnapshot(lost))
+
+	// and the status messages of the detail view and its explorer
+	detail := views[1]
+	detail.disk = &grpc.ServerDisk{Server: "filesystem", Dirs: []grpc.DiskDir{{Kind: grpc.DiskCache, Size: 1 << 20}}}
+	stopped := detail
+	stopped.selectedServer = "browser"
+	explorer := press(detail, "e")
+	explorer.explorer.Clipboard = func(string) error { return fmt.Errorf("no clipboard") }
+	views = append(views,
+		detail, press(detail, "o"), press(detail, "p"), press(detail, "s"), press(stopped, "o"), press(stopped, "e"),
+		press(explorer, "/", "z", "z", "enter"), press(explorer, "y"))
+	explorer.explorer.Clipboard = func(string) error { return nil }
+	views = append(views, press(explorer, "Y"))
+
+	for _, m := range views {
+		m.View()
+	}
+	assert.Empty(t, untranslated(), "messages missing from locales/es.json")
+}
@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// filterTag returns the servers of names labeled with tag, all of them if
+// tag is empty
+func filterTag(servers map[string]*server.Server, names []string, tag string) []string {
+	if tag == "" {
+		return names
+	}
+	var tagged []string
+	for _, name := range names {
+		if srv, exists := servers[name]; exists && srv.HasTag(tag) {
+			tagged = append(tagged, name)
+		}
+	}
+	return tagged
+}
+
+// cycleTag moves the tag filter of the list to the next of the servers'
+// tags in alphabetical order, and back to none after the last
+func (m Model) cycleTag() Model {
+	servers, order := m.snapshot.Servers, m.snapshot.Order
+	var tags []string
+	for _, srv := range servers {
+		for _, tag := range srv.Tags {
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	slices.Sort(tags)
+
+	next := ""
+	if i := slices.Index(tags, m.tagFilter); i+1 < len(tags) {
+		next = tags[i+1]
+	}
+	m.tagFilter = next
+	m.cursor = 0
+	m.setServers(servers, order)
+	return m
+}
+
+// withTags prefixes a description with the tags of its server
+func withTags(description string, tags []string) string {
+	if len(tags) == 0 {
+		return description
+	}
+	return "[" + strings.Join(tags, ", ") + "] " + description
+}
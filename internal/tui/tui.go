@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 	"os/exec"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -12,17 +13,41 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/tartavull/mcp-manager/internal/api"
+	"github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/i18n"
 	"github.com/tartavull/mcp-manager/internal/server"
+	"github.com/tartavull/mcp-manager/internal/transcript"
+	"github.com/tartavull/mcp-manager/internal/tui/jsontree"
+	"github.com/tartavull/mcp-manager/internal/uptime"
 )
 
 // ViewState represents the current view
 type ViewState int
 
 const (
-	ViewList   ViewState = iota // List of servers
-	ViewDetail                  // Detailed view of a single server
+	ViewList     ViewState = iota // List of servers
+	ViewDetail                    // Detailed view of a single server
+	ViewExplorer                  // JSON explorer opened from the detail view
+	ViewOverview                  // Aggregate stats of all servers
+	ViewSessions                  // Session transcripts of the proxies' clients
 )
 
+// String returns the name of the view, e.g. for logs
+func (v ViewState) String() string {
+	switch v {
+	case ViewDetail:
+		return "detail"
+	case ViewExplorer:
+		return "explorer"
+	case ViewOverview:
+		return "overview"
+	case ViewSessions:
+		return "sessions"
+	default:
+		return "list"
+	}
+}
+
 // Styles for the TUI
 var (
 	titleStyle = lipgloss.NewStyle().
@@ -54,6 +79,12 @@ var (
 	stoppingStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#FAB387")) // Orange for stopping
 
+	unhealthyStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#EBA0AC")) // Red for failing health checks
+
+	maintenanceStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#89B4FA")) // Blue for servers in maintenance
+
 	disabledStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#6C7086"))
 
@@ -72,11 +103,16 @@ var (
 // Message types
 type tickMsg time.Time
 type refreshMsg struct{}
+type changedMsg struct{}
 
 // Model represents the TUI state
 type Model struct {
 	manager        api.ManagerInterface
-	servers        []string // Ordered list of server names
+	servers        []string            // Ordered list of server names
+	rows           []string            // Rows of the list, servers and groups of instances
+	tagFilter      string              // Tag the list is filtered by, empty for all servers
+	groups         map[string][]string // Instances of each group by its name
+	expanded       map[string]bool     // Groups showing their instances
 	cursor         int
 	width          int
 	height         int
@@ -86,78 +122,206 @@ type Model struct {
 	viewState      ViewState
 	selectedServer string
 	scrollOffset   int
+	statusMessage  string           // Result of the last detail view action
+	imagePreview   string           // Rendered preview of the selected server's latest image
+	disk           *grpc.ServerDisk // Disk used by the selected server, nil if not tracked
+	runtime        *grpc.Runtime    // What the selected server runs as, nil if not described
+	notifications  string           // Notification level of the selected server, "" if not known
+	explorer       jsontree.Model
+	explorerTitle  string
+	explorerReturn ViewState // View the explorer was opened from
+
+	statuses    map[string]server.Status // Server statuses at the last refresh
+	events      []overviewEvent          // Recent changes, oldest first
+	daemonStart time.Time                // Zero unless connected to a daemon
+	validation  *grpc.Validation         // Nil unless the manager validates its config
+	providers   []grpc.ProviderGroup     // Generic gateway tools, nil without a gateway
+	uptime      []uptime.Stats           // Availability over the last week, nil unless tracked
+	approvals   []grpc.Approval          // Tool calls waiting for approval, oldest first
+
+	sessions      []transcript.Session // Session transcripts, latest first
+	sessionCursor int
+	sessionFilter string // Request or run ID the sessions are filtered by
+	filtering     bool   // The session filter is being typed
+
+	windowTitle bool   // Show the server summary in the terminal title
+	title       string // Terminal title last set
+
+	snapshot Snapshot // State of the manager the views render
+	debug    bool     // Log how long frames take to render
+
+	focusEvents bool // The terminal reports focus events
+	blurred     bool // The terminal lost focus
 }
 
 // New creates a new TUI model
 func New(mgr api.ManagerInterface) Model {
 	servers, order, _ := mgr.GetServers()
-	serverNames := getOrderedServerNames(servers, order)
 
-	return Model{
+	m := Model{
 		manager:     mgr,
-		servers:     serverNames,
 		cursor:      0,
 		lastRefresh: time.Now(),
 	}
+	m.setServers(servers, order)
+	m.recordEvents(servers)
+	m.captureServers(servers, order)
+	m.refreshUptime()
+	m.refreshValidation()
+	m.refreshProviders()
+	m.refreshAvailability()
+	return m
+}
+
+// WithWindowTitle returns the model keeping the terminal title set to
+// the running and total server counts, e.g. "MCP 3/10"
+func (m Model) WithWindowTitle() Model {
+	m.windowTitle = true
+	return m
 }
 
 // Init initializes the model
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(
-		tickCmd(),
+		tickCmd(m.tickInterval()),
 		tea.EnterAltScreen,
+		m.windowTitleCmd(m.snapshot.Servers),
+		m.fetchApprovals(),
 	)
 }
 
 // Update handles messages and updates the model
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if focused, ok := focusEvent(msg); ok {
+		// Ticks slow down while unfocused, catch up when focused again
+		m.blurred = !focused
+		if focused {
+			return m, refreshCmd()
+		}
+		return m, nil
+	}
+
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		m.explorer.Width, m.explorer.Height = explorerSize(m.width, m.height)
 		return m, nil
 
+	case resumedMsg:
+		return m, refreshCmd()
+
 	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlZ {
+			return m.suspend()
+		}
+		if m.snapshot.Lost() {
+			return m.handleReconnectKeys(msg)
+		}
 		switch m.viewState {
 		case ViewList:
 			return m.handleListKeys(msg)
 		case ViewDetail:
 			return m.handleDetailKeys(msg)
+		case ViewExplorer:
+			return m.handleExplorerKeys(msg)
+		case ViewOverview:
+			return m.handleOverviewKeys(msg)
+		case ViewSessions:
+			return m.handleSessionsKeys(msg)
 		}
 
 	case tickMsg:
+		// Daemons report changes as they happen, standalone managers are
+		// cheap to poll
+		if m.snapshot.Daemon {
+			m.captureLiveness()
+		} else {
+			m.capture()
+		}
+
+		// Calls waiting for approval can't wait for the next refresh.
+		// Daemons report them as they are parked.
+		cmds := []tea.Cmd{tickCmd(m.tickInterval())}
+		if !m.snapshot.Daemon {
+			cmds = append(cmds, m.fetchApprovals())
+		}
+
 		// Auto-refresh every 5 seconds
 		if time.Since(m.lastRefresh) > 5*time.Second {
 			m.lastRefresh = time.Now()
-			m.manager.UpdateToolCounts()
-			return m, tea.Batch(tickCmd(), refreshCmd())
+			cmds = append(cmds, updateToolCountsCmd(m.manager), refreshCmd())
+			if m.snapshot.Daemon {
+				cmds = append(cmds, m.fetchApprovals())
+			}
+			if m.viewState == ViewOverview {
+				m.refreshUptime() // Notices daemon restarts
+				m.refreshValidation()
+				m.refreshProviders()
+				m.refreshAvailability()
+			}
+			if m.viewState == ViewSessions {
+				m.refreshSessions()
+			}
+			if m.viewState == ViewDetail {
+				cmds = append(cmds, m.fetchDisk())
+				m.refreshRuntime()
+				m.refreshNotifications()
+			}
+		}
+		return m, tea.Batch(cmds...)
+
+	case approvalsMsg:
+		if msg.err == nil {
+			m.approvals = msg.approvals
+		}
+		return m, nil
+
+	case diskMsg:
+		if msg.server == m.selectedServer {
+			m.disk = msg.disk
+		}
+		return m, nil
+
+	case blobOpenedMsg:
+		if msg.err != nil {
+			m.statusMessage = msg.err.Error()
+		} else {
+			m.statusMessage = i18n.T("Opened %s", msg.path)
 		}
-		return m, tickCmd()
+		return m, nil
+
+	case imagePreviewMsg:
+		if msg.server != m.selectedServer || m.viewState != ViewDetail {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.statusMessage = msg.err.Error()
+		} else {
+			m.statusMessage = ""
+			m.imagePreview = msg.rendered
+		}
+		return m, nil
+
+	case changedMsg:
+		// Calls parked for approval are among the changes
+		_, titleCmd := m.refreshServers()
+		return m, tea.Batch(titleCmd, m.fetchApprovals())
 
 	case refreshMsg:
 		// Update server list and refresh data
-		servers, order, _ := m.manager.GetServers()
-		m.servers = getOrderedServerNames(servers, order)
+		servers, titleCmd := m.refreshServers()
 		m.refreshing = false
 		m.lastRefresh = time.Now()
 
-		// Ensure cursor is within bounds
-		if m.cursor >= len(m.servers) {
-			m.cursor = len(m.servers) - 1
-		}
-		if m.cursor < 0 {
-			m.cursor = 0
-		}
-
 		// Continue refreshing if operations might still be in progress
-		servers, _, _ = m.manager.GetServers()
 		if hasOperationsInProgress(servers) {
-			return m, tea.Tick(200*time.Millisecond, func(t time.Time) tea.Msg {
+			return m, tea.Batch(titleCmd, tea.Tick(200*time.Millisecond, func(t time.Time) tea.Msg {
 				return refreshMsg{}
-			})
+			}))
 		}
 
-		return m, nil
+		return m, titleCmd
 	}
 
 	return m, nil
@@ -175,16 +339,27 @@ func (m Model) handleListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 
 	case "down", "j":
-		if m.cursor < len(m.servers)-1 {
+		if m.cursor < len(m.rows)-1 {
 			m.cursor++
 		}
 
+	case "right", "l":
+		// Show the instances of the selected group
+		return m.setExpanded(true), nil
+
+	case "left", "h":
+		// Hide the instances of the selected group
+		return m.setExpanded(false), nil
+
 	case " ":
-		// Toggle selected server (start if stopped, stop if running)
-		if m.cursor < len(m.servers) {
-			serverName := m.servers[m.cursor]
-			srv, err := m.manager.GetServer(serverName)
-			if err == nil && srv != nil {
+		// Toggle selected server (start if stopped, stop if running), or
+		// all instances of the selected group
+		if _, instances, ok := m.selectedRow(); ok && instances != nil {
+			return m.toggleGroup(instances)
+		}
+		if m.cursor < len(m.rows) {
+			serverName := m.rows[m.cursor]
+			if srv, exists := m.snapshot.Servers[serverName]; exists {
 				m.refreshing = true
 				if srv.IsRunning() {
 					// Stop the server
@@ -208,64 +383,121 @@ func (m Model) handleListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					tea.Tick(500*time.Millisecond, func(t time.Time) tea.Msg {
 						return refreshMsg{}
 					}),
-					tickCmd(),
 				)
 			}
 		}
 
 	case "enter":
+		// Expand or collapse the selected group
+		if row, instances, ok := m.selectedRow(); ok && instances != nil {
+			return m.setExpanded(!m.expanded[row]), nil
+		}
+
 		// View server details
-		if m.cursor < len(m.servers) {
-			m.selectedServer = m.servers[m.cursor]
+		if m.cursor < len(m.rows) {
+			m.selectedServer = m.rows[m.cursor]
 			m.viewState = ViewDetail
 			m.scrollOffset = 0
+			m.statusMessage = ""
+			m.imagePreview = ""
+			m.disk = nil
+			m.refreshRuntime()
+			m.refreshNotifications()
+			return m, m.fetchDisk()
+		}
+
+	case "m":
+		// Toggle maintenance mode of the selected server or group
+		if _, instances, ok := m.selectedRow(); ok && instances != nil {
+			m.toggleGroupMaintenance(instances)
+			return m, refreshCmd()
+		}
+		if m.cursor < len(m.rows) {
+			if srv, exists := m.snapshot.Servers[m.rows[m.cursor]]; exists {
+				if err := m.manager.SetMaintenance(srv.Name, !srv.Maintenance); err != nil {
+					log.Printf("Failed to toggle maintenance for %s: %v", srv.Name, err)
+				}
+			}
 		}
+		return m, refreshCmd()
+
+	case "M":
+		// Toggle daemon-wide maintenance mode
+		if err := m.manager.SetMaintenance("", !m.snapshot.Maintenance); err != nil {
+			log.Printf("Failed to toggle maintenance mode: %v", err)
+		}
+		return m, refreshCmd()
 
 	case "r":
 		// Manual refresh
 		m.refreshing = true
-		return m, tea.Batch(refreshCmd(), tickCmd())
+		return m, refreshCmd()
+
+	case "a":
+		// Approve the oldest call waiting for approval
+		return m.decideApproval(true)
+
+	case "x":
+		// Reject the oldest call waiting for approval
+		return m.decideApproval(false)
+
+	case "s":
+		return m.openSessions()
+
+	case "tab":
+		m.viewState = ViewOverview
 
 	case "c":
-		// Open config file in default editor
-		configPath, _ := m.manager.GetConfigPath()
-
-		// Try to determine the default editor
-		editor := os.Getenv("EDITOR")
-		if editor == "" {
-			editor = os.Getenv("VISUAL")
-		}
-		if editor == "" {
-			// Default to common editors
-			if _, err := exec.LookPath("code"); err == nil {
-				editor = "code"
-			} else if _, err := exec.LookPath("vim"); err == nil {
-				editor = "vim"
-			} else if _, err := exec.LookPath("nano"); err == nil {
-				editor = "nano"
-			} else {
-				editor = "vi" // Most systems have vi
-			}
-		}
+		return m, m.openConfig()
 
-		// Open the editor
-		cmd := exec.Command(editor, configPath)
-		cmd.Stdin = os.Stdin
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
+	case "d":
+		return m.duplicateServer()
 
-		// Suspend the TUI temporarily
-		return m, tea.ExecProcess(cmd, func(err error) tea.Msg {
-			if err != nil {
-				log.Printf("Failed to open editor: %v", err)
-			}
-			return refreshMsg{}
-		})
+	case "t":
+		// Filter the list by the next tag
+		return m.cycleTag(), nil
 	}
 
 	return m, nil
 }
 
+// openConfig opens mcp.json in the user's editor, suspending the TUI
+func (m Model) openConfig() tea.Cmd {
+	configPath, _ := m.manager.GetConfigPath()
+
+	// Try to determine the default editor
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = os.Getenv("VISUAL")
+	}
+	if editor == "" {
+		// Default to common editors
+		if _, err := exec.LookPath("code"); err == nil {
+			editor = "code"
+		} else if _, err := exec.LookPath("vim"); err == nil {
+			editor = "vim"
+		} else if _, err := exec.LookPath("nano"); err == nil {
+			editor = "nano"
+		} else {
+			editor = "vi" // Most systems have vi
+		}
+	}
+
+	// Open the editor
+	cmd := exec.Command(editor, configPath)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	// Suspend the TUI temporarily
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		if err != nil {
+			log.Printf("Failed to open editor: %v", err)
+		}
+		return refreshMsg{}
+	})
+}
+
 // handleDetailKeys handles key events in the detail view
 func (m Model) handleDetailKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
@@ -285,20 +517,107 @@ func (m Model) handleDetailKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "down", "j":
 		// Scroll down (we'll calculate max scroll in View)
 		m.scrollOffset++
+
+	case "o":
+		// Open the latest blob returned by the server in the system viewer
+		srv, exists := m.snapshot.Servers[m.selectedServer]
+		if !exists || !srv.IsRunning() {
+			m.statusMessage = i18n.T("Server is not running")
+			return m, nil
+		}
+		m.statusMessage = i18n.T("Opening latest blob...")
+		return m, openLatestBlobCmd(srv.Port)
+
+	case "e":
+		// Explore the server's tools and their input schemas
+		srv, exists := m.snapshot.Servers[m.selectedServer]
+		if !exists || len(srv.Tools) == 0 {
+			m.statusMessage = i18n.T("No tools to explore")
+			return m, nil
+		}
+		m.openExplorer(srv.Name+" tools", srv.Tools)
+
+	case "p":
+		// Toggle the preview of the latest image returned by the server
+		if m.imagePreview != "" {
+			m.imagePreview = ""
+			return m, nil
+		}
+		srv, exists := m.snapshot.Servers[m.selectedServer]
+		if !exists || !srv.IsRunning() {
+			m.statusMessage = i18n.T("Server is not running")
+			return m, nil
+		}
+		m.statusMessage = i18n.T("Loading image preview...")
+		return m, previewLatestImageCmd(srv.Name, srv.Port, max(m.width-4, 1), max(m.height/3, 1))
+
+	case "s":
+		// Snapshot the server's browser profile
+		return m.snapshotProfile()
+
+	case "r":
+		// Reset the server's browser profile, once stopped
+		return m.resetProfile()
+
+	case "n":
+		// Cycle the level of the server's desktop notifications
+		return m.cycleNotifications()
 	}
 
 	return m, nil
 }
 
-// View renders the TUI
-func (m Model) View() string {
+// openExplorer shows v in the JSON explorer
+func (m *Model) openExplorer(title string, v interface{}) {
+	m.explorer = jsontree.New(v)
+	m.explorer.Width, m.explorer.Height = explorerSize(m.width, m.height)
+	m.explorerTitle = title
+	m.explorerReturn = m.viewState
+	m.viewState = ViewExplorer
+}
+
+// explorerSize returns the size of the JSON explorer for a window size,
+// leaving room for the title and help
+func explorerSize(width, height int) (int, int) {
+	return width, max(height-6, 3)
+}
+
+// handleExplorerKeys handles key events in the JSON explorer
+func (m Model) handleExplorerKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if !m.explorer.Searching() {
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "esc", "backspace":
+			// Go back to the view the explorer was opened from
+			m.viewState = m.explorerReturn
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.explorer, cmd = m.explorer.Update(msg)
+	return m, cmd
+}
+
+// render renders the TUI
+func (m Model) render() string {
 	if m.width == 0 {
 		return "Loading..."
 	}
+	if m.snapshot.Lost() {
+		return m.viewReconnect()
+	}
 
 	switch m.viewState {
 	case ViewDetail:
 		return m.viewDetail()
+	case ViewExplorer:
+		return m.viewExplorer()
+	case ViewOverview:
+		return m.viewOverview()
+	case ViewSessions:
+		return m.viewSessions()
 	default:
 		return m.viewList()
 	}
@@ -309,7 +628,7 @@ func (m Model) viewList() string {
 	var b strings.Builder
 
 	// Get running server count to determine title color
-	servers, _, _ := m.manager.GetServers()
+	servers := m.snapshot.Servers
 	runningCount := countRunningServers(servers)
 
 	// Dynamic title style based on server status
@@ -327,16 +646,25 @@ func (m Model) viewList() string {
 		Padding(0, 1)
 
 	// Title and status on same line
-	title := dynamicTitleStyle.Render("🚀 MCP Server Manager")
+	title := dynamicTitleStyle.Render(i18n.T("🚀 MCP Server Manager"))
 
 	// Status info
-	statusInfo := fmt.Sprintf("Servers: %d | Running: %d | Last refresh: %s",
+	statusInfo := i18n.T("Servers: %d | Running: %d | Last refresh: %s",
 		len(servers),
 		runningCount,
 		m.lastRefresh.Format("15:04:05"),
 	)
+	if m.snapshot.Maintenance {
+		statusInfo = i18n.T("🔧 MAINTENANCE") + " | " + statusInfo
+	}
+	if m.snapshot.Daemon {
+		statusInfo = connectionIndicator(m.snapshot) + " | " + statusInfo
+	}
+	if m.tagFilter != "" {
+		statusInfo = i18n.T("Tag: %s", m.tagFilter) + " | " + statusInfo
+	}
 	if m.refreshing {
-		statusInfo += " | Refreshing..."
+		statusInfo += " | " + i18n.T("Refreshing...")
 	}
 
 	// Create the full title line with status on the right
@@ -367,12 +695,19 @@ func (m Model) viewList() string {
 
 	// Table header
 	header := fmt.Sprintf("%-20s %-6s %-10s %-8s %-8s %s",
-		"Name", "Port", "Status", "Tools", "PID", "Description")
+		i18n.T("Name"), i18n.T("Port"), i18n.T("Status"), i18n.T("Tools"), i18n.T("PID"), i18n.T("Description"))
 	b.WriteString(headerStyle.Render(header))
 	b.WriteString("\n")
 
 	// Server rows
-	for i, serverName := range m.servers {
+	for i, serverName := range m.rows {
+		if instances, ok := m.groups[serverName]; ok {
+			row, status := groupRow(serverName, instances, servers, m.expanded[serverName])
+			b.WriteString(styleRow(row, status, i == m.cursor, false, false))
+			b.WriteString("\n")
+			continue
+		}
+
 		srv, exists := servers[serverName]
 		if !exists {
 			continue
@@ -389,11 +724,20 @@ func (m Model) viewList() string {
 			toolCount = strconv.Itoa(srv.ToolCount)
 		}
 
-		// Truncate long server names
+		// Truncate long server names, keeping the maintenance marker, and
+		// indent the instances of groups
 		displayName := srv.Name
-		if len(displayName) > 19 {
-			displayName = displayName[:17] + ".."
+		if srv.Group != "" {
+			displayName = "  " + displayName
+		}
+		suffix := ""
+		if srv.Maintenance {
+			suffix = " [M]"
 		}
+		if len(displayName)+len(suffix) > 19 {
+			displayName = displayName[:17-len(suffix)] + ".."
+		}
+		displayName += suffix
 
 		// Calculate available width for description
 		// Format: name(20) + port(6) + status(10) + tools(8) + pid(8) + spaces(5) = 57
@@ -403,66 +747,56 @@ func (m Model) viewList() string {
 		}
 
 		// Truncate description based on available width
-		description := srv.Description
+		description := withTags(srv.Description, srv.Tags)
 		if len(description) > descWidth {
 			description = description[:descWidth-3] + "..."
 		}
 
+		// Running servers failing their custom health check are flagged
+		status := string(srv.Status)
+		unhealthy := srv.IsRunning() && srv.Health == server.HealthUnhealthy
+		if unhealthy {
+			status = string(server.HealthUnhealthy)
+		}
+
 		row := fmt.Sprintf("%-20s %-6d %-10s %-8s %-8s %s",
 			displayName,
 			srv.Port,
-			string(srv.Status),
+			status,
 			toolCount,
 			pid,
 			description,
 		)
 
-		// Apply styling based on status and selection
-		if i == m.cursor {
-			// Selected row - use different styles based on status
-			switch srv.Status {
-			case server.StatusRunning:
-				// Show running servers in green even when selected
-				row = runningStyle.Bold(true).Background(lipgloss.Color("#1E5E3E")).Render(row)
-			case server.StatusStarting:
-				// Show starting servers in yellow even when selected
-				row = startingStyle.Bold(true).Background(lipgloss.Color("#5E5E1E")).Render(row)
-			case server.StatusStopping:
-				// Show stopping servers in orange even when selected
-				row = stoppingStyle.Bold(true).Background(lipgloss.Color("#5E3E1E")).Render(row)
-			default:
-				// Show stopped servers in pink when selected
-				row = selectedStyle.Render(row)
-			}
-		} else {
-			// Not selected - apply status-based styling
-			switch srv.Status {
-			case server.StatusRunning:
-				row = runningStyle.Render(row)
-			case server.StatusStarting:
-				row = startingStyle.Render(row)
-			case server.StatusStopping:
-				row = stoppingStyle.Render(row)
-			default:
-				row = stoppedStyle.Render(row)
-			}
-		}
-
-		b.WriteString(row)
+		b.WriteString(styleRow(row, srv.Status, i == m.cursor, srv.Maintenance, unhealthy))
 		b.WriteString("\n")
 	}
 
 	// Add spacing before help box
 	b.WriteString("\n\n")
 
+	if queue := m.approvalQueue(); queue != "" {
+		b.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, queue))
+		b.WriteString("\n")
+	}
+
 	// Key bindings help at the bottom
 	keys := []string{
-		"↑/↓ Navigate",
-		"Space Toggle",
-		"Enter Details",
-		"R Refresh",
-		"C Open Config",
-		"Q Quit",
+		i18n.T("↑/↓ Navigate"),
+		i18n.T("Space Toggle"),
+		i18n.T("Enter Details"),
+		i18n.T("M Maintenance"),
+		i18n.T("Shift+M All"),
+		i18n.T("R Refresh"),
+		i18n.T("Tab Overview"),
+		i18n.T("S Sessions"),
+		i18n.T("T Tag Filter"),
+		i18n.T("D Duplicate"),
+		i18n.T("C Open Config"),
+		i18n.T("Q Quit"),
+	}
+	if len(m.groups) > 0 {
+		keys = slices.Insert(keys, 1, i18n.T("←/→ Expand"))
 	}
 
 	keyHelp := lipgloss.NewStyle().
@@ -480,13 +814,51 @@ func (m Model) viewList() string {
 	return b.String()
 }
 
+// styleRow colors a row of the server list by status, highlighting it if
+// selected
+func styleRow(row string, status server.Status, selected, maintenance, unhealthy bool) string {
+	if selected {
+		// Selected row - use different styles based on status
+		switch status {
+		case server.StatusRunning:
+			// Show running servers in green even when selected
+			return runningStyle.Bold(true).Background(lipgloss.Color("#1E5E3E")).Render(row)
+		case server.StatusStarting:
+			// Show starting servers in yellow even when selected
+			return startingStyle.Bold(true).Background(lipgloss.Color("#5E5E1E")).Render(row)
+		case server.StatusStopping:
+			// Show stopping servers in orange even when selected
+			return stoppingStyle.Bold(true).Background(lipgloss.Color("#5E3E1E")).Render(row)
+		default:
+			// Show stopped servers in pink when selected
+			return selectedStyle.Render(row)
+		}
+	}
+
+	// Not selected - apply status-based styling
+	switch {
+	case maintenance:
+		return maintenanceStyle.Render(row)
+	case unhealthy:
+		return unhealthyStyle.Render(row)
+	case status == server.StatusRunning:
+		return runningStyle.Render(row)
+	case status == server.StatusStarting:
+		return startingStyle.Render(row)
+	case status == server.StatusStopping:
+		return stoppingStyle.Render(row)
+	default:
+		return stoppedStyle.Render(row)
+	}
+}
+
 // viewDetail renders the detailed server view
 func (m Model) viewDetail() string {
 	var b strings.Builder
 
-	srv, err := m.manager.GetServer(m.selectedServer)
-	if err != nil {
-		return "Server not found"
+	srv, exists := m.snapshot.Servers[m.selectedServer]
+	if !exists {
+		return i18n.T("Server not found")
 	}
 
 	// Title bar
@@ -501,14 +873,14 @@ func (m Model) viewDetail() string {
 		Background(titleBg).
 		Padding(0, 1)
 
-	title := dynamicTitleStyle.Render(fmt.Sprintf("🔍 %s Details", srv.Name))
+	title := dynamicTitleStyle.Render(i18n.T("🔍 %s Details", srv.Name))
 	b.WriteString(title)
 	b.WriteString("\n\n")
 
 	// Server information
 	infoStyle := lipgloss.NewStyle().Padding(0, 2)
 
-	info := fmt.Sprintf(
+	info := i18n.T(
 		"Status: %s\nPort: %d\nPID: %s\nCommand: %s\nDescription: %s\n",
 		srv.Status,
 		srv.Port,
@@ -522,57 +894,99 @@ func (m Model) viewDetail() string {
 		srv.Description,
 	)
 
+	if srv.Host != "" {
+		info += i18n.T("Host: %s", srv.Host) + "\n"
+	}
+	info += i18n.T("Restart Policy: %s, %d restarts", srv.RestartPolicy, srv.Restarts) + "\n"
+	if srv.Maintenance {
+		info += i18n.T("Maintenance: on (automatic restarts and alerts suppressed)") + "\n"
+	}
+	if srv.BrowserProfile != "" {
+		info += i18n.T("Browser Profile: %s", srv.BrowserProfile) + "\n"
+	}
+	info += m.diskLine()
+	info += m.notificationLine()
+
+	if srv.IsRunning() && srv.Health != server.HealthUnknown {
+		info += i18n.T("Health: %s", srv.Health)
+		if srv.HealthMessage != "" {
+			info += fmt.Sprintf(" (%s)", srv.HealthMessage)
+		}
+		info += "\n"
+	}
+
 	b.WriteString(infoStyle.Render(info))
 	b.WriteString("\n")
 
+	if m.statusMessage != "" {
+		b.WriteString(helpStyle.Render("  " + m.statusMessage))
+		b.WriteString("\n")
+	}
+
+	if m.imagePreview != "" {
+		b.WriteString(m.imagePreview)
+		b.WriteString("\n\n")
+	}
+
+	runtime := m.runtimeSection()
+	b.WriteString(runtime)
+
 	// Tools section
-	toolsHeader := headerStyle.Render(fmt.Sprintf(" Available Tools (%d) ", srv.ToolCount))
+	toolsHeader := headerStyle.Render(" " + i18n.T("Available Tools (%d)", srv.ToolCount) + " ")
 	b.WriteString(toolsHeader)
 	b.WriteString("\n\n")
 
 	// Calculate visible area for tools
-	headerLines := 10 // Approximate lines used by header and info
-	footerLines := 5  // Lines for help
+	headerLines := 10 + strings.Count(runtime, "\n") // Approximate lines used by header, info and runtime
+	footerLines := 5                                 // Lines for help
 	availableLines := m.height - headerLines - footerLines
 
 	if srv.IsRunning() && len(srv.Tools) > 0 {
 		toolsStyle := lipgloss.NewStyle().Padding(0, 2)
 
-		// Apply scrolling
-		visibleTools := srv.Tools
-		maxScroll := len(srv.Tools) - availableLines + 2
-		if maxScroll > 0 && m.scrollOffset > maxScroll {
-			m.scrollOffset = maxScroll
+		// Descriptions are rendered from markdown next to the names
+		toolLines := make([]string, len(srv.Tools))
+		for i, tool := range srv.Tools {
+			name := toolNameStyle.Render(tool.Name) + " "
+			descWidth := 0
+			if m.width > 0 {
+				descWidth = max(m.width-4-lipgloss.Width(name), 20)
+			}
+			toolLines[i] = lipgloss.JoinHorizontal(lipgloss.Top, name, renderMarkdown(tool.Description, descWidth, toolDescStyle))
 		}
+		height := func(toolLine string) int { return strings.Count(toolLine, "\n") + 1 }
 
-		startIdx := m.scrollOffset
-		endIdx := startIdx + availableLines - 2
-		if endIdx > len(srv.Tools) {
-			endIdx = len(srv.Tools)
+		// Apply scrolling, down to the tools that fill the last page
+		maxScroll, lastPage := len(srv.Tools)-1, height(toolLines[len(srv.Tools)-1])
+		for maxScroll > 0 && lastPage+height(toolLines[maxScroll-1]) <= availableLines-2 {
+			maxScroll--
+			lastPage += height(toolLines[maxScroll])
 		}
-		if startIdx < len(srv.Tools) {
-			visibleTools = srv.Tools[startIdx:endIdx]
+		if m.scrollOffset > maxScroll {
+			m.scrollOffset = maxScroll
 		}
 
-		for _, tool := range visibleTools {
-			toolLine := fmt.Sprintf("%s %s",
-				toolNameStyle.Render(tool.Name),
-				toolDescStyle.Render(tool.Description),
-			)
+		startIdx, endIdx, usedLines := m.scrollOffset, m.scrollOffset, 0
+		for _, toolLine := range toolLines[startIdx:] {
+			if endIdx > startIdx && usedLines+height(toolLine) > availableLines-2 {
+				break
+			}
 			b.WriteString(toolsStyle.Render(toolLine))
 			b.WriteString("\n")
+			endIdx++
+			usedLines += height(toolLine)
 		}
 
 		// Show scroll indicator if needed
-		if len(srv.Tools) > availableLines-2 {
-			scrollInfo := fmt.Sprintf("\n  Showing %d-%d of %d tools (↑/↓ to scroll)",
+		if startIdx > 0 || endIdx < len(srv.Tools) {
+			scrollInfo := "\n  " + i18n.T("Showing %d-%d of %d tools (↑/↓ to scroll)",
 				startIdx+1, endIdx, len(srv.Tools))
 			b.WriteString(helpStyle.Render(scrollInfo))
 		}
 	} else if srv.IsRunning() {
-		b.WriteString(helpStyle.Render("  No tools available"))
+		b.WriteString(helpStyle.Render("  " + i18n.T("No tools available")))
 	} else {
-		b.WriteString(helpStyle.Render("  Server is not running"))
+		b.WriteString(helpStyle.Render("  " + i18n.T("Server is not running")))
 	}
 
 	// Fill remaining space
@@ -584,10 +998,19 @@ func (m Model) viewDetail() string {
 
 	// Help at the bottom
 	keys := []string{
-		"ESC/Backspace Return to list",
-		"↑/↓ Scroll",
-		"Q Quit",
+		i18n.T("ESC/Backspace Return to list"),
+		i18n.T("↑/↓ Scroll"),
+		i18n.T("O Open latest blob"),
+		i18n.T("P Preview image"),
+		i18n.T("E Explore tools"),
 	}
+	if srv.BrowserProfile != "" {
+		keys = append(keys, i18n.T("S Snapshot profile"), i18n.T("R Reset profile"))
+	}
+	if m.notifications != "" {
+		keys = append(keys, i18n.T("N Notifications"))
+	}
+	keys = append(keys, i18n.T("Q Quit"))
 
 	keyHelp := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#585B70")).
@@ -603,15 +1026,80 @@ func (m Model) viewDetail() string {
 	return b.String()
 }
 
+// viewExplorer renders the JSON explorer
+func (m Model) viewExplorer() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render("🔎 " + m.explorerTitle))
+	b.WriteString("\n\n")
+	b.WriteString(m.explorer.View())
+	b.WriteString("\n")
+
+	keys := []string{
+		"↑/↓ Move",
+		"Enter Toggle",
+		"←/→ Collapse/Expand",
+		"E/C All",
+		"/ Search",
+		"N Next",
+		"Y Copy value",
+		"Shift+Y Copy path",
+		"ESC Back",
+	}
+
+	keyHelp := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#585B70")).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#585B70")).
+		Padding(0, 1).
+		Render(strings.Join(keys, " • "))
+
+	b.WriteString(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, keyHelp))
+
+	return b.String()
+}
+
 // Helper functions
 
-// tickCmd returns a command that sends a tick message
-func tickCmd() tea.Cmd {
-	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+// connectionIndicator describes the connection to the daemon
+func connectionIndicator(s Snapshot) string {
+	if s.Connected {
+		return "● " + i18n.T("Daemon")
+	}
+	since := s.Now.Sub(s.LastHeartbeat).Truncate(time.Second)
+	return "○ " + i18n.T("Daemon: no heartbeat for %s", since)
+}
+
+// windowTitleCmd sets the terminal title to the server summary if it
+// changed since the last refresh
+func (m *Model) windowTitleCmd(servers map[string]*server.Server) tea.Cmd {
+	if !m.windowTitle {
+		return nil
+	}
+	title := "MCP " + server.Summarize(servers).String()
+	if title == m.title {
+		return nil
+	}
+	m.title = title
+	return tea.SetWindowTitle(title)
+}
+
+// tickCmd returns a command that sends a tick message after d
+func tickCmd(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(t time.Time) tea.Msg {
 		return tickMsg(t)
 	})
 }
 
+// updateToolCountsCmd returns a command that asks mgr to refresh the tools
+// of its servers, in the background as daemons may be slow to answer
+func updateToolCountsCmd(mgr api.ManagerInterface) tea.Cmd {
+	return func() tea.Msg {
+		mgr.UpdateToolCounts()
+		return nil
+	}
+}
+
 // refreshCmd returns a command that sends a refresh message
 func refreshCmd() tea.Cmd {
 	return func() tea.Msg {
@@ -1,28 +1,24 @@
 package tui
 
 import (
+	"fmt"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"github.com/tartavull/mcp-manager/internal/manager"
+	"github.com/tartavull/mcp-manager/apitest"
+	"github.com/tartavull/mcp-manager/internal/grpc"
 	"github.com/tartavull/mcp-manager/internal/server"
+	"github.com/tartavull/mcp-manager/internal/transcript"
 )
 
-func createTestManager(t *testing.T) *manager.Manager {
-	tempDir := t.TempDir()
-
-	// Set up config directory for test
-	originalHome := os.Getenv("HOME")
-	defer os.Setenv("HOME", originalHome)
-	os.Setenv("HOME", tempDir)
-
-	// Create a fresh manager
-	mgr, err := manager.New()
-	require.NoError(t, err)
+func createTestManager(t *testing.T) *apitest.Manager {
+	mgr := apitest.NewManager()
 
 	// Add some test servers to the manager
 	mgr.AddServer("test1", "echo test1", 4001, "Test server 1")
@@ -38,6 +34,17 @@ func createTestManager(t *testing.T) *manager.Manager {
 	return mgr
 }
 
+// createTestDaemon returns a daemon with the servers of createTestManager
+func createTestDaemon(t *testing.T) *apitest.Daemon {
+	servers, order, err := createTestManager(t).GetServers()
+	require.NoError(t, err)
+	daemon := apitest.NewDaemon()
+	for _, name := range order {
+		require.NoError(t, daemon.Add(servers[name]))
+	}
+	return daemon
+}
+
 func TestNew(t *testing.T) {
 	mgr := createTestManager(t)
 	model := New(mgr)
@@ -46,7 +53,6 @@ func TestNew(t *testing.T) {
 	assert.Equal(t, 0, model.cursor)
 	assert.Equal(t, 0, model.width)
 	assert.Equal(t, 0, model.height)
-	// Should contain both default servers and test servers
 	assert.GreaterOrEqual(t, len(model.servers), 3)
 	assert.Contains(t, model.servers, "test1")
 	assert.Contains(t, model.servers, "test2")
@@ -304,7 +310,7 @@ func TestCountRunningServers(t *testing.T) {
 }
 
 func TestTickCmd(t *testing.T) {
-	cmd := tickCmd()
+	cmd := tickCmd(time.Millisecond)
 	assert.NotNil(t, cmd)
 
 	// Execute the command
@@ -385,3 +391,454 @@ func TestModel_View_TruncatedDescription(t *testing.T) {
 	assert.Contains(t, view, "...")                      // Should have ellipsis somewhere
 	assert.NotContains(t, view, "prevent layout issues") // This part should be truncated
 }
+
+func TestModel_Explorer(t *testing.T) {
+	mgr := createTestManager(t)
+	srv, _ := mgr.GetServer("test1")
+	srv.Tools = []server.Tool{{Name: "search", Description: "Search the web"}}
+
+	model := New(mgr)
+	model.width, model.height = 120, 40
+	model.viewState = ViewDetail
+	model.selectedServer = "test1"
+
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	m := updated.(Model)
+	assert.Equal(t, ViewExplorer, m.viewState)
+	assert.Contains(t, m.View(), "test1 tools")
+
+	// Esc returns to the detail view unless a search is being typed
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Equal(t, ViewExplorer, updated.(Model).viewState)
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Equal(t, ViewDetail, updated.(Model).viewState)
+}
+
+func TestModel_Maintenance(t *testing.T) {
+	mgr := createTestManager(t)
+	model := New(mgr)
+	model.width, model.height = 120, 40
+	model.cursor = indexOf(model.servers, "test1")
+
+	// Toggles show once the refresh they return runs
+	press := func(m tea.Model, key rune) tea.Model {
+		updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{key}})
+		require.NotNil(t, cmd)
+		updated, _ = updated.Update(cmd())
+		return updated
+	}
+
+	updated := press(model, 'm')
+	srv, _ := mgr.GetServer("test1")
+	assert.True(t, srv.Maintenance)
+	assert.Contains(t, updated.View(), "test1 [M]")
+
+	updated = press(updated, 'M')
+	assert.Contains(t, updated.View(), "MAINTENANCE")
+
+	updated = press(press(updated, 'M'), 'm')
+	assert.False(t, srv.Maintenance)
+	assert.NotContains(t, updated.View(), "MAINTENANCE")
+}
+
+func TestModel_DiskUsage(t *testing.T) {
+	daemon := createTestDaemon(t)
+	daemon.SetDisk("test2", []grpc.DiskDir{
+		{Kind: grpc.DiskCache, Label: "npx", Path: "/npx", Size: 3 << 20},
+		{Kind: grpc.DiskData, Label: "profiles", Path: "/profiles", Size: 512},
+	})
+	model := New(daemon)
+	model.width, model.height = 120, 40
+	model.cursor = indexOf(model.servers, "test2")
+
+	// The disk is measured in the background
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.NotContains(t, updated.View(), "Disk:")
+	updated, _ = updated.Update(cmd())
+	assert.Contains(t, updated.View(), "Disk: 3.0 MiB (cache 3.0 MiB, data 512 B)")
+}
+
+func indexOf(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestConnectionIndicator(t *testing.T) {
+	now := time.Now()
+	assert.Equal(t, "● Daemon", connectionIndicator(Snapshot{Daemon: true, Connected: true, LastHeartbeat: now, Now: now}))
+
+	stale := Snapshot{Daemon: true, LastHeartbeat: now.Add(-20 * time.Second), Now: now}
+	assert.Equal(t, "○ Daemon: no heartbeat for 20s", connectionIndicator(stale))
+}
+
+func TestModel_Overview(t *testing.T) {
+	mgr := createTestManager(t)
+	model := New(mgr)
+	model.width, model.height = 120, 40
+
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyTab})
+	assert.Equal(t, ViewOverview, updated.(Model).viewState)
+
+	view := updated.View()
+	assert.Contains(t, view, "MCP Overview")
+	assert.Contains(t, view, "1 running")
+	assert.Contains(t, view, "10 tools")
+	assert.Contains(t, view, "Mode: standalone")
+	assert.Contains(t, view, "No requests served yet")
+	assert.Contains(t, view, "No status changes")
+
+	// Status changes between refreshes appear in the events feed
+	srv, _ := mgr.GetServer("test2")
+	srv.SetStatus(server.StatusError)
+	updated, _ = updated.Update(refreshMsg{})
+	assert.Contains(t, updated.View(), "test2: stopped → error")
+
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyTab})
+	assert.Equal(t, ViewList, updated.(Model).viewState)
+}
+
+func TestTopServers(t *testing.T) {
+	servers := map[string]*server.Server{
+		"quiet": {Name: "quiet"},
+		"busy":  {Name: "busy", Requests: 100},
+		"some":  {Name: "some", Requests: 10},
+	}
+
+	lines := strings.Split(topServers(servers), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "busy")
+	assert.Contains(t, lines[0], strings.Repeat("█", trafficBarWidth))
+	assert.Contains(t, lines[1], "some")
+}
+
+func TestValidationReport(t *testing.T) {
+	report := validationReport(&grpc.Validation{
+		Problems: []string{"gateway requires a port"},
+		Conflicts: []grpc.ToolConflict{{
+			Tool:    "search",
+			Servers: []string{"github", "gitlab", "jira"},
+			Renamed: map[string]string{"gitlab": "gitlab_search"},
+			Hidden:  []string{"jira"},
+		}},
+	})
+
+	lines := strings.Split(report, "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "gateway requires a port")
+	assert.Contains(t, lines[1], "search: kept by github, gitlab as gitlab_search, jira hidden")
+}
+
+func TestProvidersReport(t *testing.T) {
+	report := providersReport([]grpc.ProviderGroup{
+		{
+			Tool:     "search.web",
+			Selected: "google",
+			Providers: []grpc.ProviderStats{
+				{Server: "bing", Running: true},
+				{Server: "brave", Running: true, Calls: 5, ErrorRate: 0.6, Latency: 300 * time.Millisecond},
+				{Server: "google", Running: true, Calls: 3, ErrorRate: 0.1, Latency: 120 * time.Millisecond},
+			},
+		},
+		{Tool: "fetch", Pinned: "jina", Providers: []grpc.ProviderStats{{Server: "jina"}}},
+	})
+
+	lines := strings.Split(report, "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "search.web → google (auto)")
+	assert.Contains(t, lines[0], "bing untried")
+	assert.Contains(t, lines[0], "brave 300ms, 60% errors")
+	assert.Contains(t, lines[0], "google 120ms, 10% errors")
+	assert.Contains(t, lines[1], "fetch → no provider running")
+	assert.Contains(t, lines[1], "jina stopped")
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	description := "## Search\n\nSearches the **web** with `query`,\nsee [docs](https://example.com).\n\n" +
+		"- first result\n* second, a * b\n  1. nested\n\n```\nsearch(\"x\")\n```"
+
+	assert.Equal(t, strings.Join([]string{
+		"Search",
+		"",
+		"Searches the web with query, see docs.",
+		"",
+		"• first result",
+		"• second, a * b",
+		"  1. nested",
+		"",
+		`  search("x")`,
+	}, "\n"), renderMarkdown(description, 0, lipgloss.NewStyle()))
+
+	// Wrapped lines of list items are indented under their text
+	assert.Equal(t, "• one two\n  three", renderMarkdown("- one two three", 10, lipgloss.NewStyle()))
+	assert.Equal(t, "snake_case and italic text", inlineMarkdown("snake_case and _italic_ text", lipgloss.NewStyle()))
+	assert.Equal(t, "", renderMarkdown("", 40, lipgloss.NewStyle()))
+}
+
+func TestModel_WindowTitle(t *testing.T) {
+	mgr := createTestManager(t)
+	model := New(mgr).WithWindowTitle()
+
+	updated, cmd := model.Update(refreshMsg{})
+	require.NotNil(t, cmd)
+	assert.Contains(t, fmt.Sprint(cmd()), "MCP 1/")
+
+	// The title is only set again when the summary changes
+	_, cmd = updated.Update(refreshMsg{})
+	assert.Nil(t, cmd)
+}
+
+func TestModel_Approvals(t *testing.T) {
+	daemon := createTestDaemon(t)
+	daemon.ParkCall(grpc.Approval{ID: "ab12", Server: "slack", Tool: "post_message", Arguments: `{"text":"hi"}`, Deadline: time.Now().Add(time.Minute)})
+	daemon.ParkCall(grpc.Approval{ID: "cd34", Server: "github", Tool: "create_issue", Deadline: time.Now().Add(time.Minute)})
+	model := New(daemon)
+	model.width = 120
+	updated := run(model, model.Init())
+
+	// The oldest call is shown with the number waiting
+	view := updated.View()
+	assert.Contains(t, view, "2 call(s) waiting for approval")
+	assert.Contains(t, view, `slack: post_message {"text":"hi"}`)
+
+	updated, cmd := updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	updated, cmd = run(updated, cmd).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	updated = run(updated, cmd)
+	var decided []string
+	for len(decided) < 2 {
+		if change := <-daemon.ApprovalChanges(); change.State != grpc.ApprovalPending {
+			decided = append(decided, change.ID+" "+change.State)
+		}
+	}
+	assert.Equal(t, []string{"ab12 approved", "cd34 rejected"}, decided)
+	assert.NotContains(t, updated.View(), "waiting for approval")
+}
+
+func TestModel_ApprovalsInBackground(t *testing.T) {
+	daemon := createTestDaemon(t)
+	model := New(daemon)
+	model.width = 120
+	daemon.SetReachable(false)
+	daemon.ParkCall(grpc.Approval{ID: "ab12", Server: "slack", Tool: "post_message", Deadline: time.Now().Add(time.Minute)})
+
+	// Ticks don't ask the daemon, which reports the calls it parks
+	updated, _ := model.Update(tickMsg(time.Now()))
+	assert.NotContains(t, updated.View(), "waiting for approval")
+
+	daemon.SetReachable(true)
+	updated, cmd := updated.Update(Changed())
+	assert.Contains(t, run(updated, cmd).View(), "1 call(s) waiting for approval")
+}
+
+// run runs cmd and the commands it batches, except ticks, passing the
+// messages they return to m
+func run(m tea.Model, cmd tea.Cmd) tea.Model {
+	if cmd == nil {
+		return m
+	}
+	switch msg := cmd().(type) {
+	case nil, tickMsg:
+	case tea.BatchMsg:
+		for _, cmd := range msg {
+			m = run(m, cmd)
+		}
+	default:
+		m, _ = m.Update(msg)
+	}
+	return m
+}
+
+func TestModel_Sessions(t *testing.T) {
+	daemon := createTestDaemon(t)
+	daemon.Record("key:1a2b3c4d", transcript.Entry{Time: time.Now(), Server: "github", Method: "tools/call", Tool: "create_issue", Arguments: `{"title":"bug"}`})
+	model := New(daemon)
+	model.width, model.height = 120, 40
+	key := func(m tea.Model, k string) tea.Model {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		switch k {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		}
+		updated, _ := m.Update(msg)
+		return updated
+	}
+
+	updated := key(model, "s")
+	assert.Equal(t, ViewSessions, updated.(Model).viewState)
+	assert.Contains(t, updated.View(), "key:1a2b3c4d")
+
+	// The explorer returns to the sessions
+	updated = key(updated, "enter")
+	assert.Equal(t, ViewExplorer, updated.(Model).viewState)
+	assert.Contains(t, updated.(Model).explorerTitle, "key:1a2b3c4d")
+	updated = key(updated, "esc")
+	assert.Equal(t, ViewSessions, updated.(Model).viewState)
+
+	// Sessions are exported to the temp directory
+	t.Setenv("TMPDIR", t.TempDir())
+	updated = key(updated, "e")
+	message := updated.(Model).statusMessage
+	require.True(t, strings.HasPrefix(message, "Exported to "), message)
+	data, err := os.ReadFile(strings.TrimPrefix(message, "Exported to "))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "create_issue")
+
+	updated = key(updated, "E")
+	assert.True(t, strings.HasSuffix(updated.(Model).statusMessage, ".json"))
+
+	// Filters keep the calls given a request or run ID
+	daemon.Record("key:5e6f7a8b", transcript.Entry{Time: time.Now(), Server: "slack", Method: "tools/call", Tool: "post_message",
+		Correlation: transcript.Correlation{RunID: "run-42"}})
+	updated = key(updated, "/")
+	for _, r := range "run-42" {
+		updated = key(updated, string(r))
+	}
+	assert.Contains(t, updated.View(), "Request or run ID: run-42█")
+	updated = key(updated, "enter")
+	assert.Len(t, updated.(Model).sessions, 1)
+	assert.Contains(t, updated.View(), "key:5e6f7a8b")
+	assert.NotContains(t, updated.View(), "key:1a2b3c4d")
+	updated = key(updated, "enter")
+	assert.Contains(t, updated.(Model).explorerTitle, "key:5e6f7a8b")
+	updated = key(updated, "esc")
+
+	// Escape clears the filter, then leaves
+	updated = key(updated, "esc")
+	assert.Len(t, updated.(Model).sessions, 2)
+	updated = key(updated, "esc")
+	assert.Equal(t, ViewList, updated.(Model).viewState)
+}
+
+func TestModel_Groups(t *testing.T) {
+	mgr := createTestManager(t)
+	for i := 1; i <= 3; i++ {
+		srv := server.NewServer(fmt.Sprintf("web-%d", i), "web-mcp", 4010+i, "Web")
+		srv.Group = "web"
+		require.NoError(t, mgr.Add(srv))
+	}
+	model := New(mgr)
+	model.width, model.height = 120, 40
+
+	// Instances are collapsed into their group
+	assert.Equal(t, []string{"test1", "test2", "test3", "web"}, model.rows)
+	assert.Contains(t, model.View(), "▸ web (3)")
+	assert.Contains(t, model.View(), "←/→ Expand")
+	assert.NotContains(t, model.View(), "web-1")
+
+	model.cursor = indexOf(model.rows, "web")
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(Model)
+	assert.Equal(t, []string{"test1", "test2", "test3", "web", "web-1", "web-2", "web-3"}, model.rows)
+	assert.Contains(t, model.View(), "▾ web (3)")
+	assert.Contains(t, model.View(), "  web-2")
+
+	// Collapsing from an instance moves the cursor to its group
+	model.cursor = indexOf(model.rows, "web-2")
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	model = updated.(Model)
+	assert.Equal(t, []string{"test1", "test2", "test3", "web"}, model.rows)
+	assert.Equal(t, indexOf(model.rows, "web"), model.cursor)
+
+	// Maintenance applies to all instances
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+	model = updated.(Model)
+	for _, name := range []string{"web-1", "web-2", "web-3"} {
+		srv, _ := mgr.GetServer(name)
+		assert.True(t, srv.Maintenance, name)
+	}
+
+	// Space starts all instances when none runs
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	model = updated.(Model)
+	assert.Eventually(t, func() bool {
+		servers, _, _ := mgr.GetServers()
+		return groupRunning(servers, model.groups["web"]) == 3
+	}, time.Second, 10*time.Millisecond)
+
+	servers, _, _ := mgr.GetServers()
+	row, status := groupRow("web", model.groups["web"], servers, false)
+	assert.Equal(t, server.StatusRunning, status)
+	assert.Contains(t, row, "4011")
+}
+
+func TestModel_Notifications(t *testing.T) {
+	daemon := createTestDaemon(t)
+	model := New(daemon)
+	model.width, model.height = 120, 40
+	require.NotEmpty(t, model.rows)
+	name := model.rows[0]
+	level := func() string {
+		_, preferences, err := daemon.Notifications()
+		require.NoError(t, err)
+		for _, preference := range preferences {
+			if preference.Server == name {
+				return preference.Level
+			}
+		}
+		return ""
+	}
+
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Contains(t, updated.View(), "Notifications: crash")
+
+	// Levels cycle from the quietest to all and back to mute
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	assert.Equal(t, "tools", level())
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	assert.Equal(t, "mute", level())
+	assert.Contains(t, updated.View(), "Notifications: mute")
+}
+
+func TestModel_Duplicate(t *testing.T) {
+	daemon := createTestDaemon(t)
+	srv := server.NewServer("web-1", "web-mcp", 4011, "Web")
+	srv.Group = "web"
+	require.NoError(t, daemon.Add(srv))
+	model := New(daemon)
+	model.width, model.height = 120, 40
+	assert.Contains(t, model.View(), "D Duplicate")
+
+	// The copy is opened in the editor
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	assert.NotNil(t, cmd)
+	assert.Equal(t, []string{"clone test1"}, daemon.Calls())
+
+	// Groups duplicate their template
+	model = updated.(Model)
+	model.cursor = indexOf(model.rows, "web")
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	assert.Equal(t, []string{"clone test1", "clone web"}, daemon.Calls())
+}
+
+func TestModel_TagFilter(t *testing.T) {
+	mgr := createTestManager(t)
+	for name, tags := range map[string][]string{"test1": {"browser"}, "test3": {"browser", "heavy"}} {
+		srv, err := mgr.GetServer(name)
+		require.NoError(t, err)
+		srv.Tags = tags
+	}
+	model := New(mgr)
+	model.width, model.height = 160, 40
+	assert.Contains(t, model.View(), "[browser, heavy] Test server 3")
+
+	// Tags cycle in alphabetical order, then back to all servers
+	tag := func() Model {
+		updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+		return updated.(Model)
+	}
+	model = tag()
+	assert.Equal(t, []string{"test1", "test3"}, model.rows)
+	assert.Contains(t, model.View(), "Tag: browser")
+	model = tag()
+	assert.Equal(t, []string{"test3"}, model.rows)
+	model = tag()
+	assert.Equal(t, []string{"test1", "test2", "test3"}, model.rows)
+	assert.NotContains(t, model.View(), "Tag:")
+}
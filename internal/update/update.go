@@ -0,0 +1,253 @@
+package update
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultAPIURL is the GitHub API endpoint used to look up releases
+	DefaultAPIURL = "https://api.github.com"
+
+	// DefaultRepository is the repository that publishes release binaries
+	DefaultRepository = "tartavull/mcp-manager"
+
+	// ChecksumsFile is the name of the checksum asset attached to every release
+	ChecksumsFile = "checksums.txt"
+)
+
+// Release represents a published GitHub release
+type Release struct {
+	TagName string  `json:"tag_name"`
+	Assets  []Asset `json:"assets"`
+}
+
+// Asset represents a downloadable file attached to a release
+type Asset struct {
+	Name        string `json:"name"`
+	DownloadURL string `json:"browser_download_url"`
+}
+
+// FindAsset returns the asset with the given name
+func (r *Release) FindAsset(name string) (*Asset, error) {
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i], nil
+		}
+	}
+	return nil, fmt.Errorf("release %s has no asset '%s'", r.TagName, name)
+}
+
+// Updater downloads and installs release binaries
+type Updater struct {
+	APIURL     string
+	Repository string
+	GOOS       string
+	GOARCH     string
+	client     *http.Client
+}
+
+// New creates an updater for the current platform
+func New() *Updater {
+	return &Updater{
+		APIURL:     DefaultAPIURL,
+		Repository: DefaultRepository,
+		GOOS:       runtime.GOOS,
+		GOARCH:     runtime.GOARCH,
+		client:     &http.Client{Timeout: 5 * time.Minute},
+	}
+}
+
+// Suffix returns the platform suffix used in release binary names
+func (u *Updater) Suffix() string {
+	return fmt.Sprintf("%s-%s", u.GOOS, u.GOARCH)
+}
+
+// ArchiveName returns the name of the release archive for the platform
+func (u *Updater) ArchiveName() string {
+	return fmt.Sprintf("mcp-manager-%s.tar.gz", u.Suffix())
+}
+
+// LatestRelease fetches metadata for the latest published release
+func (u *Updater) LatestRelease() (*Release, error) {
+	url := fmt.Sprintf("%s/repos/%s/releases/latest", strings.TrimRight(u.APIURL, "/"), u.Repository)
+
+	data, err := u.get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch latest release: %w", err)
+	}
+
+	var release Release
+	if err := json.Unmarshal(data, &release); err != nil {
+		return nil, fmt.Errorf("failed to parse release metadata: %w", err)
+	}
+
+	return &release, nil
+}
+
+// Download fetches the platform archive of a release and verifies it
+// against the release checksums. The checksums come from the same release
+// and aren't signed, so this catches corrupted downloads, not a tampered
+// release. It returns the binaries contained in the archive keyed by their
+// base name without platform suffix.
+func (u *Updater) Download(release *Release) (map[string][]byte, error) {
+	if u.GOOS == "windows" {
+		return nil, fmt.Errorf("self-update is not supported on windows")
+	}
+
+	archiveName := u.ArchiveName()
+	archive, err := release.FindAsset(archiveName)
+	if err != nil {
+		return nil, err
+	}
+	checksums, err := release.FindAsset(ChecksumsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	checksumData, err := u.get(checksums.DownloadURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download checksums: %w", err)
+	}
+
+	archiveData, err := u.get(archive.DownloadURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", archiveName, err)
+	}
+
+	if err := VerifyChecksum(archiveData, checksumData, archiveName); err != nil {
+		return nil, err
+	}
+
+	return ExtractBinaries(archiveData, u.Suffix())
+}
+
+// get performs a GET request and returns the response body
+func (u *Updater) get(url string) ([]byte, error) {
+	resp, err := u.client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d from %s", resp.StatusCode, url)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// VerifyChecksum checks data against the sha256sum-formatted checksums
+// file entry for name
+func VerifyChecksum(data, checksums []byte, name string) error {
+	expected := ""
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			expected = strings.ToLower(fields[0])
+			break
+		}
+	}
+	if expected == "" {
+		return fmt.Errorf("no checksum found for %s", name)
+	}
+
+	sum := sha256.Sum256(data)
+	actual := hex.EncodeToString(sum[:])
+	if actual != expected {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", name, expected, actual)
+	}
+
+	return nil
+}
+
+// ExtractBinaries reads a gzipped tarball and returns the files named
+// "<binary>-<suffix>" keyed by binary name
+func ExtractBinaries(archive []byte, suffix string) (map[string][]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer gz.Close()
+
+	binaries := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		base := filepath.Base(header.Name)
+		name := strings.TrimSuffix(base, "-"+suffix)
+		if name == base {
+			continue
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from archive: %w", base, err)
+		}
+		binaries[name] = data
+	}
+
+	if len(binaries) == 0 {
+		return nil, fmt.Errorf("archive contains no binaries for %s", suffix)
+	}
+
+	return binaries, nil
+}
+
+// ReplaceExecutable atomically replaces the file at path with data. The new
+// file is written next to the target and renamed over it, so a failure never
+// leaves a partially written binary behind.
+func ReplaceExecutable(path string, data []byte) error {
+	mode := os.FileMode(0755)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".new-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", tmpPath, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", tmpPath, err)
+	}
+	if err := os.Chmod(tmpPath, mode); err != nil {
+		return fmt.Errorf("failed to set permissions on %s: %w", tmpPath, err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package update
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildArchive creates a gzipped tarball containing the given files
+func buildArchive(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0755,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func checksumLine(data []byte, name string) string {
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf("%s  %s\n", hex.EncodeToString(sum[:]), name)
+}
+
+// setupReleaseServer serves a fake GitHub release for linux-amd64
+func setupReleaseServer(t *testing.T, archive []byte, checksums string) *Updater {
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	mux.HandleFunc("/repos/tartavull/mcp-manager/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Release{
+			TagName: "v1.2.3",
+			Assets: []Asset{
+				{Name: "mcp-manager-linux-amd64.tar.gz", DownloadURL: srv.URL + "/download/archive"},
+				{Name: ChecksumsFile, DownloadURL: srv.URL + "/download/checksums"},
+			},
+		})
+	})
+	mux.HandleFunc("/download/archive", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(archive)
+	})
+	mux.HandleFunc("/download/checksums", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(checksums))
+	})
+
+	u := New()
+	u.APIURL = srv.URL
+	u.GOOS = "linux"
+	u.GOARCH = "amd64"
+	return u
+}
+
+func TestUpdater_ArchiveName(t *testing.T) {
+	u := New()
+	u.GOOS = "darwin"
+	u.GOARCH = "arm64"
+
+	assert.Equal(t, "darwin-arm64", u.Suffix())
+	assert.Equal(t, "mcp-manager-darwin-arm64.tar.gz", u.ArchiveName())
+}
+
+func TestUpdater_LatestReleaseAndDownload(t *testing.T) {
+	archive := buildArchive(t, map[string]string{
+		"mcp-manager-linux-amd64": "manager-binary",
+		"mcp-daemon-linux-amd64":  "daemon-binary",
+	})
+	u := setupReleaseServer(t, archive, checksumLine(archive, "mcp-manager-linux-amd64.tar.gz"))
+
+	release, err := u.LatestRelease()
+	require.NoError(t, err)
+	assert.Equal(t, "v1.2.3", release.TagName)
+
+	binaries, err := u.Download(release)
+	require.NoError(t, err)
+	assert.Equal(t, "manager-binary", string(binaries["mcp-manager"]))
+	assert.Equal(t, "daemon-binary", string(binaries["mcp-daemon"]))
+}
+
+func TestUpdater_Download_ChecksumMismatch(t *testing.T) {
+	archive := buildArchive(t, map[string]string{
+		"mcp-manager-linux-amd64": "manager-binary",
+	})
+	u := setupReleaseServer(t, archive, checksumLine([]byte("tampered"), "mcp-manager-linux-amd64.tar.gz"))
+
+	release, err := u.LatestRelease()
+	require.NoError(t, err)
+
+	_, err = u.Download(release)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "checksum mismatch")
+}
+
+func TestUpdater_Download_MissingAsset(t *testing.T) {
+	u := New()
+	u.GOOS = "linux"
+	u.GOARCH = "riscv64"
+
+	_, err := u.Download(&Release{TagName: "v1.0.0"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "mcp-manager-linux-riscv64.tar.gz")
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	data := []byte("hello")
+	checksums := checksumLine([]byte("other"), "other.tar.gz") + checksumLine(data, "target.tar.gz")
+
+	assert.NoError(t, VerifyChecksum(data, []byte(checksums), "target.tar.gz"))
+	assert.Error(t, VerifyChecksum([]byte("bye"), []byte(checksums), "target.tar.gz"))
+	assert.Error(t, VerifyChecksum(data, []byte(checksums), "missing.tar.gz"))
+}
+
+func TestReplaceExecutable(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "mcp-manager")
+	require.NoError(t, os.WriteFile(path, []byte("old"), 0750))
+
+	require.NoError(t, ReplaceExecutable(path, []byte("new")))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(data))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0750), info.Mode().Perm())
+
+	// No temporary files should be left behind
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
@@ -0,0 +1,303 @@
+// Package uptime derives the availability of servers from their status
+// changes: the time they ran and were down per day, their outages and the
+// mean time to repair them, persisted so reports outlive the event journal
+package uptime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	pb "github.com/tartavull/mcp-manager/internal/grpc/pb"
+)
+
+const (
+	// Retention is how many days of availability are kept
+	Retention = 90
+
+	// flushInterval is how often the time of servers that keep their
+	// status is written, so a daemon killed outright loses little of it
+	flushInterval = time.Minute
+
+	dateLayout = "2006-01-02"
+)
+
+// Day is the availability of a server during a calendar day. Time stopped
+// by hand or while the daemon wasn't running counts as neither up nor down.
+type Day struct {
+	Up       float64 `json:"up"`       // Seconds running
+	Down     float64 `json:"down"`     // Seconds in the error state
+	Outages  int     `json:"outages"`  // Times the server entered the error state
+	Repaired int     `json:"repaired"` // Outages that ended this day
+	Repair   float64 `json:"repair"`   // Seconds the outages that ended this day lasted
+}
+
+// Stats are the availability of a server over a window of days
+type Stats struct {
+	Server  string
+	Up      time.Duration // Time running
+	Down    time.Duration // Time in the error state
+	Outages int           // Times the server entered the error state
+	MTTR    time.Duration // Mean time from entering the error state to leaving it, zero without repairs
+}
+
+// Uptime returns the percentage of the time the server was up or down that
+// it was up, false if it was neither during the window
+func (s Stats) Uptime() (float64, bool) {
+	total := s.Up + s.Down
+	if total == 0 {
+		return 0, false
+	}
+	return 100 * float64(s.Up) / float64(total), true
+}
+
+// state is what a server's status counts as
+type state int
+
+const (
+	idle state = iota // Stopped, starting or stopping
+	up
+	down
+)
+
+// current is the state a server has been in since a time
+type current struct {
+	state     state
+	since     time.Time // Start of the time not yet added to the days
+	downSince time.Time // Start of the outage while down
+}
+
+// Tracker records the availability of servers from SERVER_STATUS events
+// and persists it in a file
+type Tracker struct {
+	path string
+
+	mu      sync.Mutex
+	days    map[string]map[string]*Day // By server and date
+	current map[string]*current
+
+	now func() time.Time // Replaceable for tests
+}
+
+// Open opens the availability persisted at path, starting empty if the
+// file doesn't exist yet
+func Open(path string) (*Tracker, error) {
+	t := &Tracker{
+		path:    path,
+		days:    make(map[string]map[string]*Day),
+		current: make(map[string]*current),
+		now:     time.Now,
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return t, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read uptime: %w", err)
+	}
+	if err := json.Unmarshal(data, &t.days); err != nil {
+		return nil, fmt.Errorf("failed to parse uptime %s: %w", path, err)
+	}
+	return t, nil
+}
+
+// Export records the status changes among events
+func (t *Tracker) Export(event *pb.Event) {
+	change := event.GetServerStatus()
+	if event.Type != pb.EventType_SERVER_STATUS || change == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.observe(change.ServerName, stateOf(change.NewStatus), t.now())
+	if err := t.save(); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+}
+
+// stateOf returns what a status counts as
+func stateOf(status pb.ServerStatus) state {
+	switch status {
+	case pb.ServerStatus_RUNNING:
+		return up
+	case pb.ServerStatus_ERROR:
+		return down
+	default:
+		return idle
+	}
+}
+
+// observe records a server entering a state at a time. Must be called with
+// t.mu held.
+func (t *Tracker) observe(server string, s state, at time.Time) {
+	cur, exists := t.current[server]
+	if !exists {
+		cur = &current{since: at}
+		t.current[server] = cur
+	}
+	t.accrue(server, cur, at)
+
+	switch {
+	case cur.state == down && s != down:
+		day := t.day(server, at)
+		day.Repaired++
+		day.Repair += at.Sub(cur.downSince).Seconds()
+	case cur.state != down && s == down:
+		t.day(server, at).Outages++
+		cur.downSince = at
+	}
+	cur.state = s
+}
+
+// accrue adds the time a server spent in its state until a time to the
+// days it spans. Must be called with t.mu held.
+func (t *Tracker) accrue(server string, cur *current, until time.Time) {
+	for from := cur.since; from.Before(until); {
+		y, m, d := from.Date()
+		end := time.Date(y, m, d+1, 0, 0, 0, 0, from.Location())
+		if end.After(until) {
+			end = until
+		}
+		switch cur.state {
+		case up:
+			t.day(server, from).Up += end.Sub(from).Seconds()
+		case down:
+			t.day(server, from).Down += end.Sub(from).Seconds()
+		}
+		from = end
+	}
+	if until.After(cur.since) {
+		cur.since = until
+	}
+}
+
+// day returns the availability of a server on the date of a time. Must be
+// called with t.mu held.
+func (t *Tracker) day(server string, at time.Time) *Day {
+	days, exists := t.days[server]
+	if !exists {
+		days = make(map[string]*Day)
+		t.days[server] = days
+	}
+	date := at.Format(dateLayout)
+	if days[date] == nil {
+		days[date] = &Day{}
+	}
+	return days[date]
+}
+
+// Report returns the availability of the servers over the last days
+// calendar days, today included, sorted by server
+func (t *Tracker) Report(days int) []Stats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	for server, cur := range t.current {
+		t.accrue(server, cur, now)
+	}
+	y, m, d := now.Date()
+	first := time.Date(y, m, d-days+1, 0, 0, 0, 0, now.Location()).Format(dateLayout)
+
+	var report []Stats
+	for server, byDate := range t.days {
+		var total Day
+		for date, day := range byDate {
+			if date < first {
+				continue
+			}
+			total.Up += day.Up
+			total.Down += day.Down
+			total.Outages += day.Outages
+			total.Repaired += day.Repaired
+			total.Repair += day.Repair
+		}
+		if total.Up+total.Down == 0 && total.Outages == 0 {
+			continue
+		}
+		stats := Stats{Server: server, Up: seconds(total.Up), Down: seconds(total.Down), Outages: total.Outages}
+		if total.Repaired > 0 {
+			stats.MTTR = seconds(total.Repair / float64(total.Repaired))
+		}
+		report = append(report, stats)
+	}
+	sort.Slice(report, func(i, j int) bool { return report[i].Server < report[j].Server })
+	return report
+}
+
+// seconds converts seconds to a duration, rounded to the second
+func seconds(s float64) time.Duration {
+	return (time.Duration(s * float64(time.Second))).Round(time.Second)
+}
+
+// Run writes the time of servers that keep their status every minute
+// until ctx is done
+func (t *Tracker) Run(ctx context.Context) {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := t.Flush(); err != nil {
+				log.Printf("Warning: %v", err)
+			}
+		}
+	}
+}
+
+// Flush adds the time of servers in their current state until now and
+// writes the availability, e.g. before the daemon exits
+func (t *Tracker) Flush() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	for server, cur := range t.current {
+		t.accrue(server, cur, now)
+	}
+	return t.save()
+}
+
+// save writes the availability, dropping days older than Retention. Must
+// be called with t.mu held.
+func (t *Tracker) save() error {
+	now := t.now()
+	y, m, d := now.Date()
+	oldest := time.Date(y, m, d-Retention+1, 0, 0, 0, 0, now.Location()).Format(dateLayout)
+	for server, byDate := range t.days {
+		for date := range byDate {
+			if date < oldest {
+				delete(byDate, date)
+			}
+		}
+		if len(byDate) == 0 {
+			delete(t.days, server)
+		}
+	}
+
+	data, err := json.Marshal(t.days)
+	if err != nil {
+		return fmt.Errorf("failed to save uptime: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(t.path), 0755); err != nil {
+		return fmt.Errorf("failed to save uptime: %w", err)
+	}
+	tmp := t.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to save uptime: %w", err)
+	}
+	if err := os.Rename(tmp, t.path); err != nil {
+		return fmt.Errorf("failed to save uptime: %w", err)
+	}
+	return nil
+}
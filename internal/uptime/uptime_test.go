@@ -0,0 +1,122 @@
+package uptime
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	pb "github.com/tartavull/mcp-manager/internal/grpc/pb"
+)
+
+func statusEvent(name string, status pb.ServerStatus) *pb.Event {
+	return &pb.Event{
+		Type: pb.EventType_SERVER_STATUS,
+		Payload: &pb.Event_ServerStatus{ServerStatus: &pb.ServerStatusEvent{
+			ServerName: name,
+			NewStatus:  status,
+		}},
+	}
+}
+
+// openTest opens a tracker whose clock is set by the returned function
+func openTest(t *testing.T, path string) (*Tracker, func(time.Time)) {
+	tracker, err := Open(path)
+	require.NoError(t, err)
+	var clock time.Time
+	tracker.now = func() time.Time { return clock }
+	return tracker, func(at time.Time) { clock = at }
+}
+
+func TestTracker_Report(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "uptime.json")
+	tracker, setClock := openTest(t, path)
+	day := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	// Up 22h, crashing twice for 10m and 50m, then stopped by hand
+	events := []struct {
+		at     time.Duration
+		status pb.ServerStatus
+	}{
+		{0, pb.ServerStatus_STARTING},
+		{time.Hour, pb.ServerStatus_RUNNING},
+		{10 * time.Hour, pb.ServerStatus_ERROR},
+		{10*time.Hour + 10*time.Minute, pb.ServerStatus_RUNNING},
+		{20 * time.Hour, pb.ServerStatus_ERROR},
+		{20*time.Hour + 50*time.Minute, pb.ServerStatus_STARTING},
+		{21 * time.Hour, pb.ServerStatus_RUNNING},
+		{(24 + 12) * time.Hour, pb.ServerStatus_STOPPED},
+	}
+	for _, e := range events {
+		setClock(day.Add(e.at))
+		tracker.Export(statusEvent("github", e.status))
+	}
+	tracker.Export(&pb.Event{Type: pb.EventType_TOOL_UPDATE})
+
+	setClock(day.Add(48 * time.Hour))
+	report := tracker.Report(1)
+	assert.Empty(t, report, "nothing ran today")
+
+	report = tracker.Report(3)
+	require.Len(t, report, 1)
+	stats := report[0]
+	assert.Equal(t, "github", stats.Server)
+	assert.Equal(t, 33*time.Hour+50*time.Minute, stats.Up)
+	assert.Equal(t, time.Hour, stats.Down)
+	assert.Equal(t, 2, stats.Outages)
+	assert.Equal(t, 30*time.Minute, stats.MTTR)
+	uptime, ok := stats.Uptime()
+	assert.True(t, ok)
+	assert.InDelta(t, 100*(33+50/60.0)/(34+50/60.0), uptime, 0.001)
+
+	// The running time crossing midnight is split between the days
+	setClock(day.Add(36 * time.Hour))
+	report = tracker.Report(1)
+	require.Len(t, report, 1)
+	assert.Equal(t, 12*time.Hour, report[0].Up)
+	assert.Zero(t, report[0].Outages)
+
+	// The days are persisted
+	reopened, setClock := openTest(t, path)
+	setClock(day.Add(48 * time.Hour))
+	assert.Equal(t, tracker.Report(3), reopened.Report(3))
+}
+
+func TestTracker_Flush(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "uptime.json")
+	tracker, setClock := openTest(t, path)
+	start := time.Date(2024, 1, 10, 8, 0, 0, 0, time.UTC)
+
+	setClock(start)
+	tracker.Export(statusEvent("github", pb.ServerStatus_RUNNING))
+	tracker.Export(statusEvent("slack", pb.ServerStatus_ERROR))
+	setClock(start.Add(time.Hour))
+	require.NoError(t, tracker.Flush())
+
+	// The time of servers still running or down is written
+	reopened, setClock := openTest(t, path)
+	setClock(start.Add(2 * time.Hour))
+	report := reopened.Report(1)
+	require.Len(t, report, 2)
+	assert.Equal(t, Stats{Server: "github", Up: time.Hour}, report[0])
+	assert.Equal(t, Stats{Server: "slack", Down: time.Hour, Outages: 1}, report[1])
+	_, ok := Stats{}.Uptime()
+	assert.False(t, ok)
+
+	// Days past the retention are dropped
+	setClock(start.Add(Retention * 24 * time.Hour))
+	require.NoError(t, reopened.Flush())
+	assert.Empty(t, reopened.Report(Retention))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "{}", string(data))
+}
+
+func TestOpen_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "uptime.json")
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0644))
+	_, err := Open(path)
+	assert.ErrorContains(t, err, "failed to parse uptime")
+}
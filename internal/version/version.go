@@ -0,0 +1,10 @@
+package version
+
+// Version is the release version, set at build time with
+// -ldflags "-X github.com/tartavull/mcp-manager/internal/version.Version=v0.1.0"
+var Version = "dev"
+
+// IsRelease returns true if the binary was built from a release tag
+func IsRelease() bool {
+	return Version != "dev" && Version != ""
+}
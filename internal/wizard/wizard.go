@@ -0,0 +1,339 @@
+package wizard
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/tartavull/mcp-manager/internal/catalog"
+	"github.com/tartavull/mcp-manager/internal/config"
+	"github.com/tartavull/mcp-manager/internal/secrets"
+)
+
+// defaultBindAddress keeps the proxies local unless the user opts in
+const defaultBindAddress = "127.0.0.1"
+
+// Result is the outcome of the setup wizard
+type Result struct {
+	Config         *config.MCPConfig
+	InstallService bool
+}
+
+// Wizard interactively builds the initial mcp.json
+type Wizard struct {
+	in  *bufio.Reader
+	out io.Writer
+
+	// ReadSecret reads a secret without echoing it. When nil, secrets are
+	// read as regular input lines.
+	ReadSecret func() (string, error)
+
+	// BasePort is the base port offered, config.MCPBasePort when zero
+	BasePort int
+
+	// Secrets stores the secrets entered, which mcp.json then references
+	// as secret:<server>/<variable>. When nil, mcp.json holds them.
+	Secrets secrets.Store
+
+	// Entries are offered, catalog.Entries when nil
+	Entries []catalog.Entry
+
+	// Policy checks the provenance of the entries selected; nil only warns
+	// about unsigned entries and unpinned packages
+	Policy *catalog.Policy
+}
+
+// New creates a wizard reading answers from in and writing prompts to out
+func New(in io.Reader, out io.Writer) *Wizard {
+	return &Wizard{in: bufio.NewReader(in), out: out}
+}
+
+// Run asks the user which servers to set up and how, and returns the
+// resulting configuration. The configuration is not saved.
+func (w *Wizard) Run() (*Result, error) {
+	fmt.Fprintln(w.out, "Welcome to MCP Manager! Let's set up your MCP servers.")
+	fmt.Fprintln(w.out)
+
+	entries, err := w.selectEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	mcpConfig := &config.MCPConfig{Servers: make(map[string]*config.MCPServerConfig)}
+	for _, entry := range entries {
+		if err := w.checkProvenance(entry); err != nil {
+			fmt.Fprintf(w.out, "\nSkipping %s: %v\n", entry.Name, err)
+			continue
+		}
+		srv, err := w.configureEntry(entry)
+		if err != nil {
+			return nil, err
+		}
+		mcpConfig.Servers[entry.Name] = srv
+		mcpConfig.ServerOrder = append(mcpConfig.ServerOrder, entry.Name)
+	}
+
+	defaultBasePort := w.BasePort
+	if defaultBasePort == 0 {
+		defaultBasePort = config.MCPBasePort
+	}
+	basePort, err := w.askPort("Base port for server proxies", defaultBasePort)
+	if err != nil {
+		return nil, err
+	}
+	for i, name := range mcpConfig.ServerOrder {
+		mcpConfig.Servers[name].Port = basePort + i
+	}
+
+	bindAddress, err := w.askBindAddress()
+	if err != nil {
+		return nil, err
+	}
+	mcpConfig.BindAddress = bindAddress
+
+	installService, err := w.askYesNo("Install the daemon as a service that starts at login?", false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{Config: mcpConfig, InstallService: installService}, nil
+}
+
+// selectEntries lists the catalog and returns the entries the user picked
+func (w *Wizard) selectEntries() ([]*catalog.Entry, error) {
+	fmt.Fprintln(w.out, "Available servers:")
+	for i, entry := range w.entries() {
+		marker := " "
+		if entry.Recommended {
+			marker = "*"
+		}
+		fmt.Fprintf(w.out, "  %2d) %s %-20s %s\n", i+1, marker, entry.Name, entry.Description)
+	}
+	fmt.Fprintln(w.out, "  (* recommended)")
+	fmt.Fprintln(w.out)
+
+	for {
+		answer, err := w.ask("Select servers (e.g. 1,3,5 or 'all', Enter for recommended)", "")
+		if err != nil {
+			return nil, err
+		}
+
+		entries, err := parseSelection(w.entries(), answer)
+		if err == nil {
+			return entries, nil
+		}
+		fmt.Fprintf(w.out, "  %v\n", err)
+	}
+}
+
+// entries returns the entries offered
+func (w *Wizard) entries() []catalog.Entry {
+	if w.Entries == nil {
+		return catalog.Entries
+	}
+	return w.Entries
+}
+
+// parseSelection converts a selection answer into catalog entries
+func parseSelection(available []catalog.Entry, answer string) ([]*catalog.Entry, error) {
+	var names []string
+
+	switch strings.ToLower(answer) {
+	case "":
+		names = catalog.RecommendedIn(available)
+	case "all":
+		for _, entry := range available {
+			names = append(names, entry.Name)
+		}
+	default:
+		for _, field := range strings.Split(answer, ",") {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+			n, err := strconv.Atoi(field)
+			if err != nil || n < 1 || n > len(available) {
+				return nil, fmt.Errorf("invalid selection '%s'", field)
+			}
+			names = append(names, available[n-1].Name)
+		}
+	}
+
+	seen := make(map[string]bool)
+	var entries []*catalog.Entry
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		entry, err := catalog.FindIn(available, name)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no servers selected")
+	}
+	return entries, nil
+}
+
+// checkProvenance returns an error if the policy refuses an entry,
+// printing warnings about what it accepts without verifying
+func (w *Wizard) checkProvenance(entry *catalog.Entry) error {
+	warn := func(warning string) {
+		fmt.Fprintf(w.out, "Warning: %s: %s\n", entry.Name, warning)
+	}
+	var err error
+	if !entry.Signed {
+		err = catalog.ErrUnsigned
+	}
+	if err := w.Policy.Admit(entry.Source, err, warn); err != nil {
+		return err
+	}
+	return w.Policy.CheckCommand(entry.Command, warn)
+}
+
+// configureEntry prompts for the params and secrets of a catalog entry
+func (w *Wizard) configureEntry(entry *catalog.Entry) (*config.MCPServerConfig, error) {
+	if len(entry.Params) == 0 && len(entry.Secrets) == 0 {
+		return &config.MCPServerConfig{Command: entry.Render(nil), Description: entry.Description}, nil
+	}
+
+	fmt.Fprintf(w.out, "\nConfiguring %s:\n", entry.Name)
+
+	values := make(map[string]string)
+	for _, param := range entry.Params {
+		value, err := w.ask("  "+param.Prompt, param.Default)
+		if err != nil {
+			return nil, err
+		}
+		values[param.Name] = value
+	}
+
+	var env map[string]string
+	for _, secret := range entry.Secrets {
+		fmt.Fprintf(w.out, "  %s (%s, Enter to skip): ", secret.Prompt, secret.Env)
+		value, err := w.readSecret()
+		if err != nil {
+			return nil, err
+		}
+		if value == "" {
+			continue
+		}
+		if w.Secrets != nil {
+			name := entry.Name + "/" + secret.Env
+			if err := w.Secrets.Set(name, value); err != nil {
+				return nil, fmt.Errorf("failed to store %s: %w", secret.Env, err)
+			}
+			value = secrets.Ref(name)
+		}
+		if env == nil {
+			env = make(map[string]string)
+		}
+		env[secret.Env] = value
+	}
+
+	return &config.MCPServerConfig{
+		Command:     entry.Render(values),
+		Description: entry.Description,
+		Env:         env,
+	}, nil
+}
+
+// askPort prompts until a valid port number is entered
+func (w *Wizard) askPort(prompt string, defaultPort int) (int, error) {
+	for {
+		answer, err := w.ask(prompt, strconv.Itoa(defaultPort))
+		if err != nil {
+			return 0, err
+		}
+		port, err := strconv.Atoi(answer)
+		if err == nil && port > 0 && port < 65536 {
+			return port, nil
+		}
+		fmt.Fprintf(w.out, "  invalid port '%s'\n", answer)
+	}
+}
+
+// askBindAddress prompts until a valid IP address is entered
+func (w *Wizard) askBindAddress() (string, error) {
+	for {
+		answer, err := w.ask("Bind address for server proxies (0.0.0.0 for all interfaces)", defaultBindAddress)
+		if err != nil {
+			return "", err
+		}
+		if net.ParseIP(answer) != nil {
+			return answer, nil
+		}
+		fmt.Fprintf(w.out, "  invalid address '%s'\n", answer)
+	}
+}
+
+// askYesNo prompts for a yes/no answer
+func (w *Wizard) askYesNo(prompt string, defaultYes bool) (bool, error) {
+	options := "y/N"
+	if defaultYes {
+		options = "Y/n"
+	}
+
+	fmt.Fprintf(w.out, "%s [%s]: ", prompt, options)
+	answer, err := w.readLine()
+	if err != nil {
+		return false, err
+	}
+
+	switch strings.ToLower(answer) {
+	case "":
+		return defaultYes, nil
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// ask prompts for a value, returning defaultValue when the answer is empty
+func (w *Wizard) ask(prompt, defaultValue string) (string, error) {
+	if defaultValue != "" {
+		fmt.Fprintf(w.out, "%s [%s]: ", prompt, defaultValue)
+	} else {
+		fmt.Fprintf(w.out, "%s: ", prompt)
+	}
+
+	answer, err := w.readLine()
+	if err != nil {
+		return "", err
+	}
+	if answer == "" {
+		return defaultValue, nil
+	}
+	return answer, nil
+}
+
+// readSecret reads a secret, without echo when supported
+func (w *Wizard) readSecret() (string, error) {
+	if w.ReadSecret == nil {
+		return w.readLine()
+	}
+
+	value, err := w.ReadSecret()
+	fmt.Fprintln(w.out)
+	if err != nil {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+	return strings.TrimSpace(value), nil
+}
+
+// readLine reads a trimmed line of input
+func (w *Wizard) readLine() (string, error) {
+	line, err := w.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
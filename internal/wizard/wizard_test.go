@@ -0,0 +1,145 @@
+package wizard
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/internal/catalog"
+)
+
+func run(t *testing.T, answers ...string) *Result {
+	t.Helper()
+
+	var out bytes.Buffer
+	w := New(strings.NewReader(strings.Join(answers, "\n")+"\n"), &out)
+	result, err := w.Run()
+	require.NoError(t, err, out.String())
+	return result
+}
+
+func TestRun_Defaults(t *testing.T) {
+	// Recommended servers, default params, ports, bind address, no service
+	result := run(t, "", "", "", "", "")
+
+	assert.Equal(t, catalog.Recommended(), result.Config.ServerOrder)
+	assert.Equal(t, "npx @modelcontextprotocol/server-filesystem@latest /tmp", result.Config.Servers["filesystem"].Command)
+	assert.Equal(t, 4001, result.Config.Servers["playwright"].Port)
+	assert.Equal(t, 4002, result.Config.Servers["filesystem"].Port)
+	assert.Equal(t, "127.0.0.1", result.Config.BindAddress)
+	assert.False(t, result.InstallService)
+}
+
+func TestRun_CustomSelection(t *testing.T) {
+	github := indexOf(t, "github") + 1
+	postgres := indexOf(t, "postgres") + 1
+
+	result := run(t,
+		strings.Join([]string{strconv.Itoa(postgres), strconv.Itoa(github)}, ","),
+		"postgresql://localhost/app", // postgres url
+		"ghp_secret",                 // github token
+		"5000",                       // base port
+		"not-an-ip", "0.0.0.0",       // bind address, retried after invalid input
+		"y", // install service
+	)
+
+	assert.Equal(t, []string{"postgres", "github"}, result.Config.ServerOrder)
+	assert.Equal(t, "npx @modelcontextprotocol/server-postgres@latest postgresql://localhost/app", result.Config.Servers["postgres"].Command)
+	assert.Equal(t, map[string]string{"GITHUB_PERSONAL_ACCESS_TOKEN": "ghp_secret"}, result.Config.Servers["github"].Env)
+	assert.Equal(t, 5000, result.Config.Servers["postgres"].Port)
+	assert.Equal(t, 5001, result.Config.Servers["github"].Port)
+	assert.Equal(t, "0.0.0.0", result.Config.BindAddress)
+	assert.True(t, result.InstallService)
+}
+
+func TestRun_SkippedSecret(t *testing.T) {
+	result := run(t, strconv.Itoa(indexOf(t, "github")+1), "", "", "", "")
+
+	assert.Nil(t, result.Config.Servers["github"].Env)
+}
+
+// memoryStore keeps secrets in memory
+type memoryStore map[string]string
+
+func (s memoryStore) Get(name string) (string, error) { return s[name], nil }
+func (s memoryStore) Set(name, value string) error    { s[name] = value; return nil }
+func (s memoryStore) Delete(name string) error        { delete(s, name); return nil }
+func (s memoryStore) List() ([]string, error)         { return nil, nil }
+func (s memoryStore) Backend() string                 { return "memory" }
+
+func TestRun_SecretsStore(t *testing.T) {
+	store := memoryStore{}
+	answers := []string{strconv.Itoa(indexOf(t, "github") + 1), "ghp_secret", "", "", ""}
+	w := New(strings.NewReader(strings.Join(answers, "\n")+"\n"), &bytes.Buffer{})
+	w.Secrets = store
+	result, err := w.Run()
+	require.NoError(t, err)
+
+	// mcp.json references the secret rather than holding it
+	assert.Equal(t, map[string]string{"GITHUB_PERSONAL_ACCESS_TOKEN": "secret:github/GITHUB_PERSONAL_ACCESS_TOKEN"}, result.Config.Servers["github"].Env)
+	assert.Equal(t, memoryStore{"github/GITHUB_PERSONAL_ACCESS_TOKEN": "ghp_secret"}, store)
+}
+
+func TestRun_EOF(t *testing.T) {
+	w := New(strings.NewReader(""), &bytes.Buffer{})
+	_, err := w.Run()
+	assert.Error(t, err)
+}
+
+func TestParseSelection(t *testing.T) {
+	entries, err := parseSelection(catalog.Entries, "all")
+	require.NoError(t, err)
+	assert.Len(t, entries, len(catalog.Entries))
+
+	entries, err = parseSelection(catalog.Entries, "1, 1 ,2")
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "duplicates should be ignored")
+
+	_, err = parseSelection(catalog.Entries, "0")
+	assert.Error(t, err)
+
+	_, err = parseSelection(catalog.Entries, "abc")
+	assert.Error(t, err)
+}
+
+func indexOf(t *testing.T, name string) int {
+	for i, entry := range catalog.Entries {
+		if entry.Name == name {
+			return i
+		}
+	}
+	t.Fatalf("catalog entry %s not found", name)
+	return -1
+}
+
+func TestRun_Provenance(t *testing.T) {
+	entries := []catalog.Entry{
+		{Name: "pinned", Command: "npx pinned-server@1.0.0", Recommended: true, Signed: true},
+		{Name: "floating", Command: "npx floating-server@latest", Recommended: true, Signed: true},
+		{Name: "unsigned", Command: "npx unsigned-server@1.0.0", Recommended: true, Source: "team.json"},
+	}
+
+	// Without a policy, unverified entries are only warned about
+	var out bytes.Buffer
+	w := New(strings.NewReader("\n\n\n\n\n"), &out)
+	w.Entries = entries
+	result, err := w.Run()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pinned", "floating", "unsigned"}, result.Config.ServerOrder)
+	assert.Contains(t, out.String(), "Warning: floating: floating-server@latest isn't pinned")
+	assert.Contains(t, out.String(), "Warning: unsigned: team.json: not signed by a trusted key")
+
+	// A locked-down policy skips them
+	out.Reset()
+	w = New(strings.NewReader("\n\n\n\n\n"), &out)
+	w.Entries = entries
+	w.Policy = &catalog.Policy{RequireSigned: true, BlockUnpinned: true}
+	result, err = w.Run()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pinned"}, result.Config.ServerOrder)
+	assert.Contains(t, out.String(), "Skipping floating: command fetches unpinned floating-server@latest")
+	assert.Contains(t, out.String(), "Skipping unsigned: team.json: not signed by a trusted key")
+}
@@ -0,0 +1,246 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/internal/grpc"
+	"github.com/tartavull/mcp-manager/internal/server"
+)
+
+// binDir holds the binaries built for the suite
+var binDir string
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "mcp-e2e")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	binDir = dir
+
+	code := 1
+	if err := build(dir); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	} else {
+		code = m.Run()
+	}
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// build builds mcp-daemon and mcp-manager into dir
+func build(dir string) error {
+	for _, name := range []string{"mcp-daemon", "mcp-manager"} {
+		cmd := exec.Command("go", "build", "-o", filepath.Join(dir, name), "../../cmd/"+name)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to build %s: %v\n%s", name, err, out)
+		}
+	}
+	return nil
+}
+
+// freePort returns a TCP port nothing listens on
+func freePort(t *testing.T) int {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer lis.Close()
+	return lis.Addr().(*net.TCPAddr).Port
+}
+
+// env is the environment of a daemon or command isolated in home
+func env(home string) []string {
+	return append(os.Environ(), "HOME="+home,
+		"MCP_CONFIG_DIR="+filepath.Join(home, ".config", "mcp-manager"),
+		"MCP_STATE_DIR="+filepath.Join(home, ".local", "state", "mcp-manager"),
+		"MCP_CACHE_DIR="+filepath.Join(home, ".cache", "mcp-manager"))
+}
+
+// writeConfig writes mcp.json with the given servers
+func writeConfig(t *testing.T, home string, servers map[string]interface{}) {
+	t.Helper()
+
+	data, err := json.MarshalIndent(map[string]interface{}{"servers": servers}, "", "  ")
+	require.NoError(t, err)
+	dir := filepath.Join(home, ".config", "mcp-manager")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "mcp.json"), data, 0644))
+}
+
+// mockServer is the mcp.json entry of a mock server listing tools
+func mockServer(port int, tools string) map[string]interface{} {
+	return map[string]interface{}{
+		"command":     fmt.Sprintf("'%s' mock -tools %s", filepath.Join(binDir, "mcp-manager"), tools),
+		"port":        port,
+		"description": "Mock server",
+	}
+}
+
+// cli runs mcp-manager with args, returning its output
+func cli(t *testing.T, home string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command(filepath.Join(binDir, "mcp-manager"), args...)
+	cmd.Env = env(home)
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	return string(out)
+}
+
+// callTool calls a tool through a server's proxy, returning the text of
+// the result
+func callTool(t *testing.T, port int, name string) string {
+	t.Helper()
+
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params":  map[string]interface{}{"name": name, "arguments": map[string]interface{}{}},
+	})
+	require.NoError(t, err)
+
+	resp, err := http.Post(fmt.Sprintf("http://localhost:%d/", port), "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var result struct {
+		Result struct {
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
+		} `json:"result"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	require.Len(t, result.Result.Content, 1)
+	return result.Result.Content[0].Text
+}
+
+// toolNames returns the names of the tools a server lists
+func toolNames(client *grpc.Client, name string) []string {
+	tools, err := client.GetTools(name)
+	if err != nil {
+		return nil
+	}
+	names := make([]string, len(tools))
+	for i, tool := range tools {
+		names[i] = tool.Name
+	}
+	return names
+}
+
+// TestLifecycle drives a daemon through the life of a server: adding it,
+// starting it, calling a tool, editing its config, which restarts it, and
+// stopping it, all with the mock server built into mcp-manager
+func TestLifecycle(t *testing.T) {
+	home := t.TempDir()
+	grpcPort := freePort(t)
+	serverPort := freePort(t)
+	address := fmt.Sprintf("localhost:%d", grpcPort)
+	writeConfig(t, home, map[string]interface{}{})
+
+	// Start the daemon
+	daemon := exec.Command(filepath.Join(binDir, "mcp-daemon"), "run", "-port", fmt.Sprint(grpcPort))
+	daemon.Env = env(home)
+	require.NoError(t, daemon.Start())
+	exited := make(chan error, 1)
+	go func() { exited <- daemon.Wait() }()
+	defer daemon.Process.Kill()
+
+	var client *grpc.Client
+	require.Eventually(t, func() bool {
+		c, err := grpc.NewClient(address)
+		if err != nil {
+			return false
+		}
+		if _, err := c.Health(); err != nil {
+			c.Close()
+			return false
+		}
+		client = c
+		return true
+	}, 10*time.Second, 100*time.Millisecond)
+	defer client.Close()
+
+	// Add a server by editing mcp.json
+	writeConfig(t, home, map[string]interface{}{"mock": mockServer(serverPort, "echo")})
+	require.Eventually(t, func() bool {
+		_, err := client.GetServer("mock")
+		return err == nil
+	}, 5*time.Second, 100*time.Millisecond)
+
+	// Start it and call a tool
+	assert.Contains(t, cli(t, home, "start", "-daemon", address, "mock"), "Started mock")
+	srv, err := client.GetServer("mock")
+	require.NoError(t, err)
+	assert.Equal(t, server.StatusRunning, srv.Status)
+	assert.Equal(t, "called echo", callTool(t, serverPort, "echo"))
+	assert.Eventually(t, func() bool {
+		return strings.Join(toolNames(client, "mock"), ",") == "echo"
+	}, 10*time.Second, 200*time.Millisecond)
+
+	// Changing its command restarts it with the new tools
+	writeConfig(t, home, map[string]interface{}{"mock": mockServer(serverPort, "echo,reverse")})
+	assert.Eventually(t, func() bool {
+		return strings.Join(toolNames(client, "mock"), ",") == "echo,reverse"
+	}, 15*time.Second, 200*time.Millisecond)
+	srv, err = client.GetServer("mock")
+	require.NoError(t, err)
+	assert.Equal(t, server.StatusRunning, srv.Status)
+	assert.Equal(t, "called reverse", callTool(t, serverPort, "reverse"))
+
+	// Inventories print the servers and their tools as JSON
+	assert.Equal(t, "mock\n", cli(t, home, "list", "-daemon", address))
+	var listed struct {
+		Servers []server.Server `json:"servers"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(cli(t, home, "list", "-json", "-daemon", address)), &listed))
+	require.Len(t, listed.Servers, 1)
+	assert.Equal(t, serverPort, listed.Servers[0].Port)
+	var tools struct {
+		Tools []struct {
+			Server string `json:"server"`
+			Name   string `json:"name"`
+		} `json:"tools"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(cli(t, home, "tools", "-json", "-daemon", address, "mock")), &tools))
+	require.Len(t, tools.Tools, 2)
+	assert.Equal(t, "mock", tools.Tools[0].Server)
+	assert.Equal(t, "echo", tools.Tools[0].Name)
+
+	// Stop it, closing its proxy
+	assert.Contains(t, cli(t, home, "stop", "-daemon", address, "mock"), "Stopped mock")
+	srv, err = client.GetServer("mock")
+	require.NoError(t, err)
+	assert.Equal(t, server.StatusStopped, srv.Status)
+	_, err = http.Get(fmt.Sprintf("http://localhost:%d/", serverPort))
+	assert.Error(t, err)
+
+	// Quiet commands print only the data asked for
+	assert.Empty(t, cli(t, home, "-quiet", "start", "-daemon", address, "mock"))
+	assert.NotContains(t, cli(t, home, "-machine", "status", "-daemon", address), "\033[")
+	assert.Empty(t, cli(t, home, "-machine", "stop", "-daemon", address, "mock"))
+
+	// The daemon shuts down cleanly
+	require.NoError(t, daemon.Process.Signal(syscall.SIGTERM))
+	select {
+	case err := <-exited:
+		assert.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("daemon did not exit")
+	}
+}
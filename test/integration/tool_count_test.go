@@ -10,6 +10,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tartavull/mcp-manager/internal/catalog"
 	"github.com/tartavull/mcp-manager/internal/manager"
 )
 
@@ -113,14 +114,15 @@ func TestRealMCPServersToolCounts(t *testing.T) {
 	mgr.StopAllServers()
 }
 
-// TestAllDefaultServersHaveTools verifies all default servers report tools when running
-func TestAllDefaultServersHaveTools(t *testing.T) {
+// TestRecommendedServersHaveTools verifies the servers the catalog
+// recommends report tools when running
+func TestRecommendedServersHaveTools(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping integration test in short mode")
 	}
 
 	// This test requires all MCP servers to be installed
-	// Run with: go test -v ./test/integration -run TestAllDefaultServersHaveTools
+	// Run with: go test -v ./test/integration -run TestRecommendedServersHaveTools
 
 	tempDir := t.TempDir()
 	originalHome := os.Getenv("HOME")
@@ -130,6 +132,14 @@ func TestAllDefaultServersHaveTools(t *testing.T) {
 	mgr, err := manager.New()
 	require.NoError(t, err)
 
+	// Without an mcp.json there are no servers, add those the wizard
+	// preselects with their default params
+	for i, name := range catalog.Recommended() {
+		entry, err := catalog.Find(name)
+		require.NoError(t, err)
+		require.NoError(t, mgr.AddServer(name, entry.Render(nil), 5101+i, entry.Description))
+	}
+
 	// Get all servers
 	servers, _, err := mgr.GetServers()
 	require.NoError(t, err)